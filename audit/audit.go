@@ -0,0 +1,382 @@
+// Package audit projects the events of shelfs, galleries, pages and navs into
+// a queryable audit trail.
+//
+// Events don't carry the identity of the caller that caused them. The Log
+// therefore attributes events to actors that it learns from the Context of
+// command dispatches and event publications. Wrap the command and event buses
+// of the application, and add the actor to the Context that is passed to the
+// buses:
+//
+//	log := audit.NewLog()
+//	commands = log.Commands(commands)
+//	events = log.Events(events)
+//
+//	ctx = audit.WithActor(ctx, "user-id")
+//	commands.Dispatch(ctx, gallery.DeleteStack(galleryID, stackID))
+//
+//	errs, err := log.Project(ctx, events, store)
+//
+// Attributions are kept in memory, so events that are replayed from the event
+// store after a restart have no actor.
+package audit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/command"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/projector"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/page"
+)
+
+const (
+	// DefaultLimit is the default number of Entries in a Page.
+	DefaultLimit = 50
+
+	// MaxLimit is the maximum number of Entries in a Page.
+	MaxLimit = 500
+
+	// DefaultWindow is the default time window in which events are attributed
+	// to a dispatched command.
+	DefaultWindow = time.Minute
+)
+
+// Entry is an entry of the audit trail. An Entry is created for every event of
+// a shelf, gallery, page or nav.
+type Entry struct {
+	// ID is the UUID of the event.
+	ID   uuid.UUID `json:"id"`
+	Time time.Time `json:"time"`
+
+	// Actor is the identity of the caller that caused the event, or empty if
+	// the actor is unknown.
+	Actor string `json:"actor,omitempty"`
+
+	// Command is the name of the command that caused the event, or empty if
+	// the event wasn't caused by a command that was dispatched through the
+	// Log.
+	Command string `json:"command,omitempty"`
+
+	Event            string    `json:"event"`
+	Aggregate        string    `json:"aggregate"`
+	AggregateID      uuid.UUID `json:"aggregateId"`
+	AggregateVersion int       `json:"aggregateVersion"`
+}
+
+// Query filters and pages Entries. Zero fields don't filter.
+type Query struct {
+	Actor       string    `json:"actor,omitempty"`
+	Aggregate   string    `json:"aggregate,omitempty"`
+	AggregateID uuid.UUID `json:"aggregateId,omitempty"`
+	Event       string    `json:"event,omitempty"`
+	Since       time.Time `json:"since,omitempty"`
+	Until       time.Time `json:"until,omitempty"`
+
+	// Offset is the number of matching Entries to skip.
+	Offset int `json:"offset,omitempty"`
+
+	// Limit is the maximum number of Entries to return. Limit defaults to
+	// DefaultLimit and is capped at MaxLimit.
+	Limit int `json:"limit,omitempty"`
+}
+
+// Page is a page of Entries, newest first.
+type Page struct {
+	Entries []Entry `json:"entries"`
+
+	// Total is the number of Entries that match the Query.
+	Total  int `json:"total"`
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// Next returns the Query for the next Page, or false if there is no next Page.
+func (p Page) Next(q Query) (Query, bool) {
+	if p.Offset+len(p.Entries) >= p.Total {
+		return q, false
+	}
+	q.Offset = p.Offset + len(p.Entries)
+	q.Limit = p.Limit
+	return q, true
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx that contains the identity of an actor.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor that was added to ctx using WithActor.
+func ActorFrom(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(actorKey{}).(string)
+	return actor, ok
+}
+
+// Option is a Log option.
+type Option func(*Log)
+
+// Window returns an Option that sets the time window in which the events of an
+// aggregate are attributed to the last command that was dispatched for that
+// aggregate. Default is DefaultWindow.
+func Window(d time.Duration) Option {
+	return func(l *Log) {
+		l.window = d
+	}
+}
+
+// Actor returns an Option that provides the Log with a function that returns
+// the actor from a Context. The function is used when the Context contains no
+// actor that was added by WithActor, so that identities of other packages can
+// be used (e.g. mediarpc.IdentityFrom).
+func Actor(fn func(context.Context) (string, bool)) Option {
+	return func(l *Log) {
+		l.actorFuncs = append(l.actorFuncs, fn)
+	}
+}
+
+// Projection returns an Option that configures the error handling of the
+// projection.
+func Projection(opts ...projector.Option) Option {
+	return func(l *Log) {
+		l.projectorOpts = append(l.projectorOpts, opts...)
+	}
+}
+
+// Log is a projection of the audit trail of shelfs, galleries, pages and navs.
+// Log is thread-safe.
+type Log struct {
+	projector     *projector.Projector
+	projectorOpts []projector.Option
+	window        time.Duration
+	actorFuncs    []func(context.Context) (string, bool)
+
+	audited map[string]bool
+
+	mux      sync.RWMutex
+	entries  []Entry
+	seen     map[uuid.UUID]struct{}
+	events   map[uuid.UUID]string
+	commands map[aggregate.Ref]attribution
+}
+
+type attribution struct {
+	actor   string
+	command string
+	time    time.Time
+}
+
+// NewLog returns a new Log.
+func NewLog(opts ...Option) *Log {
+	l := &Log{
+		window:   DefaultWindow,
+		audited:  make(map[string]bool),
+		seen:     make(map[uuid.UUID]struct{}),
+		events:   make(map[uuid.UUID]string),
+		commands: make(map[aggregate.Ref]attribution),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	for _, name := range Events() {
+		l.audited[name] = true
+	}
+	l.projector = projector.New(l.projectorOpts...)
+	return l
+}
+
+// Events returns the events that are projected by a Log.
+func Events() []string {
+	events := make([]string, 0, len(document.Events)+len(gallery.Events)+len(page.Events)+len(nav.Events))
+	events = append(events, document.Events[:]...)
+	events = append(events, gallery.Events[:]...)
+	events = append(events, page.Events[:]...)
+	return append(events, nav.Events[:]...)
+}
+
+// Project projects the Log in a new goroutine and returns a channel of
+// asynchronous errors.
+func (l *Log) Project(ctx context.Context, bus event.Bus, store event.Store, opts ...schedule.ContinuousOption) (<-chan error, error) {
+	schedule := schedule.Continuously(bus, store, Events(), opts...)
+	return l.projector.Run(ctx, schedule, l)
+}
+
+// Status returns the projection status of the Log.
+func (l *Log) Status() projector.Status {
+	return l.projector.Status()
+}
+
+// ApplyEvent applies aggregate events.
+func (l *Log) ApplyEvent(evt event.Event) {
+	id, name, version := evt.Aggregate()
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	if _, ok := l.seen[evt.ID()]; ok {
+		return
+	}
+	l.seen[evt.ID()] = struct{}{}
+
+	entry := Entry{
+		ID:               evt.ID(),
+		Time:             evt.Time(),
+		Event:            evt.Name(),
+		Aggregate:        name,
+		AggregateID:      id,
+		AggregateVersion: version,
+	}
+
+	if actor, ok := l.events[evt.ID()]; ok {
+		entry.Actor = actor
+		delete(l.events, evt.ID())
+	}
+
+	if a, ok := l.commands[aggregate.Ref{Name: name, ID: id}]; ok && l.attributable(a, evt.Time()) {
+		entry.Command = a.command
+		if entry.Actor == "" {
+			entry.Actor = a.actor
+		}
+	}
+
+	// Events are usually applied in order, so the Entry is inserted from the
+	// end of the trail.
+	i := len(l.entries)
+	for i > 0 && l.entries[i-1].Time.After(entry.Time) {
+		i--
+	}
+	l.entries = append(l.entries, Entry{})
+	copy(l.entries[i+1:], l.entries[i:])
+	l.entries[i] = entry
+}
+
+// attributable returns whether an event at the given time can be attributed to
+// a.
+func (l *Log) attributable(a attribution, t time.Time) bool {
+	return !t.Before(a.time) && t.Sub(a.time) <= l.window
+}
+
+// Entries returns the Page of Entries that match the Query, newest first.
+func (l *Log) Entries(q Query) Page {
+	if q.Limit <= 0 {
+		q.Limit = DefaultLimit
+	}
+	if q.Limit > MaxLimit {
+		q.Limit = MaxLimit
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+
+	page := Page{Entries: make([]Entry, 0), Offset: q.Offset, Limit: q.Limit}
+
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		entry := l.entries[i]
+		if !q.matches(entry) {
+			continue
+		}
+		if page.Total >= q.Offset && len(page.Entries) < q.Limit {
+			page.Entries = append(page.Entries, entry)
+		}
+		page.Total++
+	}
+
+	return page
+}
+
+func (q Query) matches(e Entry) bool {
+	return (q.Actor == "" || e.Actor == q.Actor) &&
+		(q.Aggregate == "" || e.Aggregate == q.Aggregate) &&
+		(q.AggregateID == uuid.Nil || e.AggregateID == q.AggregateID) &&
+		(q.Event == "" || e.Event == q.Event) &&
+		(q.Since.IsZero() || !e.Time.Before(q.Since)) &&
+		(q.Until.IsZero() || e.Time.Before(q.Until))
+}
+
+func (l *Log) actor(ctx context.Context) (string, bool) {
+	if actor, ok := ActorFrom(ctx); ok {
+		return actor, true
+	}
+	for _, fn := range l.actorFuncs {
+		if actor, ok := fn(ctx); ok {
+			return actor, true
+		}
+	}
+	return "", false
+}
+
+// Commands returns a command.Bus that dispatches commands through bus. The
+// events that are raised by the aggregate of a dispatched command are
+// attributed to that command and to the actor of the dispatch Context.
+func (l *Log) Commands(bus command.Bus) command.Bus {
+	return &commandBus{Bus: bus, log: l}
+}
+
+type commandBus struct {
+	command.Bus
+
+	log *Log
+}
+
+func (bus *commandBus) Dispatch(ctx context.Context, cmd command.Command, opts ...command.DispatchOption) error {
+	if ref := cmd.Aggregate(); ref.ID != uuid.Nil {
+		actor, _ := bus.log.actor(ctx)
+		bus.log.attribute(ref, attribution{
+			actor:   actor,
+			command: cmd.Name(),
+			time:    time.Now(),
+		})
+	}
+	return bus.Bus.Dispatch(ctx, cmd, opts...)
+}
+
+func (l *Log) attribute(ref aggregate.Ref, a attribution) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	for r, old := range l.commands {
+		if a.time.Sub(old.time) > l.window {
+			delete(l.commands, r)
+		}
+	}
+
+	l.commands[ref] = a
+}
+
+// Events returns an event.Bus that publishes events through bus. Published
+// events are attributed to the actor of the publish Context. Use Events to
+// attribute changes that are not made by commands, like the uploads of the
+// media gRPC server.
+func (l *Log) Events(bus event.Bus) event.Bus {
+	return &eventBus{Bus: bus, log: l}
+}
+
+type eventBus struct {
+	event.Bus
+
+	log *Log
+}
+
+func (bus *eventBus) Publish(ctx context.Context, events ...event.Event) error {
+	if actor, ok := bus.log.actor(ctx); ok && actor != "" {
+		bus.log.mux.Lock()
+		for _, evt := range events {
+			if _, applied := bus.log.seen[evt.ID()]; !applied && bus.log.audited[evt.Name()] {
+				bus.log.events[evt.ID()] = actor
+			}
+		}
+		bus.log.mux.Unlock()
+	}
+	return bus.Bus.Publish(ctx, events...)
+}
@@ -0,0 +1,175 @@
+package audit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/codec"
+	"github.com/modernice/goes/command/cmdbus"
+	"github.com/modernice/goes/command/cmdbus/dispatch"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/audit"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/page"
+)
+
+func TestLog(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	log := audit.NewLog()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), log.Events(ebus))
+	repo := repository.New(estore)
+
+	creg := codec.New()
+	gallery.RegisterCommands(creg)
+	commands := log.Commands(cmdbus.New(creg, ebus))
+
+	galleries := gallery.GoesRepository(repo)
+	navs := nav.GoesRepository(repo)
+
+	gallery.HandleCommands(ctx, commands, galleries, nil)
+
+	if _, err := log.Project(ctx, ebus, estore); err != nil {
+		t.Fatalf("project audit log: %v", err)
+	}
+
+	galleryID := uuid.New()
+	if err := commands.Dispatch(audit.WithActor(ctx, "alice"), gallery.Create(galleryID, "foo").Any(), dispatch.Sync()); err != nil {
+		t.Fatalf("dispatch command: %v", err)
+	}
+
+	n, err := nav.Create("main")
+	if err != nil {
+		t.Fatalf("create nav: %v", err)
+	}
+	if err := navs.Save(audit.WithActor(ctx, "bob"), n); err != nil {
+		t.Fatalf("save nav: %v", err)
+	}
+
+	page := awaitEntries(t, log, 2)
+
+	want := []audit.Entry{
+		{Actor: "bob", Event: nav.Created, Aggregate: nav.Aggregate, AggregateID: n.ID, AggregateVersion: 1},
+		{Actor: "alice", Command: gallery.CreateCommand, Event: gallery.Created, Aggregate: gallery.Aggregate, AggregateID: galleryID, AggregateVersion: 1},
+	}
+
+	for i, entry := range page.Entries {
+		entry.ID, entry.Time = uuid.Nil, time.Time{}
+		if entry != want[i] {
+			t.Fatalf("Entry #%d should be\n\n%#v\n\ngot\n\n%#v", i, want[i], entry)
+		}
+	}
+
+	if page := log.Entries(audit.Query{Actor: "alice"}); page.Total != 1 || page.Entries[0].Actor != "alice" {
+		t.Fatalf("Entries should be filtered by actor; got %v", page.Entries)
+	}
+}
+
+func TestLog_pages(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	log := audit.NewLog()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), log.Events(ebus))
+	pages := page.GoesRepository(repository.New(estore))
+
+	if _, err := log.Project(ctx, ebus, estore); err != nil {
+		t.Fatalf("project audit log: %v", err)
+	}
+
+	p := page.New(uuid.New())
+	p.Create("home")
+	if err := p.Publish(); err != nil {
+		t.Fatalf("publish page: %v", err)
+	}
+	if err := pages.Save(audit.WithActor(ctx, "alice"), p); err != nil {
+		t.Fatalf("save page: %v", err)
+	}
+
+	entries := awaitEntries(t, log, 2)
+
+	for i, name := range []string{page.Published, page.Created} {
+		if e := entries.Entries[i]; e.Event != name || e.Aggregate != page.Aggregate || e.AggregateID != p.ID || e.Actor != "alice" {
+			t.Fatalf("Entry #%d should be the %q event of the Page; got %#v", i, name, e)
+		}
+	}
+}
+
+func TestLog_Entries(t *testing.T) {
+	log := audit.NewLog()
+
+	galleryID := uuid.New()
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		log.ApplyEvent(event.New(
+			gallery.StackTagged,
+			gallery.StackTaggedData{},
+			event.Time(start.Add(time.Duration(i)*time.Second)),
+			event.Aggregate(galleryID, gallery.Aggregate, i+1),
+		).Any())
+	}
+
+	// Events that are applied twice are ignored.
+	evt := event.New(gallery.Created, gallery.CreatedData{}, event.Time(start.Add(-time.Second)), event.Aggregate(galleryID, gallery.Aggregate, 0))
+	log.ApplyEvent(evt.Any())
+	log.ApplyEvent(evt.Any())
+
+	q := audit.Query{AggregateID: galleryID, Limit: 4}
+	page := log.Entries(q)
+
+	if page.Total != 6 || len(page.Entries) != 4 {
+		t.Fatalf("Page should have 4 of 6 Entries; has %d of %d", len(page.Entries), page.Total)
+	}
+
+	if page.Entries[0].AggregateVersion != 5 {
+		t.Fatalf("Entries should be sorted newest first; first Entry has version %d", page.Entries[0].AggregateVersion)
+	}
+
+	q, ok := page.Next(q)
+	if !ok {
+		t.Fatalf("Page should have a next Page")
+	}
+
+	page = log.Entries(q)
+	if len(page.Entries) != 2 || page.Entries[1].Event != gallery.Created {
+		t.Fatalf("next Page should end with the oldest Entry; got %v", page.Entries)
+	}
+
+	if _, ok := page.Next(q); ok {
+		t.Fatalf("last Page should have no next Page")
+	}
+
+	if page := log.Entries(audit.Query{Since: start.Add(3 * time.Second)}); page.Total != 2 {
+		t.Fatalf("Entries should be filtered by time; got %d Entries", page.Total)
+	}
+
+	if page := log.Entries(audit.Query{Event: gallery.Created}); page.Total != 1 {
+		t.Fatalf("Entries should be filtered by event; got %d Entries", page.Total)
+	}
+}
+
+func awaitEntries(t *testing.T, log *audit.Log, n int) audit.Page {
+	t.Helper()
+	timeout := time.After(3 * time.Second)
+	for {
+		if page := log.Entries(audit.Query{}); page.Total >= n {
+			return page
+		}
+		select {
+		case <-timeout:
+			t.Fatalf("timed out waiting for %d audit Entries", n)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
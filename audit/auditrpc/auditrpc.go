@@ -0,0 +1,52 @@
+// Package auditrpc provides the audit trail gRPC server and client.
+package auditrpc
+
+import (
+	"context"
+
+	"github.com/modernice/nice-cms/audit"
+	protoaudit "github.com/modernice/nice-cms/proto/gen/audit/v1"
+	"github.com/modernice/nice-cms/proto/ptypes/v1"
+	"google.golang.org/grpc"
+)
+
+// Server is the audit gRPC server.
+type Server struct {
+	protoaudit.UnimplementedAuditServiceServer
+
+	log *audit.Log
+}
+
+// NewServer returns the audit gRPC server.
+func NewServer(log *audit.Log) *Server {
+	return &Server{log: log}
+}
+
+// Register registers the server into a ServiceRegistrar.
+func (s *Server) Register(reg grpc.ServiceRegistrar) {
+	protoaudit.RegisterAuditServiceServer(reg, s)
+}
+
+// ListEntries returns a page of audit Entries.
+func (s *Server) ListEntries(ctx context.Context, req *protoaudit.ListEntriesReq) (*protoaudit.EntryPage, error) {
+	return ptypes.AuditPageProto(s.log.Entries(ptypes.AuditQuery(req))), nil
+}
+
+// Client is the audit gRPC client.
+type Client struct {
+	client protoaudit.AuditServiceClient
+}
+
+// NewClient returns the audit gRPC client.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: protoaudit.NewAuditServiceClient(conn)}
+}
+
+// Entries returns the Page of audit Entries that match the Query.
+func (c *Client) Entries(ctx context.Context, q audit.Query) (audit.Page, error) {
+	resp, err := c.client.ListEntries(ctx, ptypes.AuditQueryProto(q))
+	if err != nil {
+		return audit.Page{}, err
+	}
+	return ptypes.AuditPage(resp), nil
+}
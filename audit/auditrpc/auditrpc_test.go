@@ -0,0 +1,40 @@
+package auditrpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/audit"
+	"github.com/modernice/nice-cms/audit/auditrpc"
+	"github.com/modernice/nice-cms/internal/grpctest"
+	"github.com/modernice/nice-cms/static/nav"
+	"google.golang.org/grpc"
+)
+
+func TestClient_Entries(t *testing.T) {
+	log := audit.NewLog()
+	navID := uuid.New()
+	log.ApplyEvent(event.New(nav.Created, nav.CreatedData{Name: "main"}, event.Aggregate(navID, nav.Aggregate, 1)).Any())
+	log.ApplyEvent(event.New(nav.ItemsAdded, nav.ItemsAddedData{}, event.Aggregate(navID, nav.Aggregate, 2)).Any())
+	log.ApplyEvent(event.New(nav.Created, nav.CreatedData{Name: "footer"}, event.Aggregate(uuid.New(), nav.Aggregate, 1)).Any())
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		auditrpc.NewServer(log).Register(s)
+	})
+	conn := dial()
+	defer conn.Close()
+
+	client := auditrpc.NewClient(conn)
+
+	page, err := client.Entries(context.Background(), audit.Query{AggregateID: navID, Limit: 1})
+	if err != nil {
+		t.Fatalf("Entries failed with %q", err)
+	}
+
+	want := log.Entries(audit.Query{AggregateID: navID, Limit: 1})
+	if page.Total != 2 || len(page.Entries) != 1 || page.Entries[0].ID != want.Entries[0].ID || !page.Entries[0].Time.Equal(want.Entries[0].Time) {
+		t.Fatalf("Entries should return\n\n%v\n\ngot\n\n%v", want, page)
+	}
+}
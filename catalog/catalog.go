@@ -0,0 +1,178 @@
+// Package catalog describes the event and command contract of the CMS.
+//
+// A Catalog is generated from the functions that register event data and
+// command payloads into a codec registry. Every registered name becomes an
+// Entry with the JSON Schema of its payload and the aggregate it belongs to,
+// so that integration teams can discover the contract programmatically:
+//
+//	c := catalog.Default()
+//	e, ok := c.Event(gallery.StackTagged)
+//	// e.Aggregate == "cms.media.image.gallery"
+//
+// Custom events and commands can be described by passing their register
+// functions to New:
+//
+//	c := catalog.New(
+//		catalog.Events(events.Register, blog.RegisterEvents),
+//		catalog.Commands(commands.Register),
+//		catalog.Aggregates(nav.Aggregate, blog.Aggregate),
+//	)
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modernice/goes/codec"
+	"github.com/modernice/nice-cms/internal/commands"
+	"github.com/modernice/nice-cms/internal/events"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/schema"
+	"github.com/modernice/nice-cms/static/nav"
+)
+
+// Catalog is a catalog of events and commands. Entries are sorted by name.
+type Catalog struct {
+	Events   []Entry `json:"events"`
+	Commands []Entry `json:"commands"`
+}
+
+// Entry describes an event or command.
+type Entry struct {
+	// Name is the name of the event or command.
+	Name string `json:"name"`
+
+	// Aggregate is the name of the aggregate the event or command belongs to,
+	// or empty if it doesn't belong to one of the configured aggregates.
+	Aggregate string `json:"aggregate,omitempty"`
+
+	// Payload is the JSON Schema of the event data or command payload.
+	Payload *schema.Schema `json:"payload"`
+}
+
+// Option is an option for New.
+type Option func(*builder)
+
+type builder struct {
+	events     []func(codec.Registerer)
+	commands   []func(codec.Registerer)
+	aggregates []string
+}
+
+// Events returns an Option that adds the events that are registered by the
+// provided functions to the Catalog.
+func Events(register ...func(codec.Registerer)) Option {
+	return func(b *builder) {
+		b.events = append(b.events, register...)
+	}
+}
+
+// Commands returns an Option that adds the commands that are registered by the
+// provided functions to the Catalog.
+func Commands(register ...func(codec.Registerer)) Option {
+	return func(b *builder) {
+		b.commands = append(b.commands, register...)
+	}
+}
+
+// Aggregates returns an Option that adds aggregate names to the Catalog. An
+// event or command belongs to the aggregate whose name is the longest prefix
+// of its own name.
+func Aggregates(names ...string) Option {
+	return func(b *builder) {
+		b.aggregates = append(b.aggregates, names...)
+	}
+}
+
+// Default returns the Catalog of all events and commands of the CMS.
+func Default() Catalog {
+	return New(
+		Events(events.Register),
+		Commands(commands.Register),
+		Aggregates(nav.Aggregate, document.Aggregate, gallery.Aggregate, gallery.JobAggregate),
+	)
+}
+
+// New returns the Catalog of the events and commands that are registered by
+// the functions provided by opts. New panics if a payload type cannot be
+// represented as a JSON Schema.
+func New(opts ...Option) Catalog {
+	var b builder
+	for _, opt := range opts {
+		opt(&b)
+	}
+
+	return Catalog{
+		Events:   b.entries(b.events),
+		Commands: b.entries(b.commands),
+	}
+}
+
+// Event returns the Entry of the given event.
+func (c Catalog) Event(name string) (Entry, bool) {
+	return find(c.Events, name)
+}
+
+// Command returns the Entry of the given command.
+func (c Catalog) Command(name string) (Entry, bool) {
+	return find(c.Commands, name)
+}
+
+func find(entries []Entry, name string) (Entry, bool) {
+	for _, e := range entries {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+func (b builder) entries(register []func(codec.Registerer)) []Entry {
+	r := make(recorder)
+	for _, fn := range register {
+		fn(r)
+	}
+
+	entries := make([]Entry, 0, len(r))
+	for name, factory := range r {
+		entries = append(entries, Entry{
+			Name:      name,
+			Aggregate: b.aggregate(name),
+			Payload:   payloadSchema(name, factory()),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries
+}
+
+func (b builder) aggregate(name string) string {
+	var out string
+	for _, agg := range b.aggregates {
+		if strings.HasPrefix(name, agg+".") && len(agg) > len(out) {
+			out = agg
+		}
+	}
+	return out
+}
+
+func payloadSchema(name string, v any) (s *schema.Schema) {
+	defer func() {
+		if r := recover(); r != nil {
+			panic(fmt.Errorf("catalog: payload of %q: %v", name, r))
+		}
+	}()
+	return schema.Of(v, schema.Title(name))
+}
+
+// recorder is a codec.Registerer that records the registered factories.
+type recorder map[string]func() any
+
+func (r recorder) Register(name string, factory func() any) {
+	r[name] = factory
+}
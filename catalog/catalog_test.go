@@ -0,0 +1,82 @@
+package catalog_test
+
+import (
+	"testing"
+
+	"github.com/modernice/goes/codec"
+	"github.com/modernice/nice-cms/catalog"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/media/usage"
+	"github.com/modernice/nice-cms/static/nav"
+)
+
+func TestDefault(t *testing.T) {
+	c := catalog.Default()
+
+	for _, name := range document.Events {
+		e, ok := c.Event(name)
+		if !ok {
+			t.Fatalf("Catalog should contain the %q event", name)
+		}
+		if e.Aggregate != document.Aggregate {
+			t.Errorf("aggregate of %q should be %q; is %q", name, document.Aggregate, e.Aggregate)
+		}
+	}
+
+	for _, name := range gallery.Events {
+		if e, ok := c.Event(name); !ok || e.Aggregate != gallery.Aggregate {
+			t.Errorf("Catalog should contain the %q event of the %q aggregate; got %v", name, gallery.Aggregate, e)
+		}
+	}
+
+	if e, ok := c.Command(nav.CreateCommand); !ok || e.Aggregate != nav.Aggregate {
+		t.Errorf("Catalog should contain the %q command of the %q aggregate; got %v", nav.CreateCommand, nav.Aggregate, e)
+	}
+
+	if e, ok := c.Event(usage.LevelChanged); !ok || e.Aggregate != "" {
+		t.Errorf("Catalog should contain the %q event without an aggregate; got %v", usage.LevelChanged, e)
+	}
+
+	for i := 1; i < len(c.Events); i++ {
+		if c.Events[i-1].Name >= c.Events[i].Name {
+			t.Fatalf("events should be sorted by name; %q is before %q", c.Events[i-1].Name, c.Events[i].Name)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	type payload struct {
+		Title string   `json:"title"`
+		Tags  []string `json:"tags"`
+	}
+
+	c := catalog.New(
+		catalog.Events(func(r codec.Registerer) {
+			codec.Register[payload](r, "blog.post.published")
+			codec.Register[payload](r, "blog.post.comment.added")
+		}),
+		catalog.Aggregates("blog.post", "blog.post.comment"),
+	)
+
+	if len(c.Commands) != 0 {
+		t.Fatalf("Catalog should contain no commands; contains %d", len(c.Commands))
+	}
+
+	e, ok := c.Event("blog.post.published")
+	if !ok {
+		t.Fatalf("Catalog should contain the %q event", "blog.post.published")
+	}
+
+	if e.Aggregate != "blog.post" {
+		t.Errorf("aggregate should be %q; is %q", "blog.post", e.Aggregate)
+	}
+
+	if e.Payload.Type != "object" || e.Payload.Properties["title"] == nil || e.Payload.Properties["tags"].Type != "array" {
+		t.Errorf("payload schema should describe the payload type; got %v", e.Payload)
+	}
+
+	if e, _ := c.Event("blog.post.comment.added"); e.Aggregate != "blog.post.comment" {
+		t.Errorf("aggregate should be the longest matching aggregate %q; is %q", "blog.post.comment", e.Aggregate)
+	}
+}
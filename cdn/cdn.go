@@ -0,0 +1,222 @@
+// Package cdn purges cached assets from CDNs when their content changes.
+//
+// An Invalidator subscribes to the events that change or remove the files of
+// Stacks and Documents, resolves the public URLs of the affected files and
+// purges them using a Purger:
+//
+//	inv := cdn.NewInvalidator(
+//		cloudflare.New("zone-id", "api-token"),
+//		cdn.BaseURL("https://cdn.example.com"),
+//	)
+//
+//	errs, err := inv.Run(ctx, bus)
+//
+// Reference Purgers for Cloudflare and Fastly are provided by the cloudflare
+// and fastly subpackages.
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/helper/streams"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+// Purger purges cached URLs from a CDN.
+type Purger interface {
+	// Purge purges the given URLs from the cache of the CDN.
+	Purge(ctx context.Context, urls []string) error
+}
+
+// PurgerFunc allows a function to be used as a Purger.
+type PurgerFunc func(context.Context, []string) error
+
+// Purge calls fn(ctx, urls).
+func (fn PurgerFunc) Purge(ctx context.Context, urls []string) error {
+	return fn(ctx, urls)
+}
+
+// Asset is a file of a Stack or Document whose cached URLs must be purged.
+type Asset struct {
+	// Event is the name of the event that changed the Asset.
+	Event string
+
+	// AggregateID is the UUID of the Gallery or Shelf of the Asset.
+	AggregateID uuid.UUID
+
+	// ID is the UUID of the Stack or Document of the Asset.
+	ID uuid.UUID
+
+	File media.File
+}
+
+// Resolver returns the public URLs of an Asset. A Resolver may return multiple
+// URLs, e.g. if a file is served by the media server and from a storage
+// bucket, or no URLs if the Asset is not cached.
+type Resolver func(Asset) []string
+
+// BaseURL returns a Resolver that resolves the URL of an Asset by appending
+// the path of its File to the given base URL.
+//
+//	cdn.BaseURL("https://cdn.example.com")
+//	// https://cdn.example.com/galleries/.../image.jpg
+func BaseURL(base string) Resolver {
+	base = strings.TrimRight(base, "/")
+	return func(a Asset) []string {
+		if a.File.Path == "" {
+			return nil
+		}
+		path := (&url.URL{Path: strings.TrimLeft(a.File.Path, "/")}).EscapedPath()
+		return []string{base + "/" + path}
+	}
+}
+
+// Events returns the events that trigger purges: a replaced image or document
+// keeps its storage path, and the files of deleted Stacks and Documents must
+// not be served anymore.
+func Events() []string {
+	return []string{
+		gallery.ImageReplaced,
+		gallery.StackDeleted,
+		document.DocumentReplaced,
+		document.DocumentRemoved,
+	}
+}
+
+// Invalidator purges the URLs of changed Assets from a CDN.
+type Invalidator struct {
+	purger  Purger
+	resolve Resolver
+}
+
+// NewInvalidator returns an Invalidator that resolves the URLs of changed
+// Assets using resolve and purges them using p.
+func NewInvalidator(p Purger, resolve Resolver) *Invalidator {
+	return &Invalidator{
+		purger:  p,
+		resolve: resolve,
+	}
+}
+
+// Run subscribes to the events returned by Events and purges the URLs of the
+// affected Assets until ctx is canceled. Failed purges are reported through
+// the returned error channel.
+func (inv *Invalidator) Run(ctx context.Context, bus event.Bus) (<-chan error, error) {
+	events, errs, err := bus.Subscribe(ctx, Events()...)
+	if err != nil {
+		return nil, fmt.Errorf("subscribe to %v events: %w", Events(), err)
+	}
+
+	out := make(chan error)
+
+	go func() {
+		defer close(out)
+
+		fail := func(err error) {
+			select {
+			case <-ctx.Done():
+			case out <- err:
+			}
+		}
+
+		streams.ForEach(ctx, func(evt event.Event) {
+			urls := inv.URLs(evt)
+			if len(urls) == 0 {
+				return
+			}
+			if err := inv.purger.Purge(ctx, urls); err != nil {
+				fail(fmt.Errorf("purge %d URLs of %q event: %w", len(urls), evt.Name(), err))
+			}
+		}, fail, events, errs)
+	}()
+
+	return out, nil
+}
+
+// URLs returns the deduplicated URLs that must be purged for the given event.
+func (inv *Invalidator) URLs(evt event.Event) []string {
+	seen := make(map[string]bool)
+	var urls []string
+	for _, a := range Assets(evt) {
+		for _, u := range inv.resolve(a) {
+			if !seen[u] {
+				seen[u] = true
+				urls = append(urls, u)
+			}
+		}
+	}
+	return urls
+}
+
+// Assets returns the Assets that are changed by the given event. Assets
+// returns nil for events that are not returned by Events.
+func Assets(evt event.Event) []Asset {
+	id, _, _ := evt.Aggregate()
+
+	var (
+		itemID uuid.UUID
+		files  []media.File
+	)
+
+	switch data := evt.Data().(type) {
+	case gallery.ImageReplacedData:
+		itemID, files = data.Stack.ID, stackFiles(data.Stack)
+	case gallery.StackDeletedData:
+		itemID, files = data.Stack.ID, stackFiles(data.Stack)
+	case document.DocumentReplacedData:
+		itemID, files = data.Document.ID, documentFiles(data.Document)
+	case document.DocumentRemovedData:
+		itemID, files = data.Document.ID, documentFiles(data.Document)
+	default:
+		return nil
+	}
+
+	assets := make([]Asset, 0, len(files))
+	for _, f := range files {
+		if f.Path == "" {
+			continue
+		}
+		assets = append(assets, Asset{
+			Event:       evt.Name(),
+			AggregateID: id,
+			ID:          itemID,
+			File:        f,
+		})
+	}
+	return assets
+}
+
+func stackFiles(stack gallery.Stack) []media.File {
+	files := make([]media.File, 0, len(stack.Images)+1)
+	for _, img := range stack.Images {
+		files = append(files, img.File)
+	}
+	if stack.Video != nil {
+		files = append(files, stack.Video.File)
+	}
+	return files
+}
+
+func documentFiles(doc document.Document) []media.File {
+	files := []media.File{doc.File}
+	locales := make([]string, 0, len(doc.Variants))
+	for locale := range doc.Variants {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	for _, locale := range locales {
+		files = append(files, doc.Variants[locale].File)
+	}
+	if doc.Preview != nil {
+		files = append(files, doc.Preview.Image.File)
+	}
+	return files
+}
@@ -0,0 +1,143 @@
+package cdn_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/nice-cms/cdn"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+func TestInvalidator(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := eventbus.New()
+
+	purged := make(chan []string)
+	inv := cdn.NewInvalidator(cdn.PurgerFunc(func(_ context.Context, urls []string) error {
+		purged <- urls
+		return nil
+	}), cdn.BaseURL("https://cdn.example.com/"))
+
+	if _, err := inv.Run(ctx, bus); err != nil {
+		t.Fatalf("run invalidator: %v", err)
+	}
+
+	stack := gallery.Stack{
+		ID: uuid.New(),
+		Images: []gallery.Image{
+			{Image: media.Image{File: media.File{Path: "/galleries/foo/image.jpg"}}, Original: true},
+			{Image: media.Image{File: media.File{Path: "/galleries/foo/image_small.jpg"}}, Size: "small"},
+		},
+	}
+
+	publish(ctx, t, bus, gallery.ImageReplaced, gallery.ImageReplacedData{Stack: stack}, gallery.Aggregate)
+
+	want := []string{
+		"https://cdn.example.com/galleries/foo/image.jpg",
+		"https://cdn.example.com/galleries/foo/image_small.jpg",
+	}
+	if urls := await(t, purged); !reflect.DeepEqual(urls, want) {
+		t.Fatalf("Invalidator should purge %v; purged %v", want, urls)
+	}
+
+	doc := document.Document{
+		Document: media.Document{File: media.File{Path: "shelfs/foo/my document.pdf"}},
+		ID:       uuid.New(),
+		Variants: map[string]media.Document{
+			"de": {File: media.File{Path: "shelfs/foo/de.pdf"}},
+		},
+	}
+
+	publish(ctx, t, bus, document.DocumentRemoved, document.DocumentRemovedData{Document: doc}, document.Aggregate)
+
+	want = []string{
+		"https://cdn.example.com/shelfs/foo/my%20document.pdf",
+		"https://cdn.example.com/shelfs/foo/de.pdf",
+	}
+	if urls := await(t, purged); !reflect.DeepEqual(urls, want) {
+		t.Fatalf("Invalidator should purge %v; purged %v", want, urls)
+	}
+}
+
+func TestInvalidator_error(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := eventbus.New()
+
+	mockError := errors.New("mock error")
+	inv := cdn.NewInvalidator(cdn.PurgerFunc(func(context.Context, []string) error {
+		return mockError
+	}), cdn.BaseURL("https://cdn.example.com"))
+
+	errs, err := inv.Run(ctx, bus)
+	if err != nil {
+		t.Fatalf("run invalidator: %v", err)
+	}
+
+	stack := gallery.Stack{
+		ID:     uuid.New(),
+		Images: []gallery.Image{{Image: media.Image{File: media.File{Path: "image.jpg"}}, Original: true}},
+	}
+	publish(ctx, t, bus, gallery.StackDeleted, gallery.StackDeletedData{Stack: stack}, gallery.Aggregate)
+
+	select {
+	case <-time.After(3 * time.Second):
+		t.Fatalf("failed purge should be reported")
+	case err := <-errs:
+		if !errors.Is(err, mockError) {
+			t.Fatalf("error should be %q; got %q", mockError, err)
+		}
+	}
+}
+
+func TestAssets(t *testing.T) {
+	galleryID := uuid.New()
+	stack := gallery.Stack{
+		ID:     uuid.New(),
+		Images: []gallery.Image{{Image: media.Image{File: media.File{Path: "image.jpg"}}, Original: true}},
+		Video:  &media.Video{File: media.File{Path: "video.mp4"}},
+	}
+
+	evt := event.New(gallery.StackDeleted, gallery.StackDeletedData{Stack: stack}, event.Aggregate(galleryID, gallery.Aggregate, 1))
+
+	want := []cdn.Asset{
+		{Event: gallery.StackDeleted, AggregateID: galleryID, ID: stack.ID, File: stack.Images[0].File},
+		{Event: gallery.StackDeleted, AggregateID: galleryID, ID: stack.ID, File: stack.Video.File},
+	}
+	if assets := cdn.Assets(evt.Any()); !reflect.DeepEqual(assets, want) {
+		t.Fatalf("Assets should return %v; got %v", want, assets)
+	}
+
+	other := event.New(gallery.Created, gallery.CreatedData{Name: "foo"}, event.Aggregate(galleryID, gallery.Aggregate, 1))
+	if assets := cdn.Assets(other.Any()); assets != nil {
+		t.Fatalf("Assets should return nil for %q events; got %v", gallery.Created, assets)
+	}
+}
+
+func publish(ctx context.Context, t *testing.T, bus event.Bus, name string, data any, aggregate string) {
+	evt := event.New(name, data, event.Aggregate(uuid.New(), aggregate, 1))
+	if err := bus.Publish(ctx, evt.Any()); err != nil {
+		t.Fatalf("publish %q event: %v", name, err)
+	}
+}
+
+func await(t *testing.T, purged <-chan []string) []string {
+	select {
+	case <-time.After(3 * time.Second):
+		t.Fatalf("URLs should be purged")
+		return nil
+	case urls := <-purged:
+		return urls
+	}
+}
@@ -0,0 +1,131 @@
+// Package cloudflare provides a cdn.Purger that purges URLs from the cache of a
+// Cloudflare zone.
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/modernice/nice-cms/cdn"
+)
+
+const (
+	// DefaultEndpoint is the base URL of the Cloudflare API.
+	DefaultEndpoint = "https://api.cloudflare.com/client/v4"
+
+	// MaxFiles is the maximum number of URLs per purge request. Purger splits
+	// larger purges into multiple requests.
+	MaxFiles = 30
+)
+
+var _ cdn.Purger = (*Purger)(nil)
+
+// Option is a Purger option.
+type Option func(*Purger)
+
+// Endpoint returns an Option that sets the base URL of the Cloudflare API.
+// Default is DefaultEndpoint.
+func Endpoint(url string) Option {
+	return func(p *Purger) {
+		p.endpoint = strings.TrimRight(url, "/")
+	}
+}
+
+// HTTPClient returns an Option that sets the http.Client that calls the
+// Cloudflare API. Default is http.DefaultClient.
+func HTTPClient(client *http.Client) Option {
+	return func(p *Purger) {
+		p.client = client
+	}
+}
+
+// Purger purges URLs from the cache of a Cloudflare zone using the purge_cache
+// endpoint of the Cloudflare API.
+type Purger struct {
+	zoneID   string
+	token    string
+	endpoint string
+	client   *http.Client
+}
+
+// New returns a Purger for the zone with the given ID. The API token must have
+// the "Cache Purge" permission for the zone.
+func New(zoneID, token string, opts ...Option) *Purger {
+	p := Purger{
+		zoneID:   zoneID,
+		token:    token,
+		endpoint: DefaultEndpoint,
+		client:   http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(&p)
+	}
+	return &p
+}
+
+type purgeRequest struct {
+	Files []string `json:"files"`
+}
+
+type purgeResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Purge purges the given URLs in batches of MaxFiles URLs.
+func (p *Purger) Purge(ctx context.Context, urls []string) error {
+	for len(urls) > 0 {
+		n := len(urls)
+		if n > MaxFiles {
+			n = MaxFiles
+		}
+		if err := p.purge(ctx, urls[:n]); err != nil {
+			return err
+		}
+		urls = urls[n:]
+	}
+	return nil
+}
+
+func (p *Purger) purge(ctx context.Context, urls []string) error {
+	body, err := json.Marshal(purgeRequest{Files: urls})
+	if err != nil {
+		return fmt.Errorf("encode request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/zones/%s/purge_cache", p.endpoint, p.zoneID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("purge cache: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var res purgeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return fmt.Errorf("purge cache: decode %d response: %w", resp.StatusCode, err)
+	}
+
+	if resp.StatusCode >= 300 || !res.Success {
+		msgs := make([]string, len(res.Errors))
+		for i, e := range res.Errors {
+			msgs[i] = fmt.Sprintf("%s (%d)", e.Message, e.Code)
+		}
+		return fmt.Errorf("purge cache: status %d: %s", resp.StatusCode, strings.Join(msgs, "; "))
+	}
+
+	return nil
+}
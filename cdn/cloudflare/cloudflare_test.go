@@ -0,0 +1,74 @@
+package cloudflare_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modernice/nice-cms/cdn/cloudflare"
+)
+
+func TestPurger_Purge(t *testing.T) {
+	var mux sync.Mutex
+	var batches [][]string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/zones/zone/purge_cache" {
+			t.Errorf("request should be POST /zones/zone/purge_cache; is %s %s", r.Method, r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer token" {
+			t.Errorf("Authorization header should be %q; is %q", "Bearer token", auth)
+		}
+
+		var body struct{ Files []string }
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+
+		mux.Lock()
+		batches = append(batches, body.Files)
+		mux.Unlock()
+
+		fmt.Fprint(w, `{"success":true,"errors":[]}`)
+	}))
+	defer srv.Close()
+
+	p := cloudflare.New("zone", "token", cloudflare.Endpoint(srv.URL))
+
+	urls := make([]string, cloudflare.MaxFiles+5)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("https://cdn.example.com/%d.jpg", i)
+	}
+
+	if err := p.Purge(context.Background(), urls); err != nil {
+		t.Fatalf("Purge failed with %q", err)
+	}
+
+	if len(batches) != 2 || len(batches[0]) != cloudflare.MaxFiles || len(batches[1]) != 5 {
+		t.Fatalf("URLs should be purged in batches of %d URLs; got %v", cloudflare.MaxFiles, batches)
+	}
+
+	if batches[1][4] != urls[len(urls)-1] {
+		t.Fatalf("last purged URL should be %q; is %q", urls[len(urls)-1], batches[1][4])
+	}
+}
+
+func TestPurger_Purge_error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"success":false,"errors":[{"code":10000,"message":"Authentication error"}]}`)
+	}))
+	defer srv.Close()
+
+	p := cloudflare.New("zone", "token", cloudflare.Endpoint(srv.URL))
+
+	err := p.Purge(context.Background(), []string{"https://cdn.example.com/foo.jpg"})
+	if err == nil || !strings.Contains(err.Error(), "Authentication error") {
+		t.Fatalf("Purge should fail with the API error; got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+// Package fastly provides a cdn.Purger that purges URLs from the cache of
+// Fastly services.
+package fastly
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/modernice/nice-cms/cdn"
+)
+
+// DefaultEndpoint is the base URL of the Fastly API.
+const DefaultEndpoint = "https://api.fastly.com"
+
+var _ cdn.Purger = (*Purger)(nil)
+
+// Option is a Purger option.
+type Option func(*Purger)
+
+// Endpoint returns an Option that sets the base URL of the Fastly API. Default
+// is DefaultEndpoint.
+func Endpoint(url string) Option {
+	return func(p *Purger) {
+		p.endpoint = strings.TrimRight(url, "/")
+	}
+}
+
+// HTTPClient returns an Option that sets the http.Client that calls the Fastly
+// API. Default is http.DefaultClient.
+func HTTPClient(client *http.Client) Option {
+	return func(p *Purger) {
+		p.client = client
+	}
+}
+
+// Soft returns an Option that soft purges URLs: purged content is marked as
+// stale instead of being removed, so that Fastly can still serve it if the
+// origin is unavailable.
+func Soft() Option {
+	return func(p *Purger) {
+		p.soft = true
+	}
+}
+
+// Purger purges URLs from the cache of Fastly using the single URL purge
+// endpoint of the Fastly API.
+type Purger struct {
+	key      string
+	soft     bool
+	endpoint string
+	client   *http.Client
+}
+
+// New returns a Purger that authenticates with the given API key. The key must
+// have the "purge_select" scope.
+func New(key string, opts ...Option) *Purger {
+	p := Purger{
+		key:      key,
+		endpoint: DefaultEndpoint,
+		client:   http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(&p)
+	}
+	return &p
+}
+
+// Purge purges the given URLs. Fastly purges a single URL per request, so the
+// URLs are purged one after another. Purge stops at the first failed purge.
+// Query strings of the URLs are ignored.
+func (p *Purger) Purge(ctx context.Context, urls []string) error {
+	for _, u := range urls {
+		if err := p.purge(ctx, u); err != nil {
+			return fmt.Errorf("purge %q: %w", u, err)
+		}
+	}
+	return nil
+}
+
+func (p *Purger) purge(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("parse url: %w", err)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("url has no host")
+	}
+
+	// The purge endpoint expects the URL without its scheme.
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+"/purge/"+u.Host+u.EscapedPath(), nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Fastly-Key", p.key)
+	req.Header.Set("Accept", "application/json")
+	if p.soft {
+		req.Header.Set("Fastly-Soft-Purge", "1")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	io.Copy(io.Discard, resp.Body)
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package fastly_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modernice/nice-cms/cdn/fastly"
+)
+
+func TestPurger_Purge(t *testing.T) {
+	var mux sync.Mutex
+	var purged []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("request method should be %s; is %s", http.MethodPost, r.Method)
+		}
+		if key := r.Header.Get("Fastly-Key"); key != "key" {
+			t.Errorf("Fastly-Key header should be %q; is %q", "key", key)
+		}
+		if soft := r.Header.Get("Fastly-Soft-Purge"); soft != "1" {
+			t.Errorf("Fastly-Soft-Purge header should be %q; is %q", "1", soft)
+		}
+
+		mux.Lock()
+		purged = append(purged, r.URL.EscapedPath())
+		mux.Unlock()
+
+		fmt.Fprint(w, `{"status":"ok","id":"1"}`)
+	}))
+	defer srv.Close()
+
+	p := fastly.New("key", fastly.Endpoint(srv.URL), fastly.Soft())
+
+	err := p.Purge(context.Background(), []string{
+		"https://cdn.example.com/foo.jpg",
+		"https://cdn.example.com/shelfs/my%20document.pdf",
+	})
+	if err != nil {
+		t.Fatalf("Purge failed with %q", err)
+	}
+
+	want := []string{
+		"/purge/cdn.example.com/foo.jpg",
+		"/purge/cdn.example.com/shelfs/my%20document.pdf",
+	}
+	if !reflect.DeepEqual(purged, want) {
+		t.Fatalf("Purger should call %v; called %v", want, purged)
+	}
+}
+
+func TestPurger_Purge_error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"msg":"Provided credentials are missing or invalid"}`)
+	}))
+	defer srv.Close()
+
+	p := fastly.New("key", fastly.Endpoint(srv.URL))
+
+	err := p.Purge(context.Background(), []string{"https://cdn.example.com/foo.jpg"})
+	if err == nil || !strings.Contains(err.Error(), "credentials are missing") {
+		t.Fatalf("Purge should fail with the API error; got %v", err)
+	}
+}
@@ -0,0 +1,421 @@
+// Package export exports CMS content as portable bundles and imports bundles
+// into another environment, e.g. to promote content from staging to
+// production. A bundle is a ZIP archive that contains the selected Galleries,
+// Shelfs, Pages and Navs in a "bundle.json" Manifest and the original files of
+// their Stacks and Documents in "files/".
+//
+//	exp := export.NewExporter(
+//		export.Storage(storage),
+//		export.Galleries(galleries),
+//		export.Shelfs(shelfs),
+//		export.Pages(pages),
+//		export.Navs(navs),
+//	)
+//	m, err := exp.Export(ctx, w, export.Selection{Pages: []uuid.UUID{...}})
+//
+//	imp := export.NewImporter(...)
+//	report, err := imp.Import(ctx, r, size, export.Namespace(ns))
+//
+// Unlike package media/export, which archives single Galleries and Shelfs for
+// downloads, bundles can be imported again. Bundles are written and read with
+// the archive functions of package media/export.
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	stdpath "path"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+	mediaexport "github.com/modernice/nice-cms/media/export"
+	"github.com/modernice/nice-cms/media/image"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+// Version is the version of the bundle format that is written by Export.
+const Version = 1
+
+// ManifestName is the name of the Manifest within a bundle.
+const ManifestName = "bundle.json"
+
+var (
+	// ErrNotFound is returned when exporting an aggregate that doesn't exist.
+	ErrNotFound = errors.New("aggregate not found")
+
+	// ErrNotConfigured is returned when exporting or importing content for
+	// which the Exporter or Importer has no repository or storage.
+	ErrNotConfigured = errors.New("not configured")
+)
+
+// Manifest describes the content of a bundle.
+type Manifest struct {
+	Version   int       `json:"version"`
+	Galleries []Gallery `json:"galleries,omitempty"`
+	Shelfs    []Shelf   `json:"shelfs,omitempty"`
+	Pages     []Page    `json:"pages,omitempty"`
+	Navs      []Nav     `json:"navs,omitempty"`
+}
+
+// Gallery is an exported Gallery.
+type Gallery struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	DefaultTags []string  `json:"defaultTags,omitempty"`
+	Stacks      []Stack   `json:"stacks"`
+}
+
+// Stack is an exported Stack. Only the original image or video of a Stack is
+// exported; the other Images are generated again by the post-processor of the
+// importing environment.
+type Stack struct {
+	ID             uuid.UUID         `json:"id"`
+	File           File              `json:"file"`
+	Video          bool              `json:"video,omitempty"`
+	Tags           []string          `json:"tags,omitempty"`
+	Alt            string            `json:"alt,omitempty"`
+	Caption        string            `json:"caption,omitempty"`
+	CustomMetadata map[string]string `json:"customMetadata,omitempty"`
+	FocalPoint     *image.FocalPoint `json:"focalPoint,omitempty"`
+	Crop           *image.Area       `json:"crop,omitempty"`
+}
+
+// Shelf is an exported Shelf.
+type Shelf struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	Documents []Document `json:"documents"`
+}
+
+// Document is an exported Document of a Shelf.
+type Document struct {
+	ID         uuid.UUID         `json:"id"`
+	UniqueName string            `json:"uniqueName,omitempty"`
+	File       File              `json:"file"`
+	Tags       []string          `json:"tags,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	Variants   map[string]File   `json:"variants,omitempty"`
+}
+
+// File is an exported file. The contents of the file are stored at Entry
+// within the bundle and are uploaded to Disk on import, at Path or at a path
+// that is derived from the new UUID of the imported aggregate (see Import).
+type File struct {
+	Name  string `json:"name"`
+	Disk  string `json:"disk"`
+	Path  string `json:"path"`
+	Entry string `json:"entry"`
+}
+
+// Page is an exported Page. Published Pages are exported with their live
+// Fields and are published on import. Unpublished Pages are exported with
+// their draft Fields.
+type Page struct {
+	ID        uuid.UUID     `json:"id"`
+	Name      string        `json:"name"`
+	Fields    []field.Field `json:"fields"`
+	Published bool          `json:"published"`
+}
+
+// Nav is an exported navigation.
+type Nav struct {
+	ID    uuid.UUID  `json:"id"`
+	Name  string     `json:"name"`
+	Items []nav.Item `json:"items"`
+}
+
+// Selection selects the aggregates that are exported.
+type Selection struct {
+	Galleries []uuid.UUID `json:"galleries"`
+	Shelfs    []uuid.UUID `json:"shelfs"`
+	Pages     []uuid.UUID `json:"pages"`
+	Navs      []uuid.UUID `json:"navs"`
+}
+
+// Option is an option for Exporters and Importers.
+type Option func(*config)
+
+// Storage returns an Option that sets the Storage of the files of Stacks and
+// Documents.
+func Storage(storage media.Storage) Option {
+	return func(c *config) {
+		c.storage = storage
+	}
+}
+
+// Galleries returns an Option that sets the Gallery repository.
+func Galleries(repo gallery.Repository) Option {
+	return func(c *config) {
+		c.galleries = repo
+	}
+}
+
+// Shelfs returns an Option that sets the Shelf repository.
+func Shelfs(repo document.Repository) Option {
+	return func(c *config) {
+		c.shelfs = repo
+	}
+}
+
+// Pages returns an Option that sets the Page repository.
+func Pages(repo page.Repository) Option {
+	return func(c *config) {
+		c.pages = repo
+	}
+}
+
+// Navs returns an Option that sets the Nav repository.
+func Navs(repo nav.Repository) Option {
+	return func(c *config) {
+		c.navs = repo
+	}
+}
+
+type config struct {
+	storage   media.Storage
+	galleries gallery.Repository
+	shelfs    document.Repository
+	pages     page.Repository
+	navs      nav.Repository
+}
+
+func newConfig(opts []Option) config {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+func (c config) check(galleries, shelfs, pages, navs int) error {
+	if galleries > 0 && c.galleries == nil {
+		return fmt.Errorf("%w: no gallery repository", ErrNotConfigured)
+	}
+	if shelfs > 0 && c.shelfs == nil {
+		return fmt.Errorf("%w: no shelf repository", ErrNotConfigured)
+	}
+	if pages > 0 && c.pages == nil {
+		return fmt.Errorf("%w: no page repository", ErrNotConfigured)
+	}
+	if navs > 0 && c.navs == nil {
+		return fmt.Errorf("%w: no nav repository", ErrNotConfigured)
+	}
+	if (galleries > 0 || shelfs > 0) && c.storage == nil {
+		return fmt.Errorf("%w: no storage", ErrNotConfigured)
+	}
+	return nil
+}
+
+// Exporter exports bundles.
+type Exporter struct {
+	config
+}
+
+// NewExporter returns a new Exporter.
+func NewExporter(opts ...Option) *Exporter {
+	return &Exporter{config: newConfig(opts)}
+}
+
+// Export writes the bundle of the selected aggregates to w and returns its
+// Manifest. The bundle is written in a single pass, so nothing is written if
+// a selected aggregate doesn't exist.
+func (e *Exporter) Export(ctx context.Context, w io.Writer, sel Selection) (Manifest, error) {
+	m := Manifest{Version: Version}
+
+	if err := e.check(len(sel.Galleries), len(sel.Shelfs), len(sel.Pages), len(sel.Navs)); err != nil {
+		return m, err
+	}
+
+	var files []mediaexport.Entry
+
+	for _, id := range sel.Galleries {
+		g, stacks, err := e.gallery(ctx, id)
+		if err != nil {
+			return m, fmt.Errorf("export gallery %s: %w", id, err)
+		}
+		m.Galleries = append(m.Galleries, g)
+		files = append(files, stacks...)
+	}
+
+	for _, id := range sel.Shelfs {
+		s, docs, err := e.shelf(ctx, id)
+		if err != nil {
+			return m, fmt.Errorf("export shelf %s: %w", id, err)
+		}
+		m.Shelfs = append(m.Shelfs, s)
+		files = append(files, docs...)
+	}
+
+	for _, id := range sel.Pages {
+		p, err := e.page(ctx, id)
+		if err != nil {
+			return m, fmt.Errorf("export page %s: %w", id, err)
+		}
+		m.Pages = append(m.Pages, p)
+	}
+
+	for _, id := range sel.Navs {
+		n, err := e.nav(ctx, id)
+		if err != nil {
+			return m, fmt.Errorf("export nav %s: %w", id, err)
+		}
+		m.Navs = append(m.Navs, n)
+	}
+
+	if err := mediaexport.Archive(ctx, w, e.storage, files, ManifestName, m, nil); err != nil {
+		return m, fmt.Errorf("write bundle: %w", err)
+	}
+
+	return m, nil
+}
+
+func (e *Exporter) gallery(ctx context.Context, id uuid.UUID) (Gallery, []mediaexport.Entry, error) {
+	g, err := e.galleries.Fetch(ctx, id)
+	if err != nil {
+		return Gallery{}, nil, fmt.Errorf("fetch gallery: %w", err)
+	}
+	if g.AggregateVersion() == 0 {
+		return Gallery{}, nil, ErrNotFound
+	}
+
+	out := Gallery{
+		ID:          id,
+		Name:        g.Implementation.Name,
+		DefaultTags: g.DefaultTags,
+		Stacks:      make([]Stack, 0, len(g.Stacks)),
+	}
+
+	var files []mediaexport.Entry
+	for _, s := range g.Stacks {
+		original := s.Original().File
+		if s.Video != nil {
+			original = s.Video.File
+		}
+		if original.Path == "" {
+			continue
+		}
+
+		f := exportFile(original, s.ID.String())
+		files = append(files, mediaexport.Entry{Name: f.Entry, File: original})
+
+		out.Stacks = append(out.Stacks, Stack{
+			ID:             s.ID,
+			File:           f,
+			Video:          s.Video != nil,
+			Tags:           original.Tags,
+			Alt:            s.Alt,
+			Caption:        s.Caption,
+			CustomMetadata: s.CustomMetadata,
+			FocalPoint:     s.FocalPoint,
+			Crop:           s.Crop,
+		})
+	}
+
+	return out, files, nil
+}
+
+func (e *Exporter) shelf(ctx context.Context, id uuid.UUID) (Shelf, []mediaexport.Entry, error) {
+	s, err := e.shelfs.Fetch(ctx, id)
+	if err != nil {
+		return Shelf{}, nil, fmt.Errorf("fetch shelf: %w", err)
+	}
+	if s.AggregateVersion() == 0 {
+		return Shelf{}, nil, ErrNotFound
+	}
+
+	out := Shelf{
+		ID:        id,
+		Name:      s.Name,
+		Documents: make([]Document, 0, len(s.Documents)),
+	}
+
+	var files []mediaexport.Entry
+	for _, doc := range s.Documents {
+		f := exportFile(doc.File, doc.ID.String())
+		files = append(files, mediaexport.Entry{Name: f.Entry, File: doc.File})
+
+		d := Document{
+			ID:         doc.ID,
+			UniqueName: doc.UniqueName,
+			File:       f,
+			Tags:       doc.Tags,
+			Metadata:   doc.Metadata,
+		}
+
+		for _, locale := range doc.Locales() {
+			variant := doc.Variants[locale].File
+			vf := exportFile(variant, doc.ID.String(), locale)
+			files = append(files, mediaexport.Entry{Name: vf.Entry, File: variant})
+
+			if d.Variants == nil {
+				d.Variants = make(map[string]File)
+			}
+			d.Variants[locale] = vf
+		}
+
+		out.Documents = append(out.Documents, d)
+	}
+
+	return out, files, nil
+}
+
+func (e *Exporter) page(ctx context.Context, id uuid.UUID) (Page, error) {
+	p, err := e.pages.Fetch(ctx, id)
+	if err != nil {
+		return Page{}, fmt.Errorf("fetch page: %w", err)
+	}
+	if p.AggregateVersion() == 0 {
+		return Page{}, ErrNotFound
+	}
+
+	fields := p.Fields
+	if p.Published {
+		fields = p.LiveFields
+	}
+
+	return Page{
+		ID:        id,
+		Name:      p.Name,
+		Fields:    fields,
+		Published: p.Published,
+	}, nil
+}
+
+func (e *Exporter) nav(ctx context.Context, id uuid.UUID) (Nav, error) {
+	n, err := e.navs.Fetch(ctx, id)
+	if err != nil {
+		return Nav{}, fmt.Errorf("fetch nav: %w", err)
+	}
+	if n.AggregateVersion() == 0 {
+		return Nav{}, ErrNotFound
+	}
+
+	items := make([]nav.Item, 0)
+	if n.Tree != nil {
+		items = append(items, n.Items...)
+	}
+
+	return Nav{ID: id, Name: n.Name, Items: items}, nil
+}
+
+// exportFile returns the File of f within the bundle. The contents of f are
+// stored in "files/" followed by the given path segments and the base name of
+// f, which makes Entry unique within the bundle.
+func exportFile(f media.File, segments ...string) File {
+	name := stdpath.Base(f.Path)
+	if name == "." || name == "/" {
+		name = "file"
+	}
+	return File{
+		Name:  f.Name,
+		Disk:  f.Disk,
+		Path:  f.Path,
+		Entry: stdpath.Join(append(append([]string{"files"}, segments...), name)...),
+	}
+}
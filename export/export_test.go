@@ -0,0 +1,341 @@
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"image/color"
+	"io"
+	stdpath "path"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/export"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/internal/testutil"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+func options(e *testutil.Env) []export.Option {
+	return []export.Option{
+		export.Storage(e.Storage),
+		export.Galleries(e.Galleries),
+		export.Shelfs(e.Shelfs),
+		export.Pages(e.Pages),
+		export.Navs(e.Navs),
+	}
+}
+
+func TestExporter_Export(t *testing.T) {
+	ctx := context.Background()
+	source := testutil.NewEnv()
+	sel, stack, doc := seed(t, source)
+
+	var buf bytes.Buffer
+	m, err := export.NewExporter(options(source)...).Export(ctx, &buf, sel)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	if len(m.Galleries) != 1 || len(m.Shelfs) != 1 || len(m.Pages) != 1 || len(m.Navs) != 1 {
+		t.Fatalf("Manifest should contain the selected aggregates; got %+v", m)
+	}
+
+	read, err := export.ReadManifest(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	if read.Version != export.Version {
+		t.Fatalf("Manifest should have version %d; got %d", export.Version, read.Version)
+	}
+
+	ns := uuid.New()
+	target := testutil.NewEnv()
+	report, err := export.NewImporter(options(target)...).Import(ctx, bytes.NewReader(buf.Bytes()), int64(buf.Len()), export.Namespace(ns))
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	if report.Galleries != 1 || report.Stacks != 1 || report.Shelfs != 1 || report.Documents != 1 || report.Pages != 1 || report.Navs != 1 {
+		t.Fatalf("Import should report one of each; got %+v", report)
+	}
+	if len(report.IDs) != 6 {
+		t.Fatalf("Report should map the UUIDs of 4 aggregates, 1 Stack and 1 Document; got %v", report.IDs)
+	}
+
+	galleryID := uuid.NewSHA1(ns, sel.Galleries[0][:])
+	g, err := target.Galleries.Fetch(ctx, galleryID)
+	if err != nil {
+		t.Fatalf("fetch gallery: %v", err)
+	}
+	if g.Implementation.Name != "Photos" || len(g.Stacks) != 1 {
+		t.Fatalf("Gallery should be imported; got %q with %d stacks", g.Implementation.Name, len(g.Stacks))
+	}
+	imported := g.Stacks[0]
+	if imported.Alt != stack.Alt || !imported.Original().HasTag("hero") {
+		t.Fatalf("Stack should keep its alt text and tags; got %+v", imported)
+	}
+	assertFile(t, target.Storage, imported.Original().File, stack.Original().Checksum)
+
+	shelfID := uuid.NewSHA1(ns, sel.Shelfs[0][:])
+	s, err := target.Shelfs.Fetch(ctx, shelfID)
+	if err != nil {
+		t.Fatalf("fetch shelf: %v", err)
+	}
+	idoc, err := s.Find("terms")
+	if err != nil {
+		t.Fatalf("Document should be imported with its unique name: %v", err)
+	}
+	if v, ok := idoc.Variant("de"); !ok {
+		t.Fatalf("Document should have its %q variant", "de")
+	} else {
+		assertFile(t, target.Storage, v.File, doc.Variants["de"].Checksum)
+	}
+
+	p, err := target.Pages.Fetch(ctx, uuid.NewSHA1(ns, sel.Pages[0][:]))
+	if err != nil {
+		t.Fatalf("fetch page: %v", err)
+	}
+	if !p.Published {
+		t.Fatalf("Page should be published")
+	}
+
+	f, _ := p.Field("hero")
+	ref, err := f.ImageRef("")
+	if err != nil {
+		t.Fatalf("image ref: %v", err)
+	}
+	if ref != (field.ImageRef{Gallery: galleryID, Stack: imported.ID}) {
+		t.Fatalf("Image field should reference the imported Stack; got %+v", ref)
+	}
+
+	f, _ = p.Field("terms")
+	dref, err := f.DocumentRef("")
+	if err != nil {
+		t.Fatalf("document ref: %v", err)
+	}
+	if dref != (field.DocumentRef{Shelf: shelfID, Document: idoc.ID}) {
+		t.Fatalf("Document field should reference the imported Document; got %+v", dref)
+	}
+
+	f, _ = p.Field("cards")
+	items, err := f.Items("")
+	if err != nil {
+		t.Fatalf("items: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("List field should have %d item; got %d", 1, len(items))
+	}
+	b, _ := json.Marshal(items[0]["image"])
+	if cref, err := field.ParseImageRef(string(b)); err != nil || cref != (field.ImageRef{Gallery: galleryID, Stack: imported.ID}) {
+		t.Fatalf("Image props of List fields should reference the imported Stack; got %s", b)
+	}
+
+	n, err := target.Navs.Fetch(ctx, uuid.NewSHA1(ns, sel.Navs[0][:]))
+	if err != nil {
+		t.Fatalf("fetch nav: %v", err)
+	}
+	item, err := n.Item("about.page")
+	if err != nil {
+		t.Fatalf("Nav should have the nested item: %v", err)
+	}
+	if item.Page == nil || *item.Page != p.ID {
+		t.Fatalf("PageLink item should link to the imported Page %s; got %v", p.ID, item.Page)
+	}
+}
+
+func TestImporter_Import_exists(t *testing.T) {
+	ctx := context.Background()
+	source := testutil.NewEnv()
+	sel, stack, _ := seed(t, source)
+
+	var buf bytes.Buffer
+	if _, err := export.NewExporter(options(source)...).Export(ctx, &buf, sel); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	imp := export.NewImporter(options(source)...)
+
+	_, err := imp.Import(ctx, bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if !errors.Is(err, export.ErrExists) {
+		t.Fatalf("importing existing aggregates should fail with %q; got %v", export.ErrExists, err)
+	}
+
+	report, err := imp.Import(ctx, bytes.NewReader(buf.Bytes()), int64(buf.Len()), export.RandomIDs())
+	if err != nil {
+		t.Fatalf("importing with random UUIDs should not fail; got %v", err)
+	}
+
+	galleryID := report.IDs[sel.Galleries[0]]
+	g, err := source.Galleries.Fetch(ctx, galleryID)
+	if err != nil {
+		t.Fatalf("fetch gallery: %v", err)
+	}
+	imported := g.Stacks[0].Original().File
+	if want := "/photos/" + galleryID.String() + "/hero.png"; imported.Path != want {
+		t.Fatalf("files of imported Stacks should be stored at paths of the new UUIDs; got %q; want %q", imported.Path, want)
+	}
+
+	assertFile(t, source.Storage, stack.Original().File, stack.Original().Checksum)
+}
+
+func TestImporter_Import_rollback(t *testing.T) {
+	ctx := context.Background()
+	source := testutil.NewEnv()
+	sel, stack, _ := seed(t, source)
+
+	var buf bytes.Buffer
+	if _, err := export.NewExporter(options(source)...).Export(ctx, &buf, sel); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	target := testutil.NewEnv()
+	opts := append(options(target), export.Navs(failingNavs{target.Navs}))
+
+	ns := uuid.New()
+	if _, err := export.NewImporter(opts...).Import(ctx, bytes.NewReader(buf.Bytes()), int64(buf.Len()), export.Namespace(ns)); !errors.Is(err, errSaveFailed) {
+		t.Fatalf("Import should fail with %q; got %v", errSaveFailed, err)
+	}
+
+	galleryID := uuid.NewSHA1(ns, sel.Galleries[0][:])
+	if g, err := target.Galleries.Fetch(ctx, galleryID); err == nil && g.AggregateVersion() > 0 {
+		t.Fatalf("saved Gallery should be deleted after a failed import")
+	}
+	if p, err := target.Pages.Fetch(ctx, uuid.NewSHA1(ns, sel.Pages[0][:])); err == nil && p.AggregateVersion() > 0 {
+		t.Fatalf("saved Page should be deleted after a failed import")
+	}
+
+	disk, _ := target.Storage.Disk(testutil.Disk)
+	path := "/photos/" + galleryID.String() + "/" + stdpath.Base(stack.Original().Path)
+	if _, err := media.Stat(ctx, disk, path); !errors.Is(err, media.ErrFileNotFound) {
+		t.Fatalf("uploaded file %q should be deleted after a failed import; got %v", path, err)
+	}
+}
+
+func TestExporter_Export_notFound(t *testing.T) {
+	e := testutil.NewEnv()
+	exp := export.NewExporter(options(e)...)
+
+	_, err := exp.Export(context.Background(), io.Discard, export.Selection{Pages: []uuid.UUID{uuid.New()}})
+	if !errors.Is(err, export.ErrNotFound) {
+		t.Fatalf("exporting an unknown Page should fail with %q; got %v", export.ErrNotFound, err)
+	}
+
+	_, err = export.NewExporter().Export(context.Background(), io.Discard, export.Selection{Navs: []uuid.UUID{uuid.New()}})
+	if !errors.Is(err, export.ErrNotConfigured) {
+		t.Fatalf("exporting without a Nav repository should fail with %q; got %v", export.ErrNotConfigured, err)
+	}
+}
+
+func seed(t *testing.T, e *testutil.Env) (export.Selection, gallery.Stack, document.Document) {
+	ctx := context.Background()
+
+	g := gallery.New(uuid.New())
+	if err := g.Create("Photos"); err != nil {
+		t.Fatalf("create gallery: %v", err)
+	}
+	_, img := imggen.ColoredRectangle(8, 8, color.Black)
+	stack, err := g.Upload(ctx, e.Storage, img, "Hero", testutil.Disk, "/photos/hero.png")
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if stack, err = g.Tag(ctx, stack, "hero"); err != nil {
+		t.Fatalf("tag: %v", err)
+	}
+	if stack, err = g.UpdateStackMetadata(ctx, stack.ID, "A black square", ""); err != nil {
+		t.Fatalf("update metadata: %v", err)
+	}
+	if err := e.Galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save gallery: %v", err)
+	}
+
+	s := document.NewShelf(uuid.New())
+	if err := s.Create("Downloads"); err != nil {
+		t.Fatalf("create shelf: %v", err)
+	}
+	doc, err := s.Add(ctx, e.Storage, bytes.NewReader([]byte("terms")), "terms", "Terms", testutil.Disk, "/downloads/terms.pdf")
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+	if doc, err = s.AddVariant(ctx, e.Storage, bytes.NewReader([]byte("agb")), doc.ID, "de", "AGB", testutil.Disk, "/downloads/agb.pdf"); err != nil {
+		t.Fatalf("add variant: %v", err)
+	}
+	if err := e.Shelfs.Save(ctx, s); err != nil {
+		t.Fatalf("save shelf: %v", err)
+	}
+
+	p := page.New(uuid.New())
+	if err := p.Create("About",
+		field.NewImage("hero", field.ImageRef{Gallery: g.ID, Stack: stack.ID}),
+		field.NewDocument("terms", field.DocumentRef{Shelf: s.ID, Document: doc.ID}),
+		field.NewList("cards", cardSchema, field.Items{{
+			"title": "Hero",
+			"image": field.ImageRef{Gallery: g.ID, Stack: stack.ID},
+		}}),
+	); err != nil {
+		t.Fatalf("create page: %v", err)
+	}
+	if err := p.Publish(); err != nil {
+		t.Fatalf("publish page: %v", err)
+	}
+	if err := e.Pages.Save(ctx, p); err != nil {
+		t.Fatalf("save page: %v", err)
+	}
+
+	n := nav.New(uuid.New())
+	if err := n.Create("main"); err != nil {
+		t.Fatalf("create nav: %v", err)
+	}
+	if err := n.Append(nav.NewLabel("about", "About", nav.SubTree(nav.NewPageLink("page", p.ID)))); err != nil {
+		t.Fatalf("append items: %v", err)
+	}
+	if err := e.Navs.Save(ctx, n); err != nil {
+		t.Fatalf("save nav: %v", err)
+	}
+
+	return export.Selection{
+		Galleries: []uuid.UUID{g.ID},
+		Shelfs:    []uuid.UUID{s.ID},
+		Pages:     []uuid.UUID{p.ID},
+		Navs:      []uuid.UUID{n.ID},
+	}, stack, doc
+}
+
+var errSaveFailed = errors.New("save failed")
+
+type failingNavs struct {
+	nav.Repository
+}
+
+func (failingNavs) Save(context.Context, *nav.Nav) error {
+	return errSaveFailed
+}
+
+var cardSchema = field.Schema{
+	Props: []field.Prop{
+		{Name: "title", Type: field.Text},
+		{Name: "image", Type: field.Image},
+	},
+}
+
+func assertFile(t *testing.T, storage media.Storage, f media.File, checksum string) {
+	r, err := f.Reader(context.Background(), storage)
+	if err != nil {
+		t.Fatalf("read %q: %v", f.Path, err)
+	}
+	defer r.Close()
+
+	got, err := media.Checksum(r)
+	if err != nil {
+		t.Fatalf("checksum %q: %v", f.Path, err)
+	}
+	if got != checksum {
+		t.Fatalf("%q should have checksum %q; got %q", f.Path, checksum, got)
+	}
+}
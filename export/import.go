@@ -0,0 +1,670 @@
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	stdpath "path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+	mediaexport "github.com/modernice/nice-cms/media/export"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+var (
+	// ErrInvalidBundle is returned when importing an invalid bundle.
+	ErrInvalidBundle = errors.New("invalid bundle")
+
+	// ErrExists is returned when importing an aggregate whose UUID is already
+	// used by an existing aggregate.
+	ErrExists = errors.New("aggregate already exists")
+)
+
+// ImportOption is an option for Import.
+type ImportOption func(*importConfig)
+
+type importConfig struct {
+	mapID func(uuid.UUID) uuid.UUID
+}
+
+// MapIDs returns an ImportOption that maps the UUIDs of the imported
+// Galleries, Shelfs, Pages and Navs to the UUIDs of the aggregates that are
+// created by the import. By default, the aggregates keep their UUIDs.
+func MapIDs(fn func(uuid.UUID) uuid.UUID) ImportOption {
+	return func(cfg *importConfig) {
+		cfg.mapID = fn
+	}
+}
+
+// RandomIDs returns an ImportOption that creates the imported aggregates with
+// random UUIDs, so that a bundle can be imported multiple times.
+func RandomIDs() ImportOption {
+	return MapIDs(func(uuid.UUID) uuid.UUID {
+		return uuid.New()
+	})
+}
+
+// Namespace returns an ImportOption that creates the imported aggregates with
+// UUIDs that are derived from their original UUIDs within the given
+// namespace. Importing the same bundle into the same namespace again results
+// in the same UUIDs.
+func Namespace(ns uuid.UUID) ImportOption {
+	return MapIDs(func(id uuid.UUID) uuid.UUID {
+		return uuid.NewSHA1(ns, id[:])
+	})
+}
+
+// Report reports the content that was created by Import.
+type Report struct {
+	Galleries int `json:"galleries"`
+	Stacks    int `json:"stacks"`
+	Shelfs    int `json:"shelfs"`
+	Documents int `json:"documents"`
+	Pages     int `json:"pages"`
+	Navs      int `json:"navs"`
+
+	// IDs maps the UUIDs of the exported aggregates, Stacks and Documents to
+	// the UUIDs of the imported ones. Stacks and Documents always get new
+	// UUIDs.
+	IDs map[uuid.UUID]uuid.UUID `json:"ids"`
+}
+
+// Importer imports bundles.
+type Importer struct {
+	config
+}
+
+// NewImporter returns a new Importer.
+func NewImporter(opts ...Option) *Importer {
+	return &Importer{config: newConfig(opts)}
+}
+
+// ReadManifest reads and validates the Manifest of the bundle in r.
+func ReadManifest(r io.ReaderAt, size int64) (Manifest, error) {
+	m, _, err := open(r, size)
+	return m, err
+}
+
+func open(r io.ReaderAt, size int64) (Manifest, *zip.Reader, error) {
+	var m Manifest
+	zr, err := mediaexport.OpenArchive(r, size, ManifestName, &m)
+	if err != nil {
+		return m, zr, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+
+	if m.Version < 1 || m.Version > Version {
+		return m, zr, fmt.Errorf("%w: unsupported version %d", ErrInvalidBundle, m.Version)
+	}
+
+	return m, zr, nil
+}
+
+// Import creates the content of the bundle in r. Import checks that none of
+// the aggregates and files exists before it creates anything and returns
+// ErrExists otherwise. Image and Document Fields and props of List Fields of
+// the imported Pages and PageLink Items of the imported Navs are rewritten to
+// reference the imported aggregates; references to aggregates that are not part
+// of the bundle are kept.
+//
+// Files are uploaded to their exported paths or, if their aggregate gets a new
+// UUID, to paths that are derived from the new UUID. Import is
+// atomic: the files of all Stacks and Documents are uploaded before any
+// aggregate is saved. If the import fails, the uploaded files and the saved
+// aggregates are removed again.
+func (imp *Importer) Import(ctx context.Context, r io.ReaderAt, size int64, opts ...ImportOption) (Report, error) {
+	cfg := importConfig{mapID: func(id uuid.UUID) uuid.UUID { return id }}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	m, zr, err := open(r, size)
+	if err != nil {
+		return Report{}, err
+	}
+
+	if err := imp.check(len(m.Galleries), len(m.Shelfs), len(m.Pages), len(m.Navs)); err != nil {
+		return Report{}, err
+	}
+
+	ids := make(map[uuid.UUID]uuid.UUID)
+	if err := imp.mapIDs(ctx, m, cfg.mapID, ids); err != nil {
+		return Report{}, err
+	}
+
+	if err := imp.checkFiles(ctx, m, ids); err != nil {
+		return Report{}, err
+	}
+
+	tx := importTx{imp: imp, zr: zr, report: Report{IDs: ids}}
+	if err := tx.run(ctx, m); err != nil {
+		tx.rollback(ctx)
+		return Report{}, err
+	}
+
+	return tx.report, nil
+}
+
+// mapIDs maps the UUIDs of the aggregates of the Manifest and checks that no
+// aggregate with a mapped UUID exists.
+func (imp *Importer) mapIDs(ctx context.Context, m Manifest, mapID func(uuid.UUID) uuid.UUID, ids map[uuid.UUID]uuid.UUID) error {
+	add := func(kind string, id uuid.UUID, exists func(uuid.UUID) (bool, error)) error {
+		if _, ok := ids[id]; ok {
+			return fmt.Errorf("%w: duplicate %s %s", ErrInvalidBundle, kind, id)
+		}
+		mapped := mapID(id)
+		ok, err := exists(mapped)
+		if err != nil {
+			return fmt.Errorf("fetch %s %s: %w", kind, mapped, err)
+		}
+		if ok {
+			return fmt.Errorf("%s %s: %w", kind, mapped, ErrExists)
+		}
+		ids[id] = mapped
+		return nil
+	}
+
+	for _, g := range m.Galleries {
+		if err := add("gallery", g.ID, func(id uuid.UUID) (bool, error) {
+			g, err := imp.galleries.Fetch(ctx, id)
+			return err == nil && g.AggregateVersion() > 0, ignoreNotFound(err, gallery.ErrNotFound)
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range m.Shelfs {
+		if err := add("shelf", s.ID, func(id uuid.UUID) (bool, error) {
+			s, err := imp.shelfs.Fetch(ctx, id)
+			return err == nil && s.AggregateVersion() > 0, ignoreNotFound(err, document.ErrShelfNotFound)
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range m.Pages {
+		if err := add("page", p.ID, func(id uuid.UUID) (bool, error) {
+			p, err := imp.pages.Fetch(ctx, id)
+			return err == nil && p.AggregateVersion() > 0, err
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, n := range m.Navs {
+		if err := add("nav", n.ID, func(id uuid.UUID) (bool, error) {
+			n, err := imp.navs.Fetch(ctx, id)
+			return err == nil && n.AggregateVersion() > 0, err
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func ignoreNotFound(err, notFound error) error {
+	if errors.Is(err, notFound) {
+		return nil
+	}
+	return err
+}
+
+// checkFiles checks that none of the files of the bundle exists at its import
+// path (see importPath), so that a failed import can remove the uploaded files
+// without removing existing ones.
+func (imp *Importer) checkFiles(ctx context.Context, m Manifest, ids map[uuid.UUID]uuid.UUID) error {
+	check := func(owner uuid.UUID, f File) error {
+		disk, err := imp.storage.Disk(f.Disk)
+		if err != nil {
+			return fmt.Errorf("get %q disk: %w", f.Disk, err)
+		}
+		path := importPath(f.Path, owner, ids[owner])
+		_, err = media.Stat(ctx, disk, path)
+		if err == nil {
+			return fmt.Errorf("file %q: %w", path, ErrExists)
+		}
+		if !errors.Is(err, media.ErrFileNotFound) {
+			return fmt.Errorf("stat %q: %w", path, err)
+		}
+		return nil
+	}
+
+	for _, g := range m.Galleries {
+		for _, s := range g.Stacks {
+			if err := check(g.ID, s.File); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, s := range m.Shelfs {
+		for _, d := range s.Documents {
+			if err := check(s.ID, d.File); err != nil {
+				return err
+			}
+			for _, f := range d.Variants {
+				if err := check(s.ID, f); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// importPath returns the storage path of an imported file of the aggregate
+// from, which is imported as the aggregate to. If the UUID of the aggregate
+// changes, the UUID is replaced within path, or, if path doesn't contain the
+// UUID, the file is stored in a directory named after the new UUID. Importing
+// a bundle with new UUIDs into the environment it was exported from therefore
+// doesn't overwrite the exported files.
+func importPath(path string, from, to uuid.UUID) string {
+	if from == to {
+		return path
+	}
+	if strings.Contains(path, from.String()) {
+		return strings.ReplaceAll(path, from.String(), to.String())
+	}
+	return stdpath.Join(stdpath.Dir(path), to.String(), stdpath.Base(path))
+}
+
+// importTx creates the content of a bundle. The aggregates are saved only
+// after the files of all Stacks and Documents have been uploaded.
+type importTx struct {
+	imp    *Importer
+	zr     *zip.Reader
+	report Report
+
+	galleries []*gallery.Gallery
+	shelfs    []*document.Shelf
+	pages     []*page.Page
+	navs      []*nav.Nav
+
+	saved []func(context.Context) error
+}
+
+func (tx *importTx) run(ctx context.Context, m Manifest) error {
+	for _, g := range m.Galleries {
+		if err := tx.importGallery(ctx, g); err != nil {
+			return fmt.Errorf("import gallery %q: %w", g.Name, err)
+		}
+	}
+
+	for _, s := range m.Shelfs {
+		if err := tx.importShelf(ctx, s); err != nil {
+			return fmt.Errorf("import shelf %q: %w", s.Name, err)
+		}
+	}
+
+	for _, p := range m.Pages {
+		if err := tx.importPage(p); err != nil {
+			return fmt.Errorf("import page %q: %w", p.Name, err)
+		}
+	}
+
+	for _, n := range m.Navs {
+		if err := tx.importNav(n); err != nil {
+			return fmt.Errorf("import nav %q: %w", n.Name, err)
+		}
+	}
+
+	return tx.save(ctx)
+}
+
+func (tx *importTx) save(ctx context.Context) error {
+	for _, g := range tx.galleries {
+		if err := tx.imp.galleries.Save(ctx, g); err != nil {
+			return fmt.Errorf("save gallery %q: %w", g.Implementation.Name, err)
+		}
+		g := g
+		tx.saved = append(tx.saved, func(ctx context.Context) error { return tx.imp.galleries.Delete(ctx, g) })
+	}
+
+	for _, s := range tx.shelfs {
+		if err := tx.imp.shelfs.Save(ctx, s); err != nil {
+			return fmt.Errorf("save shelf %q: %w", s.Name, err)
+		}
+		s := s
+		tx.saved = append(tx.saved, func(ctx context.Context) error { return tx.imp.shelfs.Delete(ctx, s) })
+	}
+
+	for _, p := range tx.pages {
+		if err := tx.imp.pages.Save(ctx, p); err != nil {
+			return fmt.Errorf("save page %q: %w", p.Name, err)
+		}
+		p := p
+		tx.saved = append(tx.saved, func(ctx context.Context) error { return tx.imp.pages.Delete(ctx, p) })
+	}
+
+	for _, n := range tx.navs {
+		if err := tx.imp.navs.Save(ctx, n); err != nil {
+			return fmt.Errorf("save nav %q: %w", n.Name, err)
+		}
+		n := n
+		tx.saved = append(tx.saved, func(ctx context.Context) error { return tx.imp.navs.Delete(ctx, n) })
+	}
+
+	return nil
+}
+
+// rollback deletes the saved aggregates and the uploaded files. rollback is
+// best-effort and ignores errors, because the import already failed.
+func (tx *importTx) rollback(ctx context.Context) {
+	for i := len(tx.saved) - 1; i >= 0; i-- {
+		tx.saved[i](ctx)
+	}
+
+	for _, g := range tx.galleries {
+		for _, stack := range g.Stacks {
+			g.Delete(ctx, tx.imp.storage, stack)
+		}
+	}
+
+	for _, s := range tx.shelfs {
+		for _, doc := range s.Documents {
+			s.Remove(ctx, tx.imp.storage, doc.ID)
+		}
+	}
+}
+
+func (tx *importTx) importGallery(ctx context.Context, eg Gallery) error {
+	g := gallery.New(tx.report.IDs[eg.ID])
+	if err := g.Create(eg.Name); err != nil {
+		return err
+	}
+	tx.galleries = append(tx.galleries, g)
+
+	if len(eg.DefaultTags) > 0 {
+		if err := g.SetDefaultTags(eg.DefaultTags...); err != nil {
+			return err
+		}
+	}
+
+	for _, es := range eg.Stacks {
+		stack, err := tx.importStack(ctx, g, eg.ID, es)
+		if err != nil {
+			return fmt.Errorf("import stack %s: %w", es.ID, err)
+		}
+		tx.report.IDs[es.ID] = stack.ID
+		tx.report.Stacks++
+	}
+
+	tx.report.Galleries++
+
+	return nil
+}
+
+func (tx *importTx) importStack(ctx context.Context, g *gallery.Gallery, exported uuid.UUID, es Stack) (gallery.Stack, error) {
+	r, err := tx.zr.Open(es.File.Entry)
+	if err != nil {
+		return gallery.Stack{}, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+	defer r.Close()
+
+	upload := g.Upload
+	if es.Video {
+		upload = g.UploadVideo
+	}
+
+	path := importPath(es.File.Path, exported, g.ID)
+	stack, err := upload(ctx, tx.imp.storage, r, es.File.Name, es.File.Disk, path)
+	if err != nil {
+		return stack, err
+	}
+
+	if len(es.Tags) > 0 {
+		if stack, err = g.Tag(ctx, stack, es.Tags...); err != nil {
+			return stack, err
+		}
+	}
+
+	if stack, err = g.UpdateStackMetadata(ctx, stack.ID, es.Alt, es.Caption); err != nil {
+		return stack, err
+	}
+
+	if len(es.CustomMetadata) > 0 {
+		if stack, err = g.SetCustomMetadata(stack.ID, es.CustomMetadata); err != nil {
+			return stack, err
+		}
+	}
+
+	if es.FocalPoint != nil {
+		if stack, err = g.SetFocalPoint(stack.ID, *es.FocalPoint); err != nil {
+			return stack, err
+		}
+	}
+
+	if es.Crop != nil {
+		if stack, err = g.CropStack(stack.ID, *es.Crop); err != nil {
+			return stack, err
+		}
+	}
+
+	return stack, nil
+}
+
+func (tx *importTx) importShelf(ctx context.Context, es Shelf) error {
+	s := document.NewShelf(tx.report.IDs[es.ID])
+	if err := s.Create(es.Name); err != nil {
+		return err
+	}
+	tx.shelfs = append(tx.shelfs, s)
+
+	for _, ed := range es.Documents {
+		doc, err := tx.importDocument(ctx, s, es.ID, ed)
+		if err != nil {
+			return fmt.Errorf("import document %s: %w", ed.ID, err)
+		}
+		tx.report.IDs[ed.ID] = doc.ID
+		tx.report.Documents++
+	}
+
+	tx.report.Shelfs++
+
+	return nil
+}
+
+func (tx *importTx) importDocument(ctx context.Context, s *document.Shelf, exported uuid.UUID, ed Document) (document.Document, error) {
+	doc, err := tx.upload(ed.File, func(r io.Reader) (document.Document, error) {
+		path := importPath(ed.File.Path, exported, s.ID)
+		return s.Add(ctx, tx.imp.storage, r, ed.UniqueName, ed.File.Name, ed.File.Disk, path)
+	})
+	if err != nil {
+		return doc, err
+	}
+
+	for locale, f := range ed.Variants {
+		if doc, err = tx.upload(f, func(r io.Reader) (document.Document, error) {
+			path := importPath(f.Path, exported, s.ID)
+			return s.AddVariant(ctx, tx.imp.storage, r, doc.ID, locale, f.Name, f.Disk, path)
+		}); err != nil {
+			return doc, fmt.Errorf("add %q variant: %w", locale, err)
+		}
+	}
+
+	if len(ed.Tags) > 0 {
+		if doc, err = s.Tag(doc.ID, ed.Tags...); err != nil {
+			return doc, err
+		}
+	}
+
+	if len(ed.Metadata) > 0 {
+		if doc, err = s.SetMetadata(doc.ID, ed.Metadata); err != nil {
+			return doc, err
+		}
+	}
+
+	return doc, nil
+}
+
+func (tx *importTx) upload(f File, fn func(io.Reader) (document.Document, error)) (document.Document, error) {
+	r, err := tx.zr.Open(f.Entry)
+	if err != nil {
+		return document.Document{}, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+	defer r.Close()
+	return fn(r)
+}
+
+func (tx *importTx) importPage(ep Page) error {
+	var guarded, fields []field.Field
+	for _, f := range ep.Fields {
+		f = remapField(f, tx.report.IDs)
+		if f.Guarded {
+			guarded = append(guarded, f)
+		} else {
+			fields = append(fields, f)
+		}
+	}
+
+	p := page.New(tx.report.IDs[ep.ID])
+	if err := p.Create(ep.Name, guarded...); err != nil {
+		return err
+	}
+
+	if len(fields) > 0 {
+		if err := p.Add(fields...); err != nil {
+			return err
+		}
+	}
+
+	if ep.Published {
+		if err := p.Publish(); err != nil {
+			return err
+		}
+	}
+
+	tx.pages = append(tx.pages, p)
+	tx.report.Pages++
+
+	return nil
+}
+
+// remapField returns a copy of the Field in which the references of Image and
+// Document Fields and of the Image and Document props of List Fields to
+// exported aggregates are replaced by references to the imported aggregates.
+func remapField(f field.Field, ids map[uuid.UUID]uuid.UUID) field.Field {
+	values := make(map[string]string, len(f.Values))
+	for locale, value := range f.Values {
+		if f.Type == field.List && f.Schema != nil {
+			values[locale] = remapList(*f.Schema, value, ids)
+			continue
+		}
+		values[locale] = remapRef(f.Type, value, ids)
+	}
+	f.Values = values
+	return f
+}
+
+// remapList remaps the references of the Image and Document props of the items
+// of a List value, including the items of nested List props. Values that cannot
+// be decoded are kept.
+func remapList(s field.Schema, value string, ids map[uuid.UUID]uuid.UUID) string {
+	items, err := field.ParseItems(value)
+	if err != nil {
+		return value
+	}
+
+	for _, item := range items {
+		for _, p := range s.Props {
+			v, ok := item[p.Name]
+			if !ok || v == nil {
+				continue
+			}
+
+			b, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+
+			switch {
+			case p.Type == field.Image || p.Type == field.Document:
+				item[p.Name] = json.RawMessage(remapRef(p.Type, string(b), ids))
+			case p.Type == field.List && p.Items != nil:
+				item[p.Name] = json.RawMessage(remapList(*p.Items, string(b), ids))
+			}
+		}
+	}
+
+	out, err := items.JSON()
+	if err != nil {
+		return value
+	}
+
+	return out
+}
+
+func remapRef(typ field.Type, value string, ids map[uuid.UUID]uuid.UUID) string {
+	switch typ {
+	case field.Image:
+		ref, err := field.ParseImageRef(value)
+		if err != nil || ref.IsZero() {
+			return value
+		}
+		gallery, ok := ids[ref.Gallery]
+		stack, ok2 := ids[ref.Stack]
+		if !ok || !ok2 {
+			return value
+		}
+		return field.ImageRef{Gallery: gallery, Stack: stack}.String()
+	case field.Document:
+		ref, err := field.ParseDocumentRef(value)
+		if err != nil || ref.IsZero() {
+			return value
+		}
+		shelf, ok := ids[ref.Shelf]
+		doc, ok2 := ids[ref.Document]
+		if !ok || !ok2 {
+			return value
+		}
+		return field.DocumentRef{Shelf: shelf, Document: doc}.String()
+	default:
+		return value
+	}
+}
+
+func (tx *importTx) importNav(en Nav) error {
+	n := nav.New(tx.report.IDs[en.ID])
+	if err := n.Create(en.Name); err != nil {
+		return err
+	}
+
+	if len(en.Items) > 0 {
+		if err := n.Append(remapItems(en.Items, tx.report.IDs)...); err != nil {
+			return fmt.Errorf("append items: %w", err)
+		}
+	}
+
+	tx.navs = append(tx.navs, n)
+	tx.report.Navs++
+
+	return nil
+}
+
+// remapItems returns copies of the Items in which PageLink Items to imported
+// Pages link to the imported Pages.
+func remapItems(items []nav.Item, ids map[uuid.UUID]uuid.UUID) []nav.Item {
+	out := make([]nav.Item, len(items))
+	for i, item := range items {
+		if item.Page != nil {
+			if id, ok := ids[*item.Page]; ok {
+				item.Page = &id
+			}
+		}
+		if item.Tree != nil {
+			item.Tree = nav.NewTree(remapItems(item.Tree.Items, ids)...)
+		}
+		out[i] = item
+	}
+	return out
+}
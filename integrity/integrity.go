@@ -0,0 +1,496 @@
+// Package integrity checks the links between navigations, pages and media.
+//
+// A Checker watches the deletion of Pages, Stacks and Documents and flags the
+// nav Items that link to the removed content and the Image and Document fields
+// of Pages that reference it (see mediaref.Monitor):
+//
+//	c := integrity.NewChecker(
+//		integrity.MediaURLs(cdn.BaseURL("https://cdn.example.com")),
+//		integrity.PageURLs(func(id uuid.UUID, name string) []string {
+//			return []string{"/" + name}
+//		}),
+//		integrity.AutoRemove(commands),
+//	)
+//
+//	errs, err := c.Run(ctx, bus, store)
+//
+// Nav Items link to removed content if one of their paths is a URL of a
+// removed Page or of a file of a removed Stack or Document, or if they are
+// PageLink Items of a removed Page.
+package integrity
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/command"
+	"github.com/modernice/goes/command/cmdbus/dispatch"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/helper/streams"
+	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/cdn"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/projector"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/mediaref"
+)
+
+// TargetType is the type of removed content.
+type TargetType string
+
+// Target types
+const (
+	PageTarget     = TargetType("page")
+	StackTarget    = TargetType("stack")
+	DocumentTarget = TargetType("document")
+)
+
+// Target is removed content that is linked by a nav Item.
+type Target struct {
+	Type TargetType `json:"type"`
+	ID   uuid.UUID  `json:"id"`
+
+	// Parent is the UUID of the Gallery of a Stack or the Shelf of a
+	// Document.
+	Parent uuid.UUID `json:"parent"`
+
+	RemovedAt time.Time `json:"removedAt"`
+}
+
+// NavReference is a nav Item that links to removed content.
+type NavReference struct {
+	NavID uuid.UUID `json:"navId"`
+	Nav   string    `json:"nav"`
+
+	// Item is the dot-separated path of the Item (see nav.Nav.Remove).
+	Item string `json:"item"`
+
+	// Locale and Path are the locale and the path of the Item that link to
+	// the Target. Both are empty for PageLink Items.
+	Locale string `json:"locale"`
+	Path   string `json:"path"`
+	Target Target `json:"target"`
+}
+
+// Report is a report of broken references.
+type Report struct {
+	Navs  []NavReference    `json:"navs"`
+	Pages []mediaref.Report `json:"pages"`
+}
+
+// Empty returns whether the Report has no broken references.
+func (r Report) Empty() bool {
+	return len(r.Navs) == 0 && len(r.Pages) == 0
+}
+
+// ReportHandler handles Reports. ReportHandlers are called synchronously while
+// the Checker is projected and must not block.
+type ReportHandler func(context.Context, Report)
+
+// Option is a Checker option.
+type Option func(*Checker)
+
+// MediaURLs returns an Option that sets the Resolver for the URLs of removed
+// Stack and Document files. By default, the URL of a file is its path.
+func MediaURLs(resolve cdn.Resolver) Option {
+	return func(c *Checker) {
+		c.mediaURLs = resolve
+	}
+}
+
+// PageURLs returns an Option that sets the function that returns the URLs of
+// a removed Page. By default, only PageLink Items are checked for links to
+// Pages.
+func PageURLs(fn func(id uuid.UUID, name string) []string) Option {
+	return func(c *Checker) {
+		c.pageURLs = fn
+	}
+}
+
+// OnBroken returns an Option that registers a ReportHandler that is called
+// with the references that are broken by a removed Page, Stack or Document.
+func OnBroken(fn ReportHandler) Option {
+	return func(c *Checker) {
+		c.handlers = append(c.handlers, fn)
+	}
+}
+
+// AutoRemove returns an Option that removes broken references by dispatching
+// commands over the given command bus: nav Items that link to removed content
+// are removed (see nav.RemoveItemsCmd) and broken references in the draft
+// Fields of Pages are cleared (see page.UpdateFieldCmd). Broken references in
+// the published Fields of a Page remain until the Page is published again. The
+// commands must be handled by nav.HandleCommands and page.HandleCommands.
+func AutoRemove(commands command.Bus) Option {
+	return func(c *Checker) {
+		c.commands = commands
+	}
+}
+
+// Projection returns an Option that configures the error handling of the
+// projection.
+func Projection(opts ...projector.Option) Option {
+	return func(c *Checker) {
+		c.projectorOpts = append(c.projectorOpts, opts...)
+	}
+}
+
+// Checker is a projection of the links between navigations, Pages and media
+// that reports broken references. Checker is thread-safe.
+type Checker struct {
+	projector     *projector.Projector
+	projectorOpts []projector.Option
+	mediaURLs     cdn.Resolver
+	pageURLs      func(uuid.UUID, string) []string
+	handlers      []ReportHandler
+	commands      command.Bus
+
+	// refs projects the references of Pages to Stacks and Documents.
+	refs *mediaref.Monitor
+
+	mux  sync.RWMutex
+	ctx  context.Context
+	navs map[uuid.UUID]*nav.Nav
+
+	// removed are the removed Targets, keyed by URL.
+	removed map[string]Target
+
+	// removedPages are the removed Pages, keyed by UUID.
+	removedPages map[uuid.UUID]Target
+
+	// brokenPages collects the page references that are broken by the event
+	// that is currently applied.
+	brokenPages []mediaref.Report
+
+	pendingMux sync.Mutex
+	pending    []command.Command
+	notify     chan struct{}
+}
+
+// NewChecker returns a new Checker.
+func NewChecker(opts ...Option) *Checker {
+	c := &Checker{
+		mediaURLs:    func(a cdn.Asset) []string { return []string{a.File.Path} },
+		ctx:          context.Background(),
+		navs:         make(map[uuid.UUID]*nav.Nav),
+		removed:      make(map[string]Target),
+		removedPages: make(map[uuid.UUID]Target),
+		notify:       make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.projector = projector.New(c.projectorOpts...)
+	c.refs = mediaref.NewMonitor(mediaref.OnBroken(func(_ context.Context, r mediaref.Report) {
+		// called by c.refs.ApplyEvent while c.mux is locked
+		c.brokenPages = append(c.brokenPages, r)
+	}))
+	return c
+}
+
+// Events returns the events that are projected by a Checker.
+func Events() []string {
+	return append(mediaref.Events(), nav.Events[:]...)
+}
+
+// Run projects the Checker in a new goroutine and returns a channel of
+// asynchronous projection and dispatch errors. ReportHandlers are called with
+// ctx.
+func (c *Checker) Run(ctx context.Context, bus event.Bus, store event.Store, opts ...schedule.ContinuousOption) (<-chan error, error) {
+	c.mux.Lock()
+	c.ctx = ctx
+	c.mux.Unlock()
+
+	schedule := schedule.Continuously(bus, store, Events(), opts...)
+
+	projectionErrors, err := c.projector.Run(ctx, schedule, c)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.commands == nil {
+		return projectionErrors, nil
+	}
+
+	dispatchErrors := make(chan error)
+	go c.work(ctx, dispatchErrors)
+
+	return streams.FanInContext(ctx, projectionErrors, dispatchErrors), nil
+}
+
+// Status returns the projection status of the Checker.
+func (c *Checker) Status() projector.Status {
+	return c.projector.Status()
+}
+
+// Broken returns the currently broken references. Nav references are sorted
+// by nav name, Item and locale.
+func (c *Checker) Broken() Report {
+	c.mux.RLock()
+	navs := make([]NavReference, 0)
+	for _, n := range c.navs {
+		navs = append(navs, c.navRefs(n, c.removed, c.removedPages)...)
+	}
+	c.mux.RUnlock()
+
+	sortNavRefs(navs)
+
+	return Report{
+		Navs:  navs,
+		Pages: c.refs.Broken(),
+	}
+}
+
+// ApplyEvent applies aggregate events.
+func (c *Checker) ApplyEvent(evt event.Event) {
+	c.mux.Lock()
+
+	c.brokenPages = nil
+	c.refs.ApplyEvent(evt)
+
+	removed := c.removedTargets(evt)
+	for u, t := range removed {
+		c.removed[u] = t
+	}
+
+	removedPages := removedPage(evt)
+	for id, t := range removedPages {
+		c.removedPages[id] = t
+	}
+
+	if id, name, _ := evt.Aggregate(); name == nav.Aggregate {
+		c.applyNavEvent(id, evt)
+	}
+
+	report := Report{Pages: c.brokenPages}
+	if len(removed) > 0 || len(removedPages) > 0 {
+		for _, n := range c.navs {
+			report.Navs = append(report.Navs, c.navRefs(n, removed, removedPages)...)
+		}
+		sortNavRefs(report.Navs)
+	}
+	c.brokenPages = nil
+	ctx := c.ctx
+
+	c.mux.Unlock()
+
+	if report.Empty() {
+		return
+	}
+
+	for _, fn := range c.handlers {
+		fn(ctx, report)
+	}
+
+	if c.commands != nil {
+		c.queue(removalCommands(report)...)
+	}
+}
+
+// removedTargets returns the Targets that are removed by the event, keyed by
+// their URLs.
+func (c *Checker) removedTargets(evt event.Event) map[string]Target {
+	id, _, _ := evt.Aggregate()
+	removed := make(map[string]Target)
+
+	if data, ok := evt.Data().(page.DeletedData); ok {
+		if c.pageURLs == nil {
+			return nil
+		}
+		for _, u := range c.pageURLs(id, data.Name) {
+			removed[u] = Target{Type: PageTarget, ID: id, RemovedAt: evt.Time()}
+		}
+		return removed
+	}
+
+	for _, a := range cdn.Assets(evt) {
+		var typ TargetType
+		switch a.Event {
+		case gallery.StackDeleted:
+			typ = StackTarget
+		case document.DocumentRemoved:
+			typ = DocumentTarget
+		default:
+			continue
+		}
+		for _, u := range c.mediaURLs(a) {
+			removed[u] = Target{Type: typ, ID: a.ID, Parent: a.AggregateID, RemovedAt: evt.Time()}
+		}
+	}
+
+	return removed
+}
+
+// removedPage returns the Page that is removed by the event, keyed by its
+// UUID.
+func removedPage(evt event.Event) map[uuid.UUID]Target {
+	if evt.Name() != page.Deleted {
+		return nil
+	}
+	id, _, _ := evt.Aggregate()
+	return map[uuid.UUID]Target{
+		id: {Type: PageTarget, ID: id, RemovedAt: evt.Time()},
+	}
+}
+
+func (c *Checker) applyNavEvent(id uuid.UUID, evt event.Event) {
+	n, ok := c.navs[id]
+	if !ok {
+		n = nav.New(id)
+		c.navs[id] = n
+	}
+	n.ApplyEvent(evt)
+}
+
+// navRefs returns the Items of the Nav that link to one of the given Targets
+// by URL or, for PageLink Items, to one of the given Pages.
+func (c *Checker) navRefs(n *nav.Nav, targets map[string]Target, pages map[uuid.UUID]Target) []NavReference {
+	var refs []NavReference
+	if n.Tree == nil || (len(targets) == 0 && len(pages) == 0) {
+		return refs
+	}
+	walkItems(n.Items, "", func(path string, item nav.Item) {
+		if item.Type == nav.PageLink && item.Page != nil {
+			if t, ok := pages[*item.Page]; ok {
+				refs = append(refs, NavReference{
+					NavID:  n.ID,
+					Nav:    n.Name,
+					Item:   path,
+					Target: t,
+				})
+			}
+		}
+		for locale, p := range item.Paths {
+			t, ok := targets[p]
+			if !ok {
+				continue
+			}
+			refs = append(refs, NavReference{
+				NavID:  n.ID,
+				Nav:    n.Name,
+				Item:   path,
+				Locale: locale,
+				Path:   p,
+				Target: t,
+			})
+		}
+	})
+	return refs
+}
+
+func walkItems(items []nav.Item, prefix string, fn func(string, nav.Item)) {
+	for _, item := range items {
+		path := item.ID
+		if prefix != "" {
+			path = prefix + "." + item.ID
+		}
+		fn(path, item)
+		if item.Tree != nil {
+			walkItems(item.Tree.Items, path, fn)
+		}
+	}
+}
+
+func sortNavRefs(refs []NavReference) {
+	sort.Slice(refs, func(i, j int) bool {
+		a, b := refs[i], refs[j]
+		if a.Nav != b.Nav {
+			return a.Nav < b.Nav
+		}
+		if a.NavID != b.NavID {
+			return a.NavID.String() < b.NavID.String()
+		}
+		if a.Item != b.Item {
+			return a.Item < b.Item
+		}
+		return a.Locale < b.Locale
+	})
+}
+
+// removalCommands returns the commands that remove the broken references of
+// the Report.
+func removalCommands(r Report) []command.Command {
+	var cmds []command.Command
+
+	items := make(map[uuid.UUID][]string)
+	var navIDs []uuid.UUID
+	for _, ref := range r.Navs {
+		if _, ok := items[ref.NavID]; !ok {
+			navIDs = append(navIDs, ref.NavID)
+		}
+		if !containsItem(items[ref.NavID], ref.Item) {
+			items[ref.NavID] = append(items[ref.NavID], ref.Item)
+		}
+	}
+	for _, id := range navIDs {
+		cmds = append(cmds, nav.RemoveItemsCmd(id, items[id]...).Any())
+	}
+
+	for _, ref := range r.Pages {
+		if ref.Live {
+			continue
+		}
+		cmds = append(cmds, page.UpdateFieldCmd(ref.PageID, ref.Field, "", ref.Locale).Any())
+	}
+
+	return cmds
+}
+
+// containsItem returns whether the Item or one of its parents is in items.
+func containsItem(items []string, item string) bool {
+	for _, i := range items {
+		if i == item || strings.HasPrefix(item, i+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Checker) queue(cmds ...command.Command) {
+	if len(cmds) == 0 {
+		return
+	}
+
+	c.pendingMux.Lock()
+	c.pending = append(c.pending, cmds...)
+	c.pendingMux.Unlock()
+
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (c *Checker) work(ctx context.Context, out chan<- error) {
+	defer close(out)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.notify:
+		}
+
+		c.pendingMux.Lock()
+		cmds := c.pending
+		c.pending = nil
+		c.pendingMux.Unlock()
+
+		for _, cmd := range cmds {
+			if err := c.commands.Dispatch(ctx, cmd, dispatch.Sync()); err != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case out <- fmt.Errorf("dispatch %q command for %s %s: %w", cmd.Name(), cmd.Aggregate().Name, cmd.Aggregate().ID, err):
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package integrity_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/integrity"
+	"github.com/modernice/nice-cms/internal/testutil"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+func TestChecker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env := testutil.NewEnv()
+
+	var mux sync.Mutex
+	var reports []integrity.Report
+	c := integrity.NewChecker(
+		integrity.PageURLs(func(_ uuid.UUID, name string) []string { return []string{"/" + name} }),
+		integrity.OnBroken(func(_ context.Context, r integrity.Report) {
+			mux.Lock()
+			defer mux.Unlock()
+			reports = append(reports, r)
+		}),
+	)
+	env.Project(ctx, t, c.Run)
+
+	shelf, doc := env.AddDocument(ctx, t)
+	g, stack := env.UploadStack(ctx, t)
+
+	p := page.New(uuid.New())
+	p.Create("about", field.NewImage("hero", field.ImageRef{Gallery: g.ID, Stack: stack.ID}))
+	if err := env.Pages.Save(ctx, p); err != nil {
+		t.Fatalf("save Page: %v", err)
+	}
+
+	n, _ := nav.Create("main")
+	n.Append(
+		nav.NewStaticLink("about", "/about", "About"),
+		nav.NewLabel("downloads", "Downloads", nav.SubTree(
+			nav.NewStaticLink("report", doc.Path, "Report", nav.LocalePath("de", "/de/report.pdf")),
+		)),
+		nav.NewPageLink("team", p.ID),
+	)
+	if err := env.Navs.Save(ctx, n); err != nil {
+		t.Fatalf("save Nav: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	if broken := c.Broken(); !broken.Empty() {
+		t.Fatalf("Broken should return an empty Report; got %v", broken)
+	}
+
+	if err := env.Shelfs.Use(ctx, shelf.ID, func(s *document.Shelf) error {
+		return s.Remove(ctx, env.Storage, doc.ID)
+	}); err != nil {
+		t.Fatalf("remove Document: %v", err)
+	}
+	if err := env.Galleries.Use(ctx, g.ID, func(g *gallery.Gallery) error {
+		return g.Delete(ctx, env.Storage, stack)
+	}); err != nil {
+		t.Fatalf("delete Stack: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	broken := c.Broken()
+	if len(broken.Navs) != 1 || len(broken.Pages) != 1 {
+		t.Fatalf("Broken should return %d nav and %d page reference; got %v", 1, 1, broken)
+	}
+
+	ref := broken.Navs[0]
+	if ref.NavID != n.ID || ref.Nav != "main" || ref.Item != "downloads.report" || ref.Locale != "" || ref.Path != doc.Path {
+		t.Fatalf("invalid nav reference: %v", ref)
+	}
+	if ref.Target.Type != integrity.DocumentTarget || ref.Target.ID != doc.ID || ref.Target.Parent != shelf.ID {
+		t.Fatalf("invalid Target: %v", ref.Target)
+	}
+
+	if r := broken.Pages[0]; r.PageID != p.ID || r.Field != "hero" || r.Live {
+		t.Fatalf("invalid page reference: %v", r)
+	}
+
+	if err := env.Pages.Use(ctx, p.ID, func(p *page.Page) error {
+		return p.Destroy()
+	}); err != nil {
+		t.Fatalf("destroy Page: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	broken = c.Broken()
+	if len(broken.Navs) != 3 || broken.Navs[0].Item != "about" || broken.Navs[0].Target.Type != integrity.PageTarget {
+		t.Fatalf("the links to the deleted Page should be broken; got %v", broken.Navs)
+	}
+	if ref := broken.Navs[2]; ref.Item != "team" || ref.Path != "" || ref.Target.Type != integrity.PageTarget || ref.Target.ID != p.ID {
+		t.Fatalf("the page link to the deleted Page should be broken; got %v", ref)
+	}
+	if len(broken.Pages) != 0 {
+		t.Fatalf("the references of the deleted Page should not be reported; got %v", broken.Pages)
+	}
+
+	mux.Lock()
+	defer mux.Unlock()
+	if len(reports) != 3 {
+		t.Fatalf("ReportHandler should have been called %d times; got %d", 3, len(reports))
+	}
+	if len(reports[0].Navs) != 1 || len(reports[1].Pages) != 1 || len(reports[2].Navs) != 2 {
+		t.Fatalf("ReportHandler should be called with the newly broken references; got %v", reports)
+	}
+}
+
+func TestAutoRemove(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env := testutil.NewEnv()
+	handleCommands(ctx, t, env)
+
+	c := integrity.NewChecker(integrity.AutoRemove(env.Commands))
+	env.Project(ctx, t, c.Run)
+
+	g, stack := env.UploadStack(ctx, t)
+	ref := field.ImageRef{Gallery: g.ID, Stack: stack.ID}
+
+	p := page.New(uuid.New())
+	p.Create("about", field.NewImage("hero", ref))
+	p.Publish()
+	if err := env.Pages.Save(ctx, p); err != nil {
+		t.Fatalf("save Page: %v", err)
+	}
+
+	n, _ := nav.Create("main")
+	n.Append(nav.NewStaticLink("image", stack.Original().Path, "Image"), nav.NewLabel("foo", "Foo"))
+	if err := env.Navs.Save(ctx, n); err != nil {
+		t.Fatalf("save Nav: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	if err := env.Galleries.Use(ctx, g.ID, func(g *gallery.Gallery) error {
+		return g.Delete(ctx, env.Storage, stack)
+	}); err != nil {
+		t.Fatalf("delete Stack: %v", err)
+	}
+
+	<-time.After(100 * time.Millisecond)
+
+	fetchedNav, err := env.Navs.Fetch(ctx, n.ID)
+	if err != nil {
+		t.Fatalf("fetch Nav: %v", err)
+	}
+	if fetchedNav.HasItem("image") || !fetchedNav.HasItem("foo") {
+		t.Fatalf("only the broken Item should have been removed; got %v", fetchedNav.Items)
+	}
+
+	fetchedPage, err := env.Pages.Fetch(ctx, p.ID)
+	if err != nil {
+		t.Fatalf("fetch Page: %v", err)
+	}
+	f, _ := fetchedPage.Field("hero")
+	if v := f.Value(""); v != "" {
+		t.Fatalf("the broken draft reference should have been cleared; got %q", v)
+	}
+	if v := fetchedPage.LiveFields[0].Value(""); v != ref.String() {
+		t.Fatalf("the live reference should not be changed; got %q", v)
+	}
+
+	broken := c.Broken()
+	if len(broken.Navs) != 0 || len(broken.Pages) != 1 || !broken.Pages[0].Live {
+		t.Fatalf("only the live reference should be broken; got %v", broken)
+	}
+}
+
+func handleCommands(ctx context.Context, t *testing.T, env *testutil.Env) {
+	lookup := nav.NewLookup()
+	env.Project(ctx, t, lookup.Project)
+	testutil.PanicOn(nav.HandleCommands(ctx, env.Commands, env.Navs, lookup))
+	testutil.PanicOn(page.HandleCommands(ctx, env.Commands, env.Pages))
+}
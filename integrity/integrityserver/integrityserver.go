@@ -0,0 +1,53 @@
+// Package integrityserver serves the broken references of an
+// integrity.Checker over HTTP.
+//
+//	srv := integrityserver.New(checker)
+//	http.ListenAndServe(":8000", srv)
+//
+//	// GET /integrity/broken-references
+//
+// The Server does not protect its routes. Protect them with an authentication
+// middleware.
+package integrityserver
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/modernice/nice-cms/integrity"
+	"github.com/modernice/nice-cms/internal/api"
+)
+
+// Routes of the Server.
+const (
+	BrokenReferencesRoute = "/integrity/broken-references"
+)
+
+// Server serves the broken references of an integrity.Checker.
+type Server struct {
+	router  chi.Router
+	checker *integrity.Checker
+}
+
+// New returns a Server that serves the broken references of the given Checker.
+// The Checker must be run by the caller.
+func New(checker *integrity.Checker) *Server {
+	s := Server{
+		router:  chi.NewRouter(),
+		checker: checker,
+	}
+
+	s.router.Get(BrokenReferencesRoute, s.brokenReferences)
+
+	return &s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
+}
+
+// brokenReferences responds with the integrity.Report of the currently broken
+// references.
+func (s *Server) brokenReferences(w http.ResponseWriter, r *http.Request) {
+	api.JSON(w, r, http.StatusOK, s.checker.Broken())
+}
@@ -0,0 +1,78 @@
+package integrityserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/integrity"
+	"github.com/modernice/nice-cms/integrity/integrityserver"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/page"
+)
+
+func TestServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	repo := repository.New(estore)
+	pages := page.GoesRepository(repo)
+	navs := nav.GoesRepository(repo)
+
+	c := integrity.NewChecker(integrity.PageURLs(func(_ uuid.UUID, name string) []string {
+		return []string{"/" + name}
+	}))
+	errs, err := c.Run(ctx, ebus, estore)
+	if err != nil {
+		t.Fatalf("run Checker: %v", err)
+	}
+	go func() {
+		for err := range errs {
+			panic(err)
+		}
+	}()
+
+	p := page.New(uuid.New())
+	p.Create("about")
+	n, _ := nav.Create("main", nav.NewStaticLink("about", "/about", "About"))
+	if err := pages.Save(ctx, p); err != nil {
+		t.Fatalf("save Page: %v", err)
+	}
+	if err := navs.Save(ctx, n); err != nil {
+		t.Fatalf("save Nav: %v", err)
+	}
+	if err := pages.Use(ctx, p.ID, func(p *page.Page) error { return p.Destroy() }); err != nil {
+		t.Fatalf("destroy Page: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	srv := integrityserver.New(c)
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/integrity/broken-references", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+
+	var report integrity.Report
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("decode Report: %v", err)
+	}
+
+	if len(report.Navs) != 1 || report.Navs[0].Item != "about" || report.Navs[0].Target.ID != p.ID {
+		t.Fatalf("Server should serve the broken nav references; got %v", report.Navs)
+	}
+	if report.Pages == nil {
+		t.Fatalf("Pages should be an empty list; got %v", report.Pages)
+	}
+}
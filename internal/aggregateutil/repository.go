@@ -0,0 +1,123 @@
+package aggregateutil
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+)
+
+// Repository is a repository of aggregates of type A that uses an
+// aggregate.Repository under the hood. Use retries to save an aggregate when
+// it was changed concurrently.
+type Repository[A aggregate.Aggregate] struct {
+	repo  aggregate.Repository
+	name  string
+	new   func(uuid.UUID) A
+	init  []func(A)
+	retry []Option
+}
+
+// RepositoryOption is an option for NewRepository.
+type RepositoryOption[A aggregate.Aggregate] interface {
+	ApplyRepository(*Repository[A])
+}
+
+type repositoryOption[A aggregate.Aggregate] func(*Repository[A])
+
+func (opt repositoryOption[A]) ApplyRepository(r *Repository[A]) {
+	opt(r)
+}
+
+// NewRepository returns a Repository of the aggregates that are created by
+// new. name is the name of the aggregate in errors, e.g. "shelf".
+func NewRepository[A aggregate.Aggregate](repo aggregate.Repository, name string, new func(uuid.UUID) A, opts ...RepositoryOption[A]) *Repository[A] {
+	r := &Repository[A]{repo: repo, name: name, new: new}
+	for _, opt := range opts {
+		opt.ApplyRepository(r)
+	}
+	return r
+}
+
+// Init returns a RepositoryOption that calls fn with every fetched aggregate
+// before its events are applied, e.g. to register hooks.
+func Init[A aggregate.Aggregate](fn func(A)) RepositoryOption[A] {
+	return repositoryOption[A](func(r *Repository[A]) {
+		r.init = append(r.init, fn)
+	})
+}
+
+// RetryMaxTries returns a RepositoryOption that limits the number of tries of
+// Repository.Use (see MaxTries).
+func RetryMaxTries[A aggregate.Aggregate](n int) RepositoryOption[A] {
+	return retryOption[A](MaxTries(n))
+}
+
+// RetryBackoff returns a RepositoryOption that configures the delay between
+// the tries of Repository.Use (see Backoff).
+func RetryBackoff[A aggregate.Aggregate](base, max time.Duration) RepositoryOption[A] {
+	return retryOption[A](Backoff(base, max))
+}
+
+// RetryJitter returns a RepositoryOption that adds a random delay to every
+// delay between the tries of Repository.Use (see Jitter).
+func RetryJitter[A aggregate.Aggregate](d time.Duration) RepositoryOption[A] {
+	return retryOption[A](Jitter(d))
+}
+
+// RetryTimeout returns a RepositoryOption that limits the total duration of
+// all tries of Repository.Use (see Timeout).
+func RetryTimeout[A aggregate.Aggregate](d time.Duration) RepositoryOption[A] {
+	return retryOption[A](Timeout(d))
+}
+
+func retryOption[A aggregate.Aggregate](opt Option) RepositoryOption[A] {
+	return repositoryOption[A](func(r *Repository[A]) {
+		r.retry = append(r.retry, opt)
+	})
+}
+
+// Save saves the given aggregate.
+func (r *Repository[A]) Save(ctx context.Context, a A) error {
+	return r.repo.Save(ctx, a)
+}
+
+// Fetch fetches the aggregate with the given UUID.
+func (r *Repository[A]) Fetch(ctx context.Context, id uuid.UUID) (A, error) {
+	a := r.new(id)
+	for _, fn := range r.init {
+		fn(a)
+	}
+	if err := r.repo.Fetch(ctx, a); err != nil {
+		var zero A
+		return zero, fmt.Errorf("fetch %s %q: %w", r.name, id, err)
+	}
+	return a, nil
+}
+
+// Delete deletes the given aggregate.
+func (r *Repository[A]) Delete(ctx context.Context, a A) error {
+	return r.repo.Delete(ctx, a)
+}
+
+// Use fetches the aggregate with the given UUID, calls fn with it and saves
+// it if fn returns nil. If the aggregate cannot be saved, Use retries
+// according to the retry options of the Repository (see Retry).
+func (r *Repository[A]) Use(ctx context.Context, id uuid.UUID, fn func(A) error) error {
+	return Retry(
+		ctx,
+		func(ctx context.Context) (A, error) {
+			return r.Fetch(ctx, id)
+		},
+		fn,
+		func(ctx context.Context, a A) error {
+			if err := r.Save(ctx, a); err != nil {
+				return fmt.Errorf("save %s: %w", r.name, err)
+			}
+			return nil
+		},
+		r.retry...,
+	)
+}
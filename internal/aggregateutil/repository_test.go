@@ -0,0 +1,86 @@
+package aggregateutil_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/aggregateutil"
+)
+
+func TestRepository_Use_retry(t *testing.T) {
+	ctx := context.Background()
+	repo := &flakyRepository{Repository: repository.New(eventstore.New()), failures: 2}
+	foos := aggregateutil.NewRepository(repo, "foo", newFoo, aggregateutil.RetryBackoff[*aggregate.Base](time.Millisecond, 0))
+
+	var calls int
+	if err := foos.Use(ctx, uuid.New(), func(foo *aggregate.Base) error {
+		calls++
+		aggregate.Next(foo, "foo", 0)
+		return nil
+	}); err != nil {
+		t.Fatalf("Use should retry failed saves; failed with %q", err)
+	}
+
+	if calls != 3 {
+		t.Fatalf("fn should be called %d times; was called %d times", 3, calls)
+	}
+
+	repo.failures = 2
+	foos = aggregateutil.NewRepository(
+		repo, "foo", newFoo,
+		aggregateutil.RetryMaxTries[*aggregate.Base](2),
+		aggregateutil.RetryBackoff[*aggregate.Base](time.Millisecond, 0),
+	)
+
+	if err := foos.Use(ctx, uuid.New(), func(foo *aggregate.Base) error {
+		aggregate.Next(foo, "foo", 0)
+		return nil
+	}); !errors.Is(err, errSaveFailed) {
+		t.Fatalf("Use should fail with %q after 2 attempts; got %q", errSaveFailed, err)
+	}
+}
+
+func TestInit(t *testing.T) {
+	ctx := context.Background()
+	var initialized []uuid.UUID
+	foos := aggregateutil.NewRepository(
+		repository.New(eventstore.New()), "foo", newFoo,
+		aggregateutil.Init(func(foo *aggregate.Base) {
+			initialized = append(initialized, foo.AggregateID())
+		}),
+	)
+
+	id := uuid.New()
+	if _, err := foos.Fetch(ctx, id); err != nil {
+		t.Fatalf("Fetch failed with %q", err)
+	}
+
+	if len(initialized) != 1 || initialized[0] != id {
+		t.Fatalf("init func should be called with the fetched aggregate; was called with %v", initialized)
+	}
+}
+
+var errSaveFailed = errors.New("save failed")
+
+type flakyRepository struct {
+	aggregate.Repository
+	failures int
+}
+
+func (r *flakyRepository) Save(ctx context.Context, a aggregate.Aggregate) error {
+	if r.failures > 0 {
+		r.failures--
+		return errSaveFailed
+	}
+	return r.Repository.Save(ctx, a)
+}
+
+func newFoo(id uuid.UUID) *aggregate.Base {
+	return aggregate.New("foo", id)
+}
@@ -0,0 +1,162 @@
+// Package aggregateutil provides helpers for repositories of aggregates.
+package aggregateutil
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// Policy configures the retries of Retry. The zero Policy is not used
+// directly; NewPolicy applies Options to the default Policy.
+type Policy struct {
+	// MaxTries is the maximum number of tries. Zero or less means that tries
+	// are only limited by the Timeout.
+	MaxTries int
+
+	// Timeout limits the total duration of all tries. Zero or less means no
+	// timeout.
+	Timeout time.Duration
+
+	// Backoff is the delay before the first retry.
+	Backoff time.Duration
+
+	// MaxBackoff is the maximum delay between tries. If MaxBackoff is greater
+	// than Backoff, the delay is doubled after every retry until MaxBackoff is
+	// reached. Otherwise the delay is constant.
+	MaxBackoff time.Duration
+
+	// Jitter is the maximum random delay that is added to every delay, so that
+	// concurrent writers don't retry in lockstep.
+	Jitter time.Duration
+}
+
+// Option is an option for Retry.
+type Option func(*Policy)
+
+// MaxTries returns an Option that limits the number of tries. Default is no
+// limit (tries are only limited by the timeout).
+func MaxTries(n int) Option {
+	return func(p *Policy) {
+		p.MaxTries = n
+	}
+}
+
+// Timeout returns an Option that limits the total duration of all tries.
+// Default is 5 seconds. Zero or less disables the timeout.
+func Timeout(d time.Duration) Option {
+	return func(p *Policy) {
+		p.Timeout = d
+	}
+}
+
+// Backoff returns an Option that configures the delay between tries. The
+// delay starts at base and is doubled after every retry until max is reached.
+// If max is not greater than base, the delay is constant. Default is a
+// constant delay of 50ms.
+func Backoff(base, max time.Duration) Option {
+	return func(p *Policy) {
+		p.Backoff = base
+		p.MaxBackoff = max
+	}
+}
+
+// Jitter returns an Option that adds a random delay of up to d to every
+// delay. Default is 100ms.
+func Jitter(d time.Duration) Option {
+	return func(p *Policy) {
+		p.Jitter = d
+	}
+}
+
+// NewPolicy returns the default Policy with the given Options applied.
+func NewPolicy(opts ...Option) Policy {
+	p := Policy{
+		Timeout: 5 * time.Second,
+		Backoff: 50 * time.Millisecond,
+		Jitter:  100 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(&p)
+	}
+	return p
+}
+
+// Delay returns the delay before the given retry (starting at 1), including a
+// random jitter.
+func (p Policy) Delay(retry int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < retry && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > p.Backoff && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(p.Jitter)))
+	}
+
+	return delay
+}
+
+// Retry fetches an aggregate, calls fn with it and saves it. If the aggregate
+// cannot be saved, e.g. because it was changed concurrently, Retry fetches the
+// aggregate again and retries according to the provided Options. Errors of
+// fetch and fn are returned immediately. When Retry gives up, the error of
+// the last save is returned.
+func Retry[A any](
+	ctx context.Context,
+	fetch func(context.Context) (A, error),
+	fn func(A) error,
+	save func(context.Context, A) error,
+	opts ...Option,
+) error {
+	p := NewPolicy(opts...)
+
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+
+	var lastError error
+	for tries := 0; ; tries++ {
+		if tries > 0 {
+			if p.MaxTries > 0 && tries >= p.MaxTries {
+				return lastError
+			}
+
+			timer := time.NewTimer(p.Delay(tries))
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+
+				if lastError != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return lastError
+				}
+
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		a, err := fetch(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(a); err != nil {
+			return err
+		}
+
+		if err := save(ctx, a); err != nil {
+			lastError = err
+			continue
+		}
+
+		return nil
+	}
+}
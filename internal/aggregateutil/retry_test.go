@@ -0,0 +1,147 @@
+package aggregateutil_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/modernice/nice-cms/internal/aggregateutil"
+)
+
+func TestPolicy_Delay(t *testing.T) {
+	p := aggregateutil.Policy{
+		Backoff:    10 * time.Millisecond,
+		MaxBackoff: 50 * time.Millisecond,
+	}
+
+	for i, want := range []time.Duration{
+		10 * time.Millisecond,
+		20 * time.Millisecond,
+		40 * time.Millisecond,
+		50 * time.Millisecond,
+		50 * time.Millisecond,
+	} {
+		if got := p.Delay(i + 1); got != want {
+			t.Errorf("Delay(%d) should return %v; got %v", i+1, want, got)
+		}
+	}
+
+	p = aggregateutil.Policy{Backoff: 10 * time.Millisecond, Jitter: 5 * time.Millisecond}
+	for i := 0; i < 20; i++ {
+		if got := p.Delay(3); got < 10*time.Millisecond || got >= 15*time.Millisecond {
+			t.Fatalf("Delay should be between %v and %v; got %v", 10*time.Millisecond, 15*time.Millisecond, got)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	mockError := errors.New("mock error")
+
+	var fetched, saved int
+	err := aggregateutil.Retry(
+		context.Background(),
+		func(context.Context) (int, error) {
+			fetched++
+			return fetched, nil
+		},
+		func(int) error { return nil },
+		func(_ context.Context, v int) error {
+			saved = v
+			if v < 3 {
+				return mockError
+			}
+			return nil
+		},
+		aggregateutil.Backoff(time.Millisecond, 0),
+	)
+	if err != nil {
+		t.Fatalf("Retry failed with %q", err)
+	}
+
+	if fetched != 3 || saved != 3 {
+		t.Fatalf("the value should be fetched and saved 3 times; fetched=%d saved=%d", fetched, saved)
+	}
+}
+
+func TestRetry_maxTries(t *testing.T) {
+	mockError := errors.New("mock error")
+
+	var saves int
+	err := aggregateutil.Retry(
+		context.Background(),
+		func(context.Context) (int, error) { return 0, nil },
+		func(int) error { return nil },
+		func(context.Context, int) error {
+			saves++
+			return mockError
+		},
+		aggregateutil.MaxTries(3),
+		aggregateutil.Backoff(time.Millisecond, 0),
+		aggregateutil.Jitter(0),
+	)
+
+	if !errors.Is(err, mockError) {
+		t.Fatalf("Retry should fail with %q; got %q", mockError, err)
+	}
+
+	if saves != 3 {
+		t.Fatalf("save should be called %d times; was called %d times", 3, saves)
+	}
+}
+
+func TestRetry_timeout(t *testing.T) {
+	mockError := errors.New("mock error")
+
+	start := time.Now()
+	err := aggregateutil.Retry(
+		context.Background(),
+		func(context.Context) (int, error) { return 0, nil },
+		func(int) error { return nil },
+		func(context.Context, int) error { return mockError },
+		aggregateutil.Timeout(50*time.Millisecond),
+		aggregateutil.Backoff(10*time.Millisecond, 0),
+	)
+
+	if !errors.Is(err, mockError) {
+		t.Fatalf("Retry should fail with the last error %q; got %q", mockError, err)
+	}
+
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("Retry should give up after the timeout; took %v", d)
+	}
+}
+
+func TestRetry_noRetry(t *testing.T) {
+	mockError := errors.New("mock error")
+
+	for name, tt := range map[string]struct {
+		fetch func(context.Context) (int, error)
+		fn    func(int) error
+	}{
+		"fetch": {
+			fetch: func(context.Context) (int, error) { return 0, mockError },
+			fn:    func(int) error { return nil },
+		},
+		"fn": {
+			fetch: func(context.Context) (int, error) { return 0, nil },
+			fn:    func(int) error { return mockError },
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var saves int
+			err := aggregateutil.Retry(context.Background(), tt.fetch, tt.fn, func(context.Context, int) error {
+				saves++
+				return nil
+			})
+
+			if !errors.Is(err, mockError) {
+				t.Fatalf("Retry should fail with %q; got %q", mockError, err)
+			}
+
+			if saves != 0 {
+				t.Fatalf("save should not be called")
+			}
+		})
+	}
+}
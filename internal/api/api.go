@@ -1,10 +1,12 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
@@ -74,7 +76,7 @@ func NoContent(w http.ResponseWriter, r *http.Request) {
 func ParseUUID(raw, desc string) (uuid.UUID, error) {
 	id, err := uuid.Parse(raw)
 	if err != nil {
-		return id, Friendly(err, "Invalid UUID for %q: %v", desc, id)
+		return id, Friendly(err, "Invalid UUID for %q: %q", desc, raw)
 	}
 	return id, nil
 }
@@ -83,6 +85,47 @@ func ExtractUUID(r *http.Request, name string) (uuid.UUID, error) {
 	return ParseUUID(chi.URLParam(r, name), name)
 }
 
+type uuidParamsKey struct{}
+
+// BindUUIDs is middleware that parses the URL parameters of the matched route
+// whose names end with "ID" as UUIDs. If one of the parameters is not a valid
+// UUID, BindUUIDs responds with 400 Bad Request. Otherwise the UUIDs are added
+// to the request context and can be retrieved using UUIDParam.
+//
+// BindUUIDs must be used as route-level middleware, because the URL parameters
+// are only known after routing.
+func BindUUIDs(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rctx := chi.RouteContext(r.Context())
+		if rctx == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ids := make(map[string]uuid.UUID)
+		for i, key := range rctx.URLParams.Keys {
+			if !strings.HasSuffix(key, "ID") {
+				continue
+			}
+			id, err := ParseUUID(rctx.URLParams.Values[i], key)
+			if err != nil {
+				Error(w, r, http.StatusBadRequest, err)
+				return
+			}
+			ids[key] = id
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), uuidParamsKey{}, ids)))
+	})
+}
+
+// UUIDParam returns the UUID of the given URL parameter that was bound by
+// BindUUIDs, or uuid.Nil if the parameter wasn't bound.
+func UUIDParam(r *http.Request, name string) uuid.UUID {
+	ids, _ := r.Context().Value(uuidParamsKey{}).(map[string]uuid.UUID)
+	return ids[name]
+}
+
 func Decode(r io.Reader, v any) error {
 	if err := json.NewDecoder(r).Decode(v); err != nil {
 		return Friendly(err, "Malformed JSON request: %v", err)
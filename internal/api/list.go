@@ -0,0 +1,410 @@
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Operator is a comparison operator of a Filter.
+type Operator string
+
+// Filter operators
+const (
+	// Eq matches values that are equal to the filter value. Eq is the default
+	// operator of a filter parameter without an operator ("?tag=foo").
+	Eq = Operator("eq")
+
+	// Ne matches values that are not equal to the filter value.
+	Ne = Operator("ne")
+
+	// Contains matches values that contain the filter value, ignoring case.
+	Contains = Operator("contains")
+
+	// Prefix matches values that start with the filter value, ignoring case.
+	Prefix = Operator("prefix")
+
+	// Gt, Gte, Lt and Lte compare values as numbers if both values are numbers,
+	// as times if both values are RFC 3339 times, and as strings otherwise.
+	Gt  = Operator("gt")
+	Gte = Operator("gte")
+	Lt  = Operator("lt")
+	Lte = Operator("lte")
+)
+
+// Reserved query parameters of list endpoints. They cannot be used as filters.
+const (
+	CursorParam = "cursor"
+	OffsetParam = "offset"
+	LimitParam  = "limit"
+	SortParam   = "sort"
+)
+
+// Response headers of list endpoints.
+const (
+	// TotalCountHeader is the number of items that match the query.
+	TotalCountHeader = "X-Total-Count"
+
+	// NextCursorHeader is the cursor of the next page.
+	NextCursorHeader = "X-Next-Cursor"
+)
+
+// List describes the query parameters that a list endpoint accepts:
+//
+//	GET /galleries/{GalleryID}/trash?name[contains]=beach&sort=-trashedAt&limit=20
+//
+// Pages are requested using an opaque "cursor" from the previous page, or
+// using "offset", and "limit". "sort" is a comma-separated list of fields; a
+// "-" prefix sorts a field in descending order. Filters are query parameters
+// named after the field, optionally followed by an operator in brackets.
+// Parameters that are neither reserved nor filters are ignored, so that list
+// endpoints can accept additional parameters.
+type List struct {
+	// DefaultLimit is the limit of a query without a "limit" parameter. If
+	// DefaultLimit is 0, all items are returned by default.
+	DefaultLimit int
+
+	// MaxLimit caps the limit of a query. If MaxLimit is 0, the limit is not
+	// capped.
+	MaxLimit int
+
+	// Sorts are the fields that the items can be sorted by.
+	Sorts []string
+
+	// Filters are the fields that the items can be filtered by, and the
+	// operators that can be used for each field.
+	Filters map[string][]Operator
+}
+
+// ListQuery is the parsed query of a list endpoint.
+type ListQuery struct {
+	// Offset is the number of matching items to skip.
+	Offset int
+
+	// Limit is the maximum number of items to return. If Limit is 0, all
+	// items after Offset are returned.
+	Limit int
+
+	Sort    []Sort
+	Filters []Filter
+
+	// Set is true if any of the reserved or filter parameters were set.
+	Set bool
+}
+
+// Sort is a sort field of a ListQuery.
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Filter is a filter of a ListQuery.
+type Filter struct {
+	Field string
+	Op    Operator
+	Value string
+}
+
+// Parse parses the list query parameters of r. Invalid parameters, unknown
+// sort fields and operators that are not allowed for a filter return a
+// FriendlyError.
+func (l List) Parse(r *http.Request) (ListQuery, error) {
+	values := r.URL.Query()
+	q := ListQuery{Limit: l.DefaultLimit}
+
+	if raw := values.Get(CursorParam); raw != "" {
+		offset, err := decodeCursor(raw)
+		if err != nil {
+			return q, Friendly(err, "Invalid cursor: %q", raw)
+		}
+		q.Offset = offset
+		q.Set = true
+	} else if values.Has(OffsetParam) {
+		offset, err := parseCount(values.Get(OffsetParam), OffsetParam)
+		if err != nil {
+			return q, err
+		}
+		q.Offset = offset
+		q.Set = true
+	}
+
+	if values.Has(LimitParam) {
+		limit, err := parseCount(values.Get(LimitParam), LimitParam)
+		if err != nil {
+			return q, err
+		}
+		if limit > 0 {
+			q.Limit = limit
+		}
+		q.Set = true
+	}
+	if l.MaxLimit > 0 && (q.Limit <= 0 || q.Limit > l.MaxLimit) {
+		q.Limit = l.MaxLimit
+	}
+
+	if raw := values.Get(SortParam); raw != "" {
+		for _, field := range strings.Split(raw, ",") {
+			s := Sort{Field: strings.TrimSpace(field)}
+			if strings.HasPrefix(s.Field, "-") {
+				s.Field, s.Desc = s.Field[1:], true
+			}
+			if !contains(l.Sorts, s.Field) {
+				return q, Friendly(nil, "Cannot sort by %q. Sortable fields: %s", s.Field, strings.Join(l.Sorts, ", "))
+			}
+			q.Sort = append(q.Sort, s)
+		}
+		q.Set = true
+	}
+
+	for key, vals := range values {
+		field, op := key, Eq
+		if i := strings.IndexByte(key, '['); i > 0 && strings.HasSuffix(key, "]") {
+			field, op = key[:i], Operator(key[i+1:len(key)-1])
+		}
+
+		ops, ok := l.Filters[field]
+		if !ok {
+			continue
+		}
+		if !containsOp(ops, op) {
+			return q, Friendly(nil, "Operator %q is not allowed for %q.", op, field)
+		}
+
+		for _, v := range vals {
+			q.Filters = append(q.Filters, Filter{Field: field, Op: op, Value: v})
+		}
+		q.Set = true
+	}
+
+	// Map iteration order is random, so filters are sorted to make queries
+	// comparable.
+	sort.SliceStable(q.Filters, func(i, j int) bool {
+		if q.Filters[i].Field != q.Filters[j].Field {
+			return q.Filters[i].Field < q.Filters[j].Field
+		}
+		return q.Filters[i].Op < q.Filters[j].Op
+	})
+
+	return q, nil
+}
+
+// Values returns the values of the filters for field and op, or nil if the
+// query has no such filter.
+func (q ListQuery) Values(field string, op Operator) []string {
+	var out []string
+	for _, f := range q.Filters {
+		if f.Field == field && f.Op == op {
+			out = append(out, f.Value)
+		}
+	}
+	return out
+}
+
+// Value returns the first filter value for field and op, or false if the query
+// has no such filter.
+func (q ListQuery) Value(field string, op Operator) (string, bool) {
+	if vals := q.Values(field, op); len(vals) > 0 {
+		return vals[0], true
+	}
+	return "", false
+}
+
+// Match returns whether the filters of q for field match value. Fields
+// without filters match every value.
+func (q ListQuery) Match(field, value string) bool {
+	for _, f := range q.Filters {
+		if f.Field == field && !f.Match(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Match returns whether value matches the filter.
+func (f Filter) Match(value string) bool {
+	switch f.Op {
+	case Eq:
+		return value == f.Value
+	case Ne:
+		return value != f.Value
+	case Contains:
+		return strings.Contains(strings.ToLower(value), strings.ToLower(f.Value))
+	case Prefix:
+		return strings.HasPrefix(strings.ToLower(value), strings.ToLower(f.Value))
+	case Gt:
+		return compare(value, f.Value) > 0
+	case Gte:
+		return compare(value, f.Value) >= 0
+	case Lt:
+		return compare(value, f.Value) < 0
+	case Lte:
+		return compare(value, f.Value) <= 0
+	default:
+		return false
+	}
+}
+
+// Page is the pagination metadata of a page of items.
+type Page struct {
+	// Total is the number of matching items.
+	Total int
+
+	// Offset and Count are the position and number of the items in the page.
+	Offset int
+	Count  int
+}
+
+// Next returns the offset of the next page, or false if there is no next page.
+func (p Page) Next() (int, bool) {
+	next := p.Offset + p.Count
+	return next, next < p.Total
+}
+
+// Paginate returns the page of items that is selected by the Offset and Limit
+// of q. The items must already be filtered and sorted.
+func Paginate[T any](items []T, q ListQuery) ([]T, Page) {
+	page := Page{Total: len(items), Offset: q.Offset}
+	if q.Offset >= len(items) {
+		return items[:0], page
+	}
+	items = items[q.Offset:]
+	if q.Limit > 0 && len(items) > q.Limit {
+		items = items[:q.Limit]
+	}
+	page.Count = len(items)
+	return items, page
+}
+
+// SortFunc sorts items by the sort fields of q, using cmp to compare two items
+// by a field. cmp returns a negative number if a is less than b, a positive
+// number if a is greater than b, and 0 otherwise. The sort is stable.
+func SortFunc[T any](items []T, q ListQuery, cmp func(field string, a, b T) int) {
+	if len(q.Sort) == 0 {
+		return
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		for _, s := range q.Sort {
+			c := cmp(s.Field, items[i], items[j])
+			if s.Desc {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// PageMeta is the pagination metadata in the body of list responses that
+// respond with an object. List responses embed PageMeta:
+//
+//	{"documents": [...], "total": 120, "nextCursor": "...", "links": {"next": "..."}}
+type PageMeta struct {
+	NextCursor string `json:"nextCursor,omitempty"`
+	Links      struct {
+		Next string `json:"next,omitempty"`
+	} `json:"links"`
+}
+
+// WritePage writes the pagination headers of a page to w and returns the
+// pagination metadata for the response body. The URL of the next page is the
+// URL of r with the cursor of the next page, and is also sent as a "next" Link
+// header. WritePage must be called before the body is written.
+func WritePage(w http.ResponseWriter, r *http.Request, page Page) PageMeta {
+	var meta PageMeta
+	w.Header().Set(TotalCountHeader, strconv.Itoa(page.Total))
+
+	offset, ok := page.Next()
+	if !ok {
+		return meta
+	}
+
+	meta.NextCursor = encodeCursor(offset)
+	values := r.URL.Query()
+	values.Del(OffsetParam)
+	values.Set(CursorParam, meta.NextCursor)
+	meta.Links.Next = (&url.URL{Path: r.URL.Path, RawQuery: values.Encode()}).String()
+
+	w.Header().Set(NextCursorHeader, meta.NextCursor)
+	w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"next\"", meta.Links.Next))
+
+	return meta
+}
+
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("o:" + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	if !strings.HasPrefix(string(b), "o:") {
+		return 0, fmt.Errorf("unknown cursor format")
+	}
+	raw := strings.TrimPrefix(string(b), "o:")
+	offset, err := strconv.Atoi(raw)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor offset %q", raw)
+	}
+	return offset, nil
+}
+
+func parseCount(raw, name string) (int, error) {
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, Friendly(err, "Invalid %s: %q", name, raw)
+	}
+	return n, nil
+}
+
+func compare(a, b string) int {
+	if x, err := strconv.ParseFloat(a, 64); err == nil {
+		if y, err := strconv.ParseFloat(b, 64); err == nil {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	if x, err := time.Parse(time.RFC3339, a); err == nil {
+		if y, err := time.Parse(time.RFC3339, b); err == nil {
+			switch {
+			case x.Before(y):
+				return -1
+			case x.After(y):
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+func contains(vals []string, v string) bool {
+	for _, val := range vals {
+		if val == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsOp(ops []Operator, op Operator) bool {
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package api_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/modernice/nice-cms/internal/api"
+)
+
+var exampleList = api.List{
+	DefaultLimit: 10,
+	MaxLimit:     100,
+	Sorts:        []string{"name", "size"},
+	Filters: map[string][]api.Operator{
+		"name": {api.Eq, api.Contains},
+		"size": {api.Gte, api.Lt},
+	},
+}
+
+func TestList_Parse(t *testing.T) {
+	tests := map[string]api.ListQuery{
+		"":                  {Limit: 10},
+		"locale=de":         {Limit: 10},
+		"limit=0":           {Limit: 10, Set: true},
+		"limit=500":         {Limit: 100, Set: true},
+		"offset=20&limit=5": {Offset: 20, Limit: 5, Set: true},
+		"sort=name,-size":   {Limit: 10, Sort: []api.Sort{{Field: "name"}, {Field: "size", Desc: true}}, Set: true},
+		"name=foo":          {Limit: 10, Filters: []api.Filter{{Field: "name", Op: api.Eq, Value: "foo"}}, Set: true},
+		"name[contains]=foo&size[lt]=3": {
+			Limit: 10,
+			Filters: []api.Filter{
+				{Field: "name", Op: api.Contains, Value: "foo"},
+				{Field: "size", Op: api.Lt, Value: "3"},
+			},
+			Set: true,
+		},
+	}
+
+	for query, want := range tests {
+		t.Run(query, func(t *testing.T) {
+			q, err := exampleList.Parse(httptest.NewRequest(http.MethodGet, "/?"+query, nil))
+			if err != nil {
+				t.Fatalf("Parse failed with %q", err)
+			}
+			if !reflect.DeepEqual(q, want) {
+				t.Fatalf("Parse should return %+v; got %+v", want, q)
+			}
+		})
+	}
+
+	for _, query := range []string{"limit=foo", "offset=-1", "cursor=foo", "sort=date", "name[gt]=foo"} {
+		_, err := exampleList.Parse(httptest.NewRequest(http.MethodGet, "/?"+query, nil))
+		var friendly api.FriendlyError
+		if !errors.As(err, &friendly) {
+			t.Errorf("Parse should fail with a FriendlyError for %q; got %v", query, err)
+		}
+	}
+}
+
+func TestWritePage(t *testing.T) {
+	items := []int{0, 1, 2, 3, 4}
+
+	req := httptest.NewRequest(http.MethodGet, "/items?limit=2&offset=1&name=foo", nil)
+	q, err := exampleList.Parse(req)
+	if err != nil {
+		t.Fatalf("Parse failed with %q", err)
+	}
+
+	page, meta := paginate(t, req, items, q)
+	if !reflect.DeepEqual(page, []int{1, 2}) {
+		t.Fatalf("page should be %v; is %v", []int{1, 2}, page)
+	}
+	if meta.Links.Next == "" || meta.NextCursor == "" {
+		t.Fatalf("page should have a next link and cursor; got %+v", meta)
+	}
+	if strings.Contains(meta.Links.Next, "offset=") || !strings.Contains(meta.Links.Next, "name=foo") {
+		t.Fatalf("next link should keep the filters and replace the offset with a cursor; is %q", meta.Links.Next)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, meta.Links.Next, nil)
+	if q, err = exampleList.Parse(req); err != nil {
+		t.Fatalf("Parse failed with %q", err)
+	}
+
+	page, meta = paginate(t, req, items, q)
+	if !reflect.DeepEqual(page, []int{3, 4}) {
+		t.Fatalf("next page should be %v; is %v", []int{3, 4}, page)
+	}
+	if meta.Links.Next != "" {
+		t.Fatalf("last page should not have a next link; has %q", meta.Links.Next)
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	tests := []struct {
+		filter api.Filter
+		value  string
+		want   bool
+	}{
+		{api.Filter{Op: api.Eq, Value: "foo"}, "foo", true},
+		{api.Filter{Op: api.Ne, Value: "foo"}, "foo", false},
+		{api.Filter{Op: api.Contains, Value: "OO"}, "foo", true},
+		{api.Filter{Op: api.Prefix, Value: "Fo"}, "foo", true},
+		{api.Filter{Op: api.Prefix, Value: "oo"}, "foo", false},
+		{api.Filter{Op: api.Gt, Value: "9"}, "10", true},
+		{api.Filter{Op: api.Lte, Value: "9"}, "10", false},
+		{api.Filter{Op: api.Lt, Value: "2022-01-02T00:00:00Z"}, "2022-01-01T12:00:00+02:00", true},
+		{api.Filter{Op: api.Gte, Value: "b"}, "a", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.filter.Match(tt.value); got != tt.want {
+			t.Errorf("%s %q should match %q: %v; got %v", tt.filter.Op, tt.filter.Value, tt.value, tt.want, got)
+		}
+	}
+}
+
+func paginate(t *testing.T, req *http.Request, items []int, q api.ListQuery) ([]int, api.PageMeta) {
+	t.Helper()
+
+	page, p := api.Paginate(items, q)
+	rec := httptest.NewRecorder()
+	meta := api.WritePage(rec, req, p)
+
+	if total := rec.Header().Get(api.TotalCountHeader); total != "5" {
+		t.Fatalf("%s header should be %q; is %q", api.TotalCountHeader, "5", total)
+	}
+	if next := rec.Header().Get("Link"); (next != "") != (meta.Links.Next != "") {
+		t.Fatalf("Link header should link to the next page; is %q", next)
+	}
+
+	return page, meta
+}
@@ -0,0 +1,71 @@
+// Command tsclient generates the TypeScript client of the media, page and nav
+// servers (@nice-cms/client) from the OpenAPI documents of their routes.
+package main
+
+//go:generate go run . -out ../../../packages/client/src/api.ts
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/modernice/goes/command/cmdbus"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/nice-cms/catalog"
+	"github.com/modernice/nice-cms/internal/commands"
+	"github.com/modernice/nice-cms/media/mediaserver"
+	"github.com/modernice/nice-cms/openapi"
+	"github.com/modernice/nice-cms/openapi/typescript"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/nav/navserver"
+	"github.com/modernice/nice-cms/static/page/pageserver"
+)
+
+func main() {
+	out := flag.String("out", "api.ts", "path of the generated module")
+	flag.Parse()
+
+	if err := os.WriteFile(*out, generate(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate returns the client module for media, page and nav servers with all
+// routes.
+func generate() []byte {
+	srv := mediaserver.New(
+		nil,
+		mediaserver.WithDocuments(nil, ""),
+		mediaserver.WithGalleries(nil),
+		mediaserver.WithJobs(nil),
+		mediaserver.WithAudit(nil),
+		mediaserver.WithDeadLetters(nil),
+		mediaserver.WithSchemas(nil),
+		mediaserver.WithCatalog(catalog.Catalog{}),
+		mediaserver.WithHealth(nil),
+		mediaserver.WithOpenAPI(openapi.Info{}, nil),
+	)
+	// The page and nav routes that dispatch commands are only installed with
+	// a command bus.
+	bus := cmdbus.New(commands.NewRegistry(), eventbus.New())
+
+	pages := pageserver.New(
+		nil,
+		pageserver.WithPreview([]byte{}),
+		pageserver.WithRevisions(bus),
+		pageserver.WithSchema(),
+		pageserver.WithOpenAPI(openapi.Info{}),
+	)
+	navs := navserver.New(
+		nil,
+		navserver.WithLookup(nav.NewLookup()),
+		navserver.WithItemUpdates(bus),
+		navserver.WithOpenAPI(openapi.Info{}),
+	)
+
+	doc := srv.OpenAPI(openapi.Info{Title: "nice-cms API"})
+	doc.Merge(pages.OpenAPI(openapi.Info{}))
+	doc.Merge(navs.OpenAPI(openapi.Info{}))
+
+	return typescript.Generate(doc)
+}
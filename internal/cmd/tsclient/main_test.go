@@ -0,0 +1,18 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	want, err := os.ReadFile("../../../packages/client/src/api.ts")
+	if err != nil {
+		t.Fatalf("read client: %v", err)
+	}
+
+	if !bytes.Equal(generate(), want) {
+		t.Fatalf("packages/client/src/api.ts is outdated. Run \"go generate ./internal/cmd/tsclient\".")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/modernice/nice-cms/media/document"
 	"github.com/modernice/nice-cms/media/image/gallery"
 	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/page"
 )
 
 // NewRegistry returns a new command registry with all commands registered.
@@ -17,6 +18,7 @@ func NewRegistry() *codec.Registry {
 // Register registers all commands into the registry.
 func Register(r codec.Registerer) {
 	nav.RegisterCommands(r)
+	page.RegisterCommands(r)
 	document.RegisterCommands(r)
 	gallery.RegisterCommands(r)
 }
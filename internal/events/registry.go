@@ -5,7 +5,10 @@ import (
 	"github.com/modernice/goes/command/cmdbus"
 	"github.com/modernice/nice-cms/media/document"
 	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/media/usage"
+	"github.com/modernice/nice-cms/seed"
 	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/page"
 )
 
 // NewRegistry returns a new event registry with all events registered.
@@ -18,7 +21,11 @@ func NewRegistry() codec.Registerer {
 // Register registers all events into the event registry.
 func Register(r codec.Registerer) {
 	nav.RegisterEvents(r)
+	page.RegisterEvents(r)
 	document.RegisterEvents(r)
 	gallery.RegisterEvents(r)
+	gallery.RegisterJobEvents(r)
+	usage.RegisterEvents(r)
+	seed.RegisterEvents(r)
 	cmdbus.RegisterEvents(r)
 }
@@ -10,17 +10,18 @@ import (
 )
 
 // NewServer returns a *grpc.Server and a dialer for that server. The server s
-// is started with s.Serve using a *bufconn.Listener. If init is non-nil, it
-// is called with the *grpc.Server before calling s.Serve.
+// is created with the provided options and started with s.Serve using a
+// *bufconn.Listener. If init is non-nil, it is called with the *grpc.Server
+// before calling s.Serve.
 //
 //	_, dial := NewServer(func(s *grpc.Server) {
 //		proto.RegisterFooServer(s, ...)
 //	})
 //	conn := dial()
 //	defer conn.Close()
-func NewServer(init func(*grpc.Server)) (*grpc.Server, func() *grpc.ClientConn) {
+func NewServer(init func(*grpc.Server), opts ...grpc.ServerOption) (*grpc.Server, func() *grpc.ClientConn) {
 	lis := bufconn.Listen(1024)
-	srv := grpc.NewServer()
+	srv := grpc.NewServer(opts...)
 
 	if init != nil {
 		init(srv)
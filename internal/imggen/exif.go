@@ -0,0 +1,124 @@
+package imggen
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+)
+
+// JPEGWithEXIF encodes img as a JPEG and embeds the given EXIF (TIFF) data in
+// an APP1 segment. If tiff is nil, the JPEG has no EXIF metadata.
+func JPEGWithEXIF(img image.Image, tiff []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		return nil, err
+	}
+
+	if tiff == nil {
+		return buf.Bytes(), nil
+	}
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xff, 0xe1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	b := buf.Bytes()
+	out := append([]byte{}, b[:2]...)
+	out = append(out, segment...)
+	return append(out, b[2:]...), nil
+}
+
+type tiffEntry struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+// EXIF returns little-endian EXIF (TIFF) data with the given orientation. The
+// data contains the following metadata:
+//
+//	Make: "Foo"
+//	Model: "Foo 1"
+//	FNumber: 2.8
+//	ISO: 400
+//	DateTimeOriginal: 2021-07-01 12:30:00
+//	GPS: 48.5 N, 9.25 W
+func EXIF(orientation uint16) []byte {
+	le := binary.LittleEndian
+
+	short := func(v uint16) []byte {
+		b := make([]byte, 2)
+		le.PutUint16(b, v)
+		return b
+	}
+	rationals := func(v ...uint32) []byte {
+		b := make([]byte, 4*len(v))
+		for i, n := range v {
+			le.PutUint32(b[i*4:], n)
+		}
+		return b
+	}
+	ascii := func(s string) []byte { return append([]byte(s), 0) }
+
+	const (
+		ifd0Offset = 8
+		exifOffset = 200
+		gpsOffset  = 400
+	)
+
+	data := make([]byte, 600)
+	copy(data, "II")
+	le.PutUint16(data[2:], 42)
+	le.PutUint32(data[4:], ifd0Offset)
+
+	writeIFD := func(offset int, entries []tiffEntry) {
+		le.PutUint16(data[offset:], uint16(len(entries)))
+		extra := offset + 2 + len(entries)*12 + 4
+		for i, e := range entries {
+			raw := data[offset+2+i*12:]
+			le.PutUint16(raw, e.tag)
+			le.PutUint16(raw[2:], e.typ)
+			le.PutUint32(raw[4:], e.count)
+			if len(e.value) <= 4 {
+				copy(raw[8:], e.value)
+				continue
+			}
+			le.PutUint32(raw[8:], uint32(extra))
+			copy(data[extra:], e.value)
+			extra += len(e.value)
+		}
+	}
+
+	pointer := make([]byte, 4)
+
+	le.PutUint32(pointer, exifOffset)
+	exifPointer := append([]byte{}, pointer...)
+	le.PutUint32(pointer, gpsOffset)
+	gpsPointer := append([]byte{}, pointer...)
+
+	writeIFD(ifd0Offset, []tiffEntry{
+		{0x010f, 2, 4, ascii("Foo")},
+		{0x0110, 2, 6, ascii("Foo 1")},
+		{0x0112, 3, 1, short(orientation)},
+		{0x8769, 4, 1, exifPointer},
+		{0x8825, 4, 1, gpsPointer},
+	})
+
+	writeIFD(exifOffset, []tiffEntry{
+		{0x829d, 5, 1, rationals(28, 10)},
+		{0x8827, 3, 1, short(400)},
+		{0x9003, 2, 20, ascii("2021:07:01 12:30:00")},
+	})
+
+	writeIFD(gpsOffset, []tiffEntry{
+		{0x0001, 2, 2, ascii("N")},
+		{0x0002, 5, 3, rationals(48, 1, 30, 1, 0, 1)},
+		{0x0003, 2, 2, ascii("W")},
+		{0x0004, 5, 3, rationals(9, 1, 15, 1, 0, 1)},
+	})
+
+	return data
+}
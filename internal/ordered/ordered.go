@@ -0,0 +1,48 @@
+// Package ordered applies the events of an aggregate in the order of their
+// versions.
+package ordered
+
+import "github.com/modernice/goes/event"
+
+// Events buffers the events of a single aggregate that are received ahead of
+// their predecessors. Projections use Events to apply the events of an
+// aggregate to appliers that are not idempotent, because events may be
+// delivered more than once and out of order. The zero value is ready to use.
+type Events struct {
+	version int
+	pending map[int]event.Event
+}
+
+// Version returns the version of the last applied event.
+func (e *Events) Version() int {
+	return e.version
+}
+
+// Apply calls apply for evt if evt is the successor of the last applied event,
+// followed by every buffered event that succeeds evt, in the order of their
+// versions. Otherwise, evt is buffered until its predecessors are applied.
+// Events that were already applied are discarded. Apply returns the number of
+// applied events.
+func (e *Events) Apply(evt event.Event, apply func(event.Event)) int {
+	_, _, version := evt.Aggregate()
+	if version <= e.version {
+		return 0
+	}
+
+	if e.pending == nil {
+		e.pending = make(map[int]event.Event)
+	}
+	e.pending[version] = evt
+
+	var applied int
+	for {
+		next, ok := e.pending[e.version+1]
+		if !ok {
+			return applied
+		}
+		delete(e.pending, e.version+1)
+		apply(next)
+		e.version++
+		applied++
+	}
+}
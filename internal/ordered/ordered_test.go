@@ -0,0 +1,47 @@
+package ordered_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/internal/ordered"
+)
+
+func TestEvents_Apply(t *testing.T) {
+	id := uuid.New()
+	newEvent := func(version int) event.Event {
+		return event.New("foo", version, event.Aggregate(id, "foo", version)).Any()
+	}
+
+	var events ordered.Events
+	var applied []int
+	apply := func(evt event.Event) {
+		applied = append(applied, evt.Data().(int))
+	}
+
+	for _, step := range []struct {
+		version int
+		want    int
+	}{
+		{version: 2, want: 0},
+		{version: 1, want: 2},
+		{version: 2, want: 0},
+		{version: 4, want: 0},
+		{version: 3, want: 2},
+		{version: 1, want: 0},
+	} {
+		if n := events.Apply(newEvent(step.version), apply); n != step.want {
+			t.Fatalf("Apply(v%d) should apply %d events; applied %d", step.version, step.want, n)
+		}
+	}
+
+	if want := []int{1, 2, 3, 4}; !reflect.DeepEqual(applied, want) {
+		t.Fatalf("events should be applied in order %v; got %v", want, applied)
+	}
+
+	if events.Version() != 4 {
+		t.Fatalf("Version should be %d; is %d", 4, events.Version())
+	}
+}
@@ -0,0 +1,107 @@
+package testutil
+
+import (
+	"context"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/command"
+	"github.com/modernice/goes/command/cmdbus"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/internal/commands"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/page"
+)
+
+// Disk is the name of the memory disk of the Storage of an Env.
+const Disk = "foo-disk"
+
+// Env is a test environment that wires the repositories of all aggregates to
+// the goes components of Goes. Its Storage has a single memory disk (Disk).
+type Env struct {
+	Bus       event.Bus
+	Store     event.Store
+	Commands  command.Bus
+	Storage   media.Storage
+	Galleries gallery.Repository
+	Shelfs    document.Repository
+	Pages     page.Repository
+	Navs      nav.Repository
+}
+
+// ProjectFunc is the signature of the Project methods of the lookups.
+type ProjectFunc func(context.Context, event.Bus, event.Store, ...schedule.ContinuousOption) (<-chan error, error)
+
+// NewEnv returns a new Env.
+func NewEnv() *Env {
+	setupEvents, _, setupAggregates := Goes()
+	ebus, estore, _ := setupEvents()
+	repo := setupAggregates()
+	return &Env{
+		Bus:       ebus,
+		Store:     estore,
+		Commands:  cmdbus.New(commands.NewRegistry(), ebus),
+		Storage:   media.NewStorage(media.ConfigureDisk(Disk, media.MemoryDisk())),
+		Galleries: gallery.GoesRepository(repo),
+		Shelfs:    document.GoesRepository(repo),
+		Pages:     page.GoesRepository(repo),
+		Navs:      nav.GoesRepository(repo),
+	}
+}
+
+// Project runs the given projections on the events of the Env and panics on
+// their asynchronous errors.
+func (env *Env) Project(ctx context.Context, t testing.TB, fns ...ProjectFunc) {
+	for _, fn := range fns {
+		errs, err := fn(ctx, env.Bus, env.Store)
+		if err != nil {
+			t.Fatalf("project: %v", err)
+		}
+		PanicOn(errs)
+	}
+}
+
+// UploadStack creates and saves a Gallery with a single uploaded image.
+func (env *Env) UploadStack(ctx context.Context, t testing.TB) (*gallery.Gallery, gallery.Stack) {
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+	_, buf := imggen.ColoredRectangle(80, 60, color.Black)
+	stack, err := g.Upload(ctx, env.Storage, buf, "Example", Disk, "/images/"+g.ID.String()+".png")
+	if err != nil {
+		t.Fatalf("upload image: %v", err)
+	}
+	if err := env.Galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save Gallery: %v", err)
+	}
+	return g, stack
+}
+
+// AddDocument creates and saves a Shelf with a single Document.
+func (env *Env) AddDocument(ctx context.Context, t testing.TB) (*document.Shelf, document.Document) {
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create("foo")
+	doc, err := shelf.Add(ctx, env.Storage, strings.NewReader("annual report"), "", "Annual Report", Disk, "/reports/"+shelf.ID.String()+".pdf")
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+	if err := env.Shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save Shelf: %v", err)
+	}
+	return shelf, doc
+}
+
+// PanicOn panics on the first error that is received from errs.
+func PanicOn(errs <-chan error) {
+	go func() {
+		for err := range errs {
+			panic(err)
+		}
+	}()
+}
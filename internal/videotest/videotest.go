@@ -0,0 +1,55 @@
+// Package videotest provides fake ffprobe and ffmpeg executables for tests.
+package videotest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modernice/nice-cms/media/video"
+)
+
+// FFmpeg returns a video.FFmpeg that uses fake shell scripts as ffprobe and
+// ffmpeg executables. The fake ffprobe reports info for every input and the
+// fake ffmpeg writes frame to its output file. The scripts are removed when
+// the test finishes.
+func FFmpeg(t *testing.T, info video.Info, frame []byte) video.FFmpeg {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	framePath := filepath.Join(dir, "frame.png")
+	if err := os.WriteFile(framePath, frame, 0644); err != nil {
+		t.Fatalf("write frame: %v", err)
+	}
+
+	probe := fmt.Sprintf(
+		`{"streams":[{"width":%d,"height":%d,"codec_name":%q}],"format":{"duration":"%.3f"}}`,
+		info.Width, info.Height, info.Codec, info.Duration.Seconds(),
+	)
+
+	ffprobe := script(t, dir, "ffprobe", fmt.Sprintf("cat <<'EOF'\n%s\nEOF\n", probe))
+	ffmpeg := script(t, dir, "ffmpeg", fmt.Sprintf("for last; do :; done\ncp %q \"$last\"\n", framePath))
+
+	return video.FFmpeg{FFprobe: ffprobe, FFmpeg: ffmpeg}
+}
+
+// Info returns example video metadata.
+func Info() video.Info {
+	return video.Info{
+		Width:    1920,
+		Height:   1080,
+		Duration: 12500 * time.Millisecond,
+		Codec:    "h264",
+	}
+}
+
+func script(t *testing.T, dir, name, body string) string {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0755); err != nil {
+		t.Fatalf("write %s script: %v", name, err)
+	}
+	return path
+}
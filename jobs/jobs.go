@@ -0,0 +1,131 @@
+// Package jobs runs long-running background jobs such as exports. Jobs are
+// persisted in a Store, report their progress, can be canceled, are retried
+// when they fail and are resumed when the Runner is restarted. Jobs may store a
+// result artifact on a storage disk that expires after a configurable time.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotFound is returned when a Job cannot be found in a Store.
+	ErrNotFound = errors.New("job not found")
+
+	// ErrUnknownKind is returned when starting a Job of a kind that has no
+	// registered Handler.
+	ErrUnknownKind = errors.New("unknown job kind")
+
+	// ErrFinished is returned when canceling a Job that has already finished.
+	ErrFinished = errors.New("job already finished")
+
+	// ErrNoArtifact is returned when requesting the artifact of a Job that has
+	// no artifact.
+	ErrNoArtifact = errors.New("job has no artifact")
+
+	// ErrArtifactExpired is returned when requesting an expired artifact.
+	ErrArtifactExpired = errors.New("artifact expired")
+
+	// ErrNoStorage is returned when storing an artifact with a Runner that has
+	// no artifact storage.
+	ErrNoStorage = errors.New("no artifact storage")
+)
+
+// Status is the status of a Job.
+type Status string
+
+// Job statuses
+const (
+	// StatusPending means that the Job waits to be run.
+	StatusPending = Status("pending")
+
+	// StatusRunning means that the Job is currently running.
+	StatusRunning = Status("running")
+
+	// StatusSucceeded means that the Job has finished successfully.
+	StatusSucceeded = Status("succeeded")
+
+	// StatusFailed means that the Job has failed and won't be retried.
+	StatusFailed = Status("failed")
+
+	// StatusCanceled means that the Job was canceled.
+	StatusCanceled = Status("canceled")
+)
+
+// Finished returns whether the Status is final.
+func (s Status) Finished() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCanceled
+}
+
+// Job is a background job.
+type Job struct {
+	ID   uuid.UUID `json:"id"`
+	Kind string    `json:"kind"`
+
+	// Params are the JSON-encoded parameters of the Job.
+	Params json.RawMessage `json:"params,omitempty"`
+
+	Status Status `json:"status"`
+
+	// Progress is the progress of the Job in percent.
+	Progress int `json:"progress"`
+
+	// Attempts is the number of times the Job has been started.
+	Attempts int `json:"attempts"`
+
+	// Error is the error of the last failed attempt.
+	Error string `json:"error,omitempty"`
+
+	// Checkpoint is the JSON-encoded state that was reported by the Handler
+	// of the Job together with its progress. Handlers can resume from the
+	// Checkpoint when the Job is retried or resumed.
+	Checkpoint json.RawMessage `json:"checkpoint,omitempty"`
+
+	// Artifact is the result file of the Job.
+	Artifact *Artifact `json:"artifact,omitempty"`
+
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// Artifact is the result file of a Job. It is stored on a storage disk and
+// deleted from the disk after it has expired.
+type Artifact struct {
+	Name        string    `json:"name"`
+	ContentType string    `json:"contentType,omitempty"`
+	Disk        string    `json:"disk"`
+	Path        string    `json:"path"`
+	Size        int64     `json:"size"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+
+	// Deleted is true if the Artifact has been deleted from its disk.
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// Expired returns whether the Artifact has expired at the given time or has
+// been deleted.
+func (a Artifact) Expired(now time.Time) bool {
+	return a.Deleted || (!a.ExpiresAt.IsZero() && !now.Before(a.ExpiresAt))
+}
+
+// Store persists Jobs.
+type Store interface {
+	// Save saves a Job.
+	Save(context.Context, Job) error
+
+	// Fetch returns the Job with the given UUID or ErrNotFound.
+	Fetch(context.Context, uuid.UUID) (Job, error)
+
+	// Jobs returns all Jobs.
+	Jobs(context.Context) ([]Job, error)
+
+	// Delete deletes the Job with the given UUID. Delete returns no error if
+	// the Job does not exist.
+	Delete(context.Context, uuid.UUID) error
+}
@@ -0,0 +1,381 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/jobs"
+	"github.com/modernice/nice-cms/media"
+)
+
+const (
+	exampleKind = "example"
+	exampleDisk = "foo-disk"
+)
+
+type exampleParams struct {
+	Name string
+}
+
+func TestStores(t *testing.T) {
+	for name, store := range map[string]jobs.Store{
+		"memory": jobs.MemoryStore(),
+		"fs":     jobs.FSStore(t.TempDir()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			if _, err := store.Fetch(ctx, uuid.New()); !errors.Is(err, jobs.ErrNotFound) {
+				t.Fatalf("Fetch should fail with %q for an unknown job; got %v", jobs.ErrNotFound, err)
+			}
+
+			job := jobs.Job{ID: uuid.New(), Kind: exampleKind, Status: jobs.StatusPending, CreatedAt: time.Now()}
+			if err := store.Save(ctx, job); err != nil {
+				t.Fatalf("save job: %v", err)
+			}
+
+			job.Progress = 50
+			if err := store.Save(ctx, job); err != nil {
+				t.Fatalf("save job: %v", err)
+			}
+
+			fetched, err := store.Fetch(ctx, job.ID)
+			if err != nil {
+				t.Fatalf("fetch job: %v", err)
+			}
+			if fetched.Progress != 50 || fetched.Kind != exampleKind {
+				t.Fatalf("fetched job should be %v; got %v", job, fetched)
+			}
+
+			all, err := store.Jobs(ctx)
+			if err != nil {
+				t.Fatalf("fetch jobs: %v", err)
+			}
+			if len(all) != 1 {
+				t.Fatalf("Jobs should return %d job; got %d", 1, len(all))
+			}
+
+			if err := store.Delete(ctx, job.ID); err != nil {
+				t.Fatalf("delete job: %v", err)
+			}
+			if _, err := store.Fetch(ctx, job.ID); !errors.Is(err, jobs.ErrNotFound) {
+				t.Fatalf("Fetch should fail with %q for a deleted job; got %v", jobs.ErrNotFound, err)
+			}
+		})
+	}
+}
+
+func TestRunner_Start_unknownKind(t *testing.T) {
+	r := jobs.NewRunner(jobs.MemoryStore())
+	if _, err := r.Start(context.Background(), "foo", nil); !errors.Is(err, jobs.ErrUnknownKind) {
+		t.Fatalf("Start should fail with %q; got %v", jobs.ErrUnknownKind, err)
+	}
+}
+
+func TestRunner_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	r := jobs.NewRunner(
+		jobs.MemoryStore(),
+		jobs.Artifacts(storage, exampleDisk),
+		jobs.Handle(exampleKind, func(c *jobs.Context) error {
+			var params exampleParams
+			if err := c.Params(&params); err != nil {
+				return err
+			}
+			if err := c.Progress(50, nil); err != nil {
+				return err
+			}
+			return c.Artifact("foo.txt", "text/plain", strings.NewReader("hello "+params.Name))
+		}),
+	)
+
+	errs := run(t, ctx, r)
+
+	job, err := r.Start(ctx, exampleKind, exampleParams{Name: "bob"})
+	if err != nil {
+		t.Fatalf("start job: %v", err)
+	}
+
+	job = await(t, r, job.ID, jobs.StatusSucceeded, errs)
+
+	if job.Progress != 100 {
+		t.Fatalf("Progress should be %d; is %d", 100, job.Progress)
+	}
+	if job.FinishedAt == nil {
+		t.Fatalf("FinishedAt should be set")
+	}
+	if job.Artifact == nil || job.Artifact.Size != int64(len("hello bob")) {
+		t.Fatalf("Job should have an artifact of %d bytes; got %v", len("hello bob"), job.Artifact)
+	}
+
+	_, rc, err := r.Artifact(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("get artifact: %v", err)
+	}
+	defer rc.Close()
+
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("read artifact: %v", err)
+	}
+	if string(b) != "hello bob" {
+		t.Fatalf("artifact should be %q; is %q", "hello bob", b)
+	}
+}
+
+func TestRunner_Run_retry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := jobs.NewRunner(
+		jobs.MemoryStore(),
+		jobs.RetryDelay(time.Millisecond),
+		jobs.Handle(exampleKind, func(c *jobs.Context) error {
+			var step int
+			if _, err := c.Checkpoint(&step); err != nil {
+				return err
+			}
+			if err := c.Progress(50, step+1); err != nil {
+				return err
+			}
+			if step < 2 {
+				return errors.New("mock error")
+			}
+			return nil
+		}),
+	)
+
+	errs := run(t, ctx, r)
+
+	job, err := r.Start(ctx, exampleKind, nil)
+	if err != nil {
+		t.Fatalf("start job: %v", err)
+	}
+
+	job = await(t, r, job.ID, jobs.StatusSucceeded, errs)
+
+	if job.Attempts != 3 {
+		t.Fatalf("Job should have been attempted %d times; got %d", 3, job.Attempts)
+	}
+}
+
+func TestRunner_Run_failed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockError := errors.New("mock error")
+	r := jobs.NewRunner(
+		jobs.MemoryStore(),
+		jobs.Handle(exampleKind, func(c *jobs.Context) error {
+			return jobs.Permanent(mockError)
+		}),
+	)
+
+	errs := run(t, ctx, r)
+
+	job, err := r.Start(ctx, exampleKind, nil)
+	if err != nil {
+		t.Fatalf("start job: %v", err)
+	}
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("timed out")
+	case err := <-errs:
+		if !errors.Is(err, mockError) {
+			t.Fatalf("Runner should report %q; got %q", mockError, err)
+		}
+	}
+
+	job, err = r.Job(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("fetch job: %v", err)
+	}
+
+	if job.Status != jobs.StatusFailed || job.Attempts != 1 {
+		t.Fatalf("Job should have failed after %d attempt; got status %q after %d attempts", 1, job.Status, job.Attempts)
+	}
+	if job.Error != mockError.Error() {
+		t.Fatalf("Error should be %q; is %q", mockError, job.Error)
+	}
+}
+
+func TestRunner_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan struct{})
+	r := jobs.NewRunner(
+		jobs.MemoryStore(),
+		jobs.Handle(exampleKind, func(c *jobs.Context) error {
+			close(started)
+			<-c.Done()
+			return c.Err()
+		}),
+	)
+
+	errs := run(t, ctx, r)
+
+	job, err := r.Start(ctx, exampleKind, nil)
+	if err != nil {
+		t.Fatalf("start job: %v", err)
+	}
+
+	<-started
+
+	if _, err := r.Cancel(ctx, job.ID); err != nil {
+		t.Fatalf("cancel job: %v", err)
+	}
+
+	await(t, r, job.ID, jobs.StatusCanceled, errs)
+
+	if _, err := r.Cancel(ctx, job.ID); !errors.Is(err, jobs.ErrFinished) {
+		t.Fatalf("Cancel should fail with %q for a finished job; got %v", jobs.ErrFinished, err)
+	}
+}
+
+func TestRunner_Run_resume(t *testing.T) {
+	store := jobs.FSStore(t.TempDir())
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	started := make(chan struct{})
+	r := jobs.NewRunner(store, jobs.Handle(exampleKind, func(c *jobs.Context) error {
+		if err := c.Progress(30, "foo"); err != nil {
+			return err
+		}
+		close(started)
+		<-c.Done()
+		return c.Err()
+	}))
+
+	errs := run(t, ctx, r)
+
+	job, err := r.Start(ctx, exampleKind, nil)
+	if err != nil {
+		t.Fatalf("start job: %v", err)
+	}
+
+	<-started
+	cancel()
+	for range errs {
+	}
+
+	job, err = store.Fetch(context.Background(), job.ID)
+	if err != nil {
+		t.Fatalf("fetch job: %v", err)
+	}
+	if job.Status != jobs.StatusPending {
+		t.Fatalf("interrupted Job should be %q; is %q", jobs.StatusPending, job.Status)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	var checkpoint string
+	r = jobs.NewRunner(store, jobs.Handle(exampleKind, func(c *jobs.Context) error {
+		_, err := c.Checkpoint(&checkpoint)
+		return err
+	}))
+
+	errs = run(t, ctx, r)
+
+	await(t, r, job.ID, jobs.StatusSucceeded, errs)
+
+	if checkpoint != "foo" {
+		t.Fatalf("resumed Job should start from checkpoint %q; got %q", "foo", checkpoint)
+	}
+}
+
+func TestRunner_Cleanup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	disk := media.MemoryDisk()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, disk))
+
+	r := jobs.NewRunner(
+		jobs.MemoryStore(),
+		jobs.Artifacts(storage, exampleDisk),
+		jobs.ArtifactTTL(time.Millisecond),
+		jobs.CleanupInterval(0),
+		jobs.Handle(exampleKind, func(c *jobs.Context) error {
+			return c.Artifact("foo.txt", "text/plain", strings.NewReader("foo"))
+		}),
+	)
+
+	errs := run(t, ctx, r)
+
+	job, err := r.Start(ctx, exampleKind, nil)
+	if err != nil {
+		t.Fatalf("start job: %v", err)
+	}
+
+	job = await(t, r, job.ID, jobs.StatusSucceeded, errs)
+
+	<-time.After(10 * time.Millisecond)
+
+	if _, _, err := r.Artifact(ctx, job.ID); !errors.Is(err, jobs.ErrArtifactExpired) {
+		t.Fatalf("Artifact should fail with %q; got %v", jobs.ErrArtifactExpired, err)
+	}
+
+	if err := r.Cleanup(ctx); err != nil {
+		t.Fatalf("cleanup: %v", err)
+	}
+
+	if _, err := disk.Get(ctx, job.Artifact.Path); err == nil {
+		t.Fatalf("expired artifact should have been deleted from disk")
+	}
+
+	job, err = r.Job(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("fetch job: %v", err)
+	}
+	if !job.Artifact.Deleted {
+		t.Fatalf("Artifact should be marked as deleted")
+	}
+}
+
+func run(t *testing.T, ctx context.Context, r *jobs.Runner) <-chan error {
+	t.Helper()
+	errs, err := r.Run(ctx)
+	if err != nil {
+		t.Fatalf("run Runner: %v", err)
+	}
+	return errs
+}
+
+func await(t *testing.T, r *jobs.Runner, id uuid.UUID, status jobs.Status, errs <-chan error) jobs.Job {
+	t.Helper()
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case <-timeout:
+			t.Fatalf("timed out waiting for status %q", status)
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				break
+			}
+			t.Fatalf("Runner failed: %v", err)
+		case <-time.After(5 * time.Millisecond):
+		}
+
+		job, err := r.Job(context.Background(), id)
+		if err != nil {
+			t.Fatalf("fetch job: %v", err)
+		}
+		if job.Status == status {
+			return job
+		}
+	}
+}
@@ -0,0 +1,719 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	stdpath "path"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/media"
+)
+
+const (
+	// DefaultArtifactDir is the default directory of the artifact disk in
+	// which artifacts are stored.
+	DefaultArtifactDir = "/jobs"
+
+	// DefaultArtifactTTL is the default time after which artifacts expire.
+	DefaultArtifactTTL = 24 * time.Hour
+
+	// DefaultRetries is the default number of times a failed Job is retried.
+	DefaultRetries = 3
+
+	// DefaultRetryDelay is the default delay before the first retry of a
+	// failed Job. The delay is multiplied by the number of attempts.
+	DefaultRetryDelay = 5 * time.Second
+
+	// DefaultCleanupInterval is the default interval in which expired
+	// artifacts are deleted.
+	DefaultCleanupInterval = time.Hour
+)
+
+// ErrRunning is returned when calling Runner.Run on a Runner that is already
+// running.
+var ErrRunning = errors.New("runner already running")
+
+// Handler runs a Job. Handlers should regularly report their progress using
+// Context.Progress and must return when the Context is canceled. A Handler may
+// be called multiple times for the same Job if the Job is retried or resumed
+// after a restart, so Handlers should resume from the Checkpoint of the Job.
+type Handler func(*Context) error
+
+// Option is an option for a Runner.
+type Option func(*Runner)
+
+// Handle returns an Option that registers the Handler for Jobs of the given
+// kind.
+func Handle(kind string, h Handler) Option {
+	return func(r *Runner) {
+		r.handlers[kind] = h
+	}
+}
+
+// Artifacts returns an Option that configures the storage disk on which the
+// artifacts of Jobs are stored. Without artifact storage, Context.Artifact
+// returns ErrNoStorage.
+func Artifacts(storage media.Storage, disk string) Option {
+	return func(r *Runner) {
+		r.storage = storage
+		r.disk = disk
+	}
+}
+
+// ArtifactDir returns an Option that sets the directory of the artifact disk
+// in which artifacts are stored. Default is DefaultArtifactDir.
+func ArtifactDir(dir string) Option {
+	return func(r *Runner) {
+		r.artifactDir = dir
+	}
+}
+
+// ArtifactTTL returns an Option that sets the time after which artifacts
+// expire. Default is DefaultArtifactTTL.
+func ArtifactTTL(ttl time.Duration) Option {
+	return func(r *Runner) {
+		r.artifactTTL = ttl
+	}
+}
+
+// Retries returns an Option that sets the number of times a failed Job is
+// retried before it is marked as failed. Default is DefaultRetries.
+func Retries(n int) Option {
+	return func(r *Runner) {
+		r.retries = n
+	}
+}
+
+// RetryDelay returns an Option that sets the delay before the first retry of
+// a failed Job. The delay is multiplied by the number of attempts. Default is
+// DefaultRetryDelay.
+func RetryDelay(d time.Duration) Option {
+	return func(r *Runner) {
+		r.retryDelay = d
+	}
+}
+
+// Workers returns an Option that sets the number of Jobs that run in
+// parallel. Default is 1.
+func Workers(n int) Option {
+	return func(r *Runner) {
+		r.workers = n
+	}
+}
+
+// CleanupInterval returns an Option that sets the interval in which expired
+// artifacts are deleted from their disk. A zero or negative interval disables
+// the periodic cleanup. Default is DefaultCleanupInterval.
+func CleanupInterval(d time.Duration) Option {
+	return func(r *Runner) {
+		r.cleanupInterval = d
+	}
+}
+
+// Runner runs Jobs in the background.
+type Runner struct {
+	store    Store
+	handlers map[string]Handler
+
+	storage         media.Storage
+	disk            string
+	artifactDir     string
+	artifactTTL     time.Duration
+	retries         int
+	retryDelay      time.Duration
+	workers         int
+	cleanupInterval time.Duration
+
+	mux      sync.Mutex
+	ctx      context.Context
+	queue    chan uuid.UUID
+	running  map[uuid.UUID]context.CancelFunc
+	canceled map[uuid.UUID]bool
+}
+
+// NewRunner returns a Runner that persists Jobs in the given Store. Handlers
+// for the different kinds of Jobs are registered using the Handle Option.
+func NewRunner(store Store, opts ...Option) *Runner {
+	r := Runner{
+		store:           store,
+		handlers:        make(map[string]Handler),
+		artifactDir:     DefaultArtifactDir,
+		artifactTTL:     DefaultArtifactTTL,
+		retries:         DefaultRetries,
+		retryDelay:      DefaultRetryDelay,
+		workers:         1,
+		cleanupInterval: DefaultCleanupInterval,
+		running:         make(map[uuid.UUID]context.CancelFunc),
+		canceled:        make(map[uuid.UUID]bool),
+	}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	if r.workers < 1 {
+		r.workers = 1
+	}
+	return &r
+}
+
+// Run starts the Runner in the background and returns a channel of Job errors.
+// Only errors of Jobs that have permanently failed are reported. Jobs that are
+// pending or were running when the Runner was stopped are resumed. When ctx is
+// canceled, running Jobs are interrupted and reset to pending, so that they can
+// be resumed by the next Run.
+func (r *Runner) Run(ctx context.Context) (<-chan error, error) {
+	r.mux.Lock()
+	if r.ctx != nil {
+		r.mux.Unlock()
+		return nil, ErrRunning
+	}
+	r.ctx = ctx
+	r.queue = make(chan uuid.UUID)
+	r.mux.Unlock()
+
+	jobs, err := r.store.Jobs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jobs: %w", err)
+	}
+
+	out := make(chan error)
+
+	var wg sync.WaitGroup
+	wg.Add(r.workers)
+	for i := 0; i < r.workers; i++ {
+		go func() {
+			defer wg.Done()
+			r.work(ctx, out)
+		}()
+	}
+
+	if r.cleanupInterval > 0 {
+		go r.cleanup(ctx, out)
+	}
+
+	go func() {
+		wg.Wait()
+		r.mux.Lock()
+		r.ctx = nil
+		r.mux.Unlock()
+		close(out)
+	}()
+
+	for _, job := range jobs {
+		if job.Status.Finished() {
+			continue
+		}
+		go r.enqueue(ctx, job.ID, 0)
+	}
+
+	return out, nil
+}
+
+// Start creates a pending Job of the given kind and schedules it. params are
+// JSON-encoded and can be decoded by the Handler using Context.Params. If the
+// Runner is not running, the Job is run by the next Run. Start returns
+// ErrUnknownKind if no Handler is registered for kind.
+func (r *Runner) Start(ctx context.Context, kind string, params any) (Job, error) {
+	if _, ok := r.handlers[kind]; !ok {
+		return Job{}, fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+
+	b, err := json.Marshal(params)
+	if err != nil {
+		return Job{}, fmt.Errorf("encode params: %w", err)
+	}
+
+	now := time.Now()
+	job := Job{
+		ID:        uuid.New(),
+		Kind:      kind,
+		Params:    b,
+		Status:    StatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := r.store.Save(ctx, job); err != nil {
+		return job, fmt.Errorf("save job: %w", err)
+	}
+
+	r.mux.Lock()
+	runCtx := r.ctx
+	r.mux.Unlock()
+
+	if runCtx != nil {
+		go r.enqueue(runCtx, job.ID, 0)
+	}
+
+	return job, nil
+}
+
+// Job returns the Job with the given UUID or ErrNotFound.
+func (r *Runner) Job(ctx context.Context, id uuid.UUID) (Job, error) {
+	return r.store.Fetch(ctx, id)
+}
+
+// Cancel cancels the Job with the given UUID. A running Job is interrupted and
+// its artifact is deleted. Cancel returns ErrFinished if the Job has already
+// finished.
+func (r *Runner) Cancel(ctx context.Context, id uuid.UUID) (Job, error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	job, err := r.store.Fetch(ctx, id)
+	if err != nil {
+		return job, err
+	}
+
+	if job.Status.Finished() {
+		return job, ErrFinished
+	}
+
+	if cancel, ok := r.running[id]; ok {
+		r.canceled[id] = true
+		cancel()
+	}
+
+	r.finish(&job, StatusCanceled)
+	if err := r.store.Save(ctx, job); err != nil {
+		return job, fmt.Errorf("save job: %w", err)
+	}
+
+	return job, nil
+}
+
+// Artifact returns the Job with the given UUID together with a reader for its
+// artifact. Artifact returns ErrNoArtifact if the Job has no artifact and
+// ErrArtifactExpired if the artifact has expired.
+func (r *Runner) Artifact(ctx context.Context, id uuid.UUID) (Job, io.ReadCloser, error) {
+	job, err := r.store.Fetch(ctx, id)
+	if err != nil {
+		return job, nil, err
+	}
+
+	if job.Artifact == nil {
+		return job, nil, ErrNoArtifact
+	}
+
+	if job.Artifact.Expired(time.Now()) {
+		return job, nil, ErrArtifactExpired
+	}
+
+	if r.storage == nil {
+		return job, nil, ErrNoStorage
+	}
+
+	disk, err := r.storage.Disk(job.Artifact.Disk)
+	if err != nil {
+		return job, nil, fmt.Errorf("get %q disk: %w", job.Artifact.Disk, err)
+	}
+
+	rc, err := media.GetReader(ctx, disk, job.Artifact.Path)
+	if err != nil {
+		return job, nil, fmt.Errorf("read artifact: %w", err)
+	}
+
+	return job, rc, nil
+}
+
+// Cleanup deletes expired artifacts from their disks. The Jobs themselves are
+// kept, so that requesting an expired artifact can be distinguished from
+// requesting an artifact that never existed.
+func (r *Runner) Cleanup(ctx context.Context) error {
+	if r.storage == nil {
+		return nil
+	}
+
+	jobs, err := r.store.Jobs(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch jobs: %w", err)
+	}
+
+	now := time.Now()
+	for _, job := range jobs {
+		if job.Artifact == nil || job.Artifact.Deleted || !job.Artifact.Expired(now) {
+			continue
+		}
+
+		if err := r.expire(ctx, job.ID); err != nil {
+			return fmt.Errorf("delete artifact of job %s: %w", job.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Runner) expire(ctx context.Context, id uuid.UUID) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	job, err := r.store.Fetch(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if job.Artifact == nil || job.Artifact.Deleted || !job.Artifact.Expired(time.Now()) {
+		return nil
+	}
+
+	if err := r.deleteArtifact(ctx, &job); err != nil {
+		return err
+	}
+
+	return r.store.Save(ctx, job)
+}
+
+func (r *Runner) cleanup(ctx context.Context, out chan<- error) {
+	ticker := time.NewTicker(r.cleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Cleanup(ctx); err != nil {
+				fail(ctx, out, err)
+			}
+		}
+	}
+}
+
+func (r *Runner) enqueue(ctx context.Context, id uuid.UUID, delay time.Duration) {
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+	case r.queue <- id:
+	}
+}
+
+func (r *Runner) work(ctx context.Context, out chan<- error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-r.queue:
+			if err := r.run(ctx, id); err != nil {
+				fail(ctx, out, err)
+			}
+		}
+	}
+}
+
+func (r *Runner) run(ctx context.Context, id uuid.UUID) error {
+	r.mux.Lock()
+	job, err := r.store.Fetch(ctx, id)
+	if err != nil {
+		r.mux.Unlock()
+		return fmt.Errorf("fetch job %s: %w", id, err)
+	}
+
+	if job.Status.Finished() {
+		r.mux.Unlock()
+		return nil
+	}
+
+	handler, ok := r.handlers[job.Kind]
+	if !ok {
+		job.Error = fmt.Sprintf("%v: %q", ErrUnknownKind, job.Kind)
+		r.finish(&job, StatusFailed)
+		err := r.store.Save(ctx, job)
+		r.mux.Unlock()
+		if err != nil {
+			return fmt.Errorf("save job %s: %w", id, err)
+		}
+		return fmt.Errorf("job %s: %w: %q", id, ErrUnknownKind, job.Kind)
+	}
+
+	job.Status = StatusRunning
+	job.Attempts++
+	job.UpdatedAt = time.Now()
+	if err := r.store.Save(ctx, job); err != nil {
+		r.mux.Unlock()
+		return fmt.Errorf("save job %s: %w", id, err)
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	r.running[id] = cancel
+	r.mux.Unlock()
+
+	c := newContext(jobCtx, r, job)
+	runErr := handler(c)
+	job = c.Job()
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	delete(r.running, id)
+
+	// Saving the final state must not fail because the Runner was stopped.
+	saveCtx := context.Background()
+
+	if r.canceled[id] {
+		delete(r.canceled, id)
+		r.finish(&job, StatusCanceled)
+		if job.Artifact != nil {
+			r.deleteArtifact(saveCtx, &job)
+		}
+		return r.save(saveCtx, job)
+	}
+
+	if runErr == nil {
+		job.Error = ""
+		job.Progress = 100
+		r.finish(&job, StatusSucceeded)
+		return r.save(saveCtx, job)
+	}
+
+	if ctx.Err() != nil {
+		job.Status = StatusPending
+		job.UpdatedAt = time.Now()
+		return r.save(saveCtx, job)
+	}
+
+	job.Error = runErr.Error()
+
+	var perm *permanentError
+	if errors.As(runErr, &perm) || job.Attempts > r.retries {
+		r.finish(&job, StatusFailed)
+		if err := r.save(saveCtx, job); err != nil {
+			return err
+		}
+		return fmt.Errorf("job %s failed after %d attempt(s): %w", id, job.Attempts, runErr)
+	}
+
+	job.Status = StatusPending
+	job.UpdatedAt = time.Now()
+	if err := r.save(saveCtx, job); err != nil {
+		return err
+	}
+
+	go r.enqueue(ctx, id, r.retryDelay*time.Duration(job.Attempts))
+
+	return nil
+}
+
+func (r *Runner) save(ctx context.Context, job Job) error {
+	if err := r.store.Save(ctx, job); err != nil {
+		return fmt.Errorf("save job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+func (r *Runner) finish(job *Job, status Status) {
+	now := time.Now()
+	job.Status = status
+	job.UpdatedAt = now
+	job.FinishedAt = &now
+}
+
+func (r *Runner) deleteArtifact(ctx context.Context, job *Job) error {
+	if r.storage == nil {
+		return ErrNoStorage
+	}
+
+	disk, err := r.storage.Disk(job.Artifact.Disk)
+	if err != nil {
+		return fmt.Errorf("get %q disk: %w", job.Artifact.Disk, err)
+	}
+
+	if err := disk.Delete(ctx, job.Artifact.Path); err != nil {
+		return fmt.Errorf("delete %q: %w", job.Artifact.Path, err)
+	}
+
+	artifact := *job.Artifact
+	artifact.Deleted = true
+	job.Artifact = &artifact
+
+	return nil
+}
+
+func fail(ctx context.Context, out chan<- error, err error) {
+	select {
+	case <-ctx.Done():
+	case out <- err:
+	}
+}
+
+// Context is passed to Handlers. It is canceled when the Job is canceled or
+// the Runner is stopped. A Context is safe for concurrent use.
+type Context struct {
+	context.Context
+
+	runner *Runner
+	id     uuid.UUID
+
+	mux sync.Mutex
+	job Job
+}
+
+func newContext(ctx context.Context, r *Runner, job Job) *Context {
+	return &Context{Context: ctx, runner: r, id: job.ID, job: job}
+}
+
+// Job returns the current state of the Job.
+func (c *Context) Job() Job {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.job
+}
+
+// Params decodes the params of the Job into v.
+func (c *Context) Params(v any) error {
+	job := c.Job()
+	if len(job.Params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(job.Params, v); err != nil {
+		return fmt.Errorf("decode params: %w", err)
+	}
+	return nil
+}
+
+// Checkpoint decodes the last checkpoint that was reported by Progress into v.
+// Checkpoint returns false if no checkpoint has been reported yet.
+func (c *Context) Checkpoint(v any) (bool, error) {
+	job := c.Job()
+	if len(job.Checkpoint) == 0 {
+		return false, nil
+	}
+	if err := json.Unmarshal(job.Checkpoint, v); err != nil {
+		return false, fmt.Errorf("decode checkpoint: %w", err)
+	}
+	return true, nil
+}
+
+// Progress reports the progress of the Job in percent, together with an
+// optional checkpoint from which the Job can be resumed. A nil checkpoint
+// keeps the previous checkpoint. Progress returns the error of the Context if
+// the Job was canceled.
+func (c *Context) Progress(percent int, checkpoint any) error {
+	if percent < 0 {
+		percent = 0
+	}
+	if percent > 100 {
+		percent = 100
+	}
+
+	var b []byte
+	if checkpoint != nil {
+		var err error
+		if b, err = json.Marshal(checkpoint); err != nil {
+			return fmt.Errorf("encode checkpoint: %w", err)
+		}
+	}
+
+	return c.update(func(job *Job) {
+		job.Progress = percent
+		if b != nil {
+			job.Checkpoint = b
+		}
+	})
+}
+
+// Artifact stores the contents of r as the artifact of the Job, replacing any
+// previous artifact. The artifact expires after the configured ArtifactTTL.
+// Artifact returns ErrNoStorage if the Runner has no artifact storage.
+func (c *Context) Artifact(name, contentType string, r io.Reader) error {
+	runner := c.runner
+	if runner.storage == nil {
+		return ErrNoStorage
+	}
+
+	disk, err := runner.storage.Disk(runner.disk)
+	if err != nil {
+		return fmt.Errorf("get %q disk: %w", runner.disk, err)
+	}
+
+	path := stdpath.Join("/", runner.artifactDir, c.id.String(), stdpath.Base("/"+name))
+	counter := &countingReader{r: r}
+	if err := media.PutReader(c, disk, path, counter); err != nil {
+		return fmt.Errorf("put artifact: %w", err)
+	}
+
+	return c.update(func(job *Job) {
+		job.Artifact = &Artifact{
+			Name:        name,
+			ContentType: contentType,
+			Disk:        runner.disk,
+			Path:        path,
+			Size:        counter.n,
+			ExpiresAt:   time.Now().Add(runner.artifactTTL),
+		}
+	})
+}
+
+func (c *Context) update(fn func(*Job)) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	r := c.runner
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	// A canceled Job must not be overwritten by a Handler that is still
+	// running.
+	if r.canceled[c.id] {
+		return context.Canceled
+	}
+
+	if err := c.Err(); err != nil {
+		return err
+	}
+
+	job := c.job
+	fn(&job)
+	job.UpdatedAt = time.Now()
+
+	if err := r.store.Save(c, job); err != nil {
+		return fmt.Errorf("save job: %w", err)
+	}
+	c.job = job
+
+	return nil
+}
+
+// Permanent marks err as permanent. Jobs that fail with a permanent error are
+// not retried.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (err *permanentError) Error() string {
+	return err.err.Error()
+}
+
+func (err *permanentError) Unwrap() error {
+	return err.err
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
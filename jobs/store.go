@@ -0,0 +1,172 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type memoryStore struct {
+	mux  sync.RWMutex
+	jobs map[uuid.UUID]Job
+}
+
+// MemoryStore returns an in-memory Store. Jobs in a MemoryStore are lost when
+// the process exits, so they cannot be resumed after a restart.
+func MemoryStore() Store {
+	return &memoryStore{jobs: make(map[uuid.UUID]Job)}
+}
+
+func (s *memoryStore) Save(_ context.Context, job Job) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.jobs[job.ID] = job
+	return nil
+}
+
+func (s *memoryStore) Fetch(_ context.Context, id uuid.UUID) (Job, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	return job, nil
+}
+
+func (s *memoryStore) Jobs(context.Context) ([]Job, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	out := make([]Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		out = append(out, job)
+	}
+	sortJobs(out)
+	return out, nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, id uuid.UUID) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	delete(s.jobs, id)
+	return nil
+}
+
+type fsStore struct {
+	mux  sync.RWMutex
+	root string
+}
+
+// FSStore returns a Store that persists Jobs as JSON files in the given
+// directory of the local filesystem. The directory is created if it doesn't
+// exist.
+func FSStore(dir string) Store {
+	return &fsStore{root: dir}
+}
+
+func (s *fsStore) Save(_ context.Context, job Job) error {
+	b, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("encode job: %w", err)
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if err := os.MkdirAll(s.root, 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	// Write to a temporary file first, so that a crash cannot leave a
+	// partially written job behind.
+	tmp := s.path(job.ID) + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return fmt.Errorf("write job: %w", err)
+	}
+
+	if err := os.Rename(tmp, s.path(job.ID)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write job: %w", err)
+	}
+
+	return nil
+}
+
+func (s *fsStore) Fetch(_ context.Context, id uuid.UUID) (Job, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.read(s.path(id))
+}
+
+func (s *fsStore) Jobs(context.Context) ([]Job, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	entries, err := os.ReadDir(s.root)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read directory: %w", err)
+	}
+
+	out := make([]Job, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		job, err := s.read(filepath.Join(s.root, entry.Name()))
+		if err != nil {
+			return out, err
+		}
+		out = append(out, job)
+	}
+	sortJobs(out)
+
+	return out, nil
+}
+
+func (s *fsStore) Delete(_ context.Context, id uuid.UUID) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *fsStore) read(path string) (Job, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return Job{}, ErrNotFound
+		}
+		return Job{}, fmt.Errorf("read job: %w", err)
+	}
+
+	var job Job
+	if err := json.Unmarshal(b, &job); err != nil {
+		return Job{}, fmt.Errorf("decode job %q: %w", filepath.Base(path), err)
+	}
+
+	return job, nil
+}
+
+func (s *fsStore) path(id uuid.UUID) string {
+	return filepath.Join(s.root, id.String()+".json")
+}
+
+func sortJobs(jobs []Job) {
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+}
@@ -0,0 +1,65 @@
+package media
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// AudioMetadata is the metadata of an audio file.
+type AudioMetadata struct {
+	Duration time.Duration `json:"duration"`
+
+	// Bitrate is the bitrate in bits per second.
+	Bitrate int `json:"bitrate"`
+}
+
+// An AudioMetadataExtractor extracts the metadata of audio files. The FFmpeg
+// type of the media/video package implements AudioMetadataExtractor.
+type AudioMetadataExtractor interface {
+	ExtractAudioMetadata(context.Context, io.Reader) (AudioMetadata, error)
+}
+
+// Audio is a storage audio file. The duration and bitrate of an Audio cannot
+// be read by Go itself. They are filled in by an AudioMetadataExtractor after
+// the upload.
+type Audio struct {
+	File
+	AudioMetadata
+}
+
+// NewAudio returns an Audio with the given data.
+func NewAudio(name, disk, path string, filesize int) Audio {
+	return Audio{File: NewFile(name, disk, path, filesize)}
+}
+
+// WithTag adds the given tags and returns the updated Audio.
+func (a Audio) WithTag(tags ...string) Audio {
+	a.File = a.File.WithTag(tags...)
+	return a
+}
+
+// WithoutTag removes the given tags and returns the updated Audio.
+func (a Audio) WithoutTag(tags ...string) Audio {
+	a.File = a.File.WithoutTag(tags...)
+	return a
+}
+
+// Upload uploads the audio file to storage and returns the Audio with updated
+// Filesize.
+func (a Audio) Upload(ctx context.Context, r io.Reader, storage Storage) (Audio, error) {
+	f, err := a.File.Upload(ctx, r, storage)
+	if err != nil {
+		return a, err
+	}
+	a.File = f
+	return a, nil
+}
+
+// Replace replaces the audio file in Storage with the audio file in r and
+// returns the updated Audio. The metadata is reset, because it is unknown
+// until the metadata of the new file has been extracted.
+func (a Audio) Replace(ctx context.Context, r io.Reader, storage Storage) (Audio, error) {
+	a.AudioMetadata = AudioMetadata{}
+	return a.Upload(ctx, r, storage)
+}
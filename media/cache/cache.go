@@ -0,0 +1,97 @@
+// Package cache provides the stores of the read caches of Shelfs and Galleries
+// (see document.ReadCache and gallery.ReadCache).
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// A Store stores encoded aggregates by key. Implementations must be
+// thread-safe.
+type Store interface {
+	// Get returns the value of the given key. If the key does not exist, Get
+	// returns false.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set sets the value of the given key.
+	Set(ctx context.Context, key string, value []byte) error
+
+	// Delete deletes the given key. Deleting a key that does not exist is not
+	// an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Key returns the key of the aggregate with the given name and UUID.
+func Key(name string, id uuid.UUID) string {
+	return name + ":" + id.String()
+}
+
+type lru struct {
+	size int
+
+	mux     sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type lruEntry struct {
+	key   string
+	value []byte
+}
+
+// LRU returns an in-memory Store that holds at most size values. When the Store
+// is full, the least recently used value is evicted. If size is zero or less,
+// the Store is unbounded.
+func LRU(size int) Store {
+	return &lru{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *lru) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).value, true, nil
+}
+
+func (c *lru) Set(_ context.Context, key string, value []byte) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruEntry).value = value
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value})
+
+	if c.size > 0 && c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+
+	return nil
+}
+
+func (c *lru) Delete(_ context.Context, key string) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modernice/nice-cms/media/cache"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	store := cache.LRU(2)
+
+	store.Set(ctx, "a", []byte("a"))
+	store.Set(ctx, "b", []byte("b"))
+
+	// "a" is now the most recently used value.
+	expectValue(t, store, "a", "a")
+
+	store.Set(ctx, "c", []byte("c"))
+
+	expectNoValue(t, store, "b")
+	expectValue(t, store, "a", "a")
+	expectValue(t, store, "c", "c")
+
+	store.Set(ctx, "a", []byte("updated"))
+	expectValue(t, store, "a", "updated")
+
+	if err := store.Delete(ctx, "a"); err != nil {
+		t.Fatalf("Delete failed with %q", err)
+	}
+	expectNoValue(t, store, "a")
+
+	if err := store.Delete(ctx, "a"); err != nil {
+		t.Fatalf("deleting a missing key should not fail; got %q", err)
+	}
+}
+
+func TestRedis(t *testing.T) {
+	ctx := context.Background()
+	client := &redisClient{values: make(map[string][]byte)}
+	store := cache.Redis(client, cache.Prefix("test:"), cache.TTL(time.Minute))
+
+	if err := store.Set(ctx, "a", []byte("a")); err != nil {
+		t.Fatalf("Set failed with %q", err)
+	}
+
+	if _, ok := client.values["test:a"]; !ok {
+		t.Fatalf("value should be stored with the prefixed key %q", "test:a")
+	}
+	if client.ttl != time.Minute {
+		t.Fatalf("value should be stored with a TTL of %v; got %v", time.Minute, client.ttl)
+	}
+
+	expectValue(t, store, "a", "a")
+
+	if err := store.Delete(ctx, "a"); err != nil {
+		t.Fatalf("Delete failed with %q", err)
+	}
+	expectNoValue(t, store, "a")
+}
+
+func expectValue(t *testing.T, store cache.Store, key, want string) {
+	t.Helper()
+	b, ok, err := store.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get(%q) failed with %q", key, err)
+	}
+	if !ok || string(b) != want {
+		t.Fatalf("Get(%q) should return %q; got %q (%v)", key, want, b, ok)
+	}
+}
+
+func expectNoValue(t *testing.T, store cache.Store, key string) {
+	t.Helper()
+	if _, ok, err := store.Get(context.Background(), key); err != nil || ok {
+		t.Fatalf("Get(%q) should return no value; got %v (%v)", key, ok, err)
+	}
+}
+
+type redisClient struct {
+	values map[string][]byte
+	ttl    time.Duration
+}
+
+func (c *redisClient) Get(_ context.Context, key string) ([]byte, bool, error) {
+	b, ok := c.values[key]
+	return b, ok, nil
+}
+
+func (c *redisClient) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.values[key] = value
+	c.ttl = ttl
+	return nil
+}
+
+func (c *redisClient) Del(_ context.Context, key string) error {
+	delete(c.values, key)
+	return nil
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// RedisClient is the subset of a Redis client that is used by the Redis Store.
+// This package does not depend on a Redis driver; wrap the client of your
+// driver instead. For example, using github.com/go-redis/redis/v8:
+//
+//	type client struct{ *redis.Client }
+//
+//	func (c client) Get(ctx context.Context, key string) ([]byte, bool, error) {
+//		b, err := c.Client.Get(ctx, key).Bytes()
+//		if errors.Is(err, redis.Nil) {
+//			return nil, false, nil
+//		}
+//		return b, err == nil, err
+//	}
+//
+//	func (c client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+//		return c.Client.Set(ctx, key, value, ttl).Err()
+//	}
+//
+//	func (c client) Del(ctx context.Context, key string) error {
+//		return c.Client.Del(ctx, key).Err()
+//	}
+type RedisClient interface {
+	// Get returns the value of the given key, or false if the key does not
+	// exist.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set sets the value of the given key. If ttl is greater than zero, the
+	// key expires after ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Del deletes the given key.
+	Del(ctx context.Context, key string) error
+}
+
+// RedisOption is an option for the Redis Store.
+type RedisOption func(*redisStore)
+
+// Prefix returns a RedisOption that prefixes all keys with the given prefix.
+// Default is "nice-cms:".
+func Prefix(prefix string) RedisOption {
+	return func(s *redisStore) {
+		s.prefix = prefix
+	}
+}
+
+// TTL returns a RedisOption that expires the values after the given duration.
+// Default is no expiry.
+func TTL(d time.Duration) RedisOption {
+	return func(s *redisStore) {
+		s.ttl = d
+	}
+}
+
+type redisStore struct {
+	client RedisClient
+	prefix string
+	ttl    time.Duration
+}
+
+// Redis returns a Store that stores values in Redis. A Redis Store can be
+// shared by multiple instances of an application, as long as every instance
+// runs its read caches to invalidate the values.
+func Redis(client RedisClient, opts ...RedisOption) Store {
+	s := &redisStore{client: client, prefix: "nice-cms:"}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *redisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return s.client.Get(ctx, s.prefix+key)
+}
+
+func (s *redisStore) Set(ctx context.Context, key string, value []byte) error {
+	return s.client.Set(ctx, s.prefix+key, value, s.ttl)
+}
+
+func (s *redisStore) Delete(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.prefix+key)
+}
@@ -0,0 +1,121 @@
+package media
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	stdpath "path"
+	"strings"
+	"time"
+)
+
+// ContentDisk is a StorageDisk that stores files under content-addressed
+// paths. Files that are uploaded to a ContentDisk keep their Path as a logical
+// path, while their contents are stored at StoragePath. See ContentAddressed.
+type ContentDisk interface {
+	StorageDisk
+
+	// ContentPath returns the storage path of a file with the given checksum
+	// and logical path.
+	ContentPath(checksum, path string) string
+}
+
+// ContentAddressed returns a ContentDisk that stores uploaded files under the
+// hex-encoded SHA-256 checksum of their contents instead of their Path. The
+// first two characters of the checksum are used as a directory and the file
+// extension of the logical path is kept:
+//
+//	/2c/26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae.png
+//
+// Because the storage path changes with the contents of a file, replacing a
+// file never overwrites content that may still be cached by a CDN, and
+// identical files are stored only once. For the same reason, File.Delete does
+// not delete the contents of a content-addressed File, because they may still
+// be referenced by other Files.
+//
+// All other operations are delegated to disk.
+func ContentAddressed(disk StorageDisk) ContentDisk {
+	return contentDisk{disk}
+}
+
+type contentDisk struct {
+	StorageDisk
+}
+
+func (d contentDisk) ContentPath(checksum, path string) string {
+	ext := strings.ToLower(stdpath.Ext(path))
+	if len(checksum) < 3 {
+		return "/" + checksum + ext
+	}
+	return "/" + checksum[:2] + "/" + checksum[2:] + ext
+}
+
+func (d contentDisk) PutReader(ctx context.Context, path string, r io.Reader) error {
+	return PutReader(ctx, d.StorageDisk, path, r)
+}
+
+func (d contentDisk) GetReader(ctx context.Context, path string) (io.ReadCloser, error) {
+	return GetReader(ctx, d.StorageDisk, path)
+}
+
+func (d contentDisk) GetRange(ctx context.Context, path string, off, length int64) ([]byte, error) {
+	return GetRange(ctx, d.StorageDisk, path, off, length)
+}
+
+func (d contentDisk) GetRangeReader(ctx context.Context, path string, off, length int64) (io.ReadCloser, error) {
+	return GetRangeReader(ctx, d.StorageDisk, path, off, length)
+}
+
+func (d contentDisk) Copy(ctx context.Context, src, dst string) error {
+	return Copy(ctx, d.StorageDisk, src, dst)
+}
+
+func (d contentDisk) Move(ctx context.Context, src, dst string) error {
+	return Move(ctx, d.StorageDisk, src, dst)
+}
+
+func (d contentDisk) List(ctx context.Context, prefix string) ([]FileInfo, error) {
+	return List(ctx, d.StorageDisk, prefix)
+}
+
+func (d contentDisk) Stat(ctx context.Context, path string) (FileInfo, error) {
+	return Stat(ctx, d.StorageDisk, path)
+}
+
+func (d contentDisk) SignURL(ctx context.Context, path string, expiry time.Duration) (string, error) {
+	return SignURL(ctx, d.StorageDisk, path, expiry)
+}
+
+// uploadContent streams r to a temporary path of disk and moves it to the
+// content path of its checksum afterwards. An existing file at the content
+// path has the same contents and is overwritten.
+func (f File) uploadContent(ctx context.Context, r io.Reader, disk ContentDisk) (File, error) {
+	tmp, err := tempPath()
+	if err != nil {
+		return f, err
+	}
+
+	uploaded, err := f.put(ctx, r, disk, tmp)
+	if err != nil {
+		return f, err
+	}
+
+	path := disk.ContentPath(uploaded.Checksum, f.Path)
+	if err := Move(ctx, disk, tmp, path); err != nil {
+		disk.Delete(ctx, tmp)
+		return f, fmt.Errorf("move upload to %q: %w", path, err)
+	}
+	uploaded.StoragePath = path
+
+	return uploaded, nil
+}
+
+func tempPath() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate temporary path: %w", err)
+	}
+	return "/.tmp/" + hex.EncodeToString(b), nil
+}
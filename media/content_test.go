@@ -0,0 +1,86 @@
+package media_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/modernice/nice-cms/media"
+)
+
+func TestContentAddressed(t *testing.T) {
+	ctx := context.Background()
+	disk := media.MemoryDisk()
+	storage := media.NewStorage(media.ConfigureDisk("foo", media.ContentAddressed(disk)))
+
+	// echo -n "foo" | sha256sum
+	checksum := "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+	wantPath := "/2c/26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae.txt"
+
+	f, err := media.NewFile("foo", "foo", "/foo.txt", 0).Upload(ctx, strings.NewReader("foo"), storage)
+	if err != nil {
+		t.Fatalf("Upload shouldn't fail; failed with %q", err)
+	}
+
+	if f.Path != "/foo.txt" {
+		t.Fatalf("Path should be the logical path %q; is %q", "/foo.txt", f.Path)
+	}
+
+	if f.Checksum != checksum {
+		t.Fatalf("Checksum should be %q; is %q", checksum, f.Checksum)
+	}
+
+	if f.StoragePath != wantPath {
+		t.Fatalf("StoragePath should be %q; is %q", wantPath, f.StoragePath)
+	}
+
+	if f.Location() != wantPath {
+		t.Fatalf("Location should return %q; got %q", wantPath, f.Location())
+	}
+
+	if _, err := disk.Get(ctx, "/foo.txt"); !errors.Is(err, media.ErrFileNotFound) {
+		t.Fatalf("nothing should be stored at the logical path; Get returned %v", err)
+	}
+
+	b, err := f.Download(ctx, storage)
+	if err != nil {
+		t.Fatalf("Download shouldn't fail; failed with %q", err)
+	}
+
+	if string(b) != "foo" {
+		t.Fatalf("Download should return %q; got %q", "foo", b)
+	}
+
+	dup, err := media.NewFile("bar", "foo", "/bar.txt", 0).Upload(ctx, strings.NewReader("foo"), storage)
+	if err != nil {
+		t.Fatalf("Upload shouldn't fail; failed with %q", err)
+	}
+
+	if dup.StoragePath != f.StoragePath {
+		t.Fatalf("identical files should be stored once at %q; got %q", f.StoragePath, dup.StoragePath)
+	}
+
+	replaced, err := f.Replace(ctx, strings.NewReader("bar"), storage)
+	if err != nil {
+		t.Fatalf("Replace shouldn't fail; failed with %q", err)
+	}
+
+	if replaced.StoragePath == f.StoragePath {
+		t.Fatalf("replaced file should have a new StoragePath")
+	}
+
+	// The previous contents may still be referenced and cached.
+	if b, err := disk.Get(ctx, f.StoragePath); err != nil || !bytes.Equal(b, []byte("foo")) {
+		t.Fatalf("previous contents should still be stored at %q; got %q (%v)", f.StoragePath, b, err)
+	}
+
+	if err := replaced.Delete(ctx, storage); err != nil {
+		t.Fatalf("Delete shouldn't fail; failed with %q", err)
+	}
+
+	if _, err := disk.Get(ctx, replaced.StoragePath); err != nil {
+		t.Fatalf("Delete shouldn't delete the contents of a content-addressed file; Get returned %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// DeleteConcurrency is the maximum number of files that DeleteFiles deletes
+// concurrently.
+const DeleteConcurrency = 16
+
+// DeleteFailure is a file that could not be deleted from a Storage.
+type DeleteFailure struct {
+	Disk  string `json:"disk"`
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// DeleteFiles concurrently deletes the given files from storage and returns
+// the files that could not be deleted. Files that don't exist are not reported.
+// Content-addressed files are not deleted (see File.Delete). If ctx is
+// canceled, the remaining files are reported with the error of ctx.
+func DeleteFiles(ctx context.Context, storage Storage, files ...File) []DeleteFailure {
+	var (
+		mux      sync.Mutex
+		failures []DeleteFailure
+		wg       sync.WaitGroup
+	)
+
+	fail := func(f File, err error) {
+		mux.Lock()
+		defer mux.Unlock()
+		failures = append(failures, DeleteFailure{Disk: f.Disk, Path: f.Path, Error: err.Error()})
+	}
+
+	sem := make(chan struct{}, DeleteConcurrency)
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			fail(f, err)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			fail(f, ctx.Err())
+			continue
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(f File) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := f.Delete(ctx, storage); err != nil && !errors.Is(err, ErrFileNotFound) {
+				fail(f, err)
+			}
+		}(f)
+	}
+	wg.Wait()
+
+	return failures
+}
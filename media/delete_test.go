@@ -0,0 +1,53 @@
+package media_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/modernice/nice-cms/media"
+)
+
+func TestDeleteFiles(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+	disk, _ := storage.Disk("foo-disk")
+
+	var files []media.File
+	for i := 0; i < 2*media.DeleteConcurrency; i++ {
+		path := fmt.Sprintf("/foo/%d.txt", i)
+		if err := disk.Put(ctx, path, []byte("foo")); err != nil {
+			t.Fatalf("put file: %v", err)
+		}
+		files = append(files, media.NewFile("foo", "foo-disk", path, 3))
+	}
+	files = append(files, media.NewFile("bar", "bar-disk", "/bar.txt", 3))
+
+	failures := media.DeleteFiles(ctx, storage, files...)
+
+	if len(failures) != 1 || failures[0].Disk != "bar-disk" || failures[0].Path != "/bar.txt" || failures[0].Error == "" {
+		t.Fatalf("DeleteFiles should report the file on the unconfigured disk; got %v", failures)
+	}
+
+	for _, f := range files[:len(files)-1] {
+		if _, err := disk.Get(ctx, f.Path); !errors.Is(err, media.ErrFileNotFound) {
+			t.Fatalf("%q should have been deleted; Get returned %v", f.Path, err)
+		}
+	}
+}
+
+func TestDeleteFiles_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+	files := []media.File{
+		media.NewFile("foo", "foo-disk", "/foo.txt", 3),
+		media.NewFile("bar", "foo-disk", "/bar.txt", 3),
+	}
+
+	if failures := media.DeleteFiles(ctx, storage, files...); len(failures) != len(files) {
+		t.Fatalf("DeleteFiles should report all files when ctx is canceled; got %v", failures)
+	}
+}
@@ -0,0 +1,390 @@
+// Package delta implements rsync-style binary deltas between two versions of
+// a file. A client that knows the current contents of a file computes a Delta
+// against its modified copy using Diff and uploads only the Delta. The server
+// reconstructs the modified file from its stored copy using Patch.
+package delta
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultBlockSize is the default size of the blocks of the base file that
+// are matched against the target file.
+const DefaultBlockSize = 4 << 10
+
+var (
+	// ErrBaseMismatch is returned when a Delta is applied to a file that is not
+	// the file the Delta was computed against. Clients should fall back to
+	// uploading the full file.
+	ErrBaseMismatch = errors.New("delta base mismatch")
+
+	// ErrChecksumMismatch is returned by Patch if the reconstructed file does
+	// not match the checksum of the target file.
+	ErrChecksumMismatch = errors.New("delta checksum mismatch")
+
+	// ErrInvalidDelta is returned when decoding a malformed Delta.
+	ErrInvalidDelta = errors.New("invalid delta")
+)
+
+// Delta is the difference between a base file and a target file.
+type Delta struct {
+	// BaseChecksum is the hex-encoded SHA-256 checksum of the base file. It
+	// can be compared to the Checksum of a media.File.
+	BaseChecksum string
+
+	// Checksum is the hex-encoded SHA-256 checksum of the target file.
+	Checksum string
+
+	// Ops are the operations that reconstruct the target file from the base
+	// file.
+	Ops []Op
+}
+
+// Op is an operation of a Delta. If Data is nil, Op copies Length bytes at
+// Offset from the base file. Otherwise Op writes Data.
+type Op struct {
+	Offset int64
+	Length int64
+	Data   []byte
+}
+
+// Size returns the number of literal bytes of the Delta, which is roughly the
+// number of bytes that have to be transferred in addition to the Delta's
+// metadata.
+func (d Delta) Size() int {
+	var n int
+	for _, op := range d.Ops {
+		n += len(op.Data)
+	}
+	return n
+}
+
+// Option is an option for Diff.
+type Option func(*differ)
+
+// BlockSize returns an Option that sets the block size that is used to match
+// the base file against the target file. Smaller blocks result in smaller
+// Deltas at the cost of a slower Diff. Defaults to DefaultBlockSize.
+func BlockSize(n int) Option {
+	return func(d *differ) {
+		if n > 0 {
+			d.blockSize = n
+		}
+	}
+}
+
+type differ struct {
+	blockSize int
+	blocks    map[uint32][]block
+	last      block
+	delta     Delta
+}
+
+type block struct {
+	offset int64
+	length int
+	sum    [sha256.Size]byte
+}
+
+// Diff returns the Delta between base and target. base is read in blocks and
+// is never held in memory as a whole.
+func Diff(base io.Reader, target []byte, opts ...Option) (Delta, error) {
+	d := differ{
+		blockSize: DefaultBlockSize,
+		blocks:    make(map[uint32][]block),
+	}
+	for _, opt := range opts {
+		opt(&d)
+	}
+
+	baseChecksum, err := d.sign(base)
+	if err != nil {
+		return Delta{}, fmt.Errorf("sign base: %w", err)
+	}
+
+	sum := sha256.Sum256(target)
+	d.delta = Delta{
+		BaseChecksum: baseChecksum,
+		Checksum:     hex.EncodeToString(sum[:]),
+	}
+	d.diff(target)
+
+	return d.delta, nil
+}
+
+// sign reads the blocks of base and returns the checksum of base.
+func (d *differ) sign(base io.Reader) (string, error) {
+	h := sha256.New()
+	buf := make([]byte, d.blockSize)
+	var offset int64
+	for {
+		n, err := io.ReadFull(base, buf)
+		if n > 0 {
+			h.Write(buf[:n])
+			b := block{
+				offset: offset,
+				length: n,
+				sum:    sha256.Sum256(buf[:n]),
+			}
+			if n == d.blockSize {
+				weak := weakSum(buf[:n])
+				d.blocks[weak] = append(d.blocks[weak], b)
+			} else {
+				d.last = b
+			}
+			offset += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return hex.EncodeToString(h.Sum(nil)), nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+func (d *differ) diff(target []byte) {
+	bs := d.blockSize
+	var literal, i int
+	var a, b uint32
+	if len(target) >= bs {
+		a, b = rollingSum(target[:bs])
+	}
+
+	for i+bs <= len(target) {
+		if blk, ok := d.match(a|b<<16, target[i:i+bs]); ok {
+			d.write(target[literal:i])
+			d.copy(blk.offset, int64(blk.length))
+			i += bs
+			literal = i
+			if i+bs <= len(target) {
+				a, b = rollingSum(target[i : i+bs])
+			}
+			continue
+		}
+
+		if i+bs < len(target) {
+			out, in := uint32(target[i]), uint32(target[i+bs])
+			a = (a - out + in) & 0xffff
+			b = (b - uint32(bs)*out + a) & 0xffff
+		}
+		i++
+	}
+
+	// The last block of the base file is shorter than the block size and can
+	// only match the tail of the target file.
+	if tail := target[literal:]; d.last.length > 0 && len(tail) >= d.last.length {
+		start := len(target) - d.last.length
+		if sha256.Sum256(target[start:]) == d.last.sum {
+			d.write(target[literal:start])
+			d.copy(d.last.offset, int64(d.last.length))
+			literal = len(target)
+		}
+	}
+
+	d.write(target[literal:])
+}
+
+func (d *differ) match(weak uint32, data []byte) (block, bool) {
+	candidates, ok := d.blocks[weak]
+	if !ok {
+		return block{}, false
+	}
+	sum := sha256.Sum256(data)
+	for _, blk := range candidates {
+		if blk.sum == sum {
+			return blk, true
+		}
+	}
+	return block{}, false
+}
+
+func (d *differ) write(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	d.delta.Ops = append(d.delta.Ops, Op{Data: append([]byte(nil), data...)})
+}
+
+// copy adds a copy operation and merges it with the previous operation if both
+// copy adjacent ranges of the base file.
+func (d *differ) copy(offset, length int64) {
+	if n := len(d.delta.Ops); n > 0 {
+		prev := &d.delta.Ops[n-1]
+		if prev.Data == nil && prev.Offset+prev.Length == offset {
+			prev.Length += length
+			return
+		}
+	}
+	d.delta.Ops = append(d.delta.Ops, Op{Offset: offset, Length: length})
+}
+
+// rollingSum returns the two halves of the rsync rolling checksum of data.
+func rollingSum(data []byte) (a, b uint32) {
+	l := uint32(len(data))
+	for i, c := range data {
+		a += uint32(c)
+		b += (l - uint32(i)) * uint32(c)
+	}
+	return a & 0xffff, b & 0xffff
+}
+
+func weakSum(data []byte) uint32 {
+	a, b := rollingSum(data)
+	return a | b<<16
+}
+
+// Patch reconstructs the target file of d from base and writes it to w. If
+// the reconstructed file does not match the checksum of d, ErrChecksumMismatch
+// is returned after the file has been written. Callers that need to verify the
+// base file should compare d.BaseChecksum before calling Patch.
+func Patch(base io.ReadSeeker, d Delta, w io.Writer) error {
+	h := sha256.New()
+	w = io.MultiWriter(w, h)
+
+	for _, op := range d.Ops {
+		if op.Data != nil {
+			if _, err := w.Write(op.Data); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err := base.Seek(op.Offset, io.SeekStart); err != nil {
+			return fmt.Errorf("seek base: %w", err)
+		}
+		if _, err := io.CopyN(w, base, op.Length); err != nil {
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("copy %d bytes at offset %d: %w", op.Length, op.Offset, ErrBaseMismatch)
+			}
+			return fmt.Errorf("copy from base: %w", err)
+		}
+	}
+
+	if d.Checksum != "" && hex.EncodeToString(h.Sum(nil)) != d.Checksum {
+		return ErrChecksumMismatch
+	}
+
+	return nil
+}
+
+// Binary format of an encoded Delta:
+//
+//	magic     "NCD1"
+//	base      32 bytes SHA-256 checksum of the base file
+//	target    32 bytes SHA-256 checksum of the target file
+//	ops       sequence of operations until the end of the data
+//
+// A copy operation is opCopy followed by the uvarint offset and length. A data
+// operation is opData followed by the uvarint length and the data.
+const magic = "NCD1"
+
+const (
+	opCopy = byte(1)
+	opData = byte(2)
+)
+
+// MarshalBinary encodes d into its binary format.
+func (d Delta) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(magic)
+
+	for _, sum := range []string{d.BaseChecksum, d.Checksum} {
+		b, err := hex.DecodeString(sum)
+		if err != nil || len(b) != sha256.Size {
+			return nil, fmt.Errorf("%w: malformed checksum %q", ErrInvalidDelta, sum)
+		}
+		buf.Write(b)
+	}
+
+	var num [binary.MaxVarintLen64]byte
+	writeUvarint := func(v uint64) {
+		buf.Write(num[:binary.PutUvarint(num[:], v)])
+	}
+
+	for _, op := range d.Ops {
+		if op.Data != nil {
+			buf.WriteByte(opData)
+			writeUvarint(uint64(len(op.Data)))
+			buf.Write(op.Data)
+			continue
+		}
+		buf.WriteByte(opCopy)
+		writeUvarint(uint64(op.Offset))
+		writeUvarint(uint64(op.Length))
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a Delta from its binary format.
+func (d *Delta) UnmarshalBinary(b []byte) error {
+	r := bytes.NewReader(b)
+
+	header := make([]byte, len(magic)+2*sha256.Size)
+	if _, err := io.ReadFull(r, header); err != nil || string(header[:len(magic)]) != magic {
+		return fmt.Errorf("%w: malformed header", ErrInvalidDelta)
+	}
+	header = header[len(magic):]
+
+	out := Delta{
+		BaseChecksum: hex.EncodeToString(header[:sha256.Size]),
+		Checksum:     hex.EncodeToString(header[sha256.Size:]),
+	}
+
+	for {
+		typ, err := r.ReadByte()
+		if err == io.EOF {
+			break
+		}
+
+		switch typ {
+		case opCopy:
+			offset, err := binary.ReadUvarint(r)
+			if err != nil {
+				return fmt.Errorf("%w: read offset: %v", ErrInvalidDelta, err)
+			}
+			length, err := binary.ReadUvarint(r)
+			if err != nil {
+				return fmt.Errorf("%w: read length: %v", ErrInvalidDelta, err)
+			}
+			out.Ops = append(out.Ops, Op{Offset: int64(offset), Length: int64(length)})
+		case opData:
+			length, err := binary.ReadUvarint(r)
+			if err != nil {
+				return fmt.Errorf("%w: read length: %v", ErrInvalidDelta, err)
+			}
+			if length > uint64(r.Len()) {
+				return fmt.Errorf("%w: data exceeds delta", ErrInvalidDelta)
+			}
+			data := make([]byte, length)
+			r.Read(data)
+			out.Ops = append(out.Ops, Op{Data: data})
+		default:
+			return fmt.Errorf("%w: unknown operation %d", ErrInvalidDelta, typ)
+		}
+	}
+
+	*d = out
+
+	return nil
+}
+
+// Decode reads an encoded Delta from r.
+func Decode(r io.Reader) (Delta, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return Delta{}, err
+	}
+	var d Delta
+	if err := d.UnmarshalBinary(b); err != nil {
+		return Delta{}, err
+	}
+	return d, nil
+}
@@ -0,0 +1,127 @@
+package delta_test
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/modernice/nice-cms/media/delta"
+)
+
+func TestDiff(t *testing.T) {
+	base := randomBytes(100 << 10)
+
+	tests := map[string][]byte{
+		"unchanged": base,
+		"insert":    concat(base[:30<<10], []byte("inserted"), base[30<<10:]),
+		"delete":    concat(base[:10<<10], base[12<<10:]),
+		"modify":    concat(base[:50<<10], []byte("modified"), base[50<<10+8:]),
+		"append":    concat(base, []byte("appended")),
+		"prepend":   concat([]byte("prepended"), base),
+		"empty":     {},
+		"unrelated": randomBytes(20 << 10),
+	}
+
+	for name, target := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, err := delta.Diff(bytes.NewReader(base), target)
+			if err != nil {
+				t.Fatalf("Diff failed with %q", err)
+			}
+
+			var buf bytes.Buffer
+			if err := delta.Patch(bytes.NewReader(base), d, &buf); err != nil {
+				t.Fatalf("Patch failed with %q", err)
+			}
+
+			if !bytes.Equal(buf.Bytes(), target) {
+				t.Fatalf("patched file should equal the target file")
+			}
+		})
+	}
+}
+
+func TestDiff_size(t *testing.T) {
+	base := randomBytes(1 << 20)
+	target := concat(base[:300<<10], []byte("foo"), base[300<<10:])
+
+	d, err := delta.Diff(bytes.NewReader(base), target)
+	if err != nil {
+		t.Fatalf("Diff failed with %q", err)
+	}
+
+	if d.Size() > 2*delta.DefaultBlockSize {
+		t.Fatalf("Delta should contain at most %d literal bytes; has %d", 2*delta.DefaultBlockSize, d.Size())
+	}
+}
+
+func TestPatch_checksumMismatch(t *testing.T) {
+	base := randomBytes(20 << 10)
+	target := concat(base, []byte("foo"))
+
+	d, err := delta.Diff(bytes.NewReader(base), target)
+	if err != nil {
+		t.Fatalf("Diff failed with %q", err)
+	}
+
+	other := randomBytes(20 << 10)
+	if err := delta.Patch(bytes.NewReader(other), d, &bytes.Buffer{}); !errors.Is(err, delta.ErrChecksumMismatch) {
+		t.Fatalf("Patch should fail with %q; got %q", delta.ErrChecksumMismatch, err)
+	}
+
+	if err := delta.Patch(bytes.NewReader(base[:10]), d, &bytes.Buffer{}); !errors.Is(err, delta.ErrBaseMismatch) {
+		t.Fatalf("Patch should fail with %q for a shorter base; got %q", delta.ErrBaseMismatch, err)
+	}
+}
+
+func TestDelta_MarshalBinary(t *testing.T) {
+	base := randomBytes(50 << 10)
+	target := concat(base[:20<<10], []byte("foo"), base[25<<10:])
+
+	d, err := delta.Diff(bytes.NewReader(base), target, delta.BlockSize(1<<10))
+	if err != nil {
+		t.Fatalf("Diff failed with %q", err)
+	}
+
+	b, err := d.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed with %q", err)
+	}
+
+	decoded, err := delta.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("Decode failed with %q", err)
+	}
+
+	var buf bytes.Buffer
+	if err := delta.Patch(bytes.NewReader(base), decoded, &buf); err != nil {
+		t.Fatalf("Patch failed with %q", err)
+	}
+
+	if !bytes.Equal(buf.Bytes(), target) {
+		t.Fatalf("patched file should equal the target file")
+	}
+
+	if _, err := delta.Decode(bytes.NewReader(b[:len(b)-1])); !errors.Is(err, delta.ErrInvalidDelta) {
+		t.Fatalf("Decode should fail with %q for a truncated Delta; got %q", delta.ErrInvalidDelta, err)
+	}
+
+	if _, err := delta.Decode(bytes.NewReader([]byte("foo"))); !errors.Is(err, delta.ErrInvalidDelta) {
+		t.Fatalf("Decode should fail with %q; got %q", delta.ErrInvalidDelta, err)
+	}
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	rand.Read(b)
+	return b
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
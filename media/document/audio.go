@@ -0,0 +1,65 @@
+package document
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/media"
+)
+
+var audioExtensions = map[string]bool{
+	".aac":  true,
+	".flac": true,
+	".m4a":  true,
+	".mp3":  true,
+	".oga":  true,
+	".ogg":  true,
+	".opus": true,
+	".wav":  true,
+}
+
+// IsAudio returns whether the Document is an audio file, based on the file
+// extension of its path.
+func (doc Document) IsAudio() bool {
+	return audioExtensions[strings.ToLower(filepath.Ext(doc.Path))]
+}
+
+// AsAudio returns the Document as a media.Audio. AsAudio returns false if the
+// metadata of the Document was not extracted yet.
+func (doc Document) AsAudio() (media.Audio, bool) {
+	if doc.Audio == nil {
+		return media.Audio{}, false
+	}
+	return media.Audio{File: doc.File, AudioMetadata: *doc.Audio}, true
+}
+
+// SetAudioMetadata sets the extracted audio metadata of the Document with the
+// given UUID. If the Document cannot be found in the Shelf, ErrNotFound is
+// returned.
+func (s *Shelf) SetAudioMetadata(id uuid.UUID, meta media.AudioMetadata) (Document, error) {
+	doc, err := s.Document(id)
+	if err != nil {
+		return doc, err
+	}
+
+	aggregate.NextEvent(s, AudioExtracted, AudioExtractedData{
+		DocumentID: doc.ID,
+		Metadata:   meta,
+	})
+
+	return s.Document(doc.ID)
+}
+
+func (s *Shelf) extractAudio(evt event.Event) {
+	data := evt.Data().(AudioExtractedData)
+	doc, err := s.Document(data.DocumentID)
+	if err != nil {
+		return
+	}
+	meta := data.Metadata
+	doc.Audio = &meta
+	s.replace(doc.ID, doc)
+}
@@ -0,0 +1,201 @@
+package document_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+)
+
+const exampleAudioPath = "/example/episode.mp3"
+
+var exampleAudioMetadata = media.AudioMetadata{
+	Duration: 3*time.Minute + 4*time.Second,
+	Bitrate:  128000,
+}
+
+func TestShelf_SetAudioMetadata(t *testing.T) {
+	shelf, doc := newShelfWithAudio(t)
+
+	updated, err := shelf.SetAudioMetadata(doc.ID, exampleAudioMetadata)
+	if err != nil {
+		t.Fatalf("SetAudioMetadata failed with %q", err)
+	}
+
+	if updated.Audio == nil || *updated.Audio != exampleAudioMetadata {
+		t.Fatalf("Audio should be %v; got %v", exampleAudioMetadata, updated.Audio)
+	}
+
+	audio, ok := updated.AsAudio()
+	if !ok {
+		t.Fatalf("AsAudio should return true after the metadata was set")
+	}
+
+	if !audio.Same(updated.File) || audio.AudioMetadata != exampleAudioMetadata {
+		t.Fatalf("AsAudio should return the file and metadata of the Document; got %v", audio)
+	}
+
+	test.Change(t, shelf, document.AudioExtracted, test.EventData(document.AudioExtractedData{
+		DocumentID: doc.ID,
+		Metadata:   exampleAudioMetadata,
+	}))
+}
+
+func TestShelf_SetAudioMetadata_notFound(t *testing.T) {
+	shelf, _ := newShelfWithAudio(t)
+
+	if _, err := shelf.SetAudioMetadata(uuid.New(), exampleAudioMetadata); !errors.Is(err, document.ErrNotFound) {
+		t.Fatalf("SetAudioMetadata should fail with %q; got %q", document.ErrNotFound, err)
+	}
+}
+
+func TestShelf_Replace_audio(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf, doc := newShelfWithAudio(t)
+	shelf.SetAudioMetadata(doc.ID, exampleAudioMetadata)
+
+	replaced, err := shelf.Replace(context.Background(), storage, bytes.NewReader([]byte("bar")), doc.ID)
+	if err != nil {
+		t.Fatalf("Replace failed with %q", err)
+	}
+
+	if replaced.Audio != nil {
+		t.Fatalf("Audio should be reset after Replace; got %v", replaced.Audio)
+	}
+}
+
+func TestDocument_IsAudio(t *testing.T) {
+	tests := map[string]bool{
+		"/example/episode.mp3":  true,
+		"/example/episode.M4A":  true,
+		"/example/episode.flac": true,
+		"/example/example.pdf":  false,
+		"/example/example":      false,
+	}
+
+	for path, want := range tests {
+		doc := document.Document{Document: media.NewDocument(exampleName, exampleDisk, path, 0)}
+		if got := doc.IsAudio(); got != want {
+			t.Errorf("IsAudio should return %v for %q; got %v", want, path, got)
+		}
+	}
+}
+
+func TestPostProcessor_Process_audio(t *testing.T) {
+	ctx := context.Background()
+	storage, shelfs := newPreviewServices()
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+	doc, err := shelf.Add(ctx, storage, bytes.NewReader([]byte("foo")), "", exampleName, exampleDisk, exampleAudioPath)
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save shelf: %v", err)
+	}
+
+	extractor := mockAudioExtractor(func(r io.Reader) (media.AudioMetadata, error) {
+		if b, _ := io.ReadAll(r); string(b) != "foo" {
+			t.Errorf("AudioMetadataExtractor should receive the document content %q; got %q", "foo", b)
+		}
+		return exampleAudioMetadata, nil
+	})
+
+	svc := document.NewPostProcessor(nil, storage, shelfs, document.ExtractAudio(extractor))
+
+	if err := svc.Process(ctx, shelf.ID, doc.ID); err != nil {
+		t.Fatalf("Process failed with %q", err)
+	}
+
+	doc = fetchDocument(t, shelfs, shelf.ID, doc.ID)
+
+	if doc.Audio == nil || *doc.Audio != exampleAudioMetadata {
+		t.Fatalf("Audio should be %v; got %v", exampleAudioMetadata, doc.Audio)
+	}
+}
+
+func TestPostProcessor_Process_audioError(t *testing.T) {
+	ctx := context.Background()
+	storage, shelfs := newPreviewServices()
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+	doc, err := shelf.Add(ctx, storage, bytes.NewReader([]byte("foo")), "", exampleName, exampleDisk, exampleAudioPath)
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save shelf: %v", err)
+	}
+
+	mockError := errors.New("mock error")
+	extractor := mockAudioExtractor(func(io.Reader) (media.AudioMetadata, error) {
+		return media.AudioMetadata{}, mockError
+	})
+
+	svc := document.NewPostProcessor(nil, storage, shelfs, document.ExtractAudio(extractor))
+
+	if err := svc.Process(ctx, shelf.ID, doc.ID); !errors.Is(err, mockError) {
+		t.Fatalf("Process should fail with %q; got %q", mockError, err)
+	}
+
+	if doc = fetchDocument(t, shelfs, shelf.ID, doc.ID); doc.Audio != nil {
+		t.Fatalf("Audio should be nil; got %v", doc.Audio)
+	}
+}
+
+func TestPostProcessor_Process_audioNonAudio(t *testing.T) {
+	ctx := context.Background()
+	storage, shelfs := newPreviewServices()
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+	doc, err := shelf.Add(ctx, storage, newPDF(), "", exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save shelf: %v", err)
+	}
+
+	extractor := mockAudioExtractor(func(io.Reader) (media.AudioMetadata, error) {
+		t.Fatal("AudioMetadataExtractor should not be called for non-audio documents")
+		return media.AudioMetadata{}, nil
+	})
+
+	svc := document.NewPostProcessor(nil, storage, shelfs, document.ExtractAudio(extractor))
+
+	if err := svc.Process(ctx, shelf.ID, doc.ID); err != nil {
+		t.Fatalf("Process failed with %q", err)
+	}
+}
+
+type mockAudioExtractor func(io.Reader) (media.AudioMetadata, error)
+
+func (fn mockAudioExtractor) ExtractAudioMetadata(_ context.Context, r io.Reader) (media.AudioMetadata, error) {
+	return fn(r)
+}
+
+func newShelfWithAudio(t *testing.T) (*document.Shelf, document.Document) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(context.Background(), storage, bytes.NewReader([]byte("foo")), "", exampleName, exampleDisk, exampleAudioPath)
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+
+	return shelf, doc
+}
@@ -2,6 +2,7 @@ package document
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/modernice/goes/codec"
@@ -13,12 +14,26 @@ import (
 // Shelf commands.
 const (
 	CreateShelfCommand   = "cms.media.document.shelf.create"
+	DeleteShelfCommand   = "cms.media.document.shelf.delete"
+	RenameShelfCommand   = "cms.media.document.shelf.rename"
 	RemoveCommand        = "cms.media.document.shelf.remove_document"
 	RenameCommand        = "cms.media.document.shelf.rename_document"
 	MakeUniqueCommand    = "cms.media.document.shelf.make_document_unique"
 	MakeNonUniqueCommand = "cms.media.document.shelf.make_document_non_unique"
 	TagCommand           = "cms.media.document.shelf.tag_document"
 	UntagCommand         = "cms.media.document.shelf.untag_document"
+	RetagCommand         = "cms.media.document.shelf.retag_documents"
+	CopyCommand          = "cms.media.document.shelf.copy_document"
+	RelocateCommand      = "cms.media.document.shelf.relocate_document"
+	RemoveVariantCommand = "cms.media.document.shelf.remove_variant"
+	TrashCommand         = "cms.media.document.shelf.trash_document"
+	RestoreCommand       = "cms.media.document.shelf.restore_document"
+	PurgeCommand         = "cms.media.document.shelf.purge_document"
+	SetLegalHoldCommand  = "cms.media.document.shelf.set_legal_hold"
+	LiftLegalHoldCommand = "cms.media.document.shelf.lift_legal_hold"
+
+	SetMetadataCommand    = "cms.media.document.shelf.set_document_metadata"
+	DeleteMetadataCommand = "cms.media.document.shelf.delete_document_metadata"
 )
 
 type createShelfPayload struct{ Name string }
@@ -28,6 +43,21 @@ func CreateShelf(id uuid.UUID, name string) command.Cmd[createShelfPayload] {
 	return command.New(CreateShelfCommand, createShelfPayload{Name: name}, command.Aggregate(Aggregate, id))
 }
 
+type deleteShelfPayload struct{}
+
+// DeleteShelf returns the command to delete a shelf and the files of its
+// documents. See Shelf.Destroy.
+func DeleteShelf(id uuid.UUID) command.Cmd[deleteShelfPayload] {
+	return command.New(DeleteShelfCommand, deleteShelfPayload{}, command.Aggregate(Aggregate, id))
+}
+
+type renameShelfPayload struct{ Name string }
+
+// RenameShelf returns the command to rename a shelf.
+func RenameShelf(id uuid.UUID, name string) command.Cmd[renameShelfPayload] {
+	return command.New(RenameShelfCommand, renameShelfPayload{Name: name}, command.Aggregate(Aggregate, id))
+}
+
 type removePayload struct{ DocumentID uuid.UUID }
 
 // Remove returns the command to remove a document from a shelf.
@@ -97,19 +127,215 @@ func Untag(shelfID, documentID uuid.UUID, tags []string) command.Cmd[untagPayloa
 	}, command.Aggregate(Aggregate, shelfID))
 }
 
+type retagPayload struct {
+	DocumentIDs []uuid.UUID
+	Add         []string
+	Remove      []string
+}
+
+// Retag returns the command to add and remove tags of multiple documents of a
+// shelf. See Shelf.Retag.
+func Retag(shelfID uuid.UUID, documentIDs []uuid.UUID, add, remove []string) command.Cmd[retagPayload] {
+	return command.New(RetagCommand, retagPayload{
+		DocumentIDs: documentIDs,
+		Add:         add,
+		Remove:      remove,
+	}, command.Aggregate(Aggregate, shelfID))
+}
+
+type copyPayload struct {
+	DocumentID    uuid.UUID
+	TargetShelfID uuid.UUID
+	CopyID        uuid.UUID
+	Disk          string
+	Path          string
+}
+
+// Copy returns the command to copy a document of a shelf to the target shelf.
+// The copy gets the UUID copyID and is stored at the given disk and path. See
+// Shelf.Copy.
+func Copy(shelfID, documentID, targetShelfID, copyID uuid.UUID, disk, path string) command.Cmd[copyPayload] {
+	return command.New(CopyCommand, copyPayload{
+		DocumentID:    documentID,
+		TargetShelfID: targetShelfID,
+		CopyID:        copyID,
+		Disk:          disk,
+		Path:          path,
+	}, command.Aggregate(Aggregate, shelfID))
+}
+
+type relocatePayload struct {
+	DocumentID uuid.UUID
+	Disk       string
+	Path       string
+}
+
+// Relocate returns the command to move the file of a document to the given
+// disk and path. See Shelf.RelocateDocument.
+func Relocate(shelfID, documentID uuid.UUID, disk, path string) command.Cmd[relocatePayload] {
+	return command.New(RelocateCommand, relocatePayload{
+		DocumentID: documentID,
+		Disk:       disk,
+		Path:       path,
+	}, command.Aggregate(Aggregate, shelfID))
+}
+
+type setMetadataPayload struct {
+	DocumentID uuid.UUID
+	Fields     map[string]string
+}
+
+// SetMetadata returns the command to set custom metadata fields of a document
+// of a shelf.
+func SetMetadata(shelfID, documentID uuid.UUID, fields map[string]string) command.Cmd[setMetadataPayload] {
+	return command.New(SetMetadataCommand, setMetadataPayload{
+		DocumentID: documentID,
+		Fields:     fields,
+	}, command.Aggregate(Aggregate, shelfID))
+}
+
+type deleteMetadataPayload struct {
+	DocumentID uuid.UUID
+	Keys       []string
+}
+
+// DeleteMetadata returns the command to remove custom metadata fields from a
+// document of a shelf.
+func DeleteMetadata(shelfID, documentID uuid.UUID, keys []string) command.Cmd[deleteMetadataPayload] {
+	return command.New(DeleteMetadataCommand, deleteMetadataPayload{
+		DocumentID: documentID,
+		Keys:       keys,
+	}, command.Aggregate(Aggregate, shelfID))
+}
+
+type removeVariantPayload struct {
+	DocumentID uuid.UUID
+	Locale     string
+}
+
+// RemoveVariant returns the command to remove a locale variant from a document
+// of a shelf.
+func RemoveVariant(shelfID, documentID uuid.UUID, locale string) command.Cmd[removeVariantPayload] {
+	return command.New(RemoveVariantCommand, removeVariantPayload{
+		DocumentID: documentID,
+		Locale:     locale,
+	}, command.Aggregate(Aggregate, shelfID))
+}
+
+type trashPayload struct{ DocumentID uuid.UUID }
+
+// Trash returns the command to move a document of a shelf to the trash.
+func Trash(shelfID, documentID uuid.UUID) command.Cmd[trashPayload] {
+	return command.New(TrashCommand, trashPayload{DocumentID: documentID}, command.Aggregate(Aggregate, shelfID))
+}
+
+type restorePayload struct{ DocumentID uuid.UUID }
+
+// Restore returns the command to restore a document from the trash of a shelf.
+func Restore(shelfID, documentID uuid.UUID) command.Cmd[restorePayload] {
+	return command.New(RestoreCommand, restorePayload{DocumentID: documentID}, command.Aggregate(Aggregate, shelfID))
+}
+
+type purgePayload struct{ DocumentID uuid.UUID }
+
+// Purge returns the command to delete a document in the trash of a shelf.
+func Purge(shelfID, documentID uuid.UUID) command.Cmd[purgePayload] {
+	return command.New(PurgeCommand, purgePayload{DocumentID: documentID}, command.Aggregate(Aggregate, shelfID))
+}
+
+type setLegalHoldPayload struct {
+	DocumentID uuid.UUID
+	Reason     string
+}
+
+// SetLegalHold returns the command to set a legal hold on a document of a
+// shelf. If documentID is uuid.Nil, the legal hold is set on the whole shelf.
+func SetLegalHold(shelfID, documentID uuid.UUID, reason string) command.Cmd[setLegalHoldPayload] {
+	return command.New(SetLegalHoldCommand, setLegalHoldPayload{
+		DocumentID: documentID,
+		Reason:     reason,
+	}, command.Aggregate(Aggregate, shelfID))
+}
+
+type liftLegalHoldPayload struct{ DocumentID uuid.UUID }
+
+// LiftLegalHold returns the command to lift the legal hold of a document of a
+// shelf. If documentID is uuid.Nil, the legal hold of the whole shelf is
+// lifted.
+func LiftLegalHold(shelfID, documentID uuid.UUID) command.Cmd[liftLegalHoldPayload] {
+	return command.New(LiftLegalHoldCommand, liftLegalHoldPayload{DocumentID: documentID}, command.Aggregate(Aggregate, shelfID))
+}
+
 // RegisterCommand registers document commands.
 func RegisterCommands(r codec.Registerer) {
 	codec.Register[createShelfPayload](r, CreateShelfCommand)
+	codec.Register[deleteShelfPayload](r, DeleteShelfCommand)
+	codec.Register[renameShelfPayload](r, RenameShelfCommand)
 	codec.Register[removePayload](r, RemoveCommand)
 	codec.Register[renamePayload](r, RenameCommand)
 	codec.Register[makeUniquePayload](r, MakeUniqueCommand)
 	codec.Register[makeNonUniquePayload](r, MakeNonUniqueCommand)
 	codec.Register[tagPayload](r, TagCommand)
 	codec.Register[untagPayload](r, UntagCommand)
+	codec.Register[retagPayload](r, RetagCommand)
+	codec.Register[copyPayload](r, CopyCommand)
+	codec.Register[relocatePayload](r, RelocateCommand)
+	codec.Register[setMetadataPayload](r, SetMetadataCommand)
+	codec.Register[deleteMetadataPayload](r, DeleteMetadataCommand)
+	codec.Register[removeVariantPayload](r, RemoveVariantCommand)
+	codec.Register[trashPayload](r, TrashCommand)
+	codec.Register[restorePayload](r, RestoreCommand)
+	codec.Register[purgePayload](r, PurgeCommand)
+	codec.Register[setLegalHoldPayload](r, SetLegalHoldCommand)
+	codec.Register[liftLegalHoldPayload](r, LiftLegalHoldCommand)
+}
+
+// CommandOption is an option for HandleCommands.
+type CommandOption func(*commandConfig)
+
+type commandConfig struct {
+	paths PathStrategy
+}
+
+// MovePaths returns a CommandOption that moves the file of a document to the
+// path returned by strategy when the document is renamed or its unique name
+// changes. The rename and the move are applied together: if the file cannot be
+// moved, the document is not renamed, and if the Shelf cannot be saved, the
+// file is moved back to its previous path.
+//
+//	errs := document.HandleCommands(ctx, bus, shelfs, storage, document.MovePaths(document.SlugPath("")))
+func MovePaths(strategy PathStrategy) CommandOption {
+	return func(cfg *commandConfig) {
+		cfg.paths = strategy
+	}
 }
 
 // HandleCommand handles commands until ctx is canceled.
-func HandleCommands(ctx context.Context, bus command.Bus, shelfs Repository, storage media.Storage) <-chan error {
+func HandleCommands(ctx context.Context, bus command.Bus, shelfs Repository, storage media.Storage, opts ...CommandOption) <-chan error {
+	var cfg commandConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	// rename calls fn with the Shelf and moves the renamed Document using the
+	// configured PathStrategy.
+	rename := func(ctx context.Context, shelfID, documentID uuid.UUID, fn func(*Shelf) error) error {
+		var moved *DocumentMovedData
+		err := shelfs.Use(ctx, shelfID, func(s *Shelf) error {
+			if err := fn(s); err != nil {
+				return err
+			}
+			if cfg.paths == nil {
+				return nil
+			}
+			return s.movePath(ctx, storage, documentID, cfg.paths, &moved)
+		})
+		if err != nil && moved != nil {
+			rollbackMove(ctx, storage, *moved)
+		}
+		return err
+	}
+
 	createErrors := command.MustHandle(ctx, bus, CreateShelfCommand, func(ctx command.Ctx[createShelfPayload]) error {
 		load := ctx.Payload()
 
@@ -118,6 +344,33 @@ func HandleCommands(ctx context.Context, bus command.Bus, shelfs Repository, sto
 		})
 	})
 
+	deleteErrors := command.MustHandle(ctx, bus, DeleteShelfCommand, func(ctx command.Ctx[deleteShelfPayload]) error {
+		s, err := shelfs.Fetch(ctx, ctx.AggregateID())
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.Destroy(ctx, storage); err != nil {
+			return err
+		}
+
+		// The ShelfDeleted event is saved before the Shelf is deleted so that
+		// projections can remove the Shelf.
+		if err := shelfs.Save(ctx, s); err != nil {
+			return fmt.Errorf("save Shelf: %w", err)
+		}
+
+		return shelfs.Delete(ctx, s)
+	})
+
+	renameShelfErrors := command.MustHandle(ctx, bus, RenameShelfCommand, func(ctx command.Ctx[renameShelfPayload]) error {
+		load := ctx.Payload()
+
+		return shelfs.Use(ctx, ctx.AggregateID(), func(s *Shelf) error {
+			return s.Rename(load.Name)
+		})
+	})
+
 	removeErrors := command.MustHandle(ctx, bus, RemoveCommand, func(ctx command.Ctx[removePayload]) error {
 		load := ctx.Payload()
 
@@ -129,7 +382,7 @@ func HandleCommands(ctx context.Context, bus command.Bus, shelfs Repository, sto
 	renameErrors := command.MustHandle(ctx, bus, RenameCommand, func(ctx command.Ctx[renamePayload]) error {
 		load := ctx.Payload()
 
-		return shelfs.Use(ctx, ctx.AggregateID(), func(s *Shelf) error {
+		return rename(ctx, ctx.AggregateID(), load.DocumentID, func(s *Shelf) error {
 			_, err := s.RenameDocument(load.DocumentID, load.Name)
 			return err
 		})
@@ -138,7 +391,7 @@ func HandleCommands(ctx context.Context, bus command.Bus, shelfs Repository, sto
 	makeUniqueErrors := command.MustHandle(ctx, bus, MakeUniqueCommand, func(ctx command.Ctx[makeUniquePayload]) error {
 		load := ctx.Payload()
 
-		return shelfs.Use(ctx, ctx.AggregateID(), func(s *Shelf) error {
+		return rename(ctx, ctx.AggregateID(), load.DocumentID, func(s *Shelf) error {
 			_, err := s.MakeUnique(load.DocumentID, load.UniqueName)
 			return err
 		})
@@ -147,7 +400,7 @@ func HandleCommands(ctx context.Context, bus command.Bus, shelfs Repository, sto
 	makeNonUniqueErrors := command.MustHandle(ctx, bus, MakeNonUniqueCommand, func(ctx command.Ctx[makeNonUniquePayload]) error {
 		load := ctx.Payload()
 
-		return shelfs.Use(ctx, ctx.AggregateID(), func(s *Shelf) error {
+		return rename(ctx, ctx.AggregateID(), load.DocumentID, func(s *Shelf) error {
 			_, err := s.MakeNonUnique(load.DocumentID)
 			return err
 		})
@@ -171,14 +424,133 @@ func HandleCommands(ctx context.Context, bus command.Bus, shelfs Repository, sto
 		})
 	})
 
+	retagErrors := command.MustHandle(ctx, bus, RetagCommand, func(ctx command.Ctx[retagPayload]) error {
+		load := ctx.Payload()
+
+		return shelfs.Use(ctx, ctx.AggregateID(), func(s *Shelf) error {
+			_, err := s.Retag(load.DocumentIDs, load.Add, load.Remove)
+			return err
+		})
+	})
+
+	copyErrors := command.MustHandle(ctx, bus, CopyCommand, func(ctx command.Ctx[copyPayload]) error {
+		load := ctx.Payload()
+
+		source, err := shelfs.Fetch(ctx, ctx.AggregateID())
+		if err != nil {
+			return err
+		}
+
+		doc, err := source.Document(load.DocumentID)
+		if err != nil {
+			return err
+		}
+
+		return shelfs.Use(ctx, load.TargetShelfID, func(s *Shelf) error {
+			_, err := s.Copy(ctx, storage, doc, load.CopyID, load.Disk, load.Path)
+			return err
+		})
+	})
+
+	relocateErrors := command.MustHandle(ctx, bus, RelocateCommand, func(ctx command.Ctx[relocatePayload]) error {
+		load := ctx.Payload()
+
+		return shelfs.Use(ctx, ctx.AggregateID(), func(s *Shelf) error {
+			_, err := s.RelocateDocument(ctx, storage, load.DocumentID, load.Disk, load.Path)
+			return err
+		})
+	})
+
+	setMetadataErrors := command.MustHandle(ctx, bus, SetMetadataCommand, func(ctx command.Ctx[setMetadataPayload]) error {
+		load := ctx.Payload()
+
+		return shelfs.Use(ctx, ctx.AggregateID(), func(s *Shelf) error {
+			_, err := s.SetMetadata(load.DocumentID, load.Fields)
+			return err
+		})
+	})
+
+	deleteMetadataErrors := command.MustHandle(ctx, bus, DeleteMetadataCommand, func(ctx command.Ctx[deleteMetadataPayload]) error {
+		load := ctx.Payload()
+
+		return shelfs.Use(ctx, ctx.AggregateID(), func(s *Shelf) error {
+			_, err := s.DeleteMetadata(load.DocumentID, load.Keys...)
+			return err
+		})
+	})
+
+	removeVariantErrors := command.MustHandle(ctx, bus, RemoveVariantCommand, func(ctx command.Ctx[removeVariantPayload]) error {
+		load := ctx.Payload()
+
+		return shelfs.Use(ctx, ctx.AggregateID(), func(s *Shelf) error {
+			_, err := s.RemoveVariant(ctx, storage, load.DocumentID, load.Locale)
+			return err
+		})
+	})
+
+	trashErrors := command.MustHandle(ctx, bus, TrashCommand, func(ctx command.Ctx[trashPayload]) error {
+		load := ctx.Payload()
+
+		return shelfs.Use(ctx, ctx.AggregateID(), func(s *Shelf) error {
+			_, err := s.Trash(load.DocumentID)
+			return err
+		})
+	})
+
+	restoreErrors := command.MustHandle(ctx, bus, RestoreCommand, func(ctx command.Ctx[restorePayload]) error {
+		load := ctx.Payload()
+
+		return shelfs.Use(ctx, ctx.AggregateID(), func(s *Shelf) error {
+			_, err := s.Restore(load.DocumentID)
+			return err
+		})
+	})
+
+	purgeErrors := command.MustHandle(ctx, bus, PurgeCommand, func(ctx command.Ctx[purgePayload]) error {
+		load := ctx.Payload()
+
+		return shelfs.Use(ctx, ctx.AggregateID(), func(s *Shelf) error {
+			return s.Purge(ctx, storage, load.DocumentID)
+		})
+	})
+
+	setLegalHoldErrors := command.MustHandle(ctx, bus, SetLegalHoldCommand, func(ctx command.Ctx[setLegalHoldPayload]) error {
+		load := ctx.Payload()
+
+		return shelfs.Use(ctx, ctx.AggregateID(), func(s *Shelf) error {
+			return s.SetLegalHold(load.DocumentID, load.Reason)
+		})
+	})
+
+	liftLegalHoldErrors := command.MustHandle(ctx, bus, LiftLegalHoldCommand, func(ctx command.Ctx[liftLegalHoldPayload]) error {
+		load := ctx.Payload()
+
+		return shelfs.Use(ctx, ctx.AggregateID(), func(s *Shelf) error {
+			return s.LiftLegalHold(load.DocumentID)
+		})
+	})
+
 	return streams.FanInContext(
 		ctx,
 		createErrors,
+		deleteErrors,
+		renameShelfErrors,
 		removeErrors,
 		renameErrors,
 		makeUniqueErrors,
 		makeNonUniqueErrors,
 		tagErrors,
 		untagErrors,
+		retagErrors,
+		copyErrors,
+		relocateErrors,
+		setMetadataErrors,
+		deleteMetadataErrors,
+		removeVariantErrors,
+		trashErrors,
+		restoreErrors,
+		purgeErrors,
+		setLegalHoldErrors,
+		liftLegalHoldErrors,
 	)
 }
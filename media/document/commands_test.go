@@ -0,0 +1,112 @@
+package document_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/command/cmdbus"
+	"github.com/modernice/goes/command/cmdbus/dispatch"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/commands"
+	"github.com/modernice/nice-cms/internal/discard"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+)
+
+func TestMovePaths(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	disk := media.MemoryDisk()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, disk))
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	cbus := cmdbus.New(commands.NewRegistry(), ebus)
+	shelfs := document.GoesRepository(repository.New(estore))
+
+	shelf, doc := savedShelfWithDocument(t, ctx, shelfs, storage)
+
+	errs := document.HandleCommands(ctx, cbus, shelfs, storage, document.MovePaths(document.SlugPath("")))
+	go discard.Errors(errs)
+
+	if err := cbus.Dispatch(ctx, document.Rename(shelf.ID, doc.ID, "Annual Report").Any(), dispatch.Sync()); err != nil {
+		t.Fatalf("dispatch command: %v", err)
+	}
+
+	if shelf, err := shelfs.Fetch(ctx, shelf.ID); err != nil {
+		t.Fatalf("fetch Shelf: %v", err)
+	} else if doc, _ = shelf.Document(doc.ID); doc.Path != "/example/annual-report.pdf" {
+		t.Fatalf("Document should have been moved to %q; has path %q", "/example/annual-report.pdf", doc.Path)
+	}
+
+	if _, err := disk.Get(ctx, "/example/annual-report.pdf"); err != nil {
+		t.Fatalf("file should have been moved; Get returned %v", err)
+	}
+}
+
+func TestMovePaths_rollback(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	disk := media.MemoryDisk()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, disk))
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	cbus := cmdbus.New(commands.NewRegistry(), ebus)
+	shelfs := document.GoesRepository(repository.New(estore))
+
+	shelf, doc := savedShelfWithDocument(t, ctx, shelfs, storage)
+
+	errs := document.HandleCommands(ctx, cbus, failingRepository{shelfs}, storage, document.MovePaths(document.SlugPath("")))
+	go discard.Errors(errs)
+
+	if err := cbus.Dispatch(ctx, document.Rename(shelf.ID, doc.ID, "Annual Report").Any(), dispatch.Sync()); err == nil {
+		t.Fatalf("dispatch should fail")
+	}
+
+	if _, err := disk.Get(ctx, examplePath); err != nil {
+		t.Fatalf("file should have been moved back to %q; Get returned %v", examplePath, err)
+	}
+
+	if _, err := disk.Get(ctx, "/example/annual-report.pdf"); !errors.Is(err, media.ErrFileNotFound) {
+		t.Fatalf("file should not exist at the new path; Get returned %v", err)
+	}
+}
+
+func savedShelfWithDocument(t *testing.T, ctx context.Context, shelfs document.Repository, storage media.Storage) (*document.Shelf, document.Document) {
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), "", exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save Shelf: %v", err)
+	}
+
+	return shelf, doc
+}
+
+// failingRepository is a Repository that fails to save Shelfs in Use.
+type failingRepository struct {
+	document.Repository
+}
+
+func (r failingRepository) Use(ctx context.Context, id uuid.UUID, fn func(*document.Shelf) error) error {
+	shelf, err := r.Fetch(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := fn(shelf); err != nil {
+		return err
+	}
+	return errors.New("save failed")
+}
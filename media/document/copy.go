@@ -0,0 +1,103 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	stdpath "path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/nice-cms/media"
+)
+
+// Copy adds a copy of the source Document, which usually belongs to another
+// Shelf, to the Shelf. The file of the source Document is copied to the given
+// disk and path of storage, and the copy keeps the name, tags and custom
+// metadata of the source Document. The unique name, locale variants, preview
+// and legal hold of the source Document are not copied.
+//
+// If disk is empty, the copy is stored on the disk of the source Document. If
+// path is empty, the copy keeps the path of the source Document; if the copy
+// is stored on the same disk, the UUID of the copy is appended to the file name
+// so that the copy doesn't overwrite the source file. If the path is already
+// used by the source Document or by another Document of the Shelf,
+// ErrDuplicatePath is returned.
+//
+// BeforeUpload and AfterUpload hooks are called before and after the upload.
+func (s *Shelf) Copy(ctx context.Context, storage media.Storage, source Document, id uuid.UUID, disk, path string) (Document, error) {
+	if err := s.checkCreated(); err != nil {
+		return Document{}, err
+	}
+
+	if _, err := s.Document(id); err == nil {
+		return Document{}, fmt.Errorf("copy to Document %q: Document already exists", id)
+	}
+
+	if disk == "" {
+		disk = source.Disk
+	}
+
+	if path == "" {
+		path = source.Path
+		if disk == source.Disk {
+			path = copyPath(path, id)
+		}
+	}
+
+	if disk == source.Disk && path == source.Path {
+		return Document{}, ErrDuplicatePath
+	}
+
+	for _, other := range s.Documents {
+		if other.Disk == disk && other.Path == path {
+			return Document{}, ErrDuplicatePath
+		}
+	}
+
+	upload := UploadInfo{Name: source.Name, Disk: disk, Path: path}
+	for _, fn := range s.hooks.beforeUpload {
+		if err := fn(ctx, s, upload); err != nil {
+			return Document{}, err
+		}
+	}
+
+	r, err := source.Reader(ctx, storage)
+	if err != nil {
+		return Document{}, fmt.Errorf("open %q: %w", source.Path, err)
+	}
+	defer r.Close()
+
+	doc, err := s.addWithID(ctx, storage, r, "", source.Name, disk, path, id)
+	if err != nil {
+		return doc, err
+	}
+
+	doc.Tags = append(doc.Tags, source.Tags...)
+	if len(source.Metadata) > 0 {
+		doc.Metadata = make(map[string]string, len(source.Metadata))
+		for key, value := range source.Metadata {
+			doc.Metadata[key] = value
+		}
+	}
+
+	aggregate.NextEvent(s, DocumentAdded, DocumentAddedData{Document: doc})
+
+	if doc, err = s.Document(doc.ID); err != nil {
+		return doc, err
+	}
+
+	for _, fn := range s.hooks.afterUpload {
+		fn(ctx, s, doc)
+	}
+
+	return doc, nil
+}
+
+// copyPath returns path with the given UUID appended to the file name:
+//
+//	copyPath("/reports/2023.pdf", id) // "/reports/2023-<id>.pdf"
+func copyPath(path string, id uuid.UUID) string {
+	ext := stdpath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + id.String() + ext
+}
@@ -0,0 +1,144 @@
+package document_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/command/cmdbus"
+	"github.com/modernice/goes/command/cmdbus/dispatch"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/commands"
+	"github.com/modernice/nice-cms/internal/discard"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+)
+
+func TestShelf_Copy(t *testing.T) {
+	ctx := context.Background()
+	disk := media.MemoryDisk()
+	archive := media.MemoryDisk()
+	storage := media.NewStorage(
+		media.ConfigureDisk(exampleDisk, disk),
+		media.ConfigureDisk("archive", archive),
+	)
+
+	source := document.NewShelf(uuid.New())
+	source.Create(exampleShelfName)
+
+	doc, err := source.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+	if doc, err = source.Tag(doc.ID, "foo", "bar"); err != nil {
+		t.Fatalf("Tag failed with %q", err)
+	}
+	if doc, err = source.SetMetadata(doc.ID, map[string]string{"copyright": "ACME"}); err != nil {
+		t.Fatalf("SetMetadata failed with %q", err)
+	}
+
+	target := document.NewShelf(uuid.New())
+	target.Create("target")
+
+	id := uuid.New()
+	copied, err := target.Copy(ctx, storage, doc, id, "", "")
+	if err != nil {
+		t.Fatalf("Copy failed with %q", err)
+	}
+
+	if copied.ID != id {
+		t.Fatalf("copy should have UUID %q; has %q", id, copied.ID)
+	}
+
+	wantPath := "/example/example-" + id.String() + ".pdf"
+	if copied.Disk != exampleDisk || copied.Path != wantPath {
+		t.Fatalf("copy should be stored at %q on %q; is stored at %q on %q", wantPath, exampleDisk, copied.Path, copied.Disk)
+	}
+
+	if copied.Name != doc.Name || copied.UniqueName != "" {
+		t.Fatalf("copy should have the name %q and no unique name; has %q and %q", doc.Name, copied.Name, copied.UniqueName)
+	}
+
+	if !copied.HasTag("foo", "bar") || copied.Metadata["copyright"] != "ACME" {
+		t.Fatalf("copy should keep the tags and metadata; has %v and %v", copied.Tags, copied.Metadata)
+	}
+
+	if copied.Checksum != doc.Checksum {
+		t.Fatalf("copy should have the checksum %q; has %q", doc.Checksum, copied.Checksum)
+	}
+
+	if b, err := disk.Get(ctx, wantPath); err != nil || !bytes.Equal(b, examplePDF) {
+		t.Fatalf("file should have been copied to %q; Get returned %v", wantPath, err)
+	}
+
+	if _, err := disk.Get(ctx, examplePath); err != nil {
+		t.Fatalf("source file should be kept; Get returned %v", err)
+	}
+
+	if archived, err := target.Copy(ctx, storage, doc, uuid.New(), "archive", ""); err != nil {
+		t.Fatalf("Copy failed with %q", err)
+	} else if archived.Disk != "archive" || archived.Path != examplePath {
+		t.Fatalf("copy should be stored at %q on %q; is stored at %q on %q", examplePath, "archive", archived.Path, archived.Disk)
+	}
+
+	if _, err := archive.Get(ctx, examplePath); err != nil {
+		t.Fatalf("file should have been copied to the archive disk; Get returned %v", err)
+	}
+
+	for _, path := range []string{examplePath, wantPath} {
+		if _, err := target.Copy(ctx, storage, doc, uuid.New(), exampleDisk, path); !errors.Is(err, document.ErrDuplicatePath) {
+			t.Fatalf("Copy to %q should fail with %q; got %q", path, document.ErrDuplicatePath, err)
+		}
+	}
+}
+
+func TestCopy(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	disk := media.MemoryDisk()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, disk))
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	cbus := cmdbus.New(commands.NewRegistry(), ebus)
+	shelfs := document.GoesRepository(repository.New(estore))
+
+	source, doc := savedShelfWithDocument(t, ctx, shelfs, storage)
+
+	target := document.NewShelf(uuid.New())
+	target.Create("target")
+	if err := shelfs.Save(ctx, target); err != nil {
+		t.Fatalf("save Shelf: %v", err)
+	}
+
+	errs := document.HandleCommands(ctx, cbus, shelfs, storage)
+	go discard.Errors(errs)
+
+	id := uuid.New()
+	if err := cbus.Dispatch(ctx, document.Copy(source.ID, doc.ID, target.ID, id, "", "/copies/example.pdf").Any(), dispatch.Sync()); err != nil {
+		t.Fatalf("dispatch command: %v", err)
+	}
+
+	target, err := shelfs.Fetch(ctx, target.ID)
+	if err != nil {
+		t.Fatalf("fetch Shelf: %v", err)
+	}
+
+	copied, err := target.Document(id)
+	if err != nil {
+		t.Fatalf("target Shelf should contain the copy; Document failed with %q", err)
+	}
+
+	if copied.Disk != exampleDisk || copied.Path != "/copies/example.pdf" {
+		t.Fatalf("copy should be stored at %q; is stored at %q on %q", "/copies/example.pdf", copied.Path, copied.Disk)
+	}
+
+	if _, err := disk.Get(ctx, "/copies/example.pdf"); err != nil {
+		t.Fatalf("file should have been copied; Get returned %v", err)
+	}
+}
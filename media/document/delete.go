@@ -0,0 +1,79 @@
+package document
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/media"
+)
+
+// Destroy deletes the files of all Documents of the Shelf from storage,
+// including the Documents in the trash, their variants and previews, and then
+// marks the Shelf as deleted. Files are deleted concurrently. Files that could
+// not be deleted are returned and reported in the ShelfDeletedData of the
+// ShelfDeleted event. A destroyed Shelf has no name. Use the DeleteShelf
+// command to also delete the Shelf from the event store.
+//
+// If the Shelf or one of its Documents is under legal hold, ErrLegalHold is
+// returned. If ctx is canceled before all files were deleted, the Shelf is not
+// marked as deleted and the error of ctx is returned.
+func (s *Shelf) Destroy(ctx context.Context, storage media.Storage) ([]media.DeleteFailure, error) {
+	if err := s.checkCreated(); err != nil {
+		return nil, err
+	}
+	if s.LegalHold != nil {
+		return nil, ErrLegalHold
+	}
+
+	docs := make([]Document, 0, len(s.Documents)+len(s.Trashed))
+	docs = append(docs, s.Documents...)
+	for _, trashed := range s.Trashed {
+		docs = append(docs, trashed.Document)
+	}
+
+	ids := make([]uuid.UUID, 0, len(docs))
+	var files []media.File
+	for _, doc := range docs {
+		if err := s.checkHold(doc); err != nil {
+			return nil, err
+		}
+		ids = append(ids, doc.ID)
+		files = append(files, documentFiles(doc)...)
+	}
+
+	failures := media.DeleteFiles(ctx, storage, files...)
+	if err := ctx.Err(); err != nil {
+		return failures, err
+	}
+
+	aggregate.NextEvent(s, ShelfDeleted, ShelfDeletedData{
+		Name:      s.Name,
+		Documents: ids,
+		Failures:  failures,
+	})
+
+	return failures, nil
+}
+
+func (s *Shelf) delete(event.Event) {
+	s.Name = ""
+	s.Documents = make([]Document, 0)
+	s.Redirects = nil
+	s.Trashed = nil
+	s.LegalHold = nil
+}
+
+// documentFiles returns the stored files of doc, including its variants and
+// its rendered preview.
+func documentFiles(doc Document) []media.File {
+	files := []media.File{doc.File}
+	for _, locale := range doc.Locales() {
+		files = append(files, doc.Variants[locale].File)
+	}
+	if doc.Preview != nil && doc.Preview.Status == PreviewStatusRendered {
+		files = append(files, doc.Preview.Image.File)
+	}
+	return files
+}
@@ -0,0 +1,147 @@
+package document_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/command/cmdbus"
+	"github.com/modernice/goes/command/cmdbus/dispatch"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/internal/commands"
+	"github.com/modernice/nice-cms/internal/discard"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+)
+
+func TestShelf_Destroy(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(
+		media.ConfigureDisk(exampleDisk, media.MemoryDisk()),
+		media.ConfigureDisk("other-disk", media.MemoryDisk()),
+	)
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	trashed, err := shelf.Add(ctx, storage, newPDF2(), "", "Trashed", "other-disk", "/trashed.pdf")
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+	if _, err := shelf.Trash(trashed.ID); err != nil {
+		t.Fatalf("trash Document: %v", err)
+	}
+
+	// "other-disk" is not configured, so the trashed Document cannot be deleted.
+	failures, err := shelf.Destroy(ctx, media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk())))
+	if err != nil {
+		t.Fatalf("Destroy failed with %q", err)
+	}
+
+	if len(failures) != 1 || failures[0].Disk != "other-disk" || failures[0].Path != "/trashed.pdf" {
+		t.Fatalf("Destroy should report the file of the trashed Document; got %v", failures)
+	}
+
+	if shelf.Name != "" {
+		t.Fatalf("Shelf should have no name after Destroy; has %q", shelf.Name)
+	}
+	if len(shelf.Documents) != 0 || len(shelf.Trashed) != 0 {
+		t.Fatalf("Shelf should have no Documents after Destroy; has %v and %v in the trash", shelf.Documents, shelf.Trashed)
+	}
+
+	test.Change(t, shelf, document.ShelfDeleted, test.EventData(document.ShelfDeletedData{
+		Name:      exampleShelfName,
+		Documents: []uuid.UUID{doc.ID, trashed.ID},
+		Failures:  failures,
+	}))
+}
+
+func TestShelf_Destroy_legalHold(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), "", exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	if err := shelf.SetLegalHold(doc.ID, "case-1"); err != nil {
+		t.Fatalf("set legal hold: %v", err)
+	}
+
+	if _, err := shelf.Destroy(ctx, storage); !errors.Is(err, document.ErrLegalHold) {
+		t.Fatalf("Destroy should fail with %q; got %q", document.ErrLegalHold, err)
+	}
+
+	disk, _ := storage.Disk(exampleDisk)
+	if _, err := disk.Get(ctx, examplePath); err != nil {
+		t.Fatalf("file of a held Document should not be deleted; got %q", err)
+	}
+}
+
+func TestShelf_Destroy_notCreated(t *testing.T) {
+	shelf := document.NewShelf(uuid.New())
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	if _, err := shelf.Destroy(context.Background(), storage); !errors.Is(err, document.ErrShelfNotCreated) {
+		t.Fatalf("Destroy should fail with %q; got %q", document.ErrShelfNotCreated, err)
+	}
+}
+
+func TestDeleteShelf(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	disk := media.MemoryDisk()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, disk))
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	cbus := cmdbus.New(commands.NewRegistry(), ebus)
+	shelfs := document.GoesRepository(repository.New(estore))
+
+	errs := document.HandleCommands(ctx, cbus, shelfs, storage)
+	go discard.Errors(errs)
+
+	lookup := document.NewLookup()
+	lookupErrs, err := lookup.Project(ctx, ebus, estore)
+	if err != nil {
+		t.Fatalf("project lookup: %v", err)
+	}
+	go discard.Errors(lookupErrs)
+
+	shelf, doc := savedShelfWithDocument(t, ctx, shelfs, storage)
+
+	if err := cbus.Dispatch(ctx, document.DeleteShelf(shelf.ID).Any(), dispatch.Sync()); err != nil {
+		t.Fatalf("dispatch command: %v", err)
+	}
+
+	if shelf, err := shelfs.Fetch(ctx, shelf.ID); err != nil {
+		t.Fatalf("fetch Shelf: %v", err)
+	} else if shelf.AggregateVersion() != 0 {
+		t.Fatalf("Shelf should have been deleted; has version %d", shelf.AggregateVersion())
+	}
+
+	if _, err := disk.Get(ctx, doc.Path); !errors.Is(err, media.ErrFileNotFound) {
+		t.Fatalf("file of the Document should have been deleted; Get returned %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	if id, ok := lookup.ShelfName(exampleShelfName); ok {
+		t.Fatalf("name of the deleted Shelf should be free; is used by %q", id)
+	}
+}
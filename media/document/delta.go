@@ -0,0 +1,47 @@
+package document
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/delta"
+)
+
+// ReplaceDelta replaces the Document with the given UUID with the file that is
+// reconstructed from the stored file of the Document and d. If d was not
+// computed against the stored file, delta.ErrBaseMismatch is returned and the
+// client should upload the full file using Replace instead. If the Document or
+// the Shelf is under legal hold, ErrLegalHold is returned.
+func (s *Shelf) ReplaceDelta(ctx context.Context, storage media.Storage, d delta.Delta, id uuid.UUID) (Document, error) {
+	doc, err := s.Document(id)
+	if err != nil {
+		return doc, err
+	}
+
+	if err := s.checkHold(doc); err != nil {
+		return doc, err
+	}
+
+	if doc.Checksum == "" || doc.Checksum != d.BaseChecksum {
+		return doc, delta.ErrBaseMismatch
+	}
+
+	base, err := doc.Open(ctx, storage)
+	if err != nil {
+		return doc, fmt.Errorf("open document: %w", err)
+	}
+	defer base.Close()
+
+	// The file is reconstructed in memory because the Document may be stored
+	// at the same path as the base file, and the reconstructed file must be
+	// verified before the base file is overwritten.
+	var buf bytes.Buffer
+	if err := delta.Patch(base, d, &buf); err != nil {
+		return doc, fmt.Errorf("patch document: %w", err)
+	}
+
+	return s.Replace(ctx, storage, &buf, id)
+}
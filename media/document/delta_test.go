@@ -0,0 +1,81 @@
+package document_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/delta"
+	"github.com/modernice/nice-cms/media/document"
+)
+
+func TestShelf_ReplaceDelta(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	target := append(append([]byte{}, examplePDF...), []byte("%% appended")...)
+
+	d, err := delta.Diff(bytes.NewReader(examplePDF), target)
+	if err != nil {
+		t.Fatalf("Diff failed with %q", err)
+	}
+
+	replaced, err := shelf.ReplaceDelta(ctx, storage, d, doc.ID)
+	if err != nil {
+		t.Fatalf("ReplaceDelta failed with %q", err)
+	}
+
+	if replaced.Filesize != len(target) {
+		t.Fatalf("Filesize should be %d; is %d", len(target), replaced.Filesize)
+	}
+
+	if replaced.Checksum != d.Checksum {
+		t.Fatalf("Checksum should be %q; is %q", d.Checksum, replaced.Checksum)
+	}
+
+	disk, _ := storage.Disk(exampleDisk)
+	content, err := disk.Get(ctx, replaced.Path)
+	if err != nil {
+		t.Fatalf("get file from storage: %v", err)
+	}
+
+	if !bytes.Equal(content, target) {
+		t.Fatalf("stored file should equal the target file")
+	}
+
+	test.Change(t, shelf, document.DocumentReplaced, test.EventData(document.DocumentReplacedData{Document: replaced}))
+}
+
+func TestShelf_ReplaceDelta_baseMismatch(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	d, err := delta.Diff(bytes.NewReader(examplePDF2), examplePDF2[:len(examplePDF2)/2])
+	if err != nil {
+		t.Fatalf("Diff failed with %q", err)
+	}
+
+	if _, err := shelf.ReplaceDelta(ctx, storage, d, doc.ID); !errors.Is(err, delta.ErrBaseMismatch) {
+		t.Fatalf("ReplaceDelta should fail with %q; got %q", delta.ErrBaseMismatch, err)
+	}
+
+	test.NoChange(t, shelf, document.DocumentReplaced)
+}
@@ -3,26 +3,88 @@ package document
 import (
 	"github.com/google/uuid"
 	"github.com/modernice/goes/codec"
+	"github.com/modernice/nice-cms/media"
 )
 
 // Shelf events
 const (
 	ShelfCreated          = "cms.media.document.shelf.created"
+	ShelfDeleted          = "cms.media.document.shelf.deleted"
+	ShelfRenamed          = "cms.media.document.shelf.renamed"
 	DocumentAdded         = "cms.media.document.shelf.document_added"
 	DocumentRemoved       = "cms.media.document.shelf.document_removed"
 	DocumentReplaced      = "cms.media.document.shelf.document_replaced"
 	DocumentRenamed       = "cms.media.document.shelf.document_renamed"
+	DocumentMoved         = "cms.media.document.shelf.document_moved"
 	DocumentMadeUnique    = "cms.media.document.shelf.document_made_unique"
 	DocumentMadeNonUnique = "cms.media.document.shelf.document_made_non_unique"
 	DocumentTagged        = "cms.media.document.shelf.document_tagged"
 	DocumentUntagged      = "cms.media.document.shelf.document_untagged"
+	VariantAdded          = "cms.media.document.shelf.variant_added"
+	VariantRemoved        = "cms.media.document.shelf.variant_removed"
+	PreviewStarted        = "cms.media.document.shelf.preview_started"
+	PreviewRendered       = "cms.media.document.shelf.preview_rendered"
+	PreviewFailed         = "cms.media.document.shelf.preview_failed"
+	AudioExtracted        = "cms.media.document.shelf.audio_extracted"
+	DocumentTrashed       = "cms.media.document.shelf.document_trashed"
+	DocumentRestored      = "cms.media.document.shelf.document_restored"
+	DocumentPurged        = "cms.media.document.shelf.document_purged"
+	LegalHoldSet          = "cms.media.document.shelf.legal_hold_set"
+	LegalHoldLifted       = "cms.media.document.shelf.legal_hold_lifted"
+
+	DocumentMetadataSet     = "cms.media.document.shelf.document_metadata_set"
+	DocumentMetadataDeleted = "cms.media.document.shelf.document_metadata_deleted"
 )
 
+// Events are all shelf events.
+var Events = [...]string{
+	ShelfCreated,
+	ShelfDeleted,
+	ShelfRenamed,
+	DocumentAdded,
+	DocumentRemoved,
+	DocumentReplaced,
+	DocumentRenamed,
+	DocumentMoved,
+	DocumentMadeUnique,
+	DocumentMadeNonUnique,
+	DocumentTagged,
+	DocumentUntagged,
+	DocumentMetadataSet,
+	DocumentMetadataDeleted,
+	VariantAdded,
+	VariantRemoved,
+	PreviewStarted,
+	PreviewRendered,
+	PreviewFailed,
+	AudioExtracted,
+	DocumentTrashed,
+	DocumentRestored,
+	DocumentPurged,
+	LegalHoldSet,
+	LegalHoldLifted,
+}
+
 // ShelfCreatedData is the event data for the ShelfCreated event.
 type ShelfCreatedData struct {
 	Name string
 }
 
+// ShelfDeletedData is the event data for the ShelfDeleted event. Documents are
+// the UUIDs of the Documents that were deleted, including the trashed
+// Documents. Failures are the files that could not be deleted from storage.
+type ShelfDeletedData struct {
+	Name      string
+	Documents []uuid.UUID
+	Failures  []media.DeleteFailure
+}
+
+// ShelfRenamedData is the event data for the ShelfRenamed event.
+type ShelfRenamedData struct {
+	OldName string
+	Name    string
+}
+
 // DocumentAddedData is the event data for the DocumentAdded event.
 type DocumentAddedData struct {
 	Document Document
@@ -46,6 +108,24 @@ type DocumentRenamedData struct {
 	Name       string
 }
 
+// DocumentMovedData is the event data for the DocumentMoved event.
+type DocumentMovedData struct {
+	DocumentID uuid.UUID
+	Disk       string
+	OldPath    string
+	Path       string
+
+	// OldDisk is the previous disk of the Document, or empty if the Document
+	// was moved within its disk.
+	OldDisk string
+
+	// StoragePath is the storage path of the Document on the new disk. It is
+	// only set if the Document was moved to another disk.
+	StoragePath string
+
+	DeleteError string
+}
+
 // DocumentMadeUniqueData is the event data for the DocumentMadeUnique event.
 type DocumentMadeUniqueData struct {
 	DocumentID uuid.UUID
@@ -70,15 +150,111 @@ type DocumentUntaggedData struct {
 	Tags       []string
 }
 
+// DocumentMetadataSetData is the event data for the DocumentMetadataSet event.
+type DocumentMetadataSetData struct {
+	DocumentID uuid.UUID
+	Fields     map[string]string
+}
+
+// DocumentMetadataDeletedData is the event data for the
+// DocumentMetadataDeleted event.
+type DocumentMetadataDeletedData struct {
+	DocumentID uuid.UUID
+	Keys       []string
+}
+
+// VariantAddedData is the event data for the VariantAdded event.
+type VariantAddedData struct {
+	DocumentID uuid.UUID
+	Locale     string
+	Variant    media.Document
+}
+
+// VariantRemovedData is the event data for the VariantRemoved event.
+type VariantRemovedData struct {
+	DocumentID  uuid.UUID
+	Locale      string
+	Variant     media.Document
+	DeleteError string
+}
+
+// PreviewStartedData is the event data for the PreviewStarted event.
+type PreviewStartedData struct {
+	DocumentID uuid.UUID
+}
+
+// PreviewRenderedData is the event data for the PreviewRendered event.
+type PreviewRenderedData struct {
+	DocumentID uuid.UUID
+	Image      media.Image
+}
+
+// PreviewFailedData is the event data for the PreviewFailed event.
+type PreviewFailedData struct {
+	DocumentID uuid.UUID
+	Error      string
+}
+
+// AudioExtractedData is the event data for the AudioExtracted event.
+type AudioExtractedData struct {
+	DocumentID uuid.UUID
+	Metadata   media.AudioMetadata
+}
+
+// DocumentTrashedData is the event data for the DocumentTrashed event.
+type DocumentTrashedData struct {
+	Document Document
+}
+
+// DocumentRestoredData is the event data for the DocumentRestored event.
+type DocumentRestoredData struct {
+	Document Document
+}
+
+// DocumentPurgedData is the event data for the DocumentPurged event.
+type DocumentPurgedData struct {
+	Document    Document
+	DeleteError string
+}
+
+// LegalHoldSetData is the event data for the LegalHoldSet event. DocumentID
+// is uuid.Nil if the legal hold was set on the whole Shelf.
+type LegalHoldSetData struct {
+	DocumentID uuid.UUID
+	Reason     string
+}
+
+// LegalHoldLiftedData is the event data for the LegalHoldLifted event.
+// DocumentID is uuid.Nil if the legal hold of the whole Shelf was lifted.
+type LegalHoldLiftedData struct {
+	DocumentID uuid.UUID
+}
+
 // RegisterEvents registers Shelf events into an event registry.
 func RegisterEvents(r codec.Registerer) {
 	codec.Register[ShelfCreatedData](r, ShelfCreated)
+	codec.Register[ShelfDeletedData](r, ShelfDeleted)
+	codec.Register[ShelfRenamedData](r, ShelfRenamed)
 	codec.Register[DocumentAddedData](r, DocumentAdded)
 	codec.Register[DocumentReplacedData](r, DocumentReplaced)
 	codec.Register[DocumentRemovedData](r, DocumentRemoved)
 	codec.Register[DocumentRenamedData](r, DocumentRenamed)
+	codec.Register[DocumentMovedData](r, DocumentMoved)
 	codec.Register[DocumentMadeUniqueData](r, DocumentMadeUnique)
 	codec.Register[DocumentMadeNonUniqueData](r, DocumentMadeNonUnique)
 	codec.Register[DocumentTaggedData](r, DocumentTagged)
 	codec.Register[DocumentUntaggedData](r, DocumentUntagged)
+	codec.Register[DocumentMetadataSetData](r, DocumentMetadataSet)
+	codec.Register[DocumentMetadataDeletedData](r, DocumentMetadataDeleted)
+	codec.Register[VariantAddedData](r, VariantAdded)
+	codec.Register[VariantRemovedData](r, VariantRemoved)
+	codec.Register[PreviewStartedData](r, PreviewStarted)
+	codec.Register[PreviewRenderedData](r, PreviewRendered)
+	codec.Register[PreviewFailedData](r, PreviewFailed)
+	codec.Register[AudioExtractedData](r, AudioExtracted)
+	codec.Register[DocumentTrashedData](r, DocumentTrashed)
+	codec.Register[DocumentRestoredData](r, DocumentRestored)
+	codec.Register[DocumentPurgedData](r, DocumentPurged)
+	codec.Register[LegalHoldSetData](r, LegalHoldSet)
+	codec.Register[LegalHoldLiftedData](r, LegalHoldLifted)
 }
@@ -0,0 +1,130 @@
+package document
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/media"
+)
+
+// ErrLegalHold is returned when a Document that is under legal hold, or that
+// belongs to a Shelf under legal hold, would be deleted. While a legal hold is
+// set, Remove, Replace, RemoveVariant, Trash and Purge fail with ErrLegalHold,
+// and the Janitor skips the held Documents.
+var ErrLegalHold = errors.New("document under legal hold")
+
+// SetLegalHold sets a legal hold on the Document with the given UUID, which
+// may also be in the trash of the Shelf. If id is uuid.Nil, the legal hold is
+// set on the whole Shelf. If the Document is already held for the same reason,
+// SetLegalHold does nothing.
+func (s *Shelf) SetLegalHold(id uuid.UUID, reason string) error {
+	if err := s.checkCreated(); err != nil {
+		return err
+	}
+
+	current, err := s.legalHold(id)
+	if err != nil {
+		return err
+	}
+
+	if current != nil && current.Reason == reason {
+		return nil
+	}
+
+	aggregate.NextEvent(s, LegalHoldSet, LegalHoldSetData{
+		DocumentID: id,
+		Reason:     reason,
+	})
+
+	return nil
+}
+
+func (s *Shelf) setLegalHold(evt event.Event) {
+	data := evt.Data().(LegalHoldSetData)
+	s.updateLegalHold(data.DocumentID, &media.LegalHold{
+		Reason: data.Reason,
+		Since:  evt.Time(),
+	})
+}
+
+// LiftLegalHold lifts the legal hold of the Document with the given UUID. If
+// id is uuid.Nil, the legal hold of the whole Shelf is lifted. Legal holds of
+// single Documents are not lifted by lifting the legal hold of the Shelf. If
+// the Document is not held, LiftLegalHold does nothing.
+func (s *Shelf) LiftLegalHold(id uuid.UUID) error {
+	if err := s.checkCreated(); err != nil {
+		return err
+	}
+
+	current, err := s.legalHold(id)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		return nil
+	}
+
+	aggregate.NextEvent(s, LegalHoldLifted, LegalHoldLiftedData{DocumentID: id})
+
+	return nil
+}
+
+func (s *Shelf) liftLegalHold(evt event.Event) {
+	data := evt.Data().(LegalHoldLiftedData)
+	s.updateLegalHold(data.DocumentID, nil)
+}
+
+// Held returns whether the Document with the given UUID is under legal hold,
+// either by itself or through the Shelf.
+func (s *Shelf) Held(id uuid.UUID) bool {
+	if s.LegalHold != nil {
+		return true
+	}
+	hold, _ := s.legalHold(id)
+	return hold != nil
+}
+
+// checkHold returns ErrLegalHold if doc or the Shelf is under legal hold.
+func (s *Shelf) checkHold(doc Document) error {
+	if s.LegalHold != nil || doc.LegalHold != nil {
+		return ErrLegalHold
+	}
+	return nil
+}
+
+// legalHold returns the legal hold of the Document with the given UUID, or of
+// the Shelf if id is uuid.Nil.
+func (s *Shelf) legalHold(id uuid.UUID) (*media.LegalHold, error) {
+	if id == uuid.Nil {
+		return s.LegalHold, nil
+	}
+	if doc, err := s.Document(id); err == nil {
+		return doc.LegalHold, nil
+	}
+	if doc, err := s.TrashedDocument(id); err == nil {
+		return doc.LegalHold, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (s *Shelf) updateLegalHold(id uuid.UUID, hold *media.LegalHold) {
+	if id == uuid.Nil {
+		s.LegalHold = hold
+		return
+	}
+	for i, doc := range s.Documents {
+		if doc.ID == id {
+			s.Documents[i].LegalHold = hold
+			return
+		}
+	}
+	for i, doc := range s.Trashed {
+		if doc.ID == id {
+			s.Trashed[i].LegalHold = hold
+			return
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package document_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+)
+
+func TestShelf_SetLegalHold(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	if err := shelf.SetLegalHold(doc.ID, "case-1"); err != nil {
+		t.Fatalf("SetLegalHold failed with %q", err)
+	}
+
+	held, _ := shelf.Document(doc.ID)
+	if held.LegalHold == nil || held.LegalHold.Reason != "case-1" || held.LegalHold.Since.IsZero() {
+		t.Fatalf("Document should be under legal hold; LegalHold is %v", held.LegalHold)
+	}
+
+	if !shelf.Held(doc.ID) {
+		t.Fatalf("Held should return true for a held Document")
+	}
+
+	test.Change(t, shelf, document.LegalHoldSet, test.EventData(document.LegalHoldSetData{
+		DocumentID: doc.ID,
+		Reason:     "case-1",
+	}))
+
+	if err := shelf.Remove(ctx, storage, doc.ID); !errors.Is(err, document.ErrLegalHold) {
+		t.Fatalf("Remove should fail with %q; got %q", document.ErrLegalHold, err)
+	}
+
+	if _, err := shelf.Trash(doc.ID); !errors.Is(err, document.ErrLegalHold) {
+		t.Fatalf("Trash should fail with %q; got %q", document.ErrLegalHold, err)
+	}
+
+	if _, err := shelf.Replace(ctx, storage, bytes.NewReader(examplePDF2), doc.ID); !errors.Is(err, document.ErrLegalHold) {
+		t.Fatalf("Replace should fail with %q; got %q", document.ErrLegalHold, err)
+	}
+
+	if _, err := shelf.Tag(doc.ID, "foo"); err != nil {
+		t.Fatalf("Tag failed with %q", err)
+	}
+
+	if tagged, _ := shelf.Document(doc.ID); tagged.LegalHold == nil {
+		t.Fatalf("legal hold should be kept when the Document is updated")
+	}
+}
+
+func TestShelf_SetLegalHold_shelf(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	if err := shelf.SetLegalHold(uuid.Nil, "case-1"); err != nil {
+		t.Fatalf("SetLegalHold failed with %q", err)
+	}
+
+	if shelf.LegalHold == nil {
+		t.Fatalf("Shelf should be under legal hold")
+	}
+
+	if _, err := shelf.Trash(doc.ID); !errors.Is(err, document.ErrLegalHold) {
+		t.Fatalf("Trash should fail with %q; got %q", document.ErrLegalHold, err)
+	}
+
+	if err := shelf.LiftLegalHold(uuid.Nil); err != nil {
+		t.Fatalf("LiftLegalHold failed with %q", err)
+	}
+
+	if shelf.LegalHold != nil {
+		t.Fatalf("legal hold of the Shelf should be lifted")
+	}
+
+	test.Change(t, shelf, document.LegalHoldLifted, test.EventData(document.LegalHoldLiftedData{DocumentID: uuid.Nil}))
+
+	if _, err := shelf.Trash(doc.ID); err != nil {
+		t.Fatalf("Trash failed with %q", err)
+	}
+}
+
+func TestShelf_SetLegalHold_trashed(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	if _, err := shelf.Trash(doc.ID); err != nil {
+		t.Fatalf("Trash failed with %q", err)
+	}
+
+	if err := shelf.SetLegalHold(doc.ID, ""); err != nil {
+		t.Fatalf("SetLegalHold failed with %q", err)
+	}
+
+	if err := shelf.Purge(ctx, storage, doc.ID); !errors.Is(err, document.ErrLegalHold) {
+		t.Fatalf("Purge should fail with %q; got %q", document.ErrLegalHold, err)
+	}
+
+	disk, _ := storage.Disk(exampleDisk)
+	if _, err := disk.Get(ctx, doc.Path); err != nil {
+		t.Fatalf("file of a held Document should not be deleted; Get returned %q", err)
+	}
+
+	restored, err := shelf.Restore(doc.ID)
+	if err != nil {
+		t.Fatalf("Restore failed with %q", err)
+	}
+
+	if restored.LegalHold == nil {
+		t.Fatalf("legal hold should be kept when the Document is restored")
+	}
+}
+
+func TestShelf_LiftLegalHold(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	if err := shelf.LiftLegalHold(doc.ID); err != nil {
+		t.Fatalf("LiftLegalHold failed with %q", err)
+	}
+
+	test.NoChange(t, shelf, document.LegalHoldLifted)
+
+	if err := shelf.SetLegalHold(doc.ID, "case-1"); err != nil {
+		t.Fatalf("SetLegalHold failed with %q", err)
+	}
+
+	if err := shelf.LiftLegalHold(doc.ID); err != nil {
+		t.Fatalf("LiftLegalHold failed with %q", err)
+	}
+
+	if shelf.Held(doc.ID) {
+		t.Fatalf("legal hold of the Document should be lifted")
+	}
+
+	test.Change(t, shelf, document.LegalHoldLifted, test.EventData(document.LegalHoldLiftedData{DocumentID: doc.ID}))
+
+	if err := shelf.Remove(ctx, storage, doc.ID); err != nil {
+		t.Fatalf("Remove failed with %q", err)
+	}
+
+	if err := shelf.SetLegalHold(uuid.New(), ""); !errors.Is(err, document.ErrNotFound) {
+		t.Fatalf("SetLegalHold should fail with %q for an unknown Document; got %q", document.ErrNotFound, err)
+	}
+}
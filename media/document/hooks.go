@@ -0,0 +1,73 @@
+package document
+
+import "context"
+
+// UploadInfo describes a document that is about to be added to a Shelf.
+type UploadInfo struct {
+	UniqueName string
+	Name       string
+	Disk       string
+	Path       string
+}
+
+// HookOption registers a lifecycle hook on a Shelf. Hooks are typically
+// configured where the Repository is constructed:
+//
+//	shelfs := document.GoesRepository(repo, document.AfterUpload(
+//		func(ctx context.Context, s *document.Shelf, doc document.Document) {
+//			billing.CountUpload(s.ID, doc.Filesize)
+//		},
+//	))
+type HookOption func(*hooks)
+
+type hooks struct {
+	beforeUpload []func(context.Context, *Shelf, UploadInfo) error
+	afterUpload  []func(context.Context, *Shelf, Document)
+	beforeDelete []func(context.Context, *Shelf, Document) error
+	afterDelete  []func(context.Context, *Shelf, Document)
+}
+
+// BeforeUpload returns a HookOption that registers fn as a hook that is called
+// before a document is uploaded to storage. If fn returns an error, the upload
+// is aborted and Add returns the error.
+func BeforeUpload(fn func(context.Context, *Shelf, UploadInfo) error) HookOption {
+	return func(h *hooks) {
+		h.beforeUpload = append(h.beforeUpload, fn)
+	}
+}
+
+// AfterUpload returns a HookOption that registers fn as a hook that is called
+// after a document has been uploaded and added to the Shelf. The hook is called
+// before the Shelf is saved.
+func AfterUpload(fn func(context.Context, *Shelf, Document)) HookOption {
+	return func(h *hooks) {
+		h.afterUpload = append(h.afterUpload, fn)
+	}
+}
+
+// BeforeDelete returns a HookOption that registers fn as a hook that is called
+// before a document is removed. If fn returns an error, the document is not
+// removed and Remove returns the error.
+func BeforeDelete(fn func(context.Context, *Shelf, Document) error) HookOption {
+	return func(h *hooks) {
+		h.beforeDelete = append(h.beforeDelete, fn)
+	}
+}
+
+// AfterDelete returns a HookOption that registers fn as a hook that is called
+// after a document has been deleted from storage and removed from the Shelf.
+// The hook is called before the Shelf is saved.
+func AfterDelete(fn func(context.Context, *Shelf, Document)) HookOption {
+	return func(h *hooks) {
+		h.afterDelete = append(h.afterDelete, fn)
+	}
+}
+
+// UseHooks registers lifecycle hooks on the Shelf. Shelfs that are fetched
+// from a Repository that was constructed with HookOptions already have these
+// hooks registered.
+func (s *Shelf) UseHooks(opts ...HookOption) {
+	for _, opt := range opts {
+		opt(&s.hooks)
+	}
+}
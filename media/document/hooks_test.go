@@ -0,0 +1,139 @@
+package document_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+)
+
+func TestBeforeUpload(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	mockError := errors.New("mock error")
+	var upload document.UploadInfo
+	shelf.UseHooks(document.BeforeUpload(func(_ context.Context, s *document.Shelf, u document.UploadInfo) error {
+		upload = u
+		return mockError
+	}))
+
+	if _, err := shelf.Add(context.Background(), storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath); !errors.Is(err, mockError) {
+		t.Fatalf("Add should fail with %q; got %q", mockError, err)
+	}
+
+	want := document.UploadInfo{UniqueName: exampleUniqueName, Name: exampleName, Disk: exampleDisk, Path: examplePath}
+	if upload != want {
+		t.Fatalf("BeforeUpload hook should receive %v; got %v", want, upload)
+	}
+
+	if len(shelf.Documents) != 0 {
+		t.Fatalf("Shelf should have no Documents; has %d", len(shelf.Documents))
+	}
+
+	disk, _ := storage.Disk(exampleDisk)
+	if _, err := disk.Get(context.Background(), examplePath); err == nil {
+		t.Fatalf("Document should not have been uploaded to storage")
+	}
+}
+
+func TestAfterUpload(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	var uploaded []document.Document
+	shelf.UseHooks(document.AfterUpload(func(_ context.Context, _ *document.Shelf, doc document.Document) {
+		uploaded = append(uploaded, doc)
+	}))
+
+	doc, err := shelf.Add(context.Background(), storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	if len(uploaded) != 1 || uploaded[0].ID != doc.ID {
+		t.Fatalf("AfterUpload hook should be called with the added Document; got %v", uploaded)
+	}
+}
+
+func TestBeforeDelete_AfterDelete(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(context.Background(), storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	mockError := errors.New("mock error")
+	reject := true
+	var deleted []document.Document
+	shelf.UseHooks(
+		document.BeforeDelete(func(context.Context, *document.Shelf, document.Document) error {
+			if reject {
+				return mockError
+			}
+			return nil
+		}),
+		document.AfterDelete(func(_ context.Context, _ *document.Shelf, doc document.Document) {
+			deleted = append(deleted, doc)
+		}),
+	)
+
+	if err := shelf.Remove(context.Background(), storage, doc.ID); !errors.Is(err, mockError) {
+		t.Fatalf("Remove should fail with %q; got %q", mockError, err)
+	}
+
+	if _, err := shelf.Document(doc.ID); err != nil {
+		t.Fatalf("Document should not have been removed: %v", err)
+	}
+
+	if len(deleted) != 0 {
+		t.Fatalf("AfterDelete hook should not be called if the deletion was rejected")
+	}
+
+	reject = false
+	if err := shelf.Remove(context.Background(), storage, doc.ID); err != nil {
+		t.Fatalf("Remove failed with %q", err)
+	}
+
+	if len(deleted) != 1 || deleted[0].ID != doc.ID {
+		t.Fatalf("AfterDelete hook should be called with the removed Document; got %v", deleted)
+	}
+}
+
+func TestGoesRepository_hooks(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	var calls int
+	shelfs := document.GoesRepository(repository.New(eventstore.New()), document.AfterUpload(
+		func(context.Context, *document.Shelf, document.Document) { calls++ },
+	))
+
+	id := uuid.New()
+	if err := shelfs.Use(ctx, id, func(s *document.Shelf) error {
+		return s.Create(exampleShelfName)
+	}); err != nil {
+		t.Fatalf("create shelf: %v", err)
+	}
+
+	if err := shelfs.Use(ctx, id, func(s *document.Shelf) error {
+		_, err := s.Add(ctx, storage, newPDF(), "", exampleName, exampleDisk, examplePath)
+		return err
+	}); err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("AfterUpload hook should be called %d time(s); was called %d time(s)", 1, calls)
+	}
+}
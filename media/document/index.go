@@ -0,0 +1,95 @@
+package document
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/internal/ordered"
+	"github.com/modernice/nice-cms/projector"
+)
+
+// Index is a projection of the Documents of Shelfs that serves paginated
+// queries without fetching the Shelfs from the event store. It is thread-safe.
+type Index struct {
+	projector *projector.Projector
+
+	mux    sync.RWMutex
+	shelfs shelfProjection
+}
+
+// shelfProjection are the Shelfs of a projection, projected from their events.
+type shelfProjection map[uuid.UUID]*indexedShelf
+
+// indexedShelf is a projected Shelf and the events that were received ahead of
+// their predecessors.
+type indexedShelf struct {
+	*Shelf
+
+	events ordered.Events
+}
+
+// NewIndex returns a new Index. The provided options configure the error
+// handling of the projection.
+func NewIndex(opts ...projector.Option) *Index {
+	return &Index{
+		projector: projector.New(opts...),
+		shelfs:    make(shelfProjection),
+	}
+}
+
+// List returns the page of the Documents of the Shelf with the given UUID that
+// match q, or ErrShelfNotFound if the Index has no such Shelf.
+func (idx *Index) List(shelfID uuid.UUID, q Query) (DocumentPage, error) {
+	idx.mux.RLock()
+	defer idx.mux.RUnlock()
+
+	shelf, ok := idx.shelfs[shelfID]
+	if !ok || shelf.Name == "" {
+		return DocumentPage{}, ErrShelfNotFound
+	}
+
+	return shelf.List(q), nil
+}
+
+// Project projects the Index in a new goroutine and returns a channel of
+// asynchronous errors. Failed projection jobs are handled according to the
+// projector.Policy of the Index.
+func (idx *Index) Project(ctx context.Context, bus event.Bus, store event.Store, opts ...schedule.ContinuousOption) (<-chan error, error) {
+	schedule := schedule.Continuously(bus, store, Events[:], opts...)
+	return idx.projector.Run(ctx, schedule, idx)
+}
+
+// Ready returns a channel that is closed when the Index caught up with the
+// past events. Until then, List may miss Shelfs that already exist.
+func (idx *Index) Ready() <-chan struct{} {
+	return idx.projector.Ready()
+}
+
+// Status returns the projection status of the Index.
+func (idx *Index) Status() projector.Status {
+	return idx.projector.Status()
+}
+
+// ApplyEvent applies aggregate events.
+func (idx *Index) ApplyEvent(evt event.Event) {
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+	idx.shelfs.apply(evt)
+}
+
+func (p shelfProjection) apply(evt event.Event) {
+	id, _, _ := evt.Aggregate()
+
+	shelf, ok := p[id]
+	if !ok {
+		shelf = &indexedShelf{Shelf: NewShelf(id)}
+		p[id] = shelf
+	}
+
+	// The Shelf appliers are not idempotent, but events may be delivered more
+	// than once and out of order.
+	shelf.events.Apply(evt, shelf.ApplyEvent)
+}
@@ -1,11 +1,20 @@
 package document
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/media"
+)
 
 type JSONShelf struct {
-	ID        uuid.UUID  `json:"id"`
-	Name      string     `json:"name"`
-	Documents []Document `json:"documents"`
+	ID        uuid.UUID         `json:"id"`
+	Name      string            `json:"name"`
+	Documents []Document        `json:"documents"`
+	Trashed   []TrashedDocument `json:"trashed,omitempty"`
+	LegalHold *media.LegalHold  `json:"legalHold,omitempty"`
+
+	// Version is the aggregate version of the Shelf. It changes whenever the
+	// Shelf changes.
+	Version int `json:"version"`
 }
 
 func (s *Shelf) JSON() JSONShelf {
@@ -13,6 +22,9 @@ func (s *Shelf) JSON() JSONShelf {
 		ID:        s.ID,
 		Name:      s.Name,
 		Documents: s.Documents,
+		Trashed:   s.Trashed,
+		LegalHold: s.LegalHold,
+		Version:   s.AggregateVersion(),
 	}
 }
 
@@ -25,3 +37,29 @@ func (s JSONShelf) Document(id uuid.UUID) (Document, error) {
 	}
 	return Document{}, ErrNotFound
 }
+
+// TrashedDocument returns the Document with the given UUID from the trash of
+// the Shelf, or ErrNotTrashed.
+func (s JSONShelf) TrashedDocument(id uuid.UUID) (TrashedDocument, error) {
+	for _, doc := range s.Trashed {
+		if doc.ID == id {
+			return doc, nil
+		}
+	}
+	return TrashedDocument{}, ErrNotTrashed
+}
+
+// Held returns whether the Document with the given UUID is under legal hold,
+// either by itself or through the Shelf. The Document may also be in the trash.
+func (s JSONShelf) Held(id uuid.UUID) bool {
+	if s.LegalHold != nil {
+		return true
+	}
+	if doc, err := s.Document(id); err == nil {
+		return doc.LegalHold != nil
+	}
+	if doc, err := s.TrashedDocument(id); err == nil {
+		return doc.LegalHold != nil
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package document
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// OfficeExtensions are the file extensions of the office formats that the
+// LibreOffice Renderer supports by default.
+var OfficeExtensions = []string{
+	".doc", ".docx", ".odt", ".rtf",
+	".xls", ".xlsx", ".ods",
+	".ppt", ".pptx", ".odp",
+}
+
+// LibreOffice is a Renderer that renders previews of office documents by
+// converting the first page of a document into a PNG image using the
+// LibreOffice command-line interface.
+type LibreOffice struct {
+	// Binary is the path to the LibreOffice executable. Defaults to "soffice".
+	Binary string
+
+	// Extensions are the supported file extensions. Defaults to
+	// OfficeExtensions.
+	Extensions []string
+}
+
+// Supports returns whether ext is one of the supported file extensions.
+func (lo LibreOffice) Supports(ext string) bool {
+	exts := lo.Extensions
+	if exts == nil {
+		exts = OfficeExtensions
+	}
+	for _, e := range exts {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// Render writes the document in r to a temporary directory, converts it into a
+// PNG image using LibreOffice and writes the image to w.
+func (lo LibreOffice) Render(ctx context.Context, w io.Writer, r io.Reader, ext string) error {
+	if !lo.Supports(ext) {
+		return fmt.Errorf("%w: %q", ErrUnsupportedFormat, ext)
+	}
+
+	dir, err := os.MkdirTemp("", "nice-cms-preview-*")
+	if err != nil {
+		return fmt.Errorf("create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "document"+ext)
+	if err := writeFile(input, r); err != nil {
+		return fmt.Errorf("write document: %w", err)
+	}
+
+	binary := lo.Binary
+	if binary == "" {
+		binary = "soffice"
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, "--headless", "--convert-to", "png", "--outdir", dir, input)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("run %q: %w: %s", binary, err, stderr.String())
+	}
+
+	f, err := os.Open(filepath.Join(dir, "document.png"))
+	if err != nil {
+		return fmt.Errorf("open rendered preview: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(w, f); err != nil {
+		return fmt.Errorf("write preview: %w", err)
+	}
+
+	return nil
+}
+
+func writeFile(path string, r io.Reader) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,137 @@
+package document
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/media"
+)
+
+// SortFields are the fields that Documents can be sorted by in a Query.
+var SortFields = [...]string{"name", "uniqueName", "filesize"}
+
+// Query is a paginated query for the Documents of a Shelf.
+type Query struct {
+	// Offset is the number of matching Documents to skip.
+	Offset int
+
+	// Limit is the maximum number of Documents to return. If Limit is 0, all
+	// matching Documents after Offset are returned.
+	Limit int
+
+	// Tags filters Documents by their tags. See ForTag.
+	Tags []string
+
+	// Search filters Documents by their names. See ForSearch.
+	Search string
+
+	// Sort is a comma-separated list of SortFields. A "-" prefix sorts a
+	// field in descending order. Unknown fields are ignored. If Sort is empty,
+	// Documents are returned in the order in which they were added to the
+	// Shelf.
+	Sort string
+}
+
+func (q Query) options() []SearchOption {
+	var opts []SearchOption
+	if len(q.Tags) > 0 {
+		opts = append(opts, ForTag(q.Tags...))
+	}
+	if q.Search != "" {
+		opts = append(opts, ForSearch(q.Search))
+	}
+	return opts
+}
+
+// DocumentPage is a page of the Documents of a Shelf that match a Query.
+type DocumentPage struct {
+	ID        uuid.UUID        `json:"id"`
+	Name      string           `json:"name"`
+	Documents []Document       `json:"documents"`
+	LegalHold *media.LegalHold `json:"legalHold,omitempty"`
+
+	// Total is the number of Documents that match the Query.
+	Total int `json:"total"`
+
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// Next returns the Query for the next page, or false if there is no next page.
+func (p DocumentPage) Next(q Query) (Query, bool) {
+	if p.Offset+len(p.Documents) >= p.Total {
+		return q, false
+	}
+	q.Offset = p.Offset + len(p.Documents)
+	q.Limit = p.Limit
+	return q, true
+}
+
+// List returns the page of the Documents in s that match q, sorted by the Sort
+// fields of q.
+func (s *Shelf) List(q Query) DocumentPage {
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	if q.Limit < 0 {
+		q.Limit = 0
+	}
+
+	matches := s.Search(q.options()...)
+	sortDocuments(matches, q.Sort)
+
+	page := DocumentPage{
+		ID:        s.ID,
+		Name:      s.Name,
+		Documents: []Document{},
+		LegalHold: s.LegalHold,
+		Total:     len(matches),
+		Offset:    q.Offset,
+		Limit:     q.Limit,
+	}
+
+	if q.Offset >= len(matches) {
+		return page
+	}
+
+	matches = matches[q.Offset:]
+	if q.Limit > 0 && len(matches) > q.Limit {
+		matches = matches[:q.Limit]
+	}
+	page.Documents = append(page.Documents, matches...)
+
+	return page
+}
+
+func sortDocuments(docs []Document, fields string) {
+	if fields == "" {
+		return
+	}
+	sort.SliceStable(docs, func(i, j int) bool {
+		for _, field := range strings.Split(fields, ",") {
+			desc := strings.HasPrefix(field, "-")
+			c := compareDocuments(strings.TrimPrefix(field, "-"), docs[i], docs[j])
+			if desc {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+func compareDocuments(field string, a, b Document) int {
+	switch field {
+	case "name":
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	case "uniqueName":
+		return strings.Compare(a.UniqueName, b.UniqueName)
+	case "filesize":
+		return a.Filesize - b.Filesize
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,188 @@
+package document_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+)
+
+func TestShelf_List(t *testing.T) {
+	shelf := newListShelf(t)
+
+	tests := map[string]struct {
+		query document.Query
+		want  []string
+		total int
+	}{
+		"all": {
+			query: document.Query{},
+			want:  []string{"Report 0", "Report 1", "Invoice 2", "Report 3", "Invoice 4"},
+			total: 5,
+		},
+		"page": {
+			query: document.Query{Offset: 1, Limit: 2},
+			want:  []string{"Report 1", "Invoice 2"},
+			total: 5,
+		},
+		"last page": {
+			query: document.Query{Offset: 4, Limit: 2},
+			want:  []string{"Invoice 4"},
+			total: 5,
+		},
+		"out of range": {
+			query: document.Query{Offset: 10, Limit: 2},
+			want:  []string{},
+			total: 5,
+		},
+		"tag": {
+			query: document.Query{Tags: []string{"even"}},
+			want:  []string{"Report 0", "Invoice 2", "Invoice 4"},
+			total: 3,
+		},
+		"search": {
+			query: document.Query{Search: "invoice"},
+			want:  []string{"Invoice 2", "Invoice 4"},
+			total: 2,
+		},
+		"search unique name": {
+			query: document.Query{Search: "doc-3"},
+			want:  []string{"Report 3"},
+			total: 1,
+		},
+		"sort": {
+			query: document.Query{Sort: "-name"},
+			want:  []string{"Report 3", "Report 1", "Report 0", "Invoice 4", "Invoice 2"},
+			total: 5,
+		},
+		"sort and page": {
+			query: document.Query{Sort: "name", Offset: 1, Limit: 2},
+			want:  []string{"Invoice 4", "Report 0"},
+			total: 5,
+		},
+		"tag, search and page": {
+			query: document.Query{Tags: []string{"even"}, Search: "invoice", Offset: 1, Limit: 1},
+			want:  []string{"Invoice 4"},
+			total: 2,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			page := shelf.List(tt.query)
+			expectPage(t, page, tt.want, tt.total)
+
+			if page.ID != shelf.ID || page.Name != shelf.Name {
+				t.Fatalf("page should belong to Shelf %q (%s); belongs to %q (%s)", shelf.Name, shelf.ID, page.Name, page.ID)
+			}
+		})
+	}
+}
+
+func TestIndex_List(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	shelfs := document.GoesRepository(repository.New(estore))
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	idx := document.NewIndex()
+
+	errs, err := idx.Project(ctx, ebus, estore)
+	if err != nil {
+		t.Fatalf("run index: %v", err)
+	}
+	go func() {
+		for err := range errs {
+			panic(err)
+		}
+	}()
+
+	shelf := newListShelf(t)
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save Shelf: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	page, err := idx.List(shelf.ID, document.Query{Search: "invoice"})
+	if err != nil {
+		t.Fatalf("List failed with %q", err)
+	}
+	expectPage(t, page, []string{"Invoice 2", "Invoice 4"}, 2)
+
+	if err := shelf.Remove(ctx, storage, shelf.Documents[2].ID); err != nil {
+		t.Fatalf("remove document: %v", err)
+	}
+	if _, err := shelf.RenameDocument(shelf.Documents[0].ID, "Invoice 0"); err != nil {
+		t.Fatalf("rename document: %v", err)
+	}
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save Shelf: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	page, err = idx.List(shelf.ID, document.Query{Search: "invoice"})
+	if err != nil {
+		t.Fatalf("List failed with %q", err)
+	}
+	expectPage(t, page, []string{"Invoice 0", "Invoice 4"}, 2)
+
+	if _, err := idx.List(uuid.New(), document.Query{}); err != document.ErrShelfNotFound {
+		t.Fatalf("List should fail with %q for an unknown Shelf; got %q", document.ErrShelfNotFound, err)
+	}
+}
+
+// newListShelf returns a Shelf with 5 Documents. Even Documents are tagged
+// with "even" and every other even Document is named "Invoice".
+func newListShelf(t *testing.T) *document.Shelf {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("Report %d", i)
+		if i > 0 && i%2 == 0 {
+			name = fmt.Sprintf("Invoice %d", i)
+		}
+		doc, err := shelf.Add(context.Background(), storage, newPDF(), fmt.Sprintf("doc-%d", i), name, exampleDisk, fmt.Sprintf("/docs/%d.pdf", i))
+		if err != nil {
+			t.Fatalf("Add failed with %q", err)
+		}
+		if i%2 == 0 {
+			if _, err := shelf.Tag(doc.ID, "even"); err != nil {
+				t.Fatalf("Tag failed with %q", err)
+			}
+		}
+	}
+
+	return shelf
+}
+
+func expectPage(t *testing.T, page document.DocumentPage, names []string, total int) {
+	t.Helper()
+
+	if page.Total != total {
+		t.Fatalf("Total should be %d; is %d", total, page.Total)
+	}
+
+	if len(page.Documents) != len(names) {
+		t.Fatalf("page should have %d Documents; has %d", len(names), len(page.Documents))
+	}
+
+	for i, doc := range page.Documents {
+		if doc.Name != names[i] {
+			t.Fatalf("Documents[%d] should be %q; is %q", i, names[i], doc.Name)
+		}
+	}
+}
@@ -3,28 +3,91 @@ package document
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/google/uuid"
 	"github.com/modernice/goes/event"
-	"github.com/modernice/goes/projection"
+	"github.com/modernice/goes/event/query"
+	"github.com/modernice/goes/helper/streams"
 	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/projector"
 )
 
+// LookupProjection is the name of the persisted state of a Lookup (see
+// projector.Persist).
+const LookupProjection = "cms.media.document.lookup"
+
+// lookupEvents are the events that are projected by a Lookup.
+var lookupEvents = []string{
+	ShelfCreated,
+	ShelfDeleted,
+	ShelfRenamed,
+	DocumentAdded,
+	DocumentReplaced,
+	DocumentRemoved,
+	DocumentMadeUnique,
+	DocumentMadeNonUnique,
+	DocumentTrashed,
+	DocumentRestored,
+}
+
 // Lookup provides lookup of Shelf UUIDs. It is thread-safe.
 type Lookup struct {
+	projector *projector.Projector
+
+	// state is the persisted state of the Lookup, or nil.
+	state *projector.State
+
+	// applyMux serializes applied events and rebuilds.
+	applyMux sync.Mutex
+
 	shelfsMux sync.RWMutex
 	shelfs    map[uuid.UUID]*shelfLookup
 
 	shelfNamesMux sync.RWMutex
 	shelfNameToID map[string]uuid.UUID
+	shelfIDToName map[uuid.UUID]string
+
+	// shelfVersions are the aggregate versions of the last applied ShelfCreated,
+	// ShelfRenamed and ShelfDeleted events of the Shelfs. The event bus may
+	// deliver events of different names out of order, so older events are
+	// ignored.
+	shelfVersions map[uuid.UUID]int
+
+	checksumsMux      sync.RWMutex
+	checksums         map[string]documentRef
+	documentChecksums map[uuid.UUID]documentChecksum
+}
+
+type documentRef struct {
+	shelfID    uuid.UUID
+	documentID uuid.UUID
 }
 
-// NewLookup returns a new Lookup.
-func NewLookup() *Lookup {
+// documentChecksum is the checksum of a Document and the Shelf of the
+// Document.
+type documentChecksum struct {
+	shelfID  uuid.UUID
+	checksum string
+}
+
+// NewLookup returns a new Lookup. The provided options configure the error
+// handling of the projection and the persistence of its state (see
+// projector.Persist).
+func NewLookup(opts ...projector.Option) *Lookup {
+	proj := projector.New(opts...)
 	return &Lookup{
-		shelfs:        make(map[uuid.UUID]*shelfLookup),
-		shelfNameToID: make(map[string]uuid.UUID),
+		projector:         proj,
+		state:             proj.State(LookupProjection),
+		shelfs:            make(map[uuid.UUID]*shelfLookup),
+		shelfNameToID:     make(map[string]uuid.UUID),
+		shelfIDToName:     make(map[uuid.UUID]string),
+		shelfVersions:     make(map[uuid.UUID]int),
+		checksums:         make(map[string]documentRef),
+		documentChecksums: make(map[uuid.UUID]documentChecksum),
 	}
 }
 
@@ -34,73 +97,336 @@ func (l *Lookup) ShelfName(name string) (uuid.UUID, bool) {
 	return l.shelfName(name)
 }
 
+// ShelfRef is the UUID and name of a Shelf.
+type ShelfRef struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+// ShelfList is a page of the Shelfs of a Lookup.
+type ShelfList struct {
+	Shelfs []ShelfRef `json:"shelfs"`
+
+	// Total is the number of Shelfs.
+	Total int `json:"total"`
+
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// Shelfs returns the page of all created Shelfs, sorted by name. If limit is
+// 0, all Shelfs after offset are returned.
+func (l *Lookup) Shelfs(offset, limit int) ShelfList {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit < 0 {
+		limit = 0
+	}
+
+	l.shelfNamesMux.RLock()
+	refs := make([]ShelfRef, 0, len(l.shelfIDToName))
+	for id, name := range l.shelfIDToName {
+		refs = append(refs, ShelfRef{ID: id, Name: name})
+	}
+	l.shelfNamesMux.RUnlock()
+
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Name != refs[j].Name {
+			return refs[i].Name < refs[j].Name
+		}
+		return refs[i].ID.String() < refs[j].ID.String()
+	})
+
+	list := ShelfList{
+		Shelfs: []ShelfRef{},
+		Total:  len(refs),
+		Offset: offset,
+		Limit:  limit,
+	}
+
+	if offset >= len(refs) {
+		return list
+	}
+
+	refs = refs[offset:]
+	if limit > 0 && len(refs) > limit {
+		refs = refs[:limit]
+	}
+	list.Shelfs = append(list.Shelfs, refs...)
+
+	return list
+}
+
 // UniqueName returns the UUID of the Document with the given UniqueName in the
 // Shelf with the given UUID, or false if Lookup has no UUID for uniqueName.
 func (l *Lookup) UniqueName(shelfID uuid.UUID, uniqueName string) (uuid.UUID, bool) {
 	return l.shelf(shelfID).uniqueName(uniqueName)
 }
 
-// Project projects the Lookup in a new goroutine and returns a channel of
-// asynchronous errors.
+// Checksum returns the UUIDs of the Shelf and Document whose file has the
+// given SHA-256 checksum, or false if Lookup has no Document for checksum.
+func (l *Lookup) Checksum(checksum string) (shelfID, documentID uuid.UUID, ok bool) {
+	l.checksumsMux.RLock()
+	defer l.checksumsMux.RUnlock()
+	ref, ok := l.checksums[checksum]
+	return ref.shelfID, ref.documentID, ok
+}
+
+// HasDocument returns whether the Shelf with the given UUID has a Document
+// with the given UUID. Trashed and removed Documents are not found.
+func (l *Lookup) HasDocument(shelfID, documentID uuid.UUID) bool {
+	// Every added Document has a checksum, so the checksums of the Lookup also
+	// track which Documents exist.
+	l.checksumsMux.RLock()
+	defer l.checksumsMux.RUnlock()
+	ref, ok := l.documentChecksums[documentID]
+	return ok && ref.shelfID == shelfID
+}
+
+// Project catches up the Lookup with the past events, projects new events in
+// a new goroutine and returns a channel of asynchronous errors. If catching up
+// fails, the error is sent to the channel and Ready is not closed until the
+// Lookup is rebuilt (see Rebuild). Failed projection jobs
+// are handled according to the projector.Policy of the Lookup. If the state
+// of the Lookup is persisted (see projector.Persist), Project first restores
+// the persisted state, so that only the events after it are applied.
 func (l *Lookup) Project(ctx context.Context, bus event.Bus, store event.Store, opts ...schedule.ContinuousOption) (<-chan error, error) {
-	schedule := schedule.Continuously(bus, store, []string{
-		ShelfCreated,
-		DocumentAdded,
-		DocumentRemoved,
-		DocumentMadeUnique,
-		DocumentMadeNonUnique,
-	}, opts...)
-
-	errs, err := schedule.Subscribe(ctx, l.applyJob)
+	if l.state != nil {
+		if err := l.restore(ctx); err != nil {
+			return nil, fmt.Errorf("restore persisted state: %w", err)
+		}
+	}
+
+	schedule := schedule.Continuously(bus, store, lookupEvents, opts...)
+
+	return l.projector.Run(ctx, schedule, l)
+}
+
+// Ready returns a channel that is closed when the Lookup caught up with the
+// past events, either by Project or by Rebuild. Until then, lookups (e.g.
+// ShelfName) may miss Shelfs that already exist.
+func (l *Lookup) Ready() <-chan struct{} {
+	return l.projector.Ready()
+}
+
+// Rebuild replays the events of the Lookup from the event store into a new
+// state and replaces the current state with it, e.g. to repair a Lookup that
+// missed events. Rebuild blocks until all events are applied and marks the
+// Lookup as ready (see Ready). Events that are projected concurrently are
+// applied after the rebuild. If Rebuild fails, the current state is kept. If
+// the state of the Lookup is persisted, the persisted state is replaced, too.
+func (l *Lookup) Rebuild(ctx context.Context, store event.Store) error {
+	l.applyMux.Lock()
+	defer l.applyMux.Unlock()
+
+	events, errs, err := store.Query(ctx, query.New(
+		query.Name(lookupEvents...),
+		query.SortByTime(),
+	))
+	if err != nil {
+		return fmt.Errorf("query events: %w", err)
+	}
+
+	rebuilt := NewLookup()
+	var progress projector.Progress
+	if err := streams.Walk(ctx, func(evt event.Event) error {
+		rebuilt.applyEvent(evt)
+		if !progress.Time.Equal(evt.Time()) {
+			progress = projector.Progress{Time: evt.Time()}
+		}
+		progress.Events = append(progress.Events, evt.ID())
+		return nil
+	}, events, errs); err != nil {
+		return fmt.Errorf("apply events: %w", err)
+	}
+
+	l.replace(rebuilt)
+	l.projector.MarkReady()
+
+	if l.state != nil {
+		l.state.Reset(rebuilt.entries(), progress)
+		if err := l.state.Flush(ctx); err != nil {
+			return fmt.Errorf("persist rebuilt state: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// restore replaces the current state with the persisted state.
+func (l *Lookup) restore(ctx context.Context) error {
+	entries, err := l.state.Load(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("subscribe to projection schedule: %w", err)
+		return err
 	}
 
-	go schedule.Trigger(ctx)
+	restored := NewLookup()
+	for key, value := range entries {
+		if err := restored.restoreEntry(key, value); err != nil {
+			return err
+		}
+	}
+
+	l.applyMux.Lock()
+	defer l.applyMux.Unlock()
+	l.replace(restored)
+
+	return nil
+}
+
+// replace replaces the current state with the state of another Lookup. The
+// caller must hold applyMux.
+func (l *Lookup) replace(other *Lookup) {
+	l.shelfsMux.Lock()
+	l.shelfs = other.shelfs
+	l.shelfsMux.Unlock()
+
+	l.shelfNamesMux.Lock()
+	l.shelfNameToID = other.shelfNameToID
+	l.shelfIDToName = other.shelfIDToName
+	l.shelfVersions = other.shelfVersions
+	l.shelfNamesMux.Unlock()
 
-	return errs, nil
+	l.checksumsMux.Lock()
+	l.checksums = other.checksums
+	l.documentChecksums = other.documentChecksums
+	l.checksumsMux.Unlock()
 }
 
-func (l *Lookup) applyJob(job projection.Job) error {
-	return job.Apply(job, l)
+// Status returns the projection status of the Lookup.
+func (l *Lookup) Status() projector.Status {
+	return l.projector.Status()
 }
 
 // ApplyEvent applies aggregate events.
 func (l *Lookup) ApplyEvent(evt event.Event) {
+	l.applyMux.Lock()
+	defer l.applyMux.Unlock()
+	l.applyEvent(evt)
+}
+
+func (l *Lookup) applyEvent(evt event.Event) {
 	switch evt.Name() {
 	case ShelfCreated:
 		l.shelfCreated(evt)
+	case ShelfDeleted:
+		l.shelfDeleted(evt)
+	case ShelfRenamed:
+		l.shelfRenamed(evt)
 	case DocumentAdded:
 		l.documentAdded(evt)
+	case DocumentReplaced:
+		l.documentReplaced(evt)
 	case DocumentRemoved:
 		l.documentRemoved(evt)
 	case DocumentMadeUnique:
 		l.documentMadeUnique(evt)
 	case DocumentMadeNonUnique:
 		l.documentMadeNonUnique(evt)
+	case DocumentTrashed:
+		l.documentTrashed(evt)
+	case DocumentRestored:
+		l.documentRestored(evt)
 	}
 }
 
 func (l *Lookup) shelfCreated(evt event.Event) {
 	data := evt.Data().(ShelfCreatedData)
-	id, _, _ := evt.Aggregate()
+	id, _, v := evt.Aggregate()
+
+	l.shelfNamesMux.Lock()
+	defer l.shelfNamesMux.Unlock()
+	if l.advanceShelf(id, v) {
+		l.setShelfName(id, data.Name)
+	}
+}
+
+func (l *Lookup) shelfDeleted(evt event.Event) {
+	data := evt.Data().(ShelfDeletedData)
+	id, _, v := evt.Aggregate()
+
+	l.shelfNamesMux.Lock()
+	if !l.advanceShelf(id, v) {
+		l.shelfNamesMux.Unlock()
+		return
+	}
+	if l.shelfNameToID[data.Name] == id {
+		delete(l.shelfNameToID, data.Name)
+	}
+	delete(l.shelfIDToName, id)
+	l.shelfNamesMux.Unlock()
+
+	l.shelfsMux.Lock()
+	if s, ok := l.shelfs[id]; ok {
+		for _, name := range s.names() {
+			l.unpersist(uniqueNameKey(id, name))
+		}
+	}
+	delete(l.shelfs, id)
+	l.shelfsMux.Unlock()
+	l.unpersist(shelfKey(id))
+
+	for _, documentID := range data.Documents {
+		l.setChecksum(id, documentID, "")
+	}
+}
+
+func (l *Lookup) shelfRenamed(evt event.Event) {
+	data := evt.Data().(ShelfRenamedData)
+	id, _, v := evt.Aggregate()
+
+	l.shelfNamesMux.Lock()
+	defer l.shelfNamesMux.Unlock()
+	if !l.advanceShelf(id, v) {
+		return
+	}
+	if l.shelfNameToID[data.OldName] == id {
+		delete(l.shelfNameToID, data.OldName)
+	}
 	l.setShelfName(id, data.Name)
 }
 
 func (l *Lookup) documentAdded(evt event.Event) {
 	data := evt.Data().(DocumentAddedData)
+	id, _, _ := evt.Aggregate()
 	if data.Document.UniqueName != "" {
-		id, _, _ := evt.Aggregate()
 		l.setUniqueName(id, data.Document.ID, data.Document.UniqueName)
 	}
+	l.setChecksum(id, data.Document.ID, data.Document.Checksum)
+}
+
+func (l *Lookup) documentReplaced(evt event.Event) {
+	data := evt.Data().(DocumentReplacedData)
+	id, _, _ := evt.Aggregate()
+	l.setChecksum(id, data.Document.ID, data.Document.Checksum)
 }
 
 func (l *Lookup) documentRemoved(evt event.Event) {
 	data := evt.Data().(DocumentRemovedData)
+	id, _, _ := evt.Aggregate()
+	if data.Document.UniqueName != "" {
+		l.removeUniqueName(id, data.Document.ID, data.Document.UniqueName)
+	}
+	l.setChecksum(id, data.Document.ID, "")
+}
+
+func (l *Lookup) documentTrashed(evt event.Event) {
+	data := evt.Data().(DocumentTrashedData)
+	id, _, _ := evt.Aggregate()
 	if data.Document.UniqueName != "" {
-		id, _, _ := evt.Aggregate()
 		l.removeUniqueName(id, data.Document.ID, data.Document.UniqueName)
 	}
+	l.setChecksum(id, data.Document.ID, "")
+}
+
+func (l *Lookup) documentRestored(evt event.Event) {
+	data := evt.Data().(DocumentRestoredData)
+	id, _, _ := evt.Aggregate()
+	if data.Document.UniqueName != "" {
+		l.setUniqueName(id, data.Document.ID, data.Document.UniqueName)
+	}
+	l.setChecksum(id, data.Document.ID, data.Document.Checksum)
 }
 
 func (l *Lookup) documentMadeUnique(evt event.Event) {
@@ -118,11 +444,39 @@ func (l *Lookup) documentMadeNonUnique(evt event.Event) {
 func (l *Lookup) setUniqueName(shelfID, documentID uuid.UUID, name string) {
 	s := l.shelf(shelfID)
 	s.setUniqueName(documentID, name)
+	l.persist(uniqueNameKey(shelfID, name), documentID.String())
 }
 
 func (l *Lookup) removeUniqueName(shelfID, documentID uuid.UUID, name string) {
 	s := l.shelf(shelfID)
-	s.removeUniqueName(documentID, name)
+	if s.removeUniqueName(documentID, name) {
+		l.unpersist(uniqueNameKey(shelfID, name))
+	}
+}
+
+// setChecksum sets the checksum of the given Document. An empty checksum
+// removes the Document from the checksum lookup.
+func (l *Lookup) setChecksum(shelfID, documentID uuid.UUID, checksum string) {
+	l.checksumsMux.Lock()
+	defer l.checksumsMux.Unlock()
+
+	if old, ok := l.documentChecksums[documentID]; ok {
+		if l.checksums[old.checksum].documentID == documentID {
+			delete(l.checksums, old.checksum)
+		}
+		delete(l.documentChecksums, documentID)
+		l.unpersist(checksumKey(documentID))
+	}
+
+	if checksum == "" {
+		return
+	}
+
+	l.documentChecksums[documentID] = documentChecksum{shelfID: shelfID, checksum: checksum}
+	l.persist(checksumKey(documentID), shelfID.String()+"/"+checksum)
+	if _, taken := l.checksums[checksum]; !taken {
+		l.checksums[checksum] = documentRef{shelfID: shelfID, documentID: documentID}
+	}
 }
 
 func (l *Lookup) shelf(id uuid.UUID) *shelfLookup {
@@ -141,10 +495,24 @@ func (l *Lookup) shelf(id uuid.UUID) *shelfLookup {
 	return s
 }
 
+// advanceShelf records v as the version of the Shelf with the given UUID and
+// returns true if v is newer than the recorded version. The caller must hold
+// shelfNamesMux.
+func (l *Lookup) advanceShelf(id uuid.UUID, v int) bool {
+	if v <= l.shelfVersions[id] {
+		return false
+	}
+	l.shelfVersions[id] = v
+	l.persist(versionKey(id), strconv.Itoa(v))
+	return true
+}
+
+// setShelfName sets the name of the Shelf with the given UUID. The caller must
+// hold shelfNamesMux.
 func (l *Lookup) setShelfName(id uuid.UUID, name string) {
-	l.shelfNamesMux.Lock()
-	defer l.shelfNamesMux.Unlock()
 	l.shelfNameToID[name] = id
+	l.shelfIDToName[id] = name
+	l.persist(shelfKey(id), name)
 }
 
 func (l *Lookup) shelfName(name string) (uuid.UUID, bool) {
@@ -169,12 +537,14 @@ func (l *shelfLookup) setUniqueName(id uuid.UUID, name string) {
 	l.uniqueNameToID[name] = id
 }
 
-func (l *shelfLookup) removeUniqueName(id uuid.UUID, name string) {
+func (l *shelfLookup) removeUniqueName(id uuid.UUID, name string) bool {
 	l.mux.Lock()
 	defer l.mux.Unlock()
-	if l.uniqueNameToID[name] == id {
-		delete(l.uniqueNameToID, name)
+	if l.uniqueNameToID[name] != id {
+		return false
 	}
+	delete(l.uniqueNameToID, name)
+	return true
 }
 
 func (l *shelfLookup) uniqueName(name string) (uuid.UUID, bool) {
@@ -183,3 +553,122 @@ func (l *shelfLookup) uniqueName(name string) (uuid.UUID, bool) {
 	id, ok := l.uniqueNameToID[name]
 	return id, ok
 }
+
+func (l *shelfLookup) names() []string {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+	names := make([]string, 0, len(l.uniqueNameToID))
+	for name := range l.uniqueNameToID {
+		names = append(names, name)
+	}
+	return names
+}
+
+// The persisted state of a Lookup consists of the following entries:
+//
+//	shelf/<shelfID>              -> <name>
+//	version/<shelfID>            -> <version>
+//	unique/<shelfID>/<name>      -> <documentID>
+//	checksum/<documentID>        -> <shelfID>/<checksum>
+
+func shelfKey(shelfID uuid.UUID) string {
+	return "shelf/" + shelfID.String()
+}
+
+func versionKey(shelfID uuid.UUID) string {
+	return "version/" + shelfID.String()
+}
+
+func uniqueNameKey(shelfID uuid.UUID, name string) string {
+	return "unique/" + shelfID.String() + "/" + name
+}
+
+func checksumKey(documentID uuid.UUID) string {
+	return "checksum/" + documentID.String()
+}
+
+func (l *Lookup) persist(key, value string) {
+	if l.state != nil {
+		l.state.Set(key, value)
+	}
+}
+
+func (l *Lookup) unpersist(key string) {
+	if l.state != nil {
+		l.state.Delete(key)
+	}
+}
+
+// entries returns the current state as persisted entries.
+func (l *Lookup) entries() map[string]string {
+	entries := make(map[string]string)
+
+	l.shelfNamesMux.RLock()
+	for id, name := range l.shelfIDToName {
+		entries[shelfKey(id)] = name
+	}
+	for id, v := range l.shelfVersions {
+		entries[versionKey(id)] = strconv.Itoa(v)
+	}
+	l.shelfNamesMux.RUnlock()
+
+	l.shelfsMux.RLock()
+	for shelfID, s := range l.shelfs {
+		s.mux.RLock()
+		for name, documentID := range s.uniqueNameToID {
+			entries[uniqueNameKey(shelfID, name)] = documentID.String()
+		}
+		s.mux.RUnlock()
+	}
+	l.shelfsMux.RUnlock()
+
+	l.checksumsMux.RLock()
+	for documentID, c := range l.documentChecksums {
+		entries[checksumKey(documentID)] = c.shelfID.String() + "/" + c.checksum
+	}
+	l.checksumsMux.RUnlock()
+
+	return entries
+}
+
+// restoreEntry applies a persisted entry.
+func (l *Lookup) restoreEntry(key, value string) error {
+	kind, rest, _ := strings.Cut(key, "/")
+	rawID, name, hasName := strings.Cut(rest, "/")
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return fmt.Errorf("invalid entry %q: %w", key, err)
+	}
+
+	switch kind {
+	case "shelf":
+		l.shelfNamesMux.Lock()
+		l.setShelfName(id, value)
+		l.shelfNamesMux.Unlock()
+	case "version":
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid entry %q", key)
+		}
+		l.shelfNamesMux.Lock()
+		l.shelfVersions[id] = v
+		l.shelfNamesMux.Unlock()
+	case "unique":
+		documentID, err := uuid.Parse(value)
+		if err != nil || !hasName {
+			return fmt.Errorf("invalid entry %q", key)
+		}
+		l.setUniqueName(id, documentID, name)
+	case "checksum":
+		rawShelfID, checksum, _ := strings.Cut(value, "/")
+		shelfID, err := uuid.Parse(rawShelfID)
+		if err != nil || checksum == "" {
+			return fmt.Errorf("invalid entry %q", key)
+		}
+		l.setChecksum(shelfID, id, checksum)
+	default:
+		return fmt.Errorf("unknown entry %q", key)
+	}
+
+	return nil
+}
@@ -0,0 +1,348 @@
+package document_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/projector"
+)
+
+func TestLookup_Checksum(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	shelfs := document.GoesRepository(repository.New(estore))
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	lookup := document.NewLookup()
+
+	errs, err := lookup.Project(ctx, ebus, estore)
+	if err != nil {
+		t.Fatalf("run lookup: %v", err)
+	}
+	go func() {
+		for err := range errs {
+			panic(err)
+		}
+	}()
+
+	checksum, err := media.Checksum(newPDF())
+	if err != nil {
+		t.Fatalf("compute checksum: %v", err)
+	}
+
+	if _, _, ok := lookup.Checksum(checksum); ok {
+		t.Fatalf("Checksum(%q) should return %v", checksum, false)
+	}
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+	doc, err := shelf.Add(ctx, storage, newPDF(), "", exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save Shelf: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	shelfID, documentID, ok := lookup.Checksum(checksum)
+	if !ok {
+		t.Fatalf("Checksum(%q) returned %v", checksum, false)
+	}
+
+	if shelfID != shelf.ID || documentID != doc.ID {
+		t.Fatalf("Checksum(%q) should return (%s, %s); got (%s, %s)", checksum, shelf.ID, doc.ID, shelfID, documentID)
+	}
+
+	if !lookup.HasDocument(shelf.ID, doc.ID) {
+		t.Fatalf("HasDocument(%s, %s) should return %v", shelf.ID, doc.ID, true)
+	}
+
+	if lookup.HasDocument(uuid.New(), doc.ID) {
+		t.Fatalf("HasDocument should return %v for another Shelf", false)
+	}
+
+	if err := shelf.Remove(ctx, storage, doc.ID); err != nil {
+		t.Fatalf("remove document: %v", err)
+	}
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save Shelf: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	if _, _, ok := lookup.Checksum(checksum); ok {
+		t.Fatalf("Checksum(%q) should return %v after the Document was removed", checksum, false)
+	}
+
+	if lookup.HasDocument(shelf.ID, doc.ID) {
+		t.Fatalf("HasDocument(%s, %s) should return %v after the Document was removed", shelf.ID, doc.ID, false)
+	}
+}
+
+func TestLookup_Shelfs(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	lookup := document.NewLookup()
+
+	var deleted *document.Shelf
+	for _, name := range []string{"foo", "bar", "baz"} {
+		shelf := document.NewShelf(uuid.New())
+		shelf.Create(name)
+		if name == "baz" {
+			if _, err := shelf.Destroy(ctx, storage); err != nil {
+				t.Fatalf("destroy Shelf: %v", err)
+			}
+			deleted = shelf
+		}
+		for _, evt := range shelf.AggregateChanges() {
+			lookup.ApplyEvent(evt)
+		}
+	}
+
+	list := lookup.Shelfs(0, 0)
+	if list.Total != 2 || len(list.Shelfs) != 2 {
+		t.Fatalf("Shelfs should return 2 Shelfs; got %v", list)
+	}
+	if list.Shelfs[0].Name != "bar" || list.Shelfs[1].Name != "foo" {
+		t.Fatalf("Shelfs should be sorted by name; got %v", list.Shelfs)
+	}
+	for _, ref := range list.Shelfs {
+		if ref.ID == deleted.ID {
+			t.Fatalf("Shelfs should not return the deleted Shelf")
+		}
+		if id, _ := lookup.ShelfName(ref.Name); id != ref.ID {
+			t.Fatalf("Shelf %q should have UUID %s; has %s", ref.Name, id, ref.ID)
+		}
+	}
+
+	page := lookup.Shelfs(1, 1)
+	if page.Total != 2 || len(page.Shelfs) != 1 || page.Shelfs[0] != list.Shelfs[1] {
+		t.Fatalf("Shelfs(1, 1) should return %v; got %v", list.Shelfs[1:], page)
+	}
+
+	if page := lookup.Shelfs(5, 1); page.Total != 2 || len(page.Shelfs) != 0 {
+		t.Fatalf("Shelfs(5, 1) should return an empty page; got %v", page)
+	}
+}
+
+func TestLookup_ShelfName_renamed(t *testing.T) {
+	lookup := document.NewLookup()
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create("foo")
+	shelf.Rename("bar")
+	for _, evt := range shelf.AggregateChanges() {
+		lookup.ApplyEvent(evt)
+	}
+
+	if _, ok := lookup.ShelfName("foo"); ok {
+		t.Fatalf("the old name should not be found after renaming the Shelf")
+	}
+	if id, ok := lookup.ShelfName("bar"); !ok || id != shelf.ID {
+		t.Fatalf("ShelfName(%q) should return %s; got %s (%v)", "bar", shelf.ID, id, ok)
+	}
+	if list := lookup.Shelfs(0, 0); len(list.Shelfs) != 1 || list.Shelfs[0].Name != "bar" {
+		t.Fatalf("Shelfs should return the new name; got %v", list.Shelfs)
+	}
+}
+
+func TestLookup_ShelfName_outOfOrder(t *testing.T) {
+	lookup := document.NewLookup()
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create("foo")
+	shelf.Rename("bar")
+	changes := shelf.AggregateChanges()
+
+	// the event bus may deliver the ShelfCreated event after the ShelfRenamed
+	// event
+	for i := len(changes) - 1; i >= 0; i-- {
+		lookup.ApplyEvent(changes[i])
+	}
+
+	if _, ok := lookup.ShelfName("foo"); ok {
+		t.Fatalf("a late ShelfCreated event should not restore the old name")
+	}
+	if id, ok := lookup.ShelfName("bar"); !ok || id != shelf.ID {
+		t.Fatalf("ShelfName(%q) should return %s; got %s (%v)", "bar", shelf.ID, id, ok)
+	}
+}
+
+func TestLookup_Project_ready(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	shelfs := document.GoesRepository(repository.New(estore))
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save Shelf: %v", err)
+	}
+
+	lookup := document.NewLookup()
+
+	if _, err := lookup.Project(ctx, ebus, estore); err != nil {
+		t.Fatalf("run lookup: %v", err)
+	}
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("Lookup should be ready after catching up")
+	case <-lookup.Ready():
+	}
+
+	if !lookup.Status().Ready {
+		t.Fatalf("Status should report the Lookup as ready")
+	}
+
+	if id, ok := lookup.ShelfName(exampleShelfName); !ok || id != shelf.ID {
+		t.Fatalf("ShelfName(%q) should return %s once the Lookup is ready; got %s (%v)", exampleShelfName, shelf.ID, id, ok)
+	}
+}
+
+func TestLookup_Rebuild(t *testing.T) {
+	ctx := context.Background()
+	estore := eventstore.New()
+	shelfs := document.GoesRepository(repository.New(estore))
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save Shelf: %v", err)
+	}
+
+	lookup := document.NewLookup()
+
+	select {
+	case <-lookup.Ready():
+		t.Fatalf("Lookup should not be ready before it caught up")
+	default:
+	}
+
+	// an event that is not in the store is discarded by the rebuild
+	stale := document.NewShelf(uuid.New())
+	stale.Create("stale")
+	for _, evt := range stale.AggregateChanges() {
+		lookup.ApplyEvent(evt)
+	}
+
+	if err := lookup.Rebuild(ctx, estore); err != nil {
+		t.Fatalf("Rebuild failed with %q", err)
+	}
+
+	select {
+	case <-lookup.Ready():
+	default:
+		t.Fatalf("Lookup should be ready after Rebuild")
+	}
+
+	if id, ok := lookup.ShelfName(exampleShelfName); !ok || id != shelf.ID {
+		t.Fatalf("ShelfName(%q) should return %s; got %s (%v)", exampleShelfName, shelf.ID, id, ok)
+	}
+
+	if _, ok := lookup.ShelfName("stale"); ok {
+		t.Fatalf("Rebuild should replace the state of the Lookup")
+	}
+}
+
+func TestLookup_Project_persist(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	shelfs := document.GoesRepository(repository.New(estore))
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	states := projector.MemoryStateStore()
+
+	lookup := document.NewLookup(projector.Persist(states))
+	if _, err := lookup.Project(ctx, ebus, estore); err != nil {
+		t.Fatalf("run lookup: %v", err)
+	}
+	<-lookup.Ready()
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create("foo")
+	doc, err := shelf.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("add Document: %v", err)
+	}
+	if err := shelf.Rename("bar"); err != nil {
+		t.Fatalf("rename Shelf: %v", err)
+	}
+	changes := shelf.AggregateChanges()
+	last := changes[len(changes)-1]
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save Shelf: %v", err)
+	}
+
+	awaitProgress(t, states, document.LookupProjection, last.Time())
+
+	// The restarted Lookup projects an empty event store, so it can only know
+	// the Shelf from the persisted state.
+	restarted := document.NewLookup(projector.Persist(states))
+	if _, err := restarted.Project(ctx, eventbus.New(), eventstore.New()); err != nil {
+		t.Fatalf("run lookup: %v", err)
+	}
+	<-restarted.Ready()
+
+	if id, ok := restarted.ShelfName("bar"); !ok || id != shelf.ID {
+		t.Fatalf("ShelfName(%q) should return %s; got %s (%v)", "bar", shelf.ID, id, ok)
+	}
+
+	if _, ok := restarted.ShelfName("foo"); ok {
+		t.Fatalf("ShelfName(%q) should return %v after the Shelf was renamed", "foo", false)
+	}
+
+	if id, ok := restarted.UniqueName(shelf.ID, exampleUniqueName); !ok || id != doc.ID {
+		t.Fatalf("UniqueName(%q) should return %s; got %s (%v)", exampleUniqueName, doc.ID, id, ok)
+	}
+
+	if shelfID, documentID, ok := restarted.Checksum(doc.Checksum); !ok || shelfID != shelf.ID || documentID != doc.ID {
+		t.Fatalf("Checksum(%q) should return (%s, %s); got (%s, %s, %v)", doc.Checksum, shelf.ID, doc.ID, shelfID, documentID, ok)
+	}
+
+	if err := restarted.Rebuild(ctx, eventstore.New()); err != nil {
+		t.Fatalf("Rebuild failed with %q", err)
+	}
+
+	if entries, _, _ := states.Load(ctx, document.LookupProjection); len(entries) != 0 {
+		t.Fatalf("Rebuild should replace the persisted state; got %v", entries)
+	}
+}
+
+// awaitProgress waits until the persisted Progress of the given projection
+// reached the given time.
+func awaitProgress(t *testing.T, states projector.StateStore, projection string, at time.Time) {
+	timeout := time.After(time.Second)
+	for {
+		_, progress, err := states.Load(context.Background(), projection)
+		if err != nil {
+			t.Fatalf("load state: %v", err)
+		}
+		if !progress.Time.Before(at) {
+			return
+		}
+
+		select {
+		case <-timeout:
+			t.Fatalf("Progress should reach %v; is %v", at, progress.Time)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
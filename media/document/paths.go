@@ -0,0 +1,112 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/media"
+)
+
+// A PathStrategy returns the storage path for a Document. PathStrategies are
+// used to move the file of a Document when the Document is renamed. See
+// MovePaths.
+type PathStrategy func(Document) string
+
+// SlugPath returns a PathStrategy that stores a Document under the slug of its
+// UniqueName, or of its Name if it has no UniqueName, within dir. The file
+// extension of the current path is kept. If dir is empty, the Document stays
+// in its current directory:
+//
+//	doc.Path = "/docs/abc123.pdf"
+//	doc.UniqueName = "Annual Report 2021"
+//	SlugPath("")(doc) // "/docs/annual-report-2021.pdf"
+//
+// If the slug is empty, the current path of the Document is returned.
+func SlugPath(dir string) PathStrategy {
+	return func(doc Document) string {
+		name := doc.UniqueName
+		if name == "" {
+			name = doc.Name
+		}
+
+		ext := path.Ext(doc.Path)
+		if strings.EqualFold(path.Ext(name), ext) {
+			name = strings.TrimSuffix(name, path.Ext(name))
+		}
+
+		s := slug(name)
+		if s == "" {
+			return doc.Path
+		}
+
+		d := dir
+		if d == "" {
+			d = path.Dir(doc.Path)
+		}
+
+		return path.Join(d, s+ext)
+	}
+}
+
+func slug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
+
+// movePath moves the Document with the given UUID to the path returned by
+// strategy. If the file was moved, moved is set to the DocumentMoved event
+// data, so that the move can be rolled back.
+func (s *Shelf) movePath(ctx context.Context, storage media.Storage, id uuid.UUID, strategy PathStrategy, moved **DocumentMovedData) error {
+	doc, err := s.Document(id)
+	if err != nil {
+		return err
+	}
+
+	p := strategy(doc)
+	if p == "" || p == doc.Path {
+		return nil
+	}
+
+	if _, err := s.MoveDocument(ctx, storage, doc.ID, p); err != nil {
+		return fmt.Errorf("move document: %w", err)
+	}
+
+	// Content-addressed files are not moved in storage.
+	if doc.StoragePath != "" {
+		return nil
+	}
+
+	*moved = &DocumentMovedData{
+		DocumentID: doc.ID,
+		Disk:       doc.Disk,
+		OldPath:    doc.Path,
+		Path:       p,
+	}
+
+	return nil
+}
+
+// rollbackMove moves a file back to its previous path.
+func rollbackMove(ctx context.Context, storage media.Storage, data DocumentMovedData) {
+	disk, err := storage.Disk(data.Disk)
+	if err != nil {
+		return
+	}
+	media.Move(ctx, disk, data.Path, data.OldPath)
+}
@@ -0,0 +1,111 @@
+package document
+
+import (
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/media"
+)
+
+// PreviewStatus is the rendering status of a Preview.
+type PreviewStatus string
+
+// Preview statuses
+const (
+	// PreviewStatusPending means that the preview is currently being rendered.
+	PreviewStatusPending = PreviewStatus("pending")
+
+	// PreviewStatusRendered means that the preview was rendered successfully.
+	PreviewStatusRendered = PreviewStatus("rendered")
+
+	// PreviewStatusFailed means that rendering the preview failed.
+	PreviewStatusFailed = PreviewStatus("failed")
+)
+
+// Preview is the rendered preview image of a Document.
+type Preview struct {
+	Status PreviewStatus `json:"status"`
+
+	// Image is the preview image. Image is only set if Status is
+	// PreviewStatusRendered.
+	Image media.Image `json:"image"`
+
+	// Error is the rendering error if Status is PreviewStatusFailed.
+	Error string `json:"error,omitempty"`
+}
+
+// StartPreview marks the preview of the Document with the given UUID as
+// pending. If the Document cannot be found in the Shelf, ErrNotFound is
+// returned.
+func (s *Shelf) StartPreview(id uuid.UUID) (Document, error) {
+	doc, err := s.Document(id)
+	if err != nil {
+		return doc, err
+	}
+
+	aggregate.NextEvent(s, PreviewStarted, PreviewStartedData{DocumentID: doc.ID})
+
+	return s.Document(doc.ID)
+}
+
+func (s *Shelf) startPreview(evt event.Event) {
+	data := evt.Data().(PreviewStartedData)
+	s.setPreview(data.DocumentID, Preview{Status: PreviewStatusPending})
+}
+
+// RenderPreview sets the rendered preview image of the Document with the given
+// UUID. If the Document cannot be found in the Shelf, ErrNotFound is returned.
+func (s *Shelf) RenderPreview(id uuid.UUID, img media.Image) (Document, error) {
+	doc, err := s.Document(id)
+	if err != nil {
+		return doc, err
+	}
+
+	aggregate.NextEvent(s, PreviewRendered, PreviewRenderedData{
+		DocumentID: doc.ID,
+		Image:      img,
+	})
+
+	return s.Document(doc.ID)
+}
+
+func (s *Shelf) renderPreview(evt event.Event) {
+	data := evt.Data().(PreviewRenderedData)
+	s.setPreview(data.DocumentID, Preview{
+		Status: PreviewStatusRendered,
+		Image:  data.Image,
+	})
+}
+
+// FailPreview marks the preview of the Document with the given UUID as failed.
+// If the Document cannot be found in the Shelf, ErrNotFound is returned.
+func (s *Shelf) FailPreview(id uuid.UUID, renderError error) (Document, error) {
+	doc, err := s.Document(id)
+	if err != nil {
+		return doc, err
+	}
+
+	aggregate.NextEvent(s, PreviewFailed, PreviewFailedData{
+		DocumentID: doc.ID,
+		Error:      renderError.Error(),
+	})
+
+	return s.Document(doc.ID)
+}
+
+func (s *Shelf) failPreview(evt event.Event) {
+	data := evt.Data().(PreviewFailedData)
+	s.setPreview(data.DocumentID, Preview{
+		Status: PreviewStatusFailed,
+		Error:  data.Error,
+	})
+}
+
+func (s *Shelf) setPreview(id uuid.UUID, preview Preview) {
+	doc, err := s.Document(id)
+	if err != nil {
+		return
+	}
+	doc.Preview = &preview
+	s.replace(doc.ID, doc)
+}
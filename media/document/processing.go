@@ -0,0 +1,323 @@
+package document
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	_ "image/png" // decode rendered previews
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/helper/streams"
+	"github.com/modernice/nice-cms/media"
+)
+
+// ErrUnsupportedFormat is returned by a Renderer that cannot render previews
+// for the format of a document.
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// Renderer renders preview images of documents.
+type Renderer interface {
+	// Supports returns whether the Renderer can render previews of documents
+	// with the given file extension (e.g. ".docx").
+	Supports(ext string) bool
+
+	// Render renders a preview of the document in r and writes it as a PNG
+	// image to w. ext is the file extension of the document.
+	Render(ctx context.Context, w io.Writer, r io.Reader, ext string) error
+}
+
+// Printer is a logger.
+type Printer interface {
+	Print(...any)
+}
+
+// PostProcessor renders the previews of Documents in the background. If the
+// PostProcessor is configured with an AudioMetadataExtractor (see
+// ExtractAudio), it also extracts the metadata of audio Documents.
+type PostProcessor struct {
+	renderer Renderer
+	storage  media.Storage
+	shelfs   Repository
+	audio    media.AudioMetadataExtractor
+}
+
+// ProcessorOption is an option for NewPostProcessor.
+type ProcessorOption func(*PostProcessor)
+
+// ExtractAudio returns a ProcessorOption that makes the PostProcessor extract
+// the metadata of audio Documents using the provided AudioMetadataExtractor.
+func ExtractAudio(ext media.AudioMetadataExtractor) ProcessorOption {
+	return func(svc *PostProcessor) {
+		svc.audio = ext
+	}
+}
+
+// NewPostProcessor returns a PostProcessor. renderer may be nil if only the
+// metadata of audio Documents should be extracted.
+func NewPostProcessor(renderer Renderer, storage media.Storage, shelfs Repository, opts ...ProcessorOption) *PostProcessor {
+	svc := &PostProcessor{
+		renderer: renderer,
+		storage:  storage,
+		shelfs:   shelfs,
+	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	return svc
+}
+
+// Process renders the preview of the Document with the given UUID and updates
+// the Shelf. While the preview is rendered, the status of the preview is
+// PreviewStatusPending. If rendering fails, the status of the preview is set to
+// PreviewStatusFailed and the rendering error is returned. Documents that the
+// Renderer doesn't support are skipped.
+//
+// If the PostProcessor extracts audio metadata and the Document is an audio
+// file, the metadata is extracted before the preview is rendered.
+func (svc *PostProcessor) Process(ctx context.Context, shelfID, documentID uuid.UUID) error {
+	shelf, err := svc.shelfs.Fetch(ctx, shelfID)
+	if err != nil {
+		return fmt.Errorf("fetch Shelf %q: %w", shelfID, err)
+	}
+
+	doc, err := shelf.Document(documentID)
+	if err != nil {
+		return fmt.Errorf("get Document %q: %w", documentID, err)
+	}
+
+	if svc.audio != nil && doc.IsAudio() {
+		if err := svc.extractAudio(ctx, shelfID, doc); err != nil {
+			return fmt.Errorf("extract audio metadata of Document %q: %w", documentID, err)
+		}
+	}
+
+	if svc.renderer == nil || !svc.renderer.Supports(strings.ToLower(filepath.Ext(doc.Path))) {
+		return nil
+	}
+
+	if err := svc.shelfs.Use(ctx, shelfID, func(s *Shelf) error {
+		_, err := s.StartPreview(documentID)
+		return err
+	}); err != nil {
+		return fmt.Errorf("start preview: %w", err)
+	}
+
+	img, renderError := svc.render(ctx, doc)
+
+	if err := svc.shelfs.Use(ctx, shelfID, func(s *Shelf) error {
+		if renderError != nil {
+			_, err := s.FailPreview(documentID, renderError)
+			return err
+		}
+		_, err := s.RenderPreview(documentID, img)
+		return err
+	}); err != nil {
+		return fmt.Errorf("update preview: %w", err)
+	}
+
+	if renderError != nil {
+		return fmt.Errorf("render preview of Document %q: %w", documentID, renderError)
+	}
+
+	return nil
+}
+
+func (svc *PostProcessor) extractAudio(ctx context.Context, shelfID uuid.UUID, doc Document) error {
+	content, err := doc.Reader(ctx, svc.storage)
+	if err != nil {
+		return fmt.Errorf("read document: %w", err)
+	}
+	defer content.Close()
+
+	meta, err := svc.audio.ExtractAudioMetadata(ctx, content)
+	if err != nil {
+		return err
+	}
+
+	return svc.shelfs.Use(ctx, shelfID, func(s *Shelf) error {
+		_, err := s.SetAudioMetadata(doc.ID, meta)
+		return err
+	})
+}
+
+// render renders the preview of doc and uploads it next to the document.
+func (svc *PostProcessor) render(ctx context.Context, doc Document) (media.Image, error) {
+	content, err := doc.Reader(ctx, svc.storage)
+	if err != nil {
+		return media.Image{}, fmt.Errorf("read document: %w", err)
+	}
+	defer content.Close()
+
+	pr, pw := io.Pipe()
+	renderError := make(chan error, 1)
+	go func() {
+		err := svc.renderer.Render(ctx, pw, content, strings.ToLower(filepath.Ext(doc.Path)))
+		pw.CloseWithError(err)
+		renderError <- err
+	}()
+
+	img := media.NewImage(0, 0, doc.Name, doc.Disk, previewPath(doc.Path), 0)
+	img, err = img.Upload(ctx, pr, svc.storage)
+
+	// Unblock the Renderer if the upload failed before the preview was read.
+	pr.CloseWithError(err)
+
+	// A rendering error is more relevant than the resulting upload error.
+	if rerr := <-renderError; rerr != nil {
+		return img, rerr
+	}
+
+	return img, err
+}
+
+func previewPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".preview.png"
+}
+
+// PostProcessorOption is an option for PostProcessor.Run.
+type PostProcessorOption func(*postProcessorConfig)
+
+type postProcessorConfig struct {
+	logger  Printer
+	workers int
+}
+
+// ProcessorLogger returns a PostProcessorOption that provides the post-processor
+// with a logger.
+func ProcessorLogger(logger Printer) PostProcessorOption {
+	return func(cfg *postProcessorConfig) {
+		cfg.logger = logger
+	}
+}
+
+// ProcessorWorkers returns a PostProcessorOption that configures the worker
+// count for rendering previews. Default & minimum workers is 1.
+func ProcessorWorkers(workers int) PostProcessorOption {
+	return func(cfg *postProcessorConfig) {
+		cfg.workers = workers
+	}
+}
+
+// Run starts the PostProcessor in the background and returns a channel of
+// asynchronous processing errors. The PostProcessor processes added and
+// replaced Documents until ctx is canceled.
+func (svc *PostProcessor) Run(ctx context.Context, bus event.Bus, opts ...PostProcessorOption) (<-chan error, error) {
+	cfg := newProcessorConfig(opts...)
+
+	events, errs, err := bus.Subscribe(ctx, DocumentAdded, DocumentReplaced)
+	if err != nil {
+		return nil, fmt.Errorf("subscribe to %q event: %w", DocumentAdded, err)
+	}
+
+	queue := make(chan processorJob)
+	out := make(chan error)
+
+	go svc.work(ctx, cfg, queue, out)
+	go svc.accept(ctx, queue, events, errs, out)
+
+	return out, nil
+}
+
+type processorJob struct {
+	shelfID    uuid.UUID
+	documentID uuid.UUID
+}
+
+func (svc *PostProcessor) work(ctx context.Context, cfg postProcessorConfig, queue <-chan processorJob, out chan<- error) {
+	defer close(out)
+
+	cfg.logf("Post-processor running with %d worker(s).", cfg.workers)
+
+	var wg sync.WaitGroup
+	wg.Add(cfg.workers)
+
+	for i := 0; i < cfg.workers; i++ {
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				cfg.logf("Processing document (ShelfID=%v DocumentID=%v)", job.shelfID, job.documentID)
+				start := time.Now()
+
+				if err := svc.Process(ctx, job.shelfID, job.documentID); err != nil {
+					select {
+					case <-ctx.Done():
+					case out <- err:
+					}
+					continue
+				}
+
+				cfg.logf("Processing done (DocumentID=%v Duration=%v)", job.documentID, time.Since(start))
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// listen for added and replaced documents and enqueue the processing jobs
+func (svc *PostProcessor) accept(
+	ctx context.Context,
+	queue chan<- processorJob,
+	events <-chan event.Event,
+	errs <-chan error,
+	out chan<- error,
+) {
+	defer close(queue)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	enqueue := func(shelfID, documentID uuid.UUID) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case <-ctx.Done():
+			case queue <- processorJob{shelfID: shelfID, documentID: documentID}:
+			}
+		}()
+	}
+
+	streams.ForEach(
+		ctx,
+		func(evt event.Event) {
+			id, _, _ := evt.Aggregate()
+			switch data := evt.Data().(type) {
+			case DocumentAddedData:
+				enqueue(id, data.Document.ID)
+			case DocumentReplacedData:
+				enqueue(id, data.Document.ID)
+			}
+		},
+		func(err error) {
+			select {
+			case <-ctx.Done():
+			case out <- err:
+			}
+		},
+		events, errs,
+	)
+}
+
+func newProcessorConfig(opts ...PostProcessorOption) postProcessorConfig {
+	var cfg postProcessorConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.workers < 1 {
+		cfg.workers = 1
+	}
+	return cfg
+}
+
+func (cfg postProcessorConfig) logf(format string, v ...any) {
+	if cfg.logger != nil {
+		cfg.logger.Print(fmt.Sprintf(format, v...))
+	}
+}
@@ -0,0 +1,353 @@
+package document_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/color"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+)
+
+func TestShelf_StartPreview(t *testing.T) {
+	shelf, doc := newShelfWithDocument(t)
+
+	updated, err := shelf.StartPreview(doc.ID)
+	if err != nil {
+		t.Fatalf("StartPreview failed with %q", err)
+	}
+
+	if updated.Preview == nil || updated.Preview.Status != document.PreviewStatusPending {
+		t.Fatalf("Preview should have status %q; got %v", document.PreviewStatusPending, updated.Preview)
+	}
+
+	test.Change(t, shelf, document.PreviewStarted, test.EventData(document.PreviewStartedData{DocumentID: doc.ID}))
+}
+
+func TestShelf_RenderPreview(t *testing.T) {
+	shelf, doc := newShelfWithDocument(t)
+
+	img := media.NewImage(800, 600, "preview", exampleDisk, "/example/example.preview.png", 100)
+
+	updated, err := shelf.RenderPreview(doc.ID, img)
+	if err != nil {
+		t.Fatalf("RenderPreview failed with %q", err)
+	}
+
+	want := document.Preview{Status: document.PreviewStatusRendered, Image: img}
+	if updated.Preview == nil || !cmpPreview(*updated.Preview, want) {
+		t.Fatalf("Preview should be %v; got %v", want, updated.Preview)
+	}
+
+	test.Change(t, shelf, document.PreviewRendered, test.EventData(document.PreviewRenderedData{
+		DocumentID: doc.ID,
+		Image:      img,
+	}))
+}
+
+func TestShelf_FailPreview(t *testing.T) {
+	shelf, doc := newShelfWithDocument(t)
+
+	updated, err := shelf.FailPreview(doc.ID, errors.New("mock error"))
+	if err != nil {
+		t.Fatalf("FailPreview failed with %q", err)
+	}
+
+	want := document.Preview{Status: document.PreviewStatusFailed, Error: "mock error"}
+	if updated.Preview == nil || !cmpPreview(*updated.Preview, want) {
+		t.Fatalf("Preview should be %v; got %v", want, updated.Preview)
+	}
+
+	test.Change(t, shelf, document.PreviewFailed, test.EventData(document.PreviewFailedData{
+		DocumentID: doc.ID,
+		Error:      "mock error",
+	}))
+}
+
+func TestShelf_StartPreview_notFound(t *testing.T) {
+	shelf, _ := newShelfWithDocument(t)
+
+	if _, err := shelf.StartPreview(uuid.New()); !errors.Is(err, document.ErrNotFound) {
+		t.Fatalf("StartPreview should fail with %q; got %q", document.ErrNotFound, err)
+	}
+}
+
+func TestPostProcessor_Process(t *testing.T) {
+	ctx := context.Background()
+	storage, shelfs := newPreviewServices()
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+	doc, err := shelf.Add(ctx, storage, bytes.NewReader([]byte("foo")), "", exampleName, exampleDisk, "/example/example.docx")
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save shelf: %v", err)
+	}
+
+	_, preview := imggen.ColoredRectangle(80, 60, color.Black)
+	renderer := mockRenderer{
+		ext: ".docx",
+		render: func(w io.Writer, r io.Reader) error {
+			if b, _ := io.ReadAll(r); string(b) != "foo" {
+				t.Errorf("Renderer should receive the document content %q; got %q", "foo", b)
+			}
+			_, err := io.Copy(w, preview)
+			return err
+		},
+	}
+
+	svc := document.NewPostProcessor(renderer, storage, shelfs)
+
+	if err := svc.Process(ctx, shelf.ID, doc.ID); err != nil {
+		t.Fatalf("Process failed with %q", err)
+	}
+
+	doc = fetchDocument(t, shelfs, shelf.ID, doc.ID)
+
+	if doc.Preview == nil || doc.Preview.Status != document.PreviewStatusRendered {
+		t.Fatalf("Preview should have status %q; got %v", document.PreviewStatusRendered, doc.Preview)
+	}
+
+	img := doc.Preview.Image
+	if img.Width != 80 || img.Height != 60 {
+		t.Fatalf("preview should be 80x60; is %dx%d", img.Width, img.Height)
+	}
+
+	if img.Path != "/example/example.preview.png" {
+		t.Fatalf("preview should be stored at %q; got %q", "/example/example.preview.png", img.Path)
+	}
+
+	if _, err := img.File.Download(ctx, storage); err != nil {
+		t.Fatalf("download preview: %v", err)
+	}
+}
+
+func TestPostProcessor_Process_renderError(t *testing.T) {
+	ctx := context.Background()
+	storage, shelfs := newPreviewServices()
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+	doc, err := shelf.Add(ctx, storage, bytes.NewReader([]byte("foo")), "", exampleName, exampleDisk, "/example/example.docx")
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save shelf: %v", err)
+	}
+
+	mockError := errors.New("mock error")
+	renderer := mockRenderer{
+		ext: ".docx",
+		render: func(io.Writer, io.Reader) error {
+			return mockError
+		},
+	}
+
+	svc := document.NewPostProcessor(renderer, storage, shelfs)
+
+	if err := svc.Process(ctx, shelf.ID, doc.ID); !errors.Is(err, mockError) {
+		t.Fatalf("Process should fail with %q; got %q", mockError, err)
+	}
+
+	doc = fetchDocument(t, shelfs, shelf.ID, doc.ID)
+
+	if doc.Preview == nil || doc.Preview.Status != document.PreviewStatusFailed {
+		t.Fatalf("Preview should have status %q; got %v", document.PreviewStatusFailed, doc.Preview)
+	}
+
+	if doc.Preview.Error == "" {
+		t.Fatalf("Preview should contain the rendering error")
+	}
+}
+
+func TestPostProcessor_Process_unsupported(t *testing.T) {
+	ctx := context.Background()
+	storage, shelfs := newPreviewServices()
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+	doc, err := shelf.Add(ctx, storage, newPDF(), "", exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save shelf: %v", err)
+	}
+
+	renderer := mockRenderer{
+		ext: ".docx",
+		render: func(io.Writer, io.Reader) error {
+			t.Fatal("Renderer should not be called for unsupported documents")
+			return nil
+		},
+	}
+
+	svc := document.NewPostProcessor(renderer, storage, shelfs)
+
+	if err := svc.Process(ctx, shelf.ID, doc.ID); err != nil {
+		t.Fatalf("Process failed with %q", err)
+	}
+
+	if doc = fetchDocument(t, shelfs, shelf.ID, doc.ID); doc.Preview != nil {
+		t.Fatalf("Preview should be nil; got %v", doc.Preview)
+	}
+}
+
+func TestPostProcessor_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	shelfs := document.GoesRepository(repository.New(estore))
+
+	_, preview := imggen.ColoredRectangle(80, 60, color.Black)
+	rendered := make(chan struct{})
+	renderer := mockRenderer{
+		ext: ".docx",
+		render: func(w io.Writer, r io.Reader) error {
+			defer close(rendered)
+			_, err := io.Copy(w, preview)
+			return err
+		},
+	}
+
+	svc := document.NewPostProcessor(renderer, storage, shelfs)
+
+	errs, err := svc.Run(ctx, ebus)
+	if err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+	doc, err := shelf.Add(ctx, storage, bytes.NewReader([]byte("foo")), "", exampleName, exampleDisk, "/example/example.docx")
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save shelf: %v", err)
+	}
+
+	timer := time.NewTimer(3 * time.Second)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		t.Fatal("timed out")
+	case err := <-errs:
+		t.Fatal(err)
+	case <-rendered:
+	}
+
+	for {
+		doc = fetchDocument(t, shelfs, shelf.ID, doc.ID)
+		if doc.Preview != nil && doc.Preview.Status == document.PreviewStatusRendered {
+			return
+		}
+
+		select {
+		case <-timer.C:
+			t.Fatalf("timed out; Preview is %v", doc.Preview)
+		case err := <-errs:
+			t.Fatal(err)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestLibreOffice_Supports(t *testing.T) {
+	lo := document.LibreOffice{}
+
+	for _, ext := range []string{".docx", ".xlsx", ".pptx", ".odt"} {
+		if !lo.Supports(ext) {
+			t.Errorf("LibreOffice should support %q", ext)
+		}
+	}
+
+	if lo.Supports(".pdf") {
+		t.Errorf("LibreOffice should not support %q by default", ".pdf")
+	}
+
+	lo = document.LibreOffice{Extensions: []string{".pdf"}}
+	if !lo.Supports(".pdf") || lo.Supports(".docx") {
+		t.Errorf("LibreOffice should only support the configured extensions")
+	}
+}
+
+func TestLibreOffice_Render_unsupported(t *testing.T) {
+	var buf bytes.Buffer
+	err := document.LibreOffice{}.Render(context.Background(), &buf, bytes.NewReader(nil), ".pdf")
+	if !errors.Is(err, document.ErrUnsupportedFormat) {
+		t.Fatalf("Render should fail with %q; got %q", document.ErrUnsupportedFormat, err)
+	}
+}
+
+type mockRenderer struct {
+	ext    string
+	render func(io.Writer, io.Reader) error
+}
+
+func (r mockRenderer) Supports(ext string) bool {
+	return ext == r.ext
+}
+
+func (r mockRenderer) Render(_ context.Context, w io.Writer, doc io.Reader, _ string) error {
+	return r.render(w, doc)
+}
+
+func newShelfWithDocument(t *testing.T) (*document.Shelf, document.Document) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(context.Background(), storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+
+	return shelf, doc
+}
+
+func newPreviewServices() (media.Storage, document.Repository) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelfs := document.GoesRepository(repository.New(eventstore.New()))
+	return storage, shelfs
+}
+
+func fetchDocument(t *testing.T, shelfs document.Repository, shelfID, documentID uuid.UUID) document.Document {
+	shelf, err := shelfs.Fetch(context.Background(), shelfID)
+	if err != nil {
+		t.Fatalf("fetch shelf: %v", err)
+	}
+
+	doc, err := shelf.Document(documentID)
+	if err != nil {
+		t.Fatalf("get document: %v", err)
+	}
+
+	return doc
+}
+
+func cmpPreview(a, b document.Preview) bool {
+	return a.Status == b.Status && a.Error == b.Error && a.Image.Same(b.Image.File) &&
+		a.Image.Width == b.Image.Width && a.Image.Height == b.Image.Height
+}
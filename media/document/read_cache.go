@@ -0,0 +1,164 @@
+package document
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/helper/streams"
+	"github.com/modernice/nice-cms/media/cache"
+)
+
+// ReadCache is a Repository that caches fetched Shelfs in a cache.Store, so
+// that reads don't replay the events of a Shelf on every Fetch. Save, Delete
+// and Use are passed through to the underlying Repository and invalidate the
+// cached Shelf. Run must be called to invalidate Shelfs that are changed by
+// other Repositories or application instances.
+//
+// Shelfs that are returned from the cache have no hooks registered. Use the
+// Use method to change a Shelf.
+type ReadCache struct {
+	// generation is incremented on every invalidation, so that a Fetch that
+	// raced an invalidation does not cache a stale Shelf.
+	generation uint64
+
+	repo  Repository
+	store cache.Store
+}
+
+type cachedShelf struct {
+	Version int             `json:"version"`
+	Name    string          `json:"name"`
+	State   json.RawMessage `json:"state"`
+}
+
+// NewReadCache returns a ReadCache that caches the Shelfs of repo in store.
+func NewReadCache(repo Repository, store cache.Store) *ReadCache {
+	return &ReadCache{repo: repo, store: store}
+}
+
+// Fetch returns the cached Shelf with the given UUID. If the Shelf is not
+// cached, Fetch fetches it from the underlying Repository and caches it. Errors
+// of the cache.Store are not returned; the Shelf is fetched from the underlying
+// Repository instead.
+func (c *ReadCache) Fetch(ctx context.Context, id uuid.UUID) (*Shelf, error) {
+	key := cache.Key(Aggregate, id)
+
+	if b, ok, err := c.store.Get(ctx, key); err == nil && ok {
+		if shelf, err := decodeCachedShelf(id, b); err == nil {
+			return shelf, nil
+		}
+	}
+
+	generation := atomic.LoadUint64(&c.generation)
+
+	shelf, err := c.repo.Fetch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Shelfs that don't exist are not cached.
+	if shelf.AggregateVersion() == 0 {
+		return shelf, nil
+	}
+
+	// A Set error only means that the next Fetch misses the cache.
+	if b, err := encodeCachedShelf(shelf); err == nil && atomic.LoadUint64(&c.generation) == generation {
+		c.store.Set(ctx, key, b)
+	}
+
+	return shelf, nil
+}
+
+// Save saves the Shelf into the underlying Repository and invalidates it.
+func (c *ReadCache) Save(ctx context.Context, shelf *Shelf) error {
+	if err := c.repo.Save(ctx, shelf); err != nil {
+		return err
+	}
+	return c.invalidate(ctx, shelf.ID)
+}
+
+// Delete deletes the Shelf from the underlying Repository and invalidates it.
+func (c *ReadCache) Delete(ctx context.Context, shelf *Shelf) error {
+	if err := c.repo.Delete(ctx, shelf); err != nil {
+		return err
+	}
+	return c.invalidate(ctx, shelf.ID)
+}
+
+// Use calls Use on the underlying Repository and invalidates the Shelf.
+func (c *ReadCache) Use(ctx context.Context, id uuid.UUID, fn func(*Shelf) error) error {
+	if err := c.repo.Use(ctx, id, fn); err != nil {
+		return err
+	}
+	return c.invalidate(ctx, id)
+}
+
+// Run subscribes to Shelf events in a new goroutine and returns a channel of
+// asynchronous errors that is closed when ctx is canceled. Whenever a Shelf
+// event is published, the Shelf is invalidated.
+func (c *ReadCache) Run(ctx context.Context, bus event.Bus) (<-chan error, error) {
+	events, errs, err := bus.Subscribe(ctx, Events[:]...)
+	if err != nil {
+		return nil, fmt.Errorf("subscribe to %v events: %w", Events, err)
+	}
+
+	out := make(chan error)
+
+	go func() {
+		defer close(out)
+
+		fail := func(err error) {
+			select {
+			case <-ctx.Done():
+			case out <- err:
+			}
+		}
+
+		streams.ForEach(ctx, func(evt event.Event) {
+			id, _, _ := evt.Aggregate()
+			if err := c.invalidate(ctx, id); err != nil {
+				fail(err)
+			}
+		}, fail, events, errs)
+	}()
+
+	return out, nil
+}
+
+func (c *ReadCache) invalidate(ctx context.Context, id uuid.UUID) error {
+	atomic.AddUint64(&c.generation, 1)
+	if err := c.store.Delete(ctx, cache.Key(Aggregate, id)); err != nil {
+		return fmt.Errorf("invalidate Shelf %q: %w", id, err)
+	}
+	return nil
+}
+
+func encodeCachedShelf(shelf *Shelf) ([]byte, error) {
+	state, err := shelf.MarshalSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(cachedShelf{
+		Version: shelf.AggregateVersion(),
+		Name:    shelf.Name,
+		State:   state,
+	})
+}
+
+func decodeCachedShelf(id uuid.UUID, b []byte) (*Shelf, error) {
+	var cached cachedShelf
+	if err := json.Unmarshal(b, &cached); err != nil {
+		return nil, err
+	}
+	shelf := NewShelf(id)
+	if err := shelf.UnmarshalSnapshot(cached.State); err != nil {
+		return nil, err
+	}
+	shelf.Name = cached.Name
+	shelf.SetVersion(cached.Version)
+	return shelf, nil
+}
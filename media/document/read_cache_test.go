@@ -0,0 +1,115 @@
+package document_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/media/cache"
+	"github.com/modernice/nice-cms/media/document"
+)
+
+func TestReadCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := eventbus.New()
+	store := eventstore.New()
+
+	repo := &countingRepository{Repository: document.GoesRepository(repository.New(eventstore.WithBus(store, bus)))}
+	shelfs := document.NewReadCache(repo, cache.LRU(10))
+
+	id := uuid.New()
+	// Not published, so that the creation does not race the first Fetch.
+	if err := document.GoesRepository(repository.New(store)).Use(ctx, id, func(s *document.Shelf) error {
+		return s.Create(exampleShelfName)
+	}); err != nil {
+		t.Fatalf("create Shelf: %v", err)
+	}
+
+	errs, err := shelfs.Run(ctx, bus)
+	if err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+	go func() {
+		for err := range errs {
+			panic(err)
+		}
+	}()
+
+	for i := 0; i < 3; i++ {
+		shelf, err := shelfs.Fetch(ctx, id)
+		if err != nil {
+			t.Fatalf("Fetch failed with %q", err)
+		}
+		if shelf.Name != exampleShelfName || shelf.AggregateVersion() != 1 {
+			t.Fatalf("Fetch should return Shelf %q at version %d; got %q at version %d", exampleShelfName, 1, shelf.Name, shelf.AggregateVersion())
+		}
+	}
+
+	if n := repo.count(); n != 1 {
+		t.Fatalf("Shelf should be fetched from the Repository %d time; was fetched %d times", 1, n)
+	}
+
+	// Changes through the underlying Repository are invalidated by the events.
+	if err := repo.Use(ctx, id, func(s *document.Shelf) error {
+		return s.Rename("bar")
+	}); err != nil {
+		t.Fatalf("rename Shelf: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		shelf, err := shelfs.Fetch(ctx, id)
+		if err != nil {
+			t.Fatalf("Fetch failed with %q", err)
+		}
+		if shelf.Name == "bar" && shelf.AggregateVersion() == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Shelf should be invalidated after it was renamed; got %q at version %d", shelf.Name, shelf.AggregateVersion())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReadCache_notFound(t *testing.T) {
+	repo := &countingRepository{Repository: document.GoesRepository(repository.New(eventstore.New()))}
+	shelfs := document.NewReadCache(repo, cache.LRU(10))
+
+	for i := 0; i < 2; i++ {
+		if _, err := shelfs.Fetch(context.Background(), uuid.New()); err != nil {
+			t.Fatalf("Fetch failed with %q", err)
+		}
+	}
+
+	if n := repo.count(); n != 2 {
+		t.Fatalf("Shelfs that don't exist should not be cached; Repository was called %d times", n)
+	}
+}
+
+type countingRepository struct {
+	document.Repository
+
+	mux     sync.Mutex
+	fetches int
+}
+
+func (r *countingRepository) Fetch(ctx context.Context, id uuid.UUID) (*document.Shelf, error) {
+	r.mux.Lock()
+	r.fetches++
+	r.mux.Unlock()
+	return r.Repository.Fetch(ctx, id)
+}
+
+func (r *countingRepository) count() int {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.fetches
+}
@@ -0,0 +1,44 @@
+package document
+
+import (
+	"time"
+
+	"github.com/modernice/nice-cms/internal/aggregateutil"
+)
+
+// RepositoryOption is an option for GoesRepository. HookOptions are
+// RepositoryOptions that register hooks on every fetched Shelf.
+type RepositoryOption = aggregateutil.RepositoryOption[*Shelf]
+
+// ApplyRepository registers the hook on every Shelf that is fetched by the
+// repository.
+func (opt HookOption) ApplyRepository(r *aggregateutil.Repository[*Shelf]) {
+	aggregateutil.Init(func(v *Shelf) { v.UseHooks(opt) }).ApplyRepository(r)
+}
+
+// RetryMaxTries returns a RepositoryOption that limits the number of tries of
+// Repository.Use. Default is no limit (tries are only limited by the timeout).
+func RetryMaxTries(n int) RepositoryOption {
+	return aggregateutil.RetryMaxTries[*Shelf](n)
+}
+
+// RetryBackoff returns a RepositoryOption that configures the delay between
+// the tries of Repository.Use. The delay starts at base and is doubled after
+// every retry until max is reached. If max is not greater than base, the delay
+// is constant. Default is a constant delay of 50ms.
+func RetryBackoff(base, max time.Duration) RepositoryOption {
+	return aggregateutil.RetryBackoff[*Shelf](base, max)
+}
+
+// RetryJitter returns a RepositoryOption that adds a random delay of up to d
+// to every delay between the tries of Repository.Use. Default is 100ms.
+func RetryJitter(d time.Duration) RepositoryOption {
+	return aggregateutil.RetryJitter[*Shelf](d)
+}
+
+// RetryTimeout returns a RepositoryOption that limits the total duration of
+// all tries of Repository.Use. Default is 5 seconds. Zero or less disables the
+// timeout.
+func RetryTimeout(d time.Duration) RepositoryOption {
+	return aggregateutil.RetryTimeout[*Shelf](d)
+}
@@ -0,0 +1,179 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/projector"
+)
+
+// SearchService is a projection of the Documents of all Shelfs that searches
+// Documents across Shelfs. It is thread-safe.
+type SearchService struct {
+	projector *projector.Projector
+
+	mux    sync.RWMutex
+	shelfs shelfProjection
+}
+
+// SearchQuery is a paginated query for Documents across Shelfs. A Document
+// must match every provided filter.
+type SearchQuery struct {
+	// Offset is the number of matching Documents to skip.
+	Offset int
+
+	// Limit is the maximum number of Documents to return. If Limit is 0, all
+	// matching Documents after Offset are returned.
+	Limit int
+
+	// Search filters Documents by a search term. See ForSearch.
+	Search string
+
+	// Names filters Documents by their names. See ForName.
+	Names []string
+
+	// UniqueNames filters Documents by their unique names. See ForUniqueName.
+	UniqueNames []string
+
+	// Tags filters Documents by their tags. See ForTag.
+	Tags []string
+
+	// Path is a regular expression that filters Documents by their storage
+	// paths. See ForPath.
+	Path string
+}
+
+func (q SearchQuery) options() ([]SearchOption, error) {
+	var opts []SearchOption
+	if q.Search != "" {
+		opts = append(opts, ForSearch(q.Search))
+	}
+	if len(q.Names) > 0 {
+		opts = append(opts, ForName(q.Names...))
+	}
+	if len(q.UniqueNames) > 0 {
+		opts = append(opts, ForUniqueName(q.UniqueNames...))
+	}
+	if len(q.Tags) > 0 {
+		opts = append(opts, ForTag(q.Tags...))
+	}
+	if q.Path != "" {
+		expr, err := regexp.Compile(q.Path)
+		if err != nil {
+			return nil, fmt.Errorf("compile path expression: %w", err)
+		}
+		opts = append(opts, ForPath(expr))
+	}
+	return opts, nil
+}
+
+// SearchResult is a Document that was found by a SearchService, and the Shelf
+// of the Document.
+type SearchResult struct {
+	Document
+
+	ShelfID   uuid.UUID `json:"shelfId"`
+	ShelfName string    `json:"shelfName"`
+}
+
+// SearchResults is a page of the Documents that match a SearchQuery.
+type SearchResults struct {
+	Documents []SearchResult `json:"documents"`
+
+	// Total is the number of Documents that match the SearchQuery.
+	Total int `json:"total"`
+
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// NewSearchService returns a new SearchService. The provided options configure
+// the error handling of the projection.
+func NewSearchService(opts ...projector.Option) *SearchService {
+	return &SearchService{
+		projector: projector.New(opts...),
+		shelfs:    make(shelfProjection),
+	}
+}
+
+// Search returns the page of the Documents that match q. Documents are sorted
+// by the names of their Shelfs and then by their order within the Shelf.
+// Trashed Documents are not included. Search fails if the Path of q is not a
+// valid regular expression.
+func (svc *SearchService) Search(q SearchQuery) (SearchResults, error) {
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	if q.Limit < 0 {
+		q.Limit = 0
+	}
+
+	opts, err := q.options()
+	if err != nil {
+		return SearchResults{}, err
+	}
+
+	svc.mux.RLock()
+	defer svc.mux.RUnlock()
+
+	shelfs := make([]*indexedShelf, 0, len(svc.shelfs))
+	for _, shelf := range svc.shelfs {
+		if shelf.Name != "" {
+			shelfs = append(shelfs, shelf)
+		}
+	}
+	sort.Slice(shelfs, func(i, j int) bool {
+		if shelfs[i].Name != shelfs[j].Name {
+			return shelfs[i].Name < shelfs[j].Name
+		}
+		return shelfs[i].ID.String() < shelfs[j].ID.String()
+	})
+
+	results := SearchResults{
+		Documents: []SearchResult{},
+		Offset:    q.Offset,
+		Limit:     q.Limit,
+	}
+
+	for _, shelf := range shelfs {
+		for _, doc := range shelf.Search(opts...) {
+			results.Total++
+			if results.Total <= q.Offset || (q.Limit > 0 && len(results.Documents) >= q.Limit) {
+				continue
+			}
+			results.Documents = append(results.Documents, SearchResult{
+				Document:  doc,
+				ShelfID:   shelf.ID,
+				ShelfName: shelf.Name,
+			})
+		}
+	}
+
+	return results, nil
+}
+
+// Project projects the SearchService in a new goroutine and returns a channel
+// of asynchronous errors. Failed projection jobs are handled according to the
+// projector.Policy of the SearchService.
+func (svc *SearchService) Project(ctx context.Context, bus event.Bus, store event.Store, opts ...schedule.ContinuousOption) (<-chan error, error) {
+	schedule := schedule.Continuously(bus, store, Events[:], opts...)
+	return svc.projector.Run(ctx, schedule, svc)
+}
+
+// Status returns the projection status of the SearchService.
+func (svc *SearchService) Status() projector.Status {
+	return svc.projector.Status()
+}
+
+// ApplyEvent applies aggregate events.
+func (svc *SearchService) ApplyEvent(evt event.Event) {
+	svc.mux.Lock()
+	defer svc.mux.Unlock()
+	svc.shelfs.apply(evt)
+}
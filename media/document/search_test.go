@@ -0,0 +1,135 @@
+package document_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+)
+
+func TestSearchService_Search(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	shelfs := document.GoesRepository(repository.New(estore))
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	svc := document.NewSearchService()
+
+	errs, err := svc.Project(ctx, ebus, estore)
+	if err != nil {
+		t.Fatalf("run search service: %v", err)
+	}
+	go func() {
+		for err := range errs {
+			panic(err)
+		}
+	}()
+
+	reports := newListShelf(t)
+
+	archive := document.NewShelf(uuid.New())
+	archive.Create("archive")
+	for _, name := range []string{"Invoice 2019", "Contract"} {
+		doc, err := archive.Add(ctx, storage, newPDF(), "", name, exampleDisk, "/archive/"+name+".pdf")
+		if err != nil {
+			t.Fatalf("Add failed with %q", err)
+		}
+		if _, err := archive.Tag(doc.ID, "old"); err != nil {
+			t.Fatalf("Tag failed with %q", err)
+		}
+	}
+
+	for _, shelf := range []*document.Shelf{reports, archive} {
+		if err := shelfs.Save(ctx, shelf); err != nil {
+			t.Fatalf("save Shelf: %v", err)
+		}
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	tests := map[string]struct {
+		query document.SearchQuery
+		want  []string
+		total int
+	}{
+		"all": {
+			query: document.SearchQuery{},
+			want:  []string{"Invoice 2019", "Contract", "Report 0", "Report 1", "Invoice 2", "Report 3", "Invoice 4"},
+			total: 7,
+		},
+		"search": {
+			query: document.SearchQuery{Search: "invoice"},
+			want:  []string{"Invoice 2019", "Invoice 2", "Invoice 4"},
+			total: 3,
+		},
+		"name": {
+			query: document.SearchQuery{Names: []string{"Contract", "Report 1"}},
+			want:  []string{"Contract", "Report 1"},
+			total: 2,
+		},
+		"unique name": {
+			query: document.SearchQuery{UniqueNames: []string{"doc-3"}},
+			want:  []string{"Report 3"},
+			total: 1,
+		},
+		"tags": {
+			query: document.SearchQuery{Tags: []string{"old", "even"}},
+			want:  []string{"Invoice 2019", "Contract", "Report 0", "Invoice 2", "Invoice 4"},
+			total: 5,
+		},
+		"path": {
+			query: document.SearchQuery{Path: `^/docs/[0-2]\.pdf$`},
+			want:  []string{"Report 0", "Report 1", "Invoice 2"},
+			total: 3,
+		},
+		"search and page": {
+			query: document.SearchQuery{Search: "invoice", Offset: 1, Limit: 1},
+			want:  []string{"Invoice 2"},
+			total: 3,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			results, err := svc.Search(tt.query)
+			if err != nil {
+				t.Fatalf("Search failed with %q", err)
+			}
+
+			if results.Total != tt.total {
+				t.Fatalf("Total should be %d; is %d", tt.total, results.Total)
+			}
+
+			if len(results.Documents) != len(tt.want) {
+				t.Fatalf("Search should return %d Documents; got %d", len(tt.want), len(results.Documents))
+			}
+
+			for i, res := range results.Documents {
+				if res.Name != tt.want[i] {
+					t.Fatalf("Documents[%d] should be %q; is %q", i, tt.want[i], res.Name)
+				}
+
+				shelf := reports
+				if res.ShelfID == archive.ID {
+					shelf = archive
+				}
+				if res.ShelfName != shelf.Name {
+					t.Fatalf("ShelfName of Documents[%d] should be %q; is %q", i, shelf.Name, res.ShelfName)
+				}
+			}
+		})
+	}
+
+	if _, err := svc.Search(document.SearchQuery{Path: "("}); err == nil {
+		t.Fatalf("Search should fail for an invalid path expression")
+	}
+}
@@ -9,11 +9,13 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/google/uuid"
 	"github.com/modernice/goes/aggregate"
 	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/internal/aggregateutil"
 	"github.com/modernice/nice-cms/internal/unique"
 	"github.com/modernice/nice-cms/media"
 )
@@ -40,6 +42,22 @@ var (
 
 	// ErrNotFound is returned when a Document cannot be found within a Shelf.
 	ErrNotFound = errors.New("document not found")
+
+	// ErrEmptyLocale is returned when providing a locale that is empty or
+	// contains only whitespace.
+	ErrEmptyLocale = errors.New("empty locale")
+
+	// ErrDuplicateLocale is returned when adding a variant to a Document that
+	// already has a variant for the same locale.
+	ErrDuplicateLocale = errors.New("duplicate locale")
+
+	// ErrVariantNotFound is returned when a Document has no variant for a
+	// locale.
+	ErrVariantNotFound = errors.New("variant not found")
+
+	// ErrDuplicatePath is returned when moving a Document to a storage path
+	// that is already used by another Document of the Shelf.
+	ErrDuplicatePath = errors.New("duplicate path")
 )
 
 // Repository stores and retrieves Documents.
@@ -65,6 +83,26 @@ type Shelf struct {
 
 	Name      string
 	Documents []Document
+
+	// Redirects are the previous storage paths of moved Documents.
+	Redirects []Redirect
+
+	// Trashed are the Documents in the trash of the Shelf.
+	Trashed []TrashedDocument
+
+	// LegalHold is the legal hold of the Shelf, or nil if the Shelf is not
+	// under legal hold. See SetLegalHold.
+	LegalHold *media.LegalHold
+
+	hooks hooks
+}
+
+// Redirect points from the previous storage path of a moved Document to the
+// Document.
+type Redirect struct {
+	Disk       string    `json:"disk"`
+	Path       string    `json:"path"`
+	DocumentID uuid.UUID `json:"documentId"`
 }
 
 // Document is a document in a Shelf.
@@ -76,6 +114,71 @@ type Document struct {
 	// UniqueName is the unique name of the document. UniqueName may be
 	// empty but if it is not, it should be unique.
 	UniqueName string `json:"uniqueName"`
+
+	// Variants are the language variants of the document, keyed by locale.
+	Variants map[string]media.Document `json:"variants"`
+
+	// Preview is the rendered preview of the document. Preview is nil if no
+	// preview was requested for the document yet.
+	Preview *Preview `json:"preview,omitempty"`
+
+	// Audio is the metadata of an audio document. Audio is nil if the document
+	// is not an audio file or if its metadata was not extracted yet.
+	Audio *media.AudioMetadata `json:"audio,omitempty"`
+
+	// Metadata are custom key/value fields of the document, e.g. a copyright
+	// notice or a source URL. See SetMetadata.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// LegalHold is the legal hold of the document, or nil if the document is
+	// not under legal hold.
+	LegalHold *media.LegalHold `json:"legalHold,omitempty"`
+}
+
+// Variant returns the variant of the Document for the given locale, or false
+// if the Document has no variant for locale.
+func (doc Document) Variant(locale string) (media.Document, bool) {
+	v, ok := doc.Variants[normalizeLocale(locale)]
+	return v, ok
+}
+
+// Localized returns the variant of the Document for the first of the provided
+// locales that the Document has a variant for, together with the matched
+// locale. If a Document has no variant for a regional locale (e.g. "de-at"),
+// the variant for the language (e.g. "de") is used. If no variant matches,
+// Localized falls back to the Document itself and returns an empty locale.
+func (doc Document) Localized(locales ...string) (media.Document, string) {
+	for _, locale := range locales {
+		locale = normalizeLocale(locale)
+		if locale == "" {
+			continue
+		}
+
+		if v, ok := doc.Variants[locale]; ok {
+			return v, locale
+		}
+
+		if i := strings.IndexByte(locale, '-'); i > 0 {
+			if v, ok := doc.Variants[locale[:i]]; ok {
+				return v, locale[:i]
+			}
+		}
+	}
+	return doc.Document, ""
+}
+
+// Locales returns the sorted locales of the variants of the Document.
+func (doc Document) Locales() []string {
+	locales := make([]string, 0, len(doc.Variants))
+	for locale := range doc.Variants {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
 }
 
 // NewShelf returns a new Shelf.
@@ -109,11 +212,60 @@ func (s *Shelf) Find(uniqueName string) (Document, error) {
 	return Document{}, ErrNotFound
 }
 
+// FindByChecksum returns the first Document whose file has the given SHA-256
+// checksum (see media.File.Checksum) or ErrNotFound. FindByChecksum can be used
+// to detect duplicate uploads before adding a Document.
+func (s *Shelf) FindByChecksum(checksum string) (Document, error) {
+	if checksum == "" {
+		return Document{}, ErrNotFound
+	}
+	for _, doc := range s.Documents {
+		if doc.Checksum == checksum {
+			return doc, nil
+		}
+	}
+	return Document{}, ErrNotFound
+}
+
+// Resolve returns the Document that is stored at the given disk and path. If no
+// Document is currently stored there, the Redirects of moved Documents are
+// followed. If neither a Document nor a Redirect exists for the path,
+// ErrNotFound is returned.
+func (s *Shelf) Resolve(disk, path string) (Document, error) {
+	for _, doc := range s.Documents {
+		if doc.Disk == disk && doc.Path == path {
+			return doc, nil
+		}
+	}
+	for _, r := range s.Redirects {
+		if r.Disk == disk && r.Path == path {
+			return s.Document(r.DocumentID)
+		}
+	}
+	return Document{}, ErrNotFound
+}
+
+// FindLocalized returns the variant of the Document with the provided
+// UniqueName for the first matching locale. See Document.Localized for the
+// fallback rules.
+func (s *Shelf) FindLocalized(uniqueName string, locales ...string) (media.Document, error) {
+	doc, err := s.Find(uniqueName)
+	if err != nil {
+		return media.Document{}, err
+	}
+	v, _ := doc.Localized(locales...)
+	return v, nil
+}
+
 // ApplyEvent applies aggregate events.
 func (s *Shelf) ApplyEvent(evt event.Event) {
 	switch evt.Name() {
 	case ShelfCreated:
 		s.create(evt)
+	case ShelfDeleted:
+		s.delete(evt)
+	case ShelfRenamed:
+		s.rename(evt)
 	case DocumentAdded:
 		s.addDocument(evt)
 	case DocumentReplaced:
@@ -122,6 +274,8 @@ func (s *Shelf) ApplyEvent(evt event.Event) {
 		s.removeDocument(evt)
 	case DocumentRenamed:
 		s.renameDocument(evt)
+	case DocumentMoved:
+		s.moveDocument(evt)
 	case DocumentMadeUnique:
 		s.makeUnique(evt)
 	case DocumentMadeNonUnique:
@@ -130,6 +284,32 @@ func (s *Shelf) ApplyEvent(evt event.Event) {
 		s.tag(evt)
 	case DocumentUntagged:
 		s.untag(evt)
+	case DocumentMetadataSet:
+		s.setMetadata(evt)
+	case DocumentMetadataDeleted:
+		s.deleteMetadata(evt)
+	case VariantAdded:
+		s.addVariant(evt)
+	case VariantRemoved:
+		s.removeVariant(evt)
+	case PreviewStarted:
+		s.startPreview(evt)
+	case PreviewRendered:
+		s.renderPreview(evt)
+	case PreviewFailed:
+		s.failPreview(evt)
+	case AudioExtracted:
+		s.extractAudio(evt)
+	case DocumentTrashed:
+		s.trash(evt)
+	case DocumentRestored:
+		s.restore(evt)
+	case DocumentPurged:
+		s.purge(evt)
+	case LegalHoldSet:
+		s.setLegalHold(evt)
+	case LegalHoldLifted:
+		s.liftLegalHold(evt)
 	}
 }
 
@@ -152,6 +332,28 @@ func (s *Shelf) create(evt event.Event) {
 	s.Name = data.Name
 }
 
+// Rename renames the Shelf. The name is trimmed and must not be empty. If the
+// Shelf already has the name, Rename does nothing. Rename does not check if the
+// name is used by another Shelf; use a Lookup to check the name.
+func (s *Shelf) Rename(name string) error {
+	if err := s.checkCreated(); err != nil {
+		return err
+	}
+	if name = strings.TrimSpace(name); name == "" {
+		return ErrEmptyName
+	}
+	if name == s.Name {
+		return nil
+	}
+	aggregate.NextEvent(s, ShelfRenamed, ShelfRenamedData{OldName: s.Name, Name: name})
+	return nil
+}
+
+func (s *Shelf) rename(evt event.Event) {
+	data := evt.Data().(ShelfRenamedData)
+	s.Name = data.Name
+}
+
 // Add uploads the file in r to storage, adds it as a Document to the Shelf and
 // returns the Document. If the Shelf wasn't created yet, ErrShelfNotCreated is
 // returned.
@@ -160,13 +362,26 @@ func (s *Shelf) create(evt event.Event) {
 // Documents in the Shelf. Documents with a UniqueName can be accessed by their
 // unique names. If uniqueName is already in use by another Document,
 // ErrDuplicateUniqueName is returned.
+//
+// BeforeUpload and AfterUpload hooks are called before and after the upload.
 func (s *Shelf) Add(ctx context.Context, storage media.Storage, r io.Reader, uniqueName, name, disk, path string) (Document, error) {
+	if err := s.checkCreated(); err != nil {
+		return Document{}, err
+	}
+
 	if uniqueName != "" {
 		if _, err := s.Find(uniqueName); err == nil {
 			return Document{}, ErrDuplicateUniqueName
 		}
 	}
 
+	upload := UploadInfo{UniqueName: uniqueName, Name: name, Disk: disk, Path: path}
+	for _, fn := range s.hooks.beforeUpload {
+		if err := fn(ctx, s, upload); err != nil {
+			return Document{}, err
+		}
+	}
+
 	doc, err := s.addWithID(ctx, storage, r, uniqueName, name, disk, path, uuid.New())
 	if err != nil {
 		return doc, err
@@ -174,7 +389,15 @@ func (s *Shelf) Add(ctx context.Context, storage media.Storage, r io.Reader, uni
 
 	aggregate.NextEvent(s, DocumentAdded, DocumentAddedData{Document: doc})
 
-	return s.Document(doc.ID)
+	if doc, err = s.Document(doc.ID); err != nil {
+		return doc, err
+	}
+
+	for _, fn := range s.hooks.afterUpload {
+		fn(ctx, s, doc)
+	}
+
+	return doc, nil
 }
 
 func (s *Shelf) addWithID(ctx context.Context, storage media.Storage, r io.Reader, uniqueName, name, disk, path string, id uuid.UUID) (Document, error) {
@@ -192,6 +415,7 @@ func (s *Shelf) addWithID(ctx context.Context, storage media.Storage, r io.Reade
 		Document:   doc,
 		ID:         id,
 		UniqueName: uniqueName,
+		Variants:   make(map[string]media.Document),
 	}
 
 	return sdoc, nil
@@ -199,16 +423,23 @@ func (s *Shelf) addWithID(ctx context.Context, storage media.Storage, r io.Reade
 
 func (s *Shelf) addDocument(evt event.Event) {
 	data := evt.Data().(DocumentAddedData)
+	if data.Document.Variants == nil {
+		data.Document.Variants = make(map[string]media.Document)
+	}
 	s.Documents = append(s.Documents, data.Document)
 }
 
-// Remove deletes the Document with the given UUID from storage and removes it
-// from the Shelf. If the Shelf wasn't created yet, ErrShelfNotCreated is
+// Remove deletes the Document with the given UUID and all of its variants from
+// storage and removes it from the Shelf. If the Shelf wasn't created yet, ErrShelfNotCreated is
 // returned.
 //
 // No error is returned if the Storage fails to delete the file. Instead, the
 // new `DocumentRemoved` aggregate event of the Shelf will contain the deletion
 // error.
+//
+// If the Document or the Shelf is under legal hold, ErrLegalHold is returned.
+//
+// BeforeDelete and AfterDelete hooks are called before and after the deletion.
 func (s *Shelf) Remove(ctx context.Context, storage media.Storage, id uuid.UUID) error {
 	if err := s.checkCreated(); err != nil {
 		return err
@@ -219,25 +450,55 @@ func (s *Shelf) Remove(ctx context.Context, storage media.Storage, id uuid.UUID)
 		return err
 	}
 
-	deleteError := doc.Delete(ctx, storage)
+	if err := s.checkHold(doc); err != nil {
+		return err
+	}
+
+	for _, fn := range s.hooks.beforeDelete {
+		if err := fn(ctx, s, doc); err != nil {
+			return err
+		}
+	}
 
 	data := DocumentRemovedData{Document: doc}
 
-	if deleteError != nil {
-		data.DeleteError = deleteError.Error()
+	if err := deleteFiles(ctx, storage, doc); err != nil {
+		data.DeleteError = err.Error()
 	}
 
 	aggregate.NextEvent(s, DocumentRemoved, data)
 
+	for _, fn := range s.hooks.afterDelete {
+		fn(ctx, s, doc)
+	}
+
 	return nil
 }
 
+// deleteFiles deletes the file, variants and preview of doc from storage. The
+// first deletion error is returned after all deletions were attempted.
+func deleteFiles(ctx context.Context, storage media.Storage, doc Document) error {
+	deleteError := doc.Delete(ctx, storage)
+	for _, locale := range doc.Locales() {
+		if err := doc.Variants[locale].Delete(ctx, storage); err != nil && deleteError == nil {
+			deleteError = fmt.Errorf("delete %q variant: %w", locale, err)
+		}
+	}
+	if doc.Preview != nil && doc.Preview.Status == PreviewStatusRendered {
+		if err := doc.Preview.Image.Delete(ctx, storage); err != nil && deleteError == nil {
+			deleteError = fmt.Errorf("delete preview: %w", err)
+		}
+	}
+	return deleteError
+}
+
 func (s *Shelf) removeDocument(evt event.Event) {
 	data := evt.Data().(DocumentRemovedData)
 	s.remove(data.Document.ID)
 }
 
 func (s *Shelf) remove(id uuid.UUID) {
+	s.removeRedirects(func(r Redirect) bool { return r.DocumentID == id })
 	for i, doc := range s.Documents {
 		if doc.ID == id {
 			s.Documents = append(s.Documents[:i], s.Documents[i+1:]...)
@@ -247,16 +508,23 @@ func (s *Shelf) remove(id uuid.UUID) {
 }
 
 // Replace replaces the document with the given UUID with the document in r.
+// If the Document or the Shelf is under legal hold, ErrLegalHold is returned.
 func (s *Shelf) Replace(ctx context.Context, storage media.Storage, r io.Reader, id uuid.UUID) (Document, error) {
 	doc, err := s.Document(id)
 	if err != nil {
 		return doc, err
 	}
 
+	if err := s.checkHold(doc); err != nil {
+		return doc, err
+	}
+
 	replaced, err := s.addWithID(ctx, storage, r, doc.UniqueName, doc.Name, doc.Disk, doc.Path, doc.ID)
 	if err != nil {
 		return doc, fmt.Errorf("upload document: %w", err)
 	}
+	replaced.Variants = doc.Variants
+	replaced.Metadata = doc.Metadata
 
 	aggregate.NextEvent(s, DocumentReplaced, DocumentReplacedData{Document: replaced})
 
@@ -296,10 +564,127 @@ func (s *Shelf) renameDocument(evt event.Event) {
 	s.replace(doc.ID, doc)
 }
 
+// MoveDocument moves the file of the Document with the given UUID to path on
+// the same disk. MoveDocument is a shorthand for RelocateDocument with an empty
+// disk.
+func (s *Shelf) MoveDocument(ctx context.Context, storage media.Storage, id uuid.UUID, path string) (Document, error) {
+	if path == "" {
+		return Document{}, fmt.Errorf("empty path")
+	}
+	return s.RelocateDocument(ctx, storage, id, "", path)
+}
+
+// RelocateDocument moves the file of the Document with the given UUID to the
+// given disk and path of storage. If disk is empty, the file is moved within
+// its disk; if path is empty, the file keeps its path. Variants and the preview
+// of the Document are not moved.
+//
+// The file is copied to its new location before the DocumentMoved event is
+// raised, so if copying fails, the Shelf is left unchanged. The file at the
+// previous location is deleted afterwards. If the deletion fails, no error is
+// returned. Instead, the DocumentMoved event contains the deletion error.
+//
+// If the file of the Document is content-addressed (see media.ContentAddressed)
+// and stays on its disk, only the logical path of the Document is changed and
+// storage is left untouched.
+//
+// If the new location is already used by another Document of the Shelf,
+// ErrDuplicatePath is returned. If the Document already has the given disk and
+// path, RelocateDocument does nothing.
+func (s *Shelf) RelocateDocument(ctx context.Context, storage media.Storage, id uuid.UUID, disk, path string) (Document, error) {
+	doc, err := s.Document(id)
+	if err != nil {
+		return doc, err
+	}
+
+	if disk == "" {
+		disk = doc.Disk
+	}
+
+	if path == "" {
+		path = doc.Path
+	}
+
+	if disk == doc.Disk && path == doc.Path {
+		return doc, nil
+	}
+
+	for _, other := range s.Documents {
+		if other.ID != doc.ID && other.Disk == disk && other.Path == path {
+			return doc, ErrDuplicatePath
+		}
+	}
+
+	data := DocumentMovedData{
+		DocumentID: doc.ID,
+		Disk:       disk,
+		OldPath:    doc.Path,
+		Path:       path,
+	}
+
+	relocated, err := doc.File.Relocate(ctx, storage, disk, path)
+	if err != nil {
+		return doc, err
+	}
+
+	if disk != doc.Disk {
+		data.OldDisk = doc.Disk
+		data.StoragePath = relocated.StoragePath
+	}
+
+	if err := doc.File.Delete(ctx, storage); err != nil {
+		data.DeleteError = err.Error()
+	}
+
+	aggregate.NextEvent(s, DocumentMoved, data)
+
+	return s.Document(doc.ID)
+}
+
+func (s *Shelf) moveDocument(evt event.Event) {
+	data := evt.Data().(DocumentMovedData)
+	doc, err := s.Document(data.DocumentID)
+	if err != nil {
+		return
+	}
+	doc.Path = data.Path
+
+	oldDisk := data.Disk
+	if data.OldDisk != "" {
+		oldDisk = data.OldDisk
+		doc.Disk = data.Disk
+		doc.StoragePath = data.StoragePath
+	}
+	s.replace(doc.ID, doc)
+
+	s.removeRedirects(func(r Redirect) bool {
+		return (r.Disk == data.Disk && r.Path == data.Path) || (r.Disk == oldDisk && r.Path == data.OldPath)
+	})
+	s.Redirects = append(s.Redirects, Redirect{
+		Disk:       oldDisk,
+		Path:       data.OldPath,
+		DocumentID: doc.ID,
+	})
+}
+
+func (s *Shelf) removeRedirects(fn func(Redirect) bool) {
+	redirects := s.Redirects[:0]
+	for _, r := range s.Redirects {
+		if !fn(r) {
+			redirects = append(redirects, r)
+		}
+	}
+	s.Redirects = redirects
+}
+
+// replace replaces the Document with the given UUID. The legal hold of the
+// Document is kept because it can only be changed by SetLegalHold and
+// LiftLegalHold.
 func (s *Shelf) replace(id uuid.UUID, doc Document) {
 	doc.ID = id
 	for i, sdoc := range s.Documents {
 		if sdoc.ID == doc.ID {
+			doc.LegalHold = sdoc.LegalHold
 			s.Documents[i] = doc
 			return
 		}
@@ -442,6 +827,33 @@ func (s *Shelf) Untag(id uuid.UUID, tags ...string) (Document, error) {
 	return s.Document(doc.ID)
 }
 
+// Retag adds and removes tags of the Documents with the given UUIDs. The tags
+// in add are added before the tags in remove are removed. If one of the
+// Documents cannot be found in the Shelf, ErrNotFound is returned and none of
+// the Documents are changed.
+func (s *Shelf) Retag(ids []uuid.UUID, add, remove []string) ([]Document, error) {
+	for _, id := range ids {
+		if _, err := s.Document(id); err != nil {
+			return nil, fmt.Errorf("find Document %q: %w", id, err)
+		}
+	}
+
+	docs := make([]Document, len(ids))
+	for i, id := range ids {
+		if _, err := s.Tag(id, add...); err != nil {
+			return nil, err
+		}
+
+		doc, err := s.Untag(id, remove...)
+		if err != nil {
+			return nil, err
+		}
+		docs[i] = doc
+	}
+
+	return docs, nil
+}
+
 func (s *Shelf) untag(evt event.Event) {
 	data := evt.Data().(DocumentUntaggedData)
 	doc, err := s.Document(data.DocumentID)
@@ -452,13 +864,200 @@ func (s *Shelf) untag(evt event.Event) {
 	s.replace(doc.ID, doc)
 }
 
+// SetMetadata adds the custom metadata fields to the Document with the given
+// UUID, overwriting existing fields with the same keys. Keys and values are
+// trimmed. If a key is empty, media.ErrEmptyMetadataKey is returned.
+func (s *Shelf) SetMetadata(id uuid.UUID, fields map[string]string) (Document, error) {
+	doc, err := s.Document(id)
+	if err != nil {
+		return doc, err
+	}
+
+	fields, err = media.NormalizeMetadata(fields)
+	if err != nil {
+		return doc, err
+	}
+
+	if !media.MetadataChanged(doc.Metadata, fields) {
+		return doc, nil
+	}
+
+	aggregate.NextEvent(s, DocumentMetadataSet, DocumentMetadataSetData{
+		DocumentID: doc.ID,
+		Fields:     fields,
+	})
+
+	return s.Document(doc.ID)
+}
+
+func (s *Shelf) setMetadata(evt event.Event) {
+	data := evt.Data().(DocumentMetadataSetData)
+	doc, err := s.Document(data.DocumentID)
+	if err != nil {
+		return
+	}
+	doc.Metadata = media.WithMetadata(doc.Metadata, data.Fields)
+	s.replace(doc.ID, doc)
+}
+
+// DeleteMetadata removes the custom metadata fields with the given keys from
+// the Document with the given UUID.
+func (s *Shelf) DeleteMetadata(id uuid.UUID, keys ...string) (Document, error) {
+	doc, err := s.Document(id)
+	if err != nil {
+		return doc, err
+	}
+
+	keys = media.NormalizeMetadataKeys(keys...)
+
+	if !media.MetadataChanged(doc.Metadata, nil, keys...) {
+		return doc, nil
+	}
+
+	aggregate.NextEvent(s, DocumentMetadataDeleted, DocumentMetadataDeletedData{
+		DocumentID: doc.ID,
+		Keys:       keys,
+	})
+
+	return s.Document(doc.ID)
+}
+
+func (s *Shelf) deleteMetadata(evt event.Event) {
+	data := evt.Data().(DocumentMetadataDeletedData)
+	doc, err := s.Document(data.DocumentID)
+	if err != nil {
+		return
+	}
+	doc.Metadata = media.WithoutMetadata(doc.Metadata, data.Keys...)
+	s.replace(doc.ID, doc)
+}
+
+// AddVariant uploads the file in r to storage and adds it as the variant for
+// the given locale to the Document with the given UUID. Locales are
+// case-insensitive and "_" is treated as "-", so "de_AT" and "de-at" are the
+// same locale. If the Document already has a variant for locale,
+// ErrDuplicateLocale is returned; remove the variant first to replace it.
+func (s *Shelf) AddVariant(ctx context.Context, storage media.Storage, r io.Reader, id uuid.UUID, locale, name, disk, path string) (Document, error) {
+	if err := s.checkCreated(); err != nil {
+		return Document{}, err
+	}
+
+	doc, err := s.Document(id)
+	if err != nil {
+		return doc, err
+	}
+
+	if locale = normalizeLocale(locale); locale == "" {
+		return doc, ErrEmptyLocale
+	}
+
+	if _, ok := doc.Variants[locale]; ok {
+		return doc, ErrDuplicateLocale
+	}
+
+	variant := media.NewDocument(name, disk, path, 0)
+	if variant, err = variant.Upload(ctx, r, storage); err != nil {
+		return doc, fmt.Errorf("upload to storage: %w", err)
+	}
+
+	aggregate.NextEvent(s, VariantAdded, VariantAddedData{
+		DocumentID: doc.ID,
+		Locale:     locale,
+		Variant:    variant,
+	})
+
+	return s.Document(doc.ID)
+}
+
+func (s *Shelf) addVariant(evt event.Event) {
+	data := evt.Data().(VariantAddedData)
+	doc, err := s.Document(data.DocumentID)
+	if err != nil {
+		return
+	}
+	variants := make(map[string]media.Document, len(doc.Variants)+1)
+	for locale, v := range doc.Variants {
+		variants[locale] = v
+	}
+	variants[data.Locale] = data.Variant
+	doc.Variants = variants
+	s.replace(doc.ID, doc)
+}
+
+// RemoveVariant deletes the variant for the given locale of the Document with
+// the given UUID from storage and removes it from the Document. If the
+// Document has no variant for locale, ErrVariantNotFound is returned. If the
+// Document or the Shelf is under legal hold, ErrLegalHold is returned.
+//
+// No error is returned if the Storage fails to delete the file. Instead, the
+// new `VariantRemoved` aggregate event of the Shelf will contain the deletion
+// error.
+func (s *Shelf) RemoveVariant(ctx context.Context, storage media.Storage, id uuid.UUID, locale string) (Document, error) {
+	if err := s.checkCreated(); err != nil {
+		return Document{}, err
+	}
+
+	doc, err := s.Document(id)
+	if err != nil {
+		return doc, err
+	}
+
+	locale = normalizeLocale(locale)
+	variant, ok := doc.Variants[locale]
+	if !ok {
+		return doc, ErrVariantNotFound
+	}
+
+	if err := s.checkHold(doc); err != nil {
+		return doc, err
+	}
+
+	data := VariantRemovedData{
+		DocumentID: doc.ID,
+		Locale:     locale,
+		Variant:    variant,
+	}
+
+	if err := variant.Delete(ctx, storage); err != nil {
+		data.DeleteError = err.Error()
+	}
+
+	aggregate.NextEvent(s, VariantRemoved, data)
+
+	return s.Document(doc.ID)
+}
+
+func (s *Shelf) removeVariant(evt event.Event) {
+	data := evt.Data().(VariantRemovedData)
+	doc, err := s.Document(data.DocumentID)
+	if err != nil {
+		return
+	}
+	variants := make(map[string]media.Document, len(doc.Variants))
+	for locale, v := range doc.Variants {
+		if locale != data.Locale {
+			variants[locale] = v
+		}
+	}
+	doc.Variants = variants
+	s.replace(doc.ID, doc)
+}
+
 type snapshot struct {
-	Documents []Document `json:"documents"`
+	Documents []Document        `json:"documents"`
+	Redirects []Redirect        `json:"redirects,omitempty"`
+	Trashed   []TrashedDocument `json:"trashed,omitempty"`
+	LegalHold *media.LegalHold  `json:"legalHold,omitempty"`
 }
 
 // MarshalSnapshot implements snapshot.Marshaler.
 func (s *Shelf) MarshalSnapshot() ([]byte, error) {
-	return json.Marshal(snapshot{Documents: s.Documents})
+	return json.Marshal(snapshot{
+		Documents: s.Documents,
+		Redirects: s.Redirects,
+		Trashed:   s.Trashed,
+		LegalHold: s.LegalHold,
+	})
 }
 
 // UnmarshalSnapshot implements snapshot.Unmarshaler.
@@ -468,6 +1067,9 @@ func (s *Shelf) UnmarshalSnapshot(b []byte) error {
 		return err
 	}
 	s.Documents = snap.Documents
+	s.Redirects = snap.Redirects
+	s.Trashed = snap.Trashed
+	s.LegalHold = snap.LegalHold
 	if s.Documents == nil {
 		s.Documents = make([]Document, 0)
 	}
@@ -478,9 +1080,13 @@ func (s *Shelf) UnmarshalSnapshot(b []byte) error {
 type SearchOption func(*searchConfig)
 
 type searchConfig struct {
-	names []string
-	exprs []*regexp.Regexp
-	tags  []string
+	names       []string
+	uniqueNames []string
+	exprs       []*regexp.Regexp
+	paths       []*regexp.Regexp
+	terms       []string
+	tags        []string
+	locales     []string
 }
 
 func (cfg searchConfig) allows(doc Document) bool {
@@ -497,6 +1103,19 @@ func (cfg searchConfig) allows(doc Document) bool {
 		}
 	}
 
+	if len(cfg.uniqueNames) > 0 {
+		var found bool
+		for _, name := range cfg.uniqueNames {
+			if name == doc.UniqueName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
 	if len(cfg.exprs) > 0 {
 		var found bool
 		for _, expr := range cfg.exprs {
@@ -511,6 +1130,26 @@ func (cfg searchConfig) allows(doc Document) bool {
 		}
 	}
 
+	if len(cfg.paths) > 0 {
+		var found bool
+		for _, expr := range cfg.paths {
+			if expr.MatchString(doc.Path) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for _, term := range cfg.terms {
+		if !strings.Contains(strings.ToLower(doc.Name), term) &&
+			!strings.Contains(strings.ToLower(doc.UniqueName), term) {
+			return false
+		}
+	}
+
 	if len(cfg.tags) > 0 {
 		var found bool
 		for _, tag := range cfg.tags {
@@ -524,6 +1163,12 @@ func (cfg searchConfig) allows(doc Document) bool {
 		}
 	}
 
+	if len(cfg.locales) > 0 {
+		if _, locale := doc.Localized(cfg.locales...); locale == "" {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -535,6 +1180,15 @@ func ForName(names ...string) SearchOption {
 	}
 }
 
+// ForUniqueName returns a SearchOption that filters Documents by their unique
+// names. A Document is included in the result if it has one of the provided
+// unique names.
+func ForUniqueName(names ...string) SearchOption {
+	return func(cfg *searchConfig) {
+		cfg.uniqueNames = unique.Strings(append(cfg.uniqueNames, names...)...)
+	}
+}
+
 // ForRegexp returns a SearchOption that filters Documents by a regular
 // expression. A Document is included in the result if expr matches on either
 // the Name, UniqueName, Disk or Path of the Document.
@@ -544,6 +1198,25 @@ func ForRegexp(expr *regexp.Regexp) SearchOption {
 	}
 }
 
+// ForPath returns a SearchOption that filters Documents by their storage
+// paths. A Document is included in the result if expr matches its Path.
+func ForPath(expr *regexp.Regexp) SearchOption {
+	return func(cfg *searchConfig) {
+		cfg.paths = append(cfg.paths, expr)
+	}
+}
+
+// ForSearch returns a SearchOption that filters Documents by a search term. A
+// Document is included in the result if its Name or UniqueName contains term,
+// ignoring case. An empty term matches every Document.
+func ForSearch(term string) SearchOption {
+	return func(cfg *searchConfig) {
+		if term = strings.ToLower(strings.TrimSpace(term)); term != "" {
+			cfg.terms = append(cfg.terms, term)
+		}
+	}
+}
+
 // ForTag returns a SearchOption that filters Documents by their tags. A
 // Document is included in the result if it has at least one of the provided
 // tags.
@@ -553,6 +1226,15 @@ func ForTag(tags ...string) SearchOption {
 	}
 }
 
+// ForLocale returns a SearchOption that filters Documents by their locales. A
+// Document is included in the result if it has a variant for at least one of
+// the provided locales. See Document.Localized for how locales are matched.
+func ForLocale(locales ...string) SearchOption {
+	return func(cfg *searchConfig) {
+		cfg.locales = append(cfg.locales, locales...)
+	}
+}
+
 // Search returns the Documents in s that are allowed by the provided
 // SearchOptions.
 func (s *Shelf) Search(opts ...SearchOption) []Document {
@@ -578,42 +1260,17 @@ func (s *Shelf) checkCreated() error {
 	return nil
 }
 
-type goesRepository struct {
-	repo aggregate.Repository
-}
-
 // GoesRepository returns a Repository that uses an aggregate.Repository under
-// the hood.
-func GoesRepository(repo aggregate.Repository) Repository {
-	return &goesRepository{repo: repo}
-}
-
-func (r *goesRepository) Save(ctx context.Context, shelf *Shelf) error {
-	return r.repo.Save(ctx, shelf)
-}
-
-func (r *goesRepository) Fetch(ctx context.Context, id uuid.UUID) (*Shelf, error) {
-	shelf := NewShelf(id)
-	if err := r.repo.Fetch(ctx, shelf); err != nil {
-		return nil, fmt.Errorf("fetch Shelf %q: %w", id, err)
-	}
-	return shelf, nil
-}
-
-func (r *goesRepository) Delete(ctx context.Context, shelf *Shelf) error {
-	return r.repo.Delete(ctx, shelf)
-}
-
-func (r *goesRepository) Use(ctx context.Context, id uuid.UUID, fn func(*Shelf) error) error {
-	shelf, err := r.Fetch(ctx, id)
-	if err != nil {
-		return err
-	}
-	if err := fn(shelf); err != nil {
-		return err
-	}
-	if err := r.Save(ctx, shelf); err != nil {
-		return fmt.Errorf("save shelf: %w", err)
-	}
-	return nil
+// the hood. Repository.Use retries to save the Shelf when it was changed
+// concurrently; the retries can be tuned with the Retry options (e.g.
+// RetryMaxTries). The provided HookOptions are registered on every fetched
+// Shelf:
+//
+//	documents := document.GoesRepository(repo,
+//		document.RetryMaxTries(10),
+//		document.RetryBackoff(20*time.Millisecond, time.Second),
+//		document.AfterUpload(...),
+//	)
+func GoesRepository(repo aggregate.Repository, opts ...RepositoryOption) Repository {
+	return aggregateutil.NewRepository(repo, "shelf", NewShelf, opts...)
 }
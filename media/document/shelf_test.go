@@ -84,6 +84,57 @@ func TestShelf_Create_emptyName(t *testing.T) {
 	test.NoChange(t, shelf, document.ShelfCreated)
 }
 
+func TestShelf_Rename_notCreated(t *testing.T) {
+	shelf := document.NewShelf(uuid.New())
+
+	if err := shelf.Rename("bar"); !errors.Is(err, document.ErrShelfNotCreated) {
+		t.Fatalf("Rename should fail with %q if the Shelf wasn't created yet; got %q", document.ErrShelfNotCreated, err)
+	}
+
+	test.NoChange(t, shelf, document.ShelfRenamed)
+}
+
+func TestShelf_Rename(t *testing.T) {
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	if err := shelf.Rename(" bar "); err != nil {
+		t.Fatalf("Rename failed with %q", err)
+	}
+
+	if shelf.Name != "bar" {
+		t.Fatalf("Name should be %q; is %q", "bar", shelf.Name)
+	}
+
+	test.Change(t, shelf, document.ShelfRenamed, test.EventData(document.ShelfRenamedData{OldName: exampleShelfName, Name: "bar"}))
+}
+
+func TestShelf_Rename_emptyName(t *testing.T) {
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	if err := shelf.Rename("   "); !errors.Is(err, document.ErrEmptyName) {
+		t.Fatalf("Rename should fail with %q when provided an empty name; got %q", document.ErrEmptyName, err)
+	}
+
+	if shelf.Name != exampleShelfName {
+		t.Fatalf("Name should be %q; is %q", exampleShelfName, shelf.Name)
+	}
+
+	test.NoChange(t, shelf, document.ShelfRenamed)
+}
+
+func TestShelf_Rename_sameName(t *testing.T) {
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	if err := shelf.Rename(exampleShelfName); err != nil {
+		t.Fatalf("Rename failed with %q", err)
+	}
+
+	test.NoChange(t, shelf, document.ShelfRenamed)
+}
+
 func TestShelf_Add_notCreated(t *testing.T) {
 	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
 	shelf := document.NewShelf(uuid.New())
@@ -328,6 +379,259 @@ func TestShelf_RenameDocument(t *testing.T) {
 	}))
 }
 
+func TestShelf_MoveDocument(t *testing.T) {
+	ctx := context.Background()
+	disk := media.MemoryDisk()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, disk))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	moved, err := shelf.MoveDocument(ctx, storage, doc.ID, "/example/moved.pdf")
+	if err != nil {
+		t.Fatalf("MoveDocument failed with %q", err)
+	}
+
+	if moved.Path != "/example/moved.pdf" {
+		t.Fatalf("moved Path should be %q; is %q", "/example/moved.pdf", moved.Path)
+	}
+
+	b, err := disk.Get(ctx, "/example/moved.pdf")
+	if err != nil {
+		t.Fatalf("get moved file: %v", err)
+	}
+
+	if !bytes.Equal(b, examplePDF) {
+		t.Fatalf("moved file has wrong contents")
+	}
+
+	if _, err := disk.Get(ctx, examplePath); !errors.Is(err, media.ErrFileNotFound) {
+		t.Fatalf("file at previous path should be deleted; Get returned %v", err)
+	}
+
+	resolved, err := shelf.Resolve(exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Resolve failed with %q", err)
+	}
+
+	if resolved.ID != doc.ID {
+		t.Fatalf("Resolve should redirect the previous path to Document %q; got %q", doc.ID, resolved.ID)
+	}
+
+	test.Change(t, shelf, document.DocumentMoved, test.EventData(document.DocumentMovedData{
+		DocumentID: doc.ID,
+		Disk:       exampleDisk,
+		OldPath:    examplePath,
+		Path:       "/example/moved.pdf",
+	}))
+}
+
+func TestShelf_MoveDocument_contentAddressed(t *testing.T) {
+	ctx := context.Background()
+	disk := media.MemoryDisk()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.ContentAddressed(disk)))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	if doc.StoragePath == "" {
+		t.Fatalf("StoragePath should be set")
+	}
+
+	moved, err := shelf.MoveDocument(ctx, storage, doc.ID, "/example/moved.pdf")
+	if err != nil {
+		t.Fatalf("MoveDocument failed with %q", err)
+	}
+
+	if moved.Path != "/example/moved.pdf" {
+		t.Fatalf("moved Path should be %q; is %q", "/example/moved.pdf", moved.Path)
+	}
+
+	if moved.StoragePath != doc.StoragePath {
+		t.Fatalf("StoragePath shouldn't change; changed from %q to %q", doc.StoragePath, moved.StoragePath)
+	}
+
+	b, err := moved.Download(ctx, storage)
+	if err != nil {
+		t.Fatalf("download moved file: %v", err)
+	}
+
+	if !bytes.Equal(b, examplePDF) {
+		t.Fatalf("moved file has wrong contents")
+	}
+}
+
+func TestShelf_MoveDocument_duplicatePath(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), "", exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	if _, err := shelf.Add(ctx, storage, newPDF(), "", exampleName, exampleDisk, "/example/other.pdf"); err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	if _, err := shelf.MoveDocument(ctx, storage, doc.ID, "/example/other.pdf"); !errors.Is(err, document.ErrDuplicatePath) {
+		t.Fatalf("MoveDocument should fail with %q; got %v", document.ErrDuplicatePath, err)
+	}
+
+	test.NoChange(t, shelf, document.DocumentMoved)
+}
+
+func TestShelf_RelocateDocument(t *testing.T) {
+	ctx := context.Background()
+	disk := media.MemoryDisk()
+	archive := media.MemoryDisk()
+	storage := media.NewStorage(
+		media.ConfigureDisk(exampleDisk, disk),
+		media.ConfigureDisk("archive", archive),
+	)
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	moved, err := shelf.RelocateDocument(ctx, storage, doc.ID, "archive", "")
+	if err != nil {
+		t.Fatalf("RelocateDocument failed with %q", err)
+	}
+
+	if moved.Disk != "archive" || moved.Path != examplePath {
+		t.Fatalf("Document should be stored at %q on %q; is stored at %q on %q", examplePath, "archive", moved.Path, moved.Disk)
+	}
+
+	if b, err := archive.Get(ctx, examplePath); err != nil || !bytes.Equal(b, examplePDF) {
+		t.Fatalf("file should have been copied to the archive disk; Get returned %v", err)
+	}
+
+	if _, err := disk.Get(ctx, examplePath); !errors.Is(err, media.ErrFileNotFound) {
+		t.Fatalf("file on the previous disk should be deleted; Get returned %v", err)
+	}
+
+	resolved, err := shelf.Resolve(exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Resolve failed with %q", err)
+	}
+
+	if resolved.ID != doc.ID {
+		t.Fatalf("Resolve should redirect the previous location to Document %q; got %q", doc.ID, resolved.ID)
+	}
+
+	test.Change(t, shelf, document.DocumentMoved, test.EventData(document.DocumentMovedData{
+		DocumentID: doc.ID,
+		Disk:       "archive",
+		OldPath:    examplePath,
+		Path:       examplePath,
+		OldDisk:    exampleDisk,
+	}))
+
+	if _, err := shelf.Add(ctx, storage, newPDF(), "", exampleName, exampleDisk, examplePath); err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	if _, err := shelf.RelocateDocument(ctx, storage, doc.ID, exampleDisk, ""); !errors.Is(err, document.ErrDuplicatePath) {
+		t.Fatalf("RelocateDocument should fail with %q; got %v", document.ErrDuplicatePath, err)
+	}
+}
+
+func TestShelf_RelocateDocument_contentAddressed(t *testing.T) {
+	ctx := context.Background()
+	disk := media.MemoryDisk()
+	storage := media.NewStorage(
+		media.ConfigureDisk(exampleDisk, disk),
+		media.ConfigureDisk("content", media.ContentAddressed(media.MemoryDisk())),
+	)
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	moved, err := shelf.RelocateDocument(ctx, storage, doc.ID, "content", "/example/moved.pdf")
+	if err != nil {
+		t.Fatalf("RelocateDocument failed with %q", err)
+	}
+
+	if moved.StoragePath == "" {
+		t.Fatalf("StoragePath should be set after moving to a content-addressed disk")
+	}
+
+	b, err := moved.Download(ctx, storage)
+	if err != nil {
+		t.Fatalf("download moved file: %v", err)
+	}
+
+	if !bytes.Equal(b, examplePDF) {
+		t.Fatalf("moved file has wrong contents")
+	}
+
+	back, err := shelf.RelocateDocument(ctx, storage, doc.ID, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("RelocateDocument failed with %q", err)
+	}
+
+	if back.StoragePath != "" {
+		t.Fatalf("StoragePath should be cleared after moving to a plain disk; is %q", back.StoragePath)
+	}
+
+	if b, err := disk.Get(ctx, examplePath); err != nil || !bytes.Equal(b, examplePDF) {
+		t.Fatalf("file should have been copied back to %q; Get returned %v", examplePath, err)
+	}
+}
+
+func TestSlugPath(t *testing.T) {
+	tests := []struct {
+		dir  string
+		doc  document.Document
+		want string
+	}{
+		{
+			doc:  document.Document{Document: media.NewDocument("Annual Report 2021", exampleDisk, "/docs/abc.pdf", 0)},
+			want: "/docs/annual-report-2021.pdf",
+		},
+		{
+			doc: document.Document{
+				Document:   media.NewDocument("Report.pdf", exampleDisk, "/docs/abc.pdf", 0),
+				UniqueName: "Über uns",
+			},
+			want: "/docs/über-uns.pdf",
+		},
+		{
+			dir:  "/reports",
+			doc:  document.Document{Document: media.NewDocument("Report.pdf", exampleDisk, "/docs/abc.pdf", 0)},
+			want: "/reports/report.pdf",
+		},
+		{
+			doc:  document.Document{Document: media.NewDocument("!!!", exampleDisk, "/docs/abc.pdf", 0)},
+			want: "/docs/abc.pdf",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := document.SlugPath(tt.dir)(tt.doc); got != tt.want {
+			t.Fatalf("SlugPath(%q) should return %q for %q; got %q", tt.dir, tt.want, tt.doc.Name, got)
+		}
+	}
+}
+
 func TestShelf_MakeUnique(t *testing.T) {
 	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
 	shelf := document.NewShelf(uuid.New())
@@ -535,6 +839,121 @@ func TestShelf_Tag_Untag(t *testing.T) {
 	}))
 }
 
+func TestShelf_Retag(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	var ids []uuid.UUID
+	for _, path := range []string{"/foo.pdf", "/bar.pdf"} {
+		doc, err := shelf.Add(ctx, storage, newPDF(), "", exampleName, exampleDisk, path)
+		if err != nil {
+			t.Fatalf("Add failed with %q", err)
+		}
+		ids = append(ids, doc.ID)
+	}
+
+	if _, err := shelf.Tag(ids[0], "draft"); err != nil {
+		t.Fatalf("Tag failed with %q", err)
+	}
+
+	docs, err := shelf.Retag(ids, []string{"final", "2024"}, []string{"draft"})
+	if err != nil {
+		t.Fatalf("Retag failed with %q", err)
+	}
+
+	if len(docs) != 2 {
+		t.Fatalf("Retag should return %d Documents; got %d", 2, len(docs))
+	}
+
+	for i, doc := range docs {
+		if doc.ID != ids[i] {
+			t.Fatalf("Retag should return the Documents in order; got %q at index %d", doc.ID, i)
+		}
+		if !doc.HasTag("final", "2024") || doc.HasTag("draft") {
+			t.Fatalf("Document should have tags %v; has %v", []string{"final", "2024"}, doc.Tags)
+		}
+	}
+
+	changes := len(shelf.AggregateChanges())
+	if _, err := shelf.Retag([]uuid.UUID{ids[0], uuid.New()}, []string{"foo"}, nil); !errors.Is(err, document.ErrNotFound) {
+		t.Fatalf("Retag should fail with %q for an unknown Document; got %q", document.ErrNotFound, err)
+	}
+
+	if len(shelf.AggregateChanges()) != changes {
+		t.Fatalf("Retag should not change the Shelf if a Document cannot be found")
+	}
+}
+
+func TestShelf_SetMetadata_DeleteMetadata(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	doc, err = shelf.SetMetadata(doc.ID, map[string]string{" copyright ": " ACME ", "license": "CC-BY"})
+	if err != nil {
+		t.Fatalf("SetMetadata failed with %q", err)
+	}
+
+	want := map[string]string{"copyright": "ACME", "license": "CC-BY"}
+	if !reflect.DeepEqual(doc.Metadata, want) {
+		t.Fatalf("Metadata should be %v; is %v", want, doc.Metadata)
+	}
+
+	test.Change(t, shelf, document.DocumentMetadataSet, test.EventData(document.DocumentMetadataSetData{
+		DocumentID: doc.ID,
+		Fields:     want,
+	}))
+
+	if _, err := shelf.SetMetadata(doc.ID, map[string]string{"license": "CC-BY"}); err != nil {
+		t.Fatalf("SetMetadata failed with %q", err)
+	}
+	test.Change(t, shelf, document.DocumentMetadataSet, test.Exactly(1))
+
+	if _, err := shelf.SetMetadata(doc.ID, map[string]string{" ": "foo"}); !errors.Is(err, media.ErrEmptyMetadataKey) {
+		t.Fatalf("SetMetadata should fail with %q for an empty key; got %q", media.ErrEmptyMetadataKey, err)
+	}
+
+	replaced, err := shelf.Replace(ctx, storage, newPDF(), doc.ID)
+	if err != nil {
+		t.Fatalf("Replace failed with %q", err)
+	}
+	if !reflect.DeepEqual(replaced.Metadata, want) {
+		t.Fatalf("Replace should keep the Metadata %v; got %v", want, replaced.Metadata)
+	}
+
+	doc, err = shelf.DeleteMetadata(doc.ID, "copyright", "unknown")
+	if err != nil {
+		t.Fatalf("DeleteMetadata failed with %q", err)
+	}
+
+	want = map[string]string{"license": "CC-BY"}
+	if !reflect.DeepEqual(doc.Metadata, want) {
+		t.Fatalf("Metadata should be %v; is %v", want, doc.Metadata)
+	}
+
+	test.Change(t, shelf, document.DocumentMetadataDeleted, test.EventData(document.DocumentMetadataDeletedData{
+		DocumentID: doc.ID,
+		Keys:       []string{"copyright", "unknown"},
+	}))
+
+	if _, err := shelf.DeleteMetadata(doc.ID, "unknown"); err != nil {
+		t.Fatalf("DeleteMetadata failed with %q", err)
+	}
+	test.Change(t, shelf, document.DocumentMetadataDeleted, test.Exactly(1))
+
+	if _, err := shelf.SetMetadata(uuid.New(), want); !errors.Is(err, document.ErrNotFound) {
+		t.Fatalf("SetMetadata should fail with %q for an unknown Document; got %q", document.ErrNotFound, err)
+	}
+}
+
 func TestShelf_Search(t *testing.T) {
 	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
 	shelf := document.NewShelf(uuid.New())
@@ -593,6 +1012,213 @@ func TestShelf_Search(t *testing.T) {
 	}
 }
 
+func TestShelf_AddVariant(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(context.Background(), storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	variantPath := "/example/example.de.pdf"
+	doc, err = shelf.AddVariant(context.Background(), storage, newPDF2(), doc.ID, "DE", "Beispieldokument", exampleDisk, variantPath)
+	if err != nil {
+		t.Fatalf("AddVariant shouldn't fail; failed with %q", err)
+	}
+
+	variant, ok := doc.Variant("de")
+	if !ok {
+		t.Fatalf("Document should have a %q variant", "de")
+	}
+
+	if variant.Path != variantPath {
+		t.Fatalf("Path of variant should be %q; is %q", variantPath, variant.Path)
+	}
+
+	if variant.Filesize != len(examplePDF2) {
+		t.Fatalf("Filesize of variant should be %d; is %d", len(examplePDF2), variant.Filesize)
+	}
+
+	disk, _ := storage.Disk(exampleDisk)
+	b, err := disk.Get(context.Background(), variantPath)
+	if err != nil {
+		t.Fatalf("variant should be uploaded to storage: %v", err)
+	}
+
+	if !bytes.Equal(b, examplePDF2) {
+		t.Fatalf("storage returned wrong variant contents")
+	}
+
+	test.Change(t, shelf, document.VariantAdded, test.EventData(document.VariantAddedData{
+		DocumentID: doc.ID,
+		Locale:     "de",
+		Variant:    variant,
+	}))
+
+	if _, err := shelf.AddVariant(context.Background(), storage, newPDF2(), doc.ID, "de", "Beispieldokument", exampleDisk, variantPath); !errors.Is(err, document.ErrDuplicateLocale) {
+		t.Fatalf("AddVariant should fail with %q for an existing locale; got %q", document.ErrDuplicateLocale, err)
+	}
+
+	if _, err := shelf.AddVariant(context.Background(), storage, newPDF2(), doc.ID, " ", "Beispieldokument", exampleDisk, variantPath); !errors.Is(err, document.ErrEmptyLocale) {
+		t.Fatalf("AddVariant should fail with %q for an empty locale; got %q", document.ErrEmptyLocale, err)
+	}
+}
+
+func TestShelf_RemoveVariant(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(context.Background(), storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	variantPath := "/example/example.de.pdf"
+	doc, err = shelf.AddVariant(context.Background(), storage, newPDF2(), doc.ID, "de", "Beispieldokument", exampleDisk, variantPath)
+	if err != nil {
+		t.Fatalf("AddVariant failed with %q", err)
+	}
+	variant, _ := doc.Variant("de")
+
+	if doc, err = shelf.RemoveVariant(context.Background(), storage, doc.ID, "de"); err != nil {
+		t.Fatalf("RemoveVariant shouldn't fail; failed with %q", err)
+	}
+
+	if _, ok := doc.Variant("de"); ok {
+		t.Fatalf("Document should not have a %q variant anymore", "de")
+	}
+
+	disk, _ := storage.Disk(exampleDisk)
+	if _, err := disk.Get(context.Background(), variantPath); err == nil {
+		t.Fatalf("variant should be deleted from storage")
+	}
+
+	test.Change(t, shelf, document.VariantRemoved, test.EventData(document.VariantRemovedData{
+		DocumentID: doc.ID,
+		Locale:     "de",
+		Variant:    variant,
+	}))
+
+	if _, err := shelf.RemoveVariant(context.Background(), storage, doc.ID, "de"); !errors.Is(err, document.ErrVariantNotFound) {
+		t.Fatalf("RemoveVariant should fail with %q for a missing variant; got %q", document.ErrVariantNotFound, err)
+	}
+}
+
+func TestShelf_Remove_variants(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(context.Background(), storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	variantPath := "/example/example.de.pdf"
+	if _, err = shelf.AddVariant(context.Background(), storage, newPDF2(), doc.ID, "de", "Beispieldokument", exampleDisk, variantPath); err != nil {
+		t.Fatalf("AddVariant failed with %q", err)
+	}
+
+	if err := shelf.Remove(context.Background(), storage, doc.ID); err != nil {
+		t.Fatalf("Remove shouldn't fail; failed with %q", err)
+	}
+
+	disk, _ := storage.Disk(exampleDisk)
+	if _, err := disk.Get(context.Background(), variantPath); err == nil {
+		t.Fatalf("variant should be deleted from storage")
+	}
+}
+
+func TestShelf_FindLocalized(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(context.Background(), storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	for _, locale := range []string{"de", "fr"} {
+		if _, err := shelf.AddVariant(context.Background(), storage, newPDF2(), doc.ID, locale, exampleName, exampleDisk, "/example/example."+locale+".pdf"); err != nil {
+			t.Fatalf("AddVariant failed with %q", err)
+		}
+	}
+
+	tests := []struct {
+		locales  []string
+		wantPath string
+	}{
+		{nil, examplePath},
+		{[]string{"de"}, "/example/example.de.pdf"},
+		{[]string{"de_AT"}, "/example/example.de.pdf"},
+		{[]string{"es", "fr"}, "/example/example.fr.pdf"},
+		{[]string{"es"}, examplePath},
+	}
+
+	for _, tt := range tests {
+		localized, err := shelf.FindLocalized(exampleUniqueName, tt.locales...)
+		if err != nil {
+			t.Fatalf("FindLocalized shouldn't fail; failed with %q", err)
+		}
+
+		if localized.Path != tt.wantPath {
+			t.Fatalf("FindLocalized(%v) should return %q; got %q", tt.locales, tt.wantPath, localized.Path)
+		}
+	}
+
+	if docs := shelf.Search(document.ForLocale("fr")); len(docs) != 1 {
+		t.Fatalf("Search should return 1 Document for locale %q; got %d", "fr", len(docs))
+	}
+
+	if docs := shelf.Search(document.ForLocale("es")); len(docs) != 0 {
+		t.Fatalf("Search should return no Documents for locale %q; got %d", "es", len(docs))
+	}
+}
+
+func TestShelf_FindByChecksum(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(context.Background(), storage, newPDF(), "", exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+
+	checksum, err := media.Checksum(newPDF())
+	if err != nil {
+		t.Fatalf("compute checksum: %v", err)
+	}
+
+	if doc.Checksum != checksum {
+		t.Fatalf("Checksum should be %q; is %q", checksum, doc.Checksum)
+	}
+
+	found, err := shelf.FindByChecksum(checksum)
+	if err != nil {
+		t.Fatalf("FindByChecksum shouldn't fail; failed with %q", err)
+	}
+
+	if found.ID != doc.ID {
+		t.Fatalf("FindByChecksum should return Document %q; got %q", doc.ID, found.ID)
+	}
+
+	other, err := media.Checksum(newPDF2())
+	if err != nil {
+		t.Fatalf("compute checksum: %v", err)
+	}
+
+	for _, checksum := range []string{"", other} {
+		if _, err := shelf.FindByChecksum(checksum); !errors.Is(err, document.ErrNotFound) {
+			t.Fatalf("FindByChecksum(%q) should fail with %q; got %q", checksum, document.ErrNotFound, err)
+		}
+	}
+}
+
 func newPDF() *bytes.Reader {
 	return bytes.NewReader(examplePDF)
 }
@@ -0,0 +1,353 @@
+package document
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/helper/streams"
+	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/projector"
+)
+
+const (
+	// DefaultRetention is the default time that trashed Documents are kept
+	// before they are purged by a Janitor.
+	DefaultRetention = 30 * 24 * time.Hour
+
+	// DefaultJanitorInterval is the default interval in which a Janitor purges
+	// expired Documents.
+	DefaultJanitorInterval = time.Hour
+)
+
+// ErrNotTrashed is returned when a Document cannot be found in the trash of a
+// Shelf.
+var ErrNotTrashed = errors.New("document not in trash")
+
+// TrashedDocument is a Document in the trash of a Shelf.
+type TrashedDocument struct {
+	Document
+
+	TrashedAt time.Time `json:"trashedAt"`
+}
+
+// Expired returns whether the retention period of the TrashedDocument has
+// passed at the given time.
+func (doc TrashedDocument) Expired(retention time.Duration, now time.Time) bool {
+	return !now.Before(doc.TrashedAt.Add(retention))
+}
+
+// TrashedDocument returns the Document with the given UUID from the trash of
+// the Shelf, or ErrNotTrashed.
+func (s *Shelf) TrashedDocument(id uuid.UUID) (TrashedDocument, error) {
+	for _, doc := range s.Trashed {
+		if doc.ID == id {
+			return doc, nil
+		}
+	}
+	return TrashedDocument{}, ErrNotTrashed
+}
+
+// Trash moves the Document with the given UUID to the trash of the Shelf. The
+// files of the Document are kept until the Document is purged, so that it can
+// be restored using Restore. Trashed Documents are not part of s.Documents and
+// their UniqueNames may be used by other Documents. If the Document or the
+// Shelf is under legal hold, ErrLegalHold is returned.
+func (s *Shelf) Trash(id uuid.UUID) (TrashedDocument, error) {
+	if err := s.checkCreated(); err != nil {
+		return TrashedDocument{}, err
+	}
+
+	doc, err := s.Document(id)
+	if err != nil {
+		return TrashedDocument{}, err
+	}
+
+	if err := s.checkHold(doc); err != nil {
+		return TrashedDocument{}, err
+	}
+
+	aggregate.NextEvent(s, DocumentTrashed, DocumentTrashedData{Document: doc})
+
+	return s.TrashedDocument(id)
+}
+
+func (s *Shelf) trash(evt event.Event) {
+	data := evt.Data().(DocumentTrashedData)
+	for i, doc := range s.Documents {
+		if doc.ID == data.Document.ID {
+			s.Documents = append(s.Documents[:i], s.Documents[i+1:]...)
+			break
+		}
+	}
+	s.Trashed = append(s.Trashed, TrashedDocument{
+		Document:  data.Document,
+		TrashedAt: evt.Time(),
+	})
+}
+
+// Restore moves the Document with the given UUID from the trash back to the
+// Documents of the Shelf. If the Document is not in the trash, ErrNotTrashed is
+// returned. If the UniqueName of the Document was taken by another Document
+// in the meantime, ErrDuplicateUniqueName is returned.
+func (s *Shelf) Restore(id uuid.UUID) (Document, error) {
+	if err := s.checkCreated(); err != nil {
+		return Document{}, err
+	}
+
+	trashed, err := s.TrashedDocument(id)
+	if err != nil {
+		return Document{}, err
+	}
+
+	if trashed.UniqueName != "" {
+		if _, err := s.Find(trashed.UniqueName); err == nil {
+			return trashed.Document, ErrDuplicateUniqueName
+		}
+	}
+
+	aggregate.NextEvent(s, DocumentRestored, DocumentRestoredData{Document: trashed.Document})
+
+	return s.Document(id)
+}
+
+func (s *Shelf) restore(evt event.Event) {
+	data := evt.Data().(DocumentRestoredData)
+	s.removeTrashed(data.Document.ID)
+	s.Documents = append(s.Documents, data.Document)
+}
+
+// Purge deletes the Document with the given UUID and all of its variants from
+// storage and removes it from the trash of the Shelf. If the Document is not in
+// the trash, ErrNotTrashed is returned. If the Document or the Shelf is under
+// legal hold, ErrLegalHold is returned.
+//
+// No error is returned if the Storage fails to delete the files. Instead, the
+// new `DocumentPurged` aggregate event of the Shelf will contain the deletion
+// error.
+//
+// BeforeDelete and AfterDelete hooks are called before and after the deletion.
+func (s *Shelf) Purge(ctx context.Context, storage media.Storage, id uuid.UUID) error {
+	if err := s.checkCreated(); err != nil {
+		return err
+	}
+
+	trashed, err := s.TrashedDocument(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkHold(trashed.Document); err != nil {
+		return err
+	}
+
+	for _, fn := range s.hooks.beforeDelete {
+		if err := fn(ctx, s, trashed.Document); err != nil {
+			return err
+		}
+	}
+
+	data := DocumentPurgedData{Document: trashed.Document}
+	if err := deleteFiles(ctx, storage, trashed.Document); err != nil {
+		data.DeleteError = err.Error()
+	}
+
+	aggregate.NextEvent(s, DocumentPurged, data)
+
+	for _, fn := range s.hooks.afterDelete {
+		fn(ctx, s, trashed.Document)
+	}
+
+	return nil
+}
+
+func (s *Shelf) purge(evt event.Event) {
+	data := evt.Data().(DocumentPurgedData)
+	s.removeTrashed(data.Document.ID)
+	s.removeRedirects(func(r Redirect) bool { return r.DocumentID == data.Document.ID })
+}
+
+func (s *Shelf) removeTrashed(id uuid.UUID) {
+	for i, doc := range s.Trashed {
+		if doc.ID == id {
+			s.Trashed = append(s.Trashed[:i], s.Trashed[i+1:]...)
+			return
+		}
+	}
+}
+
+// Expired returns the Documents in the trash of the Shelf whose retention
+// period has passed at the given time.
+func (s *Shelf) Expired(retention time.Duration, now time.Time) []TrashedDocument {
+	var out []TrashedDocument
+	for _, doc := range s.Trashed {
+		if doc.Expired(retention, now) {
+			out = append(out, doc)
+		}
+	}
+	return out
+}
+
+// JanitorOption is an option for a Janitor.
+type JanitorOption func(*Janitor)
+
+// Retention returns a JanitorOption that sets the time that trashed Documents
+// are kept before they are purged. Default is DefaultRetention.
+func Retention(d time.Duration) JanitorOption {
+	return func(j *Janitor) {
+		j.retention = d
+	}
+}
+
+// JanitorInterval returns a JanitorOption that sets the interval in which the
+// Janitor purges expired Documents. Default is DefaultJanitorInterval.
+func JanitorInterval(d time.Duration) JanitorOption {
+	return func(j *Janitor) {
+		j.interval = d
+	}
+}
+
+// JanitorProjection returns a JanitorOption that configures the error handling
+// of the projection of the Janitor.
+func JanitorProjection(opts ...projector.Option) JanitorOption {
+	return func(j *Janitor) {
+		j.projectorOpts = append(j.projectorOpts, opts...)
+	}
+}
+
+// Janitor purges trashed Documents after their retention period. The Janitor
+// projects the trash of all Shelfs, so that it doesn't have to fetch every
+// Shelf to find expired Documents. Janitor is thread-safe.
+type Janitor struct {
+	shelfs        Repository
+	storage       media.Storage
+	retention     time.Duration
+	interval      time.Duration
+	projector     *projector.Projector
+	projectorOpts []projector.Option
+
+	mux     sync.RWMutex
+	trashed map[documentRef]time.Time
+}
+
+// NewJanitor returns a Janitor that purges Documents of the Shelfs in the
+// provided Repository from storage.
+func NewJanitor(shelfs Repository, storage media.Storage, opts ...JanitorOption) *Janitor {
+	j := &Janitor{
+		shelfs:    shelfs,
+		storage:   storage,
+		retention: DefaultRetention,
+		interval:  DefaultJanitorInterval,
+		trashed:   make(map[documentRef]time.Time),
+	}
+	for _, opt := range opts {
+		opt(j)
+	}
+	j.projector = projector.New(j.projectorOpts...)
+	return j
+}
+
+// Run projects the trash of all Shelfs and purges expired Documents in the
+// configured interval until ctx is canceled. Run returns a channel of
+// asynchronous projection and purge errors.
+func (j *Janitor) Run(ctx context.Context, bus event.Bus, store event.Store, opts ...schedule.ContinuousOption) (<-chan error, error) {
+	schedule := schedule.Continuously(bus, store, []string{
+		DocumentTrashed,
+		DocumentRestored,
+		DocumentPurged,
+		ShelfDeleted,
+	}, opts...)
+
+	projectionErrors, err := j.projector.Run(ctx, schedule, j)
+	if err != nil {
+		return nil, err
+	}
+
+	cleanupErrors := make(chan error)
+	go j.cleanup(ctx, cleanupErrors)
+
+	return streams.FanInContext(ctx, projectionErrors, cleanupErrors), nil
+}
+
+// Status returns the projection status of the Janitor.
+func (j *Janitor) Status() projector.Status {
+	return j.projector.Status()
+}
+
+// ApplyEvent applies aggregate events.
+func (j *Janitor) ApplyEvent(evt event.Event) {
+	shelfID, _, _ := evt.Aggregate()
+
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	switch data := evt.Data().(type) {
+	case DocumentTrashedData:
+		j.trashed[documentRef{shelfID: shelfID, documentID: data.Document.ID}] = evt.Time()
+	case DocumentRestoredData:
+		delete(j.trashed, documentRef{shelfID: shelfID, documentID: data.Document.ID})
+	case DocumentPurgedData:
+		delete(j.trashed, documentRef{shelfID: shelfID, documentID: data.Document.ID})
+	case ShelfDeletedData:
+		for _, id := range data.Documents {
+			delete(j.trashed, documentRef{shelfID: shelfID, documentID: id})
+		}
+	}
+}
+
+// Cleanup purges the Documents whose retention period has passed.
+func (j *Janitor) Cleanup(ctx context.Context) error {
+	now := time.Now()
+
+	j.mux.RLock()
+	var expired []documentRef
+	for ref, trashedAt := range j.trashed {
+		if !now.Before(trashedAt.Add(j.retention)) {
+			expired = append(expired, ref)
+		}
+	}
+	j.mux.RUnlock()
+
+	for _, ref := range expired {
+		if err := j.shelfs.Use(ctx, ref.shelfID, func(s *Shelf) error {
+			// The Document may have been restored or purged since the last
+			// projection. Held Documents are purged once the hold is lifted.
+			doc, err := s.TrashedDocument(ref.documentID)
+			if err != nil || !doc.Expired(j.retention, now) || s.Held(doc.ID) {
+				return nil
+			}
+			return s.Purge(ctx, j.storage, ref.documentID)
+		}); err != nil {
+			return fmt.Errorf("purge document %s of shelf %s: %w", ref.documentID, ref.shelfID, err)
+		}
+	}
+
+	return nil
+}
+
+func (j *Janitor) cleanup(ctx context.Context, out chan<- error) {
+	defer close(out)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := j.Cleanup(ctx); err != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case out <- err:
+				}
+			}
+		}
+	}
+}
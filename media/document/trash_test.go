@@ -0,0 +1,224 @@
+package document_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/command/cmdbus"
+	"github.com/modernice/goes/command/cmdbus/dispatch"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/internal/commands"
+	"github.com/modernice/nice-cms/internal/discard"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+)
+
+func TestShelf_Trash(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	trashed, err := shelf.Trash(doc.ID)
+	if err != nil {
+		t.Fatalf("Trash failed with %q", err)
+	}
+
+	if trashed.ID != doc.ID || trashed.TrashedAt.IsZero() {
+		t.Fatalf("Trash should return the trashed Document; got %v", trashed)
+	}
+
+	if _, err := shelf.Document(doc.ID); !errors.Is(err, document.ErrNotFound) {
+		t.Fatalf("Document should return %q for a trashed Document; got %q", document.ErrNotFound, err)
+	}
+
+	disk, _ := storage.Disk(exampleDisk)
+	if _, err := disk.Get(ctx, doc.Path); err != nil {
+		t.Fatalf("file of a trashed Document should not be deleted; Get returned %q", err)
+	}
+
+	test.Change(t, shelf, document.DocumentTrashed, test.EventData(document.DocumentTrashedData{Document: doc}))
+
+	if _, err := shelf.Trash(doc.ID); !errors.Is(err, document.ErrNotFound) {
+		t.Fatalf("Trash should fail with %q for a trashed Document; got %q", document.ErrNotFound, err)
+	}
+}
+
+func TestShelf_Restore(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	if _, err := shelf.Restore(doc.ID); !errors.Is(err, document.ErrNotTrashed) {
+		t.Fatalf("Restore should fail with %q for an untrashed Document; got %q", document.ErrNotTrashed, err)
+	}
+
+	if _, err := shelf.Trash(doc.ID); err != nil {
+		t.Fatalf("Trash failed with %q", err)
+	}
+
+	restored, err := shelf.Restore(doc.ID)
+	if err != nil {
+		t.Fatalf("Restore failed with %q", err)
+	}
+
+	if restored.ID != doc.ID || restored.UniqueName != exampleUniqueName {
+		t.Fatalf("Restore should return the restored Document; got %v", restored)
+	}
+
+	if len(shelf.Trashed) != 0 {
+		t.Fatalf("trash should be empty; has %d Documents", len(shelf.Trashed))
+	}
+
+	test.Change(t, shelf, document.DocumentRestored, test.EventData(document.DocumentRestoredData{Document: doc}))
+}
+
+func TestShelf_Restore_duplicateUniqueName(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	if _, err := shelf.Trash(doc.ID); err != nil {
+		t.Fatalf("Trash failed with %q", err)
+	}
+
+	if _, err := shelf.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, "/example/other.pdf"); err != nil {
+		t.Fatalf("UniqueName of a trashed Document should be available; Add failed with %q", err)
+	}
+
+	if _, err := shelf.Restore(doc.ID); !errors.Is(err, document.ErrDuplicateUniqueName) {
+		t.Fatalf("Restore should fail with %q; got %q", document.ErrDuplicateUniqueName, err)
+	}
+}
+
+func TestShelf_Purge(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), exampleUniqueName, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	if err := shelf.Purge(ctx, storage, doc.ID); !errors.Is(err, document.ErrNotTrashed) {
+		t.Fatalf("Purge should fail with %q for an untrashed Document; got %q", document.ErrNotTrashed, err)
+	}
+
+	if _, err := shelf.Trash(doc.ID); err != nil {
+		t.Fatalf("Trash failed with %q", err)
+	}
+
+	if err := shelf.Purge(ctx, storage, doc.ID); err != nil {
+		t.Fatalf("Purge failed with %q", err)
+	}
+
+	if _, err := shelf.TrashedDocument(doc.ID); !errors.Is(err, document.ErrNotTrashed) {
+		t.Fatalf("TrashedDocument should return %q for a purged Document; got %q", document.ErrNotTrashed, err)
+	}
+
+	disk, _ := storage.Disk(exampleDisk)
+	if _, err := disk.Get(ctx, doc.Path); !errors.Is(err, media.ErrFileNotFound) {
+		t.Fatalf("file of a purged Document should be deleted; Get returned %q", err)
+	}
+
+	test.Change(t, shelf, document.DocumentPurged, test.EventData(document.DocumentPurgedData{Document: doc}))
+}
+
+func TestShelf_Expired(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create(exampleShelfName)
+
+	doc, err := shelf.Add(ctx, storage, newPDF(), "", exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Add failed with %q", err)
+	}
+
+	trashed, err := shelf.Trash(doc.ID)
+	if err != nil {
+		t.Fatalf("Trash failed with %q", err)
+	}
+
+	if expired := shelf.Expired(time.Hour, trashed.TrashedAt.Add(time.Minute)); len(expired) != 0 {
+		t.Fatalf("Document should not expire before the retention period; got %v", expired)
+	}
+
+	if expired := shelf.Expired(time.Hour, trashed.TrashedAt.Add(time.Hour)); len(expired) != 1 || expired[0].ID != doc.ID {
+		t.Fatalf("Document should expire after the retention period; got %v", expired)
+	}
+}
+
+func TestJanitor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	disk := media.MemoryDisk()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, disk))
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	cbus := cmdbus.New(commands.NewRegistry(), ebus)
+	shelfs := document.GoesRepository(repository.New(estore))
+
+	errs := document.HandleCommands(ctx, cbus, shelfs, storage)
+	go discard.Errors(errs)
+
+	janitor := document.NewJanitor(shelfs, storage, document.Retention(50*time.Millisecond), document.JanitorInterval(10*time.Millisecond))
+	janitorErrs, err := janitor.Run(ctx, ebus, estore)
+	if err != nil {
+		t.Fatalf("run janitor: %v", err)
+	}
+	go discard.Errors(janitorErrs)
+
+	shelf, doc := savedShelfWithDocument(t, ctx, shelfs, storage)
+
+	if err := cbus.Dispatch(ctx, document.Trash(shelf.ID, doc.ID).Any(), dispatch.Sync()); err != nil {
+		t.Fatalf("dispatch command: %v", err)
+	}
+
+	timeout := time.After(3 * time.Second)
+	for {
+		shelf, err := shelfs.Fetch(ctx, shelf.ID)
+		if err != nil {
+			t.Fatalf("fetch Shelf: %v", err)
+		}
+		if len(shelf.Trashed) == 0 {
+			break
+		}
+		select {
+		case <-timeout:
+			t.Fatalf("trashed Document should have been purged")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	if _, err := disk.Get(ctx, doc.Path); !errors.Is(err, media.ErrFileNotFound) {
+		t.Fatalf("file of a purged Document should be deleted; Get returned %q", err)
+	}
+}
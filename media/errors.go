@@ -10,6 +10,10 @@ var (
 	// ErrFileNotFound is returned when a file cannot be found in a Storage.
 	ErrFileNotFound = errors.New("file not found")
 
+	// ErrListingUnsupported is returned when listing the files of a
+	// StorageDisk that doesn't implement ListDisk.
+	ErrListingUnsupported = errors.New("disk does not support listing")
+
 	// ErrUploadFailed is returned when an upload of a file fails.
 	ErrUploadFailed = errors.New("upload failed")
 
@@ -0,0 +1,284 @@
+// Package export exports Galleries and Shelfs as ZIP archives. Exports run as
+// background jobs (see package jobs) and store the archive as the artifact of
+// the Job. Each archive contains the original files and a JSON manifest of the
+// exported Gallery or Shelf. Archive and OpenArchive write and read archives
+// of files and a manifest for other packages.
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	stdpath "path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/jobs"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+const (
+	// GalleryKind is the Job kind of Gallery exports.
+	GalleryKind = "cms.media.export.gallery"
+
+	// ShelfKind is the Job kind of Shelf exports.
+	ShelfKind = "cms.media.export.shelf"
+)
+
+// GalleryParams are the parameters of a Gallery export.
+type GalleryParams struct {
+	GalleryID uuid.UUID `json:"galleryId"`
+}
+
+// ShelfParams are the parameters of a Shelf export.
+type ShelfParams struct {
+	ShelfID uuid.UUID `json:"shelfId"`
+}
+
+// Gallery returns the jobs.Handler for Gallery exports. The archive contains
+// the original image or video of every Stack and a "gallery.json" manifest.
+// Artifacts can be downloaded by everyone who can download the artifacts of
+// Jobs, so if the Gallery protects its originals, the archive contains the
+// largest processed image of every Stack instead, and Stacks without a
+// processed image and videos are skipped. Archives are written in a single
+// pass, so an interrupted export restarts from the beginning.
+func Gallery(galleries gallery.Repository, storage media.Storage) jobs.Handler {
+	return func(ctx *jobs.Context) error {
+		var params GalleryParams
+		if err := ctx.Params(&params); err != nil {
+			return jobs.Permanent(err)
+		}
+
+		g, err := galleries.Fetch(ctx, params.GalleryID)
+		if err != nil {
+			if errors.Is(err, gallery.ErrNotFound) {
+				return jobs.Permanent(err)
+			}
+			return fmt.Errorf("fetch gallery: %w", err)
+		}
+
+		if g.AggregateVersion() == 0 {
+			return jobs.Permanent(gallery.ErrNotFound)
+		}
+
+		var files []entry
+		for _, s := range g.Stacks {
+			if g.OriginalsProtected {
+				if img, ok := s.Processed(); ok && s.Video == nil {
+					files = append(files, entry{file: img.File})
+				}
+				continue
+			}
+			if s.Video != nil {
+				files = append(files, entry{file: s.Video.File})
+				continue
+			}
+			files = append(files, entry{file: s.Original().File})
+		}
+
+		manifest := g.JSON()
+
+		return write(ctx, storage, archiveName(manifest.Name, params.GalleryID), "gallery.json", manifest, files)
+	}
+}
+
+// Shelf returns the jobs.Handler for Shelf exports. The archive contains every
+// Document, the language variants of the Documents in "variants/{locale}/"
+// and a "shelf.json" manifest. Archives are written in a single pass, so an
+// interrupted export restarts from the beginning.
+func Shelf(shelfs document.Repository, storage media.Storage) jobs.Handler {
+	return func(ctx *jobs.Context) error {
+		var params ShelfParams
+		if err := ctx.Params(&params); err != nil {
+			return jobs.Permanent(err)
+		}
+
+		s, err := shelfs.Fetch(ctx, params.ShelfID)
+		if err != nil {
+			if errors.Is(err, document.ErrShelfNotFound) {
+				return jobs.Permanent(err)
+			}
+			return fmt.Errorf("fetch shelf: %w", err)
+		}
+
+		if s.AggregateVersion() == 0 {
+			return jobs.Permanent(document.ErrShelfNotFound)
+		}
+
+		var files []entry
+		for _, doc := range s.Documents {
+			files = append(files, entry{file: doc.File})
+
+			locales := make([]string, 0, len(doc.Variants))
+			for locale := range doc.Variants {
+				locales = append(locales, locale)
+			}
+			sort.Strings(locales)
+
+			for _, locale := range locales {
+				files = append(files, entry{
+					dir:  "variants/" + locale,
+					file: doc.Variants[locale].File,
+				})
+			}
+		}
+
+		return write(ctx, storage, archiveName(s.Name, params.ShelfID), "shelf.json", s.JSON(), files)
+	}
+}
+
+type entry struct {
+	dir  string
+	file media.File
+}
+
+// Entry is a file of an archive.
+type Entry struct {
+	// Name is the name of the file within the archive.
+	Name string
+	File media.File
+}
+
+// write streams the ZIP archive of files and the manifest to the artifact of
+// the Job and reports the progress after every file.
+func write(ctx *jobs.Context, storage media.Storage, name, manifestName string, manifest any, files []entry) error {
+	entries := make([]Entry, len(files))
+	names := make(map[string]bool)
+	for i, e := range files {
+		entries[i] = Entry{
+			Name: uniqueName(names, stdpath.Join(e.dir, fileName(e.file))),
+			File: e.file,
+		}
+	}
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		pw.CloseWithError(Archive(ctx, pw, storage, entries, manifestName, manifest, func(n int) error {
+			// The archive is complete only after the manifest has been
+			// written, so the files make up 99% of the progress.
+			return ctx.Progress(n*99/len(entries), nil)
+		}))
+	}()
+
+	err := ctx.Artifact(name, "application/zip", pr)
+	pr.CloseWithError(err)
+
+	return err
+}
+
+// Archive writes the ZIP archive of the files and the JSON manifest to w. The
+// manifest is written last, so an archive is complete only if it contains the
+// manifest. If progress is non-nil, it is called with the number of archived
+// files after every file.
+func Archive(ctx context.Context, w io.Writer, storage media.Storage, files []Entry, manifestName string, manifest any, progress func(int) error) error {
+	zw := zip.NewWriter(w)
+
+	for i, e := range files {
+		if err := copyFile(ctx, zw, storage, e.Name, e.File); err != nil {
+			return err
+		}
+
+		if progress != nil {
+			if err := progress(i + 1); err != nil {
+				return err
+			}
+		}
+	}
+
+	mw, err := zw.Create(manifestName)
+	if err != nil {
+		return fmt.Errorf("create %q: %w", manifestName, err)
+	}
+
+	enc := json.NewEncoder(mw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return fmt.Errorf("encode %q: %w", manifestName, err)
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("close archive: %w", err)
+	}
+
+	return nil
+}
+
+// OpenArchive opens the ZIP archive in r and decodes its JSON manifest into
+// manifest.
+func OpenArchive(r io.ReaderAt, size int64, manifestName string, manifest any) (*zip.Reader, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := zr.Open(manifestName)
+	if err != nil {
+		return zr, err
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(manifest); err != nil {
+		return zr, fmt.Errorf("decode %q: %w", manifestName, err)
+	}
+
+	return zr, nil
+}
+
+func copyFile(ctx context.Context, zw *zip.Writer, storage media.Storage, name string, f media.File) error {
+	r, err := f.Reader(ctx, storage)
+	if err != nil {
+		return fmt.Errorf("read %q: %w", f.Path, err)
+	}
+	defer r.Close()
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("create %q: %w", name, err)
+	}
+
+	if _, err := io.Copy(w, r); err != nil {
+		return fmt.Errorf("copy %q: %w", f.Path, err)
+	}
+
+	return nil
+}
+
+func fileName(f media.File) string {
+	if name := stdpath.Base(f.Path); name != "." && name != "/" {
+		return name
+	}
+	return f.Name
+}
+
+// uniqueName returns name or, if name is already taken, name with a numeric
+// suffix ("foo (2).png").
+func uniqueName(taken map[string]bool, name string) string {
+	unique := name
+	ext := stdpath.Ext(name)
+	for i := 2; taken[unique]; i++ {
+		unique = strings.TrimSuffix(name, ext) + " (" + strconv.Itoa(i) + ")" + ext
+	}
+	taken[unique] = true
+	return unique
+}
+
+func archiveName(name string, id uuid.UUID) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		name = id.String()
+	}
+	return name + ".zip"
+}
@@ -0,0 +1,288 @@
+package export_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"image/color"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/jobs"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/export"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+const exampleDisk = "foo-disk"
+
+func TestGallery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	galleries := gallery.GoesRepository(repository.New(eventstore.New()))
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+	for _, path := range []string{"/a/foo.png", "/b/foo.png"} {
+		_, buf := imggen.ColoredRectangle(8, 8, color.Black)
+		if _, err := g.Upload(ctx, storage, buf, "foo", exampleDisk, path); err != nil {
+			t.Fatalf("upload image: %v", err)
+		}
+	}
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save Gallery: %v", err)
+	}
+
+	r := jobs.NewRunner(
+		jobs.MemoryStore(),
+		jobs.Artifacts(storage, exampleDisk),
+		jobs.Handle(export.GalleryKind, export.Gallery(galleries, storage)),
+	)
+
+	job := runJob(t, ctx, r, export.GalleryKind, export.GalleryParams{GalleryID: g.AggregateID()})
+
+	if job.Artifact.Name != "foo.zip" {
+		t.Fatalf("artifact should be named %q; is %q", "foo.zip", job.Artifact.Name)
+	}
+
+	files := readArchive(t, ctx, r, job.ID)
+
+	for _, name := range []string{"foo.png", "foo (2).png", "gallery.json"} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("archive should contain %q; got %v", name, keys(files))
+		}
+	}
+
+	var manifest gallery.JSONGallery
+	if err := json.Unmarshal(files["gallery.json"], &manifest); err != nil {
+		t.Fatalf("decode manifest: %v", err)
+	}
+	if manifest.ID != g.AggregateID() || len(manifest.Stacks) != 2 {
+		t.Fatalf("manifest should contain the Gallery; got %v", manifest)
+	}
+}
+
+func TestGallery_originalsProtected(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	galleries := gallery.GoesRepository(repository.New(eventstore.New()))
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	var stacks []gallery.Stack
+	for _, path := range []string{"/a/foo.png", "/b/bar.png"} {
+		_, buf := imggen.ColoredRectangle(8, 8, color.Black)
+		stack, err := g.Upload(ctx, storage, buf, "foo", exampleDisk, path)
+		if err != nil {
+			t.Fatalf("upload image: %v", err)
+		}
+		stacks = append(stacks, stack)
+	}
+
+	// only the first Stack has a processed image
+	disk, _ := storage.Disk(exampleDisk)
+	if err := disk.Put(ctx, "/a/foo_small.png", []byte("small")); err != nil {
+		t.Fatalf("put processed image: %v", err)
+	}
+	if err := g.Update(stacks[0].ID, func(s gallery.Stack) gallery.Stack {
+		s.Images = append(s.Images, gallery.Image{
+			Image: media.NewImage(4, 4, "foo", exampleDisk, "/a/foo_small.png", 5),
+			Size:  "small",
+		})
+		return s
+	}); err != nil {
+		t.Fatalf("update Stack: %v", err)
+	}
+
+	if err := g.ProtectOriginals(true); err != nil {
+		t.Fatalf("protect originals: %v", err)
+	}
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save Gallery: %v", err)
+	}
+
+	r := jobs.NewRunner(
+		jobs.MemoryStore(),
+		jobs.Artifacts(storage, exampleDisk),
+		jobs.Handle(export.GalleryKind, export.Gallery(galleries, storage)),
+	)
+
+	job := runJob(t, ctx, r, export.GalleryKind, export.GalleryParams{GalleryID: g.AggregateID()})
+	files := readArchive(t, ctx, r, job.ID)
+
+	if len(files) != 2 || string(files["foo_small.png"]) != "small" {
+		t.Fatalf("archive should only contain the processed image and the manifest; got %v", keys(files))
+	}
+}
+
+func TestGallery_notFound(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	galleries := gallery.GoesRepository(repository.New(eventstore.New()))
+
+	r := jobs.NewRunner(
+		jobs.MemoryStore(),
+		jobs.Artifacts(storage, exampleDisk),
+		jobs.Handle(export.GalleryKind, export.Gallery(galleries, storage)),
+	)
+
+	errs, err := r.Run(ctx)
+	if err != nil {
+		t.Fatalf("run Runner: %v", err)
+	}
+
+	job, err := r.Start(ctx, export.GalleryKind, export.GalleryParams{GalleryID: uuid.New()})
+	if err != nil {
+		t.Fatalf("start export: %v", err)
+	}
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("timed out")
+	case err := <-errs:
+		if !errors.Is(err, gallery.ErrNotFound) {
+			t.Fatalf("export should fail with %q; got %q", gallery.ErrNotFound, err)
+		}
+	}
+
+	if job, _ = r.Job(ctx, job.ID); job.Status != jobs.StatusFailed || job.Attempts != 1 {
+		t.Fatalf("export should fail without retries; got status %q after %d attempts", job.Status, job.Attempts)
+	}
+}
+
+func TestShelf(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	shelfs := document.GoesRepository(repository.New(eventstore.New()))
+
+	s := document.NewShelf(uuid.New())
+	s.Create("foo")
+	doc, err := s.Add(ctx, storage, strings.NewReader("foo"), "", "foo", exampleDisk, "/foo.pdf")
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+	if _, err := s.AddVariant(ctx, storage, strings.NewReader("bar"), doc.ID, "de", "foo", exampleDisk, "/foo.de.pdf"); err != nil {
+		t.Fatalf("add variant: %v", err)
+	}
+	if err := shelfs.Save(ctx, s); err != nil {
+		t.Fatalf("save Shelf: %v", err)
+	}
+
+	r := jobs.NewRunner(
+		jobs.MemoryStore(),
+		jobs.Artifacts(storage, exampleDisk),
+		jobs.Handle(export.ShelfKind, export.Shelf(shelfs, storage)),
+	)
+
+	job := runJob(t, ctx, r, export.ShelfKind, export.ShelfParams{ShelfID: s.ID})
+
+	files := readArchive(t, ctx, r, job.ID)
+
+	want := map[string]string{
+		"foo.pdf":                "foo",
+		"variants/de/foo.de.pdf": "bar",
+	}
+	for name, contents := range want {
+		if string(files[name]) != contents {
+			t.Fatalf("%q should contain %q; got %q", name, contents, files[name])
+		}
+	}
+
+	if _, ok := files["shelf.json"]; !ok {
+		t.Fatalf("archive should contain %q; got %v", "shelf.json", keys(files))
+	}
+}
+
+func runJob(t *testing.T, ctx context.Context, r *jobs.Runner, kind string, params any) jobs.Job {
+	t.Helper()
+
+	errs, err := r.Run(ctx)
+	if err != nil {
+		t.Fatalf("run Runner: %v", err)
+	}
+
+	job, err := r.Start(ctx, kind, params)
+	if err != nil {
+		t.Fatalf("start export: %v", err)
+	}
+
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case <-timeout:
+			t.Fatalf("timed out")
+		case err := <-errs:
+			t.Fatalf("export failed: %v", err)
+		case <-time.After(5 * time.Millisecond):
+		}
+
+		if job, err = r.Job(ctx, job.ID); err != nil {
+			t.Fatalf("fetch job: %v", err)
+		}
+		if job.Status == jobs.StatusSucceeded {
+			return job
+		}
+	}
+}
+
+func readArchive(t *testing.T, ctx context.Context, r *jobs.Runner, id uuid.UUID) map[string][]byte {
+	t.Helper()
+
+	_, rc, err := r.Artifact(ctx, id)
+	if err != nil {
+		t.Fatalf("get artifact: %v", err)
+	}
+	defer rc.Close()
+
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("read artifact: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatalf("open archive: %v", err)
+	}
+
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %q: %v", f.Name, err)
+		}
+		b, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("read %q: %v", f.Name, err)
+		}
+		files[f.Name] = b
+	}
+
+	return files
+}
+
+func keys(files map[string][]byte) []string {
+	out := make([]string, 0, len(files))
+	for name := range files {
+		out = append(out, name)
+	}
+	return out
+}
@@ -0,0 +1,13 @@
+package media
+
+import "time"
+
+// LegalHold is a legal hold on a resource. While a legal hold is set, the
+// files of the resource must not be deleted or overwritten.
+type LegalHold struct {
+	// Reason is the reason for the legal hold, e.g. a case number.
+	Reason string `json:"reason,omitempty"`
+
+	// Since is the time at which the legal hold was set.
+	Since time.Time `json:"since"`
+}
@@ -0,0 +1,164 @@
+// Package blurhash implements the BlurHash algorithm, which encodes a compact
+// representation of an image as a short string. Frontends can decode the hash
+// into a blurred placeholder that is shown while the image is loading.
+//
+// See https://blurha.sh for details.
+package blurhash
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"strings"
+
+	"github.com/disintegration/imaging"
+)
+
+const (
+	// maxSampleSize is the maximum width and height of the image that is used to
+	// compute the hash. Larger images are downscaled first, which doesn't
+	// affect the result noticeably because the hash only encodes low
+	// frequencies.
+	maxSampleSize = 64
+
+	characters = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+)
+
+var (
+	// ErrInvalidComponents is returned when the number of X or Y components is
+	// not between 1 and 9.
+	ErrInvalidComponents = errors.New("invalid components")
+
+	// ErrEmptyImage is returned when encoding an empty image.
+	ErrEmptyImage = errors.New("empty image")
+)
+
+// Encode returns the BlurHash of img with the given number of X and Y
+// components. More components produce a more detailed but longer hash. Each
+// number of components must be between 1 and 9; 4 and 3 are good defaults for
+// landscape images.
+func Encode(img image.Image, xComponents, yComponents int) (string, error) {
+	if xComponents < 1 || xComponents > 9 || yComponents < 1 || yComponents > 9 {
+		return "", fmt.Errorf("%w: %dx%d", ErrInvalidComponents, xComponents, yComponents)
+	}
+
+	b := img.Bounds()
+	if b.Empty() {
+		return "", ErrEmptyImage
+	}
+
+	if b.Dx() > maxSampleSize || b.Dy() > maxSampleSize {
+		img = imaging.Fit(img, maxSampleSize, maxSampleSize, imaging.Box)
+	}
+	nrgba := imaging.Clone(img)
+
+	factors := make([][3]float64, 0, xComponents*yComponents)
+	for y := 0; y < yComponents; y++ {
+		for x := 0; x < xComponents; x++ {
+			factors = append(factors, factor(nrgba, x, y))
+		}
+	}
+
+	var hash strings.Builder
+	hash.WriteString(encode83((xComponents-1)+(yComponents-1)*9, 1))
+
+	dc, ac := factors[0], factors[1:]
+
+	maxValue := 1.0
+	if len(ac) > 0 {
+		var actualMax float64
+		for _, f := range ac {
+			actualMax = math.Max(actualMax, math.Max(math.Abs(f[0]), math.Max(math.Abs(f[1]), math.Abs(f[2]))))
+		}
+		quantisedMax := clamp(int(math.Floor(actualMax*166-0.5)), 0, 82)
+		maxValue = float64(quantisedMax+1) / 166
+		hash.WriteString(encode83(quantisedMax, 1))
+	} else {
+		hash.WriteString(encode83(0, 1))
+	}
+
+	hash.WriteString(encode83(encodeDC(dc), 4))
+	for _, f := range ac {
+		hash.WriteString(encode83(encodeAC(f, maxValue), 2))
+	}
+
+	return hash.String(), nil
+}
+
+// factor returns the factor of the (x, y) cosine component of img.
+func factor(img *image.NRGBA, xComponent, yComponent int) [3]float64 {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+
+	var r, g, b float64
+	for y := 0; y < height; y++ {
+		yBasis := math.Cos(math.Pi * float64(yComponent) * float64(y) / float64(height))
+		row := img.Pix[y*img.Stride:]
+		for x := 0; x < width; x++ {
+			basis := yBasis * math.Cos(math.Pi*float64(xComponent)*float64(x)/float64(width))
+			px := row[x*4:]
+			r += basis * sRGBToLinear(px[0])
+			g += basis * sRGBToLinear(px[1])
+			b += basis * sRGBToLinear(px[2])
+		}
+	}
+
+	normalisation := 2.0
+	if xComponent == 0 && yComponent == 0 {
+		normalisation = 1
+	}
+	scale := normalisation / float64(width*height)
+
+	return [3]float64{r * scale, g * scale, b * scale}
+}
+
+func encodeDC(c [3]float64) int {
+	return linearToSRGB(c[0])<<16 + linearToSRGB(c[1])<<8 + linearToSRGB(c[2])
+}
+
+func encodeAC(c [3]float64, maxValue float64) int {
+	quant := func(v float64) int {
+		return clamp(int(math.Floor(signPow(v/maxValue, 0.5)*9+9.5)), 0, 18)
+	}
+	return quant(c[0])*19*19 + quant(c[1])*19 + quant(c[2])
+}
+
+func encode83(value, length int) string {
+	out := make([]byte, length)
+	for i := 0; i < length; i++ {
+		digit := value % 83
+		value /= 83
+		out[length-i-1] = characters[digit]
+	}
+	return string(out)
+}
+
+func sRGBToLinear(v uint8) float64 {
+	f := float64(v) / 255
+	if f <= 0.04045 {
+		return f / 12.92
+	}
+	return math.Pow((f+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(v float64) int {
+	v = math.Max(0, math.Min(1, v))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func signPow(v, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), exp), v)
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
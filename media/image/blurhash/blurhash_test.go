@@ -0,0 +1,93 @@
+package blurhash_test
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"testing"
+
+	"github.com/modernice/nice-cms/media/image/blurhash"
+)
+
+const characters = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+func TestEncode(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 200, 100))
+	draw.Draw(img, img.Rect, image.NewUniform(color.NRGBA{200, 100, 50, 0xff}), image.Point{}, draw.Src)
+
+	hash, err := blurhash.Encode(img, 4, 3)
+	if err != nil {
+		t.Fatalf("Encode failed with %q", err)
+	}
+
+	if want := 1 + 1 + 4 + 2*(4*3-1); len(hash) != want {
+		t.Fatalf("hash should have length %d; has length %d (%q)", want, len(hash), hash)
+	}
+
+	if sizeFlag := decode83(hash[:1]); sizeFlag != 3+2*9 {
+		t.Fatalf("size flag should be %d; is %d", 3+2*9, sizeFlag)
+	}
+
+	dc := decode83(hash[2:6])
+	r, g, b := dc>>16, (dc>>8)&0xff, dc&0xff
+	if r != 200 || g != 100 || b != 50 {
+		t.Fatalf("average color should be (200, 100, 50); is (%d, %d, %d)", r, g, b)
+	}
+}
+
+func TestEncode_details(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 300, 300))
+	for y := 0; y < 300; y++ {
+		for x := 0; x < 300; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x * 255 / 300), uint8(y * 255 / 300), 0, 0xff})
+		}
+	}
+
+	hash, err := blurhash.Encode(img, 3, 3)
+	if err != nil {
+		t.Fatalf("Encode failed with %q", err)
+	}
+
+	uniform := image.NewNRGBA(image.Rect(0, 0, 300, 300))
+	uniformHash, err := blurhash.Encode(uniform, 3, 3)
+	if err != nil {
+		t.Fatalf("Encode failed with %q", err)
+	}
+
+	if hash[6:] == uniformHash[6:] {
+		t.Fatalf("AC components of a gradient should differ from those of a uniform image; got %q", hash)
+	}
+
+	// The gradient increases to the right, so the first horizontal component
+	// of the red channel is negative.
+	ac := decode83(hash[6:8])
+	if r := ac / (19 * 19); r >= 9 {
+		t.Fatalf("first horizontal red component should be negative; got quantised value %d", r)
+	}
+}
+
+func TestEncode_invalidComponents(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+
+	for _, c := range [][2]int{{0, 3}, {4, 10}} {
+		if _, err := blurhash.Encode(img, c[0], c[1]); !errors.Is(err, blurhash.ErrInvalidComponents) {
+			t.Fatalf("Encode should fail with %q for %dx%d components; got %v", blurhash.ErrInvalidComponents, c[0], c[1], err)
+		}
+	}
+}
+
+func TestEncode_emptyImage(t *testing.T) {
+	if _, err := blurhash.Encode(image.NewNRGBA(image.Rect(0, 0, 0, 0)), 4, 3); !errors.Is(err, blurhash.ErrEmptyImage) {
+		t.Fatalf("Encode should fail with %q; got %v", blurhash.ErrEmptyImage, err)
+	}
+}
+
+func decode83(s string) int {
+	var v int
+	for _, c := range s {
+		v = v*83 + strings.IndexRune(characters, c)
+	}
+	return v
+}
@@ -0,0 +1,132 @@
+package image
+
+import (
+	"image"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+// FocalPoint is the point of an image that should remain visible when the
+// image is cropped. X and Y are percentages of the width and height of the
+// image, measured from the top-left corner.
+type FocalPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Center is the FocalPoint at the center of an image.
+var Center = FocalPoint{X: 50, Y: 50}
+
+// Valid returns whether X and Y are between 0 and 100.
+func (p FocalPoint) Valid() bool {
+	return p.X >= 0 && p.X <= 100 && p.Y >= 0 && p.Y <= 100
+}
+
+// Area is a rectangular area of an image. X, Y, Width and Height are
+// percentages of the width and height of the image; X and Y are measured from
+// the top-left corner.
+type Area struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// Full is the Area that covers an entire image.
+var Full = Area{Width: 100, Height: 100}
+
+// Valid returns whether the Area is not empty and lies within the image.
+func (a Area) Valid() bool {
+	return a.X >= 0 && a.Y >= 0 && a.Width > 0 && a.Height > 0 &&
+		a.X+a.Width <= 100 && a.Y+a.Height <= 100
+}
+
+// Rect returns the rectangle of the Area within the given image bounds. The
+// returned rectangle is at least 1x1 pixels if the bounds are not empty.
+func (a Area) Rect(b image.Rectangle) image.Rectangle {
+	x0 := b.Min.X + int(math.Round(a.X/100*float64(b.Dx())))
+	y0 := b.Min.Y + int(math.Round(a.Y/100*float64(b.Dy())))
+	x1 := b.Min.X + int(math.Round((a.X+a.Width)/100*float64(b.Dx())))
+	y1 := b.Min.Y + int(math.Round((a.Y+a.Height)/100*float64(b.Dy())))
+	if x1 <= x0 {
+		x1 = x0 + 1
+	}
+	if y1 <= y0 {
+		y1 = y0 + 1
+	}
+	return image.Rect(x0, y0, x1, y1).Intersect(b)
+}
+
+// Crop crops the Area out of an Image.
+func (a Area) Crop(img image.Image) *image.NRGBA {
+	return imaging.Crop(img, a.Rect(img.Bounds()))
+}
+
+// Focus returns the position of the FocalPoint p of an image within the
+// Area of that image. If p lies outside of the Area, the closest point on the
+// edge of the Area is returned.
+func (a Area) Focus(p FocalPoint) FocalPoint {
+	if a.Width <= 0 || a.Height <= 0 {
+		return Center
+	}
+	return FocalPoint{
+		X: math.Min(math.Max((p.X-a.X)/a.Width*100, 0), 100),
+		Y: math.Min(math.Max((p.Y-a.Y)/a.Height*100, 0), 100),
+	}
+}
+
+// A Cropper crops images into different Dimensions around a FocalPoint.
+// Cropping is done in parallel for each Dimensions.
+type Cropper map[string]Dimensions
+
+// Crop crops an Image into the configured Dimensions so that the given
+// FocalPoint is as close to the center of the cropped images as possible. See
+// Dimensions.Crop.
+//
+//	c := Cropper{
+//		"square": {Width: 600, Height: 600},
+//		"banner": {Width: 1920, Height: 480},
+//	}
+//
+//	cropped := c.Crop(img, FocalPoint{X: 30, Y: 40})
+func (c Cropper) Crop(img image.Image, focus FocalPoint) map[string]image.Image {
+	src := NRGBA(img)
+	return transform(c, func(d Dimensions) *image.NRGBA {
+		return d.Crop(src, focus)
+	})
+}
+
+// Crop scales an Image to cover the Dimensions and crops it to the exact
+// Dimensions. The crop is positioned so that the FocalPoint is as close to the
+// center of the cropped image as the bounds of the image allow. If the Width
+// or Height of the Dimensions is 0, the Image is resized instead.
+func (dim Dimensions) Crop(img image.Image, focus FocalPoint) *image.NRGBA {
+	b := img.Bounds()
+	if dim.Width <= 0 || dim.Height <= 0 || b.Dx() == 0 || b.Dy() == 0 {
+		return dim.Resize(img)
+	}
+
+	scale := math.Max(float64(dim.Width)/float64(b.Dx()), float64(dim.Height)/float64(b.Dy()))
+	width := int(math.Max(math.Round(float64(b.Dx())*scale), float64(dim.Width)))
+	height := int(math.Max(math.Round(float64(b.Dy())*scale), float64(dim.Height)))
+	scaled := imaging.Resize(img, width, height, imaging.Lanczos)
+
+	x := offset(focus.X, width, dim.Width)
+	y := offset(focus.Y, height, dim.Height)
+
+	return imaging.Crop(scaled, image.Rect(x, y, x+dim.Width, y+dim.Height))
+}
+
+// offset returns the offset of a crop of the given length within size that
+// centers the crop on the focus percentage.
+func offset(focus float64, size, length int) int {
+	off := int(math.Round(focus/100*float64(size) - float64(length)/2))
+	if off < 0 {
+		return 0
+	}
+	if max := size - length; off > max {
+		return max
+	}
+	return off
+}
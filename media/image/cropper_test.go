@@ -0,0 +1,136 @@
+package image_test
+
+import (
+	stdimage "image"
+	"image/color"
+	"testing"
+
+	"github.com/modernice/nice-cms/media/image"
+)
+
+var (
+	red  = color.NRGBA{255, 0, 0, 255}
+	blue = color.NRGBA{0, 0, 255, 255}
+)
+
+func TestCropper_Crop(t *testing.T) {
+	img := newHalvedImage(800, 400)
+
+	cropper := image.Cropper{
+		"tall": {Width: 50, Height: 100},
+		"wide": {Width: 400, Height: 0},
+	}
+
+	tests := []struct {
+		focus image.FocalPoint
+		want  color.NRGBA
+	}{
+		{focus: image.FocalPoint{X: 10, Y: 50}, want: red},
+		{focus: image.FocalPoint{X: 90, Y: 50}, want: blue},
+		{focus: image.FocalPoint{X: 0, Y: 0}, want: red},
+		{focus: image.FocalPoint{X: 100, Y: 100}, want: blue},
+	}
+
+	for _, tt := range tests {
+		cropped := cropper.Crop(img, tt.focus)
+
+		tall := cropped["tall"]
+		if b := tall.Bounds(); b.Dx() != 50 || b.Dy() != 100 {
+			t.Fatalf("cropped image should be 50x100; is %dx%d", b.Dx(), b.Dy())
+		}
+
+		b := tall.Bounds()
+		for _, p := range []stdimage.Point{b.Min, {b.Max.X - 1, b.Max.Y - 1}} {
+			if got := color.NRGBAModel.Convert(tall.At(p.X, p.Y)); got != tt.want {
+				t.Fatalf("pixel %v of the image cropped around %v should be %v; is %v", p, tt.focus, tt.want, got)
+			}
+		}
+
+		wide := cropped["wide"]
+		if b := wide.Bounds(); b.Dx() != 400 || b.Dy() != 200 {
+			t.Fatalf("image without a height should be resized to 400x200; is %dx%d", b.Dx(), b.Dy())
+		}
+	}
+}
+
+func TestFocalPoint_Valid(t *testing.T) {
+	tests := map[image.FocalPoint]bool{
+		image.Center:      true,
+		{X: 0, Y: 100}:    true,
+		{X: -1, Y: 50}:    false,
+		{X: 50, Y: 100.5}: false,
+	}
+
+	for p, want := range tests {
+		if got := p.Valid(); got != want {
+			t.Errorf("%v.Valid() should return %v; got %v", p, want, got)
+		}
+	}
+}
+
+func TestArea_Crop(t *testing.T) {
+	img := newHalvedImage(800, 400)
+
+	area := image.Area{X: 50, Y: 25, Width: 25, Height: 50}
+
+	if r := area.Rect(img.Bounds()); r != stdimage.Rect(400, 100, 600, 300) {
+		t.Fatalf("Rect() should return %v; got %v", stdimage.Rect(400, 100, 600, 300), r)
+	}
+
+	cropped := area.Crop(img)
+	if b := cropped.Bounds(); b.Dx() != 200 || b.Dy() != 200 {
+		t.Fatalf("cropped image should be 200x200; is %dx%d", b.Dx(), b.Dy())
+	}
+	if got := color.NRGBAModel.Convert(cropped.At(0, 0)); got != blue {
+		t.Fatalf("cropped image should be %v; is %v", blue, got)
+	}
+}
+
+func TestArea_Valid(t *testing.T) {
+	tests := map[image.Area]bool{
+		image.Full:                                true,
+		{X: 10, Y: 20, Width: 30, Height: 40}:     true,
+		{X: 10, Y: 20, Width: 0, Height: 40}:      false,
+		{X: -1, Y: 0, Width: 50, Height: 50}:      false,
+		{X: 60, Y: 0, Width: 50, Height: 50}:      false,
+		{X: 0, Y: 50, Width: 100, Height: 50.001}: false,
+	}
+
+	for a, want := range tests {
+		if got := a.Valid(); got != want {
+			t.Errorf("%v.Valid() should return %v; got %v", a, want, got)
+		}
+	}
+}
+
+func TestArea_Focus(t *testing.T) {
+	area := image.Area{X: 50, Y: 0, Width: 50, Height: 50}
+
+	tests := map[image.FocalPoint]image.FocalPoint{
+		{X: 75, Y: 25}: image.Center,
+		{X: 60, Y: 40}: {X: 20, Y: 80},
+		{X: 10, Y: 90}: {X: 0, Y: 100},
+	}
+
+	for p, want := range tests {
+		if got := area.Focus(p); got != want {
+			t.Errorf("Focus(%v) should return %v; got %v", p, want, got)
+		}
+	}
+}
+
+// newHalvedImage returns an image whose left half is red and whose right half
+// is blue.
+func newHalvedImage(width, height int) *stdimage.NRGBA {
+	img := stdimage.NewNRGBA(stdimage.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			c := red
+			if x >= width/2 {
+				c = blue
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
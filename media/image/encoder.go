@@ -9,6 +9,8 @@ import (
 	"image/png"
 	"io"
 	"sync"
+
+	"github.com/modernice/nice-cms/media/image/webp"
 )
 
 var (
@@ -58,8 +60,8 @@ func WithFormat(format string, enc FormatEncoder) EncoderOption {
 	}
 }
 
-// NewEncoder returns a new Encoder with default support for JPEGs, GIFs and
-// PNGs. When provided an unknown image format, Encoder falls back to the PNG
+// NewEncoder returns a new Encoder with default support for JPEGs, GIFs, PNGs
+// and (lossless) WebPs. When provided an unknown image format, Encoder falls back to the PNG
 // encoder.
 func NewEncoder(opts ...EncoderOption) Encoder {
 	return newEncoder(opts...)
@@ -72,6 +74,7 @@ func newEncoder(opts ...EncoderOption) *encoder {
 			"jpeg": jpgEnc,
 			"jpg":  jpgEnc,
 			"gif":  Func(GIFEncoder),
+			"webp": Func(WebPEncoder),
 			"":     Func(PNGEncoder),
 		},
 	}
@@ -91,6 +94,11 @@ func GIFEncoder(w io.Writer, img image.Image) error {
 	return gif.Encode(w, img, nil)
 }
 
+// WebPEncoder encodes images using webp.Encode, which produces lossless WebPs.
+func WebPEncoder(w io.Writer, img image.Image) error {
+	return webp.Encode(w, img)
+}
+
 var pngEncoder = png.Encoder{CompressionLevel: png.BestCompression}
 
 // PNGEncoder encodes images using a png.Encoder with png.BestCompression as the
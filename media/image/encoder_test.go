@@ -8,12 +8,13 @@ import (
 
 	"github.com/modernice/nice-cms/internal/imggen"
 	"github.com/modernice/nice-cms/media/image"
+	_ "golang.org/x/image/webp"
 )
 
 func TestEncoder_Encode(t *testing.T) {
 	enc := image.NewEncoder()
 
-	tests := []string{"jpeg", "png"}
+	tests := []string{"jpeg", "png", "webp"}
 
 	for _, tt := range tests {
 		t.Run(tt, func(t *testing.T) {
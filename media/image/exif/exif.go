@@ -0,0 +1,415 @@
+// Package exif reads and strips the EXIF metadata of JPEG and PNG images.
+package exif
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNoEXIF is returned by Decode if an image has no EXIF metadata.
+	ErrNoEXIF = errors.New("no EXIF metadata")
+
+	// ErrUnsupportedFormat is returned for images that are neither JPEG nor PNG.
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+
+	// ErrInvalid is returned if the EXIF metadata of an image is malformed.
+	ErrInvalid = errors.New("invalid EXIF metadata")
+)
+
+// Metadata is the EXIF metadata of an image.
+type Metadata struct {
+	// Orientation is the EXIF orientation (1-8) of the image. Orientation is 0
+	// if the image has no orientation tag.
+	Orientation int
+
+	Make      string
+	Model     string
+	LensModel string
+
+	// DateTime is the time at which the image was taken. The time zone of
+	// EXIF timestamps is unknown, so DateTime is in UTC.
+	DateTime time.Time
+
+	// ExposureTime is the exposure time in seconds.
+	ExposureTime float64
+	FNumber      float64
+	ISO          int
+
+	// FocalLength is the focal length in millimeters.
+	FocalLength float64
+
+	// GPS are the coordinates at which the image was taken, or nil if the image
+	// has no GPS metadata.
+	GPS *GPS
+}
+
+// GPS are GPS coordinates in decimal degrees.
+type GPS struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// EXIF tags
+const (
+	tagMake             = 0x010f
+	tagModel            = 0x0110
+	tagOrientation      = 0x0112
+	tagExposureTime     = 0x829a
+	tagFNumber          = 0x829d
+	tagExifIFD          = 0x8769
+	tagGPSIFD           = 0x8825
+	tagISO              = 0x8827
+	tagDateTimeOriginal = 0x9003
+	tagFocalLength      = 0x920a
+	tagLensModel        = 0xa434
+
+	tagGPSLatitudeRef  = 0x0001
+	tagGPSLatitude     = 0x0002
+	tagGPSLongitudeRef = 0x0003
+	tagGPSLongitude    = 0x0004
+)
+
+var (
+	jpegSOI      = []byte{0xff, 0xd8}
+	pngSignature = []byte("\x89PNG\r\n\x1a\n")
+	exifHeader   = []byte("Exif\x00\x00")
+)
+
+// Decode reads the EXIF metadata of the JPEG or PNG image in r. If the image
+// has no EXIF metadata, ErrNoEXIF is returned.
+func Decode(r io.Reader) (Metadata, error) {
+	br := bufio.NewReader(r)
+
+	magic, err := br.Peek(len(pngSignature))
+	if err != nil && !bytes.HasPrefix(magic, jpegSOI) {
+		return Metadata{}, ErrUnsupportedFormat
+	}
+
+	var tiff []byte
+	switch {
+	case bytes.HasPrefix(magic, jpegSOI):
+		tiff, err = jpegEXIF(br)
+	case bytes.Equal(magic, pngSignature):
+		tiff, err = pngEXIF(br)
+	default:
+		return Metadata{}, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return Metadata{}, err
+	}
+	if tiff == nil {
+		return Metadata{}, ErrNoEXIF
+	}
+
+	return parseTIFF(tiff)
+}
+
+func jpegEXIF(r *bufio.Reader) ([]byte, error) {
+	var found []byte
+	err := jpegSegments(r, func(marker byte, payload []byte) bool {
+		if marker == 0xe1 && bytes.HasPrefix(payload, exifHeader) {
+			found = payload[len(exifHeader):]
+			return false
+		}
+		return true
+	}, nil)
+	return found, err
+}
+
+func pngEXIF(r *bufio.Reader) ([]byte, error) {
+	var found []byte
+	err := pngChunks(r, func(typ string, data, _ []byte) bool {
+		if typ == "eXIf" {
+			found = data
+			return false
+		}
+		return typ != "IEND"
+	})
+	return found, err
+}
+
+// jpegSegments calls fn for each marker segment of the JPEG in r until the
+// start of the image data or until fn returns false. If rest is non-nil, the
+// remaining image data is passed to rest.
+func jpegSegments(r *bufio.Reader, fn func(marker byte, payload []byte) bool, rest func(io.Reader) error) error {
+	if _, err := r.Discard(len(jpegSOI)); err != nil {
+		return err
+	}
+
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return fmt.Errorf("%w: read marker: %v", ErrInvalid, err)
+		}
+		if b != 0xff {
+			return fmt.Errorf("%w: expected marker, got %#x", ErrInvalid, b)
+		}
+
+		marker, err := r.ReadByte()
+		if err != nil {
+			return fmt.Errorf("%w: read marker: %v", ErrInvalid, err)
+		}
+
+		switch {
+		case marker == 0xff:
+			// Fill byte.
+			r.UnreadByte()
+			continue
+		case marker == 0x01 || marker >= 0xd0 && marker <= 0xd7:
+			// Markers without a payload.
+			if !fn(marker, nil) {
+				return nil
+			}
+			continue
+		case marker == 0xd9:
+			return nil
+		}
+
+		var size [2]byte
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			return fmt.Errorf("%w: read segment size: %v", ErrInvalid, err)
+		}
+		n := int(binary.BigEndian.Uint16(size[:]))
+		if n < 2 {
+			return fmt.Errorf("%w: invalid segment size %d", ErrInvalid, n)
+		}
+
+		payload := make([]byte, n-2)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return fmt.Errorf("%w: read segment: %v", ErrInvalid, err)
+		}
+
+		if !fn(marker, payload) {
+			return nil
+		}
+
+		// Start of scan; the entropy-coded image data follows.
+		if marker == 0xda {
+			if rest != nil {
+				return rest(r)
+			}
+			return nil
+		}
+	}
+}
+
+// pngChunks calls fn for each chunk of the PNG in r until fn returns false or
+// the end of r is reached. The signature of the PNG must not have been read.
+func pngChunks(r *bufio.Reader, fn func(typ string, data, crc []byte) bool) error {
+	if _, err := r.Discard(len(pngSignature)); err != nil {
+		return err
+	}
+
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("%w: read chunk header: %v", ErrInvalid, err)
+		}
+
+		n := binary.BigEndian.Uint32(header[:4])
+		if n > math.MaxInt32 {
+			return fmt.Errorf("%w: invalid chunk size %d", ErrInvalid, n)
+		}
+
+		// Chunk data and CRC.
+		data := make([]byte, int(n)+4)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return fmt.Errorf("%w: read chunk: %v", ErrInvalid, err)
+		}
+
+		if !fn(string(header[4:]), data[:n], data[n:]) {
+			return nil
+		}
+	}
+}
+
+type tiffReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+type ifdEntry struct {
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+// sizes of the TIFF field types
+var typeSizes = map[uint16]int{
+	1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8,
+}
+
+func parseTIFF(data []byte) (Metadata, error) {
+	if len(data) < 8 {
+		return Metadata{}, fmt.Errorf("%w: short TIFF header", ErrInvalid)
+	}
+
+	t := tiffReader{data: data}
+	switch string(data[:2]) {
+	case "II":
+		t.order = binary.LittleEndian
+	case "MM":
+		t.order = binary.BigEndian
+	default:
+		return Metadata{}, fmt.Errorf("%w: invalid byte order %q", ErrInvalid, data[:2])
+	}
+
+	if t.order.Uint16(data[2:]) != 42 {
+		return Metadata{}, fmt.Errorf("%w: invalid TIFF header", ErrInvalid)
+	}
+
+	ifd0, err := t.ifd(t.order.Uint32(data[4:]))
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	var meta Metadata
+	meta.Orientation = t.int(ifd0[tagOrientation])
+	meta.Make = t.string(ifd0[tagMake])
+	meta.Model = t.string(ifd0[tagModel])
+
+	if e, ok := ifd0[tagExifIFD]; ok {
+		exif, err := t.ifd(uint32(t.int(e)))
+		if err != nil {
+			return meta, err
+		}
+		meta.ExposureTime = t.rational(exif[tagExposureTime], 0)
+		meta.FNumber = t.rational(exif[tagFNumber], 0)
+		meta.ISO = t.int(exif[tagISO])
+		meta.FocalLength = t.rational(exif[tagFocalLength], 0)
+		meta.LensModel = t.string(exif[tagLensModel])
+
+		if dt := t.string(exif[tagDateTimeOriginal]); dt != "" {
+			if parsed, err := time.Parse("2006:01:02 15:04:05", dt); err == nil {
+				meta.DateTime = parsed
+			}
+		}
+	}
+
+	if e, ok := ifd0[tagGPSIFD]; ok {
+		gps, err := t.ifd(uint32(t.int(e)))
+		if err != nil {
+			return meta, err
+		}
+		meta.GPS = t.gps(gps)
+	}
+
+	return meta, nil
+}
+
+func (t tiffReader) ifd(offset uint32) (map[uint16]ifdEntry, error) {
+	if int64(offset)+2 > int64(len(t.data)) {
+		return nil, fmt.Errorf("%w: IFD offset out of range", ErrInvalid)
+	}
+
+	n := int(t.order.Uint16(t.data[offset:]))
+	start := int(offset) + 2
+	if start+n*12 > len(t.data) {
+		return nil, fmt.Errorf("%w: IFD out of range", ErrInvalid)
+	}
+
+	entries := make(map[uint16]ifdEntry, n)
+	for i := 0; i < n; i++ {
+		raw := t.data[start+i*12 : start+(i+1)*12]
+		e := ifdEntry{
+			typ:   t.order.Uint16(raw[2:]),
+			count: t.order.Uint32(raw[4:]),
+		}
+
+		size, ok := typeSizes[e.typ]
+		if !ok || e.count > uint32(len(t.data)) {
+			continue
+		}
+
+		length := size * int(e.count)
+		if length <= 4 {
+			e.value = raw[8 : 8+length]
+		} else {
+			off := int(t.order.Uint32(raw[8:]))
+			if off < 0 || off+length > len(t.data) {
+				continue
+			}
+			e.value = t.data[off : off+length]
+		}
+
+		entries[t.order.Uint16(raw)] = e
+	}
+
+	return entries, nil
+}
+
+func (t tiffReader) int(e ifdEntry) int {
+	switch {
+	case e.typ == 3 && len(e.value) >= 2:
+		return int(t.order.Uint16(e.value))
+	case e.typ == 4 && len(e.value) >= 4:
+		return int(t.order.Uint32(e.value))
+	case e.typ == 1 && len(e.value) >= 1:
+		return int(e.value[0])
+	}
+	return 0
+}
+
+func (t tiffReader) string(e ifdEntry) string {
+	if e.typ != 2 {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(string(e.value), "\x00"))
+}
+
+func (t tiffReader) rational(e ifdEntry, i int) float64 {
+	if (e.typ != 5 && e.typ != 10) || len(e.value) < (i+1)*8 {
+		return 0
+	}
+
+	v := e.value[i*8:]
+	if e.typ == 10 {
+		num, den := int32(t.order.Uint32(v)), int32(t.order.Uint32(v[4:]))
+		if den == 0 {
+			return 0
+		}
+		return float64(num) / float64(den)
+	}
+
+	num, den := t.order.Uint32(v), t.order.Uint32(v[4:])
+	if den == 0 {
+		return 0
+	}
+	return float64(num) / float64(den)
+}
+
+func (t tiffReader) gps(ifd map[uint16]ifdEntry) *GPS {
+	lat, latOK := t.degrees(ifd[tagGPSLatitude])
+	lon, lonOK := t.degrees(ifd[tagGPSLongitude])
+	if !latOK || !lonOK {
+		return nil
+	}
+
+	if t.string(ifd[tagGPSLatitudeRef]) == "S" {
+		lat = -lat
+	}
+	if t.string(ifd[tagGPSLongitudeRef]) == "W" {
+		lon = -lon
+	}
+
+	return &GPS{Latitude: lat, Longitude: lon}
+}
+
+// degrees converts degrees, minutes and seconds to decimal degrees.
+func (t tiffReader) degrees(e ifdEntry) (float64, bool) {
+	if e.typ != 5 || e.count != 3 {
+		return 0, false
+	}
+	return t.rational(e, 0) + t.rational(e, 1)/60 + t.rational(e, 2)/3600, true
+}
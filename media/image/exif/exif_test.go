@@ -0,0 +1,194 @@
+package exif_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media/image/exif"
+)
+
+func TestDecode_jpeg(t *testing.T) {
+	b := exampleJPEG(t, imggen.EXIF(6))
+
+	meta, err := exif.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("Decode failed with %q", err)
+	}
+
+	assertMetadata(t, meta)
+}
+
+func TestDecode_png(t *testing.T) {
+	b := examplePNG(t, imggen.EXIF(6))
+
+	meta, err := exif.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("Decode failed with %q", err)
+	}
+
+	assertMetadata(t, meta)
+}
+
+func TestDecode_noEXIF(t *testing.T) {
+	_, buf := imggen.ColoredRectangle(8, 8, color.Black)
+
+	if _, err := exif.Decode(buf); !errors.Is(err, exif.ErrNoEXIF) {
+		t.Fatalf("Decode should fail with %q; got %q", exif.ErrNoEXIF, err)
+	}
+
+	if _, err := exif.Decode(bytes.NewReader(exampleJPEG(t, nil))); !errors.Is(err, exif.ErrNoEXIF) {
+		t.Fatalf("Decode should fail with %q; got %q", exif.ErrNoEXIF, err)
+	}
+}
+
+func TestDecode_unsupportedFormat(t *testing.T) {
+	if _, err := exif.Decode(bytes.NewReader([]byte("GIF89a..."))); !errors.Is(err, exif.ErrUnsupportedFormat) {
+		t.Fatalf("Decode should fail with %q; got %q", exif.ErrUnsupportedFormat, err)
+	}
+}
+
+func TestDecode_invalid(t *testing.T) {
+	tiff := imggen.EXIF(6)
+	tiff[0], tiff[1] = 'X', 'X'
+
+	if _, err := exif.Decode(bytes.NewReader(exampleJPEG(t, tiff))); !errors.Is(err, exif.ErrInvalid) {
+		t.Fatalf("Decode should fail with %q; got %q", exif.ErrInvalid, err)
+	}
+}
+
+func TestStrip_jpeg(t *testing.T) {
+	b := exampleJPEG(t, imggen.EXIF(6))
+
+	var stripped bytes.Buffer
+	if err := exif.Strip(&stripped, bytes.NewReader(b)); err != nil {
+		t.Fatalf("Strip failed with %q", err)
+	}
+
+	if _, err := exif.Decode(bytes.NewReader(stripped.Bytes())); !errors.Is(err, exif.ErrNoEXIF) {
+		t.Fatalf("stripped image should have no EXIF metadata; Decode returned %v", err)
+	}
+
+	if _, err := jpeg.Decode(&stripped); err != nil {
+		t.Fatalf("stripped image should be a valid JPEG: %v", err)
+	}
+}
+
+func TestStrip_png(t *testing.T) {
+	b := examplePNG(t, imggen.EXIF(6))
+
+	var stripped bytes.Buffer
+	if err := exif.Strip(&stripped, bytes.NewReader(b)); err != nil {
+		t.Fatalf("Strip failed with %q", err)
+	}
+
+	if _, err := exif.Decode(bytes.NewReader(stripped.Bytes())); !errors.Is(err, exif.ErrNoEXIF) {
+		t.Fatalf("stripped image should have no EXIF metadata; Decode returned %v", err)
+	}
+
+	if _, err := png.Decode(&stripped); err != nil {
+		t.Fatalf("stripped image should be a valid PNG: %v", err)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+
+	tests := map[int]image.Point{
+		0: {4, 2},
+		1: {4, 2},
+		2: {4, 2},
+		3: {4, 2},
+		4: {4, 2},
+		5: {2, 4},
+		6: {2, 4},
+		7: {2, 4},
+		8: {2, 4},
+	}
+
+	for orientation, size := range tests {
+		if got := exif.Orient(img, orientation).Bounds().Size(); got != size {
+			t.Errorf("Orient(%d) should return a %v image; got %v", orientation, size, got)
+		}
+	}
+}
+
+func TestOrient_rotation(t *testing.T) {
+	// 2x1 image with a red pixel on the left. An orientation of 6 means that
+	// the image must be rotated 90° clockwise, so the red pixel must be at the
+	// top.
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+
+	oriented := exif.Orient(img, 6)
+
+	if r, _, _, _ := oriented.At(0, 0).RGBA(); r == 0 {
+		t.Fatalf("red pixel should be at the top of the oriented image")
+	}
+}
+
+func assertMetadata(t *testing.T, meta exif.Metadata) {
+	t.Helper()
+
+	if meta.Orientation != 6 {
+		t.Errorf("Orientation should be %d; is %d", 6, meta.Orientation)
+	}
+
+	if meta.Make != "Foo" || meta.Model != "Foo 1" {
+		t.Errorf("Make and Model should be %q and %q; are %q and %q", "Foo", "Foo 1", meta.Make, meta.Model)
+	}
+
+	if meta.FNumber != 2.8 {
+		t.Errorf("FNumber should be %v; is %v", 2.8, meta.FNumber)
+	}
+
+	if meta.ISO != 400 {
+		t.Errorf("ISO should be %d; is %d", 400, meta.ISO)
+	}
+
+	want := time.Date(2021, 7, 1, 12, 30, 0, 0, time.UTC)
+	if !meta.DateTime.Equal(want) {
+		t.Errorf("DateTime should be %v; is %v", want, meta.DateTime)
+	}
+
+	if meta.GPS == nil {
+		t.Fatal("GPS should not be nil")
+	}
+
+	if math.Abs(meta.GPS.Latitude-48.5) > 1e-9 || math.Abs(meta.GPS.Longitude+9.25) > 1e-9 {
+		t.Errorf("GPS should be (48.5, -9.25); is (%v, %v)", meta.GPS.Latitude, meta.GPS.Longitude)
+	}
+}
+
+func exampleJPEG(t *testing.T, tiff []byte) []byte {
+	img, _ := imggen.ColoredRectangle(8, 8, color.White)
+	b, err := imggen.JPEGWithEXIF(img, tiff)
+	if err != nil {
+		t.Fatalf("encode jpeg: %v", err)
+	}
+	return b
+}
+
+func examplePNG(t *testing.T, tiff []byte) []byte {
+	_, buf := imggen.ColoredRectangle(8, 8, color.White)
+	b := buf.Bytes()
+
+	chunk := make([]byte, 8)
+	binary.BigEndian.PutUint32(chunk, uint32(len(tiff)))
+	copy(chunk[4:], "eXIf")
+	chunk = append(chunk, tiff...)
+	chunk = append(chunk, 0, 0, 0, 0) // CRC isn't checked by Decode
+
+	// Insert the chunk after the IHDR chunk (8 byte signature + 25 byte IHDR).
+	out := append([]byte{}, b[:33]...)
+	out = append(out, chunk...)
+	return append(out, b[33:]...)
+}
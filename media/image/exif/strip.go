@@ -0,0 +1,125 @@
+package exif
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"image"
+	"io"
+
+	"github.com/disintegration/imaging"
+)
+
+// Strip copies the JPEG or PNG image in r to w without its EXIF metadata. For
+// JPEGs, all APP1 segments are removed, which also removes XMP metadata. For
+// PNGs, the eXIf chunk is removed. The image data itself is copied unchanged.
+//
+// Strip also removes the orientation of the image, so images should be
+// rotated using Orient before their metadata is stripped.
+func Strip(w io.Writer, r io.Reader) error {
+	br := bufio.NewReader(r)
+
+	magic, err := br.Peek(len(pngSignature))
+	if err != nil && !bytes.HasPrefix(magic, jpegSOI) {
+		return ErrUnsupportedFormat
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, jpegSOI):
+		return stripJPEG(w, br)
+	case bytes.Equal(magic, pngSignature):
+		return stripPNG(w, br)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+func stripJPEG(w io.Writer, r *bufio.Reader) error {
+	if _, err := w.Write(jpegSOI); err != nil {
+		return err
+	}
+
+	var werr error
+	write := func(b ...byte) {
+		if werr == nil {
+			_, werr = w.Write(b)
+		}
+	}
+
+	if err := jpegSegments(r, func(marker byte, payload []byte) bool {
+		if marker == 0xe1 {
+			return true
+		}
+		write(0xff, marker)
+		if payload != nil {
+			var size [2]byte
+			binary.BigEndian.PutUint16(size[:], uint16(len(payload)+2))
+			write(size[:]...)
+			write(payload...)
+		}
+		return werr == nil
+	}, func(rest io.Reader) error {
+		_, err := io.Copy(w, rest)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	return werr
+}
+
+func stripPNG(w io.Writer, r *bufio.Reader) error {
+	if _, err := w.Write(pngSignature); err != nil {
+		return err
+	}
+
+	var werr error
+	if err := pngChunks(r, func(typ string, data, crc []byte) bool {
+		if typ == "eXIf" {
+			return true
+		}
+
+		var header [8]byte
+		binary.BigEndian.PutUint32(header[:4], uint32(len(data)))
+		copy(header[4:], typ)
+
+		if _, werr = w.Write(header[:]); werr != nil {
+			return false
+		}
+
+		if _, werr = w.Write(data); werr != nil {
+			return false
+		}
+
+		_, werr = w.Write(crc)
+		return werr == nil
+	}); err != nil {
+		return err
+	}
+
+	return werr
+}
+
+// Orient transforms img according to the given EXIF orientation, so that the
+// returned image is displayed correctly without the orientation. Invalid
+// orientations are ignored.
+func Orient(img image.Image, orientation int) image.Image {
+	switch orientation {
+	case 2:
+		return imaging.FlipH(img)
+	case 3:
+		return imaging.Rotate180(img)
+	case 4:
+		return imaging.FlipV(img)
+	case 5:
+		return imaging.Transpose(img)
+	case 6:
+		return imaging.Rotate270(img)
+	case 7:
+		return imaging.Transverse(img)
+	case 8:
+		return imaging.Rotate90(img)
+	default:
+		return img
+	}
+}
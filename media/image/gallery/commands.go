@@ -2,23 +2,55 @@ package gallery
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/modernice/goes/codec"
 	"github.com/modernice/goes/command"
 	"github.com/modernice/goes/helper/streams"
 	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image"
 )
 
 // Gallery commands
 const (
 	CreateCommand      = "cms.media.image.gallery.create"
+	DeleteCommand      = "cms.media.image.gallery.delete"
+	RenameCommand      = "cms.media.image.gallery.rename"
 	DeleteStackCommand = "cms.media.image.gallery.delete_stack"
 	TagStackCommand    = "cms.media.image.gallery.tag_stack"
 	UntagStackCommand  = "cms.media.image.gallery.untag_stack"
 	RenameStackCommand = "cms.media.image.gallery.rename_stack"
 	UpdateStackCommand = "cms.media.image.gallery.update_stack"
 	SortCommand        = "cms.media.image.gallery.sort"
+	MoveStackCommand   = "cms.media.image.gallery.move_stack"
+
+	RelocateStackCommand = "cms.media.image.gallery.relocate_stack"
+
+	UpdateStackMetadataCommand = "cms.media.image.gallery.update_stack_metadata"
+
+	SetCustomMetadataCommand    = "cms.media.image.gallery.set_stack_custom_metadata"
+	DeleteCustomMetadataCommand = "cms.media.image.gallery.delete_stack_custom_metadata"
+
+	SetFocalPointCommand = "cms.media.image.gallery.set_focal_point"
+	CropStackCommand     = "cms.media.image.gallery.crop_stack"
+	RotateStackCommand   = "cms.media.image.gallery.rotate_stack"
+	FlipStackCommand     = "cms.media.image.gallery.flip_stack"
+
+	TrashStackCommand   = "cms.media.image.gallery.trash_stack"
+	RestoreStackCommand = "cms.media.image.gallery.restore_stack"
+	PurgeStackCommand   = "cms.media.image.gallery.purge_stack"
+
+	SetDefaultTagsCommand   = "cms.media.image.gallery.set_default_tags"
+	ProtectOriginalsCommand = "cms.media.image.gallery.protect_originals"
+
+	SetLegalHoldCommand  = "cms.media.image.gallery.set_legal_hold"
+	LiftLegalHoldCommand = "cms.media.image.gallery.lift_legal_hold"
+
+	SetVersionLimitCommand = "cms.media.image.gallery.set_version_limit"
+	RevertStackCommand     = "cms.media.image.gallery.revert_stack"
+
+	ReprocessStackCommand = "cms.media.image.gallery.reprocess_stack"
 )
 
 type createPayload struct {
@@ -30,6 +62,23 @@ func Create(id uuid.UUID, name string) command.Cmd[createPayload] {
 	return command.New(CreateCommand, createPayload{Name: name}, command.Aggregate(Aggregate, id))
 }
 
+type deletePayload struct{}
+
+// Delete returns the command to delete a gallery and the files of its stacks.
+// See Gallery.Destroy.
+func Delete(id uuid.UUID) command.Cmd[deletePayload] {
+	return command.New(DeleteCommand, deletePayload{}, command.Aggregate(Aggregate, id))
+}
+
+type renamePayload struct {
+	Name string
+}
+
+// Rename returns the command to rename a gallery.
+func Rename(id uuid.UUID, name string) command.Cmd[renamePayload] {
+	return command.New(RenameCommand, renamePayload{Name: name}, command.Aggregate(Aggregate, id))
+}
+
 type deleteStackPayload struct {
 	StackID uuid.UUID
 }
@@ -78,6 +127,106 @@ func RenameStack(galleryID, stackID uuid.UUID, name string) command.Cmd[renameSt
 	}, command.Aggregate(Aggregate, galleryID))
 }
 
+type updateStackMetadataPayload struct {
+	StackID uuid.UUID
+	Alt     string
+	Caption string
+}
+
+// UpdateStackMetadata returns the command to set the alternative text and
+// caption of a stack of a gallery.
+func UpdateStackMetadata(galleryID, stackID uuid.UUID, alt, caption string) command.Cmd[updateStackMetadataPayload] {
+	return command.New(UpdateStackMetadataCommand, updateStackMetadataPayload{
+		StackID: stackID,
+		Alt:     alt,
+		Caption: caption,
+	}, command.Aggregate(Aggregate, galleryID))
+}
+
+type setCustomMetadataPayload struct {
+	StackID uuid.UUID
+	Fields  map[string]string
+}
+
+// SetCustomMetadata returns the command to set custom metadata fields of a
+// stack of a gallery.
+func SetCustomMetadata(galleryID, stackID uuid.UUID, fields map[string]string) command.Cmd[setCustomMetadataPayload] {
+	return command.New(SetCustomMetadataCommand, setCustomMetadataPayload{
+		StackID: stackID,
+		Fields:  fields,
+	}, command.Aggregate(Aggregate, galleryID))
+}
+
+type deleteCustomMetadataPayload struct {
+	StackID uuid.UUID
+	Keys    []string
+}
+
+// DeleteCustomMetadata returns the command to remove custom metadata fields
+// from a stack of a gallery.
+func DeleteCustomMetadata(galleryID, stackID uuid.UUID, keys []string) command.Cmd[deleteCustomMetadataPayload] {
+	return command.New(DeleteCustomMetadataCommand, deleteCustomMetadataPayload{
+		StackID: stackID,
+		Keys:    keys,
+	}, command.Aggregate(Aggregate, galleryID))
+}
+
+type setFocalPointPayload struct {
+	StackID    uuid.UUID
+	FocalPoint image.FocalPoint
+}
+
+// SetFocalPoint returns the command to set the focal point of a stack of a
+// gallery.
+func SetFocalPoint(galleryID, stackID uuid.UUID, focus image.FocalPoint) command.Cmd[setFocalPointPayload] {
+	return command.New(SetFocalPointCommand, setFocalPointPayload{
+		StackID:    stackID,
+		FocalPoint: focus,
+	}, command.Aggregate(Aggregate, galleryID))
+}
+
+type cropStackPayload struct {
+	StackID uuid.UUID
+	Crop    image.Area
+}
+
+// CropStack returns the command to crop a stack of a gallery to an area of its
+// original image.
+func CropStack(galleryID, stackID uuid.UUID, area image.Area) command.Cmd[cropStackPayload] {
+	return command.New(CropStackCommand, cropStackPayload{
+		StackID: stackID,
+		Crop:    area,
+	}, command.Aggregate(Aggregate, galleryID))
+}
+
+type rotateStackPayload struct {
+	StackID uuid.UUID
+	Degrees int
+}
+
+// RotateStack returns the command to rotate the original image of a stack of a
+// gallery clockwise by the given degrees.
+func RotateStack(galleryID, stackID uuid.UUID, degrees int) command.Cmd[rotateStackPayload] {
+	return command.New(RotateStackCommand, rotateStackPayload{
+		StackID: stackID,
+		Degrees: degrees,
+	}, command.Aggregate(Aggregate, galleryID))
+}
+
+type flipStackPayload struct {
+	StackID uuid.UUID
+	Flip    image.Flip
+}
+
+// FlipStack returns the command to flip the original image of a stack of a
+// gallery.
+func FlipStack(galleryID, stackID uuid.UUID, flip image.Flip) command.Cmd[flipStackPayload] {
+	return command.New(FlipStackCommand, flipStackPayload{
+		StackID: stackID,
+		Flip:    flip,
+	}, command.Aggregate(Aggregate, galleryID))
+}
+
 type updateStackPayload struct {
 	Stack Stack
 }
@@ -96,15 +245,172 @@ func Sort(galleryID uuid.UUID, sorting []uuid.UUID) command.Cmd[sortPayload] {
 	return command.New(SortCommand, sortPayload{Sorting: sorting}, command.Aggregate(Aggregate, galleryID))
 }
 
+type moveStackPayload struct {
+	StackID  uuid.UUID
+	BeforeID uuid.UUID
+}
+
+// MoveStackBefore returns the command to move a stack of a gallery in front of
+// another stack. If beforeID is uuid.Nil, the stack is moved to the end of the
+// gallery.
+func MoveStackBefore(galleryID, stackID, beforeID uuid.UUID) command.Cmd[moveStackPayload] {
+	return command.New(MoveStackCommand, moveStackPayload{
+		StackID:  stackID,
+		BeforeID: beforeID,
+	}, command.Aggregate(Aggregate, galleryID))
+}
+
+type trashStackPayload struct {
+	StackID uuid.UUID
+}
+
+// TrashStack returns the command to move a stack of a gallery to the trash.
+func TrashStack(galleryID, stackID uuid.UUID) command.Cmd[trashStackPayload] {
+	return command.New(TrashStackCommand, trashStackPayload{StackID: stackID}, command.Aggregate(Aggregate, galleryID))
+}
+
+type restoreStackPayload struct {
+	StackID uuid.UUID
+}
+
+// RestoreStack returns the command to restore a stack from the trash of a
+// gallery.
+func RestoreStack(galleryID, stackID uuid.UUID) command.Cmd[restoreStackPayload] {
+	return command.New(RestoreStackCommand, restoreStackPayload{StackID: stackID}, command.Aggregate(Aggregate, galleryID))
+}
+
+type purgeStackPayload struct {
+	StackID uuid.UUID
+}
+
+// PurgeStack returns the command to delete a stack in the trash of a gallery.
+func PurgeStack(galleryID, stackID uuid.UUID) command.Cmd[purgeStackPayload] {
+	return command.New(PurgeStackCommand, purgeStackPayload{StackID: stackID}, command.Aggregate(Aggregate, galleryID))
+}
+
+type relocateStackPayload struct {
+	StackID uuid.UUID
+	Disk    string
+	Path    string
+}
+
+// RelocateStack returns the command to move the files of a stack to the given
+// disk and path. See Gallery.RelocateStack.
+func RelocateStack(galleryID, stackID uuid.UUID, disk, path string) command.Cmd[relocateStackPayload] {
+	return command.New(RelocateStackCommand, relocateStackPayload{
+		StackID: stackID,
+		Disk:    disk,
+		Path:    path,
+	}, command.Aggregate(Aggregate, galleryID))
+}
+
+type setDefaultTagsPayload struct {
+	Tags []string
+}
+
+// SetDefaultTags returns the command to set the default tags of a gallery.
+func SetDefaultTags(galleryID uuid.UUID, tags []string) command.Cmd[setDefaultTagsPayload] {
+	return command.New(SetDefaultTagsCommand, setDefaultTagsPayload{Tags: tags}, command.Aggregate(Aggregate, galleryID))
+}
+
+type protectOriginalsPayload struct {
+	Protected bool
+}
+
+// ProtectOriginals returns the command to set whether the original images of
+// a gallery are protected.
+func ProtectOriginals(galleryID uuid.UUID, protected bool) command.Cmd[protectOriginalsPayload] {
+	return command.New(ProtectOriginalsCommand, protectOriginalsPayload{Protected: protected}, command.Aggregate(Aggregate, galleryID))
+}
+
+type setLegalHoldPayload struct {
+	StackID uuid.UUID
+	Reason  string
+}
+
+// SetLegalHold returns the command to set a legal hold on a stack of a gallery.
+// If stackID is uuid.Nil, the legal hold is set on the whole gallery.
+func SetLegalHold(galleryID, stackID uuid.UUID, reason string) command.Cmd[setLegalHoldPayload] {
+	return command.New(SetLegalHoldCommand, setLegalHoldPayload{
+		StackID: stackID,
+		Reason:  reason,
+	}, command.Aggregate(Aggregate, galleryID))
+}
+
+type liftLegalHoldPayload struct {
+	StackID uuid.UUID
+}
+
+// LiftLegalHold returns the command to lift the legal hold of a stack of a
+// gallery. If stackID is uuid.Nil, the legal hold of the whole gallery is
+// lifted.
+func LiftLegalHold(galleryID, stackID uuid.UUID) command.Cmd[liftLegalHoldPayload] {
+	return command.New(LiftLegalHoldCommand, liftLegalHoldPayload{StackID: stackID}, command.Aggregate(Aggregate, galleryID))
+}
+
+type setVersionLimitPayload struct {
+	Limit int
+}
+
+// SetVersionLimit returns the command to set the number of previous versions
+// that are kept for each stack of a gallery.
+func SetVersionLimit(galleryID uuid.UUID, limit int) command.Cmd[setVersionLimitPayload] {
+	return command.New(SetVersionLimitCommand, setVersionLimitPayload{Limit: limit}, command.Aggregate(Aggregate, galleryID))
+}
+
+type revertStackPayload struct {
+	StackID uuid.UUID
+	Version int
+}
+
+// RevertStack returns the command to revert a stack of a gallery to a previous
+// version.
+func RevertStack(galleryID, stackID uuid.UUID, version int) command.Cmd[revertStackPayload] {
+	return command.New(RevertStackCommand, revertStackPayload{
+		StackID: stackID,
+		Version: version,
+	}, command.Aggregate(Aggregate, galleryID))
+}
+
+type reprocessStackPayload struct {
+	StackID uuid.UUID
+}
+
+// ReprocessStack returns the command to process a stack of a gallery again.
+func ReprocessStack(galleryID, stackID uuid.UUID) command.Cmd[reprocessStackPayload] {
+	return command.New(ReprocessStackCommand, reprocessStackPayload{StackID: stackID}, command.Aggregate(Aggregate, galleryID))
+}
+
 // RegisterCommands register the gallery commands into a command registry.
 func RegisterCommands(r codec.Registerer) {
 	codec.Register[createPayload](r, CreateCommand)
+	codec.Register[deletePayload](r, DeleteCommand)
+	codec.Register[renamePayload](r, RenameCommand)
 	codec.Register[deleteStackPayload](r, DeleteStackCommand)
 	codec.Register[tagStackPayload](r, TagStackCommand)
 	codec.Register[untagStackPayload](r, UntagStackCommand)
 	codec.Register[renameStackPayload](r, RenameStackCommand)
+	codec.Register[updateStackMetadataPayload](r, UpdateStackMetadataCommand)
+	codec.Register[setCustomMetadataPayload](r, SetCustomMetadataCommand)
+	codec.Register[deleteCustomMetadataPayload](r, DeleteCustomMetadataCommand)
+	codec.Register[setFocalPointPayload](r, SetFocalPointCommand)
+	codec.Register[cropStackPayload](r, CropStackCommand)
+	codec.Register[rotateStackPayload](r, RotateStackCommand)
+	codec.Register[flipStackPayload](r, FlipStackCommand)
 	codec.Register[updateStackPayload](r, UpdateStackCommand)
 	codec.Register[sortPayload](r, SortCommand)
+	codec.Register[moveStackPayload](r, MoveStackCommand)
+	codec.Register[trashStackPayload](r, TrashStackCommand)
+	codec.Register[restoreStackPayload](r, RestoreStackCommand)
+	codec.Register[purgeStackPayload](r, PurgeStackCommand)
+	codec.Register[relocateStackPayload](r, RelocateStackCommand)
+	codec.Register[setDefaultTagsPayload](r, SetDefaultTagsCommand)
+	codec.Register[protectOriginalsPayload](r, ProtectOriginalsCommand)
+	codec.Register[setLegalHoldPayload](r, SetLegalHoldCommand)
+	codec.Register[liftLegalHoldPayload](r, LiftLegalHoldCommand)
+	codec.Register[setVersionLimitPayload](r, SetVersionLimitCommand)
+	codec.Register[revertStackPayload](r, RevertStackCommand)
+	codec.Register[reprocessStackPayload](r, ReprocessStackCommand)
 }
 
 // HandleCommands handles commands until ctx is canceled.
@@ -117,6 +423,33 @@ func HandleCommands(ctx context.Context, bus command.Bus, galleries Repository,
 		})
 	})
 
+	deleteErrors := command.MustHandle(ctx, bus, DeleteCommand, func(ctx command.Context) error {
+		g, err := galleries.Fetch(ctx, ctx.AggregateID())
+		if err != nil {
+			return err
+		}
+
+		if _, err := g.Destroy(ctx, storage); err != nil {
+			return err
+		}
+
+		// The Deleted event is saved before the Gallery is deleted so that
+		// projections can remove the Gallery.
+		if err := galleries.Save(ctx, g); err != nil {
+			return fmt.Errorf("save Gallery: %w", err)
+		}
+
+		return galleries.Delete(ctx, g)
+	})
+
+	renameErrors := command.MustHandle(ctx, bus, RenameCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(renamePayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			return g.Rename(load.Name)
+		})
+	})
+
 	deleteStackErrors := command.MustHandle(ctx, bus, DeleteStackCommand, func(ctx command.Context) error {
 		load := ctx.Payload().(deleteStackPayload)
 
@@ -164,6 +497,69 @@ func HandleCommands(ctx context.Context, bus command.Bus, galleries Repository,
 		})
 	})
 
+	updateStackMetadataErrors := command.MustHandle(ctx, bus, UpdateStackMetadataCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(updateStackMetadataPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			_, err := g.UpdateStackMetadata(ctx, load.StackID, load.Alt, load.Caption)
+			return err
+		})
+	})
+
+	setCustomMetadataErrors := command.MustHandle(ctx, bus, SetCustomMetadataCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(setCustomMetadataPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			_, err := g.SetCustomMetadata(load.StackID, load.Fields)
+			return err
+		})
+	})
+
+	deleteCustomMetadataErrors := command.MustHandle(ctx, bus, DeleteCustomMetadataCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(deleteCustomMetadataPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			_, err := g.DeleteCustomMetadata(load.StackID, load.Keys...)
+			return err
+		})
+	})
+
+	setFocalPointErrors := command.MustHandle(ctx, bus, SetFocalPointCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(setFocalPointPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			_, err := g.SetFocalPoint(load.StackID, load.FocalPoint)
+			return err
+		})
+	})
+
+	cropStackErrors := command.MustHandle(ctx, bus, CropStackCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(cropStackPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			_, err := g.CropStack(load.StackID, load.Crop)
+			return err
+		})
+	})
+
+	rotateStackErrors := command.MustHandle(ctx, bus, RotateStackCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(rotateStackPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			_, err := g.RotateStack(ctx, storage, load.StackID, load.Degrees)
+			return err
+		})
+	})
+
+	flipStackErrors := command.MustHandle(ctx, bus, FlipStackCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(flipStackPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			_, err := g.FlipStack(ctx, storage, load.StackID, load.Flip)
+			return err
+		})
+	})
+
 	updateStackErrors := command.MustHandle(ctx, bus, UpdateStackCommand, func(ctx command.Context) error {
 		load := ctx.Payload().(updateStackPayload)
 
@@ -183,14 +579,136 @@ func HandleCommands(ctx context.Context, bus command.Bus, galleries Repository,
 		})
 	})
 
+	moveStackErrors := command.MustHandle(ctx, bus, MoveStackCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(moveStackPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			return g.MoveStackBefore(load.StackID, load.BeforeID)
+		})
+	})
+
+	trashStackErrors := command.MustHandle(ctx, bus, TrashStackCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(trashStackPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			_, err := g.Trash(load.StackID)
+			return err
+		})
+	})
+
+	restoreStackErrors := command.MustHandle(ctx, bus, RestoreStackCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(restoreStackPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			_, err := g.Restore(load.StackID)
+			return err
+		})
+	})
+
+	purgeStackErrors := command.MustHandle(ctx, bus, PurgeStackCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(purgeStackPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			return g.Purge(ctx, storage, load.StackID)
+		})
+	})
+
+	relocateStackErrors := command.MustHandle(ctx, bus, RelocateStackCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(relocateStackPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			_, err := g.RelocateStack(ctx, storage, load.StackID, load.Disk, load.Path)
+			return err
+		})
+	})
+
+	setDefaultTagsErrors := command.MustHandle(ctx, bus, SetDefaultTagsCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(setDefaultTagsPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			return g.SetDefaultTags(load.Tags...)
+		})
+	})
+
+	protectOriginalsErrors := command.MustHandle(ctx, bus, ProtectOriginalsCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(protectOriginalsPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			return g.ProtectOriginals(load.Protected)
+		})
+	})
+
+	setLegalHoldErrors := command.MustHandle(ctx, bus, SetLegalHoldCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(setLegalHoldPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			return g.SetLegalHold(load.StackID, load.Reason)
+		})
+	})
+
+	liftLegalHoldErrors := command.MustHandle(ctx, bus, LiftLegalHoldCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(liftLegalHoldPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			return g.LiftLegalHold(load.StackID)
+		})
+	})
+
+	setVersionLimitErrors := command.MustHandle(ctx, bus, SetVersionLimitCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(setVersionLimitPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			return g.SetVersionLimit(load.Limit)
+		})
+	})
+
+	revertStackErrors := command.MustHandle(ctx, bus, RevertStackCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(revertStackPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			_, err := g.Revert(ctx, storage, load.StackID, load.Version)
+			return err
+		})
+	})
+
+	reprocessStackErrors := command.MustHandle(ctx, bus, ReprocessStackCommand, func(ctx command.Context) error {
+		load := ctx.Payload().(reprocessStackPayload)
+
+		return galleries.Use(ctx, ctx.AggregateID(), func(g *Gallery) error {
+			_, err := g.ReprocessStack(load.StackID)
+			return err
+		})
+	})
+
 	return streams.FanInContext(
 		ctx,
 		createErrors,
+		deleteErrors,
+		renameErrors,
 		deleteStackErrors,
 		tagStackErrors,
 		untagStackErrors,
 		renameStackErrors,
+		updateStackMetadataErrors,
+		setCustomMetadataErrors,
+		deleteCustomMetadataErrors,
+		setFocalPointErrors,
+		cropStackErrors,
+		rotateStackErrors,
+		flipStackErrors,
 		updateStackErrors,
 		sortErrors,
+		moveStackErrors,
+		trashStackErrors,
+		restoreStackErrors,
+		purgeStackErrors,
+		relocateStackErrors,
+		setDefaultTagsErrors,
+		protectOriginalsErrors,
+		setLegalHoldErrors,
+		liftLegalHoldErrors,
+		setVersionLimitErrors,
+		revertStackErrors,
+		reprocessStackErrors,
 	)
 }
@@ -0,0 +1,150 @@
+package gallery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/projector"
+)
+
+// A DeadLetter is a Stack whose processing failed permanently, i.e. after all
+// retries of the PostProcessor (see ProcessorRetries).
+type DeadLetter struct {
+	GalleryID uuid.UUID `json:"galleryId"`
+	StackID   uuid.UUID `json:"stackId"`
+
+	// Error is the error of the last processing attempt.
+	Error string `json:"error"`
+
+	// Attempts is the number of processing attempts, or 0 if unknown.
+	Attempts int `json:"attempts"`
+
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// DeadLetters is a projection of the DeadLetters of all Galleries. DeadLetters
+// are projected from the StackProcessingFailed events of the Galleries, so
+// they are persisted in the event store. A DeadLetter is removed when its
+// Stack is processed successfully or deleted. Use PostProcessor.Redispatch or
+// the ReprocessStack command to process the Stack of a DeadLetter again.
+// DeadLetters is thread-safe.
+type DeadLetters struct {
+	projector *projector.Projector
+
+	mux     sync.RWMutex
+	letters map[stackRef]DeadLetter
+}
+
+// NewDeadLetters returns a new DeadLetters projection. The provided options
+// configure the error handling of the projection.
+func NewDeadLetters(opts ...projector.Option) *DeadLetters {
+	return &DeadLetters{
+		projector: projector.New(opts...),
+		letters:   make(map[stackRef]DeadLetter),
+	}
+}
+
+// Letters returns the DeadLetters of the Gallery with the given UUID, or the
+// DeadLetters of all Galleries if galleryID is uuid.Nil. DeadLetters are
+// sorted by the time of their failure, newest first.
+func (dl *DeadLetters) Letters(galleryID uuid.UUID) []DeadLetter {
+	dl.mux.RLock()
+	defer dl.mux.RUnlock()
+
+	letters := make([]DeadLetter, 0, len(dl.letters))
+	for _, letter := range dl.letters {
+		if galleryID == uuid.Nil || letter.GalleryID == galleryID {
+			letters = append(letters, letter)
+		}
+	}
+
+	sort.Slice(letters, func(i, j int) bool {
+		if !letters[i].FailedAt.Equal(letters[j].FailedAt) {
+			return letters[i].FailedAt.After(letters[j].FailedAt)
+		}
+		return letters[i].StackID.String() < letters[j].StackID.String()
+	})
+
+	return letters
+}
+
+// Letter returns the DeadLetter of the given Stack, or false if the processing
+// of the Stack didn't fail.
+func (dl *DeadLetters) Letter(galleryID, stackID uuid.UUID) (DeadLetter, bool) {
+	dl.mux.RLock()
+	defer dl.mux.RUnlock()
+	letter, ok := dl.letters[stackRef{galleryID: galleryID, stackID: stackID}]
+	return letter, ok
+}
+
+// Project projects the DeadLetters in a new goroutine and returns a channel of
+// asynchronous errors. Failed projection jobs are handled according to the
+// projector.Policy of the DeadLetters.
+func (dl *DeadLetters) Project(ctx context.Context, bus event.Bus, store event.Store, opts ...schedule.ContinuousOption) (<-chan error, error) {
+	schedule := schedule.Continuously(bus, store, []string{
+		Deleted,
+		StackDeleted,
+		StackPurged,
+		StackProcessed,
+		StackProcessingFailed,
+	}, opts...)
+
+	return dl.projector.Run(ctx, schedule, dl)
+}
+
+// Status returns the projection status of the DeadLetters.
+func (dl *DeadLetters) Status() projector.Status {
+	return dl.projector.Status()
+}
+
+// ApplyEvent applies aggregate events.
+func (dl *DeadLetters) ApplyEvent(evt event.Event) {
+	id, _, _ := evt.Aggregate()
+
+	dl.mux.Lock()
+	defer dl.mux.Unlock()
+
+	switch data := evt.Data().(type) {
+	case DeletedData:
+		for ref := range dl.letters {
+			if ref.galleryID == id {
+				delete(dl.letters, ref)
+			}
+		}
+	case StackDeletedData:
+		delete(dl.letters, stackRef{galleryID: id, stackID: data.Stack.ID})
+	case StackPurgedData:
+		delete(dl.letters, stackRef{galleryID: id, stackID: data.Stack.ID})
+	case StackProcessedData:
+		delete(dl.letters, stackRef{galleryID: id, stackID: data.Stack.ID})
+	case StackProcessingFailedData:
+		dl.letters[stackRef{galleryID: id, stackID: data.StackID}] = DeadLetter{
+			GalleryID: id,
+			StackID:   data.StackID,
+			Error:     data.Error,
+			Attempts:  data.Attempts,
+			FailedAt:  evt.Time(),
+		}
+	}
+}
+
+// Redispatch requests the processing of the Stack of a DeadLetter. The Stack is
+// processed asynchronously by the running PostProcessor (see Run), which
+// generates all Images of the Stack again. See Gallery.ReprocessStack.
+func (svc *PostProcessor) Redispatch(ctx context.Context, letter DeadLetter) (Stack, error) {
+	var stack Stack
+	if err := svc.galleries.Use(ctx, letter.GalleryID, func(g *Gallery) error {
+		var err error
+		stack, err = g.ReprocessStack(letter.StackID)
+		return err
+	}); err != nil {
+		return stack, fmt.Errorf("redispatch Stack %q: %w", letter.StackID, err)
+	}
+	return stack, nil
+}
@@ -0,0 +1,235 @@
+package gallery_test
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+func TestDeadLetters(t *testing.T) {
+	dl := gallery.NewDeadLetters()
+
+	galleryID := uuid.New()
+	otherGalleryID := uuid.New()
+	stackID := uuid.New()
+	otherStackID := uuid.New()
+	now := time.Now()
+
+	dl.ApplyEvent(event.New(gallery.StackProcessingFailed, gallery.StackProcessingFailedData{
+		StackID:  stackID,
+		Error:    "mock error",
+		Attempts: 3,
+	}, event.Time(now), event.Aggregate(galleryID, gallery.Aggregate, 1)).Any())
+
+	dl.ApplyEvent(event.New(gallery.StackProcessingFailed, gallery.StackProcessingFailedData{
+		StackID: otherStackID,
+		Error:   "other error",
+	}, event.Time(now.Add(time.Second)), event.Aggregate(otherGalleryID, gallery.Aggregate, 1)).Any())
+
+	letter, ok := dl.Letter(galleryID, stackID)
+	if !ok {
+		t.Fatalf("Letter should return the DeadLetter of Stack %q", stackID)
+	}
+
+	want := gallery.DeadLetter{
+		GalleryID: galleryID,
+		StackID:   stackID,
+		Error:     "mock error",
+		Attempts:  3,
+		FailedAt:  now,
+	}
+	if !letter.FailedAt.Equal(want.FailedAt) {
+		t.Fatalf("FailedAt should be %v; is %v", want.FailedAt, letter.FailedAt)
+	}
+	letter.FailedAt = want.FailedAt
+	if letter != want {
+		t.Fatalf("Letter should return %v; got %v", want, letter)
+	}
+
+	if letters := dl.Letters(uuid.Nil); len(letters) != 2 || letters[0].StackID != otherStackID {
+		t.Fatalf("Letters should return all DeadLetters, newest first; got %v", letters)
+	}
+
+	if letters := dl.Letters(galleryID); len(letters) != 1 || letters[0].StackID != stackID {
+		t.Fatalf("Letters should return the DeadLetters of Gallery %q; got %v", galleryID, letters)
+	}
+
+	dl.ApplyEvent(event.New(gallery.StackProcessed, gallery.StackProcessedData{
+		Stack: gallery.Stack{ID: stackID},
+	}, event.Aggregate(galleryID, gallery.Aggregate, 2)).Any())
+
+	if _, ok := dl.Letter(galleryID, stackID); ok {
+		t.Fatalf("DeadLetter should be removed when the Stack is processed")
+	}
+
+	dl.ApplyEvent(event.New(gallery.Deleted, gallery.DeletedData{}, event.Aggregate(otherGalleryID, gallery.Aggregate, 2)).Any())
+
+	if letters := dl.Letters(uuid.Nil); len(letters) != 0 {
+		t.Fatalf("DeadLetters of deleted Galleries should be removed; got %v", letters)
+	}
+}
+
+func TestPostProcessor_Run_retries(t *testing.T) {
+	enc := image.NewEncoder()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	galleries := gallery.GoesRepository(repository.New(estore))
+	svc := gallery.NewPostProcessor(enc, storage, galleries)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockError := errors.New("mock error")
+
+	var calls int32
+	pipe := gallery.ProcessingPipeline{
+		gallery.ProcessorFunc(func(*gallery.ProcessorContext) error {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				return mockError
+			}
+			return nil
+		}),
+	}
+
+	processed := make(chan gallery.Stack)
+	errs, err := svc.Run(
+		ctx, ebus, pipe,
+		gallery.ProcessorRetries(2),
+		gallery.ProcessorRetryDelay(time.Millisecond),
+		gallery.OnProcessed(func(s gallery.Stack, _ *gallery.Gallery) { processed <- s }),
+	)
+	if err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+	if _, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("failed to save Gallery: %v", err)
+	}
+
+	select {
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out")
+	case err := <-errs:
+		t.Fatalf("retried job should not fail; got %q", err)
+	case stack := <-processed:
+		if stack.ProcessingError != "" || stack.ProcessedAt.IsZero() {
+			t.Fatalf("Stack should be processed; ProcessingError=%q ProcessedAt=%v", stack.ProcessingError, stack.ProcessedAt)
+		}
+	}
+
+	if calls := atomic.LoadInt32(&calls); calls != 3 {
+		t.Fatalf("pipeline should have been called 3 times; was called %d times", calls)
+	}
+}
+
+func TestPostProcessor_Run_deadLetter(t *testing.T) {
+	enc := image.NewEncoder()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	galleries := gallery.GoesRepository(repository.New(estore))
+	svc := gallery.NewPostProcessor(enc, storage, galleries)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockError := errors.New("mock error")
+	pipe := gallery.ProcessingPipeline{
+		gallery.ProcessorFunc(func(*gallery.ProcessorContext) error {
+			return mockError
+		}),
+	}
+
+	dl := gallery.NewDeadLetters()
+	dlErrs, err := dl.Project(ctx, ebus, estore)
+	if err != nil {
+		t.Fatalf("project DeadLetters: %v", err)
+	}
+	go func() {
+		for err := range dlErrs {
+			panic(err)
+		}
+	}()
+
+	errs, err := svc.Run(ctx, ebus, pipe, gallery.ProcessorRetries(1), gallery.ProcessorRetryDelay(time.Millisecond))
+	if err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+	uploaded, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("failed to save Gallery: %v", err)
+	}
+
+	select {
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out")
+	case err := <-errs:
+		if !errors.Is(err, mockError) {
+			t.Fatalf("Run should fail with %q; got %q", mockError, err)
+		}
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	letter, ok := dl.Letter(g.ID, uploaded.ID)
+	if !ok {
+		t.Fatalf("failed Stack should be recorded as a DeadLetter")
+	}
+
+	if letter.Attempts != 2 || letter.Error == "" {
+		t.Fatalf("DeadLetter should have 2 attempts and an error; Attempts=%d Error=%q", letter.Attempts, letter.Error)
+	}
+
+	g, err = galleries.Fetch(ctx, g.ID)
+	if err != nil {
+		t.Fatalf("fetch Gallery: %v", err)
+	}
+
+	if stack, _ := g.Stack(uploaded.ID); stack.Pending || stack.ProcessingError == "" {
+		t.Fatalf("Stack should not be pending and have a ProcessingError; Pending=%v ProcessingError=%q", stack.Pending, stack.ProcessingError)
+	}
+
+	if _, err := svc.Redispatch(ctx, letter); err != nil {
+		t.Fatalf("Redispatch failed with %q", err)
+	}
+
+	select {
+	case <-time.After(3 * time.Second):
+		t.Fatal("redispatched Stack should be processed again")
+	case err := <-errs:
+		if !errors.Is(err, mockError) {
+			t.Fatalf("Run should fail with %q; got %q", mockError, err)
+		}
+	}
+}
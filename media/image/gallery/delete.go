@@ -0,0 +1,85 @@
+package gallery
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/media"
+)
+
+// Destroy deletes the files of all Stacks of the Gallery from storage,
+// including the Stacks in the trash and the previous versions of the Stacks,
+// and then marks the Gallery as deleted. Files are deleted concurrently. Files
+// that could not be deleted are returned and reported in the DeletedData of the
+// Deleted event. A destroyed Gallery is no longer Created. Use the Delete
+// command to also delete the Gallery from the event store.
+//
+// If the Gallery or one of its Stacks is under legal hold, ErrLegalHold is
+// returned. If ctx is canceled before all files were deleted, the Gallery is
+// not marked as deleted and the error of ctx is returned.
+func (g *Implementation) Destroy(ctx context.Context, storage media.Storage) ([]media.DeleteFailure, error) {
+	if err := g.checkCreated(); err != nil {
+		return nil, err
+	}
+	if err := g.checkHold(uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	stacks := make([]Stack, 0, len(g.Stacks)+len(g.Trashed))
+	stacks = append(stacks, g.Stacks...)
+	for _, trashed := range g.Trashed {
+		stacks = append(stacks, trashed.Stack)
+	}
+
+	ids := make([]uuid.UUID, 0, len(stacks))
+	var files []media.File
+	for _, stack := range stacks {
+		if err := g.checkHold(stack.ID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, stack.ID)
+		files = append(files, stackFiles(stack)...)
+	}
+
+	failures := media.DeleteFiles(ctx, storage, files...)
+	if err := ctx.Err(); err != nil {
+		return failures, err
+	}
+
+	aggregate.NextEvent(g.gallery, Deleted, DeletedData{
+		Name:     g.Name,
+		Stacks:   ids,
+		Failures: failures,
+	})
+
+	return failures, nil
+}
+
+func (g *Implementation) delete(event.Event) {
+	*g = Implementation{
+		Stacks:  make([]Stack, 0),
+		gallery: g.gallery,
+		hooks:   g.hooks,
+	}
+}
+
+// stackFiles returns the stored files of a Stack, including the files of its
+// previous versions.
+func stackFiles(stack Stack) []media.File {
+	files := make([]media.File, 0, len(stack.Images)+1)
+	for _, img := range stack.Images {
+		files = append(files, img.File)
+	}
+	if stack.Video != nil {
+		files = append(files, stack.Video.File)
+	}
+	for _, v := range stack.Versions {
+		for _, f := range v.files() {
+			f.Path = VersionPath(stack.ID, v.Version, f.Path)
+			files = append(files, f)
+		}
+	}
+	return files
+}
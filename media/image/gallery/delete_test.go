@@ -0,0 +1,186 @@
+package gallery_test
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/command/cmdbus"
+	"github.com/modernice/goes/command/cmdbus/dispatch"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/internal/commands"
+	"github.com/modernice/nice-cms/internal/discard"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+func TestGallery_Destroy(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(
+		media.ConfigureDisk(exampleDisk, media.MemoryDisk()),
+		media.ConfigureDisk("other-disk", media.MemoryDisk()),
+	)
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(80, 60, color.White)
+	stack, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	_, buf = imggen.ColoredRectangle(80, 60, color.Black)
+	trashed, err := g.Upload(ctx, storage, buf, "Trashed", "other-disk", "/trashed.png")
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	if _, err := g.Trash(trashed.ID); err != nil {
+		t.Fatalf("trash stack: %v", err)
+	}
+
+	// "other-disk" is not configured, so the trashed Stack cannot be deleted.
+	failures, err := g.Destroy(ctx, media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk())))
+	if err != nil {
+		t.Fatalf("Destroy failed with %q", err)
+	}
+
+	if len(failures) != 1 || failures[0].Disk != "other-disk" || failures[0].Path != "/trashed.png" {
+		t.Fatalf("Destroy should report the file of the trashed Stack; got %v", failures)
+	}
+
+	if g.Created() {
+		t.Fatalf("Gallery should not be created after Destroy")
+	}
+	if len(g.Stacks) != 0 || len(g.Trashed) != 0 {
+		t.Fatalf("Gallery should have no Stacks after Destroy; has %v and %v in the trash", g.Stacks, g.Trashed)
+	}
+
+	test.Change(t, g, gallery.Deleted, test.EventData(gallery.DeletedData{
+		Name:     "foo",
+		Stacks:   []uuid.UUID{stack.ID, trashed.ID},
+		Failures: failures,
+	}))
+}
+
+func TestGallery_Destroy_deletesFiles(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(80, 60, color.White)
+	stack, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	failures, err := g.Destroy(ctx, storage)
+	if err != nil {
+		t.Fatalf("Destroy failed with %q", err)
+	}
+	if len(failures) != 0 {
+		t.Fatalf("Destroy should not report failures; got %v", failures)
+	}
+
+	for _, img := range stack.Images {
+		expectNoStorageFile(t, storage, img.Disk, img.Path)
+	}
+}
+
+func TestGallery_Destroy_legalHold(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(80, 60, color.White)
+	stack, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	if err := g.SetLegalHold(stack.ID, "case-1"); err != nil {
+		t.Fatalf("set legal hold: %v", err)
+	}
+
+	if _, err := g.Destroy(ctx, storage); !errors.Is(err, gallery.ErrLegalHold) {
+		t.Fatalf("Destroy should fail with %q; got %q", gallery.ErrLegalHold, err)
+	}
+
+	if !g.Created() {
+		t.Fatalf("Gallery should still be created")
+	}
+
+	disk, _ := storage.Disk(exampleDisk)
+	if _, err := disk.Get(ctx, examplePath); err != nil {
+		t.Fatalf("file of a held Stack should not be deleted; got %q", err)
+	}
+}
+
+func TestGallery_Destroy_notCreated(t *testing.T) {
+	g := gallery.New(uuid.New())
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	if _, err := g.Destroy(context.Background(), storage); !errors.Is(err, gallery.ErrNotCreated) {
+		t.Fatalf("Destroy should fail with %q; got %q", gallery.ErrNotCreated, err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	cbus := cmdbus.New(commands.NewRegistry(), ebus)
+	galleries := gallery.GoesRepository(repository.New(estore))
+
+	errs := gallery.HandleCommands(ctx, cbus, galleries, storage)
+	go discard.Errors(errs)
+
+	lookup := gallery.NewLookup()
+	lookupErrs, err := lookup.Project(ctx, ebus, estore)
+	if err != nil {
+		t.Fatalf("project lookup: %v", err)
+	}
+	go discard.Errors(lookupErrs)
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+	stack := uploadStack(t, g.Implementation, storage)
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save Gallery: %v", err)
+	}
+
+	if err := cbus.Dispatch(ctx, gallery.Delete(g.ID).Any(), dispatch.Sync()); err != nil {
+		t.Fatalf("dispatch command: %v", err)
+	}
+
+	if g, err := galleries.Fetch(ctx, g.ID); err != nil {
+		t.Fatalf("fetch Gallery: %v", err)
+	} else if g.AggregateVersion() != 0 {
+		t.Fatalf("Gallery should have been deleted; has version %d", g.AggregateVersion())
+	}
+
+	for _, img := range stack.Images {
+		expectNoStorageFile(t, storage, img.Disk, img.Path)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	if id, ok := lookup.GalleryName("foo"); ok {
+		t.Fatalf("name of the deleted Gallery should be free; is used by %q", id)
+	}
+}
@@ -3,10 +3,14 @@ package gallery
 import (
 	"github.com/google/uuid"
 	"github.com/modernice/goes/codec"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image"
 )
 
 const (
 	Created       = "cms.media.image.gallery.created"
+	Deleted       = "cms.media.image.gallery.deleted"
+	Renamed       = "cms.media.image.gallery.renamed"
 	ImageUploaded = "cms.media.image.gallery.image_uploaded"
 	ImageReplaced = "cms.media.image.gallery.stack_replaced"
 	StackDeleted  = "cms.media.image.gallery.stack_deleted"
@@ -15,18 +19,103 @@ const (
 	StackRenamed  = "cms.media.image.gallery.stack_renamed"
 	StackUpdated  = "cms.media.image.gallery.stack_updated"
 	Sorted        = "cms.media.image.gallery.sorted"
+	StackMoved    = "cms.media.image.gallery.stack_moved"
+	StackTrashed  = "cms.media.image.gallery.stack_trashed"
+	StackRestored = "cms.media.image.gallery.stack_restored"
+	StackPurged   = "cms.media.image.gallery.stack_purged"
+
+	StackRelocated = "cms.media.image.gallery.stack_relocated"
+
+	StackMetadataUpdated = "cms.media.image.gallery.stack_metadata_updated"
+
+	StackCustomMetadataSet     = "cms.media.image.gallery.stack_custom_metadata_set"
+	StackCustomMetadataDeleted = "cms.media.image.gallery.stack_custom_metadata_deleted"
+
+	StackFocalPointSet = "cms.media.image.gallery.stack_focal_point_set"
+	StackCropped       = "cms.media.image.gallery.stack_cropped"
+
+	DefaultTagsChanged = "cms.media.image.gallery.default_tags_changed"
+	ThemeUpdated       = "cms.media.image.gallery.theme_updated"
+
+	OriginalsProtectionChanged = "cms.media.image.gallery.originals_protection_changed"
+
+	LegalHoldSet    = "cms.media.image.gallery.legal_hold_set"
+	LegalHoldLifted = "cms.media.image.gallery.legal_hold_lifted"
+
+	VersionLimitChanged = "cms.media.image.gallery.version_limit_changed"
+
+	StackProcessingStarted = "cms.media.image.gallery.stack_processing_started"
+	StackProcessed         = "cms.media.image.gallery.stack_processed"
+	StackProcessingFailed  = "cms.media.image.gallery.stack_processing_failed"
+
+	StackReprocessingRequested = "cms.media.image.gallery.stack_reprocessing_requested"
 )
 
+// Events are all gallery events.
+var Events = [...]string{
+	Created,
+	Deleted,
+	Renamed,
+	ImageUploaded,
+	ImageReplaced,
+	StackDeleted,
+	StackTagged,
+	StackUntagged,
+	StackRenamed,
+	StackMetadataUpdated,
+	StackCustomMetadataSet,
+	StackCustomMetadataDeleted,
+	StackFocalPointSet,
+	StackCropped,
+	StackUpdated,
+	Sorted,
+	StackMoved,
+	StackTrashed,
+	StackRestored,
+	StackPurged,
+	StackRelocated,
+	DefaultTagsChanged,
+	ThemeUpdated,
+	OriginalsProtectionChanged,
+	LegalHoldSet,
+	LegalHoldLifted,
+	VersionLimitChanged,
+	StackProcessingStarted,
+	StackProcessed,
+	StackProcessingFailed,
+	StackReprocessingRequested,
+}
+
 type CreatedData struct {
 	Name string
 }
 
+// DeletedData is the event data for the Deleted event. Stacks are the UUIDs of
+// the deleted Stacks, including the Stacks in the trash. Failures are the files
+// that could not be deleted from storage.
+type DeletedData struct {
+	Name     string
+	Stacks   []uuid.UUID
+	Failures []media.DeleteFailure
+}
+
+// RenamedData is the event data for the Renamed event.
+type RenamedData struct {
+	OldName string
+	Name    string
+}
+
 type ImageUploadedData struct {
 	Stack Stack
 }
 
+// ImageReplacedData is the event data for the ImageReplaced event. Reverted is
+// true if the Stack was reverted to a previous version using Revert, in which
+// case RevertedTo is the restored version.
 type ImageReplacedData struct {
-	Stack Stack
+	Stack      Stack
+	Reverted   bool
+	RevertedTo int
 }
 
 type StackDeletedData struct {
@@ -49,6 +138,41 @@ type StackRenamedData struct {
 	Name    string
 }
 
+// StackMetadataUpdatedData is the event data for the StackMetadataUpdated
+// event. Alt and Caption replace the previous values of the Stack.
+type StackMetadataUpdatedData struct {
+	StackID uuid.UUID
+	Alt     string
+	Caption string
+}
+
+// StackCustomMetadataSetData is the event data for the StackCustomMetadataSet
+// event.
+type StackCustomMetadataSetData struct {
+	StackID uuid.UUID
+	Fields  map[string]string
+}
+
+// StackCustomMetadataDeletedData is the event data for the
+// StackCustomMetadataDeleted event.
+type StackCustomMetadataDeletedData struct {
+	StackID uuid.UUID
+	Keys    []string
+}
+
+// StackFocalPointSetData is the event data for the StackFocalPointSet event.
+type StackFocalPointSetData struct {
+	StackID    uuid.UUID
+	FocalPoint image.FocalPoint
+}
+
+// StackCroppedData is the event data for the StackCropped event. A Crop of
+// image.Full removes the crop of the Stack.
+type StackCroppedData struct {
+	StackID uuid.UUID
+	Crop    image.Area
+}
+
 type StackUpdatedData struct {
 	Stack Stack
 }
@@ -57,14 +181,110 @@ type SortedData struct {
 	Sorting []uuid.UUID
 }
 
+type StackMovedData struct {
+	StackID  uuid.UUID
+	BeforeID uuid.UUID
+}
+
+type StackTrashedData struct {
+	Stack Stack
+}
+
+type StackRestoredData struct {
+	Stack Stack
+}
+
+type StackPurgedData struct {
+	Stack Stack
+}
+
+type StackRelocatedData struct {
+	Stack       Stack
+	DeleteError string
+}
+
+type DefaultTagsChangedData struct {
+	Tags []string
+}
+
+type ThemeUpdatedData struct {
+	Theme *Theme
+}
+
+type OriginalsProtectionChangedData struct {
+	Protected bool
+}
+
+type VersionLimitChangedData struct {
+	Limit int
+}
+
+// LegalHoldSetData is the event data for the LegalHoldSet event. StackID is
+// uuid.Nil if the legal hold was set on the whole Gallery.
+type LegalHoldSetData struct {
+	StackID uuid.UUID
+	Reason  string
+}
+
+// LegalHoldLiftedData is the event data for the LegalHoldLifted event. StackID
+// is uuid.Nil if the legal hold of the whole Gallery was lifted.
+type LegalHoldLiftedData struct {
+	StackID uuid.UUID
+}
+
+type StackProcessingStartedData struct {
+	StackID uuid.UUID
+}
+
+type StackProcessedData struct {
+	Stack Stack
+}
+
+type StackProcessingFailedData struct {
+	StackID uuid.UUID
+	Error   string
+
+	// Attempts is the number of times the PostProcessor tried to process the
+	// Stack, or 0 if unknown.
+	Attempts int
+}
+
+// StackReprocessingRequestedData is the event data for the
+// StackReprocessingRequested event.
+type StackReprocessingRequestedData struct {
+	StackID uuid.UUID
+}
+
 func RegisterEvents(r codec.Registerer) {
 	codec.Register[CreatedData](r, Created)
+	codec.Register[DeletedData](r, Deleted)
+	codec.Register[RenamedData](r, Renamed)
 	codec.Register[ImageUploadedData](r, ImageUploaded)
 	codec.Register[ImageReplacedData](r, ImageReplaced)
 	codec.Register[StackDeletedData](r, StackDeleted)
 	codec.Register[StackTaggedData](r, StackTagged)
 	codec.Register[StackUntaggedData](r, StackUntagged)
 	codec.Register[StackRenamedData](r, StackRenamed)
+	codec.Register[StackMetadataUpdatedData](r, StackMetadataUpdated)
+	codec.Register[StackCustomMetadataSetData](r, StackCustomMetadataSet)
+	codec.Register[StackCustomMetadataDeletedData](r, StackCustomMetadataDeleted)
+	codec.Register[StackFocalPointSetData](r, StackFocalPointSet)
+	codec.Register[StackCroppedData](r, StackCropped)
 	codec.Register[StackUpdatedData](r, StackUpdated)
 	codec.Register[SortedData](r, Sorted)
+	codec.Register[StackMovedData](r, StackMoved)
+	codec.Register[StackTrashedData](r, StackTrashed)
+	codec.Register[StackRestoredData](r, StackRestored)
+	codec.Register[StackPurgedData](r, StackPurged)
+	codec.Register[StackRelocatedData](r, StackRelocated)
+	codec.Register[DefaultTagsChangedData](r, DefaultTagsChanged)
+	codec.Register[ThemeUpdatedData](r, ThemeUpdated)
+	codec.Register[OriginalsProtectionChangedData](r, OriginalsProtectionChanged)
+	codec.Register[LegalHoldSetData](r, LegalHoldSet)
+	codec.Register[LegalHoldLiftedData](r, LegalHoldLifted)
+	codec.Register[VersionLimitChangedData](r, VersionLimitChanged)
+	codec.Register[StackProcessingStartedData](r, StackProcessingStarted)
+	codec.Register[StackProcessedData](r, StackProcessed)
+	codec.Register[StackProcessingFailedData](r, StackProcessingFailed)
+	codec.Register[StackReprocessingRequestedData](r, StackReprocessingRequested)
 }
@@ -8,7 +8,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"math/rand"
 	"sort"
 	"strings"
 	"sync"
@@ -17,9 +16,12 @@ import (
 	"github.com/google/uuid"
 	"github.com/modernice/goes/aggregate"
 	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/internal/aggregateutil"
 	"github.com/modernice/nice-cms/internal/concurrent"
 	"github.com/modernice/nice-cms/internal/unique"
 	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image"
+	"github.com/modernice/nice-cms/media/image/exif"
 )
 
 // Aggregate is the name of the Gallery aggregate.
@@ -43,6 +45,26 @@ var (
 
 	// ErrStackCorrupted is returned updating a Stack in an illegal way.
 	ErrStackCorrupted = errors.New("stack corrupted")
+
+	// ErrNoEncoder is returned by the AVIFConverter if it has no FormatEncoder.
+	// nice-cms does not ship an AVIF encoder (see AVIFConverter).
+	ErrNoEncoder = errors.New("no encoder")
+
+	// ErrInvalidFocalPoint is returned when setting a focal point outside of
+	// the image.
+	ErrInvalidFocalPoint = errors.New("invalid focal point")
+
+	// ErrInvalidCrop is returned when cropping a Stack to an empty area or to
+	// an area outside of the image.
+	ErrInvalidCrop = errors.New("invalid crop")
+
+	// ErrInvalidTransformation is returned when rotating a Stack by an angle
+	// that is not a multiple of 90 degrees or flipping it in an unknown
+	// direction.
+	ErrInvalidTransformation = errors.New("invalid transformation")
+
+	// ErrVideoStack is returned when rotating or flipping a video Stack.
+	ErrVideoStack = errors.New("video stack")
 )
 
 // Repository handles persistence of Galleries.
@@ -100,7 +122,31 @@ type Implementation struct {
 	Name   string `json:"name"`
 	Stacks Stacks `json:"stacks"`
 
+	// DefaultTags are added to every image that is uploaded to the Gallery.
+	DefaultTags []string `json:"defaultTags,omitempty"`
+
+	// Theme is the color theme of the Gallery. Theme is nil until the palette
+	// of a Stack has been extracted. See RefreshTheme.
+	Theme *Theme `json:"theme,omitempty"`
+
+	// OriginalsProtected is true if the original images of the Gallery must
+	// not be served publicly. Public clients are served the processed images
+	// of the Stacks instead. See ProtectOriginals.
+	OriginalsProtected bool `json:"originalsProtected,omitempty"`
+
+	// Trashed are the Stacks in the trash of the Gallery. See Trash.
+	Trashed []TrashedStack `json:"trashed,omitempty"`
+
+	// LegalHold is the legal hold of the Gallery, or nil if the Gallery is not
+	// under legal hold. See SetLegalHold.
+	LegalHold *media.LegalHold `json:"legalHold,omitempty"`
+
+	// VersionLimit is the number of previous versions that are kept for each
+	// Stack when it is replaced. See SetVersionLimit.
+	VersionLimit int `json:"versionLimit,omitempty"`
+
 	gallery aggregate.Aggregate
+	hooks   hooks
 }
 
 type Stacks []Stack
@@ -137,6 +183,21 @@ func (g *Implementation) Stack(id uuid.UUID) (Stack, error) {
 	return Stack{}, ErrStackNotFound
 }
 
+// FindStackByChecksum returns the first Stack whose uploaded image or video
+// has the given SHA-256 checksum (see media.File.Checksum) or ErrStackNotFound.
+// FindStackByChecksum can be used to detect duplicate uploads.
+func (g *Implementation) FindStackByChecksum(checksum string) (Stack, error) {
+	if checksum == "" {
+		return Stack{}, ErrStackNotFound
+	}
+	for _, stack := range g.Stacks {
+		if stack.Checksum == checksum || stack.Original().Checksum == checksum {
+			return stack.copy(), nil
+		}
+	}
+	return Stack{}, ErrStackNotFound
+}
+
 // FindByTag returns the Stacks that have all provided tags.
 func (g *Implementation) FindByTag(tags ...string) []Stack {
 	out := make([]Stack, 0)
@@ -169,15 +230,114 @@ func (g *Implementation) create(evt event.Event) {
 	g.Name = data.Name
 }
 
+// Rename renames the Gallery. The name is trimmed and must not be empty. If the
+// Gallery already has the name, Rename does nothing. Rename does not check if
+// the name is used by another Gallery; use a Lookup to check the name.
+func (g *Implementation) Rename(name string) error {
+	if err := g.checkCreated(); err != nil {
+		return err
+	}
+	if name = strings.TrimSpace(name); name == "" {
+		return ErrEmptyName
+	}
+	if name == g.Name {
+		return nil
+	}
+	aggregate.NextEvent(g.gallery, Renamed, RenamedData{OldName: g.Name, Name: name})
+	return nil
+}
+
+func (g *Implementation) rename(evt event.Event) {
+	data := evt.Data().(RenamedData)
+	g.Name = data.Name
+}
+
+// SetDefaultTags sets the tags that are added to every image that is uploaded
+// to the Gallery. Images that have already been uploaded are not tagged.
+func (g *Implementation) SetDefaultTags(tags ...string) error {
+	if err := g.checkCreated(); err != nil {
+		return err
+	}
+	tags = unique.Strings(tags...)
+	aggregate.NextEvent(g.gallery, DefaultTagsChanged, DefaultTagsChangedData{Tags: tags})
+	return nil
+}
+
+func (g *Implementation) changeDefaultTags(evt event.Event) {
+	data := evt.Data().(DefaultTagsChangedData)
+	g.DefaultTags = data.Tags
+}
+
+// ProtectOriginals sets whether the original images of the Gallery are
+// protected. The original images of a protected Gallery are only served to
+// authorized clients; public clients are served processed images only.
+func (g *Implementation) ProtectOriginals(protected bool) error {
+	if err := g.checkCreated(); err != nil {
+		return err
+	}
+	if g.OriginalsProtected == protected {
+		return nil
+	}
+	aggregate.NextEvent(g.gallery, OriginalsProtectionChanged, OriginalsProtectionChangedData{Protected: protected})
+	return nil
+}
+
+func (g *Implementation) changeOriginalsProtection(evt event.Event) {
+	data := evt.Data().(OriginalsProtectionChangedData)
+	g.OriginalsProtected = data.Protected
+}
+
 // Upload uploads the image in r to storage and returns the Stack for that image.
+// The DefaultTags of the Gallery are added to the uploaded image. BeforeUpload
+// and AfterUpload hooks are called before and after the upload.
 func (g *Implementation) Upload(ctx context.Context, storage media.Storage, r io.Reader, name, diskName, path string) (Stack, error) {
-	stack, err := g.uploadWithID(ctx, storage, r, name, diskName, path, uuid.New())
+	return g.upload(ctx, name, diskName, path, func() (Stack, error) {
+		return g.uploadWithID(ctx, storage, r, name, diskName, path, uuid.New())
+	})
+}
+
+// UploadVideo uploads the video in r to storage and returns the video Stack for
+// that video. The Stack has no Images until a frame of the video has been
+// extracted by the VideoThumbnailer Processor. The DefaultTags of the Gallery
+// are added to the uploaded video. BeforeUpload and AfterUpload hooks are
+// called before and after the upload.
+func (g *Implementation) UploadVideo(ctx context.Context, storage media.Storage, r io.Reader, name, diskName, path string) (Stack, error) {
+	return g.upload(ctx, name, diskName, path, func() (Stack, error) {
+		video, err := media.NewVideo(name, diskName, path, 0).Upload(ctx, r, storage)
+		if err != nil {
+			return Stack{}, fmt.Errorf("upload to %q storage: %w", diskName, err)
+		}
+		return Stack{ID: uuid.New(), Images: []Image{}, Video: &video, Checksum: video.Checksum}, nil
+	})
+}
+
+func (g *Implementation) upload(ctx context.Context, name, diskName, path string, upload func() (Stack, error)) (Stack, error) {
+	if err := g.checkCreated(); err != nil {
+		return Stack{}, err
+	}
+
+	info := UploadInfo{Name: name, Disk: diskName, Path: path}
+	for _, fn := range g.hooks.beforeUpload {
+		if err := fn(ctx, g, info); err != nil {
+			return Stack{}, err
+		}
+	}
+
+	stack, err := upload()
 	if err != nil {
 		return stack, err
 	}
 
+	if len(g.DefaultTags) > 0 {
+		stack = stack.WithTag(g.DefaultTags...)
+	}
+
 	aggregate.NextEvent(g.gallery, ImageUploaded, ImageUploadedData{Stack: stack})
 
+	for _, fn := range g.hooks.afterUpload {
+		fn(ctx, g, stack)
+	}
+
 	return stack, nil
 }
 
@@ -200,8 +360,9 @@ func (g *Implementation) uploadWithID(
 	}
 
 	stack := Stack{
-		ID:     id,
-		Images: []Image{{Image: img, Original: true}},
+		ID:       id,
+		Images:   []Image{{Image: img, Original: true}},
+		Checksum: img.Checksum,
 	}
 
 	return stack, nil
@@ -219,23 +380,97 @@ func (g *Implementation) uploadImage(evt event.Event) {
 	g.Stacks = append(g.Stacks, data.Stack)
 }
 
-// Replace replaced the Images in the given Stack with the image in r.
+// Replace replaces the original Image of the given Stack with the image in r.
+// The other Images of the Stack are marked as stale until they are replaced by
+// the post-processor. If the Stack is a video Stack, the video is replaced with
+// the video in r and all Images are marked as stale. If the Stack or the
+// Gallery is under legal hold, ErrLegalHold is returned.
 func (g *Implementation) Replace(ctx context.Context, storage media.Storage, r io.Reader, stackID uuid.UUID) (Stack, error) {
 	stack, err := g.Stack(stackID)
 	if err != nil {
 		return stack, err
 	}
 
+	if err := g.checkHold(stack.ID); err != nil {
+		return stack, err
+	}
+
+	if stack.IsVideo() {
+		return g.replaceVideo(ctx, storage, r, stack)
+	}
+
+	return g.replaceOriginal(ctx, storage, r, stack, func(replaced Stack) Stack {
+		replaced.FocalPoint = stack.FocalPoint
+		return replaced
+	})
+}
+
+// replaceOriginal replaces the original Image of an image Stack with the image
+// in r. Metadata that does not depend on the image is kept; adjust is called
+// with the replaced Stack to keep or update the remaining fields.
+func (g *Implementation) replaceOriginal(
+	ctx context.Context,
+	storage media.Storage,
+	r io.Reader,
+	stack Stack,
+	adjust func(Stack) Stack,
+) (Stack, error) {
 	org := stack.Original()
 	if org.Path == "" {
 		return stack, ErrStackCorrupted
 	}
 
+	versions, err := g.archive(ctx, storage, stack)
+	if err != nil {
+		return stack, err
+	}
+
 	replaced, err := g.uploadWithID(ctx, storage, r, org.Name, org.Disk, org.Path, stack.ID)
 	if err != nil {
+		g.discardArchive(ctx, storage, stack, versions)
 		return stack, fmt.Errorf("upload image: %w", err)
 	}
 
+	// The variants still have the content of the previous image until they
+	// are processed again.
+	for _, img := range stack.Images {
+		if !img.Original {
+			img.Stale = true
+			replaced.Images = append(replaced.Images, img)
+		}
+	}
+	replaced.Alt = stack.Alt
+	replaced.Caption = stack.Caption
+	replaced.CustomMetadata = stack.CustomMetadata
+	replaced = adjust(replaced)
+	replaced = g.versioned(ctx, storage, stack, replaced, versions)
+
+	aggregate.NextEvent(g.gallery, ImageReplaced, ImageReplacedData{Stack: replaced})
+
+	return g.Stack(replaced.ID)
+}
+
+func (g *Implementation) replaceVideo(ctx context.Context, storage media.Storage, r io.Reader, stack Stack) (Stack, error) {
+	versions, err := g.archive(ctx, storage, stack)
+	if err != nil {
+		return stack, err
+	}
+
+	replaced := stack.copy()
+
+	video, err := replaced.Video.Replace(ctx, r, storage)
+	if err != nil {
+		g.discardArchive(ctx, storage, stack, versions)
+		return stack, fmt.Errorf("upload video: %w", err)
+	}
+	replaced.Video = &video
+	replaced.Checksum = video.Checksum
+
+	for i := range replaced.Images {
+		replaced.Images[i].Stale = true
+	}
+	replaced = g.versioned(ctx, storage, stack, replaced, versions)
+
 	aggregate.NextEvent(g.gallery, ImageReplaced, ImageReplacedData{Stack: replaced})
 
 	return g.Stack(replaced.ID)
@@ -243,15 +478,46 @@ func (g *Implementation) Replace(ctx context.Context, storage media.Storage, r i
 
 func (g *Implementation) replaceImage(evt event.Event) {
 	data := evt.Data().(ImageReplacedData)
-	g.replace(data.Stack.ID, data.Stack)
+	if err := g.replace(data.Stack.ID, data.Stack); err != nil {
+		return
+	}
+	i := g.stackIndex(data.Stack.ID)
+	g.Stacks[i].Version = data.Stack.Version
+	g.Stacks[i].Versions = data.Stack.Versions
 }
 
-// Delete deletes the given Stack from the Gallery and Storage.
+// Delete deletes the given Stack from the Gallery and Storage. BeforeDelete and
+// AfterDelete hooks are called before and after the deletion. If the Stack or
+// the Gallery is under legal hold, ErrLegalHold is returned.
 func (g *Implementation) Delete(ctx context.Context, storage media.Storage, stack Stack) error {
 	if err := g.checkCreated(); err != nil {
 		return err
 	}
 
+	if err := g.checkHold(stack.ID); err != nil {
+		return err
+	}
+
+	for _, fn := range g.hooks.beforeDelete {
+		if err := fn(ctx, g, stack); err != nil {
+			return err
+		}
+	}
+
+	if err := deleteFiles(ctx, storage, stack); err != nil {
+		return err
+	}
+
+	aggregate.NextEvent(g.gallery, StackDeleted, StackDeletedData{Stack: stack})
+	for _, fn := range g.hooks.afterDelete {
+		fn(ctx, g, stack)
+	}
+
+	return nil
+}
+
+// deleteFiles deletes the images and the video of a Stack from storage.
+func deleteFiles(ctx context.Context, storage media.Storage, stack Stack) error {
 	var wg sync.WaitGroup
 	wg.Add(len(stack.Images))
 	for _, img := range stack.Images {
@@ -261,12 +527,25 @@ func (g *Implementation) Delete(ctx context.Context, storage media.Storage, stac
 			img.Delete(ctx, storage)
 		}(img)
 	}
+	if stack.Video != nil {
+		wg.Add(1)
+		go func(video media.Video) {
+			defer wg.Done()
+			video.Delete(ctx, storage)
+		}(*stack.Video)
+	}
+	wg.Add(len(stack.Versions))
+	for _, v := range stack.Versions {
+		go func(v StackVersion) {
+			defer wg.Done()
+			deleteVersionFiles(ctx, storage, stack.ID, v)
+		}(v)
+	}
 
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	case <-concurrent.Wait(&wg):
-		aggregate.NextEvent(g.gallery, StackDeleted, StackDeletedData{Stack: stack})
 		return nil
 	}
 }
@@ -308,6 +587,8 @@ func (g *Implementation) tagStack(evt event.Event) {
 	g.replace(stack.ID, stack)
 }
 
+// replace replaces the Stack with the given UUID. The legal hold of the Stack
+// is kept because it can only be changed by SetLegalHold and LiftLegalHold.
 func (g *Implementation) replace(id uuid.UUID, stack Stack) error {
 	for i, s := range g.Stacks {
 		if s.ID != id {
@@ -318,6 +599,9 @@ func (g *Implementation) replace(id uuid.UUID, stack Stack) error {
 			return fmt.Errorf("illegal StackID update: %w", ErrStackCorrupted)
 		}
 
+		stack.LegalHold = s.LegalHold
+		stack.Version = s.Version
+		stack.Versions = s.Versions
 		g.Stacks[i] = stack
 		return nil
 	}
@@ -380,6 +664,273 @@ func (g *Implementation) renameStack(evt event.Event) {
 	g.replace(stack.ID, stack)
 }
 
+// UpdateStackMetadata sets the alternative text and caption of the given
+// Stack. Both are trimmed; empty values remove them from the Stack.
+func (g *Implementation) UpdateStackMetadata(ctx context.Context, stackID uuid.UUID, alt, caption string) (Stack, error) {
+	if err := g.checkCreated(); err != nil {
+		return Stack{}, err
+	}
+
+	stack, err := g.Stack(stackID)
+	if err != nil {
+		return Stack{}, err
+	}
+
+	alt = strings.TrimSpace(alt)
+	caption = strings.TrimSpace(caption)
+	if alt == stack.Alt && caption == stack.Caption {
+		return stack, nil
+	}
+
+	aggregate.NextEvent(g.gallery, StackMetadataUpdated, StackMetadataUpdatedData{
+		StackID: stack.ID,
+		Alt:     alt,
+		Caption: caption,
+	})
+
+	return g.Stack(stack.ID)
+}
+
+func (g *Implementation) updateStackMetadata(evt event.Event) {
+	data := evt.Data().(StackMetadataUpdatedData)
+	i := g.stackIndex(data.StackID)
+	if i < 0 {
+		return
+	}
+	g.Stacks[i].Alt = data.Alt
+	g.Stacks[i].Caption = data.Caption
+}
+
+// SetCustomMetadata adds the custom metadata fields to the given Stack,
+// overwriting existing fields with the same keys. Keys and values are trimmed.
+// If a key is empty, media.ErrEmptyMetadataKey is returned.
+func (g *Implementation) SetCustomMetadata(stackID uuid.UUID, fields map[string]string) (Stack, error) {
+	if err := g.checkCreated(); err != nil {
+		return Stack{}, err
+	}
+
+	stack, err := g.Stack(stackID)
+	if err != nil {
+		return Stack{}, err
+	}
+
+	fields, err = media.NormalizeMetadata(fields)
+	if err != nil {
+		return stack, err
+	}
+
+	if !media.MetadataChanged(stack.CustomMetadata, fields) {
+		return stack, nil
+	}
+
+	aggregate.NextEvent(g.gallery, StackCustomMetadataSet, StackCustomMetadataSetData{
+		StackID: stack.ID,
+		Fields:  fields,
+	})
+
+	return g.Stack(stack.ID)
+}
+
+func (g *Implementation) setCustomMetadata(evt event.Event) {
+	data := evt.Data().(StackCustomMetadataSetData)
+	i := g.stackIndex(data.StackID)
+	if i < 0 {
+		return
+	}
+	g.Stacks[i].CustomMetadata = media.WithMetadata(g.Stacks[i].CustomMetadata, data.Fields)
+}
+
+// DeleteCustomMetadata removes the custom metadata fields with the given keys
+// from the given Stack.
+func (g *Implementation) DeleteCustomMetadata(stackID uuid.UUID, keys ...string) (Stack, error) {
+	if err := g.checkCreated(); err != nil {
+		return Stack{}, err
+	}
+
+	stack, err := g.Stack(stackID)
+	if err != nil {
+		return Stack{}, err
+	}
+
+	keys = media.NormalizeMetadataKeys(keys...)
+
+	if !media.MetadataChanged(stack.CustomMetadata, nil, keys...) {
+		return stack, nil
+	}
+
+	aggregate.NextEvent(g.gallery, StackCustomMetadataDeleted, StackCustomMetadataDeletedData{
+		StackID: stack.ID,
+		Keys:    keys,
+	})
+
+	return g.Stack(stack.ID)
+}
+
+func (g *Implementation) deleteCustomMetadata(evt event.Event) {
+	data := evt.Data().(StackCustomMetadataDeletedData)
+	i := g.stackIndex(data.StackID)
+	if i < 0 {
+		return
+	}
+	g.Stacks[i].CustomMetadata = media.WithoutMetadata(g.Stacks[i].CustomMetadata, data.Keys...)
+}
+
+// SetFocalPoint sets the focal point of the given Stack. The cropped Images of
+// the Stack become stale until they are cropped again by the PostProcessor. If
+// focus is not within the image, ErrInvalidFocalPoint is returned.
+func (g *Implementation) SetFocalPoint(stackID uuid.UUID, focus image.FocalPoint) (Stack, error) {
+	if err := g.checkCreated(); err != nil {
+		return Stack{}, err
+	}
+
+	stack, err := g.Stack(stackID)
+	if err != nil {
+		return Stack{}, err
+	}
+
+	if !focus.Valid() {
+		return stack, fmt.Errorf("%w: %v", ErrInvalidFocalPoint, focus)
+	}
+
+	if stack.FocalPoint != nil && *stack.FocalPoint == focus {
+		return stack, nil
+	}
+
+	aggregate.NextEvent(g.gallery, StackFocalPointSet, StackFocalPointSetData{
+		StackID:    stack.ID,
+		FocalPoint: focus,
+	})
+
+	return g.Stack(stack.ID)
+}
+
+func (g *Implementation) setFocalPoint(evt event.Event) {
+	data := evt.Data().(StackFocalPointSetData)
+	i := g.stackIndex(data.StackID)
+	if i < 0 {
+		return
+	}
+	stack := g.Stacks[i].copy()
+	focus := data.FocalPoint
+	stack.FocalPoint = &focus
+	for j, img := range stack.Images {
+		if img.Cropped {
+			stack.Images[j].Stale = true
+		}
+	}
+	g.Stacks[i] = stack
+}
+
+// CropStack crops the given Stack to an area of its original image. The
+// original image is kept; the resized and cropped Images of the Stack become
+// stale until the PostProcessor generates them again from the area. Cropping
+// a Stack to image.Full removes its crop. If area is empty or not within the
+// image, ErrInvalidCrop is returned.
+func (g *Implementation) CropStack(stackID uuid.UUID, area image.Area) (Stack, error) {
+	if err := g.checkCreated(); err != nil {
+		return Stack{}, err
+	}
+
+	stack, err := g.Stack(stackID)
+	if err != nil {
+		return Stack{}, err
+	}
+
+	if !area.Valid() {
+		return stack, fmt.Errorf("%w: %v", ErrInvalidCrop, area)
+	}
+
+	current := image.Full
+	if stack.Crop != nil {
+		current = *stack.Crop
+	}
+	if current == area {
+		return stack, nil
+	}
+
+	aggregate.NextEvent(g.gallery, StackCropped, StackCroppedData{
+		StackID: stack.ID,
+		Crop:    area,
+	})
+
+	return g.Stack(stack.ID)
+}
+
+func (g *Implementation) cropStack(evt event.Event) {
+	data := evt.Data().(StackCroppedData)
+	i := g.stackIndex(data.StackID)
+	if i < 0 {
+		return
+	}
+	stack := g.Stacks[i].copy()
+	stack.Crop = nil
+	if area := data.Crop; area != image.Full {
+		stack.Crop = &area
+	}
+	for j, img := range stack.Images {
+		if img.Size != "" {
+			stack.Images[j].Stale = true
+		}
+	}
+	g.Stacks[i] = stack
+}
+
+// ReprocessStack requests the PostProcessor to process the given Stack again,
+// e.g. after the ProcessingPipeline has changed. All Images of the Stack except
+// the original become stale, so that the Processors generate them again
+// instead of skipping existing sizes. Stale Images that are not generated again
+// are removed when the Stack has been processed.
+func (g *Implementation) ReprocessStack(stackID uuid.UUID) (Stack, error) {
+	if err := g.checkCreated(); err != nil {
+		return Stack{}, err
+	}
+
+	stack, err := g.Stack(stackID)
+	if err != nil {
+		return Stack{}, err
+	}
+
+	aggregate.NextEvent(g.gallery, StackReprocessingRequested, StackReprocessingRequestedData{
+		StackID: stack.ID,
+	})
+
+	return g.Stack(stack.ID)
+}
+
+// ReprocessAll requests the PostProcessor to process all Stacks of the Gallery
+// again. See ReprocessStack.
+func (g *Implementation) ReprocessAll() ([]Stack, error) {
+	if err := g.checkCreated(); err != nil {
+		return nil, err
+	}
+
+	out := make([]Stack, 0, len(g.Stacks))
+	for _, stack := range g.Stacks {
+		reprocessed, err := g.ReprocessStack(stack.ID)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, reprocessed)
+	}
+
+	return out, nil
+}
+
+func (g *Implementation) reprocessStack(evt event.Event) {
+	data := evt.Data().(StackReprocessingRequestedData)
+	i := g.stackIndex(data.StackID)
+	if i < 0 {
+		return
+	}
+	stack := g.Stacks[i].copy()
+	for j, img := range stack.Images {
+		if !img.Original {
+			stack.Images[j].Stale = true
+		}
+	}
+	g.Stacks[i] = stack
+}
+
 // Update updates the Stack with the given UUID by calling update with the
 // current Stack and replacing that Stack with the one returned by update.
 //
@@ -402,6 +953,78 @@ func (g *Implementation) updateStack(evt event.Event) {
 	g.replace(data.Stack.ID, data.Stack)
 }
 
+// StartProcessing marks the Stack with the given UUID as pending until
+// FinishProcessing or FailProcessing is called.
+func (g *Implementation) StartProcessing(id uuid.UUID) error {
+	if _, err := g.Stack(id); err != nil {
+		return err
+	}
+	aggregate.NextEvent(g.gallery, StackProcessingStarted, StackProcessingStartedData{StackID: id})
+	return nil
+}
+
+func (g *Implementation) startProcessing(evt event.Event) {
+	data := evt.Data().(StackProcessingStartedData)
+	stack, err := g.Stack(data.StackID)
+	if err != nil {
+		return
+	}
+	stack.Pending = true
+	stack.ProcessingError = ""
+	g.replace(stack.ID, stack)
+}
+
+// FinishProcessing replaces the Stack with the processed Stack and marks it as
+// processed.
+func (g *Implementation) FinishProcessing(processed Stack) error {
+	if _, err := g.Stack(processed.ID); err != nil {
+		return err
+	}
+	aggregate.NextEvent(g.gallery, StackProcessed, StackProcessedData{Stack: processed})
+	return nil
+}
+
+func (g *Implementation) finishProcessing(evt event.Event) {
+	data := evt.Data().(StackProcessedData)
+	stack := data.Stack
+	stack.Pending = false
+	stack.ProcessedAt = evt.Time()
+	stack.ProcessingError = ""
+	g.replace(stack.ID, stack)
+}
+
+// FailProcessing marks the processing of the Stack with the given UUID as
+// failed.
+func (g *Implementation) FailProcessing(id uuid.UUID, processingError error) error {
+	return g.FailProcessingAfter(id, processingError, 0)
+}
+
+// FailProcessingAfter marks the processing of the Stack with the given UUID as
+// failed after the given number of attempts. The failure is recorded as a
+// DeadLetter until the Stack is processed successfully.
+func (g *Implementation) FailProcessingAfter(id uuid.UUID, processingError error, attempts int) error {
+	if _, err := g.Stack(id); err != nil {
+		return err
+	}
+	aggregate.NextEvent(g.gallery, StackProcessingFailed, StackProcessingFailedData{
+		StackID:  id,
+		Error:    processingError.Error(),
+		Attempts: attempts,
+	})
+	return nil
+}
+
+func (g *Implementation) failProcessing(evt event.Event) {
+	data := evt.Data().(StackProcessingFailedData)
+	stack, err := g.Stack(data.StackID)
+	if err != nil {
+		return
+	}
+	stack.Pending = false
+	stack.ProcessingError = data.Error
+	g.replace(stack.ID, stack)
+}
+
 // Sort sorts the stacks by their UUIDs. The provided `sorting` determines the
 // new order of the stacks. Stacks that are present in `sorting` take precedence
 // over all over stacks. It is allowed to pass UUIDs of stacks that don't exist
@@ -469,13 +1092,100 @@ func (g *Implementation) sort(evt event.Event) {
 	})
 }
 
+// MoveStackBefore moves the Stack with the UUID `stackID` in front of the Stack
+// with the UUID `beforeID`. If `beforeID` is uuid.Nil, the Stack is moved to
+// the end of the gallery. Unlike Sort, which replaces the whole order of the
+// stacks, MoveStackBefore only records the intent to move a single Stack. Moves
+// of editors that sort the same gallery concurrently are therefore merged
+// instead of overwriting each other. Moving a Stack in front of itself or to
+// the position it already has is a no-op.
+func (g *Implementation) MoveStackBefore(stackID, beforeID uuid.UUID) error {
+	idx := g.stackIndex(stackID)
+	if idx < 0 {
+		return fmt.Errorf("stack %q: %w", stackID, ErrStackNotFound)
+	}
+
+	if beforeID == uuid.Nil {
+		if idx == len(g.Stacks)-1 {
+			return nil
+		}
+	} else {
+		beforeIdx := g.stackIndex(beforeID)
+		if beforeIdx < 0 {
+			return fmt.Errorf("stack %q: %w", beforeID, ErrStackNotFound)
+		}
+		if beforeIdx == idx || beforeIdx == idx+1 {
+			return nil
+		}
+	}
+
+	aggregate.NextEvent(g.gallery, StackMoved, StackMovedData{
+		StackID:  stackID,
+		BeforeID: beforeID,
+	})
+
+	return nil
+}
+
+func (g *Implementation) moveStack(evt event.Event) {
+	data := evt.Data().(StackMovedData)
+
+	idx := g.stackIndex(data.StackID)
+	if idx < 0 {
+		return
+	}
+	stack := g.Stacks[idx]
+
+	stacks := make(Stacks, 0, len(g.Stacks))
+	stacks = append(stacks, g.Stacks[:idx]...)
+	stacks = append(stacks, g.Stacks[idx+1:]...)
+
+	// A Stack that cannot be found anymore is treated like uuid.Nil, so the
+	// moved Stack is appended to the end of the gallery.
+	pos := len(stacks)
+	for i, s := range stacks {
+		if s.ID == data.BeforeID {
+			pos = i
+			break
+		}
+	}
+
+	stacks = append(stacks, Stack{})
+	copy(stacks[pos+1:], stacks[pos:])
+	stacks[pos] = stack
+
+	g.Stacks = stacks
+}
+
+func (g *Implementation) stackIndex(id uuid.UUID) int {
+	for i, s := range g.Stacks {
+		if s.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
 type snapshot struct {
-	Stacks []Stack `json:"stacks"`
+	Stacks             []Stack  `json:"stacks"`
+	DefaultTags        []string `json:"defaultTags,omitempty"`
+	OriginalsProtected bool     `json:"originalsProtected,omitempty"`
+
+	Trashed      []TrashedStack   `json:"trashed,omitempty"`
+	LegalHold    *media.LegalHold `json:"legalHold,omitempty"`
+	VersionLimit int              `json:"versionLimit,omitempty"`
 }
 
 // MarshalSnapshot implements snapshot.Marshaler.
 func (g *Implementation) MarshalSnapshot() ([]byte, error) {
-	return json.Marshal(snapshot{Stacks: g.Stacks})
+	return json.Marshal(snapshot{
+		Stacks:             g.Stacks,
+		DefaultTags:        g.DefaultTags,
+		OriginalsProtected: g.OriginalsProtected,
+		Trashed:            g.Trashed,
+		LegalHold:          g.LegalHold,
+		VersionLimit:       g.VersionLimit,
+	})
 }
 
 // UnmarshalSnapshot implements snapshot.Unmarshaler.
@@ -485,6 +1195,11 @@ func (g *Implementation) UnmarshalSnapshot(b []byte) error {
 		return err
 	}
 	g.Stacks = snap.Stacks
+	g.DefaultTags = snap.DefaultTags
+	g.OriginalsProtected = snap.OriginalsProtected
+	g.Trashed = snap.Trashed
+	g.LegalHold = snap.LegalHold
+	g.VersionLimit = snap.VersionLimit
 	if g.Stacks == nil {
 		g.Stacks = make([]Stack, 0)
 	}
@@ -496,6 +1211,97 @@ func (g *Implementation) UnmarshalSnapshot(b []byte) error {
 type Stack struct {
 	ID     uuid.UUID `json:"id"`
 	Images []Image   `json:"images"`
+
+	// Alt is the alternative text of the Stack for screen readers and for
+	// clients that cannot display the image.
+	Alt string `json:"alt,omitempty"`
+
+	// Caption is the caption of the Stack.
+	Caption string `json:"caption,omitempty"`
+
+	// CustomMetadata are custom key/value fields of the Stack, e.g. a
+	// copyright notice or a license. Unlike Metadata, it is not extracted from
+	// the image. See SetCustomMetadata.
+	CustomMetadata map[string]string `json:"customMetadata,omitempty"`
+
+	// FocalPoint is the point of the image that the Cropper keeps visible in
+	// cropped Images. If FocalPoint is nil, images are cropped around their
+	// center.
+	FocalPoint *image.FocalPoint `json:"focalPoint,omitempty"`
+
+	// Crop is the area of the original image that the Resizer and Cropper
+	// generate Images from. If Crop is nil, Images are generated from the
+	// entire original image. The FocalPoint is relative to the original image,
+	// not to the Crop.
+	Crop *image.Area `json:"crop,omitempty"`
+
+	// Video is the original video of a video Stack. The Images of a video
+	// Stack are generated from a frame of the video by the VideoThumbnailer
+	// Processor. Video is nil for image Stacks.
+	Video *media.Video `json:"video,omitempty"`
+
+	// Checksum is the SHA-256 checksum of the uploaded image or video (see
+	// media.File.Checksum). Unlike the Checksum of the original Image, it
+	// doesn't change when a Processor rewrites the original image.
+	Checksum string `json:"checksum,omitempty"`
+
+	// Placeholder is the BlurHash of the original image. Frontends can render
+	// it as a placeholder while the image is loading. Placeholder is set by the
+	// Placeholder Processor.
+	Placeholder string `json:"placeholder,omitempty"`
+
+	// Palette are the dominant colors of the original image as hex colors
+	// ("#rrggbb"), ordered by dominance. Palette is set by the Palette
+	// Processor.
+	Palette []string `json:"palette,omitempty"`
+
+	// Metadata is the EXIF metadata of the original image. Metadata is set by
+	// the EXIF Processor.
+	Metadata *Metadata `json:"metadata,omitempty"`
+
+	// Pending is true while the Stack is being processed by the PostProcessor.
+	Pending bool `json:"pending"`
+
+	// ProcessedAt is the time at which the Stack was last processed
+	// successfully. ProcessedAt is zero if the Stack wasn't processed yet.
+	ProcessedAt time.Time `json:"processedAt"`
+
+	// ProcessingError is the error of the last failed processing attempt.
+	ProcessingError string `json:"processingError,omitempty"`
+
+	// LegalHold is the legal hold of the Stack, or nil if the Stack is not
+	// under legal hold.
+	LegalHold *media.LegalHold `json:"legalHold,omitempty"`
+
+	// Version is the number of times the Stack was replaced or reverted.
+	Version int `json:"version,omitempty"`
+
+	// Versions are the previous versions of the Stack, oldest first. Versions
+	// are only kept if the Gallery has a VersionLimit. See Revert.
+	Versions []StackVersion `json:"versions,omitempty"`
+}
+
+// Metadata is the EXIF metadata of an image.
+type Metadata struct {
+	Make      string `json:"make,omitempty"`
+	Model     string `json:"model,omitempty"`
+	LensModel string `json:"lensModel,omitempty"`
+
+	// TakenAt is the time at which the image was taken. The time zone of EXIF
+	// timestamps is unknown, so TakenAt is in UTC.
+	TakenAt time.Time `json:"takenAt"`
+
+	// ExposureTime is the exposure time in seconds.
+	ExposureTime float64 `json:"exposureTime,omitempty"`
+	FNumber      float64 `json:"fNumber,omitempty"`
+	ISO          int     `json:"iso,omitempty"`
+
+	// FocalLength is the focal length in millimeters.
+	FocalLength float64 `json:"focalLength,omitempty"`
+
+	// GPS are the coordinates at which the image was taken. GPS is only set if
+	// the EXIF Processor is configured to keep GPS coordinates.
+	GPS *exif.GPS `json:"gps,omitempty"`
 }
 
 // Image is an image of a Stack.
@@ -504,6 +1310,18 @@ type Image struct {
 
 	Original bool   `json:"original"`
 	Size     string `json:"size"`
+
+	// Format is the image format of a converted Image (e.g. "webp"). Format
+	// is empty for the original Image and Images in the original format.
+	Format string `json:"format,omitempty"`
+
+	// Stale is true if the original Image was replaced after this Image was
+	// generated from it. Stale Images should not be served to clients.
+	Stale bool `json:"stale,omitempty"`
+
+	// Cropped is true if the Image was cropped by the Cropper. Cropped Images
+	// become stale when the FocalPoint of their Stack changes.
+	Cropped bool `json:"cropped,omitempty"`
 }
 
 // Original returns the original image in the Stack.
@@ -516,6 +1334,24 @@ func (s Stack) Original() Image {
 	return Image{}
 }
 
+// Processed returns the largest processed Image of the Stack, which is the
+// Image that is served to public clients if the original images of a Gallery
+// are protected. Stale Images are skipped. If the Stack has no processed Image,
+// false is returned.
+func (s Stack) Processed() (Image, bool) {
+	var out Image
+	var found bool
+	for _, img := range s.Images {
+		if img.Original || img.Stale {
+			continue
+		}
+		if !found || img.Width*img.Height > out.Width*out.Height {
+			out, found = img, true
+		}
+	}
+	return out, found
+}
+
 // WithTag adds the given tags to each Image in the Stack and returns the
 // updated Stack. The original Stack is not modified.
 func (s Stack) WithTag(tags ...string) Stack {
@@ -523,6 +1359,9 @@ func (s Stack) WithTag(tags ...string) Stack {
 	for i, img := range s.Images {
 		s.Images[i].Image = img.WithTag(tags...)
 	}
+	if s.Video != nil {
+		*s.Video = s.Video.WithTag(tags...)
+	}
 	return s
 }
 
@@ -533,14 +1372,20 @@ func (s Stack) WithoutTag(tags ...string) Stack {
 	for i, img := range s.Images {
 		s.Images[i].Image = img.WithoutTag(tags...)
 	}
+	if s.Video != nil {
+		*s.Video = s.Video.WithoutTag(tags...)
+	}
 	return s
 }
 
-// Sizes returns all sizes of the image.
+// Sizes returns all sizes of the image. Sizes of stale Images are excluded.
 func (s Stack) Sizes() []string {
 	out := make([]string, 0, len(s.Images))
 	found := make(map[string]struct{})
 	for _, img := range s.Images {
+		if img.Stale {
+			continue
+		}
 		if _, ok := found[img.Size]; ok {
 			continue
 		}
@@ -550,82 +1395,63 @@ func (s Stack) Sizes() []string {
 	return out
 }
 
+// Stale returns whether the Stack has stale Images.
+func (s Stack) Stale() bool {
+	for _, img := range s.Images {
+		if img.Stale {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Stack) withoutStale() Stack {
+	s = s.copy()
+	images := s.Images[:0]
+	for _, img := range s.Images {
+		if !img.Stale {
+			images = append(images, img)
+		}
+	}
+	s.Images = images
+	return s
+}
+
 func (s Stack) copy() Stack {
 	images := make([]Image, len(s.Images))
 	copy(images, s.Images)
 	s.Images = images
+	if s.Palette != nil {
+		s.Palette = append([]string(nil), s.Palette...)
+	}
+	if s.Video != nil {
+		v := *s.Video
+		s.Video = &v
+	}
+	if s.Versions != nil {
+		s.Versions = append([]StackVersion(nil), s.Versions...)
+	}
 	return s
 }
 
-type goesRepository struct {
-	repo aggregate.Repository
+// IsVideo returns whether the Stack is a video Stack.
+func (s Stack) IsVideo() bool {
+	return s.Video != nil
 }
 
 // GoesRepository returns a Repository that uses an aggregate.Repository under
-// the hood.
-func GoesRepository(repo aggregate.Repository) Repository {
-	return &goesRepository{repo: repo}
-}
-
-func (r *goesRepository) Save(ctx context.Context, g *Gallery) error {
-	return r.repo.Save(ctx, g)
-}
-
-func (r *goesRepository) Fetch(ctx context.Context, id uuid.UUID) (*Gallery, error) {
-	g := New(id)
-	if err := r.repo.Fetch(ctx, g); err != nil {
-		return nil, err
-	}
-	return g, nil
-}
-
-func (r *goesRepository) Delete(ctx context.Context, g *Gallery) error {
-	return r.repo.Delete(ctx, g)
-}
-
-func (r *goesRepository) Use(ctx context.Context, id uuid.UUID, fn func(*Gallery) error) error {
-	var tries int
-	var lastError error
-
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
-	for {
-		if tries > 0 {
-			ms := time.Duration(rand.Intn(150-50)+50) * time.Millisecond
-			timer := time.NewTimer(ms)
-
-			select {
-			case <-ctx.Done():
-				timer.Stop()
-
-				if lastError != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
-					return lastError
-				}
-
-				return ctx.Err()
-			case <-timer.C:
-				timer.Stop()
-			}
-		}
-		tries++
-
-		g, err := r.Fetch(ctx, id)
-		if err != nil {
-			return fmt.Errorf("fetch gallery: %w", err)
-		}
-
-		if err := fn(g); err != nil {
-			return err
-		}
-
-		if err := r.Save(ctx, g); err != nil {
-			lastError = fmt.Errorf("save gallery: %w", err)
-			continue
-		}
-
-		return nil
-	}
+// the hood. Repository.Use retries to save the Gallery when it was changed
+// concurrently; the retries can be tuned with the Retry options (e.g.
+// RetryMaxTries). The provided HookOptions are registered on every fetched
+// Gallery:
+//
+//	galleries := gallery.GoesRepository(repo,
+//		gallery.RetryMaxTries(10),
+//		gallery.RetryBackoff(20*time.Millisecond, time.Second),
+//		gallery.AfterUpload(...),
+//	)
+func GoesRepository(repo aggregate.Repository, opts ...RepositoryOption) Repository {
+	return aggregateutil.NewRepository(repo, "gallery", New, opts...)
 }
 
 func (stacks Stacks) FindByTags(tags ...string) Stacks {
@@ -648,6 +1474,10 @@ func EventApplier(impl *Implementation) func(event.Event) {
 		switch evt.Name() {
 		case Created:
 			impl.create(evt)
+		case Deleted:
+			impl.delete(evt)
+		case Renamed:
+			impl.rename(evt)
 		case ImageUploaded:
 			impl.uploadImage(evt)
 		case ImageReplaced:
@@ -660,10 +1490,50 @@ func EventApplier(impl *Implementation) func(event.Event) {
 			impl.untagStack(evt)
 		case StackRenamed:
 			impl.renameStack(evt)
+		case StackMetadataUpdated:
+			impl.updateStackMetadata(evt)
+		case StackCustomMetadataSet:
+			impl.setCustomMetadata(evt)
+		case StackCustomMetadataDeleted:
+			impl.deleteCustomMetadata(evt)
+		case StackFocalPointSet:
+			impl.setFocalPoint(evt)
+		case StackCropped:
+			impl.cropStack(evt)
+		case StackReprocessingRequested:
+			impl.reprocessStack(evt)
 		case StackUpdated:
 			impl.updateStack(evt)
 		case Sorted:
 			impl.sort(evt)
+		case StackMoved:
+			impl.moveStack(evt)
+		case DefaultTagsChanged:
+			impl.changeDefaultTags(evt)
+		case ThemeUpdated:
+			impl.updateTheme(evt)
+		case OriginalsProtectionChanged:
+			impl.changeOriginalsProtection(evt)
+		case StackTrashed:
+			impl.trashStack(evt)
+		case StackRestored:
+			impl.restoreStack(evt)
+		case StackPurged:
+			impl.purgeStack(evt)
+		case StackRelocated:
+			impl.relocateStack(evt)
+		case LegalHoldSet:
+			impl.setLegalHold(evt)
+		case LegalHoldLifted:
+			impl.liftLegalHold(evt)
+		case VersionLimitChanged:
+			impl.changeVersionLimit(evt)
+		case StackProcessingStarted:
+			impl.startProcessing(evt)
+		case StackProcessed:
+			impl.finishProcessing(evt)
+		case StackProcessingFailed:
+			impl.failProcessing(evt)
 		}
 	}
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/modernice/nice-cms/internal/imggen"
 	"github.com/modernice/nice-cms/internal/slice"
 	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image"
 	"github.com/modernice/nice-cms/media/image/gallery"
 	"github.com/modernice/nice-cms/media/mock_media"
 )
@@ -77,6 +78,57 @@ func TestCreate_alreadyCreated(t *testing.T) {
 	}
 }
 
+func TestGallery_Rename_notCreated(t *testing.T) {
+	g := gallery.New(uuid.New())
+
+	if err := g.Rename("bar"); !errors.Is(err, gallery.ErrNotCreated) {
+		t.Fatalf("Rename should fail with %q if the Gallery hasn't been created yet; got %q", gallery.ErrNotCreated, err)
+	}
+
+	test.NoChange(t, g, gallery.Renamed)
+}
+
+func TestGallery_Rename(t *testing.T) {
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	if err := g.Rename(" bar "); err != nil {
+		t.Fatalf("Rename failed with %q", err)
+	}
+
+	if g.Implementation.Name != "bar" {
+		t.Fatalf("Name should be %q; is %q", "bar", g.Implementation.Name)
+	}
+
+	test.Change(t, g, gallery.Renamed, test.EventData(gallery.RenamedData{OldName: "foo", Name: "bar"}))
+}
+
+func TestGallery_Rename_emptyName(t *testing.T) {
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	if err := g.Rename("   "); !errors.Is(err, gallery.ErrEmptyName) {
+		t.Fatalf("Rename with an empty name should fail with %q; got %q", gallery.ErrEmptyName, err)
+	}
+
+	if g.Implementation.Name != "foo" {
+		t.Fatalf("Name should be %q; is %q", "foo", g.Implementation.Name)
+	}
+
+	test.NoChange(t, g, gallery.Renamed)
+}
+
+func TestGallery_Rename_sameName(t *testing.T) {
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	if err := g.Rename("foo"); err != nil {
+		t.Fatalf("Rename failed with %q", err)
+	}
+
+	test.NoChange(t, g, gallery.Renamed)
+}
+
 func TestGallery_Upload_notCreated(t *testing.T) {
 	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
 
@@ -346,6 +398,114 @@ func TestGallery_Replace(t *testing.T) {
 	}))
 }
 
+func TestGallery_FindStackByChecksum(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+	checksum, err := media.Checksum(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("compute checksum: %v", err)
+	}
+
+	stack, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	if stack.Checksum != checksum {
+		t.Fatalf("Checksum should be %q; is %q", checksum, stack.Checksum)
+	}
+
+	found, err := g.FindStackByChecksum(checksum)
+	if err != nil {
+		t.Fatalf("FindStackByChecksum shouldn't fail; failed with %q", err)
+	}
+
+	if found.ID != stack.ID {
+		t.Fatalf("FindStackByChecksum should return Stack %q; got %q", stack.ID, found.ID)
+	}
+
+	_, buf = imggen.ColoredRectangle(400, 500, color.RGBA{130, 100, 100, 0xff})
+	replacedChecksum, err := media.Checksum(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("compute checksum: %v", err)
+	}
+
+	if _, err := g.Replace(ctx, storage, buf, stack.ID); err != nil {
+		t.Fatalf("Replace failed with %q", err)
+	}
+
+	if _, err := g.FindStackByChecksum(checksum); !errors.Is(err, gallery.ErrStackNotFound) {
+		t.Fatalf("FindStackByChecksum should fail with %q for the checksum of the replaced image; got %q", gallery.ErrStackNotFound, err)
+	}
+
+	if found, err = g.FindStackByChecksum(replacedChecksum); err != nil || found.ID != stack.ID {
+		t.Fatalf("FindStackByChecksum should return Stack %q; got %q (%v)", stack.ID, found.ID, err)
+	}
+
+	if _, err := g.FindStackByChecksum(""); !errors.Is(err, gallery.ErrStackNotFound) {
+		t.Fatalf("FindStackByChecksum should fail with %q for an empty checksum; got %q", gallery.ErrStackNotFound, err)
+	}
+}
+
+func TestGallery_Replace_staleVariants(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+
+	uploaded, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	if err := g.Update(uploaded.ID, func(s gallery.Stack) gallery.Stack {
+		s.Images = append(s.Images, gallery.Image{
+			Image: media.NewImage(240, 180, exampleName, exampleDisk, "/foo_thumb.png", 0),
+			Size:  "thumb",
+		})
+		return s
+	}); err != nil {
+		t.Fatalf("update Stack: %v", err)
+	}
+
+	_, buf = imggen.ColoredRectangle(400, 500, color.RGBA{130, 100, 100, 0xff})
+
+	replaced, err := g.Replace(ctx, storage, buf, uploaded.ID)
+	if err != nil {
+		t.Fatalf("Replace failed with %q", err)
+	}
+
+	if len(replaced.Images) != 2 {
+		t.Fatalf("replaced Stack should contain 2 images; contains %d", len(replaced.Images))
+	}
+
+	if org := replaced.Original(); org.Stale {
+		t.Fatalf("original image should not be stale")
+	}
+
+	if !replaced.Images[1].Stale {
+		t.Fatalf("%q image should be stale", replaced.Images[1].Size)
+	}
+
+	if !replaced.Stale() {
+		t.Fatalf("Stale should return true")
+	}
+
+	if sizes := replaced.Sizes(); !reflect.DeepEqual(sizes, []string{""}) {
+		t.Fatalf("Sizes should exclude stale images; got %v", sizes)
+	}
+}
+
 func TestGallery_Tag_Untag(t *testing.T) {
 	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
 
@@ -407,6 +567,95 @@ func TestGallery_Tag_Untag(t *testing.T) {
 	}))
 }
 
+func TestGallery_SetDefaultTags_notCreated(t *testing.T) {
+	g := gallery.New(uuid.New())
+
+	if err := g.SetDefaultTags("foo"); !errors.Is(err, gallery.ErrNotCreated) {
+		t.Fatalf("SetDefaultTags should fail with %q if the Gallery hasn't been created yet; got %q", gallery.ErrNotCreated, err)
+	}
+}
+
+func TestGallery_SetDefaultTags(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	if err := g.SetDefaultTags("foo", "bar", "bar"); err != nil {
+		t.Fatalf("SetDefaultTags failed with %q", err)
+	}
+
+	if want := []string{"foo", "bar"}; !reflect.DeepEqual(g.DefaultTags, want) {
+		t.Fatalf("DefaultTags should be %v; is %v", want, g.DefaultTags)
+	}
+
+	test.Change(t, g, gallery.DefaultTagsChanged, test.EventData(gallery.DefaultTagsChangedData{
+		Tags: []string{"foo", "bar"},
+	}))
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+
+	stack, err := g.Upload(context.Background(), storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	if !stack.Original().HasTag("foo", "bar") {
+		t.Fatalf("uploaded image should have the default tags %v; has %v", g.DefaultTags, stack.Original().Tags)
+	}
+}
+
+func TestGallery_ProtectOriginals(t *testing.T) {
+	g := gallery.New(uuid.New())
+
+	if err := g.ProtectOriginals(true); !errors.Is(err, gallery.ErrNotCreated) {
+		t.Fatalf("ProtectOriginals should fail with %q if the Gallery hasn't been created yet; got %q", gallery.ErrNotCreated, err)
+	}
+
+	g.Create("foo")
+
+	if err := g.ProtectOriginals(false); err != nil {
+		t.Fatalf("ProtectOriginals failed with %q", err)
+	}
+
+	test.NoChange(t, g, gallery.OriginalsProtectionChanged)
+
+	if err := g.ProtectOriginals(true); err != nil {
+		t.Fatalf("ProtectOriginals failed with %q", err)
+	}
+
+	if !g.OriginalsProtected || !g.JSON().OriginalsProtected {
+		t.Fatalf("originals should be protected")
+	}
+
+	test.Change(t, g, gallery.OriginalsProtectionChanged, test.EventData(gallery.OriginalsProtectionChangedData{
+		Protected: true,
+	}))
+}
+
+func TestStack_Processed(t *testing.T) {
+	image := func(width, height int) media.Image {
+		return media.Image{Width: width, Height: height}
+	}
+
+	stack := gallery.Stack{Images: []gallery.Image{
+		{Image: image(4000, 3000), Original: true},
+		{Image: image(800, 600), Size: "small"},
+		{Image: image(2000, 1500), Size: "large", Stale: true},
+		{Image: image(1600, 1200), Size: "medium"},
+	}}
+
+	img, ok := stack.Processed()
+	if !ok || img.Size != "medium" {
+		t.Fatalf("Processed should return the largest processed, non-stale Image; got %v (%v)", img, ok)
+	}
+
+	stack.Images = stack.Images[:1]
+	if _, ok := stack.Processed(); ok {
+		t.Fatalf("Processed should return false for a Stack without processed Images")
+	}
+}
+
 func TestGallery_RenameStack_notCreated(t *testing.T) {
 	g := gallery.New(uuid.New())
 
@@ -455,6 +704,280 @@ func TestGallery_RenameStack(t *testing.T) {
 	}))
 }
 
+func TestGallery_UpdateStackMetadata_notCreated(t *testing.T) {
+	g := gallery.New(uuid.New())
+
+	if _, err := g.UpdateStackMetadata(context.Background(), uuid.New(), "alt", "caption"); !errors.Is(err, gallery.ErrNotCreated) {
+		t.Fatalf("UpdateStackMetadata should fail with %q if the Gallery hasn't been created yet; got %q", gallery.ErrNotCreated, err)
+	}
+}
+
+func TestGallery_UpdateStackMetadata(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+	uploaded, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	updated, err := g.UpdateStackMetadata(ctx, uploaded.ID, " A grey rectangle ", "Grey")
+	if err != nil {
+		t.Fatalf("UpdateStackMetadata failed with %q", err)
+	}
+
+	if updated.Alt != "A grey rectangle" || updated.Caption != "Grey" {
+		t.Fatalf("Alt and Caption should be %q and %q; are %q and %q", "A grey rectangle", "Grey", updated.Alt, updated.Caption)
+	}
+
+	test.Change(t, g, gallery.StackMetadataUpdated, test.EventData(gallery.StackMetadataUpdatedData{
+		StackID: uploaded.ID,
+		Alt:     "A grey rectangle",
+		Caption: "Grey",
+	}), test.Exactly(1))
+
+	if _, err := g.UpdateStackMetadata(ctx, uploaded.ID, "A grey rectangle", "Grey"); err != nil {
+		t.Fatalf("UpdateStackMetadata failed with %q", err)
+	}
+	test.Change(t, g, gallery.StackMetadataUpdated, test.Exactly(1))
+
+	_, buf = imggen.ColoredRectangle(400, 300, color.RGBA{200, 100, 100, 0xff})
+	replaced, err := g.Replace(ctx, storage, buf, uploaded.ID)
+	if err != nil {
+		t.Fatalf("Replace failed with %q", err)
+	}
+	if replaced.Alt != updated.Alt || replaced.Caption != updated.Caption {
+		t.Fatalf("Replace should keep Alt and Caption; got %q and %q", replaced.Alt, replaced.Caption)
+	}
+}
+
+func TestGallery_UpdateStackMetadata_stackNotFound(t *testing.T) {
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	if _, err := g.UpdateStackMetadata(context.Background(), uuid.New(), "alt", ""); !errors.Is(err, gallery.ErrStackNotFound) {
+		t.Fatalf("UpdateStackMetadata should fail with %q for an unknown Stack; got %q", gallery.ErrStackNotFound, err)
+	}
+
+	test.NoChange(t, g, gallery.StackMetadataUpdated)
+}
+
+func TestGallery_SetCustomMetadata_DeleteCustomMetadata(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+
+	if _, err := g.SetCustomMetadata(uuid.New(), map[string]string{"foo": "bar"}); !errors.Is(err, gallery.ErrNotCreated) {
+		t.Fatalf("SetCustomMetadata should fail with %q if the Gallery hasn't been created yet; got %q", gallery.ErrNotCreated, err)
+	}
+
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+	stack, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	stack, err = g.SetCustomMetadata(stack.ID, map[string]string{" copyright ": " ACME ", "source": "https://example.com"})
+	if err != nil {
+		t.Fatalf("SetCustomMetadata failed with %q", err)
+	}
+
+	want := map[string]string{"copyright": "ACME", "source": "https://example.com"}
+	if !reflect.DeepEqual(stack.CustomMetadata, want) {
+		t.Fatalf("CustomMetadata should be %v; is %v", want, stack.CustomMetadata)
+	}
+
+	test.Change(t, g, gallery.StackCustomMetadataSet, test.EventData(gallery.StackCustomMetadataSetData{
+		StackID: stack.ID,
+		Fields:  want,
+	}))
+
+	if _, err := g.SetCustomMetadata(stack.ID, map[string]string{"": "foo"}); !errors.Is(err, media.ErrEmptyMetadataKey) {
+		t.Fatalf("SetCustomMetadata should fail with %q for an empty key; got %q", media.ErrEmptyMetadataKey, err)
+	}
+
+	_, buf = imggen.ColoredRectangle(400, 300, color.RGBA{200, 100, 100, 0xff})
+	replaced, err := g.Replace(ctx, storage, buf, stack.ID)
+	if err != nil {
+		t.Fatalf("Replace failed with %q", err)
+	}
+	if !reflect.DeepEqual(replaced.CustomMetadata, want) {
+		t.Fatalf("Replace should keep the CustomMetadata %v; got %v", want, replaced.CustomMetadata)
+	}
+
+	stack, err = g.DeleteCustomMetadata(stack.ID, "copyright", "source")
+	if err != nil {
+		t.Fatalf("DeleteCustomMetadata failed with %q", err)
+	}
+	if stack.CustomMetadata != nil {
+		t.Fatalf("CustomMetadata should be nil after deleting all fields; is %v", stack.CustomMetadata)
+	}
+
+	test.Change(t, g, gallery.StackCustomMetadataDeleted, test.EventData(gallery.StackCustomMetadataDeletedData{
+		StackID: stack.ID,
+		Keys:    []string{"copyright", "source"},
+	}))
+
+	if _, err := g.DeleteCustomMetadata(stack.ID, "copyright"); err != nil {
+		t.Fatalf("DeleteCustomMetadata failed with %q", err)
+	}
+	test.Change(t, g, gallery.StackCustomMetadataDeleted, test.Exactly(1))
+}
+
+func TestGallery_SetFocalPoint(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+
+	if _, err := g.SetFocalPoint(uuid.New(), image.Center); !errors.Is(err, gallery.ErrNotCreated) {
+		t.Fatalf("SetFocalPoint should fail with %q if the Gallery hasn't been created yet; got %q", gallery.ErrNotCreated, err)
+	}
+
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+	stack, err := g.Upload(context.Background(), storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	if _, err := g.SetFocalPoint(stack.ID, image.FocalPoint{X: 101, Y: 50}); !errors.Is(err, gallery.ErrInvalidFocalPoint) {
+		t.Fatalf("SetFocalPoint should fail with %q for a point outside of the image; got %q", gallery.ErrInvalidFocalPoint, err)
+	}
+
+	focus := image.FocalPoint{X: 30, Y: 70}
+	stack, err = g.SetFocalPoint(stack.ID, focus)
+	if err != nil {
+		t.Fatalf("SetFocalPoint failed with %q", err)
+	}
+
+	if stack.FocalPoint == nil || *stack.FocalPoint != focus {
+		t.Fatalf("FocalPoint should be %v; is %v", focus, stack.FocalPoint)
+	}
+
+	test.Change(t, g, gallery.StackFocalPointSet, test.EventData(gallery.StackFocalPointSetData{
+		StackID:    stack.ID,
+		FocalPoint: focus,
+	}))
+
+	if _, err := g.SetFocalPoint(stack.ID, focus); err != nil {
+		t.Fatalf("SetFocalPoint failed with %q", err)
+	}
+	test.Change(t, g, gallery.StackFocalPointSet, test.Exactly(1))
+}
+
+func TestGallery_CropStack(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+
+	if _, err := g.CropStack(uuid.New(), image.Full); !errors.Is(err, gallery.ErrNotCreated) {
+		t.Fatalf("CropStack should fail with %q if the Gallery hasn't been created yet; got %q", gallery.ErrNotCreated, err)
+	}
+
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+	stack, err := g.Upload(context.Background(), storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	stack.Images = append(stack.Images, gallery.Image{Size: "small"})
+	if err := g.Update(stack.ID, func(gallery.Stack) gallery.Stack { return stack }); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+
+	for _, area := range []image.Area{
+		{X: 60, Y: 0, Width: 50, Height: 50},
+		{X: 0, Y: 0, Width: 0, Height: 50},
+	} {
+		if _, err := g.CropStack(stack.ID, area); !errors.Is(err, gallery.ErrInvalidCrop) {
+			t.Fatalf("CropStack should fail with %q for %v; got %q", gallery.ErrInvalidCrop, area, err)
+		}
+	}
+
+	area := image.Area{X: 10, Y: 20, Width: 50, Height: 50}
+	stack, err = g.CropStack(stack.ID, area)
+	if err != nil {
+		t.Fatalf("CropStack failed with %q", err)
+	}
+
+	if stack.Crop == nil || *stack.Crop != area {
+		t.Fatalf("Crop should be %v; is %v", area, stack.Crop)
+	}
+
+	for _, img := range stack.Images {
+		if img.Stale == img.Original {
+			t.Fatalf("only the resized Images should be stale; got %v", stack.Images)
+		}
+	}
+
+	test.Change(t, g, gallery.StackCropped, test.EventData(gallery.StackCroppedData{
+		StackID: stack.ID,
+		Crop:    area,
+	}))
+
+	if _, err := g.CropStack(stack.ID, area); err != nil {
+		t.Fatalf("CropStack failed with %q", err)
+	}
+	test.Change(t, g, gallery.StackCropped, test.Exactly(1))
+
+	if stack, err = g.CropStack(stack.ID, image.Full); err != nil {
+		t.Fatalf("CropStack failed with %q", err)
+	}
+	if stack.Crop != nil {
+		t.Fatalf("cropping a Stack to the full image should remove the Crop; Crop is %v", stack.Crop)
+	}
+}
+
+func TestGallery_ReprocessStack(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+
+	if _, err := g.ReprocessStack(uuid.New()); !errors.Is(err, gallery.ErrNotCreated) {
+		t.Fatalf("ReprocessStack should fail with %q if the Gallery hasn't been created yet; got %q", gallery.ErrNotCreated, err)
+	}
+
+	g.Create("foo")
+
+	if _, err := g.ReprocessStack(uuid.New()); !errors.Is(err, gallery.ErrStackNotFound) {
+		t.Fatalf("ReprocessStack should fail with %q for an unknown Stack; got %q", gallery.ErrStackNotFound, err)
+	}
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+	stack, err := g.Upload(context.Background(), storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	stack.Images = append(stack.Images, gallery.Image{Size: "small"}, gallery.Image{Format: "webp"})
+	if err := g.Update(stack.ID, func(gallery.Stack) gallery.Stack { return stack }); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+
+	stack, err = g.ReprocessStack(stack.ID)
+	if err != nil {
+		t.Fatalf("ReprocessStack failed with %q", err)
+	}
+
+	for _, img := range stack.Images {
+		if img.Stale == img.Original {
+			t.Fatalf("all Images except the original should be stale; got %v", stack.Images)
+		}
+	}
+
+	test.Change(t, g, gallery.StackReprocessingRequested, test.EventData(gallery.StackReprocessingRequestedData{
+		StackID: stack.ID,
+	}))
+
+	if _, err := g.ReprocessAll(); err != nil {
+		t.Fatalf("ReprocessAll failed with %q", err)
+	}
+	test.Change(t, g, gallery.StackReprocessingRequested, test.Exactly(2))
+}
+
 func TestGallery_Update(t *testing.T) {
 	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
 
@@ -488,6 +1011,88 @@ func TestGallery_Update(t *testing.T) {
 	test.Change(t, g, gallery.StackUpdated, test.EventData(gallery.StackUpdatedData{Stack: replacement}))
 }
 
+func TestGallery_StartProcessing_FinishProcessing(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+
+	stack, err := g.Upload(context.Background(), storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	if err := g.StartProcessing(stack.ID); err != nil {
+		t.Fatalf("StartProcessing failed with %q", err)
+	}
+
+	if got, _ := g.Stack(stack.ID); !got.Pending {
+		t.Fatalf("Stack should be pending")
+	}
+
+	test.Change(t, g, gallery.StackProcessingStarted, test.EventData(gallery.StackProcessingStartedData{StackID: stack.ID}))
+
+	processed := stack.WithTag("foo")
+	if err := g.FinishProcessing(processed); err != nil {
+		t.Fatalf("FinishProcessing failed with %q", err)
+	}
+
+	got, _ := g.Stack(stack.ID)
+	if got.Pending {
+		t.Fatalf("Stack should not be pending")
+	}
+
+	if got.ProcessedAt.IsZero() {
+		t.Fatalf("ProcessedAt should be set")
+	}
+
+	if !got.Original().HasTag("foo") {
+		t.Fatalf("Stack should be replaced with the processed Stack")
+	}
+
+	test.Change(t, g, gallery.StackProcessed, test.EventData(gallery.StackProcessedData{Stack: processed}))
+}
+
+func TestGallery_FailProcessing(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+
+	stack, err := g.Upload(context.Background(), storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	g.StartProcessing(stack.ID)
+
+	if err := g.FailProcessing(stack.ID, errors.New("mock error")); err != nil {
+		t.Fatalf("FailProcessing failed with %q", err)
+	}
+
+	got, _ := g.Stack(stack.ID)
+	if got.Pending {
+		t.Fatalf("Stack should not be pending")
+	}
+
+	if got.ProcessingError != "mock error" {
+		t.Fatalf("ProcessingError should be %q; is %q", "mock error", got.ProcessingError)
+	}
+
+	test.Change(t, g, gallery.StackProcessingFailed, test.EventData(gallery.StackProcessingFailedData{
+		StackID: stack.ID,
+		Error:   "mock error",
+	}))
+
+	if err := g.StartProcessing(uuid.New()); !errors.Is(err, gallery.ErrStackNotFound) {
+		t.Fatalf("StartProcessing should fail with %q for an unknown Stack; got %q", gallery.ErrStackNotFound, err)
+	}
+}
+
 func TestGallery_Sort(t *testing.T) {
 
 	stacks := gallery.Stacks{
@@ -573,6 +1178,131 @@ func TestGallery_Sort(t *testing.T) {
 	}
 }
 
+func TestGallery_MoveStackBefore(t *testing.T) {
+	stacks := gallery.Stacks{
+		{ID: uuid.New()},
+		{ID: uuid.New()},
+		{ID: uuid.New()},
+		{ID: uuid.New()},
+	}
+
+	tests := []struct {
+		name   string
+		stack  uuid.UUID
+		before uuid.UUID
+		want   gallery.Stacks
+	}{
+		{
+			name:   "to front",
+			stack:  stacks[2].ID,
+			before: stacks[0].ID,
+			want:   gallery.Stacks{stacks[2], stacks[0], stacks[1], stacks[3]},
+		},
+		{
+			name:   "backwards",
+			stack:  stacks[0].ID,
+			before: stacks[3].ID,
+			want:   gallery.Stacks{stacks[1], stacks[2], stacks[0], stacks[3]},
+		},
+		{
+			name:   "to end",
+			stack:  stacks[1].ID,
+			before: uuid.Nil,
+			want:   gallery.Stacks{stacks[0], stacks[2], stacks[3], stacks[1]},
+		},
+		{
+			name:   "same position",
+			stack:  stacks[1].ID,
+			before: stacks[2].ID,
+			want:   stacks,
+		},
+		{
+			name:   "before itself",
+			stack:  stacks[1].ID,
+			before: stacks[1].ID,
+			want:   stacks,
+		},
+		{
+			name:   "last to end",
+			stack:  stacks[3].ID,
+			before: uuid.Nil,
+			want:   stacks,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gallery.New(uuid.New())
+			g.Create("foo")
+
+			g.Stacks = make(gallery.Stacks, len(stacks))
+			copy(g.Stacks, stacks)
+
+			if err := g.MoveStackBefore(tt.stack, tt.before); err != nil {
+				t.Fatalf("MoveStackBefore failed with %q", err)
+			}
+
+			if !cmp.Equal(tt.want, g.Stacks) {
+				t.Fatalf("Stacks have wrong order.\n\n%s", cmp.Diff(tt.want, g.Stacks))
+			}
+
+			if cmp.Equal(stacks, tt.want) {
+				test.NoChange(t, g, gallery.StackMoved)
+				return
+			}
+
+			test.Change(t, g, gallery.StackMoved, test.EventData(gallery.StackMovedData{
+				StackID:  tt.stack,
+				BeforeID: tt.before,
+			}))
+		})
+	}
+}
+
+func TestGallery_MoveStackBefore_merge(t *testing.T) {
+	stacks := gallery.Stacks{
+		{ID: uuid.New()},
+		{ID: uuid.New()},
+		{ID: uuid.New()},
+		{ID: uuid.New()},
+	}
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+	g.Stacks = make(gallery.Stacks, len(stacks))
+	copy(g.Stacks, stacks)
+
+	// Two editors move different stacks based on the same order. Applying both
+	// intents keeps both moves, whereas a full Sort would drop the first one.
+	if err := g.MoveStackBefore(stacks[3].ID, stacks[0].ID); err != nil {
+		t.Fatalf("MoveStackBefore failed with %q", err)
+	}
+	if err := g.MoveStackBefore(stacks[0].ID, uuid.Nil); err != nil {
+		t.Fatalf("MoveStackBefore failed with %q", err)
+	}
+
+	want := gallery.Stacks{stacks[3], stacks[1], stacks[2], stacks[0]}
+	if !cmp.Equal(want, g.Stacks) {
+		t.Fatalf("Stacks have wrong order.\n\n%s", cmp.Diff(want, g.Stacks))
+	}
+}
+
+func TestGallery_MoveStackBefore_notFound(t *testing.T) {
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+	g.Stacks = gallery.Stacks{{ID: uuid.New()}}
+
+	if err := g.MoveStackBefore(uuid.New(), uuid.Nil); !errors.Is(err, gallery.ErrStackNotFound) {
+		t.Fatalf("MoveStackBefore should fail with %q for an unknown Stack; got %q", gallery.ErrStackNotFound, err)
+	}
+
+	if err := g.MoveStackBefore(g.Stacks[0].ID, uuid.New()); !errors.Is(err, gallery.ErrStackNotFound) {
+		t.Fatalf("MoveStackBefore should fail with %q for an unknown target Stack; got %q", gallery.ErrStackNotFound, err)
+	}
+
+	test.NoChange(t, g, gallery.StackMoved)
+}
+
 func expectStorageFileContents(t *testing.T, storage media.Storage, diskName, path string, contents []byte) {
 	disk, err := storage.Disk(diskName)
 	if err != nil {
@@ -0,0 +1,108 @@
+// Package gallerymongo provides MongoDB implementations of gallery services.
+package gallerymongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type jobStore struct {
+	col *mongo.Collection
+}
+
+type jobDocument struct {
+	ID        string    `bson:"_id"`
+	GalleryID string    `bson:"galleryId"`
+	StackID   string    `bson:"stackId"`
+	Attempts  int       `bson:"attempts"`
+	QueuedAt  time.Time `bson:"queuedAt"`
+}
+
+// JobStore returns a gallery.JobStore that persists ProcessingJobs as
+// documents in the given MongoDB collection:
+//
+//	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+//	store := gallerymongo.JobStore(client.Database("cms").Collection("processing_jobs"))
+//	errs, err := svc.Run(ctx, bus, pipe, gallery.ProcessorJobStore(store))
+func JobStore(col *mongo.Collection) gallery.JobStore {
+	return &jobStore{col: col}
+}
+
+func (s *jobStore) Save(ctx context.Context, job gallery.ProcessingJob) error {
+	doc := jobDocument{
+		ID:        job.ID.String(),
+		GalleryID: job.GalleryID.String(),
+		StackID:   job.StackID.String(),
+		Attempts:  job.Attempts,
+		QueuedAt:  job.QueuedAt,
+	}
+
+	if _, err := s.col.ReplaceOne(ctx, bson.M{"_id": doc.ID}, doc, options.Replace().SetUpsert(true)); err != nil {
+		return fmt.Errorf("mongo: replace job %q: %w", job.ID, err)
+	}
+
+	return nil
+}
+
+func (s *jobStore) Jobs(ctx context.Context) ([]gallery.ProcessingJob, error) {
+	cur, err := s.col.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "queuedAt", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("mongo: find jobs: %w", err)
+	}
+
+	var docs []jobDocument
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("mongo: decode jobs: %w", err)
+	}
+
+	jobs := make([]gallery.ProcessingJob, 0, len(docs))
+	for _, doc := range docs {
+		job, err := doc.job()
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	gallery.SortJobs(jobs)
+
+	return jobs, nil
+}
+
+func (s *jobStore) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.col.DeleteOne(ctx, bson.M{"_id": id.String()}); err != nil {
+		return fmt.Errorf("mongo: delete job %q: %w", id, err)
+	}
+	return nil
+}
+
+func (doc jobDocument) job() (gallery.ProcessingJob, error) {
+	var (
+		job gallery.ProcessingJob
+		err error
+	)
+
+	for _, id := range []struct {
+		raw  string
+		dest *uuid.UUID
+	}{
+		{raw: doc.ID, dest: &job.ID},
+		{raw: doc.GalleryID, dest: &job.GalleryID},
+		{raw: doc.StackID, dest: &job.StackID},
+	} {
+		if *id.dest, err = uuid.Parse(id.raw); err != nil {
+			return job, fmt.Errorf("mongo: parse UUID %q of job %q: %w", id.raw, doc.ID, err)
+		}
+	}
+
+	job.Attempts = doc.Attempts
+	job.QueuedAt = doc.QueuedAt
+
+	return job, nil
+}
@@ -0,0 +1,279 @@
+// Package galleryprom exports the processing metrics of galleries in the
+// Prometheus text exposition format.
+//
+// Metrics implements gallery.Instrumentation and http.Handler, so it can be
+// passed to the PostProcessor and mounted as the scrape endpoint:
+//
+//	metrics := galleryprom.New()
+//	errs, err := svc.Run(ctx, bus, pipe, gallery.ProcessorInstrumentation(metrics))
+//	http.Handle("/metrics", metrics)
+package galleryprom
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+// DefaultBuckets are the default upper bounds (in seconds) of the duration
+// histograms.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// ContentType is the content type of the exposed metrics.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var _ gallery.Instrumentation = (*Metrics)(nil)
+
+// Metrics collects the processing metrics of galleries. Use New to create
+// Metrics. The following metrics are exposed (prefixed with the namespace):
+//
+//   - processing_jobs_queued (gauge): Stacks waiting for a worker
+//   - processing_jobs_dropped_total (counter): jobs dropped because the queue
+//     was full
+//   - processing_jobs_total{status} (counter): processed jobs by status
+//     ("succeeded" or "failed")
+//   - processing_job_duration_seconds (histogram): duration of processed jobs
+//   - processor_duration_seconds{processor} (histogram): duration of each
+//     Processor
+//   - processor_failures_total{processor} (counter): failed Processors
+type Metrics struct {
+	namespace string
+	buckets   []float64
+
+	mux               sync.Mutex
+	queued            int64
+	dropped           uint64
+	jobs              map[string]uint64
+	jobDuration       *histogram
+	processorDuration map[string]*histogram
+	processorFailures map[string]uint64
+}
+
+// Option is an option for Metrics.
+type Option func(*Metrics)
+
+// Namespace returns an Option that sets the prefix of the metric names.
+// Default namespace is "gallery".
+func Namespace(ns string) Option {
+	return func(m *Metrics) {
+		m.namespace = ns
+	}
+}
+
+// Buckets returns an Option that sets the upper bounds (in seconds) of the
+// duration histograms. Default buckets are DefaultBuckets.
+func Buckets(buckets ...float64) Option {
+	return func(m *Metrics) {
+		m.buckets = buckets
+	}
+}
+
+// New returns Metrics.
+func New(opts ...Option) *Metrics {
+	m := Metrics{
+		namespace:         "gallery",
+		buckets:           DefaultBuckets,
+		jobs:              make(map[string]uint64),
+		processorDuration: make(map[string]*histogram),
+		processorFailures: make(map[string]uint64),
+	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+
+	buckets := append([]float64(nil), m.buckets...)
+	sort.Float64s(buckets)
+	m.buckets = buckets
+	m.jobDuration = newHistogram(m.buckets)
+
+	return &m
+}
+
+// JobQueued implements gallery.Instrumentation.
+func (m *Metrics) JobQueued() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.queued++
+}
+
+// JobStarted implements gallery.Instrumentation.
+func (m *Metrics) JobStarted() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.queued > 0 {
+		m.queued--
+	}
+}
+
+// JobDropped implements gallery.Instrumentation.
+func (m *Metrics) JobDropped() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.queued > 0 {
+		m.queued--
+	}
+	m.dropped++
+}
+
+// JobProcessed implements gallery.Instrumentation.
+func (m *Metrics) JobProcessed(d time.Duration, err error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.jobs[status(err)]++
+	m.jobDuration.observe(d.Seconds())
+}
+
+// ProcessorDone implements gallery.Instrumentation.
+func (m *Metrics) ProcessorDone(processor string, d time.Duration, err error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	h, ok := m.processorDuration[processor]
+	if !ok {
+		h = newHistogram(m.buckets)
+		m.processorDuration[processor] = h
+	}
+	h.observe(d.Seconds())
+
+	if err != nil {
+		m.processorFailures[processor]++
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	if err := m.Write(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Write writes the metrics in the Prometheus text exposition format to w.
+func (m *Metrics) Write(w io.Writer) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	var b strings.Builder
+
+	name := m.name("processing_jobs_queued")
+	header(&b, name, "gauge", "Stacks that wait for a worker of the post-processor.")
+	fmt.Fprintf(&b, "%s %d\n", name, m.queued)
+
+	name = m.name("processing_jobs_dropped_total")
+	header(&b, name, "counter", "Processing jobs that were dropped because the queue was full.")
+	fmt.Fprintf(&b, "%s %d\n", name, m.dropped)
+
+	name = m.name("processing_jobs_total")
+	header(&b, name, "counter", "Processing jobs by status.")
+	for _, st := range []string{"failed", "succeeded"} {
+		fmt.Fprintf(&b, "%s{status=%q} %d\n", name, st, m.jobs[st])
+	}
+
+	name = m.name("processing_job_duration_seconds")
+	header(&b, name, "histogram", "Duration of processing jobs.")
+	m.jobDuration.write(&b, name, "")
+
+	name = m.name("processor_duration_seconds")
+	header(&b, name, "histogram", "Duration of processors.")
+	for _, proc := range sortedKeys(m.processorDuration) {
+		m.processorDuration[proc].write(&b, name, label("processor", proc))
+	}
+
+	name = m.name("processor_failures_total")
+	header(&b, name, "counter", "Failed processors.")
+	for _, proc := range sortedKeys(m.processorDuration) {
+		fmt.Fprintf(&b, "%s{%s} %d\n", name, label("processor", proc), m.processorFailures[proc])
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (m *Metrics) name(metric string) string {
+	if m.namespace == "" {
+		return metric
+	}
+	return m.namespace + "_" + metric
+}
+
+type histogram struct {
+	bounds []float64
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)),
+	}
+}
+
+func (h *histogram) observe(v float64) {
+	for i, bound := range h.bounds {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+func (h *histogram) write(b *strings.Builder, name, labels string) {
+	prefix := labels
+	if prefix != "" {
+		prefix += ","
+	}
+	for i, bound := range h.bounds {
+		fmt.Fprintf(b, "%s_bucket{%sle=%q} %d\n", name, prefix, formatFloat(bound), h.counts[i])
+	}
+	fmt.Fprintf(b, "%s_bucket{%sle=\"+Inf\"} %d\n", name, prefix, h.count)
+
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(b, "%s_sum%s %s\n", name, labels, formatFloat(h.sum))
+	fmt.Fprintf(b, "%s_count%s %d\n", name, labels, h.count)
+}
+
+func header(b *strings.Builder, name, typ, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, typ)
+}
+
+func label(name, value string) string {
+	value = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(value)
+	return fmt.Sprintf(`%s="%s"`, name, value)
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func status(err error) string {
+	if err != nil {
+		return "failed"
+	}
+	return "succeeded"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,73 @@
+package galleryprom_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modernice/nice-cms/media/image/gallery/galleryprom"
+)
+
+func TestMetrics(t *testing.T) {
+	m := galleryprom.New(galleryprom.Buckets(1, 0.5))
+
+	m.JobQueued()
+	m.JobQueued()
+	m.JobQueued()
+	m.JobQueued()
+	m.JobStarted()
+	m.JobStarted()
+	m.JobDropped()
+	m.JobProcessed(300*time.Millisecond, nil)
+	m.JobProcessed(2*time.Second, errors.New("mock error"))
+	m.ProcessorDone("Resizer", 750*time.Millisecond, nil)
+	m.ProcessorDone("Cropper", 100*time.Millisecond, errors.New("mock error"))
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != galleryprom.ContentType {
+		t.Fatalf("Content-Type should be %q; is %q", galleryprom.ContentType, ct)
+	}
+
+	body := rec.Body.String()
+
+	for _, line := range []string{
+		"# TYPE gallery_processing_jobs_queued gauge",
+		"gallery_processing_jobs_queued 1",
+		"gallery_processing_jobs_dropped_total 1",
+		`gallery_processing_jobs_total{status="failed"} 1`,
+		`gallery_processing_jobs_total{status="succeeded"} 1`,
+		"# TYPE gallery_processing_job_duration_seconds histogram",
+		`gallery_processing_job_duration_seconds_bucket{le="0.5"} 1`,
+		`gallery_processing_job_duration_seconds_bucket{le="1"} 1`,
+		`gallery_processing_job_duration_seconds_bucket{le="+Inf"} 2`,
+		"gallery_processing_job_duration_seconds_sum 2.3",
+		"gallery_processing_job_duration_seconds_count 2",
+		`gallery_processor_duration_seconds_bucket{processor="Resizer",le="0.5"} 0`,
+		`gallery_processor_duration_seconds_bucket{processor="Resizer",le="1"} 1`,
+		`gallery_processor_duration_seconds_count{processor="Cropper"} 1`,
+		`gallery_processor_failures_total{processor="Cropper"} 1`,
+		`gallery_processor_failures_total{processor="Resizer"} 0`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("metrics should contain %q\n\n%s", line, body)
+		}
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	m := galleryprom.New(galleryprom.Namespace("cms"))
+
+	var b strings.Builder
+	if err := m.Write(&b); err != nil {
+		t.Fatalf("Write failed with %q", err)
+	}
+
+	if !strings.Contains(b.String(), "\ncms_processing_jobs_queued 0\n") {
+		t.Fatalf("metrics should be prefixed with %q\n\n%s", "cms", b.String())
+	}
+}
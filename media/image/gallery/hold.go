@@ -0,0 +1,131 @@
+package gallery
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/media"
+)
+
+// ErrLegalHold is returned when a Stack that is under legal hold, or that
+// belongs to a Gallery under legal hold, would be deleted. While a legal hold
+// is set, Delete, Replace, Trash and Purge fail with ErrLegalHold, and the
+// Janitor skips the held Stacks.
+var ErrLegalHold = errors.New("stack under legal hold")
+
+// SetLegalHold sets a legal hold on the Stack with the given UUID, which may
+// also be in the trash of the Gallery. If id is uuid.Nil, the legal hold is set
+// on the whole Gallery. If the Stack is already held for the same reason,
+// SetLegalHold does nothing.
+func (g *Implementation) SetLegalHold(id uuid.UUID, reason string) error {
+	if err := g.checkCreated(); err != nil {
+		return err
+	}
+
+	current, err := g.legalHold(id)
+	if err != nil {
+		return err
+	}
+
+	if current != nil && current.Reason == reason {
+		return nil
+	}
+
+	aggregate.NextEvent(g.gallery, LegalHoldSet, LegalHoldSetData{
+		StackID: id,
+		Reason:  reason,
+	})
+
+	return nil
+}
+
+func (g *Implementation) setLegalHold(evt event.Event) {
+	data := evt.Data().(LegalHoldSetData)
+	g.updateLegalHold(data.StackID, &media.LegalHold{
+		Reason: data.Reason,
+		Since:  evt.Time(),
+	})
+}
+
+// LiftLegalHold lifts the legal hold of the Stack with the given UUID. If id is
+// uuid.Nil, the legal hold of the whole Gallery is lifted. Legal holds of
+// single Stacks are not lifted by lifting the legal hold of the Gallery. If the
+// Stack is not held, LiftLegalHold does nothing.
+func (g *Implementation) LiftLegalHold(id uuid.UUID) error {
+	if err := g.checkCreated(); err != nil {
+		return err
+	}
+
+	current, err := g.legalHold(id)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		return nil
+	}
+
+	aggregate.NextEvent(g.gallery, LegalHoldLifted, LegalHoldLiftedData{StackID: id})
+
+	return nil
+}
+
+func (g *Implementation) liftLegalHold(evt event.Event) {
+	data := evt.Data().(LegalHoldLiftedData)
+	g.updateLegalHold(data.StackID, nil)
+}
+
+// Held returns whether the Stack with the given UUID is under legal hold,
+// either by itself or through the Gallery.
+func (g *Implementation) Held(id uuid.UUID) bool {
+	if g.LegalHold != nil {
+		return true
+	}
+	hold, _ := g.legalHold(id)
+	return hold != nil
+}
+
+// checkHold returns ErrLegalHold if the Stack with the given UUID or the
+// Gallery is under legal hold.
+func (g *Implementation) checkHold(id uuid.UUID) error {
+	if g.Held(id) {
+		return ErrLegalHold
+	}
+	return nil
+}
+
+// legalHold returns the legal hold of the Stack with the given UUID, or of the
+// Gallery if id is uuid.Nil.
+func (g *Implementation) legalHold(id uuid.UUID) (*media.LegalHold, error) {
+	if id == uuid.Nil {
+		return g.LegalHold, nil
+	}
+	if i := g.stackIndex(id); i >= 0 {
+		return g.Stacks[i].LegalHold, nil
+	}
+	for _, s := range g.Trashed {
+		if s.ID == id {
+			return s.LegalHold, nil
+		}
+	}
+	return nil, ErrStackNotFound
+}
+
+func (g *Implementation) updateLegalHold(id uuid.UUID, hold *media.LegalHold) {
+	if id == uuid.Nil {
+		g.LegalHold = hold
+		return
+	}
+	if i := g.stackIndex(id); i >= 0 {
+		g.Stacks[i].LegalHold = hold
+		return
+	}
+	for i, s := range g.Trashed {
+		if s.ID == id {
+			g.Trashed[i].LegalHold = hold
+			return
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package gallery_test
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+func TestGallery_SetLegalHold(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	stack := uploadStack(t, g.Implementation, storage)
+
+	if err := g.SetLegalHold(stack.ID, "case-1"); err != nil {
+		t.Fatalf("SetLegalHold failed with %q", err)
+	}
+
+	held, _ := g.Stack(stack.ID)
+	if held.LegalHold == nil || held.LegalHold.Reason != "case-1" || held.LegalHold.Since.IsZero() {
+		t.Fatalf("Stack should be under legal hold; LegalHold is %v", held.LegalHold)
+	}
+
+	test.Change(t, g, gallery.LegalHoldSet, test.EventData(gallery.LegalHoldSetData{
+		StackID: stack.ID,
+		Reason:  "case-1",
+	}))
+
+	if err := g.Delete(ctx, storage, stack); !errors.Is(err, gallery.ErrLegalHold) {
+		t.Fatalf("Delete should fail with %q; got %q", gallery.ErrLegalHold, err)
+	}
+
+	if _, err := g.Trash(stack.ID); !errors.Is(err, gallery.ErrLegalHold) {
+		t.Fatalf("Trash should fail with %q; got %q", gallery.ErrLegalHold, err)
+	}
+
+	_, buf := imggen.ColoredRectangle(400, 300, color.RGBA{200, 100, 100, 0xff})
+	if _, err := g.Replace(ctx, storage, buf, stack.ID); !errors.Is(err, gallery.ErrLegalHold) {
+		t.Fatalf("Replace should fail with %q; got %q", gallery.ErrLegalHold, err)
+	}
+
+	if _, err := g.Tag(ctx, held, "foo"); err != nil {
+		t.Fatalf("Tag failed with %q", err)
+	}
+
+	if tagged, _ := g.Stack(stack.ID); tagged.LegalHold == nil {
+		t.Fatalf("legal hold should be kept when the Stack is updated")
+	}
+}
+
+func TestGallery_SetLegalHold_gallery(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	stack := uploadStack(t, g.Implementation, storage)
+
+	if err := g.SetLegalHold(uuid.Nil, "case-1"); err != nil {
+		t.Fatalf("SetLegalHold failed with %q", err)
+	}
+
+	if g.LegalHold == nil {
+		t.Fatalf("Gallery should be under legal hold")
+	}
+
+	if _, err := g.Trash(stack.ID); !errors.Is(err, gallery.ErrLegalHold) {
+		t.Fatalf("Trash should fail with %q; got %q", gallery.ErrLegalHold, err)
+	}
+
+	if err := g.LiftLegalHold(uuid.Nil); err != nil {
+		t.Fatalf("LiftLegalHold failed with %q", err)
+	}
+
+	test.Change(t, g, gallery.LegalHoldLifted, test.EventData(gallery.LegalHoldLiftedData{StackID: uuid.Nil}))
+
+	if _, err := g.Trash(stack.ID); err != nil {
+		t.Fatalf("Trash failed with %q", err)
+	}
+}
+
+func TestGallery_SetLegalHold_trashed(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	stack := uploadStack(t, g.Implementation, storage)
+
+	if _, err := g.Trash(stack.ID); err != nil {
+		t.Fatalf("Trash failed with %q", err)
+	}
+
+	if err := g.SetLegalHold(stack.ID, ""); err != nil {
+		t.Fatalf("SetLegalHold failed with %q", err)
+	}
+
+	if err := g.Purge(ctx, storage, stack.ID); !errors.Is(err, gallery.ErrLegalHold) {
+		t.Fatalf("Purge should fail with %q; got %q", gallery.ErrLegalHold, err)
+	}
+
+	for _, img := range stack.Images {
+		mustGet(t, storage, img.Disk, img.Path)
+	}
+
+	restored, err := g.Restore(stack.ID)
+	if err != nil {
+		t.Fatalf("Restore failed with %q", err)
+	}
+
+	if restored.LegalHold == nil {
+		t.Fatalf("legal hold should be kept when the Stack is restored")
+	}
+}
+
+func TestGallery_LiftLegalHold(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	stack := uploadStack(t, g.Implementation, storage)
+
+	if err := g.LiftLegalHold(stack.ID); err != nil {
+		t.Fatalf("LiftLegalHold failed with %q", err)
+	}
+
+	test.NoChange(t, g, gallery.LegalHoldLifted)
+
+	if err := g.SetLegalHold(stack.ID, "case-1"); err != nil {
+		t.Fatalf("SetLegalHold failed with %q", err)
+	}
+
+	if err := g.LiftLegalHold(stack.ID); err != nil {
+		t.Fatalf("LiftLegalHold failed with %q", err)
+	}
+
+	if g.Held(stack.ID) {
+		t.Fatalf("legal hold of the Stack should be lifted")
+	}
+
+	test.Change(t, g, gallery.LegalHoldLifted, test.EventData(gallery.LegalHoldLiftedData{StackID: stack.ID}))
+
+	if err := g.Delete(ctx, storage, stack); err != nil {
+		t.Fatalf("Delete failed with %q", err)
+	}
+
+	if err := g.SetLegalHold(uuid.New(), ""); !errors.Is(err, gallery.ErrStackNotFound) {
+		t.Fatalf("SetLegalHold should fail with %q for an unknown Stack; got %q", gallery.ErrStackNotFound, err)
+	}
+}
@@ -0,0 +1,75 @@
+package gallery
+
+import "context"
+
+// UploadInfo describes an image that is about to be uploaded to a Gallery.
+type UploadInfo struct {
+	Name string
+	Disk string
+	Path string
+}
+
+// HookOption registers a lifecycle hook on a Gallery. Hooks are typically
+// configured where the Repository is constructed:
+//
+//	galleries := gallery.GoesRepository(repo, gallery.BeforeUpload(
+//		func(ctx context.Context, g *gallery.Implementation, upload gallery.UploadInfo) error {
+//			if !strings.HasPrefix(upload.Name, g.Name) {
+//				return errors.New("image names must be prefixed with the gallery name")
+//			}
+//			return nil
+//		},
+//	))
+type HookOption func(*hooks)
+
+type hooks struct {
+	beforeUpload []func(context.Context, *Implementation, UploadInfo) error
+	afterUpload  []func(context.Context, *Implementation, Stack)
+	beforeDelete []func(context.Context, *Implementation, Stack) error
+	afterDelete  []func(context.Context, *Implementation, Stack)
+}
+
+// BeforeUpload returns a HookOption that registers fn as a hook that is called
+// before an image is uploaded to storage. If fn returns an error, the upload is
+// aborted and Upload returns the error.
+func BeforeUpload(fn func(context.Context, *Implementation, UploadInfo) error) HookOption {
+	return func(h *hooks) {
+		h.beforeUpload = append(h.beforeUpload, fn)
+	}
+}
+
+// AfterUpload returns a HookOption that registers fn as a hook that is called
+// after an image has been uploaded and added to the Gallery. The hook is called
+// before the Gallery is saved.
+func AfterUpload(fn func(context.Context, *Implementation, Stack)) HookOption {
+	return func(h *hooks) {
+		h.afterUpload = append(h.afterUpload, fn)
+	}
+}
+
+// BeforeDelete returns a HookOption that registers fn as a hook that is called
+// before a Stack is deleted. If fn returns an error, the Stack is not deleted
+// and Delete returns the error.
+func BeforeDelete(fn func(context.Context, *Implementation, Stack) error) HookOption {
+	return func(h *hooks) {
+		h.beforeDelete = append(h.beforeDelete, fn)
+	}
+}
+
+// AfterDelete returns a HookOption that registers fn as a hook that is called
+// after a Stack has been deleted from storage and removed from the Gallery.
+// The hook is called before the Gallery is saved.
+func AfterDelete(fn func(context.Context, *Implementation, Stack)) HookOption {
+	return func(h *hooks) {
+		h.afterDelete = append(h.afterDelete, fn)
+	}
+}
+
+// UseHooks registers lifecycle hooks on the Gallery. Galleries that are
+// fetched from a Repository that was constructed with HookOptions already
+// have these hooks registered.
+func (g *Implementation) UseHooks(opts ...HookOption) {
+	for _, opt := range opts {
+		opt(&g.hooks)
+	}
+}
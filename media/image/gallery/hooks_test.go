@@ -0,0 +1,145 @@
+package gallery_test
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+func TestBeforeUpload(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	mockError := errors.New("mock error")
+	var upload gallery.UploadInfo
+	g.UseHooks(gallery.BeforeUpload(func(_ context.Context, _ *gallery.Implementation, u gallery.UploadInfo) error {
+		upload = u
+		return mockError
+	}))
+
+	_, buf := imggen.ColoredRectangle(80, 60, color.Black)
+	if _, err := g.Upload(context.Background(), storage, buf, exampleName, exampleDisk, examplePath); !errors.Is(err, mockError) {
+		t.Fatalf("Upload should fail with %q; got %q", mockError, err)
+	}
+
+	want := gallery.UploadInfo{Name: exampleName, Disk: exampleDisk, Path: examplePath}
+	if upload != want {
+		t.Fatalf("BeforeUpload hook should receive %v; got %v", want, upload)
+	}
+
+	if len(g.Stacks) != 0 {
+		t.Fatalf("Gallery should have no Stacks; has %d", len(g.Stacks))
+	}
+
+	disk, _ := storage.Disk(exampleDisk)
+	if _, err := disk.Get(context.Background(), examplePath); err == nil {
+		t.Fatalf("image should not have been uploaded to storage")
+	}
+}
+
+func TestAfterUpload(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	var uploaded []gallery.Stack
+	g.UseHooks(gallery.AfterUpload(func(_ context.Context, _ *gallery.Implementation, stack gallery.Stack) {
+		uploaded = append(uploaded, stack)
+	}))
+
+	_, buf := imggen.ColoredRectangle(80, 60, color.Black)
+	stack, err := g.Upload(context.Background(), storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Upload failed with %q", err)
+	}
+
+	if len(uploaded) != 1 || uploaded[0].ID != stack.ID {
+		t.Fatalf("AfterUpload hook should be called with the uploaded Stack; got %v", uploaded)
+	}
+}
+
+func TestBeforeDelete_AfterDelete(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(80, 60, color.Black)
+	stack, err := g.Upload(context.Background(), storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Upload failed with %q", err)
+	}
+
+	mockError := errors.New("mock error")
+	reject := true
+	var deleted []gallery.Stack
+	g.UseHooks(
+		gallery.BeforeDelete(func(context.Context, *gallery.Implementation, gallery.Stack) error {
+			if reject {
+				return mockError
+			}
+			return nil
+		}),
+		gallery.AfterDelete(func(_ context.Context, _ *gallery.Implementation, stack gallery.Stack) {
+			deleted = append(deleted, stack)
+		}),
+	)
+
+	if err := g.Delete(context.Background(), storage, stack); !errors.Is(err, mockError) {
+		t.Fatalf("Delete should fail with %q; got %q", mockError, err)
+	}
+
+	if _, err := g.Stack(stack.ID); err != nil {
+		t.Fatalf("Stack should not have been deleted: %v", err)
+	}
+
+	if len(deleted) != 0 {
+		t.Fatalf("AfterDelete hook should not be called if the deletion was rejected")
+	}
+
+	reject = false
+	if err := g.Delete(context.Background(), storage, stack); err != nil {
+		t.Fatalf("Delete failed with %q", err)
+	}
+
+	if len(deleted) != 1 || deleted[0].ID != stack.ID {
+		t.Fatalf("AfterDelete hook should be called with the deleted Stack; got %v", deleted)
+	}
+}
+
+func TestGoesRepository_hooks(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	var calls int
+	galleries := gallery.GoesRepository(repository.New(eventstore.New()), gallery.AfterUpload(
+		func(context.Context, *gallery.Implementation, gallery.Stack) { calls++ },
+	))
+
+	id := uuid.New()
+	if err := galleries.Use(ctx, id, func(g *gallery.Gallery) error {
+		return g.Create("foo")
+	}); err != nil {
+		t.Fatalf("create gallery: %v", err)
+	}
+
+	if err := galleries.Use(ctx, id, func(g *gallery.Gallery) error {
+		_, buf := imggen.ColoredRectangle(80, 60, color.Black)
+		_, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath)
+		return err
+	}); err != nil {
+		t.Fatalf("upload image: %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("AfterUpload hook should be called %d time(s); was called %d time(s)", 1, calls)
+	}
+}
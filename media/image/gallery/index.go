@@ -0,0 +1,90 @@
+package gallery
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/internal/ordered"
+	"github.com/modernice/nice-cms/projector"
+)
+
+// Index is a projection of the Stacks of Galleries that serves paginated
+// queries without fetching the Galleries from the event store. It is
+// thread-safe.
+type Index struct {
+	projector *projector.Projector
+
+	mux       sync.RWMutex
+	galleries map[uuid.UUID]*indexedGallery
+}
+
+// indexedGallery is a Gallery of the Index and the events that were received
+// ahead of their predecessors.
+type indexedGallery struct {
+	*Gallery
+
+	events ordered.Events
+}
+
+// NewIndex returns a new Index. The provided options configure the error
+// handling of the projection.
+func NewIndex(opts ...projector.Option) *Index {
+	return &Index{
+		projector: projector.New(opts...),
+		galleries: make(map[uuid.UUID]*indexedGallery),
+	}
+}
+
+// List returns the page of the Stacks of the Gallery with the given UUID that
+// match q, or ErrNotFound if the Index has no such Gallery.
+func (idx *Index) List(galleryID uuid.UUID, q Query) (StackPage, error) {
+	idx.mux.RLock()
+	defer idx.mux.RUnlock()
+
+	g, ok := idx.galleries[galleryID]
+	if !ok || !g.Created() {
+		return StackPage{}, ErrNotFound
+	}
+
+	return g.List(q), nil
+}
+
+// Project projects the Index in a new goroutine and returns a channel of
+// asynchronous errors. Failed projection jobs are handled according to the
+// projector.Policy of the Index.
+func (idx *Index) Project(ctx context.Context, bus event.Bus, store event.Store, opts ...schedule.ContinuousOption) (<-chan error, error) {
+	schedule := schedule.Continuously(bus, store, Events[:], opts...)
+	return idx.projector.Run(ctx, schedule, idx)
+}
+
+// Ready returns a channel that is closed when the Index caught up with the
+// past events. Until then, List may miss Galleries that already exist.
+func (idx *Index) Ready() <-chan struct{} {
+	return idx.projector.Ready()
+}
+
+// Status returns the projection status of the Index.
+func (idx *Index) Status() projector.Status {
+	return idx.projector.Status()
+}
+
+// ApplyEvent applies aggregate events.
+func (idx *Index) ApplyEvent(evt event.Event) {
+	id, _, _ := evt.Aggregate()
+
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+
+	g, ok := idx.galleries[id]
+	if !ok {
+		g = &indexedGallery{Gallery: New(id)}
+		idx.galleries[id] = g
+	}
+
+	// The Gallery appliers are not idempotent, but events may be delivered
+	// more than once and out of order.
+	g.events.Apply(evt, g.ApplyEvent)
+}
@@ -0,0 +1,71 @@
+package gallery
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Instrumentation receives measurements of the processing of Stacks, e.g. to
+// export them as metrics. ProcessingPipelines report the duration and result
+// of each Processor (see WithInstrumentation), and the PostProcessor reports
+// its queued and processed jobs (see ProcessorInstrumentation). An
+// Instrumentation must be safe for concurrent use.
+type Instrumentation interface {
+	// JobQueued is called when the PostProcessor receives a Stack to process.
+	JobQueued()
+
+	// JobStarted is called when a worker of the PostProcessor takes a queued
+	// Stack. The difference between queued and started jobs is the backlog of
+	// the PostProcessor.
+	JobStarted()
+
+	// JobDropped is called instead of JobStarted when a queued job is dropped
+	// because the queue of the PostProcessor is full (see OverflowDrop).
+	JobDropped()
+
+	// JobProcessed is called when a worker of the PostProcessor finished a
+	// job. err is nil if the Stack was processed successfully. Jobs of
+	// Galleries without a ProcessingPipeline are skipped and not reported.
+	JobProcessed(d time.Duration, err error)
+
+	// ProcessorDone is called when a Processor of a ProcessingPipeline
+	// finished. processor is the type name of the Processor, e.g. "Resizer".
+	ProcessorDone(processor string, d time.Duration, err error)
+}
+
+// WithInstrumentation returns a ProcessorOption that reports the duration and
+// result of each Processor to the given Instrumentation.
+func WithInstrumentation(inst Instrumentation) ProcessorOption {
+	return func(cfg *processorConfig) {
+		cfg.inst = inst
+	}
+}
+
+// ProcessorInstrumentation returns a PostProcessorOption that reports the
+// jobs of the PostProcessor and the Processors of the ProcessingPipelines to
+// the given Instrumentation.
+func ProcessorInstrumentation(inst Instrumentation) PostProcessorOption {
+	return func(cfg *postProcessorConfig) {
+		cfg.inst = inst
+	}
+}
+
+// ProcessorName returns the name under which a Processor is reported to an
+// Instrumentation. Processors can provide their own name by implementing
+// interface{ Name() string }. Otherwise, the type name of the Processor is
+// used.
+func ProcessorName(proc Processor) string {
+	if named, ok := proc.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+
+	t := reflect.TypeOf(proc)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Name() == "" {
+		return fmt.Sprintf("%T", proc)
+	}
+	return t.Name()
+}
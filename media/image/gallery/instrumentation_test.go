@@ -0,0 +1,189 @@
+package gallery_test
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+func TestProcessingPipeline_Process_instrumentation(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+	stack, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	mockError := errors.New("mock error")
+	pipe := gallery.ProcessingPipeline{
+		gallery.Resizer{"small": {Width: 640}},
+		gallery.ProcessorFunc(func(*gallery.ProcessorContext) error {
+			return mockError
+		}),
+		gallery.Cropper{"square": {Width: 100, Height: 100}},
+	}
+
+	var inst recordingInstrumentation
+	if _, err := pipe.Process(ctx, stack, image.NewEncoder(), storage, gallery.WithInstrumentation(&inst)); !errors.Is(err, mockError) {
+		t.Fatalf("Process should fail with %q; got %q", mockError, err)
+	}
+
+	if len(inst.processors) != 2 {
+		t.Fatalf("2 Processors should have been reported; got %d", len(inst.processors))
+	}
+
+	if p := inst.processors[0]; p.name != "Resizer" || p.err != nil {
+		t.Fatalf("first reported Processor should be a successful %q; got %q (err=%v)", "Resizer", p.name, p.err)
+	}
+
+	if p := inst.processors[1]; p.name != "ProcessorFunc" || !errors.Is(p.err, mockError) {
+		t.Fatalf("second reported Processor should be a failed %q; got %q (err=%v)", "ProcessorFunc", p.name, p.err)
+	}
+}
+
+func TestPostProcessor_Run_instrumentation(t *testing.T) {
+	enc := image.NewEncoder()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	galleries := gallery.GoesRepository(repository.New(estore))
+	svc := gallery.NewPostProcessor(enc, storage, galleries)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pipe := gallery.ProcessingPipeline{
+		gallery.Resizer{"small": {Width: 640}},
+	}
+
+	var inst recordingInstrumentation
+	processed := make(chan struct{})
+
+	errs, err := svc.Run(
+		ctx, ebus, pipe,
+		gallery.ProcessorInstrumentation(&inst),
+		gallery.OnProcessed(func(gallery.Stack, *gallery.Gallery) { close(processed) }),
+	)
+	if err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+	if _, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("failed to save Gallery: %v", err)
+	}
+
+	select {
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out")
+	case err := <-errs:
+		t.Fatal(err)
+	case <-processed:
+	}
+
+	inst.mux.Lock()
+	defer inst.mux.Unlock()
+
+	if inst.queued != 1 || inst.started != 1 {
+		t.Fatalf("1 job should have been queued and started; queued=%d started=%d", inst.queued, inst.started)
+	}
+
+	if len(inst.jobs) != 1 || inst.jobs[0] != nil {
+		t.Fatalf("1 successful job should have been reported; got %v", inst.jobs)
+	}
+
+	if len(inst.processors) != 1 || inst.processors[0].name != "Resizer" {
+		t.Fatalf("the Resizer should have been reported; got %v", inst.processors)
+	}
+}
+
+func TestProcessorName(t *testing.T) {
+	tests := []struct {
+		proc gallery.Processor
+		want string
+	}{
+		{proc: gallery.Resizer{}, want: "Resizer"},
+		{proc: &gallery.Placeholder{}, want: "Placeholder"},
+		{proc: gallery.ProcessorFunc(func(*gallery.ProcessorContext) error { return nil }), want: "ProcessorFunc"},
+		{proc: namedProcessor("watermark"), want: "watermark"},
+	}
+
+	for _, tt := range tests {
+		if got := gallery.ProcessorName(tt.proc); got != tt.want {
+			t.Errorf("ProcessorName(%T) should return %q; got %q", tt.proc, tt.want, got)
+		}
+	}
+}
+
+type recordingInstrumentation struct {
+	mux        sync.Mutex
+	queued     int
+	started    int
+	dropped    int
+	jobs       []error
+	processors []reportedProcessor
+}
+
+type reportedProcessor struct {
+	name string
+	err  error
+}
+
+func (inst *recordingInstrumentation) JobQueued() {
+	inst.mux.Lock()
+	defer inst.mux.Unlock()
+	inst.queued++
+}
+
+func (inst *recordingInstrumentation) JobStarted() {
+	inst.mux.Lock()
+	defer inst.mux.Unlock()
+	inst.started++
+}
+
+func (inst *recordingInstrumentation) JobDropped() {
+	inst.mux.Lock()
+	defer inst.mux.Unlock()
+	inst.dropped++
+}
+
+func (inst *recordingInstrumentation) JobProcessed(_ time.Duration, err error) {
+	inst.mux.Lock()
+	defer inst.mux.Unlock()
+	inst.jobs = append(inst.jobs, err)
+}
+
+func (inst *recordingInstrumentation) ProcessorDone(name string, _ time.Duration, err error) {
+	inst.mux.Lock()
+	defer inst.mux.Unlock()
+	inst.processors = append(inst.processors, reportedProcessor{name: name, err: err})
+}
+
+type namedProcessor string
+
+func (p namedProcessor) Name() string { return string(p) }
+
+func (p namedProcessor) Process(*gallery.ProcessorContext) error { return nil }
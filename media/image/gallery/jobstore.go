@@ -0,0 +1,202 @@
+package gallery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/codec"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/query"
+	"github.com/modernice/goes/helper/streams"
+)
+
+// JobAggregate is the aggregate name of the ProcessingJobs that are persisted
+// by an EventJobStore.
+const JobAggregate = "cms.media.image.gallery.processing_job"
+
+const (
+	ProcessingJobSaved   = "cms.media.image.gallery.processing_job.saved"
+	ProcessingJobDeleted = "cms.media.image.gallery.processing_job.deleted"
+)
+
+// JobEvents are the events of an EventJobStore.
+var JobEvents = [...]string{
+	ProcessingJobSaved,
+	ProcessingJobDeleted,
+}
+
+// ProcessingJobDeletedData is the event data for the ProcessingJobDeleted
+// event.
+type ProcessingJobDeletedData struct{}
+
+// ProcessingJob is a job of the PostProcessor to process a Stack.
+type ProcessingJob struct {
+	ID        uuid.UUID `json:"id"`
+	GalleryID uuid.UUID `json:"galleryId"`
+	StackID   uuid.UUID `json:"stackId"`
+
+	// Attempts is the number of failed attempts to process the Stack.
+	Attempts int `json:"attempts"`
+
+	QueuedAt time.Time `json:"queuedAt"`
+}
+
+// JobStore persists the ProcessingJobs of a PostProcessor, so that unfinished
+// jobs can be resumed when the PostProcessor is restarted. See
+// ProcessorJobStore.
+type JobStore interface {
+	// Save saves a ProcessingJob.
+	Save(context.Context, ProcessingJob) error
+
+	// Jobs returns the unfinished ProcessingJobs, oldest first.
+	Jobs(context.Context) ([]ProcessingJob, error)
+
+	// Delete deletes the ProcessingJob with the given UUID. Delete returns no
+	// error if the ProcessingJob does not exist.
+	Delete(context.Context, uuid.UUID) error
+}
+
+// RegisterJobEvents registers the events of an EventJobStore into an event
+// registry.
+func RegisterJobEvents(r codec.Registerer) {
+	codec.Register[ProcessingJob](r, ProcessingJobSaved)
+	codec.Register[ProcessingJobDeletedData](r, ProcessingJobDeleted)
+}
+
+type memoryJobStore struct {
+	mux  sync.RWMutex
+	jobs map[uuid.UUID]ProcessingJob
+}
+
+// MemoryJobStore returns an in-memory JobStore. ProcessingJobs in a
+// MemoryJobStore are lost when the process exits.
+func MemoryJobStore() JobStore {
+	return &memoryJobStore{jobs: make(map[uuid.UUID]ProcessingJob)}
+}
+
+func (s *memoryJobStore) Save(_ context.Context, job ProcessingJob) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.jobs[job.ID] = job
+	return nil
+}
+
+func (s *memoryJobStore) Jobs(context.Context) ([]ProcessingJob, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	out := make([]ProcessingJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		out = append(out, job)
+	}
+	SortJobs(out)
+	return out, nil
+}
+
+func (s *memoryJobStore) Delete(_ context.Context, id uuid.UUID) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	delete(s.jobs, id)
+	return nil
+}
+
+type eventJobStore struct {
+	store event.Store
+
+	mux      sync.Mutex
+	versions map[uuid.UUID]int
+}
+
+// EventJobStore returns a JobStore that persists ProcessingJobs as events in
+// the given event store. Each ProcessingJob is an aggregate of its own
+// (JobAggregate), so that concurrent workers don't conflict. The events of the
+// store must be registered using RegisterJobEvents.
+func EventJobStore(store event.Store) JobStore {
+	return &eventJobStore{
+		store:    store,
+		versions: make(map[uuid.UUID]int),
+	}
+}
+
+func (s *eventJobStore) Save(ctx context.Context, job ProcessingJob) error {
+	return s.insert(ctx, job.ID, ProcessingJobSaved, job)
+}
+
+func (s *eventJobStore) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.insert(ctx, id, ProcessingJobDeleted, ProcessingJobDeletedData{})
+}
+
+func (s *eventJobStore) insert(ctx context.Context, id uuid.UUID, name string, data any) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	v := s.versions[id] + 1
+	evt := event.New(name, data, event.Aggregate(id, JobAggregate, v))
+	if err := s.store.Insert(ctx, evt.Any()); err != nil {
+		return fmt.Errorf("insert %q event: %w", name, err)
+	}
+
+	if name == ProcessingJobDeleted {
+		delete(s.versions, id)
+	} else {
+		s.versions[id] = v
+	}
+
+	return nil
+}
+
+func (s *eventJobStore) Jobs(ctx context.Context) ([]ProcessingJob, error) {
+	events, errs, err := s.store.Query(ctx, query.New(
+		query.AggregateName(JobAggregate),
+		query.SortByAggregate(),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("query %q events: %w", JobAggregate, err)
+	}
+
+	jobs := make(map[uuid.UUID]ProcessingJob)
+	versions := make(map[uuid.UUID]int)
+
+	if err := streams.Walk(ctx, func(evt event.Event) error {
+		id, _, v := evt.Aggregate()
+		versions[id] = v
+		switch data := evt.Data().(type) {
+		case ProcessingJob:
+			jobs[id] = data
+		case ProcessingJobDeletedData:
+			delete(jobs, id)
+		}
+		return nil
+	}, events, errs); err != nil {
+		return nil, fmt.Errorf("walk %q events: %w", JobAggregate, err)
+	}
+
+	s.mux.Lock()
+	for id, v := range versions {
+		if v > s.versions[id] {
+			s.versions[id] = v
+		}
+	}
+	s.mux.Unlock()
+
+	out := make([]ProcessingJob, 0, len(jobs))
+	for _, job := range jobs {
+		out = append(out, job)
+	}
+	SortJobs(out)
+
+	return out, nil
+}
+
+// SortJobs sorts ProcessingJobs by the time they were queued, oldest first.
+func SortJobs(jobs []ProcessingJob) {
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].QueuedAt.Equal(jobs[j].QueuedAt) {
+			return jobs[i].QueuedAt.Before(jobs[j].QueuedAt)
+		}
+		return jobs[i].ID.String() < jobs[j].ID.String()
+	})
+}
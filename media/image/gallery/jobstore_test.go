@@ -0,0 +1,158 @@
+package gallery_test
+
+import (
+	"context"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+func TestJobStores(t *testing.T) {
+	estore := eventstore.New()
+
+	for name, store := range map[string]gallery.JobStore{
+		"memory": gallery.MemoryJobStore(),
+		"event":  gallery.EventJobStore(estore),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Now()
+
+			first := gallery.ProcessingJob{ID: uuid.New(), GalleryID: uuid.New(), StackID: uuid.New(), QueuedAt: now}
+			second := gallery.ProcessingJob{ID: uuid.New(), GalleryID: uuid.New(), StackID: uuid.New(), QueuedAt: now.Add(-time.Minute)}
+
+			for _, job := range []gallery.ProcessingJob{first, second} {
+				if err := store.Save(ctx, job); err != nil {
+					t.Fatalf("save job: %v", err)
+				}
+			}
+
+			first.Attempts = 2
+			if err := store.Save(ctx, first); err != nil {
+				t.Fatalf("save job: %v", err)
+			}
+
+			jobs, err := store.Jobs(ctx)
+			if err != nil {
+				t.Fatalf("fetch jobs: %v", err)
+			}
+
+			if len(jobs) != 2 || jobs[0].ID != second.ID || jobs[1].ID != first.ID {
+				t.Fatalf("Jobs should return the jobs, oldest first; got %v", jobs)
+			}
+
+			if jobs[1].Attempts != 2 {
+				t.Fatalf("saved job should have 2 attempts; has %d", jobs[1].Attempts)
+			}
+
+			if err := store.Delete(ctx, second.ID); err != nil {
+				t.Fatalf("delete job: %v", err)
+			}
+
+			if err := store.Delete(ctx, uuid.New()); err != nil {
+				t.Fatalf("deleting an unknown job should not fail; got %v", err)
+			}
+
+			if jobs, _ = store.Jobs(ctx); len(jobs) != 1 || jobs[0].ID != first.ID {
+				t.Fatalf("Jobs should return the remaining job; got %v", jobs)
+			}
+
+			if err := store.Delete(ctx, first.ID); err != nil {
+				t.Fatalf("delete job: %v", err)
+			}
+		})
+	}
+}
+
+func TestEventJobStore_restart(t *testing.T) {
+	ctx := context.Background()
+	estore := eventstore.New()
+
+	job := gallery.ProcessingJob{ID: uuid.New(), GalleryID: uuid.New(), StackID: uuid.New(), QueuedAt: time.Now()}
+	if err := gallery.EventJobStore(estore).Save(ctx, job); err != nil {
+		t.Fatalf("save job: %v", err)
+	}
+
+	restarted := gallery.EventJobStore(estore)
+
+	jobs, err := restarted.Jobs(ctx)
+	if err != nil {
+		t.Fatalf("fetch jobs: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != job.ID {
+		t.Fatalf("restarted store should return the saved job; got %v", jobs)
+	}
+
+	if err := restarted.Delete(ctx, job.ID); err != nil {
+		t.Fatalf("delete job: %v", err)
+	}
+
+	if jobs, _ = gallery.EventJobStore(estore).Jobs(ctx); len(jobs) != 0 {
+		t.Fatalf("deleted job should not be returned; got %v", jobs)
+	}
+}
+
+func TestPostProcessor_Run_resume(t *testing.T) {
+	enc := image.NewEncoder()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	galleries := gallery.GoesRepository(repository.New(eventstore.New()))
+	svc := gallery.NewPostProcessor(enc, storage, galleries)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+	uploaded, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	// The Gallery is saved before the PostProcessor is running, so the
+	// Stack can only be processed by resuming the unfinished job.
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("failed to save Gallery: %v", err)
+	}
+
+	jobs := gallery.MemoryJobStore()
+	job := gallery.ProcessingJob{ID: uuid.New(), GalleryID: g.ID, StackID: uploaded.ID, QueuedAt: time.Now()}
+	if err := jobs.Save(ctx, job); err != nil {
+		t.Fatalf("save job: %v", err)
+	}
+
+	processed := make(chan gallery.Stack)
+	errs, err := svc.Run(
+		ctx, eventbus.New(), gallery.ProcessingPipeline{gallery.Resizer{"small": {Width: 640}}},
+		gallery.ProcessorJobStore(jobs),
+		gallery.OnProcessed(func(s gallery.Stack, _ *gallery.Gallery) { processed <- s }),
+	)
+	if err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+
+	select {
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out")
+	case err := <-errs:
+		t.Fatal(err)
+	case stack := <-processed:
+		if stack.ID != uploaded.ID || stack.ProcessedAt.IsZero() {
+			t.Fatalf("resumed job should process Stack %q; got %v", uploaded.ID, stack)
+		}
+	}
+
+	if remaining, _ := jobs.Jobs(ctx); len(remaining) != 0 {
+		t.Fatalf("finished job should be deleted from the JobStore; got %v", remaining)
+	}
+}
@@ -1,21 +1,58 @@
 package gallery
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/media"
+)
 
 // JSONGallery is the JSON representation of a Gallery.
 type JSONGallery struct {
 	ID     uuid.UUID `json:"id"`
 	Name   string    `json:"name"`
 	Stacks Stacks    `json:"stacks"`
+
+	DefaultTags        []string `json:"defaultTags,omitempty"`
+	Theme              *Theme   `json:"theme,omitempty"`
+	OriginalsProtected bool     `json:"originalsProtected,omitempty"`
+
+	Trashed      []TrashedStack   `json:"trashed,omitempty"`
+	LegalHold    *media.LegalHold `json:"legalHold,omitempty"`
+	VersionLimit int              `json:"versionLimit,omitempty"`
+
+	// Version is the aggregate version of the Gallery. It changes whenever the
+	// Gallery changes.
+	Version int `json:"version"`
 }
 
 // JSON returns the JSONGallery for g.
 func (g *Implementation) JSON() JSONGallery {
-	id, _, _ := g.gallery.Aggregate()
+	id, _, version := g.gallery.Aggregate()
 	return JSONGallery{
 		ID:     id,
 		Name:   g.Name,
 		Stacks: g.Stacks,
+
+		DefaultTags:        g.DefaultTags,
+		Theme:              g.Theme,
+		OriginalsProtected: g.OriginalsProtected,
+
+		Trashed:      g.Trashed,
+		LegalHold:    g.LegalHold,
+		VersionLimit: g.VersionLimit,
+		Version:      version,
+	}
+}
+
+// Index returns the JSONIndex of the Gallery.
+func (g JSONGallery) Index() JSONIndex {
+	stacks := make([]StackSummary, len(g.Stacks))
+	for i, stack := range g.Stacks {
+		stacks[i] = stack.Summary()
+	}
+	return JSONIndex{
+		ID:     g.ID,
+		Name:   g.Name,
+		Stacks: stacks,
 	}
 }
 
@@ -28,3 +65,83 @@ func (g JSONGallery) Stack(id uuid.UUID) (Stack, error) {
 	}
 	return Stack{}, ErrStackNotFound
 }
+
+// TrashedStack returns the Stack with the given UUID from the trash of the
+// Gallery, or ErrNotTrashed.
+func (g JSONGallery) TrashedStack(id uuid.UUID) (TrashedStack, error) {
+	for _, s := range g.Trashed {
+		if s.ID == id {
+			return TrashedStack{Stack: s.Stack.copy(), TrashedAt: s.TrashedAt}, nil
+		}
+	}
+	return TrashedStack{}, ErrNotTrashed
+}
+
+// Held returns whether the Stack with the given UUID is under legal hold,
+// either by itself or through the Gallery. The Stack may also be in the trash.
+func (g JSONGallery) Held(id uuid.UUID) bool {
+	if g.LegalHold != nil {
+		return true
+	}
+	if s, err := g.Stack(id); err == nil {
+		return s.LegalHold != nil
+	}
+	if s, err := g.TrashedStack(id); err == nil {
+		return s.LegalHold != nil
+	}
+	return false
+}
+
+// JSONIndex is a lightweight representation of a Gallery that contains only
+// the data that is needed to render a grid of the images in the Gallery. The
+// full Stacks can be fetched separately when needed.
+type JSONIndex struct {
+	ID     uuid.UUID      `json:"id"`
+	Name   string         `json:"name"`
+	Stacks []StackSummary `json:"stacks"`
+}
+
+// StackSummary is the summary of a Stack in a JSONIndex.
+type StackSummary struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Tags []string  `json:"tags"`
+
+	// Thumbnail is the smallest Image of the Stack.
+	Thumbnail Image `json:"thumbnail"`
+
+	Placeholder string `json:"placeholder,omitempty"`
+	Pending     bool   `json:"pending"`
+}
+
+// Summary returns the StackSummary of the Stack.
+func (s Stack) Summary() StackSummary {
+	org := s.Original()
+	tags := org.Tags
+	if tags == nil {
+		tags = make([]string, 0)
+	}
+	return StackSummary{
+		ID:          s.ID,
+		Name:        org.Name,
+		Tags:        tags,
+		Thumbnail:   s.Thumbnail(),
+		Placeholder: s.Placeholder,
+		Pending:     s.Pending,
+	}
+}
+
+// Thumbnail returns the smallest Image of the Stack by width. Stale Images are
+// skipped. If the Stack has no other Images, the original Image is returned.
+func (s Stack) Thumbnail() Image {
+	thumb := s.Original()
+	for _, img := range s.Images {
+		if img.Stale || img.Width <= 0 {
+			continue
+		}
+		if img.Width < thumb.Width {
+			thumb = img
+		}
+	}
+	return thumb
+}
@@ -0,0 +1,134 @@
+package gallery
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/media"
+)
+
+// SortFields are the fields that Stacks can be sorted by in a Query.
+var SortFields = [...]string{"name", "filesize", "processedAt"}
+
+// Query is a paginated query for the Stacks of a Gallery.
+type Query struct {
+	// Offset is the number of matching Stacks to skip.
+	Offset int
+
+	// Limit is the maximum number of Stacks to return. If Limit is 0, all
+	// matching Stacks after Offset are returned.
+	Limit int
+
+	// Tags filters Stacks by the tags of their original images. A Stack
+	// matches if it has all Tags. See FindByTag.
+	Tags []string
+
+	// Sort is a comma-separated list of SortFields. A "-" prefix sorts a
+	// field in descending order. Unknown fields are ignored. If Sort is empty,
+	// Stacks are returned in the order of the Gallery.
+	Sort string
+}
+
+// StackPage is a page of the Stacks of a Gallery that match a Query.
+type StackPage struct {
+	ID                 uuid.UUID        `json:"id"`
+	Name               string           `json:"name"`
+	Stacks             Stacks           `json:"stacks"`
+	OriginalsProtected bool             `json:"originalsProtected,omitempty"`
+	LegalHold          *media.LegalHold `json:"legalHold,omitempty"`
+
+	// Total is the number of Stacks that match the Query.
+	Total int `json:"total"`
+
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// Next returns the Query for the next page, or false if there is no next page.
+func (p StackPage) Next(q Query) (Query, bool) {
+	if p.Offset+len(p.Stacks) >= p.Total {
+		return q, false
+	}
+	q.Offset = p.Offset + len(p.Stacks)
+	q.Limit = p.Limit
+	return q, true
+}
+
+// List returns the page of the Stacks in g that match q, sorted by the Sort
+// fields of q.
+func (g *Implementation) List(q Query) StackPage {
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	if q.Limit < 0 {
+		q.Limit = 0
+	}
+
+	matches := g.FindByTag(q.Tags...)
+	sortStacks(matches, q.Sort)
+
+	id, _, _ := g.gallery.Aggregate()
+	page := StackPage{
+		ID:                 id,
+		Name:               g.Name,
+		Stacks:             Stacks{},
+		OriginalsProtected: g.OriginalsProtected,
+		LegalHold:          g.LegalHold,
+		Total:              len(matches),
+		Offset:             q.Offset,
+		Limit:              q.Limit,
+	}
+
+	if q.Offset >= len(matches) {
+		return page
+	}
+
+	matches = matches[q.Offset:]
+	if q.Limit > 0 && len(matches) > q.Limit {
+		matches = matches[:q.Limit]
+	}
+	for _, stack := range matches {
+		page.Stacks = append(page.Stacks, stack.copy())
+	}
+
+	return page
+}
+
+func sortStacks(stacks []Stack, fields string) {
+	if fields == "" {
+		return
+	}
+	sort.SliceStable(stacks, func(i, j int) bool {
+		for _, field := range strings.Split(fields, ",") {
+			desc := strings.HasPrefix(field, "-")
+			c := compareStacks(strings.TrimPrefix(field, "-"), stacks[i], stacks[j])
+			if desc {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+func compareStacks(field string, a, b Stack) int {
+	switch field {
+	case "name":
+		return strings.Compare(strings.ToLower(a.Original().Name), strings.ToLower(b.Original().Name))
+	case "filesize":
+		return a.Original().Filesize - b.Original().Filesize
+	case "processedAt":
+		switch {
+		case a.ProcessedAt.Before(b.ProcessedAt):
+			return -1
+		case a.ProcessedAt.After(b.ProcessedAt):
+			return 1
+		}
+		return 0
+	default:
+		return 0
+	}
+}
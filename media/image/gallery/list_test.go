@@ -0,0 +1,190 @@
+package gallery_test
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+func TestGallery_List(t *testing.T) {
+	g, _ := newListGallery(t)
+
+	tests := map[string]struct {
+		query gallery.Query
+		want  []string
+		total int
+	}{
+		"all": {
+			query: gallery.Query{},
+			want:  []string{"Beach 0", "Beach 1", "Forest 2", "Beach 3", "Forest 4"},
+			total: 5,
+		},
+		"page": {
+			query: gallery.Query{Offset: 1, Limit: 2},
+			want:  []string{"Beach 1", "Forest 2"},
+			total: 5,
+		},
+		"last page": {
+			query: gallery.Query{Offset: 4, Limit: 2},
+			want:  []string{"Forest 4"},
+			total: 5,
+		},
+		"out of range": {
+			query: gallery.Query{Offset: 10, Limit: 2},
+			want:  []string{},
+			total: 5,
+		},
+		"tag": {
+			query: gallery.Query{Tags: []string{"hero"}},
+			want:  []string{"Beach 0", "Forest 2", "Forest 4"},
+			total: 3,
+		},
+		"all tags": {
+			query: gallery.Query{Tags: []string{"hero", "forest"}},
+			want:  []string{"Forest 2", "Forest 4"},
+			total: 2,
+		},
+		"sort": {
+			query: gallery.Query{Sort: "-name"},
+			want:  []string{"Forest 4", "Forest 2", "Beach 3", "Beach 1", "Beach 0"},
+			total: 5,
+		},
+		"tag, sort and page": {
+			query: gallery.Query{Tags: []string{"hero"}, Sort: "-name", Offset: 1, Limit: 1},
+			want:  []string{"Forest 2"},
+			total: 3,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			page := g.List(tt.query)
+			expectStackPage(t, page, tt.want, tt.total)
+
+			if page.ID != g.ID || page.Name != g.Implementation.Name {
+				t.Fatalf("page should belong to Gallery %q (%s); belongs to %q (%s)", g.Implementation.Name, g.ID, page.Name, page.ID)
+			}
+		})
+	}
+}
+
+func TestIndex_List(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	galleries := gallery.GoesRepository(repository.New(estore))
+
+	idx := gallery.NewIndex()
+
+	errs, err := idx.Project(ctx, ebus, estore)
+	if err != nil {
+		t.Fatalf("run index: %v", err)
+	}
+	go func() {
+		for err := range errs {
+			panic(err)
+		}
+	}()
+
+	g, storage := newListGallery(t)
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save Gallery: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	page, err := idx.List(g.ID, gallery.Query{Tags: []string{"hero"}, Limit: 2})
+	if err != nil {
+		t.Fatalf("List failed with %q", err)
+	}
+	expectStackPage(t, page, []string{"Beach 0", "Forest 2"}, 3)
+
+	if err := g.Delete(ctx, storage, g.Stacks[0]); err != nil {
+		t.Fatalf("delete Stack: %v", err)
+	}
+	if _, err := g.Tag(ctx, g.Stacks[0], "hero"); err != nil {
+		t.Fatalf("tag Stack: %v", err)
+	}
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save Gallery: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	page, err = idx.List(g.ID, gallery.Query{Tags: []string{"hero"}, Limit: 2})
+	if err != nil {
+		t.Fatalf("List failed with %q", err)
+	}
+	expectStackPage(t, page, []string{"Beach 1", "Forest 2"}, 3)
+
+	if _, err := idx.List(uuid.New(), gallery.Query{}); err != gallery.ErrNotFound {
+		t.Fatalf("List should fail with %q for an unknown Gallery; got %q", gallery.ErrNotFound, err)
+	}
+}
+
+// newListGallery returns a Gallery with 5 Stacks. Even Stacks are tagged with
+// "hero" and every other even Stack is named "Forest" and tagged with
+// "forest".
+func newListGallery(t *testing.T) (*gallery.Gallery, media.Storage) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("Beach %d", i)
+		if i > 0 && i%2 == 0 {
+			name = fmt.Sprintf("Forest %d", i)
+		}
+		_, buf := imggen.ColoredRectangle(8, 6, color.Gray{Y: uint8(i * 40)})
+		stack, err := g.Upload(ctx, storage, buf, name, exampleDisk, fmt.Sprintf("/gallery/%d.png", i))
+		if err != nil {
+			t.Fatalf("Upload failed with %q", err)
+		}
+
+		var tags []string
+		if i%2 == 0 {
+			tags = append(tags, "hero")
+		}
+		if i > 0 && i%2 == 0 {
+			tags = append(tags, "forest")
+		}
+		if len(tags) > 0 {
+			if _, err := g.Tag(ctx, stack, tags...); err != nil {
+				t.Fatalf("Tag failed with %q", err)
+			}
+		}
+	}
+
+	return g, storage
+}
+
+func expectStackPage(t *testing.T, page gallery.StackPage, names []string, total int) {
+	t.Helper()
+
+	if page.Total != total {
+		t.Fatalf("Total should be %d; is %d", total, page.Total)
+	}
+
+	if len(page.Stacks) != len(names) {
+		t.Fatalf("page should have %d Stacks; has %d", len(names), len(page.Stacks))
+	}
+
+	for i, stack := range page.Stacks {
+		if name := stack.Original().Name; name != names[i] {
+			t.Fatalf("Stacks[%d] should be %q; is %q", i, names[i], name)
+		}
+	}
+}
@@ -2,29 +2,88 @@ package gallery
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/google/uuid"
 	"github.com/modernice/goes/event"
-	"github.com/modernice/goes/projection"
+	"github.com/modernice/goes/event/query"
+	"github.com/modernice/goes/helper/streams"
 	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/internal/unique"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/projector"
 )
 
+// LookupProjection is the name of the persisted state of a Lookup (see
+// projector.Persist).
+const LookupProjection = "cms.media.image.gallery.lookup"
+
+// lookupEvents are the events that are projected by a Lookup.
+var lookupEvents = []string{
+	Created,
+	Deleted,
+	Renamed,
+	ImageUploaded,
+	ImageReplaced,
+	StackDeleted,
+	StackTagged,
+	StackUntagged,
+	StackTrashed,
+	StackRestored,
+	StackRenamed,
+	StackUpdated, // TODO: remove event type; it's too broad
+}
+
 // Lookup provides lookup of Gallery UUIDs. It is thread-safe.
 type Lookup struct {
+	projector *projector.Projector
+
+	// state is the persisted state of the Lookup, or nil.
+	state *projector.State
+
+	// applyMux serializes applied events and rebuilds.
+	applyMux sync.Mutex
+
 	galleriesMux sync.RWMutex
 	galleries    map[uuid.UUID]*galleryLookup
 
 	galleryNamesMux sync.RWMutex
 	galleryNameToID map[string]uuid.UUID
+	galleryIDToName map[uuid.UUID]string
+
+	checksumsMux   sync.RWMutex
+	checksums      map[string]stackRef
+	stackChecksums map[uuid.UUID]stackChecksum
+}
+
+type stackRef struct {
+	galleryID uuid.UUID
+	stackID   uuid.UUID
 }
 
-// NewLookup returns a new Lookup.
-func NewLookup() *Lookup {
+// stackChecksum is the checksum of a Stack and the Gallery of the Stack.
+type stackChecksum struct {
+	galleryID uuid.UUID
+	checksum  string
+}
+
+// NewLookup returns a new Lookup. The provided options configure the error
+// handling of the projection and the persistence of its state (see
+// projector.Persist).
+func NewLookup(opts ...projector.Option) *Lookup {
+	proj := projector.New(opts...)
 	return &Lookup{
+		projector:       proj,
+		state:           proj.State(LookupProjection),
 		galleries:       make(map[uuid.UUID]*galleryLookup),
 		galleryNameToID: make(map[string]uuid.UUID),
+		galleryIDToName: make(map[uuid.UUID]string),
+		checksums:       make(map[string]stackRef),
+		stackChecksums:  make(map[uuid.UUID]stackChecksum),
 	}
 }
 
@@ -34,46 +93,250 @@ func (l *Lookup) GalleryName(name string) (uuid.UUID, bool) {
 	return l.galleryName(name)
 }
 
+// GalleryRef is the UUID and name of a Gallery.
+type GalleryRef struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+// GalleryList is a page of the Galleries of a Lookup.
+type GalleryList struct {
+	Galleries []GalleryRef `json:"galleries"`
+
+	// Total is the number of Galleries.
+	Total int `json:"total"`
+
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// Galleries returns the page of all created Galleries, sorted by name. If
+// limit is 0, all Galleries after offset are returned.
+func (l *Lookup) Galleries(offset, limit int) GalleryList {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit < 0 {
+		limit = 0
+	}
+
+	l.galleryNamesMux.RLock()
+	refs := make([]GalleryRef, 0, len(l.galleryIDToName))
+	for id, name := range l.galleryIDToName {
+		refs = append(refs, GalleryRef{ID: id, Name: name})
+	}
+	l.galleryNamesMux.RUnlock()
+
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Name != refs[j].Name {
+			return refs[i].Name < refs[j].Name
+		}
+		return refs[i].ID.String() < refs[j].ID.String()
+	})
+
+	list := GalleryList{
+		Galleries: []GalleryRef{},
+		Total:     len(refs),
+		Offset:    offset,
+		Limit:     limit,
+	}
+
+	if offset >= len(refs) {
+		return list
+	}
+
+	refs = refs[offset:]
+	if limit > 0 && len(refs) > limit {
+		refs = refs[:limit]
+	}
+	list.Galleries = append(list.Galleries, refs...)
+
+	return list
+}
+
 // StackName returns the UUID of the Stack with the given StackName in the
 // Gallery with the given UUID, or false if Lookup has no UUID for name.
 func (l *Lookup) StackName(galleryID uuid.UUID, name string) (uuid.UUID, bool) {
 	return l.gallery(galleryID).name(name)
 }
 
-// Project projects the Lookup in a new goroutine and returns a channel of
-// asynchronous errors.
+// StacksByTag returns the UUIDs of the Stacks in the Gallery with the given
+// UUID whose original images have the given tag, sorted by UUID, or false if
+// Lookup has no Gallery with the given UUID.
+func (l *Lookup) StacksByTag(galleryID uuid.UUID, tag string) ([]uuid.UUID, bool) {
+	l.galleryNamesMux.RLock()
+	_, ok := l.galleryIDToName[galleryID]
+	l.galleryNamesMux.RUnlock()
+	if !ok {
+		return []uuid.UUID{}, false
+	}
+	return l.gallery(galleryID).stacksByTag(tag), true
+}
+
+// Checksum returns the UUIDs of the Gallery and Stack whose uploaded image or
+// video has the given SHA-256 checksum, or false if Lookup has no Stack for
+// checksum.
+func (l *Lookup) Checksum(checksum string) (galleryID, stackID uuid.UUID, ok bool) {
+	l.checksumsMux.RLock()
+	defer l.checksumsMux.RUnlock()
+	ref, ok := l.checksums[checksum]
+	return ref.galleryID, ref.stackID, ok
+}
+
+// HasStack returns whether the Gallery with the given UUID has a Stack with
+// the given UUID. Trashed and deleted Stacks are not found.
+func (l *Lookup) HasStack(galleryID, stackID uuid.UUID) bool {
+	// Every uploaded Stack has a checksum, so the checksums of the Lookup
+	// also track which Stacks exist.
+	l.checksumsMux.RLock()
+	defer l.checksumsMux.RUnlock()
+	ref, ok := l.stackChecksums[stackID]
+	return ok && ref.galleryID == galleryID
+}
+
+// Project catches up the Lookup with the past events, projects new events in
+// a new goroutine and returns a channel of asynchronous errors. If catching up
+// fails, the error is sent to the channel and Ready is not closed until the
+// Lookup is rebuilt (see Rebuild). Failed projection jobs
+// are handled according to the projector.Policy of the Lookup. If the state
+// of the Lookup is persisted (see projector.Persist), Project first restores
+// the persisted state, so that only the events after it are applied.
 func (l *Lookup) Project(ctx context.Context, bus event.Bus, store event.Store, opts ...schedule.ContinuousOption) (<-chan error, error) {
-	schedule := schedule.Continuously(bus, store, []string{
-		Created,
-		ImageUploaded,
-		StackDeleted,
-		StackRenamed,
-		StackUpdated, // TODO: remove event type; it's too broad
-	}, opts...)
-
-	errs, err := schedule.Subscribe(ctx, l.applyJob)
+	if l.state != nil {
+		if err := l.restore(ctx); err != nil {
+			return nil, fmt.Errorf("restore persisted state: %w", err)
+		}
+	}
+
+	schedule := schedule.Continuously(bus, store, lookupEvents, opts...)
+
+	return l.projector.Run(ctx, schedule, l)
+}
+
+// Ready returns a channel that is closed when the Lookup caught up with the
+// past events, either by Project or by Rebuild. Until then, lookups (e.g.
+// GalleryName) may miss Gallerys that already exist.
+func (l *Lookup) Ready() <-chan struct{} {
+	return l.projector.Ready()
+}
+
+// Rebuild replays the events of the Lookup from the event store into a new
+// state and replaces the current state with it, e.g. to repair a Lookup that
+// missed events. Rebuild blocks until all events are applied and marks the
+// Lookup as ready (see Ready). Events that are projected concurrently are
+// applied after the rebuild. If Rebuild fails, the current state is kept. If
+// the state of the Lookup is persisted, the persisted state is replaced, too.
+func (l *Lookup) Rebuild(ctx context.Context, store event.Store) error {
+	l.applyMux.Lock()
+	defer l.applyMux.Unlock()
+
+	events, errs, err := store.Query(ctx, query.New(
+		query.Name(lookupEvents...),
+		query.SortByTime(),
+	))
 	if err != nil {
-		return nil, fmt.Errorf("subscribe to projection schedule: %w", err)
+		return fmt.Errorf("query events: %w", err)
 	}
 
-	go schedule.Trigger(ctx)
+	rebuilt := NewLookup()
+	var progress projector.Progress
+	if err := streams.Walk(ctx, func(evt event.Event) error {
+		rebuilt.applyEvent(evt)
+		if !progress.Time.Equal(evt.Time()) {
+			progress = projector.Progress{Time: evt.Time()}
+		}
+		progress.Events = append(progress.Events, evt.ID())
+		return nil
+	}, events, errs); err != nil {
+		return fmt.Errorf("apply events: %w", err)
+	}
+
+	l.replace(rebuilt)
+	l.projector.MarkReady()
+
+	if l.state != nil {
+		l.state.Reset(rebuilt.entries(), progress)
+		if err := l.state.Flush(ctx); err != nil {
+			return fmt.Errorf("persist rebuilt state: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// restore replaces the current state with the persisted state.
+func (l *Lookup) restore(ctx context.Context) error {
+	entries, err := l.state.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	restored := NewLookup()
+	for key, value := range entries {
+		if err := restored.restoreEntry(key, value); err != nil {
+			return err
+		}
+	}
+
+	l.applyMux.Lock()
+	defer l.applyMux.Unlock()
+	l.replace(restored)
 
-	return errs, nil
+	return nil
 }
 
-func (l *Lookup) applyJob(job projection.Job) error {
-	return job.Apply(job, l)
+// replace replaces the current state with the state of another Lookup. The
+// caller must hold applyMux.
+func (l *Lookup) replace(other *Lookup) {
+	l.galleriesMux.Lock()
+	l.galleries = other.galleries
+	l.galleriesMux.Unlock()
+
+	l.galleryNamesMux.Lock()
+	l.galleryNameToID = other.galleryNameToID
+	l.galleryIDToName = other.galleryIDToName
+	l.galleryNamesMux.Unlock()
+
+	l.checksumsMux.Lock()
+	l.checksums = other.checksums
+	l.stackChecksums = other.stackChecksums
+	l.checksumsMux.Unlock()
+}
+
+// Status returns the projection status of the Lookup.
+func (l *Lookup) Status() projector.Status {
+	return l.projector.Status()
 }
 
 // ApplyEvent applies aggregate events.
 func (l *Lookup) ApplyEvent(evt event.Event) {
+	l.applyMux.Lock()
+	defer l.applyMux.Unlock()
+	l.applyEvent(evt)
+}
+
+func (l *Lookup) applyEvent(evt event.Event) {
 	switch evt.Name() {
 	case Created:
 		l.galleryCreated(evt)
+	case Deleted:
+		l.galleryDeleted(evt)
+	case Renamed:
+		l.galleryRenamed(evt)
 	case ImageUploaded:
 		l.imageUploaded(evt)
+	case ImageReplaced:
+		l.imageReplaced(evt)
 	case StackDeleted:
 		l.stackDeleted(evt)
+	case StackTagged:
+		l.stackTagged(evt)
+	case StackUntagged:
+		l.stackUntagged(evt)
+	case StackTrashed:
+		l.stackTrashed(evt)
+	case StackRestored:
+		l.stackRestored(evt)
 	case StackRenamed:
 		l.stackRenamed(evt)
 	case StackUpdated:
@@ -87,16 +350,98 @@ func (l *Lookup) galleryCreated(evt event.Event) {
 	l.setGalleryName(id, data.Name)
 }
 
+func (l *Lookup) galleryDeleted(evt event.Event) {
+	data := evt.Data().(DeletedData)
+	id, _, _ := evt.Aggregate()
+
+	l.galleryNamesMux.Lock()
+	if l.galleryNameToID[data.Name] == id {
+		delete(l.galleryNameToID, data.Name)
+	}
+	delete(l.galleryIDToName, id)
+	l.galleryNamesMux.Unlock()
+
+	l.galleriesMux.Lock()
+	if g, ok := l.galleries[id]; ok {
+		for _, name := range g.names() {
+			l.unpersist(stackKey(id, name))
+		}
+		for _, stackID := range g.stacks() {
+			l.unpersist(tagsKey(id, stackID))
+		}
+	}
+	delete(l.galleries, id)
+	l.galleriesMux.Unlock()
+	l.unpersist(galleryKey(id))
+
+	for _, stackID := range data.Stacks {
+		l.setChecksum(id, stackID, "")
+	}
+}
+
+func (l *Lookup) galleryRenamed(evt event.Event) {
+	data := evt.Data().(RenamedData)
+	id, _, _ := evt.Aggregate()
+
+	l.galleryNamesMux.Lock()
+	if l.galleryNameToID[data.OldName] == id {
+		delete(l.galleryNameToID, data.OldName)
+	}
+	l.galleryNamesMux.Unlock()
+
+	l.setGalleryName(id, data.Name)
+}
+
 func (l *Lookup) imageUploaded(evt event.Event) {
 	data := evt.Data().(ImageUploadedData)
 	id, _, _ := evt.Aggregate()
 	l.setStackName(id, data.Stack.ID, data.Stack.Original().Name)
+	l.addStackTags(id, data.Stack.ID, data.Stack.Original().Tags...)
+	l.setChecksum(id, data.Stack.ID, data.Stack.Checksum)
+}
+
+func (l *Lookup) imageReplaced(evt event.Event) {
+	data := evt.Data().(ImageReplacedData)
+	id, _, _ := evt.Aggregate()
+	l.setStackTags(id, data.Stack.ID, data.Stack.Original().Tags)
+	l.setChecksum(id, data.Stack.ID, data.Stack.Checksum)
 }
 
 func (l *Lookup) stackDeleted(evt event.Event) {
 	data := evt.Data().(StackDeletedData)
 	id, _, _ := evt.Aggregate()
 	l.removeStackName(id, data.Stack.ID, data.Stack.Original().Name)
+	l.removeStackTags(id, data.Stack.ID)
+	l.setChecksum(id, data.Stack.ID, "")
+}
+
+func (l *Lookup) stackTagged(evt event.Event) {
+	data := evt.Data().(StackTaggedData)
+	id, _, _ := evt.Aggregate()
+	l.addStackTags(id, data.StackID, data.Tags...)
+}
+
+func (l *Lookup) stackUntagged(evt event.Event) {
+	data := evt.Data().(StackUntaggedData)
+	id, _, _ := evt.Aggregate()
+	tags := media.File{Tags: l.gallery(id).tags(data.StackID)}.WithoutTag(data.Tags...).Tags
+	l.setStackTags(id, data.StackID, tags)
+}
+
+func (l *Lookup) stackTrashed(evt event.Event) {
+	data := evt.Data().(StackTrashedData)
+	id, _, _ := evt.Aggregate()
+	l.removeStackName(id, data.Stack.ID, data.Stack.Original().Name)
+	l.removeStackTags(id, data.Stack.ID)
+	l.setChecksum(id, data.Stack.ID, "")
+}
+
+func (l *Lookup) stackRestored(evt event.Event) {
+	data := evt.Data().(StackRestoredData)
+	id, _, _ := evt.Aggregate()
+	l.setStackName(id, data.Stack.ID, data.Stack.Original().Name)
+	l.addStackTags(id, data.Stack.ID, data.Stack.Original().Tags...)
+	l.setChecksum(id, data.Stack.ID, data.Stack.Checksum)
 }
 
 func (l *Lookup) stackRenamed(evt event.Event) {
@@ -110,12 +455,15 @@ func (l *Lookup) stackUpdated(evt event.Event) {
 	data := evt.Data().(StackUpdatedData)
 	id, _, _ := evt.Aggregate()
 	l.setStackName(id, data.Stack.ID, data.Stack.Original().Name)
+	l.setStackTags(id, data.Stack.ID, data.Stack.Original().Tags)
 }
 
 func (l *Lookup) setGalleryName(galleryID uuid.UUID, name string) {
 	l.galleryNamesMux.Lock()
 	defer l.galleryNamesMux.Unlock()
 	l.galleryNameToID[name] = galleryID
+	l.galleryIDToName[galleryID] = name
+	l.persist(galleryKey(galleryID), name)
 }
 
 func (l *Lookup) galleryName(name string) (uuid.UUID, bool) {
@@ -128,11 +476,60 @@ func (l *Lookup) galleryName(name string) (uuid.UUID, bool) {
 func (l *Lookup) setStackName(galleryID, stackID uuid.UUID, name string) {
 	s := l.gallery(galleryID)
 	s.setStackName(stackID, name)
+	l.persist(stackKey(galleryID, name), stackID.String())
 }
 
 func (l *Lookup) removeStackName(galleryID, stackID uuid.UUID, name string) {
 	s := l.gallery(galleryID)
-	s.removeStackName(stackID, name)
+	if s.removeStackName(stackID, name) {
+		l.unpersist(stackKey(galleryID, name))
+	}
+}
+
+// setStackTags sets the tags of the given Stack.
+func (l *Lookup) setStackTags(galleryID, stackID uuid.UUID, tags []string) {
+	tags = l.gallery(galleryID).setStackTags(stackID, tags)
+	b, _ := json.Marshal(tags)
+	l.persist(tagsKey(galleryID, stackID), string(b))
+}
+
+// addStackTags adds tags to the given Stack. Uploaded and restored Stacks add
+// their tags instead of replacing the known tags, because the event bus may
+// deliver the StackTagged events of the Stack first.
+func (l *Lookup) addStackTags(galleryID, stackID uuid.UUID, tags ...string) {
+	tags = media.File{Tags: l.gallery(galleryID).tags(stackID)}.WithTag(tags...).Tags
+	l.setStackTags(galleryID, stackID, tags)
+}
+
+// removeStackTags removes the given Stack from the tag lookup.
+func (l *Lookup) removeStackTags(galleryID, stackID uuid.UUID) {
+	l.gallery(galleryID).removeStackTags(stackID)
+	l.unpersist(tagsKey(galleryID, stackID))
+}
+
+// setChecksum sets the checksum of the given Stack. An empty checksum removes
+// the Stack from the checksum lookup.
+func (l *Lookup) setChecksum(galleryID, stackID uuid.UUID, checksum string) {
+	l.checksumsMux.Lock()
+	defer l.checksumsMux.Unlock()
+
+	if old, ok := l.stackChecksums[stackID]; ok {
+		if l.checksums[old.checksum].stackID == stackID {
+			delete(l.checksums, old.checksum)
+		}
+		delete(l.stackChecksums, stackID)
+		l.unpersist(checksumKey(stackID))
+	}
+
+	if checksum == "" {
+		return
+	}
+
+	l.stackChecksums[stackID] = stackChecksum{galleryID: galleryID, checksum: checksum}
+	l.persist(checksumKey(stackID), galleryID.String()+"/"+checksum)
+	if _, taken := l.checksums[checksum]; !taken {
+		l.checksums[checksum] = stackRef{galleryID: galleryID, stackID: stackID}
+	}
 }
 
 func (l *Lookup) gallery(id uuid.UUID) *galleryLookup {
@@ -154,10 +551,18 @@ func (l *Lookup) gallery(id uuid.UUID) *galleryLookup {
 type galleryLookup struct {
 	mux           sync.RWMutex
 	stackNameToID map[string]uuid.UUID
+
+	// stackTags are the tags of the Stacks of the Gallery.
+	stackTags map[uuid.UUID][]string
+	tagStacks map[string]map[uuid.UUID]struct{}
 }
 
 func newGalleryLookup() *galleryLookup {
-	return &galleryLookup{stackNameToID: make(map[string]uuid.UUID)}
+	return &galleryLookup{
+		stackNameToID: make(map[string]uuid.UUID),
+		stackTags:     make(map[uuid.UUID][]string),
+		tagStacks:     make(map[string]map[uuid.UUID]struct{}),
+	}
 }
 
 func (l *galleryLookup) setStackName(id uuid.UUID, name string) {
@@ -166,12 +571,14 @@ func (l *galleryLookup) setStackName(id uuid.UUID, name string) {
 	l.stackNameToID[name] = id
 }
 
-func (l *galleryLookup) removeStackName(id uuid.UUID, name string) {
+func (l *galleryLookup) removeStackName(id uuid.UUID, name string) bool {
 	l.mux.Lock()
 	defer l.mux.Unlock()
-	if l.stackNameToID[name] == id {
-		delete(l.stackNameToID, name)
+	if l.stackNameToID[name] != id {
+		return false
 	}
+	delete(l.stackNameToID, name)
+	return true
 }
 
 func (l *galleryLookup) name(name string) (uuid.UUID, bool) {
@@ -180,3 +587,195 @@ func (l *galleryLookup) name(name string) (uuid.UUID, bool) {
 	id, ok := l.stackNameToID[name]
 	return id, ok
 }
+
+// setStackTags replaces the tags of the given Stack and returns the unique
+// tags.
+func (l *galleryLookup) setStackTags(id uuid.UUID, tags []string) []string {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	l.untag(id)
+
+	tags = append([]string{}, unique.Strings(tags...)...)
+	l.stackTags[id] = tags
+	for _, tag := range tags {
+		stacks, ok := l.tagStacks[tag]
+		if !ok {
+			stacks = make(map[uuid.UUID]struct{})
+			l.tagStacks[tag] = stacks
+		}
+		stacks[id] = struct{}{}
+	}
+
+	return tags
+}
+
+func (l *galleryLookup) removeStackTags(id uuid.UUID) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.untag(id)
+	delete(l.stackTags, id)
+}
+
+// untag removes the given Stack from the Stacks of its tags. The caller must
+// hold mux.
+func (l *galleryLookup) untag(id uuid.UUID) {
+	for _, tag := range l.stackTags[id] {
+		stacks := l.tagStacks[tag]
+		delete(stacks, id)
+		if len(stacks) == 0 {
+			delete(l.tagStacks, tag)
+		}
+	}
+}
+
+func (l *galleryLookup) tags(id uuid.UUID) []string {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+	return append([]string(nil), l.stackTags[id]...)
+}
+
+func (l *galleryLookup) stacksByTag(tag string) []uuid.UUID {
+	l.mux.RLock()
+	ids := make([]uuid.UUID, 0, len(l.tagStacks[tag]))
+	for id := range l.tagStacks[tag] {
+		ids = append(ids, id)
+	}
+	l.mux.RUnlock()
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+
+	return ids
+}
+
+func (l *galleryLookup) stacks() []uuid.UUID {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+	ids := make([]uuid.UUID, 0, len(l.stackTags))
+	for id := range l.stackTags {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (l *galleryLookup) names() []string {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+	names := make([]string, 0, len(l.stackNameToID))
+	for name := range l.stackNameToID {
+		names = append(names, name)
+	}
+	return names
+}
+
+// The persisted state of a Lookup consists of the following entries:
+//
+//	gallery/<galleryID>          -> <name>
+//	stack/<galleryID>/<name>     -> <stackID>
+//	tags/<galleryID>/<stackID>   -> <JSON array of tags>
+//	checksum/<stackID>           -> <galleryID>/<checksum>
+
+func galleryKey(galleryID uuid.UUID) string {
+	return "gallery/" + galleryID.String()
+}
+
+func stackKey(galleryID uuid.UUID, name string) string {
+	return "stack/" + galleryID.String() + "/" + name
+}
+
+func tagsKey(galleryID, stackID uuid.UUID) string {
+	return "tags/" + galleryID.String() + "/" + stackID.String()
+}
+
+func checksumKey(stackID uuid.UUID) string {
+	return "checksum/" + stackID.String()
+}
+
+func (l *Lookup) persist(key, value string) {
+	if l.state != nil {
+		l.state.Set(key, value)
+	}
+}
+
+func (l *Lookup) unpersist(key string) {
+	if l.state != nil {
+		l.state.Delete(key)
+	}
+}
+
+// entries returns the current state as persisted entries.
+func (l *Lookup) entries() map[string]string {
+	entries := make(map[string]string)
+
+	l.galleryNamesMux.RLock()
+	for id, name := range l.galleryIDToName {
+		entries[galleryKey(id)] = name
+	}
+	l.galleryNamesMux.RUnlock()
+
+	l.galleriesMux.RLock()
+	for galleryID, g := range l.galleries {
+		g.mux.RLock()
+		for name, stackID := range g.stackNameToID {
+			entries[stackKey(galleryID, name)] = stackID.String()
+		}
+		for stackID, tags := range g.stackTags {
+			b, _ := json.Marshal(tags)
+			entries[tagsKey(galleryID, stackID)] = string(b)
+		}
+		g.mux.RUnlock()
+	}
+	l.galleriesMux.RUnlock()
+
+	l.checksumsMux.RLock()
+	for stackID, c := range l.stackChecksums {
+		entries[checksumKey(stackID)] = c.galleryID.String() + "/" + c.checksum
+	}
+	l.checksumsMux.RUnlock()
+
+	return entries
+}
+
+// restoreEntry applies a persisted entry.
+func (l *Lookup) restoreEntry(key, value string) error {
+	kind, rest, _ := strings.Cut(key, "/")
+	rawID, name, hasName := strings.Cut(rest, "/")
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return fmt.Errorf("invalid entry %q: %w", key, err)
+	}
+
+	switch kind {
+	case "gallery":
+		l.setGalleryName(id, value)
+	case "stack":
+		stackID, err := uuid.Parse(value)
+		if err != nil || !hasName {
+			return fmt.Errorf("invalid entry %q", key)
+		}
+		l.setStackName(id, stackID, name)
+	case "tags":
+		stackID, err := uuid.Parse(name)
+		var tags []string
+		if err == nil {
+			err = json.Unmarshal([]byte(value), &tags)
+		}
+		if err != nil || !hasName {
+			return fmt.Errorf("invalid entry %q", key)
+		}
+		l.setStackTags(id, stackID, tags)
+	case "checksum":
+		rawGalleryID, checksum, _ := strings.Cut(value, "/")
+		galleryID, err := uuid.Parse(rawGalleryID)
+		if err != nil || checksum == "" {
+			return fmt.Errorf("invalid entry %q", key)
+		}
+		l.setChecksum(galleryID, id, checksum)
+	default:
+		return fmt.Errorf("unknown entry %q", key)
+	}
+
+	return nil
+}
@@ -0,0 +1,433 @@
+package gallery_test
+
+import (
+	"bytes"
+	"context"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/projector"
+)
+
+func TestLookup_Checksum(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	galleries := gallery.GoesRepository(repository.New(estore))
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	lookup := gallery.NewLookup()
+
+	errs, err := lookup.Project(ctx, ebus, estore)
+	if err != nil {
+		t.Fatalf("run lookup: %v", err)
+	}
+	go func() {
+		for err := range errs {
+			panic(err)
+		}
+	}()
+
+	_, buf := imggen.ColoredRectangle(80, 60, color.Black)
+	checksum, err := media.Checksum(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("compute checksum: %v", err)
+	}
+
+	if _, _, ok := lookup.Checksum(checksum); ok {
+		t.Fatalf("Checksum(%q) should return %v", checksum, false)
+	}
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+	stack, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload image: %v", err)
+	}
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save Gallery: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	galleryID, stackID, ok := lookup.Checksum(checksum)
+	if !ok {
+		t.Fatalf("Checksum(%q) returned %v", checksum, false)
+	}
+
+	if galleryID != g.ID || stackID != stack.ID {
+		t.Fatalf("Checksum(%q) should return (%s, %s); got (%s, %s)", checksum, g.ID, stack.ID, galleryID, stackID)
+	}
+
+	if !lookup.HasStack(g.ID, stack.ID) {
+		t.Fatalf("HasStack(%s, %s) should return %v", g.ID, stack.ID, true)
+	}
+
+	if lookup.HasStack(uuid.New(), stack.ID) {
+		t.Fatalf("HasStack should return %v for another Gallery", false)
+	}
+
+	if err := g.Delete(ctx, storage, stack); err != nil {
+		t.Fatalf("delete Stack: %v", err)
+	}
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save Gallery: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	if _, _, ok := lookup.Checksum(checksum); ok {
+		t.Fatalf("Checksum(%q) should return %v after the Stack was deleted", checksum, false)
+	}
+
+	if lookup.HasStack(g.ID, stack.ID) {
+		t.Fatalf("HasStack(%s, %s) should return %v after the Stack was deleted", g.ID, stack.ID, false)
+	}
+}
+
+func TestLookup_Galleries(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	lookup := gallery.NewLookup()
+
+	var deleted *gallery.Gallery
+	for _, name := range []string{"foo", "bar", "baz"} {
+		g := gallery.New(uuid.New())
+		g.Create(name)
+		if name == "baz" {
+			if _, err := g.Destroy(ctx, storage); err != nil {
+				t.Fatalf("destroy Gallery: %v", err)
+			}
+			deleted = g
+		}
+		for _, evt := range g.AggregateChanges() {
+			lookup.ApplyEvent(evt)
+		}
+	}
+
+	list := lookup.Galleries(0, 0)
+	if list.Total != 2 || len(list.Galleries) != 2 {
+		t.Fatalf("Galleries should return 2 Galleries; got %v", list)
+	}
+	if list.Galleries[0].Name != "bar" || list.Galleries[1].Name != "foo" {
+		t.Fatalf("Galleries should be sorted by name; got %v", list.Galleries)
+	}
+	for _, ref := range list.Galleries {
+		if ref.ID == deleted.ID {
+			t.Fatalf("Galleries should not return the deleted Gallery")
+		}
+		if id, _ := lookup.GalleryName(ref.Name); id != ref.ID {
+			t.Fatalf("Gallery %q should have UUID %s; has %s", ref.Name, id, ref.ID)
+		}
+	}
+
+	page := lookup.Galleries(1, 1)
+	if page.Total != 2 || len(page.Galleries) != 1 || page.Galleries[0] != list.Galleries[1] {
+		t.Fatalf("Galleries(1, 1) should return %v; got %v", list.Galleries[1:], page)
+	}
+
+	if page := lookup.Galleries(5, 1); page.Total != 2 || len(page.Galleries) != 0 {
+		t.Fatalf("Galleries(5, 1) should return an empty page; got %v", page)
+	}
+}
+
+func TestLookup_GalleryName_renamed(t *testing.T) {
+	lookup := gallery.NewLookup()
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+	g.Rename("bar")
+	for _, evt := range g.AggregateChanges() {
+		lookup.ApplyEvent(evt)
+	}
+
+	if _, ok := lookup.GalleryName("foo"); ok {
+		t.Fatalf("the old name should not be found after renaming the Gallery")
+	}
+	if id, ok := lookup.GalleryName("bar"); !ok || id != g.ID {
+		t.Fatalf("GalleryName(%q) should return %s; got %s (%v)", "bar", g.ID, id, ok)
+	}
+	if list := lookup.Galleries(0, 0); len(list.Galleries) != 1 || list.Galleries[0].Name != "bar" {
+		t.Fatalf("Galleries should return the new name; got %v", list.Galleries)
+	}
+}
+
+func TestLookup_Project_ready(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	galleries := gallery.GoesRepository(repository.New(estore))
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save Gallery: %v", err)
+	}
+
+	lookup := gallery.NewLookup()
+
+	if _, err := lookup.Project(ctx, ebus, estore); err != nil {
+		t.Fatalf("run lookup: %v", err)
+	}
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("Lookup should be ready after catching up")
+	case <-lookup.Ready():
+	}
+
+	if !lookup.Status().Ready {
+		t.Fatalf("Status should report the Lookup as ready")
+	}
+
+	if id, ok := lookup.GalleryName("foo"); !ok || id != g.ID {
+		t.Fatalf("GalleryName(%q) should return %s once the Lookup is ready; got %s (%v)", "foo", g.ID, id, ok)
+	}
+}
+
+func TestLookup_Rebuild(t *testing.T) {
+	ctx := context.Background()
+	estore := eventstore.New()
+	galleries := gallery.GoesRepository(repository.New(estore))
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save Gallery: %v", err)
+	}
+
+	lookup := gallery.NewLookup()
+
+	select {
+	case <-lookup.Ready():
+		t.Fatalf("Lookup should not be ready before it caught up")
+	default:
+	}
+
+	// an event that is not in the store is discarded by the rebuild
+	stale := gallery.New(uuid.New())
+	stale.Create("stale")
+	for _, evt := range stale.AggregateChanges() {
+		lookup.ApplyEvent(evt)
+	}
+
+	if err := lookup.Rebuild(ctx, estore); err != nil {
+		t.Fatalf("Rebuild failed with %q", err)
+	}
+
+	select {
+	case <-lookup.Ready():
+	default:
+		t.Fatalf("Lookup should be ready after Rebuild")
+	}
+
+	if id, ok := lookup.GalleryName("foo"); !ok || id != g.ID {
+		t.Fatalf("GalleryName(%q) should return %s; got %s (%v)", "foo", g.ID, id, ok)
+	}
+
+	if _, ok := lookup.GalleryName("stale"); ok {
+		t.Fatalf("Rebuild should replace the state of the Lookup")
+	}
+}
+
+func TestLookup_Project_persist(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	galleries := gallery.GoesRepository(repository.New(estore))
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	states := projector.MemoryStateStore()
+
+	lookup := gallery.NewLookup(projector.Persist(states))
+	if _, err := lookup.Project(ctx, ebus, estore); err != nil {
+		t.Fatalf("run lookup: %v", err)
+	}
+	<-lookup.Ready()
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+	_, buf := imggen.ColoredRectangle(80, 60, color.Black)
+	stack, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload image: %v", err)
+	}
+	if _, err := g.RenameStack(ctx, stack.ID, "renamed"); err != nil {
+		t.Fatalf("rename Stack: %v", err)
+	}
+	if _, err := g.Tag(ctx, stack, "hero"); err != nil {
+		t.Fatalf("tag Stack: %v", err)
+	}
+	if err := g.Rename("bar"); err != nil {
+		t.Fatalf("rename Gallery: %v", err)
+	}
+	changes := g.AggregateChanges()
+	last := changes[len(changes)-1]
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save Gallery: %v", err)
+	}
+
+	awaitProgress(t, states, gallery.LookupProjection, last.Time())
+
+	// The restarted Lookup projects an empty event store, so it can only know
+	// the Gallery from the persisted state.
+	restarted := gallery.NewLookup(projector.Persist(states))
+	if _, err := restarted.Project(ctx, eventbus.New(), eventstore.New()); err != nil {
+		t.Fatalf("run lookup: %v", err)
+	}
+	<-restarted.Ready()
+
+	if id, ok := restarted.GalleryName("bar"); !ok || id != g.ID {
+		t.Fatalf("GalleryName(%q) should return %s; got %s (%v)", "bar", g.ID, id, ok)
+	}
+
+	if _, ok := restarted.GalleryName("foo"); ok {
+		t.Fatalf("GalleryName(%q) should return %v after the Gallery was renamed", "foo", false)
+	}
+
+	if id, ok := restarted.StackName(g.ID, "renamed"); !ok || id != stack.ID {
+		t.Fatalf("StackName(%q) should return %s; got %s (%v)", "renamed", stack.ID, id, ok)
+	}
+
+	if _, ok := restarted.StackName(g.ID, exampleName); ok {
+		t.Fatalf("StackName(%q) should return %v after the Stack was renamed", exampleName, false)
+	}
+
+	if ids, _ := restarted.StacksByTag(g.ID, "hero"); len(ids) != 1 || ids[0] != stack.ID {
+		t.Fatalf("StacksByTag(%q) should return [%s]; got %v", "hero", stack.ID, ids)
+	}
+
+	if galleryID, stackID, ok := restarted.Checksum(stack.Checksum); !ok || galleryID != g.ID || stackID != stack.ID {
+		t.Fatalf("Checksum(%q) should return (%s, %s); got (%s, %s, %v)", stack.Checksum, g.ID, stack.ID, galleryID, stackID, ok)
+	}
+
+	if err := restarted.Rebuild(ctx, eventstore.New()); err != nil {
+		t.Fatalf("Rebuild failed with %q", err)
+	}
+
+	if entries, _, _ := states.Load(ctx, gallery.LookupProjection); len(entries) != 0 {
+		t.Fatalf("Rebuild should replace the persisted state; got %v", entries)
+	}
+}
+
+// awaitProgress waits until the persisted Progress of the given projection
+// reached the given time.
+func awaitProgress(t *testing.T, states projector.StateStore, projection string, at time.Time) {
+	timeout := time.After(time.Second)
+	for {
+		_, progress, err := states.Load(context.Background(), projection)
+		if err != nil {
+			t.Fatalf("load state: %v", err)
+		}
+		if !progress.Time.Before(at) {
+			return
+		}
+
+		select {
+		case <-timeout:
+			t.Fatalf("Progress should reach %v; is %v", at, progress.Time)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func TestLookup_StacksByTag(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	galleries := gallery.GoesRepository(repository.New(estore))
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	lookup := gallery.NewLookup()
+	if _, err := lookup.Project(ctx, ebus, estore); err != nil {
+		t.Fatalf("run lookup: %v", err)
+	}
+	<-lookup.Ready()
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+	if err := g.SetDefaultTags("default"); err != nil {
+		t.Fatalf("set default tags: %v", err)
+	}
+
+	var stacks []gallery.Stack
+	for _, path := range []string{"/example/1.png", "/example/2.png"} {
+		_, buf := imggen.ColoredRectangle(80, 60, color.Black)
+		stack, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, path)
+		if err != nil {
+			t.Fatalf("upload image: %v", err)
+		}
+		stacks = append(stacks, stack)
+	}
+
+	if _, err := g.Tag(ctx, stacks[0], "hero", "logo"); err != nil {
+		t.Fatalf("tag Stack: %v", err)
+	}
+	if _, err := g.Tag(ctx, stacks[1], "hero"); err != nil {
+		t.Fatalf("tag Stack: %v", err)
+	}
+	if _, err := g.Untag(ctx, stacks[0], "logo"); err != nil {
+		t.Fatalf("untag Stack: %v", err)
+	}
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save Gallery: %v", err)
+	}
+
+	awaitStacksByTag(t, lookup, g.ID, "hero", 2)
+
+	if ids, _ := lookup.StacksByTag(g.ID, "default"); len(ids) != 2 {
+		t.Fatalf("StacksByTag(%q) should return the Stacks with default tags; got %v", "default", ids)
+	}
+
+	if ids, _ := lookup.StacksByTag(g.ID, "logo"); len(ids) != 0 {
+		t.Fatalf("StacksByTag(%q) should return no Stacks after untagging; got %v", "logo", ids)
+	}
+
+	if ids, ok := lookup.StacksByTag(uuid.New(), "hero"); ok || len(ids) != 0 {
+		t.Fatalf("StacksByTag should return no Stacks and %v for an unknown Gallery; got %v (%v)", false, ids, ok)
+	}
+
+	if _, err := g.Trash(stacks[1].ID); err != nil {
+		t.Fatalf("trash Stack: %v", err)
+	}
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save Gallery: %v", err)
+	}
+
+	awaitStacksByTag(t, lookup, g.ID, "hero", 1)
+
+	if ids, _ := lookup.StacksByTag(g.ID, "hero"); ids[0] != stacks[0].ID {
+		t.Fatalf("StacksByTag(%q) should return Stack %s; got %v", "hero", stacks[0].ID, ids)
+	}
+}
+
+func awaitStacksByTag(t *testing.T, lookup *gallery.Lookup, galleryID uuid.UUID, tag string, n int) {
+	timeout := time.After(time.Second)
+	for {
+		ids, _ := lookup.StacksByTag(galleryID, tag)
+		if len(ids) == n {
+			return
+		}
+
+		select {
+		case <-timeout:
+			t.Fatalf("StacksByTag(%q) should return %d Stacks; got %v", tag, n, ids)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
@@ -3,11 +3,13 @@ package gallery
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	stdimage "image"
 	"io"
 	"math"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
 	"sync"
@@ -19,6 +21,9 @@ import (
 	"github.com/modernice/nice-cms/internal/concurrent"
 	"github.com/modernice/nice-cms/media"
 	"github.com/modernice/nice-cms/media/image"
+	"github.com/modernice/nice-cms/media/image/blurhash"
+	"github.com/modernice/nice-cms/media/image/exif"
+	"github.com/modernice/nice-cms/media/image/palette"
 )
 
 // ProcessorContext is passed to Processors when they process a Stack. The
@@ -72,6 +77,25 @@ func (ctx *ProcessorContext) Encode(w io.Writer, img stdimage.Image, format stri
 	return ctx.encoder.Encode(w, img, format)
 }
 
+// encodeReader encodes img in a new goroutine and returns a reader for the
+// encoded image. Encoding errors are returned by the Read method of the
+// returned reader. Callers must close the returned reader.
+func (ctx *ProcessorContext) encodeReader(img stdimage.Image, format string) io.ReadCloser {
+	return encodeReader(ctx.encoder, img, format)
+}
+
+func encodeReader(enc image.Encoder, img stdimage.Image, format string) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		if err := enc.Encode(pw, img, format); err != nil {
+			pw.CloseWithError(fmt.Errorf("encode %q image: %w", format, err))
+			return
+		}
+		pw.Close()
+	}()
+	return pr
+}
+
 // Storage returns the media storage.
 func (ctx *ProcessorContext) Storage() media.Storage {
 	return ctx.storage
@@ -98,7 +122,9 @@ type ProcessingPipeline []Processor
 type ProcessorOption func(*processorConfig)
 
 type processorConfig struct {
-	logger Printer
+	logger      Printer
+	concurrency int
+	inst        Instrumentation
 }
 
 func WithDebugger(logger Printer) ProcessorOption {
@@ -107,7 +133,18 @@ func WithDebugger(logger Printer) ProcessorOption {
 	}
 }
 
+// WithConcurrency returns a ProcessorOption that limits the number of sizes
+// that the Resizer and Cropper generate and upload concurrently for a single
+// Stack. Default concurrency is the number of CPUs.
+func WithConcurrency(n int) ProcessorOption {
+	return func(cfg *processorConfig) {
+		cfg.concurrency = n
+	}
+}
+
 // Process calls each Processor in the ProcessingPipeline with a ProcesingContext.
+// When all Processors succeeded, stale Images that were not replaced by the
+// Processors are removed from the returned Stack.
 func (pipe ProcessingPipeline) Process(
 	ctx context.Context,
 	stack Stack,
@@ -123,14 +160,88 @@ func (pipe ProcessingPipeline) Process(
 	pctx := newProcessorContext(ctx, cfg, stack, imageEncoder, storage)
 
 	for i, proc := range pipe {
-		if err := proc.Process(pctx); err != nil {
+		start := time.Now()
+		err := proc.Process(pctx)
+		if cfg.inst != nil {
+			cfg.inst.ProcessorDone(ProcessorName(proc), time.Since(start), err)
+		}
+		if err != nil {
 			return pctx.stack, fmt.Errorf("processor #%d failed: %w", i+1, err)
 		}
 	}
 
-	return pctx.stack, nil
+	// Stale Images that weren't replaced by the Processors don't match the
+	// original anymore.
+	return pctx.stack.withoutStale(), nil
+}
+
+// PreviewResult is an Image that a ProcessingPipeline would produce for an
+// image. See ProcessingPipeline.Preview.
+type PreviewResult struct {
+	// Size is the size of the Image, or an empty string for the original Image
+	// and its conversions.
+	Size string `json:"size"`
+
+	// Format is the format of a converted Image (e.g. "webp"), or an empty
+	// string for Images in the format of the original image.
+	Format string `json:"format,omitempty"`
+
+	Original bool `json:"original"`
+	Cropped  bool `json:"cropped,omitempty"`
+	Width    int  `json:"width"`
+	Height   int  `json:"height"`
+
+	// Bytes is the file size of the Image in bytes.
+	Bytes int `json:"bytes"`
+}
+
+// Preview runs the ProcessingPipeline on the image in r without persisting
+// anything and reports the Images that the ProcessingPipeline would produce,
+// so that pipelines can be tuned before they are used by the PostProcessor.
+// The Images are processed in an in-memory Storage that is discarded when
+// Preview returns.
+func (pipe ProcessingPipeline) Preview(
+	ctx context.Context,
+	r io.Reader,
+	imageEncoder image.Encoder,
+	opts ...ProcessorOption,
+) ([]PreviewResult, error) {
+	storage := media.NewStorage(media.ConfigureDisk(previewDisk, media.MemoryDisk()))
+
+	org, err := media.NewImage(0, 0, "preview", previewDisk, "preview", 0).Upload(ctx, r, storage)
+	if err != nil {
+		return nil, fmt.Errorf("upload image: %w", err)
+	}
+
+	stack := Stack{
+		ID:     uuid.New(),
+		Images: []Image{{Image: org, Original: true}},
+	}
+
+	processed, err := pipe.Process(ctx, stack, imageEncoder, storage, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]PreviewResult, len(processed.Images))
+	for i, img := range processed.Images {
+		out[i] = PreviewResult{
+			Size:     img.Size,
+			Format:   img.Format,
+			Original: img.Original,
+			Cropped:  img.Cropped,
+			Width:    img.Width,
+			Height:   img.Height,
+			Bytes:    img.Filesize,
+		}
+	}
+
+	return out, nil
 }
 
+// previewDisk is the name of the in-memory storage disk of Preview.
+const previewDisk = "preview"
+
 // A Processor processes an image through a ProcessorContext.
 type Processor interface {
 	Process(*ProcessorContext) error
@@ -145,11 +256,67 @@ func (fn ProcessorFunc) Process(ctx *ProcessorContext) error {
 }
 
 // A Resizer is a Processor that resizes the original Image of a Stack into
-// additional dimensions.
+// additional dimensions. If the Stack has a Crop, the cropped area of the
+// original Image is resized.
 type Resizer image.Resizer
 
 // Process runs the Resizer on the Stack in the given ProcessorContext.
 func (r Resizer) Process(ctx *ProcessorContext) error {
+	s := ctx.Stack()
+	resizer := resizerWithoutSizes((image.Resizer)(r), s.Sizes())
+
+	return processSizes(ctx, "Resizer", resizer, false, func(src stdimage.Image, d image.Dimensions) stdimage.Image {
+		return d.Resize(src)
+	})
+}
+
+// A Cropper is a Processor that crops the original Image of a Stack into
+// additional dimensions, which makes the aspect ratio of each size fixed. The
+// images are cropped around the FocalPoint of the Stack, or around their
+// center if the Stack has no FocalPoint. Dimensions without a Width or Height
+// are resized instead of cropped. If the Stack has a Crop, the images are
+// generated from the cropped area of the original Image.
+//
+// When the FocalPoint of a Stack changes, its cropped Images become stale and
+// the Stack is processed again, so that Cropper crops them around the new
+// FocalPoint. Cropper should run before the FormatConverter.
+type Cropper image.Cropper
+
+// Process runs the Cropper on the Stack in the given ProcessorContext.
+func (c Cropper) Process(ctx *ProcessorContext) error {
+	s := ctx.Stack()
+	cropper := image.Cropper(resizerWithoutSizes(image.Resizer(c), s.Sizes()))
+
+	focus := image.Center
+	if s.FocalPoint != nil {
+		focus = *s.FocalPoint
+	}
+	if s.Crop != nil {
+		focus = s.Crop.Focus(focus)
+	}
+
+	return processSizes(ctx, "Cropper", image.Resizer(cropper), true, func(src stdimage.Image, d image.Dimensions) stdimage.Image {
+		return d.Crop(src, focus)
+	})
+}
+
+// processSizes generates the given sizes of the original Image of the Stack by
+// calling generate with the downloaded original (cropped to the Crop of the
+// Stack), uploads the generated images next to the original and adds them to
+// the Stack. The sizes are generated and uploaded concurrently; the number of
+// concurrently processed sizes is limited by WithConcurrency. The pixels of
+// the original are decoded once and shared between the sizes.
+func processSizes(
+	ctx *ProcessorContext,
+	name string,
+	sizes image.Resizer,
+	cropped bool,
+	generate func(stdimage.Image, image.Dimensions) stdimage.Image,
+) error {
+	if len(sizes) == 0 {
+		return nil
+	}
+
 	s := ctx.Stack()
 	org := s.Original()
 	storage := ctx.Storage()
@@ -159,46 +326,79 @@ func (r Resizer) Process(ctx *ProcessorContext) error {
 		return fmt.Errorf("download original image %q (%s): %w", org.Path, org.Disk, err)
 	}
 
-	resizer := resizerWithoutSizes((image.Resizer)(r), s.Sizes())
+	if s.Crop != nil {
+		ctx.cfg.logf("[%s] Crop original image (StackID=%v Crop=%v)", name, s.ID, *s.Crop)
+		original = s.Crop.Crop(original)
+	}
+	src := image.NRGBA(original)
+
+	uploadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	ctx.cfg.logf("[Resizer] Resize image (StackID=%v Sizes=%v)", s.ID, resizer)
+	concurrency := ctx.cfg.sizeConcurrency()
+	ctx.cfg.logf("[%s] Process sizes (StackID=%v Sizes=%v Concurrency=%d)", name, s.ID, sizes, concurrency)
 	start := time.Now()
-	resized := resizer.Resize(original)
-	ctx.cfg.logf("[Resizer] Resize done (StackID=%v Duration=%v)", s.ID, time.Since(start))
 
-	encoded := make(map[string]*bytes.Buffer)
+	var (
+		mux           sync.Mutex
+		firstErr      error
+		resizedImages = make([]Image, 0, len(sizes))
+	)
 
-	for size, resizedImage := range resized {
-		var buf bytes.Buffer
-		if err := ctx.Encode(&buf, resizedImage, format); err != nil {
-			return fmt.Errorf("encode %q resized image in %q format: %w", size, format, err)
-		}
-		encoded[size] = &buf
-	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
 
-	resizedImages := make([]Image, 0, len(encoded))
+	for size, dim := range sizes {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(size string, dim image.Dimensions) {
+			defer wg.Done()
+			defer func() { <-sem }()
 
-	for size, buf := range encoded {
-		path := r.path(org.Path, size, format)
+			if uploadCtx.Err() != nil {
+				return
+			}
 
-		img := media.NewImage(0, 0, org.Name, org.Disk, path, 0)
+			path := sizePath(org.Path, size, format)
+			img := media.NewImage(0, 0, org.Name, org.Disk, path, 0)
 
-		ctx.cfg.logf("[Resizer] Upload resized image (StackID=%v Size=%v)", s.ID, size)
-		start := time.Now()
-		img, err := img.Upload(ctx, buf, storage)
-		if err != nil {
-			return fmt.Errorf("upload %q (%s): %w", path, org.Disk, err)
-		}
-		ctx.cfg.logf("[Resizer] Upload done (StackID=%v Duration=%v)", s.ID, time.Since(start))
+			encoded := ctx.encodeReader(generate(src, dim), format)
+			img, err := img.Upload(uploadCtx, encoded, storage)
+			encoded.Close()
+
+			mux.Lock()
+			defer mux.Unlock()
+
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("upload %q (%s): %w", path, org.Disk, err)
+					cancel()
+				}
+				return
+			}
+
+			ctx.cfg.logf("[%s] Size done (StackID=%v Size=%v)", name, s.ID, size)
 
-		resizedImages = append(resizedImages, Image{
-			Image: img,
-			Size:  size,
-		})
+			resizedImages = append(resizedImages, Image{
+				Image:   img,
+				Size:    size,
+				Cropped: cropped,
+			})
+		}(size, dim)
 	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	ctx.cfg.logf("[%s] Sizes done (StackID=%v Duration=%v)", name, s.ID, time.Since(start))
 
 	sort.SliceStable(resizedImages, func(i, j int) bool {
-		return resizedImages[i].Width <= resizedImages[j].Width
+		if resizedImages[i].Width != resizedImages[j].Width {
+			return resizedImages[i].Width < resizedImages[j].Width
+		}
+		return resizedImages[i].Size < resizedImages[j].Size
 	})
 
 	if err := ctx.Update(func(s Stack) Stack {
@@ -242,7 +442,7 @@ L:
 		}
 
 		for _, resized := range resizedImages {
-			if resized.Size == img.Size {
+			if resized.Size == img.Size && resized.Format == img.Format {
 				// Replaced by resized image.
 				continue L
 			}
@@ -260,13 +460,17 @@ L:
 			return false
 		}
 
-		return out[i].Width <= out[j].Width
+		if out[i].Width == out[j].Width {
+			return out[i].Format < out[j].Format
+		}
+
+		return out[i].Width < out[j].Width
 	})
 
 	return out
 }
 
-func (r Resizer) path(orgPath, size, format string) string {
+func sizePath(orgPath, size, format string) string {
 	ext := filepath.Ext(orgPath)
 	pathWithoutExt := strings.TrimSuffix(orgPath, ext)
 
@@ -277,10 +481,292 @@ func (r Resizer) path(orgPath, size, format string) string {
 	return fmt.Sprintf("%s_%s%s", pathWithoutExt, size, ext)
 }
 
+// FormatConverter is a Processor that converts the images of a Stack into the
+// given image formats (e.g. "webp"). For each format, the original image and
+// each resized image is encoded using the Encoder of the ProcessorContext and
+// added to the Stack as an Image with the same Size and the Format set to the
+// target format. Images that already have the target format are skipped.
+//
+// An Encoder without a FormatEncoder for a format falls back to its default
+// encoder, so make sure that the formats are registered in the Encoder.
+//
+// FormatConverter should run after the Resizer in a ProcessingPipeline.
+type FormatConverter []string
+
+// Process runs the FormatConverter on the Stack in the given ProcessorContext.
+func (conv FormatConverter) Process(ctx *ProcessorContext) error {
+	return conv.convert(ctx, ctx.Encoder())
+}
+
+func (conv FormatConverter) convert(ctx *ProcessorContext, enc image.Encoder) error {
+	s := ctx.Stack()
+	storage := ctx.Storage()
+
+	var converted []Image
+	for _, img := range s.Images {
+		if img.Format != "" || img.Stale {
+			continue
+		}
+
+		stdimg, orgFormat, err := img.Download(ctx, storage)
+		if err != nil {
+			return fmt.Errorf("download image %q (%s): %w", img.Path, img.Disk, err)
+		}
+
+		for _, format := range conv {
+			// Images that already have the target format are not converted.
+			if format == orgFormat {
+				continue
+			}
+
+			path := conv.path(img.Path, format)
+			b := stdimg.Bounds()
+			convertedImage := media.NewImage(b.Dx(), b.Dy(), img.Name, img.Disk, path, 0)
+
+			ctx.cfg.logf("[FormatConverter] Upload converted image (StackID=%v Size=%v Format=%v)", s.ID, img.Size, format)
+			start := time.Now()
+			encoded := encodeReader(enc, stdimg, format)
+			f, err := convertedImage.File.Upload(ctx, encoded, storage)
+			encoded.Close()
+			if err != nil {
+				return fmt.Errorf("upload %q (%s): %w", path, img.Disk, err)
+			}
+			ctx.cfg.logf("[FormatConverter] Upload done (StackID=%v Duration=%v)", s.ID, time.Since(start))
+
+			convertedImage.File = f
+			converted = append(converted, Image{
+				Image:   convertedImage,
+				Size:    img.Size,
+				Format:  format,
+				Cropped: img.Cropped,
+			})
+		}
+	}
+
+	if err := ctx.Update(func(s Stack) Stack {
+		s.Images = appendOrReplaceResizedImage(s.Images, converted)
+		return s
+	}); err != nil {
+		return fmt.Errorf("update Stack: %w", err)
+	}
+
+	return nil
+}
+
+func (conv FormatConverter) path(orgPath, format string) string {
+	pathWithoutExt := strings.TrimSuffix(orgPath, filepath.Ext(orgPath))
+	return fmt.Sprintf("%s.%s", pathWithoutExt, format)
+}
+
+// AVIFConverter is a Processor that adds AVIF variants of the original image
+// and of each resized image to a Stack. The AVIF images are added as Images
+// with the Format "avif"; existing Images are not replaced.
+//
+// AVIF encoding is not supported out of the box: nice-cms does not ship an
+// AVIF encoder, so Encoder must be provided by the application (e.g. a binding
+// to libavif). Process returns ErrNoEncoder if Encoder is nil, so the
+// AVIFConverter must not be added to a ProcessingPipeline without an Encoder.
+type AVIFConverter struct {
+	Encoder image.FormatEncoder
+}
+
+// Process runs the AVIFConverter on the Stack in the given ProcessorContext.
+func (conv AVIFConverter) Process(ctx *ProcessorContext) error {
+	if conv.Encoder == nil {
+		return fmt.Errorf("AVIF: %w", ErrNoEncoder)
+	}
+	enc := image.NewEncoder(image.WithFormat("avif", conv.Encoder))
+	return FormatConverter{"avif"}.convert(ctx, enc)
+}
+
+// Placeholder is a Processor that computes the BlurHash of the original image
+// of a Stack and stores it in the Placeholder field of the Stack. XComponents
+// and YComponents configure the detail of the hash and default to 4 and 3.
+type Placeholder struct {
+	XComponents int
+	YComponents int
+}
+
+// Process runs the Placeholder Processor on the given ProcessorContext.
+func (p Placeholder) Process(ctx *ProcessorContext) error {
+	s := ctx.Stack()
+	org := s.Original()
+
+	original, _, err := org.Download(ctx, ctx.Storage())
+	if err != nil {
+		return fmt.Errorf("download original image %q (%s): %w", org.Path, org.Disk, err)
+	}
+
+	x, y := p.XComponents, p.YComponents
+	if x == 0 {
+		x = 4
+	}
+	if y == 0 {
+		y = 3
+	}
+
+	ctx.cfg.logf("[Placeholder] Compute BlurHash (StackID=%v Components=%dx%d)", s.ID, x, y)
+	hash, err := blurhash.Encode(original, x, y)
+	if err != nil {
+		return fmt.Errorf("compute BlurHash: %w", err)
+	}
+
+	if err := ctx.Update(func(s Stack) Stack {
+		s.Placeholder = hash
+		return s
+	}); err != nil {
+		return fmt.Errorf("update Stack: %w", err)
+	}
+
+	return nil
+}
+
+// Palette is a Processor that extracts the dominant colors of the original
+// image of a Stack and stores them in the Palette field of the Stack. Colors is
+// the maximum number of colors and defaults to 5. The PostProcessor refreshes
+// the Theme of a Gallery from the palettes of its Stacks.
+type Palette struct {
+	Colors int
+}
+
+// Process runs the Palette Processor on the given ProcessorContext.
+func (p Palette) Process(ctx *ProcessorContext) error {
+	s := ctx.Stack()
+	org := s.Original()
+
+	original, _, err := org.Download(ctx, ctx.Storage())
+	if err != nil {
+		return fmt.Errorf("download original image %q (%s): %w", org.Path, org.Disk, err)
+	}
+
+	n := p.Colors
+	if n == 0 {
+		n = 5
+	}
+
+	ctx.cfg.logf("[Palette] Extract palette (StackID=%v Colors=%d)", s.ID, n)
+	colors, err := palette.Extract(original, n)
+	if err != nil {
+		return fmt.Errorf("extract palette: %w", err)
+	}
+
+	hex := make([]string, len(colors))
+	for i, c := range colors {
+		hex[i] = palette.Hex(c)
+	}
+
+	if err := ctx.Update(func(s Stack) Stack {
+		s.Palette = hex
+		return s
+	}); err != nil {
+		return fmt.Errorf("update Stack: %w", err)
+	}
+
+	return nil
+}
+
+// EXIF is a Processor that extracts the EXIF metadata of the original image
+// of a Stack and strips the EXIF metadata from all Images of the Stack. The
+// camera data of the original image is stored in the Metadata of the Stack.
+// Images with an EXIF orientation are rotated accordingly and re-encoded;
+// otherwise, the metadata is removed without re-encoding the image.
+//
+// GPS coordinates may reveal sensitive locations and are therefore only stored
+// in the Metadata of the Stack if KeepGPS is true. They are always stripped
+// from the stored images.
+//
+// EXIF should run first in a ProcessingPipeline, so that resized and converted
+// images are generated from the rotated original image.
+type EXIF struct {
+	KeepGPS bool
+}
+
+// Process runs the EXIF Processor on the given ProcessorContext.
+func (p EXIF) Process(ctx *ProcessorContext) error {
+	stack := ctx.Stack()
+	storage := ctx.Storage()
+
+	var meta *Metadata
+	for i, img := range stack.Images {
+		b, err := img.File.Download(ctx, storage)
+		if err != nil {
+			return fmt.Errorf("download image %q (%s): %w", img.Path, img.Disk, err)
+		}
+
+		m, err := exif.Decode(bytes.NewReader(b))
+		if errors.Is(err, exif.ErrNoEXIF) || errors.Is(err, exif.ErrUnsupportedFormat) {
+			continue
+		}
+		if err != nil {
+			// Malformed metadata is stripped anyway.
+			ctx.cfg.logf("[EXIF] Invalid EXIF metadata (StackID=%v Path=%v): %v", stack.ID, img.Path, err)
+		}
+
+		if img.Original && err == nil {
+			meta = p.metadata(m)
+		}
+
+		ctx.cfg.logf("[EXIF] Strip EXIF metadata (StackID=%v Disk=%v Path=%v Orientation=%v)", stack.ID, img.Disk, img.Path, m.Orientation)
+		start := time.Now()
+
+		var stripped io.ReadCloser
+		if m.Orientation > 1 {
+			decoded, format, err := stdimage.Decode(bytes.NewReader(b))
+			if err != nil {
+				return fmt.Errorf("decode image %q (%s): %w", img.Path, img.Disk, err)
+			}
+			stripped = ctx.encodeReader(exif.Orient(decoded, m.Orientation), format)
+		} else {
+			var buf bytes.Buffer
+			if err := exif.Strip(&buf, bytes.NewReader(b)); err != nil {
+				return fmt.Errorf("strip EXIF metadata of %q (%s): %w", img.Path, img.Disk, err)
+			}
+			stripped = io.NopCloser(&buf)
+		}
+
+		replaced, err := img.Replace(ctx, stripped, storage)
+		stripped.Close()
+		if err != nil {
+			return fmt.Errorf("replace image %q (%s): %w", img.Path, img.Disk, err)
+		}
+		ctx.cfg.logf("[EXIF] EXIF metadata stripped (StackID=%v Disk=%v Path=%v Duration=%v)", stack.ID, img.Disk, img.Path, time.Since(start))
+
+		stack.Images[i].Image = replaced
+	}
+
+	if err := ctx.Update(func(Stack) Stack {
+		stack.Metadata = meta
+		return stack
+	}); err != nil {
+		return fmt.Errorf("update Stack: %w", err)
+	}
+
+	return nil
+}
+
+func (p EXIF) metadata(m exif.Metadata) *Metadata {
+	meta := Metadata{
+		Make:         m.Make,
+		Model:        m.Model,
+		LensModel:    m.LensModel,
+		TakenAt:      m.DateTime,
+		ExposureTime: m.ExposureTime,
+		FNumber:      m.FNumber,
+		ISO:          m.ISO,
+		FocalLength:  m.FocalLength,
+	}
+	if p.KeepGPS {
+		meta.GPS = m.GPS
+	}
+	return &meta
+}
+
 // PNGCompressor is a Processor that compresses images using a png.Encoder with
 // a png.CompressionLevel to compress the given image.
 //
-// PNGCompressor compresses each Image in a Stack in parallel.
+// PNGCompressor compresses each Image in a Stack in parallel. Stale Images and
+// Images that were converted into another format by a FormatConverter are
+// skipped.
 type PNGCompressor image.PNGCompressor
 
 // Process runs the PNGCompressor on the given ProcessorContext.
@@ -293,6 +779,10 @@ func (comp PNGCompressor) Process(ctx *ProcessorContext) error {
 
 	var wg sync.WaitGroup
 	for i, img := range stack.Images {
+		if img.Format != "" || img.Stale {
+			continue
+		}
+
 		wg.Add(1)
 		go func(img Image, i int) {
 			defer wg.Done()
@@ -312,15 +802,11 @@ func (comp PNGCompressor) Process(ctx *ProcessorContext) error {
 			}
 			ctx.cfg.logf("[PNGCompressor]: Image compressed (StackID=%v Disk=%v Path=%v Duration=%v)", stack.ID, img.Disk, img.Path, time.Since(start))
 
-			var buf bytes.Buffer
-			if err := ctx.Encode(&buf, compressed, format); err != nil {
-				fail(fmt.Errorf("encode %q image: %w", format, err))
-				return
-			}
-
 			ctx.cfg.logf("[PNGCompressor]: Replace storage image (StackID=%v Disk=%v Path=%v)", stack.ID, img.Disk, img.Path)
 			start = time.Now()
-			replaced, err := img.Replace(ctx, &buf, storage)
+			encoded := ctx.encodeReader(compressed, format)
+			replaced, err := img.Replace(ctx, encoded, storage)
+			encoded.Close()
 			if err != nil {
 				fail(fmt.Errorf("replace image %q (%s): %w", img.Path, img.Disk, err))
 				return
@@ -373,12 +859,23 @@ func (svc *PostProcessor) Process(ctx context.Context, stack Stack, pipe Process
 	return pipe.Process(ctx, stack, svc.encoder, svc.storage, opts...)
 }
 
+// DefaultProcessorRetryDelay is the default delay before the first retry of a
+// failed processing job. See ProcessorRetries.
+const DefaultProcessorRetryDelay = 5 * time.Second
+
 // PostProcessorOption is an option for PostProcessor.Run.
 type PostProcessorOption func(*postProcessorConfig)
 
 type postProcessorConfig struct {
 	logger      Printer
 	workers     int
+	concurrency int
+	retries     int
+	retryDelay  time.Duration
+	queueSize   int
+	overflow    OverflowPolicy
+	jobs        JobStore
+	inst        Instrumentation
 	onProcessed []func(Stack, *Gallery)
 }
 
@@ -398,6 +895,46 @@ func ProcessorWorkers(workers int) PostProcessorOption {
 	}
 }
 
+// ProcessorRetries returns a PostProcessorOption that retries failed processing
+// jobs up to n times before the processing of the Stack is marked as failed
+// and recorded as a DeadLetter. The Stack stays pending between the retries.
+// Default is 0 (no retries).
+func ProcessorRetries(n int) PostProcessorOption {
+	return func(cfg *postProcessorConfig) {
+		cfg.retries = n
+	}
+}
+
+// ProcessorRetryDelay returns a PostProcessorOption that sets the delay before
+// the first retry of a failed processing job. The n-th retry waits n times the
+// delay. Default is DefaultProcessorRetryDelay.
+func ProcessorRetryDelay(d time.Duration) PostProcessorOption {
+	return func(cfg *postProcessorConfig) {
+		cfg.retryDelay = d
+	}
+}
+
+// ProcessorJobStore returns a PostProcessorOption that persists the processing
+// jobs in the given JobStore. Jobs are saved when they are queued and deleted
+// when they are finished. When the PostProcessor is started, it resumes the
+// unfinished jobs of the JobStore, so that jobs are not lost if the process
+// crashes or is stopped while Stacks are processed. Without a JobStore, jobs
+// are only kept in memory.
+func ProcessorJobStore(store JobStore) PostProcessorOption {
+	return func(cfg *postProcessorConfig) {
+		cfg.jobs = store
+	}
+}
+
+// ProcessorConcurrency returns a PostProcessorOption that limits the number of
+// sizes that are generated and uploaded concurrently for a single Stack. See
+// WithConcurrency.
+func ProcessorConcurrency(n int) PostProcessorOption {
+	return func(cfg *postProcessorConfig) {
+		cfg.concurrency = n
+	}
+}
+
 // OnProcessed returns a PostProcessorOption that registers fn as a callback
 // function that is called when a Stack has been processed.
 func OnProcessed(fn func(Stack, *Gallery)) PostProcessorOption {
@@ -406,42 +943,142 @@ func OnProcessed(fn func(Stack, *Gallery)) PostProcessorOption {
 	}
 }
 
+// PipelineSelector selects the ProcessingPipeline for the Stacks of the
+// Gallery with the given UUID and name. If a PipelineSelector returns an empty
+// ProcessingPipeline, the Stack is not processed.
+type PipelineSelector func(galleryID uuid.UUID, name string) ProcessingPipeline
+
+// Pipelines is a registry of ProcessingPipelines for specific Galleries.
+// Its Select method can be passed to PostProcessor.RunWithSelector:
+//
+//	pipes := gallery.Pipelines{
+//		Default: gallery.ProcessingPipeline{...},
+//		ByName: map[string]gallery.ProcessingPipeline{
+//			"hero": {...},
+//			"avatars": {...},
+//		},
+//	}
+//	errs, err := svc.RunWithSelector(ctx, bus, pipes.Select)
+type Pipelines struct {
+	// Default is used for Galleries that have no registered pipeline.
+	Default ProcessingPipeline
+
+	// ByID maps Gallery UUIDs to ProcessingPipelines. Pipelines registered by
+	// UUID take precedence over pipelines registered by name.
+	ByID map[uuid.UUID]ProcessingPipeline
+
+	// ByName maps Gallery names to ProcessingPipelines.
+	ByName map[string]ProcessingPipeline
+}
+
+// Select returns the ProcessingPipeline for the Gallery with the given UUID
+// and name.
+func (p Pipelines) Select(galleryID uuid.UUID, name string) ProcessingPipeline {
+	if pipe, ok := p.ByID[galleryID]; ok {
+		return pipe
+	}
+	if pipe, ok := p.ByName[name]; ok {
+		return pipe
+	}
+	return p.Default
+}
+
 // Run starts the PostProcessor in the background and returns a channel of
 // asynchronous processing errors. PostProcessor runs until ctx is canceled.
+// Every Stack is processed by the same ProcessingPipeline. Use RunWithSelector
+// to process the Stacks of different Galleries with different pipelines.
 func (svc *PostProcessor) Run(
 	ctx context.Context,
 	bus event.Bus,
 	pipe ProcessingPipeline,
 	opts ...PostProcessorOption,
+) (<-chan error, error) {
+	return svc.RunWithSelector(ctx, bus, func(uuid.UUID, string) ProcessingPipeline {
+		return pipe
+	}, opts...)
+}
+
+// RunWithSelector starts the PostProcessor in the background and returns a
+// channel of asynchronous processing errors. For every uploaded or replaced
+// image, every changed focal point or crop and every requested reprocessing
+// (see ReprocessGallery), the ProcessingPipeline is selected by calling
+// selectPipeline with the UUID and name of the Gallery. PostProcessor runs
+// until ctx is canceled.
+func (svc *PostProcessor) RunWithSelector(
+	ctx context.Context,
+	bus event.Bus,
+	selectPipeline PipelineSelector,
+	opts ...PostProcessorOption,
 ) (<-chan error, error) {
 	cfg := newProcessorConfig(opts...)
 
 	cfg.log("Logging enabled.")
 
-	events, errs, err := bus.Subscribe(ctx, ImageUploaded, ImageReplaced)
+	events, errs, err := bus.Subscribe(
+		ctx,
+		ImageUploaded,
+		ImageReplaced,
+		StackFocalPointSet,
+		StackCropped,
+		StackReprocessingRequested,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("subscribe to %q event: %w", ImageUploaded, err)
 	}
 
-	queue := make(chan processorJob)
+	var resumed []ProcessingJob
+	if cfg.jobs != nil {
+		if resumed, err = cfg.jobs.Jobs(ctx); err != nil {
+			return nil, fmt.Errorf("fetch unfinished processing jobs: %w", err)
+		}
+	}
+
+	// The workers stop when the subscription ends.
+	ctx, stop := context.WithCancel(ctx)
+
+	queue := newJobQueue(cfg)
 	out := make(chan error)
 
-	go svc.work(ctx, cfg, queue, pipe, out)
-	go svc.accept(ctx, queue, events, errs, out)
+	go svc.work(ctx, cfg, queue, selectPipeline, out)
+	go svc.accept(ctx, stop, cfg, queue, events, errs, out)
+
+	if cfg.jobs != nil {
+		go queue.resume(ctx)
+	}
+
+	if len(resumed) > 0 {
+		cfg.logf("Resuming %d unfinished processing job(s).", len(resumed))
+		for range resumed {
+			cfg.jobQueued()
+		}
+		queue.signal()
+	}
 
 	return out, nil
 }
 
-type processorJob struct {
-	galleryID uuid.UUID
-	stackID   uuid.UUID
+// ReprocessGallery requests the processing of all Stacks of the given Gallery,
+// e.g. after new sizes have been added to its ProcessingPipeline. The Stacks
+// are processed asynchronously by the running PostProcessor (see Run), which
+// generates all Images of the Stacks again. ReprocessGallery returns the
+// Stacks that will be processed.
+func (svc *PostProcessor) ReprocessGallery(ctx context.Context, galleryID uuid.UUID) ([]Stack, error) {
+	var stacks []Stack
+	if err := svc.galleries.Use(ctx, galleryID, func(g *Gallery) error {
+		var err error
+		stacks, err = g.ReprocessAll()
+		return err
+	}); err != nil {
+		return stacks, fmt.Errorf("reprocess Gallery %q: %w", galleryID, err)
+	}
+	return stacks, nil
 }
 
 func (svc *PostProcessor) work(
 	ctx context.Context,
 	cfg postProcessorConfig,
-	queue chan processorJob,
-	pipe ProcessingPipeline,
+	queue *jobQueue,
+	selectPipeline PipelineSelector,
 	out chan<- error,
 ) {
 	defer close(out)
@@ -461,27 +1098,93 @@ func (svc *PostProcessor) work(
 	for i := 0; i < cfg.workers; i++ {
 		go func() {
 			defer wg.Done()
-			for job := range queue {
-				cfg.logf("Received processing job (GalleryID=%v StackID=%v)", job.galleryID, job.stackID)
+			for {
+				var job ProcessingJob
+				select {
+				case <-ctx.Done():
+					return
+				case job = <-queue.jobs:
+				}
+
+				cfg.logf("Received processing job (GalleryID=%v StackID=%v)", job.GalleryID, job.StackID)
+				cfg.jobStarted()
+				jobStart := time.Now()
 
-				g, err := svc.galleries.Fetch(ctx, job.galleryID)
+				finish := func(err error) {
+					cfg.jobProcessed(jobStart, err)
+					cfg.deleteJob(ctx, job.ID)
+					queue.done(job.ID)
+				}
+
+				g, err := svc.galleries.Fetch(ctx, job.GalleryID)
 				if err != nil {
-					fail(fmt.Errorf("fetch Gallery %q: %w", job.galleryID, err))
+					err = fmt.Errorf("fetch Gallery %q: %w", job.GalleryID, err)
+					finish(err)
+					fail(err)
 					continue
 				}
 
-				stack, err := g.Stack(job.stackID)
+				stack, err := g.Stack(job.StackID)
 				if err != nil {
-					fail(fmt.Errorf("get Stack %q: %w", job.stackID, err))
+					err = fmt.Errorf("get Stack %q: %w", job.StackID, err)
+					finish(err)
+					fail(err)
+					continue
+				}
+
+				pipe := selectPipeline(g.ID, g.Implementation.Name)
+				if len(pipe) == 0 {
+					cfg.logf("No pipeline for gallery. Skipping stack. (GalleryID=%v GalleryName=%v StackID=%v)", g.ID, g.Implementation.Name, stack.ID)
+					cfg.deleteJob(ctx, job.ID)
+					queue.done(job.ID)
+					continue
+				}
+
+				if err := svc.galleries.Use(ctx, g.ID, func(g *Gallery) error {
+					return g.StartProcessing(stack.ID)
+				}); err != nil {
+					err = fmt.Errorf("start processing: %w [id=%v]", err, stack.ID)
+					finish(err)
+					fail(err)
 					continue
 				}
 
 				cfg.logf("Processing stack (ID=%v)", stack.ID)
 				start := time.Now()
 
-				processed, err := svc.Process(ctx, stack, pipe, WithDebugger(cfg.logger))
+				processed, err := svc.Process(
+					ctx, stack, pipe,
+					WithDebugger(cfg.logger),
+					WithConcurrency(cfg.concurrency),
+					WithInstrumentation(cfg.inst),
+				)
 				if err != nil {
-					fail(fmt.Errorf("ProcessingPipeline failed: %w", err))
+					if ctx.Err() != nil {
+						// The job stays in the JobStore and is resumed when
+						// the PostProcessor is restarted.
+						queue.done(job.ID)
+						continue
+					}
+
+					if job.Attempts < cfg.retries {
+						job.Attempts++
+						delay := cfg.retryDelay * time.Duration(job.Attempts)
+						cfg.logf("Processing failed. Retrying in %v. (StackID=%v Retry=%d/%d): %v", delay, stack.ID, job.Attempts, cfg.retries, err)
+						cfg.jobProcessed(jobStart, err)
+						cfg.saveJob(ctx, job)
+						cfg.jobQueued()
+						go queue.retry(ctx, job, delay)
+						continue
+					}
+
+					if err := svc.galleries.Use(ctx, g.ID, func(g *Gallery) error {
+						return g.FailProcessingAfter(stack.ID, err, job.Attempts+1)
+					}); err != nil {
+						cfg.logf("Failed to mark processing as failed (StackID=%v): %v", stack.ID, err)
+					}
+					err = fmt.Errorf("ProcessingPipeline failed after %d attempt(s): %w", job.Attempts+1, err)
+					finish(err)
+					fail(err)
 					continue
 				}
 
@@ -489,15 +1192,23 @@ func (svc *PostProcessor) work(
 
 				if err := svc.galleries.Use(ctx, g.ID, func(gal *Gallery) error {
 					g = gal
-					if err := g.Update(processed.ID, func(Stack) Stack { return processed }); err != nil {
-						return fmt.Errorf("update stack: %w [id=%v]", err, processed.ID)
+					if err := g.FinishProcessing(processed); err != nil {
+						return fmt.Errorf("finish processing: %w [id=%v]", err, processed.ID)
+					}
+					if err := g.RefreshTheme(); err != nil {
+						return fmt.Errorf("refresh theme: %w", err)
 					}
-					return nil
+					processed, err = g.Stack(processed.ID)
+					return err
 				}); err != nil {
-					fail(fmt.Errorf("update gallery: %w", err))
+					err = fmt.Errorf("update gallery: %w", err)
+					finish(err)
+					fail(err)
 					continue
 				}
 
+				finish(nil)
+
 				for _, fn := range cfg.onProcessed {
 					fn(processed, g)
 				}
@@ -511,12 +1222,14 @@ func (svc *PostProcessor) work(
 // listen for uploaded images and enqueue the processing jobs
 func (svc *PostProcessor) accept(
 	ctx context.Context,
-	queue chan processorJob,
+	stop context.CancelFunc,
+	cfg postProcessorConfig,
+	queue *jobQueue,
 	events <-chan event.Event,
 	errs <-chan error,
 	out chan<- error,
 ) {
-	defer close(queue)
+	defer stop()
 
 	fail := func(err error) {
 		select {
@@ -529,12 +1242,30 @@ func (svc *PostProcessor) accept(
 		ctx,
 		func(evt event.Event) {
 			id, _, _ := evt.Aggregate()
+
+			var stackID uuid.UUID
 			switch data := evt.Data().(type) {
 			case ImageUploadedData:
-				go enqueue(ctx, queue, id, data.Stack.ID)
+				stackID = data.Stack.ID
 			case ImageReplacedData:
-				go enqueue(ctx, queue, id, data.Stack.ID)
+				stackID = data.Stack.ID
+			case StackFocalPointSetData:
+				stackID = data.StackID
+			case StackCroppedData:
+				stackID = data.StackID
+			case StackReprocessingRequestedData:
+				stackID = data.StackID
+			default:
+				return
+			}
+
+			job := ProcessingJob{
+				ID:        uuid.New(),
+				GalleryID: id,
+				StackID:   stackID,
+				QueuedAt:  time.Now(),
 			}
+			queue.offer(ctx, job)
 		},
 		fail,
 		events, errs,
@@ -549,16 +1280,63 @@ func newProcessorConfig(opts ...PostProcessorOption) postProcessorConfig {
 	if cfg.workers < 1 {
 		cfg.workers = 1
 	}
+	if cfg.retries < 0 {
+		cfg.retries = 0
+	}
+	if cfg.retryDelay <= 0 {
+		cfg.retryDelay = DefaultProcessorRetryDelay
+	}
+	if cfg.queueSize < 1 {
+		cfg.queueSize = DefaultProcessorQueueSize
+	}
+	if cfg.jobs == nil && cfg.overflow == OverflowPersist {
+		cfg.jobs = MemoryJobStore()
+	}
 	return cfg
 }
 
-func enqueue(ctx context.Context, queue chan<- processorJob, galleryID, stackID uuid.UUID) {
-	select {
-	case <-ctx.Done():
-	case queue <- processorJob{
-		galleryID: galleryID,
-		stackID:   stackID,
-	}:
+// saveJob saves the job in the JobStore and reports whether it was saved.
+func (cfg postProcessorConfig) saveJob(ctx context.Context, job ProcessingJob) bool {
+	if cfg.jobs == nil {
+		return false
+	}
+	if err := cfg.jobs.Save(ctx, job); err != nil {
+		cfg.logf("Failed to save processing job (JobID=%v StackID=%v): %v", job.ID, job.StackID, err)
+		return false
+	}
+	return true
+}
+
+func (cfg postProcessorConfig) deleteJob(ctx context.Context, id uuid.UUID) {
+	if cfg.jobs == nil {
+		return
+	}
+	if err := cfg.jobs.Delete(ctx, id); err != nil {
+		cfg.logf("Failed to delete processing job (JobID=%v): %v", id, err)
+	}
+}
+
+func (cfg postProcessorConfig) jobQueued() {
+	if cfg.inst != nil {
+		cfg.inst.JobQueued()
+	}
+}
+
+func (cfg postProcessorConfig) jobDropped() {
+	if cfg.inst != nil {
+		cfg.inst.JobDropped()
+	}
+}
+
+func (cfg postProcessorConfig) jobStarted() {
+	if cfg.inst != nil {
+		cfg.inst.JobStarted()
+	}
+}
+
+func (cfg postProcessorConfig) jobProcessed(start time.Time, err error) {
+	if cfg.inst != nil {
+		cfg.inst.JobProcessed(time.Since(start), err)
 	}
 }
 
@@ -580,6 +1358,13 @@ func (cfg processorConfig) log(v ...any) {
 	}
 }
 
+func (cfg processorConfig) sizeConcurrency() int {
+	if cfg.concurrency <= 0 {
+		return runtime.NumCPU()
+	}
+	return cfg.concurrency
+}
+
 func (cfg processorConfig) logf(format string, v ...any) {
 	if cfg.logger != nil {
 		cfg.logger.Print(fmt.Sprintf(format, v...))
@@ -1,12 +1,16 @@
 package gallery_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	stdimage "image"
 	"image/color"
+	"io"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -18,6 +22,7 @@ import (
 	"github.com/modernice/nice-cms/internal/imggen"
 	"github.com/modernice/nice-cms/media"
 	"github.com/modernice/nice-cms/media/image"
+	"github.com/modernice/nice-cms/media/image/exif"
 	"github.com/modernice/nice-cms/media/image/gallery"
 )
 
@@ -131,6 +136,443 @@ func TestProcessingPipeline_Process(t *testing.T) {
 	}
 }
 
+func TestFormatConverter_Process(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	enc := image.NewEncoder()
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+
+	stack, err := g.Upload(context.Background(), storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	pipe := gallery.ProcessingPipeline{
+		gallery.Resizer{"thumb": {Width: 240}},
+		gallery.FormatConverter{"webp"},
+	}
+
+	processed, err := pipe.Process(context.Background(), stack, enc, storage)
+	if err != nil {
+		t.Fatalf("ProcessingPipeline failed to process Stack: %v", err)
+	}
+
+	if len(processed.Images) != 4 {
+		t.Fatalf("processed Stack should contain 4 images; contains %d", len(processed.Images))
+	}
+
+	if !processed.Images[0].Original || processed.Images[0].Format != "" {
+		t.Fatalf("first image of processed Stack should be the original")
+	}
+
+	pathWithoutExt := strings.TrimSuffix(examplePath, filepath.Ext(examplePath))
+
+	tests := []struct {
+		size      string
+		wantWidth int
+		wantPath  string
+	}{
+		{
+			size:      "",
+			wantWidth: 800,
+			wantPath:  pathWithoutExt + ".webp",
+		},
+		{
+			size:      "thumb",
+			wantWidth: 240,
+			wantPath:  pathWithoutExt + "_thumb.webp",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			var found bool
+			var foundImage gallery.Image
+			for _, img := range processed.Images {
+				if img.Size == tt.size && img.Format == "webp" {
+					found = true
+					foundImage = img
+					break
+				}
+			}
+
+			if !found {
+				t.Fatalf("Stack should contain %q image with %q size", "webp", tt.size)
+			}
+
+			if foundImage.Original {
+				t.Fatalf("converted image should not be the original")
+			}
+
+			if foundImage.Width != tt.wantWidth {
+				t.Fatalf("converted image should have width of %d; has %d", tt.wantWidth, foundImage.Width)
+			}
+
+			if foundImage.Path != tt.wantPath {
+				t.Fatalf("Image path should be %q; is %q", tt.wantPath, foundImage.Path)
+			}
+
+			b, err := foundImage.File.Download(context.Background(), storage)
+			if err != nil {
+				t.Fatalf("download converted image: %v", err)
+			}
+
+			if len(b) == 0 || foundImage.Filesize != len(b) {
+				t.Fatalf("Filesize of converted image should be %d; is %d", len(b), foundImage.Filesize)
+			}
+
+			if string(b[8:12]) != "WEBP" {
+				t.Fatalf("converted image should be a WebP image")
+			}
+		})
+	}
+
+	reprocessed, err := pipe.Process(context.Background(), processed, enc, storage)
+	if err != nil {
+		t.Fatalf("ProcessingPipeline failed to process Stack: %v", err)
+	}
+
+	if len(reprocessed.Images) != 4 {
+		t.Fatalf("reprocessed Stack should replace the converted images; contains %d images", len(reprocessed.Images))
+	}
+}
+
+func TestAVIFConverter_Process(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	enc := image.NewEncoder()
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+
+	stack, err := g.Upload(context.Background(), storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	avifEncoder := image.Func(func(w io.Writer, img stdimage.Image) error {
+		_, err := fmt.Fprintf(w, "avif %dx%d", img.Bounds().Dx(), img.Bounds().Dy())
+		return err
+	})
+
+	pipe := gallery.ProcessingPipeline{
+		gallery.Resizer{"thumb": {Width: 240}},
+		gallery.AVIFConverter{Encoder: avifEncoder},
+	}
+
+	processed, err := pipe.Process(context.Background(), stack, enc, storage)
+	if err != nil {
+		t.Fatalf("ProcessingPipeline failed to process Stack: %v", err)
+	}
+
+	if len(processed.Images) != 4 {
+		t.Fatalf("processed Stack should contain 4 images; contains %d", len(processed.Images))
+	}
+
+	var avifs int
+	for _, img := range processed.Images {
+		if img.Format != "avif" {
+			if filepath.Ext(img.Path) != ".png" {
+				t.Fatalf("non-AVIF images should not be replaced; got %q", img.Path)
+			}
+			continue
+		}
+		avifs++
+
+		if filepath.Ext(img.Path) != ".avif" {
+			t.Fatalf("AVIF image should have %q extension; has path %q", ".avif", img.Path)
+		}
+
+		b, err := img.File.Download(context.Background(), storage)
+		if err != nil {
+			t.Fatalf("download AVIF image: %v", err)
+		}
+
+		if want := fmt.Sprintf("avif %dx%d", img.Width, img.Height); string(b) != want {
+			t.Fatalf("AVIF image should have contents %q; has %q", want, b)
+		}
+	}
+
+	if avifs != 2 {
+		t.Fatalf("processed Stack should contain 2 AVIF images; contains %d", avifs)
+	}
+}
+
+func TestAVIFConverter_Process_noEncoder(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	pipe := gallery.ProcessingPipeline{gallery.AVIFConverter{}}
+
+	_, err := pipe.Process(context.Background(), gallery.Stack{ID: uuid.New()}, image.NewEncoder(), storage)
+	if !errors.Is(err, gallery.ErrNoEncoder) {
+		t.Fatalf("Process should fail with %q; got %q", gallery.ErrNoEncoder, err)
+	}
+}
+
+func TestProcessingPipeline_Process_staleImages(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	enc := image.NewEncoder()
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+
+	stack, err := g.Upload(context.Background(), storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	stack.Images = append(stack.Images,
+		gallery.Image{
+			Image: media.NewImage(240, 180, exampleName, exampleDisk, "/foo_thumb.png", 0),
+			Size:  "thumb",
+			Stale: true,
+		},
+		gallery.Image{
+			Image: media.NewImage(1920, 1440, exampleName, exampleDisk, "/foo_large.png", 0),
+			Size:  "large",
+			Stale: true,
+		},
+	)
+
+	pipe := gallery.ProcessingPipeline{
+		gallery.Resizer{"thumb": {Width: 240}},
+	}
+
+	processed, err := pipe.Process(context.Background(), stack, enc, storage)
+	if err != nil {
+		t.Fatalf("ProcessingPipeline failed to process Stack: %v", err)
+	}
+
+	if processed.Stale() {
+		t.Fatalf("processed Stack should not contain stale images")
+	}
+
+	if sizes := processed.Sizes(); !reflect.DeepEqual(sizes, []string{"", "thumb"}) {
+		t.Fatalf("processed Stack should have sizes %v; has %v", []string{"", "thumb"}, sizes)
+	}
+}
+
+func TestCropper_Process(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	enc := image.NewEncoder()
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	stack, err := g.Upload(ctx, storage, newHalvedImage(t, enc), exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	pipe := gallery.ProcessingPipeline{
+		gallery.Cropper{"tall": {Width: 50, Height: 100}},
+	}
+
+	red, blue := color.NRGBA{255, 0, 0, 255}, color.NRGBA{0, 0, 255, 255}
+
+	process := func(want color.NRGBA) {
+		processed, err := pipe.Process(ctx, stack, enc, storage)
+		if err != nil {
+			t.Fatalf("ProcessingPipeline failed to process Stack: %v", err)
+		}
+
+		var cropped gallery.Image
+		for _, img := range processed.Images {
+			if img.Size == "tall" {
+				cropped = img
+			}
+		}
+		if !cropped.Cropped {
+			t.Fatalf("Stack should contain a cropped %q Image; has %v", "tall", processed.Images)
+		}
+
+		downloaded, _, err := cropped.Download(ctx, storage)
+		if err != nil {
+			t.Fatalf("download cropped image: %v", err)
+		}
+		if b := downloaded.Bounds(); b.Dx() != 50 || b.Dy() != 100 {
+			t.Fatalf("cropped image should be 50x100; is %dx%d", b.Dx(), b.Dy())
+		}
+		if got := color.NRGBAModel.Convert(downloaded.At(25, 50)); got != want {
+			t.Fatalf("cropped image should be %v; is %v", want, got)
+		}
+
+		if err := g.Update(stack.ID, func(gallery.Stack) gallery.Stack { return processed }); err != nil {
+			t.Fatalf("update Stack: %v", err)
+		}
+	}
+
+	stack, err = g.SetFocalPoint(stack.ID, image.FocalPoint{X: 90, Y: 50})
+	if err != nil {
+		t.Fatalf("SetFocalPoint failed with %q", err)
+	}
+	process(blue)
+
+	stack, err = g.SetFocalPoint(stack.ID, image.FocalPoint{X: 10, Y: 50})
+	if err != nil {
+		t.Fatalf("SetFocalPoint failed with %q", err)
+	}
+	if !stack.Stale() {
+		t.Fatalf("cropped Images should be stale after changing the focal point")
+	}
+	process(red)
+
+	// The focal point lies outside of the blue half, so the images are
+	// cropped at the closest edge of the crop.
+	stack, err = g.CropStack(stack.ID, image.Area{X: 50, Y: 0, Width: 50, Height: 100})
+	if err != nil {
+		t.Fatalf("CropStack failed with %q", err)
+	}
+	process(blue)
+}
+
+func TestResizer_Process_crop(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	enc := image.NewEncoder()
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	stack, err := g.Upload(ctx, storage, newHalvedImage(t, enc), exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	stack, err = g.CropStack(stack.ID, image.Area{X: 50, Y: 0, Width: 50, Height: 100})
+	if err != nil {
+		t.Fatalf("CropStack failed with %q", err)
+	}
+
+	pipe := gallery.ProcessingPipeline{
+		gallery.Resizer{"small": {Width: 100}},
+	}
+
+	processed, err := pipe.Process(ctx, stack, enc, storage)
+	if err != nil {
+		t.Fatalf("ProcessingPipeline failed to process Stack: %v", err)
+	}
+
+	if org := processed.Original(); org.Width != 800 || org.Height != 400 {
+		t.Fatalf("original Image should not be cropped; is %dx%d", org.Width, org.Height)
+	}
+
+	var small gallery.Image
+	for _, img := range processed.Images {
+		if img.Size == "small" {
+			small = img
+		}
+	}
+
+	downloaded, _, err := small.Download(ctx, storage)
+	if err != nil {
+		t.Fatalf("download resized image: %v", err)
+	}
+	if b := downloaded.Bounds(); b.Dx() != 100 || b.Dy() != 100 {
+		t.Fatalf("resized image should be 100x100; is %dx%d", b.Dx(), b.Dy())
+	}
+	if got := color.NRGBAModel.Convert(downloaded.At(0, 0)); got != (color.NRGBA{0, 0, 255, 255}) {
+		t.Fatalf("resized image should be generated from the cropped area; pixel is %v", got)
+	}
+}
+
+func TestResizer_Process_concurrency(t *testing.T) {
+	ctx := context.Background()
+	enc := image.NewEncoder()
+
+	resizer := gallery.Resizer{}
+	for i := 1; i <= 12; i++ {
+		resizer[fmt.Sprintf("size%d", i)] = image.Dimensions{Width: i * 50}
+	}
+
+	for _, concurrency := range []int{1, 4, 0} {
+		t.Run(fmt.Sprintf("concurrency=%d", concurrency), func(t *testing.T) {
+			storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+			g := gallery.New(uuid.New())
+			g.Create("foo")
+
+			_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+			stack, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath)
+			if err != nil {
+				t.Fatalf("upload failed: %v", err)
+			}
+
+			processed, err := gallery.ProcessingPipeline{resizer}.Process(ctx, stack, enc, storage, gallery.WithConcurrency(concurrency))
+			if err != nil {
+				t.Fatalf("ProcessingPipeline failed to process Stack: %v", err)
+			}
+
+			if len(processed.Images) != len(resizer)+1 {
+				t.Fatalf("processed Stack should contain %d images; contains %d", len(resizer)+1, len(processed.Images))
+			}
+
+			for i, img := range processed.Images[1:] {
+				want := fmt.Sprintf("size%d", i+1)
+				if img.Size != want {
+					t.Fatalf("Image #%d should have size %q; has %q", i+1, want, img.Size)
+				}
+				if img.Width != resizer[want].Width {
+					t.Fatalf("Image %q should have width of %d; has %d", want, resizer[want].Width, img.Width)
+				}
+				if _, _, err := img.Download(ctx, storage); err != nil {
+					t.Fatalf("Image %q should have been uploaded: %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+// newHalvedImage returns an encoded 800x400 PNG image whose left half is red
+// and whose right half is blue.
+func newHalvedImage(t *testing.T, enc image.Encoder) *bytes.Buffer {
+	img := stdimage.NewNRGBA(stdimage.Rect(0, 0, 800, 400))
+	for x := 0; x < 800; x++ {
+		for y := 0; y < 400; y++ {
+			c := color.NRGBA{255, 0, 0, 255}
+			if x >= 400 {
+				c = color.NRGBA{0, 0, 255, 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := enc.Encode(&buf, img, "png"); err != nil {
+		t.Fatalf("encode image: %v", err)
+	}
+	return &buf
+}
+
+func TestPlaceholder_Process(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	enc := image.NewEncoder()
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+
+	stack, err := g.Upload(context.Background(), storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	pipe := gallery.ProcessingPipeline{gallery.Placeholder{}}
+
+	processed, err := pipe.Process(context.Background(), stack, enc, storage)
+	if err != nil {
+		t.Fatalf("ProcessingPipeline failed to process Stack: %v", err)
+	}
+
+	// 4x3 components: size flag, maximum AC value, DC and 11 AC components.
+	if want := 1 + 1 + 4 + 2*11; len(processed.Placeholder) != want {
+		t.Fatalf("Placeholder should have length %d; has length %d (%q)", want, len(processed.Placeholder), processed.Placeholder)
+	}
+
+	if len(processed.Images) != len(stack.Images) {
+		t.Fatalf("Placeholder should not add images to the Stack")
+	}
+}
+
 func TestProcessingPipeline_Process_illegalStackIDUpdate(t *testing.T) {
 	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
 
@@ -267,7 +709,415 @@ func TestPostProcessor_Run(t *testing.T) {
 		t.Fatalf("ProcessingPipeline failed: %v", err)
 	}
 
+	if stack.Pending || stack.ProcessedAt.IsZero() {
+		t.Fatalf("processed Stack should not be pending and have a ProcessedAt time; Pending=%v ProcessedAt=%v", stack.Pending, stack.ProcessedAt)
+	}
+	want.ProcessedAt = stack.ProcessedAt
+
 	if !reflect.DeepEqual(want, stack) {
 		t.Fatalf("PostProcessor's processed Stack is wrong.\n\nwant=%v\n\ngot=%v", want, stack)
 	}
 }
+
+func TestProcessingPipeline_Preview(t *testing.T) {
+	enc := image.NewEncoder()
+	pipe := gallery.ProcessingPipeline{
+		gallery.Resizer{"small": {Width: 400}},
+		gallery.Cropper{"square": {Width: 100, Height: 100}},
+		gallery.FormatConverter{"jpeg"},
+	}
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+
+	results, err := pipe.Preview(context.Background(), buf, enc)
+	if err != nil {
+		t.Fatalf("Preview failed with %q", err)
+	}
+
+	type variant struct {
+		size, format  string
+		width, height int
+	}
+
+	got := make(map[variant]gallery.PreviewResult)
+	for _, res := range results {
+		if res.Bytes <= 0 {
+			t.Fatalf("Preview should report the file size of %v", res)
+		}
+		got[variant{res.Size, res.Format, res.Width, res.Height}] = res
+	}
+
+	want := []variant{
+		{"", "", 800, 600},
+		{"", "jpeg", 800, 600},
+		{"small", "", 400, 300},
+		{"small", "jpeg", 400, 300},
+		{"square", "", 100, 100},
+		{"square", "jpeg", 100, 100},
+	}
+
+	if len(results) != len(want) {
+		t.Fatalf("Preview should return %d results; got %d (%v)", len(want), len(results), results)
+	}
+
+	for _, v := range want {
+		res, ok := got[v]
+		if !ok {
+			t.Fatalf("Preview should report %v; got %v", v, results)
+		}
+		if res.Original != (v.size == "" && v.format == "") {
+			t.Fatalf("only the original image should be reported as original; got %v", res)
+		}
+		if res.Cropped != (v.size == "square") {
+			t.Fatalf("only the cropped images should be reported as cropped; got %v", res)
+		}
+	}
+}
+
+func TestPostProcessor_ReprocessGallery(t *testing.T) {
+	enc := image.NewEncoder()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	galleries := gallery.GoesRepository(repository.New(estore))
+	svc := gallery.NewPostProcessor(enc, storage, galleries)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+	uploaded, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	// Process the Stack with a previous pipeline.
+	processed, err := gallery.ProcessingPipeline{
+		gallery.Resizer{"small": {Width: 320}, "legacy": {Width: 100}},
+	}.Process(ctx, uploaded, enc, storage)
+	if err != nil {
+		t.Fatalf("ProcessingPipeline failed: %v", err)
+	}
+	if err := g.Update(uploaded.ID, func(gallery.Stack) gallery.Stack { return processed }); err != nil {
+		t.Fatalf("update Stack: %v", err)
+	}
+
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("failed to save Gallery: %v", err)
+	}
+
+	processedStack := make(chan gallery.Stack)
+	errs, err := svc.Run(ctx, ebus, gallery.ProcessingPipeline{
+		gallery.Resizer{"small": {Width: 640}, "medium": {Width: 1280}},
+	}, gallery.OnProcessed(func(s gallery.Stack, _ *gallery.Gallery) {
+		processedStack <- s
+	}))
+	if err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+
+	stacks, err := svc.ReprocessGallery(ctx, g.ID)
+	if err != nil {
+		t.Fatalf("ReprocessGallery failed with %q", err)
+	}
+	if len(stacks) != 1 || stacks[0].ID != uploaded.ID {
+		t.Fatalf("ReprocessGallery should return the reprocessed Stack; got %v", stacks)
+	}
+
+	timer := time.NewTimer(3 * time.Second)
+	defer timer.Stop()
+
+	var stack gallery.Stack
+	select {
+	case <-timer.C:
+		t.Fatal("timed out")
+	case err := <-errs:
+		t.Fatal(err)
+	case stack = <-processedStack:
+	}
+
+	widths := make(map[string]int)
+	for _, img := range stack.Images {
+		if img.Stale {
+			t.Fatalf("reprocessed Stack should not have stale Images; got %v", stack.Images)
+		}
+		widths[img.Size] = img.Width
+	}
+
+	if want := map[string]int{"": 800, "small": 640, "medium": 1280}; !reflect.DeepEqual(widths, want) {
+		t.Fatalf("reprocessed Stack should have Images with widths %v; got %v", want, widths)
+	}
+}
+
+func TestPostProcessor_Run_failed(t *testing.T) {
+	enc := image.NewEncoder()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	galleries := gallery.GoesRepository(repository.New(estore))
+	svc := gallery.NewPostProcessor(enc, storage, galleries)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockError := errors.New("mock error")
+	pipe := gallery.ProcessingPipeline{
+		gallery.ProcessorFunc(func(*gallery.ProcessorContext) error {
+			return mockError
+		}),
+	}
+
+	errs, err := svc.Run(ctx, ebus, pipe)
+	if err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+	uploaded, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("failed to save Gallery: %v", err)
+	}
+
+	select {
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out")
+	case err := <-errs:
+		if !errors.Is(err, mockError) {
+			t.Fatalf("Run should fail with %q; got %q", mockError, err)
+		}
+	}
+
+	g, err = galleries.Fetch(ctx, g.ID)
+	if err != nil {
+		t.Fatalf("fetch gallery: %v", err)
+	}
+
+	stack, err := g.Stack(uploaded.ID)
+	if err != nil {
+		t.Fatalf("get stack: %v", err)
+	}
+
+	if stack.Pending || stack.ProcessingError == "" {
+		t.Fatalf("Stack should not be pending and have a ProcessingError; Pending=%v ProcessingError=%q", stack.Pending, stack.ProcessingError)
+	}
+}
+
+func TestPostProcessor_RunWithSelector(t *testing.T) {
+	enc := image.NewEncoder()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	galleries := gallery.GoesRepository(repository.New(estore))
+	svc := gallery.NewPostProcessor(enc, storage, galleries)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pipes := gallery.Pipelines{
+		Default: gallery.ProcessingPipeline{gallery.Resizer{"small": {Width: 640}}},
+		ByName: map[string]gallery.ProcessingPipeline{
+			"hero": {gallery.Resizer{"large": {Width: 1920}, "medium": {Width: 1280}}},
+		},
+	}
+
+	processed := make(chan gallery.Stack)
+
+	errs, err := svc.RunWithSelector(ctx, ebus, pipes.Select, gallery.OnProcessed(func(s gallery.Stack, _ *gallery.Gallery) {
+		processed <- s
+	}))
+	if err != nil {
+		t.Fatalf("RunWithSelector failed with %q", err)
+	}
+
+	tests := []struct {
+		name      string
+		wantSizes []string
+	}{
+		{name: "hero", wantSizes: []string{"", "large", "medium"}},
+		{name: "avatars", wantSizes: []string{"", "small"}},
+	}
+
+	for _, tt := range tests {
+		g := gallery.New(uuid.New())
+		g.Create(tt.name)
+
+		_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+		if _, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath); err != nil {
+			t.Fatalf("upload failed: %v", err)
+		}
+
+		if err := galleries.Save(ctx, g); err != nil {
+			t.Fatalf("failed to save Gallery: %v", err)
+		}
+
+		var stack gallery.Stack
+		select {
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out")
+		case err := <-errs:
+			t.Fatal(err)
+		case stack = <-processed:
+		}
+
+		sizes := stack.Sizes()
+		sort.Strings(sizes)
+		if !reflect.DeepEqual(sizes, tt.wantSizes) {
+			t.Errorf("Stack of Gallery %q should have sizes %v; has %v", tt.name, tt.wantSizes, sizes)
+		}
+	}
+}
+
+func TestPipelines_Select(t *testing.T) {
+	id := uuid.New()
+	byDefault := gallery.ProcessingPipeline{gallery.Placeholder{}}
+	byID := gallery.ProcessingPipeline{gallery.EXIF{}}
+	byName := gallery.ProcessingPipeline{gallery.Resizer{}}
+
+	pipes := gallery.Pipelines{
+		Default: byDefault,
+		ByID:    map[uuid.UUID]gallery.ProcessingPipeline{id: byID},
+		ByName:  map[string]gallery.ProcessingPipeline{"hero": byName},
+	}
+
+	tests := []struct {
+		name string
+		id   uuid.UUID
+		want gallery.ProcessingPipeline
+	}{
+		{name: "hero", id: id, want: byID},
+		{name: "hero", id: uuid.New(), want: byName},
+		{name: "foo", id: uuid.New(), want: byDefault},
+	}
+
+	for _, tt := range tests {
+		if got := pipes.Select(tt.id, tt.name); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Select(%v, %q) should return %v; got %v", tt.id, tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestEXIF_Process(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	enc := image.NewEncoder()
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	img, _ := imggen.ColoredRectangle(80, 60, color.RGBA{100, 100, 100, 0xff})
+	b, err := imggen.JPEGWithEXIF(img, imggen.EXIF(6))
+	if err != nil {
+		t.Fatalf("encode jpeg: %v", err)
+	}
+
+	stack, err := g.Upload(context.Background(), storage, bytes.NewReader(b), exampleName, exampleDisk, "/example/example.jpg")
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	pipe := gallery.ProcessingPipeline{gallery.EXIF{}}
+
+	processed, err := pipe.Process(context.Background(), stack, enc, storage)
+	if err != nil {
+		t.Fatalf("ProcessingPipeline failed to process Stack: %v", err)
+	}
+
+	original := processed.Original()
+
+	if original.Width != 60 || original.Height != 80 {
+		t.Fatalf("original should be rotated to 60x80; is %dx%d", original.Width, original.Height)
+	}
+
+	stored, err := original.File.Download(context.Background(), storage)
+	if err != nil {
+		t.Fatalf("download original: %v", err)
+	}
+
+	if _, err := exif.Decode(bytes.NewReader(stored)); !errors.Is(err, exif.ErrNoEXIF) {
+		t.Fatalf("stored image should have no EXIF metadata; Decode returned %v", err)
+	}
+
+	meta := processed.Metadata
+	if meta == nil {
+		t.Fatalf("Stack should have Metadata")
+	}
+
+	if meta.Make != "Foo" || meta.Model != "Foo 1" {
+		t.Fatalf("Make and Model should be %q and %q; are %q and %q", "Foo", "Foo 1", meta.Make, meta.Model)
+	}
+
+	if want := time.Date(2021, 7, 1, 12, 30, 0, 0, time.UTC); !meta.TakenAt.Equal(want) {
+		t.Fatalf("TakenAt should be %v; is %v", want, meta.TakenAt)
+	}
+
+	if meta.GPS != nil {
+		t.Fatalf("GPS should be stripped from the Metadata; got %v", meta.GPS)
+	}
+}
+
+func TestEXIF_Process_keepGPS(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	enc := image.NewEncoder()
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	img, _ := imggen.ColoredRectangle(80, 60, color.RGBA{100, 100, 100, 0xff})
+	b, err := imggen.JPEGWithEXIF(img, imggen.EXIF(1))
+	if err != nil {
+		t.Fatalf("encode jpeg: %v", err)
+	}
+
+	stack, err := g.Upload(context.Background(), storage, bytes.NewReader(b), exampleName, exampleDisk, "/example/example.jpg")
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	pipe := gallery.ProcessingPipeline{gallery.EXIF{KeepGPS: true}}
+
+	processed, err := pipe.Process(context.Background(), stack, enc, storage)
+	if err != nil {
+		t.Fatalf("ProcessingPipeline failed to process Stack: %v", err)
+	}
+
+	if processed.Metadata == nil || processed.Metadata.GPS == nil {
+		t.Fatalf("Stack should have GPS Metadata; got %v", processed.Metadata)
+	}
+
+	original := processed.Original()
+	if original.Width != 80 || original.Height != 60 {
+		t.Fatalf("original should not be rotated; is %dx%d", original.Width, original.Height)
+	}
+}
+
+func TestEXIF_Process_noEXIF(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	enc := image.NewEncoder()
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(80, 60, color.RGBA{100, 100, 100, 0xff})
+
+	stack, err := g.Upload(context.Background(), storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	pipe := gallery.ProcessingPipeline{gallery.EXIF{}}
+
+	processed, err := pipe.Process(context.Background(), stack, enc, storage)
+	if err != nil {
+		t.Fatalf("ProcessingPipeline failed to process Stack: %v", err)
+	}
+
+	if processed.Metadata != nil {
+		t.Fatalf("Stack should have no Metadata; got %v", processed.Metadata)
+	}
+}
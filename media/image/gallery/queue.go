@@ -0,0 +1,197 @@
+package gallery
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultProcessorQueueSize is the default capacity of the job queue of a
+// PostProcessor. See ProcessorQueueSize.
+const DefaultProcessorQueueSize = 100
+
+// OverflowPolicy determines what happens to a processing job that is received
+// while the job queue of a PostProcessor is full. See ProcessorOverflow.
+type OverflowPolicy int
+
+const (
+	// OverflowPersist keeps overflowing jobs in the JobStore of the
+	// PostProcessor and queues them as soon as the queue has room again.
+	// Without a JobStore (see ProcessorJobStore), the jobs are kept in memory.
+	// OverflowPersist is the default OverflowPolicy.
+	OverflowPersist OverflowPolicy = iota
+
+	// OverflowDrop drops overflowing jobs and reports them to the
+	// Instrumentation of the PostProcessor. The Stacks of dropped jobs stay
+	// unprocessed until they are reprocessed (see ReprocessStack).
+	OverflowDrop
+)
+
+// String returns the name of the OverflowPolicy.
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowPersist:
+		return "persist"
+	case OverflowDrop:
+		return "drop"
+	default:
+		return fmt.Sprintf("OverflowPolicy(%d)", int(p))
+	}
+}
+
+// ProcessorQueueSize returns a PostProcessorOption that sets the capacity of
+// the job queue of the PostProcessor. Jobs that are received while the queue
+// is full are handled according to the OverflowPolicy of the PostProcessor,
+// so that a burst of uploads cannot block the event bus. Default is
+// DefaultProcessorQueueSize.
+func ProcessorQueueSize(n int) PostProcessorOption {
+	return func(cfg *postProcessorConfig) {
+		cfg.queueSize = n
+	}
+}
+
+// ProcessorOverflow returns a PostProcessorOption that sets the OverflowPolicy
+// of the PostProcessor. Default is OverflowPersist.
+func ProcessorOverflow(policy OverflowPolicy) PostProcessorOption {
+	return func(cfg *postProcessorConfig) {
+		cfg.overflow = policy
+	}
+}
+
+// jobQueue is the bounded job queue of a PostProcessor. Overflowing jobs are
+// only kept in the JobStore and resumed by a separate goroutine (see resume).
+type jobQueue struct {
+	cfg  postProcessorConfig
+	jobs chan ProcessingJob
+
+	// overflowed is signaled when a job is left in the JobStore.
+	overflowed chan struct{}
+
+	mux sync.Mutex
+	// active are the jobs that are queued or processed by a worker.
+	active map[uuid.UUID]struct{}
+}
+
+func newJobQueue(cfg postProcessorConfig) *jobQueue {
+	return &jobQueue{
+		cfg:        cfg,
+		jobs:       make(chan ProcessingJob, cfg.queueSize),
+		overflowed: make(chan struct{}, 1),
+		active:     make(map[uuid.UUID]struct{}),
+	}
+}
+
+// offer queues a received job without blocking. If the queue is full, the job
+// is handled according to the OverflowPolicy.
+func (q *jobQueue) offer(ctx context.Context, job ProcessingJob) {
+	q.cfg.jobQueued()
+	saved := q.cfg.saveJob(ctx, job)
+
+	q.mux.Lock()
+	select {
+	case q.jobs <- job:
+		q.active[job.ID] = struct{}{}
+		q.mux.Unlock()
+		return
+	default:
+		q.mux.Unlock()
+	}
+
+	if q.cfg.overflow == OverflowPersist && saved {
+		q.cfg.logf("Queue is full. Job persisted for later. (JobID=%v StackID=%v)", job.ID, job.StackID)
+		q.signal()
+		return
+	}
+
+	q.cfg.logf("Queue is full. Job dropped. (JobID=%v StackID=%v)", job.ID, job.StackID)
+	q.cfg.jobDropped()
+	q.cfg.deleteJob(ctx, job.ID)
+}
+
+// signal wakes up the resume goroutine.
+func (q *jobQueue) signal() {
+	select {
+	case q.overflowed <- struct{}{}:
+	default:
+	}
+}
+
+// done marks a job as finished, so that it can be resumed if it is still in
+// the JobStore. Finished jobs must be deleted from the JobStore before done
+// is called.
+func (q *jobQueue) done(id uuid.UUID) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	delete(q.active, id)
+}
+
+// retry queues a failed job again after the given delay.
+func (q *jobQueue) retry(ctx context.Context, job ProcessingJob, delay time.Duration) {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-timer.C:
+	}
+
+	select {
+	case <-ctx.Done():
+	case q.jobs <- job:
+	}
+}
+
+// resume queues the jobs of the JobStore that are neither queued nor
+// processed, whenever a job overflowed the queue. resume blocks until ctx is
+// canceled.
+func (q *jobQueue) resume(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.overflowed:
+		}
+
+		jobs, err := q.pending(ctx)
+		if err != nil {
+			q.cfg.logf("Failed to fetch persisted processing jobs: %v", err)
+			continue
+		}
+
+		for _, job := range jobs {
+			select {
+			case <-ctx.Done():
+				return
+			case q.jobs <- job:
+			}
+		}
+	}
+}
+
+// pending returns the jobs of the JobStore that are neither queued nor
+// processed and marks them as active. The lock is held while the JobStore is
+// queried, so that a job that is finished concurrently is not resumed.
+func (q *jobQueue) pending(ctx context.Context) ([]ProcessingJob, error) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	jobs, err := q.cfg.jobs.Jobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := jobs[:0]
+	for _, job := range jobs {
+		if _, ok := q.active[job.ID]; ok {
+			continue
+		}
+		q.active[job.ID] = struct{}{}
+		pending = append(pending, job)
+	}
+
+	return pending, nil
+}
@@ -0,0 +1,136 @@
+package gallery_test
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+func TestPostProcessor_Run_overflowPersist(t *testing.T) {
+	processed, inst := runOverflow(t, gallery.OverflowPersist)
+
+	if processed != 3 {
+		t.Fatalf("all 3 Stacks should be processed; %d were processed", processed)
+	}
+
+	inst.mux.Lock()
+	defer inst.mux.Unlock()
+
+	if inst.dropped != 0 {
+		t.Fatalf("no job should be dropped; %d were dropped", inst.dropped)
+	}
+
+	if inst.queued != 3 || inst.started != 3 {
+		t.Fatalf("3 jobs should have been queued and started; queued=%d started=%d", inst.queued, inst.started)
+	}
+}
+
+func TestPostProcessor_Run_overflowDrop(t *testing.T) {
+	processed, inst := runOverflow(t, gallery.OverflowDrop)
+
+	inst.mux.Lock()
+	defer inst.mux.Unlock()
+
+	if inst.dropped == 0 {
+		t.Fatalf("overflowing jobs should be dropped")
+	}
+
+	if processed+inst.dropped != 3 {
+		t.Fatalf("jobs should either be processed or dropped; processed=%d dropped=%d", processed, inst.dropped)
+	}
+
+	if inst.queued != 3 || inst.started != processed {
+		t.Fatalf("3 jobs should have been queued and %d started; queued=%d started=%d", processed, inst.queued, inst.started)
+	}
+}
+
+// runOverflow uploads 3 Stacks to a PostProcessor with a queue size of 1,
+// whose single worker is blocked until all jobs were received, and returns
+// the number of processed Stacks.
+func runOverflow(t *testing.T, policy gallery.OverflowPolicy) (int, *recordingInstrumentation) {
+	enc := image.NewEncoder()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	galleries := gallery.GoesRepository(repository.New(estore))
+	svc := gallery.NewPostProcessor(enc, storage, galleries)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	inst := &recordingInstrumentation{}
+	unblock := make(chan struct{})
+	pipe := gallery.ProcessingPipeline{
+		gallery.ProcessorFunc(func(*gallery.ProcessorContext) error {
+			<-unblock
+			return nil
+		}),
+	}
+
+	processed := make(chan gallery.Stack, 3)
+	errs, err := svc.Run(
+		ctx, ebus, pipe,
+		gallery.ProcessorQueueSize(1),
+		gallery.ProcessorOverflow(policy),
+		gallery.ProcessorInstrumentation(inst),
+		gallery.OnProcessed(func(s gallery.Stack, _ *gallery.Gallery) { processed <- s }),
+	)
+	if err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	for i := 0; i < 3; i++ {
+		_, buf := imggen.ColoredRectangle(80, 60, color.RGBA{100, 100, 100, 0xff})
+		if _, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, fmt.Sprintf("/example/%d.png", i)); err != nil {
+			t.Fatalf("upload failed: %v", err)
+		}
+	}
+
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("failed to save Gallery: %v", err)
+	}
+
+	// wait until all jobs were received
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		inst.mux.Lock()
+		queued := inst.queued
+		inst.mux.Unlock()
+		if queued == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("3 jobs should be queued; %d were queued", queued)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	close(unblock)
+
+	var count int
+	for {
+		select {
+		case err := <-errs:
+			t.Fatal(err)
+		case <-processed:
+			count++
+			continue
+		case <-time.After(500 * time.Millisecond):
+		}
+		return count, inst
+	}
+}
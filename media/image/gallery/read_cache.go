@@ -0,0 +1,164 @@
+package gallery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/helper/streams"
+	"github.com/modernice/nice-cms/media/cache"
+)
+
+// ReadCache is a Repository that caches fetched Galleries in a cache.Store, so
+// that reads don't replay the events of a Gallery on every Fetch. Save,
+// Delete and Use are passed through to the underlying Repository and
+// invalidate the cached Gallery. Run must be called to invalidate Galleries
+// that are changed by other Repositories or application instances.
+//
+// Galleries that are returned from the cache have no hooks registered. Use the
+// Use method to change a Gallery.
+type ReadCache struct {
+	// generation is incremented on every invalidation, so that a Fetch that
+	// raced an invalidation does not cache a stale Gallery.
+	generation uint64
+
+	repo  Repository
+	store cache.Store
+}
+
+type cachedGallery struct {
+	Version int             `json:"version"`
+	State   json.RawMessage `json:"state"`
+}
+
+// NewReadCache returns a ReadCache that caches the Galleries of repo in store.
+func NewReadCache(repo Repository, store cache.Store) *ReadCache {
+	return &ReadCache{repo: repo, store: store}
+}
+
+// Fetch returns the cached Gallery with the given UUID. If the Gallery is not
+// cached, Fetch fetches it from the underlying Repository and caches it.
+// Errors of the cache.Store are not returned; the Gallery is fetched from the
+// underlying Repository instead.
+func (c *ReadCache) Fetch(ctx context.Context, id uuid.UUID) (*Gallery, error) {
+	key := cache.Key(Aggregate, id)
+
+	if b, ok, err := c.store.Get(ctx, key); err == nil && ok {
+		if g, err := decodeCachedGallery(id, b); err == nil {
+			return g, nil
+		}
+	}
+
+	generation := atomic.LoadUint64(&c.generation)
+
+	g, err := c.repo.Fetch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Galleries that don't exist are not cached.
+	if g.AggregateVersion() == 0 {
+		return g, nil
+	}
+
+	// A Set error only means that the next Fetch misses the cache.
+	if b, err := encodeCachedGallery(g); err == nil && atomic.LoadUint64(&c.generation) == generation {
+		c.store.Set(ctx, key, b)
+	}
+
+	return g, nil
+}
+
+// Save saves the Gallery into the underlying Repository and invalidates it.
+func (c *ReadCache) Save(ctx context.Context, g *Gallery) error {
+	if err := c.repo.Save(ctx, g); err != nil {
+		return err
+	}
+	return c.invalidate(ctx, g.ID)
+}
+
+// Delete deletes the Gallery from the underlying Repository and invalidates it.
+func (c *ReadCache) Delete(ctx context.Context, g *Gallery) error {
+	if err := c.repo.Delete(ctx, g); err != nil {
+		return err
+	}
+	return c.invalidate(ctx, g.ID)
+}
+
+// Use calls Use on the underlying Repository and invalidates the Gallery.
+func (c *ReadCache) Use(ctx context.Context, id uuid.UUID, fn func(*Gallery) error) error {
+	if err := c.repo.Use(ctx, id, fn); err != nil {
+		return err
+	}
+	return c.invalidate(ctx, id)
+}
+
+// Run subscribes to Gallery events in a new goroutine and returns a channel of
+// asynchronous errors that is closed when ctx is canceled. Whenever a Gallery
+// event is published, the Gallery is invalidated.
+func (c *ReadCache) Run(ctx context.Context, bus event.Bus) (<-chan error, error) {
+	events, errs, err := bus.Subscribe(ctx, Events[:]...)
+	if err != nil {
+		return nil, fmt.Errorf("subscribe to %v events: %w", Events, err)
+	}
+
+	out := make(chan error)
+
+	go func() {
+		defer close(out)
+
+		fail := func(err error) {
+			select {
+			case <-ctx.Done():
+			case out <- err:
+			}
+		}
+
+		streams.ForEach(ctx, func(evt event.Event) {
+			id, _, _ := evt.Aggregate()
+			if err := c.invalidate(ctx, id); err != nil {
+				fail(err)
+			}
+		}, fail, events, errs)
+	}()
+
+	return out, nil
+}
+
+func (c *ReadCache) invalidate(ctx context.Context, id uuid.UUID) error {
+	atomic.AddUint64(&c.generation, 1)
+	if err := c.store.Delete(ctx, cache.Key(Aggregate, id)); err != nil {
+		return fmt.Errorf("invalidate Gallery %q: %w", id, err)
+	}
+	return nil
+}
+
+func encodeCachedGallery(g *Gallery) ([]byte, error) {
+	state, err := json.Marshal(g.Implementation)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(cachedGallery{
+		Version: g.AggregateVersion(),
+		State:   state,
+	})
+}
+
+func decodeCachedGallery(id uuid.UUID, b []byte) (*Gallery, error) {
+	var cached cachedGallery
+	if err := json.Unmarshal(b, &cached); err != nil {
+		return nil, err
+	}
+	g := New(id)
+	if err := json.Unmarshal(cached.State, g.Implementation); err != nil {
+		return nil, err
+	}
+	if g.Stacks == nil {
+		g.Stacks = make([]Stack, 0)
+	}
+	g.SetVersion(cached.Version)
+	return g, nil
+}
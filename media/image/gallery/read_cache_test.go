@@ -0,0 +1,112 @@
+package gallery_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/cache"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+func TestReadCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := eventbus.New()
+	store := eventstore.New()
+
+	repo := &countingRepository{Repository: gallery.GoesRepository(repository.New(eventstore.WithBus(store, bus)))}
+	galleries := gallery.NewReadCache(repo, cache.LRU(10))
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	id := uuid.New()
+	// Not published, so that the creation does not race the first Fetch.
+	if err := gallery.GoesRepository(repository.New(store)).Use(ctx, id, func(g *gallery.Gallery) error {
+		if err := g.Create("foo"); err != nil {
+			return err
+		}
+		uploadStack(t, g.Implementation, storage)
+		return nil
+	}); err != nil {
+		t.Fatalf("create Gallery: %v", err)
+	}
+
+	errs, err := galleries.Run(ctx, bus)
+	if err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+	go func() {
+		for err := range errs {
+			panic(err)
+		}
+	}()
+
+	want, err := repo.Repository.Fetch(ctx, id)
+	if err != nil {
+		t.Fatalf("fetch Gallery: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		g, err := galleries.Fetch(ctx, id)
+		if err != nil {
+			t.Fatalf("Fetch failed with %q", err)
+		}
+		if !cmp.Equal(g.JSON(), want.JSON()) {
+			t.Fatalf("Fetch returned the wrong Gallery:\n\n%s", cmp.Diff(want.JSON(), g.JSON()))
+		}
+	}
+
+	if n := repo.count(); n != 1 {
+		t.Fatalf("Gallery should be fetched from the Repository %d time; was fetched %d times", 1, n)
+	}
+
+	// Changes through the underlying Repository are invalidated by the events.
+	if err := repo.Use(ctx, id, func(g *gallery.Gallery) error {
+		return g.Rename("bar")
+	}); err != nil {
+		t.Fatalf("rename Gallery: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		g, err := galleries.Fetch(ctx, id)
+		if err != nil {
+			t.Fatalf("Fetch failed with %q", err)
+		}
+		if g.Implementation.Name == "bar" && g.AggregateVersion() == want.AggregateVersion()+1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Gallery should be invalidated after it was renamed; got %q at version %d", g.Implementation.Name, g.AggregateVersion())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+type countingRepository struct {
+	gallery.Repository
+
+	mux     sync.Mutex
+	fetches int
+}
+
+func (r *countingRepository) Fetch(ctx context.Context, id uuid.UUID) (*gallery.Gallery, error) {
+	r.mux.Lock()
+	r.fetches++
+	r.mux.Unlock()
+	return r.Repository.Fetch(ctx, id)
+}
+
+func (r *countingRepository) count() int {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.fetches
+}
@@ -0,0 +1,145 @@
+package gallery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	stdpath "path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/media"
+)
+
+// ErrStackPending is returned when relocating a Stack that is being processed
+// by the PostProcessor.
+var ErrStackPending = errors.New("stack is being processed")
+
+// RelocateStack moves the files of the Stack with the given UUID to the given
+// disk and path of storage. If disk is empty, the files are moved within their
+// disk; if path is empty, the files keep their paths.
+//
+// path is the new path of the primary file of the Stack, which is the video of
+// a video Stack and the original image otherwise. The other files of the Stack
+// (the processed images) are moved next to it: a file whose path starts with
+// the path of the primary file without its extension gets the new prefix, and
+// any other file keeps its file name:
+//
+//	// original: /images/foo.jpg, processed: /images/foo_small.jpg
+//	g.RelocateStack(ctx, storage, id, "", "/archive/bar.jpg")
+//	// original: /archive/bar.jpg, processed: /archive/bar_small.jpg
+//
+// The files are copied to their new location before the StackRelocated event
+// is raised, so if copying fails, the Gallery is left unchanged. The files at
+// the previous location are deleted afterwards. If a deletion fails, no error
+// is returned. Instead, the StackRelocated event contains the deletion error.
+// Previous versions of the Stack are not moved (see Revert).
+//
+// If the Stack is being processed, ErrStackPending is returned.
+func (g *Implementation) RelocateStack(ctx context.Context, storage media.Storage, stackID uuid.UUID, disk, path string) (Stack, error) {
+	if err := g.checkCreated(); err != nil {
+		return Stack{}, err
+	}
+
+	stack, err := g.Stack(stackID)
+	if err != nil {
+		return Stack{}, err
+	}
+
+	if stack.Pending {
+		return stack, ErrStackPending
+	}
+
+	primary := stack.Original().File
+	if stack.Video != nil {
+		primary = stack.Video.File
+	}
+
+	if disk == "" {
+		disk = primary.Disk
+	}
+
+	if path == "" {
+		path = primary.Path
+	}
+
+	if disk == primary.Disk && path == primary.Path {
+		return stack, nil
+	}
+
+	relocate := func(f media.File) string {
+		if f.Path == primary.Path {
+			return path
+		}
+		oldBase := strings.TrimSuffix(primary.Path, stdpath.Ext(primary.Path))
+		if strings.HasPrefix(f.Path, oldBase) {
+			return strings.TrimSuffix(path, stdpath.Ext(path)) + strings.TrimPrefix(f.Path, oldBase)
+		}
+		return stdpath.Join(stdpath.Dir(path), stdpath.Base(f.Path))
+	}
+
+	relocated := stack.copy()
+	var copied, previous []media.File
+	cleanup := func() {
+		for _, f := range copied {
+			if hasFile(stack, f) {
+				continue
+			}
+			f.Delete(ctx, storage)
+		}
+	}
+
+	for i, img := range relocated.Images {
+		f, err := img.File.Relocate(ctx, storage, disk, relocate(img.File))
+		if err != nil {
+			cleanup()
+			return stack, fmt.Errorf("relocate %q: %w", img.Path, err)
+		}
+		copied = append(copied, f)
+		previous = append(previous, img.File)
+		relocated.Images[i].File = f
+	}
+
+	if relocated.Video != nil {
+		f, err := relocated.Video.File.Relocate(ctx, storage, disk, relocate(relocated.Video.File))
+		if err != nil {
+			cleanup()
+			return stack, fmt.Errorf("relocate %q: %w", relocated.Video.Path, err)
+		}
+		copied = append(copied, f)
+		previous = append(previous, relocated.Video.File)
+		relocated.Video.File = f
+	}
+
+	data := StackRelocatedData{Stack: relocated}
+	for _, f := range previous {
+		if hasFile(relocated, f) {
+			continue
+		}
+		if err := f.Delete(ctx, storage); err != nil && data.DeleteError == "" {
+			data.DeleteError = err.Error()
+		}
+	}
+
+	aggregate.NextEvent(g.gallery, StackRelocated, data)
+
+	return g.Stack(stack.ID)
+}
+
+// hasFile returns whether f is a file of stack, which is the case if a file
+// was relocated to its own location.
+func hasFile(stack Stack, f media.File) bool {
+	for _, img := range stack.Images {
+		if img.Disk == f.Disk && img.Path == f.Path {
+			return true
+		}
+	}
+	return stack.Video != nil && stack.Video.Disk == f.Disk && stack.Video.Path == f.Path
+}
+
+func (g *Implementation) relocateStack(evt event.Event) {
+	data := evt.Data().(StackRelocatedData)
+	g.replace(data.Stack.ID, data.Stack)
+}
@@ -0,0 +1,79 @@
+package gallery_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+func TestGallery_RelocateStack(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(
+		media.ConfigureDisk(exampleDisk, media.MemoryDisk()),
+		media.ConfigureDisk("archive", media.MemoryDisk()),
+	)
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	stack := uploadStack(t, g.Implementation, storage)
+	original := mustGet(t, storage, exampleDisk, examplePath)
+
+	disk, _ := storage.Disk(exampleDisk)
+	thumb := []byte("thumb")
+	if err := disk.Put(ctx, "/example/example_thumb.png", thumb); err != nil {
+		t.Fatalf("put thumbnail: %v", err)
+	}
+
+	if err := g.Update(stack.ID, func(s gallery.Stack) gallery.Stack {
+		s.Images = append(s.Images, gallery.Image{
+			Image: media.NewImage(240, 180, exampleName, exampleDisk, "/example/example_thumb.png", len(thumb)),
+			Size:  "thumb",
+		})
+		return s
+	}); err != nil {
+		t.Fatalf("update Stack: %v", err)
+	}
+
+	relocated, err := g.RelocateStack(ctx, storage, stack.ID, "archive", "/photos/foo.png")
+	if err != nil {
+		t.Fatalf("RelocateStack failed with %q", err)
+	}
+
+	if org := relocated.Original(); org.Disk != "archive" || org.Path != "/photos/foo.png" {
+		t.Fatalf("original image should be stored at %q on %q; is stored at %q on %q", "/photos/foo.png", "archive", org.Path, org.Disk)
+	}
+
+	if img := relocated.Images[1]; img.Disk != "archive" || img.Path != "/photos/foo_thumb.png" {
+		t.Fatalf("thumbnail should be stored at %q on %q; is stored at %q on %q", "/photos/foo_thumb.png", "archive", img.Path, img.Disk)
+	}
+
+	expectStorageFileContents(t, storage, "archive", "/photos/foo.png", original)
+	expectStorageFileContents(t, storage, "archive", "/photos/foo_thumb.png", thumb)
+	expectNoStorageFile(t, storage, exampleDisk, examplePath)
+	expectNoStorageFile(t, storage, exampleDisk, "/example/example_thumb.png")
+
+	test.Change(t, g, gallery.StackRelocated, test.EventData(gallery.StackRelocatedData{Stack: relocated}))
+}
+
+func TestGallery_RelocateStack_pending(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	stack := uploadStack(t, g.Implementation, storage)
+
+	if err := g.StartProcessing(stack.ID); err != nil {
+		t.Fatalf("StartProcessing failed with %q", err)
+	}
+
+	if _, err := g.RelocateStack(context.Background(), storage, stack.ID, "", "/moved.png"); !errors.Is(err, gallery.ErrStackPending) {
+		t.Fatalf("RelocateStack should fail with %q; got %v", gallery.ErrStackPending, err)
+	}
+
+	test.NoChange(t, g, gallery.StackRelocated)
+}
@@ -0,0 +1,44 @@
+package gallery
+
+import (
+	"time"
+
+	"github.com/modernice/nice-cms/internal/aggregateutil"
+)
+
+// RepositoryOption is an option for GoesRepository. HookOptions are
+// RepositoryOptions that register hooks on every fetched Gallery.
+type RepositoryOption = aggregateutil.RepositoryOption[*Gallery]
+
+// ApplyRepository registers the hook on every Gallery that is fetched by the
+// repository.
+func (opt HookOption) ApplyRepository(r *aggregateutil.Repository[*Gallery]) {
+	aggregateutil.Init(func(v *Gallery) { v.UseHooks(opt) }).ApplyRepository(r)
+}
+
+// RetryMaxTries returns a RepositoryOption that limits the number of tries of
+// Repository.Use. Default is no limit (tries are only limited by the timeout).
+func RetryMaxTries(n int) RepositoryOption {
+	return aggregateutil.RetryMaxTries[*Gallery](n)
+}
+
+// RetryBackoff returns a RepositoryOption that configures the delay between
+// the tries of Repository.Use. The delay starts at base and is doubled after
+// every retry until max is reached. If max is not greater than base, the delay
+// is constant. Default is a constant delay of 50ms.
+func RetryBackoff(base, max time.Duration) RepositoryOption {
+	return aggregateutil.RetryBackoff[*Gallery](base, max)
+}
+
+// RetryJitter returns a RepositoryOption that adds a random delay of up to d
+// to every delay between the tries of Repository.Use. Default is 100ms.
+func RetryJitter(d time.Duration) RepositoryOption {
+	return aggregateutil.RetryJitter[*Gallery](d)
+}
+
+// RetryTimeout returns a RepositoryOption that limits the total duration of
+// all tries of Repository.Use. Default is 5 seconds. Zero or less disables the
+// timeout.
+func RetryTimeout(d time.Duration) RepositoryOption {
+	return aggregateutil.RetryTimeout[*Gallery](d)
+}
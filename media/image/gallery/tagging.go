@@ -0,0 +1,279 @@
+package gallery
+
+import (
+	"bytes"
+	"fmt"
+	stdimage "image"
+	"math"
+	"path"
+	"strings"
+
+	"github.com/disintegration/imaging"
+	"github.com/modernice/nice-cms/internal/unique"
+)
+
+// A TagRule returns the tags that should be added to an uploaded image.
+type TagRule interface {
+	Match(TagSubject) []string
+}
+
+// TagSubject is the image that is passed to TagRules.
+type TagSubject struct {
+	// Stack is the Stack of the image. Stack.Metadata is only available if
+	// the EXIF Processor ran before the AutoTagger.
+	Stack Stack
+
+	// Image is the decoded original image.
+	Image stdimage.Image
+}
+
+// TagRuleFunc allows ordinary functions to be used as TagRules.
+type TagRuleFunc func(TagSubject) []string
+
+// Match returns fn(s).
+func (fn TagRuleFunc) Match(s TagSubject) []string {
+	return fn(s)
+}
+
+// FilenameRule is a TagRule that adds Tags to images whose name or filename
+// matches Pattern. Pattern uses the syntax of path.Match and is matched
+// case-insensitively.
+//
+//	gallery.FilenameRule{Pattern: "IMG_*.jpg", Tags: []string{"phone"}}
+type FilenameRule struct {
+	Pattern string
+	Tags    []string
+}
+
+// CameraRule is a TagRule that adds Tags to images that were taken with a
+// specific camera. Make and Model are compared case-insensitively against the
+// EXIF metadata of the image. An empty Make or Model matches any camera, so
+// that
+//
+//	gallery.CameraRule{Make: "Canon", Tags: []string{"canon"}}
+//
+// matches all Canon cameras. CameraRule requires the EXIF Processor to run
+// before the AutoTagger.
+type CameraRule struct {
+	Make  string
+	Model string
+	Tags  []string
+}
+
+// ColorRule is a TagRule that adds Tags to images whose dominant color is
+// Color.
+type ColorRule struct {
+	Color ColorBucket
+	Tags  []string
+}
+
+// ColorBucket is a coarse color category.
+type ColorBucket string
+
+// Color buckets
+const (
+	ColorBlack  = ColorBucket("black")
+	ColorWhite  = ColorBucket("white")
+	ColorGray   = ColorBucket("gray")
+	ColorRed    = ColorBucket("red")
+	ColorOrange = ColorBucket("orange")
+	ColorYellow = ColorBucket("yellow")
+	ColorGreen  = ColorBucket("green")
+	ColorCyan   = ColorBucket("cyan")
+	ColorBlue   = ColorBucket("blue")
+	ColorPurple = ColorBucket("purple")
+	ColorPink   = ColorBucket("pink")
+)
+
+// ColorTags is a TagRule that tags every image with the bucket of its dominant
+// color, prefixed by Prefix (e.g. "color:blue").
+type ColorTags struct {
+	Prefix string
+}
+
+// AutoTagger is a Processor that tags the images of a Stack using TagRules.
+// The tags of all matching rules are added to every image of the Stack.
+//
+// AutoTagger should run at the end of a ProcessingPipeline, so that the images
+// that were generated by previous Processors are tagged, too. CameraRules
+// require the EXIF Processor to run before the AutoTagger.
+type AutoTagger []TagRule
+
+// Match implements TagRule.
+func (r FilenameRule) Match(s TagSubject) []string {
+	org := s.Stack.Original()
+	pattern := strings.ToLower(r.Pattern)
+	for _, name := range []string{org.Name, path.Base(org.Path)} {
+		if name == "" {
+			continue
+		}
+		if ok, _ := path.Match(pattern, strings.ToLower(name)); ok {
+			return r.Tags
+		}
+	}
+	return nil
+}
+
+// Match implements TagRule.
+func (r CameraRule) Match(s TagSubject) []string {
+	meta := s.Stack.Metadata
+	if meta == nil {
+		return nil
+	}
+	if r.Make != "" && !strings.EqualFold(strings.TrimSpace(meta.Make), r.Make) {
+		return nil
+	}
+	if r.Model != "" && !strings.EqualFold(strings.TrimSpace(meta.Model), r.Model) {
+		return nil
+	}
+	return r.Tags
+}
+
+// Match implements TagRule.
+func (r ColorRule) Match(s TagSubject) []string {
+	if s.Image == nil || DominantColor(s.Image) != r.Color {
+		return nil
+	}
+	return r.Tags
+}
+
+// Match implements TagRule.
+func (r ColorTags) Match(s TagSubject) []string {
+	if s.Image == nil {
+		return nil
+	}
+	return []string{r.Prefix + string(DominantColor(s.Image))}
+}
+
+// Process runs the AutoTagger on the given ProcessorContext.
+func (rules AutoTagger) Process(ctx *ProcessorContext) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	stack := ctx.Stack()
+	org := stack.Original()
+
+	b, err := org.File.Download(ctx, ctx.Storage())
+	if err != nil {
+		return fmt.Errorf("download original image %q (%s): %w", org.Path, org.Disk, err)
+	}
+
+	img, _, err := stdimage.Decode(bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("decode original image %q (%s): %w", org.Path, org.Disk, err)
+	}
+
+	subject := TagSubject{Stack: stack, Image: img}
+
+	var tags []string
+	for _, rule := range rules {
+		tags = append(tags, rule.Match(subject)...)
+	}
+	tags = unique.Strings(tags...)
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	ctx.cfg.logf("[AutoTagger] Tag stack (StackID=%v Tags=%v)", stack.ID, tags)
+
+	if err := ctx.Update(func(s Stack) Stack {
+		return s.WithTag(tags...)
+	}); err != nil {
+		return fmt.Errorf("update Stack: %w", err)
+	}
+
+	return nil
+}
+
+// DominantColor returns the ColorBucket that most pixels of img fall into.
+func DominantColor(img stdimage.Image) ColorBucket {
+	// Downscale the image to limit the number of pixels that are classified.
+	thumb := imaging.Fit(img, 64, 64, imaging.Box)
+
+	counts := make(map[ColorBucket]int)
+	bounds := thumb.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := thumb.NRGBAAt(x, y)
+			if c.A < 128 {
+				continue
+			}
+			counts[colorBucket(c.R, c.G, c.B)]++
+		}
+	}
+
+	var dominant ColorBucket
+	var max int
+	for _, bucket := range []ColorBucket{ColorBlack, ColorWhite, ColorGray, ColorRed, ColorOrange, ColorYellow, ColorGreen, ColorCyan, ColorBlue, ColorPurple, ColorPink} {
+		if counts[bucket] > max {
+			dominant, max = bucket, counts[bucket]
+		}
+	}
+
+	return dominant
+}
+
+func colorBucket(r, g, b uint8) ColorBucket {
+	h, s, v := hsv(float64(r)/255, float64(g)/255, float64(b)/255)
+
+	switch {
+	case v < 0.2:
+		return ColorBlack
+	case s < 0.15 && v > 0.85:
+		return ColorWhite
+	case s < 0.15:
+		return ColorGray
+	}
+
+	switch {
+	case h < 15 || h >= 345:
+		return ColorRed
+	case h < 45:
+		return ColorOrange
+	case h < 70:
+		return ColorYellow
+	case h < 160:
+		return ColorGreen
+	case h < 200:
+		return ColorCyan
+	case h < 260:
+		return ColorBlue
+	case h < 290:
+		return ColorPurple
+	default:
+		return ColorPink
+	}
+}
+
+func hsv(r, g, b float64) (h, s, v float64) {
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	delta := max - min
+
+	v = max
+	if max > 0 {
+		s = delta / max
+	}
+
+	if delta == 0 {
+		return 0, s, v
+	}
+
+	switch max {
+	case r:
+		h = math.Mod((g-b)/delta, 6)
+	case g:
+		h = (b-r)/delta + 2
+	default:
+		h = (r-g)/delta + 4
+	}
+
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+
+	return h, s, v
+}
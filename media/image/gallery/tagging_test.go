@@ -0,0 +1,84 @@
+package gallery_test
+
+import (
+	"bytes"
+	"context"
+	"image/color"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+func TestAutoTagger_Process(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	enc := image.NewEncoder()
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	img, _ := imggen.ColoredRectangle(80, 60, color.RGBA{20, 40, 220, 0xff})
+	b, err := imggen.JPEGWithEXIF(img, imggen.EXIF(1))
+	if err != nil {
+		t.Fatalf("encode jpeg: %v", err)
+	}
+
+	stack, err := g.Upload(context.Background(), storage, bytes.NewReader(b), "IMG_0001", exampleDisk, "/example/IMG_0001.jpg")
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	pipe := gallery.ProcessingPipeline{
+		gallery.EXIF{},
+		gallery.Resizer{"small": {Width: 40}},
+		gallery.AutoTagger{
+			gallery.FilenameRule{Pattern: "img_*.jpg", Tags: []string{"phone"}},
+			gallery.FilenameRule{Pattern: "DSC_*", Tags: []string{"dslr"}},
+			gallery.CameraRule{Make: "foo", Tags: []string{"foo-camera"}},
+			gallery.CameraRule{Make: "Foo", Model: "Foo 2", Tags: []string{"foo-2"}},
+			gallery.ColorRule{Color: gallery.ColorBlue, Tags: []string{"blue"}},
+			gallery.ColorRule{Color: gallery.ColorRed, Tags: []string{"red"}},
+			gallery.ColorTags{Prefix: "color:"},
+		},
+	}
+
+	processed, err := pipe.Process(context.Background(), stack, enc, storage)
+	if err != nil {
+		t.Fatalf("ProcessingPipeline failed to process Stack: %v", err)
+	}
+
+	want := []string{"phone", "foo-camera", "blue", "color:blue"}
+	for _, img := range processed.Images {
+		if len(img.Tags) != len(want) || !img.HasTag(want...) {
+			t.Fatalf("Image %q should have tags %v; has %v", img.Size, want, img.Tags)
+		}
+	}
+}
+
+func TestDominantColor(t *testing.T) {
+	tests := []struct {
+		color color.Color
+		want  gallery.ColorBucket
+	}{
+		{color.Black, gallery.ColorBlack},
+		{color.White, gallery.ColorWhite},
+		{color.RGBA{128, 128, 128, 0xff}, gallery.ColorGray},
+		{color.RGBA{220, 20, 20, 0xff}, gallery.ColorRed},
+		{color.RGBA{240, 140, 20, 0xff}, gallery.ColorOrange},
+		{color.RGBA{230, 220, 30, 0xff}, gallery.ColorYellow},
+		{color.RGBA{30, 200, 40, 0xff}, gallery.ColorGreen},
+		{color.RGBA{30, 200, 210, 0xff}, gallery.ColorCyan},
+		{color.RGBA{20, 40, 220, 0xff}, gallery.ColorBlue},
+		{color.RGBA{140, 30, 220, 0xff}, gallery.ColorPurple},
+		{color.RGBA{230, 40, 160, 0xff}, gallery.ColorPink},
+	}
+
+	for _, tt := range tests {
+		img, _ := imggen.ColoredRectangle(16, 16, tt.color)
+		if got := gallery.DominantColor(img); got != tt.want {
+			t.Errorf("DominantColor of %v should be %q; got %q", tt.color, tt.want, got)
+		}
+	}
+}
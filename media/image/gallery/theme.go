@@ -0,0 +1,136 @@
+package gallery
+
+import (
+	"image/color"
+	"sort"
+
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/media/image/palette"
+)
+
+// DefaultThemeStacks is the number of recently processed Stacks whose palettes
+// are used by RefreshTheme to compute the Theme of a Gallery.
+const DefaultThemeStacks = 10
+
+// minThemeDistance is the minimum distance between the primary and secondary
+// color of a Theme. Closer colors are considered to be the same color.
+const minThemeDistance = 64
+
+// Theme is the color theme of a Gallery. Frontends can use it to tint the page
+// of a Gallery. Colors are hex colors ("#rrggbb").
+type Theme struct {
+	Primary   string `json:"primary"`
+	Secondary string `json:"secondary,omitempty"`
+}
+
+// ComputeTheme computes the Theme from the palettes of the n most recently
+// processed Stacks (see Stack.Palette). Colors that dominate more images and
+// that are more dominant within an image are preferred. The Secondary color is
+// the preferred color that is distinguishable from the Primary color. If none
+// of the Stacks has a palette, ComputeTheme returns nil.
+func ComputeTheme(stacks Stacks, n int) *Theme {
+	recent := make([]Stack, 0, len(stacks))
+	for _, s := range stacks {
+		if len(s.Palette) > 0 {
+			recent = append(recent, s)
+		}
+	}
+	sort.SliceStable(recent, func(i, j int) bool {
+		return recent[i].ProcessedAt.After(recent[j].ProcessedAt)
+	})
+	if n > 0 && len(recent) > n {
+		recent = recent[:n]
+	}
+
+	type bucket struct {
+		r, g, b float64
+		weight  float64
+	}
+
+	var keys []uint16
+	buckets := make(map[uint16]*bucket)
+	for _, s := range recent {
+		for i, hex := range s.Palette {
+			c, err := palette.Parse(hex)
+			if err != nil {
+				continue
+			}
+			key := uint16(c.R>>5)<<6 | uint16(c.G>>5)<<3 | uint16(c.B>>5)
+			b, ok := buckets[key]
+			if !ok {
+				b = &bucket{}
+				buckets[key] = b
+				keys = append(keys, key)
+			}
+			w := float64(len(s.Palette) - i)
+			b.r += float64(c.R) * w
+			b.g += float64(c.G) * w
+			b.b += float64(c.B) * w
+			b.weight += w
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return buckets[keys[i]].weight > buckets[keys[j]].weight
+	})
+
+	colors := make([]color.NRGBA, len(keys))
+	for i, key := range keys {
+		b := buckets[key]
+		colors[i] = color.NRGBA{
+			R: uint8(b.r / b.weight),
+			G: uint8(b.g / b.weight),
+			B: uint8(b.b / b.weight),
+			A: 0xff,
+		}
+	}
+
+	theme := Theme{Primary: palette.Hex(colors[0])}
+	for _, c := range colors[1:] {
+		if palette.Distance(colors[0], c) >= minThemeDistance {
+			theme.Secondary = palette.Hex(c)
+			break
+		}
+	}
+	if theme.Secondary == "" && len(colors) > 1 {
+		theme.Secondary = palette.Hex(colors[1])
+	}
+
+	return &theme
+}
+
+// RefreshTheme computes the Theme of the Gallery from the palettes of its
+// DefaultThemeStacks most recently processed Stacks. If the Theme has changed,
+// the ThemeUpdated event is raised. The PostProcessor refreshes the Theme
+// after every processed Stack.
+func (g *Implementation) RefreshTheme() error {
+	if err := g.checkCreated(); err != nil {
+		return err
+	}
+
+	theme := ComputeTheme(g.Stacks, DefaultThemeStacks)
+	if equalThemes(theme, g.Theme) {
+		return nil
+	}
+
+	aggregate.NextEvent(g.gallery, ThemeUpdated, ThemeUpdatedData{Theme: theme})
+
+	return nil
+}
+
+func (g *Implementation) updateTheme(evt event.Event) {
+	data := evt.Data().(ThemeUpdatedData)
+	g.Theme = data.Theme
+}
+
+func equalThemes(a, b *Theme) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
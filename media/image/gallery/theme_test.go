@@ -0,0 +1,162 @@
+package gallery_test
+
+import (
+	"context"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+func TestPalette_Process(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{0xff, 0, 0, 0xff})
+
+	stack, err := g.Upload(context.Background(), storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	pipe := gallery.ProcessingPipeline{gallery.Palette{}}
+
+	processed, err := pipe.Process(context.Background(), stack, image.NewEncoder(), storage)
+	if err != nil {
+		t.Fatalf("ProcessingPipeline failed to process Stack: %v", err)
+	}
+
+	if len(processed.Palette) != 1 || processed.Palette[0] != "#ff0000" {
+		t.Fatalf("Palette should be %v; is %v", []string{"#ff0000"}, processed.Palette)
+	}
+}
+
+func TestComputeTheme(t *testing.T) {
+	now := time.Now()
+
+	stacks := gallery.Stacks{
+		{ID: uuid.New(), Palette: []string{"#ff0000", "#ffffff"}, ProcessedAt: now},
+		{ID: uuid.New(), Palette: []string{"#fe0101", "#0000ff"}, ProcessedAt: now.Add(-time.Minute)},
+		{ID: uuid.New(), ProcessedAt: now},
+		{ID: uuid.New(), Palette: []string{"#00ff00"}, ProcessedAt: now.Add(-time.Hour)},
+	}
+
+	theme := gallery.ComputeTheme(stacks, 2)
+	if theme == nil {
+		t.Fatalf("ComputeTheme should return a Theme")
+	}
+
+	if theme.Primary != "#fe0000" {
+		t.Fatalf("Primary should be %q; is %q", "#fe0000", theme.Primary)
+	}
+
+	if theme.Secondary != "#ffffff" && theme.Secondary != "#0000ff" {
+		t.Fatalf("Secondary should be a color of the recent palettes; is %q", theme.Secondary)
+	}
+
+	if theme := gallery.ComputeTheme(stacks[2:3], 10); theme != nil {
+		t.Fatalf("ComputeTheme should return nil for Stacks without palettes; got %v", theme)
+	}
+}
+
+func TestGallery_RefreshTheme(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(80, 60, color.RGBA{0xff, 0, 0, 0xff})
+	stack, err := g.Upload(context.Background(), storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	if err := g.RefreshTheme(); err != nil {
+		t.Fatalf("RefreshTheme failed with %q", err)
+	}
+
+	if g.Theme != nil {
+		t.Fatalf("Theme should be nil without palettes; is %v", g.Theme)
+	}
+
+	test.NoChange(t, g, gallery.ThemeUpdated)
+
+	stack.Palette = []string{"#ff0000"}
+	if err := g.FinishProcessing(stack); err != nil {
+		t.Fatalf("finish processing: %v", err)
+	}
+
+	if err := g.RefreshTheme(); err != nil {
+		t.Fatalf("RefreshTheme failed with %q", err)
+	}
+
+	want := &gallery.Theme{Primary: "#ff0000"}
+	if g.Theme == nil || *g.Theme != *want {
+		t.Fatalf("Theme should be %v; is %v", want, g.Theme)
+	}
+
+	if json := g.JSON(); json.Theme == nil || *json.Theme != *want {
+		t.Fatalf("JSONGallery should have Theme %v; has %v", want, json.Theme)
+	}
+
+	test.Change(t, g, gallery.ThemeUpdated, test.EventData(gallery.ThemeUpdatedData{Theme: want}))
+
+	if err := g.RefreshTheme(); err != nil {
+		t.Fatalf("RefreshTheme failed with %q", err)
+	}
+
+	test.Change(t, g, gallery.ThemeUpdated, test.Exactly(1))
+}
+
+func TestPostProcessor_Run_theme(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	galleries := gallery.GoesRepository(repository.New(estore))
+	svc := gallery.NewPostProcessor(image.NewEncoder(), storage, galleries)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	processed := make(chan *gallery.Gallery)
+	errs, err := svc.Run(ctx, ebus, gallery.ProcessingPipeline{gallery.Palette{}}, gallery.OnProcessed(func(_ gallery.Stack, g *gallery.Gallery) {
+		processed <- g
+	}))
+	if err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(80, 60, color.RGBA{0, 0, 0xff, 0xff})
+	if _, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save Gallery: %v", err)
+	}
+
+	select {
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out")
+	case err := <-errs:
+		t.Fatal(err)
+	case g = <-processed:
+	}
+
+	want := gallery.Theme{Primary: "#0000ff"}
+	if g.Theme == nil || *g.Theme != want {
+		t.Fatalf("Theme should be %v; is %v", want, g.Theme)
+	}
+}
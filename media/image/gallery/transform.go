@@ -0,0 +1,87 @@
+package gallery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image"
+)
+
+// RotateStack rotates the original image of the given Stack clockwise by the
+// given degrees, which must be a multiple of 90 (ErrInvalidTransformation).
+// Negative degrees rotate counter-clockwise. Rotating a Stack by a multiple of
+// 360 degrees does nothing. See Transform.
+func (g *Implementation) RotateStack(ctx context.Context, storage media.Storage, stackID uuid.UUID, degrees int) (Stack, error) {
+	if degrees%360 == 0 {
+		if err := g.checkCreated(); err != nil {
+			return Stack{}, err
+		}
+		return g.Stack(stackID)
+	}
+	return g.Transform(ctx, storage, stackID, image.Rotation(degrees))
+}
+
+// FlipStack flips the original image of the given Stack horizontally or
+// vertically. Unknown directions return ErrInvalidTransformation. See
+// Transform.
+func (g *Implementation) FlipStack(ctx context.Context, storage media.Storage, stackID uuid.UUID, flip image.Flip) (Stack, error) {
+	return g.Transform(ctx, storage, stackID, flip)
+}
+
+// Transform applies the Transformation to the original image of the given
+// Stack, re-encodes it in its original format and replaces the original Image
+// with it (see Replace). The FocalPoint and the Crop of the Stack are
+// transformed, so that they point to the same content of the image as before.
+// The other Images of the Stack become stale until they are processed again by
+// the PostProcessor.
+//
+// Transform returns ErrVideoStack for video Stacks and ErrLegalHold if the
+// Stack or the Gallery is under legal hold.
+func (g *Implementation) Transform(ctx context.Context, storage media.Storage, stackID uuid.UUID, t image.Transformation) (Stack, error) {
+	if err := g.checkCreated(); err != nil {
+		return Stack{}, err
+	}
+
+	stack, err := g.Stack(stackID)
+	if err != nil {
+		return Stack{}, err
+	}
+
+	if !t.Valid() {
+		return stack, fmt.Errorf("%w: %v", ErrInvalidTransformation, t)
+	}
+
+	if stack.IsVideo() {
+		return stack, ErrVideoStack
+	}
+
+	if err := g.checkHold(stack.ID); err != nil {
+		return stack, err
+	}
+
+	org := stack.Original()
+	decoded, format, err := org.Download(ctx, storage)
+	if err != nil {
+		return stack, fmt.Errorf("download original image %q (%s): %w", org.Path, org.Disk, err)
+	}
+
+	var buf bytes.Buffer
+	if err := image.NewEncoder().Encode(&buf, t.Apply(decoded), format); err != nil {
+		return stack, fmt.Errorf("encode %q image: %w", format, err)
+	}
+
+	return g.replaceOriginal(ctx, storage, &buf, stack, func(replaced Stack) Stack {
+		if stack.FocalPoint != nil {
+			focus := t.Point(*stack.FocalPoint)
+			replaced.FocalPoint = &focus
+		}
+		if stack.Crop != nil {
+			area := t.Area(*stack.Crop)
+			replaced.Crop = &area
+		}
+		return replaced
+	})
+}
@@ -0,0 +1,116 @@
+package gallery_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+func TestGallery_RotateStack(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+
+	if _, err := g.RotateStack(ctx, storage, uuid.New(), 90); !errors.Is(err, gallery.ErrNotCreated) {
+		t.Fatalf("RotateStack should fail with %q if the Gallery hasn't been created yet; got %q", gallery.ErrNotCreated, err)
+	}
+
+	g.Create("foo")
+
+	stack := uploadStack(t, g.Implementation, storage)
+
+	if _, err := g.SetFocalPoint(stack.ID, image.FocalPoint{X: 10, Y: 20}); err != nil {
+		t.Fatalf("SetFocalPoint failed with %q", err)
+	}
+	if _, err := g.CropStack(stack.ID, image.Area{X: 10, Y: 20, Width: 30, Height: 40}); err != nil {
+		t.Fatalf("CropStack failed with %q", err)
+	}
+
+	if _, err := g.RotateStack(ctx, storage, stack.ID, 45); !errors.Is(err, gallery.ErrInvalidTransformation) {
+		t.Fatalf("RotateStack should fail with %q for 45 degrees; got %q", gallery.ErrInvalidTransformation, err)
+	}
+
+	rotated, err := g.RotateStack(ctx, storage, stack.ID, 90)
+	if err != nil {
+		t.Fatalf("RotateStack failed with %q", err)
+	}
+
+	org := rotated.Original()
+	downloaded, _, err := org.Download(ctx, storage)
+	if err != nil {
+		t.Fatalf("download rotated image: %v", err)
+	}
+	if b := downloaded.Bounds(); b.Dx() != 600 || b.Dy() != 800 {
+		t.Fatalf("rotated image should be 600x800; is %dx%d", b.Dx(), b.Dy())
+	}
+
+	if want := (image.FocalPoint{X: 80, Y: 10}); rotated.FocalPoint == nil || *rotated.FocalPoint != want {
+		t.Fatalf("FocalPoint should be rotated to %v; is %v", want, rotated.FocalPoint)
+	}
+
+	if want := (image.Area{X: 40, Y: 10, Width: 40, Height: 30}); rotated.Crop == nil || *rotated.Crop != want {
+		t.Fatalf("Crop should be rotated to %v; is %v", want, rotated.Crop)
+	}
+
+	test.Change(t, g, gallery.ImageReplaced, test.Exactly(1))
+
+	if _, err := g.RotateStack(ctx, storage, stack.ID, -360); err != nil {
+		t.Fatalf("RotateStack failed with %q", err)
+	}
+	test.Change(t, g, gallery.ImageReplaced, test.Exactly(1))
+}
+
+func TestGallery_FlipStack(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	stack := uploadStack(t, g.Implementation, storage)
+
+	if _, err := g.SetFocalPoint(stack.ID, image.FocalPoint{X: 10, Y: 20}); err != nil {
+		t.Fatalf("SetFocalPoint failed with %q", err)
+	}
+
+	if _, err := g.FlipStack(ctx, storage, stack.ID, image.Flip("diagonal")); !errors.Is(err, gallery.ErrInvalidTransformation) {
+		t.Fatalf("FlipStack should fail with %q for an unknown direction; got %q", gallery.ErrInvalidTransformation, err)
+	}
+
+	flipped, err := g.FlipStack(ctx, storage, stack.ID, image.FlipHorizontal)
+	if err != nil {
+		t.Fatalf("FlipStack failed with %q", err)
+	}
+
+	if want := (image.FocalPoint{X: 90, Y: 20}); flipped.FocalPoint == nil || *flipped.FocalPoint != want {
+		t.Fatalf("FocalPoint should be flipped to %v; is %v", want, flipped.FocalPoint)
+	}
+
+	if flipped.Crop != nil {
+		t.Fatalf("Crop should be nil; is %v", flipped.Crop)
+	}
+
+	test.Change(t, g, gallery.ImageReplaced, test.Exactly(1))
+}
+
+func TestGallery_Transform_video(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	stack, err := g.UploadVideo(ctx, storage, strings.NewReader("foo"), exampleName, exampleDisk, exampleVideoPath)
+	if err != nil {
+		t.Fatalf("upload video: %v", err)
+	}
+
+	if _, err := g.RotateStack(ctx, storage, stack.ID, 90); !errors.Is(err, gallery.ErrVideoStack) {
+		t.Fatalf("RotateStack should fail with %q for a video Stack; got %q", gallery.ErrVideoStack, err)
+	}
+}
@@ -0,0 +1,334 @@
+package gallery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/helper/streams"
+	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/projector"
+)
+
+const (
+	// DefaultRetention is the default time that trashed Stacks are kept before
+	// they are purged by a Janitor.
+	DefaultRetention = 30 * 24 * time.Hour
+
+	// DefaultJanitorInterval is the default interval in which a Janitor purges
+	// expired Stacks.
+	DefaultJanitorInterval = time.Hour
+)
+
+// ErrNotTrashed is returned when a Stack cannot be found in the trash of a
+// Gallery.
+var ErrNotTrashed = errors.New("stack not in trash")
+
+// TrashedStack is a Stack in the trash of a Gallery.
+type TrashedStack struct {
+	Stack
+
+	TrashedAt time.Time `json:"trashedAt"`
+}
+
+// Expired returns whether the retention period of the TrashedStack has passed
+// at the given time.
+func (s TrashedStack) Expired(retention time.Duration, now time.Time) bool {
+	return !now.Before(s.TrashedAt.Add(retention))
+}
+
+// TrashedStack returns the Stack with the given UUID from the trash of the
+// Gallery, or ErrNotTrashed.
+func (g *Implementation) TrashedStack(id uuid.UUID) (TrashedStack, error) {
+	for _, s := range g.Trashed {
+		if s.ID == id {
+			return TrashedStack{Stack: s.Stack.copy(), TrashedAt: s.TrashedAt}, nil
+		}
+	}
+	return TrashedStack{}, ErrNotTrashed
+}
+
+// Trash moves the Stack with the given UUID to the trash of the Gallery. The
+// files of the Stack are kept until the Stack is purged, so that it can be
+// restored using Restore. Trashed Stacks are not part of g.Stacks. If the
+// Stack or the Gallery is under legal hold, ErrLegalHold is returned.
+func (g *Implementation) Trash(id uuid.UUID) (TrashedStack, error) {
+	if err := g.checkCreated(); err != nil {
+		return TrashedStack{}, err
+	}
+
+	stack, err := g.Stack(id)
+	if err != nil {
+		return TrashedStack{}, err
+	}
+
+	if err := g.checkHold(id); err != nil {
+		return TrashedStack{}, err
+	}
+
+	aggregate.NextEvent(g.gallery, StackTrashed, StackTrashedData{Stack: stack})
+
+	return g.TrashedStack(id)
+}
+
+func (g *Implementation) trashStack(evt event.Event) {
+	data := evt.Data().(StackTrashedData)
+	g.remove(data.Stack.ID)
+	g.Trashed = append(g.Trashed, TrashedStack{
+		Stack:     data.Stack,
+		TrashedAt: evt.Time(),
+	})
+}
+
+// Restore moves the Stack with the given UUID from the trash back to the end
+// of the Stacks of the Gallery. If the Stack is not in the trash, ErrNotTrashed
+// is returned.
+func (g *Implementation) Restore(id uuid.UUID) (Stack, error) {
+	if err := g.checkCreated(); err != nil {
+		return Stack{}, err
+	}
+
+	trashed, err := g.TrashedStack(id)
+	if err != nil {
+		return Stack{}, err
+	}
+
+	aggregate.NextEvent(g.gallery, StackRestored, StackRestoredData{Stack: trashed.Stack})
+
+	return g.Stack(id)
+}
+
+func (g *Implementation) restoreStack(evt event.Event) {
+	data := evt.Data().(StackRestoredData)
+	g.removeTrashed(data.Stack.ID)
+	g.Stacks = append(g.Stacks, data.Stack)
+}
+
+// Purge deletes the images and the video of the Stack with the given UUID from
+// storage and removes the Stack from the trash of the Gallery. If the Stack is
+// not in the trash, ErrNotTrashed is returned. If the Stack or the Gallery is
+// under legal hold, ErrLegalHold is returned.
+//
+// BeforeDelete and AfterDelete hooks are called before and after the deletion.
+func (g *Implementation) Purge(ctx context.Context, storage media.Storage, id uuid.UUID) error {
+	if err := g.checkCreated(); err != nil {
+		return err
+	}
+
+	trashed, err := g.TrashedStack(id)
+	if err != nil {
+		return err
+	}
+
+	if err := g.checkHold(id); err != nil {
+		return err
+	}
+
+	for _, fn := range g.hooks.beforeDelete {
+		if err := fn(ctx, g, trashed.Stack); err != nil {
+			return err
+		}
+	}
+
+	if err := deleteFiles(ctx, storage, trashed.Stack); err != nil {
+		return err
+	}
+
+	aggregate.NextEvent(g.gallery, StackPurged, StackPurgedData{Stack: trashed.Stack})
+
+	for _, fn := range g.hooks.afterDelete {
+		fn(ctx, g, trashed.Stack)
+	}
+
+	return nil
+}
+
+func (g *Implementation) purgeStack(evt event.Event) {
+	data := evt.Data().(StackPurgedData)
+	g.removeTrashed(data.Stack.ID)
+}
+
+func (g *Implementation) removeTrashed(id uuid.UUID) {
+	for i, s := range g.Trashed {
+		if s.ID == id {
+			g.Trashed = append(g.Trashed[:i], g.Trashed[i+1:]...)
+			return
+		}
+	}
+}
+
+// Expired returns the Stacks in the trash of the Gallery whose retention
+// period has passed at the given time.
+func (g *Implementation) Expired(retention time.Duration, now time.Time) []TrashedStack {
+	var out []TrashedStack
+	for _, s := range g.Trashed {
+		if s.Expired(retention, now) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// JanitorOption is an option for a Janitor.
+type JanitorOption func(*Janitor)
+
+// Retention returns a JanitorOption that sets the time that trashed Stacks are
+// kept before they are purged. Default is DefaultRetention.
+func Retention(d time.Duration) JanitorOption {
+	return func(j *Janitor) {
+		j.retention = d
+	}
+}
+
+// JanitorInterval returns a JanitorOption that sets the interval in which the
+// Janitor purges expired Stacks. Default is DefaultJanitorInterval.
+func JanitorInterval(d time.Duration) JanitorOption {
+	return func(j *Janitor) {
+		j.interval = d
+	}
+}
+
+// JanitorProjection returns a JanitorOption that configures the error handling
+// of the projection of the Janitor.
+func JanitorProjection(opts ...projector.Option) JanitorOption {
+	return func(j *Janitor) {
+		j.projectorOpts = append(j.projectorOpts, opts...)
+	}
+}
+
+// Janitor purges trashed Stacks after their retention period. The Janitor
+// projects the trash of all Galleries, so that it doesn't have to fetch every
+// Gallery to find expired Stacks. Janitor is thread-safe.
+type Janitor struct {
+	galleries     Repository
+	storage       media.Storage
+	retention     time.Duration
+	interval      time.Duration
+	projector     *projector.Projector
+	projectorOpts []projector.Option
+
+	mux     sync.RWMutex
+	trashed map[stackRef]time.Time
+}
+
+// NewJanitor returns a Janitor that purges Stacks of the Galleries in the
+// provided Repository from storage.
+func NewJanitor(galleries Repository, storage media.Storage, opts ...JanitorOption) *Janitor {
+	j := &Janitor{
+		galleries: galleries,
+		storage:   storage,
+		retention: DefaultRetention,
+		interval:  DefaultJanitorInterval,
+		trashed:   make(map[stackRef]time.Time),
+	}
+	for _, opt := range opts {
+		opt(j)
+	}
+	j.projector = projector.New(j.projectorOpts...)
+	return j
+}
+
+// Run projects the trash of all Galleries and purges expired Stacks in the
+// configured interval until ctx is canceled. Run returns a channel of
+// asynchronous projection and purge errors.
+func (j *Janitor) Run(ctx context.Context, bus event.Bus, store event.Store, opts ...schedule.ContinuousOption) (<-chan error, error) {
+	schedule := schedule.Continuously(bus, store, []string{
+		StackTrashed,
+		StackRestored,
+		StackPurged,
+		Deleted,
+	}, opts...)
+
+	projectionErrors, err := j.projector.Run(ctx, schedule, j)
+	if err != nil {
+		return nil, err
+	}
+
+	cleanupErrors := make(chan error)
+	go j.cleanup(ctx, cleanupErrors)
+
+	return streams.FanInContext(ctx, projectionErrors, cleanupErrors), nil
+}
+
+// Status returns the projection status of the Janitor.
+func (j *Janitor) Status() projector.Status {
+	return j.projector.Status()
+}
+
+// ApplyEvent applies aggregate events.
+func (j *Janitor) ApplyEvent(evt event.Event) {
+	galleryID, _, _ := evt.Aggregate()
+
+	j.mux.Lock()
+	defer j.mux.Unlock()
+
+	switch data := evt.Data().(type) {
+	case StackTrashedData:
+		j.trashed[stackRef{galleryID: galleryID, stackID: data.Stack.ID}] = evt.Time()
+	case StackRestoredData:
+		delete(j.trashed, stackRef{galleryID: galleryID, stackID: data.Stack.ID})
+	case StackPurgedData:
+		delete(j.trashed, stackRef{galleryID: galleryID, stackID: data.Stack.ID})
+	case DeletedData:
+		for _, id := range data.Stacks {
+			delete(j.trashed, stackRef{galleryID: galleryID, stackID: id})
+		}
+	}
+}
+
+// Cleanup purges the Stacks whose retention period has passed.
+func (j *Janitor) Cleanup(ctx context.Context) error {
+	now := time.Now()
+
+	j.mux.RLock()
+	var expired []stackRef
+	for ref, trashedAt := range j.trashed {
+		if !now.Before(trashedAt.Add(j.retention)) {
+			expired = append(expired, ref)
+		}
+	}
+	j.mux.RUnlock()
+
+	for _, ref := range expired {
+		if err := j.galleries.Use(ctx, ref.galleryID, func(g *Gallery) error {
+			// The Stack may have been restored or purged since the last
+			// projection. Held Stacks are purged once the hold is lifted.
+			s, err := g.TrashedStack(ref.stackID)
+			if err != nil || !s.Expired(j.retention, now) || g.Held(s.ID) {
+				return nil
+			}
+			return g.Purge(ctx, j.storage, ref.stackID)
+		}); err != nil {
+			return fmt.Errorf("purge stack %s of gallery %s: %w", ref.stackID, ref.galleryID, err)
+		}
+	}
+
+	return nil
+}
+
+func (j *Janitor) cleanup(ctx context.Context, out chan<- error) {
+	defer close(out)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := j.Cleanup(ctx); err != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case out <- err:
+				}
+			}
+		}
+	}
+}
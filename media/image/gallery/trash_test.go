@@ -0,0 +1,209 @@
+package gallery_test
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/command/cmdbus"
+	"github.com/modernice/goes/command/cmdbus/dispatch"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/internal/commands"
+	"github.com/modernice/nice-cms/internal/discard"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+func TestGallery_Trash(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	stack := uploadStack(t, g.Implementation, storage)
+
+	trashed, err := g.Trash(stack.ID)
+	if err != nil {
+		t.Fatalf("Trash failed with %q", err)
+	}
+
+	if trashed.ID != stack.ID || trashed.TrashedAt.IsZero() {
+		t.Fatalf("Trash should return the trashed Stack; got %v", trashed)
+	}
+
+	if _, err := g.Stack(stack.ID); !errors.Is(err, gallery.ErrStackNotFound) {
+		t.Fatalf("Stack should return %q for a trashed Stack; got %q", gallery.ErrStackNotFound, err)
+	}
+
+	for _, img := range stack.Images {
+		expectStorageFileContents(t, storage, img.Disk, img.Path, mustGet(t, storage, img.Disk, img.Path))
+	}
+
+	test.Change(t, g, gallery.StackTrashed, test.EventData(gallery.StackTrashedData{Stack: stack}))
+
+	if _, err := g.Trash(stack.ID); !errors.Is(err, gallery.ErrStackNotFound) {
+		t.Fatalf("Trash should fail with %q for a trashed Stack; got %q", gallery.ErrStackNotFound, err)
+	}
+}
+
+func TestGallery_Restore(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	stack := uploadStack(t, g.Implementation, storage)
+
+	if _, err := g.Restore(stack.ID); !errors.Is(err, gallery.ErrNotTrashed) {
+		t.Fatalf("Restore should fail with %q for an untrashed Stack; got %q", gallery.ErrNotTrashed, err)
+	}
+
+	if _, err := g.Trash(stack.ID); err != nil {
+		t.Fatalf("Trash failed with %q", err)
+	}
+
+	restored, err := g.Restore(stack.ID)
+	if err != nil {
+		t.Fatalf("Restore failed with %q", err)
+	}
+
+	if restored.ID != stack.ID {
+		t.Fatalf("Restore should return the restored Stack; got %v", restored)
+	}
+
+	if len(g.Trashed) != 0 {
+		t.Fatalf("trash should be empty; has %d Stacks", len(g.Trashed))
+	}
+
+	test.Change(t, g, gallery.StackRestored, test.EventData(gallery.StackRestoredData{Stack: stack}))
+}
+
+func TestGallery_Purge(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	stack := uploadStack(t, g.Implementation, storage)
+
+	if err := g.Purge(context.Background(), storage, stack.ID); !errors.Is(err, gallery.ErrNotTrashed) {
+		t.Fatalf("Purge should fail with %q for an untrashed Stack; got %q", gallery.ErrNotTrashed, err)
+	}
+
+	if _, err := g.Trash(stack.ID); err != nil {
+		t.Fatalf("Trash failed with %q", err)
+	}
+
+	if err := g.Purge(context.Background(), storage, stack.ID); err != nil {
+		t.Fatalf("Purge failed with %q", err)
+	}
+
+	if _, err := g.TrashedStack(stack.ID); !errors.Is(err, gallery.ErrNotTrashed) {
+		t.Fatalf("TrashedStack should return %q for a purged Stack; got %q", gallery.ErrNotTrashed, err)
+	}
+
+	for _, img := range stack.Images {
+		expectNoStorageFile(t, storage, img.Disk, img.Path)
+	}
+
+	test.Change(t, g, gallery.StackPurged, test.EventData(gallery.StackPurgedData{Stack: stack}))
+}
+
+func TestGallery_Expired(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	stack := uploadStack(t, g.Implementation, storage)
+
+	trashed, err := g.Trash(stack.ID)
+	if err != nil {
+		t.Fatalf("Trash failed with %q", err)
+	}
+
+	if expired := g.Expired(time.Hour, trashed.TrashedAt.Add(time.Minute)); len(expired) != 0 {
+		t.Fatalf("Stack should not expire before the retention period; got %v", expired)
+	}
+
+	if expired := g.Expired(time.Hour, trashed.TrashedAt.Add(time.Hour)); len(expired) != 1 || expired[0].ID != stack.ID {
+		t.Fatalf("Stack should expire after the retention period; got %v", expired)
+	}
+}
+
+func TestJanitor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	cbus := cmdbus.New(commands.NewRegistry(), ebus)
+	galleries := gallery.GoesRepository(repository.New(estore))
+
+	errs := gallery.HandleCommands(ctx, cbus, galleries, storage)
+	go discard.Errors(errs)
+
+	janitor := gallery.NewJanitor(galleries, storage, gallery.Retention(50*time.Millisecond), gallery.JanitorInterval(10*time.Millisecond))
+	janitorErrs, err := janitor.Run(ctx, ebus, estore)
+	if err != nil {
+		t.Fatalf("run janitor: %v", err)
+	}
+	go discard.Errors(janitorErrs)
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+	stack := uploadStack(t, g.Implementation, storage)
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save Gallery: %v", err)
+	}
+
+	if err := cbus.Dispatch(ctx, gallery.TrashStack(g.ID, stack.ID).Any(), dispatch.Sync()); err != nil {
+		t.Fatalf("dispatch command: %v", err)
+	}
+
+	timeout := time.After(3 * time.Second)
+	for {
+		g, err := galleries.Fetch(ctx, g.ID)
+		if err != nil {
+			t.Fatalf("fetch Gallery: %v", err)
+		}
+		if len(g.Trashed) == 0 {
+			break
+		}
+		select {
+		case <-timeout:
+			t.Fatalf("trashed Stack should have been purged")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	for _, img := range stack.Images {
+		expectNoStorageFile(t, storage, img.Disk, img.Path)
+	}
+}
+
+func uploadStack(t *testing.T, g *gallery.Implementation, storage media.Storage) gallery.Stack {
+	_, buf := imggen.ColoredRectangle(800, 600, color.RGBA{100, 100, 100, 0xff})
+	stack, err := g.Upload(context.Background(), storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	return stack
+}
+
+func mustGet(t *testing.T, storage media.Storage, diskName, path string) []byte {
+	disk, err := storage.Disk(diskName)
+	if err != nil {
+		t.Fatalf("get %q storage disk: %v", diskName, err)
+	}
+	b, err := disk.Get(context.Background(), path)
+	if err != nil {
+		t.Fatalf("file of a trashed Stack should not be deleted; Get returned %q", err)
+	}
+	return b
+}
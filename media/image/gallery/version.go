@@ -0,0 +1,247 @@
+package gallery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	stdpath "path"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/media"
+)
+
+var (
+	// ErrVersionNotFound is returned when a version of a Stack cannot be found.
+	ErrVersionNotFound = errors.New("version not found")
+
+	// ErrInvalidVersionLimit is returned by SetVersionLimit if the limit is
+	// negative.
+	ErrInvalidVersionLimit = errors.New("invalid version limit")
+)
+
+// StackVersion is a previous version of a Stack that was kept when the Stack
+// was replaced. The Images and the Video of a StackVersion have the paths of
+// the Stack at the time it was replaced; their contents are stored at
+// VersionPath unless they are content-addressed.
+type StackVersion struct {
+	Version    int          `json:"version"`
+	Images     []Image      `json:"images"`
+	Video      *media.Video `json:"video,omitempty"`
+	Checksum   string       `json:"checksum,omitempty"`
+	ReplacedAt time.Time    `json:"replacedAt"`
+}
+
+// Original returns the original image of the version.
+func (v StackVersion) Original() Image {
+	return Stack{Images: v.Images}.Original()
+}
+
+// VersionPath returns the storage path of a file of a previous version of a
+// Stack, where path is the path of the file at the time the Stack was
+// replaced.
+func VersionPath(stackID uuid.UUID, version int, path string) string {
+	return stdpath.Join("/.versions", stackID.String(), strconv.Itoa(version), path)
+}
+
+// SetVersionLimit sets the number of previous versions that are kept for each
+// Stack of the Gallery when it is replaced. If limit is 0, Stacks are replaced
+// in place. Lowering the limit does not delete versions immediately; excess
+// versions are deleted the next time a Stack is replaced.
+func (g *Implementation) SetVersionLimit(limit int) error {
+	if err := g.checkCreated(); err != nil {
+		return err
+	}
+	if limit < 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidVersionLimit, limit)
+	}
+	if g.VersionLimit == limit {
+		return nil
+	}
+	aggregate.NextEvent(g.gallery, VersionLimitChanged, VersionLimitChangedData{Limit: limit})
+	return nil
+}
+
+func (g *Implementation) changeVersionLimit(evt event.Event) {
+	data := evt.Data().(VersionLimitChangedData)
+	g.VersionLimit = data.Limit
+}
+
+// Version returns the given previous version of the Stack with the given UUID.
+func (g *Implementation) Version(stackID uuid.UUID, version int) (StackVersion, error) {
+	stack, err := g.Stack(stackID)
+	if err != nil {
+		return StackVersion{}, err
+	}
+	for _, v := range stack.Versions {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+	return StackVersion{}, fmt.Errorf("version %d of stack %q: %w", version, stackID, ErrVersionNotFound)
+}
+
+// Revert restores the given previous version of the Stack with the given UUID.
+// The files of the version are copied back to the paths of the Stack, and the
+// current version of the Stack is kept as a new version if the Gallery has a
+// VersionLimit. Revert raises an ImageReplaced event, so that the Stack is
+// processed again. If the Stack or the Gallery is under legal hold,
+// ErrLegalHold is returned.
+func (g *Implementation) Revert(ctx context.Context, storage media.Storage, stackID uuid.UUID, version int) (Stack, error) {
+	stack, err := g.Stack(stackID)
+	if err != nil {
+		return stack, err
+	}
+
+	if err := g.checkHold(stack.ID); err != nil {
+		return stack, err
+	}
+
+	v, err := g.Version(stackID, version)
+	if err != nil {
+		return stack, err
+	}
+
+	versions, err := g.archive(ctx, storage, stack)
+	if err != nil {
+		return stack, err
+	}
+
+	if err := restoreVersionFiles(ctx, storage, stack.ID, v); err != nil {
+		g.discardArchive(ctx, storage, stack, versions)
+		return stack, err
+	}
+
+	reverted := stack.copy()
+	reverted.Images = append([]Image(nil), v.Images...)
+	reverted.Checksum = v.Checksum
+	reverted.Video = nil
+	if v.Video != nil {
+		video := *v.Video
+		reverted.Video = &video
+	}
+	reverted.Version = stack.Version + 1
+	reverted.Versions = g.prune(ctx, storage, stack.ID, versions)
+
+	aggregate.NextEvent(g.gallery, ImageReplaced, ImageReplacedData{
+		Stack:      reverted,
+		Reverted:   true,
+		RevertedTo: v.Version,
+	})
+
+	return g.Stack(reverted.ID)
+}
+
+// versioned returns replaced with the version history of stack, after stack
+// has been archived using archive.
+func (g *Implementation) versioned(ctx context.Context, storage media.Storage, stack, replaced Stack, versions []StackVersion) Stack {
+	replaced.Version = stack.Version + 1
+	replaced.Versions = g.prune(ctx, storage, stack.ID, versions)
+	return replaced
+}
+
+// archive copies the files of the current version of stack to their
+// VersionPath and returns the versions of stack including the current one. If
+// the Gallery has no VersionLimit, the versions of stack are returned as is.
+func (g *Implementation) archive(ctx context.Context, storage media.Storage, stack Stack) ([]StackVersion, error) {
+	versions := append([]StackVersion(nil), stack.Versions...)
+	if g.VersionLimit < 1 {
+		return versions, nil
+	}
+
+	current := StackVersion{
+		Version:    stack.Version,
+		Images:     append([]Image(nil), stack.Images...),
+		Checksum:   stack.Checksum,
+		ReplacedAt: time.Now(),
+	}
+	if stack.Video != nil {
+		video := *stack.Video
+		current.Video = &video
+	}
+
+	for _, f := range current.files() {
+		if err := copyFile(ctx, storage, f, f.Path, VersionPath(stack.ID, current.Version, f.Path)); err != nil {
+			deleteVersionFiles(ctx, storage, stack.ID, current)
+			return nil, fmt.Errorf("archive version %d: %w", current.Version, err)
+		}
+	}
+
+	return append(versions, current), nil
+}
+
+// discardArchive deletes the files of the version that was archived by archive
+// if the replacement of stack failed.
+func (g *Implementation) discardArchive(ctx context.Context, storage media.Storage, stack Stack, versions []StackVersion) {
+	if len(versions) > len(stack.Versions) {
+		deleteVersionFiles(ctx, storage, stack.ID, versions[len(versions)-1])
+	}
+}
+
+// prune deletes the oldest versions that exceed the VersionLimit of the
+// Gallery and returns the remaining versions.
+func (g *Implementation) prune(ctx context.Context, storage media.Storage, stackID uuid.UUID, versions []StackVersion) []StackVersion {
+	for len(versions) > g.VersionLimit {
+		deleteVersionFiles(ctx, storage, stackID, versions[0])
+		versions = versions[1:]
+	}
+	if len(versions) == 0 {
+		return nil
+	}
+	return versions
+}
+
+// files returns the files of the version.
+func (v StackVersion) files() []media.File {
+	files := make([]media.File, 0, len(v.Images)+1)
+	for _, img := range v.Images {
+		files = append(files, img.File)
+	}
+	if v.Video != nil {
+		files = append(files, v.Video.File)
+	}
+	return files
+}
+
+// restoreVersionFiles copies the files of v from their VersionPath back to
+// their paths.
+func restoreVersionFiles(ctx context.Context, storage media.Storage, stackID uuid.UUID, v StackVersion) error {
+	for _, f := range v.files() {
+		if err := copyFile(ctx, storage, f, VersionPath(stackID, v.Version, f.Path), f.Path); err != nil {
+			return fmt.Errorf("restore version %d: %w", v.Version, err)
+		}
+	}
+	return nil
+}
+
+// deleteVersionFiles deletes the files of v from their VersionPath. Errors are
+// ignored, like in deleteFiles.
+func deleteVersionFiles(ctx context.Context, storage media.Storage, stackID uuid.UUID, v StackVersion) {
+	for _, f := range v.files() {
+		if f.StoragePath != "" {
+			continue
+		}
+		if disk, err := storage.Disk(f.Disk); err == nil {
+			disk.Delete(ctx, VersionPath(stackID, v.Version, f.Path))
+		}
+	}
+}
+
+// copyFile copies the file f from src to dst on its disk. Content-addressed
+// files are never overwritten and therefore not copied.
+func copyFile(ctx context.Context, storage media.Storage, f media.File, src, dst string) error {
+	if f.StoragePath != "" {
+		return nil
+	}
+	disk, err := storage.Disk(f.Disk)
+	if err != nil {
+		return fmt.Errorf("get %q disk: %w", f.Disk, err)
+	}
+	if err := media.Copy(ctx, disk, src, dst); err != nil {
+		return fmt.Errorf("copy %q to %q: %w", src, dst, err)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package gallery_test
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+func TestGallery_SetVersionLimit(t *testing.T) {
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	if err := g.SetVersionLimit(-1); !errors.Is(err, gallery.ErrInvalidVersionLimit) {
+		t.Fatalf("SetVersionLimit should fail with %q; got %q", gallery.ErrInvalidVersionLimit, err)
+	}
+
+	if err := g.SetVersionLimit(2); err != nil {
+		t.Fatalf("SetVersionLimit failed with %q", err)
+	}
+
+	if g.VersionLimit != 2 {
+		t.Fatalf("VersionLimit should be %d; is %d", 2, g.VersionLimit)
+	}
+
+	test.Change(t, g, gallery.VersionLimitChanged, test.EventData(gallery.VersionLimitChangedData{Limit: 2}))
+}
+
+func TestGallery_Replace_versions(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+	g.SetVersionLimit(2)
+
+	stack := uploadStack(t, g.Implementation, storage)
+	contents := [][]byte{mustGet(t, storage, exampleDisk, examplePath)}
+
+	for i, c := range []color.RGBA{{200, 0, 0, 0xff}, {0, 200, 0, 0xff}, {0, 0, 200, 0xff}} {
+		_, buf := imggen.ColoredRectangle(400, 300, c)
+		replaced, err := g.Replace(ctx, storage, buf, stack.ID)
+		if err != nil {
+			t.Fatalf("Replace failed with %q", err)
+		}
+		if replaced.Version != i+1 {
+			t.Fatalf("Version should be %d; is %d", i+1, replaced.Version)
+		}
+		contents = append(contents, mustGet(t, storage, exampleDisk, examplePath))
+	}
+
+	replaced, _ := g.Stack(stack.ID)
+	if len(replaced.Versions) != 2 {
+		t.Fatalf("Stack should have %d versions; has %d", 2, len(replaced.Versions))
+	}
+
+	for i, v := range replaced.Versions {
+		if v.Version != i+1 {
+			t.Fatalf("Versions[%d] should be version %d; is %d", i, i+1, v.Version)
+		}
+		expectStorageFileContents(t, storage, exampleDisk, gallery.VersionPath(stack.ID, v.Version, examplePath), contents[v.Version])
+	}
+
+	expectNoStorageFile(t, storage, exampleDisk, gallery.VersionPath(stack.ID, 0, examplePath))
+}
+
+func TestGallery_Replace_withoutVersions(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	stack := uploadStack(t, g.Implementation, storage)
+
+	_, buf := imggen.ColoredRectangle(400, 300, color.RGBA{200, 0, 0, 0xff})
+	replaced, err := g.Replace(ctx, storage, buf, stack.ID)
+	if err != nil {
+		t.Fatalf("Replace failed with %q", err)
+	}
+
+	if len(replaced.Versions) != 0 {
+		t.Fatalf("Stack should have no versions; has %d", len(replaced.Versions))
+	}
+
+	expectNoStorageFile(t, storage, exampleDisk, gallery.VersionPath(stack.ID, 0, examplePath))
+}
+
+func TestGallery_Revert(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+	g.SetVersionLimit(5)
+
+	stack := uploadStack(t, g.Implementation, storage)
+	original := mustGet(t, storage, exampleDisk, examplePath)
+
+	_, buf := imggen.ColoredRectangle(400, 300, color.RGBA{200, 0, 0, 0xff})
+	if _, err := g.Replace(ctx, storage, buf, stack.ID); err != nil {
+		t.Fatalf("Replace failed with %q", err)
+	}
+	replacement := mustGet(t, storage, exampleDisk, examplePath)
+
+	if _, err := g.Revert(ctx, storage, stack.ID, 3); !errors.Is(err, gallery.ErrVersionNotFound) {
+		t.Fatalf("Revert should fail with %q for an unknown version; got %q", gallery.ErrVersionNotFound, err)
+	}
+
+	reverted, err := g.Revert(ctx, storage, stack.ID, 0)
+	if err != nil {
+		t.Fatalf("Revert failed with %q", err)
+	}
+
+	if reverted.Checksum != stack.Checksum {
+		t.Fatalf("Checksum should be %q; is %q", stack.Checksum, reverted.Checksum)
+	}
+
+	if reverted.Version != 2 {
+		t.Fatalf("Version should be %d; is %d", 2, reverted.Version)
+	}
+
+	if len(reverted.Versions) != 2 || reverted.Versions[1].Version != 1 {
+		t.Fatalf("replaced version should be kept; Versions are %v", reverted.Versions)
+	}
+
+	expectStorageFileContents(t, storage, exampleDisk, examplePath, original)
+	expectStorageFileContents(t, storage, exampleDisk, gallery.VersionPath(stack.ID, 1, examplePath), replacement)
+
+	test.Change(t, g, gallery.ImageReplaced, test.EventData(gallery.ImageReplacedData{
+		Stack:      reverted,
+		Reverted:   true,
+		RevertedTo: 0,
+	}))
+}
+
+func TestGallery_Revert_legalHold(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+	g.SetVersionLimit(1)
+
+	stack := uploadStack(t, g.Implementation, storage)
+
+	_, buf := imggen.ColoredRectangle(400, 300, color.RGBA{200, 0, 0, 0xff})
+	if _, err := g.Replace(ctx, storage, buf, stack.ID); err != nil {
+		t.Fatalf("Replace failed with %q", err)
+	}
+
+	g.SetLegalHold(stack.ID, "")
+
+	if _, err := g.Revert(ctx, storage, stack.ID, 0); !errors.Is(err, gallery.ErrLegalHold) {
+		t.Fatalf("Revert should fail with %q; got %q", gallery.ErrLegalHold, err)
+	}
+}
+
+func TestGallery_Delete_versions(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+	g.SetVersionLimit(1)
+
+	stack := uploadStack(t, g.Implementation, storage)
+
+	_, buf := imggen.ColoredRectangle(400, 300, color.RGBA{200, 0, 0, 0xff})
+	replaced, err := g.Replace(ctx, storage, buf, stack.ID)
+	if err != nil {
+		t.Fatalf("Replace failed with %q", err)
+	}
+
+	if err := g.Delete(ctx, storage, replaced); err != nil {
+		t.Fatalf("Delete failed with %q", err)
+	}
+
+	expectNoStorageFile(t, storage, exampleDisk, gallery.VersionPath(stack.ID, 0, examplePath))
+}
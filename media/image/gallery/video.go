@@ -0,0 +1,120 @@
+package gallery
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/video"
+)
+
+// VideoProbe is a Processor that probes the video of a video Stack and sets
+// the Width, Height, Duration and Codec of the video. Image Stacks are not
+// modified.
+//
+//	gallery.VideoProbe{Prober: video.FFmpeg{}}
+type VideoProbe struct {
+	Prober video.Prober
+}
+
+// Process runs the VideoProbe on the given ProcessorContext.
+func (p VideoProbe) Process(ctx *ProcessorContext) error {
+	stack := ctx.Stack()
+	if !stack.IsVideo() {
+		return nil
+	}
+
+	r, err := stack.Video.Reader(ctx, ctx.Storage())
+	if err != nil {
+		return fmt.Errorf("download video %q (%s): %w", stack.Video.Path, stack.Video.Disk, err)
+	}
+	defer r.Close()
+
+	ctx.cfg.logf("[VideoProbe] Probe video (StackID=%v Path=%v)", stack.ID, stack.Video.Path)
+
+	info, err := p.Prober.Probe(ctx, r)
+	if err != nil {
+		return fmt.Errorf("probe video: %w", err)
+	}
+
+	return ctx.Update(func(s Stack) Stack {
+		s.Video.Width = info.Width
+		s.Video.Height = info.Height
+		s.Video.Duration = info.Duration
+		s.Video.Codec = info.Codec
+		return s
+	})
+}
+
+// VideoThumbnailer is a Processor that extracts a frame of the video of a video
+// Stack and adds it as the original Image to the Stack. Processors that run
+// after the VideoThumbnailer process the extracted frame like the original
+// image of an image Stack, so the VideoThumbnailer must run before any other
+// Processor that needs the original Image:
+//
+//	gallery.ProcessingPipeline{
+//		gallery.VideoProbe{Prober: ff},
+//		gallery.VideoThumbnailer{Extractor: ff, At: time.Second},
+//		gallery.Resizer{"thumb": {Width: 240}},
+//	}
+//
+// The frame is stored as a PNG image next to the video. Image Stacks are not
+// modified.
+type VideoThumbnailer struct {
+	Extractor video.FrameExtractor
+
+	// At is the offset of the extracted frame. If the duration of the video is
+	// known (see VideoProbe) and At is not within the video, the first frame
+	// is extracted.
+	At time.Duration
+}
+
+// Process runs the VideoThumbnailer on the given ProcessorContext.
+func (t VideoThumbnailer) Process(ctx *ProcessorContext) error {
+	stack := ctx.Stack()
+	if !stack.IsVideo() {
+		return nil
+	}
+
+	v := *stack.Video
+	storage := ctx.Storage()
+
+	at := t.At
+	if at < 0 || (v.Duration > 0 && at >= v.Duration) {
+		at = 0
+	}
+
+	r, err := v.Reader(ctx, storage)
+	if err != nil {
+		return fmt.Errorf("download video %q (%s): %w", v.Path, v.Disk, err)
+	}
+	defer r.Close()
+
+	ctx.cfg.logf("[VideoThumbnailer] Extract frame (StackID=%v Path=%v At=%v)", stack.ID, v.Path, at)
+
+	var frame bytes.Buffer
+	if err := t.Extractor.Frame(ctx, &frame, r, at); err != nil {
+		return fmt.Errorf("extract frame: %w", err)
+	}
+
+	thumbPath := strings.TrimSuffix(v.Path, path.Ext(v.Path)) + ".png"
+	img := media.NewImage(0, 0, v.Name, v.Disk, thumbPath, 0)
+	if img, err = img.Upload(ctx, &frame, storage); err != nil {
+		return fmt.Errorf("upload frame: %w", err)
+	}
+	img.Tags = append([]string(nil), v.Tags...)
+
+	return ctx.Update(func(s Stack) Stack {
+		images := []Image{{Image: img, Original: true}}
+		for _, i := range s.Images {
+			if !i.Original {
+				images = append(images, i)
+			}
+		}
+		s.Images = images
+		return s
+	})
+}
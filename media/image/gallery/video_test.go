@@ -0,0 +1,150 @@
+package gallery_test
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/internal/videotest"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image"
+	"github.com/modernice/nice-cms/media/image/gallery"
+)
+
+const exampleVideoPath = "/example/example.mp4"
+
+func TestGallery_UploadVideo(t *testing.T) {
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+	g.SetDefaultTags("video")
+
+	stack, err := g.UploadVideo(context.Background(), storage, strings.NewReader("foo"), exampleName, exampleDisk, exampleVideoPath)
+	if err != nil {
+		t.Fatalf("UploadVideo failed with %q", err)
+	}
+
+	if !stack.IsVideo() {
+		t.Fatalf("Stack should be a video Stack")
+	}
+
+	if len(stack.Images) != 0 {
+		t.Fatalf("video Stack should have no Images before processing; has %d", len(stack.Images))
+	}
+
+	if stack.Video.Path != exampleVideoPath || stack.Video.Filesize != 3 {
+		t.Fatalf("Video should be stored at %q with 3 bytes; got %q with %d bytes", exampleVideoPath, stack.Video.Path, stack.Video.Filesize)
+	}
+
+	if !stack.Video.HasTag("video") {
+		t.Fatalf("Video should have the default tags of the Gallery")
+	}
+
+	test.Change(t, g, gallery.ImageUploaded, test.EventData(gallery.ImageUploadedData{Stack: stack}))
+}
+
+func TestGallery_Delete_video(t *testing.T) {
+	ctx := context.Background()
+	disk := media.MemoryDisk()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, disk))
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	stack, err := g.UploadVideo(ctx, storage, strings.NewReader("foo"), exampleName, exampleDisk, exampleVideoPath)
+	if err != nil {
+		t.Fatalf("UploadVideo failed with %q", err)
+	}
+
+	if err := g.Delete(ctx, storage, stack); err != nil {
+		t.Fatalf("Delete failed with %q", err)
+	}
+
+	if _, err := disk.Get(ctx, exampleVideoPath); !errors.Is(err, media.ErrFileNotFound) {
+		t.Fatalf("video should have been deleted from storage; Get returned %v", err)
+	}
+}
+
+func TestVideoThumbnailer_Process(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	_, frame := imggen.ColoredRectangle(320, 180, color.RGBA{255, 0, 0, 255})
+	info := videotest.Info()
+	ff := videotest.FFmpeg(t, info, frame.Bytes())
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	stack, err := g.UploadVideo(ctx, storage, strings.NewReader("foo"), exampleName, exampleDisk, exampleVideoPath)
+	if err != nil {
+		t.Fatalf("UploadVideo failed with %q", err)
+	}
+
+	pipe := gallery.ProcessingPipeline{
+		gallery.VideoProbe{Prober: ff},
+		gallery.VideoThumbnailer{Extractor: ff, At: info.Duration * 2},
+		gallery.Resizer{"thumb": {Width: 160}},
+	}
+
+	processed, err := pipe.Process(ctx, stack, image.NewEncoder(), storage)
+	if err != nil {
+		t.Fatalf("Process failed with %q", err)
+	}
+
+	v := processed.Video
+	if v.Width != info.Width || v.Height != info.Height || v.Duration != info.Duration || v.Codec != info.Codec {
+		t.Fatalf("Video should have metadata %+v; got %+v", info, *v)
+	}
+
+	org := processed.Original()
+	if org.Path != "/example/example.png" {
+		t.Fatalf("extracted frame should be stored at %q; is stored at %q", "/example/example.png", org.Path)
+	}
+
+	if org.Width != 320 || org.Height != 180 {
+		t.Fatalf("extracted frame should be 320x180; is %dx%d", org.Width, org.Height)
+	}
+
+	if len(processed.Images) != 2 {
+		t.Fatalf("processed Stack should have 2 Images; has %d", len(processed.Images))
+	}
+
+	if processed.Images[1].Size != "thumb" || processed.Images[1].Width != 160 {
+		t.Fatalf("frame should be resized to the %q size", "thumb")
+	}
+}
+
+func TestVideoThumbnailer_Process_imageStack(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(320, 180, color.RGBA{255, 0, 0, 255})
+	stack, err := g.Upload(ctx, storage, buf, exampleName, exampleDisk, examplePath)
+	if err != nil {
+		t.Fatalf("Upload failed with %q", err)
+	}
+
+	pipe := gallery.ProcessingPipeline{
+		gallery.VideoProbe{},
+		gallery.VideoThumbnailer{},
+	}
+
+	processed, err := pipe.Process(ctx, stack, image.NewEncoder(), storage)
+	if err != nil {
+		t.Fatalf("Process failed with %q", err)
+	}
+
+	if processed.IsVideo() || len(processed.Images) != 1 || processed.Original().Path != examplePath {
+		t.Fatalf("image Stack should not be modified by video Processors")
+	}
+}
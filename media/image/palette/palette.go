@@ -0,0 +1,133 @@
+// Package palette extracts the dominant colors of images. Frontends can use
+// the colors of an image to tint the surrounding page.
+package palette
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/disintegration/imaging"
+)
+
+const (
+	// maxSampleSize is the maximum width and height of the image that is used
+	// to extract the palette. Larger images are downscaled first.
+	maxSampleSize = 64
+
+	// bits is the number of bits per color channel that are used to group
+	// similar colors.
+	bits = 4
+)
+
+var (
+	// ErrEmptyImage is returned when extracting the palette of an empty image.
+	ErrEmptyImage = errors.New("empty image")
+
+	// ErrInvalidColor is returned by Parse for malformed hex colors.
+	ErrInvalidColor = errors.New("invalid color")
+)
+
+// Extract returns up to n dominant colors of img, ordered by how much of the
+// image they cover. Similar colors are grouped and averaged, and (mostly)
+// transparent pixels are ignored. If the image is fully transparent, an empty
+// palette is returned.
+func Extract(img image.Image, n int) ([]color.NRGBA, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("invalid number of colors: %d", n)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() <= 0 || bounds.Dy() <= 0 {
+		return nil, ErrEmptyImage
+	}
+
+	sample := imaging.Fit(img, maxSampleSize, maxSampleSize, imaging.Box)
+
+	type bucket struct {
+		r, g, b uint64
+		count   uint64
+	}
+
+	buckets := make(map[uint16]*bucket)
+	for y := sample.Rect.Min.Y; y < sample.Rect.Max.Y; y++ {
+		for x := sample.Rect.Min.X; x < sample.Rect.Max.X; x++ {
+			c := sample.NRGBAAt(x, y)
+			if c.A < 0x80 {
+				continue
+			}
+
+			key := uint16(c.R>>(8-bits))<<(2*bits) | uint16(c.G>>(8-bits))<<bits | uint16(c.B>>(8-bits))
+			b, ok := buckets[key]
+			if !ok {
+				b = &bucket{}
+				buckets[key] = b
+			}
+			b.r += uint64(c.R)
+			b.g += uint64(c.G)
+			b.b += uint64(c.B)
+			b.count++
+		}
+	}
+
+	keys := make([]uint16, 0, len(buckets))
+	for key := range buckets {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := buckets[keys[i]], buckets[keys[j]]
+		if a.count != b.count {
+			return a.count > b.count
+		}
+		return keys[i] < keys[j]
+	})
+
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+
+	out := make([]color.NRGBA, len(keys))
+	for i, key := range keys {
+		b := buckets[key]
+		out[i] = color.NRGBA{
+			R: uint8(b.r / b.count),
+			G: uint8(b.g / b.count),
+			B: uint8(b.b / b.count),
+			A: 0xff,
+		}
+	}
+
+	return out, nil
+}
+
+// Hex returns the hex representation ("#rrggbb") of c. The alpha channel is
+// ignored.
+func Hex(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B)
+}
+
+// Parse parses a hex color in the "#rrggbb" format.
+func Parse(hex string) (color.NRGBA, error) {
+	if len(hex) != 7 || hex[0] != '#' {
+		return color.NRGBA{}, fmt.Errorf("%w: %q", ErrInvalidColor, hex)
+	}
+	v, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("%w: %q", ErrInvalidColor, hex)
+	}
+	return color.NRGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+// Distance returns the euclidean distance between a and b in RGB space. The
+// distance is between 0 (equal colors) and ~441.67 (black and white).
+func Distance(a, b color.NRGBA) float64 {
+	dr := float64(a.R) - float64(b.R)
+	dg := float64(a.G) - float64(b.G)
+	db := float64(a.B) - float64(b.B)
+	return math.Sqrt(dr*dr + dg*dg + db*db)
+}
@@ -0,0 +1,90 @@
+package palette_test
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/modernice/nice-cms/media/image/palette"
+)
+
+func TestExtract(t *testing.T) {
+	red := color.NRGBA{R: 0xff, A: 0xff}
+	blue := color.NRGBA{B: 0xff, A: 0xff}
+
+	// 3/4 red, 1/4 blue
+	img := image.NewNRGBA(image.Rect(0, 0, 200, 100))
+	draw.Draw(img, img.Bounds(), image.NewUniform(red), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(150, 0, 200, 100), image.NewUniform(blue), image.Point{}, draw.Src)
+
+	colors, err := palette.Extract(img, 5)
+	if err != nil {
+		t.Fatalf("Extract failed with %q", err)
+	}
+
+	if len(colors) < 2 {
+		t.Fatalf("Extract should return at least 2 colors; got %v", colors)
+	}
+
+	if colors[0] != red {
+		t.Fatalf("most dominant color should be %v; is %v", red, colors[0])
+	}
+
+	if colors[1] != blue {
+		t.Fatalf("second most dominant color should be %v; is %v", blue, colors[1])
+	}
+
+	colors, err = palette.Extract(img, 1)
+	if err != nil {
+		t.Fatalf("Extract failed with %q", err)
+	}
+
+	if len(colors) != 1 {
+		t.Fatalf("Extract should return 1 color; got %v", colors)
+	}
+}
+
+func TestExtract_transparent(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+
+	colors, err := palette.Extract(img, 5)
+	if err != nil {
+		t.Fatalf("Extract failed with %q", err)
+	}
+
+	if len(colors) != 0 {
+		t.Fatalf("Extract should return no colors for a transparent image; got %v", colors)
+	}
+}
+
+func TestExtract_emptyImage(t *testing.T) {
+	if _, err := palette.Extract(image.NewNRGBA(image.Rect(0, 0, 0, 0)), 5); !errors.Is(err, palette.ErrEmptyImage) {
+		t.Fatalf("Extract should fail with %q; got %v", palette.ErrEmptyImage, err)
+	}
+}
+
+func TestHex_Parse(t *testing.T) {
+	c := color.NRGBA{R: 0x12, G: 0xab, B: 0xef, A: 0xff}
+
+	hex := palette.Hex(c)
+	if hex != "#12abef" {
+		t.Fatalf("Hex should return %q; got %q", "#12abef", hex)
+	}
+
+	parsed, err := palette.Parse(hex)
+	if err != nil {
+		t.Fatalf("Parse failed with %q", err)
+	}
+
+	if parsed != c {
+		t.Fatalf("Parse should return %v; got %v", c, parsed)
+	}
+
+	for _, invalid := range []string{"", "12abef", "#12abe", "#12abeg"} {
+		if _, err := palette.Parse(invalid); !errors.Is(err, palette.ErrInvalidColor) {
+			t.Fatalf("Parse(%q) should fail with %q; got %v", invalid, palette.ErrInvalidColor, err)
+		}
+	}
+}
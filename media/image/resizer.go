@@ -34,6 +34,25 @@ type Resizer map[string]Dimensions
 //	// resized["medium"].Bounds().Dx() == 1280
 //	// resized["large"].Bounds().Dx() == 1920
 func (r Resizer) Resize(img image.Image) map[string]image.Image {
+	src := NRGBA(img)
+	return transform(r, func(d Dimensions) *image.NRGBA {
+		return d.Resize(src)
+	})
+}
+
+// NRGBA returns the Image as an *image.NRGBA. Images of other types are
+// converted, which allows to convert the pixels of an Image only once when
+// it is transformed into multiple Dimensions.
+func NRGBA(img image.Image) *image.NRGBA {
+	if nrgba, ok := img.(*image.NRGBA); ok {
+		return nrgba
+	}
+	return imaging.Clone(img)
+}
+
+// transform calls fn in parallel for each of the given Dimensions and returns
+// the images returned by fn, keyed by the names of the Dimensions.
+func transform(dims map[string]Dimensions, fn func(Dimensions) *image.NRGBA) map[string]image.Image {
 	type result struct {
 		name string
 		img  *image.NRGBA
@@ -42,13 +61,13 @@ func (r Resizer) Resize(img image.Image) map[string]image.Image {
 	results := make(chan result)
 
 	var wg sync.WaitGroup
-	for name, d := range r {
+	for name, d := range dims {
 		wg.Add(1)
 		go func(name string, d Dimensions) {
 			defer wg.Done()
 			results <- result{
 				name: name,
-				img:  d.Resize(img),
+				img:  fn(d),
 			}
 		}(name, d)
 	}
@@ -50,3 +50,19 @@ func TestResizer_Resize(t *testing.T) {
 		}
 	}
 }
+
+func TestNRGBA(t *testing.T) {
+	rect, _ := imggen.ColoredRectangle(80, 60, color.RGBA{100, 100, 100, 0xff})
+
+	converted := image.NRGBA(rect)
+	if b := converted.Bounds(); b != rect.Bounds() {
+		t.Fatalf("converted image should have bounds %v; has %v", rect.Bounds(), b)
+	}
+	if got, want := converted.At(10, 10), color.NRGBAModel.Convert(rect.At(10, 10)); got != want {
+		t.Fatalf("converted pixel should be %v; is %v", want, got)
+	}
+
+	if image.NRGBA(converted) != converted {
+		t.Fatalf("NRGBA should return *image.NRGBA images as is")
+	}
+}
@@ -0,0 +1,133 @@
+package image
+
+import (
+	"image"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+// A Transformation changes the orientation of images. Besides the images
+// themselves, it transforms FocalPoints and Areas of images, so that they
+// point to the same content after the transformation.
+type Transformation interface {
+	// Valid returns whether the Transformation can be applied.
+	Valid() bool
+
+	// Apply transforms an Image.
+	Apply(image.Image) *image.NRGBA
+
+	// Point returns the position of a FocalPoint in the transformed image.
+	Point(FocalPoint) FocalPoint
+
+	// Area returns the position of an Area in the transformed image.
+	Area(Area) Area
+}
+
+// Rotation is a clockwise rotation of an image in degrees. Negative Rotations
+// rotate counter-clockwise. Only multiples of 90 degrees are valid.
+type Rotation int
+
+// Flip is the direction in which an image is flipped.
+type Flip string
+
+const (
+	// FlipHorizontal mirrors the left and right side of an image.
+	FlipHorizontal = Flip("horizontal")
+
+	// FlipVertical mirrors the top and bottom of an image.
+	FlipVertical = Flip("vertical")
+)
+
+// Valid returns whether r is a multiple of 90 degrees.
+func (r Rotation) Valid() bool {
+	return r%90 == 0
+}
+
+// Apply rotates an Image. Rotations that are not a multiple of 90 degrees are
+// rounded down to the previous multiple of 90 degrees.
+func (r Rotation) Apply(img image.Image) *image.NRGBA {
+	// imaging rotates counter-clockwise.
+	switch r.normalized() {
+	case 90:
+		return imaging.Rotate270(img)
+	case 180:
+		return imaging.Rotate180(img)
+	case 270:
+		return imaging.Rotate90(img)
+	default:
+		return imaging.Clone(img)
+	}
+}
+
+// Point returns the position of a FocalPoint in the rotated image.
+func (r Rotation) Point(p FocalPoint) FocalPoint {
+	switch r.normalized() {
+	case 90:
+		return FocalPoint{X: 100 - p.Y, Y: p.X}
+	case 180:
+		return FocalPoint{X: 100 - p.X, Y: 100 - p.Y}
+	case 270:
+		return FocalPoint{X: p.Y, Y: 100 - p.X}
+	default:
+		return p
+	}
+}
+
+// Area returns the position of an Area in the rotated image.
+func (r Rotation) Area(a Area) Area {
+	return transformArea(a, r.Point)
+}
+
+// normalized returns the Rotation as a clockwise rotation of 0, 90, 180 or 270
+// degrees.
+func (r Rotation) normalized() Rotation {
+	return (r%360 + 360) % 360 / 90 * 90
+}
+
+// Valid returns whether f is FlipHorizontal or FlipVertical.
+func (f Flip) Valid() bool {
+	return f == FlipHorizontal || f == FlipVertical
+}
+
+// Apply flips an Image. Invalid Flips return a copy of the Image.
+func (f Flip) Apply(img image.Image) *image.NRGBA {
+	switch f {
+	case FlipHorizontal:
+		return imaging.FlipH(img)
+	case FlipVertical:
+		return imaging.FlipV(img)
+	default:
+		return imaging.Clone(img)
+	}
+}
+
+// Point returns the position of a FocalPoint in the flipped image.
+func (f Flip) Point(p FocalPoint) FocalPoint {
+	switch f {
+	case FlipHorizontal:
+		return FocalPoint{X: 100 - p.X, Y: p.Y}
+	case FlipVertical:
+		return FocalPoint{X: p.X, Y: 100 - p.Y}
+	default:
+		return p
+	}
+}
+
+// Area returns the position of an Area in the flipped image.
+func (f Flip) Area(a Area) Area {
+	return transformArea(a, f.Point)
+}
+
+// transformArea transforms the corners of an Area using fn and returns the
+// Area between the transformed corners.
+func transformArea(a Area, fn func(FocalPoint) FocalPoint) Area {
+	p := fn(FocalPoint{X: a.X, Y: a.Y})
+	q := fn(FocalPoint{X: a.X + a.Width, Y: a.Y + a.Height})
+	return Area{
+		X:      math.Min(p.X, q.X),
+		Y:      math.Min(p.Y, q.Y),
+		Width:  math.Abs(q.X - p.X),
+		Height: math.Abs(q.Y - p.Y),
+	}
+}
@@ -0,0 +1,138 @@
+package image_test
+
+import (
+	stdimage "image"
+	"image/color"
+	"testing"
+
+	"github.com/modernice/nice-cms/media/image"
+)
+
+func TestRotation_Apply(t *testing.T) {
+	// Left half red, right half blue.
+	img := newHalvedImage(80, 40)
+
+	tests := []struct {
+		rotation image.Rotation
+		width    int
+		height   int
+		topLeft  color.NRGBA
+	}{
+		{rotation: 0, width: 80, height: 40, topLeft: red},
+		{rotation: 90, width: 40, height: 80, topLeft: red},
+		{rotation: 180, width: 80, height: 40, topLeft: blue},
+		{rotation: 270, width: 40, height: 80, topLeft: blue},
+		{rotation: -90, width: 40, height: 80, topLeft: blue},
+		{rotation: 450, width: 40, height: 80, topLeft: red},
+	}
+
+	for _, tt := range tests {
+		rotated := tt.rotation.Apply(img)
+
+		if b := rotated.Bounds(); b.Dx() != tt.width || b.Dy() != tt.height {
+			t.Fatalf("image rotated by %d degrees should be %dx%d; is %dx%d", tt.rotation, tt.width, tt.height, b.Dx(), b.Dy())
+		}
+
+		if got := color.NRGBAModel.Convert(rotated.At(0, 0)); got != tt.topLeft {
+			t.Fatalf("top-left pixel of the image rotated by %d degrees should be %v; is %v", tt.rotation, tt.topLeft, got)
+		}
+
+		// The focal point must point to the same pixel after the rotation.
+		focus := image.FocalPoint{X: 10, Y: 20}
+		p := tt.rotation.Point(focus)
+		want := color.NRGBAModel.Convert(img.At(8, 8))
+		if got := color.NRGBAModel.Convert(rotated.At(pixel(p, rotated.Bounds()))); got != want {
+			t.Fatalf("focal point %v rotated by %d degrees should point to %v; points to %v", focus, tt.rotation, want, got)
+		}
+	}
+}
+
+func TestRotation_Valid(t *testing.T) {
+	tests := map[image.Rotation]bool{
+		0:    true,
+		90:   true,
+		-270: true,
+		720:  true,
+		45:   false,
+		-91:  false,
+	}
+
+	for r, want := range tests {
+		if got := r.Valid(); got != want {
+			t.Errorf("Rotation(%d).Valid() should return %v; got %v", r, want, got)
+		}
+	}
+}
+
+func TestRotation_Area(t *testing.T) {
+	area := image.Area{X: 10, Y: 20, Width: 30, Height: 40}
+
+	tests := map[image.Rotation]image.Area{
+		0:   area,
+		90:  {X: 40, Y: 10, Width: 40, Height: 30},
+		180: {X: 60, Y: 40, Width: 30, Height: 40},
+		270: {X: 20, Y: 60, Width: 40, Height: 30},
+	}
+
+	for r, want := range tests {
+		if got := r.Area(area); got != want {
+			t.Errorf("Rotation(%d).Area(%v) should return %v; got %v", r, area, want, got)
+		}
+	}
+}
+
+func TestFlip(t *testing.T) {
+	img := newHalvedImage(80, 40)
+	area := image.Area{X: 10, Y: 20, Width: 30, Height: 40}
+
+	tests := []struct {
+		flip    image.Flip
+		topLeft color.NRGBA
+		point   image.FocalPoint
+		area    image.Area
+	}{
+		{
+			flip:    image.FlipHorizontal,
+			topLeft: blue,
+			point:   image.FocalPoint{X: 90, Y: 20},
+			area:    image.Area{X: 60, Y: 20, Width: 30, Height: 40},
+		},
+		{
+			flip:    image.FlipVertical,
+			topLeft: red,
+			point:   image.FocalPoint{X: 10, Y: 80},
+			area:    image.Area{X: 10, Y: 40, Width: 30, Height: 40},
+		},
+	}
+
+	for _, tt := range tests {
+		if !tt.flip.Valid() {
+			t.Fatalf("%q should be valid", tt.flip)
+		}
+
+		flipped := tt.flip.Apply(img)
+		if b := flipped.Bounds(); b.Dx() != 80 || b.Dy() != 40 {
+			t.Fatalf("flipped image should be 80x40; is %dx%d", b.Dx(), b.Dy())
+		}
+		if got := color.NRGBAModel.Convert(flipped.At(0, 0)); got != tt.topLeft {
+			t.Fatalf("top-left pixel of the %s flipped image should be %v; is %v", tt.flip, tt.topLeft, got)
+		}
+
+		if got := tt.flip.Point(image.FocalPoint{X: 10, Y: 20}); got != tt.point {
+			t.Fatalf("%s flipped focal point should be %v; is %v", tt.flip, tt.point, got)
+		}
+
+		if got := tt.flip.Area(area); got != tt.area {
+			t.Fatalf("%s flipped area should be %v; is %v", tt.flip, tt.area, got)
+		}
+	}
+
+	if image.Flip("diagonal").Valid() {
+		t.Fatalf("%q should be invalid", "diagonal")
+	}
+}
+
+// pixel returns the pixel of the FocalPoint p within the bounds b.
+func pixel(p image.FocalPoint, b stdimage.Rectangle) (int, int) {
+	return b.Min.X + int(p.X/100*float64(b.Dx())), b.Min.Y + int(p.Y/100*float64(b.Dy()))
+}
@@ -0,0 +1,367 @@
+package webp
+
+import (
+	"container/heap"
+	"sort"
+)
+
+const (
+	// maxCodeLength is the maximum length of a prefix code.
+	maxCodeLength = 15
+
+	// maxCodeLengthCodeLength is the maximum length of a code of the prefix
+	// code that encodes the code lengths.
+	maxCodeLengthCodeLength = 7
+
+	codeLengthRepeat     = 16
+	codeLengthZeros      = 17
+	codeLengthLongZeros  = 18
+	codeLengthAlphabet   = 19
+	initialRepeatedValue = 8
+)
+
+// codeLengthCodeOrder is the order in which the lengths of the code length
+// code are written.
+var codeLengthCodeOrder = [codeLengthAlphabet]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// bitWriter writes bits in least-significant-bit first order.
+type bitWriter struct {
+	buf   []byte
+	bits  uint64
+	nbits uint
+}
+
+func (w *bitWriter) write(v uint32, n uint) {
+	w.bits |= uint64(v) << w.nbits
+	w.nbits += n
+	for w.nbits >= 8 {
+		w.buf = append(w.buf, byte(w.bits))
+		w.bits >>= 8
+		w.nbits -= 8
+	}
+}
+
+func (w *bitWriter) writeBool(v bool) {
+	if v {
+		w.write(1, 1)
+		return
+	}
+	w.write(0, 1)
+}
+
+func (w *bitWriter) bytes() []byte {
+	if w.nbits > 0 {
+		w.buf = append(w.buf, byte(w.bits))
+		w.bits, w.nbits = 0, 0
+	}
+	return w.buf
+}
+
+// prefixCode is a canonical prefix code. The codes are stored bit-reversed so
+// that they can be written directly by a bitWriter.
+type prefixCode struct {
+	lengths []uint8
+	codes   []uint32
+}
+
+func (c prefixCode) write(w *bitWriter, symbol uint32) {
+	if n := c.lengths[symbol]; n > 0 {
+		w.write(c.codes[symbol], uint(n))
+	}
+}
+
+// writeSimpleCode writes a simple prefix code with a single symbol. Writing
+// the symbol of such a code requires zero bits.
+func writeSimpleCode(w *bitWriter, symbol uint32) {
+	w.write(1, 1) // simple code
+	w.write(0, 1) // 1 symbol
+	if symbol < 2 {
+		w.write(0, 1)
+		w.write(symbol, 1)
+		return
+	}
+	w.write(1, 1)
+	w.write(symbol, 8)
+}
+
+// writeCode writes the prefix code for the given symbol frequencies and
+// returns it.
+func writeCode(w *bitWriter, freqs []int) prefixCode {
+	var used []uint32
+	for symbol, f := range freqs {
+		if f > 0 {
+			used = append(used, uint32(symbol))
+		}
+	}
+
+	code := prefixCode{
+		lengths: make([]uint8, len(freqs)),
+		codes:   make([]uint32, len(freqs)),
+	}
+
+	switch {
+	case len(used) == 0:
+		writeSimpleCode(w, 0)
+		return code
+	case len(used) == 1 && used[0] < 256:
+		writeSimpleCode(w, used[0])
+		return code
+	case len(used) == 2 && used[1] < 256:
+		w.write(1, 1) // simple code
+		w.write(1, 1) // 2 symbols
+		w.write(1, 1) // 8-bit first symbol
+		w.write(used[0], 8)
+		w.write(used[1], 8)
+		code.lengths[used[0]], code.lengths[used[1]] = 1, 1
+		code.codes[used[0]], code.codes[used[1]] = 0, 1
+		return code
+	}
+
+	code.lengths = codeLengths(freqs, maxCodeLength)
+	code.codes = canonicalCodes(code.lengths)
+
+	w.write(0, 1) // normal code
+	writeCodeLengths(w, code.lengths)
+
+	return code
+}
+
+// writeCodeLengths writes the code lengths of a normal prefix code, encoded
+// with the code length code.
+func writeCodeLengths(w *bitWriter, lengths []uint8) {
+	tokens := codeLengthTokens(lengths)
+
+	freqs := make([]int, codeLengthAlphabet)
+	for _, t := range tokens {
+		freqs[t.symbol]++
+	}
+
+	clLengths := codeLengths(freqs, maxCodeLengthCodeLength)
+	clCodes := canonicalCodes(clLengths)
+
+	n := 4
+	for i := codeLengthAlphabet; i > 4; i-- {
+		if clLengths[codeLengthCodeOrder[i-1]] != 0 {
+			n = i
+			break
+		}
+	}
+
+	w.write(uint32(n-4), 4)
+	for _, symbol := range codeLengthCodeOrder[:n] {
+		w.write(uint32(clLengths[symbol]), 3)
+	}
+
+	w.write(0, 1) // max_symbol is the alphabet size
+
+	for _, t := range tokens {
+		w.write(clCodes[t.symbol], uint(clLengths[t.symbol]))
+		switch t.symbol {
+		case codeLengthRepeat:
+			w.write(t.extra, 2)
+		case codeLengthZeros:
+			w.write(t.extra, 3)
+		case codeLengthLongZeros:
+			w.write(t.extra, 7)
+		}
+	}
+}
+
+type codeLengthToken struct {
+	symbol uint8
+	extra  uint32
+}
+
+// codeLengthTokens run-length encodes code lengths into symbols of the code
+// length code.
+func codeLengthTokens(lengths []uint8) []codeLengthToken {
+	var tokens []codeLengthToken
+	prev := uint8(initialRepeatedValue)
+
+	for i := 0; i < len(lengths); {
+		l := lengths[i]
+		run := 1
+		for i+run < len(lengths) && lengths[i+run] == l {
+			run++
+		}
+		i += run
+
+		if l == 0 {
+			for run >= 3 {
+				switch {
+				case run >= 11:
+					n := min(run, 138)
+					tokens = append(tokens, codeLengthToken{codeLengthLongZeros, uint32(n - 11)})
+					run -= n
+				default:
+					n := min(run, 10)
+					tokens = append(tokens, codeLengthToken{codeLengthZeros, uint32(n - 3)})
+					run -= n
+				}
+			}
+			for ; run > 0; run-- {
+				tokens = append(tokens, codeLengthToken{symbol: 0})
+			}
+			continue
+		}
+
+		if l != prev {
+			tokens = append(tokens, codeLengthToken{symbol: l})
+			prev = l
+			run--
+		}
+
+		for run >= 3 {
+			n := min(run, 6)
+			tokens = append(tokens, codeLengthToken{codeLengthRepeat, uint32(n - 3)})
+			run -= n
+		}
+		for ; run > 0; run-- {
+			tokens = append(tokens, codeLengthToken{symbol: l})
+		}
+	}
+
+	return tokens
+}
+
+// codeLengths returns the code lengths of a Huffman code for the given symbol
+// frequencies, limited to maxLength bits. At least two symbols get a non-zero
+// code length, so that the resulting code is complete.
+func codeLengths(freqs []int, maxLength int) []uint8 {
+	f := make([]int, len(freqs))
+	copy(f, freqs)
+
+	var used int
+	for _, v := range f {
+		if v > 0 {
+			used++
+		}
+	}
+	for i := 0; used < 2 && i < len(f); i++ {
+		if f[i] == 0 {
+			f[i] = 1
+			used++
+		}
+	}
+
+	for {
+		lengths, max := huffmanLengths(f)
+		if max <= maxLength {
+			return lengths
+		}
+
+		// Flatten the distribution until the code fits into maxLength bits.
+		for i, v := range f {
+			if v > 0 {
+				f[i] = (v + 1) / 2
+			}
+		}
+	}
+}
+
+type huffmanNode struct {
+	freq        int
+	symbol      int
+	left, right *huffmanNode
+}
+
+type huffmanHeap []*huffmanNode
+
+func (h huffmanHeap) Len() int { return len(h) }
+
+func (h huffmanHeap) Less(i, j int) bool {
+	if h[i].freq == h[j].freq {
+		return h[i].symbol < h[j].symbol
+	}
+	return h[i].freq < h[j].freq
+}
+
+func (h huffmanHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *huffmanHeap) Push(x any) { *h = append(*h, x.(*huffmanNode)) }
+
+func (h *huffmanHeap) Pop() any {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+// huffmanLengths returns the (unlimited) Huffman code lengths for the given
+// frequencies and the maximum code length.
+func huffmanLengths(freqs []int) ([]uint8, int) {
+	h := &huffmanHeap{}
+	for symbol, f := range freqs {
+		if f > 0 {
+			*h = append(*h, &huffmanNode{freq: f, symbol: symbol})
+		}
+	}
+	heap.Init(h)
+
+	for next := len(freqs); h.Len() > 1; next++ {
+		a := heap.Pop(h).(*huffmanNode)
+		b := heap.Pop(h).(*huffmanNode)
+		heap.Push(h, &huffmanNode{freq: a.freq + b.freq, symbol: next, left: a, right: b})
+	}
+
+	lengths := make([]uint8, len(freqs))
+	var max int
+	var walk func(*huffmanNode, int)
+	walk = func(n *huffmanNode, depth int) {
+		if n.left == nil {
+			lengths[n.symbol] = uint8(depth)
+			if depth > max {
+				max = depth
+			}
+			return
+		}
+		walk(n.left, depth+1)
+		walk(n.right, depth+1)
+	}
+	walk(heap.Pop(h).(*huffmanNode), 0)
+
+	return lengths, max
+}
+
+// canonicalCodes returns the bit-reversed canonical codes for the given code
+// lengths.
+func canonicalCodes(lengths []uint8) []uint32 {
+	symbols := make([]int, 0, len(lengths))
+	for symbol, l := range lengths {
+		if l > 0 {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.SliceStable(symbols, func(i, j int) bool {
+		return lengths[symbols[i]] < lengths[symbols[j]]
+	})
+
+	codes := make([]uint32, len(lengths))
+	var code uint32
+	var prevLength uint8
+	for _, symbol := range symbols {
+		l := lengths[symbol]
+		code <<= l - prevLength
+		prevLength = l
+		codes[symbol] = reverse(code, l)
+		code++
+	}
+
+	return codes
+}
+
+func reverse(code uint32, n uint8) uint32 {
+	var out uint32
+	for i := uint8(0); i < n; i++ {
+		out = out<<1 | code&1
+		code >>= 1
+	}
+	return out
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
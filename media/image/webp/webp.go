@@ -0,0 +1,213 @@
+// Package webp implements a lossless WebP encoder.
+//
+// The encoder writes the VP8L bitstream using the subtract-green and predictor
+// transforms and a single set of prefix codes for the whole image. It does not
+// use backward references or color caches, which keeps the encoder simple at
+// the cost of compression ratio.
+package webp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+)
+
+const (
+	// maxDimension is the maximum width and height of a VP8L image.
+	maxDimension = 1 << 14
+
+	transformPredictor     = 0
+	transformSubtractGreen = 2
+
+	// predictorBits is the log-2 size of the blocks of the predictor
+	// transform. All blocks use the same predictor mode.
+	predictorBits = 9
+
+	// predictorModeL predicts a pixel from its left neighbour.
+	predictorModeL = 1
+)
+
+var (
+	// ErrInvalidDimensions is returned when encoding an image that is empty
+	// or too large for the WebP format.
+	ErrInvalidDimensions = errors.New("invalid image dimensions")
+)
+
+// Encode writes the image img to w in the lossless WebP format.
+func Encode(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if width < 1 || height < 1 || width > maxDimension || height > maxDimension {
+		return fmt.Errorf("%w: %dx%d", ErrInvalidDimensions, width, height)
+	}
+
+	argb, hasAlpha := pixels(img)
+
+	var bw bitWriter
+	bw.write(0x2f, 8)
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	bw.writeBool(hasAlpha)
+	bw.write(0, 3)
+
+	subtractGreen(argb)
+	bw.writeBool(true)
+	bw.write(transformSubtractGreen, 2)
+
+	residuals := predict(argb, width, height)
+	bw.writeBool(true)
+	bw.write(transformPredictor, 2)
+	bw.write(predictorBits-2, 3)
+	writePredictorImage(&bw)
+
+	bw.writeBool(false) // no more transforms
+	bw.writeBool(false) // no color cache
+	bw.writeBool(false) // no meta prefix codes
+	writeImageData(&bw, residuals)
+
+	return writeRIFF(w, bw.bytes())
+}
+
+// pixels returns the non-premultiplied ARGB pixels of img and whether any of
+// the pixels is not fully opaque.
+func pixels(img image.Image) ([]uint32, bool) {
+	nrgba, ok := img.(*image.NRGBA)
+	if !ok || nrgba.Rect.Min != (image.Point{}) {
+		b := img.Bounds()
+		nrgba = image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(nrgba, nrgba.Rect, img, b.Min, draw.Src)
+	}
+
+	width, height := nrgba.Rect.Dx(), nrgba.Rect.Dy()
+	out := make([]uint32, 0, width*height)
+	var hasAlpha bool
+	for y := 0; y < height; y++ {
+		row := nrgba.Pix[y*nrgba.Stride : y*nrgba.Stride+width*4]
+		for x := 0; x < width; x++ {
+			r, g, b, a := row[x*4], row[x*4+1], row[x*4+2], row[x*4+3]
+			if a != 0xff {
+				hasAlpha = true
+			}
+			out = append(out, uint32(a)<<24|uint32(r)<<16|uint32(g)<<8|uint32(b))
+		}
+	}
+
+	return out, hasAlpha
+}
+
+// subtractGreen subtracts the green channel from the red and blue channels of
+// each pixel.
+func subtractGreen(argb []uint32) {
+	for i, p := range argb {
+		g := (p >> 8) & 0xff
+		r := ((p >> 16) - g) & 0xff
+		b := (p - g) & 0xff
+		argb[i] = p&0xff00ff00 | r<<16 | b
+	}
+}
+
+// predict returns the residuals of the predictor transform. The top-left pixel
+// is predicted as opaque black, the top row from the left neighbour and the
+// left column from the top neighbour. All other pixels use predictorModeL.
+func predict(argb []uint32, width, height int) []uint32 {
+	out := make([]uint32, len(argb))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := y*width + x
+			var pred uint32
+			switch {
+			case x == 0 && y == 0:
+				pred = 0xff000000
+			case x == 0:
+				pred = argb[i-width]
+			default:
+				pred = argb[i-1]
+			}
+			out[i] = subPixels(argb[i], pred)
+		}
+	}
+	return out
+}
+
+// subPixels subtracts b from a per channel, modulo 256.
+func subPixels(a, b uint32) uint32 {
+	alphaGreen := 0x00ff00ff + (a & 0xff00ff00) - (b & 0xff00ff00)
+	redBlue := 0xff00ff00 + (a & 0x00ff00ff) - (b & 0x00ff00ff)
+	return alphaGreen&0xff00ff00 | redBlue&0x00ff00ff
+}
+
+// writePredictorImage writes the sub-image of the predictor transform. Every
+// block uses predictorModeL, so each prefix code has a single symbol and the
+// pixels of the sub-image need zero bits.
+func writePredictorImage(bw *bitWriter) {
+	bw.writeBool(false)                 // no color cache
+	writeSimpleCode(bw, predictorModeL) // green
+	writeSimpleCode(bw, 0)              // red
+	writeSimpleCode(bw, 0)              // blue
+	writeSimpleCode(bw, 0)              // alpha
+	writeSimpleCode(bw, 0)              // distance
+}
+
+const (
+	greenAlphabetSize = 256 + 24
+	colorAlphabetSize = 256
+)
+
+// writeImageData writes the prefix codes for the given pixels, followed by the
+// entropy-coded pixels.
+func writeImageData(bw *bitWriter, argb []uint32) {
+	green := make([]int, greenAlphabetSize)
+	red := make([]int, colorAlphabetSize)
+	blue := make([]int, colorAlphabetSize)
+	alpha := make([]int, colorAlphabetSize)
+	for _, p := range argb {
+		green[(p>>8)&0xff]++
+		red[(p>>16)&0xff]++
+		blue[p&0xff]++
+		alpha[p>>24]++
+	}
+
+	greenCode := writeCode(bw, green)
+	redCode := writeCode(bw, red)
+	blueCode := writeCode(bw, blue)
+	alphaCode := writeCode(bw, alpha)
+	writeSimpleCode(bw, 0) // distance
+
+	for _, p := range argb {
+		greenCode.write(bw, (p>>8)&0xff)
+		redCode.write(bw, (p>>16)&0xff)
+		blueCode.write(bw, p&0xff)
+		alphaCode.write(bw, p>>24)
+	}
+}
+
+// writeRIFF writes the VP8L bitstream in a RIFF container.
+func writeRIFF(w io.Writer, data []byte) error {
+	padded := len(data) + len(data)&1
+
+	header := make([]byte, 20)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(4+8+padded))
+	copy(header[8:], "WEBP")
+	copy(header[12:], "VP8L")
+	binary.LittleEndian.PutUint32(header[16:], uint32(len(data)))
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+
+	if padded != len(data) {
+		if _, err := w.Write([]byte{0}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
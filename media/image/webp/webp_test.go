@@ -0,0 +1,114 @@
+package webp_test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand"
+	"testing"
+
+	"github.com/modernice/nice-cms/media/image/webp"
+	xwebp "golang.org/x/image/webp"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name string
+		img  image.Image
+	}{
+		{name: "single pixel", img: uniform(1, 1, color.NRGBA{10, 20, 30, 0xff})},
+		{name: "uniform", img: uniform(64, 48, color.NRGBA{100, 150, 200, 0xff})},
+		{name: "gradient", img: gradient(300, 200)},
+		{name: "alpha", img: uniform(17, 33, color.NRGBA{100, 150, 200, 0x80})},
+		{name: "random", img: random(123, 77, true)},
+		{name: "random opaque", img: random(640, 3, false)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := webp.Encode(&buf, tt.img); err != nil {
+				t.Fatalf("Encode failed with %q", err)
+			}
+
+			decoded, err := xwebp.Decode(&buf)
+			if err != nil {
+				t.Fatalf("Decode failed with %q", err)
+			}
+
+			assertEqual(t, tt.img, decoded)
+		})
+	}
+}
+
+func TestEncode_subImage(t *testing.T) {
+	img := random(100, 100, true).SubImage(image.Rect(10, 20, 60, 45))
+
+	var buf bytes.Buffer
+	if err := webp.Encode(&buf, img); err != nil {
+		t.Fatalf("Encode failed with %q", err)
+	}
+
+	decoded, err := xwebp.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode failed with %q", err)
+	}
+
+	assertEqual(t, img, decoded)
+}
+
+func TestEncode_invalidDimensions(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 0, 10))
+
+	err := webp.Encode(&bytes.Buffer{}, img)
+	if !errors.Is(err, webp.ErrInvalidDimensions) {
+		t.Fatalf("Encode should fail with %q; got %v", webp.ErrInvalidDimensions, err)
+	}
+}
+
+func uniform(width, height int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Rect, image.NewUniform(c), image.Point{}, draw.Src)
+	return img
+}
+
+func gradient(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x), uint8(y), uint8(x + y), 0xff})
+		}
+	}
+	return img
+}
+
+func random(width, height int, alpha bool) *image.NRGBA {
+	rnd := rand.New(rand.NewSource(1))
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	rnd.Read(img.Pix)
+	if !alpha {
+		for i := 3; i < len(img.Pix); i += 4 {
+			img.Pix[i] = 0xff
+		}
+	}
+	return img
+}
+
+func assertEqual(t *testing.T, want, got image.Image) {
+	wb, gb := want.Bounds(), got.Bounds()
+	if wb.Dx() != gb.Dx() || wb.Dy() != gb.Dy() {
+		t.Fatalf("decoded image should have size %dx%d; has size %dx%d", wb.Dx(), wb.Dy(), gb.Dx(), gb.Dy())
+	}
+
+	for y := 0; y < wb.Dy(); y++ {
+		for x := 0; x < wb.Dx(); x++ {
+			wc := color.NRGBAModel.Convert(want.At(wb.Min.X+x, wb.Min.Y+y))
+			gc := color.NRGBAModel.Convert(got.At(gb.Min.X+x, gb.Min.Y+y))
+			if wc != gc {
+				t.Fatalf("pixel (%d, %d) should be %v; got %v", x, y, wc, gc)
+			}
+		}
+	}
+}
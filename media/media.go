@@ -5,6 +5,9 @@ package media
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"image"
 	"io"
@@ -17,6 +20,26 @@ type File struct {
 	Path     string   `json:"path"`
 	Filesize int      `json:"filesize"`
 	Tags     []string `json:"tags"`
+
+	// Checksum is the hex-encoded SHA-256 checksum of the contents of the
+	// file. Checksum is computed by Upload.
+	Checksum string `json:"checksum,omitempty"`
+
+	// StoragePath is the path of the contents of a file that was uploaded to
+	// a ContentDisk. Path is then only the logical path of the file. Use
+	// Location to get the actual storage path of a file.
+	StoragePath string `json:"storagePath,omitempty"`
+}
+
+// Checksum returns the hex-encoded SHA-256 checksum of the contents of r. The
+// returned checksum can be compared to the Checksum of an uploaded File to
+// detect duplicate uploads.
+func Checksum(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
 // NewFile returns a File with the specified data. NewFile ensures that returned
@@ -85,27 +108,47 @@ func (f File) HasTag(tags ...string) bool {
 	return true
 }
 
+// Location returns the path of the contents of the file on its disk, which is
+// StoragePath for content-addressed files and Path otherwise.
+func (f File) Location() string {
+	if f.StoragePath != "" {
+		return f.StoragePath
+	}
+	return f.Path
+}
+
 // Same returns true if other points to the same file as f (same Disk and Path).
 func (f File) Same(other File) bool {
 	return f.Disk == other.Disk && f.Path == other.Path
 }
 
-// Upload uploads the file to storage and returns the File with updated Filesize.
+// Upload uploads the file to storage and returns the File with updated Filesize
+// and Checksum. The contents of r are streamed to the StorageDisk if it
+// implements StreamDisk. If the StorageDisk is a ContentDisk, the contents are
+// stored under their content path, which is returned as StoragePath.
 func (f File) Upload(ctx context.Context, r io.Reader, storage Storage) (File, error) {
 	disk, err := f.storageDisk(storage)
 	if err != nil {
 		return f, err
 	}
 
-	b, err := io.ReadAll(r)
-	if err != nil {
-		return f, fmt.Errorf("read file: %w", err)
+	if cd, ok := disk.(ContentDisk); ok {
+		return f.uploadContent(ctx, r, cd)
 	}
 
-	if err := disk.Put(ctx, f.Path, b); err != nil {
+	f.StoragePath = ""
+
+	return f.put(ctx, r, disk, f.Path)
+}
+
+func (f File) put(ctx context.Context, r io.Reader, disk StorageDisk, path string) (File, error) {
+	h := sha256.New()
+	cr := &countReader{r: io.TeeReader(r, h)}
+	if err := PutReader(ctx, disk, path, cr); err != nil {
 		return f, fmt.Errorf("upload to %q storage: %w", f.Disk, err)
 	}
-	f.Filesize = len(b)
+	f.Filesize = int(cr.n)
+	f.Checksum = hex.EncodeToString(h.Sum(nil))
 
 	return f, nil
 }
@@ -116,7 +159,46 @@ func (f File) Download(ctx context.Context, storage Storage) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	return disk.Get(ctx, f.Path)
+	return disk.Get(ctx, f.Location())
+}
+
+// Reader returns an io.ReadCloser for the contents of the file. If the
+// StorageDisk of the file implements StreamDisk, the contents are streamed from
+// the disk. Callers must close the returned reader.
+func (f File) Reader(ctx context.Context, storage Storage) (io.ReadCloser, error) {
+	disk, err := f.storageDisk(storage)
+	if err != nil {
+		return nil, err
+	}
+	return GetReader(ctx, disk, f.Location())
+}
+
+// Open returns an io.ReadSeekCloser for the contents of the file. If the
+// StorageDisk of the file implements RangeDisk, the returned reader streams the
+// file from the current offset and only reopens the stream after a Seek, which
+// allows to efficiently serve range requests. Otherwise, the whole file is
+// downloaded upfront. Callers must close the returned reader.
+func (f File) Open(ctx context.Context, storage Storage) (io.ReadSeekCloser, error) {
+	disk, err := f.storageDisk(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	if rd, ok := disk.(RangeDisk); ok && f.Filesize > 0 {
+		return &rangeReader{
+			ctx:  ctx,
+			disk: rd,
+			path: f.Location(),
+			size: int64(f.Filesize),
+		}, nil
+	}
+
+	b, err := disk.Get(ctx, f.Location())
+	if err != nil {
+		return nil, err
+	}
+
+	return nopCloser{bytes.NewReader(b)}, nil
 }
 
 // Replace replaces the file in storage with the contents in r and returns the
@@ -125,8 +207,50 @@ func (f File) Replace(ctx context.Context, r io.Reader, storage Storage) (File,
 	return f.Upload(ctx, r, storage)
 }
 
-// Delete deletes the file from storage.
+// Relocate copies the contents of the file to the given disk and path of
+// storage and returns the relocated File. The file itself is not deleted. If
+// disk is the disk of a content-addressed file, only the logical path of the
+// file is changed, because its contents are not stored under its path.
+func (f File) Relocate(ctx context.Context, storage Storage, disk, path string) (File, error) {
+	if disk == f.Disk {
+		if f.StoragePath != "" {
+			f.Path = path
+			return f, nil
+		}
+
+		d, err := f.storageDisk(storage)
+		if err != nil {
+			return f, err
+		}
+
+		if err := Copy(ctx, d, f.Path, path); err != nil {
+			return f, fmt.Errorf("copy %q to %q: %w", f.Path, path, err)
+		}
+
+		f.Path = path
+		return f, nil
+	}
+
+	r, err := f.Reader(ctx, storage)
+	if err != nil {
+		return f, fmt.Errorf("read %q: %w", f.Path, err)
+	}
+	defer r.Close()
+
+	relocated := f
+	relocated.Disk = disk
+	relocated.Path = path
+
+	return relocated.Upload(ctx, r, storage)
+}
+
+// Delete deletes the file from storage. The contents of content-addressed
+// files are not deleted, because they may be shared with other files (see
+// ContentAddressed).
 func (f File) Delete(ctx context.Context, storage Storage) error {
+	if f.StoragePath != "" {
+		return nil
+	}
 	disk, err := f.storageDisk(storage)
 	if err != nil {
 		return err
@@ -142,6 +266,101 @@ func (f File) storageDisk(storage Storage) (StorageDisk, error) {
 	return disk, nil
 }
 
+type countReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error { return nil }
+
+// rangeReader reads a file of a RangeDisk through a single ranged stream from
+// the current offset to the end of the file. The stream is opened on the first
+// Read and reopened on the first Read after the offset was changed by Seek.
+type rangeReader struct {
+	ctx    context.Context
+	disk   RangeDisk
+	path   string
+	size   int64
+	off    int64
+	stream io.ReadCloser
+}
+
+func (r *rangeReader) Read(p []byte) (int, error) {
+	if r.off >= r.size {
+		return 0, io.EOF
+	}
+
+	if r.stream == nil {
+		stream, err := r.disk.GetRangeReader(r.ctx, r.path, r.off, r.size-r.off)
+		if err != nil {
+			return 0, err
+		}
+		r.stream = stream
+	}
+
+	n, err := r.stream.Read(p)
+	r.off += int64(n)
+
+	if errors.Is(err, io.EOF) {
+		if r.off < r.size {
+			return n, io.ErrUnexpectedEOF
+		}
+		if n > 0 {
+			return n, nil
+		}
+	}
+
+	return n, err
+}
+
+func (r *rangeReader) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = r.off + offset
+	case io.SeekEnd:
+		abs = r.size + offset
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+
+	if abs < 0 {
+		return 0, fmt.Errorf("negative position %d", abs)
+	}
+
+	if abs != r.off {
+		if err := r.Close(); err != nil {
+			return 0, err
+		}
+	}
+
+	r.off = abs
+
+	return abs, nil
+}
+
+// Close closes the current stream of the reader.
+func (r *rangeReader) Close() error {
+	if r.stream == nil {
+		return nil
+	}
+	err := r.stream.Close()
+	r.stream = nil
+	return err
+}
+
 // Image is storage image.
 type Image struct {
 	File
@@ -174,14 +393,9 @@ func (img Image) WithoutTag(tags ...string) Image {
 // Upload uploads the image to storage and returns the Image with updated
 // Filesize, Width and Height.
 func (img Image) Upload(ctx context.Context, r io.Reader, storage Storage) (Image, error) {
-	b, err := io.ReadAll(r)
-	if err != nil {
-		return img, fmt.Errorf("read image: %w", err)
-	}
-
-	br := bytes.NewReader(b)
-
-	cfg, _, err := image.DecodeConfig(br)
+	// Only the image header is buffered to decode the image config.
+	var header bytes.Buffer
+	cfg, _, err := image.DecodeConfig(io.TeeReader(r, &header))
 	if err != nil {
 		return img, fmt.Errorf("decode image: %w", err)
 	}
@@ -189,11 +403,7 @@ func (img Image) Upload(ctx context.Context, r io.Reader, storage Storage) (Imag
 	img.Width = cfg.Width
 	img.Height = cfg.Height
 
-	if _, err := br.Seek(0, io.SeekStart); err != nil {
-		return img, fmt.Errorf("reset buffer offset: %w", err)
-	}
-
-	f, err := img.File.Upload(ctx, br, storage)
+	f, err := img.File.Upload(ctx, io.MultiReader(&header, r), storage)
 	if err != nil {
 		return img, err
 	}
@@ -206,11 +416,12 @@ func (img Image) Upload(ctx context.Context, r io.Reader, storage Storage) (Imag
 // decoded Image and its format. If you want to download the image without
 // decoding it into an image.Image, use img.File.Download instead.
 func (img Image) Download(ctx context.Context, storage Storage) (image.Image, string, error) {
-	b, err := img.File.Download(ctx, storage)
+	r, err := img.File.Reader(ctx, storage)
 	if err != nil {
 		return nil, "", err
 	}
-	return image.Decode(bytes.NewReader(b))
+	defer r.Close()
+	return image.Decode(r)
 }
 
 // Replace replaces the image in Storage with the image in r and returns the
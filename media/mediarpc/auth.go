@@ -0,0 +1,291 @@
+package mediarpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	protomedia "github.com/modernice/nice-cms/proto/gen/media/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Method is a method of the media gRPC service.
+type Method string
+
+// Methods of the media gRPC service
+const (
+	LookupShelfByName        = Method("LookupShelfByName")
+	FetchShelf               = Method("FetchShelf")
+	ListDocuments            = Method("ListDocuments")
+	SearchDocuments          = Method("SearchDocuments")
+	ListShelfs               = Method("ListShelfs")
+	UploadDocument           = Method("UploadDocument")
+	ReplaceDocument          = Method("ReplaceDocument")
+	ReplaceDocumentDelta     = Method("ReplaceDocumentDelta")
+	LookupGalleryByName      = Method("LookupGalleryByName")
+	LookupGalleryStackByName = Method("LookupGalleryStackByName")
+	LookupGalleryStacksByTag = Method("LookupGalleryStacksByTag")
+	FetchGallery             = Method("FetchGallery")
+	StreamGalleryStacks      = Method("StreamGalleryStacks")
+	FetchGalleryIndex        = Method("FetchGalleryIndex")
+	ListStacks               = Method("ListStacks")
+	ListGalleries            = Method("ListGalleries")
+	FetchStack               = Method("FetchStack")
+	UploadImage              = Method("UploadImage")
+	ReplaceImage             = Method("ReplaceImage")
+	TagDocument              = Method("TagDocument")
+	UntagDocument            = Method("UntagDocument")
+	RenameDocument           = Method("RenameDocument")
+	DeleteDocument           = Method("DeleteDocument")
+	SortGallery              = Method("SortGallery")
+	TagStack                 = Method("TagStack")
+	UntagStack               = Method("UntagStack")
+	RenameStack              = Method("RenameStack")
+	DeleteStack              = Method("DeleteStack")
+)
+
+var (
+	// ReadMethods are the methods that don't modify shelfs or galleries.
+	ReadMethods = [...]Method{
+		LookupShelfByName,
+		FetchShelf,
+		ListDocuments,
+		SearchDocuments,
+		ListShelfs,
+		LookupGalleryByName,
+		LookupGalleryStackByName,
+		LookupGalleryStacksByTag,
+		FetchGallery,
+		StreamGalleryStacks,
+		FetchGalleryIndex,
+		ListStacks,
+		ListGalleries,
+		FetchStack,
+	}
+
+	// WriteMethods are the methods that modify shelfs or galleries.
+	WriteMethods = [...]Method{
+		UploadDocument,
+		ReplaceDocument,
+		ReplaceDocumentDelta,
+		UploadImage,
+		ReplaceImage,
+		TagDocument,
+		UntagDocument,
+		RenameDocument,
+		DeleteDocument,
+		SortGallery,
+		TagStack,
+		UntagStack,
+		RenameStack,
+		DeleteStack,
+	}
+)
+
+// ErrUnauthenticated is returned by an Authorizer if the caller is not
+// authenticated. Calls fail with codes.Unauthenticated.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// Authorizer authorizes calls of the media gRPC service. Authorize returns the
+// Context that is used for the call, so that an Authorizer can add the identity
+// of the caller using WithIdentity. Credentials of the caller can be read from
+// the incoming gRPC metadata (see metadata.FromIncomingContext).
+//
+// If Authorize returns an error, the call fails with codes.Unauthenticated if
+// the error wraps ErrUnauthenticated, with the code of the error if it is a gRPC
+// status error, or with codes.PermissionDenied otherwise.
+type Authorizer interface {
+	Authorize(context.Context, Method) (context.Context, error)
+}
+
+// AuthorizerFunc allows a function to be used as an Authorizer.
+type AuthorizerFunc func(context.Context, Method) (context.Context, error)
+
+// Authorize calls fn(ctx, method).
+func (fn AuthorizerFunc) Authorize(ctx context.Context, method Method) (context.Context, error) {
+	return fn(ctx, method)
+}
+
+// Option is a Server option.
+type Option func(*Server)
+
+// Authorize returns an Option that authorizes calls of the given methods using
+// the provided Authorizer. If no methods are provided, all methods are
+// authorized. Authorization is done by the interceptors returned by
+// Server.UnaryInterceptor and Server.StreamInterceptor, which must be installed
+// into the gRPC server (see Server.GRPCOptions). Calls of protected methods are
+// rejected if the interceptors are not installed.
+func Authorize(a Authorizer, methods ...Method) Option {
+	return func(s *Server) {
+		s.authorizer = a
+		s.protected = methods
+	}
+}
+
+// Audit returns an Option that calls fn after every call of the media gRPC
+// service with the Context of the call and the error that the call returned.
+// The Context contains the identity that was added by the Authorizer (see
+// IdentityFrom). Like authorization, auditing is done by the interceptors of the
+// Server.
+func Audit(fn func(ctx context.Context, method Method, err error)) Option {
+	return func(s *Server) {
+		s.audit = fn
+	}
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx that contains the identity of a caller.
+// Authorizers should add the identity of authorized callers so that it is
+// available to the audit function and the service implementation.
+func WithIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFrom returns the identity that was added to ctx using WithIdentity.
+func IdentityFrom(ctx context.Context) (string, bool) {
+	identity, ok := ctx.Value(identityKey{}).(string)
+	return identity, ok
+}
+
+type authorizedKey struct{}
+
+// GRPCOptions returns the gRPC server options that install the interceptors of
+// the Server:
+//
+//	srv := mediarpc.NewServer(..., mediarpc.Authorize(authorizer))
+//	grpcServer := grpc.NewServer(srv.GRPCOptions()...)
+//	srv.Register(grpcServer)
+func (s *Server) GRPCOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(s.StreamInterceptor()),
+	}
+}
+
+// UnaryInterceptor returns the gRPC interceptor that authorizes and audits
+// unary calls of the media gRPC service. Calls of other services are passed
+// through.
+func (s *Server) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method, ok := serviceMethod(info.FullMethod)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		ctx, err := s.intercept(ctx, method)
+		if err != nil {
+			s.report(ctx, method, err)
+			return nil, err
+		}
+
+		resp, err := handler(ctx, req)
+		s.report(ctx, method, err)
+		return resp, err
+	}
+}
+
+// StreamInterceptor returns the gRPC interceptor that authorizes and audits
+// streaming calls of the media gRPC service. Calls of other services are passed
+// through.
+func (s *Server) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		method, ok := serviceMethod(info.FullMethod)
+		if !ok {
+			return handler(srv, stream)
+		}
+
+		ctx, err := s.intercept(stream.Context(), method)
+		if err != nil {
+			s.report(ctx, method, err)
+			return err
+		}
+
+		err = handler(srv, contextStream{ServerStream: stream, ctx: ctx})
+		s.report(ctx, method, err)
+		return err
+	}
+}
+
+func (s *Server) intercept(ctx context.Context, method Method) (context.Context, error) {
+	if !s.isProtected(method) {
+		return ctx, nil
+	}
+
+	authorized, err := s.authorizer.Authorize(ctx, method)
+	if err != nil {
+		return ctx, authorizationError(err)
+	}
+	if authorized == nil {
+		authorized = ctx
+	}
+
+	return context.WithValue(authorized, authorizedKey{}, method), nil
+}
+
+func (s *Server) report(ctx context.Context, method Method, err error) {
+	if s.audit != nil {
+		s.audit(ctx, method, err)
+	}
+}
+
+// authorized returns an error if method is protected but the call was not
+// authorized by one of the interceptors of s.
+func (s *Server) authorized(ctx context.Context, method Method) error {
+	if !s.isProtected(method) {
+		return nil
+	}
+	if m, ok := ctx.Value(authorizedKey{}).(Method); !ok || m != method {
+		return status.Errorf(codes.Unauthenticated, "%s requires authorization but the mediarpc interceptors are not installed", method)
+	}
+	return nil
+}
+
+func (s *Server) isProtected(method Method) bool {
+	if s.authorizer == nil {
+		return false
+	}
+	if len(s.protected) == 0 {
+		return true
+	}
+	for _, m := range s.protected {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func authorizationError(err error) error {
+	if errors.Is(err, ErrUnauthenticated) {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(codes.PermissionDenied, err.Error())
+}
+
+// serviceMethod returns the Method of a full gRPC method name
+// ("/nicecms.media.v1.MediaService/FetchGallery"), or false if the method is
+// not a method of the media gRPC service.
+func serviceMethod(fullMethod string) (Method, bool) {
+	prefix := "/" + protomedia.MediaService_ServiceDesc.ServiceName + "/"
+	if !strings.HasPrefix(fullMethod, prefix) {
+		return "", false
+	}
+	return Method(strings.TrimPrefix(fullMethod, prefix)), true
+}
+
+// contextStream is a grpc.ServerStream with a custom Context.
+type contextStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s contextStream) Context() context.Context {
+	return s.ctx
+}
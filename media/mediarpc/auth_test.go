@@ -0,0 +1,158 @@
+package mediarpc_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/internal/grpctest"
+	"github.com/modernice/nice-cms/internal/testutil"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/mediarpc"
+	protomedia "github.com/modernice/nice-cms/proto/gen/media/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// tokenAuthorizer authorizes callers with a "user" metadata header. Only the
+// "admin" user may call write methods.
+var tokenAuthorizer = mediarpc.AuthorizerFunc(func(ctx context.Context, method mediarpc.Method) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	users := md.Get("user")
+	if len(users) == 0 {
+		return ctx, mediarpc.ErrUnauthenticated
+	}
+
+	for _, m := range mediarpc.WriteMethods {
+		if m == method && users[0] != "admin" {
+			return ctx, fmt.Errorf("%s may not call %s", users[0], method)
+		}
+	}
+
+	return mediarpc.WithIdentity(ctx, users[0]), nil
+})
+
+type auditEntry struct {
+	identity string
+	method   mediarpc.Method
+	code     codes.Code
+}
+
+func TestAuthorize(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, _, setupAggregates := testutil.Goes()
+	shelfs := document.GoesRepository(setupAggregates())
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create("foo")
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save shelf: %v", err)
+	}
+
+	var mux sync.Mutex
+	var audit []auditEntry
+
+	srv := mediarpc.NewServer(
+		shelfs, nil, nil, nil,
+		media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk())),
+		mediarpc.Authorize(tokenAuthorizer),
+		mediarpc.Audit(func(ctx context.Context, method mediarpc.Method, err error) {
+			identity, _ := mediarpc.IdentityFrom(ctx)
+			mux.Lock()
+			defer mux.Unlock()
+			audit = append(audit, auditEntry{identity: identity, method: method, code: status.Code(err)})
+		}),
+	)
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) { srv.Register(s) }, srv.GRPCOptions()...)
+	conn := dial()
+	defer conn.Close()
+
+	client := mediarpc.NewClient(conn)
+
+	as := func(user string) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, "user", user)
+	}
+
+	if _, err := client.FetchShelf(ctx, shelf.ID); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("FetchShelf should fail with %q; got %q", codes.Unauthenticated, status.Code(err))
+	}
+
+	if _, err := client.FetchShelf(as("guest"), shelf.ID); err != nil {
+		t.Fatalf("FetchShelf failed with %q", err)
+	}
+
+	if _, err := client.UploadDocument(as("guest"), shelf.ID, strings.NewReader("foo"), "", "foo", "foo-disk", "/foo.txt"); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("UploadDocument should fail with %q; got %q", codes.PermissionDenied, status.Code(err))
+	}
+
+	if _, err := client.UploadDocument(as("admin"), shelf.ID, strings.NewReader("foo"), "", "foo", "foo-disk", "/foo.txt"); err != nil {
+		t.Fatalf("UploadDocument failed with %q", err)
+	}
+
+	want := []auditEntry{
+		{method: mediarpc.FetchShelf, code: codes.Unauthenticated},
+		{identity: "guest", method: mediarpc.FetchShelf, code: codes.OK},
+		{method: mediarpc.UploadDocument, code: codes.PermissionDenied},
+		{identity: "admin", method: mediarpc.UploadDocument, code: codes.OK},
+	}
+
+	mux.Lock()
+	defer mux.Unlock()
+
+	if fmt.Sprint(audit) != fmt.Sprint(want) {
+		t.Fatalf("audit log should be\n\n%v\n\ngot\n\n%v", want, audit)
+	}
+}
+
+func TestAuthorize_methods(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, _, setupAggregates := testutil.Goes()
+	shelfs := document.GoesRepository(setupAggregates())
+
+	srv := mediarpc.NewServer(shelfs, nil, nil, nil, nil, mediarpc.Authorize(tokenAuthorizer, mediarpc.WriteMethods[:]...))
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) { srv.Register(s) }, srv.GRPCOptions()...)
+	conn := dial()
+	defer conn.Close()
+
+	client := mediarpc.NewClient(conn)
+
+	if _, err := client.FetchShelf(ctx, uuid.New()); status.Code(err) != codes.NotFound {
+		t.Fatalf("unprotected methods should not be authorized; got %q", status.Code(err))
+	}
+
+	if _, err := client.UploadDocument(ctx, uuid.New(), strings.NewReader("foo"), "", "foo", "foo-disk", "/foo.txt"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("UploadDocument should fail with %q; got %q", codes.Unauthenticated, status.Code(err))
+	}
+}
+
+func TestAuthorize_withoutInterceptors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	authorizer := mediarpc.AuthorizerFunc(func(ctx context.Context, _ mediarpc.Method) (context.Context, error) {
+		return ctx, nil
+	})
+	srv := mediarpc.NewServer(nil, nil, nil, nil, nil, mediarpc.Authorize(authorizer))
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, srv)
+	})
+	conn := dial()
+	defer conn.Close()
+
+	if _, err := mediarpc.NewClient(conn).FetchShelf(ctx, uuid.New()); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("protected methods should be rejected without interceptors; got %q", status.Code(err))
+	}
+}
@@ -0,0 +1,167 @@
+package mediarpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// CallOption configures the calls of the Client. CallOptions are configured
+// for all calls of a Client using CallDefaults, or for the calls that use a
+// specific Context using WithCallOptions.
+type CallOption func(*callConfig)
+
+type callConfig struct {
+	timeout time.Duration
+	tries   int
+	backoff time.Duration
+	codes   []codes.Code
+	md      []string
+}
+
+// Timeout returns a CallOption that cancels a call after the given duration,
+// including its retries. A deadline of the Context that is earlier than the
+// timeout takes precedence. Default is no timeout.
+func Timeout(d time.Duration) CallOption {
+	return func(cfg *callConfig) {
+		cfg.timeout = d
+	}
+}
+
+// Retry returns a CallOption that retries a call up to tries times in total if
+// it fails with a transient error (see RetryCodes). The delay between the tries
+// starts at backoff and is doubled after every retry. Uploads are streamed
+// from an io.Reader and are therefore never retried. Default is no retry.
+func Retry(tries int, backoff time.Duration) CallOption {
+	return func(cfg *callConfig) {
+		cfg.tries = tries
+		cfg.backoff = backoff
+	}
+}
+
+// RetryCodes returns a CallOption that configures the status codes that are
+// retried (see Retry). Default is codes.Unavailable.
+func RetryCodes(codes ...codes.Code) CallOption {
+	return func(cfg *callConfig) {
+		cfg.codes = codes
+	}
+}
+
+// Metadata returns a CallOption that adds the given key-value pairs to the
+// outgoing gRPC metadata of a call, e.g. to pass an authorization token or a
+// request id to the server:
+//
+//	ctx = mediarpc.WithCallOptions(ctx, mediarpc.Metadata("x-request-id", id))
+func Metadata(kv ...string) CallOption {
+	return func(cfg *callConfig) {
+		cfg.md = append(cfg.md, kv...)
+	}
+}
+
+// CallDefaults returns a ClientOption that configures all calls of the Client.
+// CallOptions that are added to the Context of a call using WithCallOptions
+// override the defaults.
+func CallDefaults(opts ...CallOption) ClientOption {
+	return func(c *Client) {
+		c.callOpts = append(c.callOpts, opts...)
+	}
+}
+
+type callOptionsKey struct{}
+
+// WithCallOptions returns a copy of ctx that configures the calls of the
+// Client that use the returned Context. The options are applied after the
+// defaults of the Client (see CallDefaults) and after the options that have
+// already been added to ctx.
+func WithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	prev, _ := ctx.Value(callOptionsKey{}).([]CallOption)
+	return context.WithValue(ctx, callOptionsKey{}, append(prev[:len(prev):len(prev)], opts...))
+}
+
+// clientConn is the ClientConn of a Client. It applies the CallOptions of the
+// Client and of the Context of a call, and adds grpcOpts to every call.
+type clientConn struct {
+	grpc.ClientConnInterface
+
+	grpcOpts []grpc.CallOption
+	defaults []CallOption
+}
+
+func (conn *clientConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	cfg := conn.config(ctx)
+	ctx, cancel := cfg.context(ctx)
+	defer cancel()
+
+	delay := cfg.backoff
+	for try := 1; ; try++ {
+		err := conn.ClientConnInterface.Invoke(ctx, method, args, reply, append(conn.grpcOpts, opts...)...)
+		if err == nil || try >= cfg.tries || !cfg.retryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+func (conn *clientConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx, cancel := conn.config(ctx).context(ctx)
+
+	stream, err := conn.ClientConnInterface.NewStream(ctx, desc, method, append(conn.grpcOpts, opts...)...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// The Context of the stream is done when the stream is finished.
+	go func() {
+		<-stream.Context().Done()
+		cancel()
+	}()
+
+	return stream, nil
+}
+
+func (conn *clientConn) config(ctx context.Context) callConfig {
+	cfg := callConfig{codes: []codes.Code{codes.Unavailable}}
+	for _, opt := range conn.defaults {
+		opt(&cfg)
+	}
+	opts, _ := ctx.Value(callOptionsKey{}).([]CallOption)
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// context returns the Context of a call with the configured timeout and
+// metadata.
+func (cfg callConfig) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if len(cfg.md) > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, cfg.md...)
+	}
+	if cfg.timeout > 0 {
+		return context.WithTimeout(ctx, cfg.timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+func (cfg callConfig) retryable(err error) bool {
+	code := status.Code(err)
+	for _, c := range cfg.codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
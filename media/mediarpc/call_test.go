@@ -0,0 +1,146 @@
+package mediarpc_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/internal/grpctest"
+	"github.com/modernice/nice-cms/media/mediarpc"
+	protocommon "github.com/modernice/nice-cms/proto/gen/common/v1"
+	protomedia "github.com/modernice/nice-cms/proto/gen/media/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestClient_Retry(t *testing.T) {
+	srv := &flakyServer{failures: 2}
+	client := newFlakyClient(t, srv, mediarpc.CallDefaults(mediarpc.Retry(3, time.Millisecond)))
+
+	if _, err := client.FetchShelf(context.Background(), uuid.New()); err != nil {
+		t.Fatalf("FetchShelf should be retried; failed with %q", err)
+	}
+
+	if calls := srv.count(); calls != 3 {
+		t.Fatalf("FetchShelf should be called %d times; was called %d times", 3, calls)
+	}
+
+	srv.reset(2)
+	ctx := mediarpc.WithCallOptions(context.Background(), mediarpc.Retry(2, time.Millisecond))
+
+	if _, err := client.FetchShelf(ctx, uuid.New()); status.Code(err) != codes.Unavailable {
+		t.Fatalf("FetchShelf should fail with %s after 2 tries; got %v", codes.Unavailable, err)
+	}
+
+	srv.reset(2)
+	ctx = mediarpc.WithCallOptions(context.Background(), mediarpc.RetryCodes(codes.Aborted))
+
+	if _, err := client.FetchShelf(ctx, uuid.New()); status.Code(err) != codes.Unavailable {
+		t.Fatalf("FetchShelf should not retry codes that are not configured; got %v", err)
+	}
+
+	if calls := srv.count(); calls != 1 {
+		t.Fatalf("FetchShelf should be called %d time; was called %d times", 1, calls)
+	}
+}
+
+func TestClient_Timeout(t *testing.T) {
+	srv := &flakyServer{block: true}
+	client := newFlakyClient(t, srv, mediarpc.CallDefaults(mediarpc.Timeout(time.Hour)))
+
+	ctx := mediarpc.WithCallOptions(context.Background(), mediarpc.Timeout(50*time.Millisecond))
+
+	start := time.Now()
+	if _, err := client.FetchShelf(ctx, uuid.New()); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("FetchShelf should fail with %s; got %v", codes.DeadlineExceeded, err)
+	}
+
+	if dur := time.Since(start); dur > time.Second {
+		t.Fatalf("FetchShelf should time out after %v; took %v", 50*time.Millisecond, dur)
+	}
+}
+
+func TestClient_Metadata(t *testing.T) {
+	srv := &flakyServer{}
+	client := newFlakyClient(t, srv, mediarpc.CallDefaults(mediarpc.Metadata("app", "foo")))
+
+	ctx := mediarpc.WithCallOptions(context.Background(), mediarpc.Metadata("x-request-id", "bar"))
+
+	if _, err := client.FetchShelf(ctx, uuid.New()); err != nil {
+		t.Fatalf("FetchShelf failed with %q", err)
+	}
+
+	md := srv.metadata()
+	if got := md.Get("app"); len(got) != 1 || got[0] != "foo" {
+		t.Fatalf("metadata %q should be %q; is %v", "app", "foo", got)
+	}
+	if got := md.Get("x-request-id"); len(got) != 1 || got[0] != "bar" {
+		t.Fatalf("metadata %q should be %q; is %v", "x-request-id", "bar", got)
+	}
+}
+
+func newFlakyClient(t *testing.T, srv *flakyServer, opts ...mediarpc.ClientOption) *mediarpc.Client {
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, srv)
+	})
+	conn := dial()
+	t.Cleanup(func() { conn.Close() })
+	return mediarpc.NewClient(conn, opts...)
+}
+
+// flakyServer fails the first calls of FetchShelf with codes.Unavailable. If
+// block is true, FetchShelf blocks until the call is canceled.
+type flakyServer struct {
+	protomedia.UnimplementedMediaServiceServer
+
+	mux      sync.Mutex
+	failures int
+	block    bool
+	calls    int
+	md       metadata.MD
+}
+
+func (s *flakyServer) FetchShelf(ctx context.Context, id *protocommon.UUID) (*protomedia.Shelf, error) {
+	s.mux.Lock()
+	s.calls++
+	s.md, _ = metadata.FromIncomingContext(ctx)
+	fail := s.failures > 0
+	if fail {
+		s.failures--
+	}
+	s.mux.Unlock()
+
+	if s.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	if fail {
+		return nil, status.Error(codes.Unavailable, "unavailable")
+	}
+
+	return &protomedia.Shelf{Id: id, Name: "foo"}, nil
+}
+
+func (s *flakyServer) count() int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.calls
+}
+
+func (s *flakyServer) reset(failures int) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.failures = failures
+	s.calls = 0
+}
+
+func (s *flakyServer) metadata() metadata.MD {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.md
+}
@@ -1,6 +1,7 @@
 package mediarpc
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -8,7 +9,9 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/nice-cms/internal/slice"
 	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/delta"
 	"github.com/modernice/nice-cms/media/document"
 	"github.com/modernice/nice-cms/media/image/gallery"
 	protocommon "github.com/modernice/nice-cms/proto/gen/common/v1"
@@ -16,7 +19,9 @@ import (
 	"github.com/modernice/nice-cms/proto/ptypes/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding/gzip"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 // Server is the media gRPC server.
@@ -25,11 +30,18 @@ type Server struct {
 
 	shelfs    document.Repository
 	docLookup *document.Lookup
+	docIndex  *document.Index
+	search    *document.SearchService
 
 	galleries     gallery.Repository
 	galleryLookup *gallery.Lookup
+	galleryIndex  *gallery.Index
 
 	storage media.Storage
+
+	authorizer Authorizer
+	protected  []Method
+	audit      func(context.Context, Method, error)
 }
 
 // Register registers the server into a ServiceRegistrar.
@@ -44,18 +56,54 @@ func NewServer(
 	galleries gallery.Repository,
 	galleryLookup *gallery.Lookup,
 	storage media.Storage,
+	opts ...Option,
 ) *Server {
-	return &Server{
+	s := &Server{
 		shelfs:        shelfs,
 		docLookup:     docLookup,
 		galleries:     galleries,
 		galleryLookup: galleryLookup,
 		storage:       storage,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// WithDocumentIndex returns an Option that serves ListDocuments from the given
+// Index instead of fetching the Shelf from the repository. The Index must be
+// projected by the caller.
+func WithDocumentIndex(idx *document.Index) Option {
+	return func(s *Server) {
+		s.docIndex = idx
+	}
+}
+
+// WithSearchService returns an Option that serves SearchDocuments from the
+// given SearchService. The SearchService must be projected by the caller.
+// Without a SearchService, SearchDocuments fails with codes.Unimplemented.
+func WithSearchService(svc *document.SearchService) Option {
+	return func(s *Server) {
+		s.search = svc
+	}
+}
+
+// WithGalleryIndex returns an Option that serves ListStacks from the given
+// Index instead of fetching the Gallery from the repository. The Index must be
+// projected by the caller.
+func WithGalleryIndex(idx *gallery.Index) Option {
+	return func(s *Server) {
+		s.galleryIndex = idx
+	}
 }
 
 // LookupShelfByName looks up the UUID of a shelf by its name.
 func (s *Server) LookupShelfByName(ctx context.Context, req *protocommon.NameLookup) (*protocommon.LookupResp, error) {
+	if err := s.authorized(ctx, LookupShelfByName); err != nil {
+		return nil, err
+	}
+
 	id, ok := s.docLookup.ShelfName(req.GetName())
 	if !ok {
 		return &protocommon.LookupResp{Found: false}, nil
@@ -68,6 +116,10 @@ func (s *Server) LookupShelfByName(ctx context.Context, req *protocommon.NameLoo
 
 // UploadDocument uploads a document to a shelf.
 func (s *Server) UploadDocument(stream protomedia.MediaService_UploadDocumentServer) error {
+	if err := s.authorized(stream.Context(), UploadDocument); err != nil {
+		return err
+	}
+
 	req, err := stream.Recv()
 	if err != nil {
 		return err
@@ -138,6 +190,10 @@ func (s *Server) UploadDocument(stream protomedia.MediaService_UploadDocumentSer
 
 // ReplaceDocument replaces a document within a shelf.
 func (s *Server) ReplaceDocument(stream protomedia.MediaService_ReplaceDocumentServer) error {
+	if err := s.authorized(stream.Context(), ReplaceDocument); err != nil {
+		return err
+	}
+
 	req, err := stream.Recv()
 	if err != nil {
 		return err
@@ -206,15 +262,225 @@ func (s *Server) ReplaceDocument(stream protomedia.MediaService_ReplaceDocumentS
 	return stream.SendAndClose(ptypes.ShelfDocumentProto(doc))
 }
 
+// ReplaceDocumentDelta replaces a document within a shelf with the file that is
+// reconstructed from the stored file and the uploaded delta (see package
+// delta). If the delta was not computed against the stored file, the call
+// fails with codes.FailedPrecondition.
+func (s *Server) ReplaceDocumentDelta(stream protomedia.MediaService_ReplaceDocumentDeltaServer) error {
+	if err := s.authorized(stream.Context(), ReplaceDocumentDelta); err != nil {
+		return err
+	}
+
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	meta := req.GetMetadata()
+	if meta == nil {
+		return status.Error(codes.InvalidArgument, "missing metadata")
+	}
+
+	var buf bytes.Buffer
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		chunk := req.GetChunk()
+		if chunk == nil {
+			return status.Error(codes.InvalidArgument, "missing chunk")
+		}
+		buf.Write(chunk)
+	}
+
+	d, err := delta.Decode(&buf)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var doc document.Document
+	if err := s.shelfs.Use(stream.Context(), ptypes.UUID(meta.GetShelfId()), func(shelf *document.Shelf) error {
+		doc, err = shelf.ReplaceDelta(stream.Context(), s.storage, d, ptypes.UUID(meta.GetDocumentId()))
+		return err
+	}); err != nil {
+		if errors.Is(err, delta.ErrBaseMismatch) {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return err
+	}
+
+	return stream.SendAndClose(ptypes.ShelfDocumentProto(doc))
+}
+
 func (s *Server) FetchShelf(ctx context.Context, id *protocommon.UUID) (*protomedia.Shelf, error) {
+	if err := s.authorized(ctx, FetchShelf); err != nil {
+		return nil, err
+	}
+
 	shelf, err := s.shelfs.Fetch(ctx, ptypes.UUID(id))
 	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if shelf.AggregateVersion() == 0 {
+		return nil, status.Error(codes.NotFound, document.ErrShelfNotFound.Error())
 	}
 	return ptypes.ShelfProto(shelf.JSON()), nil
 }
 
+// ListDocuments returns a page of the documents of a shelf, optionally
+// filtered by tags and a search term.
+func (s *Server) ListDocuments(ctx context.Context, req *protomedia.ListDocumentsReq) (*protomedia.DocumentPage, error) {
+	if err := s.authorized(ctx, ListDocuments); err != nil {
+		return nil, err
+	}
+
+	id := ptypes.UUID(req.GetShelfId())
+	q := ptypes.DocumentQuery(req)
+
+	if s.docIndex != nil {
+		page, err := s.docIndex.List(id, q)
+		if errors.Is(err, document.ErrShelfNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return ptypes.DocumentPageProto(page), nil
+	}
+
+	shelf, err := s.shelfs.Fetch(ctx, id)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if shelf.AggregateVersion() == 0 {
+		return nil, status.Error(codes.NotFound, document.ErrShelfNotFound.Error())
+	}
+	return ptypes.DocumentPageProto(shelf.List(q)), nil
+}
+
+// SearchDocuments searches the documents of all shelfs.
+func (s *Server) SearchDocuments(ctx context.Context, req *protomedia.SearchDocumentsReq) (*protomedia.SearchDocumentsResp, error) {
+	if err := s.authorized(ctx, SearchDocuments); err != nil {
+		return nil, err
+	}
+
+	if s.search == nil {
+		return nil, status.Error(codes.Unimplemented, "document search is not configured")
+	}
+
+	results, err := s.search.Search(ptypes.SearchQuery(req))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return ptypes.SearchResultsProto(results), nil
+}
+
+// ListShelfs returns a page of the UUIDs and names of all shelfs, sorted by
+// name.
+func (s *Server) ListShelfs(ctx context.Context, req *protomedia.ListShelfsReq) (*protomedia.ShelfList, error) {
+	if err := s.authorized(ctx, ListShelfs); err != nil {
+		return nil, err
+	}
+
+	return ptypes.ShelfListProto(s.docLookup.Shelfs(int(req.GetOffset()), int(req.GetLimit()))), nil
+}
+
+// TagDocument adds tags to a document.
+func (s *Server) TagDocument(ctx context.Context, req *protomedia.TagDocumentReq) (*protomedia.ShelfDocument, error) {
+	if err := s.authorized(ctx, TagDocument); err != nil {
+		return nil, err
+	}
+
+	var doc document.Document
+	if err := s.useShelf(ctx, ptypes.UUID(req.GetShelfId()), func(shelf *document.Shelf) (err error) {
+		doc, err = shelf.Tag(ptypes.UUID(req.GetDocumentId()), req.GetTags()...)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return ptypes.ShelfDocumentProto(doc), nil
+}
+
+// UntagDocument removes tags from a document.
+func (s *Server) UntagDocument(ctx context.Context, req *protomedia.TagDocumentReq) (*protomedia.ShelfDocument, error) {
+	if err := s.authorized(ctx, UntagDocument); err != nil {
+		return nil, err
+	}
+
+	var doc document.Document
+	if err := s.useShelf(ctx, ptypes.UUID(req.GetShelfId()), func(shelf *document.Shelf) (err error) {
+		doc, err = shelf.Untag(ptypes.UUID(req.GetDocumentId()), req.GetTags()...)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return ptypes.ShelfDocumentProto(doc), nil
+}
+
+// RenameDocument renames a document. The unique name of the document is not
+// changed.
+func (s *Server) RenameDocument(ctx context.Context, req *protomedia.RenameDocumentReq) (*protomedia.ShelfDocument, error) {
+	if err := s.authorized(ctx, RenameDocument); err != nil {
+		return nil, err
+	}
+
+	var doc document.Document
+	if err := s.useShelf(ctx, ptypes.UUID(req.GetShelfId()), func(shelf *document.Shelf) (err error) {
+		doc, err = shelf.RenameDocument(ptypes.UUID(req.GetDocumentId()), req.GetName())
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return ptypes.ShelfDocumentProto(doc), nil
+}
+
+// DeleteDocument moves a document to the trash of its shelf, like the DELETE
+// route of a document of the media server.
+func (s *Server) DeleteDocument(ctx context.Context, req *protomedia.DeleteDocumentReq) (*emptypb.Empty, error) {
+	if err := s.authorized(ctx, DeleteDocument); err != nil {
+		return nil, err
+	}
+
+	if err := s.useShelf(ctx, ptypes.UUID(req.GetShelfId()), func(shelf *document.Shelf) error {
+		_, err := shelf.Trash(ptypes.UUID(req.GetDocumentId()))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// useShelf calls fn with the Shelf with the given UUID and saves the Shelf.
+// Errors are returned as gRPC status errors.
+func (s *Server) useShelf(ctx context.Context, id uuid.UUID, fn func(*document.Shelf) error) error {
+	err := s.shelfs.Use(ctx, id, func(shelf *document.Shelf) error {
+		if shelf.AggregateVersion() == 0 {
+			return document.ErrShelfNotFound
+		}
+		return fn(shelf)
+	})
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, document.ErrShelfNotFound), errors.Is(err, document.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, document.ErrLegalHold):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
 func (s *Server) LookupGalleryByName(ctx context.Context, req *protocommon.NameLookup) (*protocommon.LookupResp, error) {
+	if err := s.authorized(ctx, LookupGalleryByName); err != nil {
+		return nil, err
+	}
+
 	id, ok := s.galleryLookup.GalleryName(req.GetName())
 	return &protocommon.LookupResp{
 		Found: ok,
@@ -223,6 +489,10 @@ func (s *Server) LookupGalleryByName(ctx context.Context, req *protocommon.NameL
 }
 
 func (s *Server) LookupGalleryStackByName(ctx context.Context, req *protomedia.LookupGalleryStackByNameReq) (*protocommon.LookupResp, error) {
+	if err := s.authorized(ctx, LookupGalleryStackByName); err != nil {
+		return nil, err
+	}
+
 	id, ok := s.galleryLookup.StackName(ptypes.UUID(req.GetGalleryId()), req.GetName())
 	return &protocommon.LookupResp{
 		Found: ok,
@@ -230,7 +500,27 @@ func (s *Server) LookupGalleryStackByName(ctx context.Context, req *protomedia.L
 	}, nil
 }
 
+func (s *Server) LookupGalleryStacksByTag(ctx context.Context, req *protomedia.LookupGalleryStacksByTagReq) (*protomedia.LookupGalleryStacksByTagResp, error) {
+	if err := s.authorized(ctx, LookupGalleryStacksByTag); err != nil {
+		return nil, err
+	}
+
+	ids, ok := s.galleryLookup.StacksByTag(ptypes.UUID(req.GetGalleryId()), req.GetTag())
+	resp := &protomedia.LookupGalleryStacksByTagResp{
+		Found:    ok,
+		StackIds: make([]*protocommon.UUID, len(ids)),
+	}
+	for i, id := range ids {
+		resp.StackIds[i] = ptypes.UUIDProto(id)
+	}
+	return resp, nil
+}
+
 func (s *Server) UploadImage(stream protomedia.MediaService_UploadImageServer) error {
+	if err := s.authorized(stream.Context(), UploadImage); err != nil {
+		return err
+	}
+
 	req, err := stream.Recv()
 	if err != nil {
 		return err
@@ -293,7 +583,12 @@ func (s *Server) UploadImage(stream protomedia.MediaService_UploadImageServer) e
 		return status.Errorf(codes.NotFound, "Failed to fetch gallery: %v", err)
 	}
 
-	stack, err := g.Upload(ctx, s.storage, pr, meta.GetName(), meta.GetDisk(), meta.GetPath())
+	upload := g.Upload
+	if meta.GetVideo() {
+		upload = g.UploadVideo
+	}
+
+	stack, err := upload(ctx, s.storage, pr, meta.GetName(), meta.GetDisk(), meta.GetPath())
 	if err != nil {
 		return status.Errorf(codes.Internal, "Failed to upload image: %v", err)
 	}
@@ -310,6 +605,10 @@ func (s *Server) UploadImage(stream protomedia.MediaService_UploadImageServer) e
 }
 
 func (s *Server) ReplaceImage(stream protomedia.MediaService_ReplaceImageServer) error {
+	if err := s.authorized(stream.Context(), ReplaceImage); err != nil {
+		return err
+	}
+
 	req, err := stream.Recv()
 	if err != nil {
 		return err
@@ -379,19 +678,303 @@ func (s *Server) ReplaceImage(stream protomedia.MediaService_ReplaceImageServer)
 }
 
 func (s *Server) FetchGallery(ctx context.Context, id *protocommon.UUID) (*protomedia.Gallery, error) {
-	g, err := s.galleries.Fetch(ctx, ptypes.UUID(id))
+	if err := s.authorized(ctx, FetchGallery); err != nil {
+		return nil, err
+	}
+
+	g, err := s.fetchGallery(ctx, ptypes.UUID(id))
 	if err != nil {
-		return nil, status.Error(codes.NotFound, err.Error())
+		return nil, err
 	}
 	return ptypes.GalleryProto(g.JSON()), nil
 }
 
+// defaultStreamPageSize is the default number of stacks per message of
+// StreamGalleryStacks.
+const defaultStreamPageSize = 100
+
+// StreamGalleryStacks streams a gallery in multiple messages. The first message
+// contains the gallery without its stacks and the first page of stacks; the
+// following messages contain the remaining stacks in pages of the requested
+// size.
+func (s *Server) StreamGalleryStacks(req *protomedia.StreamGalleryStacksReq, stream protomedia.MediaService_StreamGalleryStacksServer) error {
+	ctx := stream.Context()
+	if err := s.authorized(ctx, StreamGalleryStacks); err != nil {
+		return err
+	}
+
+	g, err := s.fetchGallery(ctx, ptypes.UUID(req.GetGalleryId()))
+	if err != nil {
+		return err
+	}
+
+	size := int(req.GetPageSize())
+	if size <= 0 {
+		size = defaultStreamPageSize
+	}
+
+	j := g.JSON()
+	stacks := j.Stacks
+	j.Stacks = nil
+
+	chunk := &protomedia.GalleryStacksChunk{Gallery: ptypes.GalleryProto(j)}
+	for {
+		n := size
+		if n > len(stacks) {
+			n = len(stacks)
+		}
+		chunk.Stacks = slice.Map(stacks[:n], ptypes.GalleryStackProto).([]*protomedia.Stack)
+		stacks = stacks[n:]
+
+		if err := stream.Send(chunk); err != nil {
+			return err
+		}
+
+		if len(stacks) == 0 {
+			return nil
+		}
+		chunk = &protomedia.GalleryStacksChunk{}
+	}
+}
+
+// FetchGalleryIndex returns the index of a gallery, which contains only the
+// summaries of its stacks.
+func (s *Server) FetchGalleryIndex(ctx context.Context, id *protocommon.UUID) (*protomedia.GalleryIndex, error) {
+	if err := s.authorized(ctx, FetchGalleryIndex); err != nil {
+		return nil, err
+	}
+
+	g, err := s.fetchGallery(ctx, ptypes.UUID(id))
+	if err != nil {
+		return nil, err
+	}
+	return ptypes.GalleryIndexProto(g.JSON().Index()), nil
+}
+
+// ListStacks returns a page of the stacks of a gallery, optionally filtered by
+// tags.
+func (s *Server) ListStacks(ctx context.Context, req *protomedia.ListStacksReq) (*protomedia.StackPage, error) {
+	if err := s.authorized(ctx, ListStacks); err != nil {
+		return nil, err
+	}
+
+	id := ptypes.UUID(req.GetGalleryId())
+	q := ptypes.StackQuery(req)
+
+	if s.galleryIndex != nil {
+		page, err := s.galleryIndex.List(id, q)
+		if errors.Is(err, gallery.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return ptypes.StackPageProto(page), nil
+	}
+
+	g, err := s.fetchGallery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return ptypes.StackPageProto(g.List(q)), nil
+}
+
+// ListGalleries returns a page of the UUIDs and names of all galleries, sorted
+// by name.
+func (s *Server) ListGalleries(ctx context.Context, req *protomedia.ListGalleriesReq) (*protomedia.GalleryList, error) {
+	if err := s.authorized(ctx, ListGalleries); err != nil {
+		return nil, err
+	}
+
+	return ptypes.GalleryListProto(s.galleryLookup.Galleries(int(req.GetOffset()), int(req.GetLimit()))), nil
+}
+
+// FetchStack returns a single stack of a gallery.
+func (s *Server) FetchStack(ctx context.Context, req *protomedia.FetchStackReq) (*protomedia.Stack, error) {
+	if err := s.authorized(ctx, FetchStack); err != nil {
+		return nil, err
+	}
+
+	g, err := s.fetchGallery(ctx, ptypes.UUID(req.GetGalleryId()))
+	if err != nil {
+		return nil, err
+	}
+	stack, err := g.Stack(ptypes.UUID(req.GetStackId()))
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return ptypes.GalleryStackProto(stack), nil
+}
+
+// SortGallery sorts the stacks of a gallery.
+func (s *Server) SortGallery(ctx context.Context, req *protomedia.SortGalleryReq) (*emptypb.Empty, error) {
+	if err := s.authorized(ctx, SortGallery); err != nil {
+		return nil, err
+	}
+
+	sorting := slice.Map(req.GetSorting(), ptypes.UUID).([]uuid.UUID)
+	if err := s.useGallery(ctx, ptypes.UUID(req.GetId()), func(g *gallery.Gallery) error {
+		g.Sort(sorting)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// TagStack adds tags to the images of a stack.
+func (s *Server) TagStack(ctx context.Context, req *protomedia.TagStackReq) (*protomedia.Stack, error) {
+	if err := s.authorized(ctx, TagStack); err != nil {
+		return nil, err
+	}
+
+	var stack gallery.Stack
+	if err := s.useGallery(ctx, ptypes.UUID(req.GetGalleryId()), func(g *gallery.Gallery) error {
+		s, err := g.Stack(ptypes.UUID(req.GetStackId()))
+		if err != nil {
+			return err
+		}
+		stack, err = g.Tag(ctx, s, req.GetTags()...)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return ptypes.GalleryStackProto(stack), nil
+}
+
+// UntagStack removes tags from the images of a stack.
+func (s *Server) UntagStack(ctx context.Context, req *protomedia.TagStackReq) (*protomedia.Stack, error) {
+	if err := s.authorized(ctx, UntagStack); err != nil {
+		return nil, err
+	}
+
+	var stack gallery.Stack
+	if err := s.useGallery(ctx, ptypes.UUID(req.GetGalleryId()), func(g *gallery.Gallery) error {
+		s, err := g.Stack(ptypes.UUID(req.GetStackId()))
+		if err != nil {
+			return err
+		}
+		stack, err = g.Untag(ctx, s, req.GetTags()...)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return ptypes.GalleryStackProto(stack), nil
+}
+
+// RenameStack renames the images of a stack.
+func (s *Server) RenameStack(ctx context.Context, req *protomedia.RenameStackReq) (*protomedia.Stack, error) {
+	if err := s.authorized(ctx, RenameStack); err != nil {
+		return nil, err
+	}
+
+	var stack gallery.Stack
+	if err := s.useGallery(ctx, ptypes.UUID(req.GetGalleryId()), func(g *gallery.Gallery) (err error) {
+		stack, err = g.RenameStack(ctx, ptypes.UUID(req.GetStackId()), req.GetName())
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return ptypes.GalleryStackProto(stack), nil
+}
+
+// DeleteStack moves a stack to the trash of its gallery, like the DELETE
+// route of a stack of the media server.
+func (s *Server) DeleteStack(ctx context.Context, req *protomedia.DeleteStackReq) (*emptypb.Empty, error) {
+	if err := s.authorized(ctx, DeleteStack); err != nil {
+		return nil, err
+	}
+
+	if err := s.useGallery(ctx, ptypes.UUID(req.GetGalleryId()), func(g *gallery.Gallery) error {
+		_, err := g.Trash(ptypes.UUID(req.GetStackId()))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// useGallery calls fn with the Gallery with the given UUID and saves the
+// Gallery. Errors are returned as gRPC status errors.
+func (s *Server) useGallery(ctx context.Context, id uuid.UUID, fn func(*gallery.Gallery) error) error {
+	err := s.galleries.Use(ctx, id, func(g *gallery.Gallery) error {
+		if !g.Created() {
+			return gallery.ErrNotFound
+		}
+		return fn(g)
+	})
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gallery.ErrNotFound), errors.Is(err, gallery.ErrStackNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, gallery.ErrLegalHold):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func (s *Server) fetchGallery(ctx context.Context, id uuid.UUID) (*gallery.Gallery, error) {
+	g, err := s.galleries.Fetch(ctx, id)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if g.AggregateVersion() == 0 {
+		return nil, status.Error(codes.NotFound, gallery.ErrNotFound.Error())
+	}
+	return g, nil
+}
+
 // Client is the media gRPC client.
-type Client struct{ client protomedia.MediaServiceClient }
+type Client struct {
+	client protomedia.MediaServiceClient
+
+	chunkSize int
+	grpcOpts  []grpc.CallOption
+	callOpts  []CallOption
+}
+
+// ClientOption is an option for the Client.
+type ClientOption func(*Client)
+
+// ChunkSize returns a ClientOption that sets the size of the chunks in which
+// files are uploaded. Larger chunks need fewer messages but more memory per
+// upload. Default is DefaultChunkSize. The server rejects messages larger than
+// its maximum receive size (4 MB by default), so the chunk size should stay
+// well below it.
+func ChunkSize(n int) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.chunkSize = n
+		}
+	}
+}
+
+// Gzip returns a ClientOption that compresses the requests of the Client using
+// gzip. Compression pays off for compressible files like documents, but not
+// for already compressed files like JPEG or PNG images. Servers that import
+// this package accept gzip-compressed requests.
+func Gzip() ClientOption {
+	return func(c *Client) {
+		c.grpcOpts = append(c.grpcOpts, grpc.UseCompressor(gzip.Name))
+	}
+}
 
 // NewClient returns the media gRPC client.
-func NewClient(conn grpc.ClientConnInterface) *Client {
-	return &Client{client: protomedia.NewMediaServiceClient(conn)}
+func NewClient(conn grpc.ClientConnInterface, opts ...ClientOption) *Client {
+	c := &Client{chunkSize: DefaultChunkSize}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.client = protomedia.NewMediaServiceClient(&clientConn{
+		ClientConnInterface: conn,
+		// Limit the capacity, so that concurrent calls don't append to the
+		// same array.
+		grpcOpts: c.grpcOpts[:len(c.grpcOpts):len(c.grpcOpts)],
+		defaults: c.callOpts,
+	})
+	return c
 }
 
 // LookupShelfByName looks up the UUID of a shelf by its name.
@@ -429,23 +1012,15 @@ func (c *Client) UploadDocument(
 		return document.Document{}, fmt.Errorf("send metadata: %w", stream.RecvMsg(nil))
 	}
 
-	buf := make([]byte, 512)
-L:
-	for {
-		n, err := r.Read(buf)
-		if err == io.EOF {
-			break L
-		}
-
-		if err != nil {
-			return document.Document{}, err
-		}
-
-		if err := stream.Send(&protomedia.UploadDocumentReq{
-			UploadData: &protomedia.UploadDocumentReq_Chunk{Chunk: buf[:n]},
-		}); err != nil {
+	if err := c.sendChunks(ctx, r, func(chunk []byte) error {
+		return stream.Send(&protomedia.UploadDocumentReq{
+			UploadData: &protomedia.UploadDocumentReq_Chunk{Chunk: chunk},
+		})
+	}); err != nil {
+		if errors.Is(err, errSend) {
 			return document.Document{}, fmt.Errorf("send chunk: %w", stream.RecvMsg(nil))
 		}
+		return document.Document{}, err
 	}
 
 	resp, err := stream.CloseAndRecv()
@@ -474,27 +1049,67 @@ func (c *Client) ReplaceDocument(ctx context.Context, shelfID, documentID uuid.U
 		return document.Document{}, fmt.Errorf("send metadata: %w", stream.RecvMsg(nil))
 	}
 
-	buf := make([]byte, 512)
-L:
-	for {
-		n, err := r.Read(buf)
-		if err == io.EOF {
-			break L
+	if err := c.sendChunks(ctx, r, func(chunk []byte) error {
+		return stream.Send(&protomedia.ReplaceDocumentReq{
+			ReplaceData: &protomedia.ReplaceDocumentReq_Chunk{Chunk: chunk},
+		})
+	}); err != nil {
+		if errors.Is(err, errSend) {
+			return document.Document{}, fmt.Errorf("send chunk: %w", stream.RecvMsg(nil))
 		}
+		return document.Document{}, err
+	}
 
-		if err != nil {
-			return document.Document{}, err
-		}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return document.Document{}, err
+	}
+
+	return ptypes.ShelfDocument(resp), nil
+}
+
+// ReplaceDocumentDelta replaces a document within a shelf by uploading only
+// the delta between the stored file and the new file. If the delta was not
+// computed against the stored file, an error that wraps delta.ErrBaseMismatch
+// is returned and the full file should be uploaded using ReplaceDocument.
+func (c *Client) ReplaceDocumentDelta(ctx context.Context, shelfID, documentID uuid.UUID, d delta.Delta) (document.Document, error) {
+	b, err := d.MarshalBinary()
+	if err != nil {
+		return document.Document{}, fmt.Errorf("encode delta: %w", err)
+	}
 
-		if err := stream.Send(&protomedia.ReplaceDocumentReq{
-			ReplaceData: &protomedia.ReplaceDocumentReq_Chunk{Chunk: buf[:n]},
-		}); err != nil {
+	stream, err := c.client.ReplaceDocumentDelta(ctx)
+	if err != nil {
+		return document.Document{}, err
+	}
+
+	if err := stream.Send(&protomedia.ReplaceDocumentReq{
+		ReplaceData: &protomedia.ReplaceDocumentReq_Metadata{
+			Metadata: &protomedia.ReplaceDocumentReq_ReplaceDocumentMetadata{
+				ShelfId:    ptypes.UUIDProto(shelfID),
+				DocumentId: ptypes.UUIDProto(documentID),
+			},
+		},
+	}); err != nil {
+		return document.Document{}, fmt.Errorf("send metadata: %w", stream.RecvMsg(nil))
+	}
+
+	if err := c.sendChunks(ctx, bytes.NewReader(b), func(chunk []byte) error {
+		return stream.Send(&protomedia.ReplaceDocumentReq{
+			ReplaceData: &protomedia.ReplaceDocumentReq_Chunk{Chunk: chunk},
+		})
+	}); err != nil {
+		if errors.Is(err, errSend) {
 			return document.Document{}, fmt.Errorf("send chunk: %w", stream.RecvMsg(nil))
 		}
+		return document.Document{}, err
 	}
 
 	resp, err := stream.CloseAndRecv()
 	if err != nil {
+		if status.Code(err) == codes.FailedPrecondition {
+			return document.Document{}, fmt.Errorf("%w: %v", delta.ErrBaseMismatch, err)
+		}
 		return document.Document{}, err
 	}
 
@@ -509,6 +1124,89 @@ func (c *Client) FetchShelf(ctx context.Context, id uuid.UUID) (document.JSONShe
 	return ptypes.Shelf(resp), nil
 }
 
+// ListDocuments fetches a page of the documents of a shelf that match q.
+func (c *Client) ListDocuments(ctx context.Context, shelfID uuid.UUID, q document.Query) (document.DocumentPage, error) {
+	resp, err := c.client.ListDocuments(ctx, ptypes.ListDocumentsReqProto(shelfID, q))
+	if err != nil {
+		return document.DocumentPage{}, err
+	}
+	return ptypes.DocumentPage(resp), nil
+}
+
+// SearchDocuments searches the documents of all shelfs.
+func (c *Client) SearchDocuments(ctx context.Context, q document.SearchQuery) (document.SearchResults, error) {
+	resp, err := c.client.SearchDocuments(ctx, ptypes.SearchDocumentsReqProto(q))
+	if err != nil {
+		return document.SearchResults{}, err
+	}
+	return ptypes.SearchResults(resp), nil
+}
+
+// ListShelfs returns a page of the UUIDs and names of all shelfs, sorted by
+// name. If limit is 0, all shelfs after offset are returned.
+func (c *Client) ListShelfs(ctx context.Context, offset, limit int) (document.ShelfList, error) {
+	resp, err := c.client.ListShelfs(ctx, &protomedia.ListShelfsReq{
+		Offset: int64(offset),
+		Limit:  int64(limit),
+	})
+	if err != nil {
+		return document.ShelfList{}, err
+	}
+	return ptypes.ShelfList(resp), nil
+}
+
+// TagDocument adds tags to a document and returns the updated document.
+func (c *Client) TagDocument(ctx context.Context, shelfID, documentID uuid.UUID, tags []string) (document.Document, error) {
+	resp, err := c.client.TagDocument(ctx, &protomedia.TagDocumentReq{
+		ShelfId:    ptypes.UUIDProto(shelfID),
+		DocumentId: ptypes.UUIDProto(documentID),
+		Tags:       tags,
+	})
+	if err != nil {
+		return document.Document{}, err
+	}
+	return ptypes.ShelfDocument(resp), nil
+}
+
+// UntagDocument removes tags from a document and returns the updated document.
+func (c *Client) UntagDocument(ctx context.Context, shelfID, documentID uuid.UUID, tags []string) (document.Document, error) {
+	resp, err := c.client.UntagDocument(ctx, &protomedia.TagDocumentReq{
+		ShelfId:    ptypes.UUIDProto(shelfID),
+		DocumentId: ptypes.UUIDProto(documentID),
+		Tags:       tags,
+	})
+	if err != nil {
+		return document.Document{}, err
+	}
+	return ptypes.ShelfDocument(resp), nil
+}
+
+// RenameDocument renames a document and returns the updated document.
+func (c *Client) RenameDocument(ctx context.Context, shelfID, documentID uuid.UUID, name string) (document.Document, error) {
+	resp, err := c.client.RenameDocument(ctx, &protomedia.RenameDocumentReq{
+		ShelfId:    ptypes.UUIDProto(shelfID),
+		DocumentId: ptypes.UUIDProto(documentID),
+		Name:       name,
+	})
+	if err != nil {
+		return document.Document{}, err
+	}
+	return ptypes.ShelfDocument(resp), nil
+}
+
+// DeleteDocument moves a document to the trash of its shelf. If the document
+// is under legal hold, the returned error wraps document.ErrLegalHold.
+func (c *Client) DeleteDocument(ctx context.Context, shelfID, documentID uuid.UUID) error {
+	_, err := c.client.DeleteDocument(ctx, &protomedia.DeleteDocumentReq{
+		ShelfId:    ptypes.UUIDProto(shelfID),
+		DocumentId: ptypes.UUIDProto(documentID),
+	})
+	if status.Code(err) == codes.FailedPrecondition {
+		return fmt.Errorf("%w: %v", document.ErrLegalHold, err)
+	}
+	return err
+}
+
 func (c *Client) LookupGalleryByName(ctx context.Context, name string) (uuid.UUID, bool, error) {
 	resp, err := c.client.LookupGalleryByName(ctx, &protocommon.NameLookup{Name: name})
 	if err != nil {
@@ -528,7 +1226,34 @@ func (c *Client) LookupGalleryStackByName(ctx context.Context, galleryID uuid.UU
 	return ptypes.UUID(resp.GetId()), resp.GetFound(), nil
 }
 
+// LookupGalleryStacksByTag returns the UUIDs of the stacks of the given
+// gallery that have the given tag, or false if the gallery is unknown.
+func (c *Client) LookupGalleryStacksByTag(ctx context.Context, galleryID uuid.UUID, tag string) ([]uuid.UUID, bool, error) {
+	resp, err := c.client.LookupGalleryStacksByTag(ctx, &protomedia.LookupGalleryStacksByTagReq{
+		GalleryId: ptypes.UUIDProto(galleryID),
+		Tag:       tag,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	ids := make([]uuid.UUID, len(resp.GetStackIds()))
+	for i, id := range resp.GetStackIds() {
+		ids[i] = ptypes.UUID(id)
+	}
+	return ids, resp.GetFound(), nil
+}
+
 func (c *Client) UploadImage(ctx context.Context, galleryID uuid.UUID, r io.Reader, name, disk, path string) (gallery.Stack, error) {
+	return c.upload(ctx, galleryID, r, name, disk, path, false)
+}
+
+// UploadVideo uploads the video in r to the given gallery and returns the
+// video Stack.
+func (c *Client) UploadVideo(ctx context.Context, galleryID uuid.UUID, r io.Reader, name, disk, path string) (gallery.Stack, error) {
+	return c.upload(ctx, galleryID, r, name, disk, path, true)
+}
+
+func (c *Client) upload(ctx context.Context, galleryID uuid.UUID, r io.Reader, name, disk, path string, video bool) (gallery.Stack, error) {
 	stream, err := c.client.UploadImage(ctx)
 	if err != nil {
 		return gallery.Stack{}, err
@@ -541,28 +1266,22 @@ func (c *Client) UploadImage(ctx context.Context, galleryID uuid.UUID, r io.Read
 				Name:      name,
 				Disk:      disk,
 				Path:      path,
+				Video:     video,
 			},
 		},
 	}); err != nil {
 		return gallery.Stack{}, fmt.Errorf("send metadata: %w", stream.RecvMsg(nil))
 	}
 
-	buf := make([]byte, 512)
-L:
-	for {
-		n, err := r.Read(buf)
-		if err == io.EOF {
-			break L
-		}
-		if err != nil {
-			return gallery.Stack{}, err
-		}
-
-		if err := stream.Send(&protomedia.UploadImageReq{
-			UploadData: &protomedia.UploadImageReq_Chunk{Chunk: buf[:n]},
-		}); err != nil {
+	if err := c.sendChunks(ctx, r, func(chunk []byte) error {
+		return stream.Send(&protomedia.UploadImageReq{
+			UploadData: &protomedia.UploadImageReq_Chunk{Chunk: chunk},
+		})
+	}); err != nil {
+		if errors.Is(err, errSend) {
 			return gallery.Stack{}, fmt.Errorf("send chunk: %w", stream.RecvMsg(nil))
 		}
+		return gallery.Stack{}, err
 	}
 
 	resp, err := stream.CloseAndRecv()
@@ -590,24 +1309,15 @@ func (c *Client) ReplaceImage(ctx context.Context, galleryID, stackID uuid.UUID,
 		return gallery.Stack{}, fmt.Errorf("send metadata: %w", stream.RecvMsg(nil))
 	}
 
-	buf := make([]byte, 512)
-L:
-	for {
-		n, err := r.Read(buf)
-		if err == io.EOF {
-			break L
-		}
-		if err != nil {
-			return gallery.Stack{}, err
-		}
-
-		if err := stream.Send(&protomedia.ReplaceImageReq{
-			ReplaceData: &protomedia.ReplaceImageReq_Chunk{
-				Chunk: buf[:n],
-			},
-		}); err != nil {
+	if err := c.sendChunks(ctx, r, func(chunk []byte) error {
+		return stream.Send(&protomedia.ReplaceImageReq{
+			ReplaceData: &protomedia.ReplaceImageReq_Chunk{Chunk: chunk},
+		})
+	}); err != nil {
+		if errors.Is(err, errSend) {
 			return gallery.Stack{}, fmt.Errorf("send chunk: %w", stream.RecvMsg(nil))
 		}
+		return gallery.Stack{}, err
 	}
 
 	resp, err := stream.CloseAndRecv()
@@ -618,10 +1328,204 @@ L:
 	return ptypes.GalleryStack(resp), nil
 }
 
+// FetchGallery fetches a gallery. The stacks of the gallery are streamed in
+// multiple messages (see Server.StreamGalleryStacks), so that large galleries
+// don't exceed the message size limit of gRPC. If the server does not
+// implement streaming, the gallery is fetched in a single message.
 func (c *Client) FetchGallery(ctx context.Context, id uuid.UUID) (gallery.JSONGallery, error) {
+	g, err := c.streamGallery(ctx, id)
+	if status.Code(err) != codes.Unimplemented {
+		return g, err
+	}
+
 	resp, err := c.client.FetchGallery(ctx, ptypes.UUIDProto(id))
 	if err != nil {
 		return gallery.JSONGallery{}, err
 	}
 	return ptypes.Gallery(resp), nil
 }
+
+func (c *Client) streamGallery(ctx context.Context, id uuid.UUID) (gallery.JSONGallery, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.client.StreamGalleryStacks(ctx, &protomedia.StreamGalleryStacksReq{GalleryId: ptypes.UUIDProto(id)})
+	if err != nil {
+		return gallery.JSONGallery{}, err
+	}
+
+	var g gallery.JSONGallery
+	for first := true; ; first = false {
+		chunk, err := stream.Recv()
+		if err == io.EOF && !first {
+			return g, nil
+		}
+		if err != nil {
+			return gallery.JSONGallery{}, err
+		}
+		if first {
+			g = ptypes.Gallery(chunk.GetGallery())
+		}
+		g.Stacks = append(g.Stacks, slice.Map(chunk.GetStacks(), ptypes.GalleryStack).([]gallery.Stack)...)
+	}
+}
+
+// FetchGalleryIndex fetches the index of a gallery. The index contains only
+// the summaries of the stacks, which is enough to render a grid of the images.
+// Use FetchStack to fetch the full stacks.
+func (c *Client) FetchGalleryIndex(ctx context.Context, id uuid.UUID) (gallery.JSONIndex, error) {
+	resp, err := c.client.FetchGalleryIndex(ctx, ptypes.UUIDProto(id))
+	if err != nil {
+		return gallery.JSONIndex{}, err
+	}
+	return ptypes.GalleryIndex(resp), nil
+}
+
+// ListStacks fetches a page of the stacks of a gallery that match q.
+func (c *Client) ListStacks(ctx context.Context, galleryID uuid.UUID, q gallery.Query) (gallery.StackPage, error) {
+	resp, err := c.client.ListStacks(ctx, ptypes.ListStacksReqProto(galleryID, q))
+	if err != nil {
+		return gallery.StackPage{}, err
+	}
+	return ptypes.StackPage(resp), nil
+}
+
+// ListGalleries returns a page of the UUIDs and names of all galleries, sorted
+// by name. If limit is 0, all galleries after offset are returned.
+func (c *Client) ListGalleries(ctx context.Context, offset, limit int) (gallery.GalleryList, error) {
+	resp, err := c.client.ListGalleries(ctx, &protomedia.ListGalleriesReq{
+		Offset: int64(offset),
+		Limit:  int64(limit),
+	})
+	if err != nil {
+		return gallery.GalleryList{}, err
+	}
+	return ptypes.GalleryList(resp), nil
+}
+
+// FetchStack fetches a single stack of a gallery.
+func (c *Client) FetchStack(ctx context.Context, galleryID, stackID uuid.UUID) (gallery.Stack, error) {
+	resp, err := c.client.FetchStack(ctx, &protomedia.FetchStackReq{
+		GalleryId: ptypes.UUIDProto(galleryID),
+		StackId:   ptypes.UUIDProto(stackID),
+	})
+	if err != nil {
+		return gallery.Stack{}, err
+	}
+	return ptypes.GalleryStack(resp), nil
+}
+
+// SortGallery sorts the stacks of a gallery.
+func (c *Client) SortGallery(ctx context.Context, galleryID uuid.UUID, sorting []uuid.UUID) error {
+	_, err := c.client.SortGallery(ctx, &protomedia.SortGalleryReq{
+		Id:      ptypes.UUIDProto(galleryID),
+		Sorting: slice.Map(sorting, ptypes.UUIDProto).([]*protocommon.UUID),
+	})
+	return stackChangeError(err)
+}
+
+// TagStack adds tags to the images of a stack and returns the updated stack.
+func (c *Client) TagStack(ctx context.Context, galleryID, stackID uuid.UUID, tags []string) (gallery.Stack, error) {
+	resp, err := c.client.TagStack(ctx, &protomedia.TagStackReq{
+		GalleryId: ptypes.UUIDProto(galleryID),
+		StackId:   ptypes.UUIDProto(stackID),
+		Tags:      tags,
+	})
+	if err != nil {
+		return gallery.Stack{}, stackChangeError(err)
+	}
+	return ptypes.GalleryStack(resp), nil
+}
+
+// UntagStack removes tags from the images of a stack and returns the updated
+// stack.
+func (c *Client) UntagStack(ctx context.Context, galleryID, stackID uuid.UUID, tags []string) (gallery.Stack, error) {
+	resp, err := c.client.UntagStack(ctx, &protomedia.TagStackReq{
+		GalleryId: ptypes.UUIDProto(galleryID),
+		StackId:   ptypes.UUIDProto(stackID),
+		Tags:      tags,
+	})
+	if err != nil {
+		return gallery.Stack{}, stackChangeError(err)
+	}
+	return ptypes.GalleryStack(resp), nil
+}
+
+// RenameStack renames the images of a stack and returns the updated stack.
+func (c *Client) RenameStack(ctx context.Context, galleryID, stackID uuid.UUID, name string) (gallery.Stack, error) {
+	resp, err := c.client.RenameStack(ctx, &protomedia.RenameStackReq{
+		GalleryId: ptypes.UUIDProto(galleryID),
+		StackId:   ptypes.UUIDProto(stackID),
+		Name:      name,
+	})
+	if err != nil {
+		return gallery.Stack{}, stackChangeError(err)
+	}
+	return ptypes.GalleryStack(resp), nil
+}
+
+// DeleteStack moves a stack to the trash of its gallery. If the stack is under
+// legal hold, the returned error wraps gallery.ErrLegalHold.
+func (c *Client) DeleteStack(ctx context.Context, galleryID, stackID uuid.UUID) error {
+	_, err := c.client.DeleteStack(ctx, &protomedia.DeleteStackReq{
+		GalleryId: ptypes.UUIDProto(galleryID),
+		StackId:   ptypes.UUIDProto(stackID),
+	})
+	return stackChangeError(err)
+}
+
+// stackChangeError returns an error that wraps gallery.ErrLegalHold if err
+// has codes.FailedPrecondition.
+func stackChangeError(err error) error {
+	if status.Code(err) == codes.FailedPrecondition {
+		return fmt.Errorf("%w: %v", gallery.ErrLegalHold, err)
+	}
+	return err
+}
+
+// DefaultChunkSize is the default size of the chunks of uploaded files.
+const DefaultChunkSize = 128 << 10
+
+var errSend = errors.New("send")
+
+type progressKey struct{}
+
+// WithUploadProgress returns a copy of ctx that makes the uploads of the Client
+// call fn after every sent chunk with the number of bytes of the file that
+// have been sent so far:
+//
+//	ctx = mediarpc.WithUploadProgress(ctx, func(sent int64) {
+//		log.Printf("%d/%d bytes", sent, size)
+//	})
+//	client.UploadImage(ctx, galleryID, f, name, disk, path)
+func WithUploadProgress(ctx context.Context, fn func(sent int64)) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// sendChunks reads r in chunks of at most the configured chunk size and calls
+// send for each chunk. Only a single chunk is held in memory at a time. Errors
+// returned by send are wrapped in errSend.
+func (c *Client) sendChunks(ctx context.Context, r io.Reader, send func([]byte) error) error {
+	progress, _ := ctx.Value(progressKey{}).(func(int64))
+
+	var sent int64
+	buf := make([]byte, c.chunkSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if err := send(buf[:n]); err != nil {
+				return fmt.Errorf("%w: %v", errSend, err)
+			}
+			sent += int64(n)
+			if progress != nil {
+				progress(sent)
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -1,22 +1,36 @@
 package mediarpc_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"image/color"
+	"io"
+	"math/rand"
+	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/google/uuid"
 	"github.com/modernice/goes/event"
 	"github.com/modernice/nice-cms/internal/grpctest"
 	"github.com/modernice/nice-cms/internal/imggen"
 	"github.com/modernice/nice-cms/internal/testutil"
 	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/delta"
 	"github.com/modernice/nice-cms/media/document"
 	"github.com/modernice/nice-cms/media/image/gallery"
 	"github.com/modernice/nice-cms/media/mediarpc"
+	protocommon "github.com/modernice/nice-cms/proto/gen/common/v1"
 	protomedia "github.com/modernice/nice-cms/proto/gen/media/v1"
+	"github.com/modernice/nice-cms/proto/ptypes/v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestServer_LookupDocumentByName(t *testing.T) {
@@ -36,7 +50,7 @@ func TestServer_LookupDocumentByName(t *testing.T) {
 		t.Fatalf("save shelf: %v", err)
 	}
 
-	lookup := newDocumentLookup(ctx, ebus, estore)
+	lookup := newDocumentLookup(t, ctx, ebus, estore)
 	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
 
 	_, dial := grpctest.NewServer(func(s *grpc.Server) {
@@ -78,7 +92,7 @@ func TestServer_UploadDocument(t *testing.T) {
 		t.Fatalf("save shelf: %v", err)
 	}
 
-	lookup := newDocumentLookup(ctx, ebus, estore)
+	lookup := newDocumentLookup(t, ctx, ebus, estore)
 	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
 
 	_, dial := grpctest.NewServer(func(s *grpc.Server) {
@@ -132,6 +146,115 @@ func TestServer_UploadDocument(t *testing.T) {
 	}
 }
 
+func TestServer_UploadDocument_chunks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	setupEvents, _, setupAggregates := testutil.Goes()
+	ebus, estore, _ := setupEvents()
+	aggregates := setupAggregates()
+
+	shelfs := document.GoesRepository(aggregates)
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create("foo")
+
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save shelf: %v", err)
+	}
+
+	lookup := newDocumentLookup(t, ctx, ebus, estore)
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(shelfs, lookup, nil, nil, storage))
+	})
+	conn := dial()
+	defer conn.Close()
+
+	client := mediarpc.NewClient(conn)
+
+	// Larger than multiple chunks and not a multiple of the chunk size.
+	data := make([]byte, 300<<10+17)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	// DataErrReader returns io.EOF together with the last bytes.
+	r := iotest.DataErrReader(bytes.NewReader(data))
+
+	doc, err := client.UploadDocument(ctx, shelf.ID, r, "", "Foo", "foo-disk", "/foo.bin")
+	if err != nil {
+		t.Fatalf("UploadDocument failed with %q", err)
+	}
+
+	if doc.Filesize != len(data) {
+		t.Fatalf("Filesize should be %d; is %d", len(data), doc.Filesize)
+	}
+
+	b, err := doc.Download(ctx, storage)
+	if err != nil {
+		t.Fatalf("download document: %v", err)
+	}
+
+	if !bytes.Equal(b, data) {
+		t.Fatalf("uploaded document has wrong contents")
+	}
+}
+
+func TestClient_UploadDocument_options(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	setupEvents, _, setupAggregates := testutil.Goes()
+	ebus, estore, _ := setupEvents()
+	aggregates := setupAggregates()
+
+	shelfs := document.GoesRepository(aggregates)
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create("foo")
+
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save shelf: %v", err)
+	}
+
+	lookup := newDocumentLookup(t, ctx, ebus, estore)
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(shelfs, lookup, nil, nil, storage))
+	})
+	conn := dial()
+	defer conn.Close()
+
+	client := mediarpc.NewClient(conn, mediarpc.ChunkSize(100<<10), mediarpc.Gzip())
+
+	data := bytes.Repeat([]byte("foo bar baz "), 30<<10)
+
+	var progress []int64
+	ctx = mediarpc.WithUploadProgress(ctx, func(sent int64) {
+		progress = append(progress, sent)
+	})
+
+	doc, err := client.UploadDocument(ctx, shelf.ID, bytes.NewReader(data), "", "Foo", "foo-disk", "/foo.txt")
+	if err != nil {
+		t.Fatalf("UploadDocument failed with %q", err)
+	}
+
+	want := []int64{100 << 10, 200 << 10, 300 << 10, int64(len(data))}
+	if !cmp.Equal(progress, want) {
+		t.Fatalf("progress should be reported after every chunk:\n\n%s", cmp.Diff(want, progress))
+	}
+
+	b, err := doc.Download(ctx, storage)
+	if err != nil {
+		t.Fatalf("download document: %v", err)
+	}
+
+	if !bytes.Equal(b, data) {
+		t.Fatalf("uploaded document has wrong contents")
+	}
+}
+
 func TestServer_ReplaceDocument(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -140,7 +263,7 @@ func TestServer_ReplaceDocument(t *testing.T) {
 	ebus, estore, _ := setupEvents()
 	aggregates := setupAggregates()
 
-	lookup := newDocumentLookup(ctx, ebus, estore)
+	lookup := newDocumentLookup(t, ctx, ebus, estore)
 	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
 
 	shelfs := document.GoesRepository(aggregates)
@@ -179,204 +302,1060 @@ func TestServer_ReplaceDocument(t *testing.T) {
 		t.Fatalf("Filesize should be %d; is %d", filesize, replaced.Filesize)
 	}
 
-	rshelf, err := shelfs.Fetch(ctx, shelf.ID)
+	rshelf, err := shelfs.Fetch(ctx, shelf.ID)
+	if err != nil {
+		t.Fatalf("fetch shelf from repository: %v", err)
+	}
+
+	rdoc, err := rshelf.Document(doc.ID)
+	if err != nil {
+		t.Fatalf("get document: %v", err)
+	}
+
+	if !cmp.Equal(replaced, rdoc) {
+		t.Fatal(cmp.Diff(replaced, rdoc))
+	}
+}
+
+func TestServer_ReplaceDocumentDelta(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	setupEvents, _, setupAggregates := testutil.Goes()
+	ebus, estore, _ := setupEvents()
+	aggregates := setupAggregates()
+
+	lookup := newDocumentLookup(t, ctx, ebus, estore)
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+
+	shelfs := document.GoesRepository(aggregates)
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(600, 400, color.Black)
+	base := buf.Bytes()
+
+	doc, err := shelf.Add(ctx, storage, bytes.NewReader(base), "unique-foo", "foo", "foo-disk", "/foo.png")
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save shelf: %v", err)
+	}
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(shelfs, lookup, nil, nil, storage))
+	})
+	conn := dial()
+	defer conn.Close()
+
+	client := mediarpc.NewClient(conn)
+
+	target := append(append([]byte{}, base...), []byte("foo")...)
+	d, err := delta.Diff(bytes.NewReader(base), target)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+
+	replaced, err := client.ReplaceDocumentDelta(ctx, shelf.ID, doc.ID, d)
+	if err != nil {
+		t.Fatalf("ReplaceDocumentDelta failed with %q", err)
+	}
+
+	if replaced.Filesize != len(target) {
+		t.Fatalf("Filesize should be %d; is %d", len(target), replaced.Filesize)
+	}
+
+	rshelf, err := shelfs.Fetch(ctx, shelf.ID)
+	if err != nil {
+		t.Fatalf("fetch shelf from repository: %v", err)
+	}
+
+	rdoc, err := rshelf.Document(doc.ID)
+	if err != nil {
+		t.Fatalf("get document: %v", err)
+	}
+
+	if !cmp.Equal(replaced, rdoc) {
+		t.Fatal(cmp.Diff(replaced, rdoc))
+	}
+
+	if _, err := client.ReplaceDocumentDelta(ctx, shelf.ID, doc.ID, d); !errors.Is(err, delta.ErrBaseMismatch) {
+		t.Fatalf("ReplaceDocumentDelta should fail with %q for an outdated base; got %q", delta.ErrBaseMismatch, err)
+	}
+}
+
+func TestServer_FetchShelf(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	setupEvents, _, setupAggregates := testutil.Goes()
+	ebus, estore, _ := setupEvents()
+	aggregates := setupAggregates()
+
+	lookup := newDocumentLookup(t, ctx, ebus, estore)
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+
+	shelfs := document.GoesRepository(aggregates)
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(600, 400, color.Black)
+
+	if _, err := shelf.Add(ctx, storage, buf, "unique-foo", "foo", "foo-disk", "/foo.png"); err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+
+	audio, err := shelf.Add(ctx, storage, strings.NewReader("bar"), "", "bar", "foo-disk", "/bar.mp3")
+	if err != nil {
+		t.Fatalf("add audio document: %v", err)
+	}
+
+	if _, err := shelf.SetAudioMetadata(audio.ID, media.AudioMetadata{Duration: 90 * time.Second, Bitrate: 192000}); err != nil {
+		t.Fatalf("set audio metadata: %v", err)
+	}
+
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save shelf: %v", err)
+	}
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(shelfs, lookup, nil, nil, storage))
+	})
+	conn := dial()
+	defer conn.Close()
+
+	client := mediarpc.NewClient(conn)
+
+	fetched, err := client.FetchShelf(ctx, shelf.ID)
+	if err != nil {
+		t.Fatalf("FetchShelf failed with %q", err)
+	}
+
+	if !cmp.Equal(shelf.JSON(), fetched) {
+		t.Fatal(cmp.Diff(shelf.JSON(), fetched))
+	}
+}
+
+func TestServer_documentChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, _, setupAggregates := testutil.Goes()
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+	shelfs := document.GoesRepository(setupAggregates())
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create("foo")
+
+	foo, err := shelf.Add(ctx, storage, strings.NewReader("foo"), "", "foo", "foo-disk", "/foo.txt")
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+	held, err := shelf.Add(ctx, storage, strings.NewReader("bar"), "", "bar", "foo-disk", "/bar.txt")
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+	if err := shelf.SetLegalHold(held.ID, "audit"); err != nil {
+		t.Fatalf("set legal hold: %v", err)
+	}
+
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save shelf: %v", err)
+	}
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(shelfs, nil, nil, nil, storage))
+	})
+	conn := dial()
+	defer conn.Close()
+
+	client := mediarpc.NewClient(conn)
+
+	tagged, err := client.TagDocument(ctx, shelf.ID, foo.ID, []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("TagDocument failed with %q", err)
+	}
+	if !cmp.Equal(tagged.Tags, []string{"a", "b"}) {
+		t.Fatalf("Document should have tags %v; has %v", []string{"a", "b"}, tagged.Tags)
+	}
+
+	untagged, err := client.UntagDocument(ctx, shelf.ID, foo.ID, []string{"a"})
+	if err != nil {
+		t.Fatalf("UntagDocument failed with %q", err)
+	}
+	if !cmp.Equal(untagged.Tags, []string{"b"}) {
+		t.Fatalf("Document should have tags %v; has %v", []string{"b"}, untagged.Tags)
+	}
+
+	renamed, err := client.RenameDocument(ctx, shelf.ID, foo.ID, "baz")
+	if err != nil {
+		t.Fatalf("RenameDocument failed with %q", err)
+	}
+	if renamed.Name != "baz" {
+		t.Fatalf("Document should be renamed to %q; is %q", "baz", renamed.Name)
+	}
+
+	if err := client.DeleteDocument(ctx, shelf.ID, held.ID); !errors.Is(err, document.ErrLegalHold) {
+		t.Fatalf("DeleteDocument should fail with %q for a held document; got %v", document.ErrLegalHold, err)
+	}
+
+	if err := client.DeleteDocument(ctx, shelf.ID, foo.ID); err != nil {
+		t.Fatalf("DeleteDocument failed with %q", err)
+	}
+
+	fetched, err := shelfs.Fetch(ctx, shelf.ID)
+	if err != nil {
+		t.Fatalf("fetch shelf: %v", err)
+	}
+	if len(fetched.Trashed) != 1 || fetched.Trashed[0].ID != foo.ID {
+		t.Fatalf("Document %s should be in the trash", foo.ID)
+	}
+
+	if _, err := client.TagDocument(ctx, shelf.ID, uuid.New(), []string{"a"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("TagDocument should fail with %q for an unknown document; got %q", codes.NotFound, status.Code(err))
+	}
+
+	if _, err := client.RenameDocument(ctx, uuid.New(), foo.ID, "foo"); status.Code(err) != codes.NotFound {
+		t.Fatalf("RenameDocument should fail with %q for an unknown shelf; got %q", codes.NotFound, status.Code(err))
+	}
+}
+
+func TestServer_ListDocuments(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	setupEvents, _, setupAggregates := testutil.Goes()
+	ebus, estore, _ := setupEvents()
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+	shelfs := document.GoesRepository(setupAggregates())
+
+	idx := document.NewIndex()
+	if _, err := idx.Project(ctx, ebus, estore); err != nil {
+		t.Fatalf("project index: %v", err)
+	}
+	awaitReady(t, idx.Ready())
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create("foo")
+
+	for _, name := range []string{"foo", "bar", "baz", "foobar"} {
+		doc, err := shelf.Add(ctx, storage, strings.NewReader(name), "", name, "foo-disk", "/"+name+".txt")
+		if err != nil {
+			t.Fatalf("add document: %v", err)
+		}
+		if strings.HasPrefix(name, "ba") {
+			if _, err := shelf.Tag(doc.ID, "ba"); err != nil {
+				t.Fatalf("tag document: %v", err)
+			}
+		}
+	}
+
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save shelf: %v", err)
+	}
+
+	awaitIndexed(t, "Shelf", func() bool {
+		page, err := idx.List(shelf.ID, document.Query{})
+		return err == nil && page.Total == len(shelf.Documents)
+	})
+
+	for name, opts := range map[string][]mediarpc.Option{
+		"index":      {mediarpc.WithDocumentIndex(idx)},
+		"repository": nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, dial := grpctest.NewServer(func(s *grpc.Server) {
+				protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(shelfs, nil, nil, nil, storage, opts...))
+			})
+			conn := dial()
+			defer conn.Close()
+
+			client := mediarpc.NewClient(conn)
+
+			page, err := client.ListDocuments(ctx, shelf.ID, document.Query{Search: "FOO", Offset: 1, Limit: 1})
+			if err != nil {
+				t.Fatalf("ListDocuments failed with %q", err)
+			}
+
+			want := shelf.List(document.Query{Search: "FOO", Offset: 1, Limit: 1})
+			if !cmp.Equal(want, page) {
+				t.Fatal(cmp.Diff(want, page))
+			}
+
+			page, err = client.ListDocuments(ctx, shelf.ID, document.Query{Tags: []string{"ba"}})
+			if err != nil {
+				t.Fatalf("ListDocuments failed with %q", err)
+			}
+
+			if page.Total != 2 || len(page.Documents) != 2 {
+				t.Fatalf("ListDocuments should return %d tagged documents; got %d of %d", 2, len(page.Documents), page.Total)
+			}
+
+			if _, err := client.ListDocuments(ctx, uuid.New(), document.Query{}); status.Code(err) != codes.NotFound {
+				t.Fatalf("ListDocuments should fail with %q for an unknown shelf; got %q", codes.NotFound, status.Code(err))
+			}
+		})
+	}
+}
+
+func TestServer_SearchDocuments(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	setupEvents, _, setupAggregates := testutil.Goes()
+	ebus, estore, _ := setupEvents()
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+	shelfs := document.GoesRepository(setupAggregates())
+
+	svc := document.NewSearchService()
+	if _, err := svc.Project(ctx, ebus, estore); err != nil {
+		t.Fatalf("project search service: %v", err)
+	}
+
+	for _, name := range []string{"foo", "bar"} {
+		shelf := document.NewShelf(uuid.New())
+		shelf.Create(name)
+		doc, err := shelf.Add(ctx, storage, strings.NewReader(name), name+"-doc", name+" document", "foo-disk", "/"+name+"/doc.txt")
+		if err != nil {
+			t.Fatalf("add document: %v", err)
+		}
+		if _, err := shelf.Tag(doc.ID, name); err != nil {
+			t.Fatalf("tag document: %v", err)
+		}
+		if err := shelfs.Save(ctx, shelf); err != nil {
+			t.Fatalf("save shelf: %v", err)
+		}
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(shelfs, nil, nil, nil, storage, mediarpc.WithSearchService(svc)))
+	})
+	conn := dial()
+	defer conn.Close()
+
+	client := mediarpc.NewClient(conn)
+
+	results, err := client.SearchDocuments(ctx, document.SearchQuery{Search: "DOCUMENT"})
+	if err != nil {
+		t.Fatalf("SearchDocuments failed with %q", err)
+	}
+
+	want, _ := svc.Search(document.SearchQuery{Search: "DOCUMENT"})
+	if !cmp.Equal(want, results) {
+		t.Fatal(cmp.Diff(want, results))
+	}
+
+	if results.Total != 2 || results.Documents[0].ShelfName != "bar" {
+		t.Fatalf("SearchDocuments should return the documents of both shelfs, sorted by shelf name; got %v", results.Documents)
+	}
+
+	if results, err = client.SearchDocuments(ctx, document.SearchQuery{Tags: []string{"foo"}, Path: "^/foo/"}); err != nil {
+		t.Fatalf("SearchDocuments failed with %q", err)
+	}
+	if results.Total != 1 || results.Documents[0].ShelfName != "foo" {
+		t.Fatalf("SearchDocuments should return the document of the %q shelf; got %v", "foo", results.Documents)
+	}
+
+	if _, err := client.SearchDocuments(ctx, document.SearchQuery{Path: "("}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("SearchDocuments should fail with %q for an invalid path; got %q", codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestServer_FetchShelf_trash(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, _, setupAggregates := testutil.Goes()
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+	shelfs := document.GoesRepository(setupAggregates())
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create("foo")
+
+	doc, err := shelf.Add(ctx, storage, strings.NewReader("foo"), "unique-foo", "foo", "foo-disk", "/foo.txt")
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+
+	trashed, err := shelf.Trash(doc.ID)
+	if err != nil {
+		t.Fatalf("trash document: %v", err)
+	}
+
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save shelf: %v", err)
+	}
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(shelfs, nil, nil, nil, storage))
+	})
+	conn := dial()
+	defer conn.Close()
+
+	fetched, err := mediarpc.NewClient(conn).FetchShelf(ctx, shelf.ID)
+	if err != nil {
+		t.Fatalf("FetchShelf failed with %q", err)
+	}
+
+	got, err := fetched.TrashedDocument(doc.ID)
+	if err != nil {
+		t.Fatalf("fetched Shelf should have the trashed Document; TrashedDocument failed with %q", err)
+	}
+
+	if got.UniqueName != "unique-foo" || !got.TrashedAt.Equal(trashed.TrashedAt.Truncate(time.Millisecond)) {
+		t.Fatalf("trashed Document should be\n\n%v\n\ngot\n\n%v", trashed, got)
+	}
+}
+
+func TestServer_FetchShelf_notFound(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, _, setupAggregates := testutil.Goes()
+	aggregates := setupAggregates()
+
+	shelfs := document.GoesRepository(aggregates)
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(shelfs, nil, nil, nil, nil))
+	})
+	conn := dial()
+	defer conn.Close()
+
+	client := mediarpc.NewClient(conn)
+
+	if _, err := client.FetchShelf(ctx, uuid.New()); status.Code(err) != codes.NotFound {
+		t.Fatalf("FetchShelf should fail with %q; got %q", codes.NotFound, status.Code(err))
+	}
+}
+
+func TestServer_LookupGalleryByName(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	setupEvents, _, setupAggregates := testutil.Goes()
+	ebus, estore, _ := setupEvents()
+	aggregates := setupAggregates()
+
+	galleries := gallery.GoesRepository(aggregates)
+	lookup := gallery.NewLookup()
+	go lookup.Project(ctx, ebus, estore)
+	awaitReady(t, lookup.Ready())
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save gallery: %v", err)
+	}
+
+	awaitIndexed(t, "Gallery", func() bool {
+		_, ok := lookup.GalleryName("foo")
+		return ok
+	})
+
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(nil, nil, galleries, lookup, storage))
+	})
+	conn := dial()
+	defer conn.Close()
+
+	client := mediarpc.NewClient(conn)
+
+	id, found, err := client.LookupGalleryByName(ctx, "foo")
+	if err != nil {
+		t.Fatalf("LookupGalleryByName failed with %q", err)
+	}
+
+	if !found {
+		t.Fatalf("LookupGalleryByName could not find UUID for %q", "foo")
+	}
+
+	if id != g.ID {
+		t.Fatalf("ID should be %q; is %q", g.ID, id)
+	}
+}
+
+func TestServer_LookupGalleryStackByName(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	setupEvents, _, setupAggregates := testutil.Goes()
+	ebus, estore, _ := setupEvents()
+	aggregates := setupAggregates()
+
+	galleries := gallery.GoesRepository(aggregates)
+	lookup := gallery.NewLookup()
+	lookup.Project(ctx, ebus, estore)
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.Black)
+
+	stack, err := g.Upload(ctx, storage, buf, "foo", "foo-disk", "/foo.png")
+	if err != nil {
+		t.Fatalf("upload image: %v", err)
+	}
+
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save gallery: %v", err)
+	}
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(nil, nil, galleries, lookup, storage))
+	})
+	conn := dial()
+	defer conn.Close()
+
+	client := mediarpc.NewClient(conn)
+
+	id, found, err := client.LookupGalleryStackByName(ctx, g.ID, "foo")
+	if err != nil {
+		t.Fatalf("LookupGalleryStackByName failed with %q", err)
+	}
+
+	if !found {
+		t.Fatalf("LookupGalleryStackByName could not find UUID for %q", "foo")
+	}
+
+	if id != stack.ID {
+		t.Fatalf("ID should be %q; is %q", stack.ID, id)
+	}
+}
+
+func TestServer_LookupGalleryStacksByTag(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	setupEvents, _, setupAggregates := testutil.Goes()
+	ebus, estore, _ := setupEvents()
+	aggregates := setupAggregates()
+
+	galleries := gallery.GoesRepository(aggregates)
+	lookup := gallery.NewLookup()
+	lookup.Project(ctx, ebus, estore)
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.Black)
+
+	stack, err := g.Upload(ctx, storage, buf, "foo", "foo-disk", "/foo.png")
+	if err != nil {
+		t.Fatalf("upload image: %v", err)
+	}
+
+	if _, err := g.Tag(ctx, stack, "hero"); err != nil {
+		t.Fatalf("tag stack: %v", err)
+	}
+
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save gallery: %v", err)
+	}
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(nil, nil, galleries, lookup, storage))
+	})
+	conn := dial()
+	defer conn.Close()
+
+	client := mediarpc.NewClient(conn)
+
+	var ids []uuid.UUID
+	var found bool
+	for i := 0; i < 100 && len(ids) == 0; i++ {
+		if ids, found, err = client.LookupGalleryStacksByTag(ctx, g.ID, "hero"); err != nil {
+			t.Fatalf("LookupGalleryStacksByTag failed with %q", err)
+		}
+		if len(ids) == 0 {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	if !found {
+		t.Fatalf("LookupGalleryStacksByTag could not find gallery %q", g.ID)
+	}
+
+	if len(ids) != 1 || ids[0] != stack.ID {
+		t.Fatalf("LookupGalleryStacksByTag should return [%s]; got %v", stack.ID, ids)
+	}
+
+	if _, found, _ := client.LookupGalleryStacksByTag(ctx, uuid.New(), "hero"); found {
+		t.Fatalf("LookupGalleryStacksByTag should not find an unknown gallery")
+	}
+}
+
+func TestServer_UploadImage(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, _, setupAggregates := testutil.Goes()
+	// ebus, estore, _ := setupEvents()
+	aggregates := setupAggregates()
+
+	galleries := gallery.GoesRepository(aggregates)
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save gallery: %v", err)
+	}
+
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(nil, nil, galleries, nil, storage))
+	})
+	conn := dial()
+	defer conn.Close()
+
+	client := mediarpc.NewClient(conn)
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.Black)
+	filesize := buf.Len()
+
+	name := "foo"
+	disk := "foo-disk"
+	path := "/foo.png"
+	stack, err := client.UploadImage(ctx, g.ID, buf, name, disk, path)
+	if err != nil {
+		t.Fatalf("UploadImage failed with %q", err)
+	}
+
+	if stack.Original().Filesize != filesize {
+		t.Fatalf("Filesize of original image should be %d; is %d", filesize, stack.Original().Filesize)
+	}
+}
+
+func TestServer_UploadImage_video(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, _, setupAggregates := testutil.Goes()
+	aggregates := setupAggregates()
+
+	galleries := gallery.GoesRepository(aggregates)
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save gallery: %v", err)
+	}
+
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(nil, nil, galleries, nil, storage))
+	})
+	conn := dial()
+	defer conn.Close()
+
+	client := mediarpc.NewClient(conn)
+
+	stack, err := client.UploadVideo(ctx, g.ID, strings.NewReader("foo"), "foo", "foo-disk", "/foo.mp4")
+	if err != nil {
+		t.Fatalf("UploadVideo failed with %q", err)
+	}
+
+	if !stack.IsVideo() {
+		t.Fatalf("uploaded Stack should be a video Stack")
+	}
+
+	if stack.Video.Path != "/foo.mp4" || stack.Video.Filesize != 3 {
+		t.Fatalf("Video should be stored at %q with 3 bytes; got %q with %d bytes", "/foo.mp4", stack.Video.Path, stack.Video.Filesize)
+	}
+
+	fetched, err := client.FetchStack(ctx, g.ID, stack.ID)
+	if err != nil {
+		t.Fatalf("FetchStack failed with %q", err)
+	}
+
+	if !fetched.IsVideo() {
+		t.Fatalf("fetched Stack should be a video Stack")
+	}
+}
+
+func TestServer_ReplaceImage(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, _, setupAggregates := testutil.Goes()
+	// ebus, estore, _ := setupEvents()
+	aggregates := setupAggregates()
+
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+	galleries := gallery.GoesRepository(aggregates)
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+
+	_, buf := imggen.ColoredRectangle(800, 600, color.Black)
+	name := "foo"
+	disk := "foo-disk"
+	path := "/foo.png"
+
+	stack, err := g.Upload(ctx, storage, buf, name, disk, path)
+	if err != nil {
+		t.Fatalf("upload image: %v", err)
+	}
+
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save gallery: %v", err)
+	}
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(nil, nil, galleries, nil, storage))
+	})
+	conn := dial()
+	defer conn.Close()
+
+	client := mediarpc.NewClient(conn)
+
+	_, buf = imggen.ColoredRectangle(1200, 1000, color.White)
+
+	replaced, err := client.ReplaceImage(ctx, g.ID, stack.ID, buf)
+	if err != nil {
+		t.Fatalf("ReplacImage failed with %q", err)
+	}
+
+	if replaced.Original().Width != 1200 || replaced.Original().Height != 1000 {
+		t.Fatalf(
+			"replaced image should have Width=%d Height=%d; got Width=%d Height=%d",
+			1200, 1000,
+			replaced.Original().Width, replaced.Original().Height,
+		)
+	}
+
+	g, err = galleries.Fetch(ctx, g.ID)
 	if err != nil {
-		t.Fatalf("fetch shelf from repository: %v", err)
+		t.Fatalf("fetch gallery: %v", err)
 	}
 
-	rdoc, err := rshelf.Document(doc.ID)
+	gstack, err := g.Stack(stack.ID)
 	if err != nil {
-		t.Fatalf("get document: %v", err)
+		t.Fatalf("get stack: %v", err)
 	}
 
-	if !cmp.Equal(replaced, rdoc) {
-		t.Fatal(cmp.Diff(replaced, rdoc))
+	if !cmp.Equal(replaced, gstack) {
+		t.Fatal(cmp.Diff(replaced, gstack))
 	}
 }
 
-func TestServer_FetchShelf(t *testing.T) {
+func TestServer_FetchGallery(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	setupEvents, _, setupAggregates := testutil.Goes()
-	ebus, estore, _ := setupEvents()
+	_, _, setupAggregates := testutil.Goes()
+	// ebus, estore, _ := setupEvents()
 	aggregates := setupAggregates()
 
-	lookup := newDocumentLookup(ctx, ebus, estore)
 	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+	galleries := gallery.GoesRepository(aggregates)
 
-	shelfs := document.GoesRepository(aggregates)
-
-	shelf := document.NewShelf(uuid.New())
-	shelf.Create("foo")
+	g := gallery.New(uuid.New())
+	g.Create("foo")
 
-	_, buf := imggen.ColoredRectangle(600, 400, color.Black)
+	_, buf := imggen.ColoredRectangle(800, 600, color.Black)
+	name := "foo"
+	disk := "foo-disk"
+	path := "/foo.png"
 
-	if _, err := shelf.Add(ctx, storage, buf, "unique-foo", "foo", "foo-disk", "/foo.png"); err != nil {
-		t.Fatalf("add document: %v", err)
+	if _, err := g.Upload(ctx, storage, buf, name, disk, path); err != nil {
+		t.Fatalf("upload image: %v", err)
 	}
 
-	if err := shelfs.Save(ctx, shelf); err != nil {
-		t.Fatalf("save shelf: %v", err)
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save gallery: %v", err)
 	}
 
 	_, dial := grpctest.NewServer(func(s *grpc.Server) {
-		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(shelfs, lookup, nil, nil, storage))
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(nil, nil, galleries, nil, storage))
 	})
 	conn := dial()
 	defer conn.Close()
 
 	client := mediarpc.NewClient(conn)
 
-	fetched, err := client.FetchShelf(ctx, shelf.ID)
+	fetched, err := client.FetchGallery(ctx, g.ID)
 	if err != nil {
-		t.Fatalf("FetchShelf failed with %q", err)
+		t.Fatalf("FetchGallery failed with %q", err)
 	}
 
-	if !cmp.Equal(shelf.JSON(), fetched) {
-		t.Fatal(cmp.Diff(shelf.JSON(), fetched))
+	want := g.JSON()
+	if !cmp.Equal(want, fetched) {
+		t.Fatal(cmp.Diff(want, fetched))
 	}
 }
 
-func TestServer_LookupGalleryByName(t *testing.T) {
+func TestServer_StreamGalleryStacks(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	setupEvents, _, setupAggregates := testutil.Goes()
-	ebus, estore, _ := setupEvents()
+	_, _, setupAggregates := testutil.Goes()
 	aggregates := setupAggregates()
 
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
 	galleries := gallery.GoesRepository(aggregates)
-	lookup := gallery.NewLookup()
-	go lookup.Project(ctx, ebus, estore)
 
 	g := gallery.New(uuid.New())
 	g.Create("foo")
 
+	_, buf := imggen.ColoredRectangle(8, 6, color.Black)
+	for i := 0; i < 250; i++ {
+		if _, err := g.Upload(ctx, storage, bytes.NewReader(buf.Bytes()), fmt.Sprintf("foo-%d", i), "foo-disk", fmt.Sprintf("/foo-%d.png", i)); err != nil {
+			t.Fatalf("upload image: %v", err)
+		}
+	}
+
 	if err := galleries.Save(ctx, g); err != nil {
 		t.Fatalf("save gallery: %v", err)
 	}
 
-	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
 	_, dial := grpctest.NewServer(func(s *grpc.Server) {
-		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(nil, nil, galleries, lookup, storage))
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(nil, nil, galleries, nil, storage))
 	})
 	conn := dial()
 	defer conn.Close()
 
-	client := mediarpc.NewClient(conn)
+	stream, err := protomedia.NewMediaServiceClient(conn).StreamGalleryStacks(ctx, &protomedia.StreamGalleryStacksReq{
+		GalleryId: ptypes.UUIDProto(g.ID),
+		PageSize:  100,
+	})
+	if err != nil {
+		t.Fatalf("StreamGalleryStacks failed with %q", err)
+	}
 
-	id, found, err := client.LookupGalleryByName(ctx, "foo")
+	var sizes []int
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("receive chunk: %v", err)
+		}
+		if len(sizes) == 0 && chunk.GetGallery().GetName() != "foo" {
+			t.Fatalf("first chunk should contain the gallery")
+		}
+		if len(sizes) > 0 && chunk.GetGallery() != nil {
+			t.Fatalf("only the first chunk should contain the gallery")
+		}
+		if len(chunk.GetGallery().GetStacks()) > 0 {
+			t.Fatalf("the gallery of the first chunk should have no stacks")
+		}
+		sizes = append(sizes, len(chunk.GetStacks()))
+	}
+
+	if want := []int{100, 100, 50}; !cmp.Equal(sizes, want) {
+		t.Fatalf("stacks should be streamed in pages of %v; got %v", want, sizes)
+	}
+
+	fetched, err := mediarpc.NewClient(conn).FetchGallery(ctx, g.ID)
 	if err != nil {
-		t.Fatalf("LookupGalleryByName failed with %q", err)
+		t.Fatalf("FetchGallery failed with %q", err)
 	}
 
-	if !found {
-		t.Fatalf("LookupGalleryByName could not find UUID for %q", "foo")
+	if want := g.JSON(); !cmp.Equal(want, fetched) {
+		t.Fatal(cmp.Diff(want, fetched))
 	}
+}
 
-	if id != g.ID {
-		t.Fatalf("ID should be %q; is %q", g.ID, id)
+func TestClient_FetchGallery_unaryFallback(t *testing.T) {
+	want := gallery.JSONGallery{ID: uuid.New(), Name: "foo", Stacks: gallery.Stacks{{ID: uuid.New()}}}
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, unaryGalleryServer{g: want})
+	})
+	conn := dial()
+	defer conn.Close()
+
+	fetched, err := mediarpc.NewClient(conn).FetchGallery(context.Background(), want.ID)
+	if err != nil {
+		t.Fatalf("FetchGallery should fall back to the unary call; failed with %q", err)
+	}
+
+	if !cmp.Equal(want, fetched, cmpopts.EquateEmpty()) {
+		t.Fatal(cmp.Diff(want, fetched, cmpopts.EquateEmpty()))
 	}
 }
 
-func TestServer_LookupGalleryStackByName(t *testing.T) {
+// unaryGalleryServer is a server that does not implement StreamGalleryStacks.
+type unaryGalleryServer struct {
+	protomedia.UnimplementedMediaServiceServer
+
+	g gallery.JSONGallery
+}
+
+func (s unaryGalleryServer) FetchGallery(context.Context, *protocommon.UUID) (*protomedia.Gallery, error) {
+	return ptypes.GalleryProto(s.g), nil
+}
+
+func TestServer_FetchGallery_notFound(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	setupEvents, _, setupAggregates := testutil.Goes()
-	ebus, estore, _ := setupEvents()
+	_, _, setupAggregates := testutil.Goes()
 	aggregates := setupAggregates()
 
 	galleries := gallery.GoesRepository(aggregates)
-	lookup := gallery.NewLookup()
-	lookup.Project(ctx, ebus, estore)
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(nil, nil, galleries, nil, nil))
+	})
+	conn := dial()
+	defer conn.Close()
+
+	client := mediarpc.NewClient(conn)
+
+	if _, err := client.FetchGallery(ctx, uuid.New()); status.Code(err) != codes.NotFound {
+		t.Fatalf("FetchGallery should fail with %q; got %q", codes.NotFound, status.Code(err))
+	}
+}
+
+func TestServer_FetchGalleryIndex(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, _, setupAggregates := testutil.Goes()
+	aggregates := setupAggregates()
+
 	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+	galleries := gallery.GoesRepository(aggregates)
 
 	g := gallery.New(uuid.New())
 	g.Create("foo")
 
 	_, buf := imggen.ColoredRectangle(800, 600, color.Black)
-
 	stack, err := g.Upload(ctx, storage, buf, "foo", "foo-disk", "/foo.png")
 	if err != nil {
 		t.Fatalf("upload image: %v", err)
 	}
 
+	if _, err := g.Tag(ctx, stack, "bar"); err != nil {
+		t.Fatalf("tag stack: %v", err)
+	}
+
 	if err := galleries.Save(ctx, g); err != nil {
 		t.Fatalf("save gallery: %v", err)
 	}
 
 	_, dial := grpctest.NewServer(func(s *grpc.Server) {
-		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(nil, nil, galleries, lookup, storage))
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(nil, nil, galleries, nil, storage))
 	})
 	conn := dial()
 	defer conn.Close()
 
 	client := mediarpc.NewClient(conn)
 
-	id, found, err := client.LookupGalleryStackByName(ctx, g.ID, "foo")
+	fetched, err := client.FetchGalleryIndex(ctx, g.ID)
 	if err != nil {
-		t.Fatalf("LookupGalleryStackByName failed with %q", err)
+		t.Fatalf("FetchGalleryIndex failed with %q", err)
 	}
 
-	if !found {
-		t.Fatalf("LookupGalleryStackByName could not find UUID for %q", "foo")
+	want := g.JSON().Index()
+	if !cmp.Equal(want, fetched) {
+		t.Fatal(cmp.Diff(want, fetched))
 	}
 
-	if id != stack.ID {
-		t.Fatalf("ID should be %q; is %q", stack.ID, id)
+	if len(fetched.Stacks) != 1 || fetched.Stacks[0].Name != "foo" || len(fetched.Stacks[0].Tags) != 1 {
+		t.Fatalf("index should contain the summary of the uploaded stack; got %v", fetched.Stacks)
 	}
 }
 
-func TestServer_UploadImage(t *testing.T) {
+func TestServer_ListStacks(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	_, _, setupAggregates := testutil.Goes()
-	// ebus, estore, _ := setupEvents()
-	aggregates := setupAggregates()
+	setupEvents, _, setupAggregates := testutil.Goes()
+	ebus, estore, _ := setupEvents()
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+	galleries := gallery.GoesRepository(setupAggregates())
 
-	galleries := gallery.GoesRepository(aggregates)
+	idx := gallery.NewIndex()
+	if _, err := idx.Project(ctx, ebus, estore); err != nil {
+		t.Fatalf("project index: %v", err)
+	}
+	awaitReady(t, idx.Ready())
 
 	g := gallery.New(uuid.New())
 	g.Create("foo")
 
+	for i, name := range []string{"foo", "bar", "baz", "foobar"} {
+		_, buf := imggen.ColoredRectangle(80, 60, color.Gray{Y: uint8(i * 50)})
+		stack, err := g.Upload(ctx, storage, buf, name, "foo-disk", "/"+name+".png")
+		if err != nil {
+			t.Fatalf("upload image: %v", err)
+		}
+		if strings.HasPrefix(name, "ba") {
+			if _, err := g.Tag(ctx, stack, "ba"); err != nil {
+				t.Fatalf("tag stack: %v", err)
+			}
+		}
+	}
+
 	if err := galleries.Save(ctx, g); err != nil {
 		t.Fatalf("save gallery: %v", err)
 	}
 
-	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
-	_, dial := grpctest.NewServer(func(s *grpc.Server) {
-		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(nil, nil, galleries, nil, storage))
+	awaitIndexed(t, "Gallery", func() bool {
+		page, err := idx.List(g.ID, gallery.Query{})
+		return err == nil && page.Total == len(g.Stacks)
 	})
-	conn := dial()
-	defer conn.Close()
-
-	client := mediarpc.NewClient(conn)
-
-	_, buf := imggen.ColoredRectangle(800, 600, color.Black)
-	filesize := buf.Len()
-
-	name := "foo"
-	disk := "foo-disk"
-	path := "/foo.png"
-	stack, err := client.UploadImage(ctx, g.ID, buf, name, disk, path)
-	if err != nil {
-		t.Fatalf("UploadImage failed with %q", err)
-	}
 
-	if stack.Original().Filesize != filesize {
-		t.Fatalf("Filesize of original image should be %d; is %d", filesize, stack.Original().Filesize)
+	for name, opts := range map[string][]mediarpc.Option{
+		"index":      {mediarpc.WithGalleryIndex(idx)},
+		"repository": nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, dial := grpctest.NewServer(func(s *grpc.Server) {
+				protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(nil, nil, galleries, nil, storage, opts...))
+			})
+			conn := dial()
+			defer conn.Close()
+
+			client := mediarpc.NewClient(conn)
+
+			page, err := client.ListStacks(ctx, g.ID, gallery.Query{Sort: "-name", Offset: 1, Limit: 2})
+			if err != nil {
+				t.Fatalf("ListStacks failed with %q", err)
+			}
+
+			want := g.List(gallery.Query{Sort: "-name", Offset: 1, Limit: 2})
+			if !cmp.Equal(want, page) {
+				t.Fatal(cmp.Diff(want, page))
+			}
+
+			page, err = client.ListStacks(ctx, g.ID, gallery.Query{Tags: []string{"ba"}})
+			if err != nil {
+				t.Fatalf("ListStacks failed with %q", err)
+			}
+
+			if page.Total != 2 || len(page.Stacks) != 2 {
+				t.Fatalf("ListStacks should return %d tagged stacks; got %d of %d", 2, len(page.Stacks), page.Total)
+			}
+
+			if _, err := client.ListStacks(ctx, uuid.New(), gallery.Query{}); status.Code(err) != codes.NotFound {
+				t.Fatalf("ListStacks should fail with %q for an unknown gallery; got %q", codes.NotFound, status.Code(err))
+			}
+		})
 	}
 }
 
-func TestServer_ReplaceImage(t *testing.T) {
+func TestServer_FetchStack(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	_, _, setupAggregates := testutil.Goes()
-	// ebus, estore, _ := setupEvents()
 	aggregates := setupAggregates()
 
 	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
@@ -386,11 +1365,7 @@ func TestServer_ReplaceImage(t *testing.T) {
 	g.Create("foo")
 
 	_, buf := imggen.ColoredRectangle(800, 600, color.Black)
-	name := "foo"
-	disk := "foo-disk"
-	path := "/foo.png"
-
-	stack, err := g.Upload(ctx, storage, buf, name, disk, path)
+	stack, err := g.Upload(ctx, storage, buf, "foo", "foo-disk", "/foo.png")
 	if err != nil {
 		t.Fatalf("upload image: %v", err)
 	}
@@ -407,42 +1382,29 @@ func TestServer_ReplaceImage(t *testing.T) {
 
 	client := mediarpc.NewClient(conn)
 
-	_, buf = imggen.ColoredRectangle(1200, 1000, color.White)
-
-	replaced, err := client.ReplaceImage(ctx, g.ID, stack.ID, buf)
+	fetched, err := client.FetchStack(ctx, g.ID, stack.ID)
 	if err != nil {
-		t.Fatalf("ReplacImage failed with %q", err)
-	}
-
-	if replaced.Original().Width != 1200 || replaced.Original().Height != 1000 {
-		t.Fatalf(
-			"replaced image should have Width=%d Height=%d; got Width=%d Height=%d",
-			1200, 1000,
-			replaced.Original().Width, replaced.Original().Height,
-		)
+		t.Fatalf("FetchStack failed with %q", err)
 	}
 
-	g, err = galleries.Fetch(ctx, g.ID)
-	if err != nil {
-		t.Fatalf("fetch gallery: %v", err)
+	if !cmp.Equal(stack, fetched) {
+		t.Fatal(cmp.Diff(stack, fetched))
 	}
 
-	gstack, err := g.Stack(stack.ID)
-	if err != nil {
-		t.Fatalf("get stack: %v", err)
+	if _, err := client.FetchStack(ctx, g.ID, uuid.New()); status.Code(err) != codes.NotFound {
+		t.Fatalf("FetchStack should fail with %q for an unknown stack; got %q", codes.NotFound, status.Code(err))
 	}
 
-	if !cmp.Equal(replaced, gstack) {
-		t.Fatal(cmp.Diff(replaced, gstack))
+	if _, err := client.FetchStack(ctx, uuid.New(), stack.ID); status.Code(err) != codes.NotFound {
+		t.Fatalf("FetchStack should fail with %q for an unknown gallery; got %q", codes.NotFound, status.Code(err))
 	}
 }
 
-func TestServer_FetchGallery(t *testing.T) {
+func TestServer_SortGallery_stackChanges(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	_, _, setupAggregates := testutil.Goes()
-	// ebus, estore, _ := setupEvents()
 	aggregates := setupAggregates()
 
 	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
@@ -451,12 +1413,13 @@ func TestServer_FetchGallery(t *testing.T) {
 	g := gallery.New(uuid.New())
 	g.Create("foo")
 
-	_, buf := imggen.ColoredRectangle(800, 600, color.Black)
-	name := "foo"
-	disk := "foo-disk"
-	path := "/foo.png"
-
-	if _, err := g.Upload(ctx, storage, buf, name, disk, path); err != nil {
+	_, buf := imggen.ColoredRectangle(8, 6, color.Black)
+	foo, err := g.Upload(ctx, storage, bytes.NewReader(buf.Bytes()), "foo", "foo-disk", "/foo.png")
+	if err != nil {
+		t.Fatalf("upload image: %v", err)
+	}
+	bar, err := g.Upload(ctx, storage, bytes.NewReader(buf.Bytes()), "bar", "foo-disk", "/bar.png")
+	if err != nil {
 		t.Fatalf("upload image: %v", err)
 	}
 
@@ -472,19 +1435,133 @@ func TestServer_FetchGallery(t *testing.T) {
 
 	client := mediarpc.NewClient(conn)
 
-	fetched, err := client.FetchGallery(ctx, g.ID)
+	if err := client.SortGallery(ctx, g.ID, []uuid.UUID{bar.ID, foo.ID}); err != nil {
+		t.Fatalf("SortGallery failed with %q", err)
+	}
+
+	tagged, err := client.TagStack(ctx, g.ID, foo.ID, []string{"a", "b"})
 	if err != nil {
-		t.Fatalf("FetchGallery failed with %q", err)
+		t.Fatalf("TagStack failed with %q", err)
+	}
+	if tags := tagged.Original().Tags; !cmp.Equal(tags, []string{"a", "b"}) {
+		t.Fatalf("Stack should have tags %v; has %v", []string{"a", "b"}, tags)
 	}
 
-	want := g.JSON()
-	if !cmp.Equal(want, fetched) {
-		t.Fatal(cmp.Diff(want, fetched))
+	untagged, err := client.UntagStack(ctx, g.ID, foo.ID, []string{"a"})
+	if err != nil {
+		t.Fatalf("UntagStack failed with %q", err)
+	}
+	if tags := untagged.Original().Tags; !cmp.Equal(tags, []string{"b"}) {
+		t.Fatalf("Stack should have tags %v; has %v", []string{"b"}, tags)
+	}
+
+	renamed, err := client.RenameStack(ctx, g.ID, foo.ID, "baz")
+	if err != nil {
+		t.Fatalf("RenameStack failed with %q", err)
+	}
+	if name := renamed.Original().Name; name != "baz" {
+		t.Fatalf("Stack should be renamed to %q; is %q", "baz", name)
+	}
+
+	if err := client.DeleteStack(ctx, g.ID, bar.ID); err != nil {
+		t.Fatalf("DeleteStack failed with %q", err)
+	}
+
+	fetched, err := galleries.Fetch(ctx, g.ID)
+	if err != nil {
+		t.Fatalf("fetch gallery: %v", err)
+	}
+
+	if len(fetched.Stacks) != 1 || fetched.Stacks[0].ID != foo.ID {
+		t.Fatalf("Gallery should only have Stack %s; has %v", foo.ID, fetched.Stacks)
+	}
+	if len(fetched.Trashed) != 1 || fetched.Trashed[0].Stack.ID != bar.ID {
+		t.Fatalf("Stack %s should be in the trash", bar.ID)
+	}
+
+	if _, err := client.TagStack(ctx, g.ID, uuid.New(), []string{"a"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("TagStack should fail with %q for an unknown stack; got %q", codes.NotFound, status.Code(err))
+	}
+
+	if err := client.SortGallery(ctx, uuid.New(), nil); status.Code(err) != codes.NotFound {
+		t.Fatalf("SortGallery should fail with %q for an unknown gallery; got %q", codes.NotFound, status.Code(err))
 	}
 }
 
-func newDocumentLookup(ctx context.Context, bus event.Bus, store event.Store) *document.Lookup {
+func newDocumentLookup(t *testing.T, ctx context.Context, bus event.Bus, store event.Store) *document.Lookup {
 	l := document.NewLookup()
 	go l.Project(ctx, bus, store)
+	awaitReady(t, l.Ready())
 	return l
 }
+
+func TestServer_ListShelfs_ListGalleries(t *testing.T) {
+	docLookup := document.NewLookup()
+	galleryLookup := gallery.NewLookup()
+
+	for _, name := range []string{"foo", "bar", "baz"} {
+		shelf := document.NewShelf(uuid.New())
+		shelf.Create(name)
+		for _, evt := range shelf.AggregateChanges() {
+			docLookup.ApplyEvent(evt)
+		}
+
+		g := gallery.New(uuid.New())
+		g.Create(name)
+		for _, evt := range g.AggregateChanges() {
+			galleryLookup.ApplyEvent(evt)
+		}
+	}
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protomedia.RegisterMediaServiceServer(s, mediarpc.NewServer(nil, docLookup, nil, galleryLookup, nil))
+	})
+	conn := dial()
+	defer conn.Close()
+
+	client := mediarpc.NewClient(conn)
+	ctx := context.Background()
+
+	shelfs, err := client.ListShelfs(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("ListShelfs failed with %q", err)
+	}
+	if want := docLookup.Shelfs(1, 1); !cmp.Equal(want, shelfs) {
+		t.Fatal(cmp.Diff(want, shelfs))
+	}
+	if shelfs.Total != 3 || len(shelfs.Shelfs) != 1 || shelfs.Shelfs[0].Name != "baz" {
+		t.Fatalf("ListShelfs should return the second shelf %q; got %v", "baz", shelfs)
+	}
+
+	galleries, err := client.ListGalleries(ctx, 0, 0)
+	if err != nil {
+		t.Fatalf("ListGalleries failed with %q", err)
+	}
+	if want := galleryLookup.Galleries(0, 0); !cmp.Equal(want, galleries) {
+		t.Fatal(cmp.Diff(want, galleries))
+	}
+	if galleries.Total != 3 || len(galleries.Galleries) != 3 {
+		t.Fatalf("ListGalleries should return all 3 galleries; got %v", galleries)
+	}
+}
+
+// awaitReady waits until the given ready channel of a projection is closed.
+func awaitReady(t *testing.T, ready <-chan struct{}) {
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("projection should catch up with the past events")
+	case <-ready:
+	}
+}
+
+// awaitIndexed waits until a projection applied the events of a saved
+// aggregate, i.e. until indexed returns true.
+func awaitIndexed(t *testing.T, aggregate string, indexed func() bool) {
+	deadline := time.Now().Add(time.Second)
+	for !indexed() {
+		if time.Now().After(deadline) {
+			t.Fatalf("%s should be projected after it was saved", aggregate)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,90 @@
+package mediaserver
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/modernice/nice-cms/audit"
+	"github.com/modernice/nice-cms/internal/api"
+	"github.com/modernice/nice-cms/media/mediaserver/routes"
+)
+
+// WithAudit returns an Option that adds the audit route to the media server.
+// The route pages through the Entries of the audit Log, newest first. The
+// Entries can be filtered using the "actor", "aggregate", "aggregateId",
+// "event", "since" and "until" query parameters, and paged using "cursor",
+// "offset" and "limit":
+//
+//	GET /audit?aggregate=cms.media.image.gallery&since=2022-01-01T00:00:00Z&limit=20
+func WithAudit(log *audit.Log, opts ...routes.Option) Option {
+	return func(s *Server) {
+		r := routes.New(opts...)
+		r.Install(s.router, routes.ShowAuditLog, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			q, err := auditQuery(req)
+			if err != nil {
+				api.Error(w, req, http.StatusBadRequest, err)
+				return
+			}
+
+			page := log.Entries(q)
+			resp := auditResponse{Page: page}
+			resp.PageMeta = api.WritePage(w, req, api.Page{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Count:  len(page.Entries),
+			})
+
+			api.JSON(w, req, http.StatusOK, resp)
+		}))
+	}
+}
+
+type auditResponse struct {
+	audit.Page
+	api.PageMeta
+}
+
+var auditList = api.List{
+	DefaultLimit: audit.DefaultLimit,
+	MaxLimit:     audit.MaxLimit,
+	Filters: map[string][]api.Operator{
+		"actor":       {api.Eq},
+		"aggregate":   {api.Eq},
+		"aggregateId": {api.Eq},
+		"event":       {api.Eq},
+		"since":       {api.Eq},
+		"until":       {api.Eq},
+	},
+}
+
+func auditQuery(r *http.Request) (audit.Query, error) {
+	lq, err := auditList.Parse(r)
+	if err != nil {
+		return audit.Query{}, err
+	}
+
+	q := audit.Query{Offset: lq.Offset, Limit: lq.Limit}
+	q.Actor, _ = lq.Value("actor", api.Eq)
+	q.Aggregate, _ = lq.Value("aggregate", api.Eq)
+	q.Event, _ = lq.Value("event", api.Eq)
+
+	if raw, ok := lq.Value("aggregateId", api.Eq); ok && raw != "" {
+		id, err := api.ParseUUID(raw, "aggregateId")
+		if err != nil {
+			return q, err
+		}
+		q.AggregateID = id
+	}
+
+	for name, t := range map[string]*time.Time{"since": &q.Since, "until": &q.Until} {
+		if raw, ok := lq.Value(name, api.Eq); ok && raw != "" {
+			parsed, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return q, api.Friendly(err, "Invalid time for %q: %q", name, raw)
+			}
+			*t = parsed
+		}
+	}
+
+	return q, nil
+}
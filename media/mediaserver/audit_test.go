@@ -0,0 +1,71 @@
+package mediaserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/audit"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/media/mediaserver"
+	"github.com/modernice/nice-cms/media/mediaserver/routes"
+)
+
+func TestWithAudit(t *testing.T) {
+	log := audit.NewLog()
+	galleryID := uuid.New()
+	for i := 1; i <= 3; i++ {
+		log.ApplyEvent(event.New(gallery.StackTagged, gallery.StackTaggedData{}, event.Aggregate(galleryID, gallery.Aggregate, i)).Any())
+	}
+	log.ApplyEvent(event.New(gallery.Created, gallery.CreatedData{}, event.Aggregate(uuid.New(), gallery.Aggregate, 1)).Any())
+
+	srv := mediaserver.New(&commandBus{}, mediaserver.WithAudit(log, routes.Prefix("/media")))
+
+	var resp struct {
+		audit.Page
+		Links struct {
+			Next string `json:"next"`
+		} `json:"links"`
+	}
+
+	get := func(url string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		return rec
+	}
+
+	rec := get("/media/audit?limit=2&aggregateId=" + galleryID.String())
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if resp.Total != 3 || len(resp.Entries) != 2 {
+		t.Fatalf("response should contain 2 of 3 Entries; contains %d of %d", len(resp.Entries), resp.Total)
+	}
+
+	if resp.Links.Next == "" {
+		t.Fatalf("response should link to the next page")
+	}
+
+	next := resp.Links.Next
+	resp.Links.Next = ""
+	if err := json.NewDecoder(get(next).Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if len(resp.Entries) != 1 || resp.Entries[0].AggregateVersion != 1 || resp.Links.Next != "" {
+		t.Fatalf("next page should contain the oldest Entry and no next link; got %v (next %q)", resp.Entries, resp.Links.Next)
+	}
+
+	for _, query := range []string{"limit=foo", "offset=-1", "since=yesterday", "aggregateId=foo"} {
+		if rec := get("/media/audit?" + query); rec.Code != http.StatusBadRequest {
+			t.Errorf("status should be %d for %q; is %d", http.StatusBadRequest, query, rec.Code)
+		}
+	}
+}
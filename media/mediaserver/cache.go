@@ -0,0 +1,51 @@
+package mediaserver
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/media/mediaserver/routes"
+)
+
+// versionETag returns the ETag of a resource with the given UUID that belongs
+// to an aggregate with the given version. The ETag changes whenever the
+// aggregate changes.
+func versionETag(id uuid.UUID, version int) string {
+	return `"` + id.String() + "." + strconv.Itoa(version) + `"`
+}
+
+// cacheable sets the ETag header and the configured Cache-Control header (see
+// routes.CacheControl) of a successful response to the given route. If the
+// If-None-Match header of the request matches etag, 304 Not Modified is
+// written and false is returned.
+func cacheable(w http.ResponseWriter, r *http.Request, rs routes.Routes, route routes.Route, etag string) bool {
+	h := w.Header()
+	h.Set("ETag", etag)
+	if cc := rs.CacheControl(route); cc != "" {
+		h.Set("Cache-Control", cc)
+	}
+
+	if matchETag(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return false
+	}
+
+	return true
+}
+
+// matchETag returns whether the value of an If-None-Match header matches etag.
+// Weak ETags are compared by their opaque tag, as required for If-None-Match.
+func matchETag(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
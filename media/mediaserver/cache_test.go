@@ -0,0 +1,73 @@
+package mediaserver_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/media/mediaserver"
+	"github.com/modernice/nice-cms/media/mediaserver/routes"
+)
+
+func TestServer_conditionalRequests(t *testing.T) {
+	shelf := document.JSONShelf{ID: shelfID, Name: "foo", Version: 3, Documents: []document.Document{{ID: documentID}}}
+	g := gallery.JSONGallery{ID: galleryID, Name: "foo", Version: 5}
+
+	srv := mediaserver.New(
+		&commandBus{},
+		mediaserver.WithDocuments(documentClient{shelf}, "", routes.CacheControl("no-cache")),
+		mediaserver.WithGalleries(galleryClient{g}, routes.CacheControl("public, max-age=60", routes.ShowGallery)),
+	)
+
+	tests := []struct {
+		path         string
+		etag         string
+		cacheControl string
+	}{
+		{"/shelfs/" + shelfID.String(), `"` + shelfID.String() + `.3"`, "no-cache"},
+		{"/shelfs/" + shelfID.String() + "/documents/" + documentID.String(), `"` + documentID.String() + `.3"`, "no-cache"},
+		{"/galleries/" + galleryID.String(), `"` + galleryID.String() + `.5"`, "public, max-age=60"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+			}
+			if got := rec.Header().Get("ETag"); got != tt.etag {
+				t.Fatalf("ETag should be %s; is %s", tt.etag, got)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != tt.cacheControl {
+				t.Fatalf("Cache-Control should be %q; is %q", tt.cacheControl, got)
+			}
+
+			for _, header := range []string{tt.etag, "W/" + tt.etag, `"other", ` + tt.etag, "*"} {
+				req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+				req.Header.Set("If-None-Match", header)
+				rec = httptest.NewRecorder()
+				srv.ServeHTTP(rec, req)
+
+				if rec.Code != http.StatusNotModified {
+					t.Fatalf("status should be %d for If-None-Match %s; is %d", http.StatusNotModified, header, rec.Code)
+				}
+				if rec.Body.Len() != 0 {
+					t.Fatalf("304 response should have no body; got %s", rec.Body)
+				}
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("If-None-Match", `"other"`)
+			rec = httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status should be %d for a stale ETag; is %d", http.StatusOK, rec.Code)
+			}
+		})
+	}
+}
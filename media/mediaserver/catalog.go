@@ -0,0 +1,23 @@
+package mediaserver
+
+import (
+	"net/http"
+
+	"github.com/modernice/nice-cms/catalog"
+	"github.com/modernice/nice-cms/internal/api"
+	"github.com/modernice/nice-cms/media/mediaserver/routes"
+)
+
+// WithCatalog returns an Option that adds the catalog route to the media
+// server. The route serves the events and commands of the Catalog, so that
+// integration teams can discover the event contract without reading source:
+//
+//	mediaserver.WithCatalog(catalog.Default())
+func WithCatalog(c catalog.Catalog, opts ...routes.Option) Option {
+	return func(s *Server) {
+		r := routes.New(opts...)
+		r.Install(s.router, routes.Catalog, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			api.JSON(w, req, http.StatusOK, c)
+		}))
+	}
+}
@@ -0,0 +1,37 @@
+package mediaserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modernice/nice-cms/catalog"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/media/mediaserver"
+)
+
+func TestWithCatalog(t *testing.T) {
+	srv := mediaserver.New(&commandBus{}, mediaserver.WithCatalog(catalog.Default()))
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/catalog", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+
+	var c catalog.Catalog
+	if err := json.NewDecoder(rec.Body).Decode(&c); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	e, ok := c.Command(gallery.TagStackCommand)
+	if !ok {
+		t.Fatalf("response should contain the %q command", gallery.TagStackCommand)
+	}
+
+	if e.Aggregate != gallery.Aggregate || e.Payload.Properties["Tags"] == nil {
+		t.Fatalf("command should belong to %q and describe its payload; got %v", gallery.Aggregate, e)
+	}
+}
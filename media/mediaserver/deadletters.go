@@ -0,0 +1,66 @@
+package mediaserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/internal/api"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/media/mediaserver/routes"
+)
+
+// WithDeadLetters returns an Option that adds the dead-letter routes to the
+// media server. The list route returns the DeadLetters of all galleries, or
+// of a single gallery using the "galleryId" query parameter:
+//
+//	GET /dead-letters?galleryId=...
+//
+// The redispatch route dispatches the ReprocessStack command for the stack of
+// a DeadLetter and responds with 202 Accepted:
+//
+//	POST /dead-letters/{GalleryID}/{StackID}
+func WithDeadLetters(letters *gallery.DeadLetters, opts ...routes.Option) Option {
+	return func(s *Server) {
+		r := routes.New(opts...)
+
+		install(s.router, r, routes.ListDeadLetters, func(w http.ResponseWriter, req *http.Request) {
+			var galleryID uuid.UUID
+			if raw := req.URL.Query().Get("galleryId"); raw != "" {
+				id, err := api.ParseUUID(raw, "galleryId")
+				if err != nil {
+					api.Error(w, req, http.StatusBadRequest, err)
+					return
+				}
+				galleryID = id
+			}
+
+			api.JSON(w, req, http.StatusOK, deadLettersResponse{
+				DeadLetters: letters.Letters(galleryID),
+			})
+		})
+
+		install(s.router, r, routes.RedispatchDeadLetter, func(w http.ResponseWriter, req *http.Request) {
+			galleryID, stackID := api.UUIDParam(req, "GalleryID"), api.UUIDParam(req, "StackID")
+
+			letter, ok := letters.Letter(galleryID, stackID)
+			if !ok {
+				api.Error(w, req, http.StatusNotFound, api.Friendly(
+					fmt.Errorf("no dead letter for stack %q", stackID),
+					"Stack %q has no dead letter.", stackID,
+				))
+				return
+			}
+
+			if !dispatchCommand(w, req, s.commands, gallery.ReprocessStack(galleryID, stackID).Any()) {
+				return
+			}
+
+			api.JSON(w, req, http.StatusAccepted, letter)
+		})
+	}
+}
+
+type deadLettersResponse struct {
+	DeadLetters []gallery.DeadLetter `json:"deadLetters"`
+}
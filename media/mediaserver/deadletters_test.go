@@ -0,0 +1,77 @@
+package mediaserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/media/mediaserver"
+	"github.com/modernice/nice-cms/media/mediaserver/routes"
+)
+
+func TestWithDeadLetters(t *testing.T) {
+	letters := gallery.NewDeadLetters()
+	galleryID, stackID := uuid.New(), uuid.New()
+	letters.ApplyEvent(event.New(gallery.StackProcessingFailed, gallery.StackProcessingFailedData{
+		StackID:  stackID,
+		Error:    "mock error",
+		Attempts: 2,
+	}, event.Aggregate(galleryID, gallery.Aggregate, 1)).Any())
+	letters.ApplyEvent(event.New(gallery.StackProcessingFailed, gallery.StackProcessingFailedData{
+		StackID: uuid.New(),
+		Error:   "mock error",
+	}, event.Aggregate(uuid.New(), gallery.Aggregate, 1)).Any())
+
+	bus := &commandBus{}
+	srv := mediaserver.New(bus, mediaserver.WithDeadLetters(letters, routes.Prefix("/media")))
+
+	serve := func(method, url string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(method, url, nil))
+		return rec
+	}
+
+	var resp struct {
+		DeadLetters []gallery.DeadLetter `json:"deadLetters"`
+	}
+
+	rec := serve(http.MethodGet, "/media/dead-letters")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.DeadLetters) != 2 {
+		t.Fatalf("response should contain 2 DeadLetters; contains %d", len(resp.DeadLetters))
+	}
+
+	rec = serve(http.MethodGet, "/media/dead-letters?galleryId="+galleryID.String())
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.DeadLetters) != 1 || resp.DeadLetters[0].StackID != stackID || resp.DeadLetters[0].Attempts != 2 {
+		t.Fatalf("response should contain the DeadLetter of Stack %q; got %v", stackID, resp.DeadLetters)
+	}
+
+	if rec := serve(http.MethodGet, "/media/dead-letters?galleryId=foo"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("status should be %d for an invalid gallery UUID; is %d", http.StatusBadRequest, rec.Code)
+	}
+
+	if rec := serve(http.MethodPost, "/media/dead-letters/"+galleryID.String()+"/"+uuid.NewString()); rec.Code != http.StatusNotFound {
+		t.Fatalf("status should be %d for a Stack without DeadLetter; is %d", http.StatusNotFound, rec.Code)
+	}
+
+	rec = serve(http.MethodPost, "/media/dead-letters/"+galleryID.String()+"/"+stackID.String())
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusAccepted, rec.Code, rec.Body)
+	}
+
+	if len(bus.dispatched) != 1 || bus.dispatched[0] != gallery.ReprocessStackCommand {
+		t.Fatalf("%q command should have been dispatched; dispatched %v", gallery.ReprocessStackCommand, bus.dispatched)
+	}
+}
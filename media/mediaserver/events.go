@@ -0,0 +1,167 @@
+package mediaserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/internal/api"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/media/mediaserver/routes"
+)
+
+// keepAliveInterval is the interval at which a comment is sent to idle event
+// streams, so that proxies don't close the connection.
+const keepAliveInterval = 15 * time.Second
+
+// Names of the Server-Sent Events of the gallery event stream.
+const (
+	sseStackProcessed = "stackProcessed"
+	sseStackDeleted   = "stackDeleted"
+	sseStackTrashed   = "stackTrashed"
+	sseStackRestored  = "stackRestored"
+	sseImageReplaced  = "imageReplaced"
+)
+
+// WithGalleryEvents returns an Option that adds a Server-Sent Events route to
+// the media server. Clients that connect to the route are notified when a
+// Stack of the gallery was processed, deleted, trashed, restored or replaced,
+// so that they don't have to poll the gallery:
+//
+//	const events = new EventSource('/galleries/{GalleryID}/events')
+//	events.addEventListener('stackProcessed', (e) => { ... })
+func WithGalleryEvents(bus event.Bus, opts ...routes.Option) Option {
+	return func(s *Server) {
+		newGalleryEventServer(s.router, bus, routes.New(opts...))
+	}
+}
+
+type galleryEventServer struct {
+	chi.Router
+
+	bus    event.Bus
+	routes routes.Routes
+}
+
+// galleryEvent is the data of a Server-Sent Event of the gallery event stream.
+type galleryEvent struct {
+	GalleryID uuid.UUID     `json:"galleryId"`
+	StackID   uuid.UUID     `json:"stackId"`
+	Stack     gallery.Stack `json:"stack"`
+}
+
+func newGalleryEventServer(router chi.Router, bus event.Bus, routes routes.Routes) *galleryEventServer {
+	srv := galleryEventServer{
+		Router: router,
+		bus:    bus,
+		routes: routes,
+	}
+	srv.init()
+	return &srv
+}
+
+func (s *galleryEventServer) init() {
+	install(s, s.routes, routes.StreamGalleryEvents, s.stream)
+}
+
+func (s *galleryEventServer) stream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(nil, "Streaming is not supported."))
+		return
+	}
+
+	galleryID := api.UUIDParam(r, "GalleryID")
+
+	events, errs, err := s.bus.Subscribe(
+		r.Context(),
+		gallery.StackProcessed,
+		gallery.StackDeleted,
+		gallery.StackTrashed,
+		gallery.StackRestored,
+		gallery.ImageReplaced,
+	)
+	if err != nil {
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to subscribe to gallery events: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case _, ok := <-errs:
+			if !ok {
+				errs = nil
+			}
+		case evt, ok := <-events:
+			if !ok {
+				return
+			}
+
+			name, data, ok := sseEvent(galleryID, evt)
+			if !ok {
+				break
+			}
+
+			b, err := json.Marshal(data)
+			if err != nil {
+				break
+			}
+
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, b); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// sseEvent returns the name and data of the Server-Sent Event for evt. If evt
+// doesn't belong to the given gallery, false is returned.
+func sseEvent(galleryID uuid.UUID, evt event.Event) (string, galleryEvent, bool) {
+	if id, _, _ := evt.Aggregate(); id != galleryID {
+		return "", galleryEvent{}, false
+	}
+
+	var name string
+	var stack gallery.Stack
+	switch data := evt.Data().(type) {
+	case gallery.StackProcessedData:
+		name, stack = sseStackProcessed, data.Stack
+	case gallery.StackDeletedData:
+		name, stack = sseStackDeleted, data.Stack
+	case gallery.StackTrashedData:
+		name, stack = sseStackTrashed, data.Stack
+	case gallery.StackRestoredData:
+		name, stack = sseStackRestored, data.Stack
+	case gallery.ImageReplacedData:
+		name, stack = sseImageReplaced, data.Stack
+	default:
+		return "", galleryEvent{}, false
+	}
+
+	return name, galleryEvent{
+		GalleryID: galleryID,
+		StackID:   stack.ID,
+		Stack:     stack,
+	}, true
+}
@@ -0,0 +1,116 @@
+package mediaserver_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/media/mediaserver"
+)
+
+func TestWithGalleryEvents(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	bus := eventbus.New()
+	srv := httptest.NewServer(mediaserver.New(&commandBus{}, mediaserver.WithGalleryEvents(bus)))
+	defer srv.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/galleries/"+galleryID.String()+"/events", nil)
+	if err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("connect to event stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type should be %q; is %q", "text/event-stream", ct)
+	}
+
+	otherStack := gallery.Stack{ID: uuid.New()}
+	stack := gallery.Stack{ID: stackID}
+
+	r := bufio.NewReader(resp.Body)
+
+	tests := []struct {
+		events []event.Event
+		want   string
+	}{
+		{
+			events: []event.Event{
+				event.New(gallery.StackProcessed, gallery.StackProcessedData{Stack: otherStack}, event.Aggregate(uuid.New(), gallery.Aggregate, 1)).Any(),
+				event.New(gallery.StackTagged, gallery.StackTaggedData{StackID: stackID}, event.Aggregate(galleryID, gallery.Aggregate, 1)).Any(),
+				event.New(gallery.StackProcessed, gallery.StackProcessedData{Stack: stack}, event.Aggregate(galleryID, gallery.Aggregate, 2)).Any(),
+			},
+			want: "stackProcessed",
+		},
+		{
+			events: []event.Event{
+				event.New(gallery.ImageReplaced, gallery.ImageReplacedData{Stack: stack}, event.Aggregate(galleryID, gallery.Aggregate, 3)).Any(),
+			},
+			want: "imageReplaced",
+		},
+		{
+			events: []event.Event{
+				event.New(gallery.StackDeleted, gallery.StackDeletedData{Stack: stack}, event.Aggregate(galleryID, gallery.Aggregate, 4)).Any(),
+			},
+			want: "stackDeleted",
+		},
+	}
+
+	for _, tt := range tests {
+		if err := bus.Publish(ctx, tt.events...); err != nil {
+			t.Fatalf("publish events: %v", err)
+		}
+
+		name, data := readSSE(t, r)
+		if name != tt.want {
+			t.Fatalf("event should be %q; got %q", tt.want, name)
+		}
+
+		var payload struct {
+			GalleryID uuid.UUID `json:"galleryId"`
+			StackID   uuid.UUID `json:"stackId"`
+		}
+		if err := json.Unmarshal([]byte(data), &payload); err != nil {
+			t.Fatalf("decode event data: %v", err)
+		}
+
+		if payload.GalleryID != galleryID || payload.StackID != stackID {
+			t.Fatalf("event should be sent for Stack %q of Gallery %q; got %+v", stackID, galleryID, payload)
+		}
+	}
+}
+
+func readSSE(t *testing.T, r *bufio.Reader) (string, string) {
+	var name, data string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event stream: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+
+		switch {
+		case line == "" && name != "":
+			return name, data
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
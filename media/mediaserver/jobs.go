@@ -0,0 +1,169 @@
+package mediaserver
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/modernice/nice-cms/internal/api"
+	"github.com/modernice/nice-cms/jobs"
+	"github.com/modernice/nice-cms/media/export"
+	"github.com/modernice/nice-cms/media/mediaserver/routes"
+)
+
+// WithJobs returns an Option that adds the export and job routes to the media
+// server. Exports are started as Jobs of the given Runner, which must have
+// Handlers for the export.GalleryKind and export.ShelfKind Jobs:
+//
+//	runner := jobs.NewRunner(
+//		jobs.FSStore("/var/lib/cms/jobs"),
+//		jobs.Artifacts(storage, "exports"),
+//		jobs.Handle(export.GalleryKind, export.Gallery(galleries, storage)),
+//		jobs.Handle(export.ShelfKind, export.Shelf(shelfs, storage)),
+//	)
+func WithJobs(runner *jobs.Runner, opts ...routes.Option) Option {
+	return func(s *Server) {
+		newJobServer(s.router, runner, routes.New(opts...))
+	}
+}
+
+type jobServer struct {
+	chi.Router
+
+	runner *jobs.Runner
+	routes routes.Routes
+}
+
+func newJobServer(router chi.Router, runner *jobs.Runner, routes routes.Routes) *jobServer {
+	s := jobServer{
+		Router: router,
+		runner: runner,
+		routes: routes,
+	}
+	s.init()
+	return &s
+}
+
+func (s *jobServer) init() {
+	install(s, s.routes, routes.ExportGallery, s.exportGallery)
+	install(s, s.routes, routes.ExportShelf, s.exportShelf)
+	install(s, s.routes, routes.ShowJob, s.showJob)
+	install(s, s.routes, routes.CancelJob, s.cancelJob)
+	install(s, s.routes, routes.ShowJobArtifact, s.showArtifact)
+}
+
+// jobResponse is the response body of a Job.
+type jobResponse struct {
+	jobs.Job
+	Links jobLinks `json:"links"`
+}
+
+// jobLinks are the URLs of a Job. They honor the route prefix of the server
+// component.
+type jobLinks struct {
+	// Self is the URL of the Job. It is also sent as the Location header of
+	// started Jobs.
+	Self string `json:"self"`
+
+	// Artifact is the URL of the artifact of the Job. Artifact is empty until
+	// the Job has stored an artifact.
+	Artifact string `json:"artifact,omitempty"`
+}
+
+func (s *jobServer) response(job jobs.Job) jobResponse {
+	res := jobResponse{
+		Job:   job,
+		Links: jobLinks{Self: s.routes.URL(routes.ShowJob, job.ID)},
+	}
+	if job.Artifact != nil {
+		res.Links.Artifact = s.routes.URL(routes.ShowJobArtifact, job.ID)
+	}
+	return res
+}
+
+func (s *jobServer) exportGallery(w http.ResponseWriter, r *http.Request) {
+	s.start(w, r, export.GalleryKind, export.GalleryParams{GalleryID: api.UUIDParam(r, "GalleryID")})
+}
+
+func (s *jobServer) exportShelf(w http.ResponseWriter, r *http.Request) {
+	s.start(w, r, export.ShelfKind, export.ShelfParams{ShelfID: api.UUIDParam(r, "ShelfID")})
+}
+
+func (s *jobServer) start(w http.ResponseWriter, r *http.Request, kind string, params any) {
+	job, err := s.runner.Start(r.Context(), kind, params)
+	if err != nil {
+		if errors.Is(err, jobs.ErrUnknownKind) {
+			api.Error(w, r, http.StatusNotImplemented, api.Friendly(err, "Exports are not supported."))
+			return
+		}
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to start export: %v", err))
+		return
+	}
+
+	res := s.response(job)
+	w.Header().Set("Location", res.Links.Self)
+	api.JSON(w, r, http.StatusAccepted, res)
+}
+
+func (s *jobServer) showJob(w http.ResponseWriter, r *http.Request) {
+	id := api.UUIDParam(r, "JobID")
+	job, err := s.runner.Job(r.Context(), id)
+	if err != nil {
+		api.Error(w, r, jobErrorStatus(err), api.Friendly(err, "Failed to fetch job %q: %v", id, err))
+		return
+	}
+	api.JSON(w, r, http.StatusOK, s.response(job))
+}
+
+func (s *jobServer) cancelJob(w http.ResponseWriter, r *http.Request) {
+	id := api.UUIDParam(r, "JobID")
+	job, err := s.runner.Cancel(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, jobs.ErrFinished) {
+			api.Error(w, r, http.StatusConflict, api.Friendly(err, "Job %q has already finished.", id))
+			return
+		}
+		api.Error(w, r, jobErrorStatus(err), api.Friendly(err, "Failed to cancel job %q: %v", id, err))
+		return
+	}
+	api.JSON(w, r, http.StatusOK, s.response(job))
+}
+
+func (s *jobServer) showArtifact(w http.ResponseWriter, r *http.Request) {
+	id := api.UUIDParam(r, "JobID")
+	job, content, err := s.runner.Artifact(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, jobs.ErrNoArtifact):
+			api.Error(w, r, http.StatusNotFound, api.Friendly(err, "Job %q has no artifact.", id))
+		case errors.Is(err, jobs.ErrArtifactExpired):
+			api.Error(w, r, http.StatusGone, api.Friendly(err, "The artifact of job %q has expired.", id))
+		default:
+			api.Error(w, r, jobErrorStatus(err), api.Friendly(err, "Failed to read artifact of job %q: %v", id, err))
+		}
+		return
+	}
+	defer content.Close()
+
+	artifact := job.Artifact
+	if artifact.ContentType != "" {
+		w.Header().Set("Content-Type", artifact.ContentType)
+	}
+	w.Header().Set("Content-Length", strconv.FormatInt(artifact.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": artifact.Name}))
+	w.WriteHeader(http.StatusOK)
+
+	io.Copy(w, content)
+}
+
+// jobErrorStatus returns 404 Not Found if err indicates a missing Job and 500
+// Internal Server Error otherwise.
+func jobErrorStatus(err error) int {
+	if errors.Is(err, jobs.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,145 @@
+package mediaserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/jobs"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/export"
+	"github.com/modernice/nice-cms/media/mediaserver"
+	"github.com/modernice/nice-cms/media/mediaserver/routes"
+)
+
+func TestWithJobs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv, runner := newJobServer(t, ctx, time.Hour)
+
+	rec := serveJob(srv, routes.ExportGallery, galleryID)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status should be %d; is %d", http.StatusAccepted, rec.Code)
+	}
+
+	res := decodeJob(t, rec)
+
+	if loc := rec.Header().Get("Location"); loc != routes.URL(routes.ShowJob, res.ID) {
+		t.Fatalf("Location header should be %q; is %q", routes.URL(routes.ShowJob, res.ID), loc)
+	}
+
+	awaitJob(t, runner, res.ID)
+
+	rec = serveJob(srv, routes.ShowJob, res.ID)
+	if res = decodeJob(t, rec); res.Status != jobs.StatusSucceeded {
+		t.Fatalf("Job should have status %q; has %q", jobs.StatusSucceeded, res.Status)
+	}
+	if res.Links.Artifact != routes.URL(routes.ShowJobArtifact, res.ID) {
+		t.Fatalf("artifact link should be %q; is %q", routes.URL(routes.ShowJobArtifact, res.ID), res.Links.Artifact)
+	}
+
+	rec = serveJob(srv, routes.ShowJobArtifact, res.ID)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d", http.StatusOK, rec.Code)
+	}
+	if rec.Header().Get("Content-Type") != "application/zip" {
+		t.Fatalf("Content-Type should be %q; is %q", "application/zip", rec.Header().Get("Content-Type"))
+	}
+	if body := rec.Body.String(); body != galleryID.String() {
+		t.Fatalf("artifact should be %q; is %q", galleryID.String(), body)
+	}
+
+	if rec = serveJob(srv, routes.CancelJob, res.ID); rec.Code != http.StatusConflict {
+		t.Fatalf("canceling a finished Job should respond with %d; got %d", http.StatusConflict, rec.Code)
+	}
+
+	if rec = serveJob(srv, routes.ShowJob, uuid.New()); rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown Jobs should respond with %d; got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestWithJobs_expired(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv, runner := newJobServer(t, ctx, time.Millisecond)
+
+	res := decodeJob(t, serveJob(srv, routes.ExportGallery, galleryID))
+	awaitJob(t, runner, res.ID)
+
+	<-time.After(10 * time.Millisecond)
+
+	if rec := serveJob(srv, routes.ShowJobArtifact, res.ID); rec.Code != http.StatusGone {
+		t.Fatalf("expired artifacts should respond with %d; got %d", http.StatusGone, rec.Code)
+	}
+}
+
+type jobResponse struct {
+	jobs.Job
+	Links struct {
+		Self     string `json:"self"`
+		Artifact string `json:"artifact"`
+	} `json:"links"`
+}
+
+func newJobServer(t *testing.T, ctx context.Context, ttl time.Duration) (*mediaserver.Server, *jobs.Runner) {
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+	runner := jobs.NewRunner(
+		jobs.MemoryStore(),
+		jobs.Artifacts(storage, "foo-disk"),
+		jobs.ArtifactTTL(ttl),
+		jobs.Handle(export.GalleryKind, func(c *jobs.Context) error {
+			var params export.GalleryParams
+			if err := c.Params(&params); err != nil {
+				return err
+			}
+			return c.Artifact("foo.zip", "application/zip", strings.NewReader(params.GalleryID.String()))
+		}),
+	)
+
+	if _, err := runner.Run(ctx); err != nil {
+		t.Fatalf("run Runner: %v", err)
+	}
+
+	return mediaserver.New(&commandBus{}, mediaserver.WithJobs(runner)), runner
+}
+
+func serveJob(srv http.Handler, route routes.Route, id uuid.UUID) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(route.Method, routes.URL(route, id), nil))
+	return rec
+}
+
+func decodeJob(t *testing.T, rec *httptest.ResponseRecorder) jobResponse {
+	t.Helper()
+	var res jobResponse
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return res
+}
+
+func awaitJob(t *testing.T, runner *jobs.Runner, id uuid.UUID) {
+	t.Helper()
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case <-timeout:
+			t.Fatalf("timed out")
+		case <-time.After(5 * time.Millisecond):
+		}
+		job, err := runner.Job(context.Background(), id)
+		if err != nil {
+			t.Fatalf("fetch job: %v", err)
+		}
+		if job.Status.Finished() {
+			return
+		}
+	}
+}
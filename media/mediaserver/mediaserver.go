@@ -2,18 +2,32 @@ package mediaserver
 
 import (
 	"context"
+	"errors"
 	"io"
+	"mime"
 	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/modernice/goes/command"
 	"github.com/modernice/goes/command/cmdbus/dispatch"
 	"github.com/modernice/nice-cms/internal/api"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/delta"
 	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image"
 	"github.com/modernice/nice-cms/media/image/gallery"
 	"github.com/modernice/nice-cms/media/mediaserver/routes"
+	"github.com/modernice/nice-cms/media/usage"
+	"github.com/modernice/nice-cms/projector"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Use github.com/modernice/nice-cms/media/mediarpc.NewClient to get a gRPC DocumentClient.
@@ -21,48 +35,165 @@ type DocumentClient interface {
 	LookupShelfByName(context.Context, string) (uuid.UUID, bool, error)
 	UploadDocument(_ context.Context, shelfID uuid.UUID, _ io.Reader, uniqueName, name, disk, path string) (document.Document, error)
 	ReplaceDocument(_ context.Context, shelfID, documentID uuid.UUID, _ io.Reader) (document.Document, error)
+	ReplaceDocumentDelta(_ context.Context, shelfID, documentID uuid.UUID, _ delta.Delta) (document.Document, error)
 	FetchShelf(context.Context, uuid.UUID) (document.JSONShelf, error)
+	ListDocuments(_ context.Context, shelfID uuid.UUID, _ document.Query) (document.DocumentPage, error)
+	SearchDocuments(context.Context, document.SearchQuery) (document.SearchResults, error)
+	ListShelfs(_ context.Context, offset, limit int) (document.ShelfList, error)
+}
+
+// DocumentCommandClient is a DocumentClient that also changes documents. If
+// the DocumentClient of the Server implements DocumentCommandClient, documents
+// are tagged, untagged, renamed and deleted through the client instead of the
+// command bus (see GalleryCommandClient).
+type DocumentCommandClient interface {
+	DocumentClient
+
+	TagDocument(_ context.Context, shelfID, documentID uuid.UUID, tags []string) (document.Document, error)
+	UntagDocument(_ context.Context, shelfID, documentID uuid.UUID, tags []string) (document.Document, error)
+	RenameDocument(_ context.Context, shelfID, documentID uuid.UUID, name string) (document.Document, error)
+	DeleteDocument(_ context.Context, shelfID, documentID uuid.UUID) error
 }
 
 // Use github.com/modernice/nice-cms/media/mediarpc.NewClient to get a gRPC GalleryClient.
 type GalleryClient interface {
 	LookupGalleryByName(context.Context, string) (uuid.UUID, bool, error)
 	LookupGalleryStackByName(_ context.Context, galleryID uuid.UUID, name string) (uuid.UUID, bool, error)
+	LookupGalleryStacksByTag(_ context.Context, galleryID uuid.UUID, tag string) ([]uuid.UUID, bool, error)
 	UploadImage(_ context.Context, galleryID uuid.UUID, _ io.Reader, name, disk, path string) (gallery.Stack, error)
+	UploadVideo(_ context.Context, galleryID uuid.UUID, _ io.Reader, name, disk, path string) (gallery.Stack, error)
 	ReplaceImage(_ context.Context, galleryID, stackID uuid.UUID, _ io.Reader) (gallery.Stack, error)
 	FetchGallery(context.Context, uuid.UUID) (gallery.JSONGallery, error)
+	FetchGalleryIndex(context.Context, uuid.UUID) (gallery.JSONIndex, error)
+	ListStacks(_ context.Context, galleryID uuid.UUID, _ gallery.Query) (gallery.StackPage, error)
+	FetchStack(_ context.Context, galleryID, stackID uuid.UUID) (gallery.Stack, error)
+	ListGalleries(_ context.Context, offset, limit int) (gallery.GalleryList, error)
+}
+
+// GalleryCommandClient is a GalleryClient that also changes galleries. If the
+// GalleryClient of the Server implements GalleryCommandClient, galleries are
+// sorted and stacks are tagged, untagged, renamed and deleted through the
+// client instead of the command bus, so that the Server can run against a
+// remote media service (see mediarpc.Client) without a shared command bus.
+type GalleryCommandClient interface {
+	GalleryClient
+
+	SortGallery(_ context.Context, galleryID uuid.UUID, sorting []uuid.UUID) error
+	TagStack(_ context.Context, galleryID, stackID uuid.UUID, tags []string) (gallery.Stack, error)
+	UntagStack(_ context.Context, galleryID, stackID uuid.UUID, tags []string) (gallery.Stack, error)
+	RenameStack(_ context.Context, galleryID, stackID uuid.UUID, name string) (gallery.Stack, error)
+	DeleteStack(_ context.Context, galleryID, stackID uuid.UUID) error
 }
 
 // Server is the media server.
 type Server struct {
 	router chi.Router
 
-	commands command.Bus
+	commands    command.Bus
+	storage     media.Storage
+	placeholder *Placeholder
+
+	// mounts mount the routes that depend on other options. They are run by
+	// New after all options were applied, so that the order of the options
+	// doesn't matter.
+	mounts []func()
 }
 
 // Option is server option.
 type Option func(*Server)
 
+// WithStorage returns an Option that provides the media server with the
+// Storage that is used to serve the contents of documents and images. Without
+// a Storage, the content routes respond with 501 Not Implemented.
+func WithStorage(storage media.Storage) Option {
+	return func(s *Server) {
+		s.storage = storage
+	}
+}
+
 // WithGalleries returns an Option that adds gallery routes to the media server.
 func WithGalleries(client GalleryClient, opts ...routes.Option) Option {
 	return func(s *Server) {
-		s.router.Mount("/", newGalleryServer(client, s.commands, routes.New(opts...)))
+		s.mounts = append(s.mounts, func() {
+			newGalleryServer(s.router, client, s.commands, s.storage, s.placeholder, routes.New(opts...))
+		})
 	}
 }
 
 // WithDocuments returns an Option that adds document routes to the media server.
 func WithDocuments(client DocumentClient, routePrefix string, opts ...routes.Option) Option {
 	return func(s *Server) {
-		s.router.Mount("/", newDocumentServer(client, s.commands, routes.New(opts...)))
+		s.mounts = append(s.mounts, func() {
+			newDocumentServer(s.router, client, s.commands, s.storage, routes.New(opts...))
+		})
+	}
+}
+
+// StatusReporter reports the status of a projection.
+// *document.Lookup and *gallery.Lookup implement StatusReporter.
+type StatusReporter interface {
+	Status() projector.Status
+}
+
+// DiskUsageReporter reports the storage usage of disks.
+// *usage.Index implements DiskUsageReporter.
+type DiskUsageReporter interface {
+	Disks() map[string]usage.Disk
+}
+
+// WithHealth returns an Option that adds a health route to the media server.
+// The health route reports the status of the provided projections and responds
+// with 503 Service Unavailable if one of them is not healthy. Projections that
+// also implement DiskUsageReporter additionally report the usage and usage
+// level of their disks. Disk usage levels don't affect the response status.
+func WithHealth(projections map[string]StatusReporter, opts ...routes.Option) Option {
+	return func(s *Server) {
+		routes.New(opts...).Install(s.router, routes.Health, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resp := healthResponse{
+				Status:      "ok",
+				Projections: make(map[string]projector.Status, len(projections)),
+			}
+			status := http.StatusOK
+			for name, p := range projections {
+				ps := p.Status()
+				if !ps.Healthy() {
+					resp.Status = "unavailable"
+					status = http.StatusServiceUnavailable
+				}
+				resp.Projections[name] = ps
+
+				if r, ok := p.(DiskUsageReporter); ok {
+					if resp.Disks == nil {
+						resp.Disks = make(map[string]usage.Disk)
+					}
+					for disk, d := range r.Disks() {
+						resp.Disks[disk] = d
+					}
+				}
+			}
+			api.JSON(w, r, status, resp)
+		}))
 	}
 }
 
+type healthResponse struct {
+	Status      string                      `json:"status"`
+	Projections map[string]projector.Status `json:"projections"`
+	Disks       map[string]usage.Disk       `json:"disks,omitempty"`
+}
+
 // New returns the media server. Use the WithXXX Options to add routes to the
 // media server:
 //
 //	var commands command.Bus
 //	client := mediarpc.NewClient(...)
 //	srv := New(commands, WithDocuments(client, "/shelfs"), WithGalleries(client, "/galleries"))
+//
+// The command bus may be nil to run the media server decoupled from the media
+// service, e.g. against a remote mediarpc.Client. Changes are then only made
+// through clients that implement DocumentCommandClient or
+// GalleryCommandClient; the routes of all other changes respond with 501 Not
+// Implemented.
 func New(commands command.Bus, opts ...Option) *Server {
 	s := Server{
 		router:   chi.NewRouter(),
@@ -71,6 +202,9 @@ func New(commands command.Bus, opts ...Option) *Server {
 	for _, opt := range opts {
 		opt(&s)
 	}
+	for _, mount := range s.mounts {
+		mount()
+	}
 	return &s
 }
 
@@ -78,19 +212,66 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.router.ServeHTTP(w, r)
 }
 
+// serveFile writes the contents of f to w. Range requests and HEAD requests
+// are handled by http.ServeContent.
+func serveFile(w http.ResponseWriter, r *http.Request, storage media.Storage, f media.File) {
+	if storage == nil {
+		api.Error(w, r, http.StatusNotImplemented, api.Friendly(nil, "Serving file contents is not supported."))
+		return
+	}
+
+	content, err := f.Open(r.Context(), storage)
+	if err != nil {
+		if errors.Is(err, media.ErrFileNotFound) {
+			api.Error(w, r, http.StatusNotFound, api.Friendly(err, "File %q not found.", f.Path))
+			return
+		}
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to open file: %v", err))
+		return
+	}
+	defer content.Close()
+
+	if typ := mime.TypeByExtension(path.Ext(f.Path)); typ != "" {
+		w.Header().Set("Content-Type", typ)
+	}
+
+	http.ServeContent(w, r, path.Base(f.Path), time.Time{}, content)
+}
+
+// isMissing returns whether f is missing from storage. Opening a file on a
+// RangeDisk doesn't access the file, so the first byte is read instead.
+func isMissing(ctx context.Context, storage media.Storage, f media.File) (bool, error) {
+	if f.Path == "" {
+		return true, nil
+	}
+	disk, err := storage.Disk(f.Disk)
+	if err != nil {
+		return false, err
+	}
+	if _, err := media.GetRange(ctx, disk, f.Location(), 0, 1); err != nil {
+		if errors.Is(err, media.ErrFileNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+	return false, nil
+}
+
 type documentServer struct {
 	chi.Router
 
 	client   DocumentClient
 	commands command.Bus
+	storage  media.Storage
 	routes   routes.Routes
 }
 
-func newDocumentServer(client DocumentClient, commands command.Bus, routes routes.Routes) *documentServer {
+func newDocumentServer(router chi.Router, client DocumentClient, commands command.Bus, storage media.Storage, routes routes.Routes) *documentServer {
 	s := documentServer{
-		Router:   chi.NewRouter(),
+		Router:   router,
 		client:   client,
 		commands: commands,
+		storage:  storage,
 		routes:   routes,
 	}
 	s.init()
@@ -98,576 +279,2332 @@ func newDocumentServer(client DocumentClient, commands command.Bus, routes route
 }
 
 func (s *documentServer) init() {
-	s.Get("/lookup/name/{Name}", s.lookupName)
-	s.Get("/{ShelfID}", s.showShelf)
-	s.Post("/{ShelfID}/documents", s.uploadDocument)
-	s.Put("/{ShelfID}/documents/{DocumentID}", s.replaceDocument)
-	s.Patch("/{ShelfID}/documents/{DocumentID}", s.updateDocument)
-	s.Delete("/{ShelfID}/documents/{DocumentID}", s.deleteDocument)
-	s.Post("/{ShelfID}/documents/{DocumentID}/tags", s.addTags)
-	s.Delete("/{ShelfID}/documents/{DocumentID}/tags/{Tags}", s.removeTags)
+	install(s, s.routes, routes.ListShelfs, s.listShelfs)
+	install(s, s.routes, routes.CreateShelf, s.createShelf)
+	install(s, s.routes, routes.LookupShelfByName, s.lookupName)
+	install(s, s.routes, routes.ShowShelf, s.showShelf)
+	install(s, s.routes, routes.RenameShelf, s.renameShelf)
+	install(s, s.routes, routes.DeleteShelf, s.deleteShelf)
+	install(s, s.routes, routes.SearchDocuments, s.search)
+	install(s, s.routes, routes.ShowDocument, s.showDocument)
+	install(s, s.routes, routes.ShowDocumentContent, s.showContent)
+	install(s, s.routes, routes.HeadDocumentContent, s.showContent)
+	install(s, s.routes, routes.ShowDocumentPreview, s.showPreview)
+	install(s, s.routes, routes.UploadDocument, s.uploadDocument)
+	install(s, s.routes, routes.ReplaceDocument, s.replaceDocument)
+	install(s, s.routes, routes.ReplaceDocumentDelta, s.replaceDocumentDelta)
+	install(s, s.routes, routes.UpdateDocument, s.updateDocument)
+	install(s, s.routes, routes.DeleteDocument, s.deleteDocument)
+	install(s, s.routes, routes.TagDocument, s.addTags)
+	install(s, s.routes, routes.UntagDocument, s.removeTags)
+	install(s, s.routes, routes.RetagDocuments, s.retagDocuments)
+	install(s, s.routes, routes.CopyDocument, s.copyDocument)
+	install(s, s.routes, routes.SignDocumentURL, s.signURL)
+	install(s, s.routes, routes.RestoreDocument, s.restoreDocument)
+	install(s, s.routes, routes.SetShelfLegalHold, s.setLegalHold)
+	install(s, s.routes, routes.LiftShelfLegalHold, s.liftLegalHold)
+	install(s, s.routes, routes.SetDocumentLegalHold, s.setLegalHold)
+	install(s, s.routes, routes.LiftDocumentLegalHold, s.liftLegalHold)
 }
 
-func (s *documentServer) lookupName(w http.ResponseWriter, r *http.Request) {
-	var resp struct {
-		ShelfID uuid.UUID `json:"shelfId"`
-	}
-
-	name := chi.URLParam(r, "Name")
+// install installs the handler for the given route. The UUIDs in the path of
+// the route are validated and bound to the request context by api.BindUUIDs.
+func install(router chi.Router, r routes.Routes, route routes.Route, h http.HandlerFunc) {
+	r.Install(router, route, api.BindUUIDs(h))
+}
 
-	id, ok, err := s.client.LookupShelfByName(r.Context(), name)
+// fetchShelf fetches the Shelf from the ShelfID URL parameter. If the Shelf
+// cannot be fetched, an error response is written and false is returned.
+func (s *documentServer) fetchShelf(w http.ResponseWriter, r *http.Request) (document.JSONShelf, bool) {
+	id := api.UUIDParam(r, "ShelfID")
+	shelf, err := s.client.FetchShelf(r.Context(), id)
 	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, err)
-		return
+		api.Error(w, r, errorStatus(err), api.Friendly(err, "Failed to fetch shelf %q: %v", id, err))
+		return shelf, false
 	}
+	return shelf, true
+}
+
+// fetchDocument fetches the Document from the ShelfID and DocumentID URL
+// parameters. If the Document cannot be fetched, an error response is written
+// and false is returned.
+func (s *documentServer) fetchDocument(w http.ResponseWriter, r *http.Request) (document.Document, bool) {
+	shelf, ok := s.fetchShelf(w, r)
 	if !ok {
-		api.Error(w, r, http.StatusNotFound, api.Friendly(nil, "No shelf named %q found.", name))
+		return document.Document{}, false
 	}
-	resp.ShelfID = id
 
-	api.JSON(w, r, http.StatusOK, resp)
+	id := api.UUIDParam(r, "DocumentID")
+	doc, err := shelf.Document(id)
+	if err != nil {
+		api.Error(w, r, http.StatusNotFound, api.Friendly(err, "Document %q not found.", id))
+		return doc, false
+	}
+
+	return doc, true
 }
 
-func (s *documentServer) showShelf(w http.ResponseWriter, r *http.Request) {
-	id, err := api.ExtractUUID(r, "ShelfID")
+// listShelfs returns a page of the UUIDs and names of all shelfs, sorted by
+// name. See aggregateList for the query parameters.
+func (s *documentServer) listShelfs(w http.ResponseWriter, r *http.Request) {
+	lq, err := aggregateList.Parse(r)
 	if err != nil {
 		api.Error(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	shelf, err := s.client.FetchShelf(r.Context(), id)
+	list, err := s.client.ListShelfs(r.Context(), lq.Offset, lq.Limit)
 	if err != nil {
-		api.Error(w, r, http.StatusNotFound, api.Friendly(err, "Shelf %q not found: %v.", id, err))
+		api.Error(w, r, errorStatus(err), api.Friendly(err, "Failed to list shelfs: %v", err))
 		return
 	}
 
-	api.JSON(w, r, http.StatusOK, shelf)
+	resp := shelfListResponse{ShelfList: list}
+	resp.PageMeta = api.WritePage(w, r, api.Page{
+		Total:  list.Total,
+		Offset: list.Offset,
+		Count:  len(list.Shelfs),
+	})
+
+	api.JSON(w, r, http.StatusOK, resp)
 }
 
-func (s *documentServer) uploadDocument(w http.ResponseWriter, r *http.Request) {
-	name := r.FormValue("name")
-	uniqueName := r.FormValue("uniqueName")
-	disk := r.FormValue("disk")
-	path := r.FormValue("path")
-	file, _, err := r.FormFile("document")
-	if err != nil {
-		api.Error(w, r, http.StatusUnprocessableEntity, api.Friendly(err, "Failed to parse file: %v", err))
+// aggregateList are the query parameters of the shelf and gallery lists. The
+// lists are sorted by name and have no filters.
+var aggregateList = api.List{
+	DefaultLimit: 50,
+	MaxLimit:     500,
+}
+
+type shelfListResponse struct {
+	document.ShelfList
+	api.PageMeta
+}
+
+// createShelf creates a Shelf with the name from the request body. The name
+// must not be used by another Shelf.
+func (s *documentServer) createShelf(w http.ResponseWriter, r *http.Request) {
+	name, ok := decodeCreateRequest(w, r)
+	if !ok {
 		return
 	}
-	defer file.Close()
 
-	shelfID, err := api.ExtractUUID(r, "ShelfID")
-	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, err)
+	if id, ok, err := s.client.LookupShelfByName(r.Context(), name); err != nil {
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to look up shelf name: %v", err))
+		return
+	} else if ok {
+		api.Error(w, r, http.StatusConflict, api.Friendly(nil, "Name %q is already used by shelf %q.", name, id))
 		return
 	}
 
-	doc, err := s.client.UploadDocument(r.Context(), shelfID, file, uniqueName, name, disk, path)
-	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to upload document to shelf: %v", err))
+	id := uuid.New()
+	if !s.dispatch(w, r, document.CreateShelf(id, name).Any()) {
 		return
 	}
 
-	api.JSON(w, r, http.StatusCreated, doc)
+	w.Header().Set("Location", s.routes.URL(routes.ShowShelf, id))
+	api.JSON(w, r, http.StatusCreated, document.JSONShelf{
+		ID:        id,
+		Name:      name,
+		Documents: []document.Document{},
+	})
 }
 
-func (s *documentServer) replaceDocument(w http.ResponseWriter, r *http.Request) {
-	file, _, err := r.FormFile("document")
-	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, api.Friendly(err, "Invalid file: %v", err))
-		return
+func (s *documentServer) lookupName(w http.ResponseWriter, r *http.Request) {
+	var resp struct {
+		ShelfID uuid.UUID `json:"shelfId"`
 	}
-	defer file.Close()
 
-	shelfID, err := api.ExtractUUID(r, "ShelfID")
-	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, err)
-		return
-	}
+	name := chi.URLParam(r, "Name")
 
-	documentID, err := api.ExtractUUID(r, "DocumentID")
+	id, ok, err := s.client.LookupShelfByName(r.Context(), name)
 	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, err)
+		api.Error(w, r, http.StatusInternalServerError, err)
 		return
 	}
-
-	replaced, err := s.client.ReplaceDocument(r.Context(), shelfID, documentID, file)
-	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to replace document: %v", err))
+	if !ok {
+		api.Error(w, r, http.StatusNotFound, api.Friendly(nil, "No shelf named %q found.", name))
 		return
 	}
+	resp.ShelfID = id
 
-	api.JSON(w, r, http.StatusOK, replaced)
+	api.JSON(w, r, http.StatusOK, resp)
 }
 
-func (s *documentServer) updateDocument(w http.ResponseWriter, r *http.Request) {
-	shelfID, err := api.ExtractUUID(r, "ShelfID")
-	if err != nil {
+func (s *documentServer) showShelf(w http.ResponseWriter, r *http.Request) {
+	if q, paged, err := documentQuery(r); err != nil {
 		api.Error(w, r, http.StatusBadRequest, err)
 		return
-	}
-
-	documentID, err := api.ExtractUUID(r, "DocumentID")
-	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, err)
+	} else if paged {
+		s.listDocuments(w, r, q)
 		return
 	}
 
-	var req struct {
-		Name       string  `json:"name"`
-		UniqueName *string `json:"uniqueName"`
-	}
-
-	if err := api.Decode(r.Body, &req); err != nil {
-		api.Error(w, r, http.StatusBadRequest, err)
+	shelf, ok := s.fetchShelf(w, r)
+	if !ok {
 		return
 	}
 
-	cmd := document.Rename(shelfID, documentID, req.Name).Any()
-	if err := s.commands.Dispatch(r.Context(), cmd.Any(), dispatch.Sync()); err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to dispatch %q command: %v", cmd.Name(), err))
+	if !cacheable(w, r, s.routes, routes.ShowShelf, versionETag(shelf.ID, shelf.Version)) {
 		return
 	}
 
-	if req.UniqueName != nil {
-		if *req.UniqueName != "" {
-			cmd = document.MakeUnique(shelfID, documentID, *req.UniqueName).Any()
-		} else {
-			cmd = document.MakeNonUnique(shelfID, documentID).Any()
-		}
+	api.JSON(w, r, http.StatusOK, shelf)
+}
 
-		if err := s.commands.Dispatch(r.Context(), cmd.Any(), dispatch.Sync()); err != nil {
-			api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to dispatch %q command: %v", cmd.Name(), err))
-			return
-		}
+// renameShelf renames the Shelf to the name from the request body. The name
+// must not be used by another Shelf.
+func (s *documentServer) renameShelf(w http.ResponseWriter, r *http.Request) {
+	shelf, ok := s.fetchShelf(w, r)
+	if !ok {
+		return
 	}
 
-	shelf, err := s.client.FetchShelf(r.Context(), shelfID)
-	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Shelf %q not found.", shelfID))
+	name, ok := decodeCreateRequest(w, r)
+	if !ok {
 		return
 	}
 
-	doc, err := shelf.Document(documentID)
-	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Document %q not found.", documentID))
+	if id, ok, err := s.client.LookupShelfByName(r.Context(), name); err != nil {
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to look up shelf name: %v", err))
+		return
+	} else if ok && id != shelf.ID {
+		api.Error(w, r, http.StatusConflict, api.Friendly(nil, "Name %q is already used by shelf %q.", name, id))
 		return
 	}
 
-	api.JSON(w, r, http.StatusOK, doc)
-}
-
-func (s *documentServer) deleteDocument(w http.ResponseWriter, r *http.Request) {
-	shelfID, err := api.ExtractUUID(r, "ShelfID")
-	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, err)
+	if !s.dispatch(w, r, document.RenameShelf(shelf.ID, name).Any()) {
 		return
 	}
 
-	documentID, err := api.ExtractUUID(r, "DocumentID")
-	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, err)
+	shelf.Name = name
+	api.JSON(w, r, http.StatusOK, shelf)
+}
+
+// deleteShelf deletes the Shelf and the files of its documents, including the
+// documents in the trash. Files that cannot be deleted are reported in the
+// ShelfDeleted event.
+func (s *documentServer) deleteShelf(w http.ResponseWriter, r *http.Request) {
+	shelf, ok := s.fetchShelf(w, r)
+	if !ok {
 		return
 	}
 
-	cmd := document.Remove(shelfID, documentID)
-	if err := s.commands.Dispatch(r.Context(), cmd.Any(), dispatch.Sync()); err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to remove document: %v", err))
+	if !s.dispatch(w, r, document.DeleteShelf(shelf.ID).Any()) {
 		return
 	}
 
 	api.NoContent(w, r)
 }
 
-func (s *documentServer) addTags(w http.ResponseWriter, r *http.Request) {
-	shelfID, err := api.ExtractUUID(r, "ShelfID")
+func (s *documentServer) listDocuments(w http.ResponseWriter, r *http.Request, q document.Query) {
+	id := api.UUIDParam(r, "ShelfID")
+	page, err := s.client.ListDocuments(r.Context(), id, q)
 	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, err)
+		api.Error(w, r, errorStatus(err), api.Friendly(err, "Failed to fetch shelf %q: %v", id, err))
 		return
 	}
 
-	documentID, err := api.ExtractUUID(r, "DocumentID")
-	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, err)
-		return
-	}
+	resp := documentPageResponse{DocumentPage: page}
+	resp.PageMeta = api.WritePage(w, r, api.Page{
+		Total:  page.Total,
+		Offset: page.Offset,
+		Count:  len(page.Documents),
+	})
 
-	var req struct {
-		Tags []string `json:"tags"`
-	}
+	api.JSON(w, r, http.StatusOK, resp)
+}
 
-	if err := api.Decode(r.Body, &req); err != nil {
-		api.Error(w, r, http.StatusBadGateway, err)
-		return
-	}
+// documentList are the query parameters of a paged shelf. "tag" filters
+// documents by tags (a document matches one of the tags), and "q" searches the
+// names of the documents.
+var documentList = api.List{
+	DefaultLimit: 50,
+	MaxLimit:     500,
+	Sorts:        document.SortFields[:],
+	Filters: map[string][]api.Operator{
+		"tag": {api.Eq},
+		"q":   {api.Eq},
+	},
+}
 
-	cmd := document.Tag(shelfID, documentID, req.Tags)
-	if err := s.commands.Dispatch(r.Context(), cmd.Any(), dispatch.Sync()); err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to dispatch %q command: %v", cmd.Name(), err))
-		return
-	}
+type documentPageResponse struct {
+	document.DocumentPage
+	api.PageMeta
+}
 
-	shelf, err := s.client.FetchShelf(r.Context(), shelfID)
+// documentQuery returns the paginated query for the documents of a shelf. If
+// none of the list parameters are set, documentQuery returns false and the
+// whole shelf should be returned.
+func documentQuery(r *http.Request) (document.Query, bool, error) {
+	lq, err := documentList.Parse(r)
 	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Shelf %q not found.", shelfID))
+		return document.Query{}, true, err
 	}
 
-	doc, err := shelf.Document(documentID)
-	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Document %q not found.", documentID))
-		return
+	q := document.Query{
+		Offset: lq.Offset,
+		Limit:  lq.Limit,
+		Tags:   lq.Values("tag", api.Eq),
 	}
+	q.Search, _ = lq.Value("q", api.Eq)
 
-	api.JSON(w, r, http.StatusOK, doc)
+	q.Sort = sortParam(lq)
+
+	return q, lq.Set, nil
 }
 
-func (s *documentServer) removeTags(w http.ResponseWriter, r *http.Request) {
-	shelfID, err := api.ExtractUUID(r, "ShelfID")
-	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, err)
-		return
+// sortParam returns the sort fields of lq in the format of the "sort" query
+// parameter, which is also the sort format of document and gallery queries.
+func sortParam(lq api.ListQuery) string {
+	sorts := make([]string, len(lq.Sort))
+	for i, s := range lq.Sort {
+		sorts[i] = s.Field
+		if s.Desc {
+			sorts[i] = "-" + s.Field
+		}
 	}
+	return strings.Join(sorts, ",")
+}
 
-	documentID, err := api.ExtractUUID(r, "DocumentID")
+// search searches the documents of all shelfs. See searchList for the query
+// parameters.
+func (s *documentServer) search(w http.ResponseWriter, r *http.Request) {
+	lq, err := searchList.Parse(r)
 	if err != nil {
 		api.Error(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	tags := strings.Split(chi.URLParam(r, "Tags"), ",")
-
-	cmd := document.Untag(shelfID, documentID, tags)
-	if err := s.commands.Dispatch(r.Context(), cmd.Any(), dispatch.Sync()); err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to dispatch %q command: %v", cmd.Name(), err))
-		return
+	q := document.SearchQuery{
+		Offset:      lq.Offset,
+		Limit:       lq.Limit,
+		Names:       lq.Values("name", api.Eq),
+		UniqueNames: lq.Values("uniqueName", api.Eq),
+		Tags:        lq.Values("tag", api.Eq),
 	}
-
-	shelf, err := s.client.FetchShelf(r.Context(), shelfID)
-	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Shelf %q not found.", shelfID))
+	q.Search, _ = lq.Value("q", api.Eq)
+	for _, tags := range lq.Values("tags", api.Eq) {
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				q.Tags = append(q.Tags, tag)
+			}
+		}
+	}
+	if q.Path, _ = lq.Value("path", api.Eq); q.Path != "" {
+		if _, err := regexp.Compile(q.Path); err != nil {
+			api.Error(w, r, http.StatusBadRequest, api.Friendly(err, "Invalid path expression: %v", err))
+			return
+		}
 	}
 
-	doc, err := shelf.Document(documentID)
+	results, err := s.client.SearchDocuments(r.Context(), q)
 	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Document %q not found.", documentID))
+		api.Error(w, r, errorStatus(err), api.Friendly(err, "Failed to search documents: %v", err))
 		return
 	}
 
-	api.JSON(w, r, http.StatusOK, doc)
+	resp := searchResponse{SearchResults: results}
+	resp.PageMeta = api.WritePage(w, r, api.Page{
+		Total:  results.Total,
+		Offset: results.Offset,
+		Count:  len(results.Documents),
+	})
+
+	api.JSON(w, r, http.StatusOK, resp)
 }
 
-type galleryServer struct {
-	chi.Router
+// searchList are the query parameters of the document search. "q" searches
+// the names and unique names of the documents, "name" and "uniqueName" match
+// names exactly, "tags" (comma-separated) and "tag" filter by tags (a document
+// matches one of the tags) and "path" is a regular expression that is matched
+// against the storage paths of the documents.
+var searchList = api.List{
+	DefaultLimit: 50,
+	MaxLimit:     500,
+	Filters: map[string][]api.Operator{
+		"q":          {api.Eq},
+		"name":       {api.Eq},
+		"uniqueName": {api.Eq},
+		"tag":        {api.Eq},
+		"tags":       {api.Eq},
+		"path":       {api.Eq},
+	},
+}
 
-	client   GalleryClient
-	commands command.Bus
-	routes   routes.Routes
+type searchResponse struct {
+	document.SearchResults
+	api.PageMeta
 }
 
-func newGalleryServer(client GalleryClient, commands command.Bus, routes routes.Routes) *galleryServer {
-	srv := galleryServer{
-		Router:   chi.NewRouter(),
-		client:   client,
-		commands: commands,
-		routes:   routes,
+func (s *documentServer) showContent(w http.ResponseWriter, r *http.Request) {
+	doc, ok := s.fetchDocument(w, r)
+	if !ok {
+		return
 	}
-	srv.init()
-	return &srv
-}
 
-func (s *galleryServer) init() {
-	s.routes.Install(s, routes.LookupGalleryByName, http.HandlerFunc(s.lookupName))
-	s.routes.Install(s, routes.LookupGalleryStackByName, http.HandlerFunc(s.lookupStackName))
-	s.routes.Install(s, routes.ShowGallery, http.HandlerFunc(s.showGallery))
-	s.routes.Install(s, routes.UploadImage, http.HandlerFunc(s.uploadImage))
-	s.routes.Install(s, routes.ReplaceImage, http.HandlerFunc(s.replaceImage))
-	s.routes.Install(s, routes.UpdateStack, http.HandlerFunc(s.updateStack))
-	s.routes.Install(s, routes.DeleteStack, http.HandlerFunc(s.deleteStack))
-	s.routes.Install(s, routes.TagStack, http.HandlerFunc(s.tagStack))
-	s.routes.Install(s, routes.UntagStack, http.HandlerFunc(s.untagStack))
-	s.routes.Install(s, routes.SortGallery, http.HandlerFunc(s.sortGallery))
-}
+	// The "locale" query parameter may contain multiple, comma-separated
+	// locales in order of preference. If the Document has no matching variant,
+	// the Document itself is served.
+	var locales []string
+	if locale := r.URL.Query().Get("locale"); locale != "" {
+		locales = strings.Split(locale, ",")
+	}
 
-func (s *galleryServer) lookupName(w http.ResponseWriter, r *http.Request) {
-	var resp struct {
-		GalleryID uuid.UUID `json:"galleryId"`
+	variant, locale := doc.Localized(locales...)
+	if locale != "" {
+		w.Header().Set("Content-Language", locale)
 	}
 
-	name := chi.URLParam(r, "Name")
+	serveFile(w, r, s.storage, variant.File)
+}
 
-	id, ok, err := s.client.LookupGalleryByName(r.Context(), name)
-	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, err)
+// showPreview serves the rendered preview image of a Document. If the preview
+// hasn't been rendered (yet), 404 Not Found is returned.
+func (s *documentServer) showPreview(w http.ResponseWriter, r *http.Request) {
+	doc, ok := s.fetchDocument(w, r)
+	if !ok {
 		return
 	}
-	if !ok {
-		api.Error(w, r, http.StatusNotFound, api.Friendly(nil, "Lookup failed for gallery %q.", name))
+
+	if doc.Preview == nil {
+		api.Error(w, r, http.StatusNotFound, api.Friendly(nil, "Document %q has no preview.", doc.ID))
 		return
 	}
-	resp.GalleryID = id
 
-	api.JSON(w, r, http.StatusOK, resp)
+	if doc.Preview.Status != document.PreviewStatusRendered {
+		api.Error(w, r, http.StatusNotFound, api.Friendly(nil, "Preview of document %q is %s.", doc.ID, doc.Preview.Status))
+		return
+	}
+
+	serveFile(w, r, s.storage, doc.Preview.Image.File)
 }
 
-func (s *galleryServer) lookupStackName(w http.ResponseWriter, r *http.Request) {
-	var resp struct {
-		StackID uuid.UUID `json:"stackId"`
+func (s *documentServer) uploadDocument(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.fetchShelf(w, r); !ok {
+		return
 	}
 
-	galleryID, err := api.ExtractUUID(r, "GalleryID")
+	file, err := readMultipartUpload(r, "document", "name", "uniqueName", "disk", "path")
 	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, err)
+		api.Error(w, r, http.StatusUnprocessableEntity, api.Friendly(err, "Failed to parse file: %v", err))
 		return
 	}
+	defer file.Close()
 
-	name := chi.URLParam(r, "Name")
+	name := file.Value("name")
+	uniqueName := file.Value("uniqueName")
+	disk := file.Value("disk")
+	path := file.Value("path")
 
-	id, ok, err := s.client.LookupGalleryStackByName(r.Context(), galleryID, name)
+	doc, err := s.client.UploadDocument(r.Context(), api.UUIDParam(r, "ShelfID"), file, uniqueName, name, disk, path)
 	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, err)
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to upload document to shelf: %v", err))
 		return
 	}
-	if !ok {
-		api.Error(w, r, http.StatusNotFound, api.Friendly(nil, "Stack %q not found.", name))
+
+	shelfID := api.UUIDParam(r, "ShelfID")
+	res := createdDocument{
+		Document: doc,
+		Links: links{
+			Self:    s.routes.URL(routes.ShowDocument, shelfID, doc.ID),
+			Content: s.routes.URL(routes.ShowDocumentContent, shelfID, doc.ID),
+			Shelf:   s.routes.URL(routes.ShowShelf, shelfID),
+		},
 	}
-	resp.StackID = id
 
-	api.JSON(w, r, http.StatusOK, resp)
+	w.Header().Set("Location", res.Links.Self)
+	api.JSON(w, r, http.StatusCreated, res)
 }
 
-func (s *galleryServer) showGallery(w http.ResponseWriter, r *http.Request) {
-	id, err := api.ExtractUUID(r, "GalleryID")
-	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, err)
-		return
-	}
+// createdDocument is the response body of an uploaded Document.
+type createdDocument struct {
+	document.Document
+	Links links `json:"links"`
+}
 
-	g, err := s.client.FetchGallery(r.Context(), id)
-	if err != nil {
-		api.Error(w, r, http.StatusNotFound, api.Friendly(err, "Gallery %q not found: %v.", id, err))
-	}
+// links are the URLs of a created resource. They honor the route prefix of the
+// server component.
+type links struct {
+	// Self is the URL of the created resource. It is also sent as the Location
+	// header.
+	Self string `json:"self"`
 
-	api.JSON(w, r, http.StatusOK, g)
+	// Content is the URL of the uploaded file.
+	Content string `json:"content"`
+
+	Shelf   string `json:"shelf,omitempty"`
+	Gallery string `json:"gallery,omitempty"`
 }
 
-func (s *galleryServer) uploadImage(w http.ResponseWriter, r *http.Request) {
-	name := r.FormValue("name")
-	disk := r.FormValue("disk")
-	path := r.FormValue("path")
-	file, _, err := r.FormFile("image")
-	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, api.Friendly(err, "Invalid file: %v", err))
+func (s *documentServer) replaceDocument(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.fetchDeletableDocument(w, r); !ok {
 		return
 	}
-	defer file.Close()
 
-	galleryID, err := api.ExtractUUID(r, "GalleryID")
+	file, err := readMultipartUpload(r, "document")
 	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, err)
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(err, "Invalid file: %v", err))
 		return
 	}
+	defer file.Close()
 
-	stack, err := s.client.UploadImage(r.Context(), galleryID, file, name, disk, path)
+	replaced, err := s.client.ReplaceDocument(r.Context(), api.UUIDParam(r, "ShelfID"), api.UUIDParam(r, "DocumentID"), file)
 	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to upload image: %v", err))
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to replace document: %v", err))
 		return
 	}
 
-	api.JSON(w, r, http.StatusCreated, stack)
+	api.JSON(w, r, http.StatusOK, replaced)
 }
 
-func (s *galleryServer) deleteStack(w http.ResponseWriter, r *http.Request) {
-	galleryID, err := api.ExtractUUID(r, "GalleryID")
-	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, err)
+func (s *documentServer) replaceDocumentDelta(w http.ResponseWriter, r *http.Request) {
+	doc, ok := s.fetchDeletableDocument(w, r)
+	if !ok {
 		return
 	}
 
-	stackID, err := api.ExtractUUID(r, "StackID")
+	d, err := delta.Decode(r.Body)
 	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, err)
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(err, "Invalid delta: %v", err))
 		return
 	}
 
-	cmd := gallery.DeleteStack(galleryID, stackID)
-	if err := s.commands.Dispatch(r.Context(), cmd.Any(), dispatch.Sync()); err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to dispatch %q command: %v", cmd.Name(), err))
+	if d.BaseChecksum != doc.Checksum {
+		api.Error(w, r, http.StatusPreconditionFailed, api.Friendly(delta.ErrBaseMismatch, "Delta does not match the current file of document %q. Upload the full file instead.", doc.ID))
+		return
+	}
+
+	replaced, err := s.client.ReplaceDocumentDelta(r.Context(), api.UUIDParam(r, "ShelfID"), doc.ID, d)
+	if err != nil {
+		if errors.Is(err, delta.ErrBaseMismatch) {
+			api.Error(w, r, http.StatusPreconditionFailed, api.Friendly(err, "Delta does not match the current file of document %q. Upload the full file instead.", doc.ID))
+			return
+		}
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to replace document: %v", err))
+		return
+	}
+
+	api.JSON(w, r, http.StatusOK, replaced)
+}
+
+func (s *documentServer) updateDocument(w http.ResponseWriter, r *http.Request) {
+	var req updateDocumentRequest
+	if err := api.Decode(r.Body, &req); err != nil {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	setMetadata, deleteMetadata, ok := metadataPatch(w, r, req.Metadata)
+	if !ok {
+		return
+	}
+
+	if _, ok := s.fetchDocument(w, r); !ok {
+		return
+	}
+
+	shelfID := api.UUIDParam(r, "ShelfID")
+	documentID := api.UUIDParam(r, "DocumentID")
+
+	if !s.change(w, r, document.Rename(shelfID, documentID, req.Name).Any(), func(c DocumentCommandClient) error {
+		_, err := c.RenameDocument(r.Context(), shelfID, documentID, req.Name)
+		return err
+	}) {
+		return
+	}
+
+	if req.UniqueName != nil {
+		cmd := document.MakeNonUnique(shelfID, documentID).Any()
+		if *req.UniqueName != "" {
+			cmd = document.MakeUnique(shelfID, documentID, *req.UniqueName).Any()
+		}
+		if !s.dispatch(w, r, cmd) {
+			return
+		}
+	}
+
+	if len(setMetadata) > 0 {
+		if !s.dispatch(w, r, document.SetMetadata(shelfID, documentID, setMetadata).Any()) {
+			return
+		}
+	}
+
+	if len(deleteMetadata) > 0 {
+		if !s.dispatch(w, r, document.DeleteMetadata(shelfID, documentID, deleteMetadata).Any()) {
+			return
+		}
+	}
+
+	s.showDocument(w, r)
+}
+
+func (s *documentServer) deleteDocument(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.fetchDeletableDocument(w, r); !ok {
+		return
+	}
+
+	shelfID, documentID := api.UUIDParam(r, "ShelfID"), api.UUIDParam(r, "DocumentID")
+	if !s.change(w, r, document.Trash(shelfID, documentID).Any(), func(c DocumentCommandClient) error {
+		return c.DeleteDocument(r.Context(), shelfID, documentID)
+	}) {
+		return
+	}
+
+	api.NoContent(w, r)
+}
+
+// setLegalHold sets a legal hold on the Shelf, or on the Document of the
+// DocumentID URL parameter if the route has one.
+func (s *documentServer) setLegalHold(w http.ResponseWriter, r *http.Request) {
+	var req legalHoldRequest
+	if err := api.Decode(r.Body, &req); err != nil {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	shelfID, id, ok := s.fetchHoldTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if !s.dispatch(w, r, document.SetLegalHold(shelfID, id, req.Reason).Any()) {
+		return
+	}
+
+	api.NoContent(w, r)
+}
+
+// liftLegalHold lifts the legal hold of the Shelf, or of the Document of the
+// DocumentID URL parameter if the route has one.
+func (s *documentServer) liftLegalHold(w http.ResponseWriter, r *http.Request) {
+	shelfID, id, ok := s.fetchHoldTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if !s.dispatch(w, r, document.LiftLegalHold(shelfID, id).Any()) {
+		return
+	}
+
+	api.NoContent(w, r)
+}
+
+// fetchHoldTarget returns the ShelfID and DocumentID URL parameters of a legal
+// hold route. The DocumentID is uuid.Nil for the routes of the Shelf. Trashed
+// Documents can be held, too. If the Shelf or Document cannot be found, an
+// error response is written and false is returned.
+func (s *documentServer) fetchHoldTarget(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	shelf, ok := s.fetchShelf(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id := api.UUIDParam(r, "DocumentID")
+	if id == uuid.Nil {
+		return shelf.ID, id, true
+	}
+
+	if _, err := shelf.Document(id); err == nil {
+		return shelf.ID, id, true
+	}
+
+	if _, err := shelf.TrashedDocument(id); err == nil {
+		return shelf.ID, id, true
+	}
+
+	api.Error(w, r, http.StatusNotFound, api.Friendly(document.ErrNotFound, "Document %q not found.", id))
+	return shelf.ID, id, false
+}
+
+// fetchDeletableDocument fetches the Document like fetchDocument, but responds
+// with 409 Conflict if the Document or its Shelf is under legal hold.
+func (s *documentServer) fetchDeletableDocument(w http.ResponseWriter, r *http.Request) (document.Document, bool) {
+	shelf, ok := s.fetchShelf(w, r)
+	if !ok {
+		return document.Document{}, false
+	}
+
+	id := api.UUIDParam(r, "DocumentID")
+	doc, err := shelf.Document(id)
+	if err != nil {
+		api.Error(w, r, http.StatusNotFound, api.Friendly(err, "Document %q not found.", id))
+		return doc, false
+	}
+
+	if shelf.Held(id) {
+		api.Error(w, r, http.StatusConflict, api.Friendly(document.ErrLegalHold, "Document %q is under legal hold.", id))
+		return doc, false
+	}
+
+	return doc, true
+}
+
+func (s *documentServer) restoreDocument(w http.ResponseWriter, r *http.Request) {
+	shelf, ok := s.fetchShelf(w, r)
+	if !ok {
+		return
+	}
+
+	id := api.UUIDParam(r, "DocumentID")
+	trashed, err := shelf.TrashedDocument(id)
+	if err != nil {
+		api.Error(w, r, http.StatusNotFound, api.Friendly(err, "Document %q not found in trash.", id))
+		return
+	}
+
+	if trashed.UniqueName != "" {
+		for _, doc := range shelf.Documents {
+			if doc.UniqueName == trashed.UniqueName {
+				api.Error(w, r, http.StatusConflict, api.Friendly(
+					document.ErrDuplicateUniqueName,
+					"Unique name %q is already used by document %q.", trashed.UniqueName, doc.ID,
+				))
+				return
+			}
+		}
+	}
+
+	if !s.dispatch(w, r, document.Restore(shelf.ID, id).Any()) {
+		return
+	}
+
+	api.JSON(w, r, http.StatusOK, trashed.Document)
+}
+
+func (s *documentServer) addTags(w http.ResponseWriter, r *http.Request) {
+	var req tagRequest
+	if err := api.Decode(r.Body, &req); err != nil {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, ok := s.fetchDocument(w, r); !ok {
+		return
+	}
+
+	shelfID, documentID := api.UUIDParam(r, "ShelfID"), api.UUIDParam(r, "DocumentID")
+	if !s.change(w, r, document.Tag(shelfID, documentID, req.Tags).Any(), func(c DocumentCommandClient) error {
+		_, err := c.TagDocument(r.Context(), shelfID, documentID, req.Tags)
+		return err
+	}) {
+		return
+	}
+
+	s.showDocument(w, r)
+}
+
+func (s *documentServer) removeTags(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.fetchDocument(w, r); !ok {
+		return
+	}
+
+	tags := strings.Split(chi.URLParam(r, "Tags"), ",")
+
+	shelfID, documentID := api.UUIDParam(r, "ShelfID"), api.UUIDParam(r, "DocumentID")
+	if !s.change(w, r, document.Untag(shelfID, documentID, tags).Any(), func(c DocumentCommandClient) error {
+		_, err := c.UntagDocument(r.Context(), shelfID, documentID, tags)
+		return err
+	}) {
+		return
+	}
+
+	s.showDocument(w, r)
+}
+
+// maxRetagDocuments is the maximum number of documents that can be retagged
+// by a single request.
+const maxRetagDocuments = 1000
+
+// retagDocuments adds and removes tags of multiple documents of a shelf using
+// a single command and responds with the updated documents.
+func (s *documentServer) retagDocuments(w http.ResponseWriter, r *http.Request) {
+	var req retagRequest
+	if err := api.Decode(r.Body, &req); err != nil {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if len(req.DocumentIDs) == 0 || len(req.DocumentIDs) > maxRetagDocuments {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(nil, "Between 1 and %d documents can be retagged at once.", maxRetagDocuments))
+		return
+	}
+
+	if len(req.Add) == 0 && len(req.Remove) == 0 {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(nil, "No tags to add or remove."))
+		return
+	}
+
+	shelf, ok := s.fetchShelf(w, r)
+	if !ok {
+		return
+	}
+
+	for _, id := range req.DocumentIDs {
+		if _, err := shelf.Document(id); err != nil {
+			api.Error(w, r, http.StatusNotFound, api.Friendly(err, "Document %q not found.", id))
+			return
+		}
+	}
+
+	if !s.dispatch(w, r, document.Retag(shelf.ID, req.DocumentIDs, req.Add, req.Remove).Any()) {
+		return
+	}
+
+	if shelf, ok = s.fetchShelf(w, r); !ok {
+		return
+	}
+
+	resp := struct {
+		Documents []document.Document `json:"documents"`
+	}{Documents: make([]document.Document, 0, len(req.DocumentIDs))}
+	for _, id := range req.DocumentIDs {
+		if doc, err := shelf.Document(id); err == nil {
+			resp.Documents = append(resp.Documents, doc)
+		}
+	}
+
+	api.JSON(w, r, http.StatusOK, resp)
+}
+
+// copyDocument copies the Document from the ShelfID and DocumentID URL
+// parameters to the requested target shelf and responds with the copy. If the
+// copy cannot be fetched yet, e.g. because the client reads shelfs from a
+// projection, 202 Accepted is returned with the URL of the copy.
+func (s *documentServer) copyDocument(w http.ResponseWriter, r *http.Request) {
+	var req copyDocumentRequest
+	if err := api.Decode(r.Body, &req); err != nil {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if req.ShelfID == uuid.Nil {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(nil, "Missing target shelf."))
+		return
+	}
+
+	if _, ok := s.fetchDocument(w, r); !ok {
+		return
+	}
+
+	if _, err := s.client.FetchShelf(r.Context(), req.ShelfID); err != nil {
+		api.Error(w, r, errorStatus(err), api.Friendly(err, "Failed to fetch shelf %q: %v", req.ShelfID, err))
+		return
+	}
+
+	id := uuid.New()
+	cmd := document.Copy(api.UUIDParam(r, "ShelfID"), api.UUIDParam(r, "DocumentID"), req.ShelfID, id, req.Disk, req.Path)
+	if !s.dispatch(w, r, cmd.Any()) {
+		return
+	}
+
+	res := createdDocument{
+		Document: document.Document{ID: id},
+		Links: links{
+			Self:    s.routes.URL(routes.ShowDocument, req.ShelfID, id),
+			Content: s.routes.URL(routes.ShowDocumentContent, req.ShelfID, id),
+			Shelf:   s.routes.URL(routes.ShowShelf, req.ShelfID),
+		},
+	}
+	w.Header().Set("Location", res.Links.Self)
+
+	target, err := s.client.FetchShelf(r.Context(), req.ShelfID)
+	if err != nil {
+		api.Error(w, r, errorStatus(err), api.Friendly(err, "Failed to fetch shelf %q: %v", req.ShelfID, err))
+		return
+	}
+
+	if res.Document, err = target.Document(id); err != nil {
+		res.Document = document.Document{ID: id}
+		api.JSON(w, r, http.StatusAccepted, res)
+		return
+	}
+
+	api.JSON(w, r, http.StatusCreated, res)
+}
+
+// showDocument responds with the Document from the ShelfID and DocumentID URL
+// parameters.
+// defaultURLExpiry is the expiry of signed URLs if the request doesn't
+// specify one.
+const defaultURLExpiry = 15 * time.Minute
+
+type signedURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// urlExpiry returns the expiry of a signed URL from the requested expiry in
+// seconds. If the requested expiry is invalid, an error response is written and
+// false is returned.
+func urlExpiry(w http.ResponseWriter, r *http.Request, seconds int) (time.Duration, bool) {
+	expiry := defaultURLExpiry
+	if seconds != 0 {
+		expiry = time.Duration(seconds) * time.Second
+	}
+	if expiry <= 0 || expiry > media.MaxURLExpiry {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(nil, "Expiry must be between 1 and %d seconds.", int(media.MaxURLExpiry/time.Second)))
+		return 0, false
+	}
+	return expiry, true
+}
+
+// signFile responds with a signed URL of f that expires after expiry. If the
+// disk of f cannot sign URLs, 501 Not Implemented is returned.
+func signFile(w http.ResponseWriter, r *http.Request, storage media.Storage, f media.File, expiry time.Duration) {
+	if storage == nil {
+		api.Error(w, r, http.StatusNotImplemented, api.Friendly(nil, "Signing URLs is not supported."))
+		return
+	}
+
+	expiresAt := time.Now().Add(expiry)
+	url, err := f.SignedURL(r.Context(), storage, expiry)
+	if err != nil {
+		if errors.Is(err, media.ErrSigningUnsupported) {
+			api.Error(w, r, http.StatusNotImplemented, api.Friendly(err, "Disk %q doesn't support signed URLs.", f.Disk))
+			return
+		}
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to sign URL: %v", err))
+		return
+	}
+
+	api.JSON(w, r, http.StatusOK, signedURL{URL: url, ExpiresAt: expiresAt})
+}
+
+// signURL responds with a signed, time-limited download URL of a Document (or
+// of one of its variants), so that the contents don't have to be served by the
+// media server. If the disk of the Document cannot sign URLs, 501 Not
+// Implemented is returned.
+func (s *documentServer) signURL(w http.ResponseWriter, r *http.Request) {
+	var req signedURLRequest
+	if err := api.Decode(r.Body, &req); err != nil && !errors.Is(err, io.EOF) {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	expiry, ok := urlExpiry(w, r, req.Expiry)
+	if !ok {
+		return
+	}
+
+	doc, ok := s.fetchDocument(w, r)
+	if !ok {
+		return
+	}
+
+	var locales []string
+	if req.Locale != "" {
+		locales = strings.Split(req.Locale, ",")
+	}
+	variant, _ := doc.Localized(locales...)
+
+	signFile(w, r, s.storage, variant.File, expiry)
+}
+
+// showDocument responds with the Document. The ETag of the response changes
+// whenever the Shelf of the Document changes.
+func (s *documentServer) showDocument(w http.ResponseWriter, r *http.Request) {
+	shelf, ok := s.fetchShelf(w, r)
+	if !ok {
+		return
+	}
+
+	id := api.UUIDParam(r, "DocumentID")
+	doc, err := shelf.Document(id)
+	if err != nil {
+		api.Error(w, r, http.StatusNotFound, api.Friendly(err, "Document %q not found.", id))
+		return
+	}
+
+	if !cacheable(w, r, s.routes, routes.ShowDocument, versionETag(doc.ID, shelf.Version)) {
+		return
+	}
+
+	api.JSON(w, r, http.StatusOK, doc)
+}
+
+func (s *documentServer) dispatch(w http.ResponseWriter, r *http.Request, cmd command.Command) bool {
+	return dispatchCommand(w, r, s.commands, cmd)
+}
+
+// change calls fn with the client if it implements DocumentCommandClient and
+// dispatches cmd otherwise. If the change fails, an error response is written
+// and false is returned.
+func (s *documentServer) change(w http.ResponseWriter, r *http.Request, cmd command.Command, fn func(DocumentCommandClient) error) bool {
+	client, ok := s.client.(DocumentCommandClient)
+	if !ok {
+		return s.dispatch(w, r, cmd)
+	}
+	return changeThrough(w, r, cmd, func() error { return fn(client) })
+}
+
+type galleryServer struct {
+	chi.Router
+
+	client      GalleryClient
+	commands    command.Bus
+	storage     media.Storage
+	placeholder *Placeholder
+	routes      routes.Routes
+}
+
+func newGalleryServer(
+	router chi.Router,
+	client GalleryClient,
+	commands command.Bus,
+	storage media.Storage,
+	placeholder *Placeholder,
+	routes routes.Routes,
+) *galleryServer {
+	srv := galleryServer{
+		Router:      router,
+		client:      client,
+		commands:    commands,
+		storage:     storage,
+		placeholder: placeholder,
+		routes:      routes,
+	}
+	srv.init()
+	return &srv
+}
+
+func (s *galleryServer) init() {
+	install(s, s.routes, routes.ListGalleries, s.listGalleries)
+	install(s, s.routes, routes.CreateGallery, s.createGallery)
+	install(s, s.routes, routes.LookupGalleryByName, s.lookupName)
+	install(s, s.routes, routes.LookupGalleryStackByName, s.lookupStackName)
+	install(s, s.routes, routes.LookupGalleryStacksByTag, s.lookupStackTag)
+	install(s, s.routes, routes.ShowGallery, s.showGallery)
+	install(s, s.routes, routes.RenameGallery, s.renameGallery)
+	install(s, s.routes, routes.DeleteGallery, s.deleteGallery)
+	install(s, s.routes, routes.ShowGalleryIndex, s.showIndex)
+	install(s, s.routes, routes.ShowStack, s.showStackDetail)
+	install(s, s.routes, routes.ShowStackContent, s.showStackContent)
+	install(s, s.routes, routes.HeadStackContent, s.showStackContent)
+	install(s, s.routes, routes.ShowStackOriginal, s.showStackOriginal)
+	install(s, s.routes, routes.SignStackURL, s.signStackURL)
+	install(s, s.routes, routes.ShowStackVideo, s.showStackVideo)
+	install(s, s.routes, routes.ShowStackStatus, s.showStackStatus)
+	install(s, s.routes, routes.UploadImage, s.uploadImage)
+	install(s, s.routes, routes.UploadVideo, s.uploadVideo)
+	install(s, s.routes, routes.ReplaceImage, s.replaceImage)
+	install(s, s.routes, routes.UpdateStack, s.updateStack)
+	install(s, s.routes, routes.SetStackFocalPoint, s.setFocalPoint)
+	install(s, s.routes, routes.CropStack, s.cropStack)
+	install(s, s.routes, routes.RotateStack, s.rotateStack)
+	install(s, s.routes, routes.FlipStack, s.flipStack)
+	install(s, s.routes, routes.ReprocessStack, s.reprocessStack)
+	install(s, s.routes, routes.DeleteStack, s.deleteStack)
+	install(s, s.routes, routes.TagStack, s.tagStack)
+	install(s, s.routes, routes.UntagStack, s.untagStack)
+	install(s, s.routes, routes.SortGallery, s.sortGallery)
+	install(s, s.routes, routes.MoveStack, s.moveStack)
+	install(s, s.routes, routes.SetGalleryDefaultTags, s.setDefaultTags)
+	install(s, s.routes, routes.ProtectGalleryOriginals, s.protectOriginals)
+	install(s, s.routes, routes.ShowGalleryTrash, s.showTrash)
+	install(s, s.routes, routes.RestoreStack, s.restoreStack)
+	install(s, s.routes, routes.SetGalleryLegalHold, s.setLegalHold)
+	install(s, s.routes, routes.LiftGalleryLegalHold, s.liftLegalHold)
+	install(s, s.routes, routes.SetStackLegalHold, s.setLegalHold)
+	install(s, s.routes, routes.LiftStackLegalHold, s.liftLegalHold)
+	install(s, s.routes, routes.SetGalleryVersionLimit, s.setVersionLimit)
+	install(s, s.routes, routes.ShowStackVersions, s.showVersions)
+	install(s, s.routes, routes.RevertStack, s.revertStack)
+}
+
+// fetchGallery fetches the Gallery from the GalleryID URL parameter. If the
+// Gallery cannot be fetched, an error response is written and false is
+// returned.
+func (s *galleryServer) fetchGallery(w http.ResponseWriter, r *http.Request) (gallery.JSONGallery, bool) {
+	id := api.UUIDParam(r, "GalleryID")
+	g, err := s.client.FetchGallery(r.Context(), id)
+	if err != nil {
+		api.Error(w, r, errorStatus(err), api.Friendly(err, "Failed to fetch gallery %q: %v", id, err))
+		return g, false
+	}
+	return g, true
+}
+
+// fetchStack fetches the Stack from the GalleryID and StackID URL parameters.
+// If the Stack cannot be fetched, an error response is written and false is
+// returned.
+func (s *galleryServer) fetchStack(w http.ResponseWriter, r *http.Request) (gallery.Stack, bool) {
+	galleryID, id := api.UUIDParam(r, "GalleryID"), api.UUIDParam(r, "StackID")
+	stack, err := s.client.FetchStack(r.Context(), galleryID, id)
+	if err != nil {
+		api.Error(w, r, errorStatus(err), api.Friendly(err, "Failed to fetch stack %q: %v", id, err))
+		return stack, false
+	}
+	return stack, true
+}
+
+// fetchGalleryStack fetches the Gallery and the Stack from the GalleryID and
+// StackID URL parameters. Routes that serve images use it instead of
+// fetchStack to check whether the Gallery protects its originals. If the Stack
+// cannot be fetched, an error response is written and false is returned.
+func (s *galleryServer) fetchGalleryStack(w http.ResponseWriter, r *http.Request) (gallery.JSONGallery, gallery.Stack, bool) {
+	g, ok := s.fetchGallery(w, r)
+	if !ok {
+		return g, gallery.Stack{}, false
+	}
+	id := api.UUIDParam(r, "StackID")
+	stack, err := g.Stack(id)
+	if err != nil {
+		api.Error(w, r, errorStatus(err), api.Friendly(err, "Failed to fetch stack %q: %v", id, err))
+		return g, stack, false
+	}
+	return g, stack, true
+}
+
+// stackImage returns the image of a Stack with the given size and format. If
+// size and format are empty, the original image is returned, or the largest
+// processed image if the Gallery protects its originals. If the Stack has no
+// matching image, an error response is written and false is returned.
+func stackImage(w http.ResponseWriter, r *http.Request, g gallery.JSONGallery, stack gallery.Stack, size, format string) (gallery.Image, bool) {
+	if size == "" && format == "" {
+		if !g.OriginalsProtected {
+			return stack.Original(), true
+		}
+		img, ok := stack.Processed()
+		if !ok {
+			api.Error(w, r, http.StatusNotFound, api.Friendly(nil, "Stack %q has no processed image.", stack.ID))
+		}
+		return img, ok
+	}
+
+	for _, img := range stack.Images {
+		if img.Size != size || img.Format != format {
+			continue
+		}
+		if !img.Stale {
+			return img, true
+		}
+		// A stale variant doesn't match the original anymore, so the original
+		// is served until the variant has been processed again. Galleries
+		// that protect their originals serve another processed image instead.
+		if !g.OriginalsProtected {
+			return stack.Original(), true
+		}
+		if processed, ok := stack.Processed(); ok {
+			return processed, true
+		}
+		api.Error(w, r, http.StatusNotFound, api.Friendly(nil, "Stack %q has no processed image.", stack.ID))
+		return img, false
+	}
+
+	api.Error(w, r, http.StatusNotFound, api.Friendly(nil, "Stack %q has no %q image in format %q.", stack.ID, size, format))
+	return gallery.Image{}, false
+}
+
+// listGalleries returns a page of the UUIDs and names of all galleries, sorted
+// by name. See aggregateList for the query parameters.
+func (s *galleryServer) listGalleries(w http.ResponseWriter, r *http.Request) {
+	lq, err := aggregateList.Parse(r)
+	if err != nil {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	list, err := s.client.ListGalleries(r.Context(), lq.Offset, lq.Limit)
+	if err != nil {
+		api.Error(w, r, errorStatus(err), api.Friendly(err, "Failed to list galleries: %v", err))
+		return
+	}
+
+	resp := galleryListResponse{GalleryList: list}
+	resp.PageMeta = api.WritePage(w, r, api.Page{
+		Total:  list.Total,
+		Offset: list.Offset,
+		Count:  len(list.Galleries),
+	})
+
+	api.JSON(w, r, http.StatusOK, resp)
+}
+
+type galleryListResponse struct {
+	gallery.GalleryList
+	api.PageMeta
+}
+
+// createGallery creates a Gallery with the name from the request body. The
+// name must not be used by another Gallery.
+func (s *galleryServer) createGallery(w http.ResponseWriter, r *http.Request) {
+	name, ok := decodeCreateRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if id, ok, err := s.client.LookupGalleryByName(r.Context(), name); err != nil {
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to look up gallery name: %v", err))
+		return
+	} else if ok {
+		api.Error(w, r, http.StatusConflict, api.Friendly(nil, "Name %q is already used by gallery %q.", name, id))
+		return
+	}
+
+	id := uuid.New()
+	if !s.dispatch(w, r, gallery.Create(id, name).Any()) {
+		return
+	}
+
+	w.Header().Set("Location", s.routes.URL(routes.ShowGallery, id))
+	api.JSON(w, r, http.StatusCreated, gallery.JSONGallery{
+		ID:     id,
+		Name:   name,
+		Stacks: gallery.Stacks{},
+	})
+}
+
+// renameGallery renames the Gallery to the name from the request body. The
+// name must not be used by another Gallery.
+func (s *galleryServer) renameGallery(w http.ResponseWriter, r *http.Request) {
+	g, ok := s.fetchGallery(w, r)
+	if !ok {
+		return
+	}
+
+	name, ok := decodeCreateRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if id, ok, err := s.client.LookupGalleryByName(r.Context(), name); err != nil {
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to look up gallery name: %v", err))
+		return
+	} else if ok && id != g.ID {
+		api.Error(w, r, http.StatusConflict, api.Friendly(nil, "Name %q is already used by gallery %q.", name, id))
+		return
+	}
+
+	if !s.dispatch(w, r, gallery.Rename(g.ID, name).Any()) {
+		return
+	}
+
+	g.Name = name
+	api.JSON(w, r, http.StatusOK, g)
+}
+
+// deleteGallery deletes the Gallery and the files of its stacks, including the
+// stacks in the trash and their previous versions. Files that cannot be
+// deleted are reported in the Deleted event.
+func (s *galleryServer) deleteGallery(w http.ResponseWriter, r *http.Request) {
+	g, ok := s.fetchGallery(w, r)
+	if !ok {
+		return
+	}
+
+	if !s.dispatch(w, r, gallery.Delete(g.ID).Any()) {
+		return
+	}
+
+	api.NoContent(w, r)
+}
+
+func (s *galleryServer) lookupName(w http.ResponseWriter, r *http.Request) {
+	var resp struct {
+		GalleryID uuid.UUID `json:"galleryId"`
+	}
+
+	name := chi.URLParam(r, "Name")
+
+	id, ok, err := s.client.LookupGalleryByName(r.Context(), name)
+	if err != nil {
+		api.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if !ok {
+		api.Error(w, r, http.StatusNotFound, api.Friendly(nil, "Lookup failed for gallery %q.", name))
+		return
+	}
+	resp.GalleryID = id
+
+	api.JSON(w, r, http.StatusOK, resp)
+}
+
+func (s *galleryServer) lookupStackName(w http.ResponseWriter, r *http.Request) {
+	var resp struct {
+		StackID uuid.UUID `json:"stackId"`
+	}
+
+	name := chi.URLParam(r, "Name")
+
+	id, ok, err := s.client.LookupGalleryStackByName(r.Context(), api.UUIDParam(r, "GalleryID"), name)
+	if err != nil {
+		api.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if !ok {
+		api.Error(w, r, http.StatusNotFound, api.Friendly(nil, "Stack %q not found.", name))
+		return
+	}
+	resp.StackID = id
+
+	api.JSON(w, r, http.StatusOK, resp)
+}
+
+func (s *galleryServer) lookupStackTag(w http.ResponseWriter, r *http.Request) {
+	var resp struct {
+		StackIDs []uuid.UUID `json:"stackIds"`
+	}
+
+	tag := r.URL.Query().Get("tag")
+	if tag == "" {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(nil, "Missing tag query parameter."))
+		return
+	}
+
+	ids, ok, err := s.client.LookupGalleryStacksByTag(r.Context(), api.UUIDParam(r, "GalleryID"), tag)
+	if err != nil {
+		api.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if !ok {
+		api.Error(w, r, http.StatusNotFound, api.Friendly(nil, "Gallery %q not found.", api.UUIDParam(r, "GalleryID")))
+		return
+	}
+	resp.StackIDs = ids
+
+	api.JSON(w, r, http.StatusOK, resp)
+}
+
+func (s *galleryServer) showGallery(w http.ResponseWriter, r *http.Request) {
+	if q, paged, err := stackQuery(r); err != nil {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return
+	} else if paged {
+		s.listStacks(w, r, q)
+		return
+	}
+
+	g, ok := s.fetchGallery(w, r)
+	if !ok {
+		return
+	}
+
+	if !cacheable(w, r, s.routes, routes.ShowGallery, versionETag(g.ID, g.Version)) {
+		return
+	}
+
+	api.JSON(w, r, http.StatusOK, g)
+}
+
+func (s *galleryServer) listStacks(w http.ResponseWriter, r *http.Request, q gallery.Query) {
+	id := api.UUIDParam(r, "GalleryID")
+	page, err := s.client.ListStacks(r.Context(), id, q)
+	if err != nil {
+		api.Error(w, r, errorStatus(err), api.Friendly(err, "Failed to fetch gallery %q: %v", id, err))
+		return
+	}
+
+	resp := stackPageResponse{StackPage: page}
+	resp.PageMeta = api.WritePage(w, r, api.Page{
+		Total:  page.Total,
+		Offset: page.Offset,
+		Count:  len(page.Stacks),
+	})
+
+	api.JSON(w, r, http.StatusOK, resp)
+}
+
+// stackList are the query parameters of a paged gallery. "tags" is a
+// comma-separated list of tags and can be combined with "tag" parameters; a
+// stack matches if it has all of the tags.
+var stackList = api.List{
+	DefaultLimit: 50,
+	MaxLimit:     500,
+	Sorts:        gallery.SortFields[:],
+	Filters: map[string][]api.Operator{
+		"tag":  {api.Eq},
+		"tags": {api.Eq},
+	},
+}
+
+type stackPageResponse struct {
+	gallery.StackPage
+	api.PageMeta
+}
+
+// stackQuery returns the paginated query for the stacks of a gallery. If none
+// of the list parameters are set, stackQuery returns false and the whole
+// gallery should be returned.
+func stackQuery(r *http.Request) (gallery.Query, bool, error) {
+	lq, err := stackList.Parse(r)
+	if err != nil {
+		return gallery.Query{}, true, err
+	}
+
+	q := gallery.Query{
+		Offset: lq.Offset,
+		Limit:  lq.Limit,
+		Tags:   lq.Values("tag", api.Eq),
+	}
+	for _, tags := range lq.Values("tags", api.Eq) {
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				q.Tags = append(q.Tags, tag)
+			}
+		}
+	}
+	q.Sort = sortParam(lq)
+
+	return q, lq.Set, nil
+}
+
+// showIndex serves the index of a gallery, which contains only the summaries
+// of its stacks. Clients that render a grid of images should fetch the index
+// and fetch the stacks they need in detail using showStackDetail.
+func (s *galleryServer) showIndex(w http.ResponseWriter, r *http.Request) {
+	id := api.UUIDParam(r, "GalleryID")
+	idx, err := s.client.FetchGalleryIndex(r.Context(), id)
+	if err != nil {
+		api.Error(w, r, errorStatus(err), api.Friendly(err, "Failed to fetch gallery %q: %v", id, err))
+		return
+	}
+
+	api.JSON(w, r, http.StatusOK, idx)
+}
+
+func (s *galleryServer) showStackDetail(w http.ResponseWriter, r *http.Request) {
+	stack, ok := s.fetchStack(w, r)
+	if !ok {
+		return
+	}
+
+	api.JSON(w, r, http.StatusOK, stack)
+}
+
+// showStackContent serves the image of a Stack. The size and format of the
+// image can be selected using the "size" and "format" query parameters.
+// Without them, the original image is served, or the largest processed image
+// if the Gallery protects its originals.
+func (s *galleryServer) showStackContent(w http.ResponseWriter, r *http.Request) {
+	g, stack, ok := s.fetchGalleryStack(w, r)
+	if !ok {
+		return
+	}
+
+	img, ok := stackImage(w, r, g, stack, r.URL.Query().Get("size"), r.URL.Query().Get("format"))
+	if !ok {
+		return
+	}
+
+	if s.placeholder != nil && s.storage != nil {
+		if missing, err := isMissing(r.Context(), s.storage, img.File); err == nil && missing {
+			s.placeholder.serve(w, r, img.Image)
+			return
+		}
+	}
+
+	serveFile(w, r, s.storage, img.File)
+}
+
+// showStackOriginal serves the original image of a Stack. If the Gallery
+// protects its originals, the request must be authorized for the
+// ShowStackOriginal route.
+func (s *galleryServer) showStackOriginal(w http.ResponseWriter, r *http.Request) {
+	g, stack, ok := s.fetchGalleryStack(w, r)
+	if !ok {
+		return
+	}
+
+	if g.OriginalsProtected && !s.routes.Require(w, r, routes.ShowStackOriginal) {
+		return
+	}
+
+	serveFile(w, r, s.storage, stack.Original().File)
+}
+
+// signStackURL responds with a signed, time-limited download URL of an image
+// of a Stack. The image is selected like in showStackContent. The URL of the
+// original image of a Gallery that protects its originals can only be signed
+// for requests that are authorized for the ShowStackOriginal route.
+func (s *galleryServer) signStackURL(w http.ResponseWriter, r *http.Request) {
+	var req signStackURLRequest
+	if err := api.Decode(r.Body, &req); err != nil && !errors.Is(err, io.EOF) {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	expiry, ok := urlExpiry(w, r, req.Expiry)
+	if !ok {
+		return
+	}
+
+	g, stack, ok := s.fetchGalleryStack(w, r)
+	if !ok {
+		return
+	}
+
+	if req.Original {
+		if g.OriginalsProtected && !s.routes.Require(w, r, routes.ShowStackOriginal) {
+			return
+		}
+		signFile(w, r, s.storage, stack.Original().File, expiry)
+		return
+	}
+
+	img, ok := stackImage(w, r, g, stack, req.Size, req.Format)
+	if !ok {
+		return
+	}
+
+	signFile(w, r, s.storage, img.File, expiry)
+}
+
+// showStackVideo serves the video of a video Stack. Videos have no processed
+// variants, so the video of a Gallery that protects its originals is only
+// served to requests that are authorized for the ShowStackOriginal route.
+func (s *galleryServer) showStackVideo(w http.ResponseWriter, r *http.Request) {
+	g, stack, ok := s.fetchGalleryStack(w, r)
+	if !ok {
+		return
+	}
+
+	if !stack.IsVideo() {
+		api.Error(w, r, http.StatusNotFound, api.Friendly(nil, "Stack %q is not a video.", stack.ID))
+		return
+	}
+
+	if g.OriginalsProtected && !s.routes.Require(w, r, routes.ShowStackOriginal) {
+		return
+	}
+
+	serveFile(w, r, s.storage, stack.Video.File)
+}
+
+// stackStatus is the processing status of a Stack.
+type stackStatus struct {
+	Pending         bool       `json:"pending"`
+	ProcessedAt     *time.Time `json:"processedAt"`
+	ProcessingError string     `json:"processingError,omitempty"`
+}
+
+func (s *galleryServer) showStackStatus(w http.ResponseWriter, r *http.Request) {
+	stack, ok := s.fetchStack(w, r)
+	if !ok {
+		return
+	}
+
+	status := stackStatus{
+		Pending:         stack.Pending,
+		ProcessingError: stack.ProcessingError,
+	}
+	if !stack.ProcessedAt.IsZero() {
+		status.ProcessedAt = &stack.ProcessedAt
+	}
+
+	api.JSON(w, r, http.StatusOK, status)
+}
+
+func (s *galleryServer) uploadImage(w http.ResponseWriter, r *http.Request) {
+	s.upload(w, r, "image", s.client.UploadImage)
+}
+
+func (s *galleryServer) uploadVideo(w http.ResponseWriter, r *http.Request) {
+	s.upload(w, r, "video", s.client.UploadVideo)
+}
+
+func (s *galleryServer) upload(
+	w http.ResponseWriter,
+	r *http.Request,
+	fileField string,
+	upload func(_ context.Context, galleryID uuid.UUID, _ io.Reader, name, disk, path string) (gallery.Stack, error),
+) {
+	if _, ok := s.fetchGallery(w, r); !ok {
+		return
+	}
+
+	file, err := readMultipartUpload(r, fileField, "name", "disk", "path")
+	if err != nil {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(err, "Invalid file: %v", err))
+		return
+	}
+	defer file.Close()
+
+	name := file.Value("name")
+	disk := file.Value("disk")
+	path := file.Value("path")
+
+	galleryID := api.UUIDParam(r, "GalleryID")
+
+	stack, err := upload(r.Context(), galleryID, file, name, disk, path)
+	if err != nil {
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to upload %s: %v", fileField, err))
+		return
+	}
+
+	content := routes.ShowStackContent
+	if stack.IsVideo() {
+		content = routes.ShowStackVideo
+	}
+
+	res := createdStack{
+		Stack: stack,
+		Links: links{
+			Self:    s.routes.URL(routes.ShowStack, galleryID, stack.ID),
+			Content: s.routes.URL(content, galleryID, stack.ID),
+			Gallery: s.routes.URL(routes.ShowGallery, galleryID),
+		},
+	}
+
+	w.Header().Set("Location", res.Links.Self)
+	api.JSON(w, r, http.StatusCreated, res)
+}
+
+// createdStack is the response body of an uploaded Stack.
+type createdStack struct {
+	gallery.Stack
+	Links links `json:"links"`
+}
+
+func (s *galleryServer) deleteStack(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.fetchDeletableStack(w, r); !ok {
+		return
+	}
+
+	galleryID, stackID := api.UUIDParam(r, "GalleryID"), api.UUIDParam(r, "StackID")
+	if !s.change(w, r, gallery.TrashStack(galleryID, stackID).Any(), func(c GalleryCommandClient) error {
+		return c.DeleteStack(r.Context(), galleryID, stackID)
+	}) {
+		return
+	}
+
+	api.NoContent(w, r)
+}
+
+// fetchDeletableStack fetches the Stack like fetchGalleryStack, but responds
+// with 409 Conflict if the Stack or its Gallery is under legal hold.
+func (s *galleryServer) fetchDeletableStack(w http.ResponseWriter, r *http.Request) (gallery.Stack, bool) {
+	g, stack, ok := s.fetchGalleryStack(w, r)
+	if !ok {
+		return stack, false
+	}
+
+	if g.Held(stack.ID) {
+		api.Error(w, r, http.StatusConflict, api.Friendly(gallery.ErrLegalHold, "Stack %q is under legal hold.", stack.ID))
+		return stack, false
+	}
+
+	return stack, true
+}
+
+// setLegalHold sets a legal hold on the Gallery, or on the Stack of the
+// StackID URL parameter if the route has one.
+func (s *galleryServer) setLegalHold(w http.ResponseWriter, r *http.Request) {
+	var req legalHoldRequest
+	if err := api.Decode(r.Body, &req); err != nil {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	galleryID, id, ok := s.fetchHoldTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if !s.dispatch(w, r, gallery.SetLegalHold(galleryID, id, req.Reason).Any()) {
+		return
+	}
+
+	api.NoContent(w, r)
+}
+
+// liftLegalHold lifts the legal hold of the Gallery, or of the Stack of the
+// StackID URL parameter if the route has one.
+func (s *galleryServer) liftLegalHold(w http.ResponseWriter, r *http.Request) {
+	galleryID, id, ok := s.fetchHoldTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if !s.dispatch(w, r, gallery.LiftLegalHold(galleryID, id).Any()) {
 		return
 	}
 
 	api.NoContent(w, r)
 }
 
-func (s *galleryServer) tagStack(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Tags []string `json:"tags"`
+// fetchHoldTarget returns the GalleryID and StackID URL parameters of a legal
+// hold route. The StackID is uuid.Nil for the routes of the Gallery. Trashed
+// Stacks can be held, too. If the Gallery or Stack cannot be found, an error
+// response is written and false is returned.
+func (s *galleryServer) fetchHoldTarget(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	g, ok := s.fetchGallery(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id := api.UUIDParam(r, "StackID")
+	if id == uuid.Nil {
+		return g.ID, id, true
+	}
+
+	if _, err := g.Stack(id); err == nil {
+		return g.ID, id, true
+	}
+
+	if _, err := g.TrashedStack(id); err == nil {
+		return g.ID, id, true
+	}
+
+	api.Error(w, r, http.StatusNotFound, api.Friendly(gallery.ErrStackNotFound, "Stack %q not found.", id))
+	return g.ID, id, false
+}
+
+// showTrash responds with the Stacks in the trash of a Gallery.
+func (s *galleryServer) showTrash(w http.ResponseWriter, r *http.Request) {
+	g, ok := s.fetchGallery(w, r)
+	if !ok {
+		return
+	}
+
+	q, err := trashList.Parse(r)
+	if err != nil {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	trashed := make([]gallery.TrashedStack, 0, len(g.Trashed))
+	for _, stack := range g.Trashed {
+		original := stack.Original()
+		if q.Match("name", original.Name) && matchTags(q, original.Tags) {
+			trashed = append(trashed, stack)
+		}
+	}
+
+	api.SortFunc(trashed, q, func(field string, a, b gallery.TrashedStack) int {
+		switch field {
+		case "name":
+			return strings.Compare(strings.ToLower(a.Original().Name), strings.ToLower(b.Original().Name))
+		default:
+			return compareTimes(a.TrashedAt, b.TrashedAt)
+		}
+	})
+
+	trashed, page := api.Paginate(trashed, q)
+	api.WritePage(w, r, page)
+	api.JSON(w, r, http.StatusOK, trashed)
+}
+
+// trashList are the query parameters of the trash of a gallery. Without
+// parameters, all trashed stacks are listed in the order they were trashed.
+var trashList = api.List{
+	Sorts: []string{"name", "trashedAt"},
+	Filters: map[string][]api.Operator{
+		"name": {api.Eq, api.Contains, api.Prefix},
+		"tag":  {api.Eq},
+	},
+}
+
+// matchTags returns whether tags contain one of the "tag" filters of q.
+func matchTags(q api.ListQuery, tags []string) bool {
+	want := q.Values("tag", api.Eq)
+	if len(want) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		for _, w := range want {
+			if tag == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+func (s *galleryServer) restoreStack(w http.ResponseWriter, r *http.Request) {
+	g, ok := s.fetchGallery(w, r)
+	if !ok {
+		return
+	}
+
+	id := api.UUIDParam(r, "StackID")
+	trashed, err := g.TrashedStack(id)
+	if err != nil {
+		api.Error(w, r, http.StatusNotFound, api.Friendly(err, "Stack %q not found in trash.", id))
+		return
 	}
 
+	if !s.dispatch(w, r, gallery.RestoreStack(g.ID, id).Any()) {
+		return
+	}
+
+	api.JSON(w, r, http.StatusOK, trashed.Stack)
+}
+
+func (s *galleryServer) tagStack(w http.ResponseWriter, r *http.Request) {
+	var req tagRequest
 	if err := api.Decode(r.Body, &req); err != nil {
 		api.Error(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	galleryID, err := api.ExtractUUID(r, "GalleryID")
-	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, err)
+	if _, ok := s.fetchStack(w, r); !ok {
 		return
 	}
 
-	stackID, err := api.ExtractUUID(r, "StackID")
-	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, err)
+	galleryID, stackID := api.UUIDParam(r, "GalleryID"), api.UUIDParam(r, "StackID")
+	if !s.change(w, r, gallery.TagStack(galleryID, stackID, req.Tags).Any(), func(c GalleryCommandClient) error {
+		_, err := c.TagStack(r.Context(), galleryID, stackID, req.Tags)
+		return err
+	}) {
+		return
+	}
+
+	s.showStack(w, r, http.StatusCreated)
+}
+
+func (s *galleryServer) untagStack(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.fetchStack(w, r); !ok {
+		return
+	}
+
+	tags := strings.Split(chi.URLParam(r, "Tags"), ",")
+
+	galleryID, stackID := api.UUIDParam(r, "GalleryID"), api.UUIDParam(r, "StackID")
+	if !s.change(w, r, gallery.UntagStack(galleryID, stackID, tags).Any(), func(c GalleryCommandClient) error {
+		_, err := c.UntagStack(r.Context(), galleryID, stackID, tags)
+		return err
+	}) {
 		return
 	}
 
-	cmd := gallery.TagStack(galleryID, stackID, req.Tags)
-	if err := s.commands.Dispatch(r.Context(), cmd.Any(), dispatch.Sync()); err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to dispatch %q command: %v", cmd.Name(), err))
+	s.showStack(w, r, http.StatusCreated)
+}
+
+func (s *galleryServer) replaceImage(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.fetchDeletableStack(w, r); !ok {
 		return
 	}
 
-	g, err := s.client.FetchGallery(r.Context(), galleryID)
+	file, err := readMultipartUpload(r, "image")
 	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Gallery %q not found: %v", galleryID, err))
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(err, "Invalid file: %v", err))
 		return
 	}
+	defer file.Close()
 
-	stack, err := g.Stack(stackID)
+	replaced, err := s.client.ReplaceImage(r.Context(), api.UUIDParam(r, "GalleryID"), api.UUIDParam(r, "StackID"), file)
 	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Stack %q not found.", stackID))
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to replace image: %v", err))
+		return
 	}
 
-	api.JSON(w, r, http.StatusCreated, stack)
+	api.JSON(w, r, http.StatusOK, replaced)
 }
 
-func (s *galleryServer) untagStack(w http.ResponseWriter, r *http.Request) {
-	galleryID, err := api.ExtractUUID(r, "GalleryID")
-	if err != nil {
+func (s *galleryServer) updateStack(w http.ResponseWriter, r *http.Request) {
+	var req updateStackRequest
+	if err := api.Decode(r.Body, &req); err != nil {
 		api.Error(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	stackID, err := api.ExtractUUID(r, "StackID")
-	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, err)
+	setMetadata, deleteMetadata, ok := metadataPatch(w, r, req.CustomMetadata)
+	if !ok {
 		return
 	}
 
-	tags := strings.Split(chi.URLParam(r, "Tags"), ",")
+	stack, ok := s.fetchStack(w, r)
+	if !ok {
+		return
+	}
+
+	if req.Name != "" {
+		galleryID := api.UUIDParam(r, "GalleryID")
+		if !s.change(w, r, gallery.RenameStack(galleryID, stack.ID, req.Name).Any(), func(c GalleryCommandClient) error {
+			_, err := c.RenameStack(r.Context(), galleryID, stack.ID, req.Name)
+			return err
+		}) {
+			return
+		}
+	}
+
+	if req.Alt != nil || req.Caption != nil {
+		alt, caption := stack.Alt, stack.Caption
+		if req.Alt != nil {
+			alt = *req.Alt
+		}
+		if req.Caption != nil {
+			caption = *req.Caption
+		}
+		if !s.dispatch(w, r, gallery.UpdateStackMetadata(api.UUIDParam(r, "GalleryID"), stack.ID, alt, caption).Any()) {
+			return
+		}
+	}
+
+	if len(setMetadata) > 0 {
+		if !s.dispatch(w, r, gallery.SetCustomMetadata(api.UUIDParam(r, "GalleryID"), stack.ID, setMetadata).Any()) {
+			return
+		}
+	}
+
+	if len(deleteMetadata) > 0 {
+		if !s.dispatch(w, r, gallery.DeleteCustomMetadata(api.UUIDParam(r, "GalleryID"), stack.ID, deleteMetadata).Any()) {
+			return
+		}
+	}
+
+	s.showStack(w, r, http.StatusOK)
+}
 
-	cmd := gallery.UntagStack(galleryID, stackID, tags)
-	if err := s.commands.Dispatch(r.Context(), cmd.Any(), dispatch.Sync()); err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to dispatch %q command: %v", cmd.Name(), err))
+// setFocalPoint sets the focal point of the Stack. The cropped images of the
+// Stack are cropped again by the PostProcessor.
+func (s *galleryServer) setFocalPoint(w http.ResponseWriter, r *http.Request) {
+	var req focalPointRequest
+	if err := api.Decode(r.Body, &req); err != nil {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if req.X == nil || req.Y == nil {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(nil, "Missing %q or %q field.", "x", "y"))
 		return
 	}
 
-	g, err := s.client.FetchGallery(r.Context(), galleryID)
-	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Gallery %q not found: %v", galleryID, err))
+	focus := image.FocalPoint{X: *req.X, Y: *req.Y}
+	if !focus.Valid() {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(gallery.ErrInvalidFocalPoint, "%q and %q must be between 0 and 100.", "x", "y"))
 		return
 	}
 
-	stack, err := g.Stack(stackID)
-	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Stack %q not found.", stackID))
+	stack, ok := s.fetchStack(w, r)
+	if !ok {
+		return
+	}
+
+	if !s.dispatch(w, r, gallery.SetFocalPoint(api.UUIDParam(r, "GalleryID"), stack.ID, focus).Any()) {
+		return
 	}
 
-	api.JSON(w, r, http.StatusCreated, stack)
+	s.showStack(w, r, http.StatusOK)
 }
 
-func (s *galleryServer) replaceImage(w http.ResponseWriter, r *http.Request) {
-	file, _, err := r.FormFile("image")
-	if err != nil {
-		api.Error(w, r, http.StatusBadRequest, api.Friendly(err, "Invalid file: %v", err))
+// cropStack crops the Stack to a rectangle of its original image. The resized
+// and cropped images of the Stack are generated again by the PostProcessor.
+func (s *galleryServer) cropStack(w http.ResponseWriter, r *http.Request) {
+	var req cropRequest
+	if err := api.Decode(r.Body, &req); err != nil {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if req.X == nil || req.Y == nil || req.Width == nil || req.Height == nil {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(nil, "Missing %q, %q, %q or %q field.", "x", "y", "width", "height"))
 		return
 	}
-	defer file.Close()
 
-	galleryID, err := api.ExtractUUID(r, "GalleryID")
-	if err != nil {
+	area := image.Area{X: *req.X, Y: *req.Y, Width: *req.Width, Height: *req.Height}
+	if !area.Valid() {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(gallery.ErrInvalidCrop, "The rectangle must not be empty and must lie within the image."))
+		return
+	}
+
+	stack, ok := s.fetchStack(w, r)
+	if !ok {
+		return
+	}
+
+	if !s.dispatch(w, r, gallery.CropStack(api.UUIDParam(r, "GalleryID"), stack.ID, area).Any()) {
+		return
+	}
+
+	s.showStack(w, r, http.StatusOK)
+}
+
+// rotateStack rotates the original image of the Stack. The other images of the
+// Stack are generated again by the PostProcessor.
+func (s *galleryServer) rotateStack(w http.ResponseWriter, r *http.Request) {
+	var req rotateStackRequest
+	if err := api.Decode(r.Body, &req); err != nil {
 		api.Error(w, r, http.StatusBadRequest, err)
 		return
 	}
+	if req.Degrees == nil {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(nil, "Missing %q field.", "degrees"))
+		return
+	}
+	if !image.Rotation(*req.Degrees).Valid() {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(gallery.ErrInvalidTransformation, "%q must be a multiple of 90.", "degrees"))
+		return
+	}
 
-	stackID, err := api.ExtractUUID(r, "StackID")
-	if err != nil {
+	stack, ok := s.fetchDeletableStack(w, r)
+	if !ok {
+		return
+	}
+
+	if !s.dispatch(w, r, gallery.RotateStack(api.UUIDParam(r, "GalleryID"), stack.ID, *req.Degrees).Any()) {
+		return
+	}
+
+	s.showStack(w, r, http.StatusOK)
+}
+
+// flipStack flips the original image of the Stack. The other images of the
+// Stack are generated again by the PostProcessor.
+func (s *galleryServer) flipStack(w http.ResponseWriter, r *http.Request) {
+	var req flipStackRequest
+	if err := api.Decode(r.Body, &req); err != nil {
 		api.Error(w, r, http.StatusBadRequest, err)
 		return
 	}
+	flip := image.Flip(req.Direction)
+	if !flip.Valid() {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(gallery.ErrInvalidTransformation, "%q must be %q or %q.", "direction", image.FlipHorizontal, image.FlipVertical))
+		return
+	}
 
-	replaced, err := s.client.ReplaceImage(r.Context(), galleryID, stackID, file)
-	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to replace image: %v", err))
+	stack, ok := s.fetchDeletableStack(w, r)
+	if !ok {
 		return
 	}
 
-	api.JSON(w, r, http.StatusOK, replaced)
+	if !s.dispatch(w, r, gallery.FlipStack(api.UUIDParam(r, "GalleryID"), stack.ID, flip).Any()) {
+		return
+	}
+
+	s.showStack(w, r, http.StatusOK)
 }
 
-func (s *galleryServer) updateStack(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Name string `json:"name"`
+// reprocessStack requests the PostProcessor to process the Stack again and
+// responds with 202 Accepted and the Stack, whose images are stale until they
+// have been generated again.
+func (s *galleryServer) reprocessStack(w http.ResponseWriter, r *http.Request) {
+	stack, ok := s.fetchStack(w, r)
+	if !ok {
+		return
 	}
 
-	if err := api.Decode(r.Body, &req); err != nil {
-		api.Error(w, r, http.StatusBadGateway, err)
+	if !s.dispatch(w, r, gallery.ReprocessStack(api.UUIDParam(r, "GalleryID"), stack.ID).Any()) {
 		return
 	}
 
-	galleryID, err := api.ExtractUUID(r, "GalleryID")
-	if err != nil {
+	s.showStack(w, r, http.StatusAccepted)
+}
+
+func (s *galleryServer) sortGallery(w http.ResponseWriter, r *http.Request) {
+	var req sortGalleryRequest
+	if err := api.Decode(r.Body, &req); err != nil {
 		api.Error(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	stackID, err := api.ExtractUUID(r, "StackID")
-	if err != nil {
+	if _, ok := s.fetchGallery(w, r); !ok {
+		return
+	}
+
+	galleryID := api.UUIDParam(r, "GalleryID")
+	if !s.change(w, r, gallery.Sort(galleryID, req.Sorting).Any(), func(c GalleryCommandClient) error {
+		return c.SortGallery(r.Context(), galleryID, req.Sorting)
+	}) {
+		return
+	}
+
+	api.NoContent(w, r)
+}
+
+// moveStack moves the Stack from the StackID URL parameter in front of the
+// Stack from the request body, or to the end of the gallery.
+func (s *galleryServer) moveStack(w http.ResponseWriter, r *http.Request) {
+	var req moveStackRequest
+	if err := api.Decode(r.Body, &req); err != nil && !errors.Is(err, io.EOF) {
 		api.Error(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	if req.Name != "" {
-		cmd := gallery.RenameStack(galleryID, stackID, req.Name)
-		if err := s.commands.Dispatch(r.Context(), cmd.Any(), dispatch.Sync()); err != nil {
-			api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to dispatch %q command: %v", cmd.Name(), err))
+	g, ok := s.fetchGallery(w, r)
+	if !ok {
+		return
+	}
+
+	galleryID, stackID := api.UUIDParam(r, "GalleryID"), api.UUIDParam(r, "StackID")
+	var beforeID uuid.UUID
+	if req.Before != nil {
+		beforeID = *req.Before
+	}
+
+	for _, id := range []uuid.UUID{stackID, beforeID} {
+		if id == uuid.Nil {
+			continue
+		}
+		if _, err := g.Stack(id); err != nil {
+			api.Error(w, r, http.StatusNotFound, api.Friendly(err, "Stack %q not found.", id))
 			return
 		}
 	}
 
-	g, err := s.client.FetchGallery(r.Context(), galleryID)
-	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Gallery %q not found: %v", galleryID, err))
+	if !s.dispatch(w, r, gallery.MoveStackBefore(galleryID, stackID, beforeID).Any()) {
 		return
 	}
 
-	stack, err := g.Stack(stackID)
-	if err != nil {
-		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Stack %q not found.", stackID))
+	api.NoContent(w, r)
+}
+
+// showStack responds with the Stack from the GalleryID and StackID URL
+// parameters.
+func (s *galleryServer) setDefaultTags(w http.ResponseWriter, r *http.Request) {
+	var req defaultTagsRequest
+	if err := api.Decode(r.Body, &req); err != nil {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return
 	}
 
-	api.JSON(w, r, http.StatusOK, stack)
+	if _, ok := s.fetchGallery(w, r); !ok {
+		return
+	}
+
+	if !s.dispatch(w, r, gallery.SetDefaultTags(api.UUIDParam(r, "GalleryID"), req.Tags).Any()) {
+		return
+	}
+
+	api.NoContent(w, r)
 }
 
-func (s *galleryServer) sortGallery(w http.ResponseWriter, r *http.Request) {
-	galleryID, err := api.ExtractUUID(r, "GalleryID")
-	if err != nil {
+func (s *galleryServer) protectOriginals(w http.ResponseWriter, r *http.Request) {
+	var req originalsProtectionRequest
+	if err := api.Decode(r.Body, &req); err != nil {
 		api.Error(w, r, http.StatusBadRequest, err)
 		return
 	}
+	if req.Protected == nil {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(nil, "Missing %q field.", "protected"))
+		return
+	}
+
+	if _, ok := s.fetchGallery(w, r); !ok {
+		return
+	}
+
+	if !s.dispatch(w, r, gallery.ProtectOriginals(api.UUIDParam(r, "GalleryID"), *req.Protected).Any()) {
+		return
+	}
 
-	var req struct{ Sorting []uuid.UUID }
+	api.NoContent(w, r)
+}
 
+func (s *galleryServer) setVersionLimit(w http.ResponseWriter, r *http.Request) {
+	var req versionLimitRequest
 	if err := api.Decode(r.Body, &req); err != nil {
 		api.Error(w, r, http.StatusBadRequest, err)
 		return
 	}
+	if req.Limit == nil || *req.Limit < 0 {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(nil, "%q must be a non-negative number.", "limit"))
+		return
+	}
 
-	cmd := gallery.Sort(galleryID, req.Sorting)
+	if _, ok := s.fetchGallery(w, r); !ok {
+		return
+	}
 
-	if err := s.commands.Dispatch(r.Context(), cmd.Any(), dispatch.Sync()); err != nil {
-		api.Error(w, r, http.StatusInternalServerError, err)
+	if !s.dispatch(w, r, gallery.SetVersionLimit(api.UUIDParam(r, "GalleryID"), *req.Limit).Any()) {
 		return
 	}
 
 	api.NoContent(w, r)
 }
+
+func (s *galleryServer) showVersions(w http.ResponseWriter, r *http.Request) {
+	stack, ok := s.fetchStack(w, r)
+	if !ok {
+		return
+	}
+
+	q, err := versionList.Parse(r)
+	if err != nil {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	versions := make([]gallery.StackVersion, 0, len(stack.Versions))
+	for _, v := range stack.Versions {
+		if q.Match("version", strconv.Itoa(v.Version)) {
+			versions = append(versions, v)
+		}
+	}
+
+	api.SortFunc(versions, q, func(field string, a, b gallery.StackVersion) int {
+		switch field {
+		case "replacedAt":
+			return compareTimes(a.ReplacedAt, b.ReplacedAt)
+		default:
+			return a.Version - b.Version
+		}
+	})
+
+	versions, page := api.Paginate(versions, q)
+	api.WritePage(w, r, page)
+	api.JSON(w, r, http.StatusOK, versions)
+}
+
+// versionList are the query parameters of the versions of a stack. Without
+// parameters, all versions are listed, oldest first.
+var versionList = api.List{
+	Sorts: []string{"version", "replacedAt"},
+	Filters: map[string][]api.Operator{
+		"version": {api.Eq, api.Gt, api.Gte, api.Lt, api.Lte},
+	},
+}
+
+func (s *galleryServer) revertStack(w http.ResponseWriter, r *http.Request) {
+	var req revertStackRequest
+	if err := api.Decode(r.Body, &req); err != nil {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if req.Version == nil {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(nil, "Missing %q field.", "version"))
+		return
+	}
+
+	stack, ok := s.fetchDeletableStack(w, r)
+	if !ok {
+		return
+	}
+
+	var found bool
+	for _, v := range stack.Versions {
+		if v.Version == *req.Version {
+			found = true
+			break
+		}
+	}
+	if !found {
+		api.Error(w, r, http.StatusNotFound, api.Friendly(gallery.ErrVersionNotFound, "Version %d of stack %q not found.", *req.Version, stack.ID))
+		return
+	}
+
+	if !s.dispatch(w, r, gallery.RevertStack(api.UUIDParam(r, "GalleryID"), stack.ID, *req.Version).Any()) {
+		return
+	}
+
+	s.showStack(w, r, http.StatusOK)
+}
+
+func (s *galleryServer) showStack(w http.ResponseWriter, r *http.Request, status int) {
+	stack, ok := s.fetchStack(w, r)
+	if !ok {
+		return
+	}
+
+	api.JSON(w, r, status, stack)
+}
+
+func (s *galleryServer) dispatch(w http.ResponseWriter, r *http.Request, cmd command.Command) bool {
+	return dispatchCommand(w, r, s.commands, cmd)
+}
+
+// change calls fn with the client if it implements GalleryCommandClient and
+// dispatches cmd otherwise. If the change fails, an error response is written
+// and false is returned.
+func (s *galleryServer) change(w http.ResponseWriter, r *http.Request, cmd command.Command, fn func(GalleryCommandClient) error) bool {
+	client, ok := s.client.(GalleryCommandClient)
+	if !ok {
+		return s.dispatch(w, r, cmd)
+	}
+	return changeThrough(w, r, cmd, func() error { return fn(client) })
+}
+
+// decodeCreateRequest decodes the name of a Shelf or Gallery from the request
+// body. The name is trimmed like the aggregates do. If the body is malformed or
+// the name is empty, an error response is written and false is returned.
+func decodeCreateRequest(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var req createRequest
+	if err := api.Decode(r.Body, &req); err != nil {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return "", false
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(document.ErrEmptyName, "Name is required."))
+		return "", false
+	}
+
+	return name, true
+}
+
+// metadataPatch splits the custom metadata of a request body into the fields
+// to set and the keys to delete. Null values delete a field. If a key is empty,
+// an error response is written and false is returned.
+func metadataPatch(w http.ResponseWriter, r *http.Request, patch map[string]*string) (map[string]string, []string, bool) {
+	set := make(map[string]string)
+	var del []string
+	for key, val := range patch {
+		if strings.TrimSpace(key) == "" {
+			api.Error(w, r, http.StatusBadRequest, api.Friendly(media.ErrEmptyMetadataKey, "Metadata keys must not be empty."))
+			return nil, nil, false
+		}
+		if val == nil {
+			del = append(del, key)
+			continue
+		}
+		set[key] = *val
+	}
+	sort.Strings(del)
+	return set, del, true
+}
+
+// changeThrough makes the change of cmd by calling fn instead of dispatching
+// cmd. If fn fails, an error response is written and false is returned.
+func changeThrough(w http.ResponseWriter, r *http.Request, cmd command.Command, fn func() error) bool {
+	if err := fn(); err != nil {
+		api.Error(w, r, errorStatus(err), api.Friendly(err, "Failed to execute %q command: %v", cmd.Name(), err))
+		return false
+	}
+	return true
+}
+
+// dispatchCommand synchronously dispatches cmd. If the command fails, an error
+// response is written and false is returned. Without a command bus, the
+// response is 501 Not Implemented.
+func dispatchCommand(w http.ResponseWriter, r *http.Request, bus command.Bus, cmd command.Command) bool {
+	if bus == nil {
+		api.Error(w, r, http.StatusNotImplemented, api.Friendly(nil, "%q command is not supported without a command bus.", cmd.Name()))
+		return false
+	}
+	if err := bus.Dispatch(r.Context(), cmd, dispatch.Sync()); err != nil {
+		api.Error(w, r, errorStatus(err), api.Friendly(err, "Failed to dispatch %q command: %v", cmd.Name(), err))
+		return false
+	}
+	return true
+}
+
+// errorStatus returns 404 Not Found if err indicates a missing resource and
+// 500 Internal Server Error otherwise.
+func errorStatus(err error) int {
+	if status.Code(err) == codes.NotFound ||
+		errors.Is(err, gallery.ErrNotFound) ||
+		errors.Is(err, gallery.ErrStackNotFound) ||
+		errors.Is(err, document.ErrShelfNotFound) ||
+		errors.Is(err, document.ErrNotFound) ||
+		errors.Is(err, document.ErrNotTrashed) ||
+		errors.Is(err, gallery.ErrNotTrashed) {
+		return http.StatusNotFound
+	}
+	if errors.Is(err, document.ErrLegalHold) ||
+		errors.Is(err, gallery.ErrLegalHold) ||
+		errors.Is(err, document.ErrDuplicatePath) {
+		return http.StatusConflict
+	}
+	if status.Code(err) == codes.InvalidArgument ||
+		errors.Is(err, gallery.ErrInvalidFocalPoint) ||
+		errors.Is(err, gallery.ErrInvalidCrop) ||
+		errors.Is(err, gallery.ErrInvalidTransformation) ||
+		errors.Is(err, gallery.ErrVideoStack) {
+		return http.StatusBadRequest
+	}
+	if status.Code(err) == codes.Unimplemented {
+		return http.StatusNotImplemented
+	}
+	return http.StatusInternalServerError
+}
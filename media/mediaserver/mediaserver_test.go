@@ -0,0 +1,1831 @@
+package mediaserver_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/command"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/delta"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/media/mediaserver"
+	"github.com/modernice/nice-cms/media/mediaserver/routes"
+	"github.com/modernice/nice-cms/media/usage"
+	"github.com/modernice/nice-cms/projector"
+)
+
+var (
+	shelfID    = uuid.New()
+	documentID = uuid.New()
+	trashedID  = uuid.New()
+	galleryID  = uuid.New()
+	stackID    = uuid.New()
+
+	trashedStackID = uuid.New()
+)
+
+type routeTest struct {
+	route routes.Route
+	body  func() (io.Reader, string)
+
+	// status is the expected status code for existing resources.
+	status int
+
+	// params overrides the default URL parameters of existing resources.
+	params pathParams
+
+	// query is the query string of the request.
+	query string
+}
+
+// defaultParams returns the URL parameters of the existing resources of the
+// test.
+func (tt routeTest) defaultParams() pathParams {
+	params := defaultParams()
+	for name, val := range tt.params {
+		params[name] = val
+	}
+	return params
+}
+
+var routeTests = []routeTest{
+	{route: routes.ListShelfs, status: http.StatusOK},
+	{route: routes.CreateShelf, body: jsonBody(`{"name": "bar"}`), status: http.StatusCreated},
+	{route: routes.LookupShelfByName, status: http.StatusOK},
+	{route: routes.ShowShelf, status: http.StatusOK},
+	{route: routes.RenameShelf, body: jsonBody(`{"name": "bar"}`), status: http.StatusOK},
+	{route: routes.DeleteShelf, status: http.StatusNoContent},
+	{route: routes.SearchDocuments, status: http.StatusOK},
+	{route: routes.ShowDocument, status: http.StatusOK},
+	{route: routes.ShowDocumentContent, status: http.StatusOK},
+	{route: routes.HeadDocumentContent, status: http.StatusOK},
+	{route: routes.ShowDocumentPreview, status: http.StatusOK},
+	{route: routes.UploadDocument, body: multipartBody("document"), status: http.StatusCreated},
+	{route: routes.ReplaceDocument, body: multipartBody("document"), status: http.StatusOK},
+	{route: routes.ReplaceDocumentDelta, body: deltaBody("foo", "foobar"), status: http.StatusOK},
+	{route: routes.UpdateDocument, body: jsonBody(`{"name": "bar"}`), status: http.StatusOK},
+	{route: routes.DeleteDocument, status: http.StatusNoContent},
+	{route: routes.TagDocument, body: jsonBody(`{"tags": ["foo"]}`), status: http.StatusOK},
+	{route: routes.UntagDocument, status: http.StatusOK},
+	{route: routes.CopyDocument, body: jsonBody(`{"shelfId": "` + shelfID.String() + `"}`), status: http.StatusAccepted},
+	{route: routes.RetagDocuments, body: jsonBody(`{"documentIds": ["` + documentID.String() + `"], "add": ["foo"]}`), status: http.StatusOK},
+	{route: routes.SignDocumentURL, body: jsonBody(`{"expiry": 60}`), status: http.StatusOK},
+	{route: routes.RestoreDocument, status: http.StatusOK, params: pathParams{"DocumentID": trashedID.String()}},
+	{route: routes.SetShelfLegalHold, body: jsonBody(`{"reason": "case-1"}`), status: http.StatusNoContent},
+	{route: routes.LiftShelfLegalHold, status: http.StatusNoContent},
+	{route: routes.SetDocumentLegalHold, body: jsonBody(`{"reason": "case-1"}`), status: http.StatusNoContent},
+	{route: routes.LiftDocumentLegalHold, status: http.StatusNoContent},
+
+	{route: routes.ListGalleries, status: http.StatusOK},
+	{route: routes.CreateGallery, body: jsonBody(`{"name": "bar"}`), status: http.StatusCreated},
+	{route: routes.LookupGalleryByName, status: http.StatusOK},
+	{route: routes.LookupGalleryStackByName, status: http.StatusOK},
+	{route: routes.LookupGalleryStacksByTag, query: "tag=foo", status: http.StatusOK},
+	{route: routes.ShowGallery, status: http.StatusOK},
+	{route: routes.RenameGallery, body: jsonBody(`{"name": "bar"}`), status: http.StatusOK},
+	{route: routes.DeleteGallery, status: http.StatusNoContent},
+	{route: routes.ShowGalleryIndex, status: http.StatusOK},
+	{route: routes.ShowStack, status: http.StatusOK},
+	{route: routes.ShowStackContent, status: http.StatusOK},
+	{route: routes.HeadStackContent, status: http.StatusOK},
+	{route: routes.ShowStackOriginal, status: http.StatusOK},
+	{route: routes.SignStackURL, body: jsonBody(`{"expiry": 60}`), status: http.StatusOK},
+	{route: routes.ShowStackVideo, status: http.StatusOK},
+	{route: routes.ShowStackStatus, status: http.StatusOK},
+	{route: routes.UploadImage, body: multipartBody("image"), status: http.StatusCreated},
+	{route: routes.UploadVideo, body: multipartBody("video"), status: http.StatusCreated},
+	{route: routes.ReplaceImage, body: multipartBody("image"), status: http.StatusOK},
+	{route: routes.UpdateStack, body: jsonBody(`{"name": "bar"}`), status: http.StatusOK},
+	{route: routes.SetStackFocalPoint, body: jsonBody(`{"x": 30, "y": 60}`), status: http.StatusOK},
+	{route: routes.CropStack, body: jsonBody(`{"x": 10, "y": 10, "width": 50, "height": 80}`), status: http.StatusOK},
+	{route: routes.RotateStack, body: jsonBody(`{"degrees": 90}`), status: http.StatusOK},
+	{route: routes.FlipStack, body: jsonBody(`{"direction": "horizontal"}`), status: http.StatusOK},
+	{route: routes.ReprocessStack, status: http.StatusAccepted},
+	{route: routes.DeleteStack, status: http.StatusNoContent},
+	{route: routes.TagStack, body: jsonBody(`{"tags": ["foo"]}`), status: http.StatusCreated},
+	{route: routes.UntagStack, status: http.StatusCreated},
+	{route: routes.SortGallery, body: jsonBody(`{"sorting": []}`), status: http.StatusNoContent},
+	{route: routes.MoveStack, status: http.StatusNoContent},
+	{route: routes.SetGalleryDefaultTags, body: jsonBody(`{"tags": ["foo"]}`), status: http.StatusNoContent},
+	{route: routes.ProtectGalleryOriginals, body: jsonBody(`{"protected": true}`), status: http.StatusNoContent},
+	{route: routes.ShowGalleryTrash, status: http.StatusOK},
+	{route: routes.RestoreStack, status: http.StatusOK, params: pathParams{"StackID": trashedStackID.String()}},
+	{route: routes.SetGalleryLegalHold, body: jsonBody(`{"reason": "case-1"}`), status: http.StatusNoContent},
+	{route: routes.LiftGalleryLegalHold, status: http.StatusNoContent},
+	{route: routes.SetStackLegalHold, body: jsonBody(`{"reason": "case-1"}`), status: http.StatusNoContent},
+	{route: routes.LiftStackLegalHold, status: http.StatusNoContent},
+	{route: routes.SetGalleryVersionLimit, body: jsonBody(`{"limit": 3}`), status: http.StatusNoContent},
+	{route: routes.ShowStackVersions, status: http.StatusOK},
+	{route: routes.RevertStack, body: jsonBody(`{"version": 0}`), status: http.StatusOK},
+}
+
+func TestServer_routes(t *testing.T) {
+	for _, tt := range routeTests {
+		t.Run(tt.route.Method+" "+tt.route.Path, func(t *testing.T) {
+			srv, _ := newServer(t)
+
+			rec := serve(srv, tt, tt.defaultParams())
+
+			if rec.Code != tt.status {
+				t.Fatalf("status should be %d; got %d (%s)", tt.status, rec.Code, rec.Body)
+			}
+
+			if rec.writes > 1 {
+				t.Fatalf("response header was written %d times", rec.writes)
+			}
+		})
+	}
+}
+
+func TestServer_routes_invalidUUID(t *testing.T) {
+	for _, tt := range routeTests {
+		for _, param := range []string{"ShelfID", "DocumentID", "GalleryID", "StackID"} {
+			if !strings.Contains(tt.route.Path, "{"+param+"}") {
+				continue
+			}
+
+			t.Run(fmt.Sprintf("%s %s (%s)", tt.route.Method, tt.route.Path, param), func(t *testing.T) {
+				srv, bus := newServer(t)
+
+				params := tt.defaultParams()
+				params[param] = "invalid"
+
+				rec := serve(srv, tt, params)
+
+				if rec.Code != http.StatusBadRequest {
+					t.Fatalf("status should be %d; got %d (%s)", http.StatusBadRequest, rec.Code, rec.Body)
+				}
+
+				if rec.writes > 1 {
+					t.Fatalf("response header was written %d times", rec.writes)
+				}
+
+				if len(bus.dispatched) > 0 {
+					t.Fatalf("no commands should have been dispatched; got %v", bus.dispatched)
+				}
+			})
+		}
+	}
+}
+
+func TestServer_routes_notFound(t *testing.T) {
+	for _, tt := range routeTests {
+		for _, param := range []string{"Name", "ShelfID", "DocumentID", "GalleryID", "StackID"} {
+			if !strings.Contains(tt.route.Path, "{"+param+"}") {
+				continue
+			}
+
+			t.Run(fmt.Sprintf("%s %s (%s)", tt.route.Method, tt.route.Path, param), func(t *testing.T) {
+				srv, bus := newServer(t)
+
+				params := tt.defaultParams()
+				params[param] = uuid.NewString()
+
+				rec := serve(srv, tt, params)
+
+				if rec.Code != http.StatusNotFound {
+					t.Fatalf("status should be %d; got %d (%s)", http.StatusNotFound, rec.Code, rec.Body)
+				}
+
+				if rec.writes > 1 {
+					t.Fatalf("response header was written %d times", rec.writes)
+				}
+
+				if len(bus.dispatched) > 0 {
+					t.Fatalf("no commands should have been dispatched; got %v", bus.dispatched)
+				}
+			})
+		}
+	}
+}
+
+func TestServer_routes_malformedBody(t *testing.T) {
+	for _, tt := range routeTests {
+		if tt.body == nil || tt.route.Method != http.MethodPatch && !strings.HasSuffix(tt.route.Path, "/tags") {
+			continue
+		}
+
+		t.Run(tt.route.Method+" "+tt.route.Path, func(t *testing.T) {
+			srv, bus := newServer(t)
+
+			tt.body = jsonBody("{")
+			rec := serve(srv, tt, tt.defaultParams())
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status should be %d; got %d (%s)", http.StatusBadRequest, rec.Code, rec.Body)
+			}
+
+			if len(bus.dispatched) > 0 {
+				t.Fatalf("no commands should have been dispatched; got %v", bus.dispatched)
+			}
+		})
+	}
+}
+
+type pathParams map[string]string
+
+// defaultParams returns the URL parameters of the existing resources.
+func defaultParams() pathParams {
+	return pathParams{
+		"Name":       "foo",
+		"Tags":       "foo,bar",
+		"ShelfID":    shelfID.String(),
+		"DocumentID": documentID.String(),
+		"GalleryID":  galleryID.String(),
+		"StackID":    stackID.String(),
+	}
+}
+
+func serve(srv http.Handler, tt routeTest, params pathParams) *recorder {
+	path := tt.route.Path
+	for name, val := range params {
+		path = strings.ReplaceAll(path, "{"+name+"}", val)
+	}
+
+	var body io.Reader
+	var contentType string
+	if tt.body != nil {
+		body, contentType = tt.body()
+	}
+
+	if tt.query != "" {
+		path += "?" + tt.query
+	}
+
+	req := httptest.NewRequest(tt.route.Method, path, body)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	rec := &recorder{ResponseRecorder: httptest.NewRecorder()}
+	srv.ServeHTTP(rec, req)
+
+	return rec
+}
+
+// recorder counts the number of times the response header is written.
+type recorder struct {
+	*httptest.ResponseRecorder
+
+	writes int
+}
+
+func (r *recorder) WriteHeader(code int) {
+	r.writes++
+	r.ResponseRecorder.WriteHeader(code)
+}
+
+func jsonBody(body string) func() (io.Reader, string) {
+	return func() (io.Reader, string) {
+		return strings.NewReader(body), "application/json"
+	}
+}
+
+func multipartBody(fileField string) func() (io.Reader, string) {
+	return func() (io.Reader, string) {
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		w.WriteField("name", "foo")
+		fw, _ := w.CreateFormFile(fileField, "foo.txt")
+		fw.Write([]byte("foo"))
+		w.Close()
+		return &buf, w.FormDataContentType()
+	}
+}
+
+func deltaBody(base, target string) func() (io.Reader, string) {
+	return func() (io.Reader, string) {
+		d, _ := delta.Diff(strings.NewReader(base), []byte(target))
+		b, _ := d.MarshalBinary()
+		return bytes.NewReader(b), "application/octet-stream"
+	}
+}
+
+func newServer(t *testing.T) (*mediaserver.Server, *commandBus) {
+	bus := &commandBus{}
+	return newServerWith(t, bus, func(d documentClient, g galleryClient) (mediaserver.DocumentClient, mediaserver.GalleryClient) {
+		return d, g
+	}), bus
+}
+
+// newServerWith creates a Server like newServer, but with the given command
+// bus and the clients that are returned by clients.
+func newServerWith(
+	t *testing.T,
+	bus command.Bus,
+	clients func(documentClient, galleryClient) (mediaserver.DocumentClient, mediaserver.GalleryClient),
+) *mediaserver.Server {
+	ctx := context.Background()
+
+	signer := media.NewHMACSigner([]byte("secret"), "https://files.example.com")
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.WithURLSigner(media.MemoryDisk(), signer)))
+	disk, _ := storage.Disk("foo-disk")
+	if err := disk.Put(ctx, "/foo.txt", []byte("foo")); err != nil {
+		t.Fatalf("put file: %v", err)
+	}
+
+	file := media.NewFile("foo", "foo-disk", "/foo.txt", 3)
+	file.Checksum, _ = media.Checksum(strings.NewReader("foo"))
+
+	shelf := document.JSONShelf{
+		ID:   shelfID,
+		Name: "foo",
+		Documents: []document.Document{{
+			Document: media.Document{File: file},
+			ID:       documentID,
+			Preview: &document.Preview{
+				Status: document.PreviewStatusRendered,
+				Image:  media.Image{File: file},
+			},
+		}},
+		Trashed: []document.TrashedDocument{{
+			Document: document.Document{
+				Document:   media.Document{File: file},
+				ID:         trashedID,
+				UniqueName: "trashed",
+			},
+			TrashedAt: time.Now(),
+		}},
+	}
+
+	g := gallery.JSONGallery{
+		ID:   galleryID,
+		Name: "foo",
+		Stacks: gallery.Stacks{{
+			ID: stackID,
+			Images: []gallery.Image{{
+				Image:    media.Image{File: file},
+				Original: true,
+			}},
+			Video:   &media.Video{File: file},
+			Version: 1,
+			Versions: []gallery.StackVersion{{
+				Images: []gallery.Image{{
+					Image:    media.Image{File: file},
+					Original: true,
+				}},
+				ReplacedAt: time.Now(),
+			}},
+		}},
+		Trashed: []gallery.TrashedStack{{
+			Stack: gallery.Stack{
+				ID: trashedStackID,
+				Images: []gallery.Image{{
+					Image:    media.Image{File: file},
+					Original: true,
+				}},
+			},
+			TrashedAt: time.Now(),
+		}},
+	}
+
+	documents, galleries := clients(documentClient{shelf}, galleryClient{g})
+
+	return mediaserver.New(
+		bus,
+		mediaserver.WithStorage(storage),
+		mediaserver.WithDocuments(documents, ""),
+		mediaserver.WithGalleries(galleries),
+	)
+}
+
+type documentClient struct{ shelf document.JSONShelf }
+
+func (c documentClient) LookupShelfByName(_ context.Context, name string) (uuid.UUID, bool, error) {
+	if name != c.shelf.Name {
+		return uuid.Nil, false, nil
+	}
+	return c.shelf.ID, true, nil
+}
+
+func (c documentClient) UploadDocument(_ context.Context, _ uuid.UUID, r io.Reader, uniqueName, name, disk, path string) (document.Document, error) {
+	io.Copy(io.Discard, r)
+	return c.shelf.Documents[0], nil
+}
+
+func (c documentClient) ReplaceDocument(_ context.Context, _, _ uuid.UUID, r io.Reader) (document.Document, error) {
+	io.Copy(io.Discard, r)
+	return c.shelf.Documents[0], nil
+}
+
+func (c documentClient) ReplaceDocumentDelta(_ context.Context, _, _ uuid.UUID, d delta.Delta) (document.Document, error) {
+	if d.BaseChecksum != c.shelf.Documents[0].Checksum {
+		return document.Document{}, delta.ErrBaseMismatch
+	}
+	return c.shelf.Documents[0], nil
+}
+
+func (c documentClient) FetchShelf(_ context.Context, id uuid.UUID) (document.JSONShelf, error) {
+	if id != c.shelf.ID {
+		return document.JSONShelf{}, fmt.Errorf("fetch shelf %q: %w", id, document.ErrShelfNotFound)
+	}
+	return c.shelf, nil
+}
+
+func (c documentClient) ListDocuments(_ context.Context, id uuid.UUID, q document.Query) (document.DocumentPage, error) {
+	if id != c.shelf.ID {
+		return document.DocumentPage{}, fmt.Errorf("list documents of shelf %q: %w", id, document.ErrShelfNotFound)
+	}
+	shelf := document.NewShelf(id)
+	shelf.Name = c.shelf.Name
+	shelf.Documents = c.shelf.Documents
+	return shelf.List(q), nil
+}
+
+func (c documentClient) SearchDocuments(_ context.Context, q document.SearchQuery) (document.SearchResults, error) {
+	shelf := document.NewShelf(c.shelf.ID)
+	shelf.Name = c.shelf.Name
+	shelf.Documents = c.shelf.Documents
+	page := shelf.List(document.Query{Offset: q.Offset, Limit: q.Limit, Tags: q.Tags, Search: q.Search})
+
+	results := document.SearchResults{Documents: []document.SearchResult{}, Total: page.Total, Offset: page.Offset, Limit: page.Limit}
+	for _, doc := range page.Documents {
+		results.Documents = append(results.Documents, document.SearchResult{Document: doc, ShelfID: shelf.ID, ShelfName: shelf.Name})
+	}
+	return results, nil
+}
+
+func (c documentClient) ListShelfs(_ context.Context, offset, limit int) (document.ShelfList, error) {
+	lookup := document.NewLookup()
+	shelf := document.NewShelf(c.shelf.ID)
+	shelf.Create(c.shelf.Name)
+	for _, evt := range shelf.AggregateChanges() {
+		lookup.ApplyEvent(evt)
+	}
+	return lookup.Shelfs(offset, limit), nil
+}
+
+type galleryClient struct{ gallery gallery.JSONGallery }
+
+func (c galleryClient) LookupGalleryByName(_ context.Context, name string) (uuid.UUID, bool, error) {
+	if name != c.gallery.Name {
+		return uuid.Nil, false, nil
+	}
+	return c.gallery.ID, true, nil
+}
+
+func (c galleryClient) LookupGalleryStackByName(_ context.Context, galleryID uuid.UUID, name string) (uuid.UUID, bool, error) {
+	if galleryID != c.gallery.ID || name != "foo" {
+		return uuid.Nil, false, nil
+	}
+	return c.gallery.Stacks[0].ID, true, nil
+}
+
+func (c galleryClient) LookupGalleryStacksByTag(_ context.Context, galleryID uuid.UUID, tag string) ([]uuid.UUID, bool, error) {
+	if galleryID != c.gallery.ID {
+		return nil, false, nil
+	}
+	ids := []uuid.UUID{}
+	for _, stack := range c.gallery.Stacks {
+		if stack.Original().HasTag(tag) {
+			ids = append(ids, stack.ID)
+		}
+	}
+	return ids, true, nil
+}
+
+func (c galleryClient) UploadImage(_ context.Context, _ uuid.UUID, r io.Reader, name, disk, path string) (gallery.Stack, error) {
+	io.Copy(io.Discard, r)
+	return c.gallery.Stacks[0], nil
+}
+
+func (c galleryClient) UploadVideo(_ context.Context, _ uuid.UUID, r io.Reader, name, disk, path string) (gallery.Stack, error) {
+	io.Copy(io.Discard, r)
+	return c.gallery.Stacks[0], nil
+}
+
+func (c galleryClient) ReplaceImage(_ context.Context, _, _ uuid.UUID, r io.Reader) (gallery.Stack, error) {
+	io.Copy(io.Discard, r)
+	return c.gallery.Stacks[0], nil
+}
+
+func (c galleryClient) FetchGallery(_ context.Context, id uuid.UUID) (gallery.JSONGallery, error) {
+	if id != c.gallery.ID {
+		return gallery.JSONGallery{}, fmt.Errorf("fetch gallery %q: %w", id, gallery.ErrNotFound)
+	}
+	return c.gallery, nil
+}
+
+func (c galleryClient) FetchGalleryIndex(ctx context.Context, id uuid.UUID) (gallery.JSONIndex, error) {
+	g, err := c.FetchGallery(ctx, id)
+	if err != nil {
+		return gallery.JSONIndex{}, err
+	}
+	return g.Index(), nil
+}
+
+func (c galleryClient) ListStacks(_ context.Context, id uuid.UUID, q gallery.Query) (gallery.StackPage, error) {
+	if id != c.gallery.ID {
+		return gallery.StackPage{}, fmt.Errorf("list stacks of gallery %q: %w", id, gallery.ErrNotFound)
+	}
+	g := gallery.New(id)
+	g.Implementation.Name = c.gallery.Name
+	g.Stacks = c.gallery.Stacks
+	return g.List(q), nil
+}
+
+func (c galleryClient) FetchStack(ctx context.Context, galleryID, stackID uuid.UUID) (gallery.Stack, error) {
+	g, err := c.FetchGallery(ctx, galleryID)
+	if err != nil {
+		return gallery.Stack{}, err
+	}
+	return g.Stack(stackID)
+}
+
+func (c galleryClient) ListGalleries(_ context.Context, offset, limit int) (gallery.GalleryList, error) {
+	lookup := gallery.NewLookup()
+	g := gallery.New(c.gallery.ID)
+	g.Create(c.gallery.Name)
+	for _, evt := range g.AggregateChanges() {
+		lookup.ApplyEvent(evt)
+	}
+	return lookup.Galleries(offset, limit), nil
+}
+
+// commandDocumentClient is a documentClient that records the changes that
+// are made through the client.
+type commandDocumentClient struct {
+	documentClient
+
+	changes []string
+}
+
+func (c *commandDocumentClient) TagDocument(context.Context, uuid.UUID, uuid.UUID, []string) (document.Document, error) {
+	c.changes = append(c.changes, "TagDocument")
+	return c.shelf.Documents[0], nil
+}
+
+func (c *commandDocumentClient) UntagDocument(context.Context, uuid.UUID, uuid.UUID, []string) (document.Document, error) {
+	c.changes = append(c.changes, "UntagDocument")
+	return c.shelf.Documents[0], nil
+}
+
+func (c *commandDocumentClient) RenameDocument(context.Context, uuid.UUID, uuid.UUID, string) (document.Document, error) {
+	c.changes = append(c.changes, "RenameDocument")
+	return c.shelf.Documents[0], nil
+}
+
+func (c *commandDocumentClient) DeleteDocument(context.Context, uuid.UUID, uuid.UUID) error {
+	c.changes = append(c.changes, "DeleteDocument")
+	return nil
+}
+
+// commandGalleryClient is a galleryClient that records the changes that are
+// made through the client.
+type commandGalleryClient struct {
+	galleryClient
+
+	changes []string
+}
+
+func (c *commandGalleryClient) SortGallery(context.Context, uuid.UUID, []uuid.UUID) error {
+	c.changes = append(c.changes, "SortGallery")
+	return nil
+}
+
+func (c *commandGalleryClient) TagStack(ctx context.Context, galleryID, stackID uuid.UUID, _ []string) (gallery.Stack, error) {
+	c.changes = append(c.changes, "TagStack")
+	return c.FetchStack(ctx, galleryID, stackID)
+}
+
+func (c *commandGalleryClient) UntagStack(ctx context.Context, galleryID, stackID uuid.UUID, _ []string) (gallery.Stack, error) {
+	c.changes = append(c.changes, "UntagStack")
+	return c.FetchStack(ctx, galleryID, stackID)
+}
+
+func (c *commandGalleryClient) RenameStack(ctx context.Context, galleryID, stackID uuid.UUID, _ string) (gallery.Stack, error) {
+	c.changes = append(c.changes, "RenameStack")
+	return c.FetchStack(ctx, galleryID, stackID)
+}
+
+func (c *commandGalleryClient) DeleteStack(context.Context, uuid.UUID, uuid.UUID) error {
+	c.changes = append(c.changes, "DeleteStack")
+	return fmt.Errorf("delete stack: %w", gallery.ErrLegalHold)
+}
+
+// commandBus records dispatched commands.
+type commandBus struct {
+	dispatched []string
+}
+
+func (bus *commandBus) Dispatch(_ context.Context, cmd command.Command, _ ...command.DispatchOption) error {
+	bus.dispatched = append(bus.dispatched, cmd.Name())
+	return nil
+}
+
+func (bus *commandBus) Subscribe(context.Context, ...string) (<-chan command.Context, <-chan error, error) {
+	return nil, nil, fmt.Errorf("not implemented")
+}
+
+func TestWithHealth_diskUsage(t *testing.T) {
+	disks := map[string]usage.Disk{
+		"foo-disk": {Usage: 200, Level: usage.LevelCritical, Thresholds: usage.Thresholds{Warning: 100, Critical: 150}},
+	}
+
+	srv := mediaserver.New(&commandBus{}, mediaserver.WithHealth(map[string]mediaserver.StatusReporter{
+		"usage": diskUsageReporter{disks},
+	}))
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(routes.Health.Method, routes.Health.Path, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("disk usage levels should not affect the status code; status is %d", rec.Code)
+	}
+
+	var resp struct {
+		Disks map[string]usage.Disk `json:"disks"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if !reflect.DeepEqual(resp.Disks, disks) {
+		t.Fatalf("health response should report disks %v; got %v", disks, resp.Disks)
+	}
+}
+
+type diskUsageReporter struct{ disks map[string]usage.Disk }
+
+func (r diskUsageReporter) Status() projector.Status {
+	return projector.Status{Running: true}
+}
+
+func (r diskUsageReporter) Disks() map[string]usage.Disk {
+	return r.disks
+}
+
+func TestServer_location(t *testing.T) {
+	srv, _ := newServer(t)
+
+	tests := []struct {
+		routeTest
+		want createdLinks
+	}{
+		{
+			routeTest: routeTest{route: routes.UploadDocument, body: multipartBody("document")},
+			want: createdLinks{
+				Self:    routes.URL(routes.ShowDocument, shelfID, documentID),
+				Content: routes.URL(routes.ShowDocumentContent, shelfID, documentID),
+				Shelf:   routes.URL(routes.ShowShelf, shelfID),
+			},
+		},
+		{
+			routeTest: routeTest{route: routes.UploadImage, body: multipartBody("image")},
+			want: createdLinks{
+				Self:    routes.URL(routes.ShowStack, galleryID, stackID),
+				Content: routes.URL(routes.ShowStackVideo, galleryID, stackID),
+				Gallery: routes.URL(routes.ShowGallery, galleryID),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		rec := serve(srv, tt.routeTest, defaultParams())
+		if loc := rec.Header().Get("Location"); loc != tt.want.Self {
+			t.Errorf("[%s %s] Location header should be %q; is %q", tt.route.Method, tt.route.Path, tt.want.Self, loc)
+		}
+
+		var body struct {
+			Links createdLinks `json:"links"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("[%s %s] decode response: %v", tt.route.Method, tt.route.Path, err)
+		}
+
+		if body.Links != tt.want {
+			t.Errorf("[%s %s] links should be %+v; are %+v", tt.route.Method, tt.route.Path, tt.want, body.Links)
+		}
+	}
+}
+
+type createdLinks struct {
+	Self    string `json:"self"`
+	Content string `json:"content"`
+	Shelf   string `json:"shelf"`
+	Gallery string `json:"gallery"`
+}
+
+func TestServer_signedURL(t *testing.T) {
+	srv, _ := newServer(t)
+
+	rec := serve(srv, routeTest{route: routes.SignDocumentURL}, defaultParams())
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+
+	var resp struct {
+		URL       string    `json:"url"`
+		ExpiresAt time.Time `json:"expiresAt"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if !strings.HasPrefix(resp.URL, "https://files.example.com/foo.txt?") {
+		t.Fatalf("URL should be signed by the disk; got %q", resp.URL)
+	}
+
+	if d := time.Until(resp.ExpiresAt); d <= 14*time.Minute || d > 15*time.Minute {
+		t.Fatalf("URL should expire in 15 minutes by default; expires in %v", d)
+	}
+
+	rec = serve(srv, routeTest{route: routes.SignDocumentURL, body: jsonBody(`{"expiry": 999999999}`)}, defaultParams())
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status should be %d for an invalid expiry; is %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestServer_moveStack(t *testing.T) {
+	srv, bus := newServer(t)
+
+	rec := serve(srv, routeTest{route: routes.MoveStack, body: jsonBody(`{"before": "` + stackID.String() + `"}`)}, defaultParams())
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusNoContent, rec.Code, rec.Body)
+	}
+	if len(bus.dispatched) != 1 || bus.dispatched[0] != gallery.MoveStackCommand {
+		t.Fatalf("%q command should have been dispatched; got %v", gallery.MoveStackCommand, bus.dispatched)
+	}
+
+	srv, bus = newServer(t)
+	rec = serve(srv, routeTest{route: routes.MoveStack, body: jsonBody(`{"before": "` + uuid.NewString() + `"}`)}, defaultParams())
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status should be %d for an unknown target stack; is %d", http.StatusNotFound, rec.Code)
+	}
+	if len(bus.dispatched) > 0 {
+		t.Fatalf("no commands should have been dispatched; got %v", bus.dispatched)
+	}
+}
+
+func TestServer_galleryCommandClient(t *testing.T) {
+	client := &commandGalleryClient{}
+	bus := &commandBus{}
+	srv := newServerWith(t, bus, func(d documentClient, g galleryClient) (mediaserver.DocumentClient, mediaserver.GalleryClient) {
+		client.galleryClient = g
+		return d, client
+	})
+
+	for _, tt := range []routeTest{
+		{route: routes.SortGallery, body: jsonBody(`{"sorting": []}`), status: http.StatusNoContent},
+		{route: routes.TagStack, body: jsonBody(`{"tags": ["foo"]}`), status: http.StatusCreated},
+		{route: routes.UntagStack, status: http.StatusCreated},
+		{route: routes.UpdateStack, body: jsonBody(`{"name": "bar"}`), status: http.StatusOK},
+		{route: routes.DeleteStack, status: http.StatusConflict},
+	} {
+		if rec := serve(srv, tt, defaultParams()); rec.Code != tt.status {
+			t.Fatalf("[%s %s] status should be %d; is %d (%s)", tt.route.Method, tt.route.Path, tt.status, rec.Code, rec.Body)
+		}
+	}
+
+	if want := []string{"SortGallery", "TagStack", "UntagStack", "RenameStack", "DeleteStack"}; !reflect.DeepEqual(client.changes, want) {
+		t.Fatalf("changes should be made through the client %v; got %v", want, client.changes)
+	}
+
+	if len(bus.dispatched) > 0 {
+		t.Fatalf("no commands should have been dispatched; got %v", bus.dispatched)
+	}
+}
+
+func TestServer_withoutCommandBus(t *testing.T) {
+	documents := &commandDocumentClient{}
+	galleries := &commandGalleryClient{}
+	srv := newServerWith(t, nil, func(d documentClient, g galleryClient) (mediaserver.DocumentClient, mediaserver.GalleryClient) {
+		documents.documentClient = d
+		galleries.galleryClient = g
+		return documents, galleries
+	})
+
+	for _, tt := range []routeTest{
+		{route: routes.TagDocument, body: jsonBody(`{"tags": ["foo"]}`), status: http.StatusOK},
+		{route: routes.UntagDocument, status: http.StatusOK},
+		{route: routes.UpdateDocument, body: jsonBody(`{"name": "bar"}`), status: http.StatusOK},
+		{route: routes.DeleteDocument, status: http.StatusNoContent},
+		{route: routes.SortGallery, body: jsonBody(`{"sorting": []}`), status: http.StatusNoContent},
+		{route: routes.RenameGallery, body: jsonBody(`{"name": "bar"}`), status: http.StatusNotImplemented},
+		{route: routes.SetStackFocalPoint, body: jsonBody(`{"x": 30, "y": 60}`), status: http.StatusNotImplemented},
+	} {
+		if rec := serve(srv, tt, defaultParams()); rec.Code != tt.status {
+			t.Fatalf("[%s %s] status should be %d; is %d (%s)", tt.route.Method, tt.route.Path, tt.status, rec.Code, rec.Body)
+		}
+	}
+
+	if want := []string{"TagDocument", "UntagDocument", "RenameDocument", "DeleteDocument"}; !reflect.DeepEqual(documents.changes, want) {
+		t.Fatalf("changes should be made through the document client %v; got %v", want, documents.changes)
+	}
+}
+
+func TestServer_lookupStackTag(t *testing.T) {
+	srv, _ := newServer(t)
+
+	rec := serve(srv, routeTest{route: routes.LookupGalleryStacksByTag, query: "tag=foo"}, defaultParams())
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+
+	var resp struct {
+		StackIDs []uuid.UUID `json:"stackIds"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.StackIDs == nil || len(resp.StackIDs) != 0 {
+		t.Fatalf("response should contain an empty list of stacks; got %v", resp.StackIDs)
+	}
+
+	rec = serve(srv, routeTest{route: routes.LookupGalleryStacksByTag}, defaultParams())
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status should be %d without a tag; is %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestServer_retagDocuments(t *testing.T) {
+	srv, bus := newServer(t)
+
+	rec := serve(srv, routeTest{route: routes.RetagDocuments, body: jsonBody(`{"documentIds": ["` + documentID.String() + `"], "add": ["foo"], "remove": ["bar"]}`)}, defaultParams())
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+	if len(bus.dispatched) != 1 || bus.dispatched[0] != document.RetagCommand {
+		t.Fatalf("a single %q command should have been dispatched; got %v", document.RetagCommand, bus.dispatched)
+	}
+
+	var resp struct {
+		Documents []document.Document `json:"documents"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Documents) != 1 || resp.Documents[0].ID != documentID {
+		t.Fatalf("response should contain document %q; got %v", documentID, resp.Documents)
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "no documents", body: `{"documentIds": [], "add": ["foo"]}`, status: http.StatusBadRequest},
+		{name: "no tags", body: `{"documentIds": ["` + documentID.String() + `"]}`, status: http.StatusBadRequest},
+		{name: "unknown document", body: `{"documentIds": ["` + documentID.String() + `", "` + uuid.NewString() + `"], "add": ["foo"]}`, status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, bus := newServer(t)
+
+			rec := serve(srv, routeTest{route: routes.RetagDocuments, body: jsonBody(tt.body)}, defaultParams())
+			if rec.Code != tt.status {
+				t.Fatalf("status should be %d; is %d (%s)", tt.status, rec.Code, rec.Body)
+			}
+			if len(bus.dispatched) > 0 {
+				t.Fatalf("no commands should have been dispatched; got %v", bus.dispatched)
+			}
+		})
+	}
+}
+
+func TestServer_copyDocument(t *testing.T) {
+	srv, bus := newServer(t)
+
+	rec := serve(srv, routeTest{route: routes.CopyDocument, body: jsonBody(`{"shelfId": "` + shelfID.String() + `", "path": "/copy.txt"}`)}, defaultParams())
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusAccepted, rec.Code, rec.Body)
+	}
+	if len(bus.dispatched) != 1 || bus.dispatched[0] != document.CopyCommand {
+		t.Fatalf("%q command should have been dispatched; got %v", document.CopyCommand, bus.dispatched)
+	}
+
+	var resp struct {
+		ID uuid.UUID `json:"id"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.ID == uuid.Nil || resp.ID == documentID {
+		t.Fatalf("copy should have a new UUID; has %q", resp.ID)
+	}
+	if want := routes.URL(routes.ShowDocument, shelfID, resp.ID); rec.Header().Get("Location") != want {
+		t.Fatalf("Location should be %q; is %q", want, rec.Header().Get("Location"))
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{name: "no target shelf", body: `{"path": "/copy.txt"}`, status: http.StatusBadRequest},
+		{name: "unknown target shelf", body: `{"shelfId": "` + uuid.NewString() + `"}`, status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, bus := newServer(t)
+
+			rec := serve(srv, routeTest{route: routes.CopyDocument, body: jsonBody(tt.body)}, defaultParams())
+			if rec.Code != tt.status {
+				t.Fatalf("status should be %d; is %d (%s)", tt.status, rec.Code, rec.Body)
+			}
+			if len(bus.dispatched) > 0 {
+				t.Fatalf("no commands should have been dispatched; got %v", bus.dispatched)
+			}
+		})
+	}
+}
+
+func TestServer_replaceDocumentDelta(t *testing.T) {
+	srv, _ := newServer(t)
+
+	rec := serve(srv, routeTest{route: routes.ReplaceDocumentDelta, body: deltaBody("bar", "foobar")}, defaultParams())
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("status should be %d for a delta against another file; is %d (%s)", http.StatusPreconditionFailed, rec.Code, rec.Body)
+	}
+
+	rec = serve(srv, routeTest{route: routes.ReplaceDocumentDelta, body: jsonBody("{}")}, defaultParams())
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status should be %d for a malformed delta; is %d (%s)", http.StatusBadRequest, rec.Code, rec.Body)
+	}
+}
+
+func TestServer_listDocuments(t *testing.T) {
+	shelf := document.JSONShelf{ID: shelfID, Name: "foo"}
+	for _, name := range []string{"Report", "Invoice 1", "Invoice 2", "Invoice 3"} {
+		shelf.Documents = append(shelf.Documents, document.Document{
+			Document: media.Document{File: media.File{Name: name}},
+			ID:       uuid.New(),
+		})
+	}
+
+	srv := mediaserver.New(&commandBus{}, mediaserver.WithDocuments(documentClient{shelf}, ""))
+
+	var resp struct {
+		document.DocumentPage
+		Links struct {
+			Next string `json:"next"`
+		} `json:"links"`
+	}
+
+	get := func(url string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		return rec
+	}
+
+	rec := get(routes.URL(routes.ShowShelf, shelfID) + "?q=invoice&limit=2")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if resp.Total != 3 || len(resp.Documents) != 2 || resp.Documents[0].Name != "Invoice 1" {
+		t.Fatalf("response should contain the first 2 of 3 invoices; got %v of %d", resp.Documents, resp.Total)
+	}
+
+	if resp.Links.Next == "" {
+		t.Fatalf("response should link to the next page")
+	}
+
+	next := resp.Links.Next
+	resp.Links.Next = ""
+	if err := json.NewDecoder(get(next).Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if len(resp.Documents) != 1 || resp.Documents[0].Name != "Invoice 3" || resp.Links.Next != "" {
+		t.Fatalf("next page should contain the last invoice and no next link; got %v (next %q)", resp.Documents, resp.Links.Next)
+	}
+
+	for _, query := range []string{"limit=foo", "offset=-1"} {
+		if rec := get(routes.URL(routes.ShowShelf, shelfID) + "?" + query); rec.Code != http.StatusBadRequest {
+			t.Errorf("status should be %d for %q; is %d", http.StatusBadRequest, query, rec.Code)
+		}
+	}
+
+	if rec := get(routes.URL(routes.ShowShelf, uuid.New()) + "?limit=1"); rec.Code != http.StatusNotFound {
+		t.Fatalf("status should be %d for an unknown shelf; is %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestServer_listStacks(t *testing.T) {
+	g := gallery.JSONGallery{ID: galleryID, Name: "foo"}
+	for i, name := range []string{"Beach", "Hero 1", "Hero 2", "Hero 3"} {
+		img := media.Image{File: media.File{Name: name}}
+		if i > 0 {
+			img.Tags = []string{"hero", "summer"}
+		}
+		g.Stacks = append(g.Stacks, gallery.Stack{
+			ID:     uuid.New(),
+			Images: []gallery.Image{{Image: img, Original: true}},
+		})
+	}
+
+	srv := mediaserver.New(&commandBus{}, mediaserver.WithGalleries(galleryClient{g}))
+
+	var resp struct {
+		gallery.StackPage
+		Links struct {
+			Next string `json:"next"`
+		} `json:"links"`
+	}
+
+	get := func(url string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		return rec
+	}
+
+	rec := get(routes.URL(routes.ShowGallery, galleryID) + "?tags=hero,summer&limit=2")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if resp.Total != 3 || len(resp.Stacks) != 2 || resp.Stacks[0].Original().Name != "Hero 1" {
+		t.Fatalf("response should contain the first 2 of 3 tagged stacks; got %v of %d", resp.Stacks, resp.Total)
+	}
+
+	next := resp.Links.Next
+	resp.Links.Next = ""
+	if err := json.NewDecoder(get(next).Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if len(resp.Stacks) != 1 || resp.Stacks[0].Original().Name != "Hero 3" || resp.Links.Next != "" {
+		t.Fatalf("next page should contain the last tagged stack and no next link; got %v (next %q)", resp.Stacks, resp.Links.Next)
+	}
+
+	for _, query := range []string{"limit=foo", "sort=size"} {
+		if rec := get(routes.URL(routes.ShowGallery, galleryID) + "?" + query); rec.Code != http.StatusBadRequest {
+			t.Errorf("status should be %d for %q; is %d", http.StatusBadRequest, query, rec.Code)
+		}
+	}
+
+	if rec := get(routes.URL(routes.ShowGallery, uuid.New()) + "?limit=1"); rec.Code != http.StatusNotFound {
+		t.Fatalf("status should be %d for an unknown gallery; is %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestServer_searchDocuments(t *testing.T) {
+	shelf := document.JSONShelf{ID: shelfID, Name: "foo"}
+	for _, name := range []string{"Report", "Invoice 1", "Invoice 2", "Invoice 3"} {
+		shelf.Documents = append(shelf.Documents, document.Document{
+			Document: media.Document{File: media.File{Name: name}},
+			ID:       uuid.New(),
+		})
+	}
+
+	srv := mediaserver.New(&commandBus{}, mediaserver.WithDocuments(documentClient{shelf}, ""))
+
+	get := func(url string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		return rec
+	}
+
+	rec := get(routes.URL(routes.SearchDocuments) + "?q=invoice&limit=2")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+
+	var resp struct {
+		document.SearchResults
+		Links struct {
+			Next string `json:"next"`
+		} `json:"links"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if resp.Total != 3 || len(resp.Documents) != 2 || resp.Documents[0].Name != "Invoice 1" || resp.Documents[0].ShelfID != shelfID {
+		t.Fatalf("response should contain the first 2 of 3 invoices; got %v of %d", resp.Documents, resp.Total)
+	}
+
+	if resp.Links.Next == "" {
+		t.Fatalf("response should link to the next page")
+	}
+
+	if rec := get(routes.URL(routes.SearchDocuments) + "?path=("); rec.Code != http.StatusBadRequest {
+		t.Fatalf("status should be %d for an invalid path expression; is %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestServer_trash(t *testing.T) {
+	srv, bus := newServer(t)
+
+	rec := serve(srv, routeTest{route: routes.DeleteDocument}, defaultParams())
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusNoContent, rec.Code, rec.Body)
+	}
+	if len(bus.dispatched) != 1 || bus.dispatched[0] != document.TrashCommand {
+		t.Fatalf("%q command should have been dispatched; got %v", document.TrashCommand, bus.dispatched)
+	}
+
+	srv, bus = newServer(t)
+	rec = serve(srv, routeTest{route: routes.RestoreDocument}, defaultParams())
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status should be %d for a Document that is not in the trash; is %d", http.StatusNotFound, rec.Code)
+	}
+	if len(bus.dispatched) > 0 {
+		t.Fatalf("no commands should have been dispatched; got %v", bus.dispatched)
+	}
+}
+
+func TestServer_galleryTrash(t *testing.T) {
+	srv, bus := newServer(t)
+
+	rec := serve(srv, routeTest{route: routes.DeleteStack}, defaultParams())
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusNoContent, rec.Code, rec.Body)
+	}
+	if len(bus.dispatched) != 1 || bus.dispatched[0] != gallery.TrashStackCommand {
+		t.Fatalf("%q command should have been dispatched; got %v", gallery.TrashStackCommand, bus.dispatched)
+	}
+
+	srv, _ = newServer(t)
+	rec = serve(srv, routeTest{route: routes.ShowGalleryTrash}, defaultParams())
+	var trashed []gallery.TrashedStack
+	if err := json.NewDecoder(rec.Body).Decode(&trashed); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(trashed) != 1 || trashed[0].ID != trashedStackID {
+		t.Fatalf("response should contain the trashed Stack; got %v", trashed)
+	}
+
+	srv, bus = newServer(t)
+	rec = serve(srv, routeTest{route: routes.RestoreStack}, defaultParams())
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status should be %d for a Stack that is not in the trash; is %d", http.StatusNotFound, rec.Code)
+	}
+	if len(bus.dispatched) > 0 {
+		t.Fatalf("no commands should have been dispatched; got %v", bus.dispatched)
+	}
+}
+
+func TestServer_legalHold(t *testing.T) {
+	hold := &media.LegalHold{Reason: "case-1", Since: time.Now()}
+
+	shelf := document.JSONShelf{
+		ID:        shelfID,
+		Name:      "foo",
+		Documents: []document.Document{{ID: documentID, LegalHold: hold}},
+		Trashed: []document.TrashedDocument{{
+			Document: document.Document{ID: trashedID},
+		}},
+	}
+
+	g := gallery.JSONGallery{
+		ID:        galleryID,
+		Name:      "foo",
+		Stacks:    gallery.Stacks{{ID: stackID}},
+		LegalHold: hold,
+	}
+
+	newServer := func() (*mediaserver.Server, *commandBus) {
+		bus := &commandBus{}
+		return mediaserver.New(
+			bus,
+			mediaserver.WithDocuments(documentClient{shelf}, ""),
+			mediaserver.WithGalleries(galleryClient{g}),
+		), bus
+	}
+
+	for _, tt := range []routeTest{
+		{route: routes.DeleteDocument},
+		{route: routes.ReplaceDocument, body: multipartBody("document")},
+		{route: routes.ReplaceDocumentDelta, body: deltaBody("foo", "foobar")},
+		{route: routes.DeleteStack},
+		{route: routes.ReplaceImage, body: multipartBody("image")},
+		{route: routes.RevertStack, body: jsonBody(`{"version": 0}`)},
+	} {
+		srv, bus := newServer()
+		rec := serve(srv, tt, defaultParams())
+		if rec.Code != http.StatusConflict {
+			t.Fatalf("%s %s: status should be %d for a held resource; is %d (%s)", tt.route.Method, tt.route.Path, http.StatusConflict, rec.Code, rec.Body)
+		}
+		if len(bus.dispatched) > 0 {
+			t.Fatalf("no commands should have been dispatched; got %v", bus.dispatched)
+		}
+	}
+
+	srv, bus := newServer()
+	rec := serve(srv, routeTest{route: routes.SetDocumentLegalHold, body: jsonBody(`{}`)}, pathParams{
+		"ShelfID":    shelfID.String(),
+		"DocumentID": trashedID.String(),
+	})
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status should be %d for a trashed Document; is %d (%s)", http.StatusNoContent, rec.Code, rec.Body)
+	}
+	if len(bus.dispatched) != 1 || bus.dispatched[0] != document.SetLegalHoldCommand {
+		t.Fatalf("%q command should have been dispatched; got %v", document.SetLegalHoldCommand, bus.dispatched)
+	}
+}
+
+func TestServer_revertStack(t *testing.T) {
+	srv, bus := newServer(t)
+
+	rec := serve(srv, routeTest{route: routes.RevertStack, body: jsonBody(`{"version": 3}`)}, defaultParams())
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status should be %d for an unknown version; is %d (%s)", http.StatusNotFound, rec.Code, rec.Body)
+	}
+
+	rec = serve(srv, routeTest{route: routes.RevertStack, body: jsonBody(`{}`)}, defaultParams())
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status should be %d without a version; is %d (%s)", http.StatusBadRequest, rec.Code, rec.Body)
+	}
+
+	if len(bus.dispatched) > 0 {
+		t.Fatalf("no commands should have been dispatched; got %v", bus.dispatched)
+	}
+
+	rec = serve(srv, routeTest{route: routes.SetGalleryVersionLimit, body: jsonBody(`{"limit": -1}`)}, defaultParams())
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status should be %d for a negative limit; is %d (%s)", http.StatusBadRequest, rec.Code, rec.Body)
+	}
+
+	srv, bus = newServer(t)
+	rec = serve(srv, routeTest{route: routes.RevertStack, body: jsonBody(`{"version": 0}`)}, defaultParams())
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+	if len(bus.dispatched) != 1 || bus.dispatched[0] != gallery.RevertStackCommand {
+		t.Fatalf("%q command should have been dispatched; got %v", gallery.RevertStackCommand, bus.dispatched)
+	}
+}
+
+func TestServer_listQueries(t *testing.T) {
+	srv, _ := newServer(t)
+
+	tests := []struct {
+		route routes.Route
+		query string
+		total int
+	}{
+		{route: routes.ShowGalleryTrash, query: "", total: 1},
+		{route: routes.ShowGalleryTrash, query: "name[contains]=FO&sort=-trashedAt", total: 1},
+		{route: routes.ShowGalleryTrash, query: "name=bar", total: 0},
+		{route: routes.ShowGalleryTrash, query: "tag=bar", total: 0},
+		{route: routes.ShowStackVersions, query: "version[lte]=0", total: 1},
+		{route: routes.ShowStackVersions, query: "version[gte]=1", total: 0},
+	}
+
+	for _, tt := range tests {
+		path := tt.route.Path
+		for name, val := range defaultParams() {
+			path = strings.ReplaceAll(path, "{"+name+"}", val)
+		}
+
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(tt.route.Method, path+"?"+tt.query, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("[%s?%s] status should be %d; is %d (%s)", tt.route.Path, tt.query, http.StatusOK, rec.Code, rec.Body)
+		}
+
+		var items []json.RawMessage
+		if err := json.NewDecoder(rec.Body).Decode(&items); err != nil {
+			t.Fatalf("[%s?%s] decode response: %v", tt.route.Path, tt.query, err)
+		}
+
+		if len(items) != tt.total || rec.Header().Get("X-Total-Count") != fmt.Sprint(tt.total) {
+			t.Fatalf("[%s?%s] response should contain %d items; contains %d (X-Total-Count: %q)", tt.route.Path, tt.query, tt.total, len(items), rec.Header().Get("X-Total-Count"))
+		}
+	}
+
+	for _, query := range []string{"sort=size", "name[gt]=foo", "limit=foo"} {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, routes.URL(routes.ShowGalleryTrash, galleryID)+"?"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("status should be %d for %q; is %d", http.StatusBadRequest, query, rec.Code)
+		}
+	}
+}
+
+func TestServer_create(t *testing.T) {
+	tests := []struct {
+		route   routes.Route
+		command string
+		parent  func(uuid.UUID) string
+	}{
+		{
+			route:   routes.CreateShelf,
+			command: document.CreateShelfCommand,
+			parent:  func(id uuid.UUID) string { return routes.URL(routes.ShowShelf, id) },
+		},
+		{
+			route:   routes.CreateGallery,
+			command: gallery.CreateCommand,
+			parent:  func(id uuid.UUID) string { return routes.URL(routes.ShowGallery, id) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.route.Path, func(t *testing.T) {
+			srv, bus := newServer(t)
+
+			rec := serve(srv, routeTest{route: tt.route, body: jsonBody(`{"name": " bar "}`)}, nil)
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("status should be %d; is %d (%s)", http.StatusCreated, rec.Code, rec.Body)
+			}
+
+			var resp struct {
+				ID   uuid.UUID `json:"id"`
+				Name string    `json:"name"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.ID == uuid.Nil || resp.Name != "bar" {
+				t.Fatalf("response should contain the UUID and trimmed name %q; got %v", "bar", resp)
+			}
+			if loc := rec.Header().Get("Location"); loc != tt.parent(resp.ID) {
+				t.Fatalf("Location should be %q; is %q", tt.parent(resp.ID), loc)
+			}
+			if !reflect.DeepEqual(bus.dispatched, []string{tt.command}) {
+				t.Fatalf("%q command should have been dispatched; got %v", tt.command, bus.dispatched)
+			}
+
+			for body, status := range map[string]int{
+				`{"name": "foo"}`: http.StatusConflict,
+				`{"name": " "}`:   http.StatusBadRequest,
+				`{`:               http.StatusBadRequest,
+			} {
+				bus.dispatched = nil
+				rec := serve(srv, routeTest{route: tt.route, body: jsonBody(body)}, nil)
+				if rec.Code != status {
+					t.Fatalf("status for body %s should be %d; is %d (%s)", body, status, rec.Code, rec.Body)
+				}
+				if len(bus.dispatched) > 0 {
+					t.Fatalf("no commands should have been dispatched for body %s; got %v", body, bus.dispatched)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_list(t *testing.T) {
+	tests := []struct {
+		route routes.Route
+		field string
+		id    uuid.UUID
+	}{
+		{route: routes.ListShelfs, field: "shelfs", id: shelfID},
+		{route: routes.ListGalleries, field: "galleries", id: galleryID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.route.Path, func(t *testing.T) {
+			srv, _ := newServer(t)
+
+			get := func(url string) *httptest.ResponseRecorder {
+				rec := httptest.NewRecorder()
+				srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+				return rec
+			}
+
+			rec := get(routes.URL(tt.route))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+			}
+
+			var resp map[string]json.RawMessage
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			var refs []struct {
+				ID   uuid.UUID `json:"id"`
+				Name string    `json:"name"`
+			}
+			if err := json.Unmarshal(resp[tt.field], &refs); err != nil {
+				t.Fatalf("decode %q: %v", tt.field, err)
+			}
+			if len(refs) != 1 || refs[0].ID != tt.id || refs[0].Name != "foo" {
+				t.Fatalf("response should contain %q with UUID %s; got %s", "foo", tt.id, resp[tt.field])
+			}
+			if total := string(resp["total"]); total != "1" {
+				t.Fatalf("total should be 1; is %s", total)
+			}
+
+			rec = get(routes.URL(tt.route) + "?offset=1")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+			}
+			resp = nil
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if page := string(resp[tt.field]); page != "[]" {
+				t.Fatalf("page after the last item should be empty; is %s", page)
+			}
+
+			for _, query := range []string{"limit=foo", "offset=-1", "sort=name"} {
+				if rec := get(routes.URL(tt.route) + "?" + query); rec.Code != http.StatusBadRequest {
+					t.Errorf("status should be %d for %q; is %d", http.StatusBadRequest, query, rec.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_updateStack(t *testing.T) {
+	tests := []struct {
+		body string
+		want []string
+	}{
+		{body: `{}`},
+		{body: `{"name": "bar"}`, want: []string{gallery.RenameStackCommand}},
+		{body: `{"alt": "A red car"}`, want: []string{gallery.UpdateStackMetadataCommand}},
+		{body: `{"caption": ""}`, want: []string{gallery.UpdateStackMetadataCommand}},
+		{body: `{"name": "bar", "alt": "A red car", "caption": "Foo"}`, want: []string{gallery.RenameStackCommand, gallery.UpdateStackMetadataCommand}},
+		{body: `{"customMetadata": {"license": "CC-BY"}}`, want: []string{gallery.SetCustomMetadataCommand}},
+		{body: `{"customMetadata": {"license": null}}`, want: []string{gallery.DeleteCustomMetadataCommand}},
+		{body: `{"customMetadata": {"license": "CC-BY", "source": null}}`, want: []string{gallery.SetCustomMetadataCommand, gallery.DeleteCustomMetadataCommand}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			srv, bus := newServer(t)
+
+			rec := serve(srv, routeTest{route: routes.UpdateStack, body: jsonBody(tt.body)}, defaultParams())
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+			}
+			if !reflect.DeepEqual(bus.dispatched, tt.want) {
+				t.Fatalf("dispatched commands should be %v; got %v", tt.want, bus.dispatched)
+			}
+		})
+	}
+}
+
+func TestServer_setFocalPoint(t *testing.T) {
+	tests := []struct {
+		body   string
+		status int
+		want   []string
+	}{
+		{body: `{"x": 30, "y": 60}`, status: http.StatusOK, want: []string{gallery.SetFocalPointCommand}},
+		{body: `{"x": 0, "y": 100}`, status: http.StatusOK, want: []string{gallery.SetFocalPointCommand}},
+		{body: `{"x": 30}`, status: http.StatusBadRequest},
+		{body: `{"x": -1, "y": 50}`, status: http.StatusBadRequest},
+		{body: `{"x": 50, "y": 100.5}`, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			srv, bus := newServer(t)
+
+			rec := serve(srv, routeTest{route: routes.SetStackFocalPoint, body: jsonBody(tt.body)}, defaultParams())
+			if rec.Code != tt.status {
+				t.Fatalf("status should be %d; is %d (%s)", tt.status, rec.Code, rec.Body)
+			}
+			if !reflect.DeepEqual(bus.dispatched, tt.want) {
+				t.Fatalf("dispatched commands should be %v; got %v", tt.want, bus.dispatched)
+			}
+		})
+	}
+}
+
+func TestServer_cropStack(t *testing.T) {
+	tests := []struct {
+		body   string
+		status int
+		want   []string
+	}{
+		{body: `{"x": 10, "y": 10, "width": 50, "height": 80}`, status: http.StatusOK, want: []string{gallery.CropStackCommand}},
+		{body: `{"x": 0, "y": 0, "width": 100, "height": 100}`, status: http.StatusOK, want: []string{gallery.CropStackCommand}},
+		{body: `{"x": 10, "y": 10, "width": 50}`, status: http.StatusBadRequest},
+		{body: `{"x": 60, "y": 0, "width": 50, "height": 50}`, status: http.StatusBadRequest},
+		{body: `{"x": 0, "y": 0, "width": 0, "height": 50}`, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			srv, bus := newServer(t)
+
+			rec := serve(srv, routeTest{route: routes.CropStack, body: jsonBody(tt.body)}, defaultParams())
+			if rec.Code != tt.status {
+				t.Fatalf("status should be %d; is %d (%s)", tt.status, rec.Code, rec.Body)
+			}
+			if !reflect.DeepEqual(bus.dispatched, tt.want) {
+				t.Fatalf("dispatched commands should be %v; got %v", tt.want, bus.dispatched)
+			}
+		})
+	}
+}
+
+func TestServer_transformStack(t *testing.T) {
+	tests := []struct {
+		route  routes.Route
+		body   string
+		status int
+		want   []string
+	}{
+		{route: routes.RotateStack, body: `{"degrees": 90}`, status: http.StatusOK, want: []string{gallery.RotateStackCommand}},
+		{route: routes.RotateStack, body: `{"degrees": -270}`, status: http.StatusOK, want: []string{gallery.RotateStackCommand}},
+		{route: routes.RotateStack, body: `{}`, status: http.StatusBadRequest},
+		{route: routes.RotateStack, body: `{"degrees": 45}`, status: http.StatusBadRequest},
+		{route: routes.FlipStack, body: `{"direction": "vertical"}`, status: http.StatusOK, want: []string{gallery.FlipStackCommand}},
+		{route: routes.FlipStack, body: `{"direction": "diagonal"}`, status: http.StatusBadRequest},
+		{route: routes.FlipStack, body: `{}`, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.route.Path+" "+tt.body, func(t *testing.T) {
+			srv, bus := newServer(t)
+
+			rec := serve(srv, routeTest{route: tt.route, body: jsonBody(tt.body)}, defaultParams())
+			if rec.Code != tt.status {
+				t.Fatalf("status should be %d; is %d (%s)", tt.status, rec.Code, rec.Body)
+			}
+			if !reflect.DeepEqual(bus.dispatched, tt.want) {
+				t.Fatalf("dispatched commands should be %v; got %v", tt.want, bus.dispatched)
+			}
+		})
+	}
+}
+
+func TestServer_reprocessStack(t *testing.T) {
+	srv, bus := newServer(t)
+
+	rec := serve(srv, routeTest{route: routes.ReprocessStack}, defaultParams())
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusAccepted, rec.Code, rec.Body)
+	}
+
+	if want := []string{gallery.ReprocessStackCommand}; !reflect.DeepEqual(bus.dispatched, want) {
+		t.Fatalf("dispatched commands should be %v; got %v", want, bus.dispatched)
+	}
+
+	params := defaultParams()
+	params["StackID"] = uuid.New().String()
+	if rec := serve(srv, routeTest{route: routes.ReprocessStack}, params); rec.Code != http.StatusNotFound {
+		t.Fatalf("status should be %d for an unknown stack; is %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestServer_updateDocument_metadata(t *testing.T) {
+	tests := []struct {
+		body string
+		want []string
+	}{
+		{body: `{"name": "bar"}`, want: []string{document.RenameCommand}},
+		{body: `{"name": "bar", "metadata": {"license": "CC-BY"}}`, want: []string{document.RenameCommand, document.SetMetadataCommand}},
+		{body: `{"name": "bar", "metadata": {"license": null}}`, want: []string{document.RenameCommand, document.DeleteMetadataCommand}},
+		{body: `{"name": "bar", "metadata": {"license": "CC-BY", "source": null}}`, want: []string{document.RenameCommand, document.SetMetadataCommand, document.DeleteMetadataCommand}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			srv, bus := newServer(t)
+
+			rec := serve(srv, routeTest{route: routes.UpdateDocument, body: jsonBody(tt.body)}, defaultParams())
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+			}
+			if !reflect.DeepEqual(bus.dispatched, tt.want) {
+				t.Fatalf("dispatched commands should be %v; got %v", tt.want, bus.dispatched)
+			}
+		})
+	}
+}
+
+func TestServer_update_emptyMetadataKey(t *testing.T) {
+	tests := []struct {
+		route routes.Route
+		body  string
+	}{
+		{route: routes.UpdateDocument, body: `{"name": "bar", "metadata": {" ": "foo"}}`},
+		{route: routes.UpdateStack, body: `{"customMetadata": {"": null}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.route.Path, func(t *testing.T) {
+			srv, bus := newServer(t)
+
+			rec := serve(srv, routeTest{route: tt.route, body: jsonBody(tt.body)}, defaultParams())
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status should be %d; is %d (%s)", http.StatusBadRequest, rec.Code, rec.Body)
+			}
+			if len(bus.dispatched) > 0 {
+				t.Fatalf("no commands should have been dispatched; got %v", bus.dispatched)
+			}
+		})
+	}
+}
+
+func TestServer_delete(t *testing.T) {
+	tests := []struct {
+		route   routes.Route
+		command string
+		param   string
+	}{
+		{route: routes.DeleteShelf, command: document.DeleteShelfCommand, param: "ShelfID"},
+		{route: routes.DeleteGallery, command: gallery.DeleteCommand, param: "GalleryID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.route.Path, func(t *testing.T) {
+			srv, bus := newServer(t)
+
+			rec := serve(srv, routeTest{route: tt.route}, defaultParams())
+			if rec.Code != http.StatusNoContent {
+				t.Fatalf("status should be %d; is %d (%s)", http.StatusNoContent, rec.Code, rec.Body)
+			}
+			if !reflect.DeepEqual(bus.dispatched, []string{tt.command}) {
+				t.Fatalf("%q command should have been dispatched; got %v", tt.command, bus.dispatched)
+			}
+
+			bus.dispatched = nil
+			rec = serve(srv, routeTest{route: tt.route}, pathParams{tt.param: uuid.NewString()})
+			if rec.Code != http.StatusNotFound {
+				t.Fatalf("status should be %d for an unknown ID; is %d (%s)", http.StatusNotFound, rec.Code, rec.Body)
+			}
+			if len(bus.dispatched) > 0 {
+				t.Fatalf("no commands should have been dispatched; got %v", bus.dispatched)
+			}
+		})
+	}
+}
+
+func TestServer_rename(t *testing.T) {
+	tests := []struct {
+		route   routes.Route
+		command string
+		param   string
+	}{
+		{route: routes.RenameShelf, command: document.RenameShelfCommand, param: "ShelfID"},
+		{route: routes.RenameGallery, command: gallery.RenameCommand, param: "GalleryID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.route.Path, func(t *testing.T) {
+			srv, bus := newServer(t)
+
+			rec := serve(srv, routeTest{route: tt.route, body: jsonBody(`{"name": " bar "}`)}, defaultParams())
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+			}
+			var resp struct {
+				Name string `json:"name"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.Name != "bar" {
+				t.Fatalf("name should be %q; is %q", "bar", resp.Name)
+			}
+			if !reflect.DeepEqual(bus.dispatched, []string{tt.command}) {
+				t.Fatalf("%q command should have been dispatched; got %v", tt.command, bus.dispatched)
+			}
+
+			bus.dispatched = nil
+			rec = serve(srv, routeTest{route: tt.route, body: jsonBody(`{"name": "foo"}`)}, defaultParams())
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status should be %d for the current name; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+			}
+
+			bus.dispatched = nil
+			rec = serve(srv, routeTest{route: tt.route, body: jsonBody(`{"name": "  "}`)}, defaultParams())
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status should be %d for an empty name; is %d (%s)", http.StatusBadRequest, rec.Code, rec.Body)
+			}
+
+			rec = serve(srv, routeTest{route: tt.route, body: jsonBody(`{"name": "bar"}`)}, pathParams{tt.param: uuid.NewString()})
+			if rec.Code != http.StatusNotFound {
+				t.Fatalf("status should be %d for an unknown ID; is %d (%s)", http.StatusNotFound, rec.Code, rec.Body)
+			}
+			if len(bus.dispatched) > 0 {
+				t.Fatalf("no commands should have been dispatched; got %v", bus.dispatched)
+			}
+		})
+	}
+}
+
+func TestServer_restoreDocument_duplicateUniqueName(t *testing.T) {
+	shelf := document.JSONShelf{
+		ID:   shelfID,
+		Name: "foo",
+		Documents: []document.Document{{
+			ID:         documentID,
+			UniqueName: "foo",
+		}},
+		Trashed: []document.TrashedDocument{{
+			Document: document.Document{ID: trashedID, UniqueName: "foo"},
+		}},
+	}
+
+	bus := &commandBus{}
+	srv := mediaserver.New(bus, mediaserver.WithDocuments(documentClient{shelf}, ""))
+
+	rec := serve(srv, routeTest{route: routes.RestoreDocument}, pathParams{
+		"ShelfID":    shelfID.String(),
+		"DocumentID": trashedID.String(),
+	})
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusConflict, rec.Code, rec.Body)
+	}
+	if len(bus.dispatched) > 0 {
+		t.Fatalf("no commands should have been dispatched; got %v", bus.dispatched)
+	}
+}
+
+func TestServer_protectedOriginals(t *testing.T) {
+	ctx := context.Background()
+
+	signer := media.NewHMACSigner([]byte("secret"), "https://files.example.com")
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.WithURLSigner(media.MemoryDisk(), signer)))
+	disk, _ := storage.Disk("foo-disk")
+	disk.Put(ctx, "/original.jpg", []byte("original"))
+	disk.Put(ctx, "/small.jpg", []byte("small"))
+
+	g := gallery.JSONGallery{
+		ID:   galleryID,
+		Name: "foo",
+		Stacks: gallery.Stacks{{
+			ID: stackID,
+			Images: []gallery.Image{
+				{Image: media.Image{File: media.NewFile("original", "foo-disk", "/original.jpg", 8), Width: 4000, Height: 3000}, Original: true},
+				{Image: media.Image{File: media.NewFile("small", "foo-disk", "/small.jpg", 5), Width: 800, Height: 600}, Size: "small"},
+			},
+		}},
+		OriginalsProtected: true,
+	}
+
+	authorizer := routes.AuthorizerFunc(func(r *http.Request, route routes.Route) error {
+		if route != routes.ShowStackOriginal {
+			return fmt.Errorf("unexpected route %s %s", route.Method, route.Path)
+		}
+		if r.Header.Get("X-Role") != "editor" {
+			return fmt.Errorf("download original: %w", routes.ErrForbidden)
+		}
+		return nil
+	})
+
+	srv := mediaserver.New(
+		&commandBus{},
+		mediaserver.WithStorage(storage),
+		mediaserver.WithGalleries(galleryClient{g}, routes.Authorize(authorizer)),
+	)
+
+	request := func(method, path, role string, body io.Reader) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, body)
+		req.Header.Set("X-Role", role)
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := request("GET", routes.URL(routes.ShowStackContent, galleryID, stackID), "", nil); rec.Body.String() != "small" {
+		t.Fatalf("content route should serve the processed image; got %q (%d)", rec.Body, rec.Code)
+	}
+
+	if rec := request("GET", routes.URL(routes.ShowStackOriginal, galleryID, stackID), "", nil); rec.Code != http.StatusForbidden {
+		t.Fatalf("original route should respond with %d for public requests; got %d", http.StatusForbidden, rec.Code)
+	}
+
+	if rec := request("GET", routes.URL(routes.ShowStackOriginal, galleryID, stackID), "editor", nil); rec.Body.String() != "original" {
+		t.Fatalf("original route should serve the original to authorized requests; got %q (%d)", rec.Body, rec.Code)
+	}
+
+	signURL := func(body, role string) (*httptest.ResponseRecorder, string) {
+		rec := request("POST", routes.URL(routes.SignStackURL, galleryID, stackID), role, strings.NewReader(body))
+		var resp struct {
+			URL string `json:"url"`
+		}
+		json.NewDecoder(bytes.NewReader(rec.Body.Bytes())).Decode(&resp)
+		return rec, resp.URL
+	}
+
+	if _, url := signURL(`{}`, ""); !strings.HasPrefix(url, "https://files.example.com/small.jpg?") {
+		t.Fatalf("signed URL should point to the processed image; got %q", url)
+	}
+
+	if rec, _ := signURL(`{"original": true}`, ""); rec.Code != http.StatusForbidden {
+		t.Fatalf("signing the original should respond with %d for public requests; got %d", http.StatusForbidden, rec.Code)
+	}
+
+	if _, url := signURL(`{"original": true}`, "editor"); !strings.HasPrefix(url, "https://files.example.com/original.jpg?") {
+		t.Fatalf("signed URL should point to the original for authorized requests; got %q", url)
+	}
+
+	g.OriginalsProtected = false
+	srv = mediaserver.New(&commandBus{}, mediaserver.WithStorage(storage), mediaserver.WithGalleries(galleryClient{g}))
+
+	if rec := request("GET", routes.URL(routes.ShowStackOriginal, galleryID, stackID), "", nil); rec.Body.String() != "original" {
+		t.Fatalf("original route should serve the original of unprotected galleries; got %q (%d)", rec.Body, rec.Code)
+	}
+}
@@ -0,0 +1,845 @@
+package mediaserver
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/modernice/nice-cms/internal/api"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/media/mediaserver/routes"
+	"github.com/modernice/nice-cms/openapi"
+	"github.com/modernice/nice-cms/schema"
+)
+
+// WithOpenAPI returns an Option that adds the OpenAPI route to the media
+// server. The route serves the OpenAPI document of the media server (see
+// Server.OpenAPI), so that client SDKs and admin UIs can be generated from it.
+// The extra documents are merged into the served document, so that
+// applications can describe the routes of their own APIs alongside the media
+// routes:
+//
+//	mediaserver.WithOpenAPI(openapi.Info{Title: "CMS", Version: "1.0.0"}, []*openapi.Document{pagesAPI})
+func WithOpenAPI(info openapi.Info, extra []*openapi.Document, opts ...routes.Option) Option {
+	return func(s *Server) {
+		r := routes.New(opts...)
+		r.Install(s.router, routes.OpenAPI, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			doc := s.OpenAPI(info)
+			for _, d := range extra {
+				doc.Merge(d)
+			}
+			api.JSON(w, req, http.StatusOK, doc)
+		}))
+	}
+}
+
+// OpenAPI returns the OpenAPI document of the routes that are installed in the
+// media server. Routes are described at the paths they are installed at, so
+// the document honors route prefixes, and disabled routes are not described.
+func (s *Server) OpenAPI(info openapi.Info) *openapi.Document {
+	doc := openapi.New(info)
+	ops := operations()
+
+	chi.Walk(s.router, func(method, path string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		route, ok := installedRoute(ops, method, path)
+		if !ok {
+			return nil
+		}
+
+		op := ops[route]
+		for _, tag := range op.Tags {
+			doc.AddTag(openapi.Tag{Name: tag, Description: openapiTags[tag]})
+		}
+		doc.Add(method, path, op)
+
+		return nil
+	})
+
+	return doc
+}
+
+// installedRoute returns the described Route that is installed at path. The
+// path of the Route may be prefixed. If multiple Routes match, the Route with
+// the longest path is returned.
+func installedRoute(ops map[routes.Route]openapi.Operation, method, path string) (routes.Route, bool) {
+	var match routes.Route
+	var found bool
+	for route := range ops {
+		if route.Method != method || !strings.HasSuffix(path, route.Path) {
+			continue
+		}
+		if !found || len(route.Path) > len(match.Path) {
+			match, found = route, true
+		}
+	}
+	return match, found
+}
+
+var openapiTags = map[string]string{
+	"documents":    "Shelfs and their documents.",
+	"galleries":    "Galleries and their image and video stacks.",
+	"jobs":         "Long-running jobs, e.g. exports.",
+	"audit":        "The audit log of changes.",
+	"dead-letters": "Stacks whose processing failed permanently.",
+	"schemas":      "JSON Schemas of the request bodies.",
+	"catalog":      "The event and command contract of the media service.",
+	"health":       "Health of the media server.",
+	"openapi":      "The OpenAPI document of the media server.",
+}
+
+var pathParams = map[string]string{
+	"ShelfID":    "UUID of the shelf.",
+	"DocumentID": "UUID of the document.",
+	"GalleryID":  "UUID of the gallery.",
+	"StackID":    "UUID of the stack.",
+	"JobID":      "UUID of the job.",
+	"Name":       "The name to look up.",
+	"Tags":       "Comma-separated list of tags.",
+}
+
+// operations returns the descriptions of the media routes.
+func operations() map[routes.Route]openapi.Operation {
+	schemas := Schemas()
+	body := func(name string) *openapi.RequestBody {
+		return openapi.JSONBody(schemas[name])
+	}
+
+	uuidSchema := &schema.Schema{Type: "string", Format: "uuid"}
+	documentSchema := schema.Of(document.Document{})
+	stackSchema := schema.Of(gallery.Stack{})
+	shelfSchema := schema.Of(document.JSONShelf{})
+	gallerySchema := schema.Of(gallery.JSONGallery{})
+	jobSchema := schema.Of(jobResponse{})
+
+	ops := map[routes.Route]openapi.Operation{
+		routes.ListShelfs: {
+			OperationID: "listShelfs",
+			Summary:     "List shelfs",
+			Tags:        []string{"documents"},
+			Parameters:  listParams(aggregateList),
+			Responses:   pageResponses("A page of shelfs.", schema.Of(shelfListResponse{})),
+		},
+		routes.CreateShelf: {
+			OperationID: "createShelf",
+			Summary:     "Create shelf",
+			Tags:        []string{"documents"},
+			RequestBody: body("shelf.create"),
+			Responses:   openapi.Responses{"201": createdResponse("The created shelf.", shelfSchema)},
+		},
+		routes.LookupShelfByName: {
+			OperationID: "lookupShelfByName",
+			Summary:     "Look up shelf by name",
+			Tags:        []string{"documents"},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The UUID of the shelf.", idSchema("shelfId", uuidSchema))},
+		},
+		routes.ShowShelf: {
+			OperationID: "showShelf",
+			Summary:     "Show shelf",
+			Description: "Responds with the whole shelf, or with a page of its documents if any of the list parameters are set.",
+			Tags:        []string{"documents"},
+			Parameters:  listParams(documentList),
+			Responses:   cachedResponses("The shelf, or a page of its documents.", shelfSchema),
+		},
+		routes.RenameShelf: {
+			OperationID: "renameShelf",
+			Summary:     "Rename shelf",
+			Tags:        []string{"documents"},
+			RequestBody: body("shelf.rename"),
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The renamed shelf.", shelfSchema)},
+		},
+		routes.DeleteShelf: {
+			OperationID: "deleteShelf",
+			Summary:     "Delete shelf",
+			Tags:        []string{"documents"},
+			Responses:   openapi.Responses{"204": openapi.EmptyResponse("The shelf was deleted.")},
+		},
+		routes.SearchDocuments: {
+			OperationID: "searchDocuments",
+			Summary:     "Search documents",
+			Tags:        []string{"documents"},
+			Parameters:  listParams(searchList),
+			Responses:   pageResponses("A page of matching documents.", schema.Of(searchResponse{})),
+		},
+		routes.ShowDocument: {
+			OperationID: "showDocument",
+			Summary:     "Show document",
+			Tags:        []string{"documents"},
+			Responses:   cachedResponses("The document.", documentSchema),
+		},
+		routes.ShowDocumentContent: {
+			OperationID: "showDocumentContent",
+			Summary:     "Show document content",
+			Tags:        []string{"documents"},
+			Parameters: []openapi.Parameter{
+				openapi.QueryParam("locale", "Locale of the variant to serve. Falls back to the default content.", &schema.Schema{Type: "string"}),
+			},
+			Responses: fileResponses("The content of the document."),
+		},
+		routes.HeadDocumentContent: {
+			OperationID: "headDocumentContent",
+			Summary:     "Show document content headers",
+			Tags:        []string{"documents"},
+			Parameters: []openapi.Parameter{
+				openapi.QueryParam("locale", "Locale of the variant. Falls back to the default content.", &schema.Schema{Type: "string"}),
+			},
+			Responses: openapi.Responses{"200": openapi.EmptyResponse("The headers of the content of the document.")},
+		},
+		routes.ShowDocumentPreview: {
+			OperationID: "showDocumentPreview",
+			Summary:     "Show document preview",
+			Tags:        []string{"documents"},
+			Responses:   fileResponses("The preview image of the document."),
+		},
+		routes.UploadDocument: {
+			OperationID: "uploadDocument",
+			Summary:     "Upload document",
+			Tags:        []string{"documents"},
+			RequestBody: multipartBody("document", "name", "uniqueName", "disk", "path"),
+			Responses:   openapi.Responses{"201": createdResponse("The uploaded document.", schema.Of(createdDocument{}))},
+		},
+		routes.ReplaceDocument: {
+			OperationID: "replaceDocument",
+			Summary:     "Replace document",
+			Tags:        []string{"documents"},
+			RequestBody: multipartBody("document"),
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The replaced document.", documentSchema)},
+		},
+		routes.ReplaceDocumentDelta: {
+			OperationID: "replaceDocumentDelta",
+			Summary:     "Replace document with delta",
+			Description: "Replaces the content of the document by applying a binary delta to the current content.",
+			Tags:        []string{"documents"},
+			RequestBody: &openapi.RequestBody{
+				Required: true,
+				Content:  map[string]openapi.MediaType{"application/octet-stream": {Schema: binarySchema()}},
+			},
+			Responses: openapi.Responses{"200": openapi.JSONResponse("The replaced document.", documentSchema)},
+		},
+		routes.UpdateDocument: {
+			OperationID: "updateDocument",
+			Summary:     "Update document",
+			Tags:        []string{"documents"},
+			RequestBody: body("document.update"),
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The updated document.", documentSchema)},
+		},
+		routes.DeleteDocument: {
+			OperationID: "deleteDocument",
+			Summary:     "Delete document",
+			Tags:        []string{"documents"},
+			Responses:   openapi.Responses{"204": openapi.EmptyResponse("The document was deleted.")},
+		},
+		routes.TagDocument: {
+			OperationID: "tagDocument",
+			Summary:     "Tag document",
+			Tags:        []string{"documents"},
+			RequestBody: body("document.tags"),
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The tagged document.", documentSchema)},
+		},
+		routes.UntagDocument: {
+			OperationID: "untagDocument",
+			Summary:     "Untag document",
+			Tags:        []string{"documents"},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The untagged document.", documentSchema)},
+		},
+		routes.RetagDocuments: {
+			OperationID: "retagDocuments",
+			Summary:     "Retag documents",
+			Tags:        []string{"documents"},
+			RequestBody: body("document.retag"),
+			Responses: openapi.Responses{"200": openapi.JSONResponse("The retagged documents.", &schema.Schema{
+				Type:       "object",
+				Properties: map[string]*schema.Schema{"documents": {Type: "array", Items: documentSchema}},
+				Required:   []string{"documents"},
+			})},
+		},
+		routes.CopyDocument: {
+			OperationID: "copyDocument",
+			Summary:     "Copy document",
+			Tags:        []string{"documents"},
+			RequestBody: body("document.copy"),
+			Responses: openapi.Responses{
+				"201": createdResponse("The copied document.", schema.Of(createdDocument{})),
+				"202": createdResponse("The copy was started and is processed in the background.", schema.Of(createdDocument{})),
+			},
+		},
+		routes.SignDocumentURL: {
+			OperationID: "signDocumentURL",
+			Summary:     "Sign document URL",
+			Tags:        []string{"documents"},
+			RequestBody: optional(body("document.signedURL")),
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The signed URL of the content.", schema.Of(signedURL{}))},
+		},
+		routes.RestoreDocument: {
+			OperationID: "restoreDocument",
+			Summary:     "Restore document",
+			Tags:        []string{"documents"},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The restored document.", documentSchema)},
+		},
+		routes.SetShelfLegalHold: {
+			OperationID: "setShelfLegalHold",
+			Summary:     "Set shelf legal hold",
+			Tags:        []string{"documents"},
+			RequestBody: body("legalHold"),
+			Responses:   openapi.Responses{"204": openapi.EmptyResponse("The legal hold was set.")},
+		},
+		routes.LiftShelfLegalHold: {
+			OperationID: "liftShelfLegalHold",
+			Summary:     "Lift shelf legal hold",
+			Tags:        []string{"documents"},
+			Responses:   openapi.Responses{"204": openapi.EmptyResponse("The legal hold was lifted.")},
+		},
+		routes.SetDocumentLegalHold: {
+			OperationID: "setDocumentLegalHold",
+			Summary:     "Set document legal hold",
+			Tags:        []string{"documents"},
+			RequestBody: body("legalHold"),
+			Responses:   openapi.Responses{"204": openapi.EmptyResponse("The legal hold was set.")},
+		},
+		routes.LiftDocumentLegalHold: {
+			OperationID: "liftDocumentLegalHold",
+			Summary:     "Lift document legal hold",
+			Tags:        []string{"documents"},
+			Responses:   openapi.Responses{"204": openapi.EmptyResponse("The legal hold was lifted.")},
+		},
+
+		routes.ListGalleries: {
+			OperationID: "listGalleries",
+			Summary:     "List galleries",
+			Tags:        []string{"galleries"},
+			Parameters:  listParams(aggregateList),
+			Responses:   pageResponses("A page of galleries.", schema.Of(galleryListResponse{})),
+		},
+		routes.CreateGallery: {
+			OperationID: "createGallery",
+			Summary:     "Create gallery",
+			Tags:        []string{"galleries"},
+			RequestBody: body("gallery.create"),
+			Responses:   openapi.Responses{"201": createdResponse("The created gallery.", gallerySchema)},
+		},
+		routes.LookupGalleryByName: {
+			OperationID: "lookupGalleryByName",
+			Summary:     "Look up gallery by name",
+			Tags:        []string{"galleries"},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The UUID of the gallery.", idSchema("galleryId", uuidSchema))},
+		},
+		routes.LookupGalleryStackByName: {
+			OperationID: "lookupGalleryStackByName",
+			Summary:     "Look up stack by name",
+			Tags:        []string{"galleries"},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The UUID of the stack.", idSchema("stackId", uuidSchema))},
+		},
+		routes.LookupGalleryStacksByTag: {
+			OperationID: "lookupGalleryStacksByTag",
+			Summary:     "Look up stacks by tag",
+			Tags:        []string{"galleries"},
+			Parameters: []openapi.Parameter{
+				required(openapi.QueryParam("tag", "The tag to look up.", &schema.Schema{Type: "string"})),
+			},
+			Responses: openapi.Responses{"200": openapi.JSONResponse("The UUIDs of the stacks.", idSchema("stackIds", &schema.Schema{Type: "array", Items: uuidSchema}))},
+		},
+		routes.ShowGallery: {
+			OperationID: "showGallery",
+			Summary:     "Show gallery",
+			Description: "Responds with the whole gallery, or with a page of its stacks if any of the list parameters are set.",
+			Tags:        []string{"galleries"},
+			Parameters:  listParams(stackList),
+			Responses:   cachedResponses("The gallery, or a page of its stacks.", gallerySchema),
+		},
+		routes.RenameGallery: {
+			OperationID: "renameGallery",
+			Summary:     "Rename gallery",
+			Tags:        []string{"galleries"},
+			RequestBody: body("gallery.rename"),
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The renamed gallery.", gallerySchema)},
+		},
+		routes.DeleteGallery: {
+			OperationID: "deleteGallery",
+			Summary:     "Delete gallery",
+			Tags:        []string{"galleries"},
+			Responses:   openapi.Responses{"204": openapi.EmptyResponse("The gallery was deleted.")},
+		},
+		routes.ShowGalleryIndex: {
+			OperationID: "showGalleryIndex",
+			Summary:     "Show gallery index",
+			Tags:        []string{"galleries"},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The index of the gallery.", schema.Of(gallery.JSONIndex{}))},
+		},
+		routes.ShowStack: {
+			OperationID: "showStack",
+			Summary:     "Show stack",
+			Tags:        []string{"galleries"},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The stack.", stackSchema)},
+		},
+		routes.ShowStackContent: {
+			OperationID: "showStackContent",
+			Summary:     "Show stack content",
+			Tags:        []string{"galleries"},
+			Parameters:  stackImageParams(),
+			Responses:   fileResponses("The image of the stack."),
+		},
+		routes.HeadStackContent: {
+			OperationID: "headStackContent",
+			Summary:     "Show stack content headers",
+			Tags:        []string{"galleries"},
+			Parameters:  stackImageParams(),
+			Responses:   openapi.Responses{"200": openapi.EmptyResponse("The headers of the image of the stack.")},
+		},
+		routes.ShowStackOriginal: {
+			OperationID: "showStackOriginal",
+			Summary:     "Show stack original",
+			Tags:        []string{"galleries"},
+			Responses:   fileResponses("The original image of the stack."),
+		},
+		routes.SignStackURL: {
+			OperationID: "signStackURL",
+			Summary:     "Sign stack URL",
+			Tags:        []string{"galleries"},
+			RequestBody: optional(body("stack.signedURL")),
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The signed URL of the image.", schema.Of(signedURL{}))},
+		},
+		routes.ShowStackVideo: {
+			OperationID: "showStackVideo",
+			Summary:     "Show stack video",
+			Tags:        []string{"galleries"},
+			Responses:   fileResponses("The video of the stack."),
+		},
+		routes.ShowStackStatus: {
+			OperationID: "showStackStatus",
+			Summary:     "Show stack status",
+			Tags:        []string{"galleries"},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The processing status of the stack.", schema.Of(stackStatus{}))},
+		},
+		routes.StreamGalleryEvents: {
+			OperationID: "streamGalleryEvents",
+			Summary:     "Stream gallery events",
+			Tags:        []string{"galleries"},
+			Responses: openapi.Responses{"200": {
+				Description: "Server-sent events of the gallery.",
+				Content:     map[string]openapi.MediaType{"text/event-stream": {Schema: &schema.Schema{Type: "string"}}},
+			}},
+		},
+		routes.UploadImage: {
+			OperationID: "uploadImage",
+			Summary:     "Upload image",
+			Tags:        []string{"galleries"},
+			RequestBody: multipartBody("image", "name", "disk", "path"),
+			Responses:   openapi.Responses{"201": createdResponse("The uploaded stack.", schema.Of(createdStack{}))},
+		},
+		routes.UploadVideo: {
+			OperationID: "uploadVideo",
+			Summary:     "Upload video",
+			Tags:        []string{"galleries"},
+			RequestBody: multipartBody("video", "name", "disk", "path"),
+			Responses:   openapi.Responses{"201": createdResponse("The uploaded stack.", schema.Of(createdStack{}))},
+		},
+		routes.ReplaceImage: {
+			OperationID: "replaceImage",
+			Summary:     "Replace image",
+			Tags:        []string{"galleries"},
+			RequestBody: multipartBody("image"),
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The replaced stack.", stackSchema)},
+		},
+		routes.UpdateStack: {
+			OperationID: "updateStack",
+			Summary:     "Update stack",
+			Tags:        []string{"galleries"},
+			RequestBody: body("stack.update"),
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The updated stack.", stackSchema)},
+		},
+		routes.SetStackFocalPoint: {
+			OperationID: "setStackFocalPoint",
+			Summary:     "Set stack focal point",
+			Tags:        []string{"galleries"},
+			RequestBody: body("stack.focalPoint"),
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The updated stack.", stackSchema)},
+		},
+		routes.CropStack: {
+			OperationID: "cropStack",
+			Summary:     "Crop stack",
+			Tags:        []string{"galleries"},
+			RequestBody: body("stack.crop"),
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The cropped stack.", stackSchema)},
+		},
+		routes.RotateStack: {
+			OperationID: "rotateStack",
+			Summary:     "Rotate stack",
+			Tags:        []string{"galleries"},
+			RequestBody: body("stack.rotate"),
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The rotated stack.", stackSchema)},
+		},
+		routes.FlipStack: {
+			OperationID: "flipStack",
+			Summary:     "Flip stack",
+			Tags:        []string{"galleries"},
+			RequestBody: body("stack.flip"),
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The flipped stack.", stackSchema)},
+		},
+		routes.ReprocessStack: {
+			OperationID: "reprocessStack",
+			Summary:     "Reprocess stack",
+			Tags:        []string{"galleries"},
+			Responses:   openapi.Responses{"202": openapi.JSONResponse("The stack is processed in the background.", stackSchema)},
+		},
+		routes.DeleteStack: {
+			OperationID: "deleteStack",
+			Summary:     "Delete stack",
+			Tags:        []string{"galleries"},
+			Responses:   openapi.Responses{"204": openapi.EmptyResponse("The stack was moved to the trash.")},
+		},
+		routes.TagStack: {
+			OperationID: "tagStack",
+			Summary:     "Tag stack",
+			Tags:        []string{"galleries"},
+			RequestBody: body("stack.tags"),
+			Responses:   openapi.Responses{"201": openapi.JSONResponse("The tagged stack.", stackSchema)},
+		},
+		routes.UntagStack: {
+			OperationID: "untagStack",
+			Summary:     "Untag stack",
+			Tags:        []string{"galleries"},
+			Responses:   openapi.Responses{"201": openapi.JSONResponse("The untagged stack.", stackSchema)},
+		},
+		routes.SortGallery: {
+			OperationID: "sortGallery",
+			Summary:     "Sort gallery",
+			Tags:        []string{"galleries"},
+			RequestBody: body("gallery.sorting"),
+			Responses:   openapi.Responses{"204": openapi.EmptyResponse("The gallery was sorted.")},
+		},
+		routes.MoveStack: {
+			OperationID: "moveStack",
+			Summary:     "Move stack",
+			Tags:        []string{"galleries"},
+			RequestBody: optional(body("stack.move")),
+			Responses:   openapi.Responses{"204": openapi.EmptyResponse("The stack was moved.")},
+		},
+		routes.SetGalleryDefaultTags: {
+			OperationID: "setGalleryDefaultTags",
+			Summary:     "Set gallery default tags",
+			Tags:        []string{"galleries"},
+			RequestBody: body("gallery.defaultTags"),
+			Responses:   openapi.Responses{"204": openapi.EmptyResponse("The default tags were set.")},
+		},
+		routes.ProtectGalleryOriginals: {
+			OperationID: "protectGalleryOriginals",
+			Summary:     "Protect gallery originals",
+			Tags:        []string{"galleries"},
+			RequestBody: body("gallery.originalsProtection"),
+			Responses:   openapi.Responses{"204": openapi.EmptyResponse("The protection of the originals was changed.")},
+		},
+		routes.ShowGalleryTrash: {
+			OperationID: "showGalleryTrash",
+			Summary:     "Show gallery trash",
+			Tags:        []string{"galleries"},
+			Parameters:  listParams(trashList),
+			Responses:   pageResponses("A page of trashed stacks.", schema.Of([]gallery.TrashedStack{})),
+		},
+		routes.RestoreStack: {
+			OperationID: "restoreStack",
+			Summary:     "Restore stack",
+			Tags:        []string{"galleries"},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The restored stack.", stackSchema)},
+		},
+		routes.SetGalleryLegalHold: {
+			OperationID: "setGalleryLegalHold",
+			Summary:     "Set gallery legal hold",
+			Tags:        []string{"galleries"},
+			RequestBody: body("legalHold"),
+			Responses:   openapi.Responses{"204": openapi.EmptyResponse("The legal hold was set.")},
+		},
+		routes.LiftGalleryLegalHold: {
+			OperationID: "liftGalleryLegalHold",
+			Summary:     "Lift gallery legal hold",
+			Tags:        []string{"galleries"},
+			Responses:   openapi.Responses{"204": openapi.EmptyResponse("The legal hold was lifted.")},
+		},
+		routes.SetStackLegalHold: {
+			OperationID: "setStackLegalHold",
+			Summary:     "Set stack legal hold",
+			Tags:        []string{"galleries"},
+			RequestBody: body("legalHold"),
+			Responses:   openapi.Responses{"204": openapi.EmptyResponse("The legal hold was set.")},
+		},
+		routes.LiftStackLegalHold: {
+			OperationID: "liftStackLegalHold",
+			Summary:     "Lift stack legal hold",
+			Tags:        []string{"galleries"},
+			Responses:   openapi.Responses{"204": openapi.EmptyResponse("The legal hold was lifted.")},
+		},
+		routes.SetGalleryVersionLimit: {
+			OperationID: "setGalleryVersionLimit",
+			Summary:     "Set gallery version limit",
+			Tags:        []string{"galleries"},
+			RequestBody: body("gallery.versioning"),
+			Responses:   openapi.Responses{"204": openapi.EmptyResponse("The version limit was set.")},
+		},
+		routes.ShowStackVersions: {
+			OperationID: "showStackVersions",
+			Summary:     "Show stack versions",
+			Tags:        []string{"galleries"},
+			Parameters:  listParams(versionList),
+			Responses:   pageResponses("The previous versions of the stack.", schema.Of([]gallery.StackVersion{})),
+		},
+		routes.RevertStack: {
+			OperationID: "revertStack",
+			Summary:     "Revert stack",
+			Tags:        []string{"galleries"},
+			RequestBody: body("stack.revert"),
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The reverted stack.", stackSchema)},
+		},
+
+		routes.ExportGallery: {
+			OperationID: "exportGallery",
+			Summary:     "Export gallery",
+			Tags:        []string{"jobs"},
+			Responses:   openapi.Responses{"202": createdResponse("The started export job.", jobSchema)},
+		},
+		routes.ExportShelf: {
+			OperationID: "exportShelf",
+			Summary:     "Export shelf",
+			Tags:        []string{"jobs"},
+			Responses:   openapi.Responses{"202": createdResponse("The started export job.", jobSchema)},
+		},
+		routes.ShowJob: {
+			OperationID: "showJob",
+			Summary:     "Show job",
+			Tags:        []string{"jobs"},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The job.", jobSchema)},
+		},
+		routes.CancelJob: {
+			OperationID: "cancelJob",
+			Summary:     "Cancel job",
+			Tags:        []string{"jobs"},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The canceled job.", jobSchema)},
+		},
+		routes.ShowJobArtifact: {
+			OperationID: "showJobArtifact",
+			Summary:     "Show job artifact",
+			Tags:        []string{"jobs"},
+			Responses:   fileResponses("The artifact of the job."),
+		},
+
+		routes.ShowAuditLog: {
+			OperationID: "showAuditLog",
+			Summary:     "Show audit log",
+			Tags:        []string{"audit"},
+			Parameters:  listParams(auditList),
+			Responses:   pageResponses("A page of audit entries, newest first.", schema.Of(auditResponse{})),
+		},
+
+		routes.ListDeadLetters: {
+			OperationID: "listDeadLetters",
+			Summary:     "List dead letters",
+			Tags:        []string{"dead-letters"},
+			Parameters: []openapi.Parameter{
+				openapi.QueryParam("galleryId", "Only list the dead letters of the gallery.", uuidSchema),
+			},
+			Responses: openapi.Responses{"200": openapi.JSONResponse("The dead letters.", schema.Of(deadLettersResponse{}))},
+		},
+		routes.RedispatchDeadLetter: {
+			OperationID: "redispatchDeadLetter",
+			Summary:     "Redispatch dead letter",
+			Tags:        []string{"dead-letters"},
+			Responses:   openapi.Responses{"202": openapi.JSONResponse("The stack is reprocessed in the background.", schema.Of(gallery.DeadLetter{}))},
+		},
+
+		routes.Schemas: {
+			OperationID: "listSchemas",
+			Summary:     "List schemas",
+			Tags:        []string{"schemas"},
+			Responses: openapi.Responses{"200": openapi.JSONResponse("The JSON Schemas, keyed by name.", &schema.Schema{
+				Type:                 "object",
+				AdditionalProperties: jsonSchema(),
+			})},
+		},
+		routes.ShowSchema: {
+			OperationID: "showSchema",
+			Summary:     "Show schema",
+			Tags:        []string{"schemas"},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The JSON Schema.", jsonSchema())},
+		},
+
+		routes.Catalog: {
+			OperationID: "showCatalog",
+			Summary:     "Show catalog",
+			Tags:        []string{"catalog"},
+			Responses: openapi.Responses{"200": openapi.JSONResponse("The events and commands of the media service.", &schema.Schema{
+				Type: "object",
+			})},
+		},
+
+		routes.Health: {
+			OperationID: "showHealth",
+			Summary:     "Show health",
+			Tags:        []string{"health"},
+			Responses: openapi.Responses{
+				"200": openapi.JSONResponse("All projections are healthy.", schema.Of(healthResponse{})),
+				"503": openapi.JSONResponse("A projection is not healthy.", schema.Of(healthResponse{})),
+			},
+		},
+
+		routes.OpenAPI: {
+			OperationID: "showOpenAPI",
+			Summary:     "Show OpenAPI document",
+			Tags:        []string{"openapi"},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The OpenAPI document.", &schema.Schema{Type: "object"})},
+		},
+	}
+
+	errorResponse := openapi.JSONResponse("Error.", &schema.Schema{
+		Type:       "object",
+		Properties: map[string]*schema.Schema{"error": {Type: "string"}},
+		Required:   []string{"error"},
+	})
+
+	for route, op := range ops {
+		var params []openapi.Parameter
+		for _, name := range openapi.PathParams(route.Path) {
+			params = append(params, openapi.PathParam(name, pathParams[name]))
+		}
+		op.Parameters = append(params, op.Parameters...)
+		op.Responses["default"] = errorResponse
+		ops[route] = op
+	}
+
+	return ops
+}
+
+// listParams returns the query parameters of a list endpoint.
+func listParams(l api.List) []openapi.Parameter {
+	params := []openapi.Parameter{
+		openapi.QueryParam(api.CursorParam, "Cursor of the page, from the X-Next-Cursor header of the previous page.", &schema.Schema{Type: "string"}),
+		openapi.QueryParam(api.OffsetParam, "Number of items to skip.", &schema.Schema{Type: "integer"}),
+		openapi.QueryParam(api.LimitParam, "Maximum number of items.", &schema.Schema{Type: "integer"}),
+	}
+
+	if len(l.Sorts) > 0 {
+		params = append(params, openapi.QueryParam(
+			api.SortParam,
+			"Comma-separated fields to sort by. A \"-\" prefix sorts a field in descending order. Fields: "+strings.Join(l.Sorts, ", "),
+			&schema.Schema{Type: "string"},
+		))
+	}
+
+	fields := make([]string, 0, len(l.Filters))
+	for field := range l.Filters {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		for _, op := range l.Filters[field] {
+			name, desc := field, "Filters by "+field+"."
+			if op != api.Eq {
+				name += "[" + string(op) + "]"
+				desc = "Filters by " + field + " (" + string(op) + ")."
+			}
+			params = append(params, openapi.QueryParam(name, desc, &schema.Schema{Type: "string"}))
+		}
+	}
+
+	return params
+}
+
+// stackImageParams returns the query parameters that select the image of a
+// stack.
+func stackImageParams() []openapi.Parameter {
+	return []openapi.Parameter{
+		openapi.QueryParam("size", "Size of the image. Defaults to the original image.", &schema.Schema{Type: "string"}),
+		openapi.QueryParam("format", "Format of the image, e.g. \"webp\".", &schema.Schema{Type: "string"}),
+	}
+}
+
+// pageResponses returns the responses of a list endpoint.
+func pageResponses(desc string, s *schema.Schema) openapi.Responses {
+	res := openapi.JSONResponse(desc, s)
+	res.Headers = map[string]openapi.Header{
+		api.TotalCountHeader: {Description: "Number of items that match the query.", Schema: &schema.Schema{Type: "integer"}},
+		api.NextCursorHeader: {Description: "Cursor of the next page.", Schema: &schema.Schema{Type: "string"}},
+	}
+	return openapi.Responses{"200": res}
+}
+
+// cachedResponses returns the responses of a route that responds with an
+// ETag.
+func cachedResponses(desc string, s *schema.Schema) openapi.Responses {
+	res := openapi.JSONResponse(desc, s)
+	res.Headers = map[string]openapi.Header{
+		"ETag": {Description: "Version of the resource.", Schema: &schema.Schema{Type: "string"}},
+	}
+	return openapi.Responses{
+		"200": res,
+		"304": openapi.EmptyResponse("The resource was not modified since the version in the If-None-Match header."),
+	}
+}
+
+// createdResponse returns a JSON response with a Location header.
+func createdResponse(desc string, s *schema.Schema) *openapi.Response {
+	res := openapi.JSONResponse(desc, s)
+	res.Headers = map[string]openapi.Header{
+		"Location": {Description: "URL of the resource.", Schema: &schema.Schema{Type: "string"}},
+	}
+	return res
+}
+
+// fileResponses returns the responses of a route that serves a file.
+func fileResponses(desc string) openapi.Responses {
+	return openapi.Responses{
+		"200": {
+			Description: desc,
+			Content:     map[string]openapi.MediaType{"*/*": {Schema: binarySchema()}},
+		},
+		"206": {
+			Description: "The requested range of the file.",
+			Content:     map[string]openapi.MediaType{"*/*": {Schema: binarySchema()}},
+		},
+	}
+}
+
+// multipartBody returns a multipart request body with the given file field and
+// optional string fields.
+func multipartBody(file string, fields ...string) *openapi.RequestBody {
+	s := &schema.Schema{
+		Type:       "object",
+		Properties: map[string]*schema.Schema{file: binarySchema()},
+		Required:   []string{file},
+	}
+	for _, field := range fields {
+		s.Properties[field] = &schema.Schema{Type: "string"}
+	}
+	return &openapi.RequestBody{
+		Description: "The fields should be sent before the file.",
+		Required:    true,
+		Content:     map[string]openapi.MediaType{"multipart/form-data": {Schema: s}},
+	}
+}
+
+func idSchema(field string, s *schema.Schema) *schema.Schema {
+	return &schema.Schema{
+		Type:       "object",
+		Properties: map[string]*schema.Schema{field: s},
+		Required:   []string{field},
+	}
+}
+
+func binarySchema() *schema.Schema {
+	return &schema.Schema{Type: "string", Format: "binary"}
+}
+
+// jsonSchema returns the Schema of a JSON Schema. JSON Schemas are recursive,
+// so they are only described as objects.
+func jsonSchema() *schema.Schema {
+	return &schema.Schema{Type: "object", Description: "A JSON Schema."}
+}
+
+func optional(body *openapi.RequestBody) *openapi.RequestBody {
+	body.Required = false
+	return body
+}
+
+func required(p openapi.Parameter) openapi.Parameter {
+	p.Required = true
+	return p
+}
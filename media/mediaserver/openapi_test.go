@@ -0,0 +1,95 @@
+package mediaserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modernice/nice-cms/media/mediaserver"
+	"github.com/modernice/nice-cms/media/mediaserver/routes"
+	"github.com/modernice/nice-cms/openapi"
+)
+
+func TestWithOpenAPI(t *testing.T) {
+	extra := openapi.New(openapi.Info{})
+	extra.Add("GET", "/pages/{PageID}", openapi.Operation{Summary: "Show page"})
+
+	srv := mediaserver.New(
+		&commandBus{},
+		mediaserver.WithDocuments(nil, "", routes.Prefix("/media")),
+		mediaserver.WithGalleries(nil, routes.Prefix("/media"), routes.Disable(routes.DeleteGallery)),
+		mediaserver.WithOpenAPI(openapi.Info{Title: "foo", Version: "1.0.0"}, []*openapi.Document{extra}),
+	)
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+
+	var doc openapi.Document
+	if err := json.NewDecoder(rec.Body).Decode(&doc); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if doc.OpenAPI != openapi.Version || doc.Info.Title != "foo" {
+		t.Fatalf("document should have version %q and title %q; got %q and %q", openapi.Version, "foo", doc.OpenAPI, doc.Info.Title)
+	}
+
+	var installed []routes.Route
+	installed = append(installed, routes.DocumentReadRoutes[:]...)
+	installed = append(installed, routes.DocumentWriteRoutes[:]...)
+	installed = append(installed, routes.GalleryReadRoutes[:]...)
+	installed = append(installed, routes.GalleryWriteRoutes[:]...)
+
+	for _, route := range installed {
+		if route == routes.DeleteGallery || route == routes.StreamGalleryEvents {
+			continue
+		}
+
+		op, ok := doc.Operation(route.Method, "/media"+route.Path)
+		if !ok {
+			t.Errorf("document should describe %s %s", route.Method, "/media"+route.Path)
+			continue
+		}
+
+		if op.OperationID == "" || len(op.Responses) < 2 {
+			t.Errorf("%s %s should have an operation ID and responses; got %v", route.Method, route.Path, op)
+		}
+
+		for _, name := range openapi.PathParams(route.Path) {
+			if !hasPathParam(op.Parameters, name) {
+				t.Errorf("%s %s should describe the %q path parameter", route.Method, route.Path, name)
+			}
+		}
+	}
+
+	if _, ok := doc.Operation(routes.DeleteGallery.Method, "/media"+routes.DeleteGallery.Path); ok {
+		t.Errorf("document should not describe disabled routes")
+	}
+
+	if _, ok := doc.Operation(routes.ShowGallery.Method, routes.ShowGallery.Path); ok {
+		t.Errorf("document should describe routes at their prefixed paths")
+	}
+
+	if op, ok := doc.Operation("GET", "/pages/{PageID}"); !ok || op.Summary != "Show page" {
+		t.Errorf("document should contain the operations of the extra documents; got %v", op)
+	}
+
+	if op, ok := doc.Operation(routes.UploadImage.Method, "/media"+routes.UploadImage.Path); ok {
+		if _, ok := op.RequestBody.Content["multipart/form-data"].Schema.Properties["image"]; !ok {
+			t.Errorf("upload body should describe the %q file field; got %v", "image", op.RequestBody)
+		}
+	}
+}
+
+func hasPathParam(params []openapi.Parameter, name string) bool {
+	for _, p := range params {
+		if p.Name == name && p.In == "path" && p.Required {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package mediaserver
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/modernice/nice-cms/internal/api"
+	"github.com/modernice/nice-cms/media"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// PlaceholderHeader is the response header that is set to "true" when the
+// media server responds with a placeholder instead of a missing image.
+const PlaceholderHeader = "X-Media-Placeholder"
+
+// Default dimensions of placeholders for images with unknown dimensions.
+const (
+	defaultPlaceholderWidth  = 400
+	defaultPlaceholderHeight = 300
+)
+
+// Placeholder configures the placeholder images that are served instead of
+// stack images that are missing from storage.
+type Placeholder struct {
+	// Width and Height are the dimensions of placeholders for images with
+	// unknown dimensions. Placeholders for images with known dimensions have
+	// the dimensions of the missing image. Defaults to 400x300.
+	Width  int
+	Height int
+
+	// Background is the background color. Defaults to light gray.
+	Background color.Color
+
+	// Foreground is the color of Text. Defaults to dark gray.
+	Foreground color.Color
+
+	// Text is drawn in the center of the placeholder. No text is drawn if Text
+	// is empty.
+	Text string
+}
+
+// WithPlaceholder returns an Option that makes the media server respond with
+// a generated placeholder image if a requested stack image is missing from
+// storage, so that public sites don't show broken images while the image is
+// being repaired. Placeholder responses have the PlaceholderHeader set and
+// must not be cached.
+func WithPlaceholder(p Placeholder) Option {
+	return func(s *Server) {
+		s.placeholder = &p
+	}
+}
+
+// Render renders the placeholder for the given image as a PNG image.
+func (p Placeholder) Render(img media.Image) ([]byte, error) {
+	width, height := img.Width, img.Height
+	if width <= 0 || height <= 0 {
+		width, height = p.Width, p.Height
+	}
+	if width <= 0 || height <= 0 {
+		width, height = defaultPlaceholderWidth, defaultPlaceholderHeight
+	}
+
+	bg := p.Background
+	if bg == nil {
+		bg = color.Gray{Y: 0xe5}
+	}
+
+	fg := p.Foreground
+	if fg == nil {
+		fg = color.Gray{Y: 0x73}
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+
+	if text := strings.TrimSpace(p.Text); text != "" {
+		face := basicfont.Face7x13
+		d := font.Drawer{Dst: canvas, Src: image.NewUniform(fg), Face: face}
+		textWidth := d.MeasureString(text).Ceil()
+		metrics := face.Metrics()
+		textHeight := (metrics.Ascent + metrics.Descent).Ceil()
+		d.Dot = fixed.P((width-textWidth)/2, (height-textHeight)/2+metrics.Ascent.Ceil())
+		d.DrawString(text)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, canvas); err != nil {
+		return nil, fmt.Errorf("encode placeholder: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (p Placeholder) serve(w http.ResponseWriter, r *http.Request, img media.Image) {
+	b, err := p.Render(img)
+	if err != nil {
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to render placeholder: %v", err))
+		return
+	}
+
+	w.Header().Set(PlaceholderHeader, "true")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "image/png")
+
+	http.ServeContent(w, r, "placeholder.png", time.Time{}, bytes.NewReader(b))
+}
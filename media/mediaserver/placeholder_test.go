@@ -0,0 +1,121 @@
+package mediaserver_test
+
+import (
+	"bytes"
+	"context"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/media/mediaserver"
+)
+
+func TestWithPlaceholder(t *testing.T) {
+	srv := newPlaceholderServer(t, mediaserver.Placeholder{Text: "Image unavailable"})
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, stackContentPath("thumb"), nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d", http.StatusOK, rec.Code)
+	}
+
+	if h := rec.Header().Get(mediaserver.PlaceholderHeader); h != "true" {
+		t.Fatalf("%s header should be %q; is %q", mediaserver.PlaceholderHeader, "true", h)
+	}
+
+	if cc := rec.Header().Get("Cache-Control"); cc != "no-store" {
+		t.Fatalf("Cache-Control header should be %q; is %q", "no-store", cc)
+	}
+
+	img, err := png.Decode(rec.Body)
+	if err != nil {
+		t.Fatalf("decode placeholder: %v", err)
+	}
+
+	if b := img.Bounds(); b.Dx() != 160 || b.Dy() != 90 {
+		t.Fatalf("placeholder should have the dimensions of the missing image (160x90); is %dx%d", b.Dx(), b.Dy())
+	}
+}
+
+func TestWithPlaceholder_existingImage(t *testing.T) {
+	srv := newPlaceholderServer(t, mediaserver.Placeholder{})
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, stackContentPath(""), nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d", http.StatusOK, rec.Code)
+	}
+
+	if h := rec.Header().Get(mediaserver.PlaceholderHeader); h != "" {
+		t.Fatalf("%s header should not be set for existing images; is %q", mediaserver.PlaceholderHeader, h)
+	}
+
+	if rec.Body.String() != "foo" {
+		t.Fatalf("existing image should be served; got %q", rec.Body.String())
+	}
+}
+
+func TestPlaceholder_Render(t *testing.T) {
+	bg := color.RGBA{255, 0, 0, 255}
+	p := mediaserver.Placeholder{Width: 40, Height: 30, Background: bg}
+
+	b, err := p.Render(media.Image{})
+	if err != nil {
+		t.Fatalf("Render failed with %q", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("decode placeholder: %v", err)
+	}
+
+	if bounds := img.Bounds(); bounds.Dx() != 40 || bounds.Dy() != 30 {
+		t.Fatalf("placeholder should fall back to the configured dimensions (40x30); is %dx%d", bounds.Dx(), bounds.Dy())
+	}
+
+	if c := color.RGBAModel.Convert(img.At(0, 0)); c != bg {
+		t.Fatalf("placeholder should have the background color %v; has %v", bg, c)
+	}
+}
+
+func newPlaceholderServer(t *testing.T, p mediaserver.Placeholder) *mediaserver.Server {
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+	disk, _ := storage.Disk("foo-disk")
+	if err := disk.Put(context.Background(), "/foo.txt", []byte("foo")); err != nil {
+		t.Fatalf("put file: %v", err)
+	}
+
+	g := gallery.JSONGallery{
+		ID:   galleryID,
+		Name: "foo",
+		Stacks: gallery.Stacks{{
+			ID: stackID,
+			Images: []gallery.Image{
+				{Image: media.Image{File: media.NewFile("foo", "foo-disk", "/foo.txt", 3)}, Original: true},
+				{Image: media.NewImage(160, 90, "foo", "foo-disk", "/foo_thumb.png", 100), Size: "thumb"},
+			},
+		}},
+	}
+
+	// The order of the options doesn't matter.
+	return mediaserver.New(
+		&commandBus{},
+		mediaserver.WithGalleries(galleryClient{g}),
+		mediaserver.WithPlaceholder(p),
+		mediaserver.WithStorage(storage),
+	)
+}
+
+func stackContentPath(size string) string {
+	path := "/galleries/" + galleryID.String() + "/stacks/" + stackID.String() + "/content"
+	if size != "" {
+		path += "?size=" + size
+	}
+	return path
+}
@@ -1,9 +1,24 @@
 package routes
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/modernice/nice-cms/internal/api"
+)
+
+var (
+	// ErrUnauthorized is returned by an Authorizer if a request is not
+	// authenticated. Protected routes respond with 401 Unauthorized.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrForbidden is returned by an Authorizer if an authenticated request is
+	// not allowed to access a route. Protected routes respond with 403 Forbidden.
+	ErrForbidden = errors.New("forbidden")
 )
 
 // All is a wildcard for all routes.
@@ -11,98 +26,390 @@ var All = route("*", "*")
 
 // Gallery routes
 var (
+	ListGalleries            = route("GET", "/galleries")
+	CreateGallery            = route("POST", "/galleries")
 	LookupGalleryByName      = route("GET", "/galleries/lookup/name/{Name}")
 	LookupGalleryStackByName = route("GET", "/galleries/{GalleryID}/lookup/stack-name/{Name}")
+	LookupGalleryStacksByTag = route("GET", "/galleries/{GalleryID}/stacks")
 	ShowGallery              = route("GET", "/galleries/{GalleryID}")
+	RenameGallery            = route("PATCH", "/galleries/{GalleryID}")
+	DeleteGallery            = route("DELETE", "/galleries/{GalleryID}")
+	ShowGalleryIndex         = route("GET", "/galleries/{GalleryID}/index")
+	ShowStack                = route("GET", "/galleries/{GalleryID}/stacks/{StackID}")
+	ShowStackContent         = route("GET", "/galleries/{GalleryID}/stacks/{StackID}/content")
+	HeadStackContent         = route("HEAD", "/galleries/{GalleryID}/stacks/{StackID}/content")
+	ShowStackOriginal        = route("GET", "/galleries/{GalleryID}/stacks/{StackID}/original")
+	SignStackURL             = route("POST", "/galleries/{GalleryID}/stacks/{StackID}/signed-url")
+	ShowStackVideo           = route("GET", "/galleries/{GalleryID}/stacks/{StackID}/video")
+	ShowStackStatus          = route("GET", "/galleries/{GalleryID}/stacks/{StackID}/status")
+	StreamGalleryEvents      = route("GET", "/galleries/{GalleryID}/events")
 	UploadImage              = route("POST", "/galleries/{GalleryID}/stacks")
+	UploadVideo              = route("POST", "/galleries/{GalleryID}/videos")
 	ReplaceImage             = route("PUT", "/galleries/{GalleryID}/stacks/{StackID}")
 	UpdateStack              = route("PATCH", "/galleries/{GalleryID}/stacks/{StackID}")
+	SetStackFocalPoint       = route("PUT", "/galleries/{GalleryID}/stacks/{StackID}/focal-point")
+	CropStack                = route("POST", "/galleries/{GalleryID}/stacks/{StackID}/crop")
+	RotateStack              = route("POST", "/galleries/{GalleryID}/stacks/{StackID}/rotate")
+	FlipStack                = route("POST", "/galleries/{GalleryID}/stacks/{StackID}/flip")
+	ReprocessStack           = route("POST", "/galleries/{GalleryID}/stacks/{StackID}/process")
 	DeleteStack              = route("DELETE", "/galleries/{GalleryID}/stacks/{StackID}")
 	TagStack                 = route("POST", "/galleries/{GalleryID}/stacks/{StackID}/tags")
 	UntagStack               = route("DELETE", "/galleries/{GalleryID}/stacks/{StackID}/tags/{Tags}")
 	SortGallery              = route("PATCH", "/galleries/{GalleryID}/sorting")
+	MoveStack                = route("POST", "/galleries/{GalleryID}/stacks/{StackID}/move")
+	SetGalleryDefaultTags    = route("PUT", "/galleries/{GalleryID}/default-tags")
+	ProtectGalleryOriginals  = route("PUT", "/galleries/{GalleryID}/originals-protection")
+	ShowGalleryTrash         = route("GET", "/galleries/{GalleryID}/trash")
+	RestoreStack             = route("POST", "/galleries/{GalleryID}/trash/{StackID}/restore")
+	SetGalleryLegalHold      = route("PUT", "/galleries/{GalleryID}/legal-hold")
+	LiftGalleryLegalHold     = route("DELETE", "/galleries/{GalleryID}/legal-hold")
+	SetStackLegalHold        = route("PUT", "/galleries/{GalleryID}/stacks/{StackID}/legal-hold")
+	LiftStackLegalHold       = route("DELETE", "/galleries/{GalleryID}/stacks/{StackID}/legal-hold")
+	SetGalleryVersionLimit   = route("PUT", "/galleries/{GalleryID}/versioning")
+	ShowStackVersions        = route("GET", "/galleries/{GalleryID}/stacks/{StackID}/versions")
+	RevertStack              = route("POST", "/galleries/{GalleryID}/stacks/{StackID}/revert")
 
 	GalleryReadRoutes = [...]Route{
+		ListGalleries,
 		LookupGalleryByName,
 		LookupGalleryStackByName,
+		LookupGalleryStacksByTag,
 		ShowGallery,
+		ShowGalleryIndex,
+		ShowStack,
+		ShowStackContent,
+		HeadStackContent,
+		ShowStackOriginal,
+		ShowStackVideo,
+		ShowStackStatus,
+		StreamGalleryEvents,
+		SignStackURL,
+		ShowGalleryTrash,
+		ShowStackVersions,
 	}
 
 	GalleryWriteRoutes = [...]Route{
+		CreateGallery,
+		RenameGallery,
+		DeleteGallery,
 		UploadImage,
+		UploadVideo,
 		ReplaceImage,
 		UpdateStack,
+		SetStackFocalPoint,
+		CropStack,
+		RotateStack,
+		FlipStack,
+		ReprocessStack,
 		DeleteStack,
 		TagStack,
 		UntagStack,
 		SortGallery,
+		MoveStack,
+		SetGalleryDefaultTags,
+		ProtectGalleryOriginals,
+		RestoreStack,
+		SetGalleryLegalHold,
+		LiftGalleryLegalHold,
+		SetStackLegalHold,
+		LiftStackLegalHold,
+		SetGalleryVersionLimit,
+		RevertStack,
 	}
 
 	GalleryRoutes = [...]Route{
+		ListGalleries,
 		LookupGalleryByName,
 		LookupGalleryStackByName,
+		LookupGalleryStacksByTag,
 		ShowGallery,
+		ShowGalleryIndex,
+		ShowStack,
+		ShowStackContent,
+		HeadStackContent,
+		ShowStackOriginal,
+		ShowStackVideo,
+		ShowStackStatus,
+		StreamGalleryEvents,
+		SignStackURL,
+		ShowGalleryTrash,
+		ShowStackVersions,
+		CreateGallery,
+		RenameGallery,
+		DeleteGallery,
 		UploadImage,
+		UploadVideo,
 		ReplaceImage,
 		UpdateStack,
+		SetStackFocalPoint,
+		CropStack,
+		RotateStack,
+		FlipStack,
+		ReprocessStack,
 		DeleteStack,
 		TagStack,
 		UntagStack,
+		MoveStack,
+		SetGalleryDefaultTags,
+		ProtectGalleryOriginals,
+		RestoreStack,
+		SetGalleryLegalHold,
+		LiftGalleryLegalHold,
+		SetStackLegalHold,
+		LiftStackLegalHold,
+		SetGalleryVersionLimit,
+		RevertStack,
 	}
 )
 
 // Document routes
 var (
-	LookupShelfByName = route("GET", "/shelfs/lookup/name/{Name}")
-	ShowShelf         = route("GET", "/shelfs/{ShelfID}")
-	UploadDocument    = route("POST", "/shelfs/{ShelfID}/documents")
-	ReplaceDocument   = route("PUT", "/shelfs/{ShelfID}/documents/{DocumentID}")
-	UpdateDocument    = route("PATCH", "/shelfs/{ShelfID}/documents/{DocumentID}")
-	DeleteDocument    = route("DELETE", "/shelfs/{ShelfID}/documents/{DocumentID}")
-	TagDocument       = route("POST", "/shelfs/{ShelfID}/documents/{DocumentID}/tags")
-	UntagDocument     = route("DELETE", "/shelfs/{ShelfID}/documents/{DocumentID}/tags/{Tags}")
+	ListShelfs           = route("GET", "/shelfs")
+	CreateShelf          = route("POST", "/shelfs")
+	LookupShelfByName    = route("GET", "/shelfs/lookup/name/{Name}")
+	ShowShelf            = route("GET", "/shelfs/{ShelfID}")
+	RenameShelf          = route("PATCH", "/shelfs/{ShelfID}")
+	DeleteShelf          = route("DELETE", "/shelfs/{ShelfID}")
+	SearchDocuments      = route("GET", "/search")
+	ShowDocument         = route("GET", "/shelfs/{ShelfID}/documents/{DocumentID}")
+	ShowDocumentContent  = route("GET", "/shelfs/{ShelfID}/documents/{DocumentID}/content")
+	HeadDocumentContent  = route("HEAD", "/shelfs/{ShelfID}/documents/{DocumentID}/content")
+	ShowDocumentPreview  = route("GET", "/shelfs/{ShelfID}/documents/{DocumentID}/preview")
+	UploadDocument       = route("POST", "/shelfs/{ShelfID}/documents")
+	ReplaceDocument      = route("PUT", "/shelfs/{ShelfID}/documents/{DocumentID}")
+	ReplaceDocumentDelta = route("PUT", "/shelfs/{ShelfID}/documents/{DocumentID}/delta")
+	UpdateDocument       = route("PATCH", "/shelfs/{ShelfID}/documents/{DocumentID}")
+	DeleteDocument       = route("DELETE", "/shelfs/{ShelfID}/documents/{DocumentID}")
+	TagDocument          = route("POST", "/shelfs/{ShelfID}/documents/{DocumentID}/tags")
+	UntagDocument        = route("DELETE", "/shelfs/{ShelfID}/documents/{DocumentID}/tags/{Tags}")
+	RetagDocuments       = route("POST", "/shelfs/{ShelfID}/documents/tags")
+	CopyDocument         = route("POST", "/shelfs/{ShelfID}/documents/{DocumentID}/copy")
+	SignDocumentURL      = route("POST", "/shelfs/{ShelfID}/documents/{DocumentID}/signed-url")
+	RestoreDocument      = route("POST", "/shelfs/{ShelfID}/trash/{DocumentID}/restore")
+
+	SetShelfLegalHold     = route("PUT", "/shelfs/{ShelfID}/legal-hold")
+	LiftShelfLegalHold    = route("DELETE", "/shelfs/{ShelfID}/legal-hold")
+	SetDocumentLegalHold  = route("PUT", "/shelfs/{ShelfID}/documents/{DocumentID}/legal-hold")
+	LiftDocumentLegalHold = route("DELETE", "/shelfs/{ShelfID}/documents/{DocumentID}/legal-hold")
 
 	DocumentReadRoutes = [...]Route{
+		ListShelfs,
 		LookupShelfByName,
 		ShowShelf,
+		SearchDocuments,
+		ShowDocument,
+		ShowDocumentContent,
+		HeadDocumentContent,
+		ShowDocumentPreview,
+		SignDocumentURL,
 	}
 
 	DocumentWriteRoutes = [...]Route{
+		CreateShelf,
+		RenameShelf,
+		DeleteShelf,
 		UploadDocument,
 		ReplaceDocument,
+		ReplaceDocumentDelta,
 		UpdateDocument,
 		DeleteDocument,
 		TagDocument,
 		UntagDocument,
+		RetagDocuments,
+		CopyDocument,
+		RestoreDocument,
+		SetShelfLegalHold,
+		LiftShelfLegalHold,
+		SetDocumentLegalHold,
+		LiftDocumentLegalHold,
 	}
 
 	DocumentRoutes = [...]Route{
+		ListShelfs,
 		LookupShelfByName,
 		ShowShelf,
+		SearchDocuments,
+		ShowDocument,
+		ShowDocumentContent,
+		HeadDocumentContent,
+		ShowDocumentPreview,
+		CreateShelf,
+		RenameShelf,
+		DeleteShelf,
 		UploadDocument,
 		ReplaceDocument,
+		ReplaceDocumentDelta,
 		UpdateDocument,
 		DeleteDocument,
 		TagDocument,
 		UntagDocument,
+		RetagDocuments,
+		CopyDocument,
+		SignDocumentURL,
+		RestoreDocument,
+		SetShelfLegalHold,
+		LiftShelfLegalHold,
+		SetDocumentLegalHold,
+		LiftDocumentLegalHold,
+	}
+)
+
+// LegalHoldRoutes are the routes that set and lift legal holds. They are part
+// of the write routes, but applications that restrict legal holds to specific
+// actors can protect them separately:
+//
+//	routes.Protect(routes.LegalHoldRoutes[:]...)
+var LegalHoldRoutes = [...]Route{
+	SetShelfLegalHold,
+	LiftShelfLegalHold,
+	SetDocumentLegalHold,
+	LiftDocumentLegalHold,
+	SetGalleryLegalHold,
+	LiftGalleryLegalHold,
+	SetStackLegalHold,
+	LiftStackLegalHold,
+}
+
+// Health routes
+var (
+	Health = route("GET", "/health")
+)
+
+// Job routes
+var (
+	ExportGallery   = route("POST", "/galleries/{GalleryID}/exports")
+	ExportShelf     = route("POST", "/shelfs/{ShelfID}/exports")
+	ShowJob         = route("GET", "/jobs/{JobID}")
+	CancelJob       = route("DELETE", "/jobs/{JobID}")
+	ShowJobArtifact = route("GET", "/jobs/{JobID}/artifact")
+
+	JobRoutes = [...]Route{
+		ExportGallery,
+		ExportShelf,
+		ShowJob,
+		CancelJob,
+		ShowJobArtifact,
 	}
 )
 
+// Audit routes
+var (
+	ShowAuditLog = route("GET", "/audit")
+)
+
+// Dead-letter routes
+var (
+	ListDeadLetters      = route("GET", "/dead-letters")
+	RedispatchDeadLetter = route("POST", "/dead-letters/{GalleryID}/{StackID}")
+)
+
+// Schema routes
+var (
+	Schemas    = route("GET", "/schemas")
+	ShowSchema = route("GET", "/schemas/{Name}")
+)
+
+// Catalog routes
+var (
+	Catalog = route("GET", "/catalog")
+)
+
+// OpenAPI routes
+var (
+	OpenAPI = route("GET", "/openapi.json")
+)
+
 // Route is a route with a method and path.
 type Route struct {
 	Method string
 	Path   string
 }
 
+// URL returns the URL path of the given Route. The path parameters of the
+// Route are replaced by params in the order they appear in the path:
+//
+//	routes.URL(routes.ShowStack, galleryID, stackID)
+//	// "/galleries/<galleryID>/stacks/<stackID>"
+//
+// Params are formatted using fmt.Sprint and escaped using url.PathEscape. URL
+// panics if the number of params doesn't match the number of path parameters
+// of the Route. Use Routes.URL to build URLs that honor a configured Prefix.
+func URL(route Route, params ...any) string {
+	var b strings.Builder
+	path := route.Path
+	var n int
+	for {
+		start := strings.IndexByte(path, '{')
+		if start < 0 {
+			b.WriteString(path)
+			break
+		}
+		end := strings.IndexByte(path[start:], '}')
+		if end < 0 {
+			b.WriteString(path)
+			break
+		}
+		end += start
+
+		if n >= len(params) {
+			panic(fmt.Sprintf("routes: missing value for %s parameter of %s %s", path[start:end+1], route.Method, route.Path))
+		}
+
+		b.WriteString(path[:start])
+		b.WriteString(url.PathEscape(fmt.Sprint(params[n])))
+		path = path[end+1:]
+		n++
+	}
+
+	if n != len(params) {
+		panic(fmt.Sprintf("routes: %s %s has %d parameters; got %d values", route.Method, route.Path, n, len(params)))
+	}
+
+	return b.String()
+}
+
+// Authorizer authorizes requests to protected routes. Authorize is called with
+// the request and the Route that it matched. If Authorize returns an error, the
+// request is rejected with 403 Forbidden if the error wraps ErrForbidden, or
+// with 401 Unauthorized otherwise. Errors created by api.Friendly are reported
+// with their friendly message.
+type Authorizer interface {
+	Authorize(r *http.Request, route Route) error
+}
+
+// AuthorizerFunc allows a function to be used as an Authorizer.
+type AuthorizerFunc func(*http.Request, Route) error
+
+// Authorize calls fn(r, route).
+func (fn AuthorizerFunc) Authorize(r *http.Request, route Route) error {
+	return fn(r, route)
+}
+
 // Routes configures the routes for one of the media components.
 type Routes struct {
+	prefix     string
 	disabled   []Route
+	protected  []Route
+	authorizer Authorizer
 	middleware map[Route][]func(http.Handler) http.Handler
+	cache      map[Route]string
 }
 
 // Option is a Routes option.
 type Option func(*Routes)
 
+// Prefix returns an Option that installs the routes under the given path
+// prefix (e.g. "/media").
+func Prefix(prefix string) Option {
+	return func(r *Routes) {
+		if prefix = strings.Trim(prefix, "/"); prefix != "" {
+			r.prefix = "/" + prefix
+		} else {
+			r.prefix = ""
+		}
+	}
+}
+
 // Disable disables the provided routes.
 func Disable(routes ...Route) Option {
 	return func(r *Routes) {
@@ -136,9 +443,54 @@ func Middlewares(middlewares []func(http.Handler) http.Handler, routes ...Route)
 	}
 }
 
+// Protect protects the given routes. Requests to protected routes must be
+// authorized by the Authorizer that is configured using the Authorize Option.
+// If no Authorizer is configured, requests to protected routes are rejected.
+// Use All to protect every route:
+//
+//	routes.New(
+//		routes.Authorize(myAuthorizer),
+//		routes.Protect(routes.GalleryWriteRoutes[:]...),
+//	)
+func Protect(routes ...Route) Option {
+	return func(r *Routes) {
+		r.protected = append(r.protected, routes...)
+	}
+}
+
+// Authorize returns an Option that authorizes requests to protected routes
+// using the provided Authorizer. The Authorizer runs after the middleware of
+// the route, so middleware can authenticate the request before it is
+// authorized.
+func Authorize(a Authorizer) Option {
+	return func(r *Routes) {
+		r.authorizer = a
+	}
+}
+
+// CacheControl returns an Option that sets the Cache-Control header of
+// successful responses of the given routes to value. If routes is empty, the
+// header is set for all routes. Only routes that support caching (e.g. the
+// routes that respond with an ETag) set the header:
+//
+//	routes.New(routes.CacheControl("public, max-age=60", routes.ShowGallery))
+func CacheControl(value string, routes ...Route) Option {
+	if len(routes) == 0 {
+		routes = []Route{All}
+	}
+	return func(r *Routes) {
+		for _, route := range routes {
+			r.cache[route] = value
+		}
+	}
+}
+
 // New returns a route configuration.
 func New(opts ...Option) Routes {
-	r := Routes{middleware: make(map[Route][]func(http.Handler) http.Handler)}
+	r := Routes{
+		middleware: make(map[Route][]func(http.Handler) http.Handler),
+		cache:      make(map[Route]string),
+	}
 	for _, opt := range opts {
 		opt(&r)
 	}
@@ -155,17 +507,95 @@ func (r Routes) Disabled(route Route) bool {
 	return false
 }
 
+// Protected returns whether the given Route is protected.
+func (r Routes) Protected(route Route) bool {
+	for _, p := range r.protected {
+		if route == p || p == All {
+			return true
+		}
+	}
+	return false
+}
+
 // Middleware returns the middleare for the given Route.
 func (r Routes) Middleware(route Route) []func(http.Handler) http.Handler {
-	return append(r.middleware[All], r.middleware[route]...)
+	middleware := make([]func(http.Handler) http.Handler, 0, len(r.middleware[All])+len(r.middleware[route]))
+	middleware = append(middleware, r.middleware[All]...)
+	return append(middleware, r.middleware[route]...)
+}
+
+// CacheControl returns the Cache-Control header value for the given Route, or
+// an empty string if no value was configured. A value configured for the
+// Route takes precedence over a value configured for All.
+func (r Routes) CacheControl(route Route) string {
+	if v, ok := r.cache[route]; ok {
+		return v
+	}
+	return r.cache[All]
+}
+
+// Path returns the path of the given Route with the configured Prefix.
+func (r Routes) Path(route Route) string {
+	return r.prefix + route.Path
+}
+
+// URL returns the URL path of the given Route with the configured Prefix. See
+// the URL function for how params are applied.
+func (r Routes) URL(route Route, params ...any) string {
+	return r.prefix + URL(route, params...)
 }
 
 // Install installs the routes in the given Router, using the provided Handler,
-// but only if the Route wasn't disabled.
+// but only if the Route wasn't disabled. The Route is installed under the
+// configured Prefix. Protected routes are authorized before h is called.
 func (r Routes) Install(router chi.Router, route Route, h http.Handler) {
-	if !r.Disabled(route) {
-		router.With(r.Middleware(route)...).Method(route.Method, route.Path, h)
+	if r.Disabled(route) {
+		return
+	}
+	middleware := r.Middleware(route)
+	if r.Protected(route) {
+		middleware = append(middleware, r.authorize(route))
+	}
+	router.With(middleware...).Method(route.Method, r.Path(route), h)
+}
+
+// Require authorizes a request to the given Route, even if the Route is not
+// protected. Handlers use Require to protect resources conditionally, e.g. the
+// original images of galleries that protect their originals. If the request is
+// rejected, an error response is written and false is returned. Requests to
+// protected routes have already been authorized by Install, so Require returns
+// true for them.
+func (r Routes) Require(w http.ResponseWriter, req *http.Request, route Route) bool {
+	if r.Protected(route) {
+		return true
+	}
+	return r.check(w, req, route)
+}
+
+func (r Routes) authorize(route Route) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if r.check(w, req, route) {
+				next.ServeHTTP(w, req)
+			}
+		})
+	}
+}
+
+func (r Routes) check(w http.ResponseWriter, req *http.Request, route Route) bool {
+	if r.authorizer == nil {
+		api.Error(w, req, http.StatusUnauthorized, api.Friendly(ErrUnauthorized, "Unauthorized."))
+		return false
+	}
+	if err := r.authorizer.Authorize(req, route); err != nil {
+		status := http.StatusUnauthorized
+		if errors.Is(err, ErrForbidden) {
+			status = http.StatusForbidden
+		}
+		api.Error(w, req, status, err)
+		return false
 	}
+	return true
 }
 
 func route(method, path string) Route {
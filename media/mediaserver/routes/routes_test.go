@@ -0,0 +1,210 @@
+package routes_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/media/mediaserver/routes"
+)
+
+func TestURL(t *testing.T) {
+	galleryID := uuid.New()
+	stackID := uuid.New()
+
+	tests := []struct {
+		route  routes.Route
+		params []any
+		want   string
+	}{
+		{route: routes.Health, want: "/health"},
+		{route: routes.ShowGallery, params: []any{galleryID}, want: "/galleries/" + galleryID.String()},
+		{
+			route:  routes.ShowStack,
+			params: []any{galleryID, stackID},
+			want:   "/galleries/" + galleryID.String() + "/stacks/" + stackID.String(),
+		},
+		{route: routes.LookupGalleryByName, params: []any{"foo bar/baz"}, want: "/galleries/lookup/name/foo%20bar%2Fbaz"},
+	}
+
+	for _, tt := range tests {
+		if got := routes.URL(tt.route, tt.params...); got != tt.want {
+			t.Errorf("URL(%s %s) should return %q; got %q", tt.route.Method, tt.route.Path, tt.want, got)
+		}
+	}
+}
+
+func TestURL_paramMismatch(t *testing.T) {
+	tests := [][]any{
+		{uuid.New()},
+		{uuid.New(), uuid.New(), uuid.New()},
+	}
+
+	for _, params := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("URL should panic for %d params", len(params))
+				}
+			}()
+			routes.URL(routes.ShowStack, params...)
+		}()
+	}
+}
+
+func TestRoutes_URL(t *testing.T) {
+	galleryID := uuid.New()
+	want := "/media/galleries/" + galleryID.String()
+
+	for _, prefix := range []string{"media", "/media", "/media/"} {
+		r := routes.New(routes.Prefix(prefix))
+		if got := r.URL(routes.ShowGallery, galleryID); got != want {
+			t.Errorf("URL with prefix %q should return %q; got %q", prefix, want, got)
+		}
+	}
+}
+
+func TestRoutes_CacheControl(t *testing.T) {
+	r := routes.New(
+		routes.CacheControl("no-cache"),
+		routes.CacheControl("public, max-age=60", routes.ShowGallery),
+	)
+
+	if got := r.CacheControl(routes.ShowGallery); got != "public, max-age=60" {
+		t.Errorf("CacheControl should return the value of the route; got %q", got)
+	}
+
+	if got := r.CacheControl(routes.ShowShelf); got != "no-cache" {
+		t.Errorf("CacheControl should fall back to the value for all routes; got %q", got)
+	}
+
+	if got := routes.New().CacheControl(routes.ShowGallery); got != "" {
+		t.Errorf("CacheControl should return an empty string if no value is configured; got %q", got)
+	}
+}
+
+func TestRoutes_Install_prefix(t *testing.T) {
+	router := chi.NewRouter()
+	r := routes.New(routes.Prefix("/media"))
+	r.Install(router, routes.Health, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, r.URL(routes.Health), nil))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("route should be installed under the prefix; status is %d", rec.Code)
+	}
+}
+
+func TestRoutes_Install_protected(t *testing.T) {
+	type ctxKey struct{}
+
+	authorizer := routes.AuthorizerFunc(func(r *http.Request, route routes.Route) error {
+		if route != routes.UploadImage {
+			return fmt.Errorf("unexpected route %s %s", route.Method, route.Path)
+		}
+		switch r.Context().Value(ctxKey{}) {
+		case nil:
+			return routes.ErrUnauthorized
+		case "admin":
+			return nil
+		default:
+			return fmt.Errorf("upload images: %w", routes.ErrForbidden)
+		}
+	})
+
+	authenticate := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if user := r.Header.Get("X-User"); user != "" {
+				r = r.WithContext(context.WithValue(r.Context(), ctxKey{}, user))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	router := chi.NewRouter()
+	r := routes.New(
+		routes.Middleware(authenticate),
+		routes.Authorize(authorizer),
+		routes.Protect(routes.UploadImage),
+	)
+	noContent := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	r.Install(router, routes.UploadImage, noContent)
+	r.Install(router, routes.ShowGallery, noContent)
+
+	tests := []struct {
+		route routes.Route
+		user  string
+		want  int
+	}{
+		{route: routes.UploadImage, want: http.StatusUnauthorized},
+		{route: routes.UploadImage, user: "guest", want: http.StatusForbidden},
+		{route: routes.UploadImage, user: "admin", want: http.StatusNoContent},
+		{route: routes.ShowGallery, want: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.route.Method, routes.URL(tt.route, uuid.New()), nil)
+		req.Header.Set("X-User", tt.user)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != tt.want {
+			t.Errorf("%s %s as %q should respond with %d; got %d", tt.route.Method, tt.route.Path, tt.user, tt.want, rec.Code)
+		}
+	}
+}
+
+func TestRoutes_Install_protectedWithoutAuthorizer(t *testing.T) {
+	router := chi.NewRouter()
+	r := routes.New(routes.Protect(routes.All))
+	r.Install(router, routes.Health, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, routes.URL(routes.Health), nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("protected routes without an Authorizer should respond with %d; got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestRoutes_Require(t *testing.T) {
+	authorizer := routes.AuthorizerFunc(func(r *http.Request, route routes.Route) error {
+		if r.Header.Get("X-User") == "" {
+			return routes.ErrUnauthorized
+		}
+		return nil
+	})
+
+	r := routes.New(routes.Authorize(authorizer), routes.Protect(routes.UploadImage))
+
+	require := func(route routes.Route, user string) (bool, int) {
+		req := httptest.NewRequest(route.Method, route.Path, nil)
+		req.Header.Set("X-User", user)
+		rec := httptest.NewRecorder()
+		ok := r.Require(rec, req, route)
+		return ok, rec.Code
+	}
+
+	if ok, code := require(routes.ShowStackOriginal, ""); ok || code != http.StatusUnauthorized {
+		t.Errorf("Require should reject unauthorized requests with %d; got %v (%d)", http.StatusUnauthorized, ok, code)
+	}
+
+	if ok, _ := require(routes.ShowStackOriginal, "admin"); !ok {
+		t.Errorf("Require should accept authorized requests")
+	}
+
+	if ok, _ := require(routes.UploadImage, ""); !ok {
+		t.Errorf("Require should accept requests to protected routes, which are authorized by Install")
+	}
+}
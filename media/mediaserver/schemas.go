@@ -0,0 +1,237 @@
+package mediaserver
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/internal/api"
+	"github.com/modernice/nice-cms/media/mediaserver/routes"
+	"github.com/modernice/nice-cms/schema"
+)
+
+type createRequest struct {
+	Name string `json:"name" schema:"required,minLength=1"`
+}
+
+type updateDocumentRequest struct {
+	Name       string             `json:"name" schema:"required"`
+	UniqueName *string            `json:"uniqueName"`
+	Metadata   map[string]*string `json:"metadata"`
+}
+
+type signedURLRequest struct {
+	Expiry int    `json:"expiry" schema:"minimum=1,maximum=604800"`
+	Locale string `json:"locale"`
+}
+
+type signStackURLRequest struct {
+	Expiry   int    `json:"expiry" schema:"minimum=1,maximum=604800"`
+	Size     string `json:"size"`
+	Format   string `json:"format"`
+	Original bool   `json:"original"`
+}
+
+type focalPointRequest struct {
+	X *float64 `json:"x" schema:"required,minimum=0,maximum=100"`
+	Y *float64 `json:"y" schema:"required,minimum=0,maximum=100"`
+}
+
+type cropRequest struct {
+	X      *float64 `json:"x" schema:"required,minimum=0,maximum=100"`
+	Y      *float64 `json:"y" schema:"required,minimum=0,maximum=100"`
+	Width  *float64 `json:"width" schema:"required,minimum=0,maximum=100"`
+	Height *float64 `json:"height" schema:"required,minimum=0,maximum=100"`
+}
+
+type rotateStackRequest struct {
+	Degrees *int `json:"degrees" schema:"required"`
+}
+
+type flipStackRequest struct {
+	Direction string `json:"direction" schema:"required,enum=horizontal|vertical"`
+}
+
+type originalsProtectionRequest struct {
+	Protected *bool `json:"protected" schema:"required"`
+}
+
+type versionLimitRequest struct {
+	Limit *int `json:"limit" schema:"required,minimum=0"`
+}
+
+type revertStackRequest struct {
+	Version *int `json:"version" schema:"required,minimum=0"`
+}
+
+type legalHoldRequest struct {
+	Reason string `json:"reason"`
+}
+
+type updateStackRequest struct {
+	Name           string             `json:"name"`
+	Alt            *string            `json:"alt"`
+	Caption        *string            `json:"caption"`
+	CustomMetadata map[string]*string `json:"customMetadata"`
+}
+
+type tagRequest struct {
+	Tags []string `json:"tags" schema:"required,minItems=1"`
+}
+
+type retagRequest struct {
+	DocumentIDs []uuid.UUID `json:"documentIds" schema:"required,minItems=1,maxItems=1000"`
+	Add         []string    `json:"add"`
+	Remove      []string    `json:"remove"`
+}
+
+type copyDocumentRequest struct {
+	ShelfID uuid.UUID `json:"shelfId" schema:"required"`
+	Disk    string    `json:"disk"`
+	Path    string    `json:"path"`
+}
+
+type defaultTagsRequest struct {
+	Tags []string `json:"tags" schema:"required"`
+}
+
+type sortGalleryRequest struct {
+	Sorting []uuid.UUID `json:"sorting" schema:"required"`
+}
+
+type moveStackRequest struct {
+	Before *uuid.UUID `json:"before"`
+}
+
+// Schemas returns the JSON Schemas of the request bodies of the mutating media
+// routes, keyed by resource name.
+func Schemas() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"shelf.create": schema.Of(createRequest{},
+			schema.Title("Create shelf"),
+			schema.Description("Body of POST /shelfs. The name must not be used by another shelf."),
+		),
+		"gallery.create": schema.Of(createRequest{},
+			schema.Title("Create gallery"),
+			schema.Description("Body of POST /galleries. The name must not be used by another gallery."),
+		),
+		"shelf.rename": schema.Of(createRequest{},
+			schema.Title("Rename shelf"),
+			schema.Description("Body of PATCH /shelfs/{ShelfID}. The name must not be used by another shelf."),
+		),
+		"gallery.rename": schema.Of(createRequest{},
+			schema.Title("Rename gallery"),
+			schema.Description("Body of PATCH /galleries/{GalleryID}. The name must not be used by another gallery."),
+		),
+		"document.update": schema.Of(updateDocumentRequest{},
+			schema.Title("Update document"),
+			schema.Description("Body of PATCH /shelfs/{ShelfID}/documents/{DocumentID}. An empty uniqueName makes the document non-unique. Metadata fields are added or overwritten; null values remove them."),
+		),
+		"document.tags": schema.Of(tagRequest{},
+			schema.Title("Tag document"),
+			schema.Description("Body of POST /shelfs/{ShelfID}/documents/{DocumentID}/tags."),
+		),
+		"document.retag": schema.Of(retagRequest{},
+			schema.Title("Retag documents"),
+			schema.Description("Body of POST /shelfs/{ShelfID}/documents/tags. The tags in add are added to and the tags in remove are removed from every listed document of the shelf. Either add or remove must contain at least one tag."),
+		),
+		"document.copy": schema.Of(copyDocumentRequest{},
+			schema.Title("Copy document"),
+			schema.Description("Body of POST /shelfs/{ShelfID}/documents/{DocumentID}/copy. The document and its file are copied to the shelf with the given UUID, optionally to another disk and path. The copy keeps the name, tags and metadata of the document and gets a new UUID."),
+		),
+		"legalHold": schema.Of(legalHoldRequest{},
+			schema.Title("Set legal hold"),
+			schema.Description("Body of the PUT legal-hold routes of shelfs, documents, galleries and stacks. Held resources cannot be deleted until the hold is lifted."),
+		),
+		"document.signedURL": schema.Of(signedURLRequest{},
+			schema.Title("Sign document URL"),
+			schema.Description("Body of POST /shelfs/{ShelfID}/documents/{DocumentID}/signed-url. The expiry is given in seconds and defaults to 15 minutes. The locale may contain multiple, comma-separated locales in order of preference."),
+		),
+		"stack.update": schema.Of(updateStackRequest{},
+			schema.Title("Update stack"),
+			schema.Description("Body of PATCH /galleries/{GalleryID}/stacks/{StackID}. An empty name leaves the name unchanged. Omitted alt and caption fields are left unchanged; empty ones are removed. CustomMetadata fields are added or overwritten; null values remove them."),
+		),
+		"stack.tags": schema.Of(tagRequest{},
+			schema.Title("Tag stack"),
+			schema.Description("Body of POST /galleries/{GalleryID}/stacks/{StackID}/tags."),
+		),
+		"gallery.sorting": schema.Of(sortGalleryRequest{},
+			schema.Title("Sort gallery"),
+			schema.Description("Body of PATCH /galleries/{GalleryID}/sorting."),
+		),
+		"stack.move": schema.Of(moveStackRequest{},
+			schema.Title("Move stack"),
+			schema.Description("Body of POST /galleries/{GalleryID}/stacks/{StackID}/move. The stack is moved in front of the stack with the given UUID, or to the end of the gallery if before is omitted."),
+		),
+		"stack.signedURL": schema.Of(signStackURLRequest{},
+			schema.Title("Sign stack URL"),
+			schema.Description("Body of POST /galleries/{GalleryID}/stacks/{StackID}/signed-url. The expiry is given in seconds and defaults to 15 minutes. The image is selected using size and format like the content route; original selects the original image, which requires authorization if the gallery protects its originals."),
+		),
+		"stack.focalPoint": schema.Of(focalPointRequest{},
+			schema.Title("Set stack focal point"),
+			schema.Description("Body of PUT /galleries/{GalleryID}/stacks/{StackID}/focal-point. x and y are percentages of the width and height of the image, measured from the top-left corner."),
+		),
+		"stack.crop": schema.Of(cropRequest{},
+			schema.Title("Crop stack"),
+			schema.Description("Body of POST /galleries/{GalleryID}/stacks/{StackID}/crop. The rectangle is given in percentages of the width and height of the original image, measured from the top-left corner. A rectangle of the full image removes the crop."),
+		),
+		"stack.rotate": schema.Of(rotateStackRequest{},
+			schema.Title("Rotate stack"),
+			schema.Description("Body of POST /galleries/{GalleryID}/stacks/{StackID}/rotate. The original image is rotated clockwise by the given degrees, which must be a multiple of 90. Negative degrees rotate counter-clockwise."),
+		),
+		"stack.flip": schema.Of(flipStackRequest{},
+			schema.Title("Flip stack"),
+			schema.Description("Body of POST /galleries/{GalleryID}/stacks/{StackID}/flip. The original image is mirrored horizontally (left and right) or vertically (top and bottom)."),
+		),
+		"gallery.originalsProtection": schema.Of(originalsProtectionRequest{},
+			schema.Title("Protect gallery originals"),
+			schema.Description("Body of PUT /galleries/{GalleryID}/originals-protection. The original images of a protected gallery are only served to authorized clients."),
+		),
+		"gallery.versioning": schema.Of(versionLimitRequest{},
+			schema.Title("Set version limit"),
+			schema.Description("Body of PUT /galleries/{GalleryID}/versioning. The limit is the number of previous versions that are kept for each stack when it is replaced; 0 disables versioning."),
+		),
+		"stack.revert": schema.Of(revertStackRequest{},
+			schema.Title("Revert stack"),
+			schema.Description("Body of POST /galleries/{GalleryID}/stacks/{StackID}/revert. The stack is reverted to the given previous version."),
+		),
+		"gallery.defaultTags": schema.Of(defaultTagsRequest{},
+			schema.Title("Set default tags"),
+			schema.Description("Body of PUT /galleries/{GalleryID}/default-tags. The default tags are added to every image that is uploaded to the gallery."),
+		),
+	}
+}
+
+// WithSchemas returns an Option that adds the schema routes to the media
+// server. The schema routes serve the JSON Schemas returned by Schemas and the
+// provided additional schemas, so that generic admin UIs can build forms for
+// the resources without hardcoding their shapes:
+//
+//	mediaserver.WithSchemas(map[string]*schema.Schema{
+//		"nav.item":   nav.ItemSchema(),
+//		"page.field": field.FieldSchema(),
+//	})
+func WithSchemas(extra map[string]*schema.Schema, opts ...routes.Option) Option {
+	schemas := Schemas()
+	for name, s := range extra {
+		schemas[name] = s
+	}
+
+	return func(s *Server) {
+		r := routes.New(opts...)
+
+		r.Install(s.router, routes.Schemas, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			api.JSON(w, r, http.StatusOK, schemas)
+		}))
+
+		r.Install(s.router, routes.ShowSchema, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name := chi.URLParam(r, "Name")
+			sch, ok := schemas[name]
+			if !ok {
+				api.Error(w, r, http.StatusNotFound, api.Friendly(nil, "Schema %q not found.", name))
+				return
+			}
+			api.JSON(w, r, http.StatusOK, sch)
+		}))
+	}
+}
@@ -0,0 +1,140 @@
+package mediaserver
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+)
+
+// maxFieldSize is the maximum size of a non-file field of a multipart upload.
+const maxFieldSize = 64 << 10
+
+var (
+	errMissingFile = errors.New("missing file")
+	errFieldSize   = errors.New("field too large")
+)
+
+// multipartUpload is a multipart file upload that is read directly from the
+// request body instead of being parsed into memory by http.Request.FormFile.
+type multipartUpload struct {
+	io.Reader
+
+	fields map[string]string
+	tmp    *os.File
+}
+
+// readMultipartUpload reads the multipart form of r until it reaches the file
+// part with the given field name. If all of the given fields have been read
+// before the file, the returned upload streams the file directly from the
+// request body. Otherwise, the file is written to a temporary file until the
+// remaining fields have been read. Clients should therefore send the fields
+// before the file.
+//
+// The caller must read the file before reading any other part of the request
+// and must call Close when done.
+func readMultipartUpload(r *http.Request, fileField string, fields ...string) (*multipartUpload, error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	upload := multipartUpload{fields: make(map[string]string)}
+
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			upload.Close()
+			return nil, err
+		}
+
+		if part.FormName() == fileField && part.FileName() != "" {
+			if upload.Reader != nil {
+				continue
+			}
+
+			if upload.hasFields(fields) {
+				upload.Reader = part
+				return &upload, nil
+			}
+
+			if err := upload.spool(part); err != nil {
+				upload.Close()
+				return nil, fmt.Errorf("buffer file: %w", err)
+			}
+			continue
+		}
+
+		if err := upload.readField(part); err != nil {
+			upload.Close()
+			return nil, err
+		}
+	}
+
+	if upload.Reader == nil {
+		return nil, fmt.Errorf("%w: %q", errMissingFile, fileField)
+	}
+
+	return &upload, nil
+}
+
+// Value returns the value of the given field.
+func (u *multipartUpload) Value(field string) string {
+	return u.fields[field]
+}
+
+// Close removes the temporary file of the upload, if any.
+func (u *multipartUpload) Close() error {
+	if u.tmp == nil {
+		return nil
+	}
+	u.tmp.Close()
+	return os.Remove(u.tmp.Name())
+}
+
+func (u *multipartUpload) hasFields(fields []string) bool {
+	for _, field := range fields {
+		if _, ok := u.fields[field]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (u *multipartUpload) readField(part *multipart.Part) error {
+	b, err := io.ReadAll(io.LimitReader(part, maxFieldSize+1))
+	if err != nil {
+		return fmt.Errorf("read %q field: %w", part.FormName(), err)
+	}
+	if len(b) > maxFieldSize {
+		return fmt.Errorf("%w: %q", errFieldSize, part.FormName())
+	}
+	if _, ok := u.fields[part.FormName()]; !ok {
+		u.fields[part.FormName()] = string(b)
+	}
+	return nil
+}
+
+func (u *multipartUpload) spool(part *multipart.Part) error {
+	tmp, err := os.CreateTemp("", "nice-cms-upload-*")
+	if err != nil {
+		return err
+	}
+	u.tmp = tmp
+
+	if _, err := io.Copy(tmp, part); err != nil {
+		return err
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	u.Reader = tmp
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package media
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrEmptyMetadataKey is returned when providing custom metadata with a key
+// that is empty or contains only whitespace.
+var ErrEmptyMetadataKey = errors.New("empty metadata key")
+
+// NormalizeMetadata returns a copy of the custom metadata fields with trimmed
+// keys and values. If a key is empty, ErrEmptyMetadataKey is returned.
+func NormalizeMetadata(fields map[string]string) (map[string]string, error) {
+	out := make(map[string]string, len(fields))
+	for key, val := range fields {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, ErrEmptyMetadataKey
+		}
+		out[key] = strings.TrimSpace(val)
+	}
+	return out, nil
+}
+
+// NormalizeMetadataKeys returns the trimmed, non-empty keys of custom metadata.
+func NormalizeMetadataKeys(keys ...string) []string {
+	out := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			out = append(out, key)
+		}
+	}
+	return out
+}
+
+// WithMetadata returns a copy of the custom metadata m with the given fields
+// added or overwritten.
+func WithMetadata(m, fields map[string]string) map[string]string {
+	out := make(map[string]string, len(m)+len(fields))
+	for key, val := range m {
+		out[key] = val
+	}
+	for key, val := range fields {
+		out[key] = val
+	}
+	return out
+}
+
+// WithoutMetadata returns a copy of the custom metadata m without the given
+// keys. If no fields remain, nil is returned.
+func WithoutMetadata(m map[string]string, keys ...string) map[string]string {
+	out := make(map[string]string, len(m))
+	for key, val := range m {
+		out[key] = val
+	}
+	for _, key := range keys {
+		delete(out, key)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// MetadataChanged returns whether setting fields and deleting keys would
+// change the custom metadata m.
+func MetadataChanged(m, fields map[string]string, keys ...string) bool {
+	for key, val := range fields {
+		if cur, ok := m[key]; !ok || cur != val {
+			return true
+		}
+	}
+	for _, key := range keys {
+		if _, ok := m[key]; ok {
+			return true
+		}
+	}
+	return false
+}
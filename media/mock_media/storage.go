@@ -6,6 +6,7 @@ package mock_media
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -115,3 +116,450 @@ func (mr *MockStorageDiskMockRecorder) Put(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockStorageDisk)(nil).Put), arg0, arg1, arg2)
 }
+
+// MockRangeDisk is a mock of RangeDisk interface.
+type MockRangeDisk struct {
+	ctrl     *gomock.Controller
+	recorder *MockRangeDiskMockRecorder
+}
+
+// MockRangeDiskMockRecorder is the mock recorder for MockRangeDisk.
+type MockRangeDiskMockRecorder struct {
+	mock *MockRangeDisk
+}
+
+// NewMockRangeDisk creates a new mock instance.
+func NewMockRangeDisk(ctrl *gomock.Controller) *MockRangeDisk {
+	mock := &MockRangeDisk{ctrl: ctrl}
+	mock.recorder = &MockRangeDiskMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRangeDisk) EXPECT() *MockRangeDiskMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockRangeDisk) Delete(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRangeDiskMockRecorder) Delete(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRangeDisk)(nil).Delete), arg0, arg1)
+}
+
+// Get mocks base method.
+func (m *MockRangeDisk) Get(arg0 context.Context, arg1 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRangeDiskMockRecorder) Get(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRangeDisk)(nil).Get), arg0, arg1)
+}
+
+// GetRange mocks base method.
+func (m *MockRangeDisk) GetRange(arg0 context.Context, path string, off, length int64) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRange", arg0, path, off, length)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRange indicates an expected call of GetRange.
+func (mr *MockRangeDiskMockRecorder) GetRange(arg0, path, off, length any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRange", reflect.TypeOf((*MockRangeDisk)(nil).GetRange), arg0, path, off, length)
+}
+
+// GetRangeReader mocks base method.
+func (m *MockRangeDisk) GetRangeReader(arg0 context.Context, path string, off, length int64) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRangeReader", arg0, path, off, length)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRangeReader indicates an expected call of GetRangeReader.
+func (mr *MockRangeDiskMockRecorder) GetRangeReader(arg0, path, off, length any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRangeReader", reflect.TypeOf((*MockRangeDisk)(nil).GetRangeReader), arg0, path, off, length)
+}
+
+// Put mocks base method.
+func (m *MockRangeDisk) Put(arg0 context.Context, arg1 string, arg2 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockRangeDiskMockRecorder) Put(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockRangeDisk)(nil).Put), arg0, arg1, arg2)
+}
+
+// MockStreamDisk is a mock of StreamDisk interface.
+type MockStreamDisk struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamDiskMockRecorder
+}
+
+// MockStreamDiskMockRecorder is the mock recorder for MockStreamDisk.
+type MockStreamDiskMockRecorder struct {
+	mock *MockStreamDisk
+}
+
+// NewMockStreamDisk creates a new mock instance.
+func NewMockStreamDisk(ctrl *gomock.Controller) *MockStreamDisk {
+	mock := &MockStreamDisk{ctrl: ctrl}
+	mock.recorder = &MockStreamDiskMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamDisk) EXPECT() *MockStreamDiskMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockStreamDisk) Delete(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockStreamDiskMockRecorder) Delete(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStreamDisk)(nil).Delete), arg0, arg1)
+}
+
+// Get mocks base method.
+func (m *MockStreamDisk) Get(arg0 context.Context, arg1 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockStreamDiskMockRecorder) Get(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockStreamDisk)(nil).Get), arg0, arg1)
+}
+
+// GetReader mocks base method.
+func (m *MockStreamDisk) GetReader(arg0 context.Context, path string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReader", arg0, path)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReader indicates an expected call of GetReader.
+func (mr *MockStreamDiskMockRecorder) GetReader(arg0, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReader", reflect.TypeOf((*MockStreamDisk)(nil).GetReader), arg0, path)
+}
+
+// Put mocks base method.
+func (m *MockStreamDisk) Put(arg0 context.Context, arg1 string, arg2 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockStreamDiskMockRecorder) Put(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockStreamDisk)(nil).Put), arg0, arg1, arg2)
+}
+
+// PutReader mocks base method.
+func (m *MockStreamDisk) PutReader(arg0 context.Context, path string, r io.Reader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutReader", arg0, path, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PutReader indicates an expected call of PutReader.
+func (mr *MockStreamDiskMockRecorder) PutReader(arg0, path, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutReader", reflect.TypeOf((*MockStreamDisk)(nil).PutReader), arg0, path, r)
+}
+
+// MockCopyDisk is a mock of CopyDisk interface.
+type MockCopyDisk struct {
+	ctrl     *gomock.Controller
+	recorder *MockCopyDiskMockRecorder
+}
+
+// MockCopyDiskMockRecorder is the mock recorder for MockCopyDisk.
+type MockCopyDiskMockRecorder struct {
+	mock *MockCopyDisk
+}
+
+// NewMockCopyDisk creates a new mock instance.
+func NewMockCopyDisk(ctrl *gomock.Controller) *MockCopyDisk {
+	mock := &MockCopyDisk{ctrl: ctrl}
+	mock.recorder = &MockCopyDiskMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCopyDisk) EXPECT() *MockCopyDiskMockRecorder {
+	return m.recorder
+}
+
+// Copy mocks base method.
+func (m *MockCopyDisk) Copy(arg0 context.Context, src, dst string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Copy", arg0, src, dst)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Copy indicates an expected call of Copy.
+func (mr *MockCopyDiskMockRecorder) Copy(arg0, src, dst any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Copy", reflect.TypeOf((*MockCopyDisk)(nil).Copy), arg0, src, dst)
+}
+
+// Delete mocks base method.
+func (m *MockCopyDisk) Delete(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCopyDiskMockRecorder) Delete(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCopyDisk)(nil).Delete), arg0, arg1)
+}
+
+// Get mocks base method.
+func (m *MockCopyDisk) Get(arg0 context.Context, arg1 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCopyDiskMockRecorder) Get(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCopyDisk)(nil).Get), arg0, arg1)
+}
+
+// Move mocks base method.
+func (m *MockCopyDisk) Move(arg0 context.Context, src, dst string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", arg0, src, dst)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockCopyDiskMockRecorder) Move(arg0, src, dst any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockCopyDisk)(nil).Move), arg0, src, dst)
+}
+
+// Put mocks base method.
+func (m *MockCopyDisk) Put(arg0 context.Context, arg1 string, arg2 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockCopyDiskMockRecorder) Put(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockCopyDisk)(nil).Put), arg0, arg1, arg2)
+}
+
+// MockListDisk is a mock of ListDisk interface.
+type MockListDisk struct {
+	ctrl     *gomock.Controller
+	recorder *MockListDiskMockRecorder
+}
+
+// MockListDiskMockRecorder is the mock recorder for MockListDisk.
+type MockListDiskMockRecorder struct {
+	mock *MockListDisk
+}
+
+// NewMockListDisk creates a new mock instance.
+func NewMockListDisk(ctrl *gomock.Controller) *MockListDisk {
+	mock := &MockListDisk{ctrl: ctrl}
+	mock.recorder = &MockListDiskMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockListDisk) EXPECT() *MockListDiskMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockListDisk) Delete(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockListDiskMockRecorder) Delete(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockListDisk)(nil).Delete), arg0, arg1)
+}
+
+// Get mocks base method.
+func (m *MockListDisk) Get(arg0 context.Context, arg1 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockListDiskMockRecorder) Get(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockListDisk)(nil).Get), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockListDisk) List(arg0 context.Context, prefix string) ([]media.FileInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, prefix)
+	ret0, _ := ret[0].([]media.FileInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockListDiskMockRecorder) List(arg0, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockListDisk)(nil).List), arg0, prefix)
+}
+
+// Put mocks base method.
+func (m *MockListDisk) Put(arg0 context.Context, arg1 string, arg2 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockListDiskMockRecorder) Put(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockListDisk)(nil).Put), arg0, arg1, arg2)
+}
+
+// MockStatDisk is a mock of StatDisk interface.
+type MockStatDisk struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatDiskMockRecorder
+}
+
+// MockStatDiskMockRecorder is the mock recorder for MockStatDisk.
+type MockStatDiskMockRecorder struct {
+	mock *MockStatDisk
+}
+
+// NewMockStatDisk creates a new mock instance.
+func NewMockStatDisk(ctrl *gomock.Controller) *MockStatDisk {
+	mock := &MockStatDisk{ctrl: ctrl}
+	mock.recorder = &MockStatDiskMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatDisk) EXPECT() *MockStatDiskMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockStatDisk) Delete(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockStatDiskMockRecorder) Delete(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStatDisk)(nil).Delete), arg0, arg1)
+}
+
+// Get mocks base method.
+func (m *MockStatDisk) Get(arg0 context.Context, arg1 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockStatDiskMockRecorder) Get(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockStatDisk)(nil).Get), arg0, arg1)
+}
+
+// Put mocks base method.
+func (m *MockStatDisk) Put(arg0 context.Context, arg1 string, arg2 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockStatDiskMockRecorder) Put(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockStatDisk)(nil).Put), arg0, arg1, arg2)
+}
+
+// Stat mocks base method.
+func (m *MockStatDisk) Stat(arg0 context.Context, path string) (media.FileInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stat", arg0, path)
+	ret0, _ := ret[0].(media.FileInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stat indicates an expected call of Stat.
+func (mr *MockStatDiskMockRecorder) Stat(arg0, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stat", reflect.TypeOf((*MockStatDisk)(nil).Stat), arg0, path)
+}
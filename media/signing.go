@@ -0,0 +1,406 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	stdpath "path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxURLExpiry is the maximum expiry of signed URLs. It is the limit of S3
+// presigned URLs.
+const MaxURLExpiry = 7 * 24 * time.Hour
+
+var (
+	// ErrSigningUnsupported is returned when signing a URL for a file on a
+	// StorageDisk that doesn't implement URLSigner.
+	ErrSigningUnsupported = errors.New("disk does not support signed urls")
+
+	// ErrInvalidSignature is returned when verifying a signed URL with an
+	// invalid signature.
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	// ErrSignatureExpired is returned when verifying an expired signed URL.
+	ErrSignatureExpired = errors.New("signature expired")
+)
+
+// URLSigner signs time-limited download URLs for files. Use SignURL to sign a
+// URL for a file on any StorageDisk.
+type URLSigner interface {
+	// SignURL returns a URL to download the file at the given path. The URL
+	// expires after the given duration.
+	SignURL(_ context.Context, path string, expiry time.Duration) (string, error)
+}
+
+// SignURL returns a signed download URL for the file at the specified path of
+// the provided StorageDisk. If disk doesn't implement URLSigner,
+// ErrSigningUnsupported is returned. The expiry must be positive and must not
+// exceed MaxURLExpiry.
+func SignURL(ctx context.Context, disk StorageDisk, path string, expiry time.Duration) (string, error) {
+	if expiry <= 0 || expiry > MaxURLExpiry {
+		return "", fmt.Errorf("invalid expiry %v: must be between 0 and %v", expiry, MaxURLExpiry)
+	}
+
+	signer, ok := disk.(URLSigner)
+	if !ok {
+		return "", ErrSigningUnsupported
+	}
+
+	return signer.SignURL(ctx, path, expiry)
+}
+
+// SignedURL returns a signed download URL for the file that expires after the
+// given duration. See SignURL.
+func (f File) SignedURL(ctx context.Context, storage Storage, expiry time.Duration) (string, error) {
+	disk, err := f.storageDisk(storage)
+	if err != nil {
+		return "", err
+	}
+	return SignURL(ctx, disk, f.Location(), expiry)
+}
+
+// WithURLSigner returns a StorageDisk that signs URLs using the given
+// URLSigner. All other operations are delegated to disk. To sign URLs of a
+// ContentDisk, wrap the signing disk with ContentAddressed, not vice versa:
+//
+//	// Files of an FSDisk are served by HMACSigner.Handler.
+//	signer := media.NewHMACSigner(secret, "https://files.example.com")
+//	disk := media.WithURLSigner(media.FSDisk("/var/lib/cms/files"), signer)
+//	http.Handle("/", signer.Handler(disk))
+func WithURLSigner(disk StorageDisk, signer URLSigner) StorageDisk {
+	return signingDisk{StorageDisk: disk, signer: signer}
+}
+
+type signingDisk struct {
+	StorageDisk
+	signer URLSigner
+}
+
+func (d signingDisk) SignURL(ctx context.Context, path string, expiry time.Duration) (string, error) {
+	return d.signer.SignURL(ctx, path, expiry)
+}
+
+func (d signingDisk) PutReader(ctx context.Context, path string, r io.Reader) error {
+	return PutReader(ctx, d.StorageDisk, path, r)
+}
+
+func (d signingDisk) GetReader(ctx context.Context, path string) (io.ReadCloser, error) {
+	return GetReader(ctx, d.StorageDisk, path)
+}
+
+func (d signingDisk) GetRange(ctx context.Context, path string, off, length int64) ([]byte, error) {
+	return GetRange(ctx, d.StorageDisk, path, off, length)
+}
+
+func (d signingDisk) GetRangeReader(ctx context.Context, path string, off, length int64) (io.ReadCloser, error) {
+	return GetRangeReader(ctx, d.StorageDisk, path, off, length)
+}
+
+func (d signingDisk) Copy(ctx context.Context, src, dst string) error {
+	return Copy(ctx, d.StorageDisk, src, dst)
+}
+
+func (d signingDisk) Move(ctx context.Context, src, dst string) error {
+	return Move(ctx, d.StorageDisk, src, dst)
+}
+
+func (d signingDisk) List(ctx context.Context, prefix string) ([]FileInfo, error) {
+	return List(ctx, d.StorageDisk, prefix)
+}
+
+func (d signingDisk) Stat(ctx context.Context, path string) (FileInfo, error) {
+	return Stat(ctx, d.StorageDisk, path)
+}
+
+// SignerOption is an option for the URLSigners of this package.
+type SignerOption func(*signerConfig)
+
+type signerConfig struct {
+	now func() time.Time
+}
+
+// SignerClock returns a SignerOption that sets the function that returns the
+// current time. Defaults to time.Now.
+func SignerClock(now func() time.Time) SignerOption {
+	return func(cfg *signerConfig) {
+		cfg.now = now
+	}
+}
+
+func newSignerConfig(opts []SignerOption) signerConfig {
+	cfg := signerConfig{now: time.Now}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// HMACSigner is a URLSigner for disks that have no signing mechanism of their
+// own, like FSDisk. Signed URLs point to the file path under a base URL and
+// contain the expiry time and an HMAC-SHA256 signature of the path and expiry
+// time. HMACSigner.Handler serves the files of signed URLs.
+type HMACSigner struct {
+	secret  []byte
+	baseURL string
+	cfg     signerConfig
+}
+
+// NewHMACSigner returns an HMACSigner that signs URLs with the given secret.
+// baseURL is the URL under which HMACSigner.Handler is served.
+func NewHMACSigner(secret []byte, baseURL string, opts ...SignerOption) *HMACSigner {
+	return &HMACSigner{
+		secret:  secret,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		cfg:     newSignerConfig(opts),
+	}
+}
+
+// SignURL returns the signed URL of the file at the given path.
+func (s *HMACSigner) SignURL(_ context.Context, path string, expiry time.Duration) (string, error) {
+	path = cleanSignedPath(path)
+	expires := s.cfg.now().Add(expiry).Unix()
+
+	query := url.Values{
+		"expires":   {strconv.FormatInt(expires, 10)},
+		"signature": {s.sign(path, expires)},
+	}
+
+	return s.baseURL + (&url.URL{Path: path}).EscapedPath() + "?" + query.Encode(), nil
+}
+
+// Verify verifies the signature of a signed URL with the given path and query.
+// Verify returns ErrInvalidSignature if the signature is invalid and
+// ErrSignatureExpired if the URL has expired.
+func (s *HMACSigner) Verify(path string, query url.Values) error {
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	sig := query.Get("signature")
+	if !hmac.Equal([]byte(sig), []byte(s.sign(cleanSignedPath(path), expires))) {
+		return ErrInvalidSignature
+	}
+
+	if !s.cfg.now().Before(time.Unix(expires, 0)) {
+		return ErrSignatureExpired
+	}
+
+	return nil
+}
+
+// Handler returns an http.Handler that serves the files of disk for signed
+// URLs. Requests with an invalid signature are rejected with 403 Forbidden.
+// The handler expects the request path to be the file path, so if the base
+// URL of the HMACSigner has a path, it must be stripped using
+// http.StripPrefix.
+func (s *HMACSigner) Handler(disk StorageDisk) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		path := cleanSignedPath(r.URL.Path)
+		if err := s.Verify(path, r.URL.Query()); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
+		b, err := disk.Get(r.Context(), path)
+		if err != nil {
+			if errors.Is(err, ErrFileNotFound) {
+				http.NotFound(w, r)
+				return
+			}
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		if typ := mime.TypeByExtension(stdpath.Ext(path)); typ != "" {
+			w.Header().Set("Content-Type", typ)
+		}
+
+		http.ServeContent(w, r, stdpath.Base(path), time.Time{}, bytes.NewReader(b))
+	})
+}
+
+func (s *HMACSigner) sign(path string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(path + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func cleanSignedPath(path string) string {
+	return stdpath.Clean("/" + path)
+}
+
+// S3SignerConfig configures an S3Signer.
+type S3SignerConfig struct {
+	Region string
+	Bucket string
+
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// SessionToken is the session token of temporary credentials.
+	SessionToken string
+
+	// Endpoint is the URL of an S3-compatible service. If Endpoint is set,
+	// path-style URLs ("{Endpoint}/{Bucket}/{Key}") are signed. Otherwise,
+	// virtual-hosted–style URLs of AWS S3 are signed.
+	Endpoint string
+}
+
+// S3Signer is a URLSigner that signs presigned GET URLs for objects of an S3
+// bucket (AWS Signature Version 4). Use WithURLSigner to add an S3Signer to
+// the S3 disk of the bucket.
+type S3Signer struct {
+	s3  S3SignerConfig
+	cfg signerConfig
+}
+
+// NewS3Signer returns an S3Signer.
+func NewS3Signer(cfg S3SignerConfig, opts ...SignerOption) *S3Signer {
+	return &S3Signer{s3: cfg, cfg: newSignerConfig(opts)}
+}
+
+// SignURL returns the presigned URL of the object at the given path.
+func (s *S3Signer) SignURL(_ context.Context, path string, expiry time.Duration) (string, error) {
+	key := strings.TrimPrefix(path, "/")
+	if key == "" {
+		return "", fmt.Errorf("empty object key")
+	}
+
+	u, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+
+	now := s.cfg.now().UTC()
+	date := now.Format("20060102")
+	scope := date + "/" + s.s3.Region + "/s3/aws4_request"
+
+	query := url.Values{
+		"X-Amz-Algorithm":     {"AWS4-HMAC-SHA256"},
+		"X-Amz-Credential":    {s.s3.AccessKeyID + "/" + scope},
+		"X-Amz-Date":          {now.Format("20060102T150405Z")},
+		"X-Amz-Expires":       {strconv.FormatInt(int64(expiry/time.Second), 10)},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	if s.s3.SessionToken != "" {
+		query.Set("X-Amz-Security-Token", s.s3.SessionToken)
+	}
+
+	canonicalQuery := canonicalS3Query(query)
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		canonicalQuery,
+		"host:" + u.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		now.Format("20060102T150405Z"),
+		scope,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key4 := hmacSHA256([]byte("AWS4"+s.s3.SecretAccessKey), date)
+	key4 = hmacSHA256(key4, s.s3.Region)
+	key4 = hmacSHA256(key4, "s3")
+	key4 = hmacSHA256(key4, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key4, stringToSign))
+
+	u.RawQuery = canonicalQuery + "&X-Amz-Signature=" + signature
+
+	return u.String(), nil
+}
+
+func (s *S3Signer) objectURL(key string) (*url.URL, error) {
+	if s.s3.Endpoint != "" {
+		u, err := url.Parse(strings.TrimSuffix(s.s3.Endpoint, "/"))
+		if err != nil {
+			return nil, fmt.Errorf("parse endpoint: %w", err)
+		}
+		u.Path += "/" + s.s3.Bucket + "/" + key
+		u.RawPath = u.Path[:len(u.Path)-len(key)] + escapeS3Key(key)
+		return u, nil
+	}
+
+	host := s.s3.Bucket + ".s3." + s.s3.Region + ".amazonaws.com"
+	if s.s3.Region == "" || s.s3.Region == "us-east-1" {
+		host = s.s3.Bucket + ".s3.amazonaws.com"
+	}
+
+	return &url.URL{
+		Scheme:  "https",
+		Host:    host,
+		Path:    "/" + key,
+		RawPath: "/" + escapeS3Key(key),
+	}, nil
+}
+
+// canonicalS3Query returns the sorted, URI-encoded query string of v.
+func canonicalS3Query(v url.Values) string {
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		for _, val := range v[k] {
+			parts = append(parts, escapeS3(k, true)+"="+escapeS3(val, true))
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+func escapeS3Key(key string) string {
+	return escapeS3(key, false)
+}
+
+// escapeS3 URI-encodes s as required by AWS Signature Version 4. Slashes are
+// only encoded if encodeSlash is true.
+func escapeS3(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -3,10 +3,19 @@ package media
 //go:generate mockgen -source=storage.go -destination=./mock_media/storage.go
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	stdpath "path"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/bounoable/godrive"
 )
@@ -33,6 +42,261 @@ type StorageDisk interface {
 	Delete(context.Context, string) error
 }
 
+// RangeDisk is a StorageDisk that can read parts of a file without reading the
+// whole file. Use GetRange to read a byte range from any StorageDisk.
+type RangeDisk interface {
+	StorageDisk
+
+	// GetRange returns length bytes of the file at the specified path,
+	// starting at byte offset off, or ErrFileNotFound if the file does not
+	// exist. If the range exceeds the end of the file, only the available
+	// bytes are returned.
+	GetRange(_ context.Context, path string, off, length int64) ([]byte, error)
+
+	// GetRangeReader returns an io.ReadCloser that streams length bytes of the
+	// file at the specified path, starting at byte offset off, or
+	// ErrFileNotFound if the file does not exist. If the range exceeds the end
+	// of the file, only the available bytes are streamed. Callers must close
+	// the returned reader.
+	GetRangeReader(_ context.Context, path string, off, length int64) (io.ReadCloser, error)
+}
+
+// GetRange returns length bytes of the file at the specified path, starting at
+// byte offset off. If disk implements RangeDisk, disk.GetRange is used.
+// Otherwise the whole file is fetched using disk.Get and the range is cut out
+// of the returned contents.
+func GetRange(ctx context.Context, disk StorageDisk, path string, off, length int64) ([]byte, error) {
+	if off < 0 || length < 0 {
+		return nil, fmt.Errorf("invalid range [off=%d length=%d]", off, length)
+	}
+
+	if rd, ok := disk.(RangeDisk); ok {
+		return rd.GetRange(ctx, path, off, length)
+	}
+
+	b, err := disk.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return cutRange(b, off, length), nil
+}
+
+// GetRangeReader returns an io.ReadCloser that streams length bytes of the file
+// at the specified path, starting at byte offset off. If disk implements
+// RangeDisk, disk.GetRangeReader is used. Otherwise the file is streamed using
+// GetReader and the bytes before off are discarded. Callers must close the
+// returned reader.
+func GetRangeReader(ctx context.Context, disk StorageDisk, path string, off, length int64) (io.ReadCloser, error) {
+	if off < 0 || length < 0 {
+		return nil, fmt.Errorf("invalid range [off=%d length=%d]", off, length)
+	}
+
+	if rd, ok := disk.(RangeDisk); ok {
+		return rd.GetRangeReader(ctx, path, off, length)
+	}
+
+	r, err := GetReader(ctx, disk, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := io.CopyN(io.Discard, r, off); err != nil && !errors.Is(err, io.EOF) {
+		r.Close()
+		return nil, err
+	}
+
+	return readCloser{Reader: io.LimitReader(r, length), Closer: r}, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func cutRange(b []byte, off, length int64) []byte {
+	size := int64(len(b))
+	if off >= size {
+		return []byte{}
+	}
+	end := off + length
+	if end > size {
+		end = size
+	}
+	out := make([]byte, end-off)
+	copy(out, b[off:end])
+	return out
+}
+
+// StreamDisk is a StorageDisk that can read and write files as streams without
+// buffering whole files in memory. Use PutReader and GetReader to stream files
+// to and from any StorageDisk.
+type StreamDisk interface {
+	StorageDisk
+
+	// PutReader uploads the contents of r to the specified storage path.
+	PutReader(_ context.Context, path string, r io.Reader) error
+
+	// GetReader returns an io.ReadCloser for the contents of the file at the
+	// specified path or ErrFileNotFound if the file does not exist. Callers
+	// must close the returned reader.
+	GetReader(_ context.Context, path string) (io.ReadCloser, error)
+}
+
+// PutReader uploads the contents of r to the specified path of the provided
+// StorageDisk. If disk implements StreamDisk, disk.PutReader is used. Otherwise
+// r is read into memory and uploaded using disk.Put.
+func PutReader(ctx context.Context, disk StorageDisk, path string, r io.Reader) error {
+	if sd, ok := disk.(StreamDisk); ok {
+		return sd.PutReader(ctx, path, r)
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("read file: %w", err)
+	}
+
+	return disk.Put(ctx, path, b)
+}
+
+// GetReader returns an io.ReadCloser for the contents of the file at the
+// specified path of the provided StorageDisk. If disk implements StreamDisk,
+// disk.GetReader is used. Otherwise the whole file is fetched using disk.Get.
+func GetReader(ctx context.Context, disk StorageDisk, path string) (io.ReadCloser, error) {
+	if sd, ok := disk.(StreamDisk); ok {
+		return sd.GetReader(ctx, path)
+	}
+
+	b, err := disk.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+// CopyDisk is a StorageDisk that can copy and move files without downloading
+// and re-uploading them. Use Copy and Move to copy and move files on any
+// StorageDisk.
+type CopyDisk interface {
+	StorageDisk
+
+	// Copy copies the file at path src to path dst or returns ErrFileNotFound
+	// if src does not exist. An existing file at dst is overwritten.
+	Copy(_ context.Context, src, dst string) error
+
+	// Move moves the file at path src to path dst or returns ErrFileNotFound
+	// if src does not exist. An existing file at dst is overwritten.
+	Move(_ context.Context, src, dst string) error
+}
+
+// Copy copies the file at path src of the provided StorageDisk to path dst. If
+// disk implements CopyDisk, disk.Copy is used. Otherwise the file is streamed
+// from src to dst using GetReader and PutReader.
+func Copy(ctx context.Context, disk StorageDisk, src, dst string) error {
+	if src == dst {
+		return nil
+	}
+
+	if cd, ok := disk.(CopyDisk); ok {
+		return cd.Copy(ctx, src, dst)
+	}
+
+	r, err := GetReader(ctx, disk, src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	return PutReader(ctx, disk, dst, r)
+}
+
+// Move moves the file at path src of the provided StorageDisk to path dst. If
+// disk implements CopyDisk, disk.Move is used. Otherwise the file is copied
+// using Copy and then deleted from src. If the deletion fails, the copy at dst
+// is deleted again, so that the file is either moved or left untouched.
+func Move(ctx context.Context, disk StorageDisk, src, dst string) error {
+	if src == dst {
+		return nil
+	}
+
+	if cd, ok := disk.(CopyDisk); ok {
+		return cd.Move(ctx, src, dst)
+	}
+
+	if err := Copy(ctx, disk, src, dst); err != nil {
+		return fmt.Errorf("copy file: %w", err)
+	}
+
+	if err := disk.Delete(ctx, src); err != nil {
+		disk.Delete(ctx, dst)
+		return fmt.Errorf("delete source file: %w", err)
+	}
+
+	return nil
+}
+
+// FileInfo describes a file of a StorageDisk.
+type FileInfo struct {
+	// Path is the storage path of the file.
+	Path string
+
+	// Size is the size of the file in bytes.
+	Size int64
+
+	// ModTime is the time at which the file was last written. ModTime is zero
+	// if the StorageDisk doesn't record modification times.
+	ModTime time.Time
+}
+
+// ListDisk is a StorageDisk that can list its files. Use List to list the files
+// of any StorageDisk.
+type ListDisk interface {
+	StorageDisk
+
+	// List returns the files whose storage path starts with prefix, sorted by
+	// path. If prefix is empty, all files of the disk are returned.
+	List(_ context.Context, prefix string) ([]FileInfo, error)
+}
+
+// List returns the files of the provided StorageDisk whose storage path starts
+// with prefix, sorted by path. If disk doesn't implement ListDisk,
+// ErrListingUnsupported is returned, because files cannot be listed using the
+// methods of a StorageDisk.
+func List(ctx context.Context, disk StorageDisk, prefix string) ([]FileInfo, error) {
+	if ld, ok := disk.(ListDisk); ok {
+		return ld.List(ctx, prefix)
+	}
+	return nil, ErrListingUnsupported
+}
+
+// StatDisk is a StorageDisk that can describe a file without reading it. Use
+// Stat to describe a file of any StorageDisk.
+type StatDisk interface {
+	StorageDisk
+
+	// Stat returns the FileInfo of the file at the specified path or
+	// ErrFileNotFound if the file does not exist.
+	Stat(_ context.Context, path string) (FileInfo, error)
+}
+
+// Stat returns the FileInfo of the file at the specified path of the provided
+// StorageDisk. If disk implements StatDisk, disk.Stat is used. Otherwise the
+// whole file is fetched using disk.Get to determine its size, and the ModTime
+// of the returned FileInfo is zero.
+func Stat(ctx context.Context, disk StorageDisk, path string) (FileInfo, error) {
+	if sd, ok := disk.(StatDisk); ok {
+		return sd.Stat(ctx, path)
+	}
+
+	b, err := disk.Get(ctx, path)
+	if err != nil {
+		return FileInfo{}, err
+	}
+
+	return FileInfo{Path: path, Size: int64(len(b))}, nil
+}
+
 // StorageOption is an option for creating a Storage.
 type StorageOption func(*storage)
 
@@ -91,14 +355,16 @@ func (s *godriveStorage) Disk(name string) (StorageDisk, error) {
 }
 
 type memoryDisk struct {
-	mux   sync.RWMutex
-	files map[string][]byte
+	mux      sync.RWMutex
+	files    map[string][]byte
+	modified map[string]time.Time
 }
 
 // MemoryDisk returns an in-memory StorageDisk.
 func MemoryDisk() StorageDisk {
 	return &memoryDisk{
-		files: make(map[string][]byte),
+		files:    make(map[string][]byte),
+		modified: make(map[string]time.Time),
 	}
 }
 
@@ -106,6 +372,7 @@ func (d *memoryDisk) Put(_ context.Context, path string, b []byte) error {
 	d.mux.Lock()
 	defer d.mux.Unlock()
 	d.files[path] = b
+	d.modified[path] = time.Now()
 	return nil
 }
 
@@ -120,9 +387,295 @@ func (d *memoryDisk) Get(_ context.Context, path string) ([]byte, error) {
 	return nil, ErrFileNotFound
 }
 
+func (d *memoryDisk) PutReader(ctx context.Context, path string, r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("read file: %w", err)
+	}
+	return d.Put(ctx, path, b)
+}
+
+func (d *memoryDisk) GetReader(ctx context.Context, path string) (io.ReadCloser, error) {
+	b, err := d.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (d *memoryDisk) GetRange(_ context.Context, path string, off, length int64) ([]byte, error) {
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	if b, ok := d.files[path]; ok {
+		return cutRange(b, off, length), nil
+	}
+	return nil, ErrFileNotFound
+}
+
+func (d *memoryDisk) GetRangeReader(ctx context.Context, path string, off, length int64) (io.ReadCloser, error) {
+	b, err := d.GetRange(ctx, path, off, length)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (d *memoryDisk) Copy(_ context.Context, src, dst string) error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	b, ok := d.files[src]
+	if !ok {
+		return ErrFileNotFound
+	}
+	d.files[dst] = append([]byte(nil), b...)
+	d.modified[dst] = time.Now()
+	return nil
+}
+
+func (d *memoryDisk) Move(_ context.Context, src, dst string) error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	b, ok := d.files[src]
+	if !ok {
+		return ErrFileNotFound
+	}
+	if src != dst {
+		d.files[dst] = b
+		d.modified[dst] = d.modified[src]
+		delete(d.files, src)
+		delete(d.modified, src)
+	}
+	return nil
+}
+
 func (d *memoryDisk) Delete(_ context.Context, path string) error {
 	d.mux.Lock()
 	defer d.mux.Unlock()
 	delete(d.files, path)
+	delete(d.modified, path)
 	return nil
 }
+
+func (d *memoryDisk) List(_ context.Context, prefix string) ([]FileInfo, error) {
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	files := make([]FileInfo, 0, len(d.files))
+	for path, b := range d.files {
+		if strings.HasPrefix(path, prefix) {
+			files = append(files, FileInfo{Path: path, Size: int64(len(b)), ModTime: d.modified[path]})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+func (d *memoryDisk) Stat(_ context.Context, path string) (FileInfo, error) {
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	if b, ok := d.files[path]; ok {
+		return FileInfo{Path: path, Size: int64(len(b)), ModTime: d.modified[path]}, nil
+	}
+	return FileInfo{}, ErrFileNotFound
+}
+
+type fsDisk struct {
+	root string
+}
+
+// FSDisk returns a StorageDisk that stores files in the local filesystem under
+// the provided root directory. Paths are sanitized so that files cannot be
+// written or read outside of root. Writes are atomic: files are first written
+// to a temporary file in the target directory and then moved into place.
+func FSDisk(root string) StorageDisk {
+	return &fsDisk{root: filepath.Clean(root)}
+}
+
+func (d *fsDisk) Put(ctx context.Context, path string, b []byte) error {
+	return d.PutReader(ctx, path, bytes.NewReader(b))
+}
+
+func (d *fsDisk) PutReader(_ context.Context, path string, r io.Reader) error {
+	p := d.path(path)
+
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(p), ".tmp-"+filepath.Base(p)+"-*")
+	if err != nil {
+		return fmt.Errorf("create temporary file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("write temporary file: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close temporary file: %w", err)
+	}
+
+	if err := os.Rename(f.Name(), p); err != nil {
+		return fmt.Errorf("move temporary file: %w", err)
+	}
+
+	return nil
+}
+
+func (d *fsDisk) Get(_ context.Context, path string) ([]byte, error) {
+	b, err := os.ReadFile(d.path(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrFileNotFound
+	}
+	return b, err
+}
+
+func (d *fsDisk) GetReader(_ context.Context, path string) (io.ReadCloser, error) {
+	f, err := os.Open(d.path(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrFileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (d *fsDisk) GetRange(_ context.Context, path string, off, length int64) ([]byte, error) {
+	f, err := os.Open(d.path(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrFileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	b, err := io.ReadAll(io.NewSectionReader(f, off, length))
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (d *fsDisk) GetRangeReader(_ context.Context, path string, off, length int64) (io.ReadCloser, error) {
+	f, err := os.Open(d.path(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrFileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return readCloser{Reader: io.NewSectionReader(f, off, length), Closer: f}, nil
+}
+
+func (d *fsDisk) Copy(ctx context.Context, src, dst string) error {
+	f, err := d.GetReader(ctx, src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return d.PutReader(ctx, dst, f)
+}
+
+func (d *fsDisk) Move(_ context.Context, src, dst string) error {
+	from, to := d.path(src), d.path(dst)
+
+	if _, err := os.Stat(from); errors.Is(err, fs.ErrNotExist) {
+		return ErrFileNotFound
+	}
+
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	return os.Rename(from, to)
+}
+
+func (d *fsDisk) Delete(_ context.Context, path string) error {
+	if err := os.Remove(d.path(path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List walks the deepest directory that contains all files with the given
+// prefix. Temporary files of unfinished uploads are skipped.
+func (d *fsDisk) List(ctx context.Context, prefix string) ([]FileInfo, error) {
+	prefix = "/" + strings.TrimPrefix(prefix, "/")
+
+	dir := prefix
+	if !strings.HasSuffix(dir, "/") {
+		dir = stdpath.Dir(dir)
+	}
+
+	var files []FileInfo
+	err := filepath.WalkDir(d.path(dir), func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".tmp-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(d.root, p)
+		if err != nil {
+			return err
+		}
+
+		path := "/" + filepath.ToSlash(rel)
+		if !strings.HasPrefix(path, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		files = append(files, FileInfo{Path: path, Size: info.Size(), ModTime: info.ModTime()})
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk %q: %w", dir, err)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	return files, nil
+}
+
+func (d *fsDisk) Stat(_ context.Context, path string) (FileInfo, error) {
+	info, err := os.Stat(d.path(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return FileInfo{}, ErrFileNotFound
+	}
+	if err != nil {
+		return FileInfo{}, err
+	}
+	if info.IsDir() {
+		return FileInfo{}, ErrFileNotFound
+	}
+	return FileInfo{Path: path, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// path returns the sanitized filesystem path for the given storage path.
+// Cleaning the path as an absolute path removes all ".." elements that would
+// otherwise escape the root directory.
+func (d *fsDisk) path(path string) string {
+	return filepath.Join(d.root, filepath.FromSlash(stdpath.Clean("/"+path)))
+}
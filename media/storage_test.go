@@ -1,9 +1,16 @@
 package media_test
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"io"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/golang/mock/gomock"
 	"github.com/modernice/nice-cms/media"
@@ -103,3 +110,558 @@ func TestStorage_Disk(t *testing.T) {
 		t.Fatalf("storage.Disk should return the configured disk %v; got %v", disk, disk2)
 	}
 }
+
+func TestFSDisk(t *testing.T) {
+	root := t.TempDir()
+	disk := media.FSDisk(root)
+	ctx := context.Background()
+
+	if _, err := disk.Get(ctx, "/foo/bar.txt"); !errors.Is(err, media.ErrFileNotFound) {
+		t.Fatalf("Get should fail with %q for a non-existent file; got %v", media.ErrFileNotFound, err)
+	}
+
+	if err := disk.Put(ctx, "/foo/bar.txt", []byte("foo")); err != nil {
+		t.Fatalf("Put shouldn't fail; failed with %q", err)
+	}
+
+	b, err := disk.Get(ctx, "/foo/bar.txt")
+	if err != nil {
+		t.Fatalf("Get shouldn't fail; failed with %q", err)
+	}
+
+	if string(b) != "foo" {
+		t.Fatalf("Get should return %q; got %q", "foo", b)
+	}
+
+	if b, err := os.ReadFile(filepath.Join(root, "foo", "bar.txt")); err != nil || string(b) != "foo" {
+		t.Fatalf("file should be stored in the root directory; got %q (%v)", b, err)
+	}
+
+	if err := disk.Delete(ctx, "/foo/bar.txt"); err != nil {
+		t.Fatalf("Delete shouldn't fail; failed with %q", err)
+	}
+
+	if _, err := disk.Get(ctx, "/foo/bar.txt"); !errors.Is(err, media.ErrFileNotFound) {
+		t.Fatalf("Get should fail with %q for a deleted file; got %v", media.ErrFileNotFound, err)
+	}
+
+	if err := disk.Delete(ctx, "/foo/bar.txt"); err != nil {
+		t.Fatalf("Delete shouldn't fail for a non-existent file; failed with %q", err)
+	}
+}
+
+func TestFSDisk_pathTraversal(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "root")
+	disk := media.FSDisk(root)
+	ctx := context.Background()
+
+	if err := disk.Put(ctx, "../../escaped.txt", []byte("foo")); err != nil {
+		t.Fatalf("Put shouldn't fail; failed with %q", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(parent, "escaped.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("file should not be written outside of the root directory")
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "escaped.txt")); err != nil {
+		t.Fatalf("file should be written inside of the root directory; got %v", err)
+	}
+}
+
+func TestGetRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	contents := []byte("foo bar baz")
+
+	fallbackDisk := mock_media.NewMockStorageDisk(ctrl)
+	fallbackDisk.EXPECT().Get(ctx, "/foo.txt").Return(contents, nil).AnyTimes()
+
+	disks := map[string]media.StorageDisk{
+		"memory":   media.MemoryDisk(),
+		"fs":       media.FSDisk(t.TempDir()),
+		"fallback": fallbackDisk,
+	}
+
+	for name, disk := range disks {
+		t.Run(name, func(t *testing.T) {
+			if name != "fallback" {
+				if err := disk.Put(ctx, "/foo.txt", contents); err != nil {
+					t.Fatalf("Put shouldn't fail; failed with %q", err)
+				}
+			}
+
+			tests := []struct {
+				off, length int64
+				want        string
+			}{
+				{0, 3, "foo"},
+				{4, 3, "bar"},
+				{8, 10, "baz"},
+				{20, 5, ""},
+			}
+
+			for _, tt := range tests {
+				b, err := media.GetRange(ctx, disk, "/foo.txt", tt.off, tt.length)
+				if err != nil {
+					t.Fatalf("GetRange shouldn't fail; failed with %q", err)
+				}
+
+				if string(b) != tt.want {
+					t.Fatalf("GetRange(%d, %d) should return %q; got %q", tt.off, tt.length, tt.want, b)
+				}
+
+				r, err := media.GetRangeReader(ctx, disk, "/foo.txt", tt.off, tt.length)
+				if err != nil {
+					t.Fatalf("GetRangeReader shouldn't fail; failed with %q", err)
+				}
+				b, err = io.ReadAll(r)
+				r.Close()
+				if err != nil {
+					t.Fatalf("ReadAll shouldn't fail; failed with %q", err)
+				}
+
+				if string(b) != tt.want {
+					t.Fatalf("GetRangeReader(%d, %d) should stream %q; got %q", tt.off, tt.length, tt.want, b)
+				}
+			}
+		})
+	}
+}
+
+func TestFile_Open(t *testing.T) {
+	ctx := context.Background()
+	contents := []byte("foo bar baz")
+	storage := media.NewStorage(media.ConfigureDisk("foo", media.MemoryDisk()))
+
+	f := media.NewFile("foo", "foo", "/foo.txt", 0)
+	f, err := f.Upload(ctx, bytes.NewReader(contents), storage)
+	if err != nil {
+		t.Fatalf("Upload shouldn't fail; failed with %q", err)
+	}
+
+	r, err := f.Open(ctx, storage)
+	if err != nil {
+		t.Fatalf("Open shouldn't fail; failed with %q", err)
+	}
+
+	if _, err := r.Seek(4, io.SeekStart); err != nil {
+		t.Fatalf("Seek shouldn't fail; failed with %q", err)
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll shouldn't fail; failed with %q", err)
+	}
+
+	if string(b) != "bar baz" {
+		t.Fatalf("reader should return %q; got %q", "bar baz", b)
+	}
+
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		t.Fatalf("Seek shouldn't fail; failed with %q", err)
+	}
+
+	if size != int64(len(contents)) {
+		t.Fatalf("Seek to end should return %d; got %d", len(contents), size)
+	}
+}
+
+func TestFile_Open_stream(t *testing.T) {
+	ctx := context.Background()
+	contents := []byte("foo bar baz")
+	disk := &countingRangeDisk{RangeDisk: media.MemoryDisk().(media.RangeDisk)}
+	storage := media.NewStorage(media.ConfigureDisk("foo", disk))
+
+	f, err := media.NewFile("foo", "foo", "/foo.txt", 0).Upload(ctx, bytes.NewReader(contents), storage)
+	if err != nil {
+		t.Fatalf("Upload shouldn't fail; failed with %q", err)
+	}
+
+	r, err := f.Open(ctx, storage)
+	if err != nil {
+		t.Fatalf("Open shouldn't fail; failed with %q", err)
+	}
+	defer r.Close()
+
+	if _, err := r.Seek(4, io.SeekStart); err != nil {
+		t.Fatalf("Seek shouldn't fail; failed with %q", err)
+	}
+
+	b, err := io.ReadAll(iotest.OneByteReader(r))
+	if err != nil {
+		t.Fatalf("ReadAll shouldn't fail; failed with %q", err)
+	}
+
+	if string(b) != "bar baz" {
+		t.Fatalf("reader should return %q; got %q", "bar baz", b)
+	}
+
+	if disk.streams != 1 || disk.ranges != 0 {
+		t.Fatalf("reader should open %d ranged stream; opened %d streams and fetched %d ranges", 1, disk.streams, disk.ranges)
+	}
+}
+
+type countingRangeDisk struct {
+	media.RangeDisk
+
+	streams int
+	ranges  int
+}
+
+func (d *countingRangeDisk) GetRange(ctx context.Context, path string, off, length int64) ([]byte, error) {
+	d.ranges++
+	return d.RangeDisk.GetRange(ctx, path, off, length)
+}
+
+func (d *countingRangeDisk) GetRangeReader(ctx context.Context, path string, off, length int64) (io.ReadCloser, error) {
+	d.streams++
+	return d.RangeDisk.GetRangeReader(ctx, path, off, length)
+}
+
+func TestFile_Relocate(t *testing.T) {
+	ctx := context.Background()
+	contents := []byte("foo bar baz")
+	foo, bar := media.MemoryDisk(), media.MemoryDisk()
+	storage := media.NewStorage(
+		media.ConfigureDisk("foo", foo),
+		media.ConfigureDisk("bar", bar),
+		media.ConfigureDisk("content", media.ContentAddressed(media.MemoryDisk())),
+	)
+
+	f, err := media.NewFile("foo", "foo", "/foo.txt", 0).Upload(ctx, bytes.NewReader(contents), storage)
+	if err != nil {
+		t.Fatalf("Upload shouldn't fail; failed with %q", err)
+	}
+
+	moved, err := f.Relocate(ctx, storage, "foo", "/moved.txt")
+	if err != nil {
+		t.Fatalf("Relocate shouldn't fail; failed with %q", err)
+	}
+	if moved.Disk != "foo" || moved.Path != "/moved.txt" {
+		t.Fatalf("File should be relocated to %q on %q; is at %q on %q", "/moved.txt", "foo", moved.Path, moved.Disk)
+	}
+	if b, err := foo.Get(ctx, "/moved.txt"); err != nil || !bytes.Equal(b, contents) {
+		t.Fatalf("contents should be copied to %q; Get returned %q (%v)", "/moved.txt", b, err)
+	}
+	if _, err := foo.Get(ctx, "/foo.txt"); err != nil {
+		t.Fatalf("file should not be deleted; Get returned %v", err)
+	}
+
+	migrated, err := f.Relocate(ctx, storage, "bar", "/bar.txt")
+	if err != nil {
+		t.Fatalf("Relocate shouldn't fail; failed with %q", err)
+	}
+	if migrated.Disk != "bar" || migrated.Path != "/bar.txt" || migrated.Checksum != f.Checksum {
+		t.Fatalf("File should be relocated to %q on %q with checksum %q; got %v", "/bar.txt", "bar", f.Checksum, migrated)
+	}
+	if b, err := bar.Get(ctx, "/bar.txt"); err != nil || !bytes.Equal(b, contents) {
+		t.Fatalf("contents should be copied to the other disk; Get returned %q (%v)", b, err)
+	}
+
+	content, err := f.Relocate(ctx, storage, "content", "/content.txt")
+	if err != nil {
+		t.Fatalf("Relocate shouldn't fail; failed with %q", err)
+	}
+	if content.StoragePath == "" || content.Path != "/content.txt" {
+		t.Fatalf("File should be stored content-addressed with the logical path %q; got %v", "/content.txt", content)
+	}
+
+	renamed, err := content.Relocate(ctx, storage, "content", "/renamed.txt")
+	if err != nil {
+		t.Fatalf("Relocate shouldn't fail; failed with %q", err)
+	}
+	if renamed.StoragePath != content.StoragePath || renamed.Path != "/renamed.txt" {
+		t.Fatalf("only the logical path of a content-addressed File should change; got %v", renamed)
+	}
+}
+
+func TestPutReader_GetReader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	contents := []byte("foo bar baz")
+
+	fallbackDisk := mock_media.NewMockStorageDisk(ctrl)
+	fallbackDisk.EXPECT().Put(ctx, "/foo.txt", contents).Return(nil)
+	fallbackDisk.EXPECT().Get(ctx, "/foo.txt").Return(contents, nil)
+
+	disks := map[string]media.StorageDisk{
+		"memory":   media.MemoryDisk(),
+		"fs":       media.FSDisk(t.TempDir()),
+		"fallback": fallbackDisk,
+	}
+
+	for name, disk := range disks {
+		t.Run(name, func(t *testing.T) {
+			if err := media.PutReader(ctx, disk, "/foo.txt", bytes.NewReader(contents)); err != nil {
+				t.Fatalf("PutReader shouldn't fail; failed with %q", err)
+			}
+
+			r, err := media.GetReader(ctx, disk, "/foo.txt")
+			if err != nil {
+				t.Fatalf("GetReader shouldn't fail; failed with %q", err)
+			}
+			defer r.Close()
+
+			b, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("ReadAll shouldn't fail; failed with %q", err)
+			}
+
+			if !bytes.Equal(b, contents) {
+				t.Fatalf("reader should return %q; got %q", contents, b)
+			}
+		})
+	}
+}
+
+func TestGetReader_notFound(t *testing.T) {
+	ctx := context.Background()
+
+	disks := map[string]media.StorageDisk{
+		"memory": media.MemoryDisk(),
+		"fs":     media.FSDisk(t.TempDir()),
+	}
+
+	for name, disk := range disks {
+		t.Run(name, func(t *testing.T) {
+			if _, err := media.GetReader(ctx, disk, "/foo.txt"); !errors.Is(err, media.ErrFileNotFound) {
+				t.Fatalf("GetReader should fail with %q; got %v", media.ErrFileNotFound, err)
+			}
+		})
+	}
+}
+
+func TestFile_Upload_stream(t *testing.T) {
+	ctx := context.Background()
+	contents := []byte("foo bar baz")
+	storage := media.NewStorage(media.ConfigureDisk("foo", media.FSDisk(t.TempDir())))
+
+	pr, pw := io.Pipe()
+	go func() {
+		for _, chunk := range bytes.SplitAfter(contents, []byte(" ")) {
+			pw.Write(chunk)
+		}
+		pw.Close()
+	}()
+
+	f := media.NewFile("foo", "foo", "/foo.txt", 0)
+	f, err := f.Upload(ctx, pr, storage)
+	if err != nil {
+		t.Fatalf("Upload shouldn't fail; failed with %q", err)
+	}
+
+	if f.Filesize != len(contents) {
+		t.Fatalf("Filesize should be %d; got %d", len(contents), f.Filesize)
+	}
+
+	checksum, err := media.Checksum(bytes.NewReader(contents))
+	if err != nil {
+		t.Fatalf("Checksum shouldn't fail; failed with %q", err)
+	}
+
+	if f.Checksum != checksum {
+		t.Fatalf("Checksum should be %q; got %q", checksum, f.Checksum)
+	}
+
+	r, err := f.Reader(ctx, storage)
+	if err != nil {
+		t.Fatalf("Reader shouldn't fail; failed with %q", err)
+	}
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll shouldn't fail; failed with %q", err)
+	}
+
+	if !bytes.Equal(b, contents) {
+		t.Fatalf("reader should return %q; got %q", contents, b)
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	// echo -n "foo" | sha256sum
+	want := "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+	got, err := media.Checksum(strings.NewReader("foo"))
+	if err != nil {
+		t.Fatalf("Checksum shouldn't fail; failed with %q", err)
+	}
+
+	if got != want {
+		t.Fatalf("Checksum should return %q; got %q", want, got)
+	}
+}
+
+func TestCopy_Move(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	contents := []byte("foo bar baz")
+
+	fallbackDisk := mock_media.NewMockStorageDisk(ctrl)
+	fallbackDisk.EXPECT().Put(ctx, "/foo.txt", contents).Return(nil)
+	fallbackDisk.EXPECT().Get(ctx, "/foo.txt").Return(contents, nil).Times(2)
+	fallbackDisk.EXPECT().Put(ctx, "/bar.txt", contents).Return(nil)
+	fallbackDisk.EXPECT().Put(ctx, "/baz/foo.txt", contents).Return(nil)
+	fallbackDisk.EXPECT().Delete(ctx, "/foo.txt").Return(nil)
+	fallbackDisk.EXPECT().Get(ctx, "/foo.txt").Return(nil, media.ErrFileNotFound)
+	fallbackDisk.EXPECT().Get(ctx, "/bar.txt").Return(contents, nil)
+	fallbackDisk.EXPECT().Get(ctx, "/baz/foo.txt").Return(contents, nil)
+
+	disks := map[string]media.StorageDisk{
+		"memory":   media.MemoryDisk(),
+		"fs":       media.FSDisk(t.TempDir()),
+		"fallback": fallbackDisk,
+	}
+
+	for name, disk := range disks {
+		t.Run(name, func(t *testing.T) {
+			if err := disk.Put(ctx, "/foo.txt", contents); err != nil {
+				t.Fatalf("Put shouldn't fail; failed with %q", err)
+			}
+
+			if err := media.Copy(ctx, disk, "/foo.txt", "/bar.txt"); err != nil {
+				t.Fatalf("Copy shouldn't fail; failed with %q", err)
+			}
+
+			if err := media.Move(ctx, disk, "/foo.txt", "/baz/foo.txt"); err != nil {
+				t.Fatalf("Move shouldn't fail; failed with %q", err)
+			}
+
+			if _, err := disk.Get(ctx, "/foo.txt"); !errors.Is(err, media.ErrFileNotFound) {
+				t.Fatalf("Get should fail with %q for a moved file; got %v", media.ErrFileNotFound, err)
+			}
+
+			for _, path := range []string{"/bar.txt", "/baz/foo.txt"} {
+				b, err := disk.Get(ctx, path)
+				if err != nil {
+					t.Fatalf("Get(%q) shouldn't fail; failed with %q", path, err)
+				}
+
+				if !bytes.Equal(b, contents) {
+					t.Fatalf("Get(%q) should return %q; got %q", path, contents, b)
+				}
+			}
+		})
+	}
+}
+
+func TestMove_notFound(t *testing.T) {
+	ctx := context.Background()
+
+	disks := map[string]media.StorageDisk{
+		"memory": media.MemoryDisk(),
+		"fs":     media.FSDisk(t.TempDir()),
+	}
+
+	for name, disk := range disks {
+		t.Run(name, func(t *testing.T) {
+			if err := media.Move(ctx, disk, "/foo.txt", "/bar.txt"); !errors.Is(err, media.ErrFileNotFound) {
+				t.Fatalf("Move should fail with %q; got %v", media.ErrFileNotFound, err)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	disks := map[string]media.StorageDisk{
+		"memory":  media.MemoryDisk(),
+		"fs":      media.FSDisk(t.TempDir()),
+		"content": media.ContentAddressed(media.MemoryDisk()),
+	}
+
+	for name, disk := range disks {
+		t.Run(name, func(t *testing.T) {
+			for _, path := range []string{"/foo/bar.txt", "/foo/baz/qux.txt", "/foobar.txt", "/other.txt"} {
+				if err := disk.Put(ctx, path, []byte(path)); err != nil {
+					t.Fatalf("Put shouldn't fail; failed with %q", err)
+				}
+			}
+
+			files, err := media.List(ctx, disk, "/foo")
+			if err != nil {
+				t.Fatalf("List shouldn't fail; failed with %q", err)
+			}
+
+			want := []string{"/foo/bar.txt", "/foo/baz/qux.txt", "/foobar.txt"}
+			if len(files) != len(want) {
+				t.Fatalf("List should return %d files; got %v", len(want), files)
+			}
+
+			for i, f := range files {
+				if f.Path != want[i] || f.Size != int64(len(want[i])) || f.ModTime.IsZero() {
+					t.Fatalf("List should return %q with size %d and a ModTime at index %d; got %v", want[i], len(want[i]), i, f)
+				}
+			}
+
+			if files, err = media.List(ctx, disk, "/missing/"); err != nil || len(files) != 0 {
+				t.Fatalf("List should return no files for an unknown prefix; got %v (%v)", files, err)
+			}
+
+			if files, err = media.List(ctx, disk, ""); err != nil || len(files) != 4 {
+				t.Fatalf("List should return all files for an empty prefix; got %v (%v)", files, err)
+			}
+		})
+	}
+
+	if _, err := media.List(ctx, mock_media.NewMockStorageDisk(ctrl), ""); !errors.Is(err, media.ErrListingUnsupported) {
+		t.Fatalf("List should fail with %q for a disk that doesn't implement ListDisk; got %v", media.ErrListingUnsupported, err)
+	}
+}
+
+func TestStat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	contents := []byte("foo bar baz")
+
+	fallbackDisk := mock_media.NewMockStorageDisk(ctrl)
+	fallbackDisk.EXPECT().Put(ctx, "/foo.txt", contents).Return(nil)
+	fallbackDisk.EXPECT().Get(ctx, "/foo.txt").Return(contents, nil)
+	fallbackDisk.EXPECT().Get(ctx, "/bar.txt").Return(nil, media.ErrFileNotFound)
+
+	disks := map[string]media.StorageDisk{
+		"memory":   media.MemoryDisk(),
+		"fs":       media.FSDisk(t.TempDir()),
+		"fallback": fallbackDisk,
+	}
+
+	for name, disk := range disks {
+		t.Run(name, func(t *testing.T) {
+			if err := disk.Put(ctx, "/foo.txt", contents); err != nil {
+				t.Fatalf("Put shouldn't fail; failed with %q", err)
+			}
+
+			info, err := media.Stat(ctx, disk, "/foo.txt")
+			if err != nil {
+				t.Fatalf("Stat shouldn't fail; failed with %q", err)
+			}
+
+			if info.Path != "/foo.txt" || info.Size != int64(len(contents)) {
+				t.Fatalf("Stat should return %q with size %d; got %v", "/foo.txt", len(contents), info)
+			}
+
+			if name != "fallback" && info.ModTime.IsZero() {
+				t.Fatalf("Stat should return the ModTime of the file")
+			}
+
+			if _, err := media.Stat(ctx, disk, "/bar.txt"); !errors.Is(err, media.ErrFileNotFound) {
+				t.Fatalf("Stat should fail with %q for a non-existent file; got %v", media.ErrFileNotFound, err)
+			}
+		})
+	}
+}
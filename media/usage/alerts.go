@@ -0,0 +1,112 @@
+package usage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/modernice/goes/codec"
+	"github.com/modernice/goes/event"
+)
+
+// LevelChanged is the event that is published by the AlertHandler that is
+// returned by Publish. Its event data is an Alert.
+const LevelChanged = "cms.media.usage.level_changed"
+
+// RegisterEvents registers usage events into an event registry.
+func RegisterEvents(r codec.Registerer) {
+	codec.Register[Alert](r, LevelChanged)
+}
+
+// Publish returns an AlertHandler that publishes Alerts as LevelChanged events
+// over the given event bus.
+func Publish(bus event.Bus) AlertHandler {
+	return func(ctx context.Context, alert Alert) {
+		bus.Publish(ctx, event.New(LevelChanged, alert, event.Time(alert.Time)).Any())
+	}
+}
+
+// WebhookOption is an option for Webhook.
+type WebhookOption func(*webhook)
+
+type webhook struct {
+	client  *http.Client
+	timeout time.Duration
+	onError func(error)
+}
+
+// WebhookClient returns a WebhookOption that sets the HTTP client that sends
+// the webhook requests. Defaults to http.DefaultClient.
+func WebhookClient(client *http.Client) WebhookOption {
+	return func(w *webhook) {
+		w.client = client
+	}
+}
+
+// WebhookTimeout returns a WebhookOption that sets the timeout of a webhook
+// request. Default is 10s.
+func WebhookTimeout(d time.Duration) WebhookOption {
+	return func(w *webhook) {
+		w.timeout = d
+	}
+}
+
+// WebhookErrors returns a WebhookOption that registers a function that is
+// called when a webhook request fails.
+func WebhookErrors(fn func(error)) WebhookOption {
+	return func(w *webhook) {
+		w.onError = fn
+	}
+}
+
+// Webhook returns an AlertHandler that sends Alerts as JSON to the given URL
+// using POST requests. Requests are sent in the background and are not
+// retried. Responses with a non-2xx status code are reported as errors.
+func Webhook(url string, opts ...WebhookOption) AlertHandler {
+	w := webhook{
+		client:  http.DefaultClient,
+		timeout: 10 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(&w)
+	}
+
+	return func(ctx context.Context, alert Alert) {
+		go func() {
+			if err := w.send(ctx, url, alert); err != nil && w.onError != nil {
+				w.onError(err)
+			}
+		}()
+	}
+}
+
+func (w webhook) send(ctx context.Context, url string, alert Alert) error {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	b, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("encode alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("send webhook: unexpected status %q", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,374 @@
+// Package usage computes the storage usage of disks from the files that are
+// referenced by Galleries and Shelfs, and alerts when the usage of a disk
+// crosses a configured threshold.
+package usage
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/projector"
+)
+
+// Level is the usage level of a disk.
+type Level string
+
+// Usage levels
+const (
+	// LevelOK means that the usage of a disk is below its warning threshold.
+	LevelOK = Level("ok")
+
+	// LevelWarning means that the usage of a disk reached its warning threshold.
+	LevelWarning = Level("warning")
+
+	// LevelCritical means that the usage of a disk reached its critical
+	// threshold.
+	LevelCritical = Level("critical")
+)
+
+// Thresholds are the usage thresholds of a disk in bytes. A zero threshold is
+// disabled.
+type Thresholds struct {
+	Warning  int64 `json:"warning,omitempty"`
+	Critical int64 `json:"critical,omitempty"`
+}
+
+// Level returns the Level of the given usage.
+func (t Thresholds) Level(usage int64) Level {
+	if t.Critical > 0 && usage >= t.Critical {
+		return LevelCritical
+	}
+	if t.Warning > 0 && usage >= t.Warning {
+		return LevelWarning
+	}
+	return LevelOK
+}
+
+// Disk is the usage status of a disk.
+type Disk struct {
+	// Usage is the size of all referenced files on the disk in bytes.
+	Usage int64 `json:"usage"`
+
+	Level      Level      `json:"level"`
+	Thresholds Thresholds `json:"thresholds"`
+}
+
+// Alert is emitted when the Level of a disk changes.
+type Alert struct {
+	Disk       string     `json:"disk"`
+	Usage      int64      `json:"usage"`
+	Level      Level      `json:"level"`
+	Previous   Level      `json:"previous"`
+	Thresholds Thresholds `json:"thresholds"`
+	Time       time.Time  `json:"time"`
+}
+
+// AlertHandler handles Alerts. AlertHandlers are called synchronously while
+// the Index is projected and must not block.
+type AlertHandler func(context.Context, Alert)
+
+// Option is an Index option.
+type Option func(*Index)
+
+// Threshold returns an Option that sets the usage thresholds of the given disk
+// in bytes. Pass 0 to disable a threshold.
+func Threshold(disk string, warning, critical int64) Option {
+	return func(idx *Index) {
+		idx.thresholds[disk] = Thresholds{Warning: warning, Critical: critical}
+	}
+}
+
+// OnAlert returns an Option that registers an AlertHandler that is called when
+// the Level of a disk changes.
+func OnAlert(fn AlertHandler) Option {
+	return func(idx *Index) {
+		idx.handlers = append(idx.handlers, fn)
+	}
+}
+
+// Projection returns an Option that configures the error handling of the
+// projection.
+func Projection(opts ...projector.Option) Option {
+	return func(idx *Index) {
+		idx.projectorOpts = append(idx.projectorOpts, opts...)
+	}
+}
+
+// Index is a projection of the storage usage of disks. The usage of a disk is
+// the size of all files on the disk that are referenced by the Stacks of
+// Galleries and the Documents (including variants and previews) of Shelfs.
+// Index is thread-safe.
+type Index struct {
+	projector     *projector.Projector
+	projectorOpts []projector.Option
+	thresholds    map[string]Thresholds
+	handlers      []AlertHandler
+
+	mux    sync.RWMutex
+	ctx    context.Context
+	owners map[uuid.UUID]map[file]int64
+	disks  map[string]int64
+	levels map[string]Level
+}
+
+type file struct {
+	disk string
+	path string
+}
+
+// NewIndex returns a new Index.
+func NewIndex(opts ...Option) *Index {
+	idx := &Index{
+		thresholds: make(map[string]Thresholds),
+		ctx:        context.Background(),
+		owners:     make(map[uuid.UUID]map[file]int64),
+		disks:      make(map[string]int64),
+		levels:     make(map[string]Level),
+	}
+	for _, opt := range opts {
+		opt(idx)
+	}
+	idx.projector = projector.New(idx.projectorOpts...)
+	return idx
+}
+
+// Usage returns the usage of the given disk in bytes.
+func (idx *Index) Usage(disk string) int64 {
+	idx.mux.RLock()
+	defer idx.mux.RUnlock()
+	return idx.disks[disk]
+}
+
+// Disks returns the usage status of all disks that have referenced files or
+// configured thresholds.
+func (idx *Index) Disks() map[string]Disk {
+	idx.mux.RLock()
+	defer idx.mux.RUnlock()
+
+	out := make(map[string]Disk, len(idx.disks))
+	for disk, usage := range idx.disks {
+		out[disk] = idx.disk(disk, usage)
+	}
+	for disk := range idx.thresholds {
+		if _, ok := out[disk]; !ok {
+			out[disk] = idx.disk(disk, 0)
+		}
+	}
+	return out
+}
+
+func (idx *Index) disk(name string, usage int64) Disk {
+	t := idx.thresholds[name]
+	return Disk{Usage: usage, Level: t.Level(usage), Thresholds: t}
+}
+
+// Project projects the Index in a new goroutine and returns a channel of
+// asynchronous errors. AlertHandlers are called with ctx.
+func (idx *Index) Project(ctx context.Context, bus event.Bus, store event.Store, opts ...schedule.ContinuousOption) (<-chan error, error) {
+	idx.mux.Lock()
+	idx.ctx = ctx
+	idx.mux.Unlock()
+
+	schedule := schedule.Continuously(bus, store, []string{
+		gallery.ImageUploaded,
+		gallery.ImageReplaced,
+		gallery.StackUpdated,
+		gallery.StackProcessed,
+		gallery.StackDeleted,
+		gallery.StackPurged,
+		gallery.StackRelocated,
+		gallery.Deleted,
+		document.DocumentAdded,
+		document.DocumentReplaced,
+		document.DocumentRemoved,
+		document.DocumentPurged,
+		document.ShelfDeleted,
+		document.DocumentMoved,
+		document.VariantAdded,
+		document.VariantRemoved,
+		document.PreviewRendered,
+	}, opts...)
+
+	return idx.projector.Run(ctx, schedule, idx)
+}
+
+// Status returns the projection status of the Index.
+func (idx *Index) Status() projector.Status {
+	return idx.projector.Status()
+}
+
+// ApplyEvent applies aggregate events.
+func (idx *Index) ApplyEvent(evt event.Event) {
+	idx.mux.Lock()
+
+	switch data := evt.Data().(type) {
+	case gallery.ImageUploadedData:
+		idx.setStack(data.Stack)
+	case gallery.ImageReplacedData:
+		idx.setStack(data.Stack)
+	case gallery.StackUpdatedData:
+		idx.setStack(data.Stack)
+	case gallery.StackProcessedData:
+		idx.setStack(data.Stack)
+	case gallery.StackRelocatedData:
+		idx.setStack(data.Stack)
+	case gallery.StackDeletedData:
+		idx.setFiles(data.Stack.ID, nil)
+	case gallery.StackPurgedData:
+		idx.setFiles(data.Stack.ID, nil)
+	case gallery.DeletedData:
+		for _, id := range data.Stacks {
+			idx.setFiles(id, nil)
+		}
+	case document.DocumentAddedData:
+		idx.setDocument(data.Document)
+	case document.DocumentReplacedData:
+		idx.setDocument(data.Document)
+	case document.DocumentRemovedData:
+		idx.setFiles(data.Document.ID, nil)
+	case document.DocumentPurgedData:
+		idx.setFiles(data.Document.ID, nil)
+	case document.ShelfDeletedData:
+		for _, id := range data.Documents {
+			idx.setFiles(id, nil)
+		}
+	case document.DocumentMovedData:
+		from := file{data.Disk, data.OldPath}
+		if data.OldDisk != "" {
+			from.disk = data.OldDisk
+		}
+		idx.moveFile(data.DocumentID, from, file{data.Disk, data.Path})
+	case document.VariantAddedData:
+		idx.addFile(data.DocumentID, data.Variant.File)
+	case document.VariantRemovedData:
+		idx.removeFile(data.DocumentID, data.Variant.File)
+	case document.PreviewRenderedData:
+		idx.addFile(data.DocumentID, data.Image.File)
+	}
+
+	alerts := idx.checkLevels(evt.Time())
+	ctx := idx.ctx
+
+	idx.mux.Unlock()
+
+	for _, alert := range alerts {
+		for _, fn := range idx.handlers {
+			fn(ctx, alert)
+		}
+	}
+}
+
+func (idx *Index) setStack(stack gallery.Stack) {
+	files := make(map[file]int64, len(stack.Images)+1)
+	for _, img := range stack.Images {
+		files[fileOf(img.File)] = int64(img.Filesize)
+	}
+	if stack.Video != nil {
+		files[fileOf(stack.Video.File)] = int64(stack.Video.Filesize)
+	}
+	idx.setFiles(stack.ID, files)
+}
+
+func (idx *Index) setDocument(doc document.Document) {
+	files := make(map[file]int64, len(doc.Variants)+2)
+	files[fileOf(doc.File)] = int64(doc.Filesize)
+	for _, v := range doc.Variants {
+		files[fileOf(v.File)] = int64(v.Filesize)
+	}
+	if doc.Preview != nil && doc.Preview.Status == document.PreviewStatusRendered {
+		files[fileOf(doc.Preview.Image.File)] = int64(doc.Preview.Image.Filesize)
+	}
+	idx.setFiles(doc.ID, files)
+}
+
+// setFiles replaces the files of the given owner. If files is nil, the owner
+// is removed.
+func (idx *Index) setFiles(owner uuid.UUID, files map[file]int64) {
+	for f, size := range idx.owners[owner] {
+		idx.disks[f.disk] -= size
+	}
+	if files == nil {
+		delete(idx.owners, owner)
+		return
+	}
+	for f, size := range files {
+		idx.disks[f.disk] += size
+	}
+	idx.owners[owner] = files
+}
+
+func (idx *Index) addFile(owner uuid.UUID, f media.File) {
+	files, ok := idx.owners[owner]
+	if !ok {
+		files = make(map[file]int64)
+		idx.owners[owner] = files
+	}
+	key := fileOf(f)
+	idx.disks[key.disk] += int64(f.Filesize) - files[key]
+	files[key] = int64(f.Filesize)
+}
+
+func (idx *Index) removeFile(owner uuid.UUID, f media.File) {
+	files := idx.owners[owner]
+	key := fileOf(f)
+	if size, ok := files[key]; ok {
+		idx.disks[key.disk] -= size
+		delete(files, key)
+	}
+}
+
+func (idx *Index) moveFile(owner uuid.UUID, from, to file) {
+	files := idx.owners[owner]
+	if size, ok := files[from]; ok {
+		delete(files, from)
+		files[to] = size
+		idx.disks[from.disk] -= size
+		idx.disks[to.disk] += size
+	}
+}
+
+// checkLevels updates the levels of all disks and returns an Alert for every
+// disk whose level has changed.
+func (idx *Index) checkLevels(now time.Time) []Alert {
+	names := make([]string, 0, len(idx.disks))
+	for disk := range idx.disks {
+		names = append(names, disk)
+	}
+	sort.Strings(names)
+
+	var alerts []Alert
+	for _, name := range names {
+		d := idx.disk(name, idx.disks[name])
+
+		prev, ok := idx.levels[name]
+		if !ok {
+			prev = LevelOK
+		}
+		idx.levels[name] = d.Level
+
+		if d.Level != prev {
+			alerts = append(alerts, Alert{
+				Disk:       name,
+				Usage:      d.Usage,
+				Level:      d.Level,
+				Previous:   prev,
+				Thresholds: d.Thresholds,
+				Time:       now,
+			})
+		}
+	}
+
+	return alerts
+}
+
+func fileOf(f media.File) file {
+	return file{disk: f.Disk, path: f.Location()}
+}
@@ -0,0 +1,273 @@
+package usage_test
+
+import (
+	"context"
+	"encoding/json"
+	"image/color"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/media/usage"
+)
+
+const exampleDisk = "foo-disk"
+
+func TestIndex_gallery(t *testing.T) {
+	idx := usage.NewIndex()
+
+	stack := gallery.Stack{
+		ID: uuid.New(),
+		Images: []gallery.Image{
+			{Image: media.NewImage(0, 0, "foo", exampleDisk, "/foo.png", 100), Original: true},
+		},
+	}
+
+	apply(idx, gallery.ImageUploaded, gallery.ImageUploadedData{Stack: stack})
+	assertUsage(t, idx, 100)
+
+	stack.Images = append(stack.Images, gallery.Image{Image: media.NewImage(0, 0, "foo", exampleDisk, "/foo_small.png", 30), Size: "small"})
+	apply(idx, gallery.StackProcessed, gallery.StackProcessedData{Stack: stack})
+	assertUsage(t, idx, 130)
+
+	// Applying the same event again must not count the files twice.
+	apply(idx, gallery.StackProcessed, gallery.StackProcessedData{Stack: stack})
+	assertUsage(t, idx, 130)
+
+	apply(idx, gallery.StackDeleted, gallery.StackDeletedData{Stack: stack})
+	assertUsage(t, idx, 0)
+}
+
+func TestIndex_documents(t *testing.T) {
+	idx := usage.NewIndex()
+
+	doc := document.Document{
+		Document: media.NewDocument("foo", exampleDisk, "/foo.pdf", 100),
+		ID:       uuid.New(),
+	}
+
+	apply(idx, document.DocumentAdded, document.DocumentAddedData{Document: doc})
+	assertUsage(t, idx, 100)
+
+	variant := media.NewDocument("foo", exampleDisk, "/foo.de.pdf", 50)
+	apply(idx, document.VariantAdded, document.VariantAddedData{DocumentID: doc.ID, Locale: "de", Variant: variant})
+	assertUsage(t, idx, 150)
+
+	preview := media.NewImage(80, 60, "foo", exampleDisk, "/foo.preview.png", 10)
+	apply(idx, document.PreviewRendered, document.PreviewRenderedData{DocumentID: doc.ID, Image: preview})
+	assertUsage(t, idx, 160)
+
+	apply(idx, document.DocumentMoved, document.DocumentMovedData{DocumentID: doc.ID, Disk: exampleDisk, OldPath: "/foo.pdf", Path: "/bar.pdf"})
+	assertUsage(t, idx, 160)
+
+	apply(idx, document.VariantRemoved, document.VariantRemovedData{DocumentID: doc.ID, Locale: "de", Variant: variant})
+	assertUsage(t, idx, 110)
+
+	apply(idx, document.DocumentMoved, document.DocumentMovedData{DocumentID: doc.ID, Disk: "archive", OldDisk: exampleDisk, OldPath: "/bar.pdf", Path: "/bar.pdf"})
+	assertUsage(t, idx, 10)
+	if got := idx.Usage("archive"); got != 100 {
+		t.Fatalf("usage of %q should be %d; is %d", "archive", 100, got)
+	}
+
+	doc.Path = "/bar.pdf"
+	apply(idx, document.DocumentRemoved, document.DocumentRemovedData{Document: doc})
+	assertUsage(t, idx, 0)
+}
+
+func TestIndex_alerts(t *testing.T) {
+	var alerts []usage.Alert
+	idx := usage.NewIndex(
+		usage.Threshold(exampleDisk, 100, 200),
+		usage.OnAlert(func(_ context.Context, alert usage.Alert) {
+			alerts = append(alerts, alert)
+		}),
+	)
+
+	stack := newStack(50)
+	apply(idx, gallery.ImageUploaded, gallery.ImageUploadedData{Stack: stack})
+
+	if len(alerts) != 0 {
+		t.Fatalf("no Alert should be emitted below the warning threshold; got %v", alerts)
+	}
+
+	other := newStack(60)
+	apply(idx, gallery.ImageUploaded, gallery.ImageUploadedData{Stack: other})
+	assertAlert(t, alerts, 1, usage.LevelOK, usage.LevelWarning)
+
+	third := newStack(100)
+	apply(idx, gallery.ImageUploaded, gallery.ImageUploadedData{Stack: third})
+	assertAlert(t, alerts, 2, usage.LevelWarning, usage.LevelCritical)
+
+	// Staying within a level must not emit another Alert.
+	apply(idx, gallery.ImageUploaded, gallery.ImageUploadedData{Stack: newStack(10)})
+	if len(alerts) != 2 {
+		t.Fatalf("no Alert should be emitted if the level doesn't change; got %d Alerts", len(alerts))
+	}
+
+	apply(idx, gallery.StackDeleted, gallery.StackDeletedData{Stack: third})
+	apply(idx, gallery.StackDeleted, gallery.StackDeletedData{Stack: other})
+	assertAlert(t, alerts, 4, usage.LevelWarning, usage.LevelOK)
+
+	if alerts[3].Usage != 60 {
+		t.Fatalf("Alert should report the usage %d; got %d", 60, alerts[3].Usage)
+	}
+
+	disks := idx.Disks()
+	want := usage.Disk{Usage: 60, Level: usage.LevelOK, Thresholds: usage.Thresholds{Warning: 100, Critical: 200}}
+	if disks[exampleDisk] != want {
+		t.Fatalf("Disks should report %v for %q; got %v", want, exampleDisk, disks[exampleDisk])
+	}
+}
+
+func TestIndex_Project(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	galleries := gallery.GoesRepository(repository.New(estore))
+	storage := media.NewStorage(media.ConfigureDisk(exampleDisk, media.MemoryDisk()))
+
+	idx := usage.NewIndex()
+
+	errs, err := idx.Project(ctx, ebus, estore)
+	if err != nil {
+		t.Fatalf("project Index: %v", err)
+	}
+	go func() {
+		for err := range errs {
+			panic(err)
+		}
+	}()
+
+	g := gallery.New(uuid.New())
+	g.Create("foo")
+	_, buf := imggen.ColoredRectangle(80, 60, color.Black)
+	stack, err := g.Upload(ctx, storage, buf, "foo", exampleDisk, "/foo.png")
+	if err != nil {
+		t.Fatalf("upload image: %v", err)
+	}
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save Gallery: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	assertUsage(t, idx, int64(stack.Original().Filesize))
+}
+
+func TestPublish(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := eventbus.New()
+	events, _, err := bus.Subscribe(ctx, usage.LevelChanged)
+	if err != nil {
+		t.Fatalf("subscribe to %q event: %v", usage.LevelChanged, err)
+	}
+
+	alert := usage.Alert{Disk: exampleDisk, Usage: 100, Level: usage.LevelWarning, Previous: usage.LevelOK}
+	go usage.Publish(bus)(ctx, alert)
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("timed out")
+	case evt := <-events:
+		if data := evt.Data().(usage.Alert); data != alert {
+			t.Fatalf("event data should be %v; got %v", alert, data)
+		}
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	received := make(chan usage.Alert, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert usage.Alert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			t.Errorf("decode alert: %v", err)
+		}
+		received <- alert
+	}))
+	defer srv.Close()
+
+	alert := usage.Alert{
+		Disk:     exampleDisk,
+		Usage:    200,
+		Level:    usage.LevelCritical,
+		Previous: usage.LevelWarning,
+		Time:     time.Now().UTC().Truncate(time.Second),
+	}
+
+	usage.Webhook(srv.URL)(context.Background(), alert)
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("timed out")
+	case got := <-received:
+		if !reflect.DeepEqual(got, alert) {
+			t.Fatalf("webhook should receive %v; got %v", alert, got)
+		}
+	}
+}
+
+func TestWebhook_error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	errs := make(chan error, 1)
+	usage.Webhook(srv.URL, usage.WebhookErrors(func(err error) { errs <- err }))(context.Background(), usage.Alert{})
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("timed out")
+	case err := <-errs:
+		if err == nil {
+			t.Fatalf("webhook should report an error for a non-2xx status")
+		}
+	}
+}
+
+func apply(idx *usage.Index, name string, data any) {
+	idx.ApplyEvent(event.New(name, data).Any())
+}
+
+func newStack(size int) gallery.Stack {
+	id := uuid.New()
+	return gallery.Stack{
+		ID: id,
+		Images: []gallery.Image{
+			{Image: media.NewImage(0, 0, "foo", exampleDisk, "/"+id.String()+".png", size), Original: true},
+		},
+	}
+}
+
+func assertUsage(t *testing.T, idx *usage.Index, want int64) {
+	t.Helper()
+	if got := idx.Usage(exampleDisk); got != want {
+		t.Fatalf("usage of %q should be %d; is %d", exampleDisk, want, got)
+	}
+}
+
+func assertAlert(t *testing.T, alerts []usage.Alert, n int, from, to usage.Level) {
+	t.Helper()
+	if len(alerts) != n {
+		t.Fatalf("%d Alerts should have been emitted; got %d", n, len(alerts))
+	}
+	alert := alerts[n-1]
+	if alert.Disk != exampleDisk || alert.Previous != from || alert.Level != to {
+		t.Fatalf("Alert should change the level of %q from %q to %q; got %v", exampleDisk, from, to, alert)
+	}
+}
@@ -1,30 +1,57 @@
 package media
 
-// import (
-// 	"context"
-// 	"io"
-// 	"time"
-// )
-
-// // VideoService provides uploads and retrieval of videos.
-// type VideoService interface {
-// 	// Upload uploads a video to the specified disk and path and returns the
-// 	// uploaded Video.
-// 	Upload(_ context.Context, _ io.Reader, name, disk, path string) (Video, error)
-
-// 	// Delete deletes the video at the specified disk and path. Delete returns
-// 	// ErrFileNotFound if the video does not exist.
-// 	Delete(_ context.Context, disk, path string) error
-
-// 	// Get returns
-// 	Get(_ context.Context, disk, path string) (Video, error)
-// }
-
-// // Video is an uploaded video.
-// type Video struct {
-// 	File
-
-// 	Width    int
-// 	Height   int
-// 	Duration time.Duration
-// }
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Video is a storage video. The dimensions, duration and codec of a Video
+// cannot be read by Go itself. They are filled in by a prober (e.g. the
+// ffprobe-based prober in the media/video package) after the upload.
+type Video struct {
+	File
+
+	Width    int           `json:"width"`
+	Height   int           `json:"height"`
+	Duration time.Duration `json:"duration"`
+
+	// Codec is the name of the codec of the video stream (e.g. "h264").
+	Codec string `json:"codec,omitempty"`
+}
+
+// NewVideo returns a Video with the given data.
+func NewVideo(name, disk, path string, filesize int) Video {
+	return Video{File: NewFile(name, disk, path, filesize)}
+}
+
+// WithTag adds the given tags and returns the updated Video.
+func (v Video) WithTag(tags ...string) Video {
+	v.File = v.File.WithTag(tags...)
+	return v
+}
+
+// WithoutTag removes the given tags and returns the updated Video.
+func (v Video) WithoutTag(tags ...string) Video {
+	v.File = v.File.WithoutTag(tags...)
+	return v
+}
+
+// Upload uploads the video to storage and returns the Video with updated
+// Filesize.
+func (v Video) Upload(ctx context.Context, r io.Reader, storage Storage) (Video, error) {
+	f, err := v.File.Upload(ctx, r, storage)
+	if err != nil {
+		return v, err
+	}
+	v.File = f
+	return v, nil
+}
+
+// Replace replaces the video in Storage with the video in r and returns the
+// updated Video. Width, Height, Duration and Codec are reset, because they
+// are unknown until the new video has been probed.
+func (v Video) Replace(ctx context.Context, r io.Reader, storage Storage) (Video, error) {
+	v.Width, v.Height, v.Duration, v.Codec = 0, 0, 0, ""
+	return v.Upload(ctx, r, storage)
+}
@@ -0,0 +1,245 @@
+// Package video probes videos and extracts frames from them using the ffprobe
+// and ffmpeg command-line tools. The metadata of audio files can be extracted
+// using the same tools.
+package video
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/modernice/nice-cms/media"
+)
+
+// ErrNoVideoStream is returned by Probe if the probed file has no video stream.
+var ErrNoVideoStream = errors.New("no video stream")
+
+// Info is the metadata of a video.
+type Info struct {
+	Width    int
+	Height   int
+	Duration time.Duration
+
+	// Codec is the name of the codec of the video stream (e.g. "h264").
+	Codec string
+}
+
+// A Prober returns the metadata of a video. FFmpeg implements Prober.
+type Prober interface {
+	Probe(context.Context, io.Reader) (Info, error)
+}
+
+// A FrameExtractor extracts the frame at the given offset of a video and writes
+// it as an image to w. FFmpeg implements FrameExtractor.
+type FrameExtractor interface {
+	Frame(_ context.Context, w io.Writer, r io.Reader, at time.Duration) error
+}
+
+// FFmpeg probes videos using ffprobe and extracts frames using ffmpeg. FFmpeg
+// also implements media.AudioMetadataExtractor.
+type FFmpeg struct {
+	// FFprobe is the path to the ffprobe executable. Defaults to "ffprobe".
+	FFprobe string
+
+	// FFmpeg is the path to the ffmpeg executable. Defaults to "ffmpeg".
+	FFmpeg string
+}
+
+// Probe writes the video in r to a temporary file and returns the metadata of
+// its first video stream. If the video has no video stream, ErrNoVideoStream is
+// returned.
+func (f FFmpeg) Probe(ctx context.Context, r io.Reader) (Info, error) {
+	dir, input, err := tempInput(r)
+	if err != nil {
+		return Info{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	binary := f.FFprobe
+	if binary == "" {
+		binary = "ffprobe"
+	}
+
+	out, err := run(ctx, binary,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height,codec_name:format=duration",
+		"-of", "json",
+		input,
+	)
+	if err != nil {
+		return Info{}, err
+	}
+
+	var probe struct {
+		Streams []struct {
+			Width     int    `json:"width"`
+			Height    int    `json:"height"`
+			CodecName string `json:"codec_name"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return Info{}, fmt.Errorf("decode %q output: %w", binary, err)
+	}
+
+	if len(probe.Streams) == 0 {
+		return Info{}, ErrNoVideoStream
+	}
+
+	info := Info{
+		Width:  probe.Streams[0].Width,
+		Height: probe.Streams[0].Height,
+		Codec:  probe.Streams[0].CodecName,
+	}
+
+	if probe.Format.Duration != "" {
+		secs, err := strconv.ParseFloat(probe.Format.Duration, 64)
+		if err != nil {
+			return info, fmt.Errorf("parse duration %q: %w", probe.Format.Duration, err)
+		}
+		info.Duration = time.Duration(secs * float64(time.Second))
+	}
+
+	return info, nil
+}
+
+// ExtractAudioMetadata writes the audio file in r to a temporary file and
+// returns its duration and bitrate.
+func (f FFmpeg) ExtractAudioMetadata(ctx context.Context, r io.Reader) (media.AudioMetadata, error) {
+	dir, input, err := tempInput(r)
+	if err != nil {
+		return media.AudioMetadata{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	binary := f.FFprobe
+	if binary == "" {
+		binary = "ffprobe"
+	}
+
+	out, err := run(ctx, binary,
+		"-v", "error",
+		"-show_entries", "format=duration,bit_rate",
+		"-of", "json",
+		input,
+	)
+	if err != nil {
+		return media.AudioMetadata{}, err
+	}
+
+	var probe struct {
+		Format struct {
+			Duration string `json:"duration"`
+			BitRate  string `json:"bit_rate"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(out, &probe); err != nil {
+		return media.AudioMetadata{}, fmt.Errorf("decode %q output: %w", binary, err)
+	}
+
+	var meta media.AudioMetadata
+
+	if probe.Format.Duration != "" {
+		secs, err := strconv.ParseFloat(probe.Format.Duration, 64)
+		if err != nil {
+			return meta, fmt.Errorf("parse duration %q: %w", probe.Format.Duration, err)
+		}
+		meta.Duration = time.Duration(secs * float64(time.Second))
+	}
+
+	if probe.Format.BitRate != "" {
+		if meta.Bitrate, err = strconv.Atoi(probe.Format.BitRate); err != nil {
+			return meta, fmt.Errorf("parse bitrate %q: %w", probe.Format.BitRate, err)
+		}
+	}
+
+	return meta, nil
+}
+
+// Frame writes the video in r to a temporary file, extracts the frame at the
+// given offset as a PNG image and writes the image to w.
+func (f FFmpeg) Frame(ctx context.Context, w io.Writer, r io.Reader, at time.Duration) error {
+	dir, input, err := tempInput(r)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	binary := f.FFmpeg
+	if binary == "" {
+		binary = "ffmpeg"
+	}
+
+	output := filepath.Join(dir, "frame.png")
+
+	if _, err := run(ctx, binary,
+		"-v", "error",
+		"-ss", strconv.FormatFloat(at.Seconds(), 'f', 3, 64),
+		"-i", input,
+		"-frames:v", "1",
+		"-y", output,
+	); err != nil {
+		return err
+	}
+
+	frame, err := os.Open(output)
+	if err != nil {
+		return fmt.Errorf("open extracted frame: %w", err)
+	}
+	defer frame.Close()
+
+	if _, err := io.Copy(w, frame); err != nil {
+		return fmt.Errorf("write frame: %w", err)
+	}
+
+	return nil
+}
+
+func tempInput(r io.Reader) (string, string, error) {
+	dir, err := os.MkdirTemp("", "nice-cms-video-*")
+	if err != nil {
+		return "", "", fmt.Errorf("create temp dir: %w", err)
+	}
+
+	input := filepath.Join(dir, "input")
+	f, err := os.Create(input)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", "", fmt.Errorf("create temp file: %w", err)
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.RemoveAll(dir)
+		return "", "", fmt.Errorf("write video: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		os.RemoveAll(dir)
+		return "", "", fmt.Errorf("close temp file: %w", err)
+	}
+
+	return dir, input, nil
+}
+
+func run(ctx context.Context, binary string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("run %q: %w: %s", binary, err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
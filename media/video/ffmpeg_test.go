@@ -0,0 +1,88 @@
+package video_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/internal/videotest"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/video"
+)
+
+func TestFFmpeg_Probe(t *testing.T) {
+	want := videotest.Info()
+	ff := videotest.FFmpeg(t, want, nil)
+
+	info, err := ff.Probe(context.Background(), strings.NewReader("foo"))
+	if err != nil {
+		t.Fatalf("Probe failed with %q", err)
+	}
+
+	if info != want {
+		t.Fatalf("Probe should return %+v; got %+v", want, info)
+	}
+}
+
+func TestFFmpeg_Probe_noVideoStream(t *testing.T) {
+	dir := t.TempDir()
+	ffprobe := filepath.Join(dir, "ffprobe")
+	if err := os.WriteFile(ffprobe, []byte("#!/bin/sh\necho '{\"streams\":[],\"format\":{}}'\n"), 0755); err != nil {
+		t.Fatalf("write ffprobe script: %v", err)
+	}
+
+	ff := video.FFmpeg{FFprobe: ffprobe}
+
+	if _, err := ff.Probe(context.Background(), strings.NewReader("foo")); !errors.Is(err, video.ErrNoVideoStream) {
+		t.Fatalf("Probe should fail with %q; got %v", video.ErrNoVideoStream, err)
+	}
+}
+
+func TestFFmpeg_ExtractAudioMetadata(t *testing.T) {
+	dir := t.TempDir()
+	ffprobe := filepath.Join(dir, "ffprobe")
+	if err := os.WriteFile(ffprobe, []byte("#!/bin/sh\necho '{\"format\":{\"duration\":\"184.250\",\"bit_rate\":\"128000\"}}'\n"), 0755); err != nil {
+		t.Fatalf("write ffprobe script: %v", err)
+	}
+
+	ff := video.FFmpeg{FFprobe: ffprobe}
+
+	meta, err := ff.ExtractAudioMetadata(context.Background(), strings.NewReader("foo"))
+	if err != nil {
+		t.Fatalf("ExtractAudioMetadata failed with %q", err)
+	}
+
+	want := media.AudioMetadata{Duration: 184250 * time.Millisecond, Bitrate: 128000}
+	if meta != want {
+		t.Fatalf("ExtractAudioMetadata should return %+v; got %+v", want, meta)
+	}
+}
+
+func TestFFmpeg_Frame(t *testing.T) {
+	_, frame := imggen.ColoredRectangle(64, 36, color.RGBA{255, 0, 0, 255})
+	ff := videotest.FFmpeg(t, videotest.Info(), frame.Bytes())
+
+	var buf bytes.Buffer
+	if err := ff.Frame(context.Background(), &buf, strings.NewReader("foo"), 0); err != nil {
+		t.Fatalf("Frame failed with %q", err)
+	}
+
+	if !bytes.Equal(buf.Bytes(), frame.Bytes()) {
+		t.Fatalf("Frame should write the extracted frame")
+	}
+}
+
+func TestFFmpeg_missingBinary(t *testing.T) {
+	ff := video.FFmpeg{FFprobe: filepath.Join(t.TempDir(), "ffprobe")}
+
+	if _, err := ff.Probe(context.Background(), strings.NewReader("foo")); err == nil {
+		t.Fatalf("Probe should fail if ffprobe is missing")
+	}
+}
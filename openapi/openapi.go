@@ -0,0 +1,230 @@
+// Package openapi builds OpenAPI documents that describe HTTP APIs.
+//
+// Documents use OpenAPI 3.1, whose Schema Objects are JSON Schemas, so that
+// request and response bodies are described by the Schemas of package schema:
+//
+//	doc := openapi.New(openapi.Info{Title: "Blog API", Version: "1.0.0"})
+//	doc.Add("POST", "/posts", openapi.Operation{
+//		Summary:     "Create post",
+//		Tags:        []string{"posts"},
+//		RequestBody: openapi.JSONBody(schema.Of(createPostRequest{})),
+//		Responses: openapi.Responses{
+//			"201": openapi.JSONResponse("The created post.", schema.Of(Post{})),
+//		},
+//	})
+//
+// Path parameters are written like chi route parameters ("/posts/{PostID}")
+// and are added to the Operations that don't describe them.
+package openapi
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/modernice/nice-cms/schema"
+)
+
+// Version is the OpenAPI version of generated Documents.
+const Version = "3.1.0"
+
+// Document is an OpenAPI document.
+type Document struct {
+	OpenAPI string              `json:"openapi"`
+	Info    Info                `json:"info"`
+	Tags    []Tag               `json:"tags,omitempty"`
+	Paths   map[string]PathItem `json:"paths"`
+}
+
+// Info describes the API of a Document.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Tag groups Operations, e.g. in generated SDKs and documentation.
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem are the Operations of a path, keyed by lower-case HTTP method.
+type PathItem map[string]*Operation
+
+// Operation describes a single API operation on a path.
+type Operation struct {
+	OperationID string       `json:"operationId,omitempty"`
+	Summary     string       `json:"summary,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Parameters  []Parameter  `json:"parameters,omitempty"`
+	RequestBody *RequestBody `json:"requestBody,omitempty"`
+	Responses   Responses    `json:"responses"`
+}
+
+// Parameter describes a path or query parameter of an Operation.
+type Parameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *schema.Schema `json:"schema,omitempty"`
+}
+
+// RequestBody describes the request body of an Operation.
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// Responses are the responses of an Operation, keyed by status code or
+// "default".
+type Responses map[string]*Response
+
+// Response describes a response of an Operation.
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Header describes a response header.
+type Header struct {
+	Description string         `json:"description,omitempty"`
+	Schema      *schema.Schema `json:"schema,omitempty"`
+}
+
+// MediaType describes the body of a request or response of a content type.
+type MediaType struct {
+	Schema *schema.Schema `json:"schema,omitempty"`
+}
+
+// New returns an empty Document.
+func New(info Info) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+	}
+}
+
+// Add adds the Operation for the given method and path. An Operation that was
+// added before for the same method and path is replaced. Path parameters that
+// op doesn't describe are added as required string parameters.
+func (d *Document) Add(method, path string, op Operation) {
+	for _, name := range PathParams(path) {
+		if !hasParam(op.Parameters, name, "path") {
+			op.Parameters = append(op.Parameters, PathParam(name, ""))
+		}
+	}
+
+	item, ok := d.Paths[path]
+	if !ok {
+		item = make(PathItem)
+		d.Paths[path] = item
+	}
+	item[strings.ToLower(method)] = &op
+}
+
+// Operation returns the Operation for the given method and path.
+func (d *Document) Operation(method, path string) (*Operation, bool) {
+	op, ok := d.Paths[path][strings.ToLower(method)]
+	return op, ok
+}
+
+// Merge adds the Operations and Tags of other to d. Operations of other
+// replace the Operations of d for the same method and path.
+func (d *Document) Merge(other *Document) {
+	for path, item := range other.Paths {
+		for method, op := range item {
+			d.Add(method, path, *op)
+		}
+	}
+
+	for _, tag := range other.Tags {
+		d.AddTag(tag)
+	}
+}
+
+// AddTag adds a Tag to d, or replaces the Tag with the same name. Tags are
+// sorted by name.
+func (d *Document) AddTag(tag Tag) {
+	for i, t := range d.Tags {
+		if t.Name == tag.Name {
+			d.Tags[i] = tag
+			return
+		}
+	}
+	d.Tags = append(d.Tags, tag)
+	sort.Slice(d.Tags, func(i, j int) bool { return d.Tags[i].Name < d.Tags[j].Name })
+}
+
+// PathParams returns the names of the parameters of path in the order they
+// appear in the path.
+func PathParams(path string) []string {
+	var names []string
+	for {
+		start := strings.IndexByte(path, '{')
+		if start < 0 {
+			return names
+		}
+		end := strings.IndexByte(path[start:], '}')
+		if end < 0 {
+			return names
+		}
+		names = append(names, path[start+1:start+end])
+		path = path[start+end+1:]
+	}
+}
+
+// PathParam returns a required string path parameter.
+func PathParam(name, desc string) Parameter {
+	return Parameter{
+		Name:        name,
+		In:          "path",
+		Description: desc,
+		Required:    true,
+		Schema:      &schema.Schema{Type: "string"},
+	}
+}
+
+// QueryParam returns an optional query parameter with the given Schema.
+func QueryParam(name, desc string, s *schema.Schema) Parameter {
+	return Parameter{
+		Name:        name,
+		In:          "query",
+		Description: desc,
+		Schema:      s,
+	}
+}
+
+// JSONBody returns a required JSON request body with the given Schema.
+func JSONBody(s *schema.Schema) *RequestBody {
+	return &RequestBody{
+		Required: true,
+		Content:  map[string]MediaType{"application/json": {Schema: s}},
+	}
+}
+
+// JSONResponse returns a JSON response with the given Schema.
+func JSONResponse(desc string, s *schema.Schema) *Response {
+	return &Response{
+		Description: desc,
+		Content:     map[string]MediaType{"application/json": {Schema: s}},
+	}
+}
+
+// EmptyResponse returns a response without a body.
+func EmptyResponse(desc string) *Response {
+	return &Response{Description: desc}
+}
+
+func hasParam(params []Parameter, name, in string) bool {
+	for _, p := range params {
+		if p.Name == name && p.In == in {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/modernice/nice-cms/openapi"
+	"github.com/modernice/nice-cms/schema"
+)
+
+type createRequest struct {
+	Name string `json:"name" schema:"required"`
+}
+
+func TestDocument_Add(t *testing.T) {
+	doc := openapi.New(openapi.Info{Title: "foo", Version: "1.0.0"})
+
+	doc.Add("PATCH", "/shelfs/{ShelfID}/documents/{DocumentID}", openapi.Operation{
+		Summary:     "Update document",
+		Parameters:  []openapi.Parameter{openapi.PathParam("ShelfID", "UUID of the shelf.")},
+		RequestBody: openapi.JSONBody(schema.Of(createRequest{})),
+		Responses:   openapi.Responses{"204": openapi.EmptyResponse("Updated.")},
+	})
+
+	op, ok := doc.Operation("patch", "/shelfs/{ShelfID}/documents/{DocumentID}")
+	if !ok {
+		t.Fatalf("Document should contain the added Operation")
+	}
+
+	var names []string
+	for _, p := range op.Parameters {
+		if p.In != "path" || !p.Required {
+			t.Fatalf("parameter %q should be a required path parameter", p.Name)
+		}
+		names = append(names, p.Name)
+	}
+	if want := []string{"ShelfID", "DocumentID"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("path parameters should be %v; got %v", want, names)
+	}
+	if op.Parameters[0].Description != "UUID of the shelf." {
+		t.Fatalf("described path parameters should not be replaced")
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal Document: %v", err)
+	}
+
+	var raw struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		t.Fatalf("unmarshal Document: %v", err)
+	}
+	if raw.OpenAPI != openapi.Version {
+		t.Fatalf("openapi should be %q; is %q", openapi.Version, raw.OpenAPI)
+	}
+	if _, ok := raw.Paths["/shelfs/{ShelfID}/documents/{DocumentID}"]["patch"]; !ok {
+		t.Fatalf("Operation should be keyed by the lower-case method; got %s", b)
+	}
+}
+
+func TestDocument_Merge(t *testing.T) {
+	doc := openapi.New(openapi.Info{Title: "foo", Version: "1.0.0"})
+	doc.Add("GET", "/foo", openapi.Operation{Summary: "foo"})
+	doc.Add("GET", "/bar", openapi.Operation{Summary: "bar"})
+	doc.AddTag(openapi.Tag{Name: "foo"})
+
+	other := openapi.New(openapi.Info{Title: "bar", Version: "2.0.0"})
+	other.Add("GET", "/bar", openapi.Operation{Summary: "baz"})
+	other.Add("POST", "/bar", openapi.Operation{Summary: "create bar"})
+	other.AddTag(openapi.Tag{Name: "bar"})
+
+	doc.Merge(other)
+
+	for _, tt := range []struct{ method, path, summary string }{
+		{"GET", "/foo", "foo"},
+		{"GET", "/bar", "baz"},
+		{"POST", "/bar", "create bar"},
+	} {
+		op, ok := doc.Operation(tt.method, tt.path)
+		if !ok || op.Summary != tt.summary {
+			t.Fatalf("%s %s should have summary %q; got %v", tt.method, tt.path, tt.summary, op)
+		}
+	}
+
+	if want := []openapi.Tag{{Name: "bar"}, {Name: "foo"}}; !reflect.DeepEqual(doc.Tags, want) {
+		t.Fatalf("Tags should be %v; got %v", want, doc.Tags)
+	}
+
+	if doc.Info.Title != "foo" {
+		t.Fatalf("Merge should not change the Info of the Document")
+	}
+}
+
+func TestPathParams(t *testing.T) {
+	got := openapi.PathParams("/galleries/{GalleryID}/stacks/{StackID}/tags/{Tags}")
+	if want := []string{"GalleryID", "StackID", "Tags"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("PathParams should return %v; got %v", want, got)
+	}
+}
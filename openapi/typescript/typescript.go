@@ -0,0 +1,435 @@
+// Package typescript generates TypeScript clients from OpenAPI documents.
+//
+// The generated module exports a function for each Operation of the document
+// that calls the Operation using an AxiosInstance (see createClient of
+// @nice-cms/core), and the types of its request body, query and response:
+//
+//	export async function showGallery(
+//	  client: AxiosInstance,
+//	  galleryId: string,
+//	  query: ShowGalleryQuery = {},
+//	  config?: AxiosRequestConfig
+//	): Promise<ShowGalleryResponse>
+//
+// Functions are named after the operation IDs; Operations without an operation
+// ID and Operations that stream Server-Sent Events are skipped.
+package typescript
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/modernice/nice-cms/openapi"
+	"github.com/modernice/nice-cms/schema"
+)
+
+// Header is the first line of generated modules.
+const Header = "// Code generated by github.com/modernice/nice-cms/openapi/typescript. DO NOT EDIT."
+
+var methodOrder = []string{"get", "head", "post", "put", "patch", "delete"}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Generate returns the TypeScript client module for the given Document.
+// Operations are generated in the order of their paths and methods, so that
+// the output is stable.
+func Generate(doc *openapi.Document) []byte {
+	g := generator{types: make(map[string]string)}
+	b := &g.b
+	b.WriteString(Header + "\n\n")
+	b.WriteString("import { AxiosInstance, AxiosRequestConfig } from 'axios'\n")
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		for _, method := range methodOrder {
+			op, ok := doc.Paths[path][method]
+			if !ok || op.OperationID == "" || streams(op) {
+				continue
+			}
+			b.WriteString("\n")
+			g.operation(method, path, op)
+		}
+	}
+
+	return []byte(b.String())
+}
+
+type generator struct {
+	b strings.Builder
+
+	// types maps the generated types to the name of the first type alias that
+	// was generated for them, so that identical types are aliased.
+	types map[string]string
+}
+
+func (g *generator) operation(method, path string, op *openapi.Operation) {
+	b := &g.b
+	name := pascal(op.OperationID)
+
+	type arg struct{ name, typ string }
+	args := []arg{{"client", "AxiosInstance"}}
+	url := "'" + path + "'"
+
+	if params := openapi.PathParams(path); len(params) > 0 {
+		url = path
+		for _, param := range params {
+			v := variable(param)
+			args = append(args, arg{v, "string"})
+			url = strings.Replace(url, "{"+param+"}", "${encodeURIComponent("+v+")}", 1)
+		}
+		url = "`" + url + "`"
+	}
+
+	query := querySchema(op.Parameters)
+
+	var body, bodyType string
+	if op.RequestBody != nil {
+		body, bodyType = requestBody(op.RequestBody)
+		switch body {
+		case "json":
+			bodyType = name + "Request"
+			g.writeType(bodyType, op.RequestBody.Content["application/json"].Schema)
+		case "form":
+			bodyType = name + "Form"
+			g.writeType(bodyType, op.RequestBody.Content["multipart/form-data"].Schema)
+		}
+		switch {
+		case op.RequestBody.Required:
+			args = append(args, arg{"body", bodyType})
+		case query != nil && len(query.Required) > 0:
+			args = append(args, arg{"body", bodyType + " | undefined"})
+		default:
+			args = append(args, arg{"body?", bodyType})
+		}
+	}
+
+	if query != nil {
+		g.writeType(name+"Query", query)
+		if len(query.Required) > 0 {
+			args = append(args, arg{"query", name + "Query"})
+		} else {
+			args = append(args, arg{"query", name + "Query = {}"})
+		}
+	}
+
+	args = append(args, arg{"config?", "AxiosRequestConfig"})
+
+	result, resultType := response(op.Responses)
+	if result == "json" {
+		for _, code := range successCodes(op.Responses) {
+			if mt, ok := op.Responses[code].Content["application/json"]; ok {
+				g.writeType(name+"Response", mt.Schema)
+				break
+			}
+		}
+		resultType = name + "Response"
+	}
+
+	writeDoc(b, "", op.Summary, op.Description)
+	fmt.Fprintf(b, "export async function %s(\n", op.OperationID)
+	for i, a := range args {
+		b.WriteString("  " + a.name + ": " + a.typ)
+		if i < len(args)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(b, "): Promise<%s> {\n", resultType)
+
+	if body == "form" {
+		writeForm(b, op.RequestBody.Content["multipart/form-data"].Schema)
+	}
+
+	if result == "void" {
+		b.WriteString("  await client.request({\n")
+	} else {
+		b.WriteString("  const { data } = await client.request({\n")
+	}
+	b.WriteString("    ...config,\n")
+	fmt.Fprintf(b, "    method: '%s',\n", strings.ToUpper(method))
+	fmt.Fprintf(b, "    url: %s,\n", url)
+	switch body {
+	case "form":
+		b.WriteString("    data: form,\n")
+	case "binary":
+		b.WriteString("    data: body,\n")
+		b.WriteString("    headers: { ...config?.headers, 'Content-Type': 'application/octet-stream' },\n")
+	case "json":
+		b.WriteString("    data: body,\n")
+	}
+	if query != nil {
+		b.WriteString("    params: query,\n")
+	}
+	if result == "blob" {
+		b.WriteString("    responseType: 'blob',\n")
+	}
+	b.WriteString("  })\n")
+	if result != "void" {
+		b.WriteString("  return data\n")
+	}
+	b.WriteString("}\n")
+}
+
+// requestBody returns the kind and TypeScript type of a request body.
+func requestBody(body *openapi.RequestBody) (string, string) {
+	if _, ok := body.Content["application/json"]; ok {
+		return "json", ""
+	}
+	if _, ok := body.Content["multipart/form-data"]; ok {
+		return "form", "FormData"
+	}
+	return "binary", "Blob | ArrayBuffer"
+}
+
+// response returns the kind and TypeScript type of the successful response of
+// an Operation.
+func response(res openapi.Responses) (string, string) {
+	for _, code := range successCodes(res) {
+		content := res[code].Content
+		if _, ok := content["application/json"]; ok {
+			return "json", ""
+		}
+		if len(content) > 0 {
+			return "blob", "Blob"
+		}
+	}
+	return "void", "void"
+}
+
+func successCodes(res openapi.Responses) []string {
+	var codes []string
+	for code := range res {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func streams(op *openapi.Operation) bool {
+	for _, code := range successCodes(op.Responses) {
+		if _, ok := op.Responses[code].Content["text/event-stream"]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// querySchema returns the object Schema of the query parameters, or nil if
+// there are none.
+func querySchema(params []openapi.Parameter) *schema.Schema {
+	var s *schema.Schema
+	for _, p := range params {
+		if p.In != "query" {
+			continue
+		}
+		if s == nil {
+			s = &schema.Schema{Type: "object", Properties: make(map[string]*schema.Schema)}
+		}
+		prop := &schema.Schema{Type: "string"}
+		if p.Schema != nil {
+			cp := *p.Schema
+			prop = &cp
+		}
+		if prop.Description == "" {
+			prop.Description = p.Description
+		}
+		s.Properties[p.Name] = prop
+		if p.Required {
+			s.Required = append(s.Required, p.Name)
+		}
+	}
+	return s
+}
+
+// writeType writes the type alias of a Schema. If an identical type was
+// written before, the alias refers to the previous alias instead.
+func (g *generator) writeType(name string, s *schema.Schema) {
+	typ := Type(s)
+	if prev, ok := g.types[typ]; ok {
+		typ = prev
+	} else {
+		g.types[typ] = name
+	}
+
+	desc := ""
+	if s != nil {
+		desc = s.Description
+	}
+	writeDoc(&g.b, "", desc, "")
+	fmt.Fprintf(&g.b, "export type %s = %s\n\n", name, typ)
+}
+
+// writeForm writes the FormData of a multipart request body. Files are
+// appended after the other fields, so that the server can stream them.
+func writeForm(b *strings.Builder, s *schema.Schema) {
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		fi, fj := s.Properties[names[i]].Format == "binary", s.Properties[names[j]].Format == "binary"
+		if fi != fj {
+			return fj
+		}
+		return names[i] < names[j]
+	})
+
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+
+	b.WriteString("  const form = new FormData()\n")
+	for _, name := range names {
+		value := "body." + name
+		if !identifier.MatchString(name) {
+			value = "body['" + name + "']"
+		}
+		if required[name] {
+			fmt.Fprintf(b, "  form.append('%s', %s)\n", name, value)
+			continue
+		}
+		fmt.Fprintf(b, "  if (%s !== undefined) {\n", value)
+		fmt.Fprintf(b, "    form.append('%s', %s)\n", name, value)
+		b.WriteString("  }\n")
+	}
+	b.WriteString("\n")
+}
+
+func writeDoc(b *strings.Builder, indent, summary, desc string) {
+	if summary == "" && desc == "" {
+		return
+	}
+	b.WriteString(indent + "/**\n")
+	if summary != "" {
+		writeLines(b, indent, sentence(summary))
+	}
+	if summary != "" && desc != "" {
+		b.WriteString(indent + " *\n")
+	}
+	if desc != "" {
+		writeLines(b, indent, desc)
+	}
+	b.WriteString(indent + " */\n")
+}
+
+// writeLines writes text as comment lines that are wrapped at 80 characters.
+func writeLines(b *strings.Builder, indent, text string) {
+	line := indent + " *"
+	for _, word := range strings.Fields(text) {
+		if len(line)+1+len(word) > 80 && line != indent+" *" {
+			b.WriteString(line + "\n")
+			line = indent + " *"
+		}
+		line += " " + word
+	}
+	b.WriteString(line + "\n")
+}
+
+// Type returns the TypeScript type of the given Schema. References and
+// Schemas without a type are typed as unknown.
+func Type(s *schema.Schema) string {
+	return tsType(s, "")
+}
+
+func tsType(s *schema.Schema, indent string) string {
+	if s == nil || s.Ref != "" {
+		return "unknown"
+	}
+
+	switch s.Type {
+	case "string":
+		if len(s.Enum) > 0 {
+			values := make([]string, len(s.Enum))
+			for i, v := range s.Enum {
+				values[i] = fmt.Sprintf("'%v'", v)
+			}
+			return strings.Join(values, " | ")
+		}
+		if s.Format == "binary" {
+			return "Blob"
+		}
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		item := tsType(s.Items, indent)
+		if strings.Contains(item, "|") {
+			return "Array<" + item + ">"
+		}
+		return item + "[]"
+	case "object":
+		if len(s.Properties) == 0 {
+			if s.AdditionalProperties != nil {
+				return "Record<string, " + tsType(s.AdditionalProperties, indent) + ">"
+			}
+			return "Record<string, unknown>"
+		}
+		return objectType(s, indent)
+	default:
+		return "unknown"
+	}
+}
+
+func objectType(s *schema.Schema, indent string) string {
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+
+	inner := indent + "  "
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, name := range names {
+		prop := s.Properties[name]
+		writeDoc(&b, inner, prop.Description, "")
+
+		key := name
+		if !identifier.MatchString(key) {
+			key = "'" + key + "'"
+		}
+		if !required[name] {
+			key += "?"
+		}
+		b.WriteString(inner + key + ": " + tsType(prop, inner) + "\n")
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// variable returns the TypeScript variable name of a path parameter
+// ("GalleryID" -> "galleryId").
+func variable(param string) string {
+	if strings.HasSuffix(param, "ID") {
+		param = strings.TrimSuffix(param, "ID") + "Id"
+	}
+	return strings.ToLower(param[:1]) + param[1:]
+}
+
+func pascal(s string) string {
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func sentence(s string) string {
+	if strings.HasSuffix(s, ".") {
+		return s
+	}
+	return s + "."
+}
@@ -0,0 +1,112 @@
+package typescript_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/modernice/nice-cms/openapi"
+	"github.com/modernice/nice-cms/openapi/typescript"
+	"github.com/modernice/nice-cms/schema"
+)
+
+type renameRequest struct {
+	Name string   `json:"name" schema:"required"`
+	Tags []string `json:"tags"`
+}
+
+func TestGenerate(t *testing.T) {
+	doc := openapi.New(openapi.Info{Title: "foo", Version: "1.0.0"})
+	doc.Add("PATCH", "/galleries/{GalleryID}", openapi.Operation{
+		OperationID: "renameGallery",
+		Summary:     "Rename gallery",
+		Parameters:  []openapi.Parameter{openapi.QueryParam("dry", "Validate only.", &schema.Schema{Type: "boolean"})},
+		RequestBody: openapi.JSONBody(schema.Of(renameRequest{})),
+		Responses:   openapi.Responses{"200": openapi.JSONResponse("The gallery.", schema.Of(renameRequest{}))},
+	})
+	doc.Add("GET", "/galleries/{GalleryID}/stacks/{StackID}/content", openapi.Operation{
+		OperationID: "showStackContent",
+		Responses: openapi.Responses{"200": {
+			Description: "The image.",
+			Content:     map[string]openapi.MediaType{"image/*": {Schema: &schema.Schema{Type: "string", Format: "binary"}}},
+		}},
+	})
+	doc.Add("GET", "/galleries/{GalleryID}/events", openapi.Operation{
+		OperationID: "streamGalleryEvents",
+		Responses: openapi.Responses{"200": {
+			Description: "Events.",
+			Content:     map[string]openapi.MediaType{"text/event-stream": {}},
+		}},
+	})
+	doc.Add("POST", "/galleries/{GalleryID}/stacks", openapi.Operation{
+		OperationID: "uploadImage",
+		RequestBody: &openapi.RequestBody{
+			Required: true,
+			Content: map[string]openapi.MediaType{"multipart/form-data": {Schema: &schema.Schema{
+				Type: "object",
+				Properties: map[string]*schema.Schema{
+					"image": {Type: "string", Format: "binary"},
+					"name":  {Type: "string"},
+				},
+				Required: []string{"image"},
+			}}},
+		},
+		Responses: openapi.Responses{"204": openapi.EmptyResponse("Uploaded.")},
+	})
+	doc.Add("DELETE", "/galleries/{GalleryID}", openapi.Operation{
+		Summary:   "Delete gallery",
+		Responses: openapi.Responses{"204": openapi.EmptyResponse("Deleted.")},
+	})
+
+	out := string(typescript.Generate(doc))
+
+	if !strings.HasPrefix(out, typescript.Header) {
+		t.Fatalf("output should start with the header; got %q", out[:80])
+	}
+
+	for _, want := range []string{
+		"export type RenameGalleryRequest = {\n  name: string\n  tags?: string[]\n}",
+		"export type RenameGalleryResponse = RenameGalleryRequest",
+		"export type RenameGalleryQuery = {\n  /**\n   * Validate only.\n   */\n  dry?: boolean\n}",
+		"export async function renameGallery(\n  client: AxiosInstance,\n  galleryId: string,\n  body: RenameGalleryRequest,\n  query: RenameGalleryQuery = {},\n  config?: AxiosRequestConfig\n): Promise<RenameGalleryResponse> {",
+		"    method: 'PATCH',\n    url: `/galleries/${encodeURIComponent(galleryId)}`,\n    data: body,\n    params: query,\n",
+		"): Promise<Blob> {",
+		"    responseType: 'blob',\n",
+		"export type UploadImageForm = {\n  image: Blob\n  name?: string\n}",
+		"  const form = new FormData()\n  if (body.name !== undefined) {\n    form.append('name', body.name)\n  }\n  form.append('image', body.image)\n",
+		"): Promise<void> {\n  const form = new FormData()",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output should contain\n%s\n\ngot\n%s", want, out)
+		}
+	}
+
+	if strings.Contains(out, "streamGalleryEvents") {
+		t.Errorf("operations that stream events should be skipped")
+	}
+
+	if strings.Contains(out, "'DELETE'") {
+		t.Errorf("operations without an operation ID should be skipped")
+	}
+}
+
+func TestType(t *testing.T) {
+	tests := []struct {
+		schema *schema.Schema
+		want   string
+	}{
+		{nil, "unknown"},
+		{&schema.Schema{Ref: "#"}, "unknown"},
+		{&schema.Schema{Type: "integer"}, "number"},
+		{&schema.Schema{Type: "string", Format: "binary"}, "Blob"},
+		{&schema.Schema{Type: "string", Enum: []any{"a", "b"}}, "'a' | 'b'"},
+		{&schema.Schema{Type: "array", Items: &schema.Schema{Type: "string", Enum: []any{"a", "b"}}}, "Array<'a' | 'b'>"},
+		{&schema.Schema{Type: "object", AdditionalProperties: &schema.Schema{Type: "string"}}, "Record<string, string>"},
+		{&schema.Schema{Type: "object", Properties: map[string]*schema.Schema{"name[contains]": {Type: "string"}}}, "{\n  'name[contains]'?: string\n}"},
+	}
+
+	for _, tt := range tests {
+		if got := typescript.Type(tt.schema); got != tt.want {
+			t.Errorf("Type(%v) should return %q; got %q", tt.schema, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+import { defineSirocConfig } from 'siroc'
+
+export default defineSirocConfig({
+  build: true,
+})
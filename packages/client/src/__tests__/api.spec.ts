@@ -0,0 +1,41 @@
+import { createTestClient, exampleUUID } from '@nice-cms/testing'
+import { createShelf, deleteStack, showGallery } from '../api'
+
+test('createShelf', async () => {
+  const { client, mock } = createTestClient()
+
+  mock.onPost('/shelfs', { name: 'foo' }).reply(201, {
+    id: exampleUUID,
+    name: 'foo',
+    documents: [],
+  })
+
+  const shelf = await createShelf(client, { name: 'foo' })
+
+  expect(shelf.id).toBe(exampleUUID)
+  expect(shelf.name).toBe('foo')
+})
+
+test('showGallery', async () => {
+  const { client, mock } = createTestClient()
+
+  mock
+    .onGet(`/galleries/${exampleUUID}`, { params: { limit: 10 } })
+    .reply(200, { id: exampleUUID, name: 'foo', stacks: [] })
+
+  const gallery = await showGallery(client, exampleUUID, { limit: 10 })
+
+  expect(gallery.id).toBe(exampleUUID)
+})
+
+test('deleteStack', async () => {
+  const { client, mock } = createTestClient()
+
+  mock.onDelete(`/galleries/${exampleUUID}/stacks/${exampleUUID}`).reply(204)
+
+  await expect(
+    deleteStack(client, exampleUUID, exampleUUID)
+  ).resolves.toBeUndefined()
+
+  await expect(deleteStack(client, exampleUUID, 'bar')).rejects.toThrow()
+})
@@ -0,0 +1,3411 @@
+// Code generated by github.com/modernice/nice-cms/openapi/typescript. DO NOT EDIT.
+
+import { AxiosInstance, AxiosRequestConfig } from 'axios'
+
+export type ShowAuditLogQuery = {
+  /**
+   * Filters by actor.
+   */
+  actor?: string
+  /**
+   * Filters by aggregate.
+   */
+  aggregate?: string
+  /**
+   * Filters by aggregateId.
+   */
+  aggregateId?: string
+  /**
+   * Cursor of the page, from the X-Next-Cursor header of the previous page.
+   */
+  cursor?: string
+  /**
+   * Filters by event.
+   */
+  event?: string
+  /**
+   * Maximum number of items.
+   */
+  limit?: number
+  /**
+   * Number of items to skip.
+   */
+  offset?: number
+  /**
+   * Filters by since.
+   */
+  since?: string
+  /**
+   * Filters by until.
+   */
+  until?: string
+}
+
+export type ShowAuditLogResponse = {
+  entries?: {
+    actor?: string
+    aggregate?: string
+    aggregateId?: string
+    aggregateVersion?: number
+    command?: string
+    event?: string
+    id?: string
+    time?: string
+  }[]
+  limit?: number
+  links?: {
+    next?: string
+  }
+  nextCursor?: string
+  offset?: number
+  total?: number
+}
+
+/**
+ * Show audit log.
+ */
+export async function showAuditLog(
+  client: AxiosInstance,
+  query: ShowAuditLogQuery = {},
+  config?: AxiosRequestConfig
+): Promise<ShowAuditLogResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: '/audit',
+    params: query,
+  })
+  return data
+}
+
+export type ShowCatalogResponse = Record<string, unknown>
+
+/**
+ * Show catalog.
+ */
+export async function showCatalog(
+  client: AxiosInstance,
+  config?: AxiosRequestConfig
+): Promise<ShowCatalogResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: '/catalog',
+  })
+  return data
+}
+
+export type ListDeadLettersQuery = {
+  /**
+   * Only list the dead letters of the gallery.
+   */
+  galleryId?: string
+}
+
+export type ListDeadLettersResponse = {
+  deadLetters?: {
+    attempts?: number
+    error?: string
+    failedAt?: string
+    galleryId?: string
+    stackId?: string
+  }[]
+}
+
+/**
+ * List dead letters.
+ */
+export async function listDeadLetters(
+  client: AxiosInstance,
+  query: ListDeadLettersQuery = {},
+  config?: AxiosRequestConfig
+): Promise<ListDeadLettersResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: '/dead-letters',
+    params: query,
+  })
+  return data
+}
+
+export type RedispatchDeadLetterResponse = {
+  attempts?: number
+  error?: string
+  failedAt?: string
+  galleryId?: string
+  stackId?: string
+}
+
+/**
+ * Redispatch dead letter.
+ */
+export async function redispatchDeadLetter(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  config?: AxiosRequestConfig
+): Promise<RedispatchDeadLetterResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/dead-letters/${encodeURIComponent(galleryId)}/${encodeURIComponent(stackId)}`,
+  })
+  return data
+}
+
+export type ListGalleriesQuery = {
+  /**
+   * Cursor of the page, from the X-Next-Cursor header of the previous page.
+   */
+  cursor?: string
+  /**
+   * Maximum number of items.
+   */
+  limit?: number
+  /**
+   * Number of items to skip.
+   */
+  offset?: number
+}
+
+export type ListGalleriesResponse = {
+  galleries?: {
+    id?: string
+    name?: string
+  }[]
+  limit?: number
+  links?: {
+    next?: string
+  }
+  nextCursor?: string
+  offset?: number
+  total?: number
+}
+
+/**
+ * List galleries.
+ */
+export async function listGalleries(
+  client: AxiosInstance,
+  query: ListGalleriesQuery = {},
+  config?: AxiosRequestConfig
+): Promise<ListGalleriesResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: '/galleries',
+    params: query,
+  })
+  return data
+}
+
+/**
+ * Body of POST /galleries. The name must not be used by another gallery.
+ */
+export type CreateGalleryRequest = {
+  name: string
+}
+
+export type CreateGalleryResponse = {
+  defaultTags?: string[]
+  id?: string
+  legalHold?: {
+    reason?: string
+    since?: string
+  }
+  name?: string
+  originalsProtected?: boolean
+  stacks?: {
+    alt?: string
+    caption?: string
+    checksum?: string
+    crop?: {
+      height?: number
+      width?: number
+      x?: number
+      y?: number
+    }
+    customMetadata?: Record<string, string>
+    focalPoint?: {
+      x?: number
+      y?: number
+    }
+    id?: string
+    images?: {
+      checksum?: string
+      cropped?: boolean
+      disk?: string
+      filesize?: number
+      format?: string
+      height?: number
+      name?: string
+      original?: boolean
+      path?: string
+      size?: string
+      stale?: boolean
+      storagePath?: string
+      tags?: string[]
+      width?: number
+    }[]
+    legalHold?: {
+      reason?: string
+      since?: string
+    }
+    metadata?: {
+      exposureTime?: number
+      fNumber?: number
+      focalLength?: number
+      gps?: {
+        latitude?: number
+        longitude?: number
+      }
+      iso?: number
+      lensModel?: string
+      make?: string
+      model?: string
+      takenAt?: string
+    }
+    palette?: string[]
+    pending?: boolean
+    placeholder?: string
+    processedAt?: string
+    processingError?: string
+    version?: number
+    versions?: {
+      checksum?: string
+      images?: {
+        checksum?: string
+        cropped?: boolean
+        disk?: string
+        filesize?: number
+        format?: string
+        height?: number
+        name?: string
+        original?: boolean
+        path?: string
+        size?: string
+        stale?: boolean
+        storagePath?: string
+        tags?: string[]
+        width?: number
+      }[]
+      replacedAt?: string
+      version?: number
+      video?: {
+        checksum?: string
+        codec?: string
+        disk?: string
+        duration?: number
+        filesize?: number
+        height?: number
+        name?: string
+        path?: string
+        storagePath?: string
+        tags?: string[]
+        width?: number
+      }
+    }[]
+    video?: {
+      checksum?: string
+      codec?: string
+      disk?: string
+      duration?: number
+      filesize?: number
+      height?: number
+      name?: string
+      path?: string
+      storagePath?: string
+      tags?: string[]
+      width?: number
+    }
+  }[]
+  theme?: {
+    primary?: string
+    secondary?: string
+  }
+  trashed?: {
+    alt?: string
+    caption?: string
+    checksum?: string
+    crop?: {
+      height?: number
+      width?: number
+      x?: number
+      y?: number
+    }
+    customMetadata?: Record<string, string>
+    focalPoint?: {
+      x?: number
+      y?: number
+    }
+    id?: string
+    images?: {
+      checksum?: string
+      cropped?: boolean
+      disk?: string
+      filesize?: number
+      format?: string
+      height?: number
+      name?: string
+      original?: boolean
+      path?: string
+      size?: string
+      stale?: boolean
+      storagePath?: string
+      tags?: string[]
+      width?: number
+    }[]
+    legalHold?: {
+      reason?: string
+      since?: string
+    }
+    metadata?: {
+      exposureTime?: number
+      fNumber?: number
+      focalLength?: number
+      gps?: {
+        latitude?: number
+        longitude?: number
+      }
+      iso?: number
+      lensModel?: string
+      make?: string
+      model?: string
+      takenAt?: string
+    }
+    palette?: string[]
+    pending?: boolean
+    placeholder?: string
+    processedAt?: string
+    processingError?: string
+    trashedAt?: string
+    version?: number
+    versions?: {
+      checksum?: string
+      images?: {
+        checksum?: string
+        cropped?: boolean
+        disk?: string
+        filesize?: number
+        format?: string
+        height?: number
+        name?: string
+        original?: boolean
+        path?: string
+        size?: string
+        stale?: boolean
+        storagePath?: string
+        tags?: string[]
+        width?: number
+      }[]
+      replacedAt?: string
+      version?: number
+      video?: {
+        checksum?: string
+        codec?: string
+        disk?: string
+        duration?: number
+        filesize?: number
+        height?: number
+        name?: string
+        path?: string
+        storagePath?: string
+        tags?: string[]
+        width?: number
+      }
+    }[]
+    video?: {
+      checksum?: string
+      codec?: string
+      disk?: string
+      duration?: number
+      filesize?: number
+      height?: number
+      name?: string
+      path?: string
+      storagePath?: string
+      tags?: string[]
+      width?: number
+    }
+  }[]
+  version?: number
+  versionLimit?: number
+}
+
+/**
+ * Create gallery.
+ */
+export async function createGallery(
+  client: AxiosInstance,
+  body: CreateGalleryRequest,
+  config?: AxiosRequestConfig
+): Promise<CreateGalleryResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: '/galleries',
+    data: body,
+  })
+  return data
+}
+
+export type LookupGalleryByNameResponse = {
+  galleryId: string
+}
+
+/**
+ * Look up gallery by name.
+ */
+export async function lookupGalleryByName(
+  client: AxiosInstance,
+  name: string,
+  config?: AxiosRequestConfig
+): Promise<LookupGalleryByNameResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/galleries/lookup/name/${encodeURIComponent(name)}`,
+  })
+  return data
+}
+
+export type ShowGalleryQuery = {
+  /**
+   * Cursor of the page, from the X-Next-Cursor header of the previous page.
+   */
+  cursor?: string
+  /**
+   * Maximum number of items.
+   */
+  limit?: number
+  /**
+   * Number of items to skip.
+   */
+  offset?: number
+  /**
+   * Comma-separated fields to sort by. A "-" prefix sorts a field in descending
+   * order. Fields: name, filesize, processedAt.
+   */
+  sort?: string
+  /**
+   * Filters by tag.
+   */
+  tag?: string
+  /**
+   * Filters by tags.
+   */
+  tags?: string
+}
+
+export type ShowGalleryResponse = CreateGalleryResponse
+
+/**
+ * Show gallery.
+ *
+ * Responds with the whole gallery, or with a page of its stacks if any of the
+ * list parameters are set.
+ */
+export async function showGallery(
+  client: AxiosInstance,
+  galleryId: string,
+  query: ShowGalleryQuery = {},
+  config?: AxiosRequestConfig
+): Promise<ShowGalleryResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/galleries/${encodeURIComponent(galleryId)}`,
+    params: query,
+  })
+  return data
+}
+
+/**
+ * Body of PATCH /galleries/{GalleryID}. The name must not be used by another
+ * gallery.
+ */
+export type RenameGalleryRequest = CreateGalleryRequest
+
+export type RenameGalleryResponse = CreateGalleryResponse
+
+/**
+ * Rename gallery.
+ */
+export async function renameGallery(
+  client: AxiosInstance,
+  galleryId: string,
+  body: RenameGalleryRequest,
+  config?: AxiosRequestConfig
+): Promise<RenameGalleryResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'PATCH',
+    url: `/galleries/${encodeURIComponent(galleryId)}`,
+    data: body,
+  })
+  return data
+}
+
+/**
+ * Delete gallery.
+ */
+export async function deleteGallery(
+  client: AxiosInstance,
+  galleryId: string,
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'DELETE',
+    url: `/galleries/${encodeURIComponent(galleryId)}`,
+  })
+}
+
+/**
+ * Body of PUT /galleries/{GalleryID}/default-tags. The default tags are added
+ * to every image that is uploaded to the gallery.
+ */
+export type SetGalleryDefaultTagsRequest = {
+  tags: string[]
+}
+
+/**
+ * Set gallery default tags.
+ */
+export async function setGalleryDefaultTags(
+  client: AxiosInstance,
+  galleryId: string,
+  body: SetGalleryDefaultTagsRequest,
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'PUT',
+    url: `/galleries/${encodeURIComponent(galleryId)}/default-tags`,
+    data: body,
+  })
+}
+
+export type ExportGalleryResponse = {
+  artifact?: {
+    contentType?: string
+    deleted?: boolean
+    disk?: string
+    expiresAt?: string
+    name?: string
+    path?: string
+    size?: number
+  }
+  attempts?: number
+  checkpoint?: string
+  createdAt?: string
+  error?: string
+  finishedAt?: string
+  id?: string
+  kind?: string
+  links?: {
+    artifact?: string
+    self?: string
+  }
+  params?: string
+  progress?: number
+  status?: string
+  updatedAt?: string
+}
+
+/**
+ * Export gallery.
+ */
+export async function exportGallery(
+  client: AxiosInstance,
+  galleryId: string,
+  config?: AxiosRequestConfig
+): Promise<ExportGalleryResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/galleries/${encodeURIComponent(galleryId)}/exports`,
+  })
+  return data
+}
+
+export type ShowGalleryIndexResponse = {
+  id?: string
+  name?: string
+  stacks?: {
+    id?: string
+    name?: string
+    pending?: boolean
+    placeholder?: string
+    tags?: string[]
+    thumbnail?: {
+      checksum?: string
+      cropped?: boolean
+      disk?: string
+      filesize?: number
+      format?: string
+      height?: number
+      name?: string
+      original?: boolean
+      path?: string
+      size?: string
+      stale?: boolean
+      storagePath?: string
+      tags?: string[]
+      width?: number
+    }
+  }[]
+}
+
+/**
+ * Show gallery index.
+ */
+export async function showGalleryIndex(
+  client: AxiosInstance,
+  galleryId: string,
+  config?: AxiosRequestConfig
+): Promise<ShowGalleryIndexResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/galleries/${encodeURIComponent(galleryId)}/index`,
+  })
+  return data
+}
+
+/**
+ * Body of the PUT legal-hold routes of shelfs, documents, galleries and stacks.
+ * Held resources cannot be deleted until the hold is lifted.
+ */
+export type SetGalleryLegalHoldRequest = {
+  reason?: string
+}
+
+/**
+ * Set gallery legal hold.
+ */
+export async function setGalleryLegalHold(
+  client: AxiosInstance,
+  galleryId: string,
+  body: SetGalleryLegalHoldRequest,
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'PUT',
+    url: `/galleries/${encodeURIComponent(galleryId)}/legal-hold`,
+    data: body,
+  })
+}
+
+/**
+ * Lift gallery legal hold.
+ */
+export async function liftGalleryLegalHold(
+  client: AxiosInstance,
+  galleryId: string,
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'DELETE',
+    url: `/galleries/${encodeURIComponent(galleryId)}/legal-hold`,
+  })
+}
+
+export type LookupGalleryStackByNameResponse = {
+  stackId: string
+}
+
+/**
+ * Look up stack by name.
+ */
+export async function lookupGalleryStackByName(
+  client: AxiosInstance,
+  galleryId: string,
+  name: string,
+  config?: AxiosRequestConfig
+): Promise<LookupGalleryStackByNameResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/galleries/${encodeURIComponent(galleryId)}/lookup/stack-name/${encodeURIComponent(name)}`,
+  })
+  return data
+}
+
+/**
+ * Body of PUT /galleries/{GalleryID}/originals-protection. The original images
+ * of a protected gallery are only served to authorized clients.
+ */
+export type ProtectGalleryOriginalsRequest = {
+  protected: boolean
+}
+
+/**
+ * Protect gallery originals.
+ */
+export async function protectGalleryOriginals(
+  client: AxiosInstance,
+  galleryId: string,
+  body: ProtectGalleryOriginalsRequest,
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'PUT',
+    url: `/galleries/${encodeURIComponent(galleryId)}/originals-protection`,
+    data: body,
+  })
+}
+
+/**
+ * Body of PATCH /galleries/{GalleryID}/sorting.
+ */
+export type SortGalleryRequest = {
+  sorting: string[]
+}
+
+/**
+ * Sort gallery.
+ */
+export async function sortGallery(
+  client: AxiosInstance,
+  galleryId: string,
+  body: SortGalleryRequest,
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'PATCH',
+    url: `/galleries/${encodeURIComponent(galleryId)}/sorting`,
+    data: body,
+  })
+}
+
+export type LookupGalleryStacksByTagQuery = {
+  /**
+   * The tag to look up.
+   */
+  tag: string
+}
+
+export type LookupGalleryStacksByTagResponse = {
+  stackIds: string[]
+}
+
+/**
+ * Look up stacks by tag.
+ */
+export async function lookupGalleryStacksByTag(
+  client: AxiosInstance,
+  galleryId: string,
+  query: LookupGalleryStacksByTagQuery,
+  config?: AxiosRequestConfig
+): Promise<LookupGalleryStacksByTagResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks`,
+    params: query,
+  })
+  return data
+}
+
+export type UploadImageForm = {
+  disk?: string
+  image: Blob
+  name?: string
+  path?: string
+}
+
+export type UploadImageResponse = {
+  alt?: string
+  caption?: string
+  checksum?: string
+  crop?: {
+    height?: number
+    width?: number
+    x?: number
+    y?: number
+  }
+  customMetadata?: Record<string, string>
+  focalPoint?: {
+    x?: number
+    y?: number
+  }
+  id?: string
+  images?: {
+    checksum?: string
+    cropped?: boolean
+    disk?: string
+    filesize?: number
+    format?: string
+    height?: number
+    name?: string
+    original?: boolean
+    path?: string
+    size?: string
+    stale?: boolean
+    storagePath?: string
+    tags?: string[]
+    width?: number
+  }[]
+  legalHold?: {
+    reason?: string
+    since?: string
+  }
+  links?: {
+    content?: string
+    gallery?: string
+    self?: string
+    shelf?: string
+  }
+  metadata?: {
+    exposureTime?: number
+    fNumber?: number
+    focalLength?: number
+    gps?: {
+      latitude?: number
+      longitude?: number
+    }
+    iso?: number
+    lensModel?: string
+    make?: string
+    model?: string
+    takenAt?: string
+  }
+  palette?: string[]
+  pending?: boolean
+  placeholder?: string
+  processedAt?: string
+  processingError?: string
+  version?: number
+  versions?: {
+    checksum?: string
+    images?: {
+      checksum?: string
+      cropped?: boolean
+      disk?: string
+      filesize?: number
+      format?: string
+      height?: number
+      name?: string
+      original?: boolean
+      path?: string
+      size?: string
+      stale?: boolean
+      storagePath?: string
+      tags?: string[]
+      width?: number
+    }[]
+    replacedAt?: string
+    version?: number
+    video?: {
+      checksum?: string
+      codec?: string
+      disk?: string
+      duration?: number
+      filesize?: number
+      height?: number
+      name?: string
+      path?: string
+      storagePath?: string
+      tags?: string[]
+      width?: number
+    }
+  }[]
+  video?: {
+    checksum?: string
+    codec?: string
+    disk?: string
+    duration?: number
+    filesize?: number
+    height?: number
+    name?: string
+    path?: string
+    storagePath?: string
+    tags?: string[]
+    width?: number
+  }
+}
+
+/**
+ * Upload image.
+ */
+export async function uploadImage(
+  client: AxiosInstance,
+  galleryId: string,
+  body: UploadImageForm,
+  config?: AxiosRequestConfig
+): Promise<UploadImageResponse> {
+  const form = new FormData()
+  if (body.disk !== undefined) {
+    form.append('disk', body.disk)
+  }
+  if (body.name !== undefined) {
+    form.append('name', body.name)
+  }
+  if (body.path !== undefined) {
+    form.append('path', body.path)
+  }
+  form.append('image', body.image)
+
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks`,
+    data: form,
+  })
+  return data
+}
+
+export type ShowStackResponse = {
+  alt?: string
+  caption?: string
+  checksum?: string
+  crop?: {
+    height?: number
+    width?: number
+    x?: number
+    y?: number
+  }
+  customMetadata?: Record<string, string>
+  focalPoint?: {
+    x?: number
+    y?: number
+  }
+  id?: string
+  images?: {
+    checksum?: string
+    cropped?: boolean
+    disk?: string
+    filesize?: number
+    format?: string
+    height?: number
+    name?: string
+    original?: boolean
+    path?: string
+    size?: string
+    stale?: boolean
+    storagePath?: string
+    tags?: string[]
+    width?: number
+  }[]
+  legalHold?: {
+    reason?: string
+    since?: string
+  }
+  metadata?: {
+    exposureTime?: number
+    fNumber?: number
+    focalLength?: number
+    gps?: {
+      latitude?: number
+      longitude?: number
+    }
+    iso?: number
+    lensModel?: string
+    make?: string
+    model?: string
+    takenAt?: string
+  }
+  palette?: string[]
+  pending?: boolean
+  placeholder?: string
+  processedAt?: string
+  processingError?: string
+  version?: number
+  versions?: {
+    checksum?: string
+    images?: {
+      checksum?: string
+      cropped?: boolean
+      disk?: string
+      filesize?: number
+      format?: string
+      height?: number
+      name?: string
+      original?: boolean
+      path?: string
+      size?: string
+      stale?: boolean
+      storagePath?: string
+      tags?: string[]
+      width?: number
+    }[]
+    replacedAt?: string
+    version?: number
+    video?: {
+      checksum?: string
+      codec?: string
+      disk?: string
+      duration?: number
+      filesize?: number
+      height?: number
+      name?: string
+      path?: string
+      storagePath?: string
+      tags?: string[]
+      width?: number
+    }
+  }[]
+  video?: {
+    checksum?: string
+    codec?: string
+    disk?: string
+    duration?: number
+    filesize?: number
+    height?: number
+    name?: string
+    path?: string
+    storagePath?: string
+    tags?: string[]
+    width?: number
+  }
+}
+
+/**
+ * Show stack.
+ */
+export async function showStack(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  config?: AxiosRequestConfig
+): Promise<ShowStackResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}`,
+  })
+  return data
+}
+
+export type ReplaceImageForm = {
+  image: Blob
+}
+
+export type ReplaceImageResponse = ShowStackResponse
+
+/**
+ * Replace image.
+ */
+export async function replaceImage(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  body: ReplaceImageForm,
+  config?: AxiosRequestConfig
+): Promise<ReplaceImageResponse> {
+  const form = new FormData()
+  form.append('image', body.image)
+
+  const { data } = await client.request({
+    ...config,
+    method: 'PUT',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}`,
+    data: form,
+  })
+  return data
+}
+
+/**
+ * Body of PATCH /galleries/{GalleryID}/stacks/{StackID}. An empty name leaves
+ * the name unchanged. Omitted alt and caption fields are left unchanged; empty
+ * ones are removed. CustomMetadata fields are added or overwritten; null values
+ * remove them.
+ */
+export type UpdateStackRequest = {
+  alt?: string
+  caption?: string
+  customMetadata?: Record<string, string>
+  name?: string
+}
+
+export type UpdateStackResponse = ShowStackResponse
+
+/**
+ * Update stack.
+ */
+export async function updateStack(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  body: UpdateStackRequest,
+  config?: AxiosRequestConfig
+): Promise<UpdateStackResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'PATCH',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}`,
+    data: body,
+  })
+  return data
+}
+
+/**
+ * Delete stack.
+ */
+export async function deleteStack(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'DELETE',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}`,
+  })
+}
+
+export type ShowStackContentQuery = {
+  /**
+   * Format of the image, e.g. "webp".
+   */
+  format?: string
+  /**
+   * Size of the image. Defaults to the original image.
+   */
+  size?: string
+}
+
+/**
+ * Show stack content.
+ */
+export async function showStackContent(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  query: ShowStackContentQuery = {},
+  config?: AxiosRequestConfig
+): Promise<Blob> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}/content`,
+    params: query,
+    responseType: 'blob',
+  })
+  return data
+}
+
+export type HeadStackContentQuery = ShowStackContentQuery
+
+/**
+ * Show stack content headers.
+ */
+export async function headStackContent(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  query: HeadStackContentQuery = {},
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'HEAD',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}/content`,
+    params: query,
+  })
+}
+
+/**
+ * Body of POST /galleries/{GalleryID}/stacks/{StackID}/crop. The rectangle is
+ * given in percentages of the width and height of the original image, measured
+ * from the top-left corner. A rectangle of the full image removes the crop.
+ */
+export type CropStackRequest = {
+  height: number
+  width: number
+  x: number
+  y: number
+}
+
+export type CropStackResponse = ShowStackResponse
+
+/**
+ * Crop stack.
+ */
+export async function cropStack(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  body: CropStackRequest,
+  config?: AxiosRequestConfig
+): Promise<CropStackResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}/crop`,
+    data: body,
+  })
+  return data
+}
+
+/**
+ * Body of POST /galleries/{GalleryID}/stacks/{StackID}/flip. The original image
+ * is mirrored horizontally (left and right) or vertically (top and bottom).
+ */
+export type FlipStackRequest = {
+  direction: 'horizontal' | 'vertical'
+}
+
+export type FlipStackResponse = ShowStackResponse
+
+/**
+ * Flip stack.
+ */
+export async function flipStack(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  body: FlipStackRequest,
+  config?: AxiosRequestConfig
+): Promise<FlipStackResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}/flip`,
+    data: body,
+  })
+  return data
+}
+
+/**
+ * Body of PUT /galleries/{GalleryID}/stacks/{StackID}/focal-point. x and y are
+ * percentages of the width and height of the image, measured from the top-left
+ * corner.
+ */
+export type SetStackFocalPointRequest = {
+  x: number
+  y: number
+}
+
+export type SetStackFocalPointResponse = ShowStackResponse
+
+/**
+ * Set stack focal point.
+ */
+export async function setStackFocalPoint(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  body: SetStackFocalPointRequest,
+  config?: AxiosRequestConfig
+): Promise<SetStackFocalPointResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'PUT',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}/focal-point`,
+    data: body,
+  })
+  return data
+}
+
+/**
+ * Body of the PUT legal-hold routes of shelfs, documents, galleries and stacks.
+ * Held resources cannot be deleted until the hold is lifted.
+ */
+export type SetStackLegalHoldRequest = SetGalleryLegalHoldRequest
+
+/**
+ * Set stack legal hold.
+ */
+export async function setStackLegalHold(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  body: SetStackLegalHoldRequest,
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'PUT',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}/legal-hold`,
+    data: body,
+  })
+}
+
+/**
+ * Lift stack legal hold.
+ */
+export async function liftStackLegalHold(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'DELETE',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}/legal-hold`,
+  })
+}
+
+/**
+ * Body of POST /galleries/{GalleryID}/stacks/{StackID}/move. The stack is moved
+ * in front of the stack with the given UUID, or to the end of the gallery if
+ * before is omitted.
+ */
+export type MoveStackRequest = {
+  before?: string
+}
+
+/**
+ * Move stack.
+ */
+export async function moveStack(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  body?: MoveStackRequest,
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'POST',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}/move`,
+    data: body,
+  })
+}
+
+/**
+ * Show stack original.
+ */
+export async function showStackOriginal(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  config?: AxiosRequestConfig
+): Promise<Blob> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}/original`,
+    responseType: 'blob',
+  })
+  return data
+}
+
+export type ReprocessStackResponse = ShowStackResponse
+
+/**
+ * Reprocess stack.
+ */
+export async function reprocessStack(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  config?: AxiosRequestConfig
+): Promise<ReprocessStackResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}/process`,
+  })
+  return data
+}
+
+/**
+ * Body of POST /galleries/{GalleryID}/stacks/{StackID}/revert. The stack is
+ * reverted to the given previous version.
+ */
+export type RevertStackRequest = {
+  version: number
+}
+
+export type RevertStackResponse = ShowStackResponse
+
+/**
+ * Revert stack.
+ */
+export async function revertStack(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  body: RevertStackRequest,
+  config?: AxiosRequestConfig
+): Promise<RevertStackResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}/revert`,
+    data: body,
+  })
+  return data
+}
+
+/**
+ * Body of POST /galleries/{GalleryID}/stacks/{StackID}/rotate. The original
+ * image is rotated clockwise by the given degrees, which must be a multiple of
+ * 90. Negative degrees rotate counter-clockwise.
+ */
+export type RotateStackRequest = {
+  degrees: number
+}
+
+export type RotateStackResponse = ShowStackResponse
+
+/**
+ * Rotate stack.
+ */
+export async function rotateStack(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  body: RotateStackRequest,
+  config?: AxiosRequestConfig
+): Promise<RotateStackResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}/rotate`,
+    data: body,
+  })
+  return data
+}
+
+/**
+ * Body of POST /galleries/{GalleryID}/stacks/{StackID}/signed-url. The expiry
+ * is given in seconds and defaults to 15 minutes. The image is selected using
+ * size and format like the content route; original selects the original image,
+ * which requires authorization if the gallery protects its originals.
+ */
+export type SignStackURLRequest = {
+  expiry?: number
+  format?: string
+  original?: boolean
+  size?: string
+}
+
+export type SignStackURLResponse = {
+  expiresAt?: string
+  url?: string
+}
+
+/**
+ * Sign stack URL.
+ */
+export async function signStackURL(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  body?: SignStackURLRequest,
+  config?: AxiosRequestConfig
+): Promise<SignStackURLResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}/signed-url`,
+    data: body,
+  })
+  return data
+}
+
+export type ShowStackStatusResponse = {
+  pending?: boolean
+  processedAt?: string
+  processingError?: string
+}
+
+/**
+ * Show stack status.
+ */
+export async function showStackStatus(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  config?: AxiosRequestConfig
+): Promise<ShowStackStatusResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}/status`,
+  })
+  return data
+}
+
+/**
+ * Body of POST /galleries/{GalleryID}/stacks/{StackID}/tags.
+ */
+export type TagStackRequest = SetGalleryDefaultTagsRequest
+
+export type TagStackResponse = ShowStackResponse
+
+/**
+ * Tag stack.
+ */
+export async function tagStack(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  body: TagStackRequest,
+  config?: AxiosRequestConfig
+): Promise<TagStackResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}/tags`,
+    data: body,
+  })
+  return data
+}
+
+export type UntagStackResponse = ShowStackResponse
+
+/**
+ * Untag stack.
+ */
+export async function untagStack(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  tags: string,
+  config?: AxiosRequestConfig
+): Promise<UntagStackResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'DELETE',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}/tags/${encodeURIComponent(tags)}`,
+  })
+  return data
+}
+
+export type ShowStackVersionsQuery = {
+  /**
+   * Cursor of the page, from the X-Next-Cursor header of the previous page.
+   */
+  cursor?: string
+  /**
+   * Maximum number of items.
+   */
+  limit?: number
+  /**
+   * Number of items to skip.
+   */
+  offset?: number
+  /**
+   * Comma-separated fields to sort by. A "-" prefix sorts a field in descending
+   * order. Fields: version, replacedAt.
+   */
+  sort?: string
+  /**
+   * Filters by version.
+   */
+  version?: string
+  /**
+   * Filters by version (gt).
+   */
+  'version[gt]'?: string
+  /**
+   * Filters by version (gte).
+   */
+  'version[gte]'?: string
+  /**
+   * Filters by version (lt).
+   */
+  'version[lt]'?: string
+  /**
+   * Filters by version (lte).
+   */
+  'version[lte]'?: string
+}
+
+export type ShowStackVersionsResponse = {
+  checksum?: string
+  images?: {
+    checksum?: string
+    cropped?: boolean
+    disk?: string
+    filesize?: number
+    format?: string
+    height?: number
+    name?: string
+    original?: boolean
+    path?: string
+    size?: string
+    stale?: boolean
+    storagePath?: string
+    tags?: string[]
+    width?: number
+  }[]
+  replacedAt?: string
+  version?: number
+  video?: {
+    checksum?: string
+    codec?: string
+    disk?: string
+    duration?: number
+    filesize?: number
+    height?: number
+    name?: string
+    path?: string
+    storagePath?: string
+    tags?: string[]
+    width?: number
+  }
+}[]
+
+/**
+ * Show stack versions.
+ */
+export async function showStackVersions(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  query: ShowStackVersionsQuery = {},
+  config?: AxiosRequestConfig
+): Promise<ShowStackVersionsResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}/versions`,
+    params: query,
+  })
+  return data
+}
+
+/**
+ * Show stack video.
+ */
+export async function showStackVideo(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  config?: AxiosRequestConfig
+): Promise<Blob> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/galleries/${encodeURIComponent(galleryId)}/stacks/${encodeURIComponent(stackId)}/video`,
+    responseType: 'blob',
+  })
+  return data
+}
+
+export type ShowGalleryTrashQuery = {
+  /**
+   * Cursor of the page, from the X-Next-Cursor header of the previous page.
+   */
+  cursor?: string
+  /**
+   * Maximum number of items.
+   */
+  limit?: number
+  /**
+   * Filters by name.
+   */
+  name?: string
+  /**
+   * Filters by name (contains).
+   */
+  'name[contains]'?: string
+  /**
+   * Filters by name (prefix).
+   */
+  'name[prefix]'?: string
+  /**
+   * Number of items to skip.
+   */
+  offset?: number
+  /**
+   * Comma-separated fields to sort by. A "-" prefix sorts a field in descending
+   * order. Fields: name, trashedAt.
+   */
+  sort?: string
+  /**
+   * Filters by tag.
+   */
+  tag?: string
+}
+
+export type ShowGalleryTrashResponse = {
+  alt?: string
+  caption?: string
+  checksum?: string
+  crop?: {
+    height?: number
+    width?: number
+    x?: number
+    y?: number
+  }
+  customMetadata?: Record<string, string>
+  focalPoint?: {
+    x?: number
+    y?: number
+  }
+  id?: string
+  images?: {
+    checksum?: string
+    cropped?: boolean
+    disk?: string
+    filesize?: number
+    format?: string
+    height?: number
+    name?: string
+    original?: boolean
+    path?: string
+    size?: string
+    stale?: boolean
+    storagePath?: string
+    tags?: string[]
+    width?: number
+  }[]
+  legalHold?: {
+    reason?: string
+    since?: string
+  }
+  metadata?: {
+    exposureTime?: number
+    fNumber?: number
+    focalLength?: number
+    gps?: {
+      latitude?: number
+      longitude?: number
+    }
+    iso?: number
+    lensModel?: string
+    make?: string
+    model?: string
+    takenAt?: string
+  }
+  palette?: string[]
+  pending?: boolean
+  placeholder?: string
+  processedAt?: string
+  processingError?: string
+  trashedAt?: string
+  version?: number
+  versions?: {
+    checksum?: string
+    images?: {
+      checksum?: string
+      cropped?: boolean
+      disk?: string
+      filesize?: number
+      format?: string
+      height?: number
+      name?: string
+      original?: boolean
+      path?: string
+      size?: string
+      stale?: boolean
+      storagePath?: string
+      tags?: string[]
+      width?: number
+    }[]
+    replacedAt?: string
+    version?: number
+    video?: {
+      checksum?: string
+      codec?: string
+      disk?: string
+      duration?: number
+      filesize?: number
+      height?: number
+      name?: string
+      path?: string
+      storagePath?: string
+      tags?: string[]
+      width?: number
+    }
+  }[]
+  video?: {
+    checksum?: string
+    codec?: string
+    disk?: string
+    duration?: number
+    filesize?: number
+    height?: number
+    name?: string
+    path?: string
+    storagePath?: string
+    tags?: string[]
+    width?: number
+  }
+}[]
+
+/**
+ * Show gallery trash.
+ */
+export async function showGalleryTrash(
+  client: AxiosInstance,
+  galleryId: string,
+  query: ShowGalleryTrashQuery = {},
+  config?: AxiosRequestConfig
+): Promise<ShowGalleryTrashResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/galleries/${encodeURIComponent(galleryId)}/trash`,
+    params: query,
+  })
+  return data
+}
+
+export type RestoreStackResponse = ShowStackResponse
+
+/**
+ * Restore stack.
+ */
+export async function restoreStack(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string,
+  config?: AxiosRequestConfig
+): Promise<RestoreStackResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/galleries/${encodeURIComponent(galleryId)}/trash/${encodeURIComponent(stackId)}/restore`,
+  })
+  return data
+}
+
+/**
+ * Body of PUT /galleries/{GalleryID}/versioning. The limit is the number of
+ * previous versions that are kept for each stack when it is replaced; 0
+ * disables versioning.
+ */
+export type SetGalleryVersionLimitRequest = {
+  limit: number
+}
+
+/**
+ * Set gallery version limit.
+ */
+export async function setGalleryVersionLimit(
+  client: AxiosInstance,
+  galleryId: string,
+  body: SetGalleryVersionLimitRequest,
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'PUT',
+    url: `/galleries/${encodeURIComponent(galleryId)}/versioning`,
+    data: body,
+  })
+}
+
+export type UploadVideoForm = {
+  disk?: string
+  name?: string
+  path?: string
+  video: Blob
+}
+
+export type UploadVideoResponse = UploadImageResponse
+
+/**
+ * Upload video.
+ */
+export async function uploadVideo(
+  client: AxiosInstance,
+  galleryId: string,
+  body: UploadVideoForm,
+  config?: AxiosRequestConfig
+): Promise<UploadVideoResponse> {
+  const form = new FormData()
+  if (body.disk !== undefined) {
+    form.append('disk', body.disk)
+  }
+  if (body.name !== undefined) {
+    form.append('name', body.name)
+  }
+  if (body.path !== undefined) {
+    form.append('path', body.path)
+  }
+  form.append('video', body.video)
+
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/galleries/${encodeURIComponent(galleryId)}/videos`,
+    data: form,
+  })
+  return data
+}
+
+export type ShowHealthResponse = {
+  disks?: Record<string, {
+    level?: string
+    thresholds?: {
+      critical?: number
+      warning?: number
+    }
+    usage?: number
+  }>
+  projections?: Record<string, {
+    errors?: number
+    halted?: boolean
+    lag?: number
+    lastError?: string
+    lastEventId?: string
+    lastEventName?: string
+    lastEventTime?: string
+    policy?: string
+    ready?: boolean
+    running?: boolean
+  }>
+  status?: string
+}
+
+/**
+ * Show health.
+ */
+export async function showHealth(
+  client: AxiosInstance,
+  config?: AxiosRequestConfig
+): Promise<ShowHealthResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: '/health',
+  })
+  return data
+}
+
+export type ShowJobResponse = ExportGalleryResponse
+
+/**
+ * Show job.
+ */
+export async function showJob(
+  client: AxiosInstance,
+  jobId: string,
+  config?: AxiosRequestConfig
+): Promise<ShowJobResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/jobs/${encodeURIComponent(jobId)}`,
+  })
+  return data
+}
+
+export type CancelJobResponse = ExportGalleryResponse
+
+/**
+ * Cancel job.
+ */
+export async function cancelJob(
+  client: AxiosInstance,
+  jobId: string,
+  config?: AxiosRequestConfig
+): Promise<CancelJobResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'DELETE',
+    url: `/jobs/${encodeURIComponent(jobId)}`,
+  })
+  return data
+}
+
+/**
+ * Show job artifact.
+ */
+export async function showJobArtifact(
+  client: AxiosInstance,
+  jobId: string,
+  config?: AxiosRequestConfig
+): Promise<Blob> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/jobs/${encodeURIComponent(jobId)}/artifact`,
+    responseType: 'blob',
+  })
+  return data
+}
+
+export type ShowNavByNameQuery = {
+  /**
+   * Locale of the labels and paths. Labels and paths without a value for the
+   * locale fall back to the parent and fallback locales.
+   */
+  locale?: string
+}
+
+export type ShowNavByNameResponse = {
+  id?: string
+  items?: unknown[]
+  name?: string
+}
+
+/**
+ * Show nav by name.
+ *
+ * With the locale parameter, the items have the label and path of that locale.
+ */
+export async function showNavByName(
+  client: AxiosInstance,
+  name: string,
+  query: ShowNavByNameQuery = {},
+  config?: AxiosRequestConfig
+): Promise<ShowNavByNameResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/navs/name/${encodeURIComponent(name)}`,
+    params: query,
+  })
+  return data
+}
+
+export type ShowNavOpenAPIResponse = ShowCatalogResponse
+
+/**
+ * Show nav OpenAPI document.
+ */
+export async function showNavOpenAPI(
+  client: AxiosInstance,
+  config?: AxiosRequestConfig
+): Promise<ShowNavOpenAPIResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: '/navs/openapi.json',
+  })
+  return data
+}
+
+export type ShowNavQuery = ShowNavByNameQuery
+
+export type ShowNavResponse = ShowNavByNameResponse
+
+/**
+ * Show nav.
+ *
+ * With the locale parameter, the items have the label and path of that locale.
+ */
+export async function showNav(
+  client: AxiosInstance,
+  navId: string,
+  query: ShowNavQuery = {},
+  config?: AxiosRequestConfig
+): Promise<ShowNavResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/navs/${encodeURIComponent(navId)}`,
+    params: query,
+  })
+  return data
+}
+
+export type UpdateNavItemRequest = {
+  attributes?: Record<string, string>
+  hidden?: boolean
+  localeLabels?: Record<string, string>
+  localePaths?: Record<string, string>
+  page?: string
+  requiresAuth?: boolean
+  type?: string
+}
+
+export type UpdateNavItemResponse = ShowNavByNameResponse
+
+/**
+ * Update nav item.
+ */
+export async function updateNavItem(
+  client: AxiosInstance,
+  navId: string,
+  item: string,
+  body: UpdateNavItemRequest,
+  config?: AxiosRequestConfig
+): Promise<UpdateNavItemResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'PATCH',
+    url: `/navs/${encodeURIComponent(navId)}/items/${encodeURIComponent(item)}`,
+    data: body,
+  })
+  return data
+}
+
+export type ReorderNavRequest = {
+  items: unknown[]
+}
+
+export type ReorderNavResponse = ShowNavByNameResponse
+
+/**
+ * Reorder nav.
+ */
+export async function reorderNav(
+  client: AxiosInstance,
+  navId: string,
+  body: ReorderNavRequest,
+  config?: AxiosRequestConfig
+): Promise<ReorderNavResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'PUT',
+    url: `/navs/${encodeURIComponent(navId)}/tree`,
+    data: body,
+  })
+  return data
+}
+
+export type ShowOpenAPIResponse = ShowCatalogResponse
+
+/**
+ * Show OpenAPI document.
+ */
+export async function showOpenAPI(
+  client: AxiosInstance,
+  config?: AxiosRequestConfig
+): Promise<ShowOpenAPIResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: '/openapi.json',
+  })
+  return data
+}
+
+export type ShowPageOpenAPIResponse = ShowCatalogResponse
+
+/**
+ * Show page OpenAPI document.
+ */
+export async function showPageOpenAPI(
+  client: AxiosInstance,
+  config?: AxiosRequestConfig
+): Promise<ShowPageOpenAPIResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: '/pages/openapi.json',
+  })
+  return data
+}
+
+export type ShowPageQuery = {
+  /**
+   * Locale of the field values. Fields without a value for the locale fall back
+   * to the parent and fallback locales.
+   */
+  locale?: string
+}
+
+export type ShowPageResponse = {
+  fields?: {
+    Guarded?: boolean
+    Name?: string
+    Schema?: unknown
+    Type?: string
+    Values?: Record<string, string>
+  }[]
+  id?: string
+  name?: string
+  preview?: boolean
+  publishedAt?: string
+}
+
+/**
+ * Show page.
+ *
+ * Responds with the published content of the page. With the locale parameter,
+ * the fields have the values of that locale.
+ */
+export async function showPage(
+  client: AxiosInstance,
+  pageId: string,
+  query: ShowPageQuery = {},
+  config?: AxiosRequestConfig
+): Promise<ShowPageResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/pages/${encodeURIComponent(pageId)}`,
+    params: query,
+  })
+  return data
+}
+
+export type PreviewPageQuery = {
+  /**
+   * Locale of the field values. Fields without a value for the locale fall back
+   * to the parent and fallback locales.
+   */
+  locale?: string
+  /**
+   * Preview token of the page.
+   */
+  token: string
+}
+
+export type PreviewPageResponse = ShowPageResponse
+
+/**
+ * Preview page.
+ *
+ * Responds with the draft content of the page. With the locale parameter, the
+ * fields have the values of that locale.
+ */
+export async function previewPage(
+  client: AxiosInstance,
+  pageId: string,
+  query: PreviewPageQuery,
+  config?: AxiosRequestConfig
+): Promise<PreviewPageResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/pages/${encodeURIComponent(pageId)}/preview`,
+    params: query,
+  })
+  return data
+}
+
+export type ListPageRevisionsResponse = {
+  fields?: {
+    Guarded?: boolean
+    Name?: string
+    Schema?: unknown
+    Type?: string
+    Values?: Record<string, string>
+  }[]
+  number?: number
+  publishedAt?: string
+}[]
+
+/**
+ * List page revisions.
+ */
+export async function listPageRevisions(
+  client: AxiosInstance,
+  pageId: string,
+  config?: AxiosRequestConfig
+): Promise<ListPageRevisionsResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/pages/${encodeURIComponent(pageId)}/revisions`,
+  })
+  return data
+}
+
+export type DiffPageRevisionsQuery = {
+  /**
+   * Number of the revision to diff from.
+   */
+  from: number
+  /**
+   * Number of the revision to diff to.
+   */
+  to: number
+}
+
+export type DiffPageRevisionsResponse = {
+  change?: string
+  field?: string
+  locale?: string
+  new?: string
+  old?: string
+}[]
+
+/**
+ * Diff page revisions.
+ */
+export async function diffPageRevisions(
+  client: AxiosInstance,
+  pageId: string,
+  query: DiffPageRevisionsQuery,
+  config?: AxiosRequestConfig
+): Promise<DiffPageRevisionsResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/pages/${encodeURIComponent(pageId)}/revisions/diff`,
+    params: query,
+  })
+  return data
+}
+
+export type RevertPageRevisionResponse = ShowPageResponse
+
+/**
+ * Revert page to revision.
+ */
+export async function revertPageRevision(
+  client: AxiosInstance,
+  pageId: string,
+  revision: string,
+  config?: AxiosRequestConfig
+): Promise<RevertPageRevisionResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/pages/${encodeURIComponent(pageId)}/revisions/${encodeURIComponent(revision)}/revert`,
+  })
+  return data
+}
+
+/**
+ * A JSON Schema.
+ */
+export type ShowPageSchemaResponse = ShowCatalogResponse
+
+/**
+ * Show page schema.
+ */
+export async function showPageSchema(
+  client: AxiosInstance,
+  pageId: string,
+  config?: AxiosRequestConfig
+): Promise<ShowPageSchemaResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/pages/${encodeURIComponent(pageId)}/schema`,
+  })
+  return data
+}
+
+export type ListSchemasResponse = Record<string, Record<string, unknown>>
+
+/**
+ * List schemas.
+ */
+export async function listSchemas(
+  client: AxiosInstance,
+  config?: AxiosRequestConfig
+): Promise<ListSchemasResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: '/schemas',
+  })
+  return data
+}
+
+/**
+ * A JSON Schema.
+ */
+export type ShowSchemaResponse = ShowCatalogResponse
+
+/**
+ * Show schema.
+ */
+export async function showSchema(
+  client: AxiosInstance,
+  name: string,
+  config?: AxiosRequestConfig
+): Promise<ShowSchemaResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/schemas/${encodeURIComponent(name)}`,
+  })
+  return data
+}
+
+export type SearchDocumentsQuery = {
+  /**
+   * Cursor of the page, from the X-Next-Cursor header of the previous page.
+   */
+  cursor?: string
+  /**
+   * Maximum number of items.
+   */
+  limit?: number
+  /**
+   * Filters by name.
+   */
+  name?: string
+  /**
+   * Number of items to skip.
+   */
+  offset?: number
+  /**
+   * Filters by path.
+   */
+  path?: string
+  /**
+   * Filters by q.
+   */
+  q?: string
+  /**
+   * Filters by tag.
+   */
+  tag?: string
+  /**
+   * Filters by tags.
+   */
+  tags?: string
+  /**
+   * Filters by uniqueName.
+   */
+  uniqueName?: string
+}
+
+export type SearchDocumentsResponse = {
+  documents?: {
+    audio?: {
+      bitrate?: number
+      duration?: number
+    }
+    checksum?: string
+    disk?: string
+    filesize?: number
+    id?: string
+    legalHold?: {
+      reason?: string
+      since?: string
+    }
+    metadata?: Record<string, string>
+    name?: string
+    path?: string
+    preview?: {
+      error?: string
+      image?: {
+        checksum?: string
+        disk?: string
+        filesize?: number
+        height?: number
+        name?: string
+        path?: string
+        storagePath?: string
+        tags?: string[]
+        width?: number
+      }
+      status?: string
+    }
+    shelfId?: string
+    shelfName?: string
+    storagePath?: string
+    tags?: string[]
+    uniqueName?: string
+    variants?: Record<string, {
+      checksum?: string
+      disk?: string
+      filesize?: number
+      name?: string
+      path?: string
+      storagePath?: string
+      tags?: string[]
+    }>
+  }[]
+  limit?: number
+  links?: {
+    next?: string
+  }
+  nextCursor?: string
+  offset?: number
+  total?: number
+}
+
+/**
+ * Search documents.
+ */
+export async function searchDocuments(
+  client: AxiosInstance,
+  query: SearchDocumentsQuery = {},
+  config?: AxiosRequestConfig
+): Promise<SearchDocumentsResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: '/search',
+    params: query,
+  })
+  return data
+}
+
+export type ListShelfsQuery = ListGalleriesQuery
+
+export type ListShelfsResponse = {
+  limit?: number
+  links?: {
+    next?: string
+  }
+  nextCursor?: string
+  offset?: number
+  shelfs?: {
+    id?: string
+    name?: string
+  }[]
+  total?: number
+}
+
+/**
+ * List shelfs.
+ */
+export async function listShelfs(
+  client: AxiosInstance,
+  query: ListShelfsQuery = {},
+  config?: AxiosRequestConfig
+): Promise<ListShelfsResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: '/shelfs',
+    params: query,
+  })
+  return data
+}
+
+/**
+ * Body of POST /shelfs. The name must not be used by another shelf.
+ */
+export type CreateShelfRequest = CreateGalleryRequest
+
+export type CreateShelfResponse = {
+  documents?: {
+    audio?: {
+      bitrate?: number
+      duration?: number
+    }
+    checksum?: string
+    disk?: string
+    filesize?: number
+    id?: string
+    legalHold?: {
+      reason?: string
+      since?: string
+    }
+    metadata?: Record<string, string>
+    name?: string
+    path?: string
+    preview?: {
+      error?: string
+      image?: {
+        checksum?: string
+        disk?: string
+        filesize?: number
+        height?: number
+        name?: string
+        path?: string
+        storagePath?: string
+        tags?: string[]
+        width?: number
+      }
+      status?: string
+    }
+    storagePath?: string
+    tags?: string[]
+    uniqueName?: string
+    variants?: Record<string, {
+      checksum?: string
+      disk?: string
+      filesize?: number
+      name?: string
+      path?: string
+      storagePath?: string
+      tags?: string[]
+    }>
+  }[]
+  id?: string
+  legalHold?: {
+    reason?: string
+    since?: string
+  }
+  name?: string
+  trashed?: {
+    audio?: {
+      bitrate?: number
+      duration?: number
+    }
+    checksum?: string
+    disk?: string
+    filesize?: number
+    id?: string
+    legalHold?: {
+      reason?: string
+      since?: string
+    }
+    metadata?: Record<string, string>
+    name?: string
+    path?: string
+    preview?: {
+      error?: string
+      image?: {
+        checksum?: string
+        disk?: string
+        filesize?: number
+        height?: number
+        name?: string
+        path?: string
+        storagePath?: string
+        tags?: string[]
+        width?: number
+      }
+      status?: string
+    }
+    storagePath?: string
+    tags?: string[]
+    trashedAt?: string
+    uniqueName?: string
+    variants?: Record<string, {
+      checksum?: string
+      disk?: string
+      filesize?: number
+      name?: string
+      path?: string
+      storagePath?: string
+      tags?: string[]
+    }>
+  }[]
+  version?: number
+}
+
+/**
+ * Create shelf.
+ */
+export async function createShelf(
+  client: AxiosInstance,
+  body: CreateShelfRequest,
+  config?: AxiosRequestConfig
+): Promise<CreateShelfResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: '/shelfs',
+    data: body,
+  })
+  return data
+}
+
+export type LookupShelfByNameResponse = {
+  shelfId: string
+}
+
+/**
+ * Look up shelf by name.
+ */
+export async function lookupShelfByName(
+  client: AxiosInstance,
+  name: string,
+  config?: AxiosRequestConfig
+): Promise<LookupShelfByNameResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/shelfs/lookup/name/${encodeURIComponent(name)}`,
+  })
+  return data
+}
+
+export type ShowShelfQuery = {
+  /**
+   * Cursor of the page, from the X-Next-Cursor header of the previous page.
+   */
+  cursor?: string
+  /**
+   * Maximum number of items.
+   */
+  limit?: number
+  /**
+   * Number of items to skip.
+   */
+  offset?: number
+  /**
+   * Filters by q.
+   */
+  q?: string
+  /**
+   * Comma-separated fields to sort by. A "-" prefix sorts a field in descending
+   * order. Fields: name, uniqueName, filesize.
+   */
+  sort?: string
+  /**
+   * Filters by tag.
+   */
+  tag?: string
+}
+
+export type ShowShelfResponse = CreateShelfResponse
+
+/**
+ * Show shelf.
+ *
+ * Responds with the whole shelf, or with a page of its documents if any of the
+ * list parameters are set.
+ */
+export async function showShelf(
+  client: AxiosInstance,
+  shelfId: string,
+  query: ShowShelfQuery = {},
+  config?: AxiosRequestConfig
+): Promise<ShowShelfResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/shelfs/${encodeURIComponent(shelfId)}`,
+    params: query,
+  })
+  return data
+}
+
+/**
+ * Body of PATCH /shelfs/{ShelfID}. The name must not be used by another shelf.
+ */
+export type RenameShelfRequest = CreateGalleryRequest
+
+export type RenameShelfResponse = CreateShelfResponse
+
+/**
+ * Rename shelf.
+ */
+export async function renameShelf(
+  client: AxiosInstance,
+  shelfId: string,
+  body: RenameShelfRequest,
+  config?: AxiosRequestConfig
+): Promise<RenameShelfResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'PATCH',
+    url: `/shelfs/${encodeURIComponent(shelfId)}`,
+    data: body,
+  })
+  return data
+}
+
+/**
+ * Delete shelf.
+ */
+export async function deleteShelf(
+  client: AxiosInstance,
+  shelfId: string,
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'DELETE',
+    url: `/shelfs/${encodeURIComponent(shelfId)}`,
+  })
+}
+
+export type UploadDocumentForm = {
+  disk?: string
+  document: Blob
+  name?: string
+  path?: string
+  uniqueName?: string
+}
+
+export type UploadDocumentResponse = {
+  audio?: {
+    bitrate?: number
+    duration?: number
+  }
+  checksum?: string
+  disk?: string
+  filesize?: number
+  id?: string
+  legalHold?: {
+    reason?: string
+    since?: string
+  }
+  links?: {
+    content?: string
+    gallery?: string
+    self?: string
+    shelf?: string
+  }
+  metadata?: Record<string, string>
+  name?: string
+  path?: string
+  preview?: {
+    error?: string
+    image?: {
+      checksum?: string
+      disk?: string
+      filesize?: number
+      height?: number
+      name?: string
+      path?: string
+      storagePath?: string
+      tags?: string[]
+      width?: number
+    }
+    status?: string
+  }
+  storagePath?: string
+  tags?: string[]
+  uniqueName?: string
+  variants?: Record<string, {
+    checksum?: string
+    disk?: string
+    filesize?: number
+    name?: string
+    path?: string
+    storagePath?: string
+    tags?: string[]
+  }>
+}
+
+/**
+ * Upload document.
+ */
+export async function uploadDocument(
+  client: AxiosInstance,
+  shelfId: string,
+  body: UploadDocumentForm,
+  config?: AxiosRequestConfig
+): Promise<UploadDocumentResponse> {
+  const form = new FormData()
+  if (body.disk !== undefined) {
+    form.append('disk', body.disk)
+  }
+  if (body.name !== undefined) {
+    form.append('name', body.name)
+  }
+  if (body.path !== undefined) {
+    form.append('path', body.path)
+  }
+  if (body.uniqueName !== undefined) {
+    form.append('uniqueName', body.uniqueName)
+  }
+  form.append('document', body.document)
+
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/documents`,
+    data: form,
+  })
+  return data
+}
+
+/**
+ * Body of POST /shelfs/{ShelfID}/documents/tags. The tags in add are added to
+ * and the tags in remove are removed from every listed document of the shelf.
+ * Either add or remove must contain at least one tag.
+ */
+export type RetagDocumentsRequest = {
+  add?: string[]
+  documentIds: string[]
+  remove?: string[]
+}
+
+export type RetagDocumentsResponse = {
+  documents: {
+    audio?: {
+      bitrate?: number
+      duration?: number
+    }
+    checksum?: string
+    disk?: string
+    filesize?: number
+    id?: string
+    legalHold?: {
+      reason?: string
+      since?: string
+    }
+    metadata?: Record<string, string>
+    name?: string
+    path?: string
+    preview?: {
+      error?: string
+      image?: {
+        checksum?: string
+        disk?: string
+        filesize?: number
+        height?: number
+        name?: string
+        path?: string
+        storagePath?: string
+        tags?: string[]
+        width?: number
+      }
+      status?: string
+    }
+    storagePath?: string
+    tags?: string[]
+    uniqueName?: string
+    variants?: Record<string, {
+      checksum?: string
+      disk?: string
+      filesize?: number
+      name?: string
+      path?: string
+      storagePath?: string
+      tags?: string[]
+    }>
+  }[]
+}
+
+/**
+ * Retag documents.
+ */
+export async function retagDocuments(
+  client: AxiosInstance,
+  shelfId: string,
+  body: RetagDocumentsRequest,
+  config?: AxiosRequestConfig
+): Promise<RetagDocumentsResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/documents/tags`,
+    data: body,
+  })
+  return data
+}
+
+export type ShowDocumentResponse = {
+  audio?: {
+    bitrate?: number
+    duration?: number
+  }
+  checksum?: string
+  disk?: string
+  filesize?: number
+  id?: string
+  legalHold?: {
+    reason?: string
+    since?: string
+  }
+  metadata?: Record<string, string>
+  name?: string
+  path?: string
+  preview?: {
+    error?: string
+    image?: {
+      checksum?: string
+      disk?: string
+      filesize?: number
+      height?: number
+      name?: string
+      path?: string
+      storagePath?: string
+      tags?: string[]
+      width?: number
+    }
+    status?: string
+  }
+  storagePath?: string
+  tags?: string[]
+  uniqueName?: string
+  variants?: Record<string, {
+    checksum?: string
+    disk?: string
+    filesize?: number
+    name?: string
+    path?: string
+    storagePath?: string
+    tags?: string[]
+  }>
+}
+
+/**
+ * Show document.
+ */
+export async function showDocument(
+  client: AxiosInstance,
+  shelfId: string,
+  documentId: string,
+  config?: AxiosRequestConfig
+): Promise<ShowDocumentResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/documents/${encodeURIComponent(documentId)}`,
+  })
+  return data
+}
+
+export type ReplaceDocumentForm = {
+  document: Blob
+}
+
+export type ReplaceDocumentResponse = ShowDocumentResponse
+
+/**
+ * Replace document.
+ */
+export async function replaceDocument(
+  client: AxiosInstance,
+  shelfId: string,
+  documentId: string,
+  body: ReplaceDocumentForm,
+  config?: AxiosRequestConfig
+): Promise<ReplaceDocumentResponse> {
+  const form = new FormData()
+  form.append('document', body.document)
+
+  const { data } = await client.request({
+    ...config,
+    method: 'PUT',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/documents/${encodeURIComponent(documentId)}`,
+    data: form,
+  })
+  return data
+}
+
+/**
+ * Body of PATCH /shelfs/{ShelfID}/documents/{DocumentID}. An empty uniqueName
+ * makes the document non-unique. Metadata fields are added or overwritten; null
+ * values remove them.
+ */
+export type UpdateDocumentRequest = {
+  metadata?: Record<string, string>
+  name: string
+  uniqueName?: string
+}
+
+export type UpdateDocumentResponse = ShowDocumentResponse
+
+/**
+ * Update document.
+ */
+export async function updateDocument(
+  client: AxiosInstance,
+  shelfId: string,
+  documentId: string,
+  body: UpdateDocumentRequest,
+  config?: AxiosRequestConfig
+): Promise<UpdateDocumentResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'PATCH',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/documents/${encodeURIComponent(documentId)}`,
+    data: body,
+  })
+  return data
+}
+
+/**
+ * Delete document.
+ */
+export async function deleteDocument(
+  client: AxiosInstance,
+  shelfId: string,
+  documentId: string,
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'DELETE',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/documents/${encodeURIComponent(documentId)}`,
+  })
+}
+
+export type ShowDocumentContentQuery = {
+  /**
+   * Locale of the variant to serve. Falls back to the default content.
+   */
+  locale?: string
+}
+
+/**
+ * Show document content.
+ */
+export async function showDocumentContent(
+  client: AxiosInstance,
+  shelfId: string,
+  documentId: string,
+  query: ShowDocumentContentQuery = {},
+  config?: AxiosRequestConfig
+): Promise<Blob> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/documents/${encodeURIComponent(documentId)}/content`,
+    params: query,
+    responseType: 'blob',
+  })
+  return data
+}
+
+export type HeadDocumentContentQuery = {
+  /**
+   * Locale of the variant. Falls back to the default content.
+   */
+  locale?: string
+}
+
+/**
+ * Show document content headers.
+ */
+export async function headDocumentContent(
+  client: AxiosInstance,
+  shelfId: string,
+  documentId: string,
+  query: HeadDocumentContentQuery = {},
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'HEAD',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/documents/${encodeURIComponent(documentId)}/content`,
+    params: query,
+  })
+}
+
+/**
+ * Body of POST /shelfs/{ShelfID}/documents/{DocumentID}/copy. The document and
+ * its file are copied to the shelf with the given UUID, optionally to another
+ * disk and path. The copy keeps the name, tags and metadata of the document and
+ * gets a new UUID.
+ */
+export type CopyDocumentRequest = {
+  disk?: string
+  path?: string
+  shelfId: string
+}
+
+export type CopyDocumentResponse = UploadDocumentResponse
+
+/**
+ * Copy document.
+ */
+export async function copyDocument(
+  client: AxiosInstance,
+  shelfId: string,
+  documentId: string,
+  body: CopyDocumentRequest,
+  config?: AxiosRequestConfig
+): Promise<CopyDocumentResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/documents/${encodeURIComponent(documentId)}/copy`,
+    data: body,
+  })
+  return data
+}
+
+export type ReplaceDocumentDeltaResponse = ShowDocumentResponse
+
+/**
+ * Replace document with delta.
+ *
+ * Replaces the content of the document by applying a binary delta to the
+ * current content.
+ */
+export async function replaceDocumentDelta(
+  client: AxiosInstance,
+  shelfId: string,
+  documentId: string,
+  body: Blob | ArrayBuffer,
+  config?: AxiosRequestConfig
+): Promise<ReplaceDocumentDeltaResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'PUT',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/documents/${encodeURIComponent(documentId)}/delta`,
+    data: body,
+    headers: { ...config?.headers, 'Content-Type': 'application/octet-stream' },
+  })
+  return data
+}
+
+/**
+ * Body of the PUT legal-hold routes of shelfs, documents, galleries and stacks.
+ * Held resources cannot be deleted until the hold is lifted.
+ */
+export type SetDocumentLegalHoldRequest = SetGalleryLegalHoldRequest
+
+/**
+ * Set document legal hold.
+ */
+export async function setDocumentLegalHold(
+  client: AxiosInstance,
+  shelfId: string,
+  documentId: string,
+  body: SetDocumentLegalHoldRequest,
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'PUT',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/documents/${encodeURIComponent(documentId)}/legal-hold`,
+    data: body,
+  })
+}
+
+/**
+ * Lift document legal hold.
+ */
+export async function liftDocumentLegalHold(
+  client: AxiosInstance,
+  shelfId: string,
+  documentId: string,
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'DELETE',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/documents/${encodeURIComponent(documentId)}/legal-hold`,
+  })
+}
+
+/**
+ * Show document preview.
+ */
+export async function showDocumentPreview(
+  client: AxiosInstance,
+  shelfId: string,
+  documentId: string,
+  config?: AxiosRequestConfig
+): Promise<Blob> {
+  const { data } = await client.request({
+    ...config,
+    method: 'GET',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/documents/${encodeURIComponent(documentId)}/preview`,
+    responseType: 'blob',
+  })
+  return data
+}
+
+/**
+ * Body of POST /shelfs/{ShelfID}/documents/{DocumentID}/signed-url. The expiry
+ * is given in seconds and defaults to 15 minutes. The locale may contain
+ * multiple, comma-separated locales in order of preference.
+ */
+export type SignDocumentURLRequest = {
+  expiry?: number
+  locale?: string
+}
+
+export type SignDocumentURLResponse = SignStackURLResponse
+
+/**
+ * Sign document URL.
+ */
+export async function signDocumentURL(
+  client: AxiosInstance,
+  shelfId: string,
+  documentId: string,
+  body?: SignDocumentURLRequest,
+  config?: AxiosRequestConfig
+): Promise<SignDocumentURLResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/documents/${encodeURIComponent(documentId)}/signed-url`,
+    data: body,
+  })
+  return data
+}
+
+/**
+ * Body of POST /shelfs/{ShelfID}/documents/{DocumentID}/tags.
+ */
+export type TagDocumentRequest = SetGalleryDefaultTagsRequest
+
+export type TagDocumentResponse = ShowDocumentResponse
+
+/**
+ * Tag document.
+ */
+export async function tagDocument(
+  client: AxiosInstance,
+  shelfId: string,
+  documentId: string,
+  body: TagDocumentRequest,
+  config?: AxiosRequestConfig
+): Promise<TagDocumentResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/documents/${encodeURIComponent(documentId)}/tags`,
+    data: body,
+  })
+  return data
+}
+
+export type UntagDocumentResponse = ShowDocumentResponse
+
+/**
+ * Untag document.
+ */
+export async function untagDocument(
+  client: AxiosInstance,
+  shelfId: string,
+  documentId: string,
+  tags: string,
+  config?: AxiosRequestConfig
+): Promise<UntagDocumentResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'DELETE',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/documents/${encodeURIComponent(documentId)}/tags/${encodeURIComponent(tags)}`,
+  })
+  return data
+}
+
+export type ExportShelfResponse = ExportGalleryResponse
+
+/**
+ * Export shelf.
+ */
+export async function exportShelf(
+  client: AxiosInstance,
+  shelfId: string,
+  config?: AxiosRequestConfig
+): Promise<ExportShelfResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/exports`,
+  })
+  return data
+}
+
+/**
+ * Body of the PUT legal-hold routes of shelfs, documents, galleries and stacks.
+ * Held resources cannot be deleted until the hold is lifted.
+ */
+export type SetShelfLegalHoldRequest = SetGalleryLegalHoldRequest
+
+/**
+ * Set shelf legal hold.
+ */
+export async function setShelfLegalHold(
+  client: AxiosInstance,
+  shelfId: string,
+  body: SetShelfLegalHoldRequest,
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'PUT',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/legal-hold`,
+    data: body,
+  })
+}
+
+/**
+ * Lift shelf legal hold.
+ */
+export async function liftShelfLegalHold(
+  client: AxiosInstance,
+  shelfId: string,
+  config?: AxiosRequestConfig
+): Promise<void> {
+  await client.request({
+    ...config,
+    method: 'DELETE',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/legal-hold`,
+  })
+}
+
+export type RestoreDocumentResponse = ShowDocumentResponse
+
+/**
+ * Restore document.
+ */
+export async function restoreDocument(
+  client: AxiosInstance,
+  shelfId: string,
+  documentId: string,
+  config?: AxiosRequestConfig
+): Promise<RestoreDocumentResponse> {
+  const { data } = await client.request({
+    ...config,
+    method: 'POST',
+    url: `/shelfs/${encodeURIComponent(shelfId)}/trash/${encodeURIComponent(documentId)}/restore`,
+  })
+  return data
+}
@@ -1,2 +1,3 @@
 export * from './shelf'
 export * from './lookup'
+export * from './search'
@@ -0,0 +1,121 @@
+import type { AxiosInstance } from 'axios'
+import type { ShelfDocument } from './shelf'
+
+/**
+ * Query for documents across all shelfs. A document must match every filter.
+ */
+export interface SearchQuery {
+  /**
+   * Cursor of the page, as returned in the nextCursor of the previous page.
+   * Takes precedence over the offset.
+   */
+  cursor?: string
+
+  /**
+   * Number of matching documents to skip.
+   */
+  offset?: number
+
+  /**
+   * Maximum number of documents to return (default 50, at most 500).
+   */
+  limit?: number
+
+  /**
+   * Only return documents whose name or unique name contains the search term,
+   * ignoring case.
+   */
+  search?: string
+
+  /**
+   * Only return documents that have one of the names.
+   */
+  names?: string[]
+
+  /**
+   * Only return documents that have one of the unique names.
+   */
+  uniqueNames?: string[]
+
+  /**
+   * Only return documents that have at least one of the tags.
+   */
+  tags?: string[]
+
+  /**
+   * Regular expression (Go syntax) that is matched against the storage paths
+   * of the documents.
+   */
+  path?: string
+}
+
+/**
+ * A document that was found by a search, and the shelf of the document.
+ */
+export interface SearchResult extends ShelfDocument {
+  shelfId: string
+  shelfName: string
+}
+
+/**
+ * A page of the documents that match a search query.
+ */
+export interface SearchResults {
+  documents: SearchResult[]
+
+  /**
+   * Number of documents that match the query.
+   */
+  total: number
+
+  offset: number
+  limit: number
+
+  /**
+   * Cursor of the next page. Undefined if this is the last page.
+   */
+  nextCursor?: string
+
+  links: {
+    /**
+     * URL of the next page. Undefined if this is the last page.
+     */
+    next?: string
+  }
+}
+
+/**
+ * Searches the documents of all shelfs.
+ */
+export async function searchDocuments(
+  client: AxiosInstance,
+  query: SearchQuery = {}
+): Promise<SearchResults> {
+  const params = new URLSearchParams()
+  if (query.search) {
+    params.set('q', query.search)
+  }
+  for (const name of query.names || []) {
+    params.append('name', name)
+  }
+  for (const name of query.uniqueNames || []) {
+    params.append('uniqueName', name)
+  }
+  if (query.tags?.length) {
+    params.set('tags', query.tags.join(','))
+  }
+  if (query.path) {
+    params.set('path', query.path)
+  }
+  if (query.cursor) {
+    params.set('cursor', query.cursor)
+  } else {
+    params.set('offset', String(query.offset || 0))
+  }
+  if (query.limit) {
+    params.set('limit', String(query.limit))
+  }
+
+  const { data } = await client.get('/search', { params })
+  return data
+}
@@ -1,3 +1,6 @@
+import { AxiosInstance } from 'axios'
+import { AudioMetadata, Image, LegalHold } from '../media'
+
 /**
  * A shelf of documents.
  */
@@ -13,6 +16,23 @@ export interface Shelf {
    * Documents of this shelf.
    */
   documents: ShelfDocument[]
+
+  /**
+   * Documents in the trash of this shelf. Trashed documents are deleted after
+   * the retention period of the server.
+   */
+  trashed?: TrashedDocument[]
+
+  /**
+   * Legal hold of the shelf. While the shelf is held, none of its documents
+   * can be deleted.
+   */
+  legalHold?: LegalHold
+
+  /**
+   * Version of the shelf. The version changes whenever the shelf changes.
+   */
+  version: number
 }
 
 /**
@@ -26,4 +46,262 @@ export interface ShelfDocument extends Document {
    * document. Uniqueness across multiple shelfs is not guaranteed.
    */
   uniqueName: string
+
+  /**
+   * Rendered preview of the document. Previews are rendered asynchronously
+   * after the document has been uploaded or replaced.
+   */
+  preview?: DocumentPreview
+
+  /**
+   * Metadata of an audio document. Only set for audio files after the
+   * metadata has been extracted.
+   */
+  audio?: AudioMetadata
+
+  /**
+   * Legal hold of the document.
+   */
+  legalHold?: LegalHold
+
+  /**
+   * Custom key/value fields of the document, e.g. a copyright notice.
+   */
+  metadata?: Record<string, string>
+}
+
+/**
+ * A document in the trash of a shelf.
+ */
+export interface TrashedDocument extends ShelfDocument {
+  /**
+   * Time at which the document was moved to the trash.
+   */
+  trashedAt: string
+}
+
+/**
+ * Preview image of a document.
+ */
+export interface DocumentPreview {
+  status: 'pending' | 'rendered' | 'failed'
+
+  /**
+   * Preview image. Only set if the status is "rendered".
+   */
+  image: Image
+
+  /**
+   * Rendering error if the status is "failed".
+   */
+  error?: string
+}
+
+/**
+ * Query for a page of the documents of a shelf.
+ */
+export interface DocumentQuery {
+  /**
+   * Cursor of the page, as returned in the nextCursor of the previous page.
+   * Takes precedence over the offset.
+   */
+  cursor?: string
+
+  /**
+   * Number of matching documents to skip.
+   */
+  offset?: number
+
+  /**
+   * Maximum number of documents to return (default 50, at most 500).
+   */
+  limit?: number
+
+  /**
+   * Only return documents that have at least one of the tags.
+   */
+  tags?: string[]
+
+  /**
+   * Only return documents whose name or unique name contains the search term,
+   * ignoring case.
+   */
+  search?: string
+
+  /**
+   * Comma-separated sort fields ("name", "uniqueName" or "filesize"). A "-"
+   * prefix sorts a field in descending order.
+   */
+  sort?: string
+}
+
+/**
+ * A page of the documents of a shelf.
+ */
+export interface DocumentPage {
+  id: string
+  name: string
+  documents: ShelfDocument[]
+  legalHold?: LegalHold
+
+  /**
+   * Number of documents that match the query.
+   */
+  total: number
+
+  offset: number
+  limit: number
+
+  /**
+   * Cursor of the next page. Undefined if this is the last page.
+   */
+  nextCursor?: string
+
+  links: {
+    /**
+     * URL of the next page. Undefined if this is the last page.
+     */
+    next?: string
+  }
+}
+
+/**
+ * The UUID and name of a shelf.
+ */
+export interface ShelfRef {
+  id: string
+  name: string
+}
+
+/**
+ * Query for a page of all shelfs.
+ */
+export interface ShelfListQuery {
+  /**
+   * Cursor of the page, as returned in the nextCursor of the previous page.
+   * Takes precedence over the offset.
+   */
+  cursor?: string
+
+  /**
+   * Number of shelfs to skip.
+   */
+  offset?: number
+
+  /**
+   * Maximum number of shelfs to return (default 50, at most 500).
+   */
+  limit?: number
+}
+
+/**
+ * A page of all shelfs, sorted by name.
+ */
+export interface ShelfList {
+  shelfs: ShelfRef[]
+
+  /**
+   * Number of shelfs.
+   */
+  total: number
+
+  offset: number
+  limit: number
+
+  /**
+   * Cursor of the next page. Undefined if this is the last page.
+   */
+  nextCursor?: string
+
+  links: {
+    /**
+     * URL of the next page. Undefined if this is the last page.
+     */
+    next?: string
+  }
+}
+
+/**
+ * Fetches a page of the UUIDs and names of all shelfs, sorted by name.
+ */
+export async function listShelfs(
+  client: AxiosInstance,
+  query: ShelfListQuery = {}
+): Promise<ShelfList> {
+  const params = new URLSearchParams()
+  if (query.cursor) {
+    params.set('cursor', query.cursor)
+  } else {
+    params.set('offset', String(query.offset || 0))
+  }
+  if (query.limit) {
+    params.set('limit', String(query.limit))
+  }
+
+  const { data } = await client.get('/shelfs', { params })
+  return data
+}
+
+/**
+ * Create a new shelf with the given name. Shelf names must be unique.
+ */
+export async function createShelf(
+  client: AxiosInstance,
+  name: string
+): Promise<Shelf> {
+  const { data } = await client.post('/shelfs', { name })
+  return data
+}
+
+/**
+ * Rename the shelf with the given UUID. Shelf names must be unique.
+ */
+export async function renameShelf(
+  client: AxiosInstance,
+  id: string,
+  name: string
+): Promise<Shelf> {
+  const { data } = await client.patch(`/shelfs/${id}`, { name })
+  return data
+}
+
+/**
+ * Delete the shelf with the given UUID and the files of all of its documents.
+ */
+export async function deleteShelf(
+  client: AxiosInstance,
+  id: string
+): Promise<void> {
+  await client.delete(`/shelfs/${id}`)
+}
+
+/**
+ * Fetches a page of the documents of a shelf.
+ */
+export async function listShelfDocuments(
+  client: AxiosInstance,
+  shelfId: string,
+  query: DocumentQuery = {}
+): Promise<DocumentPage> {
+  const params = new URLSearchParams()
+  for (const tag of query.tags || []) {
+    params.append('tag', tag)
+  }
+  if (query.search) {
+    params.set('q', query.search)
+  }
+  if (query.sort) {
+    params.set('sort', query.sort)
+  }
+  if (query.cursor) {
+    params.set('cursor', query.cursor)
+  } else {
+    params.set('offset', String(query.offset || 0))
+  }
+  if (query.limit) {
+    params.set('limit', String(query.limit))
+  }
+
+  const { data } = await client.get(`/shelfs/${shelfId}`, { params })
+  return data
 }
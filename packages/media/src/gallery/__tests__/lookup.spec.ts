@@ -1,5 +1,9 @@
 import { createTestClient, exampleUUID, nilUUID } from '@nice-cms/testing'
-import { lookupGalleryByName, lookupGalleryStackByName } from '../lookup'
+import {
+  lookupGalleryByName,
+  lookupGalleryStackByName,
+  lookupGalleryStacksByTag,
+} from '../lookup'
 
 test('lookupGalleryByName', async () => {
   const { client, mock } = createTestClient()
@@ -64,3 +68,15 @@ describe('lookupGalleryStackByName', () => {
     expect(found!).toBe(false)
   })
 })
+
+test('lookupGalleryStacksByTag', async () => {
+  const { client, mock } = createTestClient()
+
+  mock
+    .onGet(`/galleries/${exampleUUID}/stacks`, { params: { tag: 'hero' } })
+    .reply(200, { stackIds: [exampleUUID] })
+
+  const ids = await lookupGalleryStacksByTag(client, exampleUUID, 'hero')
+
+  expect(ids).toEqual([exampleUUID])
+})
@@ -1,6 +1,6 @@
 import { AxiosInstance } from 'axios'
 import { ApiResponse } from '@nice-cms/core'
-import { Image } from '../media'
+import { Image, LegalHold, Video } from '../media'
 
 /**
  * A gallery of images.
@@ -17,6 +17,162 @@ export interface Gallery {
    * Images of the gallery as stacks.
    */
   stacks: Stack[]
+
+  /**
+   * Tags that are added to every image that is uploaded to the gallery.
+   */
+  defaultTags?: string[]
+
+  /**
+   * Color theme of the gallery, computed from the palettes of its recently
+   * processed stacks. Can be used to tint the page of the gallery.
+   */
+  theme?: GalleryTheme
+
+  /**
+   * Whether the original images of the gallery are protected. The originals
+   * of a protected gallery are only served to authorized clients.
+   */
+  originalsProtected?: boolean
+
+  /**
+   * Stacks in the trash of the gallery. Trashed stacks are deleted after a
+   * retention period unless they are restored.
+   */
+  trashed?: TrashedStack[]
+
+  /**
+   * Legal hold of the gallery. While the gallery is held, none of its stacks
+   * can be deleted.
+   */
+  legalHold?: LegalHold
+
+  /**
+   * Number of previous versions that are kept for each stack when it is
+   * replaced. Stacks are replaced in place if undefined or 0.
+   */
+  versionLimit?: number
+
+  /**
+   * Version of the gallery. The version changes whenever the gallery changes.
+   */
+  version: number
+}
+
+/**
+ * A stack in the trash of a gallery.
+ */
+export interface TrashedStack extends Stack {
+  /**
+   * Time at which the stack was moved to the trash.
+   */
+  trashedAt: string
+}
+
+/**
+ * Color theme of a gallery. Colors are hex colors ("#rrggbb").
+ */
+export interface GalleryTheme {
+  primary: string
+  secondary?: string
+}
+
+/**
+ * Index of a gallery that contains only the summaries of its stacks. Use the
+ * index to render a grid of images and fetch the stacks in detail when needed.
+ */
+export interface GalleryIndex {
+  id: string
+  name: string
+  stacks: StackSummary[]
+}
+
+/**
+ * Summary of a stack within a gallery index.
+ */
+export interface StackSummary {
+  id: string
+  name: string
+  tags: string[]
+
+  /**
+   * Smallest up-to-date image of the stack.
+   */
+  thumbnail: StackImage
+
+  /**
+   * BlurHash of the original image. Only set after the stack has been processed.
+   */
+  placeholder?: string
+
+  /**
+   * Indicates whether the stack is currently being processed.
+   */
+  pending: boolean
+}
+
+/**
+ * Query for a page of the stacks of a gallery.
+ */
+export interface StackQuery {
+  /**
+   * Cursor of the page, as returned in the nextCursor of the previous page.
+   * Takes precedence over the offset.
+   */
+  cursor?: string
+
+  /**
+   * Number of matching stacks to skip.
+   */
+  offset?: number
+
+  /**
+   * Maximum number of stacks to return (default 50, at most 500).
+   */
+  limit?: number
+
+  /**
+   * Only return stacks that have all of the tags.
+   */
+  tags?: string[]
+
+  /**
+   * Comma-separated sort fields ("name", "filesize" or "processedAt"). A "-"
+   * prefix sorts a field in descending order. Without sort fields, stacks are
+   * returned in the order of the gallery.
+   */
+  sort?: string
+}
+
+/**
+ * A page of the stacks of a gallery.
+ */
+export interface StackPage {
+  id: string
+  name: string
+  stacks: Stack[]
+  originalsProtected?: boolean
+  legalHold?: LegalHold
+
+  /**
+   * Number of stacks that match the query.
+   */
+  total: number
+
+  offset: number
+  limit: number
+
+  /**
+   * Cursor of the next page. Undefined if this is the last page.
+   */
+  nextCursor?: string
+
+  links: {
+    /**
+     * URL of the next page. Undefined if this is the last page.
+     */
+    next?: string
+  }
 }
 
 /**
@@ -26,9 +182,154 @@ export interface Stack {
   id: string
 
   /**
-   * Variants of the image.
+   * Variants of the image. For video stacks, the original image is the frame
+   * that was extracted from the video during processing.
    */
   images: StackImage[]
+
+  /**
+   * Alternative text of the stack for screen readers and for clients that
+   * cannot display the image.
+   */
+  alt?: string
+
+  /**
+   * Caption of the stack.
+   */
+  caption?: string
+
+  /**
+   * Custom key/value fields of the stack, e.g. a copyright notice or license.
+   */
+  customMetadata?: Record<string, string>
+
+  /**
+   * Focal point of the stack that cropped images are centered on.
+   */
+  focalPoint?: FocalPoint
+
+  /**
+   * Area of the original image that the variants of the stack are generated
+   * from.
+   */
+  crop?: Area
+
+  /**
+   * Video of the stack. Only set for video stacks.
+   */
+  video?: Video
+
+  /**
+   * SHA-256 checksum of the uploaded image or video. Can be used to detect
+   * duplicate uploads.
+   */
+  checksum?: string
+
+  /**
+   * BlurHash of the original image that can be rendered as a placeholder while
+   * the image is loading. Only set after the stack has been processed.
+   */
+  placeholder?: string
+
+  /**
+   * Dominant colors of the original image as hex colors ("#rrggbb"), ordered
+   * by dominance. Only set after the stack has been processed.
+   */
+  palette?: string[]
+
+  /**
+   * Camera metadata of the original image. Only set after the stack has been
+   * processed by the EXIF processor and if the original image has EXIF metadata.
+   */
+  metadata?: StackMetadata
+
+  /**
+   * Indicates whether the stack is currently being processed.
+   */
+  pending: boolean
+
+  /**
+   * Time at which the stack was last processed successfully. Undefined if the
+   * stack was not processed yet.
+   */
+  processedAt?: Date
+
+  /**
+   * Error of the last failed processing attempt.
+   */
+  processingError?: string
+
+  /**
+   * Legal hold of the stack.
+   */
+  legalHold?: LegalHold
+
+  /**
+   * Version of the stack, incremented each time the stack is replaced or
+   * reverted.
+   */
+  version?: number
+
+  /**
+   * Previous versions of the stack, oldest first. Only kept if the gallery has
+   * a version limit.
+   */
+  versions?: StackVersion[]
+}
+
+/**
+ * A previous version of a stack.
+ */
+export interface StackVersion {
+  version: number
+  images: Image[]
+  video?: Video
+  checksum?: string
+
+  /**
+   * Time at which the version was replaced.
+   */
+  replacedAt: string
+}
+
+/**
+ * Processing status of a stack.
+ */
+export interface StackStatus {
+  pending: boolean
+  processedAt?: Date
+  processingError?: string
+}
+
+/**
+ * Camera metadata that was extracted from the EXIF metadata of an image.
+ */
+export interface StackMetadata {
+  make?: string
+  model?: string
+  lensModel?: string
+  takenAt: string
+
+  /**
+   * Exposure time in seconds.
+   */
+  exposureTime?: number
+  fNumber?: number
+  iso?: number
+
+  /**
+   * Focal length in millimeters.
+   */
+  focalLength?: number
+
+  /**
+   * GPS coordinates of the image. Only set if the EXIF processor is configured
+   * to keep GPS coordinates.
+   */
+  gps?: {
+    latitude: number
+    longitude: number
+  }
 }
 
 /**
@@ -45,6 +346,52 @@ export interface StackImage extends Image {
    * "highres" etc.). The original image has no size key (empty string).
    */
   size: string
+
+  /**
+   * Indicates whether the image was cropped to the exact dimensions of its
+   * size around the focal point of its stack.
+   */
+  cropped?: boolean
+}
+
+/**
+ * Focal point of an image as percentages of its width and height, measured
+ * from the top-left corner.
+ */
+export interface FocalPoint {
+  x: number
+  y: number
+}
+
+/**
+ * Rectangular area of an image as percentages of its width and height,
+ * measured from the top-left corner.
+ */
+export interface Area {
+  x: number
+  y: number
+  width: number
+  height: number
+}
+
+/**
+ * Name of an event that is streamed by the gallery event stream.
+ */
+export type GalleryEventName =
+  | 'stackProcessed'
+  | 'stackDeleted'
+  | 'imageReplaced'
+  | 'stackTrashed'
+  | 'stackRestored'
+
+/**
+ * Event of the gallery event stream.
+ */
+export interface GalleryEvent {
+  name: GalleryEventName
+  galleryId: string
+  stackId: string
+  stack: Stack
 }
 
 /**
@@ -57,16 +404,50 @@ export function hydrateGallery(data: ApiResponse<Gallery>): Gallery {
   }
 }
 
+/**
+ * Hydrates an API response into a GalleryIndex.
+ */
+export function hydrateGalleryIndex(
+  data: ApiResponse<GalleryIndex>
+): GalleryIndex {
+  return {
+    ...data,
+    stacks: (data.stacks as any[]).map(hydrateStackSummary),
+  }
+}
+
+/**
+ * Hydrates an API response into a StackSummary.
+ */
+export function hydrateStackSummary(
+  data: ApiResponse<StackSummary>
+): StackSummary {
+  return {
+    ...data,
+    tags: data.tags || [],
+    thumbnail: hydrateStackImage(data.thumbnail),
+  }
+}
+
 /**
  * Hydrates an API response into a Stack.
  */
 export function hydrateStack(data: ApiResponse<Stack>): Stack {
   return {
     ...data,
-    images: data.images.map(hydrateStackImage),
+    images: (data.images || []).map(hydrateStackImage),
+    processedAt: hydrateTime(data.processedAt as any),
   }
 }
 
+function hydrateTime(value?: string | null) {
+  // Go encodes the zero time as "0001-01-01T00:00:00Z".
+  if (!value || value.startsWith('0001-')) {
+    return undefined
+  }
+  return new Date(value)
+}
+
 /**
  * Hydrates an API response into a StackImage.
  */
@@ -76,6 +457,83 @@ export function hydrateStackImage(data: ApiResponse<StackImage>): StackImage {
   }
 }
 
+/**
+ * The UUID and name of a gallery.
+ */
+export interface GalleryRef {
+  id: string
+  name: string
+}
+
+/**
+ * Query for a page of all galleries.
+ */
+export interface GalleryListQuery {
+  /**
+   * Cursor of the page, as returned in the nextCursor of the previous page.
+   * Takes precedence over the offset.
+   */
+  cursor?: string
+
+  /**
+   * Number of galleries to skip.
+   */
+  offset?: number
+
+  /**
+   * Maximum number of galleries to return (default 50, at most 500).
+   */
+  limit?: number
+}
+
+/**
+ * A page of all galleries, sorted by name.
+ */
+export interface GalleryList {
+  galleries: GalleryRef[]
+
+  /**
+   * Number of galleries.
+   */
+  total: number
+
+  offset: number
+  limit: number
+
+  /**
+   * Cursor of the next page. Undefined if this is the last page.
+   */
+  nextCursor?: string
+
+  links: {
+    /**
+     * URL of the next page. Undefined if this is the last page.
+     */
+    next?: string
+  }
+}
+
+/**
+ * Fetches a page of the UUIDs and names of all galleries, sorted by name.
+ */
+export async function listGalleries(
+  client: AxiosInstance,
+  query: GalleryListQuery = {}
+): Promise<GalleryList> {
+  const params = new URLSearchParams()
+  if (query.cursor) {
+    params.set('cursor', query.cursor)
+  } else {
+    params.set('offset', String(query.offset || 0))
+  }
+  if (query.limit) {
+    params.set('limit', String(query.limit))
+  }
+
+  const { data } = await client.get('/galleries', { params })
+  return data
+}
+
 /**
  * Create a new gallery with the given name.
  */
@@ -84,6 +542,25 @@ export async function createGallery(client: AxiosInstance, name: string) {
   return hydrateGallery(data)
 }
 
+/**
+ * Rename the gallery with the given UUID. Gallery names must be unique.
+ */
+export async function renameGallery(
+  client: AxiosInstance,
+  id: string,
+  name: string
+) {
+  const { data } = await client.patch(`/galleries/${id}`, { name })
+  return hydrateGallery(data)
+}
+
+/**
+ * Delete the gallery with the given UUID and the files of all of its stacks.
+ */
+export async function deleteGallery(client: AxiosInstance, id: string) {
+  await client.delete(`/galleries/${id}`)
+}
+
 /**
  * Fetch the gallery with the given UUID.
  */
@@ -92,6 +569,58 @@ export async function fetchGallery(client: AxiosInstance, id: string) {
   return hydrateGallery(data)
 }
 
+/**
+ * Fetch the index of the gallery with the given UUID. The index contains only
+ * the summaries of the stacks and is much smaller than the full gallery.
+ */
+export async function fetchGalleryIndex(client: AxiosInstance, id: string) {
+  const { data } = await client.get(`/galleries/${id}/index`)
+  return hydrateGalleryIndex(data)
+}
+
+/**
+ * Fetch a page of the stacks of a gallery.
+ */
+export async function listGalleryStacks(
+  client: AxiosInstance,
+  id: string,
+  query: StackQuery = {}
+): Promise<StackPage> {
+  const params = new URLSearchParams()
+  if (query.tags?.length) {
+    params.set('tags', query.tags.join(','))
+  }
+  if (query.sort) {
+    params.set('sort', query.sort)
+  }
+  if (query.cursor) {
+    params.set('cursor', query.cursor)
+  } else {
+    params.set('offset', String(query.offset || 0))
+  }
+  if (query.limit) {
+    params.set('limit', String(query.limit))
+  }
+
+  const { data } = await client.get(`/galleries/${id}`, { params })
+  return {
+    ...data,
+    stacks: (data.stacks as any[]).map(hydrateStack),
+  }
+}
+
+/**
+ * Fetch a single stack of a gallery.
+ */
+export async function fetchGalleryStack(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string
+) {
+  const { data } = await client.get(`/galleries/${galleryId}/stacks/${stackId}`)
+  return hydrateStack(data)
+}
+
 /**
  * Uploads an image into a gallery and returns the created stack. The stack is
  * pushed into the provided gallery's stacks.
@@ -104,11 +633,13 @@ export async function uploadToGallery(
   disk: string,
   path: string
 ) {
+  // The fields must be sent before the image so that the server can stream
+  // the image without buffering it.
   const formData = new FormData()
-  formData.append('image', image)
   formData.append('name', name)
   formData.append('disk', disk)
   formData.append('path', path)
+  formData.append('image', image)
 
   const { data } = await client.post(
     `/galleries/${gallery.id}/stacks`,
@@ -121,6 +652,37 @@ export async function uploadToGallery(
   return stack
 }
 
+/**
+ * Uploads a video to the given gallery and returns the created video stack.
+ * The stack has no images until the video has been processed.
+ */
+export async function uploadVideoToGallery(
+  client: AxiosInstance,
+  gallery: Gallery,
+  video: File,
+  name: string,
+  disk: string,
+  path: string
+) {
+  // The fields must be sent before the video so that the server can stream
+  // the video without buffering it.
+  const formData = new FormData()
+  formData.append('name', name)
+  formData.append('disk', disk)
+  formData.append('path', path)
+  formData.append('video', video)
+
+  const { data } = await client.post(
+    `/galleries/${gallery.id}/videos`,
+    formData
+  )
+  const stack = hydrateStack(data)
+
+  gallery.stacks.push(stack)
+
+  return stack
+}
+
 /**
  * Replaces the image of the given stack and returns the updated stack. The
  * stack in the gallery is replaced with that stack.
@@ -158,12 +720,30 @@ export async function updateGalleryStack(
   stackId: string,
   options: {
     name?: string
+
+    /**
+     * Alternative text of the stack. An empty string removes it.
+     */
+    alt?: string
+
+    /**
+     * Caption of the stack. An empty string removes it.
+     */
+    caption?: string
+
+    /**
+     * Custom metadata fields to add or overwrite. Null values remove a field.
+     */
+    customMetadata?: Record<string, string | null>
   }
 ) {
   const { data } = await client.patch(
     `/galleries/${gallery.id}/stacks/${stackId}`,
     {
       name: options.name,
+      alt: options.alt,
+      caption: options.caption,
+      customMetadata: options.customMetadata,
     }
   )
 
@@ -177,6 +757,136 @@ export async function updateGalleryStack(
   return stack
 }
 
+/**
+ * Sets the focal point of the stack with the given stackId and returns the
+ * updated stack. The cropped images of the stack are cropped again in the
+ * background.
+ */
+export async function setGalleryStackFocalPoint(
+  client: AxiosInstance,
+  gallery: Gallery,
+  stackId: string,
+  focalPoint: FocalPoint
+) {
+  const { data } = await client.put(
+    `/galleries/${gallery.id}/stacks/${stackId}/focal-point`,
+    focalPoint
+  )
+
+  const stack = hydrateStack(data)
+  gallery.stacks.splice(
+    gallery.stacks.findIndex((s) => s.id === stackId),
+    1,
+    stack
+  )
+
+  return stack
+}
+
+/**
+ * Crops the stack with the given stackId to an area of its original image and
+ * returns the updated stack. The variants of the stack are generated again
+ * from the area in the background. Cropping to the full image removes the
+ * crop.
+ */
+export async function cropGalleryStack(
+  client: AxiosInstance,
+  gallery: Gallery,
+  stackId: string,
+  area: Area
+) {
+  const { data } = await client.post(
+    `/galleries/${gallery.id}/stacks/${stackId}/crop`,
+    area
+  )
+
+  const stack = hydrateStack(data)
+  gallery.stacks.splice(
+    gallery.stacks.findIndex((s) => s.id === stackId),
+    1,
+    stack
+  )
+
+  return stack
+}
+
+/**
+ * Rotates the original image of the stack with the given stackId clockwise by
+ * the given degrees (a multiple of 90) and returns the updated stack. Negative
+ * degrees rotate counter-clockwise. The variants of the stack are generated
+ * again in the background.
+ */
+export async function rotateGalleryStack(
+  client: AxiosInstance,
+  gallery: Gallery,
+  stackId: string,
+  degrees: number
+) {
+  const { data } = await client.post(
+    `/galleries/${gallery.id}/stacks/${stackId}/rotate`,
+    { degrees }
+  )
+
+  const stack = hydrateStack(data)
+  gallery.stacks.splice(
+    gallery.stacks.findIndex((s) => s.id === stackId),
+    1,
+    stack
+  )
+
+  return stack
+}
+
+/**
+ * Flips the original image of the stack with the given stackId and returns the
+ * updated stack. The variants of the stack are generated again in the
+ * background.
+ */
+export async function flipGalleryStack(
+  client: AxiosInstance,
+  gallery: Gallery,
+  stackId: string,
+  direction: 'horizontal' | 'vertical'
+) {
+  const { data } = await client.post(
+    `/galleries/${gallery.id}/stacks/${stackId}/flip`,
+    { direction }
+  )
+
+  const stack = hydrateStack(data)
+  gallery.stacks.splice(
+    gallery.stacks.findIndex((s) => s.id === stackId),
+    1,
+    stack
+  )
+
+  return stack
+}
+
+/**
+ * Requests the stack with the given stackId to be processed again with the
+ * current processing pipeline and returns the stack. The variants of the stack
+ * are stale until they have been generated again.
+ */
+export async function reprocessGalleryStack(
+  client: AxiosInstance,
+  gallery: Gallery,
+  stackId: string
+) {
+  const { data } = await client.post(
+    `/galleries/${gallery.id}/stacks/${stackId}/process`
+  )
+
+  const stack = hydrateStack(data)
+  gallery.stacks.splice(
+    gallery.stacks.findIndex((s) => s.id === stackId),
+    1,
+    stack
+  )
+
+  return stack
+}
+
 /**
  * Deletes the stack with the given stackId from the gallery and returns the
  * deleted stack.
@@ -247,6 +957,159 @@ export async function sortGallery(
   gallery.stacks = update.stacks
 }
 
+/**
+ * Fetches the processing status of a stack.
+ */
+export async function fetchStackStatus(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string
+): Promise<StackStatus> {
+  const { data } = await client.get(
+    `/galleries/${galleryId}/stacks/${stackId}/status`
+  )
+  return {
+    ...data,
+    processedAt: hydrateTime(data.processedAt),
+  }
+}
+
+/**
+ * Sets the tags that are added to every image that is uploaded to the given
+ * {@link Gallery}.
+ */
+export async function setGalleryDefaultTags(
+  client: AxiosInstance,
+  gallery: Gallery,
+  tags: string[]
+) {
+  await client.put(`/galleries/${gallery.id}/default-tags`, { tags })
+  gallery.defaultTags = tags
+}
+
+/**
+ * Sets whether the original images of the given {@link Gallery} are protected.
+ */
+export async function protectGalleryOriginals(
+  client: AxiosInstance,
+  gallery: Gallery,
+  protect: boolean
+) {
+  await client.put(`/galleries/${gallery.id}/originals-protection`, {
+    protected: protect,
+  })
+  gallery.originalsProtected = protect
+}
+
+/**
+ * Sets a legal hold on the given {@link Gallery}, or on one of its stacks if
+ * a stackId is provided.
+ */
+export async function setGalleryLegalHold(
+  client: AxiosInstance,
+  gallery: Gallery,
+  reason: string,
+  stackId?: string
+) {
+  await client.put(legalHoldPath(gallery, stackId), { reason })
+}
+
+/**
+ * Lifts the legal hold of the given {@link Gallery}, or of one of its stacks
+ * if a stackId is provided.
+ */
+export async function liftGalleryLegalHold(
+  client: AxiosInstance,
+  gallery: Gallery,
+  stackId?: string
+) {
+  await client.delete(legalHoldPath(gallery, stackId))
+}
+
+function legalHoldPath(gallery: Gallery, stackId?: string) {
+  return stackId
+    ? `/galleries/${gallery.id}/stacks/${stackId}/legal-hold`
+    : `/galleries/${gallery.id}/legal-hold`
+}
+
+/**
+ * Fetches the stacks in the trash of a gallery.
+ */
+export async function fetchGalleryTrash(
+  client: AxiosInstance,
+  galleryId: string
+): Promise<TrashedStack[]> {
+  const { data } = await client.get(`/galleries/${galleryId}/trash`)
+  return (data as any[]).map((stack) => ({
+    ...hydrateStack(stack),
+    trashedAt: stack.trashedAt,
+  }))
+}
+
+/**
+ * Restores a stack from the trash of the given {@link Gallery} and adds it to
+ * the end of its stacks.
+ */
+export async function restoreGalleryStack(
+  client: AxiosInstance,
+  gallery: Gallery,
+  stackId: string
+) {
+  const { data } = await client.post(
+    `/galleries/${gallery.id}/trash/${stackId}/restore`
+  )
+  const stack = hydrateStack(data)
+  gallery.stacks.push(stack)
+  gallery.trashed = gallery.trashed?.filter((s) => s.id !== stackId)
+  return stack
+}
+
+/**
+ * Sets the number of previous versions that are kept for each stack of the
+ * given {@link Gallery} when it is replaced.
+ */
+export async function setGalleryVersionLimit(
+  client: AxiosInstance,
+  gallery: Gallery,
+  limit: number
+) {
+  await client.put(`/galleries/${gallery.id}/versioning`, { limit })
+  gallery.versionLimit = limit
+}
+
+/**
+ * Fetches the previous versions of a stack.
+ */
+export async function fetchStackVersions(
+  client: AxiosInstance,
+  galleryId: string,
+  stackId: string
+): Promise<StackVersion[]> {
+  const { data } = await client.get(
+    `/galleries/${galleryId}/stacks/${stackId}/versions`
+  )
+  return data
+}
+
+/**
+ * Reverts a stack of the given {@link Gallery} to one of its previous
+ * versions. The stack is processed again after it was reverted.
+ */
+export async function revertGalleryStack(
+  client: AxiosInstance,
+  gallery: Gallery,
+  stackId: string,
+  version: number
+) {
+  const { data } = await client.post(
+    `/galleries/${gallery.id}/stacks/${stackId}/revert`,
+    { version }
+  )
+  const stack = hydrateStack(data)
+  gallery.stacks = gallery.stacks.map((s) => (s.id === stackId ? stack : s))
+  return stack
+}
+
 /**
  * Returns the stack with the given stackId from the stacks of the gallery.
  */
@@ -315,3 +1178,32 @@ export function findOriginalStackImage(stack: Stack) {
     }
   }
 }
+
+/**
+ * Listens for processed, deleted and replaced stacks of the given gallery,
+ * using the Server-Sent Events route of the media server. Returns a function
+ * that closes the connection.
+ */
+export function listenToGallery(
+  client: AxiosInstance,
+  galleryId: string,
+  listener: (event: GalleryEvent) => void
+) {
+  const baseUrl = (client.defaults.baseURL || '').replace(/\/+$/, '')
+  const source = new EventSource(`${baseUrl}/galleries/${galleryId}/events`)
+
+  const names: GalleryEventName[] = [
+    'stackProcessed',
+    'stackDeleted',
+    'imageReplaced',
+  ]
+
+  for (const name of names) {
+    source.addEventListener(name, (e) => {
+      const data = JSON.parse((e as MessageEvent).data)
+      listener({ ...data, name, stack: hydrateStack(data.stack) })
+    })
+  }
+
+  return () => source.close()
+}
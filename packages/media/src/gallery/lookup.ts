@@ -38,3 +38,17 @@ export async function lookupGalleryStackByName(
     throw e
   }
 }
+
+/**
+ * Looks up the UUIDs of the stacks with the given tag within the given gallery.
+ */
+export async function lookupGalleryStacksByTag(
+  client: AxiosInstance,
+  galleryId: string,
+  tag: string
+) {
+  const { data } = await client.get(`/galleries/${galleryId}/stacks`, {
+    params: { tag },
+  })
+  return data.stackIds as string[]
+}
@@ -15,6 +15,7 @@ export function makeGalleryResponse(
     id: options?.id || exampleUUID,
     name: options?.name || 'foo',
     stacks: options?.stacks || [],
+    version: options?.version || 0,
   }
 }
 
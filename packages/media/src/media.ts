@@ -28,6 +28,17 @@ export interface StorageFile {
    * Tags.
    */
   tags: string[]
+
+  /**
+   * Hex-encoded SHA-256 checksum of the file contents.
+   */
+  checksum?: string
+
+  /**
+   * Path of the file contents if the file is stored on a content-addressed
+   * disk. `path` is then only the logical path of the file.
+   */
+  storagePath?: string
 }
 
 /**
@@ -45,6 +56,67 @@ export interface Image extends StorageFile {
   height: number
 }
 
+/**
+ * A storage video.
+ */
+export interface Video extends StorageFile {
+  /**
+   * Width in pixels. Zero until the video has been probed.
+   */
+  width: number
+
+  /**
+   * Height in pixels. Zero until the video has been probed.
+   */
+  height: number
+
+  /**
+   * Duration in nanoseconds. Zero until the video has been probed.
+   */
+  duration: number
+
+  /**
+   * Codec of the video stream (e.g. "h264").
+   */
+  codec?: string
+}
+
+/**
+ * Metadata of an audio file.
+ */
+export interface AudioMetadata {
+  /**
+   * Duration in nanoseconds.
+   */
+  duration: number
+
+  /**
+   * Bitrate in bits per second.
+   */
+  bitrate: number
+}
+
+/**
+ * Legal hold of a resource. Held resources cannot be deleted or replaced until
+ * the hold is lifted.
+ */
+export interface LegalHold {
+  /**
+   * Reason for the legal hold, e.g. a case number.
+   */
+  reason?: string
+
+  /**
+   * Time at which the legal hold was set.
+   */
+  since: string
+}
+
+/**
+ * A storage audio file.
+ */
+export interface Audio extends StorageFile, AudioMetadata {}
+
 /**
  * A storage document.
  */
@@ -0,0 +1,468 @@
+// Package projector runs projections with a configurable error handling policy
+// and keeps track of their projection status.
+package projector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/projection"
+)
+
+// Policy determines how a Projector reacts to failed projection jobs.
+type Policy int
+
+const (
+	// Skip reports the error of a failed job and continues with the next job.
+	// Skip is the default Policy.
+	Skip Policy = iota
+
+	// Retry retries a failed job until it succeeds or the maximum number of
+	// retries is reached. If the job still fails, the error is reported and the
+	// job is skipped.
+	Retry
+
+	// Halt stops the projection after the first failed job.
+	Halt
+)
+
+// String returns the name of the Policy.
+func (p Policy) String() string {
+	switch p {
+	case Skip:
+		return "skip"
+	case Retry:
+		return "retry"
+	case Halt:
+		return "halt"
+	default:
+		return fmt.Sprintf("<unknown policy %d>", int(p))
+	}
+}
+
+// errorBuffer is the number of projection errors that are buffered in the
+// error channel of a projection.
+const errorBuffer = 16
+
+// Printer is the interface for a logger.
+type Printer interface {
+	Print(v ...any)
+}
+
+// Option is a Projector option.
+type Option func(*Projector)
+
+// WithPolicy returns an Option that sets the error handling Policy of a
+// Projector.
+func WithPolicy(p Policy) Option {
+	return func(proj *Projector) {
+		proj.policy = p
+	}
+}
+
+// MaxRetries returns an Option that sets the maximum number of retries for a
+// failed job when using the Retry Policy. Default is 3.
+func MaxRetries(n int) Option {
+	return func(proj *Projector) {
+		proj.maxRetries = n
+	}
+}
+
+// RetryBackoff returns an Option that sets the delay between retries of a
+// failed job when using the Retry Policy. The delay is multiplied by the number
+// of the retry. Default is 100ms.
+func RetryBackoff(d time.Duration) Option {
+	return func(proj *Projector) {
+		proj.backoff = d
+	}
+}
+
+// WithLogger returns an Option that provides the Projector with a logger.
+// Failed jobs are logged to the logger.
+func WithLogger(logger Printer) Option {
+	return func(proj *Projector) {
+		proj.logger = logger
+	}
+}
+
+// Status is the projection status of a Projector.
+type Status struct {
+	// Policy is the error handling Policy of the Projector.
+	Policy string `json:"policy"`
+
+	// Running is true while the projection is running.
+	Running bool `json:"running"`
+
+	// Halted is true if the projection was stopped because of a failed job.
+	Halted bool `json:"halted"`
+
+	// Ready is true when the projection caught up with the past events. See
+	// Projector.Ready.
+	Ready bool `json:"ready"`
+
+	// LastEventID is the UUID of the last applied event.
+	LastEventID uuid.UUID `json:"lastEventId"`
+
+	// LastEventName is the name of the last applied event.
+	LastEventName string `json:"lastEventName"`
+
+	// LastEventTime is the time of the last applied event.
+	LastEventTime time.Time `json:"lastEventTime"`
+
+	// Errors is the number of failed jobs.
+	Errors int `json:"errors"`
+
+	// LastError is the error message of the last failed job.
+	LastError string `json:"lastError,omitempty"`
+
+	// Lag is the time between the publication of the last applied event and
+	// the time it was applied.
+	Lag time.Duration `json:"lag"`
+}
+
+// Healthy returns whether the projection is running and hasn't been halted.
+func (s Status) Healthy() bool {
+	return s.Running && !s.Halted
+}
+
+// Projector runs projections. Use New to create a Projector.
+type Projector struct {
+	policy     Policy
+	maxRetries int
+	backoff    time.Duration
+	logger     Printer
+	store      StateStore
+
+	mux    sync.RWMutex
+	status Status
+	state  *State
+
+	readyOnce sync.Once
+	ready     chan struct{}
+}
+
+// New returns a new Projector.
+func New(opts ...Option) *Projector {
+	proj := Projector{
+		maxRetries: 3,
+		backoff:    100 * time.Millisecond,
+		ready:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(&proj)
+	}
+	proj.status.Policy = proj.policy.String()
+	return &proj
+}
+
+// Status returns the current projection status.
+func (proj *Projector) Status() Status {
+	proj.mux.RLock()
+	defer proj.mux.RUnlock()
+	return proj.status
+}
+
+// Ready returns a channel that is closed when the projection caught up with
+// the past events, i.e. when the first run of the Projector applied all past
+// events without error. Servers can wait for the channel before they serve
+// requests that depend on the projection. If catching up fails, the channel
+// is not closed until the projection is caught up otherwise (see MarkReady).
+func (proj *Projector) Ready() <-chan struct{} {
+	return proj.ready
+}
+
+// MarkReady marks the projection as caught up with the past events, e.g.
+// after it has been rebuilt from the event store. See Ready.
+func (proj *Projector) MarkReady() {
+	proj.readyOnce.Do(func() {
+		proj.mux.Lock()
+		proj.status.Ready = true
+		proj.mux.Unlock()
+		close(proj.ready)
+	})
+}
+
+// Run subscribes to the provided Schedule, catches up with past events and
+// applies the projection jobs to target. Run returns after the projection
+// caught up with the past events; if catching up fails, the error is reported
+// through the returned channel and Ready is not closed until the projection is
+// caught up otherwise. Run returns a channel of asynchronous projection errors
+// that is closed when ctx is canceled or the projection is halted. Errors are
+// buffered and dropped if the buffer is full, so callers don't have to receive
+// from the channel; Status counts all errors.
+func (proj *Projector) Run(ctx context.Context, s projection.Schedule, target projection.Target[any]) (<-chan error, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	// The startup job that catches up with past events is applied before
+	// Subscribe returns, so it is always the first applied job.
+	var (
+		startup    sync.Once
+		catchUpErr error
+	)
+	errs, err := s.Subscribe(ctx, func(job projection.Job) error {
+		var first bool
+		startup.Do(func() { first = true })
+
+		err := proj.apply(job, target, first)
+		if first {
+			catchUpErr = err
+			return nil
+		}
+
+		return err
+	}, projection.Startup())
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("subscribe to projection schedule: %w", err)
+	}
+
+	proj.mux.Lock()
+	proj.status.Running = true
+	proj.status.Halted = false
+	proj.mux.Unlock()
+
+	out := make(chan error, errorBuffer)
+	go proj.handleErrors(ctx, cancel, catchUpErr, errs, out)
+
+	return out, nil
+}
+
+func (proj *Projector) handleErrors(ctx context.Context, cancel context.CancelFunc, catchUpErr error, errs <-chan error, out chan<- error) {
+	defer close(out)
+	defer cancel()
+	defer func() {
+		proj.mux.Lock()
+		defer proj.mux.Unlock()
+		proj.status.Running = false
+	}()
+
+	if catchUpErr != nil {
+		if halted := proj.report(fmt.Errorf("catch up: %w", catchUpErr), out); halted {
+			return
+		}
+	} else {
+		proj.MarkReady()
+	}
+
+	for err := range errs {
+		// Jobs that fail after the projection was stopped fail because ctx
+		// was canceled, so their errors are not reported.
+		if ctx.Err() != nil {
+			return
+		}
+		if halted := proj.report(err, out); halted {
+			return
+		}
+	}
+}
+
+// report records a failed job in the status and sends it to out without
+// blocking. report returns true if the projection was halted.
+func (proj *Projector) report(err error, out chan<- error) bool {
+	proj.mux.Lock()
+	proj.status.Errors++
+	proj.status.LastError = err.Error()
+	proj.mux.Unlock()
+
+	proj.log(fmt.Sprintf("Projection job failed (Policy=%s): %v", proj.policy, err))
+
+	// Callers that don't receive from the error channel must not stop the
+	// projection, so errors are dropped when the buffer is full. Status
+	// counts every error.
+	select {
+	case out <- err:
+	default:
+	}
+
+	if proj.policy != Halt {
+		return false
+	}
+
+	proj.mux.Lock()
+	proj.status.Halted = true
+	proj.mux.Unlock()
+	proj.log("Projection halted.")
+
+	return true
+}
+
+// apply applies a projection job to target. If the state of the projection is
+// persisted, only the catch-up job skips the events before the persisted
+// Progress: the event bus may deliver events of different names out of order,
+// so the Progress cannot filter the events of later jobs.
+func (proj *Projector) apply(job projection.Job, target projection.Target[any], catchUp bool) error {
+	proj.mux.RLock()
+	state := proj.state
+	proj.mux.RUnlock()
+
+	tr := &tracker{target: target, proj: proj, state: state, applied: make(map[uuid.UUID]bool)}
+	var retry bool
+	next := func() projection.Target[any] {
+		a := tr.next()
+		switch {
+		case state != nil && catchUp:
+			return &progressTracker{attempt: a}
+		case retry:
+			return &retryTracker{attempt: a}
+		default:
+			return a
+		}
+	}
+
+	apply := func(t projection.Target[any]) error {
+		// A failed job may return before the events of its query are drained,
+		// so every attempt is canceled when it returns and its remaining
+		// events are skipped by the tracker.
+		ctx, cancel := context.WithCancel(job)
+		defer cancel()
+		if err := job.Apply(ctx, t); err != nil {
+			tr.stop()
+			return err
+		}
+		if state != nil {
+			return state.Flush(job)
+		}
+		return nil
+	}
+
+	err := apply(next())
+	if err == nil || proj.policy != Retry {
+		return err
+	}
+
+	// Jobs cache the result of their query, including partial results of
+	// failed queries. Retries query the events after the last applied event
+	// instead, and the tracker skips the events that it has already applied,
+	// so only the events that were not applied are applied by a retry.
+	retry = true
+
+	for i := 1; i <= proj.maxRetries; i++ {
+		proj.log(fmt.Sprintf("Retrying projection job (Retry=%d/%d): %v", i, proj.maxRetries, err))
+
+		timer := time.NewTimer(time.Duration(i) * proj.backoff)
+		select {
+		case <-job.Done():
+			timer.Stop()
+			return job.Err()
+		case <-timer.C:
+		}
+
+		if err = apply(next()); err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("projection job failed after %d retries: %w", proj.maxRetries, err)
+}
+
+func (proj *Projector) applied(evt event.Event) {
+	proj.mux.Lock()
+	defer proj.mux.Unlock()
+	proj.status.LastEventID = evt.ID()
+	proj.status.LastEventName = evt.Name()
+	proj.status.LastEventTime = evt.Time()
+	proj.status.Lag = time.Since(evt.Time())
+}
+
+func (proj *Projector) log(v ...any) {
+	if proj.logger != nil {
+		proj.logger.Print(v...)
+	}
+}
+
+// tracker wraps the target of a projection and records the applied events in
+// the status of the Projector and in the persisted State of the projection.
+// Events that were already applied by the tracker are skipped, so that a
+// retried job doesn't apply events twice. Every attempt to apply a job uses its
+// own attempt of the tracker; the events of stopped attempts are skipped.
+type tracker struct {
+	target projection.Target[any]
+	proj   *Projector
+	state  *State
+
+	mux     sync.Mutex
+	applied map[uuid.UUID]bool
+	last    time.Time
+	current int
+}
+
+// next returns the target of the next attempt to apply the job.
+func (t *tracker) next() *attempt {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return &attempt{tracker: t, n: t.current}
+}
+
+// stop stops the current attempt. Events that are applied concurrently finish
+// before stop returns; later events of the attempt are skipped.
+func (t *tracker) stop() {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.current++
+}
+
+func (t *tracker) apply(n int, evt event.Event) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if n != t.current || t.applied[evt.ID()] {
+		return
+	}
+	t.applied[evt.ID()] = true
+	if evt.Time().After(t.last) {
+		t.last = evt.Time()
+	}
+
+	t.target.ApplyEvent(evt)
+	t.proj.applied(evt)
+	if t.state != nil {
+		t.state.advance(evt)
+	}
+}
+
+// attempt is the target of a single attempt to apply a job.
+type attempt struct {
+	*tracker
+	n int
+}
+
+func (a *attempt) ApplyEvent(evt event.Event) {
+	a.tracker.apply(a.n, evt)
+}
+
+// progressTracker is a tracker that passes the Progress of the persisted State
+// of the projection to the projection job, so that the job only applies the
+// events after the Progress.
+type progressTracker struct {
+	*attempt
+}
+
+func (t *progressTracker) Progress() (time.Time, []uuid.UUID) {
+	return t.state.Progress()
+}
+
+// SetProgress does nothing because the Progress is advanced by ApplyEvent.
+func (t *progressTracker) SetProgress(time.Time, ...uuid.UUID) {}
+
+// retryTracker is a tracker for retried jobs. It passes the time of the last
+// applied event as the progress of the projection to the job, so that the job
+// queries the events again, starting at the last applied event.
+type retryTracker struct {
+	*attempt
+}
+
+func (t *retryTracker) Progress() (time.Time, []uuid.UUID) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if t.last.IsZero() {
+		return time.Unix(0, 0), nil
+	}
+	return t.last, nil
+}
+
+// SetProgress does nothing because the progress is advanced by ApplyEvent.
+func (t *retryTracker) SetProgress(time.Time, ...uuid.UUID) {}
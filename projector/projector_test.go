@@ -0,0 +1,401 @@
+package projector_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/goes/projection"
+	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/projector"
+)
+
+func TestProjector_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	evt := event.New[any]("foo", struct{}{}).Any()
+	store := eventstore.New(evt)
+	target := newTarget()
+
+	proj := projector.New()
+	if _, err := proj.Run(ctx, schedule.Continuously(eventbus.New(), store, []string{"foo"}), target); err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+
+	target.await(t, 1)
+
+	var status projector.Status
+	timeout := time.After(time.Second)
+	for status = proj.Status(); status.LastEventID == uuid.Nil; status = proj.Status() {
+		select {
+		case <-timeout:
+			t.Fatalf("status should be updated after the event was applied")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	if !status.Healthy() {
+		t.Fatalf("projection should be healthy; got %v", status)
+	}
+
+	if status.LastEventID != evt.ID() {
+		t.Fatalf("LastEventID should be %s; got %s", evt.ID(), status.LastEventID)
+	}
+
+	if status.LastEventName != "foo" {
+		t.Fatalf("LastEventName should be %q; got %q", "foo", status.LastEventName)
+	}
+
+	if status.Errors != 0 {
+		t.Fatalf("Errors should be 0; got %d", status.Errors)
+	}
+}
+
+func TestProjector_Run_skip(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := &failingStore{Store: eventstore.New(event.New[any]("foo", struct{}{}).Any()), failures: 1}
+
+	proj := projector.New()
+	errs, err := proj.Run(ctx, schedule.Continuously(eventbus.New(), store, []string{"foo"}), newTarget())
+	if err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+
+	awaitError(t, errs)
+
+	status := proj.Status()
+	if !status.Healthy() {
+		t.Fatalf("projection should be healthy; got %v", status)
+	}
+
+	if status.Errors != 1 {
+		t.Fatalf("Errors should be 1; got %d", status.Errors)
+	}
+
+	if status.LastError == "" {
+		t.Fatalf("LastError should be set")
+	}
+}
+
+func TestProjector_Run_retry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := &failingStore{Store: eventstore.New(event.New[any]("foo", struct{}{}).Any()), failures: 2}
+	target := newTarget()
+
+	proj := projector.New(
+		projector.WithPolicy(projector.Retry),
+		projector.MaxRetries(3),
+		projector.RetryBackoff(time.Millisecond),
+	)
+	errs, err := proj.Run(ctx, schedule.Continuously(eventbus.New(), store, []string{"foo"}), target)
+	if err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+
+	target.await(t, 1)
+
+	select {
+	case err := <-errs:
+		t.Fatalf("projection should not fail; got %q", err)
+	default:
+	}
+
+	if status := proj.Status(); status.Errors != 0 {
+		t.Fatalf("Errors should be 0; got %d", status.Errors)
+	}
+}
+
+func TestProjector_Run_skipUnreceived(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := newErrSchedule()
+	proj := projector.New()
+	if _, err := proj.Run(ctx, s, newTarget()); err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+
+	// Nobody receives from the error channel, but the Projector keeps
+	// receiving errors from the schedule.
+	for i := 0; i < 100; i++ {
+		select {
+		case <-time.After(time.Second):
+			t.Fatalf("Projector should keep receiving errors; stuck after %d errors", i)
+		case s.errs <- errors.New("mock error"):
+		}
+	}
+
+	timeout := time.After(time.Second)
+	for proj.Status().Errors != 100 {
+		select {
+		case <-timeout:
+			t.Fatalf("Errors should be %d; got %d", 100, proj.Status().Errors)
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestProjector_Run_retryPartial(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := &partialStore{Store: eventstore.New(
+		event.New[any]("foo", struct{}{}).Any(),
+		event.New[any]("foo", struct{}{}).Any(),
+	), failures: 1}
+	target := newTarget()
+
+	proj := projector.New(
+		projector.WithPolicy(projector.Retry),
+		projector.RetryBackoff(time.Millisecond),
+	)
+	if _, err := proj.Run(ctx, schedule.Continuously(eventbus.New(), store, []string{"foo"}), target); err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+
+	target.await(t, 2)
+
+	if n := target.applications(); n != 2 {
+		t.Fatalf("retried job should only apply the events that were not applied; %d events were applied", n)
+	}
+}
+
+func TestProjector_Run_halt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := &failingStore{Store: eventstore.New(event.New[any]("foo", struct{}{}).Any()), failures: 1}
+
+	proj := projector.New(projector.WithPolicy(projector.Halt))
+	errs, err := proj.Run(ctx, schedule.Continuously(eventbus.New(), store, []string{"foo"}), newTarget())
+	if err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+
+	awaitError(t, errs)
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("error channel should be closed after the projection was halted")
+	case _, ok := <-errs:
+		if ok {
+			t.Fatalf("error channel should be closed after the projection was halted")
+		}
+	}
+
+	status := proj.Status()
+	if !status.Halted {
+		t.Fatalf("projection should be halted")
+	}
+
+	if status.Healthy() {
+		t.Fatalf("halted projection should not be healthy")
+	}
+}
+
+func TestProjector_Ready(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := eventstore.New(event.New[any]("foo", struct{}{}).Any())
+	target := newTarget()
+
+	proj := projector.New()
+	if _, err := proj.Run(ctx, schedule.Continuously(eventbus.New(), store, []string{"foo"}), target); err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+
+	if target.count() != 1 {
+		t.Fatalf("Run should return after catching up; %d events were applied", target.count())
+	}
+
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("projection should be ready after catching up")
+	case <-proj.Ready():
+	}
+
+	if target.count() != 1 {
+		t.Fatalf("past events should be applied when the projection is ready; %d were applied", target.count())
+	}
+
+	if !proj.Status().Ready {
+		t.Fatalf("Status should report the projection as ready")
+	}
+}
+
+func TestProjector_Ready_catchUpFailed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := &failingStore{Store: eventstore.New(event.New[any]("foo", struct{}{}).Any()), failures: 1}
+
+	proj := projector.New()
+	errs, err := proj.Run(ctx, schedule.Continuously(eventbus.New(), store, []string{"foo"}), newTarget())
+	if err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+
+	awaitError(t, errs)
+
+	select {
+	case <-proj.Ready():
+		t.Fatalf("projection should not be ready if catching up failed")
+	default:
+	}
+
+	proj.MarkReady()
+
+	select {
+	case <-proj.Ready():
+	default:
+		t.Fatalf("projection should be ready after MarkReady")
+	}
+}
+
+func awaitError(t *testing.T, errs <-chan error) {
+	select {
+	case <-time.After(time.Second):
+		t.Fatalf("projection should fail")
+	case err := <-errs:
+		if err == nil {
+			t.Fatalf("projection error should not be nil")
+		}
+	}
+}
+
+type target struct {
+	mux     sync.Mutex
+	applied map[uuid.UUID]bool
+	total   int
+}
+
+func newTarget() *target {
+	return &target{applied: make(map[uuid.UUID]bool)}
+}
+
+func (t *target) ApplyEvent(evt event.Event) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.applied[evt.ID()] = true
+	t.total++
+}
+
+func (t *target) applications() int {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.total
+}
+
+func (t *target) count() int {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return len(t.applied)
+}
+
+func (t *target) await(tt *testing.T, n int) {
+	timeout := time.After(time.Second)
+	for {
+		t.mux.Lock()
+		count := len(t.applied)
+		t.mux.Unlock()
+		if count >= n {
+			return
+		}
+
+		select {
+		case <-timeout:
+			tt.Fatalf("%d events should have been applied; got %d", n, count)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+// failingStore fails the first queries.
+type failingStore struct {
+	event.Store
+
+	mux      sync.Mutex
+	failures int
+}
+
+func (s *failingStore) Query(ctx context.Context, q event.Query) (<-chan event.Event, <-chan error, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return nil, nil, errors.New("mock error")
+	}
+	return s.Store.Query(ctx, q)
+}
+
+// partialStore returns only the first event of the first queries, followed by
+// an error.
+type partialStore struct {
+	event.Store
+
+	mux      sync.Mutex
+	failures int
+}
+
+func (s *partialStore) Query(ctx context.Context, q event.Query) (<-chan event.Event, <-chan error, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.failures == 0 {
+		return s.Store.Query(ctx, q)
+	}
+	s.failures--
+
+	events, errs, err := s.Store.Query(ctx, q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := make(chan event.Event, 1)
+	outErrs := make(chan error, 1)
+	for evt := range events {
+		out <- evt
+		break
+	}
+	go func() {
+		for range events {
+		}
+		for range errs {
+		}
+	}()
+	outErrs <- errors.New("mock error")
+	close(out)
+	close(outErrs)
+
+	return out, outErrs, nil
+}
+
+// errSchedule is a schedule that pushes the errors that are sent to errs into
+// the error channel of its subscriber.
+type errSchedule struct {
+	errs chan error
+}
+
+func newErrSchedule() *errSchedule {
+	return &errSchedule{errs: make(chan error)}
+}
+
+func (s *errSchedule) Subscribe(context.Context, func(projection.Job) error, ...projection.SubscribeOption) (<-chan error, error) {
+	return s.errs, nil
+}
+
+func (s *errSchedule) Trigger(context.Context, ...projection.TriggerOption) error {
+	return nil
+}
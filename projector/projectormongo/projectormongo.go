@@ -0,0 +1,130 @@
+// Package projectormongo provides a MongoDB implementation of the
+// projector.StateStore.
+package projectormongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/projector"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type stateStore struct {
+	col *mongo.Collection
+}
+
+// stateDocument is either an entry or the progress of a projection.
+type stateDocument struct {
+	ID         string    `bson:"_id"`
+	Projection string    `bson:"projection"`
+	Progress   bool      `bson:"progress"`
+	Key        string    `bson:"key,omitempty"`
+	Value      string    `bson:"value,omitempty"`
+	Time       time.Time `bson:"time,omitempty"`
+	Events     []string  `bson:"events,omitempty"`
+}
+
+// StateStore returns a projector.StateStore that persists the entries and the
+// progress of projections as documents in the given MongoDB collection:
+//
+//	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+//	store := projectormongo.StateStore(client.Database("cms").Collection("projections"))
+//	lookup := gallery.NewLookup(projector.Persist(store))
+func StateStore(col *mongo.Collection) projector.StateStore {
+	return &stateStore{col: col}
+}
+
+func (s *stateStore) Load(ctx context.Context, projection string) (map[string]string, projector.Progress, error) {
+	cur, err := s.col.Find(ctx, bson.M{"projection": projection})
+	if err != nil {
+		return nil, projector.Progress{}, fmt.Errorf("mongo: find state of %q: %w", projection, err)
+	}
+
+	var docs []stateDocument
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, projector.Progress{}, fmt.Errorf("mongo: decode state of %q: %w", projection, err)
+	}
+
+	entries := make(map[string]string, len(docs))
+	var progress projector.Progress
+	for _, doc := range docs {
+		if !doc.Progress {
+			entries[doc.Key] = doc.Value
+			continue
+		}
+
+		progress.Time = doc.Time
+		for _, raw := range doc.Events {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				return nil, progress, fmt.Errorf("mongo: parse UUID %q of progress of %q: %w", raw, projection, err)
+			}
+			progress.Events = append(progress.Events, id)
+		}
+	}
+
+	return entries, progress, nil
+}
+
+func (s *stateStore) Update(ctx context.Context, projection string, update projector.StateUpdate) error {
+	var models []mongo.WriteModel
+
+	if update.Reset {
+		models = append(models, mongo.NewDeleteManyModel().SetFilter(bson.M{
+			"projection": projection,
+			"progress":   false,
+		}))
+	}
+
+	for key, value := range update.Set {
+		doc := stateDocument{
+			ID:         entryID(projection, key),
+			Projection: projection,
+			Key:        key,
+			Value:      value,
+		}
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": doc.ID}).
+			SetReplacement(doc).
+			SetUpsert(true))
+	}
+
+	for _, key := range update.Delete {
+		models = append(models, mongo.NewDeleteOneModel().SetFilter(bson.M{"_id": entryID(projection, key)}))
+	}
+
+	progress := stateDocument{
+		ID:         progressID(projection),
+		Projection: projection,
+		Progress:   true,
+		Time:       update.Progress.Time,
+		Events:     make([]string, len(update.Progress.Events)),
+	}
+	for i, id := range update.Progress.Events {
+		progress.Events[i] = id.String()
+	}
+	models = append(models, mongo.NewReplaceOneModel().
+		SetFilter(bson.M{"_id": progress.ID}).
+		SetReplacement(progress).
+		SetUpsert(true))
+
+	// The write is ordered, so that the progress is written last.
+	if _, err := s.col.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(true)); err != nil {
+		return fmt.Errorf("mongo: update state of %q: %w", projection, err)
+	}
+
+	return nil
+}
+
+func entryID(projection, key string) string {
+	return projection + "/entry/" + key
+}
+
+func progressID(projection string) string {
+	return projection + "/progress"
+}
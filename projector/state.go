@@ -0,0 +1,288 @@
+package projector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+)
+
+// Progress is the position of a projection in the event stream: the time of
+// the last applied events and the UUIDs of the events with that time.
+type Progress struct {
+	Time   time.Time   `json:"time"`
+	Events []uuid.UUID `json:"events"`
+}
+
+// StateStore persists the state of projections, so that a projection doesn't
+// have to replay all past events on startup. The state of a projection is a
+// set of key-value entries and the Progress of the projection. Updates are
+// incremental: only the changed entries are written.
+type StateStore interface {
+	// Load returns the entries and the Progress of the given projection. A
+	// projection without persisted state has no entries and a zero Progress.
+	Load(ctx context.Context, projection string) (map[string]string, Progress, error)
+
+	// Update applies a StateUpdate to the state of the given projection. The
+	// Progress must be written after the entries, so that a partially applied
+	// update is repaired by replaying the events after the old Progress.
+	Update(ctx context.Context, projection string, update StateUpdate) error
+}
+
+// StateUpdate is an incremental update of the state of a projection.
+type StateUpdate struct {
+	// Reset removes all entries before the update is applied.
+	Reset bool
+
+	// Set are the added or changed entries.
+	Set map[string]string
+
+	// Delete are the keys of the removed entries.
+	Delete []string
+
+	// Progress is the Progress of the projection after the update.
+	Progress Progress
+}
+
+type memoryStateStore struct {
+	mux    sync.RWMutex
+	states map[string]*memoryState
+}
+
+type memoryState struct {
+	entries  map[string]string
+	progress Progress
+}
+
+// MemoryStateStore returns an in-memory StateStore.
+func MemoryStateStore() StateStore {
+	return &memoryStateStore{states: make(map[string]*memoryState)}
+}
+
+func (s *memoryStateStore) Load(_ context.Context, projection string) (map[string]string, Progress, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	state, ok := s.states[projection]
+	if !ok {
+		return make(map[string]string), Progress{}, nil
+	}
+
+	entries := make(map[string]string, len(state.entries))
+	for key, value := range state.entries {
+		entries[key] = value
+	}
+
+	return entries, copyProgress(state.progress), nil
+}
+
+func (s *memoryStateStore) Update(_ context.Context, projection string, update StateUpdate) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	state, ok := s.states[projection]
+	if !ok || update.Reset {
+		state = &memoryState{entries: make(map[string]string)}
+		s.states[projection] = state
+	}
+
+	for key, value := range update.Set {
+		state.entries[key] = value
+	}
+
+	for _, key := range update.Delete {
+		delete(state.entries, key)
+	}
+
+	state.progress = copyProgress(update.Progress)
+
+	return nil
+}
+
+// Persist returns an Option that persists the state of the projection in the
+// given StateStore. Only projections that support persistence use the
+// StateStore (see Projector.State).
+func Persist(store StateStore) Option {
+	return func(proj *Projector) {
+		proj.store = store
+	}
+}
+
+// State returns the persisted State of the projection with the given name, or
+// nil if the Projector has no StateStore (see Persist). When the Projector
+// runs a projection, the catch-up job only applies the events after the
+// Progress of the State, every applied event advances the Progress, and the
+// changes of the State are flushed to the StateStore after every job. State
+// must be called before Run.
+func (proj *Projector) State(name string) *State {
+	if proj.store == nil {
+		return nil
+	}
+
+	proj.mux.Lock()
+	defer proj.mux.Unlock()
+
+	if proj.state == nil {
+		proj.state = newState(proj.store, name)
+	}
+
+	return proj.state
+}
+
+// State is the persisted state of a projection. The projection records the
+// changes of its state using Set and Delete; the changes are written to the
+// StateStore together with the Progress of the projection by Flush. State
+// implements projection.ProgressAware.
+type State struct {
+	store StateStore
+	name  string
+
+	mux      sync.Mutex
+	progress Progress
+	dirty    bool
+	reset    bool
+	set      map[string]string
+	deleted  map[string]struct{}
+}
+
+func newState(store StateStore, name string) *State {
+	return &State{
+		store:   store,
+		name:    name,
+		set:     make(map[string]string),
+		deleted: make(map[string]struct{}),
+	}
+}
+
+// Name returns the name of the projection.
+func (s *State) Name() string {
+	return s.name
+}
+
+// Load loads the entries and the Progress of the projection from the
+// StateStore. Changes that haven't been flushed are discarded.
+func (s *State) Load(ctx context.Context) (map[string]string, error) {
+	entries, progress, err := s.store.Load(ctx, s.name)
+	if err != nil {
+		return nil, fmt.Errorf("load state of %q: %w", s.name, err)
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.progress = progress
+	s.clear()
+
+	return entries, nil
+}
+
+// Set records an added or changed entry.
+func (s *State) Set(key, value string) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	delete(s.deleted, key)
+	s.set[key] = value
+	s.dirty = true
+}
+
+// Delete records a removed entry.
+func (s *State) Delete(key string) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	delete(s.set, key)
+	if !s.reset {
+		s.deleted[key] = struct{}{}
+	}
+	s.dirty = true
+}
+
+// Reset replaces all entries and the Progress of the projection, e.g. after
+// the projection has been rebuilt. The replacement is written by the next
+// Flush.
+func (s *State) Reset(entries map[string]string, progress Progress) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.clear()
+	for key, value := range entries {
+		s.set[key] = value
+	}
+	s.reset = true
+	s.dirty = true
+	s.progress = copyProgress(progress)
+}
+
+// Progress returns the time and the UUIDs of the last applied events.
+func (s *State) Progress() (time.Time, []uuid.UUID) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.progress.Time, append([]uuid.UUID(nil), s.progress.Events...)
+}
+
+// SetProgress sets the time and the UUIDs of the last applied events.
+func (s *State) SetProgress(t time.Time, ids ...uuid.UUID) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.progress = Progress{Time: t, Events: append([]uuid.UUID(nil), ids...)}
+	s.dirty = true
+}
+
+// advance advances the Progress to the given event if the event isn't older
+// than the Progress.
+func (s *State) advance(evt event.Event) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	switch t := evt.Time(); {
+	case t.After(s.progress.Time):
+		s.progress = Progress{Time: t, Events: []uuid.UUID{evt.ID()}}
+	case t.Equal(s.progress.Time):
+		s.progress.Events = append(s.progress.Events, evt.ID())
+	default:
+		return
+	}
+	s.dirty = true
+}
+
+// Flush writes the recorded changes and the Progress to the StateStore. If
+// Flush fails, the changes are kept and written by the next Flush. Changes
+// that are recorded during Flush block until Flush returns.
+func (s *State) Flush(ctx context.Context) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if !s.dirty {
+		return nil
+	}
+
+	update := StateUpdate{
+		Reset:    s.reset,
+		Set:      s.set,
+		Delete:   make([]string, 0, len(s.deleted)),
+		Progress: copyProgress(s.progress),
+	}
+	for key := range s.deleted {
+		update.Delete = append(update.Delete, key)
+	}
+
+	if err := s.store.Update(ctx, s.name, update); err != nil {
+		return fmt.Errorf("update state of %q: %w", s.name, err)
+	}
+
+	s.clear()
+
+	return nil
+}
+
+func (s *State) clear() {
+	s.dirty = false
+	s.reset = false
+	s.set = make(map[string]string)
+	s.deleted = make(map[string]struct{})
+}
+
+func copyProgress(p Progress) Progress {
+	return Progress{Time: p.Time, Events: append([]uuid.UUID(nil), p.Events...)}
+}
@@ -0,0 +1,122 @@
+package projector_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/projector"
+)
+
+func TestMemoryStateStore(t *testing.T) {
+	ctx := context.Background()
+	store := projector.MemoryStateStore()
+
+	entries, progress, err := store.Load(ctx, "foo")
+	if err != nil {
+		t.Fatalf("Load failed with %q", err)
+	}
+	if len(entries) != 0 || !progress.Time.IsZero() {
+		t.Fatalf("unknown projection should have no state; got %v %v", entries, progress)
+	}
+
+	first := projector.Progress{Time: time.Now(), Events: []uuid.UUID{uuid.New()}}
+	if err := store.Update(ctx, "foo", projector.StateUpdate{
+		Set:      map[string]string{"a": "1", "b": "2"},
+		Progress: first,
+	}); err != nil {
+		t.Fatalf("Update failed with %q", err)
+	}
+
+	second := projector.Progress{Time: first.Time.Add(time.Second), Events: []uuid.UUID{uuid.New()}}
+	if err := store.Update(ctx, "foo", projector.StateUpdate{
+		Set:      map[string]string{"c": "3"},
+		Delete:   []string{"a"},
+		Progress: second,
+	}); err != nil {
+		t.Fatalf("Update failed with %q", err)
+	}
+
+	entries, progress, _ = store.Load(ctx, "foo")
+	if len(entries) != 2 || entries["b"] != "2" || entries["c"] != "3" {
+		t.Fatalf("Load should return the updated entries; got %v", entries)
+	}
+	if !progress.Time.Equal(second.Time) || len(progress.Events) != 1 || progress.Events[0] != second.Events[0] {
+		t.Fatalf("Load should return the last Progress %v; got %v", second, progress)
+	}
+
+	if err := store.Update(ctx, "foo", projector.StateUpdate{
+		Reset:    true,
+		Set:      map[string]string{"d": "4"},
+		Progress: second,
+	}); err != nil {
+		t.Fatalf("Update failed with %q", err)
+	}
+
+	if entries, _, _ = store.Load(ctx, "foo"); len(entries) != 1 || entries["d"] != "4" {
+		t.Fatalf("reset should replace all entries; got %v", entries)
+	}
+
+	if entries, _, _ = store.Load(ctx, "bar"); len(entries) != 0 {
+		t.Fatalf("states of projections should be separate; got %v", entries)
+	}
+}
+
+func TestProjector_Run_persist(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	now := time.Now()
+	old := event.New[any]("foo", struct{}{}, event.Time(now.Add(-time.Minute))).Any()
+	estore := eventstore.New(old)
+	states := projector.MemoryStateStore()
+
+	proj := projector.New(projector.Persist(states))
+	state := proj.State("foo")
+	if state == nil {
+		t.Fatalf("State should not be nil if the Projector persists its state")
+	}
+	state.Set("key", "value")
+
+	if _, err := proj.Run(ctx, schedule.Continuously(eventbus.New(), estore, []string{"foo"}), newTarget()); err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+	<-proj.Ready()
+
+	entries, progress, _ := states.Load(ctx, "foo")
+	if entries["key"] != "value" {
+		t.Fatalf("recorded entries should be flushed after the job; got %v", entries)
+	}
+	if !progress.Time.Equal(old.Time()) || len(progress.Events) != 1 || progress.Events[0] != old.ID() {
+		t.Fatalf("Progress should be the applied event; got %v", progress)
+	}
+
+	recent := event.New[any]("foo", struct{}{}, event.Time(now)).Any()
+	if err := estore.Insert(ctx, recent); err != nil {
+		t.Fatalf("insert event: %v", err)
+	}
+
+	restarted := projector.New(projector.Persist(states))
+	if _, err := restarted.State("foo").Load(ctx); err != nil {
+		t.Fatalf("Load failed with %q", err)
+	}
+
+	target := newTarget()
+	if _, err := restarted.Run(ctx, schedule.Continuously(eventbus.New(), estore, []string{"foo"}), target); err != nil {
+		t.Fatalf("Run failed with %q", err)
+	}
+	<-restarted.Ready()
+
+	if target.count() != 1 || !target.applied[recent.ID()] {
+		t.Fatalf("only the event after the persisted Progress should be applied; got %v", target.applied)
+	}
+
+	if projector.New().State("foo") != nil {
+		t.Fatalf("State should be nil if the Projector doesn't persist its state")
+	}
+}
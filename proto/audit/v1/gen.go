@@ -0,0 +1,4 @@
+package audit
+
+//go:generate mkdir -p ../../gen/audit/v1
+//go:generate protoc -I ../../ -I. --go_out=module=github.com/modernice/nice-cms/proto/gen/audit/v1:../../gen/audit/v1 --go-grpc_out=module=github.com/modernice/nice-cms/proto/gen/audit/v1:../../gen/audit/v1 audit.proto
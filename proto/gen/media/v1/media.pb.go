@@ -32,6 +32,10 @@ type StorageFile struct {
 	Path     string   `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
 	Filesize int64    `protobuf:"varint,4,opt,name=filesize,proto3" json:"filesize,omitempty"`
 	Tags     []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Hex-encoded SHA-256 checksum of the file contents.
+	Checksum string `protobuf:"bytes,6,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	// Path of the file contents on content-addressed disks.
+	StoragePath string `protobuf:"bytes,7,opt,name=storage_path,json=storagePath,proto3" json:"storage_path,omitempty"`
 }
 
 func (x *StorageFile) Reset() {
@@ -101,6 +105,20 @@ func (x *StorageFile) GetTags() []string {
 	return nil
 }
 
+func (x *StorageFile) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *StorageFile) GetStoragePath() string {
+	if x != nil {
+		return x.StoragePath
+	}
+	return ""
+}
+
 type StorageImage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -211,6 +229,86 @@ func (x *StorageDocument) GetFile() *StorageFile {
 	return nil
 }
 
+type StorageVideo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	File   *StorageFile `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	Width  int64        `protobuf:"varint,2,opt,name=width,proto3" json:"width,omitempty"`
+	Height int64        `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	// Duration in milliseconds.
+	Duration int64  `protobuf:"varint,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	Codec    string `protobuf:"bytes,5,opt,name=codec,proto3" json:"codec,omitempty"`
+}
+
+func (x *StorageVideo) Reset() {
+	*x = StorageVideo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StorageVideo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StorageVideo) ProtoMessage() {}
+
+func (x *StorageVideo) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StorageVideo.ProtoReflect.Descriptor instead.
+func (*StorageVideo) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StorageVideo) GetFile() *StorageFile {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+func (x *StorageVideo) GetWidth() int64 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *StorageVideo) GetHeight() int64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *StorageVideo) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *StorageVideo) GetCodec() string {
+	if x != nil {
+		return x.Codec
+	}
+	return ""
+}
+
 type UploadDocumentReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -225,7 +323,7 @@ type UploadDocumentReq struct {
 func (x *UploadDocumentReq) Reset() {
 	*x = UploadDocumentReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_media_proto_msgTypes[3]
+		mi := &file_media_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -238,7 +336,7 @@ func (x *UploadDocumentReq) String() string {
 func (*UploadDocumentReq) ProtoMessage() {}
 
 func (x *UploadDocumentReq) ProtoReflect() protoreflect.Message {
-	mi := &file_media_proto_msgTypes[3]
+	mi := &file_media_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -251,7 +349,7 @@ func (x *UploadDocumentReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadDocumentReq.ProtoReflect.Descriptor instead.
 func (*UploadDocumentReq) Descriptor() ([]byte, []int) {
-	return file_media_proto_rawDescGZIP(), []int{3}
+	return file_media_proto_rawDescGZIP(), []int{4}
 }
 
 func (m *UploadDocumentReq) GetUploadData() isUploadDocumentReq_UploadData {
@@ -305,7 +403,7 @@ type ReplaceDocumentReq struct {
 func (x *ReplaceDocumentReq) Reset() {
 	*x = ReplaceDocumentReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_media_proto_msgTypes[4]
+		mi := &file_media_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -318,7 +416,7 @@ func (x *ReplaceDocumentReq) String() string {
 func (*ReplaceDocumentReq) ProtoMessage() {}
 
 func (x *ReplaceDocumentReq) ProtoReflect() protoreflect.Message {
-	mi := &file_media_proto_msgTypes[4]
+	mi := &file_media_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -331,7 +429,7 @@ func (x *ReplaceDocumentReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReplaceDocumentReq.ProtoReflect.Descriptor instead.
 func (*ReplaceDocumentReq) Descriptor() ([]byte, []int) {
-	return file_media_proto_rawDescGZIP(), []int{4}
+	return file_media_proto_rawDescGZIP(), []int{5}
 }
 
 func (m *ReplaceDocumentReq) GetReplaceData() isReplaceDocumentReq_ReplaceData {
@@ -376,15 +474,18 @@ type Shelf struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id        *v1.UUID         `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name      string           `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Documents []*ShelfDocument `protobuf:"bytes,3,rep,name=documents,proto3" json:"documents,omitempty"`
+	Id        *v1.UUID           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string             `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Documents []*ShelfDocument   `protobuf:"bytes,3,rep,name=documents,proto3" json:"documents,omitempty"`
+	Trashed   []*TrashedDocument `protobuf:"bytes,4,rep,name=trashed,proto3" json:"trashed,omitempty"`
+	LegalHold *LegalHold         `protobuf:"bytes,5,opt,name=legal_hold,json=legalHold,proto3" json:"legal_hold,omitempty"`
+	Version   int64              `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
 }
 
 func (x *Shelf) Reset() {
 	*x = Shelf{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_media_proto_msgTypes[5]
+		mi := &file_media_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -397,7 +498,7 @@ func (x *Shelf) String() string {
 func (*Shelf) ProtoMessage() {}
 
 func (x *Shelf) ProtoReflect() protoreflect.Message {
-	mi := &file_media_proto_msgTypes[5]
+	mi := &file_media_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -410,7 +511,7 @@ func (x *Shelf) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Shelf.ProtoReflect.Descriptor instead.
 func (*Shelf) Descriptor() ([]byte, []int) {
-	return file_media_proto_rawDescGZIP(), []int{5}
+	return file_media_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *Shelf) GetId() *v1.UUID {
@@ -434,80 +535,44 @@ func (x *Shelf) GetDocuments() []*ShelfDocument {
 	return nil
 }
 
-type ShelfDocument struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Document   *StorageDocument `protobuf:"bytes,1,opt,name=document,proto3" json:"document,omitempty"`
-	Id         *v1.UUID         `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
-	UniqueName string           `protobuf:"bytes,3,opt,name=uniqueName,proto3" json:"uniqueName,omitempty"`
-}
-
-func (x *ShelfDocument) Reset() {
-	*x = ShelfDocument{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_media_proto_msgTypes[6]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
-}
-
-func (x *ShelfDocument) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*ShelfDocument) ProtoMessage() {}
-
-func (x *ShelfDocument) ProtoReflect() protoreflect.Message {
-	mi := &file_media_proto_msgTypes[6]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use ShelfDocument.ProtoReflect.Descriptor instead.
-func (*ShelfDocument) Descriptor() ([]byte, []int) {
-	return file_media_proto_rawDescGZIP(), []int{6}
-}
-
-func (x *ShelfDocument) GetDocument() *StorageDocument {
+func (x *Shelf) GetTrashed() []*TrashedDocument {
 	if x != nil {
-		return x.Document
+		return x.Trashed
 	}
 	return nil
 }
 
-func (x *ShelfDocument) GetId() *v1.UUID {
+func (x *Shelf) GetLegalHold() *LegalHold {
 	if x != nil {
-		return x.Id
+		return x.LegalHold
 	}
 	return nil
 }
 
-func (x *ShelfDocument) GetUniqueName() string {
+func (x *Shelf) GetVersion() int64 {
 	if x != nil {
-		return x.UniqueName
+		return x.Version
 	}
-	return ""
+	return 0
 }
 
-type LookupGalleryStackByNameReq struct {
+type ListDocumentsReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GalleryId *v1.UUID `protobuf:"bytes,1,opt,name=galleryId,proto3" json:"galleryId,omitempty"`
-	Name      string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ShelfId *v1.UUID `protobuf:"bytes,1,opt,name=shelf_id,json=shelfId,proto3" json:"shelf_id,omitempty"`
+	Offset  int64    `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// 0 means no limit.
+	Limit  int64    `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Tags   []string `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	Search string   `protobuf:"bytes,5,opt,name=search,proto3" json:"search,omitempty"`
+	// Comma-separated sort fields; a "-" prefix sorts in descending order.
+	Sort string `protobuf:"bytes,6,opt,name=sort,proto3" json:"sort,omitempty"`
 }
 
-func (x *LookupGalleryStackByNameReq) Reset() {
-	*x = LookupGalleryStackByNameReq{}
+func (x *ListDocumentsReq) Reset() {
+	*x = ListDocumentsReq{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_media_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -515,13 +580,13 @@ func (x *LookupGalleryStackByNameReq) Reset() {
 	}
 }
 
-func (x *LookupGalleryStackByNameReq) String() string {
+func (x *ListDocumentsReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LookupGalleryStackByNameReq) ProtoMessage() {}
+func (*ListDocumentsReq) ProtoMessage() {}
 
-func (x *LookupGalleryStackByNameReq) ProtoReflect() protoreflect.Message {
+func (x *ListDocumentsReq) ProtoReflect() protoreflect.Message {
 	mi := &file_media_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -533,38 +598,69 @@ func (x *LookupGalleryStackByNameReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LookupGalleryStackByNameReq.ProtoReflect.Descriptor instead.
-func (*LookupGalleryStackByNameReq) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListDocumentsReq.ProtoReflect.Descriptor instead.
+func (*ListDocumentsReq) Descriptor() ([]byte, []int) {
 	return file_media_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *LookupGalleryStackByNameReq) GetGalleryId() *v1.UUID {
+func (x *ListDocumentsReq) GetShelfId() *v1.UUID {
 	if x != nil {
-		return x.GalleryId
+		return x.ShelfId
 	}
 	return nil
 }
 
-func (x *LookupGalleryStackByNameReq) GetName() string {
+func (x *ListDocumentsReq) GetOffset() int64 {
 	if x != nil {
-		return x.Name
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListDocumentsReq) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListDocumentsReq) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ListDocumentsReq) GetSearch() string {
+	if x != nil {
+		return x.Search
 	}
 	return ""
 }
 
-type UploadImageReq struct {
+func (x *ListDocumentsReq) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+type DocumentPage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Types that are assignable to UploadData:
-	//	*UploadImageReq_Metadata
-	//	*UploadImageReq_Chunk
-	UploadData isUploadImageReq_UploadData `protobuf_oneof:"upload_data"`
+	Id        *v1.UUID         `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string           `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Documents []*ShelfDocument `protobuf:"bytes,3,rep,name=documents,proto3" json:"documents,omitempty"`
+	LegalHold *LegalHold       `protobuf:"bytes,4,opt,name=legal_hold,json=legalHold,proto3" json:"legal_hold,omitempty"`
+	Total     int64            `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"`
+	Offset    int64            `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit     int64            `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
 }
 
-func (x *UploadImageReq) Reset() {
-	*x = UploadImageReq{}
+func (x *DocumentPage) Reset() {
+	*x = DocumentPage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_media_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -572,13 +668,13 @@ func (x *UploadImageReq) Reset() {
 	}
 }
 
-func (x *UploadImageReq) String() string {
+func (x *DocumentPage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UploadImageReq) ProtoMessage() {}
+func (*DocumentPage) ProtoMessage() {}
 
-func (x *UploadImageReq) ProtoReflect() protoreflect.Message {
+func (x *DocumentPage) ProtoReflect() protoreflect.Message {
 	mi := &file_media_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -590,61 +686,78 @@ func (x *UploadImageReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UploadImageReq.ProtoReflect.Descriptor instead.
-func (*UploadImageReq) Descriptor() ([]byte, []int) {
+// Deprecated: Use DocumentPage.ProtoReflect.Descriptor instead.
+func (*DocumentPage) Descriptor() ([]byte, []int) {
 	return file_media_proto_rawDescGZIP(), []int{8}
 }
 
-func (m *UploadImageReq) GetUploadData() isUploadImageReq_UploadData {
-	if m != nil {
-		return m.UploadData
+func (x *DocumentPage) GetId() *v1.UUID {
+	if x != nil {
+		return x.Id
 	}
 	return nil
 }
 
-func (x *UploadImageReq) GetMetadata() *UploadImageReq_UploadImageMetadata {
-	if x, ok := x.GetUploadData().(*UploadImageReq_Metadata); ok {
-		return x.Metadata
+func (x *DocumentPage) GetName() string {
+	if x != nil {
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-func (x *UploadImageReq) GetChunk() []byte {
-	if x, ok := x.GetUploadData().(*UploadImageReq_Chunk); ok {
-		return x.Chunk
+func (x *DocumentPage) GetDocuments() []*ShelfDocument {
+	if x != nil {
+		return x.Documents
 	}
 	return nil
 }
 
-type isUploadImageReq_UploadData interface {
-	isUploadImageReq_UploadData()
+func (x *DocumentPage) GetLegalHold() *LegalHold {
+	if x != nil {
+		return x.LegalHold
+	}
+	return nil
 }
 
-type UploadImageReq_Metadata struct {
-	Metadata *UploadImageReq_UploadImageMetadata `protobuf:"bytes,1,opt,name=metadata,proto3,oneof"`
+func (x *DocumentPage) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
 }
 
-type UploadImageReq_Chunk struct {
-	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+func (x *DocumentPage) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
 }
 
-func (*UploadImageReq_Metadata) isUploadImageReq_UploadData() {}
-
-func (*UploadImageReq_Chunk) isUploadImageReq_UploadData() {}
+func (x *DocumentPage) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
 
-type ReplaceImageReq struct {
+type SearchDocumentsReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Types that are assignable to ReplaceData:
-	//	*ReplaceImageReq_Metadata
-	//	*ReplaceImageReq_Chunk
-	ReplaceData isReplaceImageReq_ReplaceData `protobuf_oneof:"replace_data"`
+	Offset int64 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	// 0 means no limit.
+	Limit       int64    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Search      string   `protobuf:"bytes,3,opt,name=search,proto3" json:"search,omitempty"`
+	Names       []string `protobuf:"bytes,4,rep,name=names,proto3" json:"names,omitempty"`
+	UniqueNames []string `protobuf:"bytes,5,rep,name=unique_names,json=uniqueNames,proto3" json:"unique_names,omitempty"`
+	Tags        []string `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Regular expression that is matched against the storage paths.
+	Path string `protobuf:"bytes,7,opt,name=path,proto3" json:"path,omitempty"`
 }
 
-func (x *ReplaceImageReq) Reset() {
-	*x = ReplaceImageReq{}
+func (x *SearchDocumentsReq) Reset() {
+	*x = SearchDocumentsReq{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_media_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -652,13 +765,13 @@ func (x *ReplaceImageReq) Reset() {
 	}
 }
 
-func (x *ReplaceImageReq) String() string {
+func (x *SearchDocumentsReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReplaceImageReq) ProtoMessage() {}
+func (*SearchDocumentsReq) ProtoMessage() {}
 
-func (x *ReplaceImageReq) ProtoReflect() protoreflect.Message {
+func (x *SearchDocumentsReq) ProtoReflect() protoreflect.Message {
 	mi := &file_media_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -670,60 +783,73 @@ func (x *ReplaceImageReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReplaceImageReq.ProtoReflect.Descriptor instead.
-func (*ReplaceImageReq) Descriptor() ([]byte, []int) {
+// Deprecated: Use SearchDocumentsReq.ProtoReflect.Descriptor instead.
+func (*SearchDocumentsReq) Descriptor() ([]byte, []int) {
 	return file_media_proto_rawDescGZIP(), []int{9}
 }
 
-func (m *ReplaceImageReq) GetReplaceData() isReplaceImageReq_ReplaceData {
-	if m != nil {
-		return m.ReplaceData
+func (x *SearchDocumentsReq) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
 	}
-	return nil
+	return 0
 }
 
-func (x *ReplaceImageReq) GetMetadata() *ReplaceImageReq_ReplaceImageMetadata {
-	if x, ok := x.GetReplaceData().(*ReplaceImageReq_Metadata); ok {
-		return x.Metadata
+func (x *SearchDocumentsReq) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
 	}
-	return nil
+	return 0
 }
 
-func (x *ReplaceImageReq) GetChunk() []byte {
-	if x, ok := x.GetReplaceData().(*ReplaceImageReq_Chunk); ok {
-		return x.Chunk
+func (x *SearchDocumentsReq) GetSearch() string {
+	if x != nil {
+		return x.Search
 	}
-	return nil
+	return ""
 }
 
-type isReplaceImageReq_ReplaceData interface {
-	isReplaceImageReq_ReplaceData()
+func (x *SearchDocumentsReq) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
 }
 
-type ReplaceImageReq_Metadata struct {
-	Metadata *ReplaceImageReq_ReplaceImageMetadata `protobuf:"bytes,1,opt,name=metadata,proto3,oneof"`
+func (x *SearchDocumentsReq) GetUniqueNames() []string {
+	if x != nil {
+		return x.UniqueNames
+	}
+	return nil
 }
 
-type ReplaceImageReq_Chunk struct {
-	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+func (x *SearchDocumentsReq) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
 }
 
-func (*ReplaceImageReq_Metadata) isReplaceImageReq_ReplaceData() {}
-
-func (*ReplaceImageReq_Chunk) isReplaceImageReq_ReplaceData() {}
+func (x *SearchDocumentsReq) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
 
-type Gallery struct {
+type SearchDocumentsResp struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id     *v1.UUID `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name   string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Stacks []*Stack `protobuf:"bytes,3,rep,name=stacks,proto3" json:"stacks,omitempty"`
+	Documents []*DocumentSearchResult `protobuf:"bytes,1,rep,name=documents,proto3" json:"documents,omitempty"`
+	Total     int64                   `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Offset    int64                   `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit     int64                   `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
 }
 
-func (x *Gallery) Reset() {
-	*x = Gallery{}
+func (x *SearchDocumentsResp) Reset() {
+	*x = SearchDocumentsResp{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_media_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -731,13 +857,13 @@ func (x *Gallery) Reset() {
 	}
 }
 
-func (x *Gallery) String() string {
+func (x *SearchDocumentsResp) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Gallery) ProtoMessage() {}
+func (*SearchDocumentsResp) ProtoMessage() {}
 
-func (x *Gallery) ProtoReflect() protoreflect.Message {
+func (x *SearchDocumentsResp) ProtoReflect() protoreflect.Message {
 	mi := &file_media_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -749,43 +875,51 @@ func (x *Gallery) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Gallery.ProtoReflect.Descriptor instead.
-func (*Gallery) Descriptor() ([]byte, []int) {
+// Deprecated: Use SearchDocumentsResp.ProtoReflect.Descriptor instead.
+func (*SearchDocumentsResp) Descriptor() ([]byte, []int) {
 	return file_media_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *Gallery) GetId() *v1.UUID {
+func (x *SearchDocumentsResp) GetDocuments() []*DocumentSearchResult {
 	if x != nil {
-		return x.Id
+		return x.Documents
 	}
 	return nil
 }
 
-func (x *Gallery) GetName() string {
+func (x *SearchDocumentsResp) GetTotal() int64 {
 	if x != nil {
-		return x.Name
+		return x.Total
 	}
-	return ""
+	return 0
 }
 
-func (x *Gallery) GetStacks() []*Stack {
+func (x *SearchDocumentsResp) GetOffset() int64 {
 	if x != nil {
-		return x.Stacks
+		return x.Offset
 	}
-	return nil
+	return 0
 }
 
-type Stack struct {
+func (x *SearchDocumentsResp) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type DocumentSearchResult struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id     *v1.UUID      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Images []*StackImage `protobuf:"bytes,2,rep,name=images,proto3" json:"images,omitempty"`
+	Document  *ShelfDocument `protobuf:"bytes,1,opt,name=document,proto3" json:"document,omitempty"`
+	ShelfId   *v1.UUID       `protobuf:"bytes,2,opt,name=shelf_id,json=shelfId,proto3" json:"shelf_id,omitempty"`
+	ShelfName string         `protobuf:"bytes,3,opt,name=shelf_name,json=shelfName,proto3" json:"shelf_name,omitempty"`
 }
 
-func (x *Stack) Reset() {
-	*x = Stack{}
+func (x *DocumentSearchResult) Reset() {
+	*x = DocumentSearchResult{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_media_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -793,13 +927,13 @@ func (x *Stack) Reset() {
 	}
 }
 
-func (x *Stack) String() string {
+func (x *DocumentSearchResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Stack) ProtoMessage() {}
+func (*DocumentSearchResult) ProtoMessage() {}
 
-func (x *Stack) ProtoReflect() protoreflect.Message {
+func (x *DocumentSearchResult) ProtoReflect() protoreflect.Message {
 	mi := &file_media_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -811,37 +945,44 @@ func (x *Stack) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Stack.ProtoReflect.Descriptor instead.
-func (*Stack) Descriptor() ([]byte, []int) {
+// Deprecated: Use DocumentSearchResult.ProtoReflect.Descriptor instead.
+func (*DocumentSearchResult) Descriptor() ([]byte, []int) {
 	return file_media_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *Stack) GetId() *v1.UUID {
+func (x *DocumentSearchResult) GetDocument() *ShelfDocument {
 	if x != nil {
-		return x.Id
+		return x.Document
 	}
 	return nil
 }
 
-func (x *Stack) GetImages() []*StackImage {
+func (x *DocumentSearchResult) GetShelfId() *v1.UUID {
 	if x != nil {
-		return x.Images
+		return x.ShelfId
 	}
 	return nil
 }
 
-type StackImage struct {
+func (x *DocumentSearchResult) GetShelfName() string {
+	if x != nil {
+		return x.ShelfName
+	}
+	return ""
+}
+
+type ListShelfsReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Image    *StorageImage `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
-	Original bool          `protobuf:"varint,2,opt,name=original,proto3" json:"original,omitempty"`
-	Size     string        `protobuf:"bytes,3,opt,name=size,proto3" json:"size,omitempty"`
+	Offset int64 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	// 0 means no limit.
+	Limit int64 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
 }
 
-func (x *StackImage) Reset() {
-	*x = StackImage{}
+func (x *ListShelfsReq) Reset() {
+	*x = ListShelfsReq{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_media_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -849,13 +990,13 @@ func (x *StackImage) Reset() {
 	}
 }
 
-func (x *StackImage) String() string {
+func (x *ListShelfsReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StackImage) ProtoMessage() {}
+func (*ListShelfsReq) ProtoMessage() {}
 
-func (x *StackImage) ProtoReflect() protoreflect.Message {
+func (x *ListShelfsReq) ProtoReflect() protoreflect.Message {
 	mi := &file_media_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -867,43 +1008,38 @@ func (x *StackImage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StackImage.ProtoReflect.Descriptor instead.
-func (*StackImage) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListShelfsReq.ProtoReflect.Descriptor instead.
+func (*ListShelfsReq) Descriptor() ([]byte, []int) {
 	return file_media_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *StackImage) GetImage() *StorageImage {
-	if x != nil {
-		return x.Image
-	}
-	return nil
-}
-
-func (x *StackImage) GetOriginal() bool {
+func (x *ListShelfsReq) GetOffset() int64 {
 	if x != nil {
-		return x.Original
+		return x.Offset
 	}
-	return false
+	return 0
 }
 
-func (x *StackImage) GetSize() string {
+func (x *ListShelfsReq) GetLimit() int64 {
 	if x != nil {
-		return x.Size
+		return x.Limit
 	}
-	return ""
+	return 0
 }
 
-type SortGalleryReq struct {
+type ShelfList struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id      *v1.UUID   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Sorting []*v1.UUID `protobuf:"bytes,2,rep,name=sorting,proto3" json:"sorting,omitempty"`
+	Shelfs []*ShelfRef `protobuf:"bytes,1,rep,name=shelfs,proto3" json:"shelfs,omitempty"`
+	Total  int64       `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Offset int64       `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit  int64       `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
 }
 
-func (x *SortGalleryReq) Reset() {
-	*x = SortGalleryReq{}
+func (x *ShelfList) Reset() {
+	*x = ShelfList{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_media_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -911,13 +1047,13 @@ func (x *SortGalleryReq) Reset() {
 	}
 }
 
-func (x *SortGalleryReq) String() string {
+func (x *ShelfList) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SortGalleryReq) ProtoMessage() {}
+func (*ShelfList) ProtoMessage() {}
 
-func (x *SortGalleryReq) ProtoReflect() protoreflect.Message {
+func (x *ShelfList) ProtoReflect() protoreflect.Message {
 	mi := &file_media_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -929,39 +1065,50 @@ func (x *SortGalleryReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SortGalleryReq.ProtoReflect.Descriptor instead.
-func (*SortGalleryReq) Descriptor() ([]byte, []int) {
+// Deprecated: Use ShelfList.ProtoReflect.Descriptor instead.
+func (*ShelfList) Descriptor() ([]byte, []int) {
 	return file_media_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *SortGalleryReq) GetId() *v1.UUID {
+func (x *ShelfList) GetShelfs() []*ShelfRef {
 	if x != nil {
-		return x.Id
+		return x.Shelfs
 	}
 	return nil
 }
 
-func (x *SortGalleryReq) GetSorting() []*v1.UUID {
+func (x *ShelfList) GetTotal() int64 {
 	if x != nil {
-		return x.Sorting
+		return x.Total
 	}
-	return nil
+	return 0
 }
 
-type UploadDocumentReq_UploadDocumentMetadata struct {
+func (x *ShelfList) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ShelfList) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ShelfRef struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ShelfId    *v1.UUID `protobuf:"bytes,1,opt,name=shelfId,proto3" json:"shelfId,omitempty"`
-	UniqueName string   `protobuf:"bytes,2,opt,name=uniqueName,proto3" json:"uniqueName,omitempty"`
-	Name       string   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	Disk       string   `protobuf:"bytes,4,opt,name=disk,proto3" json:"disk,omitempty"`
-	Path       string   `protobuf:"bytes,5,opt,name=path,proto3" json:"path,omitempty"`
+	Id   *v1.UUID `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 }
 
-func (x *UploadDocumentReq_UploadDocumentMetadata) Reset() {
-	*x = UploadDocumentReq_UploadDocumentMetadata{}
+func (x *ShelfRef) Reset() {
+	*x = ShelfRef{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_media_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -969,13 +1116,13 @@ func (x *UploadDocumentReq_UploadDocumentMetadata) Reset() {
 	}
 }
 
-func (x *UploadDocumentReq_UploadDocumentMetadata) String() string {
+func (x *ShelfRef) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UploadDocumentReq_UploadDocumentMetadata) ProtoMessage() {}
+func (*ShelfRef) ProtoMessage() {}
 
-func (x *UploadDocumentReq_UploadDocumentMetadata) ProtoReflect() protoreflect.Message {
+func (x *ShelfRef) ProtoReflect() protoreflect.Message {
 	mi := &file_media_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -987,57 +1134,37 @@ func (x *UploadDocumentReq_UploadDocumentMetadata) ProtoReflect() protoreflect.M
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UploadDocumentReq_UploadDocumentMetadata.ProtoReflect.Descriptor instead.
-func (*UploadDocumentReq_UploadDocumentMetadata) Descriptor() ([]byte, []int) {
-	return file_media_proto_rawDescGZIP(), []int{3, 0}
+// Deprecated: Use ShelfRef.ProtoReflect.Descriptor instead.
+func (*ShelfRef) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *UploadDocumentReq_UploadDocumentMetadata) GetShelfId() *v1.UUID {
+func (x *ShelfRef) GetId() *v1.UUID {
 	if x != nil {
-		return x.ShelfId
+		return x.Id
 	}
 	return nil
 }
 
-func (x *UploadDocumentReq_UploadDocumentMetadata) GetUniqueName() string {
-	if x != nil {
-		return x.UniqueName
-	}
-	return ""
-}
-
-func (x *UploadDocumentReq_UploadDocumentMetadata) GetName() string {
+func (x *ShelfRef) GetName() string {
 	if x != nil {
 		return x.Name
 	}
 	return ""
 }
 
-func (x *UploadDocumentReq_UploadDocumentMetadata) GetDisk() string {
-	if x != nil {
-		return x.Disk
-	}
-	return ""
-}
-
-func (x *UploadDocumentReq_UploadDocumentMetadata) GetPath() string {
-	if x != nil {
-		return x.Path
-	}
-	return ""
-}
-
-type ReplaceDocumentReq_ReplaceDocumentMetadata struct {
+type LegalHold struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ShelfId    *v1.UUID `protobuf:"bytes,1,opt,name=shelfId,proto3" json:"shelfId,omitempty"`
-	DocumentId *v1.UUID `protobuf:"bytes,2,opt,name=documentId,proto3" json:"documentId,omitempty"`
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Unix timestamp in milliseconds.
+	Since int64 `protobuf:"varint,2,opt,name=since,proto3" json:"since,omitempty"`
 }
 
-func (x *ReplaceDocumentReq_ReplaceDocumentMetadata) Reset() {
-	*x = ReplaceDocumentReq_ReplaceDocumentMetadata{}
+func (x *LegalHold) Reset() {
+	*x = LegalHold{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_media_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1045,13 +1172,13 @@ func (x *ReplaceDocumentReq_ReplaceDocumentMetadata) Reset() {
 	}
 }
 
-func (x *ReplaceDocumentReq_ReplaceDocumentMetadata) String() string {
+func (x *LegalHold) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReplaceDocumentReq_ReplaceDocumentMetadata) ProtoMessage() {}
+func (*LegalHold) ProtoMessage() {}
 
-func (x *ReplaceDocumentReq_ReplaceDocumentMetadata) ProtoReflect() protoreflect.Message {
+func (x *LegalHold) ProtoReflect() protoreflect.Message {
 	mi := &file_media_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1063,38 +1190,37 @@ func (x *ReplaceDocumentReq_ReplaceDocumentMetadata) ProtoReflect() protoreflect
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReplaceDocumentReq_ReplaceDocumentMetadata.ProtoReflect.Descriptor instead.
-func (*ReplaceDocumentReq_ReplaceDocumentMetadata) Descriptor() ([]byte, []int) {
-	return file_media_proto_rawDescGZIP(), []int{4, 0}
+// Deprecated: Use LegalHold.ProtoReflect.Descriptor instead.
+func (*LegalHold) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *ReplaceDocumentReq_ReplaceDocumentMetadata) GetShelfId() *v1.UUID {
+func (x *LegalHold) GetReason() string {
 	if x != nil {
-		return x.ShelfId
+		return x.Reason
 	}
-	return nil
+	return ""
 }
 
-func (x *ReplaceDocumentReq_ReplaceDocumentMetadata) GetDocumentId() *v1.UUID {
+func (x *LegalHold) GetSince() int64 {
 	if x != nil {
-		return x.DocumentId
+		return x.Since
 	}
-	return nil
+	return 0
 }
 
-type UploadImageReq_UploadImageMetadata struct {
+type TrashedDocument struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GalleryId *v1.UUID `protobuf:"bytes,1,opt,name=galleryId,proto3" json:"galleryId,omitempty"`
-	Name      string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Disk      string   `protobuf:"bytes,3,opt,name=disk,proto3" json:"disk,omitempty"`
-	Path      string   `protobuf:"bytes,4,opt,name=path,proto3" json:"path,omitempty"`
+	Document *ShelfDocument `protobuf:"bytes,1,opt,name=document,proto3" json:"document,omitempty"`
+	// Unix timestamp in milliseconds.
+	TrashedAt int64 `protobuf:"varint,2,opt,name=trashed_at,json=trashedAt,proto3" json:"trashed_at,omitempty"`
 }
 
-func (x *UploadImageReq_UploadImageMetadata) Reset() {
-	*x = UploadImageReq_UploadImageMetadata{}
+func (x *TrashedDocument) Reset() {
+	*x = TrashedDocument{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_media_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1102,13 +1228,13 @@ func (x *UploadImageReq_UploadImageMetadata) Reset() {
 	}
 }
 
-func (x *UploadImageReq_UploadImageMetadata) String() string {
+func (x *TrashedDocument) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UploadImageReq_UploadImageMetadata) ProtoMessage() {}
+func (*TrashedDocument) ProtoMessage() {}
 
-func (x *UploadImageReq_UploadImageMetadata) ProtoReflect() protoreflect.Message {
+func (x *TrashedDocument) ProtoReflect() protoreflect.Message {
 	mi := &file_media_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1120,50 +1246,43 @@ func (x *UploadImageReq_UploadImageMetadata) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UploadImageReq_UploadImageMetadata.ProtoReflect.Descriptor instead.
-func (*UploadImageReq_UploadImageMetadata) Descriptor() ([]byte, []int) {
-	return file_media_proto_rawDescGZIP(), []int{8, 0}
+// Deprecated: Use TrashedDocument.ProtoReflect.Descriptor instead.
+func (*TrashedDocument) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *UploadImageReq_UploadImageMetadata) GetGalleryId() *v1.UUID {
+func (x *TrashedDocument) GetDocument() *ShelfDocument {
 	if x != nil {
-		return x.GalleryId
+		return x.Document
 	}
 	return nil
 }
 
-func (x *UploadImageReq_UploadImageMetadata) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
-}
-
-func (x *UploadImageReq_UploadImageMetadata) GetDisk() string {
-	if x != nil {
-		return x.Disk
-	}
-	return ""
-}
-
-func (x *UploadImageReq_UploadImageMetadata) GetPath() string {
+func (x *TrashedDocument) GetTrashedAt() int64 {
 	if x != nil {
-		return x.Path
+		return x.TrashedAt
 	}
-	return ""
+	return 0
 }
 
-type ReplaceImageReq_ReplaceImageMetadata struct {
+type ShelfDocument struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GalleryId *v1.UUID `protobuf:"bytes,1,opt,name=galleryId,proto3" json:"galleryId,omitempty"`
-	StackId   *v1.UUID `protobuf:"bytes,2,opt,name=stackId,proto3" json:"stackId,omitempty"`
+	Document   *StorageDocument            `protobuf:"bytes,1,opt,name=document,proto3" json:"document,omitempty"`
+	Id         *v1.UUID                    `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	UniqueName string                      `protobuf:"bytes,3,opt,name=uniqueName,proto3" json:"uniqueName,omitempty"`
+	Variants   map[string]*StorageDocument `protobuf:"bytes,4,rep,name=variants,proto3" json:"variants,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Preview    *DocumentPreview            `protobuf:"bytes,5,opt,name=preview,proto3" json:"preview,omitempty"`
+	Audio      *AudioMetadata              `protobuf:"bytes,6,opt,name=audio,proto3" json:"audio,omitempty"`
+	LegalHold  *LegalHold                  `protobuf:"bytes,7,opt,name=legal_hold,json=legalHold,proto3" json:"legal_hold,omitempty"`
+	// Custom key/value fields of the document.
+	Metadata map[string]string `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
-func (x *ReplaceImageReq_ReplaceImageMetadata) Reset() {
-	*x = ReplaceImageReq_ReplaceImageMetadata{}
+func (x *ShelfDocument) Reset() {
+	*x = ShelfDocument{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_media_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1171,13 +1290,13 @@ func (x *ReplaceImageReq_ReplaceImageMetadata) Reset() {
 	}
 }
 
-func (x *ReplaceImageReq_ReplaceImageMetadata) String() string {
+func (x *ShelfDocument) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReplaceImageReq_ReplaceImageMetadata) ProtoMessage() {}
+func (*ShelfDocument) ProtoMessage() {}
 
-func (x *ReplaceImageReq_ReplaceImageMetadata) ProtoReflect() protoreflect.Message {
+func (x *ShelfDocument) ProtoReflect() protoreflect.Message {
 	mi := &file_media_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1189,44 +1308,2783 @@ func (x *ReplaceImageReq_ReplaceImageMetadata) ProtoReflect() protoreflect.Messa
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReplaceImageReq_ReplaceImageMetadata.ProtoReflect.Descriptor instead.
-func (*ReplaceImageReq_ReplaceImageMetadata) Descriptor() ([]byte, []int) {
-	return file_media_proto_rawDescGZIP(), []int{9, 0}
+// Deprecated: Use ShelfDocument.ProtoReflect.Descriptor instead.
+func (*ShelfDocument) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *ReplaceImageReq_ReplaceImageMetadata) GetGalleryId() *v1.UUID {
+func (x *ShelfDocument) GetDocument() *StorageDocument {
 	if x != nil {
-		return x.GalleryId
+		return x.Document
 	}
 	return nil
 }
 
-func (x *ReplaceImageReq_ReplaceImageMetadata) GetStackId() *v1.UUID {
+func (x *ShelfDocument) GetId() *v1.UUID {
 	if x != nil {
-		return x.StackId
+		return x.Id
 	}
 	return nil
 }
 
-var File_media_proto protoreflect.FileDescriptor
+func (x *ShelfDocument) GetUniqueName() string {
+	if x != nil {
+		return x.UniqueName
+	}
+	return ""
+}
 
-var file_media_proto_rawDesc = []byte{
-	0x0a, 0x0b, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x10, 0x6e,
-	0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x1a,
-	0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x16, 0x63, 0x6f,
-	0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x79, 0x0a, 0x0b, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x46,
-	0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x69, 0x73, 0x6b, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x69, 0x73, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x70,
-	0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12,
-	0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74,
-	0x61, 0x67, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x22,
-	0x6f, 0x0a, 0x0c, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12,
-	0x31, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e,
-	0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31,
+func (x *ShelfDocument) GetVariants() map[string]*StorageDocument {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *ShelfDocument) GetPreview() *DocumentPreview {
+	if x != nil {
+		return x.Preview
+	}
+	return nil
+}
+
+func (x *ShelfDocument) GetAudio() *AudioMetadata {
+	if x != nil {
+		return x.Audio
+	}
+	return nil
+}
+
+func (x *ShelfDocument) GetLegalHold() *LegalHold {
+	if x != nil {
+		return x.LegalHold
+	}
+	return nil
+}
+
+func (x *ShelfDocument) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type AudioMetadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Duration in milliseconds.
+	Duration int64 `protobuf:"varint,1,opt,name=duration,proto3" json:"duration,omitempty"`
+	// Bitrate in bits per second.
+	Bitrate int64 `protobuf:"varint,2,opt,name=bitrate,proto3" json:"bitrate,omitempty"`
+}
+
+func (x *AudioMetadata) Reset() {
+	*x = AudioMetadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AudioMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AudioMetadata) ProtoMessage() {}
+
+func (x *AudioMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AudioMetadata.ProtoReflect.Descriptor instead.
+func (*AudioMetadata) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AudioMetadata) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *AudioMetadata) GetBitrate() int64 {
+	if x != nil {
+		return x.Bitrate
+	}
+	return 0
+}
+
+type DocumentPreview struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status string        `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Image  *StorageImage `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	Error  string        `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *DocumentPreview) Reset() {
+	*x = DocumentPreview{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DocumentPreview) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocumentPreview) ProtoMessage() {}
+
+func (x *DocumentPreview) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocumentPreview.ProtoReflect.Descriptor instead.
+func (*DocumentPreview) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DocumentPreview) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DocumentPreview) GetImage() *StorageImage {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+func (x *DocumentPreview) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type TagDocumentReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ShelfId    *v1.UUID `protobuf:"bytes,1,opt,name=shelf_id,json=shelfId,proto3" json:"shelf_id,omitempty"`
+	DocumentId *v1.UUID `protobuf:"bytes,2,opt,name=document_id,json=documentId,proto3" json:"document_id,omitempty"`
+	Tags       []string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (x *TagDocumentReq) Reset() {
+	*x = TagDocumentReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TagDocumentReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TagDocumentReq) ProtoMessage() {}
+
+func (x *TagDocumentReq) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TagDocumentReq.ProtoReflect.Descriptor instead.
+func (*TagDocumentReq) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *TagDocumentReq) GetShelfId() *v1.UUID {
+	if x != nil {
+		return x.ShelfId
+	}
+	return nil
+}
+
+func (x *TagDocumentReq) GetDocumentId() *v1.UUID {
+	if x != nil {
+		return x.DocumentId
+	}
+	return nil
+}
+
+func (x *TagDocumentReq) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type RenameDocumentReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ShelfId    *v1.UUID `protobuf:"bytes,1,opt,name=shelf_id,json=shelfId,proto3" json:"shelf_id,omitempty"`
+	DocumentId *v1.UUID `protobuf:"bytes,2,opt,name=document_id,json=documentId,proto3" json:"document_id,omitempty"`
+	Name       string   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *RenameDocumentReq) Reset() {
+	*x = RenameDocumentReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RenameDocumentReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameDocumentReq) ProtoMessage() {}
+
+func (x *RenameDocumentReq) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameDocumentReq.ProtoReflect.Descriptor instead.
+func (*RenameDocumentReq) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RenameDocumentReq) GetShelfId() *v1.UUID {
+	if x != nil {
+		return x.ShelfId
+	}
+	return nil
+}
+
+func (x *RenameDocumentReq) GetDocumentId() *v1.UUID {
+	if x != nil {
+		return x.DocumentId
+	}
+	return nil
+}
+
+func (x *RenameDocumentReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteDocumentReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ShelfId    *v1.UUID `protobuf:"bytes,1,opt,name=shelf_id,json=shelfId,proto3" json:"shelf_id,omitempty"`
+	DocumentId *v1.UUID `protobuf:"bytes,2,opt,name=document_id,json=documentId,proto3" json:"document_id,omitempty"`
+}
+
+func (x *DeleteDocumentReq) Reset() {
+	*x = DeleteDocumentReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteDocumentReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDocumentReq) ProtoMessage() {}
+
+func (x *DeleteDocumentReq) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDocumentReq.ProtoReflect.Descriptor instead.
+func (*DeleteDocumentReq) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DeleteDocumentReq) GetShelfId() *v1.UUID {
+	if x != nil {
+		return x.ShelfId
+	}
+	return nil
+}
+
+func (x *DeleteDocumentReq) GetDocumentId() *v1.UUID {
+	if x != nil {
+		return x.DocumentId
+	}
+	return nil
+}
+
+type LookupGalleryStackByNameReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GalleryId *v1.UUID `protobuf:"bytes,1,opt,name=galleryId,proto3" json:"galleryId,omitempty"`
+	Name      string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *LookupGalleryStackByNameReq) Reset() {
+	*x = LookupGalleryStackByNameReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LookupGalleryStackByNameReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupGalleryStackByNameReq) ProtoMessage() {}
+
+func (x *LookupGalleryStackByNameReq) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupGalleryStackByNameReq.ProtoReflect.Descriptor instead.
+func (*LookupGalleryStackByNameReq) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *LookupGalleryStackByNameReq) GetGalleryId() *v1.UUID {
+	if x != nil {
+		return x.GalleryId
+	}
+	return nil
+}
+
+func (x *LookupGalleryStackByNameReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type LookupGalleryStacksByTagReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GalleryId *v1.UUID `protobuf:"bytes,1,opt,name=galleryId,proto3" json:"galleryId,omitempty"`
+	Tag       string   `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+}
+
+func (x *LookupGalleryStacksByTagReq) Reset() {
+	*x = LookupGalleryStacksByTagReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LookupGalleryStacksByTagReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupGalleryStacksByTagReq) ProtoMessage() {}
+
+func (x *LookupGalleryStacksByTagReq) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupGalleryStacksByTagReq.ProtoReflect.Descriptor instead.
+func (*LookupGalleryStacksByTagReq) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *LookupGalleryStacksByTagReq) GetGalleryId() *v1.UUID {
+	if x != nil {
+		return x.GalleryId
+	}
+	return nil
+}
+
+func (x *LookupGalleryStacksByTagReq) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+type LookupGalleryStacksByTagResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Found    bool       `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	StackIds []*v1.UUID `protobuf:"bytes,2,rep,name=stackIds,proto3" json:"stackIds,omitempty"`
+}
+
+func (x *LookupGalleryStacksByTagResp) Reset() {
+	*x = LookupGalleryStacksByTagResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LookupGalleryStacksByTagResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupGalleryStacksByTagResp) ProtoMessage() {}
+
+func (x *LookupGalleryStacksByTagResp) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupGalleryStacksByTagResp.ProtoReflect.Descriptor instead.
+func (*LookupGalleryStacksByTagResp) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *LookupGalleryStacksByTagResp) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *LookupGalleryStacksByTagResp) GetStackIds() []*v1.UUID {
+	if x != nil {
+		return x.StackIds
+	}
+	return nil
+}
+
+type UploadImageReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to UploadData:
+	//	*UploadImageReq_Metadata
+	//	*UploadImageReq_Chunk
+	UploadData isUploadImageReq_UploadData `protobuf_oneof:"upload_data"`
+}
+
+func (x *UploadImageReq) Reset() {
+	*x = UploadImageReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UploadImageReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadImageReq) ProtoMessage() {}
+
+func (x *UploadImageReq) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadImageReq.ProtoReflect.Descriptor instead.
+func (*UploadImageReq) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{26}
+}
+
+func (m *UploadImageReq) GetUploadData() isUploadImageReq_UploadData {
+	if m != nil {
+		return m.UploadData
+	}
+	return nil
+}
+
+func (x *UploadImageReq) GetMetadata() *UploadImageReq_UploadImageMetadata {
+	if x, ok := x.GetUploadData().(*UploadImageReq_Metadata); ok {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *UploadImageReq) GetChunk() []byte {
+	if x, ok := x.GetUploadData().(*UploadImageReq_Chunk); ok {
+		return x.Chunk
+	}
+	return nil
+}
+
+type isUploadImageReq_UploadData interface {
+	isUploadImageReq_UploadData()
+}
+
+type UploadImageReq_Metadata struct {
+	Metadata *UploadImageReq_UploadImageMetadata `protobuf:"bytes,1,opt,name=metadata,proto3,oneof"`
+}
+
+type UploadImageReq_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*UploadImageReq_Metadata) isUploadImageReq_UploadData() {}
+
+func (*UploadImageReq_Chunk) isUploadImageReq_UploadData() {}
+
+type ReplaceImageReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to ReplaceData:
+	//	*ReplaceImageReq_Metadata
+	//	*ReplaceImageReq_Chunk
+	ReplaceData isReplaceImageReq_ReplaceData `protobuf_oneof:"replace_data"`
+}
+
+func (x *ReplaceImageReq) Reset() {
+	*x = ReplaceImageReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReplaceImageReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplaceImageReq) ProtoMessage() {}
+
+func (x *ReplaceImageReq) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplaceImageReq.ProtoReflect.Descriptor instead.
+func (*ReplaceImageReq) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{27}
+}
+
+func (m *ReplaceImageReq) GetReplaceData() isReplaceImageReq_ReplaceData {
+	if m != nil {
+		return m.ReplaceData
+	}
+	return nil
+}
+
+func (x *ReplaceImageReq) GetMetadata() *ReplaceImageReq_ReplaceImageMetadata {
+	if x, ok := x.GetReplaceData().(*ReplaceImageReq_Metadata); ok {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *ReplaceImageReq) GetChunk() []byte {
+	if x, ok := x.GetReplaceData().(*ReplaceImageReq_Chunk); ok {
+		return x.Chunk
+	}
+	return nil
+}
+
+type isReplaceImageReq_ReplaceData interface {
+	isReplaceImageReq_ReplaceData()
+}
+
+type ReplaceImageReq_Metadata struct {
+	Metadata *ReplaceImageReq_ReplaceImageMetadata `protobuf:"bytes,1,opt,name=metadata,proto3,oneof"`
+}
+
+type ReplaceImageReq_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*ReplaceImageReq_Metadata) isReplaceImageReq_ReplaceData() {}
+
+func (*ReplaceImageReq_Chunk) isReplaceImageReq_ReplaceData() {}
+
+type Gallery struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id                 *v1.UUID        `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name               string          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Stacks             []*Stack        `protobuf:"bytes,3,rep,name=stacks,proto3" json:"stacks,omitempty"`
+	DefaultTags        []string        `protobuf:"bytes,4,rep,name=default_tags,json=defaultTags,proto3" json:"default_tags,omitempty"`
+	Theme              *GalleryTheme   `protobuf:"bytes,5,opt,name=theme,proto3" json:"theme,omitempty"`
+	OriginalsProtected bool            `protobuf:"varint,6,opt,name=originals_protected,json=originalsProtected,proto3" json:"originals_protected,omitempty"`
+	Trashed            []*TrashedStack `protobuf:"bytes,7,rep,name=trashed,proto3" json:"trashed,omitempty"`
+	LegalHold          *LegalHold      `protobuf:"bytes,8,opt,name=legal_hold,json=legalHold,proto3" json:"legal_hold,omitempty"`
+	VersionLimit       int64           `protobuf:"varint,9,opt,name=version_limit,json=versionLimit,proto3" json:"version_limit,omitempty"`
+	Version            int64           `protobuf:"varint,10,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *Gallery) Reset() {
+	*x = Gallery{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Gallery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Gallery) ProtoMessage() {}
+
+func (x *Gallery) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Gallery.ProtoReflect.Descriptor instead.
+func (*Gallery) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Gallery) GetId() *v1.UUID {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *Gallery) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Gallery) GetStacks() []*Stack {
+	if x != nil {
+		return x.Stacks
+	}
+	return nil
+}
+
+func (x *Gallery) GetDefaultTags() []string {
+	if x != nil {
+		return x.DefaultTags
+	}
+	return nil
+}
+
+func (x *Gallery) GetTheme() *GalleryTheme {
+	if x != nil {
+		return x.Theme
+	}
+	return nil
+}
+
+func (x *Gallery) GetOriginalsProtected() bool {
+	if x != nil {
+		return x.OriginalsProtected
+	}
+	return false
+}
+
+func (x *Gallery) GetTrashed() []*TrashedStack {
+	if x != nil {
+		return x.Trashed
+	}
+	return nil
+}
+
+func (x *Gallery) GetLegalHold() *LegalHold {
+	if x != nil {
+		return x.LegalHold
+	}
+	return nil
+}
+
+func (x *Gallery) GetVersionLimit() int64 {
+	if x != nil {
+		return x.VersionLimit
+	}
+	return 0
+}
+
+func (x *Gallery) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type StreamGalleryStacksReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GalleryId *v1.UUID `protobuf:"bytes,1,opt,name=gallery_id,json=galleryId,proto3" json:"gallery_id,omitempty"`
+	// Maximum number of stacks per message. 0 means the server default.
+	PageSize int64 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+}
+
+func (x *StreamGalleryStacksReq) Reset() {
+	*x = StreamGalleryStacksReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamGalleryStacksReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamGalleryStacksReq) ProtoMessage() {}
+
+func (x *StreamGalleryStacksReq) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamGalleryStacksReq.ProtoReflect.Descriptor instead.
+func (*StreamGalleryStacksReq) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *StreamGalleryStacksReq) GetGalleryId() *v1.UUID {
+	if x != nil {
+		return x.GalleryId
+	}
+	return nil
+}
+
+func (x *StreamGalleryStacksReq) GetPageSize() int64 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type GalleryStacksChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The gallery without its stacks. Only set in the first message.
+	Gallery *Gallery `protobuf:"bytes,1,opt,name=gallery,proto3" json:"gallery,omitempty"`
+	Stacks  []*Stack `protobuf:"bytes,2,rep,name=stacks,proto3" json:"stacks,omitempty"`
+}
+
+func (x *GalleryStacksChunk) Reset() {
+	*x = GalleryStacksChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GalleryStacksChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GalleryStacksChunk) ProtoMessage() {}
+
+func (x *GalleryStacksChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GalleryStacksChunk.ProtoReflect.Descriptor instead.
+func (*GalleryStacksChunk) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GalleryStacksChunk) GetGallery() *Gallery {
+	if x != nil {
+		return x.Gallery
+	}
+	return nil
+}
+
+func (x *GalleryStacksChunk) GetStacks() []*Stack {
+	if x != nil {
+		return x.Stacks
+	}
+	return nil
+}
+
+type ListStacksReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GalleryId *v1.UUID `protobuf:"bytes,1,opt,name=gallery_id,json=galleryId,proto3" json:"gallery_id,omitempty"`
+	Offset    int64    `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// 0 means no limit.
+	Limit int64    `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Tags  []string `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Comma-separated sort fields; a "-" prefix sorts in descending order.
+	Sort string `protobuf:"bytes,5,opt,name=sort,proto3" json:"sort,omitempty"`
+}
+
+func (x *ListStacksReq) Reset() {
+	*x = ListStacksReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListStacksReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStacksReq) ProtoMessage() {}
+
+func (x *ListStacksReq) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStacksReq.ProtoReflect.Descriptor instead.
+func (*ListStacksReq) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListStacksReq) GetGalleryId() *v1.UUID {
+	if x != nil {
+		return x.GalleryId
+	}
+	return nil
+}
+
+func (x *ListStacksReq) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListStacksReq) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListStacksReq) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ListStacksReq) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+type StackPage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id                 *v1.UUID   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name               string     `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Stacks             []*Stack   `protobuf:"bytes,3,rep,name=stacks,proto3" json:"stacks,omitempty"`
+	OriginalsProtected bool       `protobuf:"varint,4,opt,name=originals_protected,json=originalsProtected,proto3" json:"originals_protected,omitempty"`
+	LegalHold          *LegalHold `protobuf:"bytes,5,opt,name=legal_hold,json=legalHold,proto3" json:"legal_hold,omitempty"`
+	Total              int64      `protobuf:"varint,6,opt,name=total,proto3" json:"total,omitempty"`
+	Offset             int64      `protobuf:"varint,7,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit              int64      `protobuf:"varint,8,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *StackPage) Reset() {
+	*x = StackPage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StackPage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StackPage) ProtoMessage() {}
+
+func (x *StackPage) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StackPage.ProtoReflect.Descriptor instead.
+func (*StackPage) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *StackPage) GetId() *v1.UUID {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *StackPage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StackPage) GetStacks() []*Stack {
+	if x != nil {
+		return x.Stacks
+	}
+	return nil
+}
+
+func (x *StackPage) GetOriginalsProtected() bool {
+	if x != nil {
+		return x.OriginalsProtected
+	}
+	return false
+}
+
+func (x *StackPage) GetLegalHold() *LegalHold {
+	if x != nil {
+		return x.LegalHold
+	}
+	return nil
+}
+
+func (x *StackPage) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *StackPage) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *StackPage) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListGalleriesReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Offset int64 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	// 0 means no limit.
+	Limit int64 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *ListGalleriesReq) Reset() {
+	*x = ListGalleriesReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListGalleriesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGalleriesReq) ProtoMessage() {}
+
+func (x *ListGalleriesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGalleriesReq.ProtoReflect.Descriptor instead.
+func (*ListGalleriesReq) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListGalleriesReq) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListGalleriesReq) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GalleryList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Galleries []*GalleryRef `protobuf:"bytes,1,rep,name=galleries,proto3" json:"galleries,omitempty"`
+	Total     int64         `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Offset    int64         `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit     int64         `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *GalleryList) Reset() {
+	*x = GalleryList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GalleryList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GalleryList) ProtoMessage() {}
+
+func (x *GalleryList) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GalleryList.ProtoReflect.Descriptor instead.
+func (*GalleryList) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GalleryList) GetGalleries() []*GalleryRef {
+	if x != nil {
+		return x.Galleries
+	}
+	return nil
+}
+
+func (x *GalleryList) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GalleryList) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GalleryList) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GalleryRef struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   *v1.UUID `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *GalleryRef) Reset() {
+	*x = GalleryRef{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GalleryRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GalleryRef) ProtoMessage() {}
+
+func (x *GalleryRef) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GalleryRef.ProtoReflect.Descriptor instead.
+func (*GalleryRef) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GalleryRef) GetId() *v1.UUID {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *GalleryRef) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type TrashedStack struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Stack     *Stack `protobuf:"bytes,1,opt,name=stack,proto3" json:"stack,omitempty"`
+	TrashedAt int64  `protobuf:"varint,2,opt,name=trashed_at,json=trashedAt,proto3" json:"trashed_at,omitempty"`
+}
+
+func (x *TrashedStack) Reset() {
+	*x = TrashedStack{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TrashedStack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrashedStack) ProtoMessage() {}
+
+func (x *TrashedStack) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrashedStack.ProtoReflect.Descriptor instead.
+func (*TrashedStack) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *TrashedStack) GetStack() *Stack {
+	if x != nil {
+		return x.Stack
+	}
+	return nil
+}
+
+func (x *TrashedStack) GetTrashedAt() int64 {
+	if x != nil {
+		return x.TrashedAt
+	}
+	return 0
+}
+
+type GalleryTheme struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Primary   string `protobuf:"bytes,1,opt,name=primary,proto3" json:"primary,omitempty"`
+	Secondary string `protobuf:"bytes,2,opt,name=secondary,proto3" json:"secondary,omitempty"`
+}
+
+func (x *GalleryTheme) Reset() {
+	*x = GalleryTheme{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GalleryTheme) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GalleryTheme) ProtoMessage() {}
+
+func (x *GalleryTheme) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GalleryTheme.ProtoReflect.Descriptor instead.
+func (*GalleryTheme) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GalleryTheme) GetPrimary() string {
+	if x != nil {
+		return x.Primary
+	}
+	return ""
+}
+
+func (x *GalleryTheme) GetSecondary() string {
+	if x != nil {
+		return x.Secondary
+	}
+	return ""
+}
+
+type GalleryIndex struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id     *v1.UUID        `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name   string          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Stacks []*StackSummary `protobuf:"bytes,3,rep,name=stacks,proto3" json:"stacks,omitempty"`
+}
+
+func (x *GalleryIndex) Reset() {
+	*x = GalleryIndex{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GalleryIndex) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GalleryIndex) ProtoMessage() {}
+
+func (x *GalleryIndex) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GalleryIndex.ProtoReflect.Descriptor instead.
+func (*GalleryIndex) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *GalleryIndex) GetId() *v1.UUID {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *GalleryIndex) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GalleryIndex) GetStacks() []*StackSummary {
+	if x != nil {
+		return x.Stacks
+	}
+	return nil
+}
+
+type StackSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          *v1.UUID    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string      `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Tags        []string    `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
+	Thumbnail   *StackImage `protobuf:"bytes,4,opt,name=thumbnail,proto3" json:"thumbnail,omitempty"`
+	Placeholder string      `protobuf:"bytes,5,opt,name=placeholder,proto3" json:"placeholder,omitempty"`
+	Pending     bool        `protobuf:"varint,6,opt,name=pending,proto3" json:"pending,omitempty"`
+}
+
+func (x *StackSummary) Reset() {
+	*x = StackSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StackSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StackSummary) ProtoMessage() {}
+
+func (x *StackSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StackSummary.ProtoReflect.Descriptor instead.
+func (*StackSummary) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *StackSummary) GetId() *v1.UUID {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *StackSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StackSummary) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *StackSummary) GetThumbnail() *StackImage {
+	if x != nil {
+		return x.Thumbnail
+	}
+	return nil
+}
+
+func (x *StackSummary) GetPlaceholder() string {
+	if x != nil {
+		return x.Placeholder
+	}
+	return ""
+}
+
+func (x *StackSummary) GetPending() bool {
+	if x != nil {
+		return x.Pending
+	}
+	return false
+}
+
+type FetchStackReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GalleryId *v1.UUID `protobuf:"bytes,1,opt,name=gallery_id,json=galleryId,proto3" json:"gallery_id,omitempty"`
+	StackId   *v1.UUID `protobuf:"bytes,2,opt,name=stack_id,json=stackId,proto3" json:"stack_id,omitempty"`
+}
+
+func (x *FetchStackReq) Reset() {
+	*x = FetchStackReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchStackReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchStackReq) ProtoMessage() {}
+
+func (x *FetchStackReq) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchStackReq.ProtoReflect.Descriptor instead.
+func (*FetchStackReq) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *FetchStackReq) GetGalleryId() *v1.UUID {
+	if x != nil {
+		return x.GalleryId
+	}
+	return nil
+}
+
+func (x *FetchStackReq) GetStackId() *v1.UUID {
+	if x != nil {
+		return x.StackId
+	}
+	return nil
+}
+
+type Stack struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          *v1.UUID       `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Images      []*StackImage  `protobuf:"bytes,2,rep,name=images,proto3" json:"images,omitempty"`
+	Placeholder string         `protobuf:"bytes,3,opt,name=placeholder,proto3" json:"placeholder,omitempty"`
+	Metadata    *StackMetadata `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Pending     bool           `protobuf:"varint,5,opt,name=pending,proto3" json:"pending,omitempty"`
+	// Unix timestamp in milliseconds. Zero if the stack was not processed yet.
+	ProcessedAt     int64         `protobuf:"varint,6,opt,name=processed_at,json=processedAt,proto3" json:"processed_at,omitempty"`
+	ProcessingError string        `protobuf:"bytes,7,opt,name=processing_error,json=processingError,proto3" json:"processing_error,omitempty"`
+	Video           *StorageVideo `protobuf:"bytes,8,opt,name=video,proto3" json:"video,omitempty"`
+	// Hex-encoded SHA-256 checksum of the uploaded image or video.
+	Checksum string `protobuf:"bytes,9,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	// Dominant colors of the original image ("#rrggbb").
+	Palette   []string        `protobuf:"bytes,10,rep,name=palette,proto3" json:"palette,omitempty"`
+	LegalHold *LegalHold      `protobuf:"bytes,11,opt,name=legal_hold,json=legalHold,proto3" json:"legal_hold,omitempty"`
+	Version   int64           `protobuf:"varint,12,opt,name=version,proto3" json:"version,omitempty"`
+	Versions  []*StackVersion `protobuf:"bytes,13,rep,name=versions,proto3" json:"versions,omitempty"`
+	// Alternative text of the stack.
+	Alt     string `protobuf:"bytes,14,opt,name=alt,proto3" json:"alt,omitempty"`
+	Caption string `protobuf:"bytes,15,opt,name=caption,proto3" json:"caption,omitempty"`
+	// Custom key/value fields of the stack.
+	CustomMetadata map[string]string `protobuf:"bytes,16,rep,name=custom_metadata,json=customMetadata,proto3" json:"custom_metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	FocalPoint     *FocalPoint       `protobuf:"bytes,17,opt,name=focal_point,json=focalPoint,proto3" json:"focal_point,omitempty"`
+	// Area of the original image that the variants are generated from.
+	Crop *Area `protobuf:"bytes,18,opt,name=crop,proto3" json:"crop,omitempty"`
+}
+
+func (x *Stack) Reset() {
+	*x = Stack{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Stack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stack) ProtoMessage() {}
+
+func (x *Stack) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stack.ProtoReflect.Descriptor instead.
+func (*Stack) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *Stack) GetId() *v1.UUID {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *Stack) GetImages() []*StackImage {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+func (x *Stack) GetPlaceholder() string {
+	if x != nil {
+		return x.Placeholder
+	}
+	return ""
+}
+
+func (x *Stack) GetMetadata() *StackMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Stack) GetPending() bool {
+	if x != nil {
+		return x.Pending
+	}
+	return false
+}
+
+func (x *Stack) GetProcessedAt() int64 {
+	if x != nil {
+		return x.ProcessedAt
+	}
+	return 0
+}
+
+func (x *Stack) GetProcessingError() string {
+	if x != nil {
+		return x.ProcessingError
+	}
+	return ""
+}
+
+func (x *Stack) GetVideo() *StorageVideo {
+	if x != nil {
+		return x.Video
+	}
+	return nil
+}
+
+func (x *Stack) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *Stack) GetPalette() []string {
+	if x != nil {
+		return x.Palette
+	}
+	return nil
+}
+
+func (x *Stack) GetLegalHold() *LegalHold {
+	if x != nil {
+		return x.LegalHold
+	}
+	return nil
+}
+
+func (x *Stack) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Stack) GetVersions() []*StackVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+func (x *Stack) GetAlt() string {
+	if x != nil {
+		return x.Alt
+	}
+	return ""
+}
+
+func (x *Stack) GetCaption() string {
+	if x != nil {
+		return x.Caption
+	}
+	return ""
+}
+
+func (x *Stack) GetCustomMetadata() map[string]string {
+	if x != nil {
+		return x.CustomMetadata
+	}
+	return nil
+}
+
+func (x *Stack) GetFocalPoint() *FocalPoint {
+	if x != nil {
+		return x.FocalPoint
+	}
+	return nil
+}
+
+func (x *Stack) GetCrop() *Area {
+	if x != nil {
+		return x.Crop
+	}
+	return nil
+}
+
+// Focal point of an image in percent of its width and height.
+type FocalPoint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	X float64 `protobuf:"fixed64,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y float64 `protobuf:"fixed64,2,opt,name=y,proto3" json:"y,omitempty"`
+}
+
+func (x *FocalPoint) Reset() {
+	*x = FocalPoint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FocalPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FocalPoint) ProtoMessage() {}
+
+func (x *FocalPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FocalPoint.ProtoReflect.Descriptor instead.
+func (*FocalPoint) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *FocalPoint) GetX() float64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *FocalPoint) GetY() float64 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+// Rectangular area of an image in percent of its width and height.
+type Area struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	X      float64 `protobuf:"fixed64,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y      float64 `protobuf:"fixed64,2,opt,name=y,proto3" json:"y,omitempty"`
+	Width  float64 `protobuf:"fixed64,3,opt,name=width,proto3" json:"width,omitempty"`
+	Height float64 `protobuf:"fixed64,4,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+func (x *Area) Reset() {
+	*x = Area{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Area) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Area) ProtoMessage() {}
+
+func (x *Area) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Area.ProtoReflect.Descriptor instead.
+func (*Area) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *Area) GetX() float64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Area) GetY() float64 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *Area) GetWidth() float64 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *Area) GetHeight() float64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+type StackVersion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version  int64         `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Images   []*StackImage `protobuf:"bytes,2,rep,name=images,proto3" json:"images,omitempty"`
+	Video    *StorageVideo `protobuf:"bytes,3,opt,name=video,proto3" json:"video,omitempty"`
+	Checksum string        `protobuf:"bytes,4,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	// Unix timestamp in milliseconds.
+	ReplacedAt int64 `protobuf:"varint,5,opt,name=replaced_at,json=replacedAt,proto3" json:"replaced_at,omitempty"`
+}
+
+func (x *StackVersion) Reset() {
+	*x = StackVersion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StackVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StackVersion) ProtoMessage() {}
+
+func (x *StackVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StackVersion.ProtoReflect.Descriptor instead.
+func (*StackVersion) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *StackVersion) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *StackVersion) GetImages() []*StackImage {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+func (x *StackVersion) GetVideo() *StorageVideo {
+	if x != nil {
+		return x.Video
+	}
+	return nil
+}
+
+func (x *StackVersion) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *StackVersion) GetReplacedAt() int64 {
+	if x != nil {
+		return x.ReplacedAt
+	}
+	return 0
+}
+
+type StackMetadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Make      string `protobuf:"bytes,1,opt,name=make,proto3" json:"make,omitempty"`
+	Model     string `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	LensModel string `protobuf:"bytes,3,opt,name=lens_model,json=lensModel,proto3" json:"lens_model,omitempty"`
+	// Unix timestamp in milliseconds.
+	TakenAt      int64   `protobuf:"varint,4,opt,name=taken_at,json=takenAt,proto3" json:"taken_at,omitempty"`
+	ExposureTime float64 `protobuf:"fixed64,5,opt,name=exposure_time,json=exposureTime,proto3" json:"exposure_time,omitempty"`
+	FNumber      float64 `protobuf:"fixed64,6,opt,name=f_number,json=fNumber,proto3" json:"f_number,omitempty"`
+	Iso          int64   `protobuf:"varint,7,opt,name=iso,proto3" json:"iso,omitempty"`
+	FocalLength  float64 `protobuf:"fixed64,8,opt,name=focal_length,json=focalLength,proto3" json:"focal_length,omitempty"`
+	Gps          *GPS    `protobuf:"bytes,9,opt,name=gps,proto3" json:"gps,omitempty"`
+}
+
+func (x *StackMetadata) Reset() {
+	*x = StackMetadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StackMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StackMetadata) ProtoMessage() {}
+
+func (x *StackMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StackMetadata.ProtoReflect.Descriptor instead.
+func (*StackMetadata) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *StackMetadata) GetMake() string {
+	if x != nil {
+		return x.Make
+	}
+	return ""
+}
+
+func (x *StackMetadata) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *StackMetadata) GetLensModel() string {
+	if x != nil {
+		return x.LensModel
+	}
+	return ""
+}
+
+func (x *StackMetadata) GetTakenAt() int64 {
+	if x != nil {
+		return x.TakenAt
+	}
+	return 0
+}
+
+func (x *StackMetadata) GetExposureTime() float64 {
+	if x != nil {
+		return x.ExposureTime
+	}
+	return 0
+}
+
+func (x *StackMetadata) GetFNumber() float64 {
+	if x != nil {
+		return x.FNumber
+	}
+	return 0
+}
+
+func (x *StackMetadata) GetIso() int64 {
+	if x != nil {
+		return x.Iso
+	}
+	return 0
+}
+
+func (x *StackMetadata) GetFocalLength() float64 {
+	if x != nil {
+		return x.FocalLength
+	}
+	return 0
+}
+
+func (x *StackMetadata) GetGps() *GPS {
+	if x != nil {
+		return x.Gps
+	}
+	return nil
+}
+
+type GPS struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Latitude  float64 `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude float64 `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+}
+
+func (x *GPS) Reset() {
+	*x = GPS{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GPS) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GPS) ProtoMessage() {}
+
+func (x *GPS) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GPS.ProtoReflect.Descriptor instead.
+func (*GPS) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *GPS) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *GPS) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+type StackImage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Image    *StorageImage `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	Original bool          `protobuf:"varint,2,opt,name=original,proto3" json:"original,omitempty"`
+	Size     string        `protobuf:"bytes,3,opt,name=size,proto3" json:"size,omitempty"`
+	Format   string        `protobuf:"bytes,4,opt,name=format,proto3" json:"format,omitempty"`
+	Stale    bool          `protobuf:"varint,5,opt,name=stale,proto3" json:"stale,omitempty"`
+	Cropped  bool          `protobuf:"varint,6,opt,name=cropped,proto3" json:"cropped,omitempty"`
+}
+
+func (x *StackImage) Reset() {
+	*x = StackImage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StackImage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StackImage) ProtoMessage() {}
+
+func (x *StackImage) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StackImage.ProtoReflect.Descriptor instead.
+func (*StackImage) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *StackImage) GetImage() *StorageImage {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+func (x *StackImage) GetOriginal() bool {
+	if x != nil {
+		return x.Original
+	}
+	return false
+}
+
+func (x *StackImage) GetSize() string {
+	if x != nil {
+		return x.Size
+	}
+	return ""
+}
+
+func (x *StackImage) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *StackImage) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *StackImage) GetCropped() bool {
+	if x != nil {
+		return x.Cropped
+	}
+	return false
+}
+
+type SortGalleryReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      *v1.UUID   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Sorting []*v1.UUID `protobuf:"bytes,2,rep,name=sorting,proto3" json:"sorting,omitempty"`
+}
+
+func (x *SortGalleryReq) Reset() {
+	*x = SortGalleryReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SortGalleryReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SortGalleryReq) ProtoMessage() {}
+
+func (x *SortGalleryReq) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SortGalleryReq.ProtoReflect.Descriptor instead.
+func (*SortGalleryReq) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *SortGalleryReq) GetId() *v1.UUID {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *SortGalleryReq) GetSorting() []*v1.UUID {
+	if x != nil {
+		return x.Sorting
+	}
+	return nil
+}
+
+type TagStackReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GalleryId *v1.UUID `protobuf:"bytes,1,opt,name=gallery_id,json=galleryId,proto3" json:"gallery_id,omitempty"`
+	StackId   *v1.UUID `protobuf:"bytes,2,opt,name=stack_id,json=stackId,proto3" json:"stack_id,omitempty"`
+	Tags      []string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (x *TagStackReq) Reset() {
+	*x = TagStackReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TagStackReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TagStackReq) ProtoMessage() {}
+
+func (x *TagStackReq) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TagStackReq.ProtoReflect.Descriptor instead.
+func (*TagStackReq) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *TagStackReq) GetGalleryId() *v1.UUID {
+	if x != nil {
+		return x.GalleryId
+	}
+	return nil
+}
+
+func (x *TagStackReq) GetStackId() *v1.UUID {
+	if x != nil {
+		return x.StackId
+	}
+	return nil
+}
+
+func (x *TagStackReq) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type RenameStackReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GalleryId *v1.UUID `protobuf:"bytes,1,opt,name=gallery_id,json=galleryId,proto3" json:"gallery_id,omitempty"`
+	StackId   *v1.UUID `protobuf:"bytes,2,opt,name=stack_id,json=stackId,proto3" json:"stack_id,omitempty"`
+	Name      string   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *RenameStackReq) Reset() {
+	*x = RenameStackReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RenameStackReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameStackReq) ProtoMessage() {}
+
+func (x *RenameStackReq) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameStackReq.ProtoReflect.Descriptor instead.
+func (*RenameStackReq) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *RenameStackReq) GetGalleryId() *v1.UUID {
+	if x != nil {
+		return x.GalleryId
+	}
+	return nil
+}
+
+func (x *RenameStackReq) GetStackId() *v1.UUID {
+	if x != nil {
+		return x.StackId
+	}
+	return nil
+}
+
+func (x *RenameStackReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteStackReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GalleryId *v1.UUID `protobuf:"bytes,1,opt,name=gallery_id,json=galleryId,proto3" json:"gallery_id,omitempty"`
+	StackId   *v1.UUID `protobuf:"bytes,2,opt,name=stack_id,json=stackId,proto3" json:"stack_id,omitempty"`
+}
+
+func (x *DeleteStackReq) Reset() {
+	*x = DeleteStackReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteStackReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteStackReq) ProtoMessage() {}
+
+func (x *DeleteStackReq) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteStackReq.ProtoReflect.Descriptor instead.
+func (*DeleteStackReq) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *DeleteStackReq) GetGalleryId() *v1.UUID {
+	if x != nil {
+		return x.GalleryId
+	}
+	return nil
+}
+
+func (x *DeleteStackReq) GetStackId() *v1.UUID {
+	if x != nil {
+		return x.StackId
+	}
+	return nil
+}
+
+type UploadDocumentReq_UploadDocumentMetadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ShelfId    *v1.UUID `protobuf:"bytes,1,opt,name=shelfId,proto3" json:"shelfId,omitempty"`
+	UniqueName string   `protobuf:"bytes,2,opt,name=uniqueName,proto3" json:"uniqueName,omitempty"`
+	Name       string   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Disk       string   `protobuf:"bytes,4,opt,name=disk,proto3" json:"disk,omitempty"`
+	Path       string   `protobuf:"bytes,5,opt,name=path,proto3" json:"path,omitempty"`
+}
+
+func (x *UploadDocumentReq_UploadDocumentMetadata) Reset() {
+	*x = UploadDocumentReq_UploadDocumentMetadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UploadDocumentReq_UploadDocumentMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadDocumentReq_UploadDocumentMetadata) ProtoMessage() {}
+
+func (x *UploadDocumentReq_UploadDocumentMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadDocumentReq_UploadDocumentMetadata.ProtoReflect.Descriptor instead.
+func (*UploadDocumentReq_UploadDocumentMetadata) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{4, 0}
+}
+
+func (x *UploadDocumentReq_UploadDocumentMetadata) GetShelfId() *v1.UUID {
+	if x != nil {
+		return x.ShelfId
+	}
+	return nil
+}
+
+func (x *UploadDocumentReq_UploadDocumentMetadata) GetUniqueName() string {
+	if x != nil {
+		return x.UniqueName
+	}
+	return ""
+}
+
+func (x *UploadDocumentReq_UploadDocumentMetadata) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UploadDocumentReq_UploadDocumentMetadata) GetDisk() string {
+	if x != nil {
+		return x.Disk
+	}
+	return ""
+}
+
+func (x *UploadDocumentReq_UploadDocumentMetadata) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type ReplaceDocumentReq_ReplaceDocumentMetadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ShelfId    *v1.UUID `protobuf:"bytes,1,opt,name=shelfId,proto3" json:"shelfId,omitempty"`
+	DocumentId *v1.UUID `protobuf:"bytes,2,opt,name=documentId,proto3" json:"documentId,omitempty"`
+}
+
+func (x *ReplaceDocumentReq_ReplaceDocumentMetadata) Reset() {
+	*x = ReplaceDocumentReq_ReplaceDocumentMetadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[53]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReplaceDocumentReq_ReplaceDocumentMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplaceDocumentReq_ReplaceDocumentMetadata) ProtoMessage() {}
+
+func (x *ReplaceDocumentReq_ReplaceDocumentMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[53]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplaceDocumentReq_ReplaceDocumentMetadata.ProtoReflect.Descriptor instead.
+func (*ReplaceDocumentReq_ReplaceDocumentMetadata) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{5, 0}
+}
+
+func (x *ReplaceDocumentReq_ReplaceDocumentMetadata) GetShelfId() *v1.UUID {
+	if x != nil {
+		return x.ShelfId
+	}
+	return nil
+}
+
+func (x *ReplaceDocumentReq_ReplaceDocumentMetadata) GetDocumentId() *v1.UUID {
+	if x != nil {
+		return x.DocumentId
+	}
+	return nil
+}
+
+type UploadImageReq_UploadImageMetadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GalleryId *v1.UUID `protobuf:"bytes,1,opt,name=galleryId,proto3" json:"galleryId,omitempty"`
+	Name      string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Disk      string   `protobuf:"bytes,3,opt,name=disk,proto3" json:"disk,omitempty"`
+	Path      string   `protobuf:"bytes,4,opt,name=path,proto3" json:"path,omitempty"`
+	Video     bool     `protobuf:"varint,5,opt,name=video,proto3" json:"video,omitempty"`
+}
+
+func (x *UploadImageReq_UploadImageMetadata) Reset() {
+	*x = UploadImageReq_UploadImageMetadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[56]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UploadImageReq_UploadImageMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadImageReq_UploadImageMetadata) ProtoMessage() {}
+
+func (x *UploadImageReq_UploadImageMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[56]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadImageReq_UploadImageMetadata.ProtoReflect.Descriptor instead.
+func (*UploadImageReq_UploadImageMetadata) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{26, 0}
+}
+
+func (x *UploadImageReq_UploadImageMetadata) GetGalleryId() *v1.UUID {
+	if x != nil {
+		return x.GalleryId
+	}
+	return nil
+}
+
+func (x *UploadImageReq_UploadImageMetadata) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UploadImageReq_UploadImageMetadata) GetDisk() string {
+	if x != nil {
+		return x.Disk
+	}
+	return ""
+}
+
+func (x *UploadImageReq_UploadImageMetadata) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *UploadImageReq_UploadImageMetadata) GetVideo() bool {
+	if x != nil {
+		return x.Video
+	}
+	return false
+}
+
+type ReplaceImageReq_ReplaceImageMetadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GalleryId *v1.UUID `protobuf:"bytes,1,opt,name=galleryId,proto3" json:"galleryId,omitempty"`
+	StackId   *v1.UUID `protobuf:"bytes,2,opt,name=stackId,proto3" json:"stackId,omitempty"`
+}
+
+func (x *ReplaceImageReq_ReplaceImageMetadata) Reset() {
+	*x = ReplaceImageReq_ReplaceImageMetadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_media_proto_msgTypes[57]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReplaceImageReq_ReplaceImageMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplaceImageReq_ReplaceImageMetadata) ProtoMessage() {}
+
+func (x *ReplaceImageReq_ReplaceImageMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_media_proto_msgTypes[57]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplaceImageReq_ReplaceImageMetadata.ProtoReflect.Descriptor instead.
+func (*ReplaceImageReq_ReplaceImageMetadata) Descriptor() ([]byte, []int) {
+	return file_media_proto_rawDescGZIP(), []int{27, 0}
+}
+
+func (x *ReplaceImageReq_ReplaceImageMetadata) GetGalleryId() *v1.UUID {
+	if x != nil {
+		return x.GalleryId
+	}
+	return nil
+}
+
+func (x *ReplaceImageReq_ReplaceImageMetadata) GetStackId() *v1.UUID {
+	if x != nil {
+		return x.StackId
+	}
+	return nil
+}
+
+var File_media_proto protoreflect.FileDescriptor
+
+var file_media_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x10, 0x6e,
+	0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x1a,
+	0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x16, 0x63, 0x6f,
+	0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x76, 0x31, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb8, 0x01, 0x0a, 0x0b, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65,
+	0x46, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x69, 0x73, 0x6b,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x69, 0x73, 0x6b, 0x12, 0x12, 0x0a, 0x04,
+	0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x61, 0x67, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73,
+	0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x12, 0x21, 0x0a, 0x0c,
+	0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x50, 0x61, 0x74, 0x68, 0x22,
+	0x6f, 0x0a, 0x0c, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12,
+	0x31, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e,
+	0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31,
 	0x2e, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x04, 0x66, 0x69,
 	0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28,
 	0x03, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67,
@@ -1235,189 +4093,717 @@ var file_media_proto_rawDesc = []byte{
 	0x65, 0x6e, 0x74, 0x12, 0x31, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x0b, 0x32, 0x1d, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69,
 	0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x46, 0x69, 0x6c, 0x65,
-	0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x22, 0xbe, 0x02, 0x0a, 0x11, 0x55, 0x70, 0x6c, 0x6f, 0x61,
-	0x64, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x12, 0x58, 0x0a, 0x08,
-	0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x3a,
-	0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76,
-	0x31, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74,
-	0x52, 0x65, 0x71, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65,
-	0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x48, 0x00, 0x52, 0x08, 0x6d, 0x65,
-	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x1a, 0xa7,
-	0x01, 0x0a, 0x16, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
-	0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x31, 0x0a, 0x07, 0x73, 0x68, 0x65,
-	0x6c, 0x66, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63,
-	0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55,
-	0x55, 0x49, 0x44, 0x52, 0x07, 0x73, 0x68, 0x65, 0x6c, 0x66, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a,
-	0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0a, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x12, 0x12, 0x0a, 0x04, 0x64, 0x69, 0x73, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x64, 0x69, 0x73, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x42, 0x0d, 0x0a, 0x0b, 0x75, 0x70, 0x6c, 0x6f,
-	0x61, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x22, 0xa0, 0x02, 0x0a, 0x12, 0x52, 0x65, 0x70, 0x6c,
-	0x61, 0x63, 0x65, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x12, 0x5a,
-	0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x3c, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61,
-	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x44, 0x6f, 0x63, 0x75, 0x6d,
-	0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x44, 0x6f,
+	0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x22, 0xa1, 0x01, 0x0a, 0x0c, 0x53, 0x74, 0x6f, 0x72, 0x61,
+	0x67, 0x65, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x12, 0x31, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e,
+	0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65,
+	0x46, 0x69, 0x6c, 0x65, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69,
+	0x64, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68,
+	0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x22, 0xbe, 0x02, 0x0a, 0x11, 0x55,
+	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71,
+	0x12, 0x58, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x3a, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64,
+	0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x44, 0x6f, 0x63, 0x75,
+	0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x44, 0x6f,
 	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x48, 0x00,
 	0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x05, 0x63, 0x68,
 	0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x63, 0x68, 0x75,
-	0x6e, 0x6b, 0x1a, 0x85, 0x01, 0x0a, 0x17, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x44, 0x6f,
-	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x31,
-	0x0a, 0x07, 0x73, 0x68, 0x65, 0x6c, 0x66, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e,
-	0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x07, 0x73, 0x68, 0x65, 0x6c, 0x66, 0x49,
-	0x64, 0x12, 0x37, 0x0a, 0x0a, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e,
-	0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x0a,
-	0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x42, 0x0e, 0x0a, 0x0c, 0x72, 0x65,
-	0x70, 0x6c, 0x61, 0x63, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x22, 0x83, 0x01, 0x0a, 0x05, 0x53,
-	0x68, 0x65, 0x6c, 0x66, 0x12, 0x27, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f,
-	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x12, 0x3d, 0x0a, 0x09, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d,
-	0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x44, 0x6f, 0x63,
-	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x09, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73,
-	0x22, 0x97, 0x01, 0x0a, 0x0d, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65,
-	0x6e, 0x74, 0x12, 0x3d, 0x0a, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d,
-	0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x44,
-	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
-	0x74, 0x12, 0x27, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
-	0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76,
-	0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x75, 0x6e,
-	0x69, 0x71, 0x75, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
-	0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x68, 0x0a, 0x1b, 0x4c, 0x6f,
-	0x6f, 0x6b, 0x75, 0x70, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x63, 0x6b,
-	0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x12, 0x35, 0x0a, 0x09, 0x67, 0x61, 0x6c,
-	0x6c, 0x65, 0x72, 0x79, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e,
-	0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31,
-	0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x09, 0x67, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x49, 0x64,
-	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x22, 0x96, 0x02, 0x0a, 0x0e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49,
-	0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x12, 0x52, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64,
-	0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x6e, 0x69, 0x63, 0x65,
-	0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x6c,
-	0x6f, 0x61, 0x64, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x2e, 0x55, 0x70, 0x6c, 0x6f,
-	0x61, 0x64, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x48,
-	0x00, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x05, 0x63,
-	0x68, 0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x63, 0x68,
-	0x75, 0x6e, 0x6b, 0x1a, 0x88, 0x01, 0x0a, 0x13, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49, 0x6d,
-	0x61, 0x67, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x35, 0x0a, 0x09, 0x67,
-	0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x6e, 0x6b, 0x1a, 0xa7, 0x01, 0x0a, 0x16, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x44, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x31, 0x0a,
+	0x07, 0x73, 0x68, 0x65, 0x6c, 0x66, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x07, 0x73, 0x68, 0x65, 0x6c, 0x66, 0x49, 0x64,
+	0x12, 0x1e, 0x0a, 0x0a, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x69, 0x73, 0x6b, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x64, 0x69, 0x73, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x42, 0x0d, 0x0a, 0x0b,
+	0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x22, 0xa0, 0x02, 0x0a, 0x12,
+	0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52,
+	0x65, 0x71, 0x12, 0x5a, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x3c, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d,
+	0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x44,
+	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61,
+	0x63, 0x65, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x48, 0x00, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16,
+	0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52,
+	0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x1a, 0x85, 0x01, 0x0a, 0x17, 0x52, 0x65, 0x70, 0x6c, 0x61,
+	0x63, 0x65, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x12, 0x31, 0x0a, 0x07, 0x73, 0x68, 0x65, 0x6c, 0x66, 0x49, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f,
+	0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x07, 0x73, 0x68,
+	0x65, 0x6c, 0x66, 0x49, 0x64, 0x12, 0x37, 0x0a, 0x0a, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
+	0x74, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65,
+	0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55,
+	0x49, 0x44, 0x52, 0x0a, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x42, 0x0e,
+	0x0a, 0x0c, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x22, 0x96,
+	0x02, 0x0a, 0x05, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x12, 0x27, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63,
+	0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x3d, 0x0a, 0x09, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
+	0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63,
+	0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x65, 0x6c,
+	0x66, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x09, 0x64, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x12, 0x3b, 0x0a, 0x07, 0x74, 0x72, 0x61, 0x73, 0x68, 0x65, 0x64, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e,
+	0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x73, 0x68, 0x65, 0x64,
+	0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x74, 0x72, 0x61, 0x73, 0x68, 0x65,
+	0x64, 0x12, 0x3a, 0x0a, 0x0a, 0x6c, 0x65, 0x67, 0x61, 0x6c, 0x5f, 0x68, 0x6f, 0x6c, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e,
+	0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x65, 0x67, 0x61, 0x6c, 0x48, 0x6f,
+	0x6c, 0x64, 0x52, 0x09, 0x6c, 0x65, 0x67, 0x61, 0x6c, 0x48, 0x6f, 0x6c, 0x64, 0x12, 0x18, 0x0a,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0xb4, 0x01, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74,
+	0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x12, 0x32, 0x0a, 0x08,
+	0x73, 0x68, 0x65, 0x6c, 0x66, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
 	0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e,
-	0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x09, 0x67, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79,
-	0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x69, 0x73, 0x6b, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x69, 0x73, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61,
-	0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x42, 0x0d,
-	0x0a, 0x0b, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x22, 0x92, 0x02,
-	0x0a, 0x0f, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65,
-	0x71, 0x12, 0x54, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x36, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65,
-	0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x49, 0x6d,
-	0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x49, 0x6d,
-	0x61, 0x67, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x48, 0x00, 0x52, 0x08, 0x6d,
-	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x1a,
-	0x80, 0x01, 0x0a, 0x14, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65,
-	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x35, 0x0a, 0x09, 0x67, 0x61, 0x6c, 0x6c,
-	0x65, 0x72, 0x79, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69,
+	0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x07, 0x73, 0x68, 0x65, 0x6c, 0x66, 0x49, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61,
+	0x67, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x6f,
+	0x72, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x22, 0x8a,
+	0x02, 0x0a, 0x0c, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x67, 0x65, 0x12,
+	0x27, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69,
 	0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e,
-	0x55, 0x55, 0x49, 0x44, 0x52, 0x09, 0x67, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x49, 0x64, 0x12,
-	0x31, 0x0a, 0x07, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f,
-	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x07, 0x73, 0x74, 0x61, 0x63, 0x6b,
-	0x49, 0x64, 0x42, 0x0e, 0x0a, 0x0c, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x5f, 0x64, 0x61,
-	0x74, 0x61, 0x22, 0x77, 0x0a, 0x07, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x12, 0x27, 0x0a,
-	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65,
+	0x55, 0x55, 0x49, 0x44, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x3d, 0x0a, 0x09,
+	0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1f, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74,
+	0x52, 0x09, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x3a, 0x0a, 0x0a, 0x6c,
+	0x65, 0x67, 0x61, 0x6c, 0x5f, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1b, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x65, 0x67, 0x61, 0x6c, 0x48, 0x6f, 0x6c, 0x64, 0x52, 0x09, 0x6c, 0x65,
+	0x67, 0x61, 0x6c, 0x48, 0x6f, 0x6c, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x16, 0x0a,
+	0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f,
+	0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0xbb, 0x01, 0x0a, 0x12,
+	0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x61, 0x6d, 0x65,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x21,
+	0x0a, 0x0c, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x4e, 0x61, 0x6d, 0x65,
+	0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x9f, 0x01, 0x0a, 0x13, 0x53, 0x65,
+	0x61, 0x72, 0x63, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x12, 0x44, 0x0a, 0x09, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d,
+	0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74,
+	0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x09, 0x64, 0x6f,
+	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x16, 0x0a,
+	0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f,
+	0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0xa6, 0x01, 0x0a, 0x14,
+	0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x12, 0x3b, 0x0a, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73,
+	0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x44,
+	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
+	0x74, 0x12, 0x32, 0x0a, 0x08, 0x73, 0x68, 0x65, 0x6c, 0x66, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f,
+	0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x07, 0x73, 0x68,
+	0x65, 0x6c, 0x66, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x68, 0x65, 0x6c, 0x66, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x68, 0x65, 0x6c, 0x66,
+	0x4e, 0x61, 0x6d, 0x65, 0x22, 0x3d, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x68, 0x65, 0x6c,
+	0x66, 0x73, 0x52, 0x65, 0x71, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x22, 0x83, 0x01, 0x0a, 0x09, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x4c, 0x69, 0x73,
+	0x74, 0x12, 0x32, 0x0a, 0x06, 0x73, 0x68, 0x65, 0x6c, 0x66, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69,
+	0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x52, 0x65, 0x66, 0x52, 0x06, 0x73,
+	0x68, 0x65, 0x6c, 0x66, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x6f,
+	0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x47, 0x0a, 0x08, 0x53, 0x68, 0x65,
+	0x6c, 0x66, 0x52, 0x65, 0x66, 0x12, 0x27, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d,
+	0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x22, 0x39, 0x0a, 0x09, 0x4c, 0x65, 0x67, 0x61, 0x6c, 0x48, 0x6f, 0x6c, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x22, 0x6d, 0x0a,
+	0x0f, 0x54, 0x72, 0x61, 0x73, 0x68, 0x65, 0x64, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74,
+	0x12, 0x3b, 0x0a, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64,
+	0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x44, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a,
+	0x0a, 0x74, 0x72, 0x61, 0x73, 0x68, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x09, 0x74, 0x72, 0x61, 0x73, 0x68, 0x65, 0x64, 0x41, 0x74, 0x22, 0xfa, 0x04, 0x0a,
+	0x0d, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x3d,
+	0x0a, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x21, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x44, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x08, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x27, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65,
 	0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55,
-	0x49, 0x44, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x74,
-	0x61, 0x63, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63,
+	0x49, 0x44, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65,
+	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x75, 0x6e, 0x69, 0x71,
+	0x75, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x49, 0x0a, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e,
+	0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63,
+	0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x65, 0x6c,
+	0x66, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e,
+	0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74,
+	0x73, 0x12, 0x3b, 0x0a, 0x07, 0x70, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64,
+	0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x72,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x07, 0x70, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x35,
+	0x0a, 0x05, 0x61, 0x75, 0x64, 0x69, 0x6f, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e,
+	0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31,
+	0x2e, 0x41, 0x75, 0x64, 0x69, 0x6f, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x05,
+	0x61, 0x75, 0x64, 0x69, 0x6f, 0x12, 0x3a, 0x0a, 0x0a, 0x6c, 0x65, 0x67, 0x61, 0x6c, 0x5f, 0x68,
+	0x6f, 0x6c, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6e, 0x69, 0x63, 0x65,
+	0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x65, 0x67,
+	0x61, 0x6c, 0x48, 0x6f, 0x6c, 0x64, 0x52, 0x09, 0x6c, 0x65, 0x67, 0x61, 0x6c, 0x48, 0x6f, 0x6c,
+	0x64, 0x12, 0x49, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x08, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65,
+	0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x44, 0x6f, 0x63, 0x75,
+	0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x5e, 0x0a, 0x0d,
+	0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x37, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21,
+	0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
+	0x74, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3b, 0x0a, 0x0d,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x45, 0x0a, 0x0d, 0x41, 0x75, 0x64,
+	0x69, 0x6f, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x64, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x69, 0x74, 0x72, 0x61, 0x74,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x62, 0x69, 0x74, 0x72, 0x61, 0x74, 0x65,
+	0x22, 0x75, 0x0a, 0x0f, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x72, 0x65, 0x76,
+	0x69, 0x65, 0x77, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x34, 0x0a, 0x05, 0x69,
+	0x6d, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6e, 0x69, 0x63,
 	0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74,
-	0x61, 0x63, 0x6b, 0x52, 0x06, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x73, 0x22, 0x66, 0x0a, 0x05, 0x53,
+	0x6f, 0x72, 0x61, 0x67, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x92, 0x01, 0x0a, 0x0e, 0x54, 0x61, 0x67, 0x44,
+	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x12, 0x32, 0x0a, 0x08, 0x73, 0x68,
+	0x65, 0x6c, 0x66, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e,
+	0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31,
+	0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x07, 0x73, 0x68, 0x65, 0x6c, 0x66, 0x49, 0x64, 0x12, 0x38,
+	0x0a, 0x0b, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f,
+	0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x0a, 0x64, 0x6f,
+	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x22, 0x95, 0x01, 0x0a,
+	0x11, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52,
+	0x65, 0x71, 0x12, 0x32, 0x0a, 0x08, 0x73, 0x68, 0x65, 0x6c, 0x66, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63,
+	0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x07, 0x73,
+	0x68, 0x65, 0x6c, 0x66, 0x49, 0x64, 0x12, 0x38, 0x0a, 0x0b, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65,
+	0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69,
+	0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e,
+	0x55, 0x55, 0x49, 0x44, 0x52, 0x0a, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x22, 0x81, 0x01, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44,
+	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x12, 0x32, 0x0a, 0x08, 0x73, 0x68,
+	0x65, 0x6c, 0x66, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e,
+	0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31,
+	0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x07, 0x73, 0x68, 0x65, 0x6c, 0x66, 0x49, 0x64, 0x12, 0x38,
+	0x0a, 0x0b, 0x64, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f,
+	0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x0a, 0x64, 0x6f,
+	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x68, 0x0a, 0x1b, 0x4c, 0x6f, 0x6f, 0x6b,
+	0x75, 0x70, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x42, 0x79,
+	0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x12, 0x35, 0x0a, 0x09, 0x67, 0x61, 0x6c, 0x6c, 0x65,
+	0x72, 0x79, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63,
+	0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55,
+	0x55, 0x49, 0x44, 0x52, 0x09, 0x67, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x49, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x22, 0x66, 0x0a, 0x1b, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x47, 0x61, 0x6c, 0x6c,
+	0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x73, 0x42, 0x79, 0x54, 0x61, 0x67, 0x52, 0x65,
+	0x71, 0x12, 0x35, 0x0a, 0x09, 0x67, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x49, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63,
+	0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x09, 0x67,
+	0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67, 0x22, 0x69, 0x0a, 0x1c, 0x4c, 0x6f,
+	0x6f, 0x6b, 0x75, 0x70, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x63, 0x6b,
+	0x73, 0x42, 0x79, 0x54, 0x61, 0x67, 0x52, 0x65, 0x73, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6f,
+	0x75, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x6f, 0x75, 0x6e, 0x64,
+	0x12, 0x33, 0x0a, 0x08, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x49, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d,
+	0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x08, 0x73, 0x74, 0x61,
+	0x63, 0x6b, 0x49, 0x64, 0x73, 0x22, 0xac, 0x02, 0x0a, 0x0e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x12, 0x52, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x6e, 0x69, 0x63,
+	0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x2e, 0x55, 0x70, 0x6c,
+	0x6f, 0x61, 0x64, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x48, 0x00, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x05,
+	0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x63,
+	0x68, 0x75, 0x6e, 0x6b, 0x1a, 0x9e, 0x01, 0x0a, 0x13, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49,
+	0x6d, 0x61, 0x67, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x35, 0x0a, 0x09,
+	0x67, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e,
+	0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x09, 0x67, 0x61, 0x6c, 0x6c, 0x65, 0x72,
+	0x79, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x69, 0x73, 0x6b, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x69, 0x73, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x70,
+	0x61, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05,
+	0x76, 0x69, 0x64, 0x65, 0x6f, 0x42, 0x0d, 0x0a, 0x0b, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f,
+	0x64, 0x61, 0x74, 0x61, 0x22, 0x92, 0x02, 0x0a, 0x0f, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65,
+	0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x12, 0x54, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x36, 0x2e, 0x6e, 0x69, 0x63,
+	0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65,
+	0x70, 0x6c, 0x61, 0x63, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x2e, 0x52, 0x65,
+	0x70, 0x6c, 0x61, 0x63, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x48, 0x00, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16,
+	0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52,
+	0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x1a, 0x80, 0x01, 0x0a, 0x14, 0x52, 0x65, 0x70, 0x6c, 0x61,
+	0x63, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12,
+	0x35, 0x0a, 0x09, 0x67, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d,
+	0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x09, 0x67, 0x61, 0x6c,
+	0x6c, 0x65, 0x72, 0x79, 0x49, 0x64, 0x12, 0x31, 0x0a, 0x07, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x49,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d,
+	0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44,
+	0x52, 0x07, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x49, 0x64, 0x42, 0x0e, 0x0a, 0x0c, 0x72, 0x65, 0x70,
+	0x6c, 0x61, 0x63, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x22, 0xb6, 0x03, 0x0a, 0x07, 0x47, 0x61,
+	0x6c, 0x6c, 0x65, 0x72, 0x79, 0x12, 0x27, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d,
+	0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64,
+	0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x52, 0x06, 0x73, 0x74, 0x61,
+	0x63, 0x6b, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x74,
+	0x61, 0x67, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x66, 0x61, 0x75,
+	0x6c, 0x74, 0x54, 0x61, 0x67, 0x73, 0x12, 0x34, 0x0a, 0x05, 0x74, 0x68, 0x65, 0x6d, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e,
+	0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79,
+	0x54, 0x68, 0x65, 0x6d, 0x65, 0x52, 0x05, 0x74, 0x68, 0x65, 0x6d, 0x65, 0x12, 0x2f, 0x0a, 0x13,
+	0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x6f, 0x72, 0x69, 0x67, 0x69,
+	0x6e, 0x61, 0x6c, 0x73, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x38, 0x0a,
+	0x07, 0x74, 0x72, 0x61, 0x73, 0x68, 0x65, 0x64, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e,
+	0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76,
+	0x31, 0x2e, 0x54, 0x72, 0x61, 0x73, 0x68, 0x65, 0x64, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x52, 0x07,
+	0x74, 0x72, 0x61, 0x73, 0x68, 0x65, 0x64, 0x12, 0x3a, 0x0a, 0x0a, 0x6c, 0x65, 0x67, 0x61, 0x6c,
+	0x5f, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6e, 0x69,
+	0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x65, 0x67, 0x61, 0x6c, 0x48, 0x6f, 0x6c, 0x64, 0x52, 0x09, 0x6c, 0x65, 0x67, 0x61, 0x6c, 0x48,
+	0x6f, 0x6c, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x22, 0x6d, 0x0a, 0x16, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x47, 0x61, 0x6c, 0x6c,
+	0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x12, 0x36, 0x0a, 0x0a,
+	0x67, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f,
+	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x09, 0x67, 0x61, 0x6c, 0x6c, 0x65,
+	0x72, 0x79, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a,
+	0x65, 0x22, 0x7a, 0x0a, 0x12, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x63,
+	0x6b, 0x73, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x33, 0x0a, 0x07, 0x67, 0x61, 0x6c, 0x6c, 0x65,
+	0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63,
+	0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6c, 0x6c,
+	0x65, 0x72, 0x79, 0x52, 0x07, 0x67, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x12, 0x2f, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e,
+	0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x74, 0x61, 0x63, 0x6b, 0x52, 0x06, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x73, 0x22, 0x9d, 0x01,
+	0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x12,
+	0x36, 0x0a, 0x0a, 0x67, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f,
+	0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x09, 0x67, 0x61,
+	0x6c, 0x6c, 0x65, 0x72, 0x79, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05,
+	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x6f, 0x72,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x22, 0xaa, 0x02,
+	0x0a, 0x09, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x67, 0x65, 0x12, 0x27, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d,
+	0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x63,
+	0x6b, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63,
+	0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x63,
+	0x6b, 0x52, 0x06, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x73, 0x12, 0x2f, 0x0a, 0x13, 0x6f, 0x72, 0x69,
+	0x67, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c,
+	0x73, 0x50, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x3a, 0x0a, 0x0a, 0x6c, 0x65,
+	0x67, 0x61, 0x6c, 0x5f, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b,
+	0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x65, 0x67, 0x61, 0x6c, 0x48, 0x6f, 0x6c, 0x64, 0x52, 0x09, 0x6c, 0x65, 0x67,
+	0x61, 0x6c, 0x48, 0x6f, 0x6c, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x16, 0x0a, 0x06,
+	0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66,
+	0x66, 0x73, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x40, 0x0a, 0x10, 0x4c, 0x69,
+	0x73, 0x74, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x12, 0x16,
+	0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06,
+	0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x8d, 0x01, 0x0a,
+	0x0b, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x3a, 0x0a, 0x09,
+	0x67, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x52, 0x65, 0x66, 0x52, 0x09, 0x67,
+	0x61, 0x6c, 0x6c, 0x65, 0x72, 0x69, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x16,
+	0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06,
+	0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x49, 0x0a, 0x0a,
+	0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x52, 0x65, 0x66, 0x12, 0x27, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73,
+	0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52,
+	0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x5c, 0x0a, 0x0c, 0x54, 0x72, 0x61, 0x73, 0x68,
+	0x65, 0x64, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x12, 0x2d, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x63, 0x6b,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73,
+	0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x52,
+	0x05, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x72, 0x61, 0x73, 0x68, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x72, 0x61, 0x73,
+	0x68, 0x65, 0x64, 0x41, 0x74, 0x22, 0x46, 0x0a, 0x0c, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79,
+	0x54, 0x68, 0x65, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x12,
+	0x1c, 0x0a, 0x09, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x61, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x61, 0x72, 0x79, 0x22, 0x83, 0x01,
+	0x0a, 0x0c, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x27,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63,
+	0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55,
+	0x55, 0x49, 0x44, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x36, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x63, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6e, 0x69,
+	0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x74, 0x61, 0x63, 0x6b, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x06, 0x73, 0x74, 0x61,
+	0x63, 0x6b, 0x73, 0x22, 0xd7, 0x01, 0x0a, 0x0c, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x53, 0x75, 0x6d,
+	0x6d, 0x61, 0x72, 0x79, 0x12, 0x27, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f,
+	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x3a, 0x0a, 0x09, 0x74, 0x68, 0x75, 0x6d, 0x62, 0x6e, 0x61,
+	0x69, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63,
+	0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x63,
+	0x6b, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x09, 0x74, 0x68, 0x75, 0x6d, 0x62, 0x6e, 0x61, 0x69,
+	0x6c, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x68, 0x6f, 0x6c, 0x64, 0x65, 0x72,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x68, 0x6f, 0x6c,
+	0x64, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x7b, 0x0a,
+	0x0d, 0x46, 0x65, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x12, 0x36,
+	0x0a, 0x0a, 0x67, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d,
+	0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x09, 0x67, 0x61, 0x6c,
+	0x6c, 0x65, 0x72, 0x79, 0x49, 0x64, 0x12, 0x32, 0x0a, 0x08, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x5f,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63,
+	0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49,
+	0x44, 0x52, 0x07, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x49, 0x64, 0x22, 0xdb, 0x06, 0x0a, 0x05, 0x53,
 	0x74, 0x61, 0x63, 0x6b, 0x12, 0x27, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
 	0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f,
 	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x02, 0x69, 0x64, 0x12, 0x34, 0x0a,
 	0x06, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e,
 	0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31,
 	0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x06, 0x69, 0x6d, 0x61,
-	0x67, 0x65, 0x73, 0x22, 0x72, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x49, 0x6d, 0x61, 0x67,
-	0x65, 0x12, 0x34, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x1e, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61,
-	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65,
-	0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x72, 0x69, 0x67, 0x69,
-	0x6e, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6f, 0x72, 0x69, 0x67, 0x69,
-	0x6e, 0x61, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x6c, 0x0a, 0x0e, 0x53, 0x6f, 0x72, 0x74, 0x47,
-	0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x12, 0x27, 0x0a, 0x02, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e,
-	0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x02,
-	0x69, 0x64, 0x12, 0x31, 0x0a, 0x07, 0x73, 0x6f, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f,
-	0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x07, 0x73, 0x6f,
-	0x72, 0x74, 0x69, 0x6e, 0x67, 0x32, 0xbd, 0x06, 0x0a, 0x0c, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x51, 0x0a, 0x11, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70,
-	0x53, 0x68, 0x65, 0x6c, 0x66, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x2e, 0x6e, 0x69,
-	0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e,
-	0x4e, 0x61, 0x6d, 0x65, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x1a, 0x1d, 0x2e, 0x6e, 0x69, 0x63,
-	0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
-	0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x12, 0x58, 0x0a, 0x0e, 0x55, 0x70, 0x6c,
-	0x6f, 0x61, 0x64, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x23, 0x2e, 0x6e, 0x69,
-	0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x55,
-	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71,
+	0x67, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x68, 0x6f, 0x6c, 0x64,
+	0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x68,
+	0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x3b, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d,
+	0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x21, 0x0a, 0x0c,
+	0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x64, 0x41, 0x74, 0x12,
+	0x29, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x5f, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x63, 0x65,
+	0x73, 0x73, 0x69, 0x6e, 0x67, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x34, 0x0a, 0x05, 0x76, 0x69,
+	0x64, 0x65, 0x6f, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6e, 0x69, 0x63, 0x65,
+	0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f,
+	0x72, 0x61, 0x67, 0x65, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x52, 0x05, 0x76, 0x69, 0x64, 0x65, 0x6f,
+	0x12, 0x1a, 0x0a, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x12, 0x18, 0x0a, 0x07,
+	0x70, 0x61, 0x6c, 0x65, 0x74, 0x74, 0x65, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x70,
+	0x61, 0x6c, 0x65, 0x74, 0x74, 0x65, 0x12, 0x3a, 0x0a, 0x0a, 0x6c, 0x65, 0x67, 0x61, 0x6c, 0x5f,
+	0x68, 0x6f, 0x6c, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6e, 0x69, 0x63,
+	0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x65,
+	0x67, 0x61, 0x6c, 0x48, 0x6f, 0x6c, 0x64, 0x52, 0x09, 0x6c, 0x65, 0x67, 0x61, 0x6c, 0x48, 0x6f,
+	0x6c, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3a, 0x0a, 0x08,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e,
+	0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x08,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x6c, 0x74, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x61, 0x6c, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x61,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x61, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x54, 0x0a, 0x0f, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x6d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x10, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e,
+	0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x2e, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x4d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0e, 0x63, 0x75, 0x73, 0x74,
+	0x6f, 0x6d, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x3d, 0x0a, 0x0b, 0x66, 0x6f,
+	0x63, 0x61, 0x6c, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e,
+	0x76, 0x31, 0x2e, 0x46, 0x6f, 0x63, 0x61, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x0a, 0x66,
+	0x6f, 0x63, 0x61, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x2a, 0x0a, 0x04, 0x63, 0x72, 0x6f,
+	0x70, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d,
+	0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x72, 0x65, 0x61, 0x52,
+	0x04, 0x63, 0x72, 0x6f, 0x70, 0x1a, 0x41, 0x0a, 0x13, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x28, 0x0a, 0x0a, 0x46, 0x6f, 0x63, 0x61,
+	0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x01, 0x79, 0x22, 0x50, 0x0a, 0x04, 0x41, 0x72, 0x65, 0x61, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x01, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06,
+	0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x68, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x22, 0xd1, 0x01, 0x0a, 0x0c, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x34, 0x0a, 0x06, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x06, 0x69,
+	0x6d, 0x61, 0x67, 0x65, 0x73, 0x12, 0x34, 0x0a, 0x05, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d,
+	0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x56,
+	0x69, 0x64, 0x65, 0x6f, 0x52, 0x05, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x12, 0x1a, 0x0a, 0x08, 0x63,
+	0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63,
+	0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x61,
+	0x63, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x72, 0x65,
+	0x70, 0x6c, 0x61, 0x63, 0x65, 0x64, 0x41, 0x74, 0x22, 0x91, 0x02, 0x0a, 0x0d, 0x53, 0x74, 0x61,
+	0x63, 0x6b, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x61,
+	0x6b, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x61, 0x6b, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d,
+	0x6f, 0x64, 0x65, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x65, 0x6e, 0x73, 0x5f, 0x6d, 0x6f, 0x64,
+	0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6c, 0x65, 0x6e, 0x73, 0x4d, 0x6f,
+	0x64, 0x65, 0x6c, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x61, 0x6b, 0x65, 0x6e, 0x5f, 0x61, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x74, 0x61, 0x6b, 0x65, 0x6e, 0x41, 0x74, 0x12, 0x23,
+	0x0a, 0x0d, 0x65, 0x78, 0x70, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x65, 0x78, 0x70, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x54,
+	0x69, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x66, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x66, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x10,
+	0x0a, 0x03, 0x69, 0x73, 0x6f, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x69, 0x73, 0x6f,
+	0x12, 0x21, 0x0a, 0x0c, 0x66, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x66, 0x6f, 0x63, 0x61, 0x6c, 0x4c, 0x65, 0x6e,
+	0x67, 0x74, 0x68, 0x12, 0x27, 0x0a, 0x03, 0x67, 0x70, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61,
+	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x50, 0x53, 0x52, 0x03, 0x67, 0x70, 0x73, 0x22, 0x3f, 0x0a, 0x03,
+	0x47, 0x50, 0x53, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12,
+	0x1c, 0x0a, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x22, 0xba, 0x01,
+	0x0a, 0x0a, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x34, 0x0a, 0x05,
+	0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6e, 0x69,
+	0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x05, 0x69, 0x6d, 0x61,
+	0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x12, 0x12,
+	0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x69,
+	0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x73, 0x74, 0x61, 0x6c, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x63, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x22, 0x6c, 0x0a, 0x0e, 0x53, 0x6f,
+	0x72, 0x74, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x12, 0x27, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63,
+	0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49,
+	0x44, 0x52, 0x02, 0x69, 0x64, 0x12, 0x31, 0x0a, 0x07, 0x73, 0x6f, 0x72, 0x74, 0x69, 0x6e, 0x67,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73,
+	0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52,
+	0x07, 0x73, 0x6f, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x22, 0x8d, 0x01, 0x0a, 0x0b, 0x54, 0x61, 0x67,
+	0x53, 0x74, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x12, 0x36, 0x0a, 0x0a, 0x67, 0x61, 0x6c, 0x6c,
+	0x65, 0x72, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e,
+	0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31,
+	0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x09, 0x67, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x49, 0x64,
+	0x12, 0x32, 0x0a, 0x08, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d,
+	0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x07, 0x73, 0x74, 0x61,
+	0x63, 0x6b, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x22, 0x90, 0x01, 0x0a, 0x0e, 0x52, 0x65, 0x6e,
+	0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x12, 0x36, 0x0a, 0x0a, 0x67,
+	0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e,
+	0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x09, 0x67, 0x61, 0x6c, 0x6c, 0x65, 0x72,
+	0x79, 0x49, 0x64, 0x12, 0x32, 0x0a, 0x08, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e,
+	0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x07,
+	0x73, 0x74, 0x61, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x7c, 0x0a, 0x0e, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x12, 0x36, 0x0a,
+	0x0a, 0x67, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d,
+	0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x52, 0x09, 0x67, 0x61, 0x6c, 0x6c,
+	0x65, 0x72, 0x79, 0x49, 0x64, 0x12, 0x32, 0x0a, 0x08, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x5f, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d,
+	0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44,
+	0x52, 0x07, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x49, 0x64, 0x32, 0xa3, 0x12, 0x0a, 0x0c, 0x4d, 0x65,
+	0x64, 0x69, 0x61, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x51, 0x0a, 0x11, 0x4c, 0x6f,
+	0x6f, 0x6b, 0x75, 0x70, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x1d, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e,
+	0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x1a, 0x1d,
+	0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x12, 0x58, 0x0a,
+	0x0e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12,
+	0x23, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e,
+	0x76, 0x31, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
+	0x74, 0x52, 0x65, 0x71, 0x1a, 0x1f, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d,
+	0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x44, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x28, 0x01, 0x12, 0x5a, 0x0a, 0x0f, 0x52, 0x65, 0x70, 0x6c, 0x61,
+	0x63, 0x65, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x24, 0x2e, 0x6e, 0x69, 0x63,
+	0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65,
+	0x70, 0x6c, 0x61, 0x63, 0x65, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71,
 	0x1a, 0x1f, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61,
 	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
-	0x74, 0x28, 0x01, 0x12, 0x5a, 0x0a, 0x0f, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x44, 0x6f,
-	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x24, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73,
-	0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63,
-	0x65, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x1f, 0x2e, 0x6e,
+	0x74, 0x28, 0x01, 0x12, 0x5f, 0x0a, 0x14, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x44, 0x6f,
+	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x24, 0x2e, 0x6e, 0x69,
+	0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65,
+	0x71, 0x1a, 0x1f, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69,
+	0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65,
+	0x6e, 0x74, 0x28, 0x01, 0x12, 0x3e, 0x0a, 0x0a, 0x46, 0x65, 0x74, 0x63, 0x68, 0x53, 0x68, 0x65,
+	0x6c, 0x66, 0x12, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d,
+	0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x1a, 0x17, 0x2e, 0x6e, 0x69,
+	0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x68, 0x65, 0x6c, 0x66, 0x12, 0x53, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x6f, 0x63, 0x75,
+	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x22, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e,
+	0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x1e, 0x2e, 0x6e, 0x69, 0x63, 0x65,
+	0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x67, 0x65, 0x12, 0x5e, 0x0a, 0x0f, 0x53, 0x65, 0x61,
+	0x72, 0x63, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x24, 0x2e, 0x6e,
 	0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e,
-	0x53, 0x68, 0x65, 0x6c, 0x66, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x28, 0x01, 0x12,
-	0x3e, 0x0a, 0x0a, 0x46, 0x65, 0x74, 0x63, 0x68, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x12, 0x17, 0x2e,
-	0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76,
-	0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x1a, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73,
-	0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x12,
-	0x53, 0x0a, 0x13, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79,
-	0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73,
-	0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x4c,
-	0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x1a, 0x1d, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e,
-	0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70,
-	0x52, 0x65, 0x73, 0x70, 0x12, 0x68, 0x0a, 0x18, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x47, 0x61,
-	0x6c, 0x6c, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65,
-	0x12, 0x2d, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61,
-	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72,
-	0x79, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x1a,
-	0x1d, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e,
-	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x12, 0x4a,
-	0x0a, 0x0b, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x20, 0x2e,
+	0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x1a, 0x25, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64,
+	0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x44, 0x6f, 0x63, 0x75,
+	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x4a, 0x0a, 0x0a, 0x4c, 0x69, 0x73,
+	0x74, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x73, 0x12, 0x1f, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d,
+	0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53,
+	0x68, 0x65, 0x6c, 0x66, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x1b, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63,
+	0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x65, 0x6c,
+	0x66, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x50, 0x0a, 0x0b, 0x54, 0x61, 0x67, 0x44, 0x6f, 0x63, 0x75,
+	0x6d, 0x65, 0x6e, 0x74, 0x12, 0x20, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d,
+	0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x67, 0x44, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x1f, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73,
+	0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x44,
+	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x52, 0x0a, 0x0d, 0x55, 0x6e, 0x74, 0x61, 0x67,
+	0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x20, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63,
+	0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x67, 0x44,
+	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x1f, 0x2e, 0x6e, 0x69, 0x63,
+	0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68,
+	0x65, 0x6c, 0x66, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x56, 0x0a, 0x0e, 0x52,
+	0x65, 0x6e, 0x61, 0x6d, 0x65, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x23, 0x2e,
 	0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31,
-	0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x1a,
-	0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e,
-	0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x28, 0x01, 0x12, 0x4c, 0x0a, 0x0c, 0x52, 0x65,
-	0x70, 0x6c, 0x61, 0x63, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x21, 0x2e, 0x6e, 0x69, 0x63,
-	0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65,
-	0x70, 0x6c, 0x61, 0x63, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x17, 0x2e,
+	0x2e, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52,
+	0x65, 0x71, 0x1a, 0x1f, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64,
+	0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x68, 0x65, 0x6c, 0x66, 0x44, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x12, 0x4d, 0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x23, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e,
+	0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x44,
+	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x12, 0x53, 0x0a, 0x13, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x47, 0x61, 0x6c, 0x6c,
+	0x65, 0x72, 0x79, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x2e, 0x6e, 0x69, 0x63, 0x65,
+	0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x61,
+	0x6d, 0x65, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x1a, 0x1d, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63,
+	0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x6f,
+	0x6b, 0x75, 0x70, 0x52, 0x65, 0x73, 0x70, 0x12, 0x68, 0x0a, 0x18, 0x4c, 0x6f, 0x6f, 0x6b, 0x75,
+	0x70, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x42, 0x79, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x2d, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65,
+	0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x47, 0x61, 0x6c,
+	0x6c, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x52,
+	0x65, 0x71, 0x1a, 0x1d, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d,
+	0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x52, 0x65, 0x73,
+	0x70, 0x12, 0x79, 0x0a, 0x18, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x47, 0x61, 0x6c, 0x6c, 0x65,
+	0x72, 0x79, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x73, 0x42, 0x79, 0x54, 0x61, 0x67, 0x12, 0x2d, 0x2e,
 	0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31,
-	0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x28, 0x01, 0x12, 0x42, 0x0a, 0x0c, 0x46, 0x65, 0x74, 0x63,
-	0x68, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x12, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63,
-	0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49,
-	0x44, 0x1a, 0x19, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69,
-	0x61, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x12, 0x47, 0x0a, 0x0b,
-	0x53, 0x6f, 0x72, 0x74, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x12, 0x20, 0x2e, 0x6e, 0x69,
+	0x2e, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x53, 0x74,
+	0x61, 0x63, 0x6b, 0x73, 0x42, 0x79, 0x54, 0x61, 0x67, 0x52, 0x65, 0x71, 0x1a, 0x2e, 0x2e, 0x6e,
+	0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e,
+	0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61,
+	0x63, 0x6b, 0x73, 0x42, 0x79, 0x54, 0x61, 0x67, 0x52, 0x65, 0x73, 0x70, 0x12, 0x4a, 0x0a, 0x0b,
+	0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x20, 0x2e, 0x6e, 0x69,
+	0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x55,
+	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x17, 0x2e,
+	0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x28, 0x01, 0x12, 0x4c, 0x0a, 0x0c, 0x52, 0x65, 0x70, 0x6c,
+	0x61, 0x63, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x21, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63,
+	0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x70, 0x6c,
+	0x61, 0x63, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x17, 0x2e, 0x6e, 0x69,
 	0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53,
-	0x6f, 0x72, 0x74, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e,
-	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
-	0x45, 0x6d, 0x70, 0x74, 0x79, 0x42, 0x3d, 0x5a, 0x3b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
-	0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x72, 0x6e, 0x69, 0x63, 0x65, 0x2f, 0x6e, 0x69,
-	0x63, 0x65, 0x2d, 0x63, 0x6d, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x65, 0x6e,
-	0x2f, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2f, 0x76, 0x31, 0x3b, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x6d,
-	0x65, 0x64, 0x69, 0x61, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x61, 0x63, 0x6b, 0x28, 0x01, 0x12, 0x42, 0x0a, 0x0c, 0x46, 0x65, 0x74, 0x63, 0x68, 0x47,
+	0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x12, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73,
+	0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49, 0x44, 0x1a,
+	0x19, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x12, 0x67, 0x0a, 0x13, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x63, 0x6b,
+	0x73, 0x12, 0x28, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69,
+	0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x47, 0x61, 0x6c, 0x6c, 0x65,
+	0x72, 0x79, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x24, 0x2e, 0x6e, 0x69,
+	0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x47,
+	0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x73, 0x43, 0x68, 0x75, 0x6e,
+	0x6b, 0x30, 0x01, 0x12, 0x4c, 0x0a, 0x11, 0x46, 0x65, 0x74, 0x63, 0x68, 0x47, 0x61, 0x6c, 0x6c,
+	0x65, 0x72, 0x79, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63,
+	0x6d, 0x73, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x55, 0x49,
+	0x44, 0x1a, 0x1e, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69,
+	0x61, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x49, 0x6e, 0x64, 0x65,
+	0x78, 0x12, 0x4a, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x73, 0x12,
+	0x1f, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e,
+	0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71,
+	0x1a, 0x1b, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x67, 0x65, 0x12, 0x52, 0x0a,
+	0x0d, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x69, 0x65, 0x73, 0x12, 0x22,
+	0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x1a, 0x1d, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64,
+	0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x4c, 0x69, 0x73,
+	0x74, 0x12, 0x46, 0x0a, 0x0a, 0x46, 0x65, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x12,
+	0x1f, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e,
+	0x76, 0x31, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71,
+	0x1a, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x12, 0x47, 0x0a, 0x0b, 0x53, 0x6f, 0x72,
+	0x74, 0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x12, 0x20, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63,
+	0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x6f, 0x72, 0x74,
+	0x47, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x12, 0x42, 0x0a, 0x08, 0x54, 0x61, 0x67, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x12, 0x1d,
+	0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76,
+	0x31, 0x2e, 0x54, 0x61, 0x67, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x1a, 0x17, 0x2e,
+	0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x12, 0x44, 0x0a, 0x0a, 0x55, 0x6e, 0x74, 0x61, 0x67, 0x53,
+	0x74, 0x61, 0x63, 0x6b, 0x12, 0x1d, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d,
+	0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x67, 0x53, 0x74, 0x61, 0x63, 0x6b,
+	0x52, 0x65, 0x71, 0x1a, 0x17, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65,
+	0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x12, 0x48, 0x0a, 0x0b,
+	0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x12, 0x20, 0x2e, 0x6e, 0x69,
+	0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x65, 0x6e, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x1a, 0x17, 0x2e,
+	0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x12, 0x47, 0x0a, 0x0b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x53, 0x74, 0x61, 0x63, 0x6b, 0x12, 0x20, 0x2e, 0x6e, 0x69, 0x63, 0x65, 0x63, 0x6d, 0x73, 0x2e,
+	0x6d, 0x65, 0x64, 0x69, 0x61, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53,
+	0x74, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x42,
+	0x3d, 0x5a, 0x3b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x6f,
+	0x64, 0x65, 0x72, 0x6e, 0x69, 0x63, 0x65, 0x2f, 0x6e, 0x69, 0x63, 0x65, 0x2d, 0x63, 0x6d, 0x73,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x6d, 0x65, 0x64, 0x69, 0x61,
+	0x2f, 0x76, 0x31, 0x3b, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1432,81 +4818,221 @@ func file_media_proto_rawDescGZIP() []byte {
 	return file_media_proto_rawDescData
 }
 
-var file_media_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_media_proto_msgTypes = make([]protoimpl.MessageInfo, 59)
 var file_media_proto_goTypes = []any{
 	(*StorageFile)(nil),                                // 0: nicecms.media.v1.StorageFile
 	(*StorageImage)(nil),                               // 1: nicecms.media.v1.StorageImage
 	(*StorageDocument)(nil),                            // 2: nicecms.media.v1.StorageDocument
-	(*UploadDocumentReq)(nil),                          // 3: nicecms.media.v1.UploadDocumentReq
-	(*ReplaceDocumentReq)(nil),                         // 4: nicecms.media.v1.ReplaceDocumentReq
-	(*Shelf)(nil),                                      // 5: nicecms.media.v1.Shelf
-	(*ShelfDocument)(nil),                              // 6: nicecms.media.v1.ShelfDocument
-	(*LookupGalleryStackByNameReq)(nil),                // 7: nicecms.media.v1.LookupGalleryStackByNameReq
-	(*UploadImageReq)(nil),                             // 8: nicecms.media.v1.UploadImageReq
-	(*ReplaceImageReq)(nil),                            // 9: nicecms.media.v1.ReplaceImageReq
-	(*Gallery)(nil),                                    // 10: nicecms.media.v1.Gallery
-	(*Stack)(nil),                                      // 11: nicecms.media.v1.Stack
-	(*StackImage)(nil),                                 // 12: nicecms.media.v1.StackImage
-	(*SortGalleryReq)(nil),                             // 13: nicecms.media.v1.SortGalleryReq
-	(*UploadDocumentReq_UploadDocumentMetadata)(nil),   // 14: nicecms.media.v1.UploadDocumentReq.UploadDocumentMetadata
-	(*ReplaceDocumentReq_ReplaceDocumentMetadata)(nil), // 15: nicecms.media.v1.ReplaceDocumentReq.ReplaceDocumentMetadata
-	(*UploadImageReq_UploadImageMetadata)(nil),         // 16: nicecms.media.v1.UploadImageReq.UploadImageMetadata
-	(*ReplaceImageReq_ReplaceImageMetadata)(nil),       // 17: nicecms.media.v1.ReplaceImageReq.ReplaceImageMetadata
-	(*v1.UUID)(nil),                                    // 18: nicecms.common.v1.UUID
-	(*v1.NameLookup)(nil),                              // 19: nicecms.common.v1.NameLookup
-	(*v1.LookupResp)(nil),                              // 20: nicecms.common.v1.LookupResp
-	(*emptypb.Empty)(nil),                              // 21: google.protobuf.Empty
+	(*StorageVideo)(nil),                               // 3: nicecms.media.v1.StorageVideo
+	(*UploadDocumentReq)(nil),                          // 4: nicecms.media.v1.UploadDocumentReq
+	(*ReplaceDocumentReq)(nil),                         // 5: nicecms.media.v1.ReplaceDocumentReq
+	(*Shelf)(nil),                                      // 6: nicecms.media.v1.Shelf
+	(*ListDocumentsReq)(nil),                           // 7: nicecms.media.v1.ListDocumentsReq
+	(*DocumentPage)(nil),                               // 8: nicecms.media.v1.DocumentPage
+	(*SearchDocumentsReq)(nil),                         // 9: nicecms.media.v1.SearchDocumentsReq
+	(*SearchDocumentsResp)(nil),                        // 10: nicecms.media.v1.SearchDocumentsResp
+	(*DocumentSearchResult)(nil),                       // 11: nicecms.media.v1.DocumentSearchResult
+	(*ListShelfsReq)(nil),                              // 12: nicecms.media.v1.ListShelfsReq
+	(*ShelfList)(nil),                                  // 13: nicecms.media.v1.ShelfList
+	(*ShelfRef)(nil),                                   // 14: nicecms.media.v1.ShelfRef
+	(*LegalHold)(nil),                                  // 15: nicecms.media.v1.LegalHold
+	(*TrashedDocument)(nil),                            // 16: nicecms.media.v1.TrashedDocument
+	(*ShelfDocument)(nil),                              // 17: nicecms.media.v1.ShelfDocument
+	(*AudioMetadata)(nil),                              // 18: nicecms.media.v1.AudioMetadata
+	(*DocumentPreview)(nil),                            // 19: nicecms.media.v1.DocumentPreview
+	(*TagDocumentReq)(nil),                             // 20: nicecms.media.v1.TagDocumentReq
+	(*RenameDocumentReq)(nil),                          // 21: nicecms.media.v1.RenameDocumentReq
+	(*DeleteDocumentReq)(nil),                          // 22: nicecms.media.v1.DeleteDocumentReq
+	(*LookupGalleryStackByNameReq)(nil),                // 23: nicecms.media.v1.LookupGalleryStackByNameReq
+	(*LookupGalleryStacksByTagReq)(nil),                // 24: nicecms.media.v1.LookupGalleryStacksByTagReq
+	(*LookupGalleryStacksByTagResp)(nil),               // 25: nicecms.media.v1.LookupGalleryStacksByTagResp
+	(*UploadImageReq)(nil),                             // 26: nicecms.media.v1.UploadImageReq
+	(*ReplaceImageReq)(nil),                            // 27: nicecms.media.v1.ReplaceImageReq
+	(*Gallery)(nil),                                    // 28: nicecms.media.v1.Gallery
+	(*StreamGalleryStacksReq)(nil),                     // 29: nicecms.media.v1.StreamGalleryStacksReq
+	(*GalleryStacksChunk)(nil),                         // 30: nicecms.media.v1.GalleryStacksChunk
+	(*ListStacksReq)(nil),                              // 31: nicecms.media.v1.ListStacksReq
+	(*StackPage)(nil),                                  // 32: nicecms.media.v1.StackPage
+	(*ListGalleriesReq)(nil),                           // 33: nicecms.media.v1.ListGalleriesReq
+	(*GalleryList)(nil),                                // 34: nicecms.media.v1.GalleryList
+	(*GalleryRef)(nil),                                 // 35: nicecms.media.v1.GalleryRef
+	(*TrashedStack)(nil),                               // 36: nicecms.media.v1.TrashedStack
+	(*GalleryTheme)(nil),                               // 37: nicecms.media.v1.GalleryTheme
+	(*GalleryIndex)(nil),                               // 38: nicecms.media.v1.GalleryIndex
+	(*StackSummary)(nil),                               // 39: nicecms.media.v1.StackSummary
+	(*FetchStackReq)(nil),                              // 40: nicecms.media.v1.FetchStackReq
+	(*Stack)(nil),                                      // 41: nicecms.media.v1.Stack
+	(*FocalPoint)(nil),                                 // 42: nicecms.media.v1.FocalPoint
+	(*Area)(nil),                                       // 43: nicecms.media.v1.Area
+	(*StackVersion)(nil),                               // 44: nicecms.media.v1.StackVersion
+	(*StackMetadata)(nil),                              // 45: nicecms.media.v1.StackMetadata
+	(*GPS)(nil),                                        // 46: nicecms.media.v1.GPS
+	(*StackImage)(nil),                                 // 47: nicecms.media.v1.StackImage
+	(*SortGalleryReq)(nil),                             // 48: nicecms.media.v1.SortGalleryReq
+	(*TagStackReq)(nil),                                // 49: nicecms.media.v1.TagStackReq
+	(*RenameStackReq)(nil),                             // 50: nicecms.media.v1.RenameStackReq
+	(*DeleteStackReq)(nil),                             // 51: nicecms.media.v1.DeleteStackReq
+	(*UploadDocumentReq_UploadDocumentMetadata)(nil),   // 52: nicecms.media.v1.UploadDocumentReq.UploadDocumentMetadata
+	(*ReplaceDocumentReq_ReplaceDocumentMetadata)(nil), // 53: nicecms.media.v1.ReplaceDocumentReq.ReplaceDocumentMetadata
+	nil, // 54: nicecms.media.v1.ShelfDocument.VariantsEntry
+	nil, // 55: nicecms.media.v1.ShelfDocument.MetadataEntry
+	(*UploadImageReq_UploadImageMetadata)(nil),   // 56: nicecms.media.v1.UploadImageReq.UploadImageMetadata
+	(*ReplaceImageReq_ReplaceImageMetadata)(nil), // 57: nicecms.media.v1.ReplaceImageReq.ReplaceImageMetadata
+	nil,                   // 58: nicecms.media.v1.Stack.CustomMetadataEntry
+	(*v1.UUID)(nil),       // 59: nicecms.common.v1.UUID
+	(*v1.NameLookup)(nil), // 60: nicecms.common.v1.NameLookup
+	(*v1.LookupResp)(nil), // 61: nicecms.common.v1.LookupResp
+	(*emptypb.Empty)(nil), // 62: google.protobuf.Empty
 }
 var file_media_proto_depIdxs = []int32{
-	0,  // 0: nicecms.media.v1.StorageImage.file:type_name -> nicecms.media.v1.StorageFile
-	0,  // 1: nicecms.media.v1.StorageDocument.file:type_name -> nicecms.media.v1.StorageFile
-	14, // 2: nicecms.media.v1.UploadDocumentReq.metadata:type_name -> nicecms.media.v1.UploadDocumentReq.UploadDocumentMetadata
-	15, // 3: nicecms.media.v1.ReplaceDocumentReq.metadata:type_name -> nicecms.media.v1.ReplaceDocumentReq.ReplaceDocumentMetadata
-	18, // 4: nicecms.media.v1.Shelf.id:type_name -> nicecms.common.v1.UUID
-	6,  // 5: nicecms.media.v1.Shelf.documents:type_name -> nicecms.media.v1.ShelfDocument
-	2,  // 6: nicecms.media.v1.ShelfDocument.document:type_name -> nicecms.media.v1.StorageDocument
-	18, // 7: nicecms.media.v1.ShelfDocument.id:type_name -> nicecms.common.v1.UUID
-	18, // 8: nicecms.media.v1.LookupGalleryStackByNameReq.galleryId:type_name -> nicecms.common.v1.UUID
-	16, // 9: nicecms.media.v1.UploadImageReq.metadata:type_name -> nicecms.media.v1.UploadImageReq.UploadImageMetadata
-	17, // 10: nicecms.media.v1.ReplaceImageReq.metadata:type_name -> nicecms.media.v1.ReplaceImageReq.ReplaceImageMetadata
-	18, // 11: nicecms.media.v1.Gallery.id:type_name -> nicecms.common.v1.UUID
-	11, // 12: nicecms.media.v1.Gallery.stacks:type_name -> nicecms.media.v1.Stack
-	18, // 13: nicecms.media.v1.Stack.id:type_name -> nicecms.common.v1.UUID
-	12, // 14: nicecms.media.v1.Stack.images:type_name -> nicecms.media.v1.StackImage
-	1,  // 15: nicecms.media.v1.StackImage.image:type_name -> nicecms.media.v1.StorageImage
-	18, // 16: nicecms.media.v1.SortGalleryReq.id:type_name -> nicecms.common.v1.UUID
-	18, // 17: nicecms.media.v1.SortGalleryReq.sorting:type_name -> nicecms.common.v1.UUID
-	18, // 18: nicecms.media.v1.UploadDocumentReq.UploadDocumentMetadata.shelfId:type_name -> nicecms.common.v1.UUID
-	18, // 19: nicecms.media.v1.ReplaceDocumentReq.ReplaceDocumentMetadata.shelfId:type_name -> nicecms.common.v1.UUID
-	18, // 20: nicecms.media.v1.ReplaceDocumentReq.ReplaceDocumentMetadata.documentId:type_name -> nicecms.common.v1.UUID
-	18, // 21: nicecms.media.v1.UploadImageReq.UploadImageMetadata.galleryId:type_name -> nicecms.common.v1.UUID
-	18, // 22: nicecms.media.v1.ReplaceImageReq.ReplaceImageMetadata.galleryId:type_name -> nicecms.common.v1.UUID
-	18, // 23: nicecms.media.v1.ReplaceImageReq.ReplaceImageMetadata.stackId:type_name -> nicecms.common.v1.UUID
-	19, // 24: nicecms.media.v1.MediaService.LookupShelfByName:input_type -> nicecms.common.v1.NameLookup
-	3,  // 25: nicecms.media.v1.MediaService.UploadDocument:input_type -> nicecms.media.v1.UploadDocumentReq
-	4,  // 26: nicecms.media.v1.MediaService.ReplaceDocument:input_type -> nicecms.media.v1.ReplaceDocumentReq
-	18, // 27: nicecms.media.v1.MediaService.FetchShelf:input_type -> nicecms.common.v1.UUID
-	19, // 28: nicecms.media.v1.MediaService.LookupGalleryByName:input_type -> nicecms.common.v1.NameLookup
-	7,  // 29: nicecms.media.v1.MediaService.LookupGalleryStackByName:input_type -> nicecms.media.v1.LookupGalleryStackByNameReq
-	8,  // 30: nicecms.media.v1.MediaService.UploadImage:input_type -> nicecms.media.v1.UploadImageReq
-	9,  // 31: nicecms.media.v1.MediaService.ReplaceImage:input_type -> nicecms.media.v1.ReplaceImageReq
-	18, // 32: nicecms.media.v1.MediaService.FetchGallery:input_type -> nicecms.common.v1.UUID
-	13, // 33: nicecms.media.v1.MediaService.SortGallery:input_type -> nicecms.media.v1.SortGalleryReq
-	20, // 34: nicecms.media.v1.MediaService.LookupShelfByName:output_type -> nicecms.common.v1.LookupResp
-	6,  // 35: nicecms.media.v1.MediaService.UploadDocument:output_type -> nicecms.media.v1.ShelfDocument
-	6,  // 36: nicecms.media.v1.MediaService.ReplaceDocument:output_type -> nicecms.media.v1.ShelfDocument
-	5,  // 37: nicecms.media.v1.MediaService.FetchShelf:output_type -> nicecms.media.v1.Shelf
-	20, // 38: nicecms.media.v1.MediaService.LookupGalleryByName:output_type -> nicecms.common.v1.LookupResp
-	20, // 39: nicecms.media.v1.MediaService.LookupGalleryStackByName:output_type -> nicecms.common.v1.LookupResp
-	11, // 40: nicecms.media.v1.MediaService.UploadImage:output_type -> nicecms.media.v1.Stack
-	11, // 41: nicecms.media.v1.MediaService.ReplaceImage:output_type -> nicecms.media.v1.Stack
-	10, // 42: nicecms.media.v1.MediaService.FetchGallery:output_type -> nicecms.media.v1.Gallery
-	21, // 43: nicecms.media.v1.MediaService.SortGallery:output_type -> google.protobuf.Empty
-	34, // [34:44] is the sub-list for method output_type
-	24, // [24:34] is the sub-list for method input_type
-	24, // [24:24] is the sub-list for extension type_name
-	24, // [24:24] is the sub-list for extension extendee
-	0,  // [0:24] is the sub-list for field type_name
+	0,   // 0: nicecms.media.v1.StorageImage.file:type_name -> nicecms.media.v1.StorageFile
+	0,   // 1: nicecms.media.v1.StorageDocument.file:type_name -> nicecms.media.v1.StorageFile
+	0,   // 2: nicecms.media.v1.StorageVideo.file:type_name -> nicecms.media.v1.StorageFile
+	52,  // 3: nicecms.media.v1.UploadDocumentReq.metadata:type_name -> nicecms.media.v1.UploadDocumentReq.UploadDocumentMetadata
+	53,  // 4: nicecms.media.v1.ReplaceDocumentReq.metadata:type_name -> nicecms.media.v1.ReplaceDocumentReq.ReplaceDocumentMetadata
+	59,  // 5: nicecms.media.v1.Shelf.id:type_name -> nicecms.common.v1.UUID
+	17,  // 6: nicecms.media.v1.Shelf.documents:type_name -> nicecms.media.v1.ShelfDocument
+	16,  // 7: nicecms.media.v1.Shelf.trashed:type_name -> nicecms.media.v1.TrashedDocument
+	15,  // 8: nicecms.media.v1.Shelf.legal_hold:type_name -> nicecms.media.v1.LegalHold
+	59,  // 9: nicecms.media.v1.ListDocumentsReq.shelf_id:type_name -> nicecms.common.v1.UUID
+	59,  // 10: nicecms.media.v1.DocumentPage.id:type_name -> nicecms.common.v1.UUID
+	17,  // 11: nicecms.media.v1.DocumentPage.documents:type_name -> nicecms.media.v1.ShelfDocument
+	15,  // 12: nicecms.media.v1.DocumentPage.legal_hold:type_name -> nicecms.media.v1.LegalHold
+	11,  // 13: nicecms.media.v1.SearchDocumentsResp.documents:type_name -> nicecms.media.v1.DocumentSearchResult
+	17,  // 14: nicecms.media.v1.DocumentSearchResult.document:type_name -> nicecms.media.v1.ShelfDocument
+	59,  // 15: nicecms.media.v1.DocumentSearchResult.shelf_id:type_name -> nicecms.common.v1.UUID
+	14,  // 16: nicecms.media.v1.ShelfList.shelfs:type_name -> nicecms.media.v1.ShelfRef
+	59,  // 17: nicecms.media.v1.ShelfRef.id:type_name -> nicecms.common.v1.UUID
+	17,  // 18: nicecms.media.v1.TrashedDocument.document:type_name -> nicecms.media.v1.ShelfDocument
+	2,   // 19: nicecms.media.v1.ShelfDocument.document:type_name -> nicecms.media.v1.StorageDocument
+	59,  // 20: nicecms.media.v1.ShelfDocument.id:type_name -> nicecms.common.v1.UUID
+	54,  // 21: nicecms.media.v1.ShelfDocument.variants:type_name -> nicecms.media.v1.ShelfDocument.VariantsEntry
+	19,  // 22: nicecms.media.v1.ShelfDocument.preview:type_name -> nicecms.media.v1.DocumentPreview
+	18,  // 23: nicecms.media.v1.ShelfDocument.audio:type_name -> nicecms.media.v1.AudioMetadata
+	15,  // 24: nicecms.media.v1.ShelfDocument.legal_hold:type_name -> nicecms.media.v1.LegalHold
+	55,  // 25: nicecms.media.v1.ShelfDocument.metadata:type_name -> nicecms.media.v1.ShelfDocument.MetadataEntry
+	1,   // 26: nicecms.media.v1.DocumentPreview.image:type_name -> nicecms.media.v1.StorageImage
+	59,  // 27: nicecms.media.v1.TagDocumentReq.shelf_id:type_name -> nicecms.common.v1.UUID
+	59,  // 28: nicecms.media.v1.TagDocumentReq.document_id:type_name -> nicecms.common.v1.UUID
+	59,  // 29: nicecms.media.v1.RenameDocumentReq.shelf_id:type_name -> nicecms.common.v1.UUID
+	59,  // 30: nicecms.media.v1.RenameDocumentReq.document_id:type_name -> nicecms.common.v1.UUID
+	59,  // 31: nicecms.media.v1.DeleteDocumentReq.shelf_id:type_name -> nicecms.common.v1.UUID
+	59,  // 32: nicecms.media.v1.DeleteDocumentReq.document_id:type_name -> nicecms.common.v1.UUID
+	59,  // 33: nicecms.media.v1.LookupGalleryStackByNameReq.galleryId:type_name -> nicecms.common.v1.UUID
+	59,  // 34: nicecms.media.v1.LookupGalleryStacksByTagReq.galleryId:type_name -> nicecms.common.v1.UUID
+	59,  // 35: nicecms.media.v1.LookupGalleryStacksByTagResp.stackIds:type_name -> nicecms.common.v1.UUID
+	56,  // 36: nicecms.media.v1.UploadImageReq.metadata:type_name -> nicecms.media.v1.UploadImageReq.UploadImageMetadata
+	57,  // 37: nicecms.media.v1.ReplaceImageReq.metadata:type_name -> nicecms.media.v1.ReplaceImageReq.ReplaceImageMetadata
+	59,  // 38: nicecms.media.v1.Gallery.id:type_name -> nicecms.common.v1.UUID
+	41,  // 39: nicecms.media.v1.Gallery.stacks:type_name -> nicecms.media.v1.Stack
+	37,  // 40: nicecms.media.v1.Gallery.theme:type_name -> nicecms.media.v1.GalleryTheme
+	36,  // 41: nicecms.media.v1.Gallery.trashed:type_name -> nicecms.media.v1.TrashedStack
+	15,  // 42: nicecms.media.v1.Gallery.legal_hold:type_name -> nicecms.media.v1.LegalHold
+	59,  // 43: nicecms.media.v1.StreamGalleryStacksReq.gallery_id:type_name -> nicecms.common.v1.UUID
+	28,  // 44: nicecms.media.v1.GalleryStacksChunk.gallery:type_name -> nicecms.media.v1.Gallery
+	41,  // 45: nicecms.media.v1.GalleryStacksChunk.stacks:type_name -> nicecms.media.v1.Stack
+	59,  // 46: nicecms.media.v1.ListStacksReq.gallery_id:type_name -> nicecms.common.v1.UUID
+	59,  // 47: nicecms.media.v1.StackPage.id:type_name -> nicecms.common.v1.UUID
+	41,  // 48: nicecms.media.v1.StackPage.stacks:type_name -> nicecms.media.v1.Stack
+	15,  // 49: nicecms.media.v1.StackPage.legal_hold:type_name -> nicecms.media.v1.LegalHold
+	35,  // 50: nicecms.media.v1.GalleryList.galleries:type_name -> nicecms.media.v1.GalleryRef
+	59,  // 51: nicecms.media.v1.GalleryRef.id:type_name -> nicecms.common.v1.UUID
+	41,  // 52: nicecms.media.v1.TrashedStack.stack:type_name -> nicecms.media.v1.Stack
+	59,  // 53: nicecms.media.v1.GalleryIndex.id:type_name -> nicecms.common.v1.UUID
+	39,  // 54: nicecms.media.v1.GalleryIndex.stacks:type_name -> nicecms.media.v1.StackSummary
+	59,  // 55: nicecms.media.v1.StackSummary.id:type_name -> nicecms.common.v1.UUID
+	47,  // 56: nicecms.media.v1.StackSummary.thumbnail:type_name -> nicecms.media.v1.StackImage
+	59,  // 57: nicecms.media.v1.FetchStackReq.gallery_id:type_name -> nicecms.common.v1.UUID
+	59,  // 58: nicecms.media.v1.FetchStackReq.stack_id:type_name -> nicecms.common.v1.UUID
+	59,  // 59: nicecms.media.v1.Stack.id:type_name -> nicecms.common.v1.UUID
+	47,  // 60: nicecms.media.v1.Stack.images:type_name -> nicecms.media.v1.StackImage
+	45,  // 61: nicecms.media.v1.Stack.metadata:type_name -> nicecms.media.v1.StackMetadata
+	3,   // 62: nicecms.media.v1.Stack.video:type_name -> nicecms.media.v1.StorageVideo
+	15,  // 63: nicecms.media.v1.Stack.legal_hold:type_name -> nicecms.media.v1.LegalHold
+	44,  // 64: nicecms.media.v1.Stack.versions:type_name -> nicecms.media.v1.StackVersion
+	58,  // 65: nicecms.media.v1.Stack.custom_metadata:type_name -> nicecms.media.v1.Stack.CustomMetadataEntry
+	42,  // 66: nicecms.media.v1.Stack.focal_point:type_name -> nicecms.media.v1.FocalPoint
+	43,  // 67: nicecms.media.v1.Stack.crop:type_name -> nicecms.media.v1.Area
+	47,  // 68: nicecms.media.v1.StackVersion.images:type_name -> nicecms.media.v1.StackImage
+	3,   // 69: nicecms.media.v1.StackVersion.video:type_name -> nicecms.media.v1.StorageVideo
+	46,  // 70: nicecms.media.v1.StackMetadata.gps:type_name -> nicecms.media.v1.GPS
+	1,   // 71: nicecms.media.v1.StackImage.image:type_name -> nicecms.media.v1.StorageImage
+	59,  // 72: nicecms.media.v1.SortGalleryReq.id:type_name -> nicecms.common.v1.UUID
+	59,  // 73: nicecms.media.v1.SortGalleryReq.sorting:type_name -> nicecms.common.v1.UUID
+	59,  // 74: nicecms.media.v1.TagStackReq.gallery_id:type_name -> nicecms.common.v1.UUID
+	59,  // 75: nicecms.media.v1.TagStackReq.stack_id:type_name -> nicecms.common.v1.UUID
+	59,  // 76: nicecms.media.v1.RenameStackReq.gallery_id:type_name -> nicecms.common.v1.UUID
+	59,  // 77: nicecms.media.v1.RenameStackReq.stack_id:type_name -> nicecms.common.v1.UUID
+	59,  // 78: nicecms.media.v1.DeleteStackReq.gallery_id:type_name -> nicecms.common.v1.UUID
+	59,  // 79: nicecms.media.v1.DeleteStackReq.stack_id:type_name -> nicecms.common.v1.UUID
+	59,  // 80: nicecms.media.v1.UploadDocumentReq.UploadDocumentMetadata.shelfId:type_name -> nicecms.common.v1.UUID
+	59,  // 81: nicecms.media.v1.ReplaceDocumentReq.ReplaceDocumentMetadata.shelfId:type_name -> nicecms.common.v1.UUID
+	59,  // 82: nicecms.media.v1.ReplaceDocumentReq.ReplaceDocumentMetadata.documentId:type_name -> nicecms.common.v1.UUID
+	2,   // 83: nicecms.media.v1.ShelfDocument.VariantsEntry.value:type_name -> nicecms.media.v1.StorageDocument
+	59,  // 84: nicecms.media.v1.UploadImageReq.UploadImageMetadata.galleryId:type_name -> nicecms.common.v1.UUID
+	59,  // 85: nicecms.media.v1.ReplaceImageReq.ReplaceImageMetadata.galleryId:type_name -> nicecms.common.v1.UUID
+	59,  // 86: nicecms.media.v1.ReplaceImageReq.ReplaceImageMetadata.stackId:type_name -> nicecms.common.v1.UUID
+	60,  // 87: nicecms.media.v1.MediaService.LookupShelfByName:input_type -> nicecms.common.v1.NameLookup
+	4,   // 88: nicecms.media.v1.MediaService.UploadDocument:input_type -> nicecms.media.v1.UploadDocumentReq
+	5,   // 89: nicecms.media.v1.MediaService.ReplaceDocument:input_type -> nicecms.media.v1.ReplaceDocumentReq
+	5,   // 90: nicecms.media.v1.MediaService.ReplaceDocumentDelta:input_type -> nicecms.media.v1.ReplaceDocumentReq
+	59,  // 91: nicecms.media.v1.MediaService.FetchShelf:input_type -> nicecms.common.v1.UUID
+	7,   // 92: nicecms.media.v1.MediaService.ListDocuments:input_type -> nicecms.media.v1.ListDocumentsReq
+	9,   // 93: nicecms.media.v1.MediaService.SearchDocuments:input_type -> nicecms.media.v1.SearchDocumentsReq
+	12,  // 94: nicecms.media.v1.MediaService.ListShelfs:input_type -> nicecms.media.v1.ListShelfsReq
+	20,  // 95: nicecms.media.v1.MediaService.TagDocument:input_type -> nicecms.media.v1.TagDocumentReq
+	20,  // 96: nicecms.media.v1.MediaService.UntagDocument:input_type -> nicecms.media.v1.TagDocumentReq
+	21,  // 97: nicecms.media.v1.MediaService.RenameDocument:input_type -> nicecms.media.v1.RenameDocumentReq
+	22,  // 98: nicecms.media.v1.MediaService.DeleteDocument:input_type -> nicecms.media.v1.DeleteDocumentReq
+	60,  // 99: nicecms.media.v1.MediaService.LookupGalleryByName:input_type -> nicecms.common.v1.NameLookup
+	23,  // 100: nicecms.media.v1.MediaService.LookupGalleryStackByName:input_type -> nicecms.media.v1.LookupGalleryStackByNameReq
+	24,  // 101: nicecms.media.v1.MediaService.LookupGalleryStacksByTag:input_type -> nicecms.media.v1.LookupGalleryStacksByTagReq
+	26,  // 102: nicecms.media.v1.MediaService.UploadImage:input_type -> nicecms.media.v1.UploadImageReq
+	27,  // 103: nicecms.media.v1.MediaService.ReplaceImage:input_type -> nicecms.media.v1.ReplaceImageReq
+	59,  // 104: nicecms.media.v1.MediaService.FetchGallery:input_type -> nicecms.common.v1.UUID
+	29,  // 105: nicecms.media.v1.MediaService.StreamGalleryStacks:input_type -> nicecms.media.v1.StreamGalleryStacksReq
+	59,  // 106: nicecms.media.v1.MediaService.FetchGalleryIndex:input_type -> nicecms.common.v1.UUID
+	31,  // 107: nicecms.media.v1.MediaService.ListStacks:input_type -> nicecms.media.v1.ListStacksReq
+	33,  // 108: nicecms.media.v1.MediaService.ListGalleries:input_type -> nicecms.media.v1.ListGalleriesReq
+	40,  // 109: nicecms.media.v1.MediaService.FetchStack:input_type -> nicecms.media.v1.FetchStackReq
+	48,  // 110: nicecms.media.v1.MediaService.SortGallery:input_type -> nicecms.media.v1.SortGalleryReq
+	49,  // 111: nicecms.media.v1.MediaService.TagStack:input_type -> nicecms.media.v1.TagStackReq
+	49,  // 112: nicecms.media.v1.MediaService.UntagStack:input_type -> nicecms.media.v1.TagStackReq
+	50,  // 113: nicecms.media.v1.MediaService.RenameStack:input_type -> nicecms.media.v1.RenameStackReq
+	51,  // 114: nicecms.media.v1.MediaService.DeleteStack:input_type -> nicecms.media.v1.DeleteStackReq
+	61,  // 115: nicecms.media.v1.MediaService.LookupShelfByName:output_type -> nicecms.common.v1.LookupResp
+	17,  // 116: nicecms.media.v1.MediaService.UploadDocument:output_type -> nicecms.media.v1.ShelfDocument
+	17,  // 117: nicecms.media.v1.MediaService.ReplaceDocument:output_type -> nicecms.media.v1.ShelfDocument
+	17,  // 118: nicecms.media.v1.MediaService.ReplaceDocumentDelta:output_type -> nicecms.media.v1.ShelfDocument
+	6,   // 119: nicecms.media.v1.MediaService.FetchShelf:output_type -> nicecms.media.v1.Shelf
+	8,   // 120: nicecms.media.v1.MediaService.ListDocuments:output_type -> nicecms.media.v1.DocumentPage
+	10,  // 121: nicecms.media.v1.MediaService.SearchDocuments:output_type -> nicecms.media.v1.SearchDocumentsResp
+	13,  // 122: nicecms.media.v1.MediaService.ListShelfs:output_type -> nicecms.media.v1.ShelfList
+	17,  // 123: nicecms.media.v1.MediaService.TagDocument:output_type -> nicecms.media.v1.ShelfDocument
+	17,  // 124: nicecms.media.v1.MediaService.UntagDocument:output_type -> nicecms.media.v1.ShelfDocument
+	17,  // 125: nicecms.media.v1.MediaService.RenameDocument:output_type -> nicecms.media.v1.ShelfDocument
+	62,  // 126: nicecms.media.v1.MediaService.DeleteDocument:output_type -> google.protobuf.Empty
+	61,  // 127: nicecms.media.v1.MediaService.LookupGalleryByName:output_type -> nicecms.common.v1.LookupResp
+	61,  // 128: nicecms.media.v1.MediaService.LookupGalleryStackByName:output_type -> nicecms.common.v1.LookupResp
+	25,  // 129: nicecms.media.v1.MediaService.LookupGalleryStacksByTag:output_type -> nicecms.media.v1.LookupGalleryStacksByTagResp
+	41,  // 130: nicecms.media.v1.MediaService.UploadImage:output_type -> nicecms.media.v1.Stack
+	41,  // 131: nicecms.media.v1.MediaService.ReplaceImage:output_type -> nicecms.media.v1.Stack
+	28,  // 132: nicecms.media.v1.MediaService.FetchGallery:output_type -> nicecms.media.v1.Gallery
+	30,  // 133: nicecms.media.v1.MediaService.StreamGalleryStacks:output_type -> nicecms.media.v1.GalleryStacksChunk
+	38,  // 134: nicecms.media.v1.MediaService.FetchGalleryIndex:output_type -> nicecms.media.v1.GalleryIndex
+	32,  // 135: nicecms.media.v1.MediaService.ListStacks:output_type -> nicecms.media.v1.StackPage
+	34,  // 136: nicecms.media.v1.MediaService.ListGalleries:output_type -> nicecms.media.v1.GalleryList
+	41,  // 137: nicecms.media.v1.MediaService.FetchStack:output_type -> nicecms.media.v1.Stack
+	62,  // 138: nicecms.media.v1.MediaService.SortGallery:output_type -> google.protobuf.Empty
+	41,  // 139: nicecms.media.v1.MediaService.TagStack:output_type -> nicecms.media.v1.Stack
+	41,  // 140: nicecms.media.v1.MediaService.UntagStack:output_type -> nicecms.media.v1.Stack
+	41,  // 141: nicecms.media.v1.MediaService.RenameStack:output_type -> nicecms.media.v1.Stack
+	62,  // 142: nicecms.media.v1.MediaService.DeleteStack:output_type -> google.protobuf.Empty
+	115, // [115:143] is the sub-list for method output_type
+	87,  // [87:115] is the sub-list for method input_type
+	87,  // [87:87] is the sub-list for extension type_name
+	87,  // [87:87] is the sub-list for extension extendee
+	0,   // [0:87] is the sub-list for field type_name
 }
 
 func init() { file_media_proto_init() }
@@ -1527,8 +5053,200 @@ func file_media_proto_init() {
 				return nil
 			}
 		}
-		file_media_proto_msgTypes[1].Exporter = func(v any, i int) any {
-			switch v := v.(*StorageImage); i {
+		file_media_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*StorageImage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*StorageDocument); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*StorageVideo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*UploadDocumentReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*ReplaceDocumentReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*Shelf); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*ListDocumentsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*DocumentPage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*SearchDocumentsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*SearchDocumentsResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*DocumentSearchResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*ListShelfsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*ShelfList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*ShelfRef); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*LegalHold); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*TrashedDocument); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*ShelfDocument); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1539,8 +5257,8 @@ func file_media_proto_init() {
 				return nil
 			}
 		}
-		file_media_proto_msgTypes[2].Exporter = func(v any, i int) any {
-			switch v := v.(*StorageDocument); i {
+		file_media_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*AudioMetadata); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1551,8 +5269,8 @@ func file_media_proto_init() {
 				return nil
 			}
 		}
-		file_media_proto_msgTypes[3].Exporter = func(v any, i int) any {
-			switch v := v.(*UploadDocumentReq); i {
+		file_media_proto_msgTypes[19].Exporter = func(v any, i int) any {
+			switch v := v.(*DocumentPreview); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1563,8 +5281,8 @@ func file_media_proto_init() {
 				return nil
 			}
 		}
-		file_media_proto_msgTypes[4].Exporter = func(v any, i int) any {
-			switch v := v.(*ReplaceDocumentReq); i {
+		file_media_proto_msgTypes[20].Exporter = func(v any, i int) any {
+			switch v := v.(*TagDocumentReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1575,8 +5293,8 @@ func file_media_proto_init() {
 				return nil
 			}
 		}
-		file_media_proto_msgTypes[5].Exporter = func(v any, i int) any {
-			switch v := v.(*Shelf); i {
+		file_media_proto_msgTypes[21].Exporter = func(v any, i int) any {
+			switch v := v.(*RenameDocumentReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1587,8 +5305,8 @@ func file_media_proto_init() {
 				return nil
 			}
 		}
-		file_media_proto_msgTypes[6].Exporter = func(v any, i int) any {
-			switch v := v.(*ShelfDocument); i {
+		file_media_proto_msgTypes[22].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteDocumentReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1599,7 +5317,7 @@ func file_media_proto_init() {
 				return nil
 			}
 		}
-		file_media_proto_msgTypes[7].Exporter = func(v any, i int) any {
+		file_media_proto_msgTypes[23].Exporter = func(v any, i int) any {
 			switch v := v.(*LookupGalleryStackByNameReq); i {
 			case 0:
 				return &v.state
@@ -1611,7 +5329,31 @@ func file_media_proto_init() {
 				return nil
 			}
 		}
-		file_media_proto_msgTypes[8].Exporter = func(v any, i int) any {
+		file_media_proto_msgTypes[24].Exporter = func(v any, i int) any {
+			switch v := v.(*LookupGalleryStacksByTagReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[25].Exporter = func(v any, i int) any {
+			switch v := v.(*LookupGalleryStacksByTagResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[26].Exporter = func(v any, i int) any {
 			switch v := v.(*UploadImageReq); i {
 			case 0:
 				return &v.state
@@ -1623,7 +5365,7 @@ func file_media_proto_init() {
 				return nil
 			}
 		}
-		file_media_proto_msgTypes[9].Exporter = func(v any, i int) any {
+		file_media_proto_msgTypes[27].Exporter = func(v any, i int) any {
 			switch v := v.(*ReplaceImageReq); i {
 			case 0:
 				return &v.state
@@ -1635,7 +5377,7 @@ func file_media_proto_init() {
 				return nil
 			}
 		}
-		file_media_proto_msgTypes[10].Exporter = func(v any, i int) any {
+		file_media_proto_msgTypes[28].Exporter = func(v any, i int) any {
 			switch v := v.(*Gallery); i {
 			case 0:
 				return &v.state
@@ -1647,7 +5389,151 @@ func file_media_proto_init() {
 				return nil
 			}
 		}
-		file_media_proto_msgTypes[11].Exporter = func(v any, i int) any {
+		file_media_proto_msgTypes[29].Exporter = func(v any, i int) any {
+			switch v := v.(*StreamGalleryStacksReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[30].Exporter = func(v any, i int) any {
+			switch v := v.(*GalleryStacksChunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[31].Exporter = func(v any, i int) any {
+			switch v := v.(*ListStacksReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[32].Exporter = func(v any, i int) any {
+			switch v := v.(*StackPage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[33].Exporter = func(v any, i int) any {
+			switch v := v.(*ListGalleriesReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[34].Exporter = func(v any, i int) any {
+			switch v := v.(*GalleryList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[35].Exporter = func(v any, i int) any {
+			switch v := v.(*GalleryRef); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[36].Exporter = func(v any, i int) any {
+			switch v := v.(*TrashedStack); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[37].Exporter = func(v any, i int) any {
+			switch v := v.(*GalleryTheme); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[38].Exporter = func(v any, i int) any {
+			switch v := v.(*GalleryIndex); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[39].Exporter = func(v any, i int) any {
+			switch v := v.(*StackSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[40].Exporter = func(v any, i int) any {
+			switch v := v.(*FetchStackReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[41].Exporter = func(v any, i int) any {
 			switch v := v.(*Stack); i {
 			case 0:
 				return &v.state
@@ -1659,7 +5545,67 @@ func file_media_proto_init() {
 				return nil
 			}
 		}
-		file_media_proto_msgTypes[12].Exporter = func(v any, i int) any {
+		file_media_proto_msgTypes[42].Exporter = func(v any, i int) any {
+			switch v := v.(*FocalPoint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[43].Exporter = func(v any, i int) any {
+			switch v := v.(*Area); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[44].Exporter = func(v any, i int) any {
+			switch v := v.(*StackVersion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[45].Exporter = func(v any, i int) any {
+			switch v := v.(*StackMetadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[46].Exporter = func(v any, i int) any {
+			switch v := v.(*GPS); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[47].Exporter = func(v any, i int) any {
 			switch v := v.(*StackImage); i {
 			case 0:
 				return &v.state
@@ -1671,7 +5617,7 @@ func file_media_proto_init() {
 				return nil
 			}
 		}
-		file_media_proto_msgTypes[13].Exporter = func(v any, i int) any {
+		file_media_proto_msgTypes[48].Exporter = func(v any, i int) any {
 			switch v := v.(*SortGalleryReq); i {
 			case 0:
 				return &v.state
@@ -1683,7 +5629,43 @@ func file_media_proto_init() {
 				return nil
 			}
 		}
-		file_media_proto_msgTypes[14].Exporter = func(v any, i int) any {
+		file_media_proto_msgTypes[49].Exporter = func(v any, i int) any {
+			switch v := v.(*TagStackReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[50].Exporter = func(v any, i int) any {
+			switch v := v.(*RenameStackReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[51].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteStackReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_media_proto_msgTypes[52].Exporter = func(v any, i int) any {
 			switch v := v.(*UploadDocumentReq_UploadDocumentMetadata); i {
 			case 0:
 				return &v.state
@@ -1695,7 +5677,7 @@ func file_media_proto_init() {
 				return nil
 			}
 		}
-		file_media_proto_msgTypes[15].Exporter = func(v any, i int) any {
+		file_media_proto_msgTypes[53].Exporter = func(v any, i int) any {
 			switch v := v.(*ReplaceDocumentReq_ReplaceDocumentMetadata); i {
 			case 0:
 				return &v.state
@@ -1707,7 +5689,7 @@ func file_media_proto_init() {
 				return nil
 			}
 		}
-		file_media_proto_msgTypes[16].Exporter = func(v any, i int) any {
+		file_media_proto_msgTypes[56].Exporter = func(v any, i int) any {
 			switch v := v.(*UploadImageReq_UploadImageMetadata); i {
 			case 0:
 				return &v.state
@@ -1719,7 +5701,7 @@ func file_media_proto_init() {
 				return nil
 			}
 		}
-		file_media_proto_msgTypes[17].Exporter = func(v any, i int) any {
+		file_media_proto_msgTypes[57].Exporter = func(v any, i int) any {
 			switch v := v.(*ReplaceImageReq_ReplaceImageMetadata); i {
 			case 0:
 				return &v.state
@@ -1732,19 +5714,19 @@ func file_media_proto_init() {
 			}
 		}
 	}
-	file_media_proto_msgTypes[3].OneofWrappers = []any{
+	file_media_proto_msgTypes[4].OneofWrappers = []any{
 		(*UploadDocumentReq_Metadata)(nil),
 		(*UploadDocumentReq_Chunk)(nil),
 	}
-	file_media_proto_msgTypes[4].OneofWrappers = []any{
+	file_media_proto_msgTypes[5].OneofWrappers = []any{
 		(*ReplaceDocumentReq_Metadata)(nil),
 		(*ReplaceDocumentReq_Chunk)(nil),
 	}
-	file_media_proto_msgTypes[8].OneofWrappers = []any{
+	file_media_proto_msgTypes[26].OneofWrappers = []any{
 		(*UploadImageReq_Metadata)(nil),
 		(*UploadImageReq_Chunk)(nil),
 	}
-	file_media_proto_msgTypes[9].OneofWrappers = []any{
+	file_media_proto_msgTypes[27].OneofWrappers = []any{
 		(*ReplaceImageReq_Metadata)(nil),
 		(*ReplaceImageReq_Chunk)(nil),
 	}
@@ -1754,7 +5736,7 @@ func file_media_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_media_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   18,
+			NumMessages:   59,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
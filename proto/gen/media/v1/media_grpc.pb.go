@@ -23,13 +23,31 @@ type MediaServiceClient interface {
 	LookupShelfByName(ctx context.Context, in *v1.NameLookup, opts ...grpc.CallOption) (*v1.LookupResp, error)
 	UploadDocument(ctx context.Context, opts ...grpc.CallOption) (MediaService_UploadDocumentClient, error)
 	ReplaceDocument(ctx context.Context, opts ...grpc.CallOption) (MediaService_ReplaceDocumentClient, error)
+	ReplaceDocumentDelta(ctx context.Context, opts ...grpc.CallOption) (MediaService_ReplaceDocumentDeltaClient, error)
 	FetchShelf(ctx context.Context, in *v1.UUID, opts ...grpc.CallOption) (*Shelf, error)
+	ListDocuments(ctx context.Context, in *ListDocumentsReq, opts ...grpc.CallOption) (*DocumentPage, error)
+	SearchDocuments(ctx context.Context, in *SearchDocumentsReq, opts ...grpc.CallOption) (*SearchDocumentsResp, error)
+	ListShelfs(ctx context.Context, in *ListShelfsReq, opts ...grpc.CallOption) (*ShelfList, error)
+	TagDocument(ctx context.Context, in *TagDocumentReq, opts ...grpc.CallOption) (*ShelfDocument, error)
+	UntagDocument(ctx context.Context, in *TagDocumentReq, opts ...grpc.CallOption) (*ShelfDocument, error)
+	RenameDocument(ctx context.Context, in *RenameDocumentReq, opts ...grpc.CallOption) (*ShelfDocument, error)
+	DeleteDocument(ctx context.Context, in *DeleteDocumentReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	LookupGalleryByName(ctx context.Context, in *v1.NameLookup, opts ...grpc.CallOption) (*v1.LookupResp, error)
 	LookupGalleryStackByName(ctx context.Context, in *LookupGalleryStackByNameReq, opts ...grpc.CallOption) (*v1.LookupResp, error)
+	LookupGalleryStacksByTag(ctx context.Context, in *LookupGalleryStacksByTagReq, opts ...grpc.CallOption) (*LookupGalleryStacksByTagResp, error)
 	UploadImage(ctx context.Context, opts ...grpc.CallOption) (MediaService_UploadImageClient, error)
 	ReplaceImage(ctx context.Context, opts ...grpc.CallOption) (MediaService_ReplaceImageClient, error)
 	FetchGallery(ctx context.Context, in *v1.UUID, opts ...grpc.CallOption) (*Gallery, error)
+	StreamGalleryStacks(ctx context.Context, in *StreamGalleryStacksReq, opts ...grpc.CallOption) (MediaService_StreamGalleryStacksClient, error)
+	FetchGalleryIndex(ctx context.Context, in *v1.UUID, opts ...grpc.CallOption) (*GalleryIndex, error)
+	ListStacks(ctx context.Context, in *ListStacksReq, opts ...grpc.CallOption) (*StackPage, error)
+	ListGalleries(ctx context.Context, in *ListGalleriesReq, opts ...grpc.CallOption) (*GalleryList, error)
+	FetchStack(ctx context.Context, in *FetchStackReq, opts ...grpc.CallOption) (*Stack, error)
 	SortGallery(ctx context.Context, in *SortGalleryReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	TagStack(ctx context.Context, in *TagStackReq, opts ...grpc.CallOption) (*Stack, error)
+	UntagStack(ctx context.Context, in *TagStackReq, opts ...grpc.CallOption) (*Stack, error)
+	RenameStack(ctx context.Context, in *RenameStackReq, opts ...grpc.CallOption) (*Stack, error)
+	DeleteStack(ctx context.Context, in *DeleteStackReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type mediaServiceClient struct {
@@ -117,6 +135,40 @@ func (x *mediaServiceReplaceDocumentClient) CloseAndRecv() (*ShelfDocument, erro
 	return m, nil
 }
 
+func (c *mediaServiceClient) ReplaceDocumentDelta(ctx context.Context, opts ...grpc.CallOption) (MediaService_ReplaceDocumentDeltaClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MediaService_ServiceDesc.Streams[2], "/nicecms.media.v1.MediaService/ReplaceDocumentDelta", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mediaServiceReplaceDocumentDeltaClient{stream}
+	return x, nil
+}
+
+type MediaService_ReplaceDocumentDeltaClient interface {
+	Send(*ReplaceDocumentReq) error
+	CloseAndRecv() (*ShelfDocument, error)
+	grpc.ClientStream
+}
+
+type mediaServiceReplaceDocumentDeltaClient struct {
+	grpc.ClientStream
+}
+
+func (x *mediaServiceReplaceDocumentDeltaClient) Send(m *ReplaceDocumentReq) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *mediaServiceReplaceDocumentDeltaClient) CloseAndRecv() (*ShelfDocument, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ShelfDocument)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *mediaServiceClient) FetchShelf(ctx context.Context, in *v1.UUID, opts ...grpc.CallOption) (*Shelf, error) {
 	out := new(Shelf)
 	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/FetchShelf", in, out, opts...)
@@ -126,6 +178,69 @@ func (c *mediaServiceClient) FetchShelf(ctx context.Context, in *v1.UUID, opts .
 	return out, nil
 }
 
+func (c *mediaServiceClient) ListDocuments(ctx context.Context, in *ListDocumentsReq, opts ...grpc.CallOption) (*DocumentPage, error) {
+	out := new(DocumentPage)
+	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/ListDocuments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) SearchDocuments(ctx context.Context, in *SearchDocumentsReq, opts ...grpc.CallOption) (*SearchDocumentsResp, error) {
+	out := new(SearchDocumentsResp)
+	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/SearchDocuments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) ListShelfs(ctx context.Context, in *ListShelfsReq, opts ...grpc.CallOption) (*ShelfList, error) {
+	out := new(ShelfList)
+	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/ListShelfs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) TagDocument(ctx context.Context, in *TagDocumentReq, opts ...grpc.CallOption) (*ShelfDocument, error) {
+	out := new(ShelfDocument)
+	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/TagDocument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) UntagDocument(ctx context.Context, in *TagDocumentReq, opts ...grpc.CallOption) (*ShelfDocument, error) {
+	out := new(ShelfDocument)
+	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/UntagDocument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) RenameDocument(ctx context.Context, in *RenameDocumentReq, opts ...grpc.CallOption) (*ShelfDocument, error) {
+	out := new(ShelfDocument)
+	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/RenameDocument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) DeleteDocument(ctx context.Context, in *DeleteDocumentReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/DeleteDocument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mediaServiceClient) LookupGalleryByName(ctx context.Context, in *v1.NameLookup, opts ...grpc.CallOption) (*v1.LookupResp, error) {
 	out := new(v1.LookupResp)
 	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/LookupGalleryByName", in, out, opts...)
@@ -144,8 +259,17 @@ func (c *mediaServiceClient) LookupGalleryStackByName(ctx context.Context, in *L
 	return out, nil
 }
 
+func (c *mediaServiceClient) LookupGalleryStacksByTag(ctx context.Context, in *LookupGalleryStacksByTagReq, opts ...grpc.CallOption) (*LookupGalleryStacksByTagResp, error) {
+	out := new(LookupGalleryStacksByTagResp)
+	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/LookupGalleryStacksByTag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mediaServiceClient) UploadImage(ctx context.Context, opts ...grpc.CallOption) (MediaService_UploadImageClient, error) {
-	stream, err := c.cc.NewStream(ctx, &MediaService_ServiceDesc.Streams[2], "/nicecms.media.v1.MediaService/UploadImage", opts...)
+	stream, err := c.cc.NewStream(ctx, &MediaService_ServiceDesc.Streams[3], "/nicecms.media.v1.MediaService/UploadImage", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -179,7 +303,7 @@ func (x *mediaServiceUploadImageClient) CloseAndRecv() (*Stack, error) {
 }
 
 func (c *mediaServiceClient) ReplaceImage(ctx context.Context, opts ...grpc.CallOption) (MediaService_ReplaceImageClient, error) {
-	stream, err := c.cc.NewStream(ctx, &MediaService_ServiceDesc.Streams[3], "/nicecms.media.v1.MediaService/ReplaceImage", opts...)
+	stream, err := c.cc.NewStream(ctx, &MediaService_ServiceDesc.Streams[4], "/nicecms.media.v1.MediaService/ReplaceImage", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -221,6 +345,74 @@ func (c *mediaServiceClient) FetchGallery(ctx context.Context, in *v1.UUID, opts
 	return out, nil
 }
 
+func (c *mediaServiceClient) StreamGalleryStacks(ctx context.Context, in *StreamGalleryStacksReq, opts ...grpc.CallOption) (MediaService_StreamGalleryStacksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MediaService_ServiceDesc.Streams[5], "/nicecms.media.v1.MediaService/StreamGalleryStacks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mediaServiceStreamGalleryStacksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MediaService_StreamGalleryStacksClient interface {
+	Recv() (*GalleryStacksChunk, error)
+	grpc.ClientStream
+}
+
+type mediaServiceStreamGalleryStacksClient struct {
+	grpc.ClientStream
+}
+
+func (x *mediaServiceStreamGalleryStacksClient) Recv() (*GalleryStacksChunk, error) {
+	m := new(GalleryStacksChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mediaServiceClient) FetchGalleryIndex(ctx context.Context, in *v1.UUID, opts ...grpc.CallOption) (*GalleryIndex, error) {
+	out := new(GalleryIndex)
+	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/FetchGalleryIndex", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) ListStacks(ctx context.Context, in *ListStacksReq, opts ...grpc.CallOption) (*StackPage, error) {
+	out := new(StackPage)
+	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/ListStacks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) ListGalleries(ctx context.Context, in *ListGalleriesReq, opts ...grpc.CallOption) (*GalleryList, error) {
+	out := new(GalleryList)
+	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/ListGalleries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) FetchStack(ctx context.Context, in *FetchStackReq, opts ...grpc.CallOption) (*Stack, error) {
+	out := new(Stack)
+	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/FetchStack", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mediaServiceClient) SortGallery(ctx context.Context, in *SortGalleryReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/SortGallery", in, out, opts...)
@@ -230,6 +422,42 @@ func (c *mediaServiceClient) SortGallery(ctx context.Context, in *SortGalleryReq
 	return out, nil
 }
 
+func (c *mediaServiceClient) TagStack(ctx context.Context, in *TagStackReq, opts ...grpc.CallOption) (*Stack, error) {
+	out := new(Stack)
+	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/TagStack", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) UntagStack(ctx context.Context, in *TagStackReq, opts ...grpc.CallOption) (*Stack, error) {
+	out := new(Stack)
+	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/UntagStack", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) RenameStack(ctx context.Context, in *RenameStackReq, opts ...grpc.CallOption) (*Stack, error) {
+	out := new(Stack)
+	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/RenameStack", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) DeleteStack(ctx context.Context, in *DeleteStackReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/nicecms.media.v1.MediaService/DeleteStack", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MediaServiceServer is the server API for MediaService service.
 // All implementations must embed UnimplementedMediaServiceServer
 // for forward compatibility
@@ -237,13 +465,31 @@ type MediaServiceServer interface {
 	LookupShelfByName(context.Context, *v1.NameLookup) (*v1.LookupResp, error)
 	UploadDocument(MediaService_UploadDocumentServer) error
 	ReplaceDocument(MediaService_ReplaceDocumentServer) error
+	ReplaceDocumentDelta(MediaService_ReplaceDocumentDeltaServer) error
 	FetchShelf(context.Context, *v1.UUID) (*Shelf, error)
+	ListDocuments(context.Context, *ListDocumentsReq) (*DocumentPage, error)
+	SearchDocuments(context.Context, *SearchDocumentsReq) (*SearchDocumentsResp, error)
+	ListShelfs(context.Context, *ListShelfsReq) (*ShelfList, error)
+	TagDocument(context.Context, *TagDocumentReq) (*ShelfDocument, error)
+	UntagDocument(context.Context, *TagDocumentReq) (*ShelfDocument, error)
+	RenameDocument(context.Context, *RenameDocumentReq) (*ShelfDocument, error)
+	DeleteDocument(context.Context, *DeleteDocumentReq) (*emptypb.Empty, error)
 	LookupGalleryByName(context.Context, *v1.NameLookup) (*v1.LookupResp, error)
 	LookupGalleryStackByName(context.Context, *LookupGalleryStackByNameReq) (*v1.LookupResp, error)
+	LookupGalleryStacksByTag(context.Context, *LookupGalleryStacksByTagReq) (*LookupGalleryStacksByTagResp, error)
 	UploadImage(MediaService_UploadImageServer) error
 	ReplaceImage(MediaService_ReplaceImageServer) error
 	FetchGallery(context.Context, *v1.UUID) (*Gallery, error)
+	StreamGalleryStacks(*StreamGalleryStacksReq, MediaService_StreamGalleryStacksServer) error
+	FetchGalleryIndex(context.Context, *v1.UUID) (*GalleryIndex, error)
+	ListStacks(context.Context, *ListStacksReq) (*StackPage, error)
+	ListGalleries(context.Context, *ListGalleriesReq) (*GalleryList, error)
+	FetchStack(context.Context, *FetchStackReq) (*Stack, error)
 	SortGallery(context.Context, *SortGalleryReq) (*emptypb.Empty, error)
+	TagStack(context.Context, *TagStackReq) (*Stack, error)
+	UntagStack(context.Context, *TagStackReq) (*Stack, error)
+	RenameStack(context.Context, *RenameStackReq) (*Stack, error)
+	DeleteStack(context.Context, *DeleteStackReq) (*emptypb.Empty, error)
 	mustEmbedUnimplementedMediaServiceServer()
 }
 
@@ -260,15 +506,42 @@ func (UnimplementedMediaServiceServer) UploadDocument(MediaService_UploadDocumen
 func (UnimplementedMediaServiceServer) ReplaceDocument(MediaService_ReplaceDocumentServer) error {
 	return status.Errorf(codes.Unimplemented, "method ReplaceDocument not implemented")
 }
+func (UnimplementedMediaServiceServer) ReplaceDocumentDelta(MediaService_ReplaceDocumentDeltaServer) error {
+	return status.Errorf(codes.Unimplemented, "method ReplaceDocumentDelta not implemented")
+}
 func (UnimplementedMediaServiceServer) FetchShelf(context.Context, *v1.UUID) (*Shelf, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FetchShelf not implemented")
 }
+func (UnimplementedMediaServiceServer) ListDocuments(context.Context, *ListDocumentsReq) (*DocumentPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDocuments not implemented")
+}
+func (UnimplementedMediaServiceServer) SearchDocuments(context.Context, *SearchDocumentsReq) (*SearchDocumentsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchDocuments not implemented")
+}
+func (UnimplementedMediaServiceServer) ListShelfs(context.Context, *ListShelfsReq) (*ShelfList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListShelfs not implemented")
+}
+func (UnimplementedMediaServiceServer) TagDocument(context.Context, *TagDocumentReq) (*ShelfDocument, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TagDocument not implemented")
+}
+func (UnimplementedMediaServiceServer) UntagDocument(context.Context, *TagDocumentReq) (*ShelfDocument, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UntagDocument not implemented")
+}
+func (UnimplementedMediaServiceServer) RenameDocument(context.Context, *RenameDocumentReq) (*ShelfDocument, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameDocument not implemented")
+}
+func (UnimplementedMediaServiceServer) DeleteDocument(context.Context, *DeleteDocumentReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDocument not implemented")
+}
 func (UnimplementedMediaServiceServer) LookupGalleryByName(context.Context, *v1.NameLookup) (*v1.LookupResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LookupGalleryByName not implemented")
 }
 func (UnimplementedMediaServiceServer) LookupGalleryStackByName(context.Context, *LookupGalleryStackByNameReq) (*v1.LookupResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LookupGalleryStackByName not implemented")
 }
+func (UnimplementedMediaServiceServer) LookupGalleryStacksByTag(context.Context, *LookupGalleryStacksByTagReq) (*LookupGalleryStacksByTagResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupGalleryStacksByTag not implemented")
+}
 func (UnimplementedMediaServiceServer) UploadImage(MediaService_UploadImageServer) error {
 	return status.Errorf(codes.Unimplemented, "method UploadImage not implemented")
 }
@@ -278,9 +551,36 @@ func (UnimplementedMediaServiceServer) ReplaceImage(MediaService_ReplaceImageSer
 func (UnimplementedMediaServiceServer) FetchGallery(context.Context, *v1.UUID) (*Gallery, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FetchGallery not implemented")
 }
+func (UnimplementedMediaServiceServer) StreamGalleryStacks(*StreamGalleryStacksReq, MediaService_StreamGalleryStacksServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamGalleryStacks not implemented")
+}
+func (UnimplementedMediaServiceServer) FetchGalleryIndex(context.Context, *v1.UUID) (*GalleryIndex, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchGalleryIndex not implemented")
+}
+func (UnimplementedMediaServiceServer) ListStacks(context.Context, *ListStacksReq) (*StackPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStacks not implemented")
+}
+func (UnimplementedMediaServiceServer) ListGalleries(context.Context, *ListGalleriesReq) (*GalleryList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGalleries not implemented")
+}
+func (UnimplementedMediaServiceServer) FetchStack(context.Context, *FetchStackReq) (*Stack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchStack not implemented")
+}
 func (UnimplementedMediaServiceServer) SortGallery(context.Context, *SortGalleryReq) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SortGallery not implemented")
 }
+func (UnimplementedMediaServiceServer) TagStack(context.Context, *TagStackReq) (*Stack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TagStack not implemented")
+}
+func (UnimplementedMediaServiceServer) UntagStack(context.Context, *TagStackReq) (*Stack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UntagStack not implemented")
+}
+func (UnimplementedMediaServiceServer) RenameStack(context.Context, *RenameStackReq) (*Stack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameStack not implemented")
+}
+func (UnimplementedMediaServiceServer) DeleteStack(context.Context, *DeleteStackReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteStack not implemented")
+}
 func (UnimplementedMediaServiceServer) mustEmbedUnimplementedMediaServiceServer() {}
 
 // UnsafeMediaServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -364,6 +664,32 @@ func (x *mediaServiceReplaceDocumentServer) Recv() (*ReplaceDocumentReq, error)
 	return m, nil
 }
 
+func _MediaService_ReplaceDocumentDelta_Handler(srv any, stream grpc.ServerStream) error {
+	return srv.(MediaServiceServer).ReplaceDocumentDelta(&mediaServiceReplaceDocumentDeltaServer{stream})
+}
+
+type MediaService_ReplaceDocumentDeltaServer interface {
+	SendAndClose(*ShelfDocument) error
+	Recv() (*ReplaceDocumentReq, error)
+	grpc.ServerStream
+}
+
+type mediaServiceReplaceDocumentDeltaServer struct {
+	grpc.ServerStream
+}
+
+func (x *mediaServiceReplaceDocumentDeltaServer) SendAndClose(m *ShelfDocument) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *mediaServiceReplaceDocumentDeltaServer) Recv() (*ReplaceDocumentReq, error) {
+	m := new(ReplaceDocumentReq)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _MediaService_FetchShelf_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
 	in := new(v1.UUID)
 	if err := dec(in); err != nil {
@@ -382,6 +708,132 @@ func _MediaService_FetchShelf_Handler(srv any, ctx context.Context, dec func(any
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MediaService_ListDocuments_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(ListDocumentsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).ListDocuments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nicecms.media.v1.MediaService/ListDocuments",
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(MediaServiceServer).ListDocuments(ctx, req.(*ListDocumentsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_SearchDocuments_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(SearchDocumentsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).SearchDocuments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nicecms.media.v1.MediaService/SearchDocuments",
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(MediaServiceServer).SearchDocuments(ctx, req.(*SearchDocumentsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_ListShelfs_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(ListShelfsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).ListShelfs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nicecms.media.v1.MediaService/ListShelfs",
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(MediaServiceServer).ListShelfs(ctx, req.(*ListShelfsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_TagDocument_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(TagDocumentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).TagDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nicecms.media.v1.MediaService/TagDocument",
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(MediaServiceServer).TagDocument(ctx, req.(*TagDocumentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_UntagDocument_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(TagDocumentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).UntagDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nicecms.media.v1.MediaService/UntagDocument",
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(MediaServiceServer).UntagDocument(ctx, req.(*TagDocumentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_RenameDocument_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(RenameDocumentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).RenameDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nicecms.media.v1.MediaService/RenameDocument",
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(MediaServiceServer).RenameDocument(ctx, req.(*RenameDocumentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_DeleteDocument_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(DeleteDocumentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).DeleteDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nicecms.media.v1.MediaService/DeleteDocument",
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(MediaServiceServer).DeleteDocument(ctx, req.(*DeleteDocumentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MediaService_LookupGalleryByName_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
 	in := new(v1.NameLookup)
 	if err := dec(in); err != nil {
@@ -418,6 +870,24 @@ func _MediaService_LookupGalleryStackByName_Handler(srv any, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MediaService_LookupGalleryStacksByTag_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(LookupGalleryStacksByTagReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).LookupGalleryStacksByTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nicecms.media.v1.MediaService/LookupGalleryStacksByTag",
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(MediaServiceServer).LookupGalleryStacksByTag(ctx, req.(*LookupGalleryStacksByTagReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MediaService_UploadImage_Handler(srv any, stream grpc.ServerStream) error {
 	return srv.(MediaServiceServer).UploadImage(&mediaServiceUploadImageServer{stream})
 }
@@ -488,6 +958,99 @@ func _MediaService_FetchGallery_Handler(srv any, ctx context.Context, dec func(a
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MediaService_StreamGalleryStacks_Handler(srv any, stream grpc.ServerStream) error {
+	m := new(StreamGalleryStacksReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MediaServiceServer).StreamGalleryStacks(m, &mediaServiceStreamGalleryStacksServer{stream})
+}
+
+type MediaService_StreamGalleryStacksServer interface {
+	Send(*GalleryStacksChunk) error
+	grpc.ServerStream
+}
+
+type mediaServiceStreamGalleryStacksServer struct {
+	grpc.ServerStream
+}
+
+func (x *mediaServiceStreamGalleryStacksServer) Send(m *GalleryStacksChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MediaService_FetchGalleryIndex_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(v1.UUID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).FetchGalleryIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nicecms.media.v1.MediaService/FetchGalleryIndex",
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(MediaServiceServer).FetchGalleryIndex(ctx, req.(*v1.UUID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_ListStacks_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(ListStacksReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).ListStacks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nicecms.media.v1.MediaService/ListStacks",
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(MediaServiceServer).ListStacks(ctx, req.(*ListStacksReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_ListGalleries_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(ListGalleriesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).ListGalleries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nicecms.media.v1.MediaService/ListGalleries",
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(MediaServiceServer).ListGalleries(ctx, req.(*ListGalleriesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_FetchStack_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(FetchStackReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).FetchStack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nicecms.media.v1.MediaService/FetchStack",
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(MediaServiceServer).FetchStack(ctx, req.(*FetchStackReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MediaService_SortGallery_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
 	in := new(SortGalleryReq)
 	if err := dec(in); err != nil {
@@ -506,6 +1069,78 @@ func _MediaService_SortGallery_Handler(srv any, ctx context.Context, dec func(an
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MediaService_TagStack_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(TagStackReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).TagStack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nicecms.media.v1.MediaService/TagStack",
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(MediaServiceServer).TagStack(ctx, req.(*TagStackReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_UntagStack_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(TagStackReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).UntagStack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nicecms.media.v1.MediaService/UntagStack",
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(MediaServiceServer).UntagStack(ctx, req.(*TagStackReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_RenameStack_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(RenameStackReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).RenameStack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nicecms.media.v1.MediaService/RenameStack",
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(MediaServiceServer).RenameStack(ctx, req.(*RenameStackReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_DeleteStack_Handler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(DeleteStackReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).DeleteStack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nicecms.media.v1.MediaService/DeleteStack",
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(MediaServiceServer).DeleteStack(ctx, req.(*DeleteStackReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MediaService_ServiceDesc is the grpc.ServiceDesc for MediaService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -521,6 +1156,34 @@ var MediaService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FetchShelf",
 			Handler:    _MediaService_FetchShelf_Handler,
 		},
+		{
+			MethodName: "ListDocuments",
+			Handler:    _MediaService_ListDocuments_Handler,
+		},
+		{
+			MethodName: "SearchDocuments",
+			Handler:    _MediaService_SearchDocuments_Handler,
+		},
+		{
+			MethodName: "ListShelfs",
+			Handler:    _MediaService_ListShelfs_Handler,
+		},
+		{
+			MethodName: "TagDocument",
+			Handler:    _MediaService_TagDocument_Handler,
+		},
+		{
+			MethodName: "UntagDocument",
+			Handler:    _MediaService_UntagDocument_Handler,
+		},
+		{
+			MethodName: "RenameDocument",
+			Handler:    _MediaService_RenameDocument_Handler,
+		},
+		{
+			MethodName: "DeleteDocument",
+			Handler:    _MediaService_DeleteDocument_Handler,
+		},
 		{
 			MethodName: "LookupGalleryByName",
 			Handler:    _MediaService_LookupGalleryByName_Handler,
@@ -529,14 +1192,50 @@ var MediaService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LookupGalleryStackByName",
 			Handler:    _MediaService_LookupGalleryStackByName_Handler,
 		},
+		{
+			MethodName: "LookupGalleryStacksByTag",
+			Handler:    _MediaService_LookupGalleryStacksByTag_Handler,
+		},
 		{
 			MethodName: "FetchGallery",
 			Handler:    _MediaService_FetchGallery_Handler,
 		},
+		{
+			MethodName: "FetchGalleryIndex",
+			Handler:    _MediaService_FetchGalleryIndex_Handler,
+		},
+		{
+			MethodName: "ListStacks",
+			Handler:    _MediaService_ListStacks_Handler,
+		},
+		{
+			MethodName: "ListGalleries",
+			Handler:    _MediaService_ListGalleries_Handler,
+		},
+		{
+			MethodName: "FetchStack",
+			Handler:    _MediaService_FetchStack_Handler,
+		},
 		{
 			MethodName: "SortGallery",
 			Handler:    _MediaService_SortGallery_Handler,
 		},
+		{
+			MethodName: "TagStack",
+			Handler:    _MediaService_TagStack_Handler,
+		},
+		{
+			MethodName: "UntagStack",
+			Handler:    _MediaService_UntagStack_Handler,
+		},
+		{
+			MethodName: "RenameStack",
+			Handler:    _MediaService_RenameStack_Handler,
+		},
+		{
+			MethodName: "DeleteStack",
+			Handler:    _MediaService_DeleteStack_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -549,6 +1248,11 @@ var MediaService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _MediaService_ReplaceDocument_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "ReplaceDocumentDelta",
+			Handler:       _MediaService_ReplaceDocumentDelta_Handler,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "UploadImage",
 			Handler:       _MediaService_UploadImage_Handler,
@@ -559,6 +1263,11 @@ var MediaService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _MediaService_ReplaceImage_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "StreamGalleryStacks",
+			Handler:       _MediaService_StreamGalleryStacks_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "media.proto",
 }
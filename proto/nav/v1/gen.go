@@ -0,0 +1,4 @@
+package nav
+
+//go:generate mkdir -p ../../gen/nav/v1
+//go:generate protoc -I ../../ -I. --go_out=module=github.com/modernice/nice-cms/proto/gen/nav/v1:../../gen/nav/v1 --go-grpc_out=module=github.com/modernice/nice-cms/proto/gen/nav/v1:../../gen/nav/v1 nav.proto
@@ -0,0 +1,4 @@
+package page
+
+//go:generate mkdir -p ../../gen/page/v1
+//go:generate protoc -I ../../ -I. --go_out=module=github.com/modernice/nice-cms/proto/gen/page/v1:../../gen/page/v1 --go-grpc_out=module=github.com/modernice/nice-cms/proto/gen/page/v1:../../gen/page/v1 page.proto
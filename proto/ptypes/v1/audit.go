@@ -0,0 +1,108 @@
+package ptypes
+
+import (
+	"time"
+
+	"github.com/modernice/nice-cms/audit"
+	protoaudit "github.com/modernice/nice-cms/proto/gen/audit/v1"
+)
+
+// AuditEntryProto encodes an audit Entry.
+func AuditEntryProto(e audit.Entry) *protoaudit.Entry {
+	return &protoaudit.Entry{
+		Id:               UUIDProto(e.ID),
+		Time:             unixNano(e.Time),
+		Actor:            e.Actor,
+		Command:          e.Command,
+		Event:            e.Event,
+		Aggregate:        e.Aggregate,
+		AggregateId:      UUIDProto(e.AggregateID),
+		AggregateVersion: int64(e.AggregateVersion),
+	}
+}
+
+// AuditEntry decodes an audit Entry.
+func AuditEntry(e *protoaudit.Entry) audit.Entry {
+	return audit.Entry{
+		ID:               UUID(e.GetId()),
+		Time:             fromUnixNano(e.GetTime()),
+		Actor:            e.GetActor(),
+		Command:          e.GetCommand(),
+		Event:            e.GetEvent(),
+		Aggregate:        e.GetAggregate(),
+		AggregateID:      UUID(e.GetAggregateId()),
+		AggregateVersion: int(e.GetAggregateVersion()),
+	}
+}
+
+// AuditQueryProto encodes an audit Query.
+func AuditQueryProto(q audit.Query) *protoaudit.ListEntriesReq {
+	return &protoaudit.ListEntriesReq{
+		Actor:       q.Actor,
+		Aggregate:   q.Aggregate,
+		AggregateId: UUIDProto(q.AggregateID),
+		Event:       q.Event,
+		Since:       unixNano(q.Since),
+		Until:       unixNano(q.Until),
+		Offset:      int64(q.Offset),
+		Limit:       int64(q.Limit),
+	}
+}
+
+// AuditQuery decodes an audit Query.
+func AuditQuery(req *protoaudit.ListEntriesReq) audit.Query {
+	return audit.Query{
+		Actor:       req.GetActor(),
+		Aggregate:   req.GetAggregate(),
+		AggregateID: UUID(req.GetAggregateId()),
+		Event:       req.GetEvent(),
+		Since:       fromUnixNano(req.GetSince()),
+		Until:       fromUnixNano(req.GetUntil()),
+		Offset:      int(req.GetOffset()),
+		Limit:       int(req.GetLimit()),
+	}
+}
+
+// AuditPageProto encodes an audit Page.
+func AuditPageProto(p audit.Page) *protoaudit.EntryPage {
+	entries := make([]*protoaudit.Entry, len(p.Entries))
+	for i, e := range p.Entries {
+		entries[i] = AuditEntryProto(e)
+	}
+	return &protoaudit.EntryPage{
+		Entries: entries,
+		Total:   int64(p.Total),
+		Offset:  int64(p.Offset),
+		Limit:   int64(p.Limit),
+	}
+}
+
+// AuditPage decodes an audit Page.
+func AuditPage(p *protoaudit.EntryPage) audit.Page {
+	entries := make([]audit.Entry, len(p.GetEntries()))
+	for i, e := range p.GetEntries() {
+		entries[i] = AuditEntry(e)
+	}
+	return audit.Page{
+		Entries: entries,
+		Total:   int(p.GetTotal()),
+		Offset:  int(p.GetOffset()),
+		Limit:   int(p.GetLimit()),
+	}
+}
+
+// Audit times are encoded in nanoseconds, so that Since and Until of a Query
+// can be set to the exact Time of an Entry.
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func fromUnixNano(ns int64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns).UTC()
+}
@@ -1,9 +1,14 @@
 package ptypes
 
 import (
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/modernice/nice-cms/internal/slice"
 	"github.com/modernice/nice-cms/media"
 	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image"
+	"github.com/modernice/nice-cms/media/image/exif"
 	"github.com/modernice/nice-cms/media/image/gallery"
 	protomedia "github.com/modernice/nice-cms/proto/gen/media/v1"
 )
@@ -11,17 +16,22 @@ import (
 // StorageFileProto encodes a File.
 func StorageFileProto(f media.File) *protomedia.StorageFile {
 	return &protomedia.StorageFile{
-		Name:     f.Name,
-		Disk:     f.Disk,
-		Path:     f.Path,
-		Filesize: int64(f.Filesize),
-		Tags:     f.Tags,
+		Name:        f.Name,
+		Disk:        f.Disk,
+		Path:        f.Path,
+		Filesize:    int64(f.Filesize),
+		Tags:        f.Tags,
+		Checksum:    f.Checksum,
+		StoragePath: f.StoragePath,
 	}
 }
 
 // StorageFile decodes a File.
 func StorageFile(f *protomedia.StorageFile) media.File {
-	return media.NewFile(f.GetName(), f.GetDisk(), f.GetPath(), int(f.GetFilesize()), f.GetTags()...)
+	file := media.NewFile(f.GetName(), f.GetDisk(), f.GetPath(), int(f.GetFilesize()), f.GetTags()...)
+	file.Checksum = f.GetChecksum()
+	file.StoragePath = f.GetStoragePath()
+	return file
 }
 
 // StorageImageProto encodes an Image.
@@ -35,14 +45,33 @@ func StorageImageProto(img media.Image) *protomedia.StorageImage {
 
 // StorageImage decodes an Image.
 func StorageImage(img *protomedia.StorageImage) media.Image {
-	return media.NewImage(
-		int(img.GetWidth()),
-		int(img.GetHeight()),
-		img.GetFile().GetName(),
-		img.GetFile().GetDisk(),
-		img.GetFile().GetPath(),
-		int(img.GetFile().Filesize),
-	)
+	return media.Image{
+		File:   StorageFile(img.GetFile()),
+		Width:  int(img.GetWidth()),
+		Height: int(img.GetHeight()),
+	}
+}
+
+// StorageVideoProto encodes a Video.
+func StorageVideoProto(v media.Video) *protomedia.StorageVideo {
+	return &protomedia.StorageVideo{
+		File:     StorageFileProto(v.File),
+		Width:    int64(v.Width),
+		Height:   int64(v.Height),
+		Duration: v.Duration.Milliseconds(),
+		Codec:    v.Codec,
+	}
+}
+
+// StorageVideo decodes a Video.
+func StorageVideo(v *protomedia.StorageVideo) media.Video {
+	return media.Video{
+		File:     StorageFile(v.GetFile()),
+		Width:    int(v.GetWidth()),
+		Height:   int(v.GetHeight()),
+		Duration: time.Duration(v.GetDuration()) * time.Millisecond,
+		Codec:    v.GetCodec(),
+	}
 }
 
 // StorageDocumentProto encodes a Document.
@@ -54,12 +83,7 @@ func StorageDocumentProto(doc media.Document) *protomedia.StorageDocument {
 
 // StorageDocument decodes a Document.
 func StorageDocument(doc *protomedia.StorageDocument) media.Document {
-	return media.NewDocument(
-		doc.GetFile().GetName(),
-		doc.GetFile().GetDisk(),
-		doc.GetFile().GetPath(),
-		int(doc.GetFile().Filesize),
-	)
+	return media.Document{File: StorageFile(doc.GetFile())}
 }
 
 func ShelfProto(s document.JSONShelf) *protomedia.Shelf {
@@ -67,6 +91,9 @@ func ShelfProto(s document.JSONShelf) *protomedia.Shelf {
 		Id:        UUIDProto(s.ID),
 		Name:      s.Name,
 		Documents: slice.Map(s.Documents, ShelfDocumentProto).([]*protomedia.ShelfDocument),
+		Trashed:   slice.Map(s.Trashed, TrashedDocumentProto).([]*protomedia.TrashedDocument),
+		LegalHold: LegalHoldProto(s.LegalHold),
+		Version:   int64(s.Version),
 	}
 }
 
@@ -75,6 +102,215 @@ func Shelf(s *protomedia.Shelf) document.JSONShelf {
 		ID:        UUID(s.GetId()),
 		Name:      s.GetName(),
 		Documents: slice.Map(s.GetDocuments(), ShelfDocument).([]document.Document),
+		Trashed:   trashedDocuments(s.GetTrashed()),
+		LegalHold: LegalHold(s.GetLegalHold()),
+		Version:   int(s.GetVersion()),
+	}
+}
+
+// ListDocumentsReqProto encodes a paginated query for the Documents of a Shelf.
+func ListDocumentsReqProto(shelfID uuid.UUID, q document.Query) *protomedia.ListDocumentsReq {
+	return &protomedia.ListDocumentsReq{
+		ShelfId: UUIDProto(shelfID),
+		Offset:  int64(q.Offset),
+		Limit:   int64(q.Limit),
+		Tags:    q.Tags,
+		Search:  q.Search,
+		Sort:    q.Sort,
+	}
+}
+
+// DocumentQuery decodes the query of a ListDocumentsReq.
+func DocumentQuery(req *protomedia.ListDocumentsReq) document.Query {
+	return document.Query{
+		Offset: int(req.GetOffset()),
+		Limit:  int(req.GetLimit()),
+		Tags:   req.GetTags(),
+		Search: req.GetSearch(),
+		Sort:   req.GetSort(),
+	}
+}
+
+// DocumentPageProto encodes a DocumentPage.
+func DocumentPageProto(p document.DocumentPage) *protomedia.DocumentPage {
+	return &protomedia.DocumentPage{
+		Id:        UUIDProto(p.ID),
+		Name:      p.Name,
+		Documents: slice.Map(p.Documents, ShelfDocumentProto).([]*protomedia.ShelfDocument),
+		LegalHold: LegalHoldProto(p.LegalHold),
+		Total:     int64(p.Total),
+		Offset:    int64(p.Offset),
+		Limit:     int64(p.Limit),
+	}
+}
+
+// DocumentPage decodes a DocumentPage.
+func DocumentPage(p *protomedia.DocumentPage) document.DocumentPage {
+	docs := slice.Map(p.GetDocuments(), ShelfDocument).([]document.Document)
+	if docs == nil {
+		docs = []document.Document{}
+	}
+	return document.DocumentPage{
+		ID:        UUID(p.GetId()),
+		Name:      p.GetName(),
+		Documents: docs,
+		LegalHold: LegalHold(p.GetLegalHold()),
+		Total:     int(p.GetTotal()),
+		Offset:    int(p.GetOffset()),
+		Limit:     int(p.GetLimit()),
+	}
+}
+
+// SearchDocumentsReqProto encodes a SearchQuery.
+func SearchDocumentsReqProto(q document.SearchQuery) *protomedia.SearchDocumentsReq {
+	return &protomedia.SearchDocumentsReq{
+		Offset:      int64(q.Offset),
+		Limit:       int64(q.Limit),
+		Search:      q.Search,
+		Names:       q.Names,
+		UniqueNames: q.UniqueNames,
+		Tags:        q.Tags,
+		Path:        q.Path,
+	}
+}
+
+// SearchQuery decodes the SearchQuery of a SearchDocumentsReq.
+func SearchQuery(req *protomedia.SearchDocumentsReq) document.SearchQuery {
+	return document.SearchQuery{
+		Offset:      int(req.GetOffset()),
+		Limit:       int(req.GetLimit()),
+		Search:      req.GetSearch(),
+		Names:       req.GetNames(),
+		UniqueNames: req.GetUniqueNames(),
+		Tags:        req.GetTags(),
+		Path:        req.GetPath(),
+	}
+}
+
+// SearchResultsProto encodes SearchResults.
+func SearchResultsProto(r document.SearchResults) *protomedia.SearchDocumentsResp {
+	return &protomedia.SearchDocumentsResp{
+		Documents: slice.Map(r.Documents, SearchResultProto).([]*protomedia.DocumentSearchResult),
+		Total:     int64(r.Total),
+		Offset:    int64(r.Offset),
+		Limit:     int64(r.Limit),
+	}
+}
+
+// SearchResults decodes SearchResults.
+func SearchResults(r *protomedia.SearchDocumentsResp) document.SearchResults {
+	docs := slice.Map(r.GetDocuments(), SearchResult).([]document.SearchResult)
+	if docs == nil {
+		docs = []document.SearchResult{}
+	}
+	return document.SearchResults{
+		Documents: docs,
+		Total:     int(r.GetTotal()),
+		Offset:    int(r.GetOffset()),
+		Limit:     int(r.GetLimit()),
+	}
+}
+
+// SearchResultProto encodes a SearchResult.
+func SearchResultProto(r document.SearchResult) *protomedia.DocumentSearchResult {
+	return &protomedia.DocumentSearchResult{
+		Document:  ShelfDocumentProto(r.Document),
+		ShelfId:   UUIDProto(r.ShelfID),
+		ShelfName: r.ShelfName,
+	}
+}
+
+// SearchResult decodes a SearchResult.
+func SearchResult(r *protomedia.DocumentSearchResult) document.SearchResult {
+	return document.SearchResult{
+		Document:  ShelfDocument(r.GetDocument()),
+		ShelfID:   UUID(r.GetShelfId()),
+		ShelfName: r.GetShelfName(),
+	}
+}
+
+// ShelfListProto encodes a ShelfList.
+func ShelfListProto(l document.ShelfList) *protomedia.ShelfList {
+	return &protomedia.ShelfList{
+		Shelfs: slice.Map(l.Shelfs, ShelfRefProto).([]*protomedia.ShelfRef),
+		Total:  int64(l.Total),
+		Offset: int64(l.Offset),
+		Limit:  int64(l.Limit),
+	}
+}
+
+// ShelfList decodes a ShelfList.
+func ShelfList(l *protomedia.ShelfList) document.ShelfList {
+	refs := slice.Map(l.GetShelfs(), ShelfRef).([]document.ShelfRef)
+	if refs == nil {
+		refs = []document.ShelfRef{}
+	}
+	return document.ShelfList{
+		Shelfs: refs,
+		Total:  int(l.GetTotal()),
+		Offset: int(l.GetOffset()),
+		Limit:  int(l.GetLimit()),
+	}
+}
+
+// ShelfRefProto encodes a ShelfRef.
+func ShelfRefProto(ref document.ShelfRef) *protomedia.ShelfRef {
+	return &protomedia.ShelfRef{
+		Id:   UUIDProto(ref.ID),
+		Name: ref.Name,
+	}
+}
+
+// ShelfRef decodes a ShelfRef.
+func ShelfRef(ref *protomedia.ShelfRef) document.ShelfRef {
+	return document.ShelfRef{
+		ID:   UUID(ref.GetId()),
+		Name: ref.GetName(),
+	}
+}
+
+func trashedDocuments(docs []*protomedia.TrashedDocument) []document.TrashedDocument {
+	if len(docs) == 0 {
+		return nil
+	}
+	return slice.Map(docs, TrashedDocument).([]document.TrashedDocument)
+}
+
+// LegalHoldProto encodes a LegalHold.
+func LegalHoldProto(hold *media.LegalHold) *protomedia.LegalHold {
+	if hold == nil {
+		return nil
+	}
+	return &protomedia.LegalHold{
+		Reason: hold.Reason,
+		Since:  unixMilli(hold.Since),
+	}
+}
+
+// LegalHold decodes a LegalHold.
+func LegalHold(hold *protomedia.LegalHold) *media.LegalHold {
+	if hold == nil {
+		return nil
+	}
+	return &media.LegalHold{
+		Reason: hold.GetReason(),
+		Since:  fromUnixMilli(hold.GetSince()),
+	}
+}
+
+// TrashedDocumentProto encodes a TrashedDocument.
+func TrashedDocumentProto(doc document.TrashedDocument) *protomedia.TrashedDocument {
+	return &protomedia.TrashedDocument{
+		Document:  ShelfDocumentProto(doc.Document),
+		TrashedAt: unixMilli(doc.TrashedAt),
+	}
+}
+
+// TrashedDocument decodes a TrashedDocument.
+func TrashedDocument(doc *protomedia.TrashedDocument) document.TrashedDocument {
+	return document.TrashedDocument{
+		Document:  ShelfDocument(doc.GetDocument()),
+		TrashedAt: fromUnixMilli(doc.GetTrashedAt()),
 	}
 }
 
@@ -84,23 +320,109 @@ func ShelfDocumentProto(doc document.Document) *protomedia.ShelfDocument {
 		Document:   StorageDocumentProto(doc.Document),
 		Id:         UUIDProto(doc.ID),
 		UniqueName: doc.UniqueName,
+		Variants:   variantsProto(doc.Variants),
+		Preview:    documentPreviewProto(doc.Preview),
+		Audio:      audioMetadataProto(doc.Audio),
+		LegalHold:  LegalHoldProto(doc.LegalHold),
+		Metadata:   doc.Metadata,
 	}
 }
 
+func audioMetadataProto(meta *media.AudioMetadata) *protomedia.AudioMetadata {
+	if meta == nil {
+		return nil
+	}
+	return &protomedia.AudioMetadata{
+		Duration: meta.Duration.Milliseconds(),
+		Bitrate:  int64(meta.Bitrate),
+	}
+}
+
+func documentPreviewProto(preview *document.Preview) *protomedia.DocumentPreview {
+	if preview == nil {
+		return nil
+	}
+	return &protomedia.DocumentPreview{
+		Status: string(preview.Status),
+		Image:  StorageImageProto(preview.Image),
+		Error:  preview.Error,
+	}
+}
+
+func variantsProto(variants map[string]media.Document) map[string]*protomedia.StorageDocument {
+	out := make(map[string]*protomedia.StorageDocument, len(variants))
+	for locale, v := range variants {
+		out[locale] = StorageDocumentProto(v)
+	}
+	return out
+}
+
 // ShelfDocument decodes a Document.
 func ShelfDocument(doc *protomedia.ShelfDocument) document.Document {
 	return document.Document{
 		Document:   StorageDocument(doc.GetDocument()),
 		ID:         UUID(doc.GetId()),
 		UniqueName: doc.GetUniqueName(),
+		Variants:   variants(doc.GetVariants()),
+		Preview:    documentPreview(doc.GetPreview()),
+		Audio:      audioMetadata(doc.GetAudio()),
+		LegalHold:  LegalHold(doc.GetLegalHold()),
+		Metadata:   doc.GetMetadata(),
 	}
 }
 
+func audioMetadata(meta *protomedia.AudioMetadata) *media.AudioMetadata {
+	if meta == nil {
+		return nil
+	}
+	return &media.AudioMetadata{
+		Duration: time.Duration(meta.GetDuration()) * time.Millisecond,
+		Bitrate:  int(meta.GetBitrate()),
+	}
+}
+
+func documentPreview(preview *protomedia.DocumentPreview) *document.Preview {
+	if preview == nil {
+		return nil
+	}
+	return &document.Preview{
+		Status: document.PreviewStatus(preview.GetStatus()),
+		Image:  StorageImage(preview.GetImage()),
+		Error:  preview.GetError(),
+	}
+}
+
+func variants(variants map[string]*protomedia.StorageDocument) map[string]media.Document {
+	out := make(map[string]media.Document, len(variants))
+	for locale, v := range variants {
+		out[locale] = StorageDocument(v)
+	}
+	return out
+}
+
 func GalleryProto(g gallery.JSONGallery) *protomedia.Gallery {
 	return &protomedia.Gallery{
 		Id:     UUIDProto(g.ID),
 		Name:   g.Name,
 		Stacks: slice.Map(g.Stacks, GalleryStackProto).([]*protomedia.Stack),
+
+		DefaultTags:        g.DefaultTags,
+		Theme:              galleryThemeProto(g.Theme),
+		OriginalsProtected: g.OriginalsProtected,
+		Trashed:            slice.Map(g.Trashed, TrashedStackProto).([]*protomedia.TrashedStack),
+		LegalHold:          LegalHoldProto(g.LegalHold),
+		VersionLimit:       int64(g.VersionLimit),
+		Version:            int64(g.Version),
+	}
+}
+
+func galleryThemeProto(t *gallery.Theme) *protomedia.GalleryTheme {
+	if t == nil {
+		return nil
+	}
+	return &protomedia.GalleryTheme{
+		Primary:   t.Primary,
+		Secondary: t.Secondary,
 	}
 }
 
@@ -109,21 +431,371 @@ func Gallery(g *protomedia.Gallery) gallery.JSONGallery {
 		ID:     UUID(g.GetId()),
 		Name:   g.GetName(),
 		Stacks: slice.Map(g.GetStacks(), GalleryStack).([]gallery.Stack),
+
+		DefaultTags:        g.GetDefaultTags(),
+		Theme:              galleryTheme(g.GetTheme()),
+		OriginalsProtected: g.GetOriginalsProtected(),
+		Trashed:            trashedStacks(g.GetTrashed()),
+		LegalHold:          LegalHold(g.GetLegalHold()),
+		VersionLimit:       int(g.GetVersionLimit()),
+		Version:            int(g.GetVersion()),
+	}
+}
+
+// ListStacksReqProto encodes a paginated query for the Stacks of a Gallery.
+func ListStacksReqProto(galleryID uuid.UUID, q gallery.Query) *protomedia.ListStacksReq {
+	return &protomedia.ListStacksReq{
+		GalleryId: UUIDProto(galleryID),
+		Offset:    int64(q.Offset),
+		Limit:     int64(q.Limit),
+		Tags:      q.Tags,
+		Sort:      q.Sort,
+	}
+}
+
+// StackQuery decodes the query of a ListStacksReq.
+func StackQuery(req *protomedia.ListStacksReq) gallery.Query {
+	return gallery.Query{
+		Offset: int(req.GetOffset()),
+		Limit:  int(req.GetLimit()),
+		Tags:   req.GetTags(),
+		Sort:   req.GetSort(),
+	}
+}
+
+// StackPageProto encodes a StackPage.
+func StackPageProto(p gallery.StackPage) *protomedia.StackPage {
+	return &protomedia.StackPage{
+		Id:                 UUIDProto(p.ID),
+		Name:               p.Name,
+		Stacks:             slice.Map(p.Stacks, GalleryStackProto).([]*protomedia.Stack),
+		OriginalsProtected: p.OriginalsProtected,
+		LegalHold:          LegalHoldProto(p.LegalHold),
+		Total:              int64(p.Total),
+		Offset:             int64(p.Offset),
+		Limit:              int64(p.Limit),
+	}
+}
+
+// StackPage decodes a StackPage.
+func StackPage(p *protomedia.StackPage) gallery.StackPage {
+	stacks := slice.Map(p.GetStacks(), GalleryStack).([]gallery.Stack)
+	if stacks == nil {
+		stacks = []gallery.Stack{}
+	}
+	return gallery.StackPage{
+		ID:                 UUID(p.GetId()),
+		Name:               p.GetName(),
+		Stacks:             stacks,
+		OriginalsProtected: p.GetOriginalsProtected(),
+		LegalHold:          LegalHold(p.GetLegalHold()),
+		Total:              int(p.GetTotal()),
+		Offset:             int(p.GetOffset()),
+		Limit:              int(p.GetLimit()),
+	}
+}
+
+// GalleryListProto encodes a GalleryList.
+func GalleryListProto(l gallery.GalleryList) *protomedia.GalleryList {
+	return &protomedia.GalleryList{
+		Galleries: slice.Map(l.Galleries, GalleryRefProto).([]*protomedia.GalleryRef),
+		Total:     int64(l.Total),
+		Offset:    int64(l.Offset),
+		Limit:     int64(l.Limit),
+	}
+}
+
+// GalleryList decodes a GalleryList.
+func GalleryList(l *protomedia.GalleryList) gallery.GalleryList {
+	refs := slice.Map(l.GetGalleries(), GalleryRef).([]gallery.GalleryRef)
+	if refs == nil {
+		refs = []gallery.GalleryRef{}
+	}
+	return gallery.GalleryList{
+		Galleries: refs,
+		Total:     int(l.GetTotal()),
+		Offset:    int(l.GetOffset()),
+		Limit:     int(l.GetLimit()),
+	}
+}
+
+// GalleryRefProto encodes a GalleryRef.
+func GalleryRefProto(ref gallery.GalleryRef) *protomedia.GalleryRef {
+	return &protomedia.GalleryRef{
+		Id:   UUIDProto(ref.ID),
+		Name: ref.Name,
+	}
+}
+
+// GalleryRef decodes a GalleryRef.
+func GalleryRef(ref *protomedia.GalleryRef) gallery.GalleryRef {
+	return gallery.GalleryRef{
+		ID:   UUID(ref.GetId()),
+		Name: ref.GetName(),
+	}
+}
+
+func trashedStacks(stacks []*protomedia.TrashedStack) []gallery.TrashedStack {
+	if len(stacks) == 0 {
+		return nil
+	}
+	return slice.Map(stacks, TrashedStack).([]gallery.TrashedStack)
+}
+
+// TrashedStackProto encodes a TrashedStack.
+func TrashedStackProto(s gallery.TrashedStack) *protomedia.TrashedStack {
+	return &protomedia.TrashedStack{
+		Stack:     GalleryStackProto(s.Stack),
+		TrashedAt: unixMilli(s.TrashedAt),
+	}
+}
+
+// TrashedStack decodes a TrashedStack.
+func TrashedStack(s *protomedia.TrashedStack) gallery.TrashedStack {
+	return gallery.TrashedStack{
+		Stack:     GalleryStack(s.GetStack()),
+		TrashedAt: fromUnixMilli(s.GetTrashedAt()),
+	}
+}
+
+func galleryTheme(t *protomedia.GalleryTheme) *gallery.Theme {
+	if t == nil {
+		return nil
+	}
+	return &gallery.Theme{
+		Primary:   t.GetPrimary(),
+		Secondary: t.GetSecondary(),
+	}
+}
+
+func GalleryIndexProto(idx gallery.JSONIndex) *protomedia.GalleryIndex {
+	return &protomedia.GalleryIndex{
+		Id:     UUIDProto(idx.ID),
+		Name:   idx.Name,
+		Stacks: slice.Map(idx.Stacks, StackSummaryProto).([]*protomedia.StackSummary),
+	}
+}
+
+func GalleryIndex(idx *protomedia.GalleryIndex) gallery.JSONIndex {
+	return gallery.JSONIndex{
+		ID:     UUID(idx.GetId()),
+		Name:   idx.GetName(),
+		Stacks: slice.Map(idx.GetStacks(), StackSummary).([]gallery.StackSummary),
+	}
+}
+
+func StackSummaryProto(s gallery.StackSummary) *protomedia.StackSummary {
+	return &protomedia.StackSummary{
+		Id:          UUIDProto(s.ID),
+		Name:        s.Name,
+		Tags:        s.Tags,
+		Thumbnail:   GalleryImageProto(s.Thumbnail),
+		Placeholder: s.Placeholder,
+		Pending:     s.Pending,
+	}
+}
+
+func StackSummary(s *protomedia.StackSummary) gallery.StackSummary {
+	tags := s.GetTags()
+	if tags == nil {
+		tags = make([]string, 0)
+	}
+	return gallery.StackSummary{
+		ID:          UUID(s.GetId()),
+		Name:        s.GetName(),
+		Tags:        tags,
+		Thumbnail:   GalleryImage(s.GetThumbnail()),
+		Placeholder: s.GetPlaceholder(),
+		Pending:     s.GetPending(),
 	}
 }
 
 func GalleryStackProto(s gallery.Stack) *protomedia.Stack {
 	return &protomedia.Stack{
-		Id:     UUIDProto(s.ID),
-		Images: slice.Map(s.Images, GalleryImageProto).([]*protomedia.StackImage),
+		Id:          UUIDProto(s.ID),
+		Images:      slice.Map(s.Images, GalleryImageProto).([]*protomedia.StackImage),
+		Alt:         s.Alt,
+		Caption:     s.Caption,
+		Placeholder: s.Placeholder,
+		Palette:     s.Palette,
+		Metadata:    stackMetadataProto(s.Metadata),
+
+		CustomMetadata: s.CustomMetadata,
+		FocalPoint:     focalPointProto(s.FocalPoint),
+		Crop:           areaProto(s.Crop),
+
+		Pending:         s.Pending,
+		ProcessedAt:     unixMilli(s.ProcessedAt),
+		ProcessingError: s.ProcessingError,
+
+		Video:    stackVideoProto(s.Video),
+		Checksum: s.Checksum,
+
+		LegalHold: LegalHoldProto(s.LegalHold),
+
+		Version:  int64(s.Version),
+		Versions: slice.Map(s.Versions, StackVersionProto).([]*protomedia.StackVersion),
+	}
+}
+
+// StackVersionProto encodes a StackVersion.
+func StackVersionProto(v gallery.StackVersion) *protomedia.StackVersion {
+	return &protomedia.StackVersion{
+		Version:    int64(v.Version),
+		Images:     slice.Map(v.Images, GalleryImageProto).([]*protomedia.StackImage),
+		Video:      stackVideoProto(v.Video),
+		Checksum:   v.Checksum,
+		ReplacedAt: unixMilli(v.ReplacedAt),
+	}
+}
+
+// StackVersion decodes a StackVersion.
+func StackVersion(v *protomedia.StackVersion) gallery.StackVersion {
+	return gallery.StackVersion{
+		Version:    int(v.GetVersion()),
+		Images:     slice.Map(v.GetImages(), GalleryImage).([]gallery.Image),
+		Video:      stackVideo(v.GetVideo()),
+		Checksum:   v.GetChecksum(),
+		ReplacedAt: fromUnixMilli(v.GetReplacedAt()),
+	}
+}
+
+func stackVersions(versions []*protomedia.StackVersion) []gallery.StackVersion {
+	if len(versions) == 0 {
+		return nil
+	}
+	return slice.Map(versions, StackVersion).([]gallery.StackVersion)
+}
+
+func stackVideoProto(v *media.Video) *protomedia.StorageVideo {
+	if v == nil {
+		return nil
+	}
+	return StorageVideoProto(*v)
+}
+
+func stackVideo(v *protomedia.StorageVideo) *media.Video {
+	if v == nil {
+		return nil
+	}
+	out := StorageVideo(v)
+	return &out
+}
+
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func fromUnixMilli(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms).UTC()
+}
+
+func stackMetadataProto(meta *gallery.Metadata) *protomedia.StackMetadata {
+	if meta == nil {
+		return nil
+	}
+	out := &protomedia.StackMetadata{
+		Make:         meta.Make,
+		Model:        meta.Model,
+		LensModel:    meta.LensModel,
+		TakenAt:      unixMilli(meta.TakenAt),
+		ExposureTime: meta.ExposureTime,
+		FNumber:      meta.FNumber,
+		Iso:          int64(meta.ISO),
+		FocalLength:  meta.FocalLength,
 	}
+	if meta.GPS != nil {
+		out.Gps = &protomedia.GPS{
+			Latitude:  meta.GPS.Latitude,
+			Longitude: meta.GPS.Longitude,
+		}
+	}
+	return out
 }
 
 func GalleryStack(s *protomedia.Stack) gallery.Stack {
 	return gallery.Stack{
-		ID:     UUID(s.GetId()),
-		Images: slice.Map(s.GetImages(), GalleryImage).([]gallery.Image),
+		ID:          UUID(s.GetId()),
+		Images:      slice.Map(s.GetImages(), GalleryImage).([]gallery.Image),
+		Alt:         s.GetAlt(),
+		Caption:     s.GetCaption(),
+		Placeholder: s.GetPlaceholder(),
+		Palette:     s.GetPalette(),
+		Metadata:    stackMetadata(s.GetMetadata()),
+
+		CustomMetadata: s.GetCustomMetadata(),
+		FocalPoint:     focalPoint(s.GetFocalPoint()),
+		Crop:           area(s.GetCrop()),
+
+		Pending:         s.GetPending(),
+		ProcessedAt:     fromUnixMilli(s.GetProcessedAt()),
+		ProcessingError: s.GetProcessingError(),
+
+		Video:    stackVideo(s.GetVideo()),
+		Checksum: s.GetChecksum(),
+
+		LegalHold: LegalHold(s.GetLegalHold()),
+
+		Version:  int(s.GetVersion()),
+		Versions: stackVersions(s.GetVersions()),
+	}
+}
+
+func focalPointProto(p *image.FocalPoint) *protomedia.FocalPoint {
+	if p == nil {
+		return nil
+	}
+	return &protomedia.FocalPoint{X: p.X, Y: p.Y}
+}
+
+func focalPoint(p *protomedia.FocalPoint) *image.FocalPoint {
+	if p == nil {
+		return nil
+	}
+	return &image.FocalPoint{X: p.GetX(), Y: p.GetY()}
+}
+
+func areaProto(a *image.Area) *protomedia.Area {
+	if a == nil {
+		return nil
+	}
+	return &protomedia.Area{X: a.X, Y: a.Y, Width: a.Width, Height: a.Height}
+}
+
+func area(a *protomedia.Area) *image.Area {
+	if a == nil {
+		return nil
+	}
+	return &image.Area{X: a.GetX(), Y: a.GetY(), Width: a.GetWidth(), Height: a.GetHeight()}
+}
+
+func stackMetadata(meta *protomedia.StackMetadata) *gallery.Metadata {
+	if meta == nil {
+		return nil
+	}
+	out := &gallery.Metadata{
+		Make:         meta.GetMake(),
+		Model:        meta.GetModel(),
+		LensModel:    meta.GetLensModel(),
+		TakenAt:      fromUnixMilli(meta.GetTakenAt()),
+		ExposureTime: meta.GetExposureTime(),
+		FNumber:      meta.GetFNumber(),
+		ISO:          int(meta.GetIso()),
+		FocalLength:  meta.GetFocalLength(),
+	}
+	if gps := meta.GetGps(); gps != nil {
+		out.GPS = &exif.GPS{
+			Latitude:  gps.GetLatitude(),
+			Longitude: gps.GetLongitude(),
+		}
 	}
+	return out
 }
 
 func GalleryImageProto(img gallery.Image) *protomedia.StackImage {
@@ -131,6 +803,9 @@ func GalleryImageProto(img gallery.Image) *protomedia.StackImage {
 		Image:    StorageImageProto(img.Image),
 		Original: img.Original,
 		Size:     img.Size,
+		Format:   img.Format,
+		Stale:    img.Stale,
+		Cropped:  img.Cropped,
 	}
 }
 
@@ -139,5 +814,8 @@ func GalleryImage(img *protomedia.StackImage) gallery.Image {
 		Image:    StorageImage(img.GetImage()),
 		Original: img.GetOriginal(),
 		Size:     img.GetSize(),
+		Format:   img.GetFormat(),
+		Stale:    img.GetStale(),
+		Cropped:  img.GetCropped(),
 	}
 }
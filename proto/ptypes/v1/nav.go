@@ -0,0 +1,148 @@
+package ptypes
+
+import (
+	"github.com/google/uuid"
+	protonav "github.com/modernice/nice-cms/proto/gen/nav/v1"
+	"github.com/modernice/nice-cms/static/nav"
+)
+
+// NavProto encodes a Nav.
+func NavProto(n *nav.Nav) *protonav.Nav {
+	var items []nav.Item
+	if n.Tree != nil {
+		items = n.Items
+	}
+	return &protonav.Nav{
+		Id:    UUIDProto(n.ID),
+		Name:  n.Name,
+		Items: itemsProto(items),
+	}
+}
+
+// Nav decodes a Nav.
+func Nav(n *protonav.Nav) *nav.Nav {
+	out := nav.New(UUID(n.GetId()))
+	out.Name = n.GetName()
+	out.Items = items(n.GetItems())
+	return out
+}
+
+// ItemProto encodes an Item.
+func ItemProto(i nav.Item) *protonav.Item {
+	var children []nav.Item
+	if i.Tree != nil {
+		children = i.Tree.Items
+	}
+	out := &protonav.Item{
+		Id:           i.ID,
+		Type:         string(i.Type),
+		Initial:      i.Initial,
+		LocalePaths:  i.Paths,
+		LocaleLabels: i.Labels,
+		Items:        itemsProto(children),
+		Hidden:       i.Hidden,
+		RequiresAuth: i.RequiresAuth,
+		Attributes:   i.Attributes,
+	}
+	if i.Page != nil {
+		out.Page = UUIDProto(*i.Page)
+	}
+	return out
+}
+
+// Item decodes an Item.
+func Item(i *protonav.Item) nav.Item {
+	item := nav.NewItem(i.GetId(), nav.ItemType(i.GetType()))
+	item.Initial = i.GetInitial()
+	item.Hidden = i.GetHidden()
+	item.RequiresAuth = i.GetRequiresAuth()
+	if len(i.GetAttributes()) > 0 {
+		item.Attributes = i.GetAttributes()
+	}
+	if i.GetPage() != nil {
+		page := UUID(i.GetPage())
+		item.Page = &page
+	}
+	for locale, path := range i.GetLocalePaths() {
+		item.Paths[locale] = path
+	}
+	for locale, label := range i.GetLocaleLabels() {
+		item.Labels[locale] = label
+	}
+	if len(i.GetItems()) > 0 {
+		item.Tree = nav.NewTree(items(i.GetItems())...)
+	}
+	return item
+}
+
+// ItemChangesProto encodes ItemChanges.
+func ItemChangesProto(c nav.ItemChanges) *protonav.ItemChanges {
+	out := &protonav.ItemChanges{
+		Type:         string(c.Type),
+		LocaleLabels: c.Labels,
+		LocalePaths:  c.Paths,
+		Hidden:       c.Hidden,
+		RequiresAuth: c.RequiresAuth,
+		Attributes:   c.Attributes,
+	}
+	if c.Page != uuid.Nil {
+		out.Page = UUIDProto(c.Page)
+	}
+	return out
+}
+
+// ItemChanges decodes ItemChanges.
+func ItemChanges(c *protonav.ItemChanges) nav.ItemChanges {
+	if c == nil {
+		return nav.ItemChanges{}
+	}
+	return nav.ItemChanges{
+		Type:         nav.ItemType(c.GetType()),
+		Page:         UUID(c.GetPage()),
+		Labels:       c.GetLocaleLabels(),
+		Paths:        c.GetLocalePaths(),
+		Hidden:       c.Hidden,
+		RequiresAuth: c.RequiresAuth,
+		Attributes:   c.GetAttributes(),
+	}
+}
+
+// OrdersProto encodes a tree of Orders.
+func OrdersProto(tree []nav.Order) []*protonav.ItemOrder {
+	out := make([]*protonav.ItemOrder, len(tree))
+	for i, o := range tree {
+		out[i] = &protonav.ItemOrder{
+			Id:    o.ID,
+			Items: OrdersProto(o.Items),
+		}
+	}
+	return out
+}
+
+// Orders decodes a tree of Orders.
+func Orders(tree []*protonav.ItemOrder) []nav.Order {
+	out := make([]nav.Order, len(tree))
+	for i, o := range tree {
+		out[i] = nav.Order{
+			ID:    o.GetId(),
+			Items: Orders(o.GetItems()),
+		}
+	}
+	return out
+}
+
+func itemsProto(items []nav.Item) []*protonav.Item {
+	out := make([]*protonav.Item, len(items))
+	for i, item := range items {
+		out[i] = ItemProto(item)
+	}
+	return out
+}
+
+func items(items []*protonav.Item) []nav.Item {
+	out := make([]nav.Item, len(items))
+	for i, item := range items {
+		out[i] = Item(item)
+	}
+	return out
+}
@@ -0,0 +1,123 @@
+package ptypes
+
+import (
+	protopage "github.com/modernice/nice-cms/proto/gen/page/v1"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+// FieldProto encodes a Field.
+func FieldProto(f field.Field) *protopage.Field {
+	return &protopage.Field{
+		Name:    f.Name,
+		Type:    string(f.Type),
+		Values:  f.Values,
+		Guarded: f.Guarded,
+		Schema:  SchemaProto(f.Schema),
+	}
+}
+
+// Field decodes a Field.
+func Field(f *protopage.Field) field.Field {
+	values := f.GetValues()
+	if values == nil {
+		values = make(map[string]string)
+	}
+	return field.Field{
+		Name:    f.GetName(),
+		Type:    field.Type(f.GetType()),
+		Values:  values,
+		Guarded: f.GetGuarded(),
+		Schema:  Schema(f.GetSchema()),
+	}
+}
+
+// SchemaProto encodes a Schema.
+func SchemaProto(s *field.Schema) *protopage.Schema {
+	if s == nil {
+		return nil
+	}
+	props := make([]*protopage.Prop, len(s.Props))
+	for i, p := range s.Props {
+		props[i] = &protopage.Prop{
+			Name:     p.Name,
+			Type:     string(p.Type),
+			Required: p.Required,
+			Items:    SchemaProto(p.Items),
+		}
+	}
+	return &protopage.Schema{
+		Props:    props,
+		MinItems: int64(s.MinItems),
+		MaxItems: int64(s.MaxItems),
+	}
+}
+
+// Schema decodes a Schema.
+func Schema(s *protopage.Schema) *field.Schema {
+	if s == nil {
+		return nil
+	}
+	props := make([]field.Prop, len(s.GetProps()))
+	for i, p := range s.GetProps() {
+		props[i] = field.Prop{
+			Name:     p.GetName(),
+			Type:     field.Type(p.GetType()),
+			Required: p.GetRequired(),
+			Items:    Schema(p.GetItems()),
+		}
+	}
+	return &field.Schema{
+		Props:    props,
+		MinItems: int(s.GetMinItems()),
+		MaxItems: int(s.GetMaxItems()),
+	}
+}
+
+// RevisionProto encodes a Revision.
+func RevisionProto(rev page.Revision) *protopage.Revision {
+	fields := make([]*protopage.Field, len(rev.Fields))
+	for i, f := range rev.Fields {
+		fields[i] = FieldProto(f)
+	}
+	return &protopage.Revision{
+		Number:      int64(rev.Number),
+		PublishedAt: unixMilli(rev.PublishedAt),
+		Fields:      fields,
+	}
+}
+
+// Revision decodes a Revision.
+func Revision(rev *protopage.Revision) page.Revision {
+	fields := make([]field.Field, len(rev.GetFields()))
+	for i, f := range rev.GetFields() {
+		fields[i] = Field(f)
+	}
+	return page.Revision{
+		Number:      int(rev.GetNumber()),
+		PublishedAt: fromUnixMilli(rev.GetPublishedAt()),
+		Fields:      fields,
+	}
+}
+
+// FieldChangeProto encodes a FieldChange.
+func FieldChangeProto(c page.FieldChange) *protopage.FieldChange {
+	return &protopage.FieldChange{
+		Field:  c.Field,
+		Locale: c.Locale,
+		Change: string(c.Change),
+		Old:    c.Old,
+		New:    c.New,
+	}
+}
+
+// FieldChange decodes a FieldChange.
+func FieldChange(c *protopage.FieldChange) page.FieldChange {
+	return page.FieldChange{
+		Field:  c.GetField(),
+		Locale: c.GetLocale(),
+		Change: page.Change(c.GetChange()),
+		Old:    c.GetOld(),
+		New:    c.GetNew(),
+	}
+}
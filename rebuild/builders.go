@@ -0,0 +1,123 @@
+package rebuild
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// HookOption is an option of a deploy hook Builder.
+type HookOption func(*hook)
+
+// HTTPClient returns a HookOption that sets the http.Client that calls the
+// deploy hook. Default is http.DefaultClient.
+func HTTPClient(client *http.Client) HookOption {
+	return func(h *hook) {
+		h.client = client
+	}
+}
+
+type hook struct {
+	url    string
+	client *http.Client
+	body   bool
+	title  string
+}
+
+// Hook returns a Builder that POSTs the Changes as JSON to the given URL, e.g.
+// to the deploy hook of a CI pipeline.
+func Hook(url string, opts ...HookOption) Builder {
+	return newHook(url, true, "", opts)
+}
+
+// Netlify returns a Builder that calls a Netlify build hook. The Changes are
+// used as the title of the deploy.
+func Netlify(url string, opts ...HookOption) Builder {
+	return newHook(url, false, "trigger_title", opts)
+}
+
+// Vercel returns a Builder that calls a Vercel deploy hook.
+func Vercel(url string, opts ...HookOption) Builder {
+	return newHook(url, false, "", opts)
+}
+
+func newHook(url string, body bool, title string, opts []HookOption) *hook {
+	h := hook{
+		url:    url,
+		client: http.DefaultClient,
+		body:   body,
+		title:  title,
+	}
+	for _, opt := range opts {
+		opt(&h)
+	}
+	return &h
+}
+
+func (h *hook) Build(ctx context.Context, c Changes) error {
+	u, err := url.Parse(h.url)
+	if err != nil {
+		return fmt.Errorf("parse url: %w", err)
+	}
+
+	if h.title != "" {
+		query := u.Query()
+		query.Set(h.title, "nice-cms: "+c.String())
+		u.RawQuery = query.Encode()
+	}
+
+	var body io.Reader
+	if h.body {
+		b, err := json.Marshal(c)
+		if err != nil {
+			return fmt.Errorf("encode changes: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), body)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	if h.body {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("call deploy hook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("call deploy hook: status %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	io.Copy(io.Discard, resp.Body)
+
+	return nil
+}
+
+// Command returns a Builder that runs the given command, e.g. a static site
+// generator. The names of the changed events are passed to the command as a
+// comma-separated list in the NICE_CMS_EVENTS environment variable.
+func Command(name string, args ...string) Builder {
+	return BuilderFunc(func(ctx context.Context, c Changes) error {
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Env = append(os.Environ(), "NICE_CMS_EVENTS="+strings.Join(c.Events, ","))
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("run %q: %w: %s", name, err, stderr.String())
+		}
+		return nil
+	})
+}
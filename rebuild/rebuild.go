@@ -0,0 +1,275 @@
+// Package rebuild triggers rebuilds of static sites when the content of the CMS
+// changes.
+//
+// A Trigger subscribes to the events of shelfs, galleries and navs and to the
+// live events of pages (see page.LiveEvents) and calls a Builder when they are
+// published. Drafts of pages are not part of static sites, so editing a page
+// doesn't trigger a Build until it is published. Events are coalesced: a Build
+// is triggered once no event was published for the debounce duration, so that
+// a burst of edits results in a single rebuild:
+//
+//	t := rebuild.New(rebuild.Netlify("https://api.netlify.com/build_hooks/..."))
+//	errs, err := t.Run(ctx, bus)
+//
+// Builds never run concurrently. Events that are published during a Build
+// trigger another Build after the current one has finished.
+package rebuild
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/page"
+)
+
+const (
+	// DefaultDebounce is the default duration without events after which a
+	// Build is triggered.
+	DefaultDebounce = 10 * time.Second
+
+	// DefaultMaxDelay is the default maximum delay of a Build after the first
+	// event of its Changes.
+	DefaultMaxDelay = 2 * time.Minute
+)
+
+// Builder rebuilds a static site. Hook, Netlify, Vercel and Command return
+// Builders.
+type Builder interface {
+	// Build rebuilds the static site because of the given Changes.
+	Build(context.Context, Changes) error
+}
+
+// BuilderFunc allows a function to be used as a Builder.
+type BuilderFunc func(context.Context, Changes) error
+
+// Build calls fn(ctx, c).
+func (fn BuilderFunc) Build(ctx context.Context, c Changes) error {
+	return fn(ctx, c)
+}
+
+// Changes are the coalesced events that triggered a Build.
+type Changes struct {
+	// Events are the names of the published events, in the order of their
+	// first occurrence.
+	Events []string `json:"events"`
+
+	// Aggregates are the changed aggregates, in the order of their first
+	// change.
+	Aggregates []event.AggregateRef `json:"aggregates"`
+
+	// Count is the number of published events.
+	Count int `json:"count"`
+
+	// First and Last are the times of the first and last event.
+	First time.Time `json:"first"`
+	Last  time.Time `json:"last"`
+}
+
+func (c *Changes) add(evt event.Event) {
+	if c.Count == 0 {
+		c.First = evt.Time()
+	}
+	c.Count++
+	c.Last = evt.Time()
+
+	if !contains(c.Events, evt.Name()) {
+		c.Events = append(c.Events, evt.Name())
+	}
+
+	id, name, _ := evt.Aggregate()
+	ref := event.AggregateRef{Name: name, ID: id}
+	for _, r := range c.Aggregates {
+		if r == ref {
+			return
+		}
+	}
+	c.Aggregates = append(c.Aggregates, ref)
+}
+
+// String returns a short description of the Changes, e.g. for the title of a
+// deploy.
+func (c Changes) String() string {
+	if c.Count == 1 {
+		return fmt.Sprintf("1 change (%s)", c.Events[0])
+	}
+	return fmt.Sprintf("%d changes (%s)", c.Count, strings.Join(c.Events, ", "))
+}
+
+// Events returns the events that a Trigger subscribes to by default.
+func Events() []string {
+	events := make([]string, 0, len(document.Events)+len(gallery.Events)+len(nav.Events)+len(page.LiveEvents))
+	events = append(events, document.Events[:]...)
+	events = append(events, gallery.Events[:]...)
+	events = append(events, nav.Events[:]...)
+	return append(events, page.LiveEvents[:]...)
+}
+
+// Option is a Trigger option.
+type Option func(*Trigger)
+
+// WithEvents returns an Option that subscribes the Trigger to additional
+// events, e.g. the events of application-defined aggregates.
+func WithEvents(events ...string) Option {
+	return func(t *Trigger) {
+		t.events = append(t.events, events...)
+	}
+}
+
+// Debounce returns an Option that sets the duration without events after
+// which a Build is triggered. Default is DefaultDebounce.
+func Debounce(d time.Duration) Option {
+	return func(t *Trigger) {
+		t.debounce = d
+	}
+}
+
+// MaxDelay returns an Option that sets the maximum delay of a Build after the
+// first event of its Changes, so that continuous edits don't postpone a
+// rebuild indefinitely. Default is DefaultMaxDelay.
+func MaxDelay(d time.Duration) Option {
+	return func(t *Trigger) {
+		t.maxDelay = d
+	}
+}
+
+// Trigger calls a Builder when the content of the CMS changes.
+type Trigger struct {
+	builder  Builder
+	events   []string
+	debounce time.Duration
+	maxDelay time.Duration
+}
+
+// New returns a Trigger that calls the given Builder.
+func New(b Builder, opts ...Option) *Trigger {
+	t := Trigger{
+		builder:  b,
+		events:   Events(),
+		debounce: DefaultDebounce,
+		maxDelay: DefaultMaxDelay,
+	}
+	for _, opt := range opts {
+		opt(&t)
+	}
+	return &t
+}
+
+// Run subscribes to the events of the Trigger and triggers Builds until ctx is
+// canceled. Failed Builds are reported through the returned error channel and
+// are not retried; the next change triggers a new Build.
+func (t *Trigger) Run(ctx context.Context, bus event.Bus) (<-chan error, error) {
+	events, errs, err := bus.Subscribe(ctx, t.events...)
+	if err != nil {
+		return nil, fmt.Errorf("subscribe to %v events: %w", t.events, err)
+	}
+
+	out := make(chan error)
+	go t.handle(ctx, events, errs, out)
+
+	return out, nil
+}
+
+func (t *Trigger) handle(ctx context.Context, events <-chan event.Event, errs <-chan error, out chan<- error) {
+	defer close(out)
+
+	fail := func(err error) {
+		select {
+		case <-ctx.Done():
+		case out <- err:
+		}
+	}
+
+	var (
+		pending  *Changes
+		ready    bool
+		building bool
+
+		debounce, deadline *time.Timer
+		debounced, expired <-chan time.Time
+	)
+
+	stopTimers := func() {
+		if debounce != nil {
+			debounce.Stop()
+			debounce, debounced = nil, nil
+		}
+		if deadline != nil {
+			deadline.Stop()
+			deadline, expired = nil, nil
+		}
+	}
+	defer stopTimers()
+
+	// done is buffered so that a running Build doesn't block when ctx is
+	// canceled.
+	done := make(chan error, 1)
+
+	build := func() {
+		stopTimers()
+		changes := *pending
+		pending, ready, building = nil, false, true
+		go func() { done <- t.builder.Build(ctx, changes) }()
+	}
+
+	for events != nil || errs != nil {
+		select {
+		case <-ctx.Done():
+			return
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				break
+			}
+			fail(err)
+		case evt, ok := <-events:
+			if !ok {
+				events = nil
+				break
+			}
+			if pending == nil {
+				pending = &Changes{}
+				deadline = time.NewTimer(t.maxDelay)
+				expired = deadline.C
+			}
+			pending.add(evt)
+			if debounce != nil {
+				debounce.Stop()
+			}
+			debounce = time.NewTimer(t.debounce)
+			debounced = debounce.C
+		case <-debounced:
+			ready = true
+			if !building {
+				build()
+			}
+		case <-expired:
+			ready = true
+			if !building {
+				build()
+			}
+		case err := <-done:
+			building = false
+			if err != nil {
+				fail(fmt.Errorf("rebuild: %w", err))
+			}
+			if ready && pending != nil {
+				build()
+			}
+		}
+	}
+}
+
+func contains(vals []string, val string) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
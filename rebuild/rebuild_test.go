@@ -0,0 +1,224 @@
+package rebuild_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/rebuild"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/page"
+)
+
+func TestTrigger(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := eventbus.New()
+
+	builds := make(chan rebuild.Changes, 10)
+	trigger := rebuild.New(rebuild.BuilderFunc(func(_ context.Context, c rebuild.Changes) error {
+		builds <- c
+		return nil
+	}), rebuild.Debounce(50*time.Millisecond))
+
+	if _, err := trigger.Run(ctx, bus); err != nil {
+		t.Fatalf("run trigger: %v", err)
+	}
+
+	navID := uuid.New()
+	for i := 0; i < 5; i++ {
+		publish(ctx, t, bus, nav.Created, nav.CreatedData{Name: "main"}, navID, nav.Aggregate)
+	}
+	publish(ctx, t, bus, gallery.Created, gallery.CreatedData{Name: "foo"}, uuid.New(), gallery.Aggregate)
+
+	c := awaitBuild(t, builds)
+	if c.Count != 6 || len(c.Events) != 2 || c.Events[0] != nav.Created || len(c.Aggregates) != 2 || c.Aggregates[0].ID != navID {
+		t.Fatalf("a burst of events should trigger a single Build; got %#v", c)
+	}
+
+	select {
+	case c := <-builds:
+		t.Fatalf("no further Build should be triggered; got %#v", c)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestTrigger_pageDrafts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := eventbus.New()
+
+	builds := make(chan rebuild.Changes, 10)
+	trigger := rebuild.New(rebuild.BuilderFunc(func(_ context.Context, c rebuild.Changes) error {
+		builds <- c
+		return nil
+	}), rebuild.Debounce(50*time.Millisecond))
+
+	if _, err := trigger.Run(ctx, bus); err != nil {
+		t.Fatalf("run trigger: %v", err)
+	}
+
+	pageID := uuid.New()
+	publish(ctx, t, bus, page.FieldUpdated, page.FieldUpdatedData{Field: "title", Value: "foo"}, pageID, page.Aggregate)
+
+	select {
+	case c := <-builds:
+		t.Fatalf("draft changes should not trigger a Build; got %#v", c)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	publish(ctx, t, bus, page.Published, page.PublishedData{}, pageID, page.Aggregate)
+
+	if c := awaitBuild(t, builds); c.Count != 1 || c.Events[0] != page.Published {
+		t.Fatalf("publishing a Page should trigger a Build; got %#v", c)
+	}
+}
+
+func TestTrigger_runningBuild(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := eventbus.New()
+
+	started := make(chan rebuild.Changes)
+	release := make(chan struct{})
+	trigger := rebuild.New(rebuild.BuilderFunc(func(_ context.Context, c rebuild.Changes) error {
+		started <- c
+		<-release
+		return nil
+	}), rebuild.Debounce(20*time.Millisecond))
+
+	if _, err := trigger.Run(ctx, bus); err != nil {
+		t.Fatalf("run trigger: %v", err)
+	}
+
+	publish(ctx, t, bus, nav.Created, nav.CreatedData{Name: "main"}, uuid.New(), nav.Aggregate)
+	awaitBuild(t, started)
+
+	// Events that are published during a Build are coalesced into a single
+	// Build that starts after the running Build.
+	for i := 0; i < 3; i++ {
+		publish(ctx, t, bus, nav.Created, nav.CreatedData{Name: "main"}, uuid.New(), nav.Aggregate)
+		time.Sleep(30 * time.Millisecond)
+	}
+
+	select {
+	case <-started:
+		t.Fatalf("Builds should not run concurrently")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+
+	if c := awaitBuild(t, started); c.Count != 3 {
+		t.Fatalf("the next Build should contain the %d events that were published during the previous Build; got %d", 3, c.Count)
+	}
+}
+
+func TestTrigger_maxDelay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := eventbus.New()
+
+	builds := make(chan rebuild.Changes, 10)
+	trigger := rebuild.New(rebuild.BuilderFunc(func(_ context.Context, c rebuild.Changes) error {
+		builds <- c
+		return nil
+	}), rebuild.Debounce(time.Hour), rebuild.MaxDelay(50*time.Millisecond))
+
+	if _, err := trigger.Run(ctx, bus); err != nil {
+		t.Fatalf("run trigger: %v", err)
+	}
+
+	publish(ctx, t, bus, nav.Created, nav.CreatedData{Name: "main"}, uuid.New(), nav.Aggregate)
+
+	if c := awaitBuild(t, builds); c.Count != 1 {
+		t.Fatalf("Build should be triggered after the max delay; got %#v", c)
+	}
+}
+
+func TestHook(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	bodies := make(chan rebuild.Changes, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var c rebuild.Changes
+		json.NewDecoder(r.Body).Decode(&c)
+		requests <- r
+		bodies <- c
+	}))
+	defer srv.Close()
+
+	changes := rebuild.Changes{Events: []string{nav.Created}, Count: 2}
+
+	if err := rebuild.Hook(srv.URL).Build(context.Background(), changes); err != nil {
+		t.Fatalf("Build failed with %q", err)
+	}
+
+	if r := <-requests; r.Method != http.MethodPost {
+		t.Fatalf("Hook should POST to the URL; method is %s", r.Method)
+	}
+	if c := <-bodies; c.Count != 2 || c.Events[0] != nav.Created {
+		t.Fatalf("Hook should send the Changes; got %#v", c)
+	}
+
+	if err := rebuild.Netlify(srv.URL+"/build_hooks/foo").Build(context.Background(), changes); err != nil {
+		t.Fatalf("Build failed with %q", err)
+	}
+
+	r := <-requests
+	<-bodies
+	if title := r.URL.Query().Get("trigger_title"); title != "nice-cms: "+changes.String() {
+		t.Fatalf("Netlify should set the deploy title to %q; got %q", "nice-cms: "+changes.String(), title)
+	}
+}
+
+func TestHook_error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	if err := rebuild.Vercel(srv.URL).Build(context.Background(), rebuild.Changes{}); err == nil {
+		t.Fatalf("Build should fail if the deploy hook fails")
+	}
+}
+
+func TestCommand(t *testing.T) {
+	changes := rebuild.Changes{Events: []string{nav.Created, gallery.Created}, Count: 2}
+
+	b := rebuild.Command("sh", "-c", `test "$NICE_CMS_EVENTS" = "`+nav.Created+","+gallery.Created+`"`)
+	if err := b.Build(context.Background(), changes); err != nil {
+		t.Fatalf("Build failed with %q", err)
+	}
+
+	if err := rebuild.Command("sh", "-c", "exit 1").Build(context.Background(), changes); err == nil {
+		t.Fatalf("Build should fail if the command fails")
+	}
+}
+
+func publish(ctx context.Context, t *testing.T, bus event.Bus, name string, data any, id uuid.UUID, aggregate string) {
+	evt := event.New(name, data, event.Aggregate(id, aggregate, 1))
+	if err := bus.Publish(ctx, evt.Any()); err != nil {
+		t.Fatalf("publish %q event: %v", name, err)
+	}
+}
+
+func awaitBuild(t *testing.T, builds <-chan rebuild.Changes) rebuild.Changes {
+	select {
+	case <-time.After(3 * time.Second):
+		t.Fatalf("Build should be triggered")
+		return rebuild.Changes{}
+	case c := <-builds:
+		return c
+	}
+}
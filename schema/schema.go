@@ -0,0 +1,278 @@
+// Package schema generates JSON Schemas from Go types.
+//
+// Schemas are generated from the exported fields of structs and their `json`
+// tags. Validation rules are read from the `schema` tag of a field:
+//
+//	type Request struct {
+//		Name string   `json:"name" schema:"required,minLength=1,maxLength=64"`
+//		Type string   `json:"type" schema:"enum=label|static_link"`
+//		Tags []string `json:"tags" schema:"minItems=1"`
+//	}
+//
+// Supported rules are "required", "minLength", "maxLength", "minimum",
+// "maximum", "minItems", "maxItems", "format" and "enum" (values separated by
+// "|").
+package schema
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Draft is the JSON Schema dialect of generated Schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	Ref         string             `json:"$ref,omitempty"`
+	Defs        map[string]*Schema `json:"$defs,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+
+	MinLength *int     `json:"minLength,omitempty"`
+	MaxLength *int     `json:"maxLength,omitempty"`
+	Minimum   *float64 `json:"minimum,omitempty"`
+	Maximum   *float64 `json:"maximum,omitempty"`
+	MinItems  *int     `json:"minItems,omitempty"`
+	MaxItems  *int     `json:"maxItems,omitempty"`
+}
+
+// Option is an option for Of.
+type Option func(*Schema)
+
+// Title returns an Option that sets the title of a Schema.
+func Title(title string) Option {
+	return func(s *Schema) {
+		s.Title = title
+	}
+}
+
+// Description returns an Option that sets the description of a Schema.
+func Description(desc string) Option {
+	return func(s *Schema) {
+		s.Description = desc
+	}
+}
+
+// Of returns the Schema of the type of v. Of panics if the type of v (or one
+// of its fields) cannot be represented as JSON or has an invalid `schema` tag.
+func Of(v any, opts ...Option) *Schema {
+	t := reflect.TypeOf(v)
+	g := generator{
+		root:      indirect(t),
+		visiting:  make(map[reflect.Type]bool),
+		recursive: make(map[reflect.Type]bool),
+		defs:      make(map[string]*Schema),
+	}
+
+	s := g.schema(t)
+	s.Schema = Draft
+	if len(g.defs) > 0 {
+		s.Defs = g.defs
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	uuidType          = reflect.TypeOf(uuid.UUID{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+type generator struct {
+	root      reflect.Type
+	visiting  map[reflect.Type]bool
+	recursive map[reflect.Type]bool
+	defs      map[string]*Schema
+}
+
+func (g *generator) schema(t reflect.Type) *Schema {
+	t = indirect(t)
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	}
+
+	if t.Kind() != reflect.String && t.Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			panic(fmt.Errorf("schema: unsupported map key type %v", t.Key()))
+		}
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		return g.structSchema(t)
+	default:
+		panic(fmt.Errorf("schema: unsupported type %v", t))
+	}
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	if g.visiting[t] {
+		g.recursive[t] = true
+		return &Schema{Ref: g.ref(t)}
+	}
+
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addFields(s, t)
+
+	if g.recursive[t] && t != g.root {
+		g.defs[t.Name()] = s
+		return &Schema{Ref: g.ref(t)}
+	}
+
+	return s
+}
+
+func (g *generator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, skip := fieldName(field)
+		if skip {
+			continue
+		}
+
+		// Fields of embedded structs are promoted to the parent.
+		if field.Anonymous && name == "" && indirect(field.Type).Kind() == reflect.Struct {
+			g.addFields(s, indirect(field.Type))
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := g.schema(field.Type)
+		required, err := applyRules(prop, field.Tag.Get("schema"))
+		if err != nil {
+			panic(fmt.Errorf("schema: field %s.%s: %w", t.Name(), field.Name, err))
+		}
+
+		s.Properties[name] = prop
+		if required {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+func (g *generator) ref(t reflect.Type) string {
+	if t == g.root {
+		return "#"
+	}
+	return "#/$defs/" + t.Name()
+}
+
+func fieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	return name, false
+}
+
+func applyRules(s *Schema, tag string) (bool, error) {
+	if tag == "" {
+		return false, nil
+	}
+
+	var required bool
+	for _, rule := range strings.Split(tag, ",") {
+		key, val, _ := strings.Cut(rule, "=")
+		switch key {
+		case "required":
+			required = true
+		case "format":
+			s.Format = val
+		case "enum":
+			for _, v := range strings.Split(val, "|") {
+				s.Enum = append(s.Enum, v)
+			}
+		case "minLength", "maxLength", "minItems", "maxItems":
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				return false, fmt.Errorf("invalid %q rule: %w", key, err)
+			}
+			switch key {
+			case "minLength":
+				s.MinLength = &n
+			case "maxLength":
+				s.MaxLength = &n
+			case "minItems":
+				s.MinItems = &n
+			case "maxItems":
+				s.MaxItems = &n
+			}
+		case "minimum", "maximum":
+			n, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				return false, fmt.Errorf("invalid %q rule: %w", key, err)
+			}
+			if key == "minimum" {
+				s.Minimum = &n
+			} else {
+				s.Maximum = &n
+			}
+		default:
+			return false, fmt.Errorf("unknown rule %q", key)
+		}
+	}
+
+	return required, nil
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
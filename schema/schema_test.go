@@ -0,0 +1,126 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/schema"
+)
+
+type example struct {
+	Name     string            `json:"name" schema:"required,minLength=1,maxLength=64"`
+	Kind     string            `json:"kind" schema:"enum=a|b"`
+	Count    int               `json:"count" schema:"minimum=0,maximum=10"`
+	Ratio    float64           `json:"ratio"`
+	Enabled  *bool             `json:"enabled"`
+	ID       uuid.UUID         `json:"id" schema:"required"`
+	Time     time.Time         `json:"time"`
+	Tags     []string          `json:"tags" schema:"minItems=1"`
+	Labels   map[string]string `json:"labels"`
+	Untagged string
+	Ignored  string `json:"-"`
+	private  string
+
+	embedded
+}
+
+type embedded struct {
+	Embedded string `json:"embedded"`
+}
+
+type node struct {
+	Name     string `json:"name"`
+	Children []node `json:"children"`
+}
+
+type tree struct {
+	Root node `json:"root"`
+}
+
+func TestOf(t *testing.T) {
+	s := schema.Of(example{}, schema.Title("Example"))
+
+	if s.Schema != schema.Draft {
+		t.Fatalf("$schema should be %q; is %q", schema.Draft, s.Schema)
+	}
+
+	if s.Title != "Example" {
+		t.Fatalf("Title should be %q; is %q", "Example", s.Title)
+	}
+
+	if s.Type != "object" {
+		t.Fatalf("Type should be %q; is %q", "object", s.Type)
+	}
+
+	wantRequired := []string{"name", "id"}
+	if !reflect.DeepEqual(s.Required, wantRequired) {
+		t.Fatalf("Required should be %v; is %v", wantRequired, s.Required)
+	}
+
+	tests := map[string]string{
+		"name":     `{"type":"string","minLength":1,"maxLength":64}`,
+		"kind":     `{"type":"string","enum":["a","b"]}`,
+		"count":    `{"type":"integer","minimum":0,"maximum":10}`,
+		"ratio":    `{"type":"number"}`,
+		"enabled":  `{"type":"boolean"}`,
+		"id":       `{"type":"string","format":"uuid"}`,
+		"time":     `{"type":"string","format":"date-time"}`,
+		"tags":     `{"type":"array","items":{"type":"string"},"minItems":1}`,
+		"labels":   `{"type":"object","additionalProperties":{"type":"string"}}`,
+		"Untagged": `{"type":"string"}`,
+		"embedded": `{"type":"string"}`,
+	}
+
+	if len(s.Properties) != len(tests) {
+		t.Fatalf("Schema should have %d properties; has %d", len(tests), len(s.Properties))
+	}
+
+	for name, want := range tests {
+		b, err := json.Marshal(s.Properties[name])
+		if err != nil {
+			t.Fatalf("marshal %q property: %v", name, err)
+		}
+
+		if string(b) != want {
+			t.Fatalf("%q property should be %s; is %s", name, want, b)
+		}
+	}
+}
+
+func TestOf_recursive(t *testing.T) {
+	s := schema.Of(node{})
+
+	if ref := s.Properties["children"].Items.Ref; ref != "#" {
+		t.Fatalf("recursive root reference should be %q; is %q", "#", ref)
+	}
+
+	s = schema.Of(tree{})
+
+	if ref := s.Properties["root"].Ref; ref != "#/$defs/node" {
+		t.Fatalf("recursive reference should be %q; is %q", "#/$defs/node", ref)
+	}
+
+	def, ok := s.Defs["node"]
+	if !ok {
+		t.Fatalf("recursive type should be added to $defs")
+	}
+
+	if ref := def.Properties["children"].Items.Ref; ref != "#/$defs/node" {
+		t.Fatalf("recursive reference should be %q; is %q", "#/$defs/node", ref)
+	}
+}
+
+func TestOf_invalidRule(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Of should panic for an unknown rule")
+		}
+	}()
+
+	schema.Of(struct {
+		Foo string `schema:"foo"`
+	}{})
+}
@@ -0,0 +1,173 @@
+// Package bleveindex provides a search.Indexer that is backed by Bleve.
+package bleveindex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/modernice/nice-cms/search"
+)
+
+// DefaultLimit is the default number of Hits returned by Search.
+const DefaultLimit = 20
+
+var (
+	_ search.Indexer  = (*Index)(nil)
+	_ search.Searcher = (*Index)(nil)
+)
+
+// Index is a search.Indexer and search.Searcher that is backed by a Bleve
+// index. The names, unique names, tags and paths of Items, and the names of
+// their shelfs or galleries are searchable. Use NewMemory, New or Open to
+// create an Index.
+type Index struct {
+	bleve bleve.Index
+}
+
+// doc is the document of an Item in the Bleve index.
+type doc struct {
+	Kind       string   `json:"kind"`
+	Name       string   `json:"name"`
+	UniqueName string   `json:"uniqueName"`
+	ParentName string   `json:"parentName"`
+	Tags       []string `json:"tags"`
+	Path       string   `json:"path"`
+
+	// Item is the JSON-encoded Item. Item is stored but not indexed.
+	Item string `json:"item"`
+}
+
+// NewMemory returns an Index that is kept in memory.
+func NewMemory() (*Index, error) {
+	idx, err := bleve.NewMemOnly(Mapping())
+	if err != nil {
+		return nil, fmt.Errorf("create bleve index: %w", err)
+	}
+	return &Index{bleve: idx}, nil
+}
+
+// New returns an Index that is stored at the given path. New fails if the
+// path already exists. Use Open to open an existing Index.
+func New(path string) (*Index, error) {
+	idx, err := bleve.New(path, Mapping())
+	if err != nil {
+		return nil, fmt.Errorf("create bleve index at %q: %w", path, err)
+	}
+	return &Index{bleve: idx}, nil
+}
+
+// Open opens the Index that is stored at the given path.
+func Open(path string) (*Index, error) {
+	idx, err := bleve.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open bleve index at %q: %w", path, err)
+	}
+	return &Index{bleve: idx}, nil
+}
+
+// Mapping returns the index mapping of an Index.
+func Mapping() mapping.IndexMapping {
+	text := bleve.NewTextFieldMapping()
+	text.Store = false
+
+	keyword := bleve.NewKeywordFieldMapping()
+	keyword.Store = false
+	keyword.IncludeInAll = false
+
+	stored := bleve.NewTextFieldMapping()
+	stored.Index = false
+	stored.IncludeInAll = false
+
+	dm := bleve.NewDocumentStaticMapping()
+	dm.AddFieldMappingsAt("kind", keyword)
+	dm.AddFieldMappingsAt("name", text)
+	dm.AddFieldMappingsAt("uniqueName", text)
+	dm.AddFieldMappingsAt("parentName", text)
+	dm.AddFieldMappingsAt("tags", text)
+	dm.AddFieldMappingsAt("path", text)
+	dm.AddFieldMappingsAt("item", stored)
+
+	m := bleve.NewIndexMapping()
+	m.DefaultMapping = dm
+	return m
+}
+
+// Bleve returns the underlying Bleve index.
+func (idx *Index) Bleve() bleve.Index {
+	return idx.bleve
+}
+
+// Close closes the Index.
+func (idx *Index) Close() error {
+	return idx.bleve.Close()
+}
+
+// Index adds item to the Index.
+func (idx *Index) Index(_ context.Context, item search.Item) error {
+	b, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("encode item: %w", err)
+	}
+
+	return idx.bleve.Index(item.ID, doc{
+		Kind:       item.Kind,
+		Name:       item.Name,
+		UniqueName: item.UniqueName,
+		ParentName: item.ParentName,
+		Tags:       item.Tags,
+		Path:       item.Path,
+		Item:       string(b),
+	})
+}
+
+// Remove removes the Item with the given ID from the Index.
+func (idx *Index) Remove(_ context.Context, id string) error {
+	return idx.bleve.Delete(id)
+}
+
+// Search returns the page of the Items that match q, sorted by relevance. q
+// uses the Bleve query string syntax, so fields can be queried directly (e.g.
+// "kind:stack tags:hero"). If q is empty, all Items are returned.
+func (idx *Index) Search(ctx context.Context, q string, offset, limit int) (search.Results, error) {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	var bq query.Query = bleve.NewQueryStringQuery(q)
+	if q == "" {
+		bq = bleve.NewMatchAllQuery()
+	}
+
+	req := bleve.NewSearchRequestOptions(bq, limit, offset, false)
+	req.Fields = []string{"item"}
+
+	res, err := idx.bleve.SearchInContext(ctx, req)
+	if err != nil {
+		return search.Results{}, fmt.Errorf("search bleve index: %w", err)
+	}
+
+	results := search.Results{
+		Hits:   make([]search.Hit, 0, len(res.Hits)),
+		Total:  int(res.Total),
+		Offset: offset,
+		Limit:  limit,
+	}
+
+	for _, h := range res.Hits {
+		raw, _ := h.Fields["item"].(string)
+		var item search.Item
+		if err := json.Unmarshal([]byte(raw), &item); err != nil {
+			return search.Results{}, fmt.Errorf("decode item %q: %w", h.ID, err)
+		}
+		results.Hits = append(results.Hits, search.Hit{Item: item, Score: h.Score})
+	}
+
+	return results, nil
+}
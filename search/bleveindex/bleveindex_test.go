@@ -0,0 +1,131 @@
+package bleveindex_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/search"
+	"github.com/modernice/nice-cms/search/bleveindex"
+)
+
+func TestIndex(t *testing.T) {
+	ctx := context.Background()
+
+	idx, err := bleveindex.NewMemory()
+	if err != nil {
+		t.Fatalf("NewMemory failed with %q", err)
+	}
+	defer idx.Close()
+
+	report := newItem(search.KindDocument, "Annual Report", "reports", "finance")
+	invoice := newItem(search.KindDocument, "Invoice", "reports", "finance")
+	beach := newItem(search.KindStack, "Beach", "holidays", "summer")
+
+	for _, item := range []search.Item{report, invoice, beach} {
+		if err := idx.Index(ctx, item); err != nil {
+			t.Fatalf("Index failed with %q", err)
+		}
+	}
+
+	tests := map[string]struct {
+		query string
+		want  []search.Item
+	}{
+		"name":        {query: "report", want: []search.Item{report}},
+		"tag":         {query: "summer", want: []search.Item{beach}},
+		"parent name": {query: "holidays", want: []search.Item{beach}},
+		"field":       {query: "+tags:finance +name:invoice", want: []search.Item{invoice}},
+		"kind":        {query: "kind:stack", want: []search.Item{beach}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := idx.Search(ctx, tt.query, 0, 0)
+			if err != nil {
+				t.Fatalf("Search failed with %q", err)
+			}
+			expectHits(t, res, tt.want...)
+		})
+	}
+
+	res, err := idx.Search(ctx, "", 1, 1)
+	if err != nil {
+		t.Fatalf("Search failed with %q", err)
+	}
+	if res.Total != 3 || len(res.Hits) != 1 || res.Offset != 1 || res.Limit != 1 {
+		t.Fatalf("Search should return 1 of 3 Hits at offset 1; got %d of %d at offset %d", len(res.Hits), res.Total, res.Offset)
+	}
+
+	if err := idx.Remove(ctx, report.ID); err != nil {
+		t.Fatalf("Remove failed with %q", err)
+	}
+	if err := idx.Remove(ctx, "unknown"); err != nil {
+		t.Fatalf("Remove should not fail for an unknown Item; failed with %q", err)
+	}
+
+	res, err = idx.Search(ctx, "report", 0, 0)
+	if err != nil {
+		t.Fatalf("Search failed with %q", err)
+	}
+	expectHits(t, res)
+}
+
+func TestOpen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "index")
+
+	idx, err := bleveindex.New(path)
+	if err != nil {
+		t.Fatalf("New failed with %q", err)
+	}
+
+	item := newItem(search.KindStack, "Beach", "holidays")
+	if err := idx.Index(ctx, item); err != nil {
+		t.Fatalf("Index failed with %q", err)
+	}
+	if err := idx.Close(); err != nil {
+		t.Fatalf("Close failed with %q", err)
+	}
+
+	if idx, err = bleveindex.Open(path); err != nil {
+		t.Fatalf("Open failed with %q", err)
+	}
+	defer idx.Close()
+
+	res, err := idx.Search(ctx, "beach", 0, 0)
+	if err != nil {
+		t.Fatalf("Search failed with %q", err)
+	}
+	expectHits(t, res, item)
+}
+
+func newItem(kind, name, parentName string, tags ...string) search.Item {
+	ref := uuid.New()
+	return search.Item{
+		ID:         search.ItemID(kind, ref),
+		Kind:       kind,
+		Ref:        ref,
+		Parent:     uuid.New(),
+		ParentName: parentName,
+		Name:       name,
+		Tags:       append([]string{}, tags...),
+		Disk:       "foo-disk",
+		Path:       "/foo/" + ref.String(),
+	}
+}
+
+func expectHits(t *testing.T, res search.Results, items ...search.Item) {
+	t.Helper()
+
+	if res.Total != len(items) || len(res.Hits) != len(items) {
+		t.Fatalf("Search should return %d Hits; returned %d of %d", len(items), len(res.Hits), res.Total)
+	}
+
+	for i, hit := range res.Hits {
+		if hit.ID != items[i].ID || hit.Name != items[i].Name || hit.Ref != items[i].Ref || hit.Parent != items[i].Parent {
+			t.Fatalf("Hits[%d] should be %#v; is %#v", i, items[i], hit.Item)
+		}
+	}
+}
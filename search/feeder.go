@@ -0,0 +1,253 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/internal/ordered"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/projector"
+)
+
+// Feeder is a projection of shelfs and galleries that keeps an Indexer up to
+// date. Trashed documents and stacks are removed from the Indexer. Failed
+// Index and Remove calls are retried when the next event is applied. Use
+// NewFeeder to create a Feeder.
+type Feeder struct {
+	indexer   Indexer
+	projector *projector.Projector
+
+	mux       sync.Mutex
+	ctx       context.Context
+	errs      chan error
+	shelfs    map[uuid.UUID]*feedShelf
+	galleries map[uuid.UUID]*feedGallery
+
+	// indexed are the Items that were passed to the Indexer, keyed by their
+	// aggregates.
+	indexed map[uuid.UUID]map[string]Item
+
+	// pending are the Items that must be passed to the Indexer. A nil Item
+	// must be removed from the Indexer.
+	pending map[string]*Item
+}
+
+type feedShelf struct {
+	*document.Shelf
+
+	events ordered.Events
+}
+
+type feedGallery struct {
+	*gallery.Gallery
+
+	events ordered.Events
+}
+
+// NewFeeder returns a new Feeder that feeds the Items of shelfs and galleries
+// into idx. The provided options configure the error handling of the
+// projection.
+func NewFeeder(idx Indexer, opts ...projector.Option) *Feeder {
+	return &Feeder{
+		indexer:   idx,
+		projector: projector.New(opts...),
+		ctx:       context.Background(),
+		shelfs:    make(map[uuid.UUID]*feedShelf),
+		galleries: make(map[uuid.UUID]*feedGallery),
+		indexed:   make(map[uuid.UUID]map[string]Item),
+		pending:   make(map[string]*Item),
+	}
+}
+
+// Events returns the events that are projected by a Feeder.
+func Events() []string {
+	events := make([]string, 0, len(document.Events)+len(gallery.Events))
+	events = append(events, document.Events[:]...)
+	return append(events, gallery.Events[:]...)
+}
+
+// Project projects the Feeder in a new goroutine and returns a channel of
+// asynchronous errors. Failed projection jobs are handled according to the
+// projector.Policy of the Feeder. Errors of the Indexer are also reported on
+// the returned channel. The Indexer is called with ctx.
+func (f *Feeder) Project(ctx context.Context, bus event.Bus, store event.Store, opts ...schedule.ContinuousOption) (<-chan error, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	indexErrs := make(chan error)
+	f.mux.Lock()
+	f.ctx = ctx
+	f.errs = indexErrs
+	f.mux.Unlock()
+
+	schedule := schedule.Continuously(bus, store, Events(), opts...)
+	errs, err := f.projector.Run(ctx, schedule, f)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	out := make(chan error)
+	go func() {
+		defer close(out)
+		defer cancel()
+		for {
+			var err error
+			select {
+			case e, ok := <-errs:
+				if !ok {
+					return
+				}
+				err = e
+			case err = <-indexErrs:
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- err:
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// Status returns the projection status of the Feeder.
+func (f *Feeder) Status() projector.Status {
+	return f.projector.Status()
+}
+
+// ApplyEvent applies aggregate events.
+func (f *Feeder) ApplyEvent(evt event.Event) {
+	id, name, _ := evt.Aggregate()
+
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	switch name {
+	case document.Aggregate:
+		shelf, ok := f.shelfs[id]
+		if !ok {
+			shelf = &feedShelf{Shelf: document.NewShelf(id)}
+			f.shelfs[id] = shelf
+		}
+		if shelf.events.Apply(evt, shelf.ApplyEvent) > 0 {
+			f.update(id, shelfItems(shelf.Shelf))
+		}
+	case gallery.Aggregate:
+		g, ok := f.galleries[id]
+		if !ok {
+			g = &feedGallery{Gallery: gallery.New(id)}
+			f.galleries[id] = g
+		}
+		if g.events.Apply(evt, g.ApplyEvent) > 0 {
+			f.update(id, galleryItems(g.Gallery))
+		}
+	default:
+		return
+	}
+
+	f.flush()
+}
+
+// update schedules the changes between the indexed Items of an aggregate and
+// its current Items.
+func (f *Feeder) update(aggregateID uuid.UUID, items []Item) {
+	prev := f.indexed[aggregateID]
+	next := make(map[string]Item, len(items))
+
+	for _, item := range items {
+		next[item.ID] = item
+		if old, ok := prev[item.ID]; ok && old.equal(item) {
+			continue
+		}
+		item := item
+		f.pending[item.ID] = &item
+	}
+
+	for id := range prev {
+		if _, ok := next[id]; !ok {
+			f.pending[id] = nil
+		}
+	}
+
+	f.indexed[aggregateID] = next
+}
+
+// flush passes the pending Items to the Indexer. Items that fail stay pending.
+func (f *Feeder) flush() {
+	for id, item := range f.pending {
+		var err error
+		if item == nil {
+			if err = f.indexer.Remove(f.ctx, id); err != nil {
+				err = fmt.Errorf("remove %q from index: %w", id, err)
+			}
+		} else if err = f.indexer.Index(f.ctx, *item); err != nil {
+			err = fmt.Errorf("index %q: %w", id, err)
+		}
+
+		if err == nil {
+			delete(f.pending, id)
+			continue
+		}
+
+		if f.errs != nil {
+			select {
+			case <-f.ctx.Done():
+			case f.errs <- err:
+			}
+		}
+	}
+}
+
+func shelfItems(shelf *document.Shelf) []Item {
+	if shelf.Name == "" {
+		return nil
+	}
+	items := make([]Item, 0, len(shelf.Documents))
+	for _, doc := range shelf.Documents {
+		items = append(items, Item{
+			ID:         ItemID(KindDocument, doc.ID),
+			Kind:       KindDocument,
+			Ref:        doc.ID,
+			Parent:     shelf.ID,
+			ParentName: shelf.Name,
+			Name:       doc.Name,
+			UniqueName: doc.UniqueName,
+			Tags:       copyTags(doc.Tags),
+			Disk:       doc.Disk,
+			Path:       doc.Path,
+		})
+	}
+	return items
+}
+
+func galleryItems(g *gallery.Gallery) []Item {
+	if !g.Created() {
+		return nil
+	}
+	items := make([]Item, 0, len(g.Stacks))
+	for _, stack := range g.Stacks {
+		org := stack.Original()
+		items = append(items, Item{
+			ID:         ItemID(KindStack, stack.ID),
+			Kind:       KindStack,
+			Ref:        stack.ID,
+			Parent:     g.ID,
+			ParentName: g.Implementation.Name,
+			Name:       org.Name,
+			Tags:       copyTags(org.Tags),
+			Disk:       org.Disk,
+			Path:       org.Path,
+		})
+	}
+	return items
+}
+
+func copyTags(tags []string) []string {
+	return append([]string{}, tags...)
+}
@@ -0,0 +1,218 @@
+package search_test
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/search"
+)
+
+func TestFeeder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	repo := repository.New(estore)
+	shelfs := document.GoesRepository(repo)
+	galleries := gallery.GoesRepository(repo)
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+
+	idx := newIndexer()
+	feeder := search.NewFeeder(idx)
+
+	errs, err := feeder.Project(ctx, ebus, estore)
+	if err != nil {
+		t.Fatalf("project feeder: %v", err)
+	}
+	go func() {
+		for err := range errs {
+			panic(err)
+		}
+	}()
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create("reports")
+	doc, err := shelf.Add(ctx, storage, strings.NewReader("annual report"), "report", "Annual Report", "foo-disk", "/reports/annual.txt")
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+	if _, err := shelf.Tag(doc.ID, "finance"); err != nil {
+		t.Fatalf("tag document: %v", err)
+	}
+
+	g := gallery.New(uuid.New())
+	g.Create("holidays")
+	_, buf := imggen.ColoredRectangle(8, 6, color.White)
+	stack, err := g.Upload(ctx, storage, buf, "Beach", "foo-disk", "/holidays/beach.png")
+	if err != nil {
+		t.Fatalf("upload image: %v", err)
+	}
+
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save shelf: %v", err)
+	}
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save gallery: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	want := map[string]search.Item{
+		search.ItemID(search.KindDocument, doc.ID): {
+			ID:         search.ItemID(search.KindDocument, doc.ID),
+			Kind:       search.KindDocument,
+			Ref:        doc.ID,
+			Parent:     shelf.ID,
+			ParentName: "reports",
+			Name:       "Annual Report",
+			UniqueName: "report",
+			Tags:       []string{"finance"},
+			Disk:       "foo-disk",
+			Path:       "/reports/annual.txt",
+		},
+		search.ItemID(search.KindStack, stack.ID): {
+			ID:         search.ItemID(search.KindStack, stack.ID),
+			Kind:       search.KindStack,
+			Ref:        stack.ID,
+			Parent:     g.ID,
+			ParentName: "holidays",
+			Name:       "Beach",
+			Tags:       []string{},
+			Disk:       "foo-disk",
+			Path:       "/holidays/beach.png",
+		},
+	}
+	idx.expect(t, want)
+
+	if _, err := shelf.RenameDocument(doc.ID, "Annual Report 2022"); err != nil {
+		t.Fatalf("rename document: %v", err)
+	}
+	if err := shelfs.Save(ctx, shelf); err != nil {
+		t.Fatalf("save shelf: %v", err)
+	}
+
+	if err := g.Delete(ctx, storage, stack); err != nil {
+		t.Fatalf("delete stack: %v", err)
+	}
+	if err := galleries.Save(ctx, g); err != nil {
+		t.Fatalf("save gallery: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	item := want[search.ItemID(search.KindDocument, doc.ID)]
+	item.Name = "Annual Report 2022"
+	idx.expect(t, map[string]search.Item{item.ID: item})
+}
+
+func TestFeeder_ApplyEvent_retry(t *testing.T) {
+	ctx := context.Background()
+	storage := media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))
+
+	idx := newIndexer()
+	idx.fail = true
+	feeder := search.NewFeeder(idx)
+
+	shelf := document.NewShelf(uuid.New())
+	shelf.Create("reports")
+	for _, evt := range shelf.AggregateChanges() {
+		feeder.ApplyEvent(evt)
+	}
+	shelf.Commit()
+
+	doc, err := shelf.Add(ctx, storage, strings.NewReader("annual report"), "", "Annual Report", "foo-disk", "/reports/annual.txt")
+	if err != nil {
+		t.Fatalf("add document: %v", err)
+	}
+	for _, evt := range shelf.AggregateChanges() {
+		feeder.ApplyEvent(evt)
+	}
+	shelf.Commit()
+
+	idx.expect(t, map[string]search.Item{})
+
+	idx.mux.Lock()
+	idx.fail = false
+	idx.mux.Unlock()
+
+	// The failed Item is indexed when the next event is applied.
+	if _, err := shelf.Tag(doc.ID, "finance"); err != nil {
+		t.Fatalf("tag document: %v", err)
+	}
+	for _, evt := range shelf.AggregateChanges() {
+		feeder.ApplyEvent(evt)
+	}
+
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+	item, ok := idx.items[search.ItemID(search.KindDocument, doc.ID)]
+	if !ok || len(item.Tags) != 1 || item.Tags[0] != "finance" {
+		t.Fatalf("Document should be indexed with tag %q; got %v", "finance", item)
+	}
+}
+
+type indexer struct {
+	mux   sync.Mutex
+	items map[string]search.Item
+	fail  bool
+}
+
+func newIndexer() *indexer {
+	return &indexer{items: make(map[string]search.Item)}
+}
+
+func (idx *indexer) Index(_ context.Context, item search.Item) error {
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+	if idx.fail {
+		return errors.New("index unavailable")
+	}
+	idx.items[item.ID] = item
+	return nil
+}
+
+func (idx *indexer) Remove(_ context.Context, id string) error {
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+	if idx.fail {
+		return errors.New("index unavailable")
+	}
+	delete(idx.items, id)
+	return nil
+}
+
+func (idx *indexer) expect(t *testing.T, want map[string]search.Item) {
+	t.Helper()
+
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+
+	if len(idx.items) != len(want) {
+		t.Fatalf("Indexer should have %d Items; has %d (%v)", len(want), len(idx.items), idx.items)
+	}
+
+	for id, item := range want {
+		got, ok := idx.items[id]
+		if !ok {
+			t.Fatalf("Indexer should have Item %q", id)
+		}
+		if !reflect.DeepEqual(got, item) {
+			t.Fatalf("Item %q should be\n\n%#v\n\ngot\n\n%#v", id, item, got)
+		}
+	}
+}
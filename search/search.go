@@ -0,0 +1,116 @@
+// Package search feeds the documents of shelfs and the stacks of galleries into
+// a full-text search index.
+//
+// An Indexer is the backend of the search index. A Feeder projects the events
+// of shelfs and galleries into Items and passes new and updated Items to the
+// Indexer, and removes Items from the Indexer when their document or stack is
+// removed:
+//
+//	idx, err := bleveindex.NewMemory()
+//	feeder := search.NewFeeder(idx)
+//	errs, err := feeder.Project(ctx, events, store)
+//
+// The bleveindex package provides an Indexer that is backed by Bleve.
+//
+// Pages are not indexed; the Feeder only projects shelfs and galleries.
+package search
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of Items.
+const (
+	// KindDocument is the Kind of an Item of a document.Document.
+	KindDocument = "document"
+
+	// KindStack is the Kind of an Item of a gallery.Stack.
+	KindStack = "stack"
+)
+
+// Item is a document or stack in a search index.
+type Item struct {
+	// ID is the ID of the Item in the search index. See ItemID.
+	ID string `json:"id"`
+
+	// Kind is either KindDocument or KindStack.
+	Kind string `json:"kind"`
+
+	// Ref is the UUID of the document or stack.
+	Ref uuid.UUID `json:"ref"`
+
+	// Parent is the UUID of the shelf or gallery of the Item.
+	Parent uuid.UUID `json:"parent"`
+
+	// ParentName is the name of the shelf or gallery of the Item.
+	ParentName string `json:"parentName"`
+
+	Name       string   `json:"name"`
+	UniqueName string   `json:"uniqueName,omitempty"`
+	Tags       []string `json:"tags"`
+	Disk       string   `json:"disk"`
+	Path       string   `json:"path"`
+}
+
+// ItemID returns the ID of the Item of the given kind and reference.
+func ItemID(kind string, ref uuid.UUID) string {
+	return fmt.Sprintf("%s:%s", kind, ref)
+}
+
+// Indexer is the backend of a search index. Index adds an Item to the index or
+// replaces the Item with the same ID. Remove removes the Item with the given ID
+// from the index. Removing an Item that is not indexed must not fail.
+type Indexer interface {
+	Index(context.Context, Item) error
+	Remove(context.Context, string) error
+}
+
+// Searcher is a search index that can be queried. Indexers may implement
+// Searcher.
+type Searcher interface {
+	// Search returns the page of the Items that match the search term q,
+	// sorted by relevance. If limit is 0, a default limit is used.
+	Search(ctx context.Context, q string, offset, limit int) (Results, error)
+}
+
+// Hit is an Item that was found by a Searcher.
+type Hit struct {
+	Item
+
+	Score float64 `json:"score"`
+}
+
+// Results is a page of the Items that match a search term.
+type Results struct {
+	Hits []Hit `json:"hits"`
+
+	// Total is the number of Items that match the search term.
+	Total int `json:"total"`
+
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+func (it Item) equal(other Item) bool {
+	if it.ID != other.ID ||
+		it.Kind != other.Kind ||
+		it.Ref != other.Ref ||
+		it.Parent != other.Parent ||
+		it.ParentName != other.ParentName ||
+		it.Name != other.Name ||
+		it.UniqueName != other.UniqueName ||
+		it.Disk != other.Disk ||
+		it.Path != other.Path ||
+		len(it.Tags) != len(other.Tags) {
+		return false
+	}
+	for i, tag := range it.Tags {
+		if other.Tags[i] != tag {
+			return false
+		}
+	}
+	return true
+}
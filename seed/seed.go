@@ -0,0 +1,795 @@
+// Package seed creates the content of new installations from a declarative
+// Manifest. Seeding is idempotent, so it can run on every startup: existing
+// galleries, shelfs, pages and navigations are reused, files that have already
+// been uploaded (identified by their checksum) are skipped and page fields and
+// navigation items are only added if they don't exist yet. If the seeded
+// entries are recorded (see Record), content that editors deleted is not
+// seeded again.
+//
+//	//go:embed seed
+//	var files embed.FS
+//
+//	m, err := seed.Load(files, "seed/manifest.json")
+//	s := seed.New(files,
+//		seed.Storage(storage),
+//		seed.Galleries(galleries, galleryLookup.GalleryName),
+//		seed.Shelfs(shelfs, shelfLookup.ShelfName),
+//		seed.Pages(pages, nil),
+//		seed.Navs(navs, navLookup.Name),
+//		seed.Record(repo),
+//	)
+//	report, err := s.Seed(ctx, m)
+//
+// Content that an application requires, like the pages and fields its
+// templates render, is created by Ensure instead, which protects the content
+// from being removed by editors.
+package seed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+var (
+	// ErrInvalidManifest is returned when loading or seeding an invalid
+	// Manifest.
+	ErrInvalidManifest = errors.New("invalid manifest")
+
+	// ErrNotConfigured is returned when seeding a Manifest that contains
+	// content for which the Seeder has no repository or storage.
+	ErrNotConfigured = errors.New("seeder not configured")
+)
+
+// DefaultNamespace is the default namespace of the UUIDs of seeded aggregates.
+var DefaultNamespace = uuid.MustParse("3f0bb6e8-8f0e-4c7a-9d07-4ad3a0f6c2d1")
+
+// Manifest describes the content that is seeded.
+type Manifest struct {
+	Galleries []Gallery `json:"galleries"`
+	Shelfs    []Shelf   `json:"shelfs"`
+	Pages     []Page    `json:"pages"`
+	Navs      []Nav     `json:"navs"`
+}
+
+// Gallery is a seeded Gallery.
+type Gallery struct {
+	Name   string `json:"name"`
+	Images []File `json:"images"`
+}
+
+// Shelf is a seeded Shelf.
+type Shelf struct {
+	Name      string     `json:"name"`
+	Documents []Document `json:"documents"`
+}
+
+// Document is a seeded Document.
+type Document struct {
+	File
+
+	UniqueName string `json:"uniqueName"`
+}
+
+// File is a seeded file. Its contents are read from Source in the filesystem
+// of the Seeder and uploaded to Path of Disk.
+type File struct {
+	Name   string `json:"name"`
+	Disk   string `json:"disk"`
+	Path   string `json:"path"`
+	Source string `json:"source"`
+}
+
+// Page is a seeded Page. The values of the Fields are the values that are
+// used when a Field is added to the Page. Fields are encoded like the Fields
+// of a Page (e.g. {"Name": "title", "Type": "text", "Values": {"": "Home"}}).
+type Page struct {
+	Name   string        `json:"name"`
+	Fields []field.Field `json:"fields"`
+}
+
+// Nav is a seeded navigation.
+type Nav struct {
+	Name  string     `json:"name"`
+	Items []nav.Item `json:"items"`
+}
+
+// Load reads and validates the JSON Manifest at the given path of fsys.
+func Load(fsys fs.FS, path string) (Manifest, error) {
+	b, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("read manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return m, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+
+	if err := m.Validate(); err != nil {
+		return m, err
+	}
+
+	return m, nil
+}
+
+// Validate validates the Manifest. Names must not be empty and must be unique
+// within their kind, the names of the Fields of a Page must be unique, and
+// files must have a disk, path and source.
+func (m Manifest) Validate() error {
+	galleries := make(map[string]bool)
+	for _, g := range m.Galleries {
+		if err := validateName("gallery", g.Name, galleries); err != nil {
+			return err
+		}
+		for _, f := range g.Images {
+			if err := f.validate(); err != nil {
+				return fmt.Errorf("gallery %q: %w", g.Name, err)
+			}
+		}
+	}
+
+	shelfs := make(map[string]bool)
+	for _, s := range m.Shelfs {
+		if err := validateName("shelf", s.Name, shelfs); err != nil {
+			return err
+		}
+		for _, doc := range s.Documents {
+			if err := doc.validate(); err != nil {
+				return fmt.Errorf("shelf %q: %w", s.Name, err)
+			}
+		}
+	}
+
+	pages := make(map[string]bool)
+	for _, p := range m.Pages {
+		if err := validateName("page", p.Name, pages); err != nil {
+			return err
+		}
+		fields := make(map[string]bool)
+		for _, f := range p.Fields {
+			if err := validateName("field", f.Name, fields); err != nil {
+				return fmt.Errorf("page %q: %w", p.Name, err)
+			}
+		}
+	}
+
+	navs := make(map[string]bool)
+	for _, n := range m.Navs {
+		if err := validateName("nav", n.Name, navs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateName(kind, name string, seen map[string]bool) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%w: %s without name", ErrInvalidManifest, kind)
+	}
+	if seen[name] {
+		return fmt.Errorf("%w: duplicate %s %q", ErrInvalidManifest, kind, name)
+	}
+	seen[name] = true
+	return nil
+}
+
+func (f File) validate() error {
+	if f.Disk == "" || f.Path == "" || f.Source == "" {
+		return fmt.Errorf("%w: file %q needs a disk, path and source", ErrInvalidManifest, f.Source)
+	}
+	return nil
+}
+
+// NameLookup returns the UUID of the aggregate with the given name. The name
+// lookups of the gallery, document, page and nav packages can be used as
+// NameLookups (e.g. (*gallery.Lookup).GalleryName).
+type NameLookup func(name string) (uuid.UUID, bool)
+
+// Option is a Seeder option.
+type Option func(*Seeder)
+
+// Storage returns an Option that sets the Storage to which seeded files are
+// uploaded.
+func Storage(storage media.Storage) Option {
+	return func(s *Seeder) {
+		s.storage = storage
+	}
+}
+
+// Galleries returns an Option that sets the repository of seeded Galleries.
+// If lookup is non-nil, an existing Gallery with the seeded name is reused,
+// even if it wasn't created by the Seeder.
+func Galleries(repo gallery.Repository, lookup NameLookup) Option {
+	return func(s *Seeder) {
+		s.galleries = repo
+		s.galleryNames = lookup
+	}
+}
+
+// Shelfs returns an Option that sets the repository of seeded Shelfs. If
+// lookup is non-nil, an existing Shelf with the seeded name is reused, even if
+// it wasn't created by the Seeder.
+func Shelfs(repo document.Repository, lookup NameLookup) Option {
+	return func(s *Seeder) {
+		s.shelfs = repo
+		s.shelfNames = lookup
+	}
+}
+
+// Pages returns an Option that sets the repository of seeded Pages. If lookup
+// is non-nil, an existing Page with the seeded name is reused, even if it
+// wasn't created by the Seeder.
+func Pages(repo page.Repository, lookup NameLookup) Option {
+	return func(s *Seeder) {
+		s.pages = repo
+		s.pageNames = lookup
+	}
+}
+
+// Navs returns an Option that sets the repository of seeded navigations. If
+// lookup is non-nil, an existing Nav with the seeded name is reused, even if
+// it wasn't created by the Seeder.
+func Navs(repo nav.Repository, lookup NameLookup) Option {
+	return func(s *Seeder) {
+		s.navs = repo
+		s.navNames = lookup
+	}
+}
+
+// Namespace returns an Option that sets the namespace of the UUIDs of seeded
+// aggregates. Seeded aggregates get UUIDs that are derived from their names,
+// so that seeding the same Manifest again finds the seeded aggregates, even if
+// no NameLookup is provided. Default is DefaultNamespace.
+func Namespace(ns uuid.UUID) Option {
+	return func(s *Seeder) {
+		s.namespace = ns
+	}
+}
+
+// Record returns an Option that records the seeded entries in a State that is
+// saved in the given repository. Entries that have been seeded are not seeded
+// again, so galleries, shelfs, pages, navigations, files, fields and items
+// that editors deleted are not re-created. Content that exists but wasn't
+// recorded yet, e.g. because it was seeded before the entries were recorded,
+// is recorded, too. Without Record, missing content is always seeded.
+func Record(repo aggregate.Repository) Option {
+	return func(s *Seeder) {
+		s.states = repo
+	}
+}
+
+// Seeder seeds Manifests.
+type Seeder struct {
+	files     fs.FS
+	storage   media.Storage
+	namespace uuid.UUID
+	states    aggregate.Repository
+
+	galleries    gallery.Repository
+	galleryNames NameLookup
+	shelfs       document.Repository
+	shelfNames   NameLookup
+	pages        page.Repository
+	pageNames    NameLookup
+	navs         nav.Repository
+	navNames     NameLookup
+}
+
+// Report reports the content that was created by Seed.
+type Report struct {
+	Galleries int `json:"galleries"`
+	Stacks    int `json:"stacks"`
+	Shelfs    int `json:"shelfs"`
+	Documents int `json:"documents"`
+	Pages     int `json:"pages"`
+	Fields    int `json:"fields"`
+	Navs      int `json:"navs"`
+	NavItems  int `json:"navItems"`
+}
+
+// Empty returns whether nothing was created.
+func (r Report) Empty() bool {
+	return r == Report{}
+}
+
+// New returns a Seeder that reads the sources of seeded files from files.
+func New(files fs.FS, opts ...Option) *Seeder {
+	s := Seeder{
+		files:     files,
+		namespace: DefaultNamespace,
+	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return &s
+}
+
+// Seed creates the content of the Manifest that doesn't exist yet. If the
+// seeded entries are recorded (see Record), entries that have already been
+// seeded are skipped.
+func (s *Seeder) Seed(ctx context.Context, m Manifest) (Report, error) {
+	return s.seed(ctx, m, false)
+}
+
+// Ensure creates the content of the Manifest that an application requires and
+// that doesn't exist yet, like Seed. Unlike seeded content, required content
+// cannot be removed by editors: Fields are added as guarded Fields and Items
+// as initial Items. Fields and Items that already exist, e.g. because they were
+// seeded or created by editors, are left unchanged and stay unprotected, so
+// content must be required by Ensure before it is created otherwise. Required
+// content is usually declared in code:
+//
+//	report, err := s.Ensure(ctx, seed.Manifest{
+//		Galleries: []seed.Gallery{{Name: "Products"}},
+//		Pages: []seed.Page{{
+//			Name:   "home",
+//			Fields: []field.Field{field.NewText("title", "Welcome")},
+//		}},
+//		Navs: []seed.Nav{{
+//			Name:  "main",
+//			Items: []nav.Item{nav.NewStaticLink("home", "/", "Home")},
+//		}},
+//	})
+func (s *Seeder) Ensure(ctx context.Context, m Manifest) (Report, error) {
+	pages := make([]Page, len(m.Pages))
+	for i, p := range m.Pages {
+		fields := make([]field.Field, len(p.Fields))
+		for j, f := range p.Fields {
+			f.Guarded = true
+			fields[j] = f
+		}
+		pages[i] = Page{Name: p.Name, Fields: fields}
+	}
+	m.Pages = pages
+
+	navs := make([]Nav, len(m.Navs))
+	for i, n := range m.Navs {
+		items := make([]nav.Item, len(n.Items))
+		for j, item := range n.Items {
+			items[j] = initial(item)
+		}
+		navs[i] = Nav{Name: n.Name, Items: items}
+	}
+	m.Navs = navs
+
+	return s.seed(ctx, m, true)
+}
+
+// run is a single run of a Seeder.
+type run struct {
+	*Seeder
+
+	// state is the recorded State, or nil if the seeded entries are not
+	// recorded.
+	state *State
+
+	// required is true if the content is required (see Ensure), so recorded
+	// entries are seeded again.
+	required bool
+
+	report Report
+}
+
+func (s *Seeder) seed(ctx context.Context, m Manifest, required bool) (Report, error) {
+	r := run{Seeder: s, required: required}
+
+	if err := m.Validate(); err != nil {
+		return r.report, err
+	}
+
+	if err := s.checkConfig(m); err != nil {
+		return r.report, err
+	}
+
+	if s.states != nil {
+		r.state = NewState(uuid.NewSHA1(s.namespace, []byte(Aggregate)))
+		if err := s.states.Fetch(ctx, r.state); err != nil {
+			return r.report, fmt.Errorf("fetch state: %w", err)
+		}
+	}
+
+	err := r.seed(ctx, m)
+
+	// The entries that were seeded before an error are recorded, too.
+	if r.state != nil && len(r.state.AggregateChanges()) > 0 {
+		if serr := s.states.Save(ctx, r.state); serr != nil && err == nil {
+			err = fmt.Errorf("save state: %w", serr)
+		}
+	}
+
+	return r.report, err
+}
+
+func (r *run) seed(ctx context.Context, m Manifest) error {
+	for _, g := range m.Galleries {
+		if err := r.seedGallery(ctx, g); err != nil {
+			return fmt.Errorf("seed gallery %q: %w", g.Name, err)
+		}
+	}
+
+	for _, sh := range m.Shelfs {
+		if err := r.seedShelf(ctx, sh); err != nil {
+			return fmt.Errorf("seed shelf %q: %w", sh.Name, err)
+		}
+	}
+
+	for _, p := range m.Pages {
+		if err := r.seedPage(ctx, p); err != nil {
+			return fmt.Errorf("seed page %q: %w", p.Name, err)
+		}
+	}
+
+	for _, n := range m.Navs {
+		if err := r.seedNav(ctx, n); err != nil {
+			return fmt.Errorf("seed nav %q: %w", n.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// skip returns whether the given entry is skipped because it has already been
+// seeded.
+func (r *run) skip(entry string) bool {
+	return !r.required && r.state != nil && r.state.Seeded(entry)
+}
+
+// record records the given entries as seeded.
+func (r *run) record(entries ...string) {
+	if r.state != nil {
+		r.state.Record(entries...)
+	}
+}
+
+func (s *Seeder) checkConfig(m Manifest) error {
+	if len(m.Galleries) > 0 && s.galleries == nil {
+		return fmt.Errorf("%w: no gallery repository", ErrNotConfigured)
+	}
+	if len(m.Shelfs) > 0 && s.shelfs == nil {
+		return fmt.Errorf("%w: no shelf repository", ErrNotConfigured)
+	}
+	if len(m.Pages) > 0 && s.pages == nil {
+		return fmt.Errorf("%w: no page repository", ErrNotConfigured)
+	}
+	if len(m.Navs) > 0 && s.navs == nil {
+		return fmt.Errorf("%w: no nav repository", ErrNotConfigured)
+	}
+	if m.hasFiles() && s.storage == nil {
+		return fmt.Errorf("%w: no storage", ErrNotConfigured)
+	}
+	return nil
+}
+
+func (m Manifest) hasFiles() bool {
+	for _, g := range m.Galleries {
+		if len(g.Images) > 0 {
+			return true
+		}
+	}
+	for _, s := range m.Shelfs {
+		if len(s.Documents) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Seeder) id(kind, name string, lookup NameLookup) uuid.UUID {
+	if lookup != nil {
+		if id, ok := lookup(name); ok {
+			return id
+		}
+	}
+	return uuid.NewSHA1(s.namespace, []byte(kind+":"+name))
+}
+
+func (r *run) seedGallery(ctx context.Context, sg Gallery) error {
+	g, err := r.galleries.Fetch(ctx, r.id(gallery.Aggregate, sg.Name, r.galleryNames))
+	if err != nil {
+		return fmt.Errorf("fetch gallery: %w", err)
+	}
+
+	entry := "gallery:" + sg.Name
+	var changed bool
+	if g.AggregateVersion() == 0 {
+		if r.skip(entry) {
+			return nil
+		}
+		if err := g.Create(sg.Name); err != nil {
+			return err
+		}
+		changed = true
+		r.report.Galleries++
+	}
+	r.record(entry)
+
+	for _, img := range sg.Images {
+		b, checksum, err := r.read(img.Source)
+		if err != nil {
+			return err
+		}
+
+		entry := "gallery:" + sg.Name + "/image:" + checksum
+		if _, err := g.FindStackByChecksum(checksum); err == nil {
+			r.record(entry)
+			continue
+		}
+		if r.skip(entry) {
+			continue
+		}
+
+		if _, err := g.Upload(ctx, r.storage, bytes.NewReader(b), img.Name, img.Disk, img.Path); err != nil {
+			return fmt.Errorf("upload %q: %w", img.Source, err)
+		}
+		changed = true
+		r.report.Stacks++
+		r.record(entry)
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return r.galleries.Save(ctx, g)
+}
+
+func (r *run) seedShelf(ctx context.Context, ss Shelf) error {
+	shelf, err := r.shelfs.Fetch(ctx, r.id(document.Aggregate, ss.Name, r.shelfNames))
+	if err != nil {
+		return fmt.Errorf("fetch shelf: %w", err)
+	}
+
+	entry := "shelf:" + ss.Name
+	var changed bool
+	if shelf.AggregateVersion() == 0 {
+		if r.skip(entry) {
+			return nil
+		}
+		if err := shelf.Create(ss.Name); err != nil {
+			return err
+		}
+		changed = true
+		r.report.Shelfs++
+	}
+	r.record(entry)
+
+	for _, doc := range ss.Documents {
+		b, checksum, err := r.read(doc.Source)
+		if err != nil {
+			return err
+		}
+
+		entry := "shelf:" + ss.Name + "/document:" + checksum
+		if _, err := shelf.FindByChecksum(checksum); err == nil {
+			r.record(entry)
+			continue
+		}
+		if r.skip(entry) {
+			continue
+		}
+
+		if _, err := shelf.Add(ctx, r.storage, bytes.NewReader(b), doc.UniqueName, doc.Name, doc.Disk, doc.Path); err != nil {
+			return fmt.Errorf("add %q: %w", doc.Source, err)
+		}
+		changed = true
+		r.report.Documents++
+		r.record(entry)
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return r.shelfs.Save(ctx, shelf)
+}
+
+func (r *run) seedPage(ctx context.Context, sp Page) error {
+	p, err := r.pages.Fetch(ctx, r.id(page.Aggregate, sp.Name, r.pageNames))
+	if err != nil {
+		return fmt.Errorf("fetch page: %w", err)
+	}
+
+	// Destroyed Pages may still be in the event store, so Pages are missing
+	// if they have no name.
+	entry := "page:" + sp.Name
+	var changed bool
+	if p.Name == "" {
+		if r.skip(entry) {
+			return nil
+		}
+		if err := p.Create(sp.Name); err != nil {
+			return err
+		}
+		changed = true
+		r.report.Pages++
+	}
+	r.record(entry)
+
+	var missing []field.Field
+	for _, f := range sp.Fields {
+		entry := "page:" + sp.Name + "/field:" + f.Name
+		if _, err := p.Field(f.Name); err == nil {
+			r.record(entry)
+			continue
+		}
+		if r.skip(entry) {
+			continue
+		}
+		missing = append(missing, f)
+		r.record(entry)
+	}
+
+	if len(missing) > 0 {
+		if err := p.Add(missing...); err != nil {
+			return fmt.Errorf("add fields: %w", err)
+		}
+		changed = true
+		r.report.Fields += len(missing)
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return r.pages.Save(ctx, p)
+}
+
+func (r *run) seedNav(ctx context.Context, sn Nav) error {
+	n, err := r.navs.Fetch(ctx, r.id(nav.Aggregate, sn.Name, r.navNames))
+	if err != nil {
+		return fmt.Errorf("fetch nav: %w", err)
+	}
+
+	entry := "nav:" + sn.Name
+	var changed bool
+	if n.AggregateVersion() == 0 {
+		if r.skip(entry) {
+			return nil
+		}
+		if err := n.Create(sn.Name); err != nil {
+			return err
+		}
+		changed = true
+		r.report.Navs++
+	}
+	r.record(entry)
+
+	added, err := r.ensureItems(n, sn.Name, "", sn.Items)
+	if err != nil {
+		return err
+	}
+	if added > 0 {
+		changed = true
+		r.report.NavItems += added
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return r.navs.Save(ctx, n)
+}
+
+// ensureItems appends the missing Items at the given path of the Nav and
+// returns the number of added Items, including the Items of added subtrees.
+// Items are identified by their paths, so the missing Items of existing
+// subtrees are added to the existing Items. Items that have already been
+// seeded are skipped with their subtrees.
+func (r *run) ensureItems(n *nav.Nav, name, path string, items []nav.Item) (int, error) {
+	var added int
+	var missing []nav.Item
+
+	for _, item := range items {
+		itemPath := item.ID
+		if path != "" {
+			itemPath = path + "." + item.ID
+		}
+		entry := "nav:" + name + "/item:" + itemPath
+
+		if !n.HasItem(itemPath) {
+			if r.skip(entry) {
+				continue
+			}
+			missing = append(missing, item)
+			added += count(item)
+			r.record(itemEntries(name, itemPath, item)...)
+			continue
+		}
+		r.record(entry)
+
+		if item.Tree == nil {
+			continue
+		}
+
+		nested, err := r.ensureItems(n, name, itemPath, item.Tree.Items)
+		if err != nil {
+			return added, err
+		}
+		added += nested
+	}
+
+	if len(missing) == 0 {
+		return added, nil
+	}
+
+	// AppendAt ignores Items that have no subtree yet, so the missing Items
+	// are inserted after the existing Items instead.
+	var index int
+	if path == "" {
+		index = len(n.Items)
+	} else if parent, err := n.Item(path); err == nil && parent.Tree != nil {
+		index = len(parent.Tree.Items)
+	}
+
+	if err := n.InsertAt(path, index, missing...); err != nil {
+		return added, fmt.Errorf("append items at %q: %w", path, err)
+	}
+
+	return added, nil
+}
+
+// initial returns a copy of the Item in which the Item and the Items of its
+// subtree are initial.
+func initial(item nav.Item) nav.Item {
+	item.Initial = true
+	if item.Tree != nil {
+		items := make([]nav.Item, len(item.Tree.Items))
+		for i, child := range item.Tree.Items {
+			items[i] = initial(child)
+		}
+		item.Tree = nav.NewTree(items...)
+	}
+	return item
+}
+
+// itemEntries returns the entries of the Item at the given path of the Nav
+// and of the Items of its subtree.
+func itemEntries(name, path string, item nav.Item) []string {
+	entries := []string{"nav:" + name + "/item:" + path}
+	if item.Tree != nil {
+		for _, child := range item.Tree.Items {
+			entries = append(entries, itemEntries(name, path+"."+child.ID, child)...)
+		}
+	}
+	return entries
+}
+
+func count(item nav.Item) int {
+	n := 1
+	if item.Tree != nil {
+		for _, child := range item.Tree.Items {
+			n += count(child)
+		}
+	}
+	return n
+}
+
+// read reads the source file and returns its contents and checksum.
+func (s *Seeder) read(source string) ([]byte, string, error) {
+	b, err := fs.ReadFile(s.files, source)
+	if err != nil {
+		return nil, "", fmt.Errorf("read %q: %w", source, err)
+	}
+
+	checksum, err := media.Checksum(bytes.NewReader(b))
+	if err != nil {
+		return nil, "", fmt.Errorf("checksum %q: %w", source, err)
+	}
+
+	return b, checksum, nil
+}
@@ -0,0 +1,402 @@
+package seed_test
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/imggen"
+	"github.com/modernice/nice-cms/media"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/seed"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+const manifest = `{
+	"galleries": [{
+		"name": "Demo",
+		"images": [{"name": "Example", "disk": "foo-disk", "path": "/demo/example.png", "source": "example.png"}]
+	}],
+	"shelfs": [{
+		"name": "Downloads",
+		"documents": [{"name": "Terms", "uniqueName": "terms", "disk": "foo-disk", "path": "/downloads/terms.pdf", "source": "terms.pdf"}]
+	}],
+	"pages": [{
+		"name": "home",
+		"fields": [{"Name": "title", "Type": "text", "Values": {"": "Welcome"}}]
+	}],
+	"navs": [{
+		"name": "main",
+		"items": [{"id": "home", "type": "static_link", "localePaths": {"": "/"}, "localeLabels": {"": "Home"}}]
+	}]
+}`
+
+func TestSeeder_Seed(t *testing.T) {
+	ctx := context.Background()
+
+	_, img := imggen.ColoredRectangle(8, 8, color.Black)
+	files := fstest.MapFS{
+		"manifest.json": {Data: []byte(manifest)},
+		"example.png":   {Data: img.Bytes()},
+		"terms.pdf":     {Data: []byte("terms")},
+	}
+
+	m, err := seed.Load(files, "manifest.json")
+	if err != nil {
+		t.Fatalf("load manifest: %v", err)
+	}
+
+	repo := repository.New(eventstore.New())
+	galleries := gallery.GoesRepository(repo)
+	shelfs := document.GoesRepository(repo)
+	pages := page.GoesRepository(repo)
+	navs := nav.GoesRepository(repo)
+
+	s := seed.New(
+		files,
+		seed.Storage(media.NewStorage(media.ConfigureDisk("foo-disk", media.MemoryDisk()))),
+		seed.Galleries(galleries, nil),
+		seed.Shelfs(shelfs, nil),
+		seed.Pages(pages, nil),
+		seed.Navs(navs, nil),
+	)
+
+	report, err := s.Seed(ctx, m)
+	if err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	want := seed.Report{Galleries: 1, Stacks: 1, Shelfs: 1, Documents: 1, Pages: 1, Fields: 1, Navs: 1, NavItems: 1}
+	if report != want {
+		t.Fatalf("Seed should report %+v; got %+v", want, report)
+	}
+
+	report, err = s.Seed(ctx, m)
+	if err != nil {
+		t.Fatalf("seed again: %v", err)
+	}
+	if !report.Empty() {
+		t.Fatalf("seeding the same Manifest again should not create anything; got %+v", report)
+	}
+
+	// Adding content to the Manifest only creates the new content.
+	m.Pages[0].Fields = append(m.Pages[0].Fields, field.NewText("subtitle", ""))
+	m.Navs[0].Items = append(m.Navs[0].Items, nav.NewLabel("about", "About"))
+	if report, err = s.Seed(ctx, m); err != nil {
+		t.Fatalf("seed again: %v", err)
+	}
+	if want := (seed.Report{Fields: 1, NavItems: 1}); report != want {
+		t.Fatalf("Seed should report %+v; got %+v", want, report)
+	}
+
+	p, err := pages.Fetch(ctx, uuid.NewSHA1(seed.DefaultNamespace, []byte(page.Aggregate+":home")))
+	if err != nil {
+		t.Fatalf("fetch page: %v", err)
+	}
+	if title, err := p.Field("title"); err != nil || title.Value("") != "Welcome" {
+		t.Fatalf("Page should have the seeded field; got %+v (%v)", title, err)
+	}
+	if _, err := p.Field("subtitle"); err != nil {
+		t.Fatalf("Page should have the added field: %v", err)
+	}
+
+	id := uuid.NewSHA1(seed.DefaultNamespace, []byte(nav.Aggregate+":main"))
+	n, err := navs.Fetch(ctx, id)
+	if err != nil {
+		t.Fatalf("fetch nav: %v", err)
+	}
+	if !n.HasItem("home", "about") {
+		t.Fatalf("Nav should have the seeded items; got %v", n.Items)
+	}
+}
+
+func TestSeeder_Seed_existing(t *testing.T) {
+	ctx := context.Background()
+
+	repo := repository.New(eventstore.New())
+	navs := nav.GoesRepository(repo)
+
+	existing, err := nav.Create("main")
+	if err != nil {
+		t.Fatalf("create nav: %v", err)
+	}
+	if err := navs.Save(ctx, existing); err != nil {
+		t.Fatalf("save nav: %v", err)
+	}
+
+	lookup := func(name string) (uuid.UUID, bool) {
+		return existing.AggregateID(), name == "main"
+	}
+
+	s := seed.New(fstest.MapFS{}, seed.Navs(navs, lookup))
+
+	report, err := s.Seed(ctx, seed.Manifest{Navs: []seed.Nav{{
+		Name:  "main",
+		Items: []nav.Item{nav.NewLabel("foo", "Foo")},
+	}}})
+	if err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	if want := (seed.Report{NavItems: 1}); report != want {
+		t.Fatalf("Seed should reuse the existing Nav and report %+v; got %+v", want, report)
+	}
+
+	n, err := navs.Fetch(ctx, existing.AggregateID())
+	if err != nil {
+		t.Fatalf("fetch nav: %v", err)
+	}
+	if !n.HasItem("foo") {
+		t.Fatalf("existing Nav should have the seeded item")
+	}
+}
+
+func TestSeeder_Seed_record(t *testing.T) {
+	ctx := context.Background()
+
+	repo := repository.New(eventstore.New())
+	galleries := gallery.GoesRepository(repo)
+	pages := page.GoesRepository(repo)
+	navs := nav.GoesRepository(repo)
+
+	s := seed.New(
+		fstest.MapFS{},
+		seed.Galleries(galleries, nil),
+		seed.Pages(pages, nil),
+		seed.Navs(navs, nil),
+		seed.Record(repo),
+	)
+
+	m := seed.Manifest{
+		Galleries: []seed.Gallery{{Name: "Demo"}},
+		Pages:     []seed.Page{{Name: "home", Fields: []field.Field{field.NewText("title", "Welcome")}}},
+		Navs:      []seed.Nav{{Name: "main", Items: []nav.Item{nav.NewLabel("home", "Home")}}},
+	}
+
+	if _, err := s.Seed(ctx, m); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	// Editors delete the seeded content.
+	g, err := galleries.Fetch(ctx, uuid.NewSHA1(seed.DefaultNamespace, []byte(gallery.Aggregate+":Demo")))
+	if err != nil {
+		t.Fatalf("fetch gallery: %v", err)
+	}
+	if err := galleries.Delete(ctx, g); err != nil {
+		t.Fatalf("delete gallery: %v", err)
+	}
+
+	pageID := uuid.NewSHA1(seed.DefaultNamespace, []byte(page.Aggregate+":home"))
+	if err := pages.Use(ctx, pageID, func(p *page.Page) error {
+		return p.Remove("title")
+	}); err != nil {
+		t.Fatalf("remove field: %v", err)
+	}
+
+	navID := uuid.NewSHA1(seed.DefaultNamespace, []byte(nav.Aggregate+":main"))
+	if err := navs.Use(ctx, navID, func(n *nav.Nav) error {
+		return n.Remove("home")
+	}); err != nil {
+		t.Fatalf("remove item: %v", err)
+	}
+
+	// Seeding again only creates the content that was never seeded.
+	m.Pages[0].Fields = append(m.Pages[0].Fields, field.NewText("subtitle", ""))
+	report, err := s.Seed(ctx, m)
+	if err != nil {
+		t.Fatalf("seed again: %v", err)
+	}
+	if want := (seed.Report{Fields: 1}); report != want {
+		t.Fatalf("Seed should not re-create deleted content and report %+v; got %+v", want, report)
+	}
+
+	p, err := pages.Fetch(ctx, pageID)
+	if err != nil {
+		t.Fatalf("fetch page: %v", err)
+	}
+	if _, err := p.Field("title"); err == nil {
+		t.Fatalf("removed Field should not be seeded again")
+	}
+
+	// Required content is created again.
+	report, err = s.Ensure(ctx, m)
+	if err != nil {
+		t.Fatalf("ensure: %v", err)
+	}
+	if want := (seed.Report{Galleries: 1, Fields: 1, NavItems: 1}); report != want {
+		t.Fatalf("Ensure should re-create required content and report %+v; got %+v", want, report)
+	}
+}
+
+func TestSeeder_Ensure_existing(t *testing.T) {
+	ctx := context.Background()
+
+	repo := repository.New(eventstore.New())
+	pages := page.GoesRepository(repo)
+	navs := nav.GoesRepository(repo)
+
+	s := seed.New(nil, seed.Pages(pages, nil), seed.Navs(navs, nil))
+
+	m := seed.Manifest{
+		Pages: []seed.Page{{Name: "home", Fields: []field.Field{field.NewText("title", "Welcome")}}},
+		Navs:  []seed.Nav{{Name: "main", Items: []nav.Item{nav.NewLabel("home", "Home")}}},
+	}
+
+	if _, err := s.Seed(ctx, m); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	report, err := s.Ensure(ctx, m)
+	if err != nil {
+		t.Fatalf("ensure: %v", err)
+	}
+	if !report.Empty() {
+		t.Fatalf("Ensure should not change existing content; got %+v", report)
+	}
+
+	// Existing content stays unprotected, so editors can still remove it.
+	pageID := uuid.NewSHA1(seed.DefaultNamespace, []byte(page.Aggregate+":home"))
+	if err := pages.Use(ctx, pageID, func(p *page.Page) error {
+		return p.Remove("title")
+	}); err != nil {
+		t.Fatalf("existing Field should not be guarded by Ensure; remove failed with %q", err)
+	}
+
+	navID := uuid.NewSHA1(seed.DefaultNamespace, []byte(nav.Aggregate+":main"))
+	if err := navs.Use(ctx, navID, func(n *nav.Nav) error {
+		return n.Remove("home")
+	}); err != nil {
+		t.Fatalf("existing Item should not be made initial by Ensure; remove failed with %q", err)
+	}
+}
+
+func TestSeeder_Seed_notConfigured(t *testing.T) {
+	s := seed.New(fstest.MapFS{})
+	_, err := s.Seed(context.Background(), seed.Manifest{Navs: []seed.Nav{{Name: "main"}}})
+	if !errors.Is(err, seed.ErrNotConfigured) {
+		t.Fatalf("Seed should fail with %q; got %v", seed.ErrNotConfigured, err)
+	}
+}
+
+func TestLoad_invalid(t *testing.T) {
+	tests := map[string]string{
+		"malformed":       `{`,
+		"empty name":      `{"galleries": [{"name": ""}]}`,
+		"duplicate name":  `{"navs": [{"name": "main"}, {"name": "main"}]}`,
+		"duplicate field": `{"pages": [{"name": "home", "fields": [{"Name": "title"}, {"Name": "title"}]}]}`,
+		"missing source":  `{"shelfs": [{"name": "foo", "documents": [{"disk": "foo-disk", "path": "/foo.pdf"}]}]}`,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			files := fstest.MapFS{"manifest.json": {Data: []byte(data)}}
+			if _, err := seed.Load(files, "manifest.json"); !errors.Is(err, seed.ErrInvalidManifest) {
+				t.Fatalf("Load should fail with %q; got %v", seed.ErrInvalidManifest, err)
+			}
+		})
+	}
+}
+
+func TestSeeder_Ensure(t *testing.T) {
+	ctx := context.Background()
+
+	repo := repository.New(eventstore.New())
+	pages := page.GoesRepository(repo)
+	navs := nav.GoesRepository(repo)
+
+	s := seed.New(
+		nil,
+		seed.Galleries(gallery.GoesRepository(repo), nil),
+		seed.Shelfs(document.GoesRepository(repo), nil),
+		seed.Pages(pages, nil),
+		seed.Navs(navs, nil),
+	)
+
+	m := seed.Manifest{
+		Galleries: []seed.Gallery{{Name: "Products"}},
+		Shelfs:    []seed.Shelf{{Name: "Downloads"}},
+		Pages:     []seed.Page{{Name: "home", Fields: []field.Field{field.NewText("title", "Welcome")}}},
+		Navs: []seed.Nav{{Name: "main", Items: []nav.Item{
+			nav.NewStaticLink("home", "/", "Home"),
+			nav.NewLabel("about", "About", nav.SubTree(nav.NewStaticLink("team", "/team", "Team"))),
+		}}},
+	}
+
+	report, err := s.Ensure(ctx, m)
+	if err != nil {
+		t.Fatalf("ensure: %v", err)
+	}
+
+	want := seed.Report{Galleries: 1, Shelfs: 1, Pages: 1, Fields: 1, Navs: 1, NavItems: 3}
+	if report != want {
+		t.Fatalf("Ensure should report %+v; got %+v", want, report)
+	}
+
+	if report, err = s.Ensure(ctx, m); err != nil {
+		t.Fatalf("ensure again: %v", err)
+	}
+	if !report.Empty() {
+		t.Fatalf("ensuring the same content again should not create anything; got %+v", report)
+	}
+
+	// Editors change the default values; requiring additional content only
+	// adds the new content.
+	pageID := uuid.NewSHA1(seed.DefaultNamespace, []byte(page.Aggregate+":home"))
+	if err := pages.Use(ctx, pageID, func(p *page.Page) error {
+		return p.UpdateField("title", "Hello")
+	}); err != nil {
+		t.Fatalf("update field: %v", err)
+	}
+
+	m = seed.Manifest{
+		Pages: []seed.Page{{Name: "home", Fields: []field.Field{field.NewText("title", "Welcome"), field.NewText("subtitle", "")}}},
+		Navs: []seed.Nav{{Name: "main", Items: []nav.Item{
+			nav.NewLabel("about", "About", nav.SubTree(
+				nav.NewStaticLink("team", "/team", "Team"),
+				nav.NewStaticLink("contact", "/contact", "Contact"),
+			)),
+		}}},
+	}
+
+	if report, err = s.Ensure(ctx, m); err != nil {
+		t.Fatalf("ensure again: %v", err)
+	}
+	if want := (seed.Report{Fields: 1, NavItems: 1}); report != want {
+		t.Fatalf("Ensure should report %+v; got %+v", want, report)
+	}
+
+	if m.Pages[0].Fields[1].Guarded {
+		t.Fatalf("Ensure should not change the Manifest")
+	}
+
+	p, err := pages.Fetch(ctx, pageID)
+	if err != nil {
+		t.Fatalf("fetch page: %v", err)
+	}
+	title, _ := p.Field("title")
+	if v := title.Value(""); v != "Hello" {
+		t.Fatalf("existing Field should keep its value %q; got %q", "Hello", v)
+	}
+	if subtitle, err := p.Field("subtitle"); err != nil || !subtitle.Guarded {
+		t.Fatalf("required Field should be added as a guarded Field; got %+v (%v)", subtitle, err)
+	}
+
+	n, err := navs.Fetch(ctx, uuid.NewSHA1(seed.DefaultNamespace, []byte(nav.Aggregate+":main")))
+	if err != nil {
+		t.Fatalf("fetch nav: %v", err)
+	}
+	if !n.HasItem("home", "about", "about.team", "about.contact") {
+		t.Fatalf("Nav should have the required items; got %v", n.Items)
+	}
+	if item, _ := n.Item("about.contact"); !item.Initial {
+		t.Fatalf("required Item should be initial")
+	}
+}
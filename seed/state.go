@@ -0,0 +1,74 @@
+package seed
+
+import (
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/codec"
+	"github.com/modernice/goes/event"
+)
+
+// Aggregate is the name of the State aggregate.
+const Aggregate = "cms.seed"
+
+// EntriesSeeded means entries of a Manifest were seeded.
+const EntriesSeeded = "cms.seed.entries_seeded"
+
+// EntriesSeededData is the event data for EntriesSeeded.
+type EntriesSeededData struct {
+	Entries []string
+}
+
+// RegisterEvents registers seed events into an event registry.
+func RegisterEvents(r codec.Registerer) {
+	codec.Register[EntriesSeededData](r, EntriesSeeded)
+}
+
+// State records the entries of Manifests that have been seeded, so that
+// content that editors deleted is not seeded again (see Record). Entries are
+// the galleries, shelfs, pages and navigations of a Manifest and their files,
+// fields and items, e.g. "page:home" and "page:home/field:title". Files are
+// identified by the checksums of their sources, so changed sources are seeded
+// again.
+type State struct {
+	*aggregate.Base
+
+	seeded map[string]bool
+}
+
+// NewState returns the State with the given UUID.
+func NewState(id uuid.UUID) *State {
+	return &State{
+		Base:   aggregate.New(Aggregate, id),
+		seeded: make(map[string]bool),
+	}
+}
+
+// Seeded returns whether the given entry has been seeded.
+func (s *State) Seeded(entry string) bool {
+	return s.seeded[entry]
+}
+
+// Record records the given entries as seeded. Entries that have already been
+// recorded are ignored.
+func (s *State) Record(entries ...string) {
+	var added []string
+	for _, entry := range entries {
+		if !s.seeded[entry] {
+			added = append(added, entry)
+		}
+	}
+	if len(added) == 0 {
+		return
+	}
+	aggregate.NextEvent(s, EntriesSeeded, EntriesSeededData{Entries: added})
+}
+
+// ApplyEvent applies aggregate events.
+func (s *State) ApplyEvent(evt event.Event) {
+	switch evt.Name() {
+	case EntriesSeeded:
+		for _, entry := range evt.Data().(EntriesSeededData).Entries {
+			s.seeded[entry] = true
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Package locale resolves localized values with fallback locales.
+//
+// Localized values of pages and navs are stored in maps that are keyed by
+// locale. The empty locale "" holds the default value. A value is resolved by
+// trying the requested locale, its parent locales, the fallback locales and
+// finally the default value:
+//
+//	values := map[string]string{"": "Hello", "de": "Hallo"}
+//	locale.Resolve(values, "de-AT") // "Hallo"
+//	locale.Resolve(values, "fr") // "Hello"
+package locale
+
+import "strings"
+
+// Default is the locale of default values.
+const Default = ""
+
+// Chain returns the locales that are tried when resolving a value for the
+// given locale, in order: the locale itself, its parent locales (e.g. "de" for
+// "de-AT"), the fallback locales and their parents, and Default. Locales are
+// only returned once.
+func Chain(locale string, fallbacks ...string) []string {
+	chain := make([]string, 0, 2+len(fallbacks))
+	seen := make(map[string]bool)
+
+	add := func(l string) {
+		for l != "" {
+			if !seen[l] {
+				seen[l] = true
+				chain = append(chain, l)
+			}
+			l = Parent(l)
+		}
+	}
+
+	add(locale)
+	for _, l := range fallbacks {
+		add(l)
+	}
+
+	return append(chain, Default)
+}
+
+// Parent returns the parent locale of the given locale, e.g. "de" for "de-AT"
+// and "zh-Hant" for "zh-Hant-TW". Parent returns Default for locales without a
+// parent. Underscores are accepted as separators, too.
+func Parent(locale string) string {
+	if i := strings.LastIndexAny(locale, "-_"); i > 0 {
+		return locale[:i]
+	}
+	return Default
+}
+
+// Resolve returns the value for the given locale. If values has no value for
+// the locale, the locales of Chain(locale, fallbacks...) are tried in order.
+// Resolve returns an empty string if none of them has a value.
+func Resolve(values map[string]string, locale string, fallbacks ...string) string {
+	if val, ok := values[locale]; ok {
+		return val
+	}
+	for _, l := range Chain(locale, fallbacks...) {
+		if val, ok := values[l]; ok {
+			return val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package locale_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/modernice/nice-cms/static/locale"
+)
+
+func TestChain(t *testing.T) {
+	tests := []struct {
+		locale    string
+		fallbacks []string
+		want      []string
+	}{
+		{"", nil, []string{""}},
+		{"de", nil, []string{"de", ""}},
+		{"de-AT", nil, []string{"de-AT", "de", ""}},
+		{"zh_Hant_TW", nil, []string{"zh_Hant_TW", "zh_Hant", "zh", ""}},
+		{"de-AT", []string{"en-GB", "de"}, []string{"de-AT", "de", "en-GB", "en", ""}},
+	}
+
+	for _, tt := range tests {
+		if got := locale.Chain(tt.locale, tt.fallbacks...); !cmp.Equal(tt.want, got) {
+			t.Errorf("Chain(%q, %v) should return %v; got %v", tt.locale, tt.fallbacks, tt.want, got)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	values := map[string]string{"": "Hello", "de": "Hallo", "en-GB": "Hello, mate"}
+
+	tests := []struct {
+		locale    string
+		fallbacks []string
+		want      string
+	}{
+		{"", nil, "Hello"},
+		{"de", nil, "Hallo"},
+		{"de-AT", nil, "Hallo"},
+		{"fr", nil, "Hello"},
+		{"fr", []string{"en-GB"}, "Hello, mate"},
+		{"fr-CA", []string{"de-CH"}, "Hallo"},
+	}
+
+	for _, tt := range tests {
+		if got := locale.Resolve(values, tt.locale, tt.fallbacks...); got != tt.want {
+			t.Errorf("Resolve(%q, %v) should return %q; got %q", tt.locale, tt.fallbacks, tt.want, got)
+		}
+	}
+
+	if got := locale.Resolve(map[string]string{"de": "Hallo"}, "fr"); got != "" {
+		t.Errorf("Resolve should return an empty string if no locale has a value; got %q", got)
+	}
+}
@@ -8,11 +8,21 @@ import (
 	"github.com/google/uuid"
 	"github.com/modernice/goes/codec"
 	"github.com/modernice/goes/command"
+	"github.com/modernice/goes/helper/streams"
 )
 
 const (
 	// CreateCommand is the command for creating a Nav.
 	CreateCommand = "cms.static.nav.create"
+
+	// RemoveItemsCommand is the command for removing Items from a Nav.
+	RemoveItemsCommand = "cms.static.nav.remove_items"
+
+	// UpdateItemCommand is the command for updating an Item of a Nav.
+	UpdateItemCommand = "cms.static.nav.update_item"
+
+	// ReorderCommand is the command for reordering the Items of a Nav.
+	ReorderCommand = "cms.static.nav.reorder"
 )
 
 type createPayload struct {
@@ -28,15 +38,52 @@ func CreateCmd(name string, items ...Item) command.Cmd[createPayload] {
 	}, command.Aggregate(Aggregate, uuid.New()))
 }
 
+type removeItemsPayload struct {
+	Items []string
+}
+
+// RemoveItemsCmd returns the command for removing Items from a Nav. Items are
+// dot-separated paths (see Nav.Remove).
+func RemoveItemsCmd(id uuid.UUID, items ...string) command.Cmd[removeItemsPayload] {
+	return command.New(RemoveItemsCommand, removeItemsPayload{Items: items}, command.Aggregate(Aggregate, id))
+}
+
+type updateItemPayload struct {
+	Item    string
+	Changes ItemChanges
+}
+
+// UpdateItemCmd returns the command for updating the Item at the given path
+// of a Nav (see Nav.UpdateItem).
+func UpdateItemCmd(id uuid.UUID, path string, changes ItemChanges) command.Cmd[updateItemPayload] {
+	return command.New(UpdateItemCommand, updateItemPayload{
+		Item:    path,
+		Changes: changes,
+	}, command.Aggregate(Aggregate, id))
+}
+
+type reorderPayload struct {
+	Items []Order
+}
+
+// ReorderCmd returns the command for reordering the Items of a Nav to match
+// the given tree (see Nav.Reorder).
+func ReorderCmd(id uuid.UUID, tree []Order) command.Cmd[reorderPayload] {
+	return command.New(ReorderCommand, reorderPayload{Items: tree}, command.Aggregate(Aggregate, id))
+}
+
 // RegisterCommands register commands into a registry.
 func RegisterCommands(r codec.Registerer) {
 	codec.Register[createPayload](r, CreateCommand)
+	codec.Register[removeItemsPayload](r, RemoveItemsCommand)
+	codec.Register[updateItemPayload](r, UpdateItemCommand)
+	codec.Register[reorderPayload](r, ReorderCommand)
 }
 
 // HandleCommands handles navigation commands until ctx is canceled. The
 // returned error channel is also closed when ctx is canceled.
 func HandleCommands(ctx context.Context, bus command.Bus, repo Repository, lookup *Lookup) <-chan error {
-	errs := command.MustHandle(ctx, bus, CreateCommand, func(ctx command.Ctx[createPayload]) error {
+	createErrors := command.MustHandle(ctx, bus, CreateCommand, func(ctx command.Ctx[createPayload]) error {
 		load := ctx.Payload()
 
 		if _, ok := lookup.Name(load.Name); ok {
@@ -55,5 +102,26 @@ func HandleCommands(ctx context.Context, bus command.Bus, repo Repository, looku
 		return nil
 	})
 
-	return errs
+	removeErrors := command.MustHandle(ctx, bus, RemoveItemsCommand, func(ctx command.Ctx[removeItemsPayload]) error {
+		load := ctx.Payload()
+		return repo.Use(ctx, ctx.AggregateID(), func(nav *Nav) error {
+			return nav.Remove(load.Items...)
+		})
+	})
+
+	updateErrors := command.MustHandle(ctx, bus, UpdateItemCommand, func(ctx command.Ctx[updateItemPayload]) error {
+		load := ctx.Payload()
+		return repo.Use(ctx, ctx.AggregateID(), func(nav *Nav) error {
+			return nav.UpdateItem(load.Item, load.Changes)
+		})
+	})
+
+	reorderErrors := command.MustHandle(ctx, bus, ReorderCommand, func(ctx command.Ctx[reorderPayload]) error {
+		load := ctx.Payload()
+		return repo.Use(ctx, ctx.AggregateID(), func(nav *Nav) error {
+			return nav.Reorder(load.Items)
+		})
+	})
+
+	return streams.FanInContext(ctx, createErrors, removeErrors, updateErrors, reorderErrors)
 }
@@ -2,6 +2,7 @@ package nav_test
 
 import (
 	"context"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -98,6 +99,87 @@ func TestCreateCmd_duplicateName(t *testing.T) {
 	}
 }
 
+func TestRemoveItemsCmd(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	cbus := cmdbus.New(commands.NewRegistry(), ebus)
+
+	repo := nav.GoesRepository(repository.New(estore))
+	lookup, errs := newLookup(t, ctx, ebus, estore)
+	panicOn(errs)
+
+	errs = handleCommands(t, ctx, cbus, repo, lookup)
+	panicOn(errs)
+
+	n, err := nav.Create("foo")
+	if err != nil {
+		t.Fatalf("create Nav: %v", err)
+	}
+	n.Append(nav.NewLabel("foo", "Foo", nav.SubTree(nav.NewLabel("bar", "Bar"))), nav.NewLabel("baz", "Baz"))
+	if err := repo.Save(ctx, n); err != nil {
+		t.Fatalf("save Nav: %v", err)
+	}
+
+	cmd := nav.RemoveItemsCmd(n.ID, "foo.bar", "baz")
+	if err := cbus.Dispatch(ctx, cmd.Any(), dispatch.Sync()); err != nil {
+		t.Fatalf("dispatch command: %v", err)
+	}
+
+	fetched, err := repo.Fetch(ctx, n.ID)
+	if err != nil {
+		t.Fatalf("fetch Nav: %v", err)
+	}
+
+	if fetched.HasItem("foo.bar") || fetched.HasItem("baz") || !fetched.HasItem("foo") {
+		t.Fatalf("only %q and %q should have been removed; got %v", "foo.bar", "baz", fetched.Items)
+	}
+}
+
+func TestReorderCmd(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	cbus := cmdbus.New(commands.NewRegistry(), ebus)
+
+	repo := nav.GoesRepository(repository.New(estore))
+	lookup, errs := newLookup(t, ctx, ebus, estore)
+	go discard.Errors(errs)
+
+	errs = handleCommands(t, ctx, cbus, repo, lookup)
+	panicOn(errs)
+
+	n, err := nav.Create("foo")
+	if err != nil {
+		t.Fatalf("create Nav: %v", err)
+	}
+	n.Append(nav.NewLabel("foo", "Foo", nav.SubTree(nav.NewLabel("bar", "Bar"))), nav.NewLabel("baz", "Baz"))
+	if err := repo.Save(ctx, n); err != nil {
+		t.Fatalf("save Nav: %v", err)
+	}
+
+	cmd := nav.ReorderCmd(n.ID, []nav.Order{
+		{ID: "baz", Items: []nav.Order{{ID: "bar"}}},
+		{ID: "foo"},
+	})
+	if err := cbus.Dispatch(ctx, cmd.Any(), dispatch.Sync()); err != nil {
+		t.Fatalf("dispatch command: %v", err)
+	}
+
+	fetched, err := repo.Fetch(ctx, n.ID)
+	if err != nil {
+		t.Fatalf("fetch Nav: %v", err)
+	}
+
+	if want := []string{"baz", "baz.bar", "foo"}; !reflect.DeepEqual(paths(fetched.Tree), want) {
+		t.Fatalf("Nav should have the Items %v; got %v", want, paths(fetched.Tree))
+	}
+}
+
 func newLookup(t *testing.T, ctx context.Context, bus event.Bus, store event.Store) (*nav.Lookup, <-chan error) {
 	l := nav.NewLookup()
 	errs, err := l.Project(ctx, bus, store)
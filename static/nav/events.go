@@ -16,6 +16,15 @@ const (
 
 	// Sorted means a Nav was sorted.
 	Sorted = "cms.static.nav.sorted"
+
+	// LabelUpdated means the label of an Item was updated for a locale.
+	LabelUpdated = "cms.static.nav.label_updated"
+
+	// PathUpdated means the path of an Item was updated for a locale.
+	PathUpdated = "cms.static.nav.path_updated"
+
+	// ItemUpdated means an Item was updated.
+	ItemUpdated = "cms.static.nav.item_updated"
 )
 
 // Events are all navigation events.
@@ -24,6 +33,9 @@ var Events = [...]string{
 	ItemsAdded,
 	ItemsRemoved,
 	Sorted,
+	LabelUpdated,
+	PathUpdated,
+	ItemUpdated,
 }
 
 // CreatedData is the event data for Created.
@@ -49,10 +61,36 @@ type SortedData struct {
 	Path    string
 }
 
+// LabelUpdatedData is the event data for LabelUpdated. Item is the path of the
+// Item. An empty Label removes the label of the Locale.
+type LabelUpdatedData struct {
+	Item   string
+	Locale string
+	Label  string
+}
+
+// PathUpdatedData is the event data for PathUpdated. Item is the path of the
+// Item. An empty Path removes the path of the Locale.
+type PathUpdatedData struct {
+	Item   string
+	Locale string
+	Path   string
+}
+
+// ItemUpdatedData is the event data for ItemUpdated. Item is the path of the
+// Item.
+type ItemUpdatedData struct {
+	Item    string
+	Changes ItemChanges
+}
+
 // RegisterEvents registers events into an event registry.
 func RegisterEvents(r codec.Registerer) {
 	codec.Register[CreatedData](r, Created)
 	codec.Register[ItemsAddedData](r, ItemsAdded)
 	codec.Register[ItemsRemovedData](r, ItemsRemoved)
 	codec.Register[SortedData](r, Sorted)
+	codec.Register[LabelUpdatedData](r, LabelUpdated)
+	codec.Register[PathUpdatedData](r, PathUpdated)
+	codec.Register[ItemUpdatedData](r, ItemUpdated)
 }
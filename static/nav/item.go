@@ -1,9 +1,16 @@
 package nav
 
+import (
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/schema"
+	loc "github.com/modernice/nice-cms/static/locale"
+)
+
 // Item types
 const (
 	Label      = ItemType("label")
 	StaticLink = ItemType("static_link")
+	PageLink   = ItemType("page_link")
 )
 
 // ItemType is an Item type.
@@ -11,10 +18,23 @@ type ItemType string
 
 // Item is a navigation item.
 type Item struct {
-	ID      string   `json:"id"`
-	Type    ItemType `json:"type"`
+	ID      string   `json:"id" schema:"required,minLength=1"`
+	Type    ItemType `json:"type" schema:"required,enum=label|static_link|page_link"`
 	Initial bool     `json:"initial"`
 
+	// Page is the UUID of the Page that is linked by a PageLink Item.
+	Page *uuid.UUID `json:"page,omitempty"`
+
+	// Hidden Items should not be rendered by frontends.
+	Hidden bool `json:"hidden"`
+
+	// RequiresAuth Items should only be rendered for authenticated users.
+	RequiresAuth bool `json:"requiresAuth"`
+
+	// Attributes are arbitrary key/value attributes that frontends can use
+	// to render the Item.
+	Attributes map[string]string `json:"attributes,omitempty"`
+
 	Paths  map[string]string `json:"localePaths"`
 	Labels map[string]string `json:"localeLabels"`
 
@@ -24,10 +44,11 @@ type Item struct {
 // ItemOption is an option for an Item.
 type ItemOption func(*Item)
 
-// LocalePath returns an ItemOption that adds a localized path to an Item.
+// LocalePath returns an ItemOption that adds a localized path to an Item. Only
+// StaticLink Items have paths.
 func LocalePath(locale, path string) ItemOption {
 	return func(i *Item) {
-		if i.Type != Label {
+		if i.Type == StaticLink {
 			i.Paths[locale] = path
 		}
 	}
@@ -55,6 +76,31 @@ func Initial() ItemOption {
 	}
 }
 
+// Hidden returns an ItemOption that hides an Item.
+func Hidden() ItemOption {
+	return func(i *Item) {
+		i.Hidden = true
+	}
+}
+
+// RequiresAuth returns an ItemOption that makes an Item visible only to
+// authenticated users.
+func RequiresAuth() ItemOption {
+	return func(i *Item) {
+		i.RequiresAuth = true
+	}
+}
+
+// Attribute returns an ItemOption that adds an attribute to an Item.
+func Attribute(key, value string) ItemOption {
+	return func(i *Item) {
+		if i.Attributes == nil {
+			i.Attributes = make(map[string]string)
+		}
+		i.Attributes[key] = value
+	}
+}
+
 // NewItem returns an Item with the given ID and ItemType.
 func NewItem(id string, typ ItemType, opts ...ItemOption) Item {
 	item := Item{
@@ -84,20 +130,41 @@ func NewStaticLink(id, path, label string, opts ...ItemOption) Item {
 	return NewItem(id, StaticLink, opts...)
 }
 
-// Path returns the path for the given locale or the default path.
-func (i Item) Path(locale string) string {
-	if path, ok := i.Paths[locale]; ok {
-		return path
-	}
-	return i.Paths[""]
+// NewPageLink returns an Item of type PageLink that links to the Page with the
+// given UUID. PageLink Items have no static paths; they link to the current
+// path of the Page and are labeled with the title of the Page unless they have
+// their own label (see Nav.ResolvePages):
+//
+//	NewPageLink("about", pageID, LocaleLabel("de", "Über uns"))
+func NewPageLink(id string, page uuid.UUID, opts ...ItemOption) Item {
+	opts = append([]ItemOption{func(i *Item) { i.Page = &page }}, opts...)
+	return NewItem(id, PageLink, opts...)
 }
 
-// Label returns the label for the given locale or the default label.
-func (i Item) Label(locale string) string {
-	if t, ok := i.Labels[locale]; ok {
-		return t
-	}
-	return i.Labels[""]
+// Path returns the path for the given locale. If the Item has no path for the
+// locale, the parent locales, the fallback locales and the default path are
+// tried in order (see locale.Resolve).
+func (i Item) Path(locale string, fallbacks ...string) string {
+	return loc.Resolve(i.Paths, locale, fallbacks...)
+}
+
+// Label returns the label for the given locale. If the Item has no label for
+// the locale, the parent locales, the fallback locales and the default label
+// are tried in order (see locale.Resolve).
+func (i Item) Label(locale string, fallbacks ...string) string {
+	return loc.Resolve(i.Labels, locale, fallbacks...)
+}
+
+// Visible returns whether the Item should be rendered for a user. Hidden Items
+// are never visible and Items that require authentication are only visible to
+// authenticated users.
+func (i Item) Visible(authenticated bool) bool {
+	return !i.Hidden && (authenticated || !i.RequiresAuth)
+}
+
+// ItemSchema returns the JSON Schema of an Item.
+func ItemSchema() *schema.Schema {
+	return schema.Of(Item{}, schema.Title("Navigation item"))
 }
 
 func (i *Item) ensureTree() {
@@ -5,16 +5,19 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
 	"github.com/modernice/nice-cms/static/nav"
 )
 
 func TestItem_Type(t *testing.T) {
 	label := nav.NewLabel("contact", "Contact")
 	staticLink := nav.NewStaticLink("contact", "/contact", "Contact")
+	pageLink := nav.NewPageLink("contact", uuid.New())
 
 	tests := map[*nav.Item]nav.ItemType{
 		&label:      nav.Label,
 		&staticLink: nav.StaticLink,
+		&pageLink:   nav.PageLink,
 	}
 
 	for item, want := range tests {
@@ -112,6 +115,23 @@ func TestNewItem_Label_LocalePath(t *testing.T) {
 	}
 }
 
+func TestNewPageLink(t *testing.T) {
+	pageID := uuid.New()
+	item := nav.NewPageLink("foo", pageID, nav.LocalePath("", "/foo"), nav.LocaleLabel("de", "Foo"))
+
+	if item.Page == nil || *item.Page != pageID {
+		t.Fatalf("Page should be %s; is %v", pageID, item.Page)
+	}
+
+	if len(item.Paths) != 0 {
+		t.Fatalf("LocalePath option should have no effect with ItemType %q", nav.PageLink)
+	}
+
+	if item.Label("de") != "Foo" {
+		t.Fatalf("Label(%q) should return %q; got %q", "de", "Foo", item.Label("de"))
+	}
+}
+
 func TestNewItem_SubTree(t *testing.T) {
 	items := []nav.Item{
 		nav.NewLabel("foo", "Foo"),
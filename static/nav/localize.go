@@ -0,0 +1,171 @@
+package nav
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/event"
+	loc "github.com/modernice/nice-cms/static/locale"
+)
+
+var (
+	// ErrLabelItem is returned when trying to set the path of a Label Item.
+	ErrLabelItem = errors.New("label item has no path")
+
+	// ErrPageLinkItem is returned when trying to set the path of a PageLink
+	// Item. PageLink Items link to the path of their Page.
+	ErrPageLinkItem = errors.New("page link item has no static path")
+)
+
+// SetLabel sets the label of the Item at the given path for a single locale.
+// Use the empty locale to set the default label. An empty label removes the
+// label of a non-default locale, so that the Item falls back to the labels of
+// other locales.
+func (nav *Nav) SetLabel(path, locale, label string) error {
+	if _, err := nav.Item(path); err != nil {
+		return fmt.Errorf("%q: %w", path, err)
+	}
+
+	aggregate.NextEvent(nav, LabelUpdated, LabelUpdatedData{
+		Item:   path,
+		Locale: locale,
+		Label:  strings.TrimSpace(label),
+	})
+
+	return nil
+}
+
+func (nav *Nav) updateLabel(evt event.Event) {
+	data := evt.Data().(LabelUpdatedData)
+	item, err := nav.Item(data.Item)
+	if err != nil {
+		return
+	}
+	item.Labels = setLocale(item.Labels, data.Locale, data.Label)
+	nav.replace(data.Item, item)
+}
+
+// SetPath sets the path of the Item at the given path for a single locale.
+// Use the empty locale to set the default path. An empty path removes the path
+// of a non-default locale, so that the Item falls back to the paths of other
+// locales. Label and PageLink Items have no static path, so SetPath returns
+// ErrLabelItem or ErrPageLinkItem for them.
+func (nav *Nav) SetPath(itemPath, locale, path string) error {
+	item, err := nav.Item(itemPath)
+	if err != nil {
+		return fmt.Errorf("%q: %w", itemPath, err)
+	}
+
+	if err := checkPaths(item.Type); err != nil {
+		return fmt.Errorf("%q: %w", itemPath, err)
+	}
+
+	aggregate.NextEvent(nav, PathUpdated, PathUpdatedData{
+		Item:   itemPath,
+		Locale: locale,
+		Path:   strings.TrimSpace(path),
+	})
+
+	return nil
+}
+
+func (nav *Nav) updatePath(evt event.Event) {
+	data := evt.Data().(PathUpdatedData)
+	item, err := nav.Item(data.Item)
+	if err != nil {
+		return
+	}
+	item.Paths = setLocale(item.Paths, data.Locale, data.Path)
+	nav.replace(data.Item, item)
+}
+
+// checkPaths returns an error if Items of the given type have no static paths.
+func checkPaths(typ ItemType) error {
+	switch typ {
+	case Label:
+		return ErrLabelItem
+	case PageLink:
+		return ErrPageLinkItem
+	default:
+		return nil
+	}
+}
+
+// setLocale returns a copy of values with the value of the given locale set to
+// val. The values are copied because they may be shared with event data.
+func setLocale(values map[string]string, locale, val string) map[string]string {
+	out := make(map[string]string, len(values)+1)
+	for l, v := range values {
+		out[l] = v
+	}
+	if val == "" && locale != loc.Default {
+		delete(out, locale)
+	} else {
+		out[locale] = val
+	}
+	return out
+}
+
+// LocalizedItem is an Item with the label and path of a single locale.
+type LocalizedItem struct {
+	ID           string            `json:"id"`
+	Type         ItemType          `json:"type"`
+	Page         *uuid.UUID        `json:"page,omitempty"`
+	Label        string            `json:"label"`
+	Path         string            `json:"path,omitempty"`
+	Hidden       bool              `json:"hidden,omitempty"`
+	RequiresAuth bool              `json:"requiresAuth,omitempty"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+	Items        []LocalizedItem   `json:"items,omitempty"`
+}
+
+// Localize returns the Item with the label and path of the given locale.
+// Labels and paths fall back to the parent locales, the given fallback locales
+// and the default value (see locale.Resolve).
+func (i Item) Localize(locale string, fallbacks ...string) LocalizedItem {
+	out := LocalizedItem{
+		ID:           i.ID,
+		Type:         i.Type,
+		Page:         i.Page,
+		Label:        i.Label(locale, fallbacks...),
+		Path:         i.Path(locale, fallbacks...),
+		Hidden:       i.Hidden,
+		RequiresAuth: i.RequiresAuth,
+		Attributes:   i.Attributes,
+	}
+	if i.Tree != nil {
+		out.Items = localizeItems(i.Tree.Items, locale, fallbacks)
+	}
+	return out
+}
+
+// LocalizedNav is a Nav with the labels and paths of a single locale.
+type LocalizedNav struct {
+	ID     uuid.UUID       `json:"id"`
+	Name   string          `json:"name"`
+	Locale string          `json:"locale"`
+	Items  []LocalizedItem `json:"items"`
+}
+
+// Localize returns the Nav with the labels and paths of the given locale.
+// Labels and paths fall back to the parent locales, the given fallback locales
+// and the default value (see locale.Resolve).
+func (nav *Nav) Localize(locale string, fallbacks ...string) LocalizedNav {
+	return LocalizedNav{
+		ID:     nav.ID,
+		Name:   nav.Name,
+		Locale: locale,
+		Items:  localizeItems(nav.Items, locale, fallbacks),
+	}
+}
+
+func localizeItems(items []Item, locale string, fallbacks []string) []LocalizedItem {
+	out := make([]LocalizedItem, len(items))
+	for i, item := range items {
+		out[i] = item.Localize(locale, fallbacks...)
+	}
+	return out
+}
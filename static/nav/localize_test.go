@@ -0,0 +1,102 @@
+package nav_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/static/nav"
+)
+
+func TestNav_SetLabel(t *testing.T) {
+	n, _ := nav.Create("foo",
+		nav.NewLabel("foo", "Foo", nav.SubTree(
+			nav.NewStaticLink("bar", "/bar", "Bar"),
+		)),
+	)
+
+	if err := n.SetLabel("foo.bar", "de", "Bär"); err != nil {
+		t.Fatalf("SetLabel failed with %q", err)
+	}
+
+	test.Change(t, n, nav.LabelUpdated, test.EventData(nav.LabelUpdatedData{
+		Item:   "foo.bar",
+		Locale: "de",
+		Label:  "Bär",
+	}))
+
+	item, _ := n.Item("foo.bar")
+	if l := item.Label("de-AT"); l != "Bär" {
+		t.Fatalf("label for %q should be %q; is %q", "de-AT", "Bär", l)
+	}
+
+	if err := n.SetLabel("foo.bar", "de", ""); err != nil {
+		t.Fatalf("SetLabel failed with %q", err)
+	}
+
+	item, _ = n.Item("foo.bar")
+	if l := item.Label("de"); l != "Bar" {
+		t.Fatalf("empty label should fall back to the default label %q; got %q", "Bar", l)
+	}
+
+	if err := n.SetLabel("foo.baz", "de", "Baz"); !errors.Is(err, nav.ErrItemNotFound) {
+		t.Fatalf("SetLabel should fail with %q; got %q", nav.ErrItemNotFound, err)
+	}
+}
+
+func TestNav_SetPath(t *testing.T) {
+	n, _ := nav.Create("foo", nav.NewLabel("foo", "Foo"), nav.NewStaticLink("bar", "/bar", "Bar"), nav.NewPageLink("baz", uuid.New()))
+
+	if err := n.SetPath("foo", "de", "/foo"); !errors.Is(err, nav.ErrLabelItem) {
+		t.Fatalf("SetPath should fail with %q for a label; got %q", nav.ErrLabelItem, err)
+	}
+
+	if err := n.SetPath("baz", "de", "/baz"); !errors.Is(err, nav.ErrPageLinkItem) {
+		t.Fatalf("SetPath should fail with %q for a page link; got %q", nav.ErrPageLinkItem, err)
+	}
+
+	if err := n.SetPath("bar", "de", "/de/bar"); err != nil {
+		t.Fatalf("SetPath failed with %q", err)
+	}
+
+	test.Change(t, n, nav.PathUpdated, test.Exactly(1))
+
+	item, _ := n.Item("bar")
+	if p := item.Path("de"); p != "/de/bar" {
+		t.Fatalf("path for %q should be %q; is %q", "de", "/de/bar", p)
+	}
+	if p := item.Path("en"); p != "/bar" {
+		t.Fatalf("path for %q should be %q; is %q", "en", "/bar", p)
+	}
+}
+
+func TestNav_Localize(t *testing.T) {
+	n, _ := nav.Create("foo",
+		nav.NewLabel("foo", "Foo", nav.LocaleLabel("de", "Fuh"), nav.SubTree(
+			nav.NewStaticLink("bar", "/bar", "Bar", nav.LocaleLabel("fr", "Barre"), nav.LocalePath("fr", "/fr/bar")),
+		)),
+	)
+
+	want := nav.LocalizedNav{
+		ID:     n.ID,
+		Name:   "foo",
+		Locale: "de-CH",
+		Items: []nav.LocalizedItem{{
+			ID:    "foo",
+			Type:  nav.Label,
+			Label: "Fuh",
+			Items: []nav.LocalizedItem{{
+				ID:    "bar",
+				Type:  nav.StaticLink,
+				Label: "Barre",
+				Path:  "/fr/bar",
+			}},
+		}},
+	}
+
+	if got := n.Localize("de-CH", "fr"); !cmp.Equal(want, got) {
+		t.Fatalf("invalid localized Nav.\n\n%s", cmp.Diff(want, got))
+	}
+}
@@ -2,27 +2,30 @@ package nav
 
 import (
 	"context"
-	"fmt"
 	"sync"
 
 	"github.com/google/uuid"
 	"github.com/modernice/goes/event"
-	"github.com/modernice/goes/projection"
 	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/projector"
 )
 
 // Lookup provides UUID lookup for Navs.
 //
 // Use NewLookup to create a Lookup.
 type Lookup struct {
+	projector *projector.Projector
+
 	nameToIDMux sync.RWMutex
 	nameToID    map[string]uuid.UUID
 }
 
-// NewLookup returns a new Lookup.
-func NewLookup() *Lookup {
+// NewLookup returns a new Lookup. The provided options configure the error
+// handling of the projection.
+func NewLookup(opts ...projector.Option) *Lookup {
 	return &Lookup{
-		nameToID: make(map[string]uuid.UUID),
+		projector: projector.New(opts...),
+		nameToID:  make(map[string]uuid.UUID),
 	}
 }
 
@@ -35,22 +38,17 @@ func (l *Lookup) Name(name string) (uuid.UUID, bool) {
 }
 
 // Project projects the Lookup in a new goroutine and returns a channel of
-// asynchronous errors.
+// asynchronous errors. Failed projection jobs are handled according to the
+// projector.Policy of the Lookup.
 func (l *Lookup) Project(ctx context.Context, bus event.Bus, store event.Store, opts ...schedule.ContinuousOption) (<-chan error, error) {
 	schedule := schedule.Continuously(bus, store, Events[:], opts...)
 
-	errs, err := schedule.Subscribe(ctx, l.applyJob)
-	if err != nil {
-		return nil, fmt.Errorf("subscribe to projection schedule: %w", err)
-	}
-
-	go schedule.Trigger(ctx)
-
-	return errs, nil
+	return l.projector.Run(ctx, schedule, l)
 }
 
-func (l *Lookup) applyJob(job projection.Job) error {
-	return job.Apply(job, l)
+// Status returns the projection status of the Lookup.
+func (l *Lookup) Status() projector.Status {
+	return l.projector.Status()
 }
 
 // ApplyEvent applies events.
@@ -11,6 +11,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/modernice/goes/aggregate"
 	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/internal/aggregateutil"
 	"github.com/modernice/nice-cms/internal/unique"
 )
 
@@ -251,6 +252,14 @@ func deepInitial(item Item) Item {
 func (nav *Nav) addItems(evt event.Event) {
 	data := evt.Data().(ItemsAddedData)
 
+	// The Items are copied because the Tree must not share subtrees with the
+	// event data.
+	items := make([]Item, len(data.Items))
+	for i, item := range data.Items {
+		items[i] = cloneItem(item)
+	}
+	data.Items = items
+
 	if data.Path == "" {
 		nav.addRootItems(data)
 		return
@@ -296,7 +305,7 @@ func parentPath(path string) string {
 		return ""
 	}
 	if len(ids) == 1 {
-		return ids[1]
+		return ""
 	}
 	return strings.Join(ids[:len(ids)-1], ".")
 }
@@ -425,11 +434,6 @@ func (nav *Nav) sort(evt event.Event) {
 		return
 	}
 
-	parent, err := nav.Item(parentPath(data.Path))
-	if err != nil || parent.Tree == nil {
-		return
-	}
-
 	item, err := nav.Item(data.Path)
 	if err != nil || item.Tree == nil {
 		return
@@ -439,7 +443,7 @@ func (nav *Nav) sort(evt event.Event) {
 	sort.SliceStable(sorted, item.Tree.lessFunc(sorted, data.Sorting))
 	item.Tree.Items = sorted
 
-	parent.Tree.replace(item.ID, item)
+	nav.replace(data.Path, item)
 }
 
 // ApplyEvent applies aggregate events.
@@ -453,6 +457,12 @@ func (nav *Nav) ApplyEvent(evt event.Event) {
 		nav.removeItems(evt)
 	case Sorted:
 		nav.sort(evt)
+	case LabelUpdated:
+		nav.updateLabel(evt)
+	case PathUpdated:
+		nav.updatePath(evt)
+	case ItemUpdated:
+		nav.updateItem(evt)
 	}
 }
 
@@ -561,44 +571,17 @@ func (t *Tree) checkDuplicates(items ...Item) error {
 	return nil
 }
 
-type goesRepository struct {
-	repo aggregate.Repository
-}
-
-// GoesRepository returns a Repository that uses the provided aggregate
-// repository under the hood.
-func GoesRepository(repo aggregate.Repository) Repository {
-	return &goesRepository{repo}
-}
-
-func (r *goesRepository) Save(ctx context.Context, n *Nav) error {
-	return r.repo.Save(ctx, n)
-}
-
-func (r *goesRepository) Fetch(ctx context.Context, id uuid.UUID) (*Nav, error) {
-	n := New(id)
-	if err := r.repo.Fetch(ctx, n); err != nil {
-		return n, fmt.Errorf("goes: %w", err)
-	}
-	return n, nil
-}
-
-func (r *goesRepository) Use(ctx context.Context, id uuid.UUID, fn func(*Nav) error) error {
-	n, err := r.Fetch(ctx, id)
-	if err != nil {
-		return fmt.Errorf("fetch nav: %w", err)
-	}
-	if err := fn(n); err != nil {
-		return err
-	}
-	if err := r.Save(ctx, n); err != nil {
-		return fmt.Errorf("save nav: %w", err)
-	}
-	return nil
-}
-
-func (r *goesRepository) Delete(ctx context.Context, n *Nav) error {
-	return r.repo.Delete(ctx, n)
+// GoesRepository returns a Repository that uses an aggregate.Repository under
+// the hood. Repository.Use retries to save the Nav when it was changed
+// concurrently; the retries can be tuned with the Retry options (e.g.
+// RetryMaxTries):
+//
+//	navs := nav.GoesRepository(repo,
+//		nav.RetryMaxTries(10),
+//		nav.RetryBackoff(20*time.Millisecond, time.Second),
+//	)
+func GoesRepository(repo aggregate.Repository, opts ...RepositoryOption) Repository {
+	return aggregateutil.NewRepository(repo, "nav", New, opts...)
 }
 
 type jsonNav struct {
@@ -460,6 +460,20 @@ func TestNav_SortAt(t *testing.T) {
 	}
 }
 
+func TestNav_SortAt_firstLevel(t *testing.T) {
+	n, _ := nav.Create("foo", nav.NewLabel("foo", "Foo", nav.SubTree(
+		nav.NewLabel("bar", "Bar"),
+		nav.NewLabel("baz", "Baz"),
+	)))
+
+	n.SortAt("foo", []string{"baz", "bar"})
+
+	item, _ := n.Item("foo")
+	if ids := []string{item.Tree.Items[0].ID, item.Tree.Items[1].ID}; !reflect.DeepEqual(ids, []string{"baz", "bar"}) {
+		t.Fatalf("subtree of %q should be sorted; got %v", "foo", ids)
+	}
+}
+
 func TestNav_Item(t *testing.T) {
 	foo := nav.NewLabel("foo", "Foo")
 	bar := nav.NewLabel("bar", "Bar", nav.SubTree(
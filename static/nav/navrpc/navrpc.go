@@ -0,0 +1,179 @@
+// Package navrpc provides the navigation gRPC server and client.
+package navrpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/command"
+	"github.com/modernice/goes/command/cmdbus/dispatch"
+	protocommon "github.com/modernice/nice-cms/proto/gen/common/v1"
+	protonav "github.com/modernice/nice-cms/proto/gen/nav/v1"
+	"github.com/modernice/nice-cms/proto/ptypes/v1"
+	"github.com/modernice/nice-cms/static/nav"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server is the navigation gRPC server.
+type Server struct {
+	protonav.UnimplementedNavServiceServer
+
+	navs     nav.Repository
+	commands command.Bus
+}
+
+// NewServer returns the navigation gRPC server. Item updates and reorderings
+// are dispatched as commands over the given command bus and must be handled by
+// nav.HandleCommands.
+func NewServer(navs nav.Repository, commands command.Bus) *Server {
+	return &Server{
+		navs:     navs,
+		commands: commands,
+	}
+}
+
+// Register registers the server into a ServiceRegistrar.
+func (s *Server) Register(reg grpc.ServiceRegistrar) {
+	protonav.RegisterNavServiceServer(reg, s)
+}
+
+// GetNav returns a Nav.
+func (s *Server) GetNav(ctx context.Context, id *protocommon.UUID) (*protonav.Nav, error) {
+	n, err := s.fetchNav(ctx, ptypes.UUID(id))
+	if err != nil {
+		return nil, err
+	}
+	return ptypes.NavProto(n), nil
+}
+
+// UpdateItem updates an Item of a Nav and returns the updated Nav.
+func (s *Server) UpdateItem(ctx context.Context, req *protonav.UpdateItemReq) (*protonav.Nav, error) {
+	id := ptypes.UUID(req.GetNavId())
+	n, err := s.fetchNav(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Errors of dispatched commands lose their type, so the changes are
+	// validated before the command is dispatched.
+	changes := ptypes.ItemChanges(req.GetChanges())
+	if err := n.UpdateItem(req.GetItem(), changes); err != nil {
+		return nil, updateError(err)
+	}
+
+	cmd := nav.UpdateItemCmd(id, req.GetItem(), changes)
+	if err := s.commands.Dispatch(ctx, cmd.Any(), dispatch.Sync()); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return s.GetNav(ctx, req.GetNavId())
+}
+
+// Reorder reorders the Items of a Nav and returns the reordered Nav.
+func (s *Server) Reorder(ctx context.Context, req *protonav.ReorderReq) (*protonav.Nav, error) {
+	id := ptypes.UUID(req.GetNavId())
+	n, err := s.fetchNav(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := ptypes.Orders(req.GetItems())
+	if err := n.Reorder(tree); err != nil {
+		return nil, reorderError(err)
+	}
+
+	cmd := nav.ReorderCmd(id, tree)
+	if err := s.commands.Dispatch(ctx, cmd.Any(), dispatch.Sync()); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return s.GetNav(ctx, req.GetNavId())
+}
+
+func (s *Server) fetchNav(ctx context.Context, id uuid.UUID) (*nav.Nav, error) {
+	n, err := s.navs.Fetch(ctx, id)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if n.Name == "" {
+		return nil, status.Errorf(codes.NotFound, "nav %s not found", id)
+	}
+	return n, nil
+}
+
+func updateError(err error) error {
+	switch {
+	case errors.Is(err, nav.ErrItemNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, nav.ErrLabelItem),
+		errors.Is(err, nav.ErrPageLinkItem),
+		errors.Is(err, nav.ErrInvalidItemType),
+		errors.Is(err, nav.ErrMissingPage),
+		errors.Is(err, nav.ErrNotPageLink),
+		errors.Is(err, nav.ErrEmptyAttribute):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func reorderError(err error) error {
+	switch {
+	case errors.Is(err, nav.ErrItemNotFound),
+		errors.Is(err, nav.ErrDuplicateItem),
+		errors.Is(err, nav.ErrAmbiguousItem),
+		errors.Is(err, nav.ErrInitialItem):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// Client is the navigation gRPC client.
+type Client struct {
+	client protonav.NavServiceClient
+}
+
+// NewClient returns the navigation gRPC client.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: protonav.NewNavServiceClient(conn)}
+}
+
+// GetNav returns the Nav with the given UUID.
+func (c *Client) GetNav(ctx context.Context, id uuid.UUID) (*nav.Nav, error) {
+	resp, err := c.client.GetNav(ctx, ptypes.UUIDProto(id))
+	if err != nil {
+		return nil, err
+	}
+	return ptypes.Nav(resp), nil
+}
+
+// UpdateItem updates the Item at the given path of a Nav and returns the
+// updated Nav.
+func (c *Client) UpdateItem(ctx context.Context, navID uuid.UUID, path string, changes nav.ItemChanges) (*nav.Nav, error) {
+	resp, err := c.client.UpdateItem(ctx, &protonav.UpdateItemReq{
+		NavId:   ptypes.UUIDProto(navID),
+		Item:    path,
+		Changes: ptypes.ItemChangesProto(changes),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ptypes.Nav(resp), nil
+}
+
+// Reorder reorders the Items of a Nav to match the given tree and returns the
+// reordered Nav.
+func (c *Client) Reorder(ctx context.Context, navID uuid.UUID, tree []nav.Order) (*nav.Nav, error) {
+	resp, err := c.client.Reorder(ctx, &protonav.ReorderReq{
+		NavId: ptypes.UUIDProto(navID),
+		Items: ptypes.OrdersProto(tree),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ptypes.Nav(resp), nil
+}
@@ -0,0 +1,132 @@
+package navrpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/command/cmdbus"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/commands"
+	"github.com/modernice/nice-cms/internal/discard"
+	"github.com/modernice/nice-cms/internal/grpctest"
+	protonav "github.com/modernice/nice-cms/proto/gen/nav/v1"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/nav/navrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer_UpdateItem(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	cbus := cmdbus.New(commands.NewRegistry(), ebus)
+	navs := nav.GoesRepository(repository.New(estore))
+
+	lookup := nav.NewLookup()
+	errs, err := lookup.Project(ctx, ebus, estore)
+	if err != nil {
+		t.Fatalf("run lookup: %v", err)
+	}
+	go discard.Errors(errs)
+	go discard.Errors(nav.HandleCommands(ctx, cbus, navs, lookup))
+
+	n, _ := nav.Create("main", nav.NewLabel("about", "About", nav.SubTree(
+		nav.NewStaticLink("team", "/team", "Team", nav.LocaleLabel("de", "Team")),
+	)))
+	if err := navs.Save(ctx, n); err != nil {
+		t.Fatalf("save Nav: %v", err)
+	}
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protonav.RegisterNavServiceServer(s, navrpc.NewServer(navs, cbus))
+	})
+	conn := dial()
+	defer conn.Close()
+
+	client := navrpc.NewClient(conn)
+
+	fetched, err := client.GetNav(ctx, n.ID)
+	if err != nil {
+		t.Fatalf("GetNav failed with %q", err)
+	}
+	if fetched.ID != n.ID || fetched.Name != "main" || !fetched.HasItem("about.team") {
+		t.Fatalf("GetNav should return the Nav; got %v", fetched)
+	}
+
+	updated, err := client.UpdateItem(ctx, n.ID, "about", nav.ItemChanges{
+		Type:   nav.StaticLink,
+		Labels: map[string]string{"de": "Über uns"},
+		Paths:  map[string]string{"": "/about"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateItem failed with %q", err)
+	}
+
+	item, err := updated.Item("about")
+	if err != nil {
+		t.Fatalf("updated Nav should have the Item: %v", err)
+	}
+	if item.Type != nav.StaticLink || item.Label("de") != "Über uns" || item.Path("") != "/about" {
+		t.Fatalf("UpdateItem should return the updated Nav; got %v", item)
+	}
+	if team, _ := updated.Item("about.team"); team.Label("de") != "Team" {
+		t.Fatalf("subtree should be preserved; got %v", team)
+	}
+
+	yes := true
+	updated, err = client.UpdateItem(ctx, n.ID, "about", nav.ItemChanges{
+		RequiresAuth: &yes,
+		Attributes:   map[string]string{"icon": "info"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateItem failed with %q", err)
+	}
+	if about, _ := updated.Item("about"); !about.RequiresAuth || about.Hidden || about.Attributes["icon"] != "info" {
+		t.Fatalf("Item should require authentication and have the attribute; got %v", about)
+	}
+
+	pageID := uuid.New()
+	updated, err = client.UpdateItem(ctx, n.ID, "about.team", nav.ItemChanges{Type: nav.PageLink, Page: pageID})
+	if err != nil {
+		t.Fatalf("UpdateItem failed with %q", err)
+	}
+	if team, _ := updated.Item("about.team"); team.Type != nav.PageLink || team.Page == nil || *team.Page != pageID || len(team.Paths) != 0 {
+		t.Fatalf("Item should be converted to a page link; got %v", team)
+	}
+
+	tests := []struct {
+		id      uuid.UUID
+		item    string
+		changes nav.ItemChanges
+		code    codes.Code
+	}{
+		{n.ID, "contact", nav.ItemChanges{Type: nav.Label}, codes.NotFound},
+		{n.ID, "about", nav.ItemChanges{Type: "foo"}, codes.InvalidArgument},
+		{n.ID, "about", nav.ItemChanges{Type: nav.PageLink}, codes.InvalidArgument},
+		{uuid.New(), "about", nav.ItemChanges{Type: nav.Label}, codes.NotFound},
+	}
+	for _, tt := range tests {
+		if _, err := client.UpdateItem(ctx, tt.id, tt.item, tt.changes); status.Code(err) != tt.code {
+			t.Fatalf("UpdateItem(%q, %v) should fail with code %s; got %v", tt.item, tt.changes, tt.code, err)
+		}
+	}
+
+	reordered, err := client.Reorder(ctx, n.ID, []nav.Order{{ID: "team"}, {ID: "about"}})
+	if err != nil {
+		t.Fatalf("Reorder failed with %q", err)
+	}
+	if len(reordered.Items) != 2 || reordered.Items[0].ID != "team" || reordered.Items[1].ID != "about" {
+		t.Fatalf("Reorder should return the reordered Nav; got %v", reordered.Items)
+	}
+
+	if _, err := client.Reorder(ctx, n.ID, []nav.Order{{ID: "team"}, {ID: "contact"}}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Reorder should fail with code %s; got %v", codes.InvalidArgument, err)
+	}
+}
@@ -0,0 +1,316 @@
+// Package navserver serves Navs over HTTP.
+//
+//	srv := navserver.New(navs, navserver.WithLookup(lookup))
+//	http.ListenAndServe(":8000", srv)
+//
+//	// GET   /navs/{NavID}?locale=de
+//	// GET   /navs/name/{Name}?locale=de
+//	// PATCH /navs/{NavID}/items/{Item}
+//	// PUT   /navs/{NavID}/tree
+//	// GET   /navs/openapi.json
+//
+// If the "locale" query parameter is set, Navs are served with the labels and
+// paths of that locale. Labels and paths without a value for the locale fall
+// back to the parent locales, the fallback locales of the Server and the
+// default value.
+//
+// With WithPages, PageLink Items are served with the current path of their Page
+// and are labeled with the title of their Page unless they have their own
+// label (see nav.Nav.ResolvePages).
+//
+// With WithVisibility, the GET routes only serve the Items that are visible to
+// the user of a request (see nav.Item.Visible), so that frontends can render
+// different navigations for authenticated users from a single Nav. Items are
+// updated by PATCHing their "hidden", "requiresAuth" and "attributes".
+//
+// The PATCH route updates the Item at the given dot-separated path with the
+// nav.ItemChanges in the request body. The PUT route reorders the Items of a
+// Nav to match the tree of Item IDs in the request body (see nav.Nav.Reorder):
+//
+//	{"items": [{"id": "about", "items": [{"id": "team"}]}, {"id": "blog"}]}
+//
+// Both routes are only installed with WithItemUpdates and are not protected by
+// the Server. Protect them with an authentication middleware.
+package navserver
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/modernice/goes/command"
+	"github.com/modernice/goes/command/cmdbus/dispatch"
+	"github.com/modernice/nice-cms/internal/api"
+	"github.com/modernice/nice-cms/openapi"
+	"github.com/modernice/nice-cms/static/nav"
+)
+
+// Routes of the Server.
+const (
+	ShowNavRoute   = "/navs/{NavID}"
+	NavByNameRoute = "/navs/name/{Name}"
+	ItemRoute      = "/navs/{NavID}/items/{Item}"
+	TreeRoute      = "/navs/{NavID}/tree"
+	OpenAPIRoute   = "/navs/openapi.json"
+)
+
+// Option is a Server option.
+type Option func(*Server)
+
+// WithLookup returns an Option that installs the route that serves Navs by
+// their names. The Lookup must be projected by the caller.
+func WithLookup(lookup *nav.Lookup) Option {
+	return func(s *Server) {
+		s.lookup = lookup
+	}
+}
+
+// WithFallbackLocales returns an Option that sets the locales that are tried
+// when an Item has no label or path for the requested locale or its parent
+// locales.
+func WithFallbackLocales(locales ...string) Option {
+	return func(s *Server) {
+		s.fallbacks = append(s.fallbacks, locales...)
+	}
+}
+
+// WithItemUpdates returns an Option that installs the routes that update and
+// reorder Items. Updates are dispatched as commands over the given command bus and
+// must be handled by nav.HandleCommands.
+func WithItemUpdates(commands command.Bus) Option {
+	return func(s *Server) {
+		s.commands = commands
+	}
+}
+
+// WithPages returns an Option that resolves the Pages of PageLink Items. Pass a
+// *nav.PageIndex that is projected by the caller.
+func WithPages(pages nav.PageResolver) Option {
+	return func(s *Server) {
+		s.pages = pages
+	}
+}
+
+// WithVisibility returns an Option that filters the Items of served Navs by
+// their visibility. authenticated reports whether the user of a request is
+// authenticated. Hidden Items are never served and Items that require
+// authentication are only served to authenticated users. Responses of the
+// PATCH route are not filtered.
+func WithVisibility(authenticated func(*http.Request) bool) Option {
+	return func(s *Server) {
+		s.authenticated = authenticated
+	}
+}
+
+// Server serves Navs over HTTP.
+type Server struct {
+	router        chi.Router
+	navs          nav.Repository
+	lookup        *nav.Lookup
+	fallbacks     []string
+	commands      command.Bus
+	pages         nav.PageResolver
+	authenticated func(*http.Request) bool
+	openapi       *openapi.Info
+}
+
+// New returns a Server that serves the Navs of the given Repository.
+func New(navs nav.Repository, opts ...Option) *Server {
+	s := Server{
+		router: chi.NewRouter(),
+		navs:   navs,
+	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	s.router.Get(ShowNavRoute, api.BindUUIDs(http.HandlerFunc(s.showNav)).ServeHTTP)
+	if s.lookup != nil {
+		s.router.Get(NavByNameRoute, s.showNavByName)
+	}
+	if s.commands != nil {
+		s.router.Patch(ItemRoute, api.BindUUIDs(http.HandlerFunc(s.updateItem)).ServeHTTP)
+		s.router.Put(TreeRoute, api.BindUUIDs(http.HandlerFunc(s.reorder)).ServeHTTP)
+	}
+	if s.openapi != nil {
+		s.router.Get(OpenAPIRoute, s.showOpenAPI)
+	}
+
+	return &s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
+}
+
+func (s *Server) showNav(w http.ResponseWriter, r *http.Request) {
+	s.respond(w, r, api.UUIDParam(r, "NavID"))
+}
+
+func (s *Server) showNavByName(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "Name")
+	id, ok := s.lookup.Name(name)
+	if !ok {
+		api.Error(w, r, http.StatusNotFound, api.Friendly(nil, "Nav %q not found.", name))
+		return
+	}
+	s.respond(w, r, id)
+}
+
+// respond responds with the Nav with the given UUID, localized to the locale
+// in the "locale" query parameter if it is set.
+func (s *Server) respond(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	n, ok := s.fetchNav(w, r, id)
+	if !ok {
+		return
+	}
+
+	n = s.resolve(n)
+	if s.authenticated != nil {
+		n = n.Visible(s.authenticated(r))
+	}
+
+	query := r.URL.Query()
+	if !query.Has("locale") {
+		api.JSON(w, r, http.StatusOK, n)
+		return
+	}
+
+	locale := query.Get("locale")
+	if locale != "" {
+		w.Header().Set("Content-Language", locale)
+	}
+
+	api.JSON(w, r, http.StatusOK, n.Localize(locale, s.fallbacks...))
+}
+
+// updateItem updates an Item of a Nav and responds with the updated Nav.
+func (s *Server) updateItem(w http.ResponseWriter, r *http.Request) {
+	var changes nav.ItemChanges
+	if err := api.Decode(r.Body, &changes); err != nil {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	id := api.UUIDParam(r, "NavID")
+	item := chi.URLParam(r, "Item")
+
+	n, ok := s.fetchNav(w, r, id)
+	if !ok {
+		return
+	}
+
+	// Errors of dispatched commands lose their type, so the changes are
+	// validated before the command is dispatched.
+	if err := n.UpdateItem(item, changes); err != nil {
+		api.Error(w, r, updateStatus(err), api.Friendly(err, "Failed to update item %q: %v", item, err))
+		return
+	}
+
+	cmd := nav.UpdateItemCmd(id, item, changes)
+	if err := s.commands.Dispatch(r.Context(), cmd.Any(), dispatch.Sync()); err != nil {
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to update item %q of nav %q: %v", item, id, err))
+		return
+	}
+
+	if n, ok = s.fetchNav(w, r, id); !ok {
+		return
+	}
+
+	api.JSON(w, r, http.StatusOK, s.resolve(n))
+}
+
+// reorder reorders the Items of a Nav and responds with the reordered Nav.
+func (s *Server) reorder(w http.ResponseWriter, r *http.Request) {
+	var req reorderRequest
+	if err := api.Decode(r.Body, &req); err != nil {
+		api.Error(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	id := api.UUIDParam(r, "NavID")
+
+	n, ok := s.fetchNav(w, r, id)
+	if !ok {
+		return
+	}
+
+	// Errors of dispatched commands lose their type, so the tree is validated
+	// before the command is dispatched.
+	if err := n.Reorder(req.Items); err != nil {
+		api.Error(w, r, reorderStatus(err), api.Friendly(err, "Failed to reorder nav %q: %v", id, err))
+		return
+	}
+
+	cmd := nav.ReorderCmd(id, req.Items)
+	if err := s.commands.Dispatch(r.Context(), cmd.Any(), dispatch.Sync()); err != nil {
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to reorder nav %q: %v", id, err))
+		return
+	}
+
+	if n, ok = s.fetchNav(w, r, id); !ok {
+		return
+	}
+
+	api.JSON(w, r, http.StatusOK, s.resolve(n))
+}
+
+// reorderRequest is the body of the PUT route.
+type reorderRequest struct {
+	Items []nav.Order `json:"items" schema:"required"`
+}
+
+func updateStatus(err error) int {
+	switch {
+	case errors.Is(err, nav.ErrItemNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, nav.ErrLabelItem),
+		errors.Is(err, nav.ErrPageLinkItem),
+		errors.Is(err, nav.ErrInvalidItemType),
+		errors.Is(err, nav.ErrMissingPage),
+		errors.Is(err, nav.ErrNotPageLink),
+		errors.Is(err, nav.ErrEmptyAttribute):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func reorderStatus(err error) int {
+	switch {
+	case errors.Is(err, nav.ErrItemNotFound),
+		errors.Is(err, nav.ErrDuplicateItem),
+		errors.Is(err, nav.ErrAmbiguousItem),
+		errors.Is(err, nav.ErrInitialItem):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// resolve resolves the Pages of the PageLink Items of the Nav if the Server has
+// a PageResolver.
+func (s *Server) resolve(n *nav.Nav) *nav.Nav {
+	if s.pages == nil {
+		return n
+	}
+	return n.ResolvePages(s.pages)
+}
+
+// fetchNav fetches the Nav with the given UUID. If the Nav cannot be fetched,
+// fetchNav responds with an error and returns false.
+func (s *Server) fetchNav(w http.ResponseWriter, r *http.Request, id uuid.UUID) (*nav.Nav, bool) {
+	n, err := s.navs.Fetch(r.Context(), id)
+	if err != nil {
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to fetch nav %q: %v", id, err))
+		return nil, false
+	}
+
+	if n.Name == "" {
+		api.Error(w, r, http.StatusNotFound, api.Friendly(nil, "Nav %q not found.", id))
+		return nil, false
+	}
+
+	return n, true
+}
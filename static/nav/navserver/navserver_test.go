@@ -0,0 +1,382 @@
+package navserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/command/cmdbus"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/commands"
+	"github.com/modernice/nice-cms/internal/discard"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/nav/navserver"
+	"github.com/modernice/nice-cms/static/page"
+)
+
+func TestServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	navs := nav.GoesRepository(repository.New(estore))
+
+	lookup := nav.NewLookup()
+	errs, err := lookup.Project(ctx, ebus, estore)
+	if err != nil {
+		t.Fatalf("run lookup: %v", err)
+	}
+	go discard.Errors(errs)
+
+	n, _ := nav.Create("main",
+		nav.NewStaticLink("about", "/about", "About", nav.LocaleLabel("de", "Über uns"), nav.LocalePath("de", "/de/ueber-uns")),
+		nav.NewStaticLink("contact", "/contact", "Contact", nav.LocaleLabel("fr", "Contact (fr)")),
+	)
+	if err := navs.Save(ctx, n); err != nil {
+		t.Fatalf("save Nav: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	srv := navserver.New(navs, navserver.WithLookup(lookup), navserver.WithFallbackLocales("fr"))
+
+	var full struct {
+		Items []nav.Item `json:"items"`
+	}
+	rec := get(srv, "/navs/"+n.ID.String())
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+	json.NewDecoder(rec.Body).Decode(&full)
+	if len(full.Items) != 2 || full.Items[0].Labels["de"] != "Über uns" {
+		t.Fatalf("Server should serve all locales without a locale parameter; got %s", rec.Body)
+	}
+
+	var localized nav.LocalizedNav
+	rec = get(srv, "/navs/name/main?locale=de-AT")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+	if cl := rec.Header().Get("Content-Language"); cl != "de-AT" {
+		t.Fatalf("Content-Language should be %q; is %q", "de-AT", cl)
+	}
+	json.NewDecoder(rec.Body).Decode(&localized)
+
+	if localized.Locale != "de-AT" || len(localized.Items) != 2 {
+		t.Fatalf("invalid localized Nav: %v", localized)
+	}
+	if about := localized.Items[0]; about.Label != "Über uns" || about.Path != "/de/ueber-uns" {
+		t.Fatalf("Item should fall back to the parent locale; got %v", about)
+	}
+	if contact := localized.Items[1]; contact.Label != "Contact (fr)" || contact.Path != "/contact" {
+		t.Fatalf("Item should fall back to the fallback locale and the default; got %v", contact)
+	}
+
+	if rec := get(srv, "/navs/name/footer"); rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown Nav should not be found; status is %d", rec.Code)
+	}
+
+	if rec := get(srv, "/navs/"+uuid.NewString()); rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown Nav should not be found; status is %d", rec.Code)
+	}
+}
+
+func TestServer_updateItem(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	cbus := cmdbus.New(commands.NewRegistry(), ebus)
+	navs := nav.GoesRepository(repository.New(estore))
+
+	lookup := nav.NewLookup()
+	errs, err := lookup.Project(ctx, ebus, estore)
+	if err != nil {
+		t.Fatalf("run lookup: %v", err)
+	}
+	go discard.Errors(errs)
+	go discard.Errors(nav.HandleCommands(ctx, cbus, navs, lookup))
+
+	n, _ := nav.Create("main", nav.NewLabel("about", "About", nav.SubTree(
+		nav.NewStaticLink("team", "/team", "Team"),
+	)))
+	if err := navs.Save(ctx, n); err != nil {
+		t.Fatalf("save Nav: %v", err)
+	}
+
+	srv := navserver.New(navs, navserver.WithItemUpdates(cbus))
+	path := "/navs/" + n.ID.String() + "/items/"
+
+	rec := patch(srv, path+"about.team", `{"localeLabels": {"de": "Das Team"}, "localePaths": {"": "/people"}}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+
+	var updated nav.Nav
+	if err := json.NewDecoder(rec.Body).Decode(&updated); err != nil {
+		t.Fatalf("decode Nav: %v", err)
+	}
+	item, err := updated.Item("about.team")
+	if err != nil {
+		t.Fatalf("updated Nav should have the Item: %v", err)
+	}
+	if item.Label("de") != "Das Team" || item.Path("") != "/people" {
+		t.Fatalf("Server should respond with the updated Item; got %v", item)
+	}
+
+	tests := []struct {
+		item   string
+		body   string
+		status int
+	}{
+		{"about", `{"localePaths": {"": "/about"}}`, http.StatusBadRequest},
+		{"about", `{"type": "foo"}`, http.StatusBadRequest},
+		{"about", `{"type": "page_link"}`, http.StatusBadRequest},
+		{"about", `{`, http.StatusBadRequest},
+		{"contact", `{"type": "label"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if rec := patch(srv, path+tt.item, tt.body); rec.Code != tt.status {
+			t.Fatalf("PATCH %s %s: status should be %d; is %d (%s)", tt.item, tt.body, tt.status, rec.Code, rec.Body)
+		}
+	}
+
+	if rec := patch(srv, "/navs/"+uuid.NewString()+"/items/about", `{"type": "label"}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown Nav should not be found; status is %d", rec.Code)
+	}
+
+	if rec := patch(navserver.New(navs), path+"about", `{"type": "label"}`); rec.Code != http.StatusMethodNotAllowed && rec.Code != http.StatusNotFound {
+		t.Fatalf("update route should not be installed without WithItemUpdates; status is %d", rec.Code)
+	}
+}
+
+func TestServer_pages(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	repo := repository.New(estore)
+	navs := nav.GoesRepository(repo)
+	pages := page.GoesRepository(repo)
+
+	idx := nav.NewPageIndex(nav.PagePath(func(_ uuid.UUID, slug string) string {
+		return "/pages/" + slug
+	}))
+	errs, err := idx.Project(ctx, ebus, estore)
+	if err != nil {
+		t.Fatalf("project page index: %v", err)
+	}
+	go discard.Errors(errs)
+
+	p := page.New(uuid.New())
+	p.Create("About Us")
+	if err := pages.Save(ctx, p); err != nil {
+		t.Fatalf("save Page: %v", err)
+	}
+
+	n, _ := nav.Create("main",
+		nav.NewPageLink("about", p.ID, nav.LocaleLabel("de", "Über uns")),
+		nav.NewPageLink("missing", uuid.New(), nav.LocaleLabel("", "Missing")),
+	)
+	if err := navs.Save(ctx, n); err != nil {
+		t.Fatalf("save Nav: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	srv := navserver.New(navs, navserver.WithPages(idx))
+
+	localized := func(locale string) nav.LocalizedNav {
+		var out nav.LocalizedNav
+		rec := get(srv, "/navs/"+n.ID.String()+"?locale="+locale)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+		}
+		json.NewDecoder(rec.Body).Decode(&out)
+		return out
+	}
+
+	ln := localized("en")
+	if about := ln.Items[0]; about.Label != "About Us" || about.Path != "/pages/about-us" || about.Page == nil || *about.Page != p.ID {
+		t.Fatalf("page link should be resolved to the title and path of the Page; got %v", about)
+	}
+	if missing := ln.Items[1]; missing.Label != "Missing" || missing.Path != "" {
+		t.Fatalf("page link to an unknown Page should have no path; got %v", missing)
+	}
+	if about := localized("de").Items[0]; about.Label != "Über uns" || about.Path != "/pages/about-us" {
+		t.Fatalf("page link should keep its own labels; got %v", about)
+	}
+
+	if err := pages.Use(ctx, p.ID, func(p *page.Page) error {
+		return p.Rename("Team")
+	}); err != nil {
+		t.Fatalf("rename Page: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	if about := localized("en").Items[0]; about.Label != "Team" || about.Path != "/pages/team" {
+		t.Fatalf("page link should follow the renamed Page; got %v", about)
+	}
+
+	var full nav.Nav
+	json.NewDecoder(get(srv, "/navs/"+n.ID.String()).Body).Decode(&full)
+	if about, _ := full.Item("about"); about.Path("") != "/pages/team" || about.Label("") != "Team" {
+		t.Fatalf("page link should be resolved without a locale; got %v", about)
+	}
+
+	stored, _ := navs.Fetch(ctx, n.ID)
+	if about, _ := stored.Item("about"); len(about.Paths) != 0 || about.Label("") != "" {
+		t.Fatalf("resolving page links should not change the Nav; got %v", about)
+	}
+}
+
+func TestServer_visibility(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	cbus := cmdbus.New(commands.NewRegistry(), ebus)
+	navs := nav.GoesRepository(repository.New(estore))
+	go discard.Errors(nav.HandleCommands(ctx, cbus, navs, nav.NewLookup()))
+
+	n, _ := nav.Create("main",
+		nav.NewStaticLink("home", "/", "Home"),
+		nav.NewStaticLink("account", "/account", "Account"),
+		nav.NewStaticLink("jobs", "/jobs", "Jobs", nav.Hidden()),
+	)
+	if err := navs.Save(ctx, n); err != nil {
+		t.Fatalf("save Nav: %v", err)
+	}
+
+	srv := navserver.New(navs, navserver.WithItemUpdates(cbus), navserver.WithVisibility(func(r *http.Request) bool {
+		return r.Header.Get("Authorization") != ""
+	}))
+
+	rec := patch(srv, "/navs/"+n.ID.String()+"/items/account", `{"requiresAuth": true, "attributes": {"icon": "user"}}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+
+	var updated nav.Nav
+	json.NewDecoder(rec.Body).Decode(&updated)
+	if account, _ := updated.Item("account"); !account.RequiresAuth || account.Attributes["icon"] != "user" || !updated.HasItem("jobs") {
+		t.Fatalf("Server should respond with the updated, unfiltered Nav; got %v", updated.Items)
+	}
+
+	items := func(auth string) []nav.LocalizedItem {
+		req := httptest.NewRequest(http.MethodGet, "/navs/"+n.ID.String()+"?locale=en", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+
+		var ln nav.LocalizedNav
+		json.NewDecoder(rec.Body).Decode(&ln)
+		return ln.Items
+	}
+
+	if public := items(""); len(public) != 1 || public[0].ID != "home" {
+		t.Fatalf("public Nav should only have the public Items; got %v", public)
+	}
+
+	authd := items("Bearer foo")
+	if len(authd) != 2 || authd[1].ID != "account" || !authd[1].RequiresAuth || authd[1].Attributes["icon"] != "user" {
+		t.Fatalf("authenticated Nav should have the visible Items with their metadata; got %v", authd)
+	}
+
+	if rec := patch(srv, "/navs/"+n.ID.String()+"/items/home", `{"attributes": {"": "foo"}}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("status should be %d for an empty attribute key; is %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestServer_reorder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	cbus := cmdbus.New(commands.NewRegistry(), ebus)
+	navs := nav.GoesRepository(repository.New(estore))
+	go discard.Errors(nav.HandleCommands(ctx, cbus, navs, nav.NewLookup()))
+
+	n, _ := nav.Create("main", nav.NewLabel("home", "Home"))
+	n.Append(
+		nav.NewLabel("about", "About", nav.SubTree(
+			nav.NewStaticLink("team", "/team", "Team"),
+			nav.NewStaticLink("jobs", "/jobs", "Jobs"),
+		)),
+		nav.NewStaticLink("blog", "/blog", "Blog"),
+	)
+	if err := navs.Save(ctx, n); err != nil {
+		t.Fatalf("save Nav: %v", err)
+	}
+
+	srv := navserver.New(navs, navserver.WithItemUpdates(cbus))
+	path := "/navs/" + n.ID.String() + "/tree"
+
+	rec := put(srv, path, `{"items": [{"id": "about", "items": [{"id": "jobs"}, {"id": "blog"}]}, {"id": "home"}]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+
+	var reordered nav.Nav
+	json.NewDecoder(rec.Body).Decode(&reordered)
+
+	if len(reordered.Items) != 2 || reordered.Items[0].ID != "about" || reordered.Items[1].ID != "home" {
+		t.Fatalf("root Items should be reordered; got %v", reordered.Items)
+	}
+	about := reordered.Items[0]
+	if len(about.Tree.Items) != 2 || about.Tree.Items[0].ID != "jobs" || about.Tree.Items[1].ID != "blog" || reordered.HasItem("about.team") {
+		t.Fatalf("subtree should be reordered; got %v", about.Tree.Items)
+	}
+
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{`{"items": [{"id": "about"}, {"id": "home"}, {"id": "contact"}]}`, http.StatusBadRequest},
+		{`{"items": [{"id": "about"}, {"id": "about"}, {"id": "home"}]}`, http.StatusBadRequest},
+		{`{"items": [{"id": "about"}]}`, http.StatusBadRequest},
+		{`{`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if rec := put(srv, path, tt.body); rec.Code != tt.status {
+			t.Fatalf("PUT %s: status should be %d; is %d (%s)", tt.body, tt.status, rec.Code, rec.Body)
+		}
+	}
+
+	if rec := put(srv, "/navs/"+uuid.NewString()+"/tree", `{"items": []}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown Nav should not be found; status is %d", rec.Code)
+	}
+}
+
+func get(h http.Handler, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func patch(h http.Handler, path, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, path, strings.NewReader(body)))
+	return rec
+}
+
+func put(h http.Handler, path, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, path, strings.NewReader(body)))
+	return rec
+}
@@ -0,0 +1,130 @@
+package navserver
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/internal/api"
+	"github.com/modernice/nice-cms/openapi"
+	"github.com/modernice/nice-cms/schema"
+	"github.com/modernice/nice-cms/static/nav"
+)
+
+// WithOpenAPI returns an Option that installs the OpenAPI route, which serves
+// the OpenAPI document of the Server (see Server.OpenAPI). The document can
+// also be merged into the document of the media server:
+//
+//	srv := navserver.New(navs, navserver.WithLookup(lookup))
+//	mediaserver.WithOpenAPI(info, []*openapi.Document{srv.OpenAPI(openapi.Info{})})
+func WithOpenAPI(info openapi.Info) Option {
+	return func(s *Server) {
+		s.openapi = &info
+	}
+}
+
+// OpenAPI returns the OpenAPI document of the routes that are installed in the
+// Server. Routes that are not installed, e.g. the PATCH and PUT routes without
+// WithItemUpdates, are not described.
+func (s *Server) OpenAPI(info openapi.Info) *openapi.Document {
+	doc := openapi.New(info)
+	doc.AddTag(openapi.Tag{Name: "navs", Description: "Navigations and their items."})
+	ops := operations()
+
+	chi.Walk(s.router, func(method, path string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		op, ok := ops[route{method, path}]
+		if !ok {
+			return nil
+		}
+		doc.Add(method, path, op)
+		return nil
+	})
+
+	return doc
+}
+
+// showOpenAPI responds with the OpenAPI document of the Server.
+func (s *Server) showOpenAPI(w http.ResponseWriter, r *http.Request) {
+	api.JSON(w, r, http.StatusOK, s.OpenAPI(*s.openapi))
+}
+
+type route struct {
+	method string
+	path   string
+}
+
+// navJSON is the JSON form of a nav.Nav (see nav.Nav.MarshalJSON).
+type navJSON struct {
+	ID    uuid.UUID  `json:"id"`
+	Name  string     `json:"name"`
+	Items []nav.Item `json:"items"`
+}
+
+var pathParams = map[string]string{
+	"NavID": "UUID of the nav.",
+	"Name":  "Name of the nav.",
+	"Item":  "Dot-separated path of the item.",
+}
+
+// operations returns the descriptions of the routes of the Server.
+func operations() map[route]openapi.Operation {
+	navSchema := schema.Of(navJSON{})
+	localeParam := openapi.QueryParam("locale", "Locale of the labels and paths. Labels and paths without a value for the locale fall back to the parent and fallback locales.", &schema.Schema{Type: "string"})
+
+	ops := map[route]openapi.Operation{
+		{http.MethodGet, ShowNavRoute}: {
+			OperationID: "showNav",
+			Summary:     "Show nav",
+			Description: "With the locale parameter, the items have the label and path of that locale.",
+			Tags:        []string{"navs"},
+			Parameters:  []openapi.Parameter{localeParam},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The nav.", navSchema)},
+		},
+		{http.MethodGet, NavByNameRoute}: {
+			OperationID: "showNavByName",
+			Summary:     "Show nav by name",
+			Description: "With the locale parameter, the items have the label and path of that locale.",
+			Tags:        []string{"navs"},
+			Parameters:  []openapi.Parameter{localeParam},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The nav.", navSchema)},
+		},
+		{http.MethodPatch, ItemRoute}: {
+			OperationID: "updateNavItem",
+			Summary:     "Update nav item",
+			Tags:        []string{"navs"},
+			RequestBody: openapi.JSONBody(schema.Of(nav.ItemChanges{})),
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The updated nav.", navSchema)},
+		},
+		{http.MethodPut, TreeRoute}: {
+			OperationID: "reorderNav",
+			Summary:     "Reorder nav",
+			Tags:        []string{"navs"},
+			RequestBody: openapi.JSONBody(schema.Of(reorderRequest{})),
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The reordered nav.", navSchema)},
+		},
+		{http.MethodGet, OpenAPIRoute}: {
+			OperationID: "showNavOpenAPI",
+			Summary:     "Show nav OpenAPI document",
+			Tags:        []string{"navs"},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The OpenAPI document.", &schema.Schema{Type: "object"})},
+		},
+	}
+
+	errorResponse := openapi.JSONResponse("Error.", &schema.Schema{
+		Type:       "object",
+		Properties: map[string]*schema.Schema{"error": {Type: "string"}},
+		Required:   []string{"error"},
+	})
+
+	for r, op := range ops {
+		var params []openapi.Parameter
+		for _, name := range openapi.PathParams(r.path) {
+			params = append(params, openapi.PathParam(name, pathParams[name]))
+		}
+		op.Parameters = append(params, op.Parameters...)
+		op.Responses["default"] = errorResponse
+		ops[r] = op
+	}
+
+	return ops
+}
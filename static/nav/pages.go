@@ -0,0 +1,185 @@
+package nav
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/projector"
+	"github.com/modernice/nice-cms/static/page"
+)
+
+// LinkedPage is a Page that is linked by PageLink Items.
+type LinkedPage struct {
+	Slug  string
+	Title string
+	Path  string
+}
+
+// PageResolver resolves the Pages that are linked by PageLink Items.
+type PageResolver interface {
+	// LinkedPage returns the Page with the given UUID, or false if the Page
+	// doesn't exist.
+	LinkedPage(uuid.UUID) (LinkedPage, bool)
+}
+
+// ResolvePages returns a copy of the Nav in which each PageLink Item links to
+// the current path of its Page. PageLink Items without a default label are
+// labeled with the title of their Page. PageLink Items of Pages that don't
+// exist have no path.
+func (nav *Nav) ResolvePages(pages PageResolver) *Nav {
+	out := New(nav.ID)
+	out.Name = nav.Name
+	if nav.Tree != nil {
+		out.Items = resolveItems(nav.Items, pages)
+	}
+	return out
+}
+
+func resolveItems(items []Item, pages PageResolver) []Item {
+	out := make([]Item, len(items))
+	for i, item := range items {
+		out[i] = resolveItem(item, pages)
+	}
+	return out
+}
+
+func resolveItem(item Item, pages PageResolver) Item {
+	item.Paths = copyLocales(item.Paths)
+	item.Labels = copyLocales(item.Labels)
+
+	if item.Tree != nil {
+		item.Tree = NewTree(resolveItems(item.Tree.Items, pages)...)
+	}
+
+	if item.Type != PageLink || item.Page == nil {
+		return item
+	}
+
+	p, ok := pages.LinkedPage(*item.Page)
+	if !ok {
+		return item
+	}
+
+	item.Paths[""] = p.Path
+	if item.Labels[""] == "" {
+		item.Labels[""] = p.Title
+	}
+
+	return item
+}
+
+func copyLocales(values map[string]string) map[string]string {
+	out := make(map[string]string, len(values))
+	for l, v := range values {
+		out[l] = v
+	}
+	return out
+}
+
+// PageIndexOption is a PageIndex option.
+type PageIndexOption func(*PageIndex)
+
+// PagePath returns a PageIndexOption that sets the function that returns the
+// path of a Page from its slug. By default, the path of a Page is "/" followed
+// by its slug.
+func PagePath(fn func(id uuid.UUID, slug string) string) PageIndexOption {
+	return func(idx *PageIndex) {
+		idx.path = fn
+	}
+}
+
+// PageProjection returns a PageIndexOption that configures the error handling
+// of the projection.
+func PageProjection(opts ...projector.Option) PageIndexOption {
+	return func(idx *PageIndex) {
+		idx.projectorOpts = append(idx.projectorOpts, opts...)
+	}
+}
+
+// PageIndex is a projection of the names of Pages that resolves the Pages of
+// PageLink Items. The title of a Page is its name and its slug is derived from
+// the name (see page.Slug), so PageLink Items follow renamed Pages. Deleted
+// Pages are removed from the PageIndex. PageIndex is thread-safe.
+//
+// Use NewPageIndex to create a PageIndex.
+type PageIndex struct {
+	projector     *projector.Projector
+	projectorOpts []projector.Option
+	path          func(uuid.UUID, string) string
+
+	mux   sync.RWMutex
+	names map[uuid.UUID]string
+}
+
+// NewPageIndex returns a new PageIndex.
+func NewPageIndex(opts ...PageIndexOption) *PageIndex {
+	idx := &PageIndex{
+		path:  func(_ uuid.UUID, slug string) string { return "/" + slug },
+		names: make(map[uuid.UUID]string),
+	}
+	for _, opt := range opts {
+		opt(idx)
+	}
+	idx.projector = projector.New(idx.projectorOpts...)
+	return idx
+}
+
+// PageEvents are the events that are projected by a PageIndex.
+var PageEvents = [...]string{
+	page.Created,
+	page.Renamed,
+	page.Deleted,
+}
+
+// LinkedPage returns the Page with the given UUID, or false if the Page
+// doesn't exist.
+func (idx *PageIndex) LinkedPage(id uuid.UUID) (LinkedPage, bool) {
+	idx.mux.RLock()
+	name, ok := idx.names[id]
+	idx.mux.RUnlock()
+	if !ok {
+		return LinkedPage{}, false
+	}
+
+	slug := page.Slug(name)
+
+	return LinkedPage{
+		Slug:  slug,
+		Title: name,
+		Path:  idx.path(id, slug),
+	}, true
+}
+
+// Project projects the PageIndex in a new goroutine and returns a channel of
+// asynchronous errors. Failed projection jobs are handled according to the
+// projector.Policy of the PageIndex.
+func (idx *PageIndex) Project(ctx context.Context, bus event.Bus, store event.Store, opts ...schedule.ContinuousOption) (<-chan error, error) {
+	schedule := schedule.Continuously(bus, store, PageEvents[:], opts...)
+
+	return idx.projector.Run(ctx, schedule, idx)
+}
+
+// Status returns the projection status of the PageIndex.
+func (idx *PageIndex) Status() projector.Status {
+	return idx.projector.Status()
+}
+
+// ApplyEvent applies page events.
+func (idx *PageIndex) ApplyEvent(evt event.Event) {
+	id, _, _ := evt.Aggregate()
+
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+
+	switch data := evt.Data().(type) {
+	case page.CreatedData:
+		idx.names[id] = data.Name
+	case page.RenamedData:
+		idx.names[id] = data.Name
+	case page.DeletedData:
+		delete(idx.names, id)
+	}
+}
@@ -0,0 +1,71 @@
+package nav_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/page"
+)
+
+func TestPageIndex(t *testing.T) {
+	idx := nav.NewPageIndex()
+	pageID := uuid.New()
+
+	if _, ok := idx.LinkedPage(pageID); ok {
+		t.Fatalf("LinkedPage should return false for an unknown Page")
+	}
+
+	idx.ApplyEvent(event.New(page.Created, page.CreatedData{Name: "About Us"}, event.Aggregate(pageID, page.Aggregate, 1)).Any())
+
+	want := nav.LinkedPage{Slug: "about-us", Title: "About Us", Path: "/about-us"}
+	if p, ok := idx.LinkedPage(pageID); !ok || p != want {
+		t.Fatalf("LinkedPage should return %v; got %v", want, p)
+	}
+
+	idx.ApplyEvent(event.New(page.Renamed, page.RenamedData{OldName: "About Us", Name: "Team"}, event.Aggregate(pageID, page.Aggregate, 2)).Any())
+
+	want = nav.LinkedPage{Slug: "team", Title: "Team", Path: "/team"}
+	if p, ok := idx.LinkedPage(pageID); !ok || p != want {
+		t.Fatalf("LinkedPage should return %v after rename; got %v", want, p)
+	}
+
+	idx.ApplyEvent(event.New(page.Deleted, page.DeletedData{Name: "Team"}, event.Aggregate(pageID, page.Aggregate, 3)).Any())
+
+	if _, ok := idx.LinkedPage(pageID); ok {
+		t.Fatalf("LinkedPage should return false for a deleted Page")
+	}
+}
+
+func TestNav_ResolvePages(t *testing.T) {
+	idx := nav.NewPageIndex()
+	pageID := uuid.New()
+	idx.ApplyEvent(event.New(page.Created, page.CreatedData{Name: "About Us"}, event.Aggregate(pageID, page.Aggregate, 1)).Any())
+
+	n, _ := nav.Create("foo",
+		nav.NewLabel("foo", "Foo", nav.SubTree(
+			nav.NewPageLink("about", pageID),
+			nav.NewPageLink("team", pageID, nav.LocaleLabel("", "Our Team")),
+		)),
+		nav.NewPageLink("missing", uuid.New()),
+	)
+
+	resolved := n.ResolvePages(idx)
+
+	if about, _ := resolved.Item("foo.about"); about.Path("") != "/about-us" || about.Label("") != "About Us" {
+		t.Fatalf("page link should link to the Page; got %v", about)
+	}
+
+	if team, _ := resolved.Item("foo.team"); team.Path("") != "/about-us" || team.Label("") != "Our Team" {
+		t.Fatalf("page link should keep its own default label; got %v", team)
+	}
+
+	if missing, _ := resolved.Item("missing"); missing.Path("") != "" {
+		t.Fatalf("page link to an unknown Page should have no path; got %v", missing)
+	}
+
+	if about, _ := n.Item("foo.about"); len(about.Paths) != 0 || len(about.Labels) != 0 {
+		t.Fatalf("ResolvePages should not change the Nav; got %v", about)
+	}
+}
@@ -0,0 +1,288 @@
+package nav
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modernice/goes/aggregate"
+)
+
+// ErrAmbiguousItem is returned by Reorder when an Item is moved to another
+// Tree, but multiple Items with its ID could be moved.
+var ErrAmbiguousItem = errors.New("ambiguous item")
+
+// Order is the desired position of an Item within a Nav. Items are identified
+// by their IDs and the Items of an Order are the desired subtree of the Item.
+type Order struct {
+	ID    string  `json:"id" schema:"required,minLength=1"`
+	Items []Order `json:"items,omitempty"`
+}
+
+// Reorder moves, removes and sorts the Items of the Nav so that the Nav
+// matches the given tree of Item IDs. Items that are not part of the tree are
+// removed. Reorder validates the complete tree before it changes the Nav and
+// raises only the events that are needed:
+//
+//	n.Reorder([]nav.Order{
+//		{ID: "about", Items: []nav.Order{{ID: "team"}, {ID: "contact"}}},
+//		{ID: "blog"},
+//	})
+//
+// An Item is identified by its position if it is already at the desired
+// position. Otherwise it is moved from the only other position with its ID, or
+// ErrAmbiguousItem is returned. Unknown IDs return ErrItemNotFound, IDs that
+// appear multiple times within the same subtree return ErrDuplicateItem and
+// removing an initial Item returns ErrInitialItem.
+func (nav *Nav) Reorder(tree []Order) error {
+	plan, err := nav.planReorder(tree)
+	if err != nil {
+		return err
+	}
+
+	// loc tracks the current paths of the Items by their paths before the
+	// reordering.
+	loc := make(map[string]string)
+	walkTree(nav.Tree, "", func(path string, _ Item) {
+		loc[path] = path
+	})
+
+	for _, m := range plan.moves {
+		from := loc[m.source]
+		if from == m.target {
+			continue
+		}
+
+		item, _ := nav.Item(from)
+		parent := parentPath(m.target)
+		index := len(nav.treeAt(parent).Items)
+
+		aggregate.NextEvent(nav, ItemsRemoved, ItemsRemovedData{Items: []string{from}})
+		aggregate.NextEvent(nav, ItemsAdded, ItemsAddedData{
+			Items: []Item{item},
+			Index: index,
+			Path:  parent,
+		})
+
+		for orig, cur := range loc {
+			if cur == from {
+				loc[orig] = m.target
+			} else if strings.HasPrefix(cur, from+".") {
+				loc[orig] = m.target + strings.TrimPrefix(cur, from)
+			}
+		}
+	}
+
+	if len(plan.removed) > 0 {
+		paths := make([]string, len(plan.removed))
+		for i, path := range plan.removed {
+			paths[i] = loc[path]
+		}
+		aggregate.NextEvent(nav, ItemsRemoved, ItemsRemovedData{Items: paths})
+	}
+
+	nav.sortLevels("", tree)
+
+	return nil
+}
+
+type reorderPlan struct {
+	// moves are the desired paths of the Items in pre-order, together with
+	// the paths of the Items before the reordering.
+	moves []reorderMove
+
+	// removed are the paths of the topmost Items that are removed.
+	removed []string
+}
+
+type reorderMove struct {
+	target string
+	source string
+}
+
+func (nav *Nav) planReorder(tree []Order) (reorderPlan, error) {
+	var plan reorderPlan
+
+	byID := make(map[string][]string)
+	used := make(map[string]bool)
+	walkTree(nav.Tree, "", func(path string, item Item) {
+		byID[item.ID] = append(byID[item.ID], path)
+	})
+
+	var targets []reorderMove
+	if err := collectTargets(tree, "", &targets); err != nil {
+		return plan, err
+	}
+
+	// Items that already are at their desired position are matched first,
+	// so that they cannot be mistaken for moved Items.
+	for i, m := range targets {
+		if _, err := nav.Item(m.target); err == nil {
+			targets[i].source = m.target
+			used[m.target] = true
+		}
+	}
+
+	for i, m := range targets {
+		if m.source != "" {
+			continue
+		}
+
+		id := m.target[strings.LastIndex(m.target, ".")+1:]
+
+		var candidates []string
+		for _, path := range byID[id] {
+			if !used[path] {
+				candidates = append(candidates, path)
+			}
+		}
+
+		switch len(candidates) {
+		case 0:
+			return plan, fmt.Errorf("%q: %w", m.target, ErrItemNotFound)
+		case 1:
+			targets[i].source = candidates[0]
+			used[candidates[0]] = true
+		default:
+			return plan, fmt.Errorf("%q: %w: %s", m.target, ErrAmbiguousItem, strings.Join(candidates, ", "))
+		}
+	}
+
+	var err error
+	walkTree(nav.Tree, "", func(path string, item Item) {
+		if err != nil || used[path] {
+			return
+		}
+		if item.Initial {
+			err = fmt.Errorf("cannot remove initial item %q: %w", path, ErrInitialItem)
+			return
+		}
+		if parent := parentPath(path); parent == "" || used[parent] {
+			plan.removed = append(plan.removed, path)
+		}
+	})
+	if err != nil {
+		return plan, err
+	}
+
+	plan.moves = targets
+
+	return plan, nil
+}
+
+// collectTargets appends the paths of the Items of the tree in pre-order.
+func collectTargets(tree []Order, parent string, out *[]reorderMove) error {
+	seen := make(map[string]bool, len(tree))
+	for _, o := range tree {
+		path := o.ID
+		if parent != "" {
+			path = parent + "." + o.ID
+		}
+
+		if o.ID == "" || strings.Contains(o.ID, ".") {
+			return fmt.Errorf("%q: %w", path, ErrItemNotFound)
+		}
+
+		if seen[o.ID] {
+			return fmt.Errorf("%q: %w", path, ErrDuplicateItem)
+		}
+		seen[o.ID] = true
+
+		*out = append(*out, reorderMove{target: path})
+
+		if err := collectTargets(o.Items, path, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortLevels sorts the Tree at the given path and its subtrees by the given
+// tree if their order differs.
+func (nav *Nav) sortLevels(path string, tree []Order) {
+	t := nav.treeAt(path)
+
+	sorting := make([]string, len(tree))
+	for i, o := range tree {
+		sorting[i] = o.ID
+	}
+
+	if !sameOrder(t.Items, sorting) {
+		aggregate.NextEvent(nav, Sorted, SortedData{
+			Sorting: sorting,
+			Path:    path,
+		})
+	}
+
+	for _, o := range tree {
+		if len(o.Items) == 0 {
+			continue
+		}
+		itemPath := o.ID
+		if path != "" {
+			itemPath = path + "." + o.ID
+		}
+		nav.sortLevels(itemPath, o.Items)
+	}
+}
+
+// treeAt returns the Tree at the given path, or an empty Tree if the Item at
+// path has no subtree.
+func (nav *Nav) treeAt(path string) *Tree {
+	if path == "" {
+		return nav.Tree
+	}
+	item, err := nav.Item(path)
+	if err != nil || item.Tree == nil {
+		return NewTree()
+	}
+	return item.Tree
+}
+
+func sameOrder(items []Item, ids []string) bool {
+	if len(items) != len(ids) {
+		return false
+	}
+	for i, item := range items {
+		if item.ID != ids[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// walkTree calls fn for each Item of the Tree in pre-order.
+func walkTree(t *Tree, prefix string, fn func(string, Item)) {
+	if t == nil {
+		return
+	}
+	for _, item := range t.Items {
+		path := item.ID
+		if prefix != "" {
+			path = prefix + "." + item.ID
+		}
+		fn(path, item)
+		walkTree(item.Tree, path, fn)
+	}
+}
+
+// cloneItem returns a deep copy of the Item.
+func cloneItem(item Item) Item {
+	item.Paths = copyLocales(item.Paths)
+	item.Labels = copyLocales(item.Labels)
+	if item.Attributes != nil {
+		item.Attributes = copyLocales(item.Attributes)
+	}
+	if item.Page != nil {
+		page := *item.Page
+		item.Page = &page
+	}
+	if item.Tree != nil {
+		items := make([]Item, len(item.Tree.Items))
+		for i, child := range item.Tree.Items {
+			items[i] = cloneItem(child)
+		}
+		item.Tree = NewTree(items...)
+	}
+	return item
+}
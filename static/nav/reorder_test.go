@@ -0,0 +1,156 @@
+package nav_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/static/nav"
+)
+
+func TestNav_Reorder(t *testing.T) {
+	n, _ := nav.Create("foo")
+	n.Append(
+		nav.NewLabel("about", "About", nav.SubTree(
+			nav.NewStaticLink("team", "/team", "Team"),
+			nav.NewStaticLink("jobs", "/jobs", "Jobs", nav.SubTree(
+				nav.NewStaticLink("dev", "/jobs/dev", "Developer"),
+			)),
+		)),
+		nav.NewStaticLink("blog", "/blog", "Blog"),
+		nav.NewStaticLink("contact", "/contact", "Contact"),
+	)
+	before := len(n.AggregateChanges())
+
+	if err := n.Reorder([]nav.Order{
+		{ID: "contact"},
+		{ID: "about", Items: []nav.Order{
+			{ID: "jobs"},
+			{ID: "team", Items: []nav.Order{{ID: "dev"}}},
+		}},
+	}); err != nil {
+		t.Fatalf("Reorder failed with %q", err)
+	}
+
+	want := []string{"contact", "about", "about.jobs", "about.team", "about.team.dev"}
+	if got := paths(n.Tree); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Nav should have the Items %v; got %v", want, got)
+	}
+
+	dev, _ := n.Item("about.team.dev")
+	if dev.Path("") != "/jobs/dev" || dev.Label("") != "Developer" {
+		t.Fatalf("moved Item should keep its data; got %v", dev)
+	}
+
+	var names []string
+	for _, evt := range n.AggregateChanges()[before:] {
+		names = append(names, evt.Name())
+	}
+	wantNames := []string{nav.ItemsRemoved, nav.ItemsAdded, nav.ItemsRemoved, nav.Sorted, nav.Sorted}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("Reorder should raise the events %v; got %v", wantNames, names)
+	}
+
+	replayed := nav.New(n.ID)
+	if err := aggregate.ApplyHistory(replayed, n.AggregateChanges()); err != nil {
+		t.Fatalf("apply history: %v", err)
+	}
+	if !cmp.Equal(paths(replayed.Tree), want) {
+		t.Fatalf("replayed Nav should have the Items %v; got %v", want, paths(replayed.Tree))
+	}
+}
+
+func TestNav_Reorder_noChange(t *testing.T) {
+	n, _ := nav.Create("foo", nav.NewLabel("foo", "Foo", nav.SubTree(nav.NewLabel("bar", "Bar"))), nav.NewLabel("baz", "Baz"))
+	n.Commit()
+
+	if err := n.Reorder([]nav.Order{{ID: "foo", Items: []nav.Order{{ID: "bar"}}}, {ID: "baz"}}); err != nil {
+		t.Fatalf("Reorder failed with %q", err)
+	}
+
+	if changes := n.AggregateChanges(); len(changes) != 0 {
+		t.Fatalf("Reorder should raise no events; got %v", changes)
+	}
+}
+
+func TestNav_Reorder_errors(t *testing.T) {
+	tests := map[string]struct {
+		items []nav.Item
+		tree  []nav.Order
+		want  error
+	}{
+		"unknown item": {
+			items: []nav.Item{nav.NewLabel("foo", "Foo")},
+			tree:  []nav.Order{{ID: "foo"}, {ID: "bar"}},
+			want:  nav.ErrItemNotFound,
+		},
+		"duplicate item": {
+			items: []nav.Item{nav.NewLabel("foo", "Foo")},
+			tree:  []nav.Order{{ID: "foo"}, {ID: "foo"}},
+			want:  nav.ErrDuplicateItem,
+		},
+		"moved twice": {
+			items: []nav.Item{nav.NewLabel("foo", "Foo", nav.SubTree(nav.NewLabel("bar", "Bar"))), nav.NewLabel("baz", "Baz")},
+			tree:  []nav.Order{{ID: "foo"}, {ID: "baz", Items: []nav.Order{{ID: "bar"}, {ID: "foo"}}}},
+			want:  nav.ErrItemNotFound,
+		},
+		"ambiguous item": {
+			items: []nav.Item{
+				nav.NewLabel("foo", "Foo", nav.SubTree(nav.NewLabel("bar", "Bar"))),
+				nav.NewLabel("baz", "Baz", nav.SubTree(nav.NewLabel("bar", "Bar"))),
+				nav.NewLabel("qux", "Qux"),
+			},
+			tree: []nav.Order{{ID: "foo"}, {ID: "baz"}, {ID: "qux", Items: []nav.Order{{ID: "bar"}}}},
+			want: nav.ErrAmbiguousItem,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			n, _ := nav.Create("foo")
+			n.Append(tt.items...)
+			n.Commit()
+
+			if err := n.Reorder(tt.tree); !errors.Is(err, tt.want) {
+				t.Fatalf("Reorder should fail with %q; got %q", tt.want, err)
+			}
+
+			if changes := n.AggregateChanges(); len(changes) != 0 {
+				t.Fatalf("failed Reorder should not change the Nav; got %v", changes)
+			}
+		})
+	}
+
+	n, _ := nav.Create("foo", nav.NewLabel("foo", "Foo", nav.SubTree(nav.NewLabel("bar", "Bar"))))
+	n.Commit()
+	if err := n.Reorder([]nav.Order{{ID: "foo"}}); !errors.Is(err, nav.ErrInitialItem) {
+		t.Fatalf("Reorder should fail with %q; got %q", nav.ErrInitialItem, err)
+	}
+
+	if err := n.Reorder([]nav.Order{{ID: "bar", Items: []nav.Order{{ID: "foo"}}}}); err != nil {
+		t.Fatalf("initial Items should be movable; Reorder failed with %q", err)
+	}
+	test.Change(t, n, nav.ItemsAdded, test.Exactly(2))
+}
+
+func paths(t *nav.Tree) []string {
+	var out []string
+	var walk func([]nav.Item, string)
+	walk = func(items []nav.Item, prefix string) {
+		for _, item := range items {
+			path := item.ID
+			if prefix != "" {
+				path = prefix + "." + item.ID
+			}
+			out = append(out, path)
+			if item.Tree != nil {
+				walk(item.Tree.Items, path)
+			}
+		}
+	}
+	walk(t.Items, "")
+	return out
+}
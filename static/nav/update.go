@@ -0,0 +1,216 @@
+package nav
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/event"
+)
+
+var (
+	// ErrInvalidItemType is returned when converting an Item to an unknown
+	// ItemType.
+	ErrInvalidItemType = errors.New("invalid item type")
+
+	// ErrMissingPage is returned when converting an Item to a PageLink without
+	// providing the Page to link to.
+	ErrMissingPage = errors.New("page link item without page")
+
+	// ErrNotPageLink is returned when trying to set the Page of an Item that is
+	// not a PageLink.
+	ErrNotPageLink = errors.New("item is not a page link")
+
+	// ErrEmptyAttribute is returned when trying to set an attribute with an
+	// empty key.
+	ErrEmptyAttribute = errors.New("empty attribute key")
+)
+
+// ItemChanges are changes to an Item. Empty fields are not changed.
+type ItemChanges struct {
+	// Type converts the Item to another ItemType. Converting an Item to a
+	// Label or PageLink removes its paths. Converting an Item to a PageLink
+	// requires a Page.
+	Type ItemType `json:"type,omitempty"`
+
+	// Page changes the Page that is linked by a PageLink Item.
+	Page uuid.UUID `json:"page,omitempty"`
+
+	// Labels are the labels of the Item by locale. An empty label removes the
+	// label of a non-default locale (see Nav.SetLabel).
+	Labels map[string]string `json:"localeLabels,omitempty"`
+
+	// Paths are the link targets of the Item by locale. An empty path removes
+	// the path of a non-default locale (see Nav.SetPath).
+	Paths map[string]string `json:"localePaths,omitempty"`
+
+	// Hidden hides or shows the Item.
+	Hidden *bool `json:"hidden,omitempty"`
+
+	// RequiresAuth changes whether the Item is only visible to authenticated
+	// users.
+	RequiresAuth *bool `json:"requiresAuth,omitempty"`
+
+	// Attributes are the attributes of the Item by key. An empty value
+	// removes an attribute.
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Empty returns whether the ItemChanges change nothing.
+func (c ItemChanges) Empty() bool {
+	return c.Type == "" &&
+		c.Page == uuid.Nil &&
+		len(c.Labels) == 0 &&
+		len(c.Paths) == 0 &&
+		c.Hidden == nil &&
+		c.RequiresAuth == nil &&
+		len(c.Attributes) == 0
+}
+
+// UpdateItem updates the label, the link target, the type, the visibility and
+// the attributes of the Item at the given path. If the updated Item is a Label or PageLink, the changes must
+// not contain paths, otherwise ErrLabelItem or ErrPageLinkItem is returned.
+// Only PageLink Items link to a Page.
+func (nav *Nav) UpdateItem(path string, changes ItemChanges) error {
+	item, err := nav.Item(path)
+	if err != nil {
+		return fmt.Errorf("%q: %w", path, err)
+	}
+
+	typ := item.Type
+	if changes.Type != "" {
+		if !validItemType(changes.Type) {
+			return fmt.Errorf("%q: %w: %q", path, ErrInvalidItemType, changes.Type)
+		}
+		typ = changes.Type
+	}
+
+	if len(changes.Paths) > 0 {
+		if err := checkPaths(typ); err != nil {
+			return fmt.Errorf("%q: %w", path, err)
+		}
+	}
+
+	if changes.Page != uuid.Nil && typ != PageLink {
+		return fmt.Errorf("%q: %w", path, ErrNotPageLink)
+	}
+
+	if typ == PageLink && item.Page == nil && changes.Page == uuid.Nil {
+		return fmt.Errorf("%q: %w", path, ErrMissingPage)
+	}
+
+	attributes := make(map[string]string, len(changes.Attributes))
+	for key, val := range changes.Attributes {
+		if key = strings.TrimSpace(key); key == "" {
+			return fmt.Errorf("%q: %w", path, ErrEmptyAttribute)
+		}
+		attributes[key] = strings.TrimSpace(val)
+	}
+	if len(attributes) == 0 {
+		attributes = nil
+	}
+
+	if changes.Empty() {
+		return nil
+	}
+
+	aggregate.NextEvent(nav, ItemUpdated, ItemUpdatedData{
+		Item: path,
+		Changes: ItemChanges{
+			Type:         changes.Type,
+			Page:         changes.Page,
+			Labels:       trimLocales(changes.Labels),
+			Paths:        trimLocales(changes.Paths),
+			Hidden:       changes.Hidden,
+			RequiresAuth: changes.RequiresAuth,
+			Attributes:   attributes,
+		},
+	})
+
+	return nil
+}
+
+func (nav *Nav) updateItem(evt event.Event) {
+	data := evt.Data().(ItemUpdatedData)
+	item, err := nav.Item(data.Item)
+	if err != nil {
+		return
+	}
+
+	if data.Changes.Type != "" {
+		item.Type = data.Changes.Type
+		if item.Type == StaticLink {
+			item.Page = nil
+		} else {
+			item.Paths = make(map[string]string)
+		}
+	}
+
+	if page := data.Changes.Page; page != uuid.Nil {
+		item.Page = &page
+	}
+
+	for locale, label := range data.Changes.Labels {
+		item.Labels = setLocale(item.Labels, locale, label)
+	}
+
+	for locale, path := range data.Changes.Paths {
+		item.Paths = setLocale(item.Paths, locale, path)
+	}
+
+	if data.Changes.Hidden != nil {
+		item.Hidden = *data.Changes.Hidden
+	}
+
+	if data.Changes.RequiresAuth != nil {
+		item.RequiresAuth = *data.Changes.RequiresAuth
+	}
+
+	if len(data.Changes.Attributes) > 0 {
+		item.Attributes = setAttributes(item.Attributes, data.Changes.Attributes)
+	}
+
+	nav.replace(data.Item, item)
+}
+
+// setAttributes returns a copy of attributes with the given changes applied.
+// Empty values remove attributes.
+func setAttributes(attributes, changes map[string]string) map[string]string {
+	out := make(map[string]string, len(attributes)+len(changes))
+	for key, val := range attributes {
+		out[key] = val
+	}
+	for key, val := range changes {
+		if val == "" {
+			delete(out, key)
+		} else {
+			out[key] = val
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+func validItemType(typ ItemType) bool {
+	switch typ {
+	case Label, StaticLink, PageLink:
+		return true
+	default:
+		return false
+	}
+}
+
+func trimLocales(values map[string]string) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(values))
+	for locale, v := range values {
+		out[locale] = strings.TrimSpace(v)
+	}
+	return out
+}
@@ -0,0 +1,152 @@
+package nav_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/static/nav"
+)
+
+func TestNav_UpdateItem(t *testing.T) {
+	n, _ := nav.Create("foo",
+		nav.NewLabel("foo", "Foo", nav.SubTree(
+			nav.NewStaticLink("bar", "/bar", "Bar", nav.LocalePath("de", "/de/bar")),
+		)),
+	)
+
+	changes := nav.ItemChanges{
+		Labels: map[string]string{"": "Baz", "de": " Bäz "},
+		Paths:  map[string]string{"": "/baz", "de": ""},
+	}
+	if err := n.UpdateItem("foo.bar", changes); err != nil {
+		t.Fatalf("UpdateItem failed with %q", err)
+	}
+
+	test.Change(t, n, nav.ItemUpdated, test.EventData(nav.ItemUpdatedData{
+		Item: "foo.bar",
+		Changes: nav.ItemChanges{
+			Labels: map[string]string{"": "Baz", "de": "Bäz"},
+			Paths:  map[string]string{"": "/baz", "de": ""},
+		},
+	}))
+
+	item, _ := n.Item("foo.bar")
+	if !cmp.Equal(item.Labels, map[string]string{"": "Baz", "de": "Bäz"}) {
+		t.Fatalf("Labels should be updated; got %v", item.Labels)
+	}
+	if !cmp.Equal(item.Paths, map[string]string{"": "/baz"}) {
+		t.Fatalf("Paths should be updated; got %v", item.Paths)
+	}
+
+	if err := n.UpdateItem("foo.bar", nav.ItemChanges{Type: nav.Label}); err != nil {
+		t.Fatalf("UpdateItem failed with %q", err)
+	}
+
+	item, _ = n.Item("foo.bar")
+	if item.Type != nav.Label || len(item.Paths) != 0 {
+		t.Fatalf("Item should be converted to a label without paths; got %v", item)
+	}
+
+	if err := n.UpdateItem("foo.bar", nav.ItemChanges{Paths: map[string]string{"": "/bar"}}); !errors.Is(err, nav.ErrLabelItem) {
+		t.Fatalf("UpdateItem should fail with %q; got %q", nav.ErrLabelItem, err)
+	}
+
+	if err := n.UpdateItem("foo", nav.ItemChanges{
+		Type:  nav.StaticLink,
+		Paths: map[string]string{"": "/foo"},
+	}); err != nil {
+		t.Fatalf("UpdateItem failed with %q", err)
+	}
+
+	item, _ = n.Item("foo")
+	if item.Type != nav.StaticLink || item.Path("") != "/foo" || item.Tree == nil || len(item.Tree.Items) != 1 {
+		t.Fatalf("label should be converted to a static link and keep its subtree; got %v", item)
+	}
+
+	if err := n.UpdateItem("foo", nav.ItemChanges{Type: "foo"}); !errors.Is(err, nav.ErrInvalidItemType) {
+		t.Fatalf("UpdateItem should fail with %q; got %q", nav.ErrInvalidItemType, err)
+	}
+
+	if err := n.UpdateItem("baz", nav.ItemChanges{Type: nav.Label}); !errors.Is(err, nav.ErrItemNotFound) {
+		t.Fatalf("UpdateItem should fail with %q; got %q", nav.ErrItemNotFound, err)
+	}
+
+	if err := n.UpdateItem("foo", nav.ItemChanges{Type: nav.PageLink}); !errors.Is(err, nav.ErrMissingPage) {
+		t.Fatalf("UpdateItem should fail with %q; got %q", nav.ErrMissingPage, err)
+	}
+
+	if err := n.UpdateItem("foo", nav.ItemChanges{Page: uuid.New()}); !errors.Is(err, nav.ErrNotPageLink) {
+		t.Fatalf("UpdateItem should fail with %q; got %q", nav.ErrNotPageLink, err)
+	}
+
+	pageID := uuid.New()
+	if err := n.UpdateItem("foo", nav.ItemChanges{Type: nav.PageLink, Page: pageID}); err != nil {
+		t.Fatalf("UpdateItem failed with %q", err)
+	}
+
+	item, _ = n.Item("foo")
+	if item.Type != nav.PageLink || item.Page == nil || *item.Page != pageID || len(item.Paths) != 0 {
+		t.Fatalf("static link should be converted to a page link without paths; got %v", item)
+	}
+
+	if err := n.UpdateItem("foo", nav.ItemChanges{Paths: map[string]string{"": "/foo"}}); !errors.Is(err, nav.ErrPageLinkItem) {
+		t.Fatalf("UpdateItem should fail with %q; got %q", nav.ErrPageLinkItem, err)
+	}
+
+	if err := n.UpdateItem("foo", nav.ItemChanges{Type: nav.StaticLink, Paths: map[string]string{"": "/foo"}}); err != nil {
+		t.Fatalf("UpdateItem failed with %q", err)
+	}
+
+	item, _ = n.Item("foo")
+	if item.Type != nav.StaticLink || item.Page != nil || item.Path("") != "/foo" {
+		t.Fatalf("page link should be converted to a static link without a page; got %v", item)
+	}
+}
+
+func TestNav_UpdateItem_visibility(t *testing.T) {
+	n, _ := nav.Create("foo", nav.NewStaticLink("foo", "/foo", "Foo", nav.Attribute("icon", "star")))
+
+	yes := true
+	changes := nav.ItemChanges{
+		Hidden:       &yes,
+		RequiresAuth: &yes,
+		Attributes:   map[string]string{" target ": " _blank ", "icon": ""},
+	}
+	if err := n.UpdateItem("foo", changes); err != nil {
+		t.Fatalf("UpdateItem failed with %q", err)
+	}
+
+	test.Change(t, n, nav.ItemUpdated, test.EventData(nav.ItemUpdatedData{
+		Item: "foo",
+		Changes: nav.ItemChanges{
+			Hidden:       &yes,
+			RequiresAuth: &yes,
+			Attributes:   map[string]string{"target": "_blank", "icon": ""},
+		},
+	}))
+
+	item, _ := n.Item("foo")
+	if !item.Hidden || !item.RequiresAuth {
+		t.Fatalf("Item should be hidden and require authentication; got %v", item)
+	}
+	if !cmp.Equal(item.Attributes, map[string]string{"target": "_blank"}) {
+		t.Fatalf("Attributes should be updated; got %v", item.Attributes)
+	}
+
+	no := false
+	if err := n.UpdateItem("foo", nav.ItemChanges{Hidden: &no}); err != nil {
+		t.Fatalf("UpdateItem failed with %q", err)
+	}
+
+	item, _ = n.Item("foo")
+	if item.Hidden || !item.RequiresAuth {
+		t.Fatalf("only Hidden should be changed; got %v", item)
+	}
+
+	if err := n.UpdateItem("foo", nav.ItemChanges{Attributes: map[string]string{" ": "foo"}}); !errors.Is(err, nav.ErrEmptyAttribute) {
+		t.Fatalf("UpdateItem should fail with %q; got %q", nav.ErrEmptyAttribute, err)
+	}
+}
@@ -0,0 +1,30 @@
+package nav
+
+// Visible returns a copy of the Nav without the Items that should not be
+// rendered for a user (see Item.Visible). The subtrees of invisible Items are
+// removed with them:
+//
+//	public := n.Visible(false)
+//	members := n.Visible(true)
+func (nav *Nav) Visible(authenticated bool) *Nav {
+	out := New(nav.ID)
+	out.Name = nav.Name
+	if nav.Tree != nil {
+		out.Items = visibleItems(nav.Items, authenticated)
+	}
+	return out
+}
+
+func visibleItems(items []Item, authenticated bool) []Item {
+	out := make([]Item, 0, len(items))
+	for _, item := range items {
+		if !item.Visible(authenticated) {
+			continue
+		}
+		if item.Tree != nil {
+			item.Tree = NewTree(visibleItems(item.Tree.Items, authenticated)...)
+		}
+		out = append(out, item)
+	}
+	return out
+}
@@ -0,0 +1,55 @@
+package nav_test
+
+import (
+	"testing"
+
+	"github.com/modernice/nice-cms/static/nav"
+)
+
+func TestItem_Visible(t *testing.T) {
+	tests := []struct {
+		item          nav.Item
+		public, authd bool
+	}{
+		{nav.NewLabel("foo", "Foo"), true, true},
+		{nav.NewLabel("foo", "Foo", nav.Hidden()), false, false},
+		{nav.NewLabel("foo", "Foo", nav.RequiresAuth()), false, true},
+		{nav.NewLabel("foo", "Foo", nav.Hidden(), nav.RequiresAuth()), false, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.item.Visible(false); got != tt.public {
+			t.Fatalf("Visible(false) should return %v for %v; got %v", tt.public, tt.item, got)
+		}
+		if got := tt.item.Visible(true); got != tt.authd {
+			t.Fatalf("Visible(true) should return %v for %v; got %v", tt.authd, tt.item, got)
+		}
+	}
+}
+
+func TestNav_Visible(t *testing.T) {
+	n, _ := nav.Create("foo",
+		nav.NewStaticLink("home", "/", "Home"),
+		nav.NewLabel("account", "Account", nav.RequiresAuth(), nav.SubTree(
+			nav.NewStaticLink("settings", "/settings", "Settings"),
+		)),
+		nav.NewLabel("about", "About", nav.SubTree(
+			nav.NewStaticLink("team", "/team", "Team"),
+			nav.NewStaticLink("jobs", "/jobs", "Jobs", nav.Hidden()),
+		)),
+	)
+
+	public := n.Visible(false)
+	if !public.HasItem("home", "about", "about.team") || public.HasItem("account") || public.HasItem("about.jobs") {
+		t.Fatalf("public Nav should only have the public Items; got %v", public.Items)
+	}
+
+	authd := n.Visible(true)
+	if !authd.HasItem("home", "account", "account.settings", "about", "about.team") || authd.HasItem("about.jobs") {
+		t.Fatalf("authenticated Nav should have all visible Items; got %v", authd.Items)
+	}
+
+	if !n.HasItem("account.settings", "about.jobs") {
+		t.Fatalf("Visible should not change the Nav; got %v", n.Items)
+	}
+}
@@ -0,0 +1,236 @@
+package page
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/codec"
+	"github.com/modernice/goes/command"
+	"github.com/modernice/goes/helper/streams"
+	"github.com/modernice/nice-cms/static/page/field"
+	"github.com/modernice/nice-cms/static/page/field/sanitize"
+)
+
+// Page commands
+const (
+	PublishCommand           = "cms.static.page.publish"
+	UnpublishCommand         = "cms.static.page.unpublish"
+	SchedulePublishCommand   = "cms.static.page.schedule_publish"
+	ScheduleUnpublishCommand = "cms.static.page.schedule_unpublish"
+	CancelScheduleCommand    = "cms.static.page.cancel_schedule"
+	RevertToRevisionCommand  = "cms.static.page.revert_to_revision"
+	UpdateFieldCommand       = "cms.static.page.update_field"
+	RenameCommand            = "cms.static.page.rename"
+)
+
+// publishPayload is the payload of PublishCommand and UnpublishCommand.
+// Scheduled is the time of the Schedule that a command was dispatched for by
+// the Scheduler, or the zero time if the command was dispatched manually.
+type publishPayload struct {
+	Scheduled time.Time
+}
+
+type schedulePayload struct {
+	At time.Time
+}
+
+type cancelSchedulePayload struct{}
+
+type revertToRevisionPayload struct {
+	Revision int
+}
+
+type updateFieldPayload struct {
+	Field   string
+	Value   string
+	Locales []string
+}
+
+type renamePayload struct {
+	Name string
+}
+
+// PublishCmd returns the command to publish a Page.
+func PublishCmd(id uuid.UUID) command.Cmd[publishPayload] {
+	return command.New(PublishCommand, publishPayload{}, command.Aggregate(Aggregate, id))
+}
+
+// UnpublishCmd returns the command to unpublish a Page.
+func UnpublishCmd(id uuid.UUID) command.Cmd[publishPayload] {
+	return command.New(UnpublishCommand, publishPayload{}, command.Aggregate(Aggregate, id))
+}
+
+func scheduledPublishCmd(id uuid.UUID, at time.Time) command.Cmd[publishPayload] {
+	return command.New(PublishCommand, publishPayload{Scheduled: at}, command.Aggregate(Aggregate, id))
+}
+
+func scheduledUnpublishCmd(id uuid.UUID, at time.Time) command.Cmd[publishPayload] {
+	return command.New(UnpublishCommand, publishPayload{Scheduled: at}, command.Aggregate(Aggregate, id))
+}
+
+// SchedulePublishCmd returns the command to schedule the publishing of a Page.
+func SchedulePublishCmd(id uuid.UUID, at time.Time) command.Cmd[schedulePayload] {
+	return command.New(SchedulePublishCommand, schedulePayload{At: at}, command.Aggregate(Aggregate, id))
+}
+
+// ScheduleUnpublishCmd returns the command to schedule the unpublishing of a
+// Page.
+func ScheduleUnpublishCmd(id uuid.UUID, at time.Time) command.Cmd[schedulePayload] {
+	return command.New(ScheduleUnpublishCommand, schedulePayload{At: at}, command.Aggregate(Aggregate, id))
+}
+
+// CancelScheduleCmd returns the command to cancel the Schedule of a Page.
+func CancelScheduleCmd(id uuid.UUID) command.Cmd[cancelSchedulePayload] {
+	return command.New(CancelScheduleCommand, cancelSchedulePayload{}, command.Aggregate(Aggregate, id))
+}
+
+// RevertToRevisionCmd returns the command to revert the draft of a Page to the
+// Revision with the given number.
+func RevertToRevisionCmd(id uuid.UUID, revision int) command.Cmd[revertToRevisionPayload] {
+	return command.New(RevertToRevisionCommand, revertToRevisionPayload{Revision: revision}, command.Aggregate(Aggregate, id))
+}
+
+// UpdateFieldCmd returns the command to update the value of a Field of a Page
+// for the given locales, or for all locales if none are provided. The value is
+// formatted using field.Format.
+func UpdateFieldCmd(id uuid.UUID, name string, value any, locales ...string) command.Cmd[updateFieldPayload] {
+	return command.New(UpdateFieldCommand, updateFieldPayload{
+		Field:   name,
+		Value:   field.Format(value),
+		Locales: locales,
+	}, command.Aggregate(Aggregate, id))
+}
+
+// RenameCmd returns the command to rename a Page.
+func RenameCmd(id uuid.UUID, name string) command.Cmd[renamePayload] {
+	return command.New(RenameCommand, renamePayload{Name: name}, command.Aggregate(Aggregate, id))
+}
+
+// RegisterCommands register commands into a registry.
+func RegisterCommands(r codec.Registerer) {
+	codec.Register[publishPayload](r, PublishCommand)
+	codec.Register[publishPayload](r, UnpublishCommand)
+	codec.Register[schedulePayload](r, SchedulePublishCommand)
+	codec.Register[schedulePayload](r, ScheduleUnpublishCommand)
+	codec.Register[cancelSchedulePayload](r, CancelScheduleCommand)
+	codec.Register[revertToRevisionPayload](r, RevertToRevisionCommand)
+	codec.Register[updateFieldPayload](r, UpdateFieldCommand)
+	codec.Register[renamePayload](r, RenameCommand)
+}
+
+// CommandOption is an option for HandleCommands.
+type CommandOption func(*commandConfig)
+
+type commandConfig struct {
+	sanitizer *sanitize.Sanitizer
+}
+
+// Sanitize returns a CommandOption that replaces the Sanitizer of the values
+// of updated Fields. Default is sanitize.New().
+//
+//	errs := page.HandleCommands(ctx, bus, pages, page.Sanitize(sanitize.New(
+//		sanitize.WithPolicy(field.HTML, sanitize.HTMLPolicy(sanitize.ImageSources("/media/"))),
+//	)))
+func Sanitize(s *sanitize.Sanitizer) CommandOption {
+	return func(cfg *commandConfig) {
+		cfg.sanitizer = s
+	}
+}
+
+// HandleCommands handles page commands until ctx is canceled. The returned
+// error channel is also closed when ctx is canceled.
+//
+// The values of updated Fields are sanitized (see Sanitize), and Pages whose
+// Fields contain unsafe values, e.g. values that were stored before they were
+// sanitized, are not published; publishing them fails with an error that
+// wraps sanitize.ErrUnsafe.
+//
+// Publish and unpublish commands that were dispatched by the Scheduler are
+// ignored if the Schedule of the Page has changed in the meantime.
+func HandleCommands(ctx context.Context, bus command.Bus, repo Repository, opts ...CommandOption) <-chan error {
+	cfg := commandConfig{sanitizer: sanitize.New()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	publishErrors := command.MustHandle(ctx, bus, PublishCommand, func(ctx command.Ctx[publishPayload]) error {
+		load := ctx.Payload()
+		return repo.Use(ctx, ctx.AggregateID(), func(p *Page) error {
+			if !load.Scheduled.IsZero() && !p.Schedule.PublishAt.Equal(load.Scheduled) {
+				return nil
+			}
+			if err := cfg.sanitizer.Verify(p.Fields...); err != nil {
+				return err
+			}
+			return p.Publish()
+		})
+	})
+
+	unpublishErrors := command.MustHandle(ctx, bus, UnpublishCommand, func(ctx command.Ctx[publishPayload]) error {
+		load := ctx.Payload()
+		return repo.Use(ctx, ctx.AggregateID(), func(p *Page) error {
+			if !load.Scheduled.IsZero() && !p.Schedule.UnpublishAt.Equal(load.Scheduled) {
+				return nil
+			}
+			return p.Unpublish()
+		})
+	})
+
+	schedulePublishErrors := command.MustHandle(ctx, bus, SchedulePublishCommand, func(ctx command.Ctx[schedulePayload]) error {
+		load := ctx.Payload()
+		return repo.Use(ctx, ctx.AggregateID(), func(p *Page) error {
+			return p.SchedulePublish(load.At)
+		})
+	})
+
+	scheduleUnpublishErrors := command.MustHandle(ctx, bus, ScheduleUnpublishCommand, func(ctx command.Ctx[schedulePayload]) error {
+		load := ctx.Payload()
+		return repo.Use(ctx, ctx.AggregateID(), func(p *Page) error {
+			return p.ScheduleUnpublish(load.At)
+		})
+	})
+
+	cancelScheduleErrors := command.MustHandle(ctx, bus, CancelScheduleCommand, func(ctx command.Ctx[cancelSchedulePayload]) error {
+		return repo.Use(ctx, ctx.AggregateID(), func(p *Page) error {
+			return p.CancelSchedule()
+		})
+	})
+
+	revertErrors := command.MustHandle(ctx, bus, RevertToRevisionCommand, func(ctx command.Ctx[revertToRevisionPayload]) error {
+		load := ctx.Payload()
+		return repo.Use(ctx, ctx.AggregateID(), func(p *Page) error {
+			return p.RevertToRevision(load.Revision)
+		})
+	})
+
+	updateFieldErrors := command.MustHandle(ctx, bus, UpdateFieldCommand, func(ctx command.Ctx[updateFieldPayload]) error {
+		load := ctx.Payload()
+		return repo.Use(ctx, ctx.AggregateID(), func(p *Page) error {
+			f, err := p.Field(load.Field)
+			if err != nil {
+				return err
+			}
+			return p.UpdateField(load.Field, cfg.sanitizer.String(f.Type, load.Value), load.Locales...)
+		})
+	})
+
+	renameErrors := command.MustHandle(ctx, bus, RenameCommand, func(ctx command.Ctx[renamePayload]) error {
+		load := ctx.Payload()
+		return repo.Use(ctx, ctx.AggregateID(), func(p *Page) error {
+			return p.Rename(load.Name)
+		})
+	})
+
+	return streams.FanInContext(
+		ctx,
+		publishErrors,
+		unpublishErrors,
+		schedulePublishErrors,
+		scheduleUnpublishErrors,
+		cancelScheduleErrors,
+		revertErrors,
+		updateFieldErrors,
+		renameErrors,
+	)
+}
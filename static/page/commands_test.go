@@ -0,0 +1,61 @@
+package page_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/command/cmdbus"
+	"github.com/modernice/goes/command/cmdbus/dispatch"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/commands"
+	"github.com/modernice/nice-cms/internal/discard"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+	"github.com/modernice/nice-cms/static/page/field/sanitize"
+)
+
+func TestHandleCommands_sanitize(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	cbus := cmdbus.New(commands.NewRegistry(), ebus)
+	pages := page.GoesRepository(repository.New(eventstore.WithBus(eventstore.New(), ebus)))
+
+	go discard.Errors(page.HandleCommands(ctx, cbus, pages))
+
+	p := page.New(uuid.New())
+	p.Create("foo", field.NewHTML("body", ""), field.NewMarkdown("text", ""))
+	if err := pages.Save(ctx, p); err != nil {
+		t.Fatalf("save Page: %v", err)
+	}
+
+	cmd := page.UpdateFieldCmd(p.ID, "body", `<p onclick="alert(1)">foo</p><script>alert(1)</script>`)
+	if err := cbus.Dispatch(ctx, cmd.Any(), dispatch.Sync()); err != nil {
+		t.Fatalf("dispatch command: %v", err)
+	}
+
+	fetched, err := pages.Fetch(ctx, p.ID)
+	if err != nil {
+		t.Fatalf("fetch Page: %v", err)
+	}
+	if body, _ := fetched.Field("body"); body.Value("") != "<p>foo</p>" {
+		t.Fatalf("updated value should be sanitized; got %q", body.Value(""))
+	}
+
+	// The value was stored without sanitization, so the Page cannot be
+	// published.
+	if err := pages.Use(ctx, p.ID, func(p *page.Page) error {
+		return p.UpdateField("text", "[click][x]\n\n[x]: javascript:alert(1)")
+	}); err != nil {
+		t.Fatalf("update field: %v", err)
+	}
+
+	if err := cbus.Dispatch(ctx, page.PublishCmd(p.ID).Any(), dispatch.Sync()); err == nil || !strings.Contains(err.Error(), sanitize.ErrUnsafe.Error()) {
+		t.Fatalf("publishing unsafe values should fail with %q; got %v", sanitize.ErrUnsafe, err)
+	}
+}
@@ -0,0 +1,28 @@
+package page
+
+import (
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+// Destroy marks the Page as deleted. A destroyed Page is no longer created and
+// has no Fields, so it is not served anymore. Projections that reference the
+// Page are notified by the Deleted event. Use the Repository to also delete the
+// Page from the event store.
+func (p *Page) Destroy() error {
+	if err := p.checkCreated(); err != nil {
+		return err
+	}
+
+	aggregate.NextEvent(p, Deleted, DeletedData{Name: p.Name})
+
+	return nil
+}
+
+func (p *Page) destroy(event.Event) {
+	*p = Page{
+		Base:   p.Base,
+		Fields: make([]field.Field, 0),
+	}
+}
@@ -0,0 +1,36 @@
+package page_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+func TestPage_Destroy(t *testing.T) {
+	p := page.New(uuid.New())
+
+	if err := p.Destroy(); !errors.Is(err, page.ErrNotCreated) {
+		t.Fatalf("Destroy should fail with %q; got %q", page.ErrNotCreated, err)
+	}
+
+	p.Create("foo", field.NewText("title", "Foo"))
+	p.Publish()
+
+	if err := p.Destroy(); err != nil {
+		t.Fatalf("Destroy failed with %q", err)
+	}
+
+	test.Change(t, p, page.Deleted, test.EventData(page.DeletedData{Name: "foo"}))
+
+	if p.Name != "" || len(p.Fields) != 0 || p.Published {
+		t.Fatalf("destroyed Page should be reset; got %v", p)
+	}
+
+	if _, err := p.Live(); !errors.Is(err, page.ErrNotPublished) {
+		t.Fatalf("destroyed Page should not be live; got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package page
+
+import (
+	"time"
+
+	"github.com/modernice/goes/codec"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+const (
+	// Created means a Page was created.
+	Created = "cms.static.page.created"
+
+	// FieldsAdded means Fields were added to a Page.
+	FieldsAdded = "cms.static.page.fields_added"
+
+	// FieldsRemoved means Fields were removed from a Page.
+	FieldsRemoved = "cms.static.page.fields_removed"
+
+	// FieldUpdated means the value of a Field was updated.
+	FieldUpdated = "cms.static.page.field_updated"
+
+	// Published means a Page was published.
+	Published = "cms.static.page.published"
+
+	// Unpublished means a Page was unpublished.
+	Unpublished = "cms.static.page.unpublished"
+
+	// PublishScheduled means the publishing of a Page was scheduled.
+	PublishScheduled = "cms.static.page.publish_scheduled"
+
+	// UnpublishScheduled means the unpublishing of a Page was scheduled.
+	UnpublishScheduled = "cms.static.page.unpublish_scheduled"
+
+	// ScheduleCanceled means the Schedule of a Page was canceled.
+	ScheduleCanceled = "cms.static.page.schedule_canceled"
+
+	// RevertedToRevision means the draft of a Page was reverted to a Revision.
+	RevertedToRevision = "cms.static.page.reverted_to_revision"
+
+	// Deleted means a Page was deleted.
+	Deleted = "cms.static.page.deleted"
+
+	// Renamed means a Page was renamed.
+	Renamed = "cms.static.page.renamed"
+)
+
+// Events are all page events.
+var Events = [...]string{
+	Created,
+	FieldsAdded,
+	FieldsRemoved,
+	FieldUpdated,
+	Published,
+	Unpublished,
+	PublishScheduled,
+	UnpublishScheduled,
+	ScheduleCanceled,
+	RevertedToRevision,
+	Deleted,
+	Renamed,
+}
+
+// LiveEvents are the page events that change the published content of a Page.
+// Events that only change the draft or the Schedule of a Page are not live
+// events, e.g. to not rebuild static sites for unpublished changes.
+var LiveEvents = [...]string{
+	Published,
+	Unpublished,
+	Deleted,
+	Renamed,
+}
+
+// CreatedData is the event data for Created.
+type CreatedData struct {
+	Name string
+}
+
+// FieldsAddedData is the event data for FieldsAdded.
+type FieldsAddedData struct {
+	Fields []field.Field
+}
+
+// FieldsRemovedData is the event data for FieldsRemoved.
+type FieldsRemovedData struct {
+	Fields []string
+}
+
+// FieldUpdatedData is the event data for FieldUpdated. If Locales is empty,
+// the value was updated for all locales.
+type FieldUpdatedData struct {
+	Field   string
+	Value   string
+	Locales []string
+}
+
+// PublishedData is the event data for Published.
+type PublishedData struct{}
+
+// UnpublishedData is the event data for Unpublished.
+type UnpublishedData struct{}
+
+// PublishScheduledData is the event data for PublishScheduled.
+type PublishScheduledData struct {
+	At time.Time
+}
+
+// UnpublishScheduledData is the event data for UnpublishScheduled.
+type UnpublishScheduledData struct {
+	At time.Time
+}
+
+// ScheduleCanceledData is the event data for ScheduleCanceled.
+type ScheduleCanceledData struct{}
+
+// RevertedToRevisionData is the event data for RevertedToRevision.
+type RevertedToRevisionData struct {
+	Revision int
+}
+
+// DeletedData is the event data for Deleted.
+type DeletedData struct {
+	Name string
+}
+
+// RenamedData is the event data for Renamed.
+type RenamedData struct {
+	OldName string
+	Name    string
+}
+
+// RegisterEvents registers events into an event registry.
+func RegisterEvents(r codec.Registerer) {
+	codec.Register[CreatedData](r, Created)
+	codec.Register[FieldsAddedData](r, FieldsAdded)
+	codec.Register[FieldsRemovedData](r, FieldsRemoved)
+	codec.Register[FieldUpdatedData](r, FieldUpdated)
+	codec.Register[PublishedData](r, Published)
+	codec.Register[UnpublishedData](r, Unpublished)
+	codec.Register[PublishScheduledData](r, PublishScheduled)
+	codec.Register[UnpublishScheduledData](r, UnpublishScheduled)
+	codec.Register[ScheduleCanceledData](r, ScheduleCanceled)
+	codec.Register[RevertedToRevisionData](r, RevertedToRevision)
+	codec.Register[DeletedData](r, Deleted)
+	codec.Register[RenamedData](r, Renamed)
+}
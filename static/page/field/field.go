@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 
+	loc "github.com/modernice/nice-cms/static/locale"
 	"github.com/modernice/nice-cms/static/page/metadata"
 	"github.com/radical-app/money"
 	"github.com/radical-app/money/moneyfmt"
@@ -11,12 +12,17 @@ import (
 
 // Built-in field types
 const (
-	Text   = Type("text")
-	Toggle = Type("toggle")
-	Int    = Type("integer")
-	Float  = Type("float")
-	Money  = Type("money")
-	Meta   = Type("meta")
+	Text     = Type("text")
+	HTML     = Type("html")
+	Markdown = Type("markdown")
+	Toggle   = Type("toggle")
+	Int      = Type("integer")
+	Float    = Type("float")
+	Money    = Type("money")
+	Meta     = Type("meta")
+	List     = Type("list")
+	Image    = Type("image")
+	Document = Type("document")
 )
 
 // Type is a field type.
@@ -28,6 +34,9 @@ type Field struct {
 	Type    Type
 	Values  map[string]string
 	Guarded bool
+
+	// Schema is the schema of the items of a List field.
+	Schema *Schema `json:",omitempty"`
 }
 
 // Option is a Field option.
@@ -65,12 +74,45 @@ func New(name string, typ Type, defaultValue string, opts ...Option) Field {
 	return f
 }
 
-// Value returns the value for the given locale as a string.
-func (f Field) Value(locale string) string {
-	if val, ok := f.Values[locale]; ok {
-		return val
+// Value returns the value for the given locale as a string. If the Field has
+// no value for the locale, the parent locales, the fallback locales and the
+// default value are tried in order (see locale.Resolve).
+func (f Field) Value(locale string, fallbacks ...string) string {
+	return loc.Resolve(f.Values, locale, fallbacks...)
+}
+
+// Format returns the string representation of a field value, as it is stored
+// in the Values of a Field. Money values are formatted like the default value
+// of a Money field, Items are formatted as JSON.
+func Format(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case bool:
+		return boolToString(v)
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return floatToString(v)
+	case money.Money:
+		return moneyfmt.MustDisplay(v, "en")
+	case metadata.Data:
+		str, err := v.JSON()
+		if err != nil {
+			panic(err)
+		}
+		return str
+	case Items:
+		str, err := v.JSON()
+		if err != nil {
+			panic(err)
+		}
+		return str
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
 	}
-	return f.Values[""]
 }
 
 // NewText returns a Text field.
@@ -78,6 +120,18 @@ func NewText(name, defaultValue string, opts ...Option) Field {
 	return New(name, Text, defaultValue, opts...)
 }
 
+// NewHTML returns an HTML field. Values of HTML fields are supplied by editors
+// and should be sanitized before they are stored (see package sanitize).
+func NewHTML(name, defaultValue string, opts ...Option) Field {
+	return New(name, HTML, defaultValue, opts...)
+}
+
+// NewMarkdown returns a Markdown field. Markdown may contain raw HTML, so values
+// of Markdown fields should be sanitized like HTML fields.
+func NewMarkdown(name, defaultValue string, opts ...Option) Field {
+	return New(name, Markdown, defaultValue, opts...)
+}
+
 // NewToggle returns a Toggle field.
 func NewToggle(name string, defaultValue bool, opts ...Option) Field {
 	return New(name, Toggle, boolToString(defaultValue), opts...)
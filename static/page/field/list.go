@@ -0,0 +1,224 @@
+package field
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidValue is returned when validating a value that doesn't match the
+// Schema of a List field.
+var ErrInvalidValue = errors.New("invalid field value")
+
+// Item is an item of a List field. Item values are strings for Text, HTML and
+// Markdown props, bools for Toggle props, numbers for Int and Float props,
+// ImageRefs and DocumentRefs for Image and Document props and Items for List
+// props.
+type Item map[string]any
+
+// Items are the items of a List field.
+type Items []Item
+
+// Schema is the schema of the items of a List field, e.g. the cards of a
+// repeating card section.
+type Schema struct {
+	// Props are the properties of the items.
+	Props []Prop
+
+	// MinItems is the minimum number of items. Zero means no minimum.
+	MinItems int `json:",omitempty"`
+
+	// MaxItems is the maximum number of items. Zero means no maximum.
+	MaxItems int `json:",omitempty"`
+}
+
+// Prop is a property of the items of a List field.
+type Prop struct {
+	Name string
+
+	// Type is the Type of the property. Supported are Text, HTML, Markdown,
+	// Toggle, Int, Float, Image, Document and List.
+	Type Type
+
+	// Required properties must be present in every item.
+	Required bool `json:",omitempty"`
+
+	// Items is the Schema of the items of a List property.
+	Items *Schema `json:",omitempty"`
+}
+
+// NewList returns a List field whose items must match the given Schema. NewList
+// panics if the default value doesn't match the Schema.
+func NewList(name string, schema Schema, defaultValue Items, opts ...Option) Field {
+	str, err := defaultValue.JSON()
+	if err != nil {
+		panic(err)
+	}
+	if err := schema.Validate(str); err != nil {
+		panic(fmt.Errorf("default value of field %q: %w", name, err))
+	}
+	f := New(name, List, str, opts...)
+	f.Schema = &schema
+	return f
+}
+
+// Items decodes the items of a List field for the given locale. Locales are
+// resolved like in f.Value.
+func (f Field) Items(locale string, fallbacks ...string) (Items, error) {
+	return ParseItems(f.Value(locale, fallbacks...))
+}
+
+// Validate validates a value of the Field. Values of List fields are validated
+// against their Schema and values of Image and Document fields must be valid
+// references. Values of other Fields are always valid.
+func (f Field) Validate(value string) error {
+	switch f.Type {
+	case List:
+		if f.Schema == nil {
+			return nil
+		}
+		return f.Schema.Validate(value)
+	case Image:
+		_, err := ParseImageRef(value)
+		return err
+	case Document:
+		_, err := ParseDocumentRef(value)
+		return err
+	default:
+		return nil
+	}
+}
+
+// JSON returns the JSON representation of the items. Nil Items are encoded as
+// an empty array.
+func (items Items) JSON() (string, error) {
+	if items == nil {
+		items = Items{}
+	}
+	b, err := json.Marshal(items)
+	if err != nil {
+		return "", fmt.Errorf("marshal items: %w", err)
+	}
+	return string(b), nil
+}
+
+// ParseItems decodes the JSON representation of Items. An empty string is
+// decoded as an empty list.
+func ParseItems(value string) (Items, error) {
+	items := Items{}
+	if value == "" {
+		return items, nil
+	}
+	if err := json.Unmarshal([]byte(value), &items); err != nil {
+		return nil, fmt.Errorf("unmarshal items: %w", err)
+	}
+	return items, nil
+}
+
+// Validate validates the JSON representation of a list against the Schema.
+// The returned error wraps ErrInvalidValue and describes the position of the
+// first invalid value, e.g. "[1].title: expected a string".
+func (s Schema) Validate(value string) error {
+	return s.validate("", json.RawMessage(value))
+}
+
+func (s Schema) validate(path string, raw json.RawMessage) error {
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return invalid(path, "expected a list of objects")
+	}
+
+	if s.MinItems > 0 && len(items) < s.MinItems {
+		return invalid(path, fmt.Sprintf("expected at least %d items; got %d", s.MinItems, len(items)))
+	}
+
+	if s.MaxItems > 0 && len(items) > s.MaxItems {
+		return invalid(path, fmt.Sprintf("expected at most %d items; got %d", s.MaxItems, len(items)))
+	}
+
+	props := make(map[string]Prop, len(s.Props))
+	for _, p := range s.Props {
+		props[p.Name] = p
+	}
+
+	for i, item := range items {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+
+		for name := range item {
+			if _, ok := props[name]; !ok {
+				return invalid(itemPath+"."+name, "unknown property")
+			}
+		}
+
+		for _, p := range s.Props {
+			val, ok := item[p.Name]
+			if !ok || isNull(val) {
+				if p.Required {
+					return invalid(itemPath+"."+p.Name, "missing required property")
+				}
+				continue
+			}
+			if err := p.validate(itemPath+"."+p.Name, val); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p Prop) validate(path string, raw json.RawMessage) error {
+	switch p.Type {
+	case Text, HTML, Markdown:
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return invalid(path, "expected a string")
+		}
+	case Toggle:
+		var v bool
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return invalid(path, "expected a boolean")
+		}
+	case Int:
+		var v float64
+		if err := json.Unmarshal(raw, &v); err != nil || v != math.Trunc(v) {
+			return invalid(path, "expected an integer")
+		}
+	case Float:
+		var v float64
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return invalid(path, "expected a number")
+		}
+	case Image:
+		var v ImageRef
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return invalid(path, "expected an image reference")
+		}
+	case Document:
+		var v DocumentRef
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return invalid(path, "expected a document reference")
+		}
+	case List:
+		if p.Items == nil {
+			return invalid(path, "list property without schema")
+		}
+		return p.Items.validate(path, raw)
+	default:
+		return invalid(path, fmt.Sprintf("unsupported property type %q", p.Type))
+	}
+	return nil
+}
+
+func invalid(path, msg string) error {
+	if path == "" {
+		return fmt.Errorf("%w: %s", ErrInvalidValue, msg)
+	}
+	return fmt.Errorf("%w: %s: %s", ErrInvalidValue, path, msg)
+}
+
+func isNull(raw json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
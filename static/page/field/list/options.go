@@ -0,0 +1,12 @@
+package list
+
+import "github.com/modernice/nice-cms/static/page/field"
+
+// Localize returns an Option that localizes a List field.
+func Localize(items field.Items, locales ...string) field.Option {
+	str, err := items.JSON()
+	if err != nil {
+		panic(err)
+	}
+	return field.Localize(str, locales...)
+}
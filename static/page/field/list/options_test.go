@@ -0,0 +1,34 @@
+package list_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/modernice/nice-cms/static/page/field"
+	"github.com/modernice/nice-cms/static/page/field/list"
+)
+
+func TestLocalize(t *testing.T) {
+	schema := field.Schema{Props: []field.Prop{{Name: "title", Type: field.Text}}}
+	def := field.Items{{"title": "Foo"}}
+	other := field.Items{{"title": "Bar"}, {"title": "Baz"}}
+
+	f := field.NewList("cards", schema, def, list.Localize(other, "de", "ch"))
+
+	tests := map[string]field.Items{
+		"":   def,
+		"en": def,
+		"de": other,
+		"ch": other,
+	}
+
+	for locale, want := range tests {
+		got, err := f.Items(locale)
+		if err != nil {
+			t.Fatalf("Items(%q) failed with %q", locale, err)
+		}
+		if !cmp.Equal(want, got) {
+			t.Fatalf("Items(%q) should return %v; got %v", locale, want, got)
+		}
+	}
+}
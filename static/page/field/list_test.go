@@ -0,0 +1,117 @@
+package field_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+var cardSchema = field.Schema{
+	Props: []field.Prop{
+		{Name: "title", Type: field.Text, Required: true},
+		{Name: "body", Type: field.Markdown},
+		{Name: "highlighted", Type: field.Toggle},
+		{Name: "order", Type: field.Int},
+		{Name: "image", Type: field.Image},
+		{Name: "links", Type: field.List, Items: &field.Schema{
+			Props:    []field.Prop{{Name: "url", Type: field.Text, Required: true}},
+			MaxItems: 2,
+		}},
+	},
+	MaxItems: 3,
+}
+
+func TestNewList(t *testing.T) {
+	def := field.Items{{"title": "Foo", "order": 1, "links": field.Items{{"url": "/foo"}}}}
+	f := field.NewList("cards", cardSchema, def)
+
+	if f.Type != field.List {
+		t.Fatalf("Type should be %q; is %q", field.List, f.Type)
+	}
+
+	if !cmp.Equal(&cardSchema, f.Schema) {
+		t.Fatalf("Schema should be %v; is %v", cardSchema, f.Schema)
+	}
+
+	items, err := f.Items("")
+	if err != nil {
+		t.Fatalf("Items failed with %q", err)
+	}
+
+	// Numbers and nested lists are decoded as float64 and []any.
+	want := field.Items{{"title": "Foo", "order": float64(1), "links": []any{map[string]any{"url": "/foo"}}}}
+	if !cmp.Equal(want, items) {
+		t.Fatalf("invalid Items.\n\n%s", cmp.Diff(want, items))
+	}
+}
+
+func TestNewList_invalidDefault(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("NewList should panic for an invalid default value")
+		}
+	}()
+	field.NewList("cards", cardSchema, field.Items{{"body": "missing title"}})
+}
+
+func TestSchema_Validate(t *testing.T) {
+	tests := map[string]string{
+		`[]`: "",
+		`[{"title":"Foo","body":"*Bar*","highlighted":true,"order":2,"links":[{"url":"/"}]}]`: "",
+		`[{"title":"Foo","body":null}]`:         "",
+		`{"title":"Foo"}`:                       "expected a list of objects",
+		`[{"body":"Bar"}]`:                      "[0].title: missing required property",
+		`[{"title":"Foo","foo":"bar"}]`:         "[0].foo: unknown property",
+		`[{"title":1}]`:                         "[0].title: expected a string",
+		`[{"title":"Foo","highlighted":"yes"}]`: "[0].highlighted: expected a boolean",
+		`[{"title":"Foo","order":1.5}]`:         "[0].order: expected an integer",
+		`[{"title":"Foo","image":{"gallery":"` + uuid.Nil.String() + `","stack":"` + uuid.Nil.String() + `"}}]`: "",
+		`[{"title":"Foo","image":"foo"}]`:                                 "[0].image: expected an image reference",
+		`[{"title":"Foo"},{"title":"Bar","links":[{"url":1}]}]`:           "[1].links[0].url: expected a string",
+		`[{"title":"Foo","links":[{"url":"/"},{"url":"/"},{"url":"/"}]}]`: "[0].links: expected at most 2 items; got 3",
+		`[{"title":"1"},{"title":"2"},{"title":"3"},{"title":"4"}]`:       "expected at most 3 items; got 4",
+	}
+
+	for value, want := range tests {
+		err := cardSchema.Validate(value)
+		if want == "" {
+			if err != nil {
+				t.Errorf("Validate(%s) failed with %q", value, err)
+			}
+			continue
+		}
+		if !errors.Is(err, field.ErrInvalidValue) {
+			t.Errorf("Validate(%s) should fail with %q; got %q", value, field.ErrInvalidValue, err)
+			continue
+		}
+		if msg := field.ErrInvalidValue.Error() + ": " + want; err.Error() != msg {
+			t.Errorf("Validate(%s) should fail with %q; got %q", value, msg, err)
+		}
+	}
+}
+
+func TestField_MarshalJSON_list(t *testing.T) {
+	f := field.NewList("cards", cardSchema, nil)
+
+	b, err := json.Marshal(f)
+	if err != nil {
+		t.Fatalf("json.Marshal failed with %q", err)
+	}
+
+	var unmarshaled field.Field
+	if err := json.Unmarshal(b, &unmarshaled); err != nil {
+		t.Fatalf("json.Unmarshal failed with %q", err)
+	}
+
+	if !cmp.Equal(f, unmarshaled) {
+		t.Fatalf("invalid unmarshal.\n\n%s", cmp.Diff(f, unmarshaled))
+	}
+
+	if err := unmarshaled.Validate(`[{}]`); !errors.Is(err, field.ErrInvalidValue) {
+		t.Fatalf("unmarshaled Field should validate against its Schema; got %q", err)
+	}
+}
@@ -0,0 +1,101 @@
+package field
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ImageRef references a Stack of a Gallery.
+type ImageRef struct {
+	Gallery uuid.UUID `json:"gallery"`
+	Stack   uuid.UUID `json:"stack"`
+}
+
+// DocumentRef references a Document of a Shelf.
+type DocumentRef struct {
+	Shelf    uuid.UUID `json:"shelf"`
+	Document uuid.UUID `json:"document"`
+}
+
+// IsZero returns whether the ImageRef references nothing.
+func (ref ImageRef) IsZero() bool {
+	return ref == ImageRef{}
+}
+
+// IsZero returns whether the DocumentRef references nothing.
+func (ref DocumentRef) IsZero() bool {
+	return ref == DocumentRef{}
+}
+
+// String returns the JSON representation of the ImageRef, or an empty string
+// if the ImageRef references nothing.
+func (ref ImageRef) String() string {
+	return refString(ref, ref.IsZero())
+}
+
+// String returns the JSON representation of the DocumentRef, or an empty
+// string if the DocumentRef references nothing.
+func (ref DocumentRef) String() string {
+	return refString(ref, ref.IsZero())
+}
+
+func refString(ref any, zero bool) string {
+	if zero {
+		return ""
+	}
+	b, err := json.Marshal(ref)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+// ParseImageRef decodes the value of an Image field. An empty value is decoded
+// as the zero ImageRef.
+func ParseImageRef(value string) (ImageRef, error) {
+	var ref ImageRef
+	err := parseRef(value, &ref)
+	return ref, err
+}
+
+// ParseDocumentRef decodes the value of a Document field. An empty value is
+// decoded as the zero DocumentRef.
+func ParseDocumentRef(value string) (DocumentRef, error) {
+	var ref DocumentRef
+	err := parseRef(value, &ref)
+	return ref, err
+}
+
+func parseRef(value string, ref any) error {
+	if value == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(value), ref); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidValue, err)
+	}
+	return nil
+}
+
+// NewImage returns an Image field that references a Stack of a Gallery.
+func NewImage(name string, ref ImageRef, opts ...Option) Field {
+	return New(name, Image, ref.String(), opts...)
+}
+
+// NewDocument returns a Document field that references a Document of a Shelf.
+func NewDocument(name string, ref DocumentRef, opts ...Option) Field {
+	return New(name, Document, ref.String(), opts...)
+}
+
+// ImageRef decodes the value of an Image field for the given locale. Locales
+// are resolved like in f.Value.
+func (f Field) ImageRef(locale string, fallbacks ...string) (ImageRef, error) {
+	return ParseImageRef(f.Value(locale, fallbacks...))
+}
+
+// DocumentRef decodes the value of a Document field for the given locale.
+// Locales are resolved like in f.Value.
+func (f Field) DocumentRef(locale string, fallbacks ...string) (DocumentRef, error) {
+	return ParseDocumentRef(f.Value(locale, fallbacks...))
+}
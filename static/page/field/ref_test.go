@@ -0,0 +1,74 @@
+package field_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+func TestNewImage(t *testing.T) {
+	ref := field.ImageRef{Gallery: uuid.New(), Stack: uuid.New()}
+	f := field.NewImage("hero", ref, field.Localize("", "de"))
+
+	if f.Type != field.Image {
+		t.Fatalf("Type should be %q; is %q", field.Image, f.Type)
+	}
+
+	got, err := f.ImageRef("en")
+	if err != nil {
+		t.Fatalf("ImageRef failed with %q", err)
+	}
+	if got != ref {
+		t.Fatalf("ImageRef should return %v; got %v", ref, got)
+	}
+
+	if v := f.Value("de"); v != "" {
+		t.Fatalf("zero references should be stored as empty values; got %q", v)
+	}
+
+	got, err = f.ImageRef("de")
+	if err != nil {
+		t.Fatalf("ImageRef failed with %q", err)
+	}
+	if !got.IsZero() {
+		t.Fatalf("ImageRef should return the zero ImageRef; got %v", got)
+	}
+}
+
+func TestNewDocument(t *testing.T) {
+	ref := field.DocumentRef{Shelf: uuid.New(), Document: uuid.New()}
+	f := field.NewDocument("brochure", ref)
+
+	if f.Type != field.Document {
+		t.Fatalf("Type should be %q; is %q", field.Document, f.Type)
+	}
+
+	got, err := f.DocumentRef("")
+	if err != nil {
+		t.Fatalf("DocumentRef failed with %q", err)
+	}
+	if got != ref {
+		t.Fatalf("DocumentRef should return %v; got %v", ref, got)
+	}
+}
+
+func TestField_Validate_ref(t *testing.T) {
+	image := field.NewImage("hero", field.ImageRef{})
+	doc := field.NewDocument("brochure", field.DocumentRef{})
+
+	for _, f := range []field.Field{image, doc} {
+		if err := f.Validate(""); err != nil {
+			t.Fatalf("empty %s references should be valid; got %v", f.Type, err)
+		}
+		if err := f.Validate("foo"); !errors.Is(err, field.ErrInvalidValue) {
+			t.Fatalf("Validate should fail with %q; got %v", field.ErrInvalidValue, err)
+		}
+	}
+
+	ref := field.ImageRef{Gallery: uuid.New(), Stack: uuid.New()}
+	if err := image.Validate(field.Format(ref)); err != nil {
+		t.Fatalf("Validate failed with %q", err)
+	}
+}
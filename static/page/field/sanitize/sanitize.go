@@ -0,0 +1,461 @@
+// Package sanitize sanitizes the values of HTML and Markdown fields to prevent
+// stored XSS in editor-supplied content. Values are sanitized using allow-list
+// Policies that are configured per field type. Sanitize values when they are
+// written and Verify them before they are published, as page.HandleCommands
+// does (see page.Sanitize):
+//
+//	s := sanitize.New(sanitize.WithPolicy(field.HTML, sanitize.HTMLPolicy(
+//		sanitize.ImageSources("https://cdn.example.com/", "/media/"),
+//	)))
+//
+//	f = s.Field(f)              // on write
+//	if err := s.Verify(f); err != nil { // on publish
+//		// errors.Is(err, sanitize.ErrUnsafe)
+//	}
+package sanitize
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/modernice/nice-cms/static/page/field"
+	"golang.org/x/net/html"
+)
+
+// ErrUnsafe is returned by Verify if a field value is not sanitized.
+var ErrUnsafe = errors.New("unsafe content")
+
+// DefaultSchemes are the URL schemes that are allowed by default.
+var DefaultSchemes = [...]string{"http", "https", "mailto", "tel"}
+
+// Policy is an allow-list policy for the values of a field type. Elements and
+// attributes that are not allowed are removed. The contents of removed
+// elements are kept, except for elements that are never rendered as text
+// (e.g. <script> and <style>), whose contents are removed as well.
+type Policy struct {
+	// Elements maps the allowed elements to their allowed attributes. The
+	// attributes of the "*" key are allowed on every allowed element.
+	Elements map[string][]string
+
+	// Schemes are the allowed schemes of URL attributes (href, src and cite).
+	// Relative URLs are always allowed, protocol-relative URLs never are.
+	Schemes []string
+
+	// Markdown specifies whether values are Markdown. Text of Markdown values
+	// is kept as is, and the destinations of Markdown links, images and link
+	// reference definitions are checked like URL attributes.
+	Markdown bool
+
+	// RewriteLink rewrites the URLs of links. If RewriteLink returns false, the
+	// link is removed but its text is kept.
+	RewriteLink func(*url.URL) (*url.URL, bool)
+
+	// ImageSources restricts the sources of images to URLs that start with one
+	// of the prefixes. Images with other sources are removed.
+	ImageSources []string
+}
+
+// PolicyOption is a Policy option.
+type PolicyOption func(*Policy)
+
+// Allow returns a PolicyOption that allows the given element with the given
+// attributes.
+func Allow(element string, attrs ...string) PolicyOption {
+	return func(p *Policy) {
+		p.Elements[element] = append(p.Elements[element], attrs...)
+	}
+}
+
+// Schemes returns a PolicyOption that replaces the allowed URL schemes.
+func Schemes(schemes ...string) PolicyOption {
+	return func(p *Policy) {
+		p.Schemes = schemes
+	}
+}
+
+// RewriteLinks returns a PolicyOption that rewrites the URLs of links.
+func RewriteLinks(fn func(*url.URL) (*url.URL, bool)) PolicyOption {
+	return func(p *Policy) {
+		p.RewriteLink = fn
+	}
+}
+
+// ImageSources returns a PolicyOption that restricts the sources of images to
+// URLs with one of the given prefixes, e.g. the URL of the CMS storage. Use
+// complete prefixes ("https://cdn.example.com/" instead of
+// "https://cdn.example.com").
+func ImageSources(prefixes ...string) PolicyOption {
+	return func(p *Policy) {
+		p.ImageSources = append(p.ImageSources, prefixes...)
+	}
+}
+
+// HTMLPolicy returns the default Policy for rich text: basic formatting, lists,
+// headings, quotes, code, tables, links and images.
+func HTMLPolicy(opts ...PolicyOption) Policy {
+	p := Policy{
+		Elements: map[string][]string{
+			"*":          {"title", "lang", "dir"},
+			"a":          {"href", "target", "rel"},
+			"img":        {"src", "alt", "width", "height"},
+			"blockquote": {"cite"},
+			"td":         {"colspan", "rowspan"},
+			"th":         {"colspan", "rowspan", "scope"},
+		},
+		Schemes: DefaultSchemes[:],
+	}
+	for _, el := range []string{
+		"p", "br", "hr", "strong", "b", "em", "i", "u", "s", "sub", "sup", "small",
+		"mark", "span", "h1", "h2", "h3", "h4", "h5", "h6", "ul", "ol", "li",
+		"code", "pre", "table", "thead", "tbody", "tfoot", "tr", "caption",
+	} {
+		p.Elements[el] = nil
+	}
+	for _, opt := range opts {
+		opt(&p)
+	}
+	return p
+}
+
+// MarkdownPolicy returns the default Policy for Markdown. Markdown values may
+// contain the same HTML as values of the HTMLPolicy.
+func MarkdownPolicy(opts ...PolicyOption) Policy {
+	p := HTMLPolicy(opts...)
+	p.Markdown = true
+	return p
+}
+
+// Sanitizer sanitizes field values using the Policies of their field types.
+// Values of field types without a Policy are not sanitized.
+type Sanitizer struct {
+	policies map[field.Type]Policy
+}
+
+// Option is a Sanitizer option.
+type Option func(*Sanitizer)
+
+// WithPolicy returns an Option that sets the Policy of the given field type.
+func WithPolicy(typ field.Type, p Policy) Option {
+	return func(s *Sanitizer) {
+		s.policies[typ] = p
+	}
+}
+
+// New returns a Sanitizer that uses HTMLPolicy for field.HTML and
+// MarkdownPolicy for field.Markdown, unless other Policies are provided.
+func New(opts ...Option) *Sanitizer {
+	s := &Sanitizer{policies: map[field.Type]Policy{
+		field.HTML:     HTMLPolicy(),
+		field.Markdown: MarkdownPolicy(),
+	}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// String sanitizes a value of the given field type.
+func (s *Sanitizer) String(typ field.Type, value string) string {
+	p, ok := s.policies[typ]
+	if !ok {
+		return value
+	}
+	return p.Sanitize(value)
+}
+
+// Field returns a copy of f with sanitized values.
+func (s *Sanitizer) Field(f field.Field) field.Field {
+	if _, ok := s.policies[f.Type]; !ok {
+		return f
+	}
+	values := make(map[string]string, len(f.Values))
+	for locale, v := range f.Values {
+		values[locale] = s.String(f.Type, v)
+	}
+	f.Values = values
+	return f
+}
+
+// Verify returns an error that wraps ErrUnsafe if one of the values of the
+// given fields would be changed by sanitization.
+func (s *Sanitizer) Verify(fields ...field.Field) error {
+	for _, f := range fields {
+		locales := make([]string, 0, len(f.Values))
+		for locale := range f.Values {
+			locales = append(locales, locale)
+		}
+		sort.Strings(locales)
+
+		for _, locale := range locales {
+			if v := f.Values[locale]; s.String(f.Type, v) != v {
+				return fmt.Errorf("field %q (locale %q): %w", f.Name, locale, ErrUnsafe)
+			}
+		}
+	}
+	return nil
+}
+
+// skipped are the elements whose contents are removed with the element.
+var skipped = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "noembed": true, "noframes": true, "template": true,
+	"textarea": true, "title": true, "xmp": true, "svg": true, "math": true,
+}
+
+var void = map[string]bool{"br": true, "hr": true, "img": true, "wbr": true}
+
+// Sanitize returns the sanitized value.
+func (p Policy) Sanitize(value string) string {
+	var b strings.Builder
+	var skip int
+	open := make(map[string]int)
+
+	var images map[string]bool
+	if p.Markdown {
+		images = markdownImageLabels(value)
+	}
+
+	z := html.NewTokenizer(strings.NewReader(value))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if z.Err() != io.EOF {
+				// The tokenizer only fails on read errors, which can't happen
+				// for a strings.Reader.
+				return ""
+			}
+			break
+		}
+
+		raw := string(z.Raw())
+		tok := z.Token()
+
+		switch tt {
+		case html.TextToken:
+			if skip > 0 {
+				continue
+			}
+			if p.Markdown {
+				b.WriteString(p.markdownLinks(raw, images))
+				continue
+			}
+			b.WriteString(html.EscapeString(tok.Data))
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if skipped[tok.Data] {
+				if tt == html.StartTagToken {
+					skip++
+				}
+				continue
+			}
+			if skip > 0 {
+				continue
+			}
+			if s, ok := p.startTag(tok); ok {
+				b.WriteString(s)
+				if tt == html.StartTagToken && !void[tok.Data] {
+					open[tok.Data]++
+				}
+			}
+
+		case html.EndTagToken:
+			if skipped[tok.Data] {
+				if skip > 0 {
+					skip--
+				}
+				continue
+			}
+			if skip > 0 || open[tok.Data] == 0 {
+				continue
+			}
+			open[tok.Data]--
+			b.WriteString("</" + tok.Data + ">")
+		}
+	}
+
+	return b.String()
+}
+
+func (p Policy) startTag(tok html.Token) (string, bool) {
+	allowed, ok := p.Elements[tok.Data]
+	if !ok {
+		return "", false
+	}
+
+	attrs := make(map[string]bool, len(allowed)+len(p.Elements["*"]))
+	for _, a := range allowed {
+		attrs[a] = true
+	}
+	for _, a := range p.Elements["*"] {
+		attrs[a] = true
+	}
+
+	var b strings.Builder
+	b.WriteString("<" + tok.Data)
+
+	var hasTarget bool
+	for _, attr := range tok.Attr {
+		key := strings.ToLower(attr.Key)
+		if attr.Namespace != "" || !attrs[key] || strings.HasPrefix(key, "on") {
+			continue
+		}
+
+		val := attr.Val
+		switch key {
+		case "href", "src", "cite":
+			u, ok := p.url(val)
+			if ok && tok.Data == "a" && key == "href" && p.RewriteLink != nil {
+				u, ok = p.RewriteLink(u)
+			}
+			if ok && tok.Data == "img" && key == "src" {
+				ok = p.imageSource(u.String())
+			}
+			if !ok {
+				// Links without a URL and images without a source are removed.
+				if (tok.Data == "a" && key == "href") || (tok.Data == "img" && key == "src") {
+					return "", false
+				}
+				continue
+			}
+			val = u.String()
+		case "target":
+			hasTarget = true
+		case "rel":
+			continue
+		}
+
+		b.WriteString(" " + key + `="` + html.EscapeString(val) + `"`)
+	}
+
+	if hasTarget && tok.Data == "a" {
+		b.WriteString(` rel="noopener noreferrer"`)
+	}
+
+	if tok.Type == html.SelfClosingTagToken {
+		b.WriteString(" />")
+	} else {
+		b.WriteString(">")
+	}
+
+	return b.String(), true
+}
+
+// url parses and validates a URL attribute value.
+func (p Policy) url(raw string) (*url.URL, bool) {
+	raw = strings.TrimSpace(raw)
+	if strings.IndexFunc(raw, func(r rune) bool { return r < 0x20 || r == 0x7f }) >= 0 {
+		return nil, false
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, false
+	}
+
+	if u.Scheme == "" {
+		return u, u.Host == "" && !strings.HasPrefix(raw, "//") && !strings.HasPrefix(raw, `\`)
+	}
+
+	for _, scheme := range p.Schemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return u, true
+		}
+	}
+
+	return nil, false
+}
+
+func (p Policy) imageSource(src string) bool {
+	if len(p.ImageSources) == 0 {
+		return true
+	}
+	for _, prefix := range p.ImageSources {
+		if strings.HasPrefix(src, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// markdownLink matches the destinations of inline Markdown links and images.
+var markdownLink = regexp.MustCompile(`(!?)\[([^\]]*)\]\(\s*(<[^>]*>|[^\s()]*(?:\([^\s()]*\)[^\s()]*)*)([^)]*)\)`)
+
+// markdownDefinition matches Markdown link reference definitions
+// ("[label]: destination "title""), which define the destinations of reference
+// links and images ("[text][label]").
+var markdownDefinition = regexp.MustCompile(`(?m)^( {0,3}\[)((?:[^\]\\]|\\.)+)(\]:[ \t]*\n?[ \t]*)(<[^>\n]*>|\S+)(.*)$`)
+
+// markdownImageRef matches the labels of Markdown images that may reference a
+// link reference definition ("![text][label]", "![label][]" and "![label]").
+var markdownImageRef = regexp.MustCompile(`!\[((?:[^\]\\]|\\.)*)\](?:\[((?:[^\]\\]|\\.)*)\])?`)
+
+// markdownImageLabels returns the normalized labels of the link reference
+// definitions that may be used by the images of a Markdown text.
+func markdownImageLabels(text string) map[string]bool {
+	labels := make(map[string]bool)
+	for _, m := range markdownImageRef.FindAllStringSubmatch(text, -1) {
+		label := m[2]
+		if label == "" {
+			label = m[1]
+		}
+		labels[markdownLabel(label)] = true
+	}
+	return labels
+}
+
+// markdownLabel normalizes the label of a link reference definition. Labels
+// are matched case-insensitively and consecutive whitespace is collapsed.
+func markdownLabel(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), " "))
+}
+
+// markdownLinks checks the destinations of the inline links and images and
+// of the link reference definitions of a Markdown text. Links with invalid
+// destinations are replaced by their text and images and definitions with
+// invalid destinations are removed. The destinations of the definitions
+// whose labels are in images must be valid image sources.
+func (p Policy) markdownLinks(text string, images map[string]bool) string {
+	text = markdownLink.ReplaceAllStringFunc(text, func(match string) string {
+		m := markdownLink.FindStringSubmatch(match)
+		image, label, dest, rest := m[1] == "!", m[2], strings.Trim(m[3], "<>"), m[4]
+
+		u, ok := p.link(dest, image)
+		if !ok {
+			if image {
+				return ""
+			}
+			return label
+		}
+
+		return m[1] + "[" + label + "](" + u.String() + rest + ")"
+	})
+
+	return markdownDefinition.ReplaceAllStringFunc(text, func(match string) string {
+		m := markdownDefinition.FindStringSubmatch(match)
+		label, dest := m[2], strings.Trim(m[4], "<>")
+
+		u, ok := p.link(dest, images[markdownLabel(label)])
+		if !ok {
+			return ""
+		}
+
+		return m[1] + label + m[3] + u.String() + m[5]
+	})
+}
+
+// link checks the destination of a Markdown link or image.
+func (p Policy) link(dest string, image bool) (*url.URL, bool) {
+	u, ok := p.url(dest)
+	if ok && !image && p.RewriteLink != nil {
+		u, ok = p.RewriteLink(u)
+	}
+	if ok && image {
+		ok = p.imageSource(u.String())
+	}
+	return u, ok
+}
@@ -0,0 +1,142 @@
+package sanitize_test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/modernice/nice-cms/static/page/field"
+	"github.com/modernice/nice-cms/static/page/field/sanitize"
+	"github.com/modernice/nice-cms/static/page/field/text"
+)
+
+func TestPolicy_Sanitize(t *testing.T) {
+	p := sanitize.HTMLPolicy()
+
+	tests := map[string]string{
+		`<p>Hello, <strong>World</strong>!</p>`:                    `<p>Hello, <strong>World</strong>!</p>`,
+		`<p>foo<script>alert(1)</script>bar</p>`:                   `<p>foobar</p>`,
+		`<div class="x"><p>foo</p></div>`:                          `<p>foo</p>`,
+		`<p onclick="alert(1)" title="foo">bar</p>`:                `<p title="foo">bar</p>`,
+		`<a href="javascript:alert(1)">foo</a>`:                    `foo`,
+		`<a href=" JaVaScRiPt:alert(1)">foo</a>`:                   `foo`,
+		`<a href="//evil.com">foo</a>`:                             `foo`,
+		`<a href="/about">foo</a>`:                                 `<a href="/about">foo</a>`,
+		`<a href="https://example.com" target="_blank">foo</a>`:    `<a href="https://example.com" target="_blank" rel="noopener noreferrer">foo</a>`,
+		`<img src="data:image/png;base64,AAAA">`:                   ``,
+		`<img src="https://example.com/a.png" onerror="alert(1)">`: `<img src="https://example.com/a.png">`,
+		`<p>foo</em></p>`:                                          `<p>foo</p>`,
+		`<!-- comment --><p>&lt;script&gt;</p>`:                    `<p>&lt;script&gt;</p>`,
+		`<svg><script>alert(1)</script></svg>foo`:                  `foo`,
+	}
+
+	for input, want := range tests {
+		if got := p.Sanitize(input); got != want {
+			t.Errorf("Sanitize(%q) should return %q; got %q", input, want, got)
+		}
+	}
+}
+
+func TestImageSources(t *testing.T) {
+	p := sanitize.HTMLPolicy(sanitize.ImageSources("https://cdn.example.com/"))
+
+	tests := map[string]string{
+		`<img src="https://cdn.example.com/a.png" alt="a">`:  `<img src="https://cdn.example.com/a.png" alt="a">`,
+		`<img src="https://cdn.example.com.evil.com/a.png">`: ``,
+		`<img src="https://evil.com/a.png">`:                 ``,
+	}
+
+	for input, want := range tests {
+		if got := p.Sanitize(input); got != want {
+			t.Errorf("Sanitize(%q) should return %q; got %q", input, want, got)
+		}
+	}
+}
+
+func TestRewriteLinks(t *testing.T) {
+	p := sanitize.HTMLPolicy(sanitize.RewriteLinks(func(u *url.URL) (*url.URL, bool) {
+		if u.Host == "blocked.com" {
+			return u, false
+		}
+		if u.Host != "" {
+			return &url.URL{Path: "/out", RawQuery: url.Values{"url": {u.String()}}.Encode()}, true
+		}
+		return u, true
+	}))
+
+	tests := map[string]string{
+		`<a href="/about">foo</a>`:              `<a href="/about">foo</a>`,
+		`<a href="https://example.com">foo</a>`: `<a href="/out?url=https%3A%2F%2Fexample.com">foo</a>`,
+		`<a href="https://blocked.com">foo</a>`: `foo`,
+	}
+
+	for input, want := range tests {
+		if got := p.Sanitize(input); got != want {
+			t.Errorf("Sanitize(%q) should return %q; got %q", input, want, got)
+		}
+	}
+}
+
+func TestMarkdownPolicy(t *testing.T) {
+	p := sanitize.MarkdownPolicy(sanitize.ImageSources("/media/"))
+
+	tests := map[string]string{
+		"# Title\n\n> quote & *text*":           "# Title\n\n> quote & *text*",
+		"[foo](https://example.com)":            "[foo](https://example.com)",
+		"[foo](javascript:alert(1))":            "foo",
+		"![foo](/media/a.png \"Foo\")":          "![foo](/media/a.png \"Foo\")",
+		"![foo](https://evil.com/a.png)":        "",
+		"foo <script>alert(1)</script>bar":      "foo bar",
+		"<a href=\"javascript:alert(1)\">x</a>": "x",
+
+		"[click][x]\n\n[x]: javascript:alert(1)":       "[click][x]\n\n",
+		"[click][x]\n\n[x]: https://example.com \"X\"": "[click][x]\n\n[x]: https://example.com \"X\"",
+		"![i][r]\n\n[R]: https://evil.com/a.png":       "![i][r]\n\n",
+		"![r]\n\n[r]:\n  https://evil.com/a.png":       "![r]\n\n",
+		"![i][r]\n\n[r]: /media/a.png":                 "![i][r]\n\n[r]: /media/a.png",
+	}
+
+	for input, want := range tests {
+		if got := p.Sanitize(input); got != want {
+			t.Errorf("Sanitize(%q) should return %q; got %q", input, want, got)
+		}
+	}
+}
+
+func TestSanitizer_Field(t *testing.T) {
+	s := sanitize.New()
+
+	f := field.NewHTML("body", `<p>foo<script>alert(1)</script></p>`, text.Localize(`<p onclick="alert(1)">bar</p>`, "de"))
+	f = s.Field(f)
+
+	if got := f.Value(""); got != "<p>foo</p>" {
+		t.Fatalf("default value should be sanitized to %q; is %q", "<p>foo</p>", got)
+	}
+
+	if got := f.Value("de"); got != "<p>bar</p>" {
+		t.Fatalf("localized value should be sanitized to %q; is %q", "<p>bar</p>", got)
+	}
+
+	txt := field.NewText("title", "<script>alert(1)</script>")
+	if got := s.Field(txt).Value(""); got != "<script>alert(1)</script>" {
+		t.Fatalf("fields without a Policy should not be sanitized; got %q", got)
+	}
+}
+
+func TestSanitizer_Verify(t *testing.T) {
+	s := sanitize.New()
+
+	safe := field.NewHTML("body", `<p>foo</p>`)
+	if err := s.Verify(safe); err != nil {
+		t.Fatalf("Verify should not fail for sanitized fields; failed with %q", err)
+	}
+
+	unsafe := field.NewMarkdown("body", "foo", text.Localize("[bar](javascript:alert(1))", "de"))
+	if err := s.Verify(safe, unsafe); !errors.Is(err, sanitize.ErrUnsafe) {
+		t.Fatalf("Verify should fail with %q; got %q", sanitize.ErrUnsafe, err)
+	}
+
+	if err := s.Verify(s.Field(unsafe)); err != nil {
+		t.Fatalf("Verify should not fail for sanitized fields; failed with %q", err)
+	}
+}
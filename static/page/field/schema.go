@@ -0,0 +1,94 @@
+package field
+
+import (
+	"github.com/modernice/nice-cms/schema"
+	"github.com/modernice/nice-cms/static/page/metadata"
+)
+
+// FieldSchema returns the JSON Schema of a Field.
+func FieldSchema() *schema.Schema {
+	return schema.Of(Field{}, schema.Title("Page field"))
+}
+
+// ValueSchema returns the JSON Schema of the values of the Field, as they are
+// passed to Page.UpdateField. Values of List fields are described by the
+// Schema of the Field and values of Image and Document fields by ImageRef and
+// DocumentRef. Values of unknown Types may be anything.
+func (f Field) ValueSchema() *schema.Schema {
+	var s *schema.Schema
+	switch f.Type {
+	case List:
+		if f.Schema == nil {
+			s = &schema.Schema{Type: "array", Items: &schema.Schema{Type: "object"}}
+			break
+		}
+		s = f.Schema.jsonSchema()
+	case Meta:
+		s = schema.Of(metadata.Data{})
+	case Image:
+		s = schema.Of(ImageRef{})
+	case Document:
+		s = schema.Of(DocumentRef{})
+	default:
+		s = typeSchema(f.Type)
+	}
+
+	s.Schema = ""
+	s.Title = f.Name
+
+	return s
+}
+
+func (s Schema) jsonSchema() *schema.Schema {
+	items := &schema.Schema{Type: "object", Properties: make(map[string]*schema.Schema)}
+	for _, p := range s.Props {
+		prop := typeSchema(p.Type)
+		switch {
+		case p.Type == List && p.Items != nil:
+			prop = p.Items.jsonSchema()
+		case p.Type == Image:
+			prop = schema.Of(ImageRef{})
+			prop.Schema = ""
+		case p.Type == Document:
+			prop = schema.Of(DocumentRef{})
+			prop.Schema = ""
+		}
+		items.Properties[p.Name] = prop
+		if p.Required {
+			items.Required = append(items.Required, p.Name)
+		}
+	}
+
+	out := &schema.Schema{Type: "array", Items: items}
+	if s.MinItems > 0 {
+		min := s.MinItems
+		out.MinItems = &min
+	}
+	if s.MaxItems > 0 {
+		max := s.MaxItems
+		out.MaxItems = &max
+	}
+
+	return out
+}
+
+func typeSchema(typ Type) *schema.Schema {
+	switch typ {
+	case Text, Money:
+		return &schema.Schema{Type: "string"}
+	case HTML:
+		return &schema.Schema{Type: "string", Format: "html"}
+	case Markdown:
+		return &schema.Schema{Type: "string", Format: "markdown"}
+	case Toggle:
+		return &schema.Schema{Type: "boolean"}
+	case Int:
+		return &schema.Schema{Type: "integer"}
+	case Float:
+		return &schema.Schema{Type: "number"}
+	case List:
+		return &schema.Schema{Type: "array"}
+	default:
+		return &schema.Schema{}
+	}
+}
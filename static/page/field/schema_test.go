@@ -0,0 +1,70 @@
+package field_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+func TestField_ValueSchema(t *testing.T) {
+	tests := map[string]struct {
+		field  field.Field
+		typ    string
+		format string
+	}{
+		"text":     {field: field.NewText("title", ""), typ: "string"},
+		"html":     {field: field.NewHTML("body", ""), typ: "string", format: "html"},
+		"markdown": {field: field.NewMarkdown("body", ""), typ: "string", format: "markdown"},
+		"toggle":   {field: field.NewToggle("visible", false), typ: "boolean"},
+		"int":      {field: field.NewInt("count", 0), typ: "integer"},
+		"float":    {field: field.NewFloat("ratio", 0), typ: "number"},
+		"image":    {field: field.NewImage("hero", field.ImageRef{}), typ: "object"},
+		"document": {field: field.NewDocument("terms", field.DocumentRef{}), typ: "object"},
+		"list":     {field: field.NewList("cards", cardSchema, nil), typ: "array"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := tt.field.ValueSchema()
+			if s.Type != tt.typ || s.Format != tt.format {
+				t.Fatalf("schema should have type %q and format %q; got %q and %q", tt.typ, tt.format, s.Type, s.Format)
+			}
+			if s.Title != tt.field.Name {
+				t.Fatalf("schema should have the title %q; got %q", tt.field.Name, s.Title)
+			}
+		})
+	}
+}
+
+func TestField_ValueSchema_nested(t *testing.T) {
+	s := field.NewList("cards", cardSchema, nil).ValueSchema()
+
+	items := s.Items
+	if items == nil || items.Type != "object" {
+		t.Fatalf("items should be objects; got %+v", items)
+	}
+	if len(items.Required) != 1 || items.Required[0] != "title" {
+		t.Fatalf("required props should be required; got %v", items.Required)
+	}
+	if items.Properties["order"].Type != "integer" {
+		t.Fatalf("props should have the schema of their type; got %+v", items.Properties["order"])
+	}
+
+	if items.Properties["image"].Properties["stack"].Format != "uuid" {
+		t.Fatalf("Image props should have the schema of ImageRef; got %+v", items.Properties["image"])
+	}
+
+	links := items.Properties["links"]
+	if links.Type != "array" || links.MaxItems == nil || *links.MaxItems != 2 {
+		t.Fatalf("list props should have the schema of their items; got %+v", links)
+	}
+	if links.Items.Properties["url"].Type != "string" {
+		t.Fatalf("nested props should have the schema of their type; got %+v", links.Items.Properties["url"])
+	}
+
+	ref := field.NewImage("hero", field.ImageRef{Gallery: uuid.New(), Stack: uuid.New()}).ValueSchema()
+	if ref.Properties["gallery"].Format != "uuid" || ref.Properties["stack"].Format != "uuid" {
+		t.Fatalf("Image fields should reference stacks by UUIDs; got %+v", ref.Properties)
+	}
+}
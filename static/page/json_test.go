@@ -0,0 +1,58 @@
+package page_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/nice-cms/static/page"
+)
+
+func TestPage_MarshalJSON(t *testing.T) {
+	p := page.New(uuid.New())
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("json.Marshal failed with %q", err)
+	}
+
+	var unmarshaled page.Page
+	if err := json.Unmarshal(b, &unmarshaled); err != nil {
+		t.Fatalf("json.Unmarshal failed with %q", err)
+	}
+
+	if !cmp.Equal(p, &unmarshaled, cmpopts.IgnoreUnexported(aggregate.Base{})) {
+		t.Fatalf("invalid unmarshal.\n\n%s", cmp.Diff(p, &unmarshaled))
+	}
+}
+
+func TestPage_MarshalJSON_schedule(t *testing.T) {
+	p := page.New(uuid.New())
+	p.Create("foo")
+
+	at := time.Date(2030, time.January, 1, 12, 0, 0, 0, time.UTC)
+	p.SchedulePublish(at)
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("json.Marshal failed with %q", err)
+	}
+
+	var data struct {
+		Published bool `json:"published"`
+		Schedule  struct {
+			PublishAt time.Time `json:"publishAt"`
+		} `json:"schedule"`
+	}
+	if err := json.Unmarshal(b, &data); err != nil {
+		t.Fatalf("json.Unmarshal failed with %q", err)
+	}
+
+	if data.Published || !data.Schedule.PublishAt.Equal(at) {
+		t.Fatalf("JSON should contain the Schedule of the Page; got %s", b)
+	}
+}
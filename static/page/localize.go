@@ -0,0 +1,54 @@
+package page
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+// SetField sets the value of the Field with the given name for a single
+// locale. Use the empty locale to set the default value of the Field. The
+// value is formatted using field.Format.
+func (p *Page) SetField(locale, name string, value any) error {
+	return p.UpdateField(name, value, locale)
+}
+
+// LocalizedField is a Field with the value of a single locale.
+type LocalizedField struct {
+	Name  string     `json:"name"`
+	Type  field.Type `json:"type"`
+	Value string     `json:"value"`
+}
+
+// LocalizedView is a View with the Field values of a single locale.
+type LocalizedView struct {
+	ID          uuid.UUID        `json:"id"`
+	Name        string           `json:"name"`
+	Locale      string           `json:"locale"`
+	Fields      []LocalizedField `json:"fields"`
+	PublishedAt time.Time        `json:"publishedAt"`
+	Preview     bool             `json:"preview,omitempty"`
+}
+
+// Localize returns the View with the Field values of the given locale. Fields
+// without a value for the locale fall back to the parent locales, the given
+// fallback locales and the default value (see locale.Resolve).
+func (v View) Localize(locale string, fallbacks ...string) LocalizedView {
+	fields := make([]LocalizedField, len(v.Fields))
+	for i, f := range v.Fields {
+		fields[i] = LocalizedField{
+			Name:  f.Name,
+			Type:  f.Type,
+			Value: f.Value(locale, fallbacks...),
+		}
+	}
+	return LocalizedView{
+		ID:          v.ID,
+		Name:        v.Name,
+		Locale:      locale,
+		Fields:      fields,
+		PublishedAt: v.PublishedAt,
+		Preview:     v.Preview,
+	}
+}
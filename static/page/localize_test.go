@@ -0,0 +1,55 @@
+package page_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+func TestPage_SetField(t *testing.T) {
+	p := page.New(uuid.New())
+	p.Create("foo", field.NewText("title", "Hello", field.Localize("Hello (en)", "en")))
+
+	if err := p.SetField("de", "title", "Hallo"); err != nil {
+		t.Fatalf("SetField failed with %q", err)
+	}
+
+	test.Change(t, p, page.FieldUpdated, test.EventData(page.FieldUpdatedData{
+		Field:   "title",
+		Value:   "Hallo",
+		Locales: []string{"de"},
+	}))
+
+	f, _ := p.Field("title")
+	for locale, want := range map[string]string{"": "Hello", "en": "Hello (en)", "de": "Hallo", "de-AT": "Hallo"} {
+		if val := f.Value(locale); val != want {
+			t.Fatalf("Value(%q) should return %q; got %q", locale, want, val)
+		}
+	}
+}
+
+func TestView_Localize(t *testing.T) {
+	p := page.New(uuid.New())
+	p.Create("foo", field.NewText("title", "Hello"), field.NewToggle("visible", true))
+	p.SetField("fr", "title", "Bonjour")
+	p.SetField("de", "visible", false)
+
+	want := page.LocalizedView{
+		ID:     p.ID,
+		Name:   "foo",
+		Locale: "de",
+		Fields: []page.LocalizedField{
+			{Name: "title", Type: field.Text, Value: "Bonjour"},
+			{Name: "visible", Type: field.Toggle, Value: "0"},
+		},
+		Preview: true,
+	}
+
+	if got := p.Preview().Localize("de", "fr"); !cmp.Equal(want, got) {
+		t.Fatalf("invalid localized View.\n\n%s", cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,158 @@
+// Package mediaref validates and resolves the Image and Document fields of
+// Pages and reports references that break when their target is deleted.
+//
+// A Validator checks that the Stack or Document referenced by a Field exists
+// before the Field is updated:
+//
+//	v := mediaref.NewValidator(galleryLookup, documentLookup)
+//	err := pages.Use(ctx, pageID, func(p *page.Page) error {
+//		return v.UpdateField(p, "hero", field.ImageRef{Gallery: galleryID, Stack: stackID})
+//	})
+//
+// A Resolver resolves references to the full Stacks and Documents (see
+// pageserver.WithResolver) and a Monitor reports the references that are broken
+// by deleted Stacks and Documents.
+package mediaref
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+// ErrBrokenReference is returned when a Field references a Stack or Document
+// that doesn't exist.
+var ErrBrokenReference = errors.New("broken reference")
+
+// Validator validates the references of Image and Document fields using the
+// Lookups of Galleries and Shelfs.
+type Validator struct {
+	galleries *gallery.Lookup
+	shelfs    *document.Lookup
+}
+
+// NewValidator returns a Validator that looks up Stacks in galleries and
+// Documents in shelfs.
+func NewValidator(galleries *gallery.Lookup, shelfs *document.Lookup) *Validator {
+	return &Validator{
+		galleries: galleries,
+		shelfs:    shelfs,
+	}
+}
+
+// Validate validates a value of the Field. If the value references a Stack or
+// Document that doesn't exist, an error that wraps ErrBrokenReference is
+// returned. Empty references and values of other Fields are only validated
+// using f.Validate.
+func (v *Validator) Validate(f field.Field, value string) error {
+	if err := f.Validate(value); err != nil {
+		return err
+	}
+
+	switch f.Type {
+	case field.Image:
+		ref, _ := field.ParseImageRef(value)
+		if !ref.IsZero() && !v.galleries.HasStack(ref.Gallery, ref.Stack) {
+			return fmt.Errorf("stack %q of gallery %q: %w", ref.Stack, ref.Gallery, ErrBrokenReference)
+		}
+	case field.Document:
+		ref, _ := field.ParseDocumentRef(value)
+		if !ref.IsZero() && !v.shelfs.HasDocument(ref.Shelf, ref.Document) {
+			return fmt.Errorf("document %q of shelf %q: %w", ref.Document, ref.Shelf, ErrBrokenReference)
+		}
+	}
+
+	return nil
+}
+
+// UpdateField validates the value and then updates the Field of the Page (see
+// page.Page.UpdateField).
+func (v *Validator) UpdateField(p *page.Page, name string, value any, locales ...string) error {
+	f, err := p.Field(name)
+	if err != nil {
+		return err
+	}
+
+	if err := v.Validate(f, field.Format(value)); err != nil {
+		return fmt.Errorf("%q: %w", name, err)
+	}
+
+	return p.UpdateField(name, value, locales...)
+}
+
+// Resolver resolves the references of Image and Document fields to the
+// referenced Stacks and Documents.
+type Resolver struct {
+	galleries gallery.Repository
+	shelfs    document.Repository
+}
+
+// NewResolver returns a Resolver that fetches Stacks from galleries and
+// Documents from shelfs.
+func NewResolver(galleries gallery.Repository, shelfs document.Repository) *Resolver {
+	return &Resolver{
+		galleries: galleries,
+		shelfs:    shelfs,
+	}
+}
+
+// Resolve returns the gallery.Stack or document.Document that is referenced by
+// a value of the Field. Resolve returns nil if the Field is not an Image or
+// Document field, if the value is empty or if the reference is broken.
+func (r *Resolver) Resolve(ctx context.Context, f field.Field, value string) (any, error) {
+	switch f.Type {
+	case field.Image:
+		ref, err := field.ParseImageRef(value)
+		if err != nil || ref.IsZero() {
+			return nil, nil
+		}
+		return r.resolveStack(ctx, ref)
+	case field.Document:
+		ref, err := field.ParseDocumentRef(value)
+		if err != nil || ref.IsZero() {
+			return nil, nil
+		}
+		return r.resolveDocument(ctx, ref)
+	default:
+		return nil, nil
+	}
+}
+
+func (r *Resolver) resolveStack(ctx context.Context, ref field.ImageRef) (any, error) {
+	g, err := r.galleries.Fetch(ctx, ref.Gallery)
+	if err != nil {
+		return nil, fmt.Errorf("fetch gallery: %w", err)
+	}
+
+	stack, err := g.Stack(ref.Stack)
+	if errors.Is(err, gallery.ErrStackNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return stack, nil
+}
+
+func (r *Resolver) resolveDocument(ctx context.Context, ref field.DocumentRef) (any, error) {
+	shelf, err := r.shelfs.Fetch(ctx, ref.Shelf)
+	if err != nil {
+		return nil, fmt.Errorf("fetch shelf: %w", err)
+	}
+
+	doc, err := shelf.Document(ref.Document)
+	if errors.Is(err, document.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,107 @@
+package mediaref_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/internal/testutil"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+	"github.com/modernice/nice-cms/static/page/mediaref"
+)
+
+func TestValidator(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env := testutil.NewEnv()
+
+	galleryLookup := gallery.NewLookup()
+	shelfLookup := document.NewLookup()
+	env.Project(ctx, t, galleryLookup.Project, shelfLookup.Project)
+
+	g, stack := env.UploadStack(ctx, t)
+	shelf, doc := env.AddDocument(ctx, t)
+	<-time.After(50 * time.Millisecond)
+
+	v := mediaref.NewValidator(galleryLookup, shelfLookup)
+
+	p := page.New(uuid.New())
+	p.Create("foo", field.NewImage("hero", field.ImageRef{}), field.NewDocument("brochure", field.DocumentRef{}))
+
+	if err := v.UpdateField(p, "hero", field.ImageRef{Gallery: g.ID, Stack: stack.ID}); err != nil {
+		t.Fatalf("UpdateField failed with %q", err)
+	}
+	if err := v.UpdateField(p, "brochure", field.DocumentRef{Shelf: shelf.ID, Document: doc.ID}); err != nil {
+		t.Fatalf("UpdateField failed with %q", err)
+	}
+	if err := v.UpdateField(p, "hero", field.ImageRef{}); err != nil {
+		t.Fatalf("empty references should be valid; UpdateField failed with %q", err)
+	}
+
+	if err := v.UpdateField(p, "hero", field.ImageRef{Gallery: g.ID, Stack: uuid.New()}); !errors.Is(err, mediaref.ErrBrokenReference) {
+		t.Fatalf("UpdateField should fail with %q; got %v", mediaref.ErrBrokenReference, err)
+	}
+	if err := v.UpdateField(p, "brochure", field.DocumentRef{Shelf: uuid.New(), Document: doc.ID}); !errors.Is(err, mediaref.ErrBrokenReference) {
+		t.Fatalf("UpdateField should fail with %q; got %v", mediaref.ErrBrokenReference, err)
+	}
+	if err := v.UpdateField(p, "hero", "foo"); !errors.Is(err, field.ErrInvalidValue) {
+		t.Fatalf("UpdateField should fail with %q; got %v", field.ErrInvalidValue, err)
+	}
+
+	ref, _ := p.Fields[0].ImageRef("")
+	if !ref.IsZero() {
+		t.Fatalf("invalid references should not be applied; got %v", ref)
+	}
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	ctx := context.Background()
+	env := testutil.NewEnv()
+	g, stack := env.UploadStack(ctx, t)
+	shelf, doc := env.AddDocument(ctx, t)
+
+	r := mediaref.NewResolver(env.Galleries, env.Shelfs)
+
+	hero := field.NewImage("hero", field.ImageRef{})
+	v, err := r.Resolve(ctx, hero, field.ImageRef{Gallery: g.ID, Stack: stack.ID}.String())
+	if err != nil {
+		t.Fatalf("Resolve failed with %q", err)
+	}
+	if resolved, ok := v.(gallery.Stack); !ok || resolved.ID != stack.ID {
+		t.Fatalf("Resolve should return Stack %s; got %v", stack.ID, v)
+	}
+
+	brochure := field.NewDocument("brochure", field.DocumentRef{})
+	v, err = r.Resolve(ctx, brochure, field.DocumentRef{Shelf: shelf.ID, Document: doc.ID}.String())
+	if err != nil {
+		t.Fatalf("Resolve failed with %q", err)
+	}
+	if resolved, ok := v.(document.Document); !ok || resolved.ID != doc.ID {
+		t.Fatalf("Resolve should return Document %s; got %v", doc.ID, v)
+	}
+
+	for _, tt := range []struct {
+		field field.Field
+		value string
+	}{
+		{hero, ""},
+		{hero, field.ImageRef{Gallery: g.ID, Stack: uuid.New()}.String()},
+		{hero, field.ImageRef{Gallery: uuid.New(), Stack: stack.ID}.String()},
+		{brochure, field.DocumentRef{Shelf: shelf.ID, Document: uuid.New()}.String()},
+		{field.NewText("title", "Foo"), "Foo"},
+	} {
+		v, err := r.Resolve(ctx, tt.field, tt.value)
+		if err != nil {
+			t.Fatalf("Resolve(%q) failed with %q", tt.value, err)
+		}
+		if v != nil {
+			t.Fatalf("Resolve(%q) should return nil; got %v", tt.value, v)
+		}
+	}
+}
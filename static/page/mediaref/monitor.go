@@ -0,0 +1,313 @@
+package mediaref
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/projector"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+// Report is a broken reference of a Page. A reference breaks when the
+// referenced Stack or Document is deleted or moved to the trash.
+type Report struct {
+	PageID uuid.UUID  `json:"pageId"`
+	Page   string     `json:"page"`
+	Field  string     `json:"field"`
+	Type   field.Type `json:"type"`
+	Locale string     `json:"locale"`
+
+	// Value is the broken reference.
+	Value string `json:"value"`
+
+	// Live is true if the reference is part of the published Fields of the
+	// Page and false if it is part of the draft.
+	Live bool `json:"live"`
+
+	// Time is the time at which the referenced Stack or Document was deleted.
+	Time time.Time `json:"time"`
+}
+
+// ReportHandler handles Reports. ReportHandlers are called synchronously while
+// the Monitor is projected and must not block.
+type ReportHandler func(context.Context, Report)
+
+// MonitorOption is a Monitor option.
+type MonitorOption func(*Monitor)
+
+// OnBroken returns a MonitorOption that registers a ReportHandler that is
+// called for every reference that is broken by a deleted Stack or Document.
+func OnBroken(fn ReportHandler) MonitorOption {
+	return func(m *Monitor) {
+		m.handlers = append(m.handlers, fn)
+	}
+}
+
+// Projection returns a MonitorOption that configures the error handling of the
+// projection.
+func Projection(opts ...projector.Option) MonitorOption {
+	return func(m *Monitor) {
+		m.projectorOpts = append(m.projectorOpts, opts...)
+	}
+}
+
+// Monitor is a projection of the references of Pages to Stacks and Documents.
+// Monitor reports the references that are broken by deleted Stacks and
+// Documents. Monitor is thread-safe.
+type Monitor struct {
+	projector     *projector.Projector
+	projectorOpts []projector.Option
+	handlers      []ReportHandler
+
+	mux   sync.RWMutex
+	ctx   context.Context
+	pages map[uuid.UUID]*page.Page
+
+	// deleted are the deletion times of the deleted Stacks and Documents.
+	deleted map[uuid.UUID]time.Time
+}
+
+// NewMonitor returns a new Monitor.
+func NewMonitor(opts ...MonitorOption) *Monitor {
+	m := &Monitor{
+		ctx:     context.Background(),
+		pages:   make(map[uuid.UUID]*page.Page),
+		deleted: make(map[uuid.UUID]time.Time),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	m.projector = projector.New(m.projectorOpts...)
+	return m
+}
+
+// Broken returns the currently broken references of all Pages, sorted by Page
+// name, Field, locale and draft before live references.
+func (m *Monitor) Broken() []Report {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	reports := make([]Report, 0)
+	for _, p := range m.pages {
+		for _, ref := range refsOf(p) {
+			if at, ok := m.deleted[ref.target]; ok {
+				reports = append(reports, ref.report(at))
+			}
+		}
+	}
+
+	sortReports(reports)
+
+	return reports
+}
+
+// Events returns the events that are projected by a Monitor: the page events
+// and the events that delete, trash and restore Stacks and Documents.
+func Events() []string {
+	return append([]string{
+		gallery.StackDeleted,
+		gallery.StackTrashed,
+		gallery.StackRestored,
+		gallery.Deleted,
+		document.DocumentRemoved,
+		document.DocumentTrashed,
+		document.DocumentRestored,
+		document.ShelfDeleted,
+	}, page.Events[:]...)
+}
+
+// Project projects the Monitor in a new goroutine and returns a channel of
+// asynchronous errors. ReportHandlers are called with ctx.
+func (m *Monitor) Project(ctx context.Context, bus event.Bus, store event.Store, opts ...schedule.ContinuousOption) (<-chan error, error) {
+	m.mux.Lock()
+	m.ctx = ctx
+	m.mux.Unlock()
+
+	schedule := schedule.Continuously(bus, store, Events(), opts...)
+
+	return m.projector.Run(ctx, schedule, m)
+}
+
+// Status returns the projection status of the Monitor.
+func (m *Monitor) Status() projector.Status {
+	return m.projector.Status()
+}
+
+// ApplyEvent applies aggregate events.
+func (m *Monitor) ApplyEvent(evt event.Event) {
+	m.mux.Lock()
+
+	var deleted []uuid.UUID
+	switch data := evt.Data().(type) {
+	case gallery.StackDeletedData:
+		deleted = []uuid.UUID{data.Stack.ID}
+	case gallery.StackTrashedData:
+		deleted = []uuid.UUID{data.Stack.ID}
+	case gallery.StackRestoredData:
+		delete(m.deleted, data.Stack.ID)
+	case gallery.DeletedData:
+		deleted = data.Stacks
+	case document.DocumentRemovedData:
+		deleted = []uuid.UUID{data.Document.ID}
+	case document.DocumentTrashedData:
+		deleted = []uuid.UUID{data.Document.ID}
+	case document.DocumentRestoredData:
+		delete(m.deleted, data.Document.ID)
+	case document.ShelfDeletedData:
+		deleted = data.Documents
+	default:
+		m.applyPageEvent(evt)
+	}
+
+	reports := m.markDeleted(deleted, evt.Time())
+	ctx := m.ctx
+
+	m.mux.Unlock()
+
+	for _, r := range reports {
+		for _, fn := range m.handlers {
+			fn(ctx, r)
+		}
+	}
+}
+
+func (m *Monitor) applyPageEvent(evt event.Event) {
+	id, name, _ := evt.Aggregate()
+	if name != page.Aggregate {
+		return
+	}
+
+	p, ok := m.pages[id]
+	if !ok {
+		p = page.New(id)
+		m.pages[id] = p
+	}
+	p.ApplyEvent(evt)
+}
+
+// markDeleted marks the Stacks and Documents with the given UUIDs as deleted
+// and returns the Reports of the references that were broken by the deletion.
+func (m *Monitor) markDeleted(ids []uuid.UUID, at time.Time) []Report {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	targets := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if _, ok := m.deleted[id]; ok {
+			continue
+		}
+		m.deleted[id] = at
+		targets[id] = true
+	}
+
+	var reports []Report
+	for _, p := range m.pages {
+		for _, ref := range refsOf(p) {
+			if targets[ref.target] {
+				reports = append(reports, ref.report(at))
+			}
+		}
+	}
+
+	sortReports(reports)
+
+	return reports
+}
+
+// sortReports sorts Reports by Page name, Field, locale and draft before live
+// references.
+func sortReports(reports []Report) {
+	sort.Slice(reports, func(i, j int) bool {
+		a, b := reports[i], reports[j]
+		if a.Page != b.Page {
+			return a.Page < b.Page
+		}
+		if a.PageID != b.PageID {
+			return a.PageID.String() < b.PageID.String()
+		}
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		if a.Locale != b.Locale {
+			return a.Locale < b.Locale
+		}
+		return !a.Live && b.Live
+	})
+}
+
+type reference struct {
+	page   *page.Page
+	field  field.Field
+	locale string
+	value  string
+	live   bool
+	target uuid.UUID
+}
+
+func (ref reference) report(at time.Time) Report {
+	return Report{
+		PageID: ref.page.ID,
+		Page:   ref.page.Name,
+		Field:  ref.field.Name,
+		Type:   ref.field.Type,
+		Locale: ref.locale,
+		Value:  ref.value,
+		Live:   ref.live,
+		Time:   at,
+	}
+}
+
+// refsOf returns the references of the draft Fields of the Page and, if the
+// Page is published, of its live Fields.
+func refsOf(p *page.Page) []reference {
+	refs := fieldRefs(p, p.Fields, false)
+	if p.Published {
+		refs = append(refs, fieldRefs(p, p.LiveFields, true)...)
+	}
+	return refs
+}
+
+func fieldRefs(p *page.Page, fields []field.Field, live bool) []reference {
+	var refs []reference
+	for _, f := range fields {
+		for locale, value := range f.Values {
+			target, ok := targetOf(f.Type, value)
+			if !ok {
+				continue
+			}
+			refs = append(refs, reference{
+				page:   p,
+				field:  f,
+				locale: locale,
+				value:  value,
+				live:   live,
+				target: target,
+			})
+		}
+	}
+	return refs
+}
+
+// targetOf returns the UUID of the Stack or Document that is referenced by
+// value.
+func targetOf(typ field.Type, value string) (uuid.UUID, bool) {
+	switch typ {
+	case field.Image:
+		ref, err := field.ParseImageRef(value)
+		return ref.Stack, err == nil && !ref.IsZero()
+	case field.Document:
+		ref, err := field.ParseDocumentRef(value)
+		return ref.Document, err == nil && !ref.IsZero()
+	default:
+		return uuid.Nil, false
+	}
+}
@@ -0,0 +1,106 @@
+package mediaref_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/internal/testutil"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+	"github.com/modernice/nice-cms/static/page/mediaref"
+)
+
+func TestMonitor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env := testutil.NewEnv()
+
+	var mux sync.Mutex
+	var reports []mediaref.Report
+	m := mediaref.NewMonitor(mediaref.OnBroken(func(_ context.Context, r mediaref.Report) {
+		mux.Lock()
+		defer mux.Unlock()
+		reports = append(reports, r)
+	}))
+	env.Project(ctx, t, m.Project)
+
+	g, stack := env.UploadStack(ctx, t)
+	shelf, doc := env.AddDocument(ctx, t)
+	imageRef := field.ImageRef{Gallery: g.ID, Stack: stack.ID}
+
+	p := page.New(uuid.New())
+	p.Create("foo",
+		field.NewImage("hero", imageRef),
+		field.NewDocument("brochure", field.DocumentRef{Shelf: shelf.ID, Document: doc.ID}),
+	)
+	if err := p.Publish(); err != nil {
+		t.Fatalf("publish Page: %v", err)
+	}
+	if err := env.Pages.Save(ctx, p); err != nil {
+		t.Fatalf("save Page: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	if broken := m.Broken(); len(broken) != 0 {
+		t.Fatalf("Broken should return no Reports; got %v", broken)
+	}
+
+	if err := env.Galleries.Use(ctx, g.ID, func(g *gallery.Gallery) error {
+		return g.Delete(ctx, env.Storage, stack)
+	}); err != nil {
+		t.Fatalf("delete Stack: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	broken := m.Broken()
+	if len(broken) != 2 {
+		t.Fatalf("Broken should return %d Reports; got %v", 2, broken)
+	}
+	for i, live := range []bool{false, true} {
+		r := broken[i]
+		if r.PageID != p.ID || r.Page != "foo" || r.Field != "hero" || r.Type != field.Image ||
+			r.Value != imageRef.String() || r.Live != live || r.Time.IsZero() {
+			t.Fatalf("invalid Report: %v", r)
+		}
+	}
+
+	mux.Lock()
+	if len(reports) != 2 || reports[0] != broken[0] || reports[1] != broken[1] {
+		t.Fatalf("ReportHandler should be called with %v; got %v", broken, reports)
+	}
+	mux.Unlock()
+
+	if err := env.Pages.Use(ctx, p.ID, func(p *page.Page) error {
+		return p.UpdateField("hero", field.ImageRef{})
+	}); err != nil {
+		t.Fatalf("update Field: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	broken = m.Broken()
+	if len(broken) != 1 || !broken[0].Live {
+		t.Fatalf("only the live reference should be broken; got %v", broken)
+	}
+
+	if err := env.Shelfs.Use(ctx, shelf.ID, func(s *document.Shelf) error {
+		return s.Remove(ctx, env.Storage, doc.ID)
+	}); err != nil {
+		t.Fatalf("remove Document: %v", err)
+	}
+
+	<-time.After(50 * time.Millisecond)
+
+	broken = m.Broken()
+	if len(broken) != 3 || broken[0].Field != "brochure" || broken[0].Type != field.Document {
+		t.Fatalf("the references to the removed Document should be broken; got %v", broken)
+	}
+}
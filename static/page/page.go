@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/modernice/goes/aggregate"
 	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/internal/aggregateutil"
 	"github.com/modernice/nice-cms/internal/unique"
 	"github.com/modernice/nice-cms/static/page/field"
 )
@@ -34,6 +36,18 @@ var (
 
 	// ErrGuarded is retured when trying to remove a guarded Field from a Page.
 	ErrGuarded = errors.New("guarded field")
+
+	// ErrAlreadyPublished is returned when trying to publish a Page that is
+	// already published.
+	ErrAlreadyPublished = errors.New("page already published")
+
+	// ErrNotPublished is returned when trying to unpublish a Page that is not
+	// published.
+	ErrNotPublished = errors.New("page not published")
+
+	// ErrInvalidSchedule is returned when scheduling the publishing or
+	// unpublishing of a Page at an invalid time.
+	ErrInvalidSchedule = errors.New("invalid schedule")
 )
 
 // A Repository persists Pages.
@@ -54,12 +68,34 @@ type Repository interface {
 	Delete(context.Context, *Page) error
 }
 
-// Page is a web page.
+// Page is a web page. Changes to the Fields of a Page are made on a draft and
+// go live when the Page is published.
 type Page struct {
 	*aggregate.Base
 
-	Name   string
+	Name string
+
+	// Fields are the draft Fields of the Page.
 	Fields []field.Field
+
+	// LiveFields are the Fields of the Page when it was last published.
+	LiveFields []field.Field
+
+	// Draft is true if the Fields were changed since the Page was last
+	// published.
+	Draft bool
+
+	// Published is true if the Page is published.
+	Published bool
+
+	// PublishedAt is the time at which the Page was last published.
+	PublishedAt time.Time
+
+	// Schedule is the publishing schedule of the Page.
+	Schedule Schedule
+
+	// Revisions are the published versions of the Page, oldest first.
+	Revisions []Revision
 }
 
 // New returns a new Page. You probably want to use Create instead.
@@ -84,6 +120,7 @@ func (p *Page) Field(name string) (field.Field, error) {
 //
 // Fields passed to Create are added to the Page as guarded Fields that cannot
 // be removed. To add removable Fields to a Page p, use p.Add instead:
+//
 //	p := page.New(uuid.New())
 //	p.Create("foo")
 //	p.Add(field.NewText(...), field.NewToggle(...))
@@ -133,7 +170,9 @@ func (p *Page) Add(fields ...field.Field) error {
 
 func (p *Page) addFields(evt event.Event) {
 	data := evt.Data().(FieldsAddedData)
-	p.Fields = append(p.Fields, data.Fields...)
+	// The Fields are copied because UpdateField modifies their Values.
+	p.Fields = append(p.Fields, cloneFields(data.Fields)...)
+	p.Draft = true
 }
 
 // Remove removes the fields with the given names from the page.
@@ -160,6 +199,7 @@ func (p *Page) removeFields(evt event.Event) {
 	for _, name := range data.Fields {
 		p.removeField(name)
 	}
+	p.Draft = true
 }
 
 func (p *Page) removeField(name string) {
@@ -171,24 +211,28 @@ func (p *Page) removeField(name string) {
 	}
 }
 
+// UpdateField updates the value of the Field with the given name for the given
+// locales, or for all locales if none are provided. The value is formatted
+// using field.Format. Values of List fields must match the Schema of the Field
+// and values of Image and Document fields must be valid references, otherwise
+// an error that wraps field.ErrInvalidValue is returned. Use a
+// mediaref.Validator to also check that referenced Stacks and Documents exist.
+// UpdateField doesn't sanitize HTML and Markdown values; HandleCommands
+// sanitizes the values of UpdateFieldCmd (see Sanitize).
 func (p *Page) UpdateField(fieldName string, value any, locales ...string) error {
-	if _, err := p.Field(fieldName); err != nil {
+	f, err := p.Field(fieldName)
+	if err != nil {
 		return err
 	}
 
-	var strval string
-	switch v := value.(type) {
-	case string:
-		strval = v
-	case fmt.Stringer:
-		strval = v.String()
-	default:
-		strval = fmt.Sprint(v)
+	formatted := field.Format(value)
+	if err := f.Validate(formatted); err != nil {
+		return fmt.Errorf("%q: %w", fieldName, err)
 	}
 
 	aggregate.NextEvent(p, FieldUpdated, FieldUpdatedData{
 		Field:   fieldName,
-		Value:   strval,
+		Value:   formatted,
 		Locales: locales,
 	})
 
@@ -217,6 +261,8 @@ func (p *Page) updateField(evt event.Event) {
 	for _, locale := range locales {
 		f.Values[locale] = data.Value
 	}
+
+	p.Draft = true
 }
 
 func (p *Page) checkCreated() error {
@@ -237,69 +283,84 @@ func (p *Page) ApplyEvent(evt event.Event) {
 		p.removeFields(evt)
 	case FieldUpdated:
 		p.updateField(evt)
+	case Published:
+		p.publish(evt)
+	case Unpublished:
+		p.unpublish(evt)
+	case PublishScheduled:
+		p.schedulePublish(evt)
+	case UnpublishScheduled:
+		p.scheduleUnpublish(evt)
+	case ScheduleCanceled:
+		p.cancelSchedule(evt)
+	case RevertedToRevision:
+		p.revertToRevision(evt)
+	case Deleted:
+		p.destroy(evt)
+	case Renamed:
+		p.rename(evt)
 	}
 }
 
-type goesRepository struct {
-	repo aggregate.Repository
-}
-
-// GoesRepository returns a Repository that uses the provided aggregate
-// repository under the hood.
-func GoesRepository(repo aggregate.Repository) Repository {
-	return &goesRepository{repo}
-}
-
-func (r *goesRepository) Save(ctx context.Context, p *Page) error {
-	return r.repo.Save(ctx, p)
-}
-
-func (r *goesRepository) Fetch(ctx context.Context, id uuid.UUID) (*Page, error) {
-	p := New(id)
-	if err := r.repo.Fetch(ctx, p); err != nil {
-		return nil, err
-	}
-	return p, nil
+// GoesRepository returns a Repository that uses an aggregate.Repository under
+// the hood. Repository.Use retries to save the Page when it was changed
+// concurrently; the retries can be tuned with the Retry options (e.g.
+// RetryMaxTries):
+//
+//	pages := page.GoesRepository(repo,
+//		page.RetryMaxTries(10),
+//		page.RetryBackoff(20*time.Millisecond, time.Second),
+//	)
+func GoesRepository(repo aggregate.Repository, opts ...RepositoryOption) Repository {
+	return aggregateutil.NewRepository(repo, "page", New, opts...)
 }
 
-func (r *goesRepository) Use(ctx context.Context, id uuid.UUID, fn func(*Page) error) error {
-	p, err := r.Fetch(ctx, id)
-	if err != nil {
-		return fmt.Errorf("fetch page: %w", err)
-	}
-	if err := fn(p); err != nil {
-		return err
-	}
-	if err := r.Save(ctx, p); err != nil {
-		return fmt.Errorf("save page: %w", err)
+func guarded(fields ...field.Field) []field.Field {
+	out := make([]field.Field, len(fields))
+	for i, f := range fields {
+		f.Guarded = true
+		out[i] = f
 	}
-	return nil
-}
-
-func (r *goesRepository) Delete(ctx context.Context, p *Page) error {
-	return r.repo.Delete(ctx, p)
+	return out
 }
 
-func guarded(fields ...field.Field) []field.Field {
+// cloneFields returns deep copies of the given Fields.
+func cloneFields(fields []field.Field) []field.Field {
 	out := make([]field.Field, len(fields))
 	for i, f := range fields {
-		f.Guarded = true
+		values := make(map[string]string, len(f.Values))
+		for locale, v := range f.Values {
+			values[locale] = v
+		}
+		f.Values = values
 		out[i] = f
 	}
 	return out
 }
 
 type jsonPage struct {
-	ID     uuid.UUID     `json:"id"`
-	Name   string        `json:"name"`
-	Fields []field.Field `json:"fields"`
+	ID          uuid.UUID     `json:"id"`
+	Name        string        `json:"name"`
+	Fields      []field.Field `json:"fields"`
+	LiveFields  []field.Field `json:"liveFields"`
+	Draft       bool          `json:"draft"`
+	Published   bool          `json:"published"`
+	PublishedAt time.Time     `json:"publishedAt"`
+	Schedule    Schedule      `json:"schedule"`
+	Revisions   []Revision    `json:"revisions"`
 }
 
 func (p *Page) MarshalJSON() ([]byte, error) {
 	return json.Marshal(jsonPage{
-		ID:     p.ID,
-		Name:   p.Name,
-		Fields: p.Fields,
+		ID:          p.ID,
+		Name:        p.Name,
+		Fields:      p.Fields,
+		LiveFields:  p.LiveFields,
+		Draft:       p.Draft,
+		Published:   p.Published,
+		PublishedAt: p.PublishedAt,
+		Schedule:    p.Schedule,
+		Revisions:   p.Revisions,
 	})
 }
 
@@ -312,6 +373,12 @@ func (p *Page) UnmarshalJSON(b []byte) error {
 	page.ID = jp.ID
 	page.Name = jp.Name
 	page.Fields = jp.Fields
+	page.LiveFields = jp.LiveFields
+	page.Draft = jp.Draft
+	page.Published = jp.Published
+	page.PublishedAt = jp.PublishedAt
+	page.Schedule = jp.Schedule
+	page.Revisions = jp.Revisions
 	*p = *page
 	return nil
 }
@@ -1,11 +1,16 @@
 package page_test
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/event/eventstore"
 	"github.com/modernice/goes/test"
 	"github.com/modernice/nice-cms/static/page"
 	"github.com/modernice/nice-cms/static/page/field"
@@ -262,3 +267,64 @@ func guarded(fields ...field.Field) []field.Field {
 	}
 	return out
 }
+
+func TestPage_UpdateField_list(t *testing.T) {
+	p := page.New(uuid.New())
+	p.Create("foo", field.NewList("cards", field.Schema{
+		Props: []field.Prop{{Name: "title", Type: field.Text, Required: true}},
+	}, nil))
+
+	if err := p.UpdateField("cards", field.Items{{"subtitle": "Foo"}}); !errors.Is(err, field.ErrInvalidValue) {
+		t.Fatalf("UpdateField should fail with %q; got %q", field.ErrInvalidValue, err)
+	}
+
+	test.NoChange(t, p, page.FieldUpdated)
+
+	cards := field.Items{{"title": "Foo"}, {"title": "Bar"}}
+	if err := p.UpdateField("cards", cards, "de"); err != nil {
+		t.Fatalf("UpdateField failed with %q", err)
+	}
+
+	f, _ := p.Field("cards")
+	items, err := f.Items("de")
+	if err != nil {
+		t.Fatalf("Items failed with %q", err)
+	}
+
+	if len(items) != 2 || items[1]["title"] != "Bar" {
+		t.Fatalf("Items(%q) should return %v; got %v", "de", cards, items)
+	}
+}
+
+func TestGoesRepository_Use_retry(t *testing.T) {
+	ctx := context.Background()
+	repo := &flakyRepository{Repository: repository.New(eventstore.New()), failures: 2}
+	pages := page.GoesRepository(repo, page.RetryBackoff(time.Millisecond, 0))
+
+	var calls int
+	if err := pages.Use(ctx, uuid.New(), func(p *page.Page) error {
+		calls++
+		return p.Create("foo")
+	}); err != nil {
+		t.Fatalf("Use should retry failed saves; failed with %q", err)
+	}
+
+	if calls != 3 {
+		t.Fatalf("fn should be called %d times; was called %d times", 3, calls)
+	}
+}
+
+var errSaveFailed = errors.New("save failed")
+
+type flakyRepository struct {
+	aggregate.Repository
+	failures int
+}
+
+func (r *flakyRepository) Save(ctx context.Context, a aggregate.Aggregate) error {
+	if r.failures > 0 {
+		r.failures--
+		return errSaveFailed
+	}
+	return r.Repository.Save(ctx, a)
+}
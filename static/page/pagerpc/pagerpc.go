@@ -0,0 +1,167 @@
+// Package pagerpc provides the page gRPC server and client.
+package pagerpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/command"
+	"github.com/modernice/goes/command/cmdbus/dispatch"
+	protocommon "github.com/modernice/nice-cms/proto/gen/common/v1"
+	protopage "github.com/modernice/nice-cms/proto/gen/page/v1"
+	"github.com/modernice/nice-cms/proto/ptypes/v1"
+	"github.com/modernice/nice-cms/static/page"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Server is the page gRPC server.
+type Server struct {
+	protopage.UnimplementedPageServiceServer
+
+	pages    page.Repository
+	commands command.Bus
+}
+
+// NewServer returns the page gRPC server. Reverts are dispatched as commands
+// over the given command bus and must be handled by page.HandleCommands.
+func NewServer(pages page.Repository, commands command.Bus) *Server {
+	return &Server{
+		pages:    pages,
+		commands: commands,
+	}
+}
+
+// Register registers the server into a ServiceRegistrar.
+func (s *Server) Register(reg grpc.ServiceRegistrar) {
+	protopage.RegisterPageServiceServer(reg, s)
+}
+
+// ListRevisions returns the Revisions of a Page, oldest first.
+func (s *Server) ListRevisions(ctx context.Context, id *protocommon.UUID) (*protopage.RevisionList, error) {
+	p, err := s.fetchPage(ctx, ptypes.UUID(id))
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*protopage.Revision, len(p.Revisions))
+	for i, rev := range p.Revisions {
+		revisions[i] = ptypes.RevisionProto(rev)
+	}
+
+	return &protopage.RevisionList{Revisions: revisions}, nil
+}
+
+// DiffRevisions returns the changes of the Fields between two Revisions of a
+// Page.
+func (s *Server) DiffRevisions(ctx context.Context, req *protopage.DiffRevisionsReq) (*protopage.DiffRevisionsResp, error) {
+	p, err := s.fetchPage(ctx, ptypes.UUID(req.GetPageId()))
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := p.DiffRevisions(int(req.GetFrom()), int(req.GetTo()))
+	if err != nil {
+		return nil, revisionError(err)
+	}
+
+	resp := make([]*protopage.FieldChange, len(changes))
+	for i, c := range changes {
+		resp[i] = ptypes.FieldChangeProto(c)
+	}
+
+	return &protopage.DiffRevisionsResp{Changes: resp}, nil
+}
+
+// RevertToRevision reverts the draft of a Page to one of its Revisions.
+func (s *Server) RevertToRevision(ctx context.Context, req *protopage.RevertToRevisionReq) (*emptypb.Empty, error) {
+	p, err := s.fetchPage(ctx, ptypes.UUID(req.GetPageId()))
+	if err != nil {
+		return nil, err
+	}
+
+	// Errors of dispatched commands lose their type, so the Revision is
+	// validated before the command is dispatched.
+	number := int(req.GetRevision())
+	if _, err := p.Revision(number); err != nil {
+		return nil, revisionError(err)
+	}
+
+	cmd := page.RevertToRevisionCmd(p.ID, number)
+	if err := s.commands.Dispatch(ctx, cmd.Any(), dispatch.Sync()); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) fetchPage(ctx context.Context, id uuid.UUID) (*page.Page, error) {
+	p, err := s.pages.Fetch(ctx, id)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if p.AggregateVersion() == 0 {
+		return nil, status.Error(codes.NotFound, page.ErrNotCreated.Error())
+	}
+	return p, nil
+}
+
+func revisionError(err error) error {
+	if errors.Is(err, page.ErrRevisionNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// Client is the page gRPC client.
+type Client struct {
+	client protopage.PageServiceClient
+}
+
+// NewClient returns the page gRPC client.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: protopage.NewPageServiceClient(conn)}
+}
+
+// ListRevisions returns the Revisions of a Page, oldest first.
+func (c *Client) ListRevisions(ctx context.Context, pageID uuid.UUID) ([]page.Revision, error) {
+	resp, err := c.client.ListRevisions(ctx, ptypes.UUIDProto(pageID))
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]page.Revision, len(resp.GetRevisions()))
+	for i, rev := range resp.GetRevisions() {
+		revisions[i] = ptypes.Revision(rev)
+	}
+	return revisions, nil
+}
+
+// DiffRevisions returns the changes of the Fields between two Revisions of a
+// Page.
+func (c *Client) DiffRevisions(ctx context.Context, pageID uuid.UUID, from, to int) ([]page.FieldChange, error) {
+	resp, err := c.client.DiffRevisions(ctx, &protopage.DiffRevisionsReq{
+		PageId: ptypes.UUIDProto(pageID),
+		From:   int64(from),
+		To:     int64(to),
+	})
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]page.FieldChange, len(resp.GetChanges()))
+	for i, ch := range resp.GetChanges() {
+		changes[i] = ptypes.FieldChange(ch)
+	}
+	return changes, nil
+}
+
+// RevertToRevision reverts the draft of a Page to one of its Revisions.
+func (c *Client) RevertToRevision(ctx context.Context, pageID uuid.UUID, revision int) error {
+	_, err := c.client.RevertToRevision(ctx, &protopage.RevertToRevisionReq{
+		PageId:   ptypes.UUIDProto(pageID),
+		Revision: int64(revision),
+	})
+	return err
+}
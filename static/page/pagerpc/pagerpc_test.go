@@ -0,0 +1,102 @@
+package pagerpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/command/cmdbus"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/commands"
+	"github.com/modernice/nice-cms/internal/discard"
+	"github.com/modernice/nice-cms/internal/grpctest"
+	protopage "github.com/modernice/nice-cms/proto/gen/page/v1"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+	"github.com/modernice/nice-cms/static/page/pagerpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer_revisions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	cbus := cmdbus.New(commands.NewRegistry(), ebus)
+	pages := page.GoesRepository(repository.New(estore))
+
+	go discard.Errors(page.HandleCommands(ctx, cbus, pages))
+
+	p := page.New(uuid.New())
+	p.Create("foo", field.NewText("title", "Foo"), field.NewList("cards", field.Schema{
+		Props:    []field.Prop{{Name: "title", Type: field.Text, Required: true}},
+		MaxItems: 3,
+	}, field.Items{{"title": "Card"}}))
+	p.Publish()
+	p.UpdateField("title", "Bar")
+	p.Publish()
+	if err := pages.Save(ctx, p); err != nil {
+		t.Fatalf("save Page: %v", err)
+	}
+
+	_, dial := grpctest.NewServer(func(s *grpc.Server) {
+		protopage.RegisterPageServiceServer(s, pagerpc.NewServer(pages, cbus))
+	})
+	conn := dial()
+	defer conn.Close()
+
+	client := pagerpc.NewClient(conn)
+
+	revisions, err := client.ListRevisions(ctx, p.ID)
+	if err != nil {
+		t.Fatalf("ListRevisions failed with %q", err)
+	}
+	if len(revisions) != 2 || revisions[0].Fields[0].Value("") != "Foo" {
+		t.Fatalf("invalid Revisions: %v", revisions)
+	}
+	if !cmp.Equal(p.Revisions[0].Fields[1], revisions[0].Fields[1]) {
+		t.Fatalf("list Field should be encoded with its Schema.\n\n%s", cmp.Diff(p.Revisions[0].Fields[1], revisions[0].Fields[1]))
+	}
+	if !revisions[1].PublishedAt.Equal(p.Revisions[1].PublishedAt.Truncate(1e6)) {
+		t.Fatalf("PublishedAt should be %v; is %v", p.Revisions[1].PublishedAt, revisions[1].PublishedAt)
+	}
+
+	changes, err := client.DiffRevisions(ctx, p.ID, 1, 2)
+	if err != nil {
+		t.Fatalf("DiffRevisions failed with %q", err)
+	}
+	want := page.FieldChange{Field: "title", Change: page.FieldChanged, Old: "Foo", New: "Bar"}
+	if len(changes) != 1 || changes[0] != want {
+		t.Fatalf("DiffRevisions should return %v; got %v", []page.FieldChange{want}, changes)
+	}
+
+	if _, err := client.DiffRevisions(ctx, p.ID, 1, 3); status.Code(err) != codes.NotFound {
+		t.Fatalf("DiffRevisions should fail with %q for an unknown Revision; got %q", codes.NotFound, err)
+	}
+
+	if err := client.RevertToRevision(ctx, p.ID, 3); status.Code(err) != codes.NotFound {
+		t.Fatalf("RevertToRevision should fail with %q for an unknown Revision; got %q", codes.NotFound, err)
+	}
+
+	if err := client.RevertToRevision(ctx, p.ID, 1); err != nil {
+		t.Fatalf("RevertToRevision failed with %q", err)
+	}
+
+	reverted, err := pages.Fetch(ctx, p.ID)
+	if err != nil {
+		t.Fatalf("fetch Page: %v", err)
+	}
+	if v := reverted.Fields[0].Value(""); v != "Foo" {
+		t.Fatalf("draft should be reverted to %q; is %q", "Foo", v)
+	}
+
+	if _, err := client.ListRevisions(ctx, uuid.New()); status.Code(err) != codes.NotFound {
+		t.Fatalf("ListRevisions should fail with %q for an unknown Page; got %q", codes.NotFound, err)
+	}
+}
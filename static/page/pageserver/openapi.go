@@ -0,0 +1,152 @@
+package pageserver
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/modernice/nice-cms/internal/api"
+	"github.com/modernice/nice-cms/openapi"
+	"github.com/modernice/nice-cms/schema"
+	"github.com/modernice/nice-cms/static/page"
+)
+
+// WithOpenAPI returns an Option that installs the OpenAPI route, which serves
+// the OpenAPI document of the Server (see Server.OpenAPI). The document can
+// also be merged into the document of the media server:
+//
+//	srv := pageserver.New(pages, pageserver.WithPreview(secret))
+//	mediaserver.WithOpenAPI(info, []*openapi.Document{srv.OpenAPI(openapi.Info{})})
+func WithOpenAPI(info openapi.Info) Option {
+	return func(s *Server) {
+		s.openapi = &info
+	}
+}
+
+// OpenAPI returns the OpenAPI document of the routes that are installed in the
+// Server. Routes that are not installed, e.g. the preview route without
+// WithPreview, are not described.
+func (s *Server) OpenAPI(info openapi.Info) *openapi.Document {
+	doc := openapi.New(info)
+	doc.AddTag(openapi.Tag{Name: "pages", Description: "Pages and their revisions."})
+	ops := operations()
+
+	chi.Walk(s.router, func(method, path string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		op, ok := ops[route{method, path}]
+		if !ok {
+			return nil
+		}
+		doc.Add(method, path, op)
+		return nil
+	})
+
+	return doc
+}
+
+// showOpenAPI responds with the OpenAPI document of the Server.
+func (s *Server) showOpenAPI(w http.ResponseWriter, r *http.Request) {
+	api.JSON(w, r, http.StatusOK, s.OpenAPI(*s.openapi))
+}
+
+type route struct {
+	method string
+	path   string
+}
+
+var pathParams = map[string]string{
+	"PageID":   "UUID of the page.",
+	"Revision": "Number of the revision.",
+}
+
+// operations returns the descriptions of the routes of the Server.
+func operations() map[route]openapi.Operation {
+	viewSchema := schema.Of(page.View{})
+	localeParam := openapi.QueryParam("locale", "Locale of the field values. Fields without a value for the locale fall back to the parent and fallback locales.", &schema.Schema{Type: "string"})
+
+	ops := map[route]openapi.Operation{
+		{http.MethodGet, ShowPageRoute}: {
+			OperationID: "showPage",
+			Summary:     "Show page",
+			Description: "Responds with the published content of the page. With the locale parameter, the fields have the values of that locale.",
+			Tags:        []string{"pages"},
+			Parameters:  []openapi.Parameter{localeParam},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The published page.", viewSchema)},
+		},
+		{http.MethodGet, PreviewPageRoute}: {
+			OperationID: "previewPage",
+			Summary:     "Preview page",
+			Description: "Responds with the draft content of the page. With the locale parameter, the fields have the values of that locale.",
+			Tags:        []string{"pages"},
+			Parameters: []openapi.Parameter{
+				required(openapi.QueryParam("token", "Preview token of the page.", &schema.Schema{Type: "string"})),
+				localeParam,
+			},
+			Responses: openapi.Responses{"200": openapi.JSONResponse("The draft of the page.", viewSchema)},
+		},
+		{http.MethodGet, RevisionsRoute}: {
+			OperationID: "listPageRevisions",
+			Summary:     "List page revisions",
+			Tags:        []string{"pages"},
+			Responses: openapi.Responses{"200": openapi.JSONResponse("The revisions of the page, oldest first.", &schema.Schema{
+				Type:  "array",
+				Items: schema.Of(page.Revision{}),
+			})},
+		},
+		{http.MethodGet, DiffRoute}: {
+			OperationID: "diffPageRevisions",
+			Summary:     "Diff page revisions",
+			Tags:        []string{"pages"},
+			Parameters: []openapi.Parameter{
+				required(openapi.QueryParam("from", "Number of the revision to diff from.", &schema.Schema{Type: "integer"})),
+				required(openapi.QueryParam("to", "Number of the revision to diff to.", &schema.Schema{Type: "integer"})),
+			},
+			Responses: openapi.Responses{"200": openapi.JSONResponse("The changes of the fields between the revisions.", &schema.Schema{
+				Type:  "array",
+				Items: schema.Of(page.FieldChange{}),
+			})},
+		},
+		{http.MethodPost, RevertRoute}: {
+			OperationID: "revertPageRevision",
+			Summary:     "Revert page to revision",
+			Tags:        []string{"pages"},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The reverted draft of the page.", viewSchema)},
+		},
+		{http.MethodGet, SchemaRoute}: {
+			OperationID: "showPageSchema",
+			Summary:     "Show page schema",
+			Tags:        []string{"pages"},
+			Responses: openapi.Responses{"200": openapi.JSONResponse("The JSON Schema of the field values of the page.", &schema.Schema{
+				Type:        "object",
+				Description: "A JSON Schema.",
+			})},
+		},
+		{http.MethodGet, OpenAPIRoute}: {
+			OperationID: "showPageOpenAPI",
+			Summary:     "Show page OpenAPI document",
+			Tags:        []string{"pages"},
+			Responses:   openapi.Responses{"200": openapi.JSONResponse("The OpenAPI document.", &schema.Schema{Type: "object"})},
+		},
+	}
+
+	errorResponse := openapi.JSONResponse("Error.", &schema.Schema{
+		Type:       "object",
+		Properties: map[string]*schema.Schema{"error": {Type: "string"}},
+		Required:   []string{"error"},
+	})
+
+	for r, op := range ops {
+		var params []openapi.Parameter
+		for _, name := range openapi.PathParams(r.path) {
+			params = append(params, openapi.PathParam(name, pathParams[name]))
+		}
+		op.Parameters = append(params, op.Parameters...)
+		op.Responses["default"] = errorResponse
+		ops[r] = op
+	}
+
+	return ops
+}
+
+func required(p openapi.Parameter) openapi.Parameter {
+	p.Required = true
+	return p
+}
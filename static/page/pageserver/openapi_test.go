@@ -0,0 +1,74 @@
+package pageserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/modernice/goes/command/cmdbus"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/nice-cms/internal/commands"
+	"github.com/modernice/nice-cms/openapi"
+	"github.com/modernice/nice-cms/static/page/pageserver"
+)
+
+func TestWithOpenAPI(t *testing.T) {
+	srv := pageserver.New(
+		nil,
+		pageserver.WithRevisions(cmdbus.New(commands.NewRegistry(), eventbus.New())),
+		pageserver.WithSchema(),
+		pageserver.WithOpenAPI(openapi.Info{Title: "foo", Version: "1.0.0"}),
+	)
+
+	rec := get(srv, pageserver.OpenAPIRoute)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+
+	var doc openapi.Document
+	if err := json.NewDecoder(rec.Body).Decode(&doc); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if doc.Info.Title != "foo" {
+		t.Fatalf("document should have title %q; got %q", "foo", doc.Info.Title)
+	}
+
+	for _, route := range []struct{ method, path string }{
+		{"GET", pageserver.ShowPageRoute},
+		{"GET", pageserver.RevisionsRoute},
+		{"GET", pageserver.DiffRoute},
+		{"POST", pageserver.RevertRoute},
+		{"GET", pageserver.SchemaRoute},
+		{"GET", pageserver.OpenAPIRoute},
+	} {
+		op, ok := doc.Operation(route.method, route.path)
+		if !ok {
+			t.Errorf("document should describe %s %s", route.method, route.path)
+			continue
+		}
+
+		if op.OperationID == "" || len(op.Responses) < 2 {
+			t.Errorf("%s %s should have an operation ID and responses; got %v", route.method, route.path, op)
+		}
+
+		for _, name := range openapi.PathParams(route.path) {
+			if !hasPathParam(op.Parameters, name) {
+				t.Errorf("%s %s should describe the %q path parameter", route.method, route.path, name)
+			}
+		}
+	}
+
+	if _, ok := doc.Operation("GET", pageserver.PreviewPageRoute); ok {
+		t.Errorf("document should not describe routes that are not installed")
+	}
+}
+
+func hasPathParam(params []openapi.Parameter, name string) bool {
+	for _, p := range params {
+		if p.Name == name && p.In == "path" && p.Required {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,400 @@
+// Package pageserver serves Pages over HTTP. Visitors get the published
+// content of a Page; editors can preview the draft of a Page using a signed
+// preview token (see page.SignPreview) and manage the Revisions of a Page.
+//
+//	srv := pageserver.New(pages, pageserver.WithPreview(secret), pageserver.WithRevisions(commands))
+//	http.ListenAndServe(":8000", srv)
+//
+//	// GET  /pages/{PageID}?locale=de
+//	// GET  /pages/{PageID}/preview?token=...&locale=de
+//	// GET  /pages/{PageID}/revisions
+//	// GET  /pages/{PageID}/revisions/diff?from=1&to=2
+//	// POST /pages/{PageID}/revisions/{Revision}/revert
+//	// GET  /pages/{PageID}/schema
+//	// GET  /pages/openapi.json
+//
+// If the "locale" query parameter is set, Pages are served with the Field values
+// of that locale. Fields without a value for the locale fall back to the
+// parent locales, the fallback locales of the Server and the default value.
+//
+// If the Server has a Resolver, the references of Image and Document fields are
+// resolved and served in the "resolved" object of the response, keyed by Field
+// name (and locale if the Page is not localized).
+//
+// The revision and schema routes are not protected by the Server. Protect them
+// with an authentication middleware.
+package pageserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/modernice/goes/command"
+	"github.com/modernice/goes/command/cmdbus/dispatch"
+	"github.com/modernice/nice-cms/internal/api"
+	"github.com/modernice/nice-cms/openapi"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+// Routes of the Server.
+const (
+	ShowPageRoute    = "/pages/{PageID}"
+	PreviewPageRoute = "/pages/{PageID}/preview"
+	RevisionsRoute   = "/pages/{PageID}/revisions"
+	DiffRoute        = "/pages/{PageID}/revisions/diff"
+	RevertRoute      = "/pages/{PageID}/revisions/{Revision}/revert"
+	SchemaRoute      = "/pages/{PageID}/schema"
+	OpenAPIRoute     = "/pages/openapi.json"
+)
+
+// Option is a Server option.
+type Option func(*Server)
+
+// WithPreview returns an Option that installs the preview route. Preview
+// tokens are verified with the given secret.
+func WithPreview(secret []byte) Option {
+	return func(s *Server) {
+		s.previewSecret = secret
+	}
+}
+
+// WithFallbackLocales returns an Option that sets the locales that are tried
+// when a Field has no value for the requested locale or its parent locales.
+func WithFallbackLocales(locales ...string) Option {
+	return func(s *Server) {
+		s.fallbacks = append(s.fallbacks, locales...)
+	}
+}
+
+// WithRevisions returns an Option that installs the revision routes. Reverts
+// are dispatched as commands over the given command bus and must be handled by
+// page.HandleCommands.
+func WithRevisions(commands command.Bus) Option {
+	return func(s *Server) {
+		s.commands = commands
+	}
+}
+
+// WithSchema returns an Option that installs the schema route, which serves the
+// JSON Schema of the Field values of a Page (see page.Page.Schema), so that
+// generic admin UIs can build forms for Pages.
+func WithSchema() Option {
+	return func(s *Server) {
+		s.schema = true
+	}
+}
+
+// A Resolver resolves the value of a Field, e.g. the reference of an Image
+// field to the referenced Stack (see mediaref.Resolver). Resolve returns nil if
+// the value has nothing to resolve.
+type Resolver interface {
+	Resolve(context.Context, field.Field, string) (any, error)
+}
+
+// WithResolver returns an Option that resolves the Field values of served
+// Pages using the given Resolver.
+func WithResolver(r Resolver) Option {
+	return func(s *Server) {
+		s.resolver = r
+	}
+}
+
+// Server serves Pages over HTTP.
+type Server struct {
+	router        chi.Router
+	pages         page.Repository
+	previewSecret []byte
+	commands      command.Bus
+	fallbacks     []string
+	resolver      Resolver
+	schema        bool
+	openapi       *openapi.Info
+}
+
+// New returns a Server that serves the Pages of the given Repository.
+func New(pages page.Repository, opts ...Option) *Server {
+	s := Server{
+		router: chi.NewRouter(),
+		pages:  pages,
+	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	s.router.Get(ShowPageRoute, api.BindUUIDs(http.HandlerFunc(s.showPage)).ServeHTTP)
+	if s.previewSecret != nil {
+		s.router.Get(PreviewPageRoute, api.BindUUIDs(http.HandlerFunc(s.previewPage)).ServeHTTP)
+	}
+	if s.commands != nil {
+		s.router.Get(RevisionsRoute, api.BindUUIDs(http.HandlerFunc(s.listRevisions)).ServeHTTP)
+		s.router.Get(DiffRoute, api.BindUUIDs(http.HandlerFunc(s.diffRevisions)).ServeHTTP)
+		s.router.Post(RevertRoute, api.BindUUIDs(http.HandlerFunc(s.revertToRevision)).ServeHTTP)
+	}
+	if s.schema {
+		s.router.Get(SchemaRoute, api.BindUUIDs(http.HandlerFunc(s.showSchema)).ServeHTTP)
+	}
+	if s.openapi != nil {
+		s.router.Get(OpenAPIRoute, s.showOpenAPI)
+	}
+
+	return &s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.router.ServeHTTP(w, r)
+}
+
+// showPage responds with the published content of a Page. Pages that are not
+// published are not found.
+func (s *Server) showPage(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.fetchPage(w, r)
+	if !ok {
+		return
+	}
+
+	view, err := p.Live()
+	if err != nil {
+		api.Error(w, r, http.StatusNotFound, api.Friendly(err, "Page %q not found.", p.ID))
+		return
+	}
+
+	s.respondView(w, r, view)
+}
+
+// previewPage responds with the draft content of a Page if the request has a
+// valid preview token in its "token" query parameter. Previews must not be
+// cached, because they may contain unpublished content.
+func (s *Server) previewPage(w http.ResponseWriter, r *http.Request) {
+	id := api.UUIDParam(r, "PageID")
+	if err := page.VerifyPreview(s.previewSecret, id, r.URL.Query().Get("token")); err != nil {
+		api.Error(w, r, http.StatusForbidden, api.Friendly(err, "Invalid preview token: %v", err))
+		return
+	}
+
+	p, ok := s.fetchPage(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	s.respondView(w, r, p.Preview())
+}
+
+// respondView responds with the View, localized to the locale in the "locale"
+// query parameter if it is set.
+func (s *Server) respondView(w http.ResponseWriter, r *http.Request, view page.View) {
+	query := r.URL.Query()
+	if !query.Has("locale") {
+		resolved, err := s.resolveView(r.Context(), view)
+		if err != nil {
+			api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to resolve page %q: %v", view.ID, err))
+			return
+		}
+		if resolved == nil {
+			api.JSON(w, r, http.StatusOK, view)
+			return
+		}
+		api.JSON(w, r, http.StatusOK, resolvedView{View: view, Resolved: resolved})
+		return
+	}
+
+	locale := query.Get("locale")
+	localized := view.Localize(locale, s.fallbacks...)
+
+	resolved, err := s.resolveLocalized(r.Context(), view, localized)
+	if err != nil {
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to resolve page %q: %v", view.ID, err))
+		return
+	}
+
+	if locale != "" {
+		w.Header().Set("Content-Language", locale)
+	}
+
+	if resolved == nil {
+		api.JSON(w, r, http.StatusOK, localized)
+		return
+	}
+	api.JSON(w, r, http.StatusOK, resolvedLocalizedView{LocalizedView: localized, Resolved: resolved})
+}
+
+type resolvedView struct {
+	page.View
+	Resolved map[string]map[string]any `json:"resolved"`
+}
+
+type resolvedLocalizedView struct {
+	page.LocalizedView
+	Resolved map[string]any `json:"resolved"`
+}
+
+// resolveView resolves the Field values of all locales of the View. Fields
+// without resolved values are omitted. resolveView returns nil if the Server
+// has no Resolver.
+func (s *Server) resolveView(ctx context.Context, view page.View) (map[string]map[string]any, error) {
+	if s.resolver == nil {
+		return nil, nil
+	}
+
+	resolved := make(map[string]map[string]any)
+	for _, f := range view.Fields {
+		for locale, value := range f.Values {
+			v, err := s.resolver.Resolve(ctx, f, value)
+			if err != nil {
+				return nil, fmt.Errorf("resolve %q field: %w", f.Name, err)
+			}
+			if v == nil {
+				continue
+			}
+			if resolved[f.Name] == nil {
+				resolved[f.Name] = make(map[string]any)
+			}
+			resolved[f.Name][locale] = v
+		}
+	}
+
+	return resolved, nil
+}
+
+// resolveLocalized resolves the Field values of the localized View. Fields
+// without resolved values are omitted. resolveLocalized returns nil if the
+// Server has no Resolver.
+func (s *Server) resolveLocalized(ctx context.Context, view page.View, localized page.LocalizedView) (map[string]any, error) {
+	if s.resolver == nil {
+		return nil, nil
+	}
+
+	resolved := make(map[string]any)
+	for i, f := range localized.Fields {
+		v, err := s.resolver.Resolve(ctx, view.Fields[i], f.Value)
+		if err != nil {
+			return nil, fmt.Errorf("resolve %q field: %w", f.Name, err)
+		}
+		if v != nil {
+			resolved[f.Name] = v
+		}
+	}
+
+	return resolved, nil
+}
+
+// listRevisions responds with the Revisions of a Page, oldest first.
+func (s *Server) listRevisions(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.fetchPage(w, r)
+	if !ok {
+		return
+	}
+
+	revisions := p.Revisions
+	if revisions == nil {
+		revisions = make([]page.Revision, 0)
+	}
+
+	api.JSON(w, r, http.StatusOK, revisions)
+}
+
+// showSchema responds with the JSON Schema of the Field values of a Page.
+func (s *Server) showSchema(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.fetchPage(w, r)
+	if !ok {
+		return
+	}
+
+	api.JSON(w, r, http.StatusOK, p.Schema())
+}
+
+// diffRevisions responds with the changes of the Fields between the Revisions
+// in the "from" and "to" query parameters.
+func (s *Server) diffRevisions(w http.ResponseWriter, r *http.Request) {
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(err, "Invalid %q revision: %v", "from", err))
+		return
+	}
+
+	to, err := strconv.Atoi(r.URL.Query().Get("to"))
+	if err != nil {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(err, "Invalid %q revision: %v", "to", err))
+		return
+	}
+
+	p, ok := s.fetchPage(w, r)
+	if !ok {
+		return
+	}
+
+	changes, err := p.DiffRevisions(from, to)
+	if err != nil {
+		api.Error(w, r, revisionStatus(err), api.Friendly(err, "Failed to diff revisions: %v", err))
+		return
+	}
+
+	api.JSON(w, r, http.StatusOK, changes)
+}
+
+// revertToRevision reverts the draft of a Page to the Revision in the URL and
+// responds with the preview of the reverted draft.
+func (s *Server) revertToRevision(w http.ResponseWriter, r *http.Request) {
+	number, err := strconv.Atoi(chi.URLParam(r, "Revision"))
+	if err != nil {
+		api.Error(w, r, http.StatusBadRequest, api.Friendly(err, "Invalid revision: %v", err))
+		return
+	}
+
+	p, ok := s.fetchPage(w, r)
+	if !ok {
+		return
+	}
+
+	// Errors of dispatched commands lose their type, so the Revision is
+	// validated before the command is dispatched.
+	if _, err := p.Revision(number); err != nil {
+		api.Error(w, r, revisionStatus(err), api.Friendly(err, "Revision %d of page %q not found.", number, p.ID))
+		return
+	}
+
+	cmd := page.RevertToRevisionCmd(p.ID, number)
+	if err := s.commands.Dispatch(r.Context(), cmd.Any(), dispatch.Sync()); err != nil {
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to revert page %q to revision %d: %v", p.ID, number, err))
+		return
+	}
+
+	if p, ok = s.fetchPage(w, r); !ok {
+		return
+	}
+
+	api.JSON(w, r, http.StatusOK, p.Preview())
+}
+
+// revisionStatus returns 404 Not Found if err is page.ErrRevisionNotFound and
+// 500 Internal Server Error otherwise.
+func revisionStatus(err error) int {
+	if errors.Is(err, page.ErrRevisionNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// fetchPage fetches the Page from the PageID URL parameter. If the Page cannot
+// be fetched or wasn't created, an error response is written and false is
+// returned.
+func (s *Server) fetchPage(w http.ResponseWriter, r *http.Request) (*page.Page, bool) {
+	id := api.UUIDParam(r, "PageID")
+	p, err := s.pages.Fetch(r.Context(), id)
+	if err != nil {
+		api.Error(w, r, http.StatusInternalServerError, api.Friendly(err, "Failed to fetch page %q: %v", id, err))
+		return nil, false
+	}
+
+	if p.Name == "" {
+		api.Error(w, r, http.StatusNotFound, api.Friendly(page.ErrNotCreated, "Page %q not found.", id))
+		return nil, false
+	}
+
+	return p, true
+}
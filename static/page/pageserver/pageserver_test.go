@@ -0,0 +1,306 @@
+package pageserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/command/cmdbus"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/nice-cms/internal/commands"
+	"github.com/modernice/nice-cms/internal/discard"
+	"github.com/modernice/nice-cms/schema"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+	"github.com/modernice/nice-cms/static/page/pageserver"
+)
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	pages := page.GoesRepository(repository.New(eventstore.New()))
+	secret := []byte("secret")
+
+	p := page.New(uuid.New())
+	p.Create("foo", field.NewText("title", "Foo"))
+	if err := pages.Save(ctx, p); err != nil {
+		t.Fatalf("save Page: %v", err)
+	}
+
+	srv := pageserver.New(pages, pageserver.WithPreview(secret))
+
+	if rec := get(srv, "/pages/"+p.ID.String()); rec.Code != http.StatusNotFound {
+		t.Fatalf("unpublished Page should not be found; status is %d", rec.Code)
+	}
+
+	if err := pages.Use(ctx, p.ID, func(p *page.Page) error {
+		if err := p.Publish(); err != nil {
+			return err
+		}
+		return p.UpdateField("title", "Bar")
+	}); err != nil {
+		t.Fatalf("publish Page: %v", err)
+	}
+
+	var view page.View
+	rec := get(srv, "/pages/"+p.ID.String())
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+	json.NewDecoder(rec.Body).Decode(&view)
+	if view.Preview || view.Fields[0].Value("") != "Foo" {
+		t.Fatalf("Server should serve the published Fields; got %v", view)
+	}
+
+	token := page.SignPreview(secret, p.ID, time.Minute)
+	rec = get(srv, "/pages/"+p.ID.String()+"/preview?token="+url.QueryEscape(token))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+	if cc := rec.Header().Get("Cache-Control"); cc != "no-store" {
+		t.Fatalf("previews should not be cached; Cache-Control is %q", cc)
+	}
+	json.NewDecoder(rec.Body).Decode(&view)
+	if !view.Preview || view.Fields[0].Value("") != "Bar" {
+		t.Fatalf("preview should serve the draft Fields; got %v", view)
+	}
+
+	other := page.SignPreview(secret, uuid.New(), time.Minute)
+	if rec := get(srv, "/pages/"+p.ID.String()+"/preview?token="+url.QueryEscape(other)); rec.Code != http.StatusForbidden {
+		t.Fatalf("preview with a token of another Page should be forbidden; status is %d", rec.Code)
+	}
+
+	if rec := get(srv, "/pages/"+uuid.NewString()); rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown Page should not be found; status is %d", rec.Code)
+	}
+}
+
+func TestServer_locale(t *testing.T) {
+	ctx := context.Background()
+	pages := page.GoesRepository(repository.New(eventstore.New()))
+
+	p := page.New(uuid.New())
+	p.Create("foo", field.NewText("title", "Hello"), field.NewText("subtitle", "World"))
+	p.SetField("de", "title", "Hallo")
+	p.SetField("fr", "subtitle", "Monde")
+	p.Publish()
+	if err := pages.Save(ctx, p); err != nil {
+		t.Fatalf("save Page: %v", err)
+	}
+
+	srv := pageserver.New(pages, pageserver.WithFallbackLocales("fr"))
+
+	var view page.LocalizedView
+	rec := get(srv, "/pages/"+p.ID.String()+"?locale=de-CH")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+	if cl := rec.Header().Get("Content-Language"); cl != "de-CH" {
+		t.Fatalf("Content-Language should be %q; is %q", "de-CH", cl)
+	}
+	json.NewDecoder(rec.Body).Decode(&view)
+
+	want := []page.LocalizedField{
+		{Name: "title", Type: field.Text, Value: "Hallo"},
+		{Name: "subtitle", Type: field.Text, Value: "Monde"},
+	}
+	if view.Locale != "de-CH" || len(view.Fields) != 2 || view.Fields[0] != want[0] || view.Fields[1] != want[1] {
+		t.Fatalf("Server should serve the Fields of the locale with fallbacks; got %v", view)
+	}
+}
+
+func TestServer_resolver(t *testing.T) {
+	ctx := context.Background()
+	pages := page.GoesRepository(repository.New(eventstore.New()))
+
+	en := field.ImageRef{Gallery: uuid.New(), Stack: uuid.New()}
+	de := field.ImageRef{Gallery: uuid.New(), Stack: uuid.New()}
+
+	p := page.New(uuid.New())
+	p.Create("foo", field.NewText("title", "Hello"), field.NewImage("hero", en))
+	p.SetField("de", "hero", de)
+	p.Publish()
+	if err := pages.Save(ctx, p); err != nil {
+		t.Fatalf("save Page: %v", err)
+	}
+
+	srv := pageserver.New(pages, pageserver.WithResolver(stackResolver{}))
+
+	var view struct {
+		page.View
+		Resolved map[string]map[string]stack `json:"resolved"`
+	}
+	rec := get(srv, "/pages/"+p.ID.String())
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+	json.NewDecoder(rec.Body).Decode(&view)
+
+	if len(view.Fields) != 2 {
+		t.Fatalf("Server should serve the Fields of the Page; got %v", view.Fields)
+	}
+	if len(view.Resolved) != 1 || view.Resolved["hero"][""].ID != en.Stack || view.Resolved["hero"]["de"].ID != de.Stack {
+		t.Fatalf("Server should serve the resolved values of all locales; got %v", view.Resolved)
+	}
+
+	var localized struct {
+		page.LocalizedView
+		Resolved map[string]stack `json:"resolved"`
+	}
+	rec = get(srv, "/pages/"+p.ID.String()+"?locale=de")
+	json.NewDecoder(rec.Body).Decode(&localized)
+
+	if localized.Locale != "de" || len(localized.Fields) != 2 {
+		t.Fatalf("Server should serve the localized Fields; got %v", localized.LocalizedView)
+	}
+	if len(localized.Resolved) != 1 || localized.Resolved["hero"].ID != de.Stack {
+		t.Fatalf("Server should serve the resolved values of the locale; got %v", localized.Resolved)
+	}
+}
+
+func TestServer_withoutPreview(t *testing.T) {
+	pages := page.GoesRepository(repository.New(eventstore.New()))
+	srv := pageserver.New(pages)
+
+	id := uuid.New()
+	token := page.SignPreview(nil, id, time.Minute)
+	if rec := get(srv, "/pages/"+id.String()+"/preview?token="+url.QueryEscape(token)); rec.Code != http.StatusNotFound {
+		t.Fatalf("preview route should not be installed without WithPreview; status is %d", rec.Code)
+	}
+}
+
+func TestServer_schema(t *testing.T) {
+	ctx := context.Background()
+	pages := page.GoesRepository(repository.New(eventstore.New()))
+
+	p := page.New(uuid.New())
+	p.Create("foo", field.NewText("title", "Foo"), field.NewToggle("visible", true, field.Guarded()))
+	if err := pages.Save(ctx, p); err != nil {
+		t.Fatalf("save Page: %v", err)
+	}
+
+	if rec := get(pageserver.New(pages), "/pages/"+p.ID.String()+"/schema"); rec.Code != http.StatusNotFound {
+		t.Fatalf("schema route should not be installed without WithSchema; status is %d", rec.Code)
+	}
+
+	rec := get(pageserver.New(pages, pageserver.WithSchema()), "/pages/"+p.ID.String()+"/schema")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+
+	var s schema.Schema
+	json.NewDecoder(rec.Body).Decode(&s)
+	if s.Title != "foo" || s.Properties["title"].Type != "string" || s.Properties["visible"].Type != "boolean" {
+		t.Fatalf("Server should serve the schema of the Field values of the Page; got %+v", s)
+	}
+}
+
+func TestServer_revisions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	cbus := cmdbus.New(commands.NewRegistry(), ebus)
+	pages := page.GoesRepository(repository.New(estore))
+
+	go discard.Errors(page.HandleCommands(ctx, cbus, pages))
+
+	p := page.New(uuid.New())
+	p.Create("foo", field.NewText("title", "Foo"))
+	p.Publish()
+	p.UpdateField("title", "Bar")
+	p.Publish()
+	if err := pages.Save(ctx, p); err != nil {
+		t.Fatalf("save Page: %v", err)
+	}
+
+	srv := pageserver.New(pages, pageserver.WithRevisions(cbus))
+	base := "/pages/" + p.ID.String() + "/revisions"
+
+	var revisions []page.Revision
+	rec := get(srv, base)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+	json.NewDecoder(rec.Body).Decode(&revisions)
+	if len(revisions) != 2 || revisions[1].Number != 2 {
+		t.Fatalf("Server should list %d Revisions; got %v", 2, revisions)
+	}
+
+	var changes []page.FieldChange
+	rec = get(srv, base+"/diff?from=1&to=2")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+	json.NewDecoder(rec.Body).Decode(&changes)
+	if len(changes) != 1 || changes[0].Old != "Foo" || changes[0].New != "Bar" {
+		t.Fatalf("invalid diff: %v", changes)
+	}
+
+	if rec := get(srv, base+"/diff?from=1&to=3"); rec.Code != http.StatusNotFound {
+		t.Fatalf("diff with an unknown Revision should not be found; status is %d", rec.Code)
+	}
+
+	if rec := get(srv, base+"/diff?from=1"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("diff without a %q Revision should be a bad request; status is %d", "to", rec.Code)
+	}
+
+	if rec := post(srv, base+"/3/revert"); rec.Code != http.StatusNotFound {
+		t.Fatalf("revert to an unknown Revision should not be found; status is %d", rec.Code)
+	}
+
+	var view page.View
+	rec = post(srv, base+"/1/revert")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status should be %d; is %d (%s)", http.StatusOK, rec.Code, rec.Body)
+	}
+	json.NewDecoder(rec.Body).Decode(&view)
+	if !view.Preview || view.Fields[0].Value("") != "Foo" {
+		t.Fatalf("revert should respond with the reverted draft; got %v", view)
+	}
+
+	reverted, err := pages.Fetch(ctx, p.ID)
+	if err != nil {
+		t.Fatalf("fetch Page: %v", err)
+	}
+	if v := reverted.Fields[0].Value(""); v != "Foo" || !reverted.Draft {
+		t.Fatalf("draft should be reverted to %q; is %q (Draft=%v)", "Foo", v, reverted.Draft)
+	}
+}
+
+type stack struct {
+	ID uuid.UUID `json:"id"`
+}
+
+// stackResolver resolves Image fields to fake Stacks.
+type stackResolver struct{}
+
+func (stackResolver) Resolve(_ context.Context, f field.Field, value string) (any, error) {
+	if f.Type != field.Image {
+		return nil, nil
+	}
+	ref, err := field.ParseImageRef(value)
+	if err != nil || ref.IsZero() {
+		return nil, err
+	}
+	return stack{ID: ref.Stack}, nil
+}
+
+func get(h http.Handler, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func post(h http.Handler, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+	return rec
+}
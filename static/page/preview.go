@@ -0,0 +1,99 @@
+package page
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+var (
+	// ErrInvalidPreviewToken is returned when verifying a preview token with an
+	// invalid signature.
+	ErrInvalidPreviewToken = errors.New("invalid preview token")
+
+	// ErrPreviewTokenExpired is returned when verifying an expired preview
+	// token.
+	ErrPreviewTokenExpired = errors.New("preview token expired")
+)
+
+// View is the content of a Page as it is served to visitors.
+type View struct {
+	ID          uuid.UUID     `json:"id"`
+	Name        string        `json:"name"`
+	Fields      []field.Field `json:"fields"`
+	PublishedAt time.Time     `json:"publishedAt"`
+
+	// Preview is true if the View contains the draft Fields of the Page.
+	Preview bool `json:"preview,omitempty"`
+}
+
+// Live returns the View of the published Page. If the Page is not published,
+// ErrNotPublished is returned.
+func (p *Page) Live() (View, error) {
+	if !p.Published {
+		return View{}, ErrNotPublished
+	}
+	return View{
+		ID:          p.ID,
+		Name:        p.Name,
+		Fields:      p.LiveFields,
+		PublishedAt: p.PublishedAt,
+	}, nil
+}
+
+// Preview returns the View of the draft of the Page.
+func (p *Page) Preview() View {
+	return View{
+		ID:          p.ID,
+		Name:        p.Name,
+		Fields:      p.Fields,
+		PublishedAt: p.PublishedAt,
+		Preview:     true,
+	}
+}
+
+// SignPreview returns a token that grants access to the draft of the Page with
+// the given UUID until it expires. The token contains the expiry time and an
+// HMAC-SHA256 signature of the Page UUID and the expiry time.
+func SignPreview(secret []byte, pageID uuid.UUID, expiry time.Duration) string {
+	expires := time.Now().Add(expiry).Unix()
+	return strconv.FormatInt(expires, 10) + "." + signPreview(secret, pageID, expires)
+}
+
+// VerifyPreview verifies a preview token for the Page with the given UUID.
+// VerifyPreview returns ErrInvalidPreviewToken if the signature of the token is
+// invalid and ErrPreviewTokenExpired if the token has expired.
+func VerifyPreview(secret []byte, pageID uuid.UUID, token string) error {
+	rawExpires, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalidPreviewToken
+	}
+
+	expires, err := strconv.ParseInt(rawExpires, 10, 64)
+	if err != nil {
+		return ErrInvalidPreviewToken
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(signPreview(secret, pageID, expires))) {
+		return ErrInvalidPreviewToken
+	}
+
+	if !time.Now().Before(time.Unix(expires, 0)) {
+		return ErrPreviewTokenExpired
+	}
+
+	return nil
+}
+
+func signPreview(secret []byte, pageID uuid.UUID, expires int64) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(pageID.String() + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,94 @@
+package page_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+func TestPage_Publish_draft(t *testing.T) {
+	p := page.New(uuid.New())
+	p.Create("foo", field.NewText("title", "Foo"))
+
+	if !p.Draft {
+		t.Fatalf("Page should have a draft after adding Fields")
+	}
+
+	if _, err := p.Live(); !errors.Is(err, page.ErrNotPublished) {
+		t.Fatalf("Live should fail with %q; got %q", page.ErrNotPublished, err)
+	}
+
+	if err := p.Publish(); err != nil {
+		t.Fatalf("Publish failed with %q", err)
+	}
+
+	if p.Draft {
+		t.Fatalf("Publish should promote the draft")
+	}
+
+	if err := p.UpdateField("title", "Bar"); err != nil {
+		t.Fatalf("UpdateField failed with %q", err)
+	}
+
+	if !p.Draft {
+		t.Fatalf("Page should have a draft after updating a Field")
+	}
+
+	live, err := p.Live()
+	if err != nil {
+		t.Fatalf("Live failed with %q", err)
+	}
+
+	if v := live.Fields[0].Value(""); v != "Foo" {
+		t.Fatalf("live value should be %q until the draft is published; is %q", "Foo", v)
+	}
+
+	if v := p.Preview().Fields[0].Value(""); v != "Bar" {
+		t.Fatalf("preview value should be %q; is %q", "Bar", v)
+	}
+
+	if err := p.Publish(); err != nil {
+		t.Fatalf("Publish failed with %q", err)
+	}
+
+	live, _ = p.Live()
+	if v := live.Fields[0].Value(""); v != "Bar" {
+		t.Fatalf("live value should be %q after the draft was published; is %q", "Bar", v)
+	}
+
+	if err := p.Publish(); !errors.Is(err, page.ErrAlreadyPublished) {
+		t.Fatalf("Publish should fail with %q without draft changes; got %q", page.ErrAlreadyPublished, err)
+	}
+}
+
+func TestVerifyPreview(t *testing.T) {
+	secret := []byte("secret")
+	id := uuid.New()
+
+	token := page.SignPreview(secret, id, time.Minute)
+
+	if err := page.VerifyPreview(secret, id, token); err != nil {
+		t.Fatalf("VerifyPreview failed with %q", err)
+	}
+
+	if err := page.VerifyPreview(secret, uuid.New(), token); !errors.Is(err, page.ErrInvalidPreviewToken) {
+		t.Fatalf("VerifyPreview should fail with %q for another Page; got %q", page.ErrInvalidPreviewToken, err)
+	}
+
+	if err := page.VerifyPreview([]byte("other"), id, token); !errors.Is(err, page.ErrInvalidPreviewToken) {
+		t.Fatalf("VerifyPreview should fail with %q for another secret; got %q", page.ErrInvalidPreviewToken, err)
+	}
+
+	if err := page.VerifyPreview(secret, id, "foo"); !errors.Is(err, page.ErrInvalidPreviewToken) {
+		t.Fatalf("VerifyPreview should fail with %q for a malformed token; got %q", page.ErrInvalidPreviewToken, err)
+	}
+
+	expired := page.SignPreview(secret, id, -time.Minute)
+	if err := page.VerifyPreview(secret, id, expired); !errors.Is(err, page.ErrPreviewTokenExpired) {
+		t.Fatalf("VerifyPreview should fail with %q for an expired token; got %q", page.ErrPreviewTokenExpired, err)
+	}
+}
@@ -0,0 +1,60 @@
+package page
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/event"
+)
+
+// Rename renames the Page. Renaming a Page to its current name does nothing.
+func (p *Page) Rename(name string) error {
+	if err := p.checkCreated(); err != nil {
+		return err
+	}
+
+	if name = strings.TrimSpace(name); name == "" {
+		return ErrEmptyName
+	}
+
+	if name == p.Name {
+		return nil
+	}
+
+	aggregate.NextEvent(p, Renamed, RenamedData{OldName: p.Name, Name: name})
+
+	return nil
+}
+
+func (p *Page) rename(evt event.Event) {
+	data := evt.Data().(RenamedData)
+	p.Name = data.Name
+}
+
+// Slug returns the slug of the name of the Page (see Slug).
+func (p *Page) Slug() string {
+	return Slug(p.Name)
+}
+
+// Slug returns the URL slug of a Page name. Slug lowercases the name and
+// replaces each sequence of characters that are neither letters nor digits
+// with a single dash:
+//
+//	Slug("About Us") // "about-us"
+func Slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package page_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/static/page"
+)
+
+func TestPage_Rename(t *testing.T) {
+	p := page.New(uuid.New())
+
+	if err := p.Rename("bar"); !errors.Is(err, page.ErrNotCreated) {
+		t.Fatalf("Rename should fail with %q; got %q", page.ErrNotCreated, err)
+	}
+
+	p.Create("foo")
+
+	if err := p.Rename("  "); !errors.Is(err, page.ErrEmptyName) {
+		t.Fatalf("Rename should fail with %q; got %q", page.ErrEmptyName, err)
+	}
+
+	if err := p.Rename(" About Us "); err != nil {
+		t.Fatalf("Rename failed with %q", err)
+	}
+
+	test.Change(t, p, page.Renamed, test.EventData(page.RenamedData{OldName: "foo", Name: "About Us"}))
+
+	if p.Name != "About Us" {
+		t.Fatalf("Name should be %q; is %q", "About Us", p.Name)
+	}
+
+	if p.Slug() != "about-us" {
+		t.Fatalf("Slug should return %q; got %q", "about-us", p.Slug())
+	}
+
+	if err := p.Rename("About Us"); err != nil {
+		t.Fatalf("Rename failed with %q", err)
+	}
+
+	test.Change(t, p, page.Renamed, test.Exactly(1))
+}
+
+func TestSlug(t *testing.T) {
+	tests := map[string]string{
+		"":                "",
+		"foo":             "foo",
+		"About Us":        "about-us",
+		"  Über  uns! ":   "über-uns",
+		"Pricing & Plans": "pricing-plans",
+	}
+
+	for name, want := range tests {
+		if got := page.Slug(name); got != want {
+			t.Fatalf("Slug(%q) should return %q; got %q", name, want, got)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package page
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+// ErrRevisionNotFound is returned when trying to get a Revision of a Page that
+// doesn't exist.
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// Revision is a published version of a Page. Revisions are derived from the
+// Published events of a Page and are numbered starting at 1.
+type Revision struct {
+	// Number is the number of the Revision.
+	Number int `json:"number"`
+
+	// PublishedAt is the time at which the Revision was published.
+	PublishedAt time.Time `json:"publishedAt"`
+
+	// Fields are the Fields of the Page as they were published.
+	Fields []field.Field `json:"fields"`
+}
+
+// Change is the kind of a FieldChange.
+type Change string
+
+// Field changes
+const (
+	FieldAdded   = Change("added")
+	FieldRemoved = Change("removed")
+	FieldChanged = Change("changed")
+)
+
+// FieldChange is the change of the value of a Field for a single locale
+// between two Revisions.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Locale string `json:"locale"`
+	Change Change `json:"change"`
+
+	// Old is the value in the older Revision. Old is empty for added Fields.
+	Old string `json:"old,omitempty"`
+
+	// New is the value in the newer Revision. New is empty for removed Fields.
+	New string `json:"new,omitempty"`
+}
+
+// Revision returns the Revision with the given number, or ErrRevisionNotFound.
+func (p *Page) Revision(number int) (Revision, error) {
+	if number < 1 || number > len(p.Revisions) {
+		return Revision{}, fmt.Errorf("%d: %w", number, ErrRevisionNotFound)
+	}
+	return p.Revisions[number-1], nil
+}
+
+// DiffRevisions returns the changes of the Fields between the Revisions with
+// the given numbers.
+func (p *Page) DiffRevisions(from, to int) ([]FieldChange, error) {
+	a, err := p.Revision(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := p.Revision(to)
+	if err != nil {
+		return nil, err
+	}
+	return Diff(a, b), nil
+}
+
+// Diff returns the changes of the Fields from Revision a to Revision b, field
+// by field and locale by locale. Changes are ordered by the Fields of a,
+// followed by the Fields that were added in b. Locales are sorted.
+func Diff(a, b Revision) []FieldChange {
+	changes := make([]FieldChange, 0)
+
+	newFields := make(map[string]field.Field, len(b.Fields))
+	for _, f := range b.Fields {
+		newFields[f.Name] = f
+	}
+
+	oldFields := make(map[string]bool, len(a.Fields))
+	for _, old := range a.Fields {
+		oldFields[old.Name] = true
+		f, ok := newFields[old.Name]
+		if !ok {
+			f = field.Field{Name: old.Name}
+		}
+		changes = append(changes, diffField(old, f)...)
+	}
+
+	for _, f := range b.Fields {
+		if oldFields[f.Name] {
+			continue
+		}
+		changes = append(changes, diffField(field.Field{Name: f.Name}, f)...)
+	}
+
+	return changes
+}
+
+func diffField(old, f field.Field) []FieldChange {
+	locales := make([]string, 0, len(old.Values)+len(f.Values))
+	for locale := range old.Values {
+		locales = append(locales, locale)
+	}
+	for locale := range f.Values {
+		if _, ok := old.Values[locale]; !ok {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales)
+
+	var changes []FieldChange
+	for _, locale := range locales {
+		oldVal, hadOld := old.Values[locale]
+		newVal, hasNew := f.Values[locale]
+		change := FieldChange{Field: f.Name, Locale: locale, Old: oldVal, New: newVal}
+		switch {
+		case !hadOld:
+			change.Change = FieldAdded
+		case !hasNew:
+			change.Change = FieldRemoved
+		case oldVal != newVal:
+			change.Change = FieldChanged
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// RevertToRevision replaces the draft Fields of the Page with the Fields of
+// the Revision with the given number. The reverted Fields go live when the Page
+// is published.
+func (p *Page) RevertToRevision(number int) error {
+	if err := p.checkCreated(); err != nil {
+		return err
+	}
+
+	if _, err := p.Revision(number); err != nil {
+		return err
+	}
+
+	aggregate.NextEvent(p, RevertedToRevision, RevertedToRevisionData{Revision: number})
+
+	return nil
+}
+
+func (p *Page) revertToRevision(evt event.Event) {
+	data := evt.Data().(RevertedToRevisionData)
+	rev, err := p.Revision(data.Revision)
+	if err != nil {
+		return
+	}
+	p.Fields = cloneFields(rev.Fields)
+	p.Draft = true
+}
+
+func (p *Page) addRevision(evt event.Event) {
+	p.Revisions = append(p.Revisions, Revision{
+		Number:      len(p.Revisions) + 1,
+		PublishedAt: evt.Time(),
+		Fields:      cloneFields(p.LiveFields),
+	})
+}
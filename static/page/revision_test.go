@@ -0,0 +1,109 @@
+package page_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/static/page"
+	"github.com/modernice/nice-cms/static/page/field"
+)
+
+func TestPage_Revisions(t *testing.T) {
+	p := page.New(uuid.New())
+	p.Create("foo", field.NewText("title", "Foo"))
+	p.Publish()
+	p.UpdateField("title", "Bar")
+	p.Publish()
+
+	if len(p.Revisions) != 2 {
+		t.Fatalf("Page should have %d Revisions; has %d", 2, len(p.Revisions))
+	}
+
+	for i, want := range []string{"Foo", "Bar"} {
+		rev, err := p.Revision(i + 1)
+		if err != nil {
+			t.Fatalf("Revision(%d) failed with %q", i+1, err)
+		}
+		if rev.Number != i+1 || rev.PublishedAt.IsZero() {
+			t.Fatalf("invalid Revision %d: %v", i+1, rev)
+		}
+		if v := rev.Fields[0].Value(""); v != want {
+			t.Fatalf("title of Revision %d should be %q; is %q", i+1, want, v)
+		}
+	}
+
+	if _, err := p.Revision(3); !errors.Is(err, page.ErrRevisionNotFound) {
+		t.Fatalf("Revision(3) should fail with %q; got %q", page.ErrRevisionNotFound, err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := page.Revision{Number: 1, Fields: []field.Field{
+		field.NewText("title", "Foo", field.Localize("Foo (de)", "de")),
+		field.NewText("subtitle", "Sub"),
+	}}
+	b := page.Revision{Number: 2, Fields: []field.Field{
+		field.NewText("title", "Bar", field.Localize("Foo (de)", "de")),
+		field.NewToggle("visible", true),
+	}}
+
+	want := []page.FieldChange{
+		{Field: "title", Locale: "", Change: page.FieldChanged, Old: "Foo", New: "Bar"},
+		{Field: "subtitle", Locale: "", Change: page.FieldRemoved, Old: "Sub"},
+		{Field: "visible", Locale: "", Change: page.FieldAdded, New: "1"},
+	}
+
+	if got := page.Diff(a, b); !cmp.Equal(want, got) {
+		t.Fatalf("invalid Diff.\n\n%s", cmp.Diff(want, got))
+	}
+
+	if got := page.Diff(a, a); len(got) != 0 {
+		t.Fatalf("Diff of equal Revisions should be empty; got %v", got)
+	}
+}
+
+func TestPage_RevertToRevision(t *testing.T) {
+	p := page.New(uuid.New())
+
+	if err := p.RevertToRevision(1); !errors.Is(err, page.ErrNotCreated) {
+		t.Fatalf("RevertToRevision should fail with %q; got %q", page.ErrNotCreated, err)
+	}
+
+	p.Create("foo", field.NewText("title", "Foo"))
+
+	if err := p.RevertToRevision(1); !errors.Is(err, page.ErrRevisionNotFound) {
+		t.Fatalf("RevertToRevision should fail with %q; got %q", page.ErrRevisionNotFound, err)
+	}
+
+	p.Publish()
+	p.UpdateField("title", "Bar")
+	p.Publish()
+
+	if err := p.RevertToRevision(1); err != nil {
+		t.Fatalf("RevertToRevision failed with %q", err)
+	}
+
+	test.Change(t, p, page.RevertedToRevision, test.EventData(page.RevertedToRevisionData{Revision: 1}))
+
+	if v := p.Fields[0].Value(""); v != "Foo" || !p.Draft {
+		t.Fatalf("draft should be reverted to %q; is %q (Draft=%v)", "Foo", v, p.Draft)
+	}
+
+	if v := p.LiveFields[0].Value(""); v != "Bar" {
+		t.Fatalf("LiveFields should not be reverted before publishing; title is %q", v)
+	}
+
+	p.UpdateField("title", "Baz")
+	if v := p.Revisions[0].Fields[0].Value(""); v != "Foo" {
+		t.Fatalf("updating the reverted draft should not change the Revision; title is %q", v)
+	}
+
+	p.Publish()
+
+	if len(p.Revisions) != 3 || p.LiveFields[0].Value("") != "Baz" {
+		t.Fatalf("publishing the reverted draft should add a Revision; Revisions=%v", p.Revisions)
+	}
+}
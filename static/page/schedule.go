@@ -0,0 +1,345 @@
+package page
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate"
+	"github.com/modernice/goes/command"
+	"github.com/modernice/goes/command/cmdbus/dispatch"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/helper/streams"
+	"github.com/modernice/goes/projection/schedule"
+	"github.com/modernice/nice-cms/projector"
+)
+
+// DefaultSchedulerInterval is the default interval in which a Scheduler
+// checks for due Schedules.
+const DefaultSchedulerInterval = 10 * time.Second
+
+// Schedule is the publishing schedule of a Page. A zero time means that
+// nothing is scheduled.
+type Schedule struct {
+	// PublishAt is the time at which the Page will be published.
+	PublishAt time.Time `json:"publishAt"`
+
+	// UnpublishAt is the time at which the Page will be unpublished.
+	UnpublishAt time.Time `json:"unpublishAt"`
+}
+
+// Scheduled returns whether the publishing or unpublishing of a Page is
+// scheduled.
+func (s Schedule) Scheduled() bool {
+	return !s.PublishAt.IsZero() || !s.UnpublishAt.IsZero()
+}
+
+// Publish publishes the Page by promoting its draft Fields to its LiveFields.
+// Every publishing adds a Revision to the Page. Publishing a Page clears its
+// scheduled publishing. If the Page is already
+// published and has no draft changes, ErrAlreadyPublished is returned.
+func (p *Page) Publish() error {
+	if err := p.checkCreated(); err != nil {
+		return err
+	}
+
+	if p.Published && !p.Draft {
+		return ErrAlreadyPublished
+	}
+
+	aggregate.NextEvent(p, Published, PublishedData{})
+
+	return nil
+}
+
+func (p *Page) publish(evt event.Event) {
+	p.Published = true
+	p.PublishedAt = evt.Time()
+	p.LiveFields = cloneFields(p.Fields)
+	p.Draft = false
+	p.Schedule.PublishAt = time.Time{}
+	p.addRevision(evt)
+}
+
+// Unpublish unpublishes the Page. Unpublishing a Page clears its scheduled
+// unpublishing. If the Page is not published, ErrNotPublished is returned.
+func (p *Page) Unpublish() error {
+	if err := p.checkCreated(); err != nil {
+		return err
+	}
+
+	if !p.Published {
+		return ErrNotPublished
+	}
+
+	aggregate.NextEvent(p, Unpublished, UnpublishedData{})
+
+	return nil
+}
+
+func (p *Page) unpublish(evt event.Event) {
+	p.Published = false
+	p.Schedule.UnpublishAt = time.Time{}
+}
+
+// SchedulePublish schedules the publishing of the Page at the given time. The
+// Page is published by the Scheduler. If the Page is already published and has
+// no draft changes, ErrAlreadyPublished is returned. If the unpublishing of the
+// Page is scheduled, at must be before the scheduled unpublishing, otherwise
+// ErrInvalidSchedule is returned.
+func (p *Page) SchedulePublish(at time.Time) error {
+	if err := p.checkCreated(); err != nil {
+		return err
+	}
+
+	if p.Published && !p.Draft {
+		return ErrAlreadyPublished
+	}
+
+	if at.IsZero() {
+		return fmt.Errorf("%w: zero time", ErrInvalidSchedule)
+	}
+
+	if unpublishAt := p.Schedule.UnpublishAt; !unpublishAt.IsZero() && !at.Before(unpublishAt) {
+		return fmt.Errorf("%w: publishing must be scheduled before the unpublishing at %v", ErrInvalidSchedule, unpublishAt)
+	}
+
+	aggregate.NextEvent(p, PublishScheduled, PublishScheduledData{At: at})
+
+	return nil
+}
+
+func (p *Page) schedulePublish(evt event.Event) {
+	data := evt.Data().(PublishScheduledData)
+	p.Schedule.PublishAt = data.At
+}
+
+// ScheduleUnpublish schedules the unpublishing of the Page at the given time.
+// The Page is unpublished by the Scheduler. If the Page is neither published
+// nor scheduled to be published, ErrNotPublished is returned. If the publishing
+// of the Page is scheduled, at must be after the scheduled publishing,
+// otherwise ErrInvalidSchedule is returned.
+func (p *Page) ScheduleUnpublish(at time.Time) error {
+	if err := p.checkCreated(); err != nil {
+		return err
+	}
+
+	publishAt := p.Schedule.PublishAt
+
+	if !p.Published && publishAt.IsZero() {
+		return ErrNotPublished
+	}
+
+	if at.IsZero() {
+		return fmt.Errorf("%w: zero time", ErrInvalidSchedule)
+	}
+
+	if !publishAt.IsZero() && !at.After(publishAt) {
+		return fmt.Errorf("%w: unpublishing must be scheduled after the publishing at %v", ErrInvalidSchedule, publishAt)
+	}
+
+	aggregate.NextEvent(p, UnpublishScheduled, UnpublishScheduledData{At: at})
+
+	return nil
+}
+
+func (p *Page) scheduleUnpublish(evt event.Event) {
+	data := evt.Data().(UnpublishScheduledData)
+	p.Schedule.UnpublishAt = data.At
+}
+
+// CancelSchedule cancels the scheduled publishing and unpublishing of the Page.
+// CancelSchedule does nothing if nothing is scheduled.
+func (p *Page) CancelSchedule() error {
+	if err := p.checkCreated(); err != nil {
+		return err
+	}
+
+	if !p.Schedule.Scheduled() {
+		return nil
+	}
+
+	aggregate.NextEvent(p, ScheduleCanceled, ScheduleCanceledData{})
+
+	return nil
+}
+
+func (p *Page) cancelSchedule(evt event.Event) {
+	p.Schedule = Schedule{}
+}
+
+// SchedulerOption is an option for a Scheduler.
+type SchedulerOption func(*Scheduler)
+
+// SchedulerInterval returns a SchedulerOption that sets the interval in which
+// the Scheduler checks for due Schedules. Default is DefaultSchedulerInterval.
+func SchedulerInterval(d time.Duration) SchedulerOption {
+	return func(s *Scheduler) {
+		s.interval = d
+	}
+}
+
+// SchedulerProjection returns a SchedulerOption that configures the error
+// handling of the projection of the Scheduler.
+func SchedulerProjection(opts ...projector.Option) SchedulerOption {
+	return func(s *Scheduler) {
+		s.projectorOpts = append(s.projectorOpts, opts...)
+	}
+}
+
+// Scheduler publishes and unpublishes Pages according to their Schedules. The
+// Scheduler projects the Schedules of all Pages from the event store and
+// dispatches the publish and unpublish commands when a Schedule is due, so
+// that it doesn't have to fetch every Page. Scheduler is thread-safe.
+type Scheduler struct {
+	commands      command.Bus
+	interval      time.Duration
+	projector     *projector.Projector
+	projectorOpts []projector.Option
+
+	mux       sync.RWMutex
+	schedules map[uuid.UUID]Schedule
+}
+
+// NewScheduler returns a Scheduler that dispatches commands over the provided
+// command bus. The commands are handled by HandleCommands.
+func NewScheduler(commands command.Bus, opts ...SchedulerOption) *Scheduler {
+	s := &Scheduler{
+		commands:  commands,
+		interval:  DefaultSchedulerInterval,
+		schedules: make(map[uuid.UUID]Schedule),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.projector = projector.New(s.projectorOpts...)
+	return s
+}
+
+// Run projects the Schedules of all Pages and publishes and unpublishes due
+// Pages in the configured interval until ctx is canceled. Run returns a
+// channel of asynchronous projection and dispatch errors.
+func (s *Scheduler) Run(ctx context.Context, bus event.Bus, store event.Store, opts ...schedule.ContinuousOption) (<-chan error, error) {
+	schedule := schedule.Continuously(bus, store, []string{
+		Published,
+		Unpublished,
+		PublishScheduled,
+		UnpublishScheduled,
+		ScheduleCanceled,
+		Deleted,
+	}, opts...)
+
+	projectionErrors, err := s.projector.Run(ctx, schedule, s)
+	if err != nil {
+		return nil, err
+	}
+
+	dispatchErrors := make(chan error)
+	go s.work(ctx, dispatchErrors)
+
+	return streams.FanInContext(ctx, projectionErrors, dispatchErrors), nil
+}
+
+// Status returns the projection status of the Scheduler.
+func (s *Scheduler) Status() projector.Status {
+	return s.projector.Status()
+}
+
+// Schedules returns the projected Schedules, keyed by the UUIDs of the Pages.
+func (s *Scheduler) Schedules() map[uuid.UUID]Schedule {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	out := make(map[uuid.UUID]Schedule, len(s.schedules))
+	for id, sched := range s.schedules {
+		out[id] = sched
+	}
+	return out
+}
+
+// ApplyEvent applies aggregate events.
+func (s *Scheduler) ApplyEvent(evt event.Event) {
+	id, _, _ := evt.Aggregate()
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	sched := s.schedules[id]
+
+	switch data := evt.Data().(type) {
+	case PublishedData:
+		sched.PublishAt = time.Time{}
+	case UnpublishedData:
+		sched.UnpublishAt = time.Time{}
+	case PublishScheduledData:
+		sched.PublishAt = data.At
+	case UnpublishScheduledData:
+		sched.UnpublishAt = data.At
+	case ScheduleCanceledData, DeletedData:
+		sched = Schedule{}
+	}
+
+	if sched.Scheduled() {
+		s.schedules[id] = sched
+	} else {
+		delete(s.schedules, id)
+	}
+}
+
+// Dispatch dispatches the publish and unpublish commands of the Schedules that
+// are due. If both the publishing and unpublishing of a Page are due, the Page
+// is published before it is unpublished.
+func (s *Scheduler) Dispatch(ctx context.Context) error {
+	now := time.Now()
+
+	type due struct {
+		pageID uuid.UUID
+		at     time.Time
+		cmd    command.Command
+	}
+
+	s.mux.RLock()
+	var dues []due
+	for id, sched := range s.schedules {
+		if at := sched.PublishAt; !at.IsZero() && !now.Before(at) {
+			dues = append(dues, due{id, at, scheduledPublishCmd(id, at).Any()})
+		}
+		if at := sched.UnpublishAt; !at.IsZero() && !now.Before(at) {
+			dues = append(dues, due{id, at, scheduledUnpublishCmd(id, at).Any()})
+		}
+	}
+	s.mux.RUnlock()
+
+	sort.SliceStable(dues, func(i, j int) bool { return dues[i].at.Before(dues[j].at) })
+
+	for _, d := range dues {
+		if err := s.commands.Dispatch(ctx, d.cmd, dispatch.Sync()); err != nil {
+			return fmt.Errorf("dispatch %q command for page %s: %w", d.cmd.Name(), d.pageID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Scheduler) work(ctx context.Context, out chan<- error) {
+	defer close(out)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Dispatch(ctx); err != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case out <- err:
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package page_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/aggregate/repository"
+	"github.com/modernice/goes/command/cmdbus"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/goes/event/eventstore"
+	"github.com/modernice/goes/test"
+	"github.com/modernice/nice-cms/internal/commands"
+	"github.com/modernice/nice-cms/internal/discard"
+	"github.com/modernice/nice-cms/static/page"
+)
+
+func TestPage_Publish(t *testing.T) {
+	p := page.New(uuid.New())
+
+	if err := p.Publish(); !errors.Is(err, page.ErrNotCreated) {
+		t.Fatalf("Publish should fail with %q; got %q", page.ErrNotCreated, err)
+	}
+
+	p.Create("foo")
+
+	if err := p.Publish(); err != nil {
+		t.Fatalf("Publish failed with %q", err)
+	}
+
+	if !p.Published || p.PublishedAt.IsZero() {
+		t.Fatalf("Page should be published; Published=%v PublishedAt=%v", p.Published, p.PublishedAt)
+	}
+
+	if err := p.Publish(); !errors.Is(err, page.ErrAlreadyPublished) {
+		t.Fatalf("Publish should fail with %q; got %q", page.ErrAlreadyPublished, err)
+	}
+
+	test.Change(t, p, page.Published, test.Exactly(1))
+
+	if err := p.Unpublish(); err != nil {
+		t.Fatalf("Unpublish failed with %q", err)
+	}
+
+	if p.Published {
+		t.Fatalf("Page should not be published")
+	}
+
+	if err := p.Unpublish(); !errors.Is(err, page.ErrNotPublished) {
+		t.Fatalf("Unpublish should fail with %q; got %q", page.ErrNotPublished, err)
+	}
+
+	test.Change(t, p, page.Unpublished, test.Exactly(1))
+}
+
+func TestPage_SchedulePublish(t *testing.T) {
+	p := page.New(uuid.New())
+	p.Create("foo")
+
+	at := time.Now().Add(time.Hour)
+	if err := p.SchedulePublish(at); err != nil {
+		t.Fatalf("SchedulePublish failed with %q", err)
+	}
+
+	if !p.Schedule.PublishAt.Equal(at) {
+		t.Fatalf("PublishAt should be %v; is %v", at, p.Schedule.PublishAt)
+	}
+
+	test.Change(t, p, page.PublishScheduled, test.EventData(page.PublishScheduledData{At: at}))
+
+	if err := p.SchedulePublish(time.Time{}); !errors.Is(err, page.ErrInvalidSchedule) {
+		t.Fatalf("SchedulePublish should fail with %q for the zero time; got %q", page.ErrInvalidSchedule, err)
+	}
+
+	if err := p.Publish(); err != nil {
+		t.Fatalf("Publish failed with %q", err)
+	}
+
+	if !p.Schedule.PublishAt.IsZero() {
+		t.Fatalf("Publish should clear the scheduled publishing; PublishAt is %v", p.Schedule.PublishAt)
+	}
+
+	if err := p.SchedulePublish(at); !errors.Is(err, page.ErrAlreadyPublished) {
+		t.Fatalf("SchedulePublish should fail with %q for a published Page; got %q", page.ErrAlreadyPublished, err)
+	}
+}
+
+func TestPage_ScheduleUnpublish(t *testing.T) {
+	p := page.New(uuid.New())
+	p.Create("foo")
+
+	at := time.Now().Add(time.Hour)
+	if err := p.ScheduleUnpublish(at); !errors.Is(err, page.ErrNotPublished) {
+		t.Fatalf("ScheduleUnpublish should fail with %q for an unpublished Page; got %q", page.ErrNotPublished, err)
+	}
+
+	if err := p.SchedulePublish(at); err != nil {
+		t.Fatalf("SchedulePublish failed with %q", err)
+	}
+
+	if err := p.ScheduleUnpublish(at.Add(-time.Minute)); !errors.Is(err, page.ErrInvalidSchedule) {
+		t.Fatalf("ScheduleUnpublish should fail with %q for a time before the publishing; got %q", page.ErrInvalidSchedule, err)
+	}
+
+	unpublishAt := at.Add(time.Hour)
+	if err := p.ScheduleUnpublish(unpublishAt); err != nil {
+		t.Fatalf("ScheduleUnpublish failed with %q", err)
+	}
+
+	if !p.Schedule.UnpublishAt.Equal(unpublishAt) {
+		t.Fatalf("UnpublishAt should be %v; is %v", unpublishAt, p.Schedule.UnpublishAt)
+	}
+
+	if err := p.SchedulePublish(unpublishAt.Add(time.Minute)); !errors.Is(err, page.ErrInvalidSchedule) {
+		t.Fatalf("SchedulePublish should fail with %q for a time after the unpublishing; got %q", page.ErrInvalidSchedule, err)
+	}
+
+	if err := p.CancelSchedule(); err != nil {
+		t.Fatalf("CancelSchedule failed with %q", err)
+	}
+
+	if p.Schedule.Scheduled() {
+		t.Fatalf("CancelSchedule should clear the Schedule; got %v", p.Schedule)
+	}
+
+	if err := p.CancelSchedule(); err != nil {
+		t.Fatalf("CancelSchedule failed with %q", err)
+	}
+
+	test.Change(t, p, page.ScheduleCanceled, test.Exactly(1))
+}
+
+func TestScheduler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ebus := eventbus.New()
+	estore := eventstore.WithBus(eventstore.New(), ebus)
+	cbus := cmdbus.New(commands.NewRegistry(), ebus)
+	pages := page.GoesRepository(repository.New(estore))
+
+	go discard.Errors(page.HandleCommands(ctx, cbus, pages))
+
+	// The publishing of this Page was scheduled before the Scheduler was
+	// started, so the Scheduler must catch up with past events.
+	p := page.New(uuid.New())
+	p.Create("foo")
+	if err := p.SchedulePublish(time.Now().Add(50 * time.Millisecond)); err != nil {
+		t.Fatalf("SchedulePublish failed with %q", err)
+	}
+	if err := p.ScheduleUnpublish(time.Now().Add(300 * time.Millisecond)); err != nil {
+		t.Fatalf("ScheduleUnpublish failed with %q", err)
+	}
+	if err := pages.Save(ctx, p); err != nil {
+		t.Fatalf("save Page: %v", err)
+	}
+
+	canceled := page.New(uuid.New())
+	canceled.Create("bar")
+	canceled.SchedulePublish(time.Now().Add(50 * time.Millisecond))
+	canceled.CancelSchedule()
+	if err := pages.Save(ctx, canceled); err != nil {
+		t.Fatalf("save Page: %v", err)
+	}
+
+	scheduler := page.NewScheduler(cbus, page.SchedulerInterval(10*time.Millisecond))
+	errs, err := scheduler.Run(ctx, ebus, estore)
+	if err != nil {
+		t.Fatalf("run scheduler: %v", err)
+	}
+	go discard.Errors(errs)
+
+	awaitPage(t, pages, p.ID, "Page should be published", func(p *page.Page) bool {
+		return p.Published && !p.Schedule.UnpublishAt.IsZero()
+	})
+
+	awaitPage(t, pages, p.ID, "Page should be unpublished", func(p *page.Page) bool {
+		return !p.Published && !p.Schedule.Scheduled()
+	})
+
+	if c, err := pages.Fetch(ctx, canceled.ID); err != nil || c.Published {
+		t.Fatalf("Page with canceled Schedule should not be published; Published=%v (%v)", c.Published, err)
+	}
+
+	if s := scheduler.Schedules(); len(s) != 0 {
+		t.Fatalf("Scheduler should have no Schedules; got %v", s)
+	}
+}
+
+func awaitPage(t *testing.T, pages page.Repository, id uuid.UUID, msg string, fn func(*page.Page) bool) {
+	timeout := time.After(3 * time.Second)
+	for {
+		p, err := pages.Fetch(context.Background(), id)
+		if err != nil {
+			t.Fatalf("fetch Page: %v", err)
+		}
+		if fn(p) {
+			return
+		}
+		select {
+		case <-timeout:
+			t.Fatalf(msg)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
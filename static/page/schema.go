@@ -0,0 +1,32 @@
+package page
+
+import (
+	"fmt"
+
+	"github.com/modernice/nice-cms/schema"
+)
+
+// Schema returns the JSON Schema of the Field values of the Page. The schema
+// is an object that has a property for each draft Field of the Page, which is
+// described by field.Field.ValueSchema. Generic admin UIs can use the schema to
+// build a form for the Page, because the Fields of a Page are its blueprint.
+func (p *Page) Schema() *schema.Schema {
+	s := &schema.Schema{
+		Schema:     schema.Draft,
+		Type:       "object",
+		Title:      p.Name,
+		Properties: make(map[string]*schema.Schema, len(p.Fields)),
+	}
+
+	for _, f := range p.Fields {
+		prop := f.ValueSchema()
+		if f.Guarded {
+			prop.Description = fmt.Sprintf("%s field (guarded)", f.Type)
+		} else {
+			prop.Description = fmt.Sprintf("%s field", f.Type)
+		}
+		s.Properties[f.Name] = prop
+	}
+
+	return s
+}
@@ -0,0 +1,599 @@
+// Package webhooks calls HTTP endpoints when events of shelfs, galleries, navs
+// and pages are published, so that external systems (e.g. search indexes or
+// cache purgers) can react to changes of the CMS.
+//
+//	d := webhooks.NewDispatcher()
+//	d.Register(webhooks.Endpoint{
+//		URL:    "https://search.example.com/hooks/cms",
+//		Events: []string{document.DocumentAdded, gallery.StackProcessed},
+//		Secret: "secret",
+//	})
+//
+//	errs, err := d.Run(ctx, bus)
+//
+// Each event is POSTed as a JSON Payload. Requests are signed with the Secret
+// of the Endpoint (see Sign and Verify). Failed deliveries are retried with
+// exponential backoff, and every Delivery is kept in a bounded delivery log.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/helper/streams"
+	"github.com/modernice/nice-cms/media/document"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/static/page"
+)
+
+const (
+	// DefaultMaxAttempts is the default number of attempts of a Delivery.
+	DefaultMaxAttempts = 5
+
+	// DefaultBackoff is the default delay before the first retry of a
+	// Delivery. The delay doubles with every retry.
+	DefaultBackoff = time.Second
+
+	// DefaultTimeout is the default timeout of a single request.
+	DefaultTimeout = 10 * time.Second
+
+	// DefaultWorkers is the default number of concurrent requests.
+	DefaultWorkers = 4
+
+	// DefaultLogSize is the default number of Deliveries that are kept in the
+	// delivery log.
+	DefaultLogSize = 1000
+)
+
+// Request headers of webhook calls.
+const (
+	// IDHeader is the UUID of the Delivery. Retries of a Delivery have the
+	// same ID, so receivers can use it to discard duplicates.
+	IDHeader = "Webhook-Id"
+
+	// EventHeader is the name of the event.
+	EventHeader = "Webhook-Event"
+
+	// TimestampHeader is the Unix time of the request in seconds.
+	TimestampHeader = "Webhook-Timestamp"
+
+	// SignatureHeader is the signature of the request (see Sign).
+	SignatureHeader = "Webhook-Signature"
+)
+
+var (
+	// ErrInvalidSignature is returned by Verify if a request is not signed
+	// with the secret.
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	// ErrExpired is returned by Verify if the timestamp of a request is outside
+	// of the tolerance.
+	ErrExpired = errors.New("expired request")
+)
+
+// Endpoint is an HTTP endpoint that is called for the events it subscribed to.
+type Endpoint struct {
+	ID  uuid.UUID `json:"id"`
+	URL string    `json:"url"`
+
+	// Events are the names of the events that the Endpoint is called for. If
+	// Events is empty, the Endpoint is called for every event of the
+	// Dispatcher.
+	Events []string `json:"events,omitempty"`
+
+	// Secret is the secret that requests to the Endpoint are signed with. If
+	// Secret is empty, requests are not signed.
+	Secret string `json:"-"`
+}
+
+func (ep Endpoint) subscribed(name string) bool {
+	if len(ep.Events) == 0 {
+		return true
+	}
+	for _, e := range ep.Events {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Payload is the request body of a webhook call.
+type Payload struct {
+	// ID is the UUID of the event.
+	ID               uuid.UUID `json:"id"`
+	Event            string    `json:"event"`
+	Time             time.Time `json:"time"`
+	Aggregate        string    `json:"aggregate"`
+	AggregateID      uuid.UUID `json:"aggregateId"`
+	AggregateVersion int       `json:"aggregateVersion"`
+
+	// Data is the data of the event.
+	Data any `json:"data,omitempty"`
+}
+
+// Status is the status of a Delivery.
+type Status string
+
+const (
+	// Pending Deliveries have not been delivered yet and will be (re)tried.
+	Pending = Status("pending")
+
+	// Delivered Deliveries were accepted by the Endpoint with a 2xx response.
+	Delivered = Status("delivered")
+
+	// Failed Deliveries were not accepted within the maximum number of
+	// attempts, or were rejected with a status that is not retried.
+	Failed = Status("failed")
+)
+
+// Delivery is the delivery of an event to an Endpoint.
+type Delivery struct {
+	ID         uuid.UUID `json:"id"`
+	EndpointID uuid.UUID `json:"endpointId"`
+	URL        string    `json:"url"`
+	EventID    uuid.UUID `json:"eventId"`
+	Event      string    `json:"event"`
+	Status     Status    `json:"status"`
+	Attempts   []Attempt `json:"attempts"`
+}
+
+// Attempt is an attempt to deliver a Delivery.
+type Attempt struct {
+	Time     time.Time     `json:"time"`
+	Duration time.Duration `json:"duration"`
+
+	// StatusCode is the status code of the response, or 0 if the request
+	// failed without a response.
+	StatusCode int `json:"statusCode,omitempty"`
+
+	// Error is the error of a failed attempt.
+	Error string `json:"error,omitempty"`
+}
+
+// Events returns the events that a Dispatcher subscribes to by default.
+func Events() []string {
+	events := make([]string, 0, len(document.Events)+len(gallery.Events)+len(nav.Events)+len(page.Events))
+	events = append(events, document.Events[:]...)
+	events = append(events, gallery.Events[:]...)
+	events = append(events, nav.Events[:]...)
+	return append(events, page.Events[:]...)
+}
+
+// Option is a Dispatcher option.
+type Option func(*Dispatcher)
+
+// WithEvents returns an Option that subscribes the Dispatcher to additional
+// events, e.g. the events of application-defined aggregates.
+func WithEvents(events ...string) Option {
+	return func(d *Dispatcher) {
+		d.events = append(d.events, events...)
+	}
+}
+
+// HTTPClient returns an Option that sets the http.Client that calls the
+// endpoints. Default is a client with DefaultTimeout.
+func HTTPClient(client *http.Client) Option {
+	return func(d *Dispatcher) {
+		d.client = client
+	}
+}
+
+// MaxAttempts returns an Option that sets the maximum number of attempts of a
+// Delivery. Default is DefaultMaxAttempts.
+func MaxAttempts(n int) Option {
+	return func(d *Dispatcher) {
+		d.maxAttempts = n
+	}
+}
+
+// Backoff returns an Option that sets the delay before the first retry of a
+// Delivery. The delay doubles with every retry. Default is DefaultBackoff.
+func Backoff(delay time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.backoff = delay
+	}
+}
+
+// Workers returns an Option that sets the number of concurrent requests.
+// Default is DefaultWorkers.
+func Workers(n int) Option {
+	return func(d *Dispatcher) {
+		d.workers = n
+	}
+}
+
+// LogSize returns an Option that sets the number of Deliveries that are kept
+// in the delivery log. Default is DefaultLogSize.
+func LogSize(n int) Option {
+	return func(d *Dispatcher) {
+		d.logSize = n
+	}
+}
+
+// Dispatcher delivers events to the registered Endpoints. Dispatcher is
+// thread-safe. Use NewDispatcher to create a Dispatcher.
+type Dispatcher struct {
+	events      []string
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration
+	workers     int
+	logSize     int
+
+	mux        sync.RWMutex
+	endpoints  map[uuid.UUID]Endpoint
+	deliveries []*Delivery
+}
+
+// NewDispatcher returns a new Dispatcher that subscribes to the events
+// returned by Events.
+func NewDispatcher(opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		events:      Events(),
+		maxAttempts: DefaultMaxAttempts,
+		backoff:     DefaultBackoff,
+		workers:     DefaultWorkers,
+		logSize:     DefaultLogSize,
+		endpoints:   make(map[uuid.UUID]Endpoint),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.client == nil {
+		d.client = &http.Client{Timeout: DefaultTimeout}
+	}
+	if d.maxAttempts < 1 {
+		d.maxAttempts = 1
+	}
+	if d.workers < 1 {
+		d.workers = 1
+	}
+	return d
+}
+
+// Register registers an Endpoint and returns it. If the ID of the Endpoint is
+// uuid.Nil, a new ID is assigned. An Endpoint with the same ID is replaced.
+func (d *Dispatcher) Register(ep Endpoint) Endpoint {
+	if ep.ID == uuid.Nil {
+		ep.ID = uuid.New()
+	}
+	ep.Events = append([]string(nil), ep.Events...)
+
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.endpoints[ep.ID] = ep
+
+	return ep
+}
+
+// Unregister removes the Endpoint with the given ID. Pending Deliveries to the
+// Endpoint are still tried. Unregister returns false if the Endpoint is not
+// registered.
+func (d *Dispatcher) Unregister(id uuid.UUID) bool {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	_, ok := d.endpoints[id]
+	delete(d.endpoints, id)
+	return ok
+}
+
+// Endpoints returns the registered Endpoints, sorted by URL.
+func (d *Dispatcher) Endpoints() []Endpoint {
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+
+	endpoints := make([]Endpoint, 0, len(d.endpoints))
+	for _, ep := range d.endpoints {
+		endpoints = append(endpoints, ep)
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].URL < endpoints[j].URL })
+
+	return endpoints
+}
+
+// Deliveries returns the Deliveries of the delivery log to the Endpoint with
+// the given ID, newest first. If endpointID is uuid.Nil, the Deliveries to all
+// Endpoints are returned.
+func (d *Dispatcher) Deliveries(endpointID uuid.UUID) []Delivery {
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+
+	out := make([]Delivery, 0)
+	for i := len(d.deliveries) - 1; i >= 0; i-- {
+		del := d.deliveries[i]
+		if endpointID != uuid.Nil && del.EndpointID != endpointID {
+			continue
+		}
+		cp := *del
+		cp.Attempts = append([]Attempt(nil), del.Attempts...)
+		out = append(out, cp)
+	}
+
+	return out
+}
+
+// Run subscribes to the events of the Dispatcher and delivers them to the
+// subscribed Endpoints until ctx is canceled. Run returns a channel of
+// asynchronous errors that reports Deliveries that failed after their last
+// attempt. The channel is closed when ctx is canceled.
+//
+// Deliveries are made concurrently, so Endpoints may receive events out of
+// order.
+func (d *Dispatcher) Run(ctx context.Context, bus event.Bus) (<-chan error, error) {
+	events, errs, err := bus.Subscribe(ctx, d.events...)
+	if err != nil {
+		return nil, fmt.Errorf("subscribe to %v events: %w", d.events, err)
+	}
+
+	queue := make(chan job)
+	out := make(chan error)
+
+	var wg sync.WaitGroup
+	wg.Add(d.workers)
+	for i := 0; i < d.workers; i++ {
+		go func() {
+			defer wg.Done()
+			d.work(ctx, queue, out)
+		}()
+	}
+
+	go func() {
+		defer func() {
+			wg.Wait()
+			close(out)
+		}()
+
+		fail := func(err error) {
+			select {
+			case <-ctx.Done():
+			case out <- err:
+			}
+		}
+
+		streams.ForEach(ctx, func(evt event.Event) {
+			for _, j := range d.jobs(evt) {
+				select {
+				case <-ctx.Done():
+					return
+				case queue <- j:
+				}
+			}
+		}, fail, events, errs)
+	}()
+
+	return out, nil
+}
+
+type job struct {
+	delivery *Delivery
+	endpoint Endpoint
+	body     []byte
+}
+
+// jobs creates the Deliveries of evt to the subscribed Endpoints and adds them
+// to the delivery log.
+func (d *Dispatcher) jobs(evt event.Event) []job {
+	id, name, version := evt.Aggregate()
+	body, err := json.Marshal(Payload{
+		ID:               evt.ID(),
+		Event:            evt.Name(),
+		Time:             evt.Time(),
+		Aggregate:        name,
+		AggregateID:      id,
+		AggregateVersion: version,
+		Data:             evt.Data(),
+	})
+
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	var jobs []job
+	for _, ep := range d.endpoints {
+		if !ep.subscribed(evt.Name()) {
+			continue
+		}
+
+		del := &Delivery{
+			ID:         uuid.New(),
+			EndpointID: ep.ID,
+			URL:        ep.URL,
+			EventID:    evt.ID(),
+			Event:      evt.Name(),
+			Status:     Pending,
+			Attempts:   make([]Attempt, 0),
+		}
+		d.log(del)
+
+		if err != nil {
+			del.Status = Failed
+			del.Attempts = append(del.Attempts, Attempt{Time: time.Now(), Error: fmt.Sprintf("encode payload: %v", err)})
+			continue
+		}
+
+		jobs = append(jobs, job{delivery: del, endpoint: ep, body: body})
+	}
+
+	return jobs
+}
+
+func (d *Dispatcher) log(del *Delivery) {
+	d.deliveries = append(d.deliveries, del)
+	if d.logSize > 0 && len(d.deliveries) > d.logSize {
+		d.deliveries = append(d.deliveries[:0:0], d.deliveries[len(d.deliveries)-d.logSize:]...)
+	}
+}
+
+func (d *Dispatcher) work(ctx context.Context, queue chan job, out chan<- error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case j := <-queue:
+			status, err := d.attempt(ctx, j)
+			if err == nil {
+				continue
+			}
+
+			switch status {
+			case Pending:
+				attempts := d.attempts(j.delivery)
+				delay := d.backoff << (attempts - 1)
+				go func() {
+					timer := time.NewTimer(delay)
+					defer timer.Stop()
+					select {
+					case <-ctx.Done():
+					case <-timer.C:
+						select {
+						case <-ctx.Done():
+						case queue <- j:
+						}
+					}
+				}()
+			case Failed:
+				select {
+				case <-ctx.Done():
+				case out <- fmt.Errorf("deliver %q event %s to %s: %w", j.delivery.Event, j.delivery.EventID, j.endpoint.URL, err):
+				}
+			}
+		}
+	}
+}
+
+// attempt makes a request for the Delivery of j and returns the new Status of
+// the Delivery.
+func (d *Dispatcher) attempt(ctx context.Context, j job) (Status, error) {
+	start := time.Now()
+	code, err := d.send(ctx, j)
+
+	a := Attempt{Time: start, Duration: time.Since(start), StatusCode: code}
+	if err != nil {
+		a.Error = err.Error()
+	}
+
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	del := j.delivery
+	del.Attempts = append(del.Attempts, a)
+
+	switch {
+	case err == nil:
+		del.Status = Delivered
+	case ctx.Err() == nil && retryable(code) && len(del.Attempts) < d.maxAttempts:
+		del.Status = Pending
+	default:
+		del.Status = Failed
+	}
+
+	return del.Status, err
+}
+
+func (d *Dispatcher) attempts(del *Delivery) int {
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	return len(del.Attempts)
+}
+
+func (d *Dispatcher) send(ctx context.Context, j job) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.endpoint.URL, bytes.NewReader(j.body))
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IDHeader, j.delivery.ID.String())
+	req.Header.Set(EventHeader, j.delivery.Event)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(ts, 10))
+	if j.endpoint.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign([]byte(j.endpoint.Secret), j.delivery.ID, ts, j.body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// retryable returns whether a request that failed with the given status code
+// should be retried. Requests without a response (status code 0) are retried.
+func retryable(code int) bool {
+	return code == 0 ||
+		code == http.StatusRequestTimeout ||
+		code == http.StatusTooManyRequests ||
+		code >= 500
+}
+
+// Sign returns the signature of a webhook request. The signature is the
+// hex-encoded HMAC-SHA256 of the Delivery ID, the Unix timestamp and the body,
+// joined by dots, prefixed with the signature version:
+//
+//	v1=hex(hmac(secret, "<id>.<timestamp>.<body>"))
+func Sign(secret []byte, id uuid.UUID, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(id.String() + "." + strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify verifies the signature of a webhook request and returns its body.
+// Requests with a timestamp that differs from the current time by more than
+// tolerance are rejected with ErrExpired, to prevent replay attacks. A
+// tolerance <= 0 disables the check:
+//
+//	body, err := webhooks.Verify(r, []byte("secret"), 5*time.Minute)
+func Verify(r *http.Request, secret []byte, tolerance time.Duration) ([]byte, error) {
+	id, err := uuid.Parse(r.Header.Get(IDHeader))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid %s header", ErrInvalidSignature, IDHeader)
+	}
+
+	ts, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid %s header", ErrInvalidSignature, TimestampHeader)
+	}
+
+	if tolerance > 0 {
+		if diff := time.Since(time.Unix(ts, 0)); diff > tolerance || diff < -tolerance {
+			return nil, ErrExpired
+		}
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+
+	sig := strings.TrimSpace(r.Header.Get(SignatureHeader))
+	if !hmac.Equal([]byte(sig), []byte(Sign(secret, id, ts, body))) {
+		return nil, ErrInvalidSignature
+	}
+
+	return body, nil
+}
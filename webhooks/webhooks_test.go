@@ -0,0 +1,231 @@
+package webhooks_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modernice/goes/event"
+	"github.com/modernice/goes/event/eventbus"
+	"github.com/modernice/nice-cms/media/image/gallery"
+	"github.com/modernice/nice-cms/static/nav"
+	"github.com/modernice/nice-cms/webhooks"
+)
+
+func TestDispatcher(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := eventbus.New()
+	d := webhooks.NewDispatcher()
+
+	galleries := newReceiver(t, "gallery-secret")
+	navs := newReceiver(t, "nav-secret")
+
+	galleryEndpoint := d.Register(webhooks.Endpoint{URL: galleries.URL, Events: []string{gallery.Created}, Secret: "gallery-secret"})
+	d.Register(webhooks.Endpoint{URL: navs.URL, Events: []string{nav.Created}, Secret: "nav-secret"})
+
+	if _, err := d.Run(ctx, bus); err != nil {
+		t.Fatalf("run dispatcher: %v", err)
+	}
+
+	galleryID := uuid.New()
+	evt := event.New(gallery.Created, gallery.CreatedData{Name: "foo"}, event.Aggregate(galleryID, gallery.Aggregate, 1))
+	if err := bus.Publish(ctx, evt.Any()); err != nil {
+		t.Fatalf("publish event: %v", err)
+	}
+
+	payloads := galleries.await(t, 1)
+
+	p := payloads[0]
+	if p.ID != evt.ID() || p.Event != gallery.Created || p.AggregateID != galleryID || p.Aggregate != gallery.Aggregate || p.AggregateVersion != 1 {
+		t.Fatalf("payload should describe the %q event; got %#v", gallery.Created, p)
+	}
+
+	if data, _ := p.Data.(map[string]any); data["Name"] != "foo" {
+		t.Fatalf("payload should contain the event data; got %v", p.Data)
+	}
+
+	if n := navs.count(); n != 0 {
+		t.Fatalf("endpoints should only be called for subscribed events; nav endpoint was called %d times", n)
+	}
+
+	deliveries := awaitDeliveries(t, d, galleryEndpoint.ID, webhooks.Delivered)
+	if len(deliveries) != 1 || deliveries[0].EventID != evt.ID() || len(deliveries[0].Attempts) != 1 || deliveries[0].Attempts[0].StatusCode != http.StatusOK {
+		t.Fatalf("delivery log should contain a delivered Delivery with 1 attempt; got %#v", deliveries)
+	}
+}
+
+func TestDispatcher_retry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := eventbus.New()
+	d := webhooks.NewDispatcher(webhooks.Backoff(time.Millisecond), webhooks.MaxAttempts(3))
+
+	var mux sync.Mutex
+	var calls int
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.Lock()
+		defer mux.Unlock()
+		if calls++; calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer flaky.Close()
+
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer rejecting.Close()
+
+	flakyEndpoint := d.Register(webhooks.Endpoint{URL: flaky.URL})
+	rejectingEndpoint := d.Register(webhooks.Endpoint{URL: rejecting.URL})
+
+	errs, err := d.Run(ctx, bus)
+	if err != nil {
+		t.Fatalf("run dispatcher: %v", err)
+	}
+
+	evt := event.New(nav.Created, nav.CreatedData{Name: "main"}, event.Aggregate(uuid.New(), nav.Aggregate, 1))
+	if err := bus.Publish(ctx, evt.Any()); err != nil {
+		t.Fatalf("publish event: %v", err)
+	}
+
+	select {
+	case <-time.After(3 * time.Second):
+		t.Fatalf("failed delivery should be reported")
+	case err := <-errs:
+		if err == nil {
+			t.Fatalf("error should not be nil")
+		}
+	}
+
+	rejected := awaitDeliveries(t, d, rejectingEndpoint.ID, webhooks.Failed)
+	if len(rejected[0].Attempts) != 1 || rejected[0].Attempts[0].StatusCode != http.StatusBadRequest {
+		t.Fatalf("rejected Delivery should not be retried; got %#v", rejected[0].Attempts)
+	}
+
+	delivered := awaitDeliveries(t, d, flakyEndpoint.ID, webhooks.Delivered)
+	if attempts := delivered[0].Attempts; len(attempts) != 3 || attempts[0].StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Delivery should be delivered after 3 attempts; got %#v", attempts)
+	}
+
+	if all := d.Deliveries(uuid.Nil); len(all) != 2 {
+		t.Fatalf("Deliveries(uuid.Nil) should return %d Deliveries; got %d", 2, len(all))
+	}
+}
+
+func TestVerify(t *testing.T) {
+	id := uuid.New()
+	body := []byte(`{"event":"foo"}`)
+	secret := []byte("secret")
+
+	newRequest := func(ts int64, body []byte) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		req.Header.Set(webhooks.IDHeader, id.String())
+		req.Header.Set(webhooks.TimestampHeader, strconv.FormatInt(ts, 10))
+		req.Header.Set(webhooks.SignatureHeader, webhooks.Sign(secret, id, ts, []byte(`{"event":"foo"}`)))
+		return req
+	}
+
+	now := time.Now().Unix()
+
+	got, err := webhooks.Verify(newRequest(now, body), secret, time.Minute)
+	if err != nil {
+		t.Fatalf("Verify failed with %q", err)
+	}
+	if !bytes.Equal(got, body) {
+		t.Fatalf("Verify should return the body %q; got %q", body, got)
+	}
+
+	if _, err := webhooks.Verify(newRequest(now, []byte(`{"event":"bar"}`)), secret, time.Minute); !errors.Is(err, webhooks.ErrInvalidSignature) {
+		t.Fatalf("Verify should fail with %q for a modified body; got %v", webhooks.ErrInvalidSignature, err)
+	}
+
+	if _, err := webhooks.Verify(newRequest(now, body), []byte("other"), time.Minute); !errors.Is(err, webhooks.ErrInvalidSignature) {
+		t.Fatalf("Verify should fail with %q for another secret; got %v", webhooks.ErrInvalidSignature, err)
+	}
+
+	if _, err := webhooks.Verify(newRequest(now-3600, body), secret, time.Minute); !errors.Is(err, webhooks.ErrExpired) {
+		t.Fatalf("Verify should fail with %q for old requests; got %v", webhooks.ErrExpired, err)
+	}
+}
+
+type receiver struct {
+	*httptest.Server
+
+	mux      sync.Mutex
+	payloads []webhooks.Payload
+}
+
+// newReceiver returns a webhook endpoint that verifies requests with secret.
+func newReceiver(t *testing.T, secret string) *receiver {
+	r := &receiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := webhooks.Verify(req, []byte(secret), time.Minute)
+		if err != nil {
+			io.Copy(io.Discard, req.Body)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var p webhooks.Payload
+		if err := json.Unmarshal(body, &p); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		r.mux.Lock()
+		defer r.mux.Unlock()
+		r.payloads = append(r.payloads, p)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) count() int {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return len(r.payloads)
+}
+
+func (r *receiver) await(t *testing.T, n int) []webhooks.Payload {
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		r.mux.Lock()
+		if len(r.payloads) >= n {
+			defer r.mux.Unlock()
+			return append([]webhooks.Payload(nil), r.payloads...)
+		}
+		r.mux.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("endpoint should receive %d payloads; received %d", n, r.count())
+	return nil
+}
+
+func awaitDeliveries(t *testing.T, d *webhooks.Dispatcher, endpointID uuid.UUID, status webhooks.Status) []webhooks.Delivery {
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		deliveries := d.Deliveries(endpointID)
+		if len(deliveries) > 0 && deliveries[0].Status == status {
+			return deliveries
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Delivery should have status %q; got %#v", status, deliveries)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}